@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/fileledger"
+)
+
+var auditCommands = map[string]command{
+	"append": {"append a chained audit entry to a ledger file", runAuditAppend},
+}
+
+func runAudit(e *env, args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "-h", "-help", "--help":
+			auditUsage(e)
+			return exitOK
+		}
+		if cmd, ok := auditCommands[args[0]]; ok {
+			return cmd.run(e, args[1:])
+		}
+		fmt.Fprintf(e.stderr, "dcp: unknown audit command %q\n", args[0])
+	}
+	auditUsage(e)
+	return exitError
+}
+
+func auditUsage(e *env) {
+	fmt.Fprintln(e.stderr, "usage: dcp audit <command> [flags]")
+	fmt.Fprintln(e.stderr, "\ncommands:")
+	names := make([]string, 0, len(auditCommands))
+	for name := range auditCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(e.stderr, "  %-10s %s\n", name, auditCommands[name].summary)
+	}
+}
+
+// appendResult is the --format json output of dcp audit append.
+type appendResult struct {
+	Ledger string         `json:"ledger"`
+	Index  int64          `json:"index"`
+	Hash   string         `json:"hash"`
+	Entry  dcp.AuditEntry `json:"entry"`
+}
+
+func runAuditAppend(e *env, args []string) int {
+	fs := e.flags("audit append", "--ledger <file> --intent <intent.json> --outcome <text> [flags]")
+	ledgerPath := fs.String("ledger", "", "ledger file (fileledger format), created if missing")
+	intentPath := fs.String("intent", "", "intent the entry records")
+	outcome := fs.String("outcome", "", "outcome of the action, e.g. success or email_sent")
+	decision := fs.String("decision", string(dcp.OutcomeApproved), "policy decision: approved, escalated or blocked")
+	auditID := fs.String("audit-id", "", "audit_id; default is a generated dcp:audit: ID")
+	tool := fs.String("tool", "", "evidence.tool")
+	resultRef := fs.String("result-ref", "", "evidence.result_ref")
+	keyPath := fs.String("key", "", "agent secret key to set agent_signature with (text, PEM or keystore)")
+	passFile := fs.String("passphrase-file", "", "file holding the keystore passphrase (default $"+passphraseEnv+")")
+	format := fs.String("format", "text", "output format: text or json")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 0 || *ledgerPath == "" || *intentPath == "" || *outcome == "" {
+		fs.Usage()
+		return exitError
+	}
+	if *format != "text" && *format != "json" {
+		return e.errorf("audit append: unknown format %q (text or json)", *format)
+	}
+	if !dcp.Outcome(*decision).IsValid() {
+		return e.errorf("audit append: unknown decision %q (approved, escalated or blocked)", *decision)
+	}
+
+	raw, _, _, err := readDocument(*intentPath, kindIntent)
+	if err != nil {
+		return e.errorf("audit append: %v", err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(raw, &intent); err != nil {
+		return e.errorf("audit append: %s: %v", *intentPath, err)
+	}
+	if err := intent.Validate(); err != nil {
+		reportInvalid(e, *intentPath, err)
+		return exitFail
+	}
+	var opts dcp.AuditChainOptions
+	if *keyPath != "" {
+		if opts.AgentSigner, err = loadSigner(e, *keyPath, *passFile); err != nil {
+			return e.errorf("audit append: %v", err)
+		}
+	}
+	fields := dcp.AuditEntryFields{
+		AuditID:        *auditID,
+		Intent:         intent,
+		PolicyDecision: dcp.Outcome(*decision),
+		Outcome:        *outcome,
+	}
+	if *tool != "" {
+		fields.Evidence.Tool = tool
+	}
+	if *resultRef != "" {
+		fields.Evidence.ResultRef = resultRef
+	}
+
+	res, err := appendToLedger(*ledgerPath, opts, fields)
+	if err != nil {
+		return e.errorf("audit append: %v", err)
+	}
+	if *format == "json" {
+		if err := writeJSON(e, "", res); err != nil {
+			return e.errorf("audit append: %v", err)
+		}
+		return exitOK
+	}
+	fmt.Fprintf(e.stdout, "%s: appended %s at index %d\nhash %s\n", res.Ledger, res.Entry.AuditID, res.Index, res.Hash)
+	return exitOK
+}
+
+// appendToLedger links an entry built from fields to the last entry of the
+// ledger at path and appends it. Like fileledger itself, it assumes no other
+// process is writing the file.
+func appendToLedger(path string, opts dcp.AuditChainOptions, fields dcp.AuditEntryFields) (*appendResult, error) {
+	ctx := context.Background()
+	store, err := fileledger.Open(path, fileledger.Options{})
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	n, err := store.Len(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Anchor, err = dcp.LedgerChainAnchor(ctx, store, n); err != nil {
+		return nil, err
+	}
+	chain := dcp.NewAuditChain(opts)
+	hash, err := chain.Append(fields)
+	if err != nil {
+		return nil, err
+	}
+	entry := chain.Entries()[0]
+	index, err := store.AppendEntry(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Close(); err != nil {
+		return nil, err
+	}
+	return &appendResult{Ledger: path, Index: index, Hash: hash, Entry: entry}, nil
+}
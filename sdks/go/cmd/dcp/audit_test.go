@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/fileledger"
+)
+
+func TestAuditAppend(t *testing.T) {
+	keys := testKeys(t)
+	ledger := filepath.Join(t.TempDir(), "ledger.db")
+	intent := filepath.Join(examplesDir(), "intent.json")
+
+	var results []appendResult
+	for _, outcome := range []string{"started", "success"} {
+		stdout, stderr, code := runCLI(t, nil, "audit", "append", "--ledger", ledger, "--intent", intent,
+			"--outcome", outcome, "--tool", "ci", "--key", filepath.Join(keys, "secret_key.txt"), "--format", "json")
+		if code != exitOK {
+			t.Fatal(stderr)
+		}
+		var res appendResult
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, res)
+	}
+	if results[0].Entry.PrevHash != "GENESIS" || results[1].Entry.PrevHash != results[0].Hash || results[1].Index != 1 {
+		t.Fatalf("results = %+v", results)
+	}
+
+	store, err := fileledger.Open(ledger, fileledger.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	_, result := dcp.VerifyLedger(context.Background(), store, dcp.AuditStreamOptions{
+		AgentPublicKeyB64: readKey(t, keys, "public_key.txt"),
+	})
+	if !result.Verified {
+		t.Fatal(result.Errors)
+	}
+	if e, _ := store.GetByIndex(context.Background(), 1); e.Outcome != "success" || *e.Evidence.Tool != "ci" || e.AgentSignature == "" {
+		t.Fatalf("entry = %+v", e)
+	}
+}
+
+func TestAuditAppendErrors(t *testing.T) {
+	ledger := filepath.Join(t.TempDir(), "ledger.db")
+	intent := filepath.Join(examplesDir(), "intent.json")
+	for _, args := range [][]string{
+		{"audit"},
+		{"audit", "rewrite"},
+		{"audit", "append", "--ledger", ledger, "--intent", intent},
+		{"audit", "append", "--ledger", ledger, "--intent", intent, "--outcome", "x", "--decision", "maybe"},
+	} {
+		if _, _, code := runCLI(t, nil, args...); code != exitError {
+			t.Errorf("%s: code = %d", strings.Join(args, " "), code)
+		}
+	}
+	bad := filepath.Join(examplesDir(), "audit_entry.json")
+	if _, _, code := runCLI(t, nil, "audit", "append", "--ledger", ledger, "--intent", bad, "--outcome", "x"); code != exitFail {
+		t.Errorf("invalid intent: code = %d", code)
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Difference kinds.
+const (
+	diffChanged  = "changed"
+	diffAdded    = "added"
+	diffRemoved  = "removed"
+	diffCosmetic = "cosmetic"
+)
+
+// difference is one difference between two documents, located by a JSON
+// pointer.
+type difference struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	A    string `json:"a,omitempty"`
+	B    string `json:"b,omitempty"`
+	// Note explains a cosmetic difference.
+	Note string `json:"note,omitempty"`
+	// Affects lists the hashes and signatures the difference changes. It
+	// is empty for cosmetic differences and for unsigned metadata.
+	Affects []string `json:"affects,omitempty"`
+}
+
+type diffReport struct {
+	A           string       `json:"a"`
+	B           string       `json:"b"`
+	Differences []difference `json:"differences"`
+}
+
+func runDiff(e *env, args []string) int {
+	fs := e.flags("diff", "[flags] <a.json> <b.json>\n\nExit status is 0 when the documents differ only cosmetically and 1 otherwise.")
+	format := fs.String("format", "text", "output format: text or json")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return exitError
+	}
+	if *format != "text" && *format != "json" {
+		return e.errorf("diff: unknown format %q (text or json)", *format)
+	}
+	a, b := fs.Arg(0), fs.Arg(1)
+	rawA, docA, kindA, err := readDocument(a, "")
+	if err != nil {
+		return e.errorf("diff: %v", err)
+	}
+	rawB, docB, kindB, err := readDocument(b, "")
+	if err != nil {
+		return e.errorf("diff: %v", err)
+	}
+	if kindA != kindB {
+		return e.errorf("diff: %s is a %s but %s is a %s", a, kindA, b, kindB)
+	}
+
+	d := differ{kind: kindA, entries: max(auditEntryCount(docA, kindA), auditEntryCount(docB, kindB))}
+	d.walk("", rawA, rawB)
+	report := diffReport{A: a, B: b, Differences: d.diffs}
+	if report.Differences == nil {
+		report.Differences = []difference{}
+	}
+	if *format == "json" {
+		err = writeJSON(e, "", report)
+	} else {
+		writeDiff(e.stdout, report)
+	}
+	if err != nil {
+		return e.errorf("diff: %v", err)
+	}
+	for _, diff := range d.diffs {
+		if diff.Kind != diffCosmetic {
+			return exitFail
+		}
+	}
+	return exitOK
+}
+
+func auditEntryCount(doc map[string]interface{}, kind string) int {
+	if kind == kindSignedBundle {
+		doc, _ = doc["bundle"].(map[string]interface{})
+	}
+	entries, _ := doc["audit_entries"].([]interface{})
+	return len(entries)
+}
+
+// differ walks two documents of the same kind in parallel.
+type differ struct {
+	kind    string
+	entries int
+	diffs   []difference
+}
+
+// walk compares the raw JSON values a and b at path. Values that decode
+// equal but are written differently (number spelling, string escapes,
+// member order) canonicalize identically and are reported as cosmetic;
+// whitespace alone is ignored.
+func (d *differ) walk(path string, a, b json.RawMessage) {
+	if compact(a) == compact(b) {
+		return
+	}
+	before := len(d.diffs)
+	var oa, ob map[string]json.RawMessage
+	var la, lb []json.RawMessage
+	switch {
+	case json.Unmarshal(a, &oa) == nil && json.Unmarshal(b, &ob) == nil && oa != nil && ob != nil:
+		for _, k := range unionKeys(oa, ob) {
+			p := path + "/" + escapePointer(k)
+			ra, inA := oa[k]
+			rb, inB := ob[k]
+			switch {
+			case !inB:
+				d.add(difference{Path: p, Kind: diffRemoved, A: preview(ra)})
+			case !inA:
+				d.add(difference{Path: p, Kind: diffAdded, B: preview(rb)})
+			default:
+				d.walk(p, ra, rb)
+			}
+		}
+		if len(d.diffs) == before {
+			d.diffs = append(d.diffs, difference{Path: path, Kind: diffCosmetic, Note: "member order"})
+		}
+	case json.Unmarshal(a, &la) == nil && json.Unmarshal(b, &lb) == nil && la != nil && lb != nil:
+		for i := 0; i < max(len(la), len(lb)); i++ {
+			p := path + "/" + strconv.Itoa(i)
+			switch {
+			case i >= len(lb):
+				d.add(difference{Path: p, Kind: diffRemoved, A: preview(la[i])})
+			case i >= len(la):
+				d.add(difference{Path: p, Kind: diffAdded, B: preview(lb[i])})
+			default:
+				d.walk(p, la[i], lb[i])
+			}
+		}
+	case sameValue(a, b):
+		d.diffs = append(d.diffs, difference{Path: path, Kind: diffCosmetic, A: preview(a), B: preview(b), Note: "same value written differently"})
+	default:
+		d.add(difference{Path: path, Kind: diffChanged, A: preview(a), B: preview(b)})
+	}
+}
+
+// sameValue reports whether two scalars decode to the same value.
+func sameValue(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	return json.Unmarshal(a, &va) == nil && json.Unmarshal(b, &vb) == nil && reflect.DeepEqual(va, vb)
+}
+
+func (d *differ) add(diff difference) {
+	diff.Affects = d.affects(diff.Path)
+	d.diffs = append(d.diffs, diff)
+}
+
+// affects lists what a change at path invalidates, following what
+// VerifySignedBundle checks.
+func (d *differ) affects(path string) []string {
+	segs := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	switch d.kind {
+	case kindSignedBundle:
+		if segs[0] != "bundle" {
+			return signatureBlockAffects(segs)
+		}
+		segs = segs[1:]
+	case kindBundle:
+	default:
+		if len(segs) > 0 && (segs[0] == "signature" || segs[0] == "agent_signature") {
+			return []string{"signature value"}
+		}
+		return []string{"document hash", "signature"}
+	}
+	if len(segs) == 0 || segs[0] == "" {
+		return []string{"bundle_hash", "bundle signature"}
+	}
+	var out []string
+	member := ""
+	if len(segs) > 1 {
+		member = segs[1]
+	}
+	switch segs[0] {
+	case "responsible_principal_record":
+		if member != "signature" {
+			out = append(out, "principal signature")
+		}
+	case "agent_passport":
+		if member != "signature" {
+			out = append(out, "passport signature")
+		}
+		if member == "public_key" || member == "" {
+			out = append(out, "agent_signature checks on audit entries")
+		}
+	case "intent":
+		out = append(out, "intent_hash of every audit entry")
+	case "chain_anchor":
+		out = append(out, "prev_hash of audit entry 0")
+	case "audit_entries":
+		if member == "" {
+			out = append(out, "prev_hash chain")
+			break
+		}
+		i, _ := strconv.Atoi(member)
+		out = append(out, fmt.Sprintf("hash of audit entry %d", i))
+		if len(segs) < 3 || segs[2] != "agent_signature" {
+			out = append(out, fmt.Sprintf("agent_signature of audit entry %d", i))
+		}
+		if i+1 < d.entries {
+			out = append(out, fmt.Sprintf("prev_hash of audit entry %d", i+1))
+		}
+	}
+	if segs[0] == "audit_entries" {
+		out = append(out, "merkle_root")
+	}
+	return append(out, "bundle_hash", "bundle signature")
+}
+
+// signatureBlockAffects handles paths under a signed bundle's "signature"
+// member, which is not itself signed.
+func signatureBlockAffects(segs []string) []string {
+	if len(segs) < 2 {
+		return []string{"bundle signature"}
+	}
+	switch segs[1] {
+	case "sig_b64":
+		return []string{"bundle signature"}
+	case "bundle_hash", "merkle_root":
+		return []string{"claimed " + segs[1]}
+	case "signer":
+		if len(segs) > 2 && segs[2] == "public_key_b64" {
+			return []string{"embedded verification key"}
+		}
+	}
+	return nil
+}
+
+func unionKeys(a, b map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapePointer escapes a member name for a JSON pointer (RFC 6901).
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func compact(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+// preview returns raw as compact JSON, shortened for display.
+func preview(raw json.RawMessage) string {
+	s := compact(raw)
+	if len(s) > 72 {
+		s = s[:69] + "..."
+	}
+	return s
+}
+
+func writeDiff(w io.Writer, r diffReport) {
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", r.A, r.B)
+	material, cosmetic := 0, 0
+	for _, d := range r.Differences {
+		path := d.Path
+		if path == "" {
+			path = "/"
+		}
+		switch d.Kind {
+		case diffChanged:
+			fmt.Fprintf(w, "~ %s: %s -> %s\n", path, d.A, d.B)
+		case diffAdded:
+			fmt.Fprintf(w, "+ %s: %s\n", path, d.B)
+		case diffRemoved:
+			fmt.Fprintf(w, "- %s: %s\n", path, d.A)
+		case diffCosmetic:
+			cosmetic++
+			if d.A != "" {
+				fmt.Fprintf(w, "= %s: %s vs %s (cosmetic: %s)\n", path, d.A, d.B, d.Note)
+			} else {
+				fmt.Fprintf(w, "= %s (cosmetic: %s)\n", path, d.Note)
+			}
+			continue
+		}
+		if len(d.Affects) == 0 {
+			fmt.Fprintln(w, "    affects: nothing signed")
+			continue
+		}
+		material++
+		fmt.Fprintf(w, "    affects: %s\n", strings.Join(d.Affects, ", "))
+	}
+	if len(r.Differences) == 0 {
+		fmt.Fprintln(w, "no differences")
+		return
+	}
+	fmt.Fprintf(w, "\n%d of %d differences affect hashes or signatures, %d cosmetic\n", material, len(r.Differences), cosmetic)
+}
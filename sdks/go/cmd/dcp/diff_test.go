@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := filepath.Join(examplesDir(), "citizenship_bundle.signed.json")
+	data, err := os.ReadFile(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.NewReplacer(
+		`"risk_score": 0.21`, `"risk_score": 2.1e-1`,
+		`"outcome": "email_sent"`, `"outcome": "email_bounced"`,
+	).Replace(string(data))
+	b := filepath.Join(t.TempDir(), "b.json")
+	os.WriteFile(b, []byte(edited), 0o644)
+
+	stdout, stderr, code := runCLI(t, nil, "diff", "--format", "json", a, b)
+	if code != exitFail {
+		t.Fatalf("code = %d, stderr = %s", code, stderr)
+	}
+	var report diffReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Differences) != 2 {
+		t.Fatalf("differences = %+v", report.Differences)
+	}
+	outcome, score := report.Differences[0], report.Differences[1]
+	if outcome.Path != "/bundle/audit_entries/1/outcome" || outcome.Kind != diffChanged ||
+		!contains(outcome.Affects, "merkle_root") || contains(outcome.Affects, "prev_hash of audit entry 2") {
+		t.Errorf("outcome = %+v", outcome)
+	}
+	if score.Path != "/bundle/policy_decision/risk_score" || score.Kind != diffCosmetic || len(score.Affects) != 0 {
+		t.Errorf("risk_score = %+v", score)
+	}
+}
+
+func TestDiffCosmeticOnly(t *testing.T) {
+	a := filepath.Join(examplesDir(), "citizenship_bundle.json")
+	var doc map[string]interface{}
+	data, _ := os.ReadFile(a)
+	json.Unmarshal(data, &doc)
+	compacted, _ := json.Marshal(doc) // sorted members, no whitespace
+	b := filepath.Join(t.TempDir(), "b.json")
+	os.WriteFile(b, compacted, 0o644)
+
+	stdout, _, code := runCLI(t, nil, "diff", a, b)
+	if code != exitOK || strings.Contains(stdout, "affects:") {
+		t.Fatalf("code = %d, stdout = %s", code, stdout)
+	}
+	if stdout, _, code = runCLI(t, nil, "diff", a, a); code != exitOK || !strings.Contains(stdout, "no differences") {
+		t.Fatalf("code = %d, stdout = %s", code, stdout)
+	}
+}
+
+func TestDiffKindMismatch(t *testing.T) {
+	_, _, code := runCLI(t, nil, "diff", filepath.Join(examplesDir(), "citizenship_bundle.json"), filepath.Join(examplesDir(), "intent.json"))
+	if code != exitError {
+		t.Fatalf("code = %d", code)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Finding severities, most severe first.
+const (
+	sevError   = "error"
+	sevWarning = "warning"
+	sevInfo    = "info"
+)
+
+var severityRank = map[string]int{sevError: 0, sevWarning: 1, sevInfo: 2}
+
+// finding is one problem dcp doctor reports. Check is a stable identifier
+// for scripts to filter on.
+type finding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	Pointer  string `json:"pointer,omitempty"`
+	Message  string `json:"message"`
+}
+
+type doctorReport struct {
+	File     string    `json:"file"`
+	Kind     string    `json:"kind"`
+	Findings []finding `json:"findings"`
+}
+
+func runDoctor(e *env, args []string) int {
+	fs := e.flags("doctor", "[flags] <file.json>...")
+	format := fs.String("format", "text", "output format: text or json")
+	failOn := fs.String("fail-on", sevError, "lowest severity that makes the exit status 1: error, warning or info")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitError
+	}
+	if *format != "text" && *format != "json" {
+		return e.errorf("doctor: unknown format %q (text or json)", *format)
+	}
+	threshold, ok := severityRank[*failOn]
+	if !ok {
+		return e.errorf("doctor: unknown severity %q (error, warning or info)", *failOn)
+	}
+
+	reports := make([]doctorReport, fs.NArg())
+	code := exitOK
+	for i, path := range fs.Args() {
+		raw, _, kind, err := readDocument(path, "")
+		if err != nil {
+			return e.errorf("doctor: %v", err)
+		}
+		reports[i] = doctorReport{File: path, Kind: kind, Findings: diagnose(raw, kind, time.Now())}
+		for _, f := range reports[i].Findings {
+			if severityRank[f.Severity] <= threshold {
+				code = exitFail
+			}
+		}
+	}
+	var err error
+	if *format == "json" {
+		var v interface{} = reports
+		if len(reports) == 1 {
+			v = reports[0]
+		}
+		err = writeJSON(e, "", v)
+	} else {
+		for _, r := range reports {
+			writeDoctor(e.stdout, r)
+		}
+	}
+	if err != nil {
+		return e.errorf("doctor: %v", err)
+	}
+	return code
+}
+
+// diagnose runs Validate and, for bundles, verification and best-practice
+// checks over a document.
+func diagnose(raw []byte, kind string, now time.Time) []finding {
+	d := &diagnosis{findings: []finding{}}
+	typed, _ := record(kind)
+	if err := json.Unmarshal(raw, typed); err != nil {
+		d.add(sevError, "parse", "", "%s", err)
+		return d.findings
+	}
+	if err := typed.Validate(); err != nil {
+		var verrs dcp.ValidationErrors
+		if errors.As(err, &verrs) {
+			for _, fe := range verrs {
+				d.add(sevError, "schema", fe.Pointer, "%s", fe.Message)
+			}
+		} else {
+			d.add(sevError, "schema", "", "%s", err)
+		}
+	}
+	switch kind {
+	case kindSignedBundle:
+		sb := typed.(*dcp.SignedBundle)
+		d.signedBundle(raw, sb)
+		d.bundle("/bundle", &sb.Bundle, now)
+	case kindBundle:
+		d.bundle("", typed.(*dcp.CitizenshipBundle), now)
+	}
+	return d.findings
+}
+
+type diagnosis struct {
+	findings []finding
+}
+
+func (d *diagnosis) add(severity, check, pointer, format string, args ...interface{}) {
+	d.findings = append(d.findings, finding{Severity: severity, Check: check, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *diagnosis) signedBundle(raw []byte, sb *dcp.SignedBundle) {
+	if _, err := dcp.ParseSignedBundleStrict(raw); err != nil {
+		var perrs dcp.ParseErrors
+		errors.As(err, &perrs)
+		for _, pe := range perrs {
+			switch pe.Code {
+			case dcp.ParseErrDuplicateKey:
+				d.add(sevError, "duplicate-key", pe.Pointer, "duplicate key; SDKs may disagree on which value was signed")
+			case dcp.ParseErrUnknownField:
+				d.add(sevWarning, "unknown-member", pe.Pointer, "member not in the schema; strict verifiers reject it")
+			}
+		}
+	}
+	if rsb, err := dcp.ParseSignedBundle(raw); err == nil {
+		if result := dcp.VerifyRawSignedBundle(rsb, ""); !result.Verified {
+			for _, msg := range result.Errors {
+				d.add(sevError, "verify", "", "%s (against the embedded key)", msg)
+			}
+		}
+	}
+	sig := &sb.Signature
+	if sig.MerkleRoot == nil && len(sb.Bundle.AuditEntries) > 0 {
+		d.add(sevWarning, "merkle-root", "/signature/merkle_root", "missing; single audit entries cannot be proven without disclosing the rest")
+	}
+	if sig.SignerInfo.PublicKeyB64 == "" {
+		d.add(sevWarning, "signer-key", "/signature/signer/public_key_b64", "missing; verifiers must obtain the key out of band")
+	}
+	if sig.Alg == "ed25519" {
+		d.add(sevInfo, "classical-only", "/signature/alg", "V1 Ed25519-only bundle; DCP 2.0 verifiers emit deprecation warnings for classical-only signatures")
+	}
+}
+
+func (d *diagnosis) bundle(base string, b *dcp.CitizenshipBundle, now time.Time) {
+	rpr, passport, intent := &b.ResponsiblePrincipalRecord, &b.AgentPassport, &b.Intent
+	rprPtr, passportPtr := base+"/responsible_principal_record", base+"/agent_passport"
+
+	if rpr.ExpiresAt == nil {
+		d.add(sevWarning, "no-expiry", rprPtr+"/expires_at", "principal record never expires; prefer a bounded validity")
+	} else if t, err := rpr.ExpiresAtTime(); err == nil && now.After(t) {
+		d.add(sevError, "expired", rprPtr+"/expires_at", "principal record expired at %s", *rpr.ExpiresAt)
+	}
+	if rpr.Signature == "" {
+		d.add(sevWarning, "unsigned-record", rprPtr+"/signature", "principal record is not signed")
+	}
+	if passport.Signature == "" {
+		d.add(sevWarning, "unsigned-record", passportPtr+"/signature", "agent passport is not signed")
+	}
+	switch passport.Status {
+	case dcp.StatusRevoked:
+		d.add(sevError, "agent-status", passportPtr+"/status", "agent is revoked")
+	case dcp.StatusSuspended:
+		d.add(sevWarning, "agent-status", passportPtr+"/status", "agent is suspended")
+	}
+
+	if passport.PrincipalBindingReference != rpr.HumanID {
+		d.add(sevError, "binding", passportPtr+"/principal_binding_reference", "%q does not name the bundle's principal %q", passport.PrincipalBindingReference, rpr.HumanID)
+	}
+	if intent.AgentID != passport.AgentID {
+		d.add(sevError, "binding", base+"/intent/agent_id", "%q is not the passport's agent %q", intent.AgentID, passport.AgentID)
+	}
+	if intent.HumanID != rpr.HumanID {
+		d.add(sevError, "binding", base+"/intent/human_id", "%q is not the bundle's principal %q", intent.HumanID, rpr.HumanID)
+	}
+	if b.PolicyDecision.IntentID != intent.IntentID {
+		d.add(sevError, "binding", base+"/policy_decision/intent_id", "%q is not the bundle's intent %q", b.PolicyDecision.IntentID, intent.IntentID)
+	}
+
+	if len(b.AuditEntries) == 0 {
+		d.add(sevWarning, "empty-chain", base+"/audit_entries", "no audit entries; the bundle attests to nothing that happened")
+	}
+	unsignedEntries := 0
+	for i, entry := range b.AuditEntries {
+		p := fmt.Sprintf("%s/audit_entries/%d", base, i)
+		if entry.IntentID != intent.IntentID {
+			d.add(sevWarning, "binding", p+"/intent_id", "%q is not the bundle's intent %q", entry.IntentID, intent.IntentID)
+		}
+		if b.PolicyDecision.Decision == dcp.DecisionBlock && entry.PolicyDecision == dcp.OutcomeApproved {
+			d.add(sevWarning, "decision", p+"/policy_decision", "entry records an approval but the policy decision is block")
+		}
+		if entry.AgentSignature == "" {
+			unsignedEntries++
+		}
+	}
+	if unsignedEntries > 0 {
+		d.add(sevInfo, "unsigned-entries", base+"/audit_entries", "%d of %d audit entries carry no agent_signature", unsignedEntries, len(b.AuditEntries))
+	}
+
+	for _, id := range []struct{ pointer, value string }{
+		{rprPtr + "/human_id", rpr.HumanID},
+		{passportPtr + "/agent_id", passport.AgentID},
+		{base + "/intent/intent_id", intent.IntentID},
+	} {
+		if !dcp.IsSchemeID(id.value) {
+			d.add(sevInfo, "legacy-id", id.pointer, "%q predates the dcp: identifier scheme", id.value)
+		}
+	}
+}
+
+func writeDoctor(w io.Writer, r doctorReport) {
+	counts := map[string]int{}
+	for _, f := range r.Findings {
+		counts[f.Severity]++
+	}
+	fmt.Fprintf(w, "%s: %d errors, %d warnings, %d info\n", r.File, counts[sevError], counts[sevWarning], counts[sevInfo])
+	for _, sev := range []string{sevError, sevWarning, sevInfo} {
+		for _, f := range r.Findings {
+			if f.Severity != sev {
+				continue
+			}
+			where := ""
+			if f.Pointer != "" {
+				where = f.Pointer + ": "
+			}
+			fmt.Fprintf(w, "  %-8s %-16s %s%s\n", f.Severity, f.Check, where, f.Message)
+		}
+	}
+}
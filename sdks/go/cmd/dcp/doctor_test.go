@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoctor(t *testing.T) {
+	signed := filepath.Join(examplesDir(), "citizenship_bundle.signed.json")
+	stdout, _, code := runCLI(t, nil, "doctor", signed)
+	if code != exitOK || !strings.Contains(stdout, "0 errors, 1 warnings") || !strings.Contains(stdout, "no-expiry") {
+		t.Fatalf("code = %d, stdout = %s", code, stdout)
+	}
+	if _, _, code = runCLI(t, nil, "doctor", "--fail-on", "warning", signed); code != exitFail {
+		t.Fatalf("--fail-on warning: code = %d", code)
+	}
+}
+
+func TestDoctorFindings(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(examplesDir(), "citizenship_bundle.signed.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.NewReplacer(
+		`"expires_at": null`, `"expires_at": "2026-02-01T00:00:00Z"`,
+		`"status": "active"`, `"status": "revoked"`,
+		`"decision": "approve"`, `"decision": "block"`,
+		`"merkle_root": "sha256:`, `"merkle_rootx": "sha256:`,
+	).Replace(string(data))
+	now, _ := time.Parse(time.RFC3339, "2026-06-01T00:00:00Z")
+	checks := map[string]string{}
+	for _, f := range diagnose([]byte(edited), kindSignedBundle, now) {
+		checks[f.Check] = f.Severity
+	}
+	for check, sev := range map[string]string{
+		"expired":        sevError,
+		"agent-status":   sevError,
+		"verify":         sevError,
+		"decision":       sevWarning,
+		"unknown-member": sevWarning,
+		"merkle-root":    sevWarning,
+	} {
+		if checks[check] != sev {
+			t.Errorf("%s: severity %q, want %q (all: %v)", check, checks[check], sev, checks)
+		}
+	}
+}
+
+func TestDoctorRecord(t *testing.T) {
+	stdout, _, code := runCLI(t, nil, "doctor", "--format", "json", filepath.Join(examplesDir(), "intent.json"))
+	var report doctorReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatal(err)
+	}
+	if code != exitOK || report.Kind != kindIntent || len(report.Findings) != 0 {
+		t.Fatalf("code = %d, report = %+v", code, report)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+// Document kinds the CLI recognizes.
+const (
+	kindSignedBundle = "signed_bundle"
+	kindBundle       = "bundle"
+	kindRPR          = "responsible_principal_record"
+	kindPassport     = "agent_passport"
+	kindIntent       = "intent"
+	kindAuditEntry   = "audit_entry"
+	kindRevocation   = "revocation_record"
+	kindPolicyBundle = "policy_bundle"
+)
+
+// detectKind guesses a document's kind from its members.
+func detectKind(doc map[string]interface{}) (string, error) {
+	has := func(keys ...string) bool {
+		for _, k := range keys {
+			if _, ok := doc[k]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case has("bundle", "signature"):
+		return kindSignedBundle, nil
+	case has("responsible_principal_record", "audit_entries"):
+		return kindBundle, nil
+	case has("human_id", "legal_name"):
+		return kindRPR, nil
+	case has("agent_id", "principal_binding_reference"):
+		return kindPassport, nil
+	case has("audit_id", "prev_hash"):
+		return kindAuditEntry, nil
+	case has("intent_id", "action_type"):
+		return kindIntent, nil
+	case has("agent_id", "reason"):
+		return kindRevocation, nil
+	case has("version", "effective_from", "policy"):
+		return kindPolicyBundle, nil
+	}
+	return "", fmt.Errorf("cannot tell what kind of DCP document this is; pass --type")
+}
+
+// record returns an empty typed value for a document kind.
+func record(kind string) (interface{ Validate() error }, error) {
+	switch kind {
+	case kindSignedBundle:
+		return &dcp.SignedBundle{}, nil
+	case kindBundle:
+		return &dcp.CitizenshipBundle{}, nil
+	case kindRPR:
+		return &dcp.ResponsiblePrincipalRecord{}, nil
+	case kindPassport:
+		return &dcp.AgentPassport{}, nil
+	case kindIntent:
+		return &dcp.Intent{}, nil
+	case kindAuditEntry:
+		return &dcp.AuditEntry{}, nil
+	case kindRevocation:
+		return &dcp.RevocationRecord{}, nil
+	case kindPolicyBundle:
+		return &pdp.PolicyBundle{}, nil
+	}
+	return nil, fmt.Errorf("unknown document type %q", kind)
+}
+
+// readDocument reads a JSON document and its kind; kind, if non-empty,
+// overrides detection.
+func readDocument(path, kind string) (raw []byte, doc map[string]interface{}, _ string, err error) {
+	if raw, err = os.ReadFile(path); err != nil {
+		return nil, nil, "", err
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, nil, "", fmt.Errorf("%s: %w", path, err)
+	}
+	if kind == "" {
+		if kind, err = detectKind(doc); err != nil {
+			return nil, nil, "", fmt.Errorf("%s: %w", path, err)
+		}
+	} else if _, err := record(kind); err != nil {
+		return nil, nil, "", err
+	}
+	return raw, doc, kind, nil
+}
+
+// writeJSON writes v as indented JSON to path, or to stdout when path is
+// empty or "-".
+func writeJSON(e *env, path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "" || path == "-" {
+		_, err = e.stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// hashCheck pairs a hash claimed in a document with the one computed from
+// its content.
+type hashCheck struct {
+	Claimed  string `json:"claimed"`
+	Computed string `json:"computed"`
+	Match    bool   `json:"match"`
+}
+
+func newHashCheck(claimed, computed string) hashCheck {
+	return hashCheck{Claimed: claimed, Computed: computed, Match: claimed == computed}
+}
+
+type signatureInfo struct {
+	Alg         string     `json:"alg"`
+	CreatedAt   string     `json:"created_at"`
+	SignerType  string     `json:"signer_type"`
+	SignerID    string     `json:"signer_id"`
+	PublicKey   string     `json:"public_key_b64"`
+	Fingerprint string     `json:"fingerprint"`
+	BundleHash  hashCheck  `json:"bundle_hash"`
+	MerkleRoot  *hashCheck `json:"merkle_root,omitempty"`
+}
+
+type principalInfo struct {
+	HumanID      string `json:"human_id"`
+	LegalName    string `json:"legal_name"`
+	EntityType   string `json:"entity_type"`
+	Jurisdiction string `json:"jurisdiction"`
+	IssuedAt     string `json:"issued_at"`
+	ExpiresAt    string `json:"expires_at,omitempty"`
+	// Expiry is "no expiry", "valid", "expired" or "invalid".
+	Expiry string `json:"expiry"`
+	Signed bool   `json:"signed"`
+}
+
+type agentInfo struct {
+	AgentID      string   `json:"agent_id"`
+	Status       string   `json:"status"`
+	RiskTier     string   `json:"risk_tier"`
+	Capabilities []string `json:"capabilities"`
+	Fingerprint  string   `json:"fingerprint"`
+	// BoundToPrincipal reports whether principal_binding_reference names
+	// the bundle's principal.
+	BoundToPrincipal bool `json:"bound_to_principal"`
+	Signed           bool `json:"signed"`
+}
+
+type intentInfo struct {
+	IntentID   string `json:"intent_id"`
+	ActionType string `json:"action_type"`
+	Channel    string `json:"channel"`
+	Impact     string `json:"estimated_impact"`
+	Hash       string `json:"hash"`
+}
+
+type policyInfo struct {
+	Decision  string   `json:"decision"`
+	RiskScore float64  `json:"risk_score"`
+	Reasons   []string `json:"reasons"`
+}
+
+type entryInfo struct {
+	Index          int       `json:"index"`
+	AuditID        string    `json:"audit_id"`
+	Timestamp      string    `json:"timestamp"`
+	PolicyDecision string    `json:"policy_decision"`
+	Outcome        string    `json:"outcome"`
+	Hash           string    `json:"hash"`
+	PrevHash       hashCheck `json:"prev_hash"`
+	IntentHash     hashCheck `json:"intent_hash"`
+	// AgentSignature is "absent", "valid", "invalid", or "unchecked" when
+	// no agent key is known.
+	AgentSignature string `json:"agent_signature"`
+}
+
+// inspection is what dcp inspect reports about a bundle.
+type inspection struct {
+	File       string         `json:"file"`
+	Kind       string         `json:"kind"`
+	Signature  *signatureInfo `json:"signature,omitempty"`
+	Principal  principalInfo  `json:"principal"`
+	Agent      agentInfo      `json:"agent"`
+	Intent     intentInfo     `json:"intent"`
+	Policy     policyInfo     `json:"policy"`
+	ChainStart string         `json:"chain_start"`
+	Entries    []entryInfo    `json:"audit_entries"`
+}
+
+func runInspect(e *env, args []string) int {
+	fs := e.flags("inspect", "[flags] <bundle.json>")
+	format := fs.String("format", "text", "output format: text or json")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitError
+	}
+	if *format != "text" && *format != "json" {
+		return e.errorf("inspect: unknown format %q (text or json)", *format)
+	}
+	path := fs.Arg(0)
+	raw, _, kind, err := readDocument(path, "")
+	if err != nil {
+		return e.errorf("inspect: %v", err)
+	}
+	if kind != kindSignedBundle && kind != kindBundle {
+		return e.errorf("inspect: %s is a %s, not a bundle", path, kind)
+	}
+	in, err := inspectBundle(raw, kind, time.Now())
+	if err != nil {
+		return e.errorf("inspect: %s: %v", path, err)
+	}
+	in.File = path
+	if *format == "json" {
+		err = writeJSON(e, "", in)
+	} else {
+		writeInspection(e.stdout, in)
+	}
+	if err != nil {
+		return e.errorf("inspect: %v", err)
+	}
+	return exitOK
+}
+
+// inspectBundle derives an inspection from a signed or unsigned bundle.
+// Hashes are computed over the received JSON, as verification does.
+func inspectBundle(data []byte, kind string, now time.Time) (*inspection, error) {
+	var sb dcp.SignedBundle
+	rawBundle := json.RawMessage(data)
+	if kind == kindSignedBundle {
+		var outer struct {
+			Bundle json.RawMessage `json:"bundle"`
+		}
+		if err := json.Unmarshal(data, &outer); err != nil {
+			return nil, err
+		}
+		rawBundle = outer.Bundle
+		if err := json.Unmarshal(data, &sb); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, &sb.Bundle); err != nil {
+		return nil, err
+	}
+	var members struct {
+		Intent       json.RawMessage   `json:"intent"`
+		AuditEntries []json.RawMessage `json:"audit_entries"`
+	}
+	if err := json.Unmarshal(rawBundle, &members); err != nil {
+		return nil, err
+	}
+	b := &sb.Bundle
+	rpr, passport := &b.ResponsiblePrincipalRecord, &b.AgentPassport
+
+	in := &inspection{
+		Kind: kind,
+		Principal: principalInfo{
+			HumanID:      rpr.HumanID,
+			LegalName:    rpr.LegalName,
+			EntityType:   string(rpr.EntityType),
+			Jurisdiction: rpr.Jurisdiction,
+			IssuedAt:     rpr.IssuedAt,
+			Expiry:       expiry(rpr, now),
+			Signed:       rpr.Signature != "",
+		},
+		Agent: agentInfo{
+			AgentID:          passport.AgentID,
+			Status:           string(passport.Status),
+			RiskTier:         string(passport.RiskTier),
+			Capabilities:     passport.Capabilities,
+			Fingerprint:      fingerprint(passport.PublicKey),
+			BoundToPrincipal: passport.PrincipalBindingReference == rpr.HumanID,
+			Signed:           passport.Signature != "",
+		},
+		Intent: intentInfo{
+			IntentID:   b.Intent.IntentID,
+			ActionType: b.Intent.ActionType,
+			Channel:    string(b.Intent.Target.Channel),
+			Impact:     string(b.Intent.EstimatedImpact),
+		},
+		Policy: policyInfo{
+			Decision:  string(b.PolicyDecision.Decision),
+			RiskScore: b.PolicyDecision.RiskScore,
+			Reasons:   b.PolicyDecision.Reasons,
+		},
+		ChainStart: "GENESIS",
+	}
+	if rpr.ExpiresAt != nil {
+		in.Principal.ExpiresAt = *rpr.ExpiresAt
+	}
+	if b.ChainAnchor != nil {
+		in.ChainStart = b.ChainAnchor.PrevEntryHash
+	}
+	var err error
+	if in.Intent.Hash, err = hashJSON(members.Intent); err != nil {
+		return nil, fmt.Errorf("intent: %w", err)
+	}
+
+	prev := in.ChainStart
+	leaves := make([]string, 0, len(members.AuditEntries))
+	for i, raw := range members.AuditEntries {
+		entry, err := inspectEntry(raw, passport.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("audit entry %d: %w", i, err)
+		}
+		entry.Index = i
+		entry.PrevHash = newHashCheck(entry.PrevHash.Claimed, prev)
+		entry.IntentHash = newHashCheck(entry.IntentHash.Claimed, in.Intent.Hash)
+		in.Entries = append(in.Entries, entry)
+		leaves = append(leaves, entry.Hash)
+		prev = entry.Hash
+	}
+
+	if kind == kindSignedBundle {
+		sig := &sb.Signature
+		bundleHash, err := hashJSON(rawBundle)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %w", err)
+		}
+		in.Signature = &signatureInfo{
+			Alg:         sig.Alg,
+			CreatedAt:   sig.CreatedAt,
+			SignerType:  sig.SignerInfo.Type,
+			SignerID:    sig.SignerInfo.ID,
+			PublicKey:   sig.SignerInfo.PublicKeyB64,
+			Fingerprint: fingerprint(sig.SignerInfo.PublicKeyB64),
+			BundleHash:  newHashCheck(sig.BundleHash, "sha256:"+bundleHash),
+		}
+		if sig.MerkleRoot != nil && len(leaves) > 0 {
+			root, err := dcp.MerkleRootFromHexLeaves(leaves)
+			if err != nil {
+				return nil, fmt.Errorf("merkle root: %w", err)
+			}
+			check := newHashCheck(*sig.MerkleRoot, "sha256:"+root)
+			in.Signature.MerkleRoot = &check
+		}
+	}
+	return in, nil
+}
+
+func inspectEntry(raw json.RawMessage, agentKey string) (entryInfo, error) {
+	var entry dcp.AuditEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return entryInfo{}, err
+	}
+	hash, err := hashJSON(raw)
+	if err != nil {
+		return entryInfo{}, err
+	}
+	info := entryInfo{
+		AuditID:        entry.AuditID,
+		Timestamp:      entry.Timestamp,
+		PolicyDecision: string(entry.PolicyDecision),
+		Outcome:        entry.Outcome,
+		Hash:           hash,
+		PrevHash:       hashCheck{Claimed: entry.PrevHash},
+		IntentHash:     hashCheck{Claimed: entry.IntentHash},
+		AgentSignature: "absent",
+	}
+	if entry.AgentSignature != "" && agentKey == "" {
+		info.AgentSignature = "unchecked"
+	} else if entry.AgentSignature != "" {
+		var generic map[string]interface{}
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return entryInfo{}, err
+		}
+		delete(generic, "agent_signature")
+		canon, err := dcp.Canonicalize(generic)
+		if err != nil {
+			return entryInfo{}, err
+		}
+		info.AgentSignature = "invalid"
+		if ok, _ := dcp.VerifyCanonical(canon, entry.AgentSignature, agentKey); ok {
+			info.AgentSignature = "valid"
+		}
+	}
+	return info, nil
+}
+
+// hashJSON returns the hex SHA-256 of the canonical form of raw JSON.
+func hashJSON(raw json.RawMessage) (string, error) {
+	canon, err := dcp.CanonicalizeJSON(raw)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(canon))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// expiry classifies a principal record's expires_at against now.
+func expiry(rpr *dcp.ResponsiblePrincipalRecord, now time.Time) string {
+	t, err := rpr.ExpiresAtTime()
+	switch {
+	case err != nil:
+		return "invalid"
+	case t.IsZero():
+		return "no expiry"
+	case now.After(t):
+		return "expired"
+	}
+	return "valid"
+}
+
+func writeInspection(w io.Writer, in *inspection) {
+	mark := func(c hashCheck) string {
+		if c.Match {
+			return "ok"
+		}
+		return "MISMATCH, claimed " + c.Claimed
+	}
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "NO"
+	}
+
+	fmt.Fprintf(w, "%s (%s)\n", in.File, strings.ReplaceAll(in.Kind, "_", " "))
+	if s := in.Signature; s != nil {
+		fmt.Fprintln(w, "\nsignature")
+		fmt.Fprintf(w, "  alg          %s, created %s\n", s.Alg, s.CreatedAt)
+		fmt.Fprintf(w, "  signer       %s %s\n", s.SignerType, s.SignerID)
+		fmt.Fprintf(w, "  fingerprint  %s\n", s.Fingerprint)
+		fmt.Fprintf(w, "  bundle_hash  %s (%s)\n", s.BundleHash.Computed, mark(s.BundleHash))
+		if s.MerkleRoot != nil {
+			fmt.Fprintf(w, "  merkle_root  %s (%s)\n", s.MerkleRoot.Computed, mark(*s.MerkleRoot))
+		}
+	}
+
+	p := in.Principal
+	fmt.Fprintln(w, "\nprincipal")
+	fmt.Fprintf(w, "  human_id     %s\n", p.HumanID)
+	fmt.Fprintf(w, "  name         %s (%s, %s)\n", p.LegalName, p.EntityType, p.Jurisdiction)
+	fmt.Fprintf(w, "  issued       %s\n", p.IssuedAt)
+	if p.ExpiresAt == "" {
+		fmt.Fprintf(w, "  expires      never\n")
+	} else {
+		fmt.Fprintf(w, "  expires      %s (%s)\n", p.ExpiresAt, p.Expiry)
+	}
+	fmt.Fprintf(w, "  signed       %s\n", yesNo(p.Signed))
+
+	a := in.Agent
+	fmt.Fprintln(w, "\nagent")
+	fmt.Fprintf(w, "  agent_id     %s (%s, %s risk)\n", a.AgentID, a.Status, a.RiskTier)
+	fmt.Fprintf(w, "  fingerprint  %s\n", a.Fingerprint)
+	fmt.Fprintf(w, "  capabilities %s\n", strings.Join(a.Capabilities, ", "))
+	fmt.Fprintf(w, "  bound        %s\n", yesNo(a.BoundToPrincipal))
+	fmt.Fprintf(w, "  signed       %s\n", yesNo(a.Signed))
+
+	fmt.Fprintln(w, "\nintent")
+	fmt.Fprintf(w, "  intent_id    %s\n", in.Intent.IntentID)
+	fmt.Fprintf(w, "  action       %s via %s (%s impact)\n", in.Intent.ActionType, in.Intent.Channel, in.Intent.Impact)
+	fmt.Fprintf(w, "  hash         %s\n", in.Intent.Hash)
+	fmt.Fprintf(w, "  decision     %s, risk score %g", in.Policy.Decision, in.Policy.RiskScore)
+	if len(in.Policy.Reasons) > 0 {
+		fmt.Fprintf(w, " (%s)", strings.Join(in.Policy.Reasons, ", "))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "\naudit chain (%d entries, starts at %s)\n", len(in.Entries), in.ChainStart)
+	for _, en := range in.Entries {
+		fmt.Fprintf(w, "  #%d %s  %s  %s: %s\n", en.Index, en.AuditID, en.Timestamp, en.PolicyDecision, en.Outcome)
+		fmt.Fprintf(w, "     hash         %s\n", en.Hash)
+		fmt.Fprintf(w, "     prev_hash    %s\n", mark(en.PrevHash))
+		fmt.Fprintf(w, "     intent_hash  %s\n", mark(en.IntentHash))
+		fmt.Fprintf(w, "     agent sig    %s\n", en.AgentSignature)
+	}
+}
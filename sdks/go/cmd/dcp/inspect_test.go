@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInspect(t *testing.T) {
+	_, signed := signedExample(t)
+	stdout, stderr, code := runCLI(t, nil, "inspect", "--format", "json", signed)
+	if code != exitOK {
+		t.Fatal(stderr)
+	}
+	var in inspection
+	if err := json.Unmarshal([]byte(stdout), &in); err != nil {
+		t.Fatal(err)
+	}
+	if in.Signature == nil || !in.Signature.BundleHash.Match || !in.Signature.MerkleRoot.Match {
+		t.Fatalf("signature = %+v", in.Signature)
+	}
+	if len(in.Entries) != 2 || in.ChainStart != "GENESIS" {
+		t.Fatalf("entries = %+v", in.Entries)
+	}
+	for _, e := range in.Entries {
+		if !e.PrevHash.Match || !e.IntentHash.Match {
+			t.Errorf("entry %d = %+v", e.Index, e)
+		}
+	}
+	if in.Entries[1].PrevHash.Computed != in.Entries[0].Hash {
+		t.Error("second entry not linked to the first")
+	}
+	if in.Principal.Expiry != "no expiry" || !in.Agent.BoundToPrincipal {
+		t.Fatalf("principal = %+v, agent = %+v", in.Principal, in.Agent)
+	}
+}
+
+func TestInspectShowsMismatches(t *testing.T) {
+	_, signed := signedExample(t)
+	data, _ := os.ReadFile(signed)
+	tampered := filepath.Join(t.TempDir(), "tampered.json")
+	os.WriteFile(tampered, []byte(strings.Replace(string(data), `"outcome": "`, `"outcome": "x`, 1)), 0o644)
+
+	stdout, _, code := runCLI(t, nil, "inspect", tampered)
+	if code != exitOK {
+		t.Fatalf("code = %d", code)
+	}
+	// The first entry's hash changes, so bundle_hash, merkle_root and the
+	// second entry's prev_hash no longer match.
+	if n := strings.Count(stdout, "MISMATCH"); n != 3 {
+		t.Fatalf("%d mismatches in\n%s", n, stdout)
+	}
+}
+
+func TestInspectExpiry(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(examplesDir(), "citizenship_bundle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), `"expires_at": null`, `"expires_at": "2027-01-01T00:00:00Z"`, 1))
+	for now, want := range map[string]string{"2026-06-01T00:00:00Z": "valid", "2027-06-01T00:00:00Z": "expired"} {
+		at, _ := time.Parse(time.RFC3339, now)
+		in, err := inspectBundle(data, kindBundle, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if in.Principal.Expiry != want || in.Signature != nil {
+			t.Errorf("at %s: expiry = %q, want %q", now, in.Principal.Expiry, want)
+		}
+	}
+}
+
+func TestInspectRejectsRecords(t *testing.T) {
+	_, stderr, code := runCLI(t, nil, "inspect", filepath.Join(examplesDir(), "intent.json"))
+	if code != exitError || !strings.Contains(stderr, "not a bundle") {
+		t.Fatalf("code = %d, stderr = %s", code, stderr)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/providers"
+	v2 "github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/v2"
+)
+
+// signers are the algorithms keygen can generate.
+var signers = map[string]v2.CryptoProvider{
+	"ed25519":      &providers.Ed25519Provider{},
+	"ml-dsa-65":    &providers.MlDsa65Provider{},
+	"slh-dsa-192f": &providers.SlhDsa192fProvider{},
+}
+
+func signerNames() string {
+	names := make([]string, 0, len(signers))
+	for name := range signers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func runKeygen(e *env, args []string) int {
+	fs := e.flags("keygen", "[flags]")
+	alg := fs.String("alg", "ed25519", "algorithm: "+signerNames())
+	out := fs.String("out", "keys", "output directory")
+	format := fs.String("format", "text", "secret key format: text (base64), pem, or keystore (passphrase-encrypted)")
+	passFile := fs.String("passphrase-file", "", "file holding the keystore passphrase (default $"+passphraseEnv+")")
+	force := fs.Bool("force", false, "overwrite existing key files")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitError
+	}
+	provider, ok := signers[*alg]
+	if !ok {
+		return e.errorf("keygen: unsupported algorithm %q (supported: %s)", *alg, signerNames())
+	}
+
+	var kp *v2.GeneratedKeypair
+	if *alg == "ed25519" {
+		// dcp.GenerateKeypair honours dcp.SetEntropy.
+		k, err := dcp.GenerateKeypair()
+		if err != nil {
+			return e.errorf("keygen: %v", err)
+		}
+		pub, _ := base64.StdEncoding.DecodeString(k.PublicKeyB64)
+		kp = &v2.GeneratedKeypair{Kid: v2.DeriveKid("ed25519", pub), PublicKeyB64: k.PublicKeyB64, SecretKeyB64: k.SecretKeyB64}
+	} else {
+		var err error
+		if kp, err = provider.GenerateKeypair(); err != nil {
+			return e.errorf("keygen: %v", err)
+		}
+	}
+
+	files := map[string][]byte{"kid.txt": []byte(kp.Kid + "\n")}
+	secret := map[string]bool{}
+	switch *format {
+	case "text":
+		files["public_key.txt"] = []byte(kp.PublicKeyB64 + "\n")
+		files["secret_key.txt"] = []byte(kp.SecretKeyB64 + "\n")
+		secret["secret_key.txt"] = true
+	case "pem":
+		pubPEM, secPEM, err := encodePEM(*alg, kp)
+		if err != nil {
+			return e.errorf("keygen: %v", err)
+		}
+		files["public_key.pem"] = pubPEM
+		files["secret_key.pem"] = secPEM
+		secret["secret_key.pem"] = true
+	case "keystore":
+		pass, err := readPassphrase(e, *passFile)
+		if err != nil {
+			return e.errorf("keygen: %v", err)
+		}
+		ks, err := dcp.EncryptKey(*alg, kp.Kid, kp.PublicKeyB64, kp.SecretKeyB64, pass)
+		if err != nil {
+			return e.errorf("keygen: %v", err)
+		}
+		data, err := json.MarshalIndent(ks, "", "  ")
+		if err != nil {
+			return e.errorf("keygen: %v", err)
+		}
+		files["public_key.txt"] = []byte(kp.PublicKeyB64 + "\n")
+		files["keystore.json"] = append(data, '\n')
+		secret["keystore.json"] = true
+	default:
+		return e.errorf("keygen: unknown format %q (text, pem or keystore)", *format)
+	}
+	if err := writeKeyFiles(*out, files, secret, *force); err != nil {
+		return e.errorf("keygen: %v", err)
+	}
+	printKey(e, *alg, kp, *out)
+	return exitOK
+}
+
+func printKey(e *env, alg string, kp *v2.GeneratedKeypair, dir string) {
+	fmt.Fprintf(e.stdout, "alg:         %s\n", alg)
+	fmt.Fprintf(e.stdout, "kid:         %s\n", kp.Kid)
+	fmt.Fprintf(e.stdout, "fingerprint: %s\n", fingerprint(kp.PublicKeyB64))
+	fmt.Fprintf(e.stdout, "written to:  %s\n", dir)
+}
+
+// fingerprint is the SHA-256 of the raw public key, in the SSH style.
+func fingerprint(publicKeyB64 string) string {
+	pub, err := base64.StdEncoding.DecodeString(publicKeyB64)
+	if err != nil {
+		return "invalid public key"
+	}
+	sum := sha256.Sum256(pub)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// encodePEM encodes Ed25519 keys as PKIX and PKCS#8. Post-quantum keys have
+// no standard container yet, so their raw bytes go in blocks named after
+// the algorithm.
+func encodePEM(alg string, kp *v2.GeneratedKeypair) (pubPEM, secPEM []byte, err error) {
+	pub, err := base64.StdEncoding.DecodeString(kp.PublicKeyB64)
+	if err != nil {
+		return nil, nil, err
+	}
+	sec, err := base64.StdEncoding.DecodeString(kp.SecretKeyB64)
+	if err != nil {
+		return nil, nil, err
+	}
+	if alg != "ed25519" {
+		name := strings.ToUpper(alg)
+		return pem.EncodeToMemory(&pem.Block{Type: name + " PUBLIC KEY", Bytes: pub}),
+			pem.EncodeToMemory(&pem.Block{Type: name + " PRIVATE KEY", Bytes: sec}), nil
+	}
+	pkix, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(pub))
+	if err != nil {
+		return nil, nil, err
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(ed25519.PrivateKey(sec))
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), nil
+}
+
+func checkOverwrite(path string, force bool) error {
+	if force {
+		return nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s exists; use --force to overwrite", path)
+	}
+	return nil
+}
+
+// writeKeyFiles writes files into dir, secret ones with mode 0600. No file
+// is written if any already exists and force is not set.
+func writeKeyFiles(dir string, files map[string][]byte, secret map[string]bool, force bool) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name := range files {
+		if err := checkOverwrite(filepath.Join(dir, name), force); err != nil {
+			return err
+		}
+	}
+	for name, data := range files {
+		mode := os.FileMode(0o644)
+		if secret[name] {
+			mode = 0o600
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, mode); err != nil {
+			return err
+		}
+		// WriteFile keeps the mode of an existing file.
+		if err := os.Chmod(path, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
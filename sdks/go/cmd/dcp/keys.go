@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// passphraseEnv is read when no --passphrase-file is given.
+const passphraseEnv = "DCP_KEYSTORE_PASSPHRASE"
+
+// readPassphrase reads the keystore passphrase from file, or from the
+// environment when file is empty.
+func readPassphrase(e *env, file string) ([]byte, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strings.TrimRight(string(data), "\r\n")), nil
+	}
+	if p := e.getenv(passphraseEnv); p != "" {
+		return []byte(p), nil
+	}
+	return nil, fmt.Errorf("a passphrase is required: use --passphrase-file or set %s", passphraseEnv)
+}
+
+// loadSigner loads an Ed25519 secret key in any of the formats keygen
+// writes: base64 text, PEM, or an encrypted keystore.
+func loadSigner(e *env, path, passFile string) (*dcp.KeySigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	alg, secretB64 := "ed25519", ""
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		ks, err := dcp.ReadKeystore(path)
+		if err != nil {
+			return nil, err
+		}
+		pass, err := readPassphrase(e, passFile)
+		if err != nil {
+			return nil, err
+		}
+		if secretB64, err = ks.Decrypt(pass); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		alg = ks.Alg
+	case bytes.HasPrefix(trimmed, []byte("-----BEGIN")):
+		block, _ := pem.Decode(trimmed)
+		if block == nil {
+			return nil, fmt.Errorf("%s: invalid PEM", path)
+		}
+		if block.Type != "PRIVATE KEY" {
+			return nil, fmt.Errorf("%s: %s is not an Ed25519 key", path, block.Type)
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		sk, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%s: %T is not an Ed25519 key", path, key)
+		}
+		secretB64 = base64.StdEncoding.EncodeToString(sk)
+	default:
+		secretB64 = string(trimmed)
+	}
+	if alg != "ed25519" {
+		return nil, fmt.Errorf("%s: %s keys cannot sign V1 documents; use an ed25519 key", path, alg)
+	}
+	return dcp.NewKeySigner(secretB64)
+}
@@ -0,0 +1,120 @@
+// Command dcp is the Digital Citizenship Protocol command-line tool.
+//
+// Usage:
+//
+//	dcp <command> [flags] [args]
+//
+// Run "dcp help" for the list of commands. Exit status is 0 on success, 1
+// when a check fails (for example a bundle does not verify), and 2 for
+// usage and I/O errors.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitFail  = 1
+	exitError = 2
+)
+
+// command is a dcp subcommand. run receives the arguments after the
+// command name.
+type command struct {
+	summary string
+	run     func(env *env, args []string) int
+}
+
+var commands = map[string]command{
+	"keygen":     {"generate a keypair", runKeygen},
+	"sign":       {"sign a bundle or record", runSign},
+	"audit":      {"append to an audit ledger", runAudit},
+	"revoke":     {"revoke an agent", runRevoke},
+	"serve":      {"run a DCP HTTP service", runServe},
+	"diff":       {"compare two bundles or records", runDiff},
+	"doctor":     {"check a bundle against the schema and best practices", runDoctor},
+	"tui":        {"explore a bundle or ledger interactively", runTUI},
+	"inspect":    {"show a bundle with computed hashes and chain links", runInspect},
+	"sitepolicy": {"generate or check a site's dcp.txt", runSitePolicy},
+	"verify":     {"verify signed bundles", runVerify},
+}
+
+// env carries the process streams so commands can be tested.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+func (e *env) errorf(format string, args ...interface{}) int {
+	fmt.Fprintf(e.stderr, "dcp: "+format+"\n", args...)
+	return exitError
+}
+
+// flags returns a flag set for a command that reports errors to stderr.
+func (e *env) flags(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: dcp %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args into fs, returning the exit code to use if parsing
+// failed or help was requested.
+func parse(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitError, false
+	}
+	return 0, true
+}
+
+func (e *env) usage() {
+	fmt.Fprintln(e.stderr, "usage: dcp <command> [flags] [args]")
+	fmt.Fprintln(e.stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(e.stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(e.stderr, "\nRun \"dcp <command> -h\" for a command's flags.")
+}
+
+func run(e *env, args []string) int {
+	if len(args) == 0 {
+		e.usage()
+		return exitError
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		e.usage()
+		return exitOK
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "dcp: unknown command %q\n", args[0])
+		e.usage()
+		return exitError
+	}
+	return cmd.run(e, args[1:])
+}
+
+func main() {
+	os.Exit(run(&env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}, os.Args[1:]))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// runCLI runs the command line with args and returns its output and exit code.
+func runCLI(t *testing.T, vars map[string]string, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	e := &env{stdin: strings.NewReader(""), stdout: &out, stderr: &errOut, getenv: func(k string) string { return vars[k] }}
+	code = run(e, args)
+	return out.String(), errOut.String(), code
+}
+
+func TestUnknownCommand(t *testing.T) {
+	if _, stderr, code := runCLI(t, nil, "frobnicate"); code != exitError || !strings.Contains(stderr, "keygen") {
+		t.Fatalf("code = %d, stderr = %s", code, stderr)
+	}
+	if _, _, code := runCLI(t, nil, "help"); code != exitOK {
+		t.Fatalf("help exit code = %d", code)
+	}
+}
+
+func TestKeygenText(t *testing.T) {
+	dir := t.TempDir()
+	stdout, stderr, code := runCLI(t, nil, "keygen", "--out", dir)
+	if code != exitOK {
+		t.Fatalf("code = %d: %s", code, stderr)
+	}
+	kid, _ := os.ReadFile(filepath.Join(dir, "kid.txt"))
+	if !strings.Contains(stdout, "kid:         "+strings.TrimSpace(string(kid))) || !strings.Contains(stdout, "fingerprint: SHA256:") {
+		t.Fatalf("stdout = %s", stdout)
+	}
+	if fi, _ := os.Stat(filepath.Join(dir, "secret_key.txt")); fi.Mode().Perm() != 0o600 {
+		t.Fatalf("secret key mode = %v", fi.Mode())
+	}
+	sk, _ := os.ReadFile(filepath.Join(dir, "secret_key.txt"))
+	sig, err := dcp.SignObject(map[string]string{"a": "b"}, strings.TrimSpace(string(sk)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, _ := os.ReadFile(filepath.Join(dir, "public_key.txt"))
+	if ok, _ := dcp.VerifyObject(map[string]string{"a": "b"}, sig, strings.TrimSpace(string(pk))); !ok {
+		t.Fatal("generated keys do not match")
+	}
+
+	if _, stderr, code := runCLI(t, nil, "keygen", "--out", dir); code != exitError || !strings.Contains(stderr, "--force") {
+		t.Fatalf("overwrite: code = %d, stderr = %s", code, stderr)
+	}
+	if _, _, code := runCLI(t, nil, "keygen", "--out", dir, "--force"); code != exitOK {
+		t.Fatal("--force should overwrite")
+	}
+	if _, _, code := runCLI(t, nil, "keygen", "--alg", "rsa", "--out", t.TempDir()); code != exitError {
+		t.Fatal("unsupported algorithm should fail")
+	}
+}
+
+func TestKeygenPEM(t *testing.T) {
+	dir := t.TempDir()
+	if _, stderr, code := runCLI(t, nil, "keygen", "--format", "pem", "--out", dir); code != exitOK {
+		t.Fatal(stderr)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "secret_key.pem"))
+	block, _ := pem.Decode(data)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "public_key.pem"))
+	block, _ = pem.Decode(data)
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil || !pub.(ed25519.PublicKey).Equal(key.(ed25519.PrivateKey).Public()) {
+		t.Fatalf("public key does not match: %v", err)
+	}
+}
+
+func TestKeygenKeystore(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, code := runCLI(t, nil, "keygen", "--format", "keystore", "--out", dir); code != exitError {
+		t.Fatal("keystore without a passphrase should fail")
+	}
+	vars := map[string]string{passphraseEnv: "s3cret"}
+	if _, stderr, code := runCLI(t, vars, "keygen", "--format", "keystore", "--alg", "ml-dsa-65", "--out", dir); code != exitOK {
+		t.Fatal(stderr)
+	}
+	ks, err := dcp.ReadKeystore(filepath.Join(dir, "keystore.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ks.Alg != "ml-dsa-65" {
+		t.Fatalf("alg = %s", ks.Alg)
+	}
+	if _, err := ks.Decrypt([]byte("s3cret")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// registryTimeout bounds a push to a revocation registry.
+const registryTimeout = 30 * time.Second
+
+func runRevoke(e *env, args []string) int {
+	fs := e.flags("revoke", "--agent <id> --human <id> --reason <text> --key <file> [flags]")
+	var attorneys listFlag
+	fs.Var(&attorneys, "attorney", "power of attorney JSON letting the --key holder revoke for --human, the human's grant first (repeatable)")
+	agentID := fs.String("agent", "", "agent_id to revoke")
+	humanID := fs.String("human", "", "human_id of the responsible principal revoking the agent")
+	reason := fs.String("reason", "", "reason for the revocation")
+	keyPath := fs.String("key", "", "principal secret key, or with --attorney the attorney's (text, PEM or keystore)")
+	passFile := fs.String("passphrase-file", "", "file holding the keystore passphrase (default $"+passphraseEnv+")")
+	listPath := fs.String("list", "revocations.json", "local revocation list to append to, created if missing")
+	out := fs.String("out", "", "also write the signed record to this file (- for stdout)")
+	registry := fs.String("registry", "", "revocation registry endpoint to POST the record to, e.g. http://localhost:3003/revoke")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 0 || *agentID == "" || *humanID == "" || *reason == "" || *keyPath == "" {
+		fs.Usage()
+		return exitError
+	}
+	signer, err := loadSigner(e, *keyPath, *passFile)
+	if err != nil {
+		return e.errorf("revoke: %v", err)
+	}
+	list, err := dcp.ReadRevocationList(*listPath)
+	if err != nil {
+		return e.errorf("revoke: %v", err)
+	}
+	if prev, ok := list.Lookup(*agentID); ok {
+		fmt.Fprintf(e.stderr, "%s: %s was already revoked at %s (%s)\n", *listPath, *agentID, prev.Timestamp, prev.Reason)
+		return exitFail
+	}
+
+	rec := dcp.NewRevocationRecord(*agentID, *humanID, *reason)
+	for _, path := range attorneys {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return e.errorf("revoke: %v", err)
+		}
+		var poa dcp.PowerOfAttorney
+		if err := json.Unmarshal(data, &poa); err != nil {
+			return e.errorf("revoke: %s: %v", path, err)
+		}
+		rec.PowersOfAttorney = append(rec.PowersOfAttorney, poa)
+	}
+	if err := rec.Sign(signer); err != nil {
+		return e.errorf("revoke: %v", err)
+	}
+	if err := rec.Validate(); err != nil {
+		reportInvalid(e, "revocation", err)
+		return exitFail
+	}
+	list.Add(rec)
+	if err := dcp.WriteRevocationList(*listPath, list); err != nil {
+		return e.errorf("revoke: %v", err)
+	}
+	fmt.Fprintf(e.stderr, "%s: revoked %s\n", *listPath, *agentID)
+	if *out != "" {
+		if err := writeJSON(e, *out, rec); err != nil {
+			return e.errorf("revoke: %v", err)
+		}
+	}
+	if *registry != "" {
+		if err := pushRevocation(*registry, rec); err != nil {
+			return e.errorf("revoke: %s: %v (the record is in %s)", *registry, err, *listPath)
+		}
+		fmt.Fprintf(e.stderr, "%s: published\n", *registry)
+	}
+	return exitOK
+}
+
+// pushRevocation POSTs rec to a registry speaking the services/revocation
+// API, which answers 201 on success and {"error": ...} otherwise.
+func pushRevocation(url string, rec dcp.RevocationRecord) error {
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: registryTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(msg, &apiErr) == nil && apiErr.Error != "" {
+		return fmt.Errorf("%s: %s", resp.Status, apiErr.Error)
+	}
+	return fmt.Errorf("%s", resp.Status)
+}
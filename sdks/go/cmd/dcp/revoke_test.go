@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestRevoke(t *testing.T) {
+	keys := testKeys(t)
+	dir := t.TempDir()
+	list := filepath.Join(dir, "revocations.json")
+
+	var pushed dcp.RevocationRecord
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/revoke" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&pushed)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer registry.Close()
+
+	args := []string{"revoke", "--agent", "dcp:agent:a1", "--human", "dcp:human:h1", "--reason", "key compromised",
+		"--key", filepath.Join(keys, "secret_key.txt"), "--list", list, "--registry", registry.URL + "/revoke"}
+	if _, stderr, code := runCLI(t, nil, args...); code != exitOK {
+		t.Fatal(stderr)
+	}
+	l, err := dcp.ReadRevocationList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, ok := l.Lookup("dcp:agent:a1")
+	if !ok {
+		t.Fatal("agent not in the revocation list")
+	}
+	if ok, err := rec.VerifySignature(readKey(t, keys, "public_key.txt")); !ok || err != nil {
+		t.Fatalf("VerifySignature = %v, %v", ok, err)
+	}
+	if !pushed.Equal(&rec) {
+		t.Fatalf("pushed %+v, want %+v", pushed, rec)
+	}
+
+	if _, _, code := runCLI(t, nil, args...); code != exitFail {
+		t.Fatalf("second revocation: code = %d", code)
+	}
+}
+
+func TestRevokeRegistryError(t *testing.T) {
+	keys := testKeys(t)
+	list := filepath.Join(t.TempDir(), "revocations.json")
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"Missing required fields"}`))
+	}))
+	defer registry.Close()
+
+	_, stderr, code := runCLI(t, nil, "revoke", "--agent", "dcp:agent:a1", "--human", "dcp:human:h1", "--reason", "r",
+		"--key", filepath.Join(keys, "secret_key.txt"), "--list", list, "--registry", registry.URL)
+	if code != exitError || !strings.Contains(stderr, "Missing required fields") {
+		t.Fatalf("code = %d, stderr = %s", code, stderr)
+	}
+	if l, _ := dcp.ReadRevocationList(list); !l.IsRevoked("dcp:agent:a1") {
+		t.Fatal("revocation not kept locally when the push failed")
+	}
+}
+
+func TestRevokeAsAttorney(t *testing.T) {
+	keys := testKeys(t)
+	dir := t.TempDir()
+	principal, _ := dcp.GenerateKeypair()
+	signer, _ := dcp.NewKeySigner(principal.SecretKeyB64)
+	poa := dcp.NewPowerOfAttorney(dcp.PrincipalMember{HumanID: "dcp:human:h1", PublicKey: principal.PublicKeyB64},
+		dcp.PrincipalMember{HumanID: "dcp:human:h2", PublicKey: readKey(t, keys, "public_key.txt")},
+		[]dcp.AttorneyScope{dcp.AttorneyRevoke}, time.Hour)
+	if err := poa.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	poaPath := filepath.Join(dir, "poa.json")
+	data, _ := json.Marshal(poa)
+	if err := os.WriteFile(poaPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	list := filepath.Join(dir, "revocations.json")
+	if _, stderr, code := runCLI(t, nil, "revoke", "--agent", "dcp:agent:a1", "--human", "dcp:human:h1", "--reason", "lost device",
+		"--key", filepath.Join(keys, "secret_key.txt"), "--attorney", poaPath, "--list", list); code != exitOK {
+		t.Fatal(stderr)
+	}
+	l, err := dcp.ReadRevocationList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, _ := l.Lookup("dcp:agent:a1")
+	if ok, err := rec.VerifyPrincipalSignature(principal.PublicKeyB64); !ok || err != nil {
+		t.Fatalf("VerifyPrincipalSignature = %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,727 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/fileledger"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/issuer"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/opa"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/passportlog"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/revocationserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/verifyserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/webhook"
+)
+
+// webhookSecretEnv is read when no --webhook-secret-file is given.
+const webhookSecretEnv = "DCP_WEBHOOK_SECRET"
+
+// redisPasswordEnv holds the password of the --redis server, if it has one.
+const redisPasswordEnv = "DCP_REDIS_PASSWORD"
+
+var serveCommands = map[string]command{
+	"verify":     {"serve POST /v1/verify for signed bundles", runServeVerify},
+	"grpc":       {"serve the dcp.v1 DcpService over gRPC", runServeGRPC},
+	"registry":   {"serve the passport and principal registry", runServeRegistry},
+	"revocation": {"serve the revocation registry and its signed list", runServeRevocation},
+	"pdp":        {"serve the DCP-02 policy decision point", runServePDP},
+	"issuer":     {"serve the principal and passport issuer", runServeIssuer},
+	"log":        {"serve the passport transparency log", runServeLog},
+}
+
+func runServe(e *env, args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "-h", "-help", "--help":
+			serveUsage(e)
+			return exitOK
+		}
+		if cmd, ok := serveCommands[args[0]]; ok {
+			return cmd.run(e, args[1:])
+		}
+		fmt.Fprintf(e.stderr, "dcp: unknown serve command %q\n", args[0])
+	}
+	serveUsage(e)
+	return exitError
+}
+
+func serveUsage(e *env) {
+	fmt.Fprintln(e.stderr, "usage: dcp serve <service> [flags]")
+	fmt.Fprintln(e.stderr, "\nservices:")
+	names := make([]string, 0, len(serveCommands))
+	for name := range serveCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(e.stderr, "  %-10s %s\n", name, serveCommands[name].summary)
+	}
+}
+
+// listFlag is a flag that may be repeated.
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// verifierFlags registers the flags configuring a verifyserver and returns
+// a function building it once the flags are parsed, and the request timeout.
+func verifierFlags(fs *flag.FlagSet) (func(*webhook.Dispatcher) (*verifyserver.Server, error), *time.Duration) {
+	var trusted listFlag
+	fs.Var(&trusted, "trusted-key", "signer public key to accept, base64 or a key file (repeatable; default: the key embedded in each bundle)")
+	var pdpKeys listFlag
+	fs.Var(&pdpKeys, "pdp-key", "PDP public key policy decisions may be signed with, base64 or a key file (repeatable)")
+	var countersigners listFlag
+	fs.Var(&countersigners, "countersigner", "public key allowed to countersign break-glass records, base64 or a key file (repeatable; required to verify bundles with them)")
+	var signerSets listFlag
+	fs.Var(&signerSets, "signer-set", "HUMAN_ID=FILE: the signer set of a group principal, as published outside its bundles (repeatable; required to verify bundles of one)")
+	var guardians listFlag
+	fs.Var(&guardians, "guardian-key", "HUMAN_ID=KEY: the guardian's key of a dependent principal, base64 or a key file, as published outside its bundles (repeatable; required to verify bundles of one)")
+	liability := fs.Bool("liability", false, "also enforce the obligations of the principal's liability mode (consent, PDP-signed decision, agent-signed entries)")
+	var insurerKeys listFlag
+	fs.Var(&insurerKeys, "insurer-key", "published key of an insurer or platform trusted to attest coverage, base64 or a key file (repeatable)")
+	revocations := revocationFlags(fs)
+	timeout := fs.Duration("timeout", verifyserver.DefaultTimeout, "per-request timeout, including revocation lookups")
+	maxBody := fs.Int64("max-body", verifyserver.DefaultMaxBodyBytes, "maximum request body in bytes")
+	build := func(hooks *webhook.Dispatcher) (*verifyserver.Server, error) {
+		cfg := verifyserver.Config{EnforceLiability: *liability, Timeout: *timeout, MaxBodyBytes: *maxBody, Webhooks: hooks}
+		for _, arg := range trusted {
+			key, err := loadPublicKey(arg)
+			if err != nil {
+				return nil, err
+			}
+			cfg.TrustedKeys = append(cfg.TrustedKeys, key)
+		}
+		for _, arg := range pdpKeys {
+			key, err := loadPublicKey(arg)
+			if err != nil {
+				return nil, err
+			}
+			cfg.PDPKeys = append(cfg.PDPKeys, key)
+		}
+		for _, arg := range countersigners {
+			key, err := loadPublicKey(arg)
+			if err != nil {
+				return nil, err
+			}
+			cfg.Countersigners = append(cfg.Countersigners, key)
+		}
+		for _, arg := range insurerKeys {
+			key, err := loadPublicKey(arg)
+			if err != nil {
+				return nil, err
+			}
+			cfg.InsurerKeys = append(cfg.InsurerKeys, key)
+		}
+		var err error
+		if cfg.SignerSets, err = loadSignerSets(signerSets); err != nil {
+			return nil, err
+		}
+		if cfg.Guardians, err = loadGuardians(guardians); err != nil {
+			return nil, err
+		}
+		if cfg.Revocations, err = revocations(); err != nil {
+			return nil, err
+		}
+		return verifyserver.New(cfg), nil
+	}
+	return build, timeout
+}
+
+// revocationFlags registers the flags naming revocation sources and returns
+// a function building them once the flags are parsed.
+func revocationFlags(fs *flag.FlagSet) func() ([]dcp.RevocationChecker, error) {
+	var files, registries listFlag
+	fs.Var(&files, "revocations", "revocation list file, re-read when it changes (repeatable)")
+	fs.Var(&registries, "revocation-registry", "revocation registry base URL, e.g. http://localhost:3003 (repeatable)")
+	registryKey := fs.String("revocation-registry-key", "", "list signing key of the revocation registries, base64 or a key file: check their signed /list instead of trusting /check answers")
+	return func() ([]dcp.RevocationChecker, error) {
+		var checkers []dcp.RevocationChecker
+		for _, path := range files {
+			src, err := verifyserver.NewFileRevocations(path)
+			if err != nil {
+				return nil, err
+			}
+			checkers = append(checkers, src)
+		}
+		listKey := ""
+		if *registryKey != "" {
+			var err error
+			if listKey, err = loadPublicKey(*registryKey); err != nil {
+				return nil, err
+			}
+		}
+		for _, u := range registries {
+			if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+				return nil, fmt.Errorf("revocation registry %q is not an http(s) URL", u)
+			}
+			if listKey != "" {
+				checkers = append(checkers, &revocationserver.ListChecker{URL: u, PublicKeyB64: listKey})
+			} else {
+				checkers = append(checkers, &verifyserver.RegistryRevocations{URL: u})
+			}
+		}
+		return checkers, nil
+	}
+}
+
+// webhookFlags registers the flags configuring event webhooks and returns
+// a function starting their dispatcher once the flags are parsed; it
+// returns nil when no --webhook is given.
+func webhookFlags(fs *flag.FlagSet) func(*env) (*webhook.Dispatcher, error) {
+	var urls listFlag
+	fs.Var(&urls, "webhook", "URL sent a signed POST for every protocol event (repeatable)")
+	secretFile := fs.String("webhook-secret-file", "", "file holding the webhook HMAC secret (default $"+webhookSecretEnv+")")
+	deadLetter := fs.String("webhook-dead-letter", "", "file undeliverable events are appended to (default: dropped)")
+	return func(e *env) (*webhook.Dispatcher, error) {
+		if len(urls) == 0 {
+			return nil, nil
+		}
+		secret := e.getenv(webhookSecretEnv)
+		if *secretFile != "" {
+			data, err := os.ReadFile(*secretFile)
+			if err != nil {
+				return nil, err
+			}
+			secret = strings.TrimRight(string(data), "\r\n")
+		}
+		if secret == "" {
+			return nil, fmt.Errorf("--webhook needs a secret: use --webhook-secret-file or set %s", webhookSecretEnv)
+		}
+		cfg := webhook.Config{DeadLetterPath: *deadLetter}
+		for _, u := range urls {
+			cfg.Endpoints = append(cfg.Endpoints, webhook.Endpoint{URL: u, Secret: secret})
+		}
+		return webhook.New(cfg)
+	}
+}
+
+// closeWebhooks flushes the events still queued for delivery, giving up
+// after timeout.
+func closeWebhooks(e *env, hooks *webhook.Dispatcher, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := hooks.Close(ctx); err != nil {
+		fmt.Fprintf(e.stderr, "dcp: webhooks: %v; undelivered events were dead-lettered\n", err)
+	}
+}
+
+func runServeVerify(e *env, args []string) int {
+	fs := e.flags("serve verify", "[flags]")
+	addr := fs.String("addr", ":8080", "listen address")
+	verifier, timeout := verifierFlags(fs)
+	webhooks := webhookFlags(fs)
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitError
+	}
+	events, err := webhooks(e)
+	if err != nil {
+		return e.errorf("serve verify: %v", err)
+	}
+	defer closeWebhooks(e, events, *timeout)
+	srv, err := verifier(events)
+	if err != nil {
+		return e.errorf("serve verify: %v", err)
+	}
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return e.errorf("serve verify: %v", err)
+	}
+	fmt.Fprintf(e.stderr, "dcp verify service listening on %s\n", ln.Addr())
+	if err := serveUntilSignal(ln, srv, *timeout); err != nil {
+		return e.errorf("serve verify: %v", err)
+	}
+	return exitOK
+}
+
+func runServeGRPC(e *env, args []string) int {
+	fs := e.flags("serve grpc", "[flags]")
+	addr := fs.String("addr", ":9090", "listen address")
+	verifier, timeout := verifierFlags(fs)
+	ledgerDir := fs.String("ledger-dir", "", "directory of per-agent fileledger files for AppendAudit (default: AppendAudit unimplemented)")
+	var passportFiles listFlag
+	fs.Var(&passportFiles, "passport", "agent passport JSON served by GetPassport and used to check agent signatures (repeatable)")
+	requireSig := fs.Bool("require-agent-signature", false, "reject audit entries without a verifiable agent_signature")
+	webhooks := webhookFlags(fs)
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitError
+	}
+	events, err := webhooks(e)
+	if err != nil {
+		return e.errorf("serve grpc: %v", err)
+	}
+	defer closeWebhooks(e, events, *timeout)
+	v, err := verifier(events)
+	if err != nil {
+		return e.errorf("serve grpc: %v", err)
+	}
+	cfg := grpcserver.Config{Verifier: v, RequireAgentSignature: *requireSig}
+	if len(passportFiles) > 0 {
+		passports := grpcserver.PassportMap{}
+		for _, path := range passportFiles {
+			raw, _, _, err := readDocument(path, kindPassport)
+			if err != nil {
+				return e.errorf("serve grpc: %v", err)
+			}
+			var p dcp.AgentPassport
+			if err := json.Unmarshal(raw, &p); err != nil {
+				return e.errorf("serve grpc: %s: %v", path, err)
+			}
+			if err := p.Validate(); err != nil {
+				reportInvalid(e, path, err)
+				return exitFail
+			}
+			passports[p.AgentID] = p
+		}
+		cfg.Passports = passports
+	}
+	if *ledgerDir != "" {
+		if err := os.MkdirAll(*ledgerDir, 0o700); err != nil {
+			return e.errorf("serve grpc: %v", err)
+		}
+		ledgers := &ledgerDirectory{dir: *ledgerDir, open: map[string]*fileledger.Store{}}
+		defer ledgers.Close()
+		cfg.Ledger = ledgers.Ledger
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return e.errorf("serve grpc: %v", err)
+	}
+	fmt.Fprintf(e.stderr, "dcp gRPC service (dcp.v1.DcpService) listening on %s\n", ln.Addr())
+	// Bound each call as the HTTP service does; clients may set shorter deadlines.
+	g := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := context.WithTimeout(ctx, *timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}))
+	grpcserver.New(cfg).Register(g)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		g.GracefulStop()
+	}()
+	if err := g.Serve(ln); err != nil {
+		return e.errorf("serve grpc: %v", err)
+	}
+	return exitOK
+}
+
+func runServeRegistry(e *env, args []string) int {
+	fs := e.flags("serve registry", "[flags]")
+	addr := fs.String("addr", ":8081", "listen address")
+	statePath := fs.String("state", "", "JSON file the registry is kept in (default: memory only)")
+	keyPath := fs.String("key", "", "secret key that signs GET /v1/snapshot (default: snapshots disabled)")
+	passFile := fs.String("passphrase-file", "", "file holding the keystore passphrase (default $"+passphraseEnv+")")
+	timeout := fs.Duration("timeout", verifyserver.DefaultTimeout, "per-request timeout")
+	maxBody := fs.Int64("max-body", verifyserver.DefaultMaxBodyBytes, "maximum request body in bytes")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitError
+	}
+	cfg := registry.Config{Path: *statePath, MaxBodyBytes: *maxBody}
+	if *keyPath != "" {
+		signer, err := loadSigner(e, *keyPath, *passFile)
+		if err != nil {
+			return e.errorf("serve registry: %v", err)
+		}
+		cfg.Signer = signer
+	}
+	reg, err := registry.New(cfg)
+	if err != nil {
+		return e.errorf("serve registry: %v", err)
+	}
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return e.errorf("serve registry: %v", err)
+	}
+	fmt.Fprintf(e.stderr, "dcp registry listening on %s\n", ln.Addr())
+	if err := serveUntilSignal(ln, reg, *timeout); err != nil {
+		return e.errorf("serve registry: %v", err)
+	}
+	return exitOK
+}
+
+func runServeRevocation(e *env, args []string) int {
+	fs := e.flags("serve revocation", "--key FILE [flags]")
+	addr := fs.String("addr", ":3003", "listen address")
+	listPath := fs.String("list", "", "revocation list file, shared with dcp revoke (default: memory only)")
+	keyPath := fs.String("key", "", "secret key that signs the published list (required)")
+	passFile := fs.String("passphrase-file", "", "file holding the keystore passphrase (default $"+passphraseEnv+")")
+	registryURL := fs.String("registry", "", "passport registry base URL; accept revocations signed by an agent's own principal")
+	var principals listFlag
+	fs.Var(&principals, "principal", "HUMAN_ID=KEY whose signed revocations of its agents are accepted (repeatable)")
+	timeout := fs.Duration("timeout", verifyserver.DefaultTimeout, "per-request timeout")
+	maxBody := fs.Int64("max-body", verifyserver.DefaultMaxBodyBytes, "maximum request body in bytes")
+	webhooks := webhookFlags(fs)
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 0 || *keyPath == "" {
+		fs.Usage()
+		return exitError
+	}
+	if *registryURL != "" && len(principals) > 0 {
+		return e.errorf("serve revocation: --registry and --principal are mutually exclusive")
+	}
+	signer, err := loadSigner(e, *keyPath, *passFile)
+	if err != nil {
+		return e.errorf("serve revocation: %v", err)
+	}
+	events, err := webhooks(e)
+	if err != nil {
+		return e.errorf("serve revocation: %v", err)
+	}
+	defer closeWebhooks(e, events, *timeout)
+	cfg := revocationserver.Config{Signer: signer, Path: *listPath, MaxBodyBytes: *maxBody, Webhooks: events}
+	switch {
+	case *registryURL != "":
+		if !strings.HasPrefix(*registryURL, "http://") && !strings.HasPrefix(*registryURL, "https://") {
+			return e.errorf("serve revocation: registry %q is not an http(s) URL", *registryURL)
+		}
+		cfg.Authority = &revocationserver.RegistryAuthority{URL: *registryURL}
+	case len(principals) > 0:
+		keys := revocationserver.KeyMap{}
+		for _, p := range principals {
+			humanID, arg, ok := strings.Cut(p, "=")
+			if !ok || humanID == "" {
+				return e.errorf("serve revocation: --principal %q is not HUMAN_ID=KEY", p)
+			}
+			key, err := loadPublicKey(arg)
+			if err != nil {
+				return e.errorf("serve revocation: %v", err)
+			}
+			keys[humanID] = key
+		}
+		cfg.Authority = keys
+	default:
+		fmt.Fprintln(e.stderr, "dcp: no --registry or --principal: serving the list read-only")
+	}
+	srv, err := revocationserver.New(cfg)
+	if err != nil {
+		return e.errorf("serve revocation: %v", err)
+	}
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return e.errorf("serve revocation: %v", err)
+	}
+	fmt.Fprintf(e.stderr, "dcp revocation registry listening on %s (list key %s)\n", ln.Addr(), signer.PublicKeyB64())
+	if err := serveUntilSignal(ln, srv, *timeout); err != nil {
+		return e.errorf("serve revocation: %v", err)
+	}
+	return exitOK
+}
+
+func runServePDP(e *env, args []string) int {
+	fs := e.flags("serve pdp", "--policy FILE|--policy-bundle FILE|--opa-bundle PATH|--opa-url URL --key FILE [flags]")
+	addr := fs.String("addr", ":8082", "listen address")
+	policyPath := fs.String("policy", "", "policy set JSON")
+	bundlePath := fs.String("policy-bundle", "", "signed policy bundle JSON")
+	var issuers listFlag
+	fs.Var(&issuers, "policy-issuer-key", "public key trusted to sign policy bundles, base64 or a key file (repeatable; required with --policy-bundle)")
+	opaBundle := fs.String("opa-bundle", "", "OPA bundle, a directory or .tar.gz, to decide with instead of a policy set")
+	opaURL := fs.String("opa-url", "", "OPA server base URL to decide with instead of a policy set")
+	opaQuery := fs.String("opa-query", opa.DefaultQuery, "Rego query of the decision document")
+	keyPath := fs.String("key", "", "secret key that signs decisions (required)")
+	passFile := fs.String("passphrase-file", "", "file holding the keystore passphrase (default $"+passphraseEnv+")")
+	revocations := revocationFlags(fs)
+	registryURL := fs.String("registry", "", "registry base URL the agent's passport and principal record are looked up in")
+	redisAddr := fs.String("redis", "", "Redis host:port the rate-limit counters are shared in (password from $"+redisPasswordEnv+"; default in memory)")
+	redisDB := fs.Int("redis-db", 0, "Redis database number")
+	cacheTTL := fs.Duration("cache-ttl", 0, "how long decisions are cached per agent and intent shape (default no cache)")
+	replayTTL := fs.Duration("replay-ttl", 0, "refuse an intent presented again within this window, shared in --redis if set (default no replay protection)")
+	timeout := fs.Duration("timeout", verifyserver.DefaultTimeout, "per-request timeout, including revocation and passport lookups")
+	maxBody := fs.Int64("max-body", verifyserver.DefaultMaxBodyBytes, "maximum request body in bytes")
+	webhooks := webhookFlags(fs)
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	sources := 0
+	for _, v := range []string{*policyPath, *bundlePath, *opaBundle, *opaURL} {
+		if v != "" {
+			sources++
+		}
+	}
+	if fs.NArg() != 0 || sources != 1 || *keyPath == "" || (*bundlePath != "") != (len(issuers) > 0) {
+		fs.Usage()
+		return exitError
+	}
+	cfg := pdp.Config{Timeout: *timeout, MaxBodyBytes: *maxBody}
+	var policyDesc string
+	var err error
+	switch {
+	case *policyPath != "":
+		if cfg.Policy, err = pdp.ReadPolicySet(*policyPath); err != nil {
+			return e.errorf("serve pdp: %v", err)
+		}
+		policyDesc = fmt.Sprintf("%d rules", len(cfg.Policy.Rules))
+	case *bundlePath != "":
+		var keys []string
+		for _, arg := range issuers {
+			key, err := loadPublicKey(arg)
+			if err != nil {
+				return e.errorf("serve pdp: %v", err)
+			}
+			keys = append(keys, key)
+		}
+		if cfg.Bundle, err = pdp.ReadPolicyBundle(*bundlePath, keys); err != nil {
+			return e.errorf("serve pdp: %v", err)
+		}
+		policyDesc = fmt.Sprintf("policy version %s, %d rules", cfg.Bundle.Version, len(cfg.Bundle.Policy.Rules))
+	case *opaBundle != "":
+		if cfg.Backend, err = opa.New(context.Background(), opa.Config{Bundle: *opaBundle, Query: *opaQuery}); err != nil {
+			return e.errorf("serve pdp: %v", err)
+		}
+		policyDesc = "OPA bundle " + *opaBundle
+	default:
+		cfg.Backend = &opa.Remote{URL: *opaURL, Path: opa.QueryPath(*opaQuery)}
+		policyDesc = "OPA at " + *opaURL
+	}
+	signer, err := loadSigner(e, *keyPath, *passFile)
+	if err != nil {
+		return e.errorf("serve pdp: %v", err)
+	}
+	checkers, err := revocations()
+	if err != nil {
+		return e.errorf("serve pdp: %v", err)
+	}
+	events, err := webhooks(e)
+	if err != nil {
+		return e.errorf("serve pdp: %v", err)
+	}
+	defer closeWebhooks(e, events, *timeout)
+	cfg.Signer, cfg.Revocations, cfg.Webhooks = signer, checkers, events
+	if *registryURL != "" {
+		reg := &registry.Client{URL: *registryURL}
+		cfg.Passports, cfg.Principals = reg, registryPrincipals{reg}
+	}
+	if *redisAddr != "" {
+		counters := &pdp.RedisCounters{Addr: *redisAddr, Password: e.getenv(redisPasswordEnv), DB: *redisDB}
+		defer counters.Close()
+		cfg.Counters = counters
+	}
+	if *replayTTL > 0 {
+		counters := cfg.Counters
+		if counters == nil {
+			counters = pdp.NewMemoryCounters()
+		}
+		cfg.Replay, cfg.ReplayTTL = pdp.CounterReplayGuard{Counters: counters}, *replayTTL
+	}
+	if *cacheTTL > 0 {
+		cfg.Cache = pdp.NewDecisionCache(*cacheTTL, 0)
+	}
+	srv, err := pdp.New(cfg)
+	if err != nil {
+		return e.errorf("serve pdp: %v", err)
+	}
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return e.errorf("serve pdp: %v", err)
+	}
+	fmt.Fprintf(e.stderr, "dcp policy decision point listening on %s (%s, decision key %s)\n", ln.Addr(), policyDesc, signer.PublicKeyB64())
+	if err := serveUntilSignal(ln, srv, *timeout); err != nil {
+		return e.errorf("serve pdp: %v", err)
+	}
+	return exitOK
+}
+
+// registryPrincipals makes a registry client a pdp.PrincipalSource.
+type registryPrincipals struct{ *registry.Client }
+
+func (r registryPrincipals) Principal(ctx context.Context, humanID string) (*dcp.ResponsiblePrincipalRecord, error) {
+	info, err := r.Client.Principal(ctx, humanID)
+	if err != nil || info == nil {
+		return nil, err
+	}
+	return &info.Record, nil
+}
+
+func runServeIssuer(e *env, args []string) int {
+	fs := e.flags("serve issuer", "--registry URL [flags]")
+	addr := fs.String("addr", ":8083", "listen address")
+	registryURL := fs.String("registry", "", "passport registry base URL the issued records are published to (required)")
+	var hooks listFlag
+	fs.Var(&hooks, "proofing-webhook", "URL that must accept each principal request before issuance (repeatable)")
+	var caps listFlag
+	fs.Var(&caps, "capability", "capability passports may be issued with, covering narrower ones such as email:* for email:send (repeatable; default: any)")
+	validity := fs.Duration("validity", 0, "lifetime of issued principal records (default: no expiry)")
+	timeout := fs.Duration("timeout", 30*time.Second, "per-request timeout, including proofing and publication")
+	maxBody := fs.Int64("max-body", verifyserver.DefaultMaxBodyBytes, "maximum request body in bytes")
+	webhooks := webhookFlags(fs)
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 0 || *registryURL == "" {
+		fs.Usage()
+		return exitError
+	}
+	for _, u := range append([]string{*registryURL}, hooks...) {
+		if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+			return e.errorf("serve issuer: %q is not an http(s) URL", u)
+		}
+	}
+	events, err := webhooks(e)
+	if err != nil {
+		return e.errorf("serve issuer: %v", err)
+	}
+	defer closeWebhooks(e, events, *timeout)
+	cfg := issuer.Config{
+		Publisher:    &registry.Client{URL: *registryURL},
+		Validity:     *validity,
+		Timeout:      *timeout,
+		MaxBodyBytes: *maxBody,
+		Webhooks:     events,
+		Capabilities: caps,
+	}
+	for _, u := range hooks {
+		cfg.Proofers = append(cfg.Proofers, &issuer.WebhookProofer{URL: u})
+	}
+	if len(hooks) == 0 {
+		fmt.Fprintln(e.stderr, "dcp: no --proofing-webhook: principals are issued without identity proofing")
+	}
+	srv, err := issuer.New(cfg)
+	if err != nil {
+		return e.errorf("serve issuer: %v", err)
+	}
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return e.errorf("serve issuer: %v", err)
+	}
+	fmt.Fprintf(e.stderr, "dcp issuer listening on %s, publishing to %s\n", ln.Addr(), *registryURL)
+	if err := serveUntilSignal(ln, srv, *timeout); err != nil {
+		return e.errorf("serve issuer: %v", err)
+	}
+	return exitOK
+}
+
+func runServeLog(e *env, args []string) int {
+	fs := e.flags("serve log", "--key FILE [flags]")
+	addr := fs.String("addr", ":3002", "listen address")
+	logPath := fs.String("log", "", "log file, one entry per line (default: memory only)")
+	keyPath := fs.String("key", "", "secret key that signs tree heads (required)")
+	passFile := fs.String("passphrase-file", "", "file holding the keystore passphrase (default $"+passphraseEnv+")")
+	origin := fs.String("origin", passportlog.DefaultOrigin, "log name recorded in its checkpoints")
+	timeout := fs.Duration("timeout", verifyserver.DefaultTimeout, "per-request timeout")
+	maxBody := fs.Int64("max-body", verifyserver.DefaultMaxBodyBytes, "maximum request body in bytes")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 0 || *keyPath == "" {
+		fs.Usage()
+		return exitError
+	}
+	signer, err := loadSigner(e, *keyPath, *passFile)
+	if err != nil {
+		return e.errorf("serve log: %v", err)
+	}
+	srv, err := passportlog.New(passportlog.Config{Signer: signer, Origin: *origin, Path: *logPath, MaxBodyBytes: *maxBody})
+	if err != nil {
+		return e.errorf("serve log: %v", err)
+	}
+	defer srv.Close()
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return e.errorf("serve log: %v", err)
+	}
+	fmt.Fprintf(e.stderr, "dcp passport log %s listening on %s (%d entries, key %s)\n", *origin, ln.Addr(), srv.Checkpoint().TreeSize, signer.PublicKeyB64())
+	if err := serveUntilSignal(ln, srv, *timeout); err != nil {
+		return e.errorf("serve log: %v", err)
+	}
+	return exitOK
+}
+
+// ledgerDirectory keeps one fileledger per agent in dir, opened on first
+// use and kept open, since a ledger file may only be opened once.
+type ledgerDirectory struct {
+	dir  string
+	mu   sync.Mutex
+	open map[string]*fileledger.Store
+}
+
+func (d *ledgerDirectory) Ledger(ctx context.Context, agentID string) (dcp.LedgerStore, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if s, ok := d.open[agentID]; ok {
+		return s, nil
+	}
+	// QueryEscape keeps distinct IDs distinct and makes them safe file names.
+	s, err := fileledger.Open(filepath.Join(d.dir, url.QueryEscape(agentID)+".log"), fileledger.Options{})
+	if err != nil {
+		return nil, err
+	}
+	d.open[agentID] = s
+	return s, nil
+}
+
+func (d *ledgerDirectory) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, s := range d.open {
+		s.Close()
+	}
+}
+
+// serveUntilSignal serves h on ln until SIGINT or SIGTERM, then lets
+// in-flight requests finish.
+func serveUntilSignal(ln net.Listener, h http.Handler, timeout time.Duration) error {
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: timeout,
+		ReadTimeout:       timeout,
+		WriteTimeout:      2 * timeout,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
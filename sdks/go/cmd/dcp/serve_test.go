@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServeConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "registry.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"serve", "nope"}, "unknown serve command"},
+		{[]string{"serve", "verify", "--trusted-key", "not-a-key"}, "neither an Ed25519 public key"},
+		{[]string{"serve", "verify", "--revocations", filepath.Join(dir, "missing.json")}, "missing.json"},
+		{[]string{"serve", "verify", "--revocation-registry", "localhost:3003"}, "not an http(s) URL"},
+		{[]string{"serve", "grpc", "--passport", filepath.Join(dir, "passport.json")}, "passport.json"},
+		{[]string{"serve", "grpc", "--trusted-key", "not-a-key"}, "neither an Ed25519 public key"},
+		{[]string{"serve", "registry", "--key", filepath.Join(dir, "missing.key")}, "missing.key"},
+		{[]string{"serve", "registry", "--state", corrupt}, "registry.json"},
+		{[]string{"serve", "revocation"}, "usage"},
+		{[]string{"serve", "revocation", "--key", filepath.Join(dir, "missing.key")}, "missing.key"},
+		{[]string{"serve", "verify", "--revocation-registry-key", "not-a-key"}, "neither an Ed25519 public key"},
+		{[]string{"serve", "pdp", "--key", "k"}, "usage"},
+		{[]string{"serve", "issuer"}, "usage"},
+		{[]string{"serve", "log"}, "usage"},
+		{[]string{"serve", "log", "--key", filepath.Join(dir, "missing.key")}, "missing.key"},
+		{[]string{"serve", "issuer", "--registry", "localhost:8081"}, "not an http(s) URL"},
+		{[]string{"serve", "pdp", "--policy", corrupt, "--key", "k"}, "registry.json"},
+		{[]string{"serve", "pdp", "--policy", corrupt, "--opa-url", "http://localhost:8181", "--key", "k"}, "usage"},
+		{[]string{"serve", "pdp", "--opa-bundle", filepath.Join(dir, "missing"), "--key", "k"}, "missing"},
+		{[]string{"serve", "pdp", "--policy-bundle", corrupt, "--key", "k"}, "usage"},
+		{[]string{"serve", "pdp", "--policy-bundle", corrupt, "--policy-issuer-key", "not-a-key", "--key", "k"}, "neither an Ed25519 public key"},
+		{[]string{"serve", "verify", "--webhook", "https://hooks.example.com/dcp"}, "needs a secret"},
+		{[]string{"serve", "verify", "--webhook", "hooks.example.com", "--webhook-secret-file", corrupt}, "not an http(s) URL"},
+	} {
+		_, stderr, code := runCLI(t, nil, tc.args...)
+		if code != exitError || !strings.Contains(stderr, tc.want) {
+			t.Errorf("%v: exit %d, stderr %q", tc.args, code, stderr)
+		}
+	}
+}
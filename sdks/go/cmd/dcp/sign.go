@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+// DetachedSignature is what sign emits for documents with no signature
+// member of their own, such as intents.
+type DetachedSignature struct {
+	Alg          string `json:"alg"`
+	Type         string `json:"type"`
+	Hash         string `json:"hash"`
+	PublicKeyB64 string `json:"public_key_b64"`
+	SigB64       string `json:"sig_b64"`
+}
+
+// signatureMember is the member each self-signed record kind signs into.
+var signatureMember = map[string]string{
+	kindRPR:        "signature",
+	kindPassport:   "signature",
+	kindRevocation: "signature",
+	kindAuditEntry: "agent_signature",
+}
+
+func runSign(e *env, args []string) int {
+	fs := e.flags("sign", "--key <secret key> [flags] <document.json>")
+	keyPath := fs.String("key", "", "Ed25519 secret key: base64 text, PEM, or keystore")
+	passFile := fs.String("passphrase-file", "", "keystore passphrase file (default $"+passphraseEnv+")")
+	kind := fs.String("type", "", "document type (default: detected): bundle, responsible_principal_record, agent_passport, intent, audit_entry, revocation_record, policy_bundle")
+	out := fs.String("out", "", "output file (default stdout)")
+	signerType := fs.String("signer-type", "human", "bundle signer type: human or organization")
+	signerID := fs.String("signer-id", "", "bundle signer id (default the principal's human_id)")
+	guardian := fs.Bool("guardian", false, "countersign an agent passport as its dependent principal's guardian")
+	cosignAs := fs.String("cosign-as", "", "cosign a signed bundle as this member of its principal's signer set")
+	skipValidate := fs.Bool("skip-validate", false, "sign even if the document fails validation")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 1 || *keyPath == "" {
+		fs.Usage()
+		return exitError
+	}
+	path := fs.Arg(0)
+	signer, err := loadSigner(e, *keyPath, *passFile)
+	if err != nil {
+		return e.errorf("sign: %v", err)
+	}
+	raw, doc, k, err := readDocument(path, *kind)
+	if err != nil {
+		return e.errorf("sign: %v", err)
+	}
+	if *guardian && k != kindPassport {
+		return e.errorf("sign: --guardian takes an agent passport")
+	}
+	if (k == kindSignedBundle) != (*cosignAs != "") {
+		if *cosignAs != "" {
+			return e.errorf("sign: --cosign-as takes a signed bundle")
+		}
+		return e.errorf("sign: %s is already signed; sign its bundle member, or cosign it with --cosign-as", path)
+	}
+	typed, _ := record(k)
+	if err := json.Unmarshal(raw, typed); err != nil {
+		return e.errorf("sign: %s: %v", path, err)
+	}
+	if !*skipValidate {
+		if err := typed.Validate(); err != nil {
+			reportInvalid(e, path, err)
+			return exitFail
+		}
+	}
+
+	var result interface{}
+	switch k {
+	case kindSignedBundle:
+		sb := typed.(*dcp.SignedBundle)
+		err = dcp.CosignBundle(sb, signer, *cosignAs)
+		result = sb
+	case kindBundle:
+		result, err = dcp.SignBundle(typed.(*dcp.CitizenshipBundle), signer,
+			dcp.Signer{Type: *signerType, ID: *signerID}, time.Now())
+	case kindIntent:
+		result, err = signDetached(signer, k, doc)
+	case kindPolicyBundle:
+		b := typed.(*pdp.PolicyBundle)
+		err = b.Sign(signer)
+		result = b
+	case kindPassport:
+		// The agent's signature does not cover the guardian's
+		// countersignature, which covers the agent's.
+		if *guardian {
+			err = signInPlace(signer, doc, "guardian_signature")
+		} else {
+			countersig, ok := doc["guardian_signature"]
+			delete(doc, "guardian_signature")
+			err = signInPlace(signer, doc, "signature")
+			if ok {
+				doc["guardian_signature"] = countersig
+			}
+		}
+		result = doc
+	default:
+		err = signInPlace(signer, doc, signatureMember[k])
+		result = doc
+	}
+	if err != nil {
+		return e.errorf("sign: %v", err)
+	}
+	if err := writeJSON(e, *out, result); err != nil {
+		return e.errorf("sign: %v", err)
+	}
+	return exitOK
+}
+
+// signInPlace signs doc into member. The signature covers the document with
+// the member empty ("signature") or absent ("agent_signature" and
+// "guardian_signature"), matching dcp.SignObject, AuditEntry.SignAsAgent and
+// AgentPassport.GuardianCountersign; unknown members are kept and signed as
+// they are.
+func signInPlace(s dcp.BundleSigner, doc map[string]interface{}, member string) error {
+	if member == "agent_signature" || member == "guardian_signature" {
+		delete(doc, member)
+	} else {
+		doc[member] = ""
+	}
+	canon, err := dcp.Canonicalize(doc)
+	if err != nil {
+		return err
+	}
+	sig, err := s.SignCanonical(canon)
+	if err != nil {
+		return err
+	}
+	doc[member] = sig
+	return nil
+}
+
+func signDetached(s dcp.BundleSigner, kind string, doc map[string]interface{}) (*DetachedSignature, error) {
+	canon, err := dcp.Canonicalize(doc)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := s.SignCanonical(canon)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := dcp.HashObject(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &DetachedSignature{Alg: "ed25519", Type: kind, Hash: "sha256:" + hash, PublicKeyB64: s.PublicKeyB64(), SigB64: sig}, nil
+}
+
+// reportInvalid prints validation errors one per line.
+func reportInvalid(e *env, path string, err error) {
+	var verrs dcp.ValidationErrors
+	if !errors.As(err, &verrs) {
+		fmt.Fprintf(e.stderr, "%s: %v\n", path, err)
+		return
+	}
+	for _, fe := range verrs {
+		fmt.Fprintf(e.stderr, "%s: %s\n", path, fe)
+	}
+}
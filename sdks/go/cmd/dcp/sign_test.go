@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+func examplesDir() string {
+	_, thisFile, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(thisFile), "..", "..", "..", "..", "tests", "conformance", "examples")
+}
+
+// testKeys runs keygen into a temporary directory and returns it.
+func testKeys(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if _, stderr, code := runCLI(t, nil, "keygen", "--out", dir); code != exitOK {
+		t.Fatal(stderr)
+	}
+	return dir
+}
+
+func readKey(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func TestSignBundle(t *testing.T) {
+	keys := testKeys(t)
+	out := filepath.Join(t.TempDir(), "signed.json")
+	_, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(keys, "secret_key.txt"), "--out", out,
+		filepath.Join(examplesDir(), "citizenship_bundle.json"))
+	if code != exitOK {
+		t.Fatalf("code = %d: %s", code, stderr)
+	}
+	data, _ := os.ReadFile(out)
+	var sb dcp.SignedBundle
+	if err := json.Unmarshal(data, &sb); err != nil {
+		t.Fatal(err)
+	}
+	if r := dcp.VerifySignedBundle(&sb, readKey(t, keys, "public_key.txt")); !r.Verified {
+		t.Fatalf("signed bundle does not verify: %v", r.Errors)
+	}
+	if sb.Signature.SignerInfo.ID != sb.Bundle.ResponsiblePrincipalRecord.HumanID {
+		t.Fatalf("signer = %+v", sb.Signature.SignerInfo)
+	}
+}
+
+func TestSignCosignBundle(t *testing.T) {
+	keys, member := testKeys(t), testKeys(t)
+	dir := t.TempDir()
+	signed, cosigned := filepath.Join(dir, "signed.json"), filepath.Join(dir, "cosigned.json")
+	bundle := filepath.Join(examplesDir(), "citizenship_bundle.json")
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(keys, "secret_key.txt"), "--out", signed, bundle); code != exitOK {
+		t.Fatal(stderr)
+	}
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(member, "secret_key.txt"), "--cosign-as", "human002", bundle); code == exitOK || !strings.Contains(stderr, "takes a signed bundle") {
+		t.Fatalf("cosigned an unsigned bundle: %d %s", code, stderr)
+	}
+	_, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(member, "secret_key.txt"), "--cosign-as", "human002", "--out", cosigned, signed)
+	if code != exitOK {
+		t.Fatalf("code = %d: %s", code, stderr)
+	}
+	data, _ := os.ReadFile(cosigned)
+	var sb dcp.SignedBundle
+	if err := json.Unmarshal(data, &sb); err != nil {
+		t.Fatal(err)
+	}
+	if len(sb.Signature.Cosignatures) != 1 || sb.Signature.Cosignatures[0].HumanID != "human002" {
+		t.Fatalf("cosignatures = %+v", sb.Signature.Cosignatures)
+	}
+	if ok, err := dcp.VerifyObject(sb.Bundle, sb.Signature.Cosignatures[0].SigB64, readKey(t, member, "public_key.txt")); !ok || err != nil {
+		t.Fatalf("cosignature: %v, %v", ok, err)
+	}
+}
+
+func TestSignGuardianCountersignature(t *testing.T) {
+	agent, guardian := testKeys(t), testKeys(t)
+	dir := t.TempDir()
+	signed, countersigned := filepath.Join(dir, "signed.json"), filepath.Join(dir, "countersigned.json")
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(agent, "secret_key.txt"), "--out", signed,
+		filepath.Join(examplesDir(), "agent_passport.json")); code != exitOK {
+		t.Fatal(stderr)
+	}
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(guardian, "secret_key.txt"), "--guardian", "--out", countersigned, signed); code != exitOK {
+		t.Fatal(stderr)
+	}
+	// Signing again as the agent keeps the countersignature valid.
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(agent, "secret_key.txt"), "--out", countersigned, countersigned); code != exitOK {
+		t.Fatal(stderr)
+	}
+	data, _ := os.ReadFile(countersigned)
+	var p dcp.AgentPassport
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := p.VerifyGuardianSignature(readKey(t, guardian, "public_key.txt")); !ok || err != nil {
+		t.Fatalf("guardian signature: %v, %v", ok, err)
+	}
+	unsigned := p
+	unsigned.Signature, unsigned.GuardianSignature = "", ""
+	if ok, err := dcp.VerifyObject(unsigned, p.Signature, readKey(t, agent, "public_key.txt")); !ok || err != nil {
+		t.Fatalf("agent signature: %v, %v", ok, err)
+	}
+}
+
+func TestSignRecords(t *testing.T) {
+	keys := testKeys(t)
+	pub := readKey(t, keys, "public_key.txt")
+	sign := func(name string) map[string]interface{} {
+		t.Helper()
+		stdout, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(keys, "secret_key.txt"), filepath.Join(examplesDir(), name))
+		if code != exitOK {
+			t.Fatalf("%s: code = %d: %s", name, code, stderr)
+		}
+		var doc map[string]interface{}
+		if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+			t.Fatal(err)
+		}
+		return doc
+	}
+
+	rpr := sign("responsible_principal_record.json")
+	sig := rpr["signature"].(string)
+	rpr["signature"] = ""
+	if ok, _ := dcp.VerifyObject(rpr, sig, pub); !ok {
+		t.Fatal("responsible principal record signature does not verify")
+	}
+
+	raw, _ := json.Marshal(sign("audit_entry.json"))
+	var entry dcp.AuditEntry
+	json.Unmarshal(raw, &entry)
+	if ok, err := entry.VerifyAgentSignature(pub); !ok {
+		t.Fatalf("audit entry signature does not verify: %v", err)
+	}
+
+	detached := sign("intent.json")
+	intent, _ := os.ReadFile(filepath.Join(examplesDir(), "intent.json"))
+	canon, _ := dcp.CanonicalizeJSON(intent)
+	if ok, _ := dcp.VerifyCanonical(canon, detached["sig_b64"].(string), pub); !ok || detached["type"] != "intent" {
+		t.Fatalf("detached intent signature = %v", detached)
+	}
+}
+
+func TestSignPolicyBundle(t *testing.T) {
+	keys := testKeys(t)
+	dir := t.TempDir()
+	in, out := filepath.Join(dir, "policy-bundle.json"), filepath.Join(dir, "signed.json")
+	doc := `{"dcp_version": "1.0", "version": "2026.03.1", "effective_from": "2026-03-01T00:00:00Z",
+		"policy": {"rules": [{"name": "email", "channels": ["email"], "decision": "approve"}]}}`
+	if err := os.WriteFile(in, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(keys, "secret_key.txt"), "--out", out, in); code != exitOK {
+		t.Fatalf("code = %d: %s", code, stderr)
+	}
+	b, err := pdp.ReadPolicyBundle(out, []string{readKey(t, keys, "public_key.txt")})
+	if err != nil || b.Version != "2026.03.1" {
+		t.Fatalf("signed policy bundle: %+v, %v", b, err)
+	}
+}
+
+func TestSignRejectsInvalidDocument(t *testing.T) {
+	keys := testKeys(t)
+	doc := filepath.Join(t.TempDir(), "intent.json")
+	os.WriteFile(doc, []byte(`{"dcp_version":"1.0","intent_id":"i","action_type":"teleport"}`), 0o644)
+	_, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(keys, "secret_key.txt"), doc)
+	if code != exitFail || !strings.Contains(stderr, "/action_type") {
+		t.Fatalf("code = %d, stderr = %s", code, stderr)
+	}
+	if _, _, code := runCLI(t, nil, "sign", doc); code != exitError {
+		t.Fatal("missing --key should be a usage error")
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/sitepolicy"
+)
+
+// runSitePolicy generates a dcp.txt with one group from its flags, or
+// checks an existing one and prints it in canonical form.
+func runSitePolicy(e *env, args []string) int {
+	fs := e.flags("sitepolicy", "[flags] | --check <file>")
+	check := fs.String("check", "", "check this dcp.txt (text or JSON) instead of generating one")
+	path := fs.String("path", "/", "path prefix the group applies to")
+	tier := fs.String("max-risk-tier", "", "riskiest passport tier admitted: low, medium or high")
+	impact := fs.String("max-impact", "", "highest intent impact admitted: low, medium or high")
+	passport := fs.Bool("require-passport", false, "refuse agents that present no passport")
+	allowCaps := fs.String("allow-capability", "", "comma-separated capabilities admitted, e.g. browse,api_call")
+	denyCaps := fs.String("disallow-capability", "", "comma-separated capabilities refused")
+	allowData := fs.String("allow-data-class", "", "comma-separated data classes intents may touch")
+	denyData := fs.String("disallow-data-class", "", "comma-separated data classes intents may not touch")
+	contact := fs.String("contact", "", "how agent operators reach the site, e.g. mailto:agents@example.com")
+	terms := fs.String("terms", "", "URL of the site's terms for agents")
+	format := fs.String("format", "text", "output format: text or json")
+	out := fs.String("out", "", "write to this file instead of stdout")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitError
+	}
+
+	var p *sitepolicy.Policy
+	if *check != "" {
+		data, err := os.ReadFile(*check)
+		if err != nil {
+			return e.errorf("sitepolicy: %v", err)
+		}
+		if p, err = sitepolicy.Parse(data); err != nil {
+			fmt.Fprintf(e.stderr, "%s: %v\n", *check, err)
+			return exitFail
+		}
+	} else {
+		p = &sitepolicy.Policy{Version: dcp.DCPVersion, Contact: *contact, Terms: *terms, Rules: []sitepolicy.Rule{{
+			Path:                 *path,
+			MaxRiskTier:          dcp.RiskTier(*tier),
+			MaxImpact:            dcp.EstimatedImpact(*impact),
+			RequirePassport:      *passport,
+			AllowCapabilities:    splitList(*allowCaps),
+			DisallowCapabilities: splitList(*denyCaps),
+			AllowDataClasses:     splitList(*allowData),
+			DisallowDataClasses:  splitList(*denyData),
+		}}}
+		if err := p.Validate(); err != nil {
+			return e.errorf("sitepolicy: %v", err)
+		}
+	}
+
+	switch *format {
+	case "json":
+		if err := writeJSON(e, *out, p); err != nil {
+			return e.errorf("sitepolicy: %v", err)
+		}
+	case "text":
+		text, err := p.Text()
+		if err != nil {
+			return e.errorf("sitepolicy: %v", err)
+		}
+		if *out == "" || *out == "-" {
+			e.stdout.Write(text)
+		} else if err := os.WriteFile(*out, text, 0o644); err != nil {
+			return e.errorf("sitepolicy: %v", err)
+		}
+	default:
+		return e.errorf("sitepolicy: unknown format %q (text or json)", *format)
+	}
+	return exitOK
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/sitepolicy"
+)
+
+func TestSitePolicy(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "dcp.txt")
+	_, stderr, code := runCLI(t, nil, "sitepolicy", "--max-risk-tier", "medium", "--allow-capability", "browse, api_call",
+		"--disallow-data-class", "credentials", "--require-passport", "--contact", "mailto:agents@example.com", "--out", out)
+	if code != exitOK {
+		t.Fatalf("code = %d: %s", code, stderr)
+	}
+	data, _ := os.ReadFile(out)
+	p, err := sitepolicy.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := p.RuleFor("/")
+	if r == nil || r.MaxRiskTier != dcp.RiskTierMedium || !r.RequirePassport || len(r.AllowCapabilities) != 2 || p.Contact != "mailto:agents@example.com" {
+		t.Fatalf("generated:\n%s", data)
+	}
+
+	stdout, stderr, code := runCLI(t, nil, "sitepolicy", "--check", out, "--format", "json")
+	if code != exitOK || !strings.Contains(stdout, `"max_risk_tier": "medium"`) {
+		t.Fatalf("check: code = %d: %s%s", code, stdout, stderr)
+	}
+
+	os.WriteFile(out, []byte("Path: /\nMax-Risk-Tier: extreme\n"), 0o644)
+	if _, stderr, code := runCLI(t, nil, "sitepolicy", "--check", out); code != exitFail || !strings.Contains(stderr, "extreme") {
+		t.Fatalf("check of an invalid file: code = %d: %s", code, stderr)
+	}
+	if _, _, code := runCLI(t, nil, "sitepolicy", "--allow-capability", "payments:initiate:<=lots"); code != exitError {
+		t.Fatalf("invalid capability: code = %d", code)
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/fileledger"
+)
+
+// ledgerMagic starts every fileledger file.
+const ledgerMagic = "DCPLOG1\n"
+
+// check is one verification check shown by the explorer.
+type check struct {
+	Name   string
+	OK     bool
+	Detail string
+}
+
+// explorer is the state of a dcp tui session: the audit chain being
+// browsed, the checks run over it and the selected entry.
+type explorer struct {
+	title   string
+	start   string
+	checks  []check
+	entries []entryInfo
+	raw     [][]byte
+	cur     int
+	out     io.Writer
+}
+
+const tuiHelp = `commands:
+  l, list        list the audit entries
+  <n>            show entry n
+  n, next        show the next entry
+  p, prev        follow the current entry's prev_hash
+  j, json        show the current entry's JSON
+  c, checks      show verification status per check
+  h, help        show this help
+  q, quit        leave
+`
+
+func runTUI(e *env, args []string) int {
+	fs := e.flags("tui", "[flags] <bundle.json | ledger file>")
+	agentKey := fs.String("agent-key", "", "agent public key for checking agent_signature on ledger entries, base64 or a key file")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitError
+	}
+	path := fs.Arg(0)
+	key := ""
+	if *agentKey != "" {
+		var err error
+		if key, err = loadPublicKey(*agentKey); err != nil {
+			return e.errorf("tui: %v", err)
+		}
+	}
+	x, err := loadExplorer(path, key)
+	if err != nil {
+		return e.errorf("tui: %v", err)
+	}
+	x.out = e.stdout
+	x.run(e.stdin)
+	return exitOK
+}
+
+// loadExplorer opens a bundle, or a fileledger file recognized by its magic.
+func loadExplorer(path, agentKey string) (*explorer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	head := make([]byte, len(ledgerMagic))
+	n, _ := io.ReadFull(f, head)
+	f.Close()
+	if string(head[:n]) == ledgerMagic {
+		return ledgerExplorer(path, agentKey)
+	}
+	return bundleExplorer(path)
+}
+
+func bundleExplorer(path string) (*explorer, error) {
+	raw, _, kind, err := readDocument(path, "")
+	if err != nil {
+		return nil, err
+	}
+	if kind != kindSignedBundle && kind != kindBundle {
+		return nil, fmt.Errorf("%s is a %s, not a bundle or ledger", path, kind)
+	}
+	in, err := inspectBundle(raw, kind, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	x := &explorer{title: fmt.Sprintf("%s (%s)", path, strings.ReplaceAll(kind, "_", " ")), start: in.ChainStart, entries: in.Entries}
+
+	var members struct {
+		AuditEntries []json.RawMessage `json:"audit_entries"`
+	}
+	var outer struct {
+		Bundle json.RawMessage `json:"bundle"`
+	}
+	rawBundle := raw
+	if kind == kindSignedBundle && json.Unmarshal(raw, &outer) == nil {
+		rawBundle = outer.Bundle
+	}
+	json.Unmarshal(rawBundle, &members)
+	for _, m := range members.AuditEntries {
+		var buf bytes.Buffer
+		json.Indent(&buf, m, "", "  ")
+		x.raw = append(x.raw, buf.Bytes())
+	}
+
+	if s := in.Signature; s != nil {
+		rsb, err := dcp.ParseSignedBundle(raw)
+		if err != nil {
+			return nil, err
+		}
+		result := dcp.VerifyRawSignedBundle(rsb, "")
+		x.checks = append(x.checks, check{"bundle verification (embedded key)", result.Verified, strings.Join(result.Errors, "; ")})
+		x.checks = append(x.checks, hashChecked("bundle_hash", s.BundleHash))
+		if s.MerkleRoot != nil {
+			x.checks = append(x.checks, hashChecked("merkle_root", *s.MerkleRoot))
+		} else {
+			x.checks = append(x.checks, check{"merkle_root", true, "not claimed"})
+		}
+	}
+	x.checks = append(x.checks, check{"principal binding", in.Agent.BoundToPrincipal, in.Agent.AgentID + " -> " + in.Principal.HumanID})
+	x.checks = append(x.checks, check{"principal expiry", in.Principal.Expiry != "expired" && in.Principal.Expiry != "invalid", in.Principal.Expiry})
+	x.chainChecks(true)
+	return x, nil
+}
+
+func ledgerExplorer(path, agentKey string) (*explorer, error) {
+	ctx := context.Background()
+	store, err := fileledger.Open(path, fileledger.Options{NoSync: true})
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	n, err := store.Len(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := dcp.LedgerEntries(ctx, store, 0, n)
+	if err != nil {
+		return nil, err
+	}
+	x := &explorer{title: fmt.Sprintf("%s (ledger, %d entries)", path, n), start: "GENESIS"}
+	prev := x.start
+	for i, entry := range entries {
+		raw, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		info, err := inspectEntry(raw, agentKey)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %v", i, err)
+		}
+		info.Index = i
+		info.PrevHash = newHashCheck(info.PrevHash.Claimed, prev)
+		prev = info.Hash
+		x.entries = append(x.entries, info)
+		x.raw = append(x.raw, raw)
+	}
+	_, result := dcp.VerifyLedger(ctx, store, dcp.AuditStreamOptions{AgentPublicKeyB64: agentKey})
+	x.checks = append(x.checks, check{"ledger verification", result.Verified, strings.Join(result.Errors, "; ")})
+	x.chainChecks(false)
+	return x, nil
+}
+
+func hashChecked(name string, c hashCheck) check {
+	if c.Match {
+		return check{name, true, c.Computed}
+	}
+	return check{name, false, fmt.Sprintf("claimed %s, computed %s", c.Claimed, c.Computed)}
+}
+
+// chainChecks summarizes the per-entry checks.
+func (x *explorer) chainChecks(intents bool) {
+	var broken, badIntent, badSig []string
+	for _, en := range x.entries {
+		id := strconv.Itoa(en.Index)
+		if !en.PrevHash.Match {
+			broken = append(broken, id)
+		}
+		if intents && !en.IntentHash.Match {
+			badIntent = append(badIntent, id)
+		}
+		if en.AgentSignature == "invalid" {
+			badSig = append(badSig, id)
+		}
+	}
+	summary := func(name string, bad []string) check {
+		if len(bad) == 0 {
+			return check{name, true, fmt.Sprintf("%d entries", len(x.entries))}
+		}
+		return check{name, false, "entries " + strings.Join(bad, ", ")}
+	}
+	x.checks = append(x.checks, summary("prev_hash chain", broken))
+	if intents {
+		x.checks = append(x.checks, summary("intent_hash", badIntent))
+	}
+	x.checks = append(x.checks, summary("agent signatures", badSig))
+}
+
+// run reads commands from in until quit or end of input.
+func (x *explorer) run(in io.Reader) {
+	fmt.Fprintf(x.out, "%s\n%d audit entries, chain starts at %s. Type h for help.\n", x.title, len(x.entries), x.start)
+	x.list()
+	sc := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(x.out, "dcp> ")
+		if !sc.Scan() {
+			fmt.Fprintln(x.out)
+			return
+		}
+		cmd := strings.TrimSpace(sc.Text())
+		if n, err := strconv.Atoi(cmd); err == nil {
+			x.goTo(n)
+			continue
+		}
+		switch cmd {
+		case "q", "quit", "exit":
+			return
+		case "l", "list", "ls":
+			x.list()
+		case "n", "next":
+			x.goTo(x.cur + 1)
+		case "p", "prev":
+			x.followPrev()
+		case "j", "json":
+			if len(x.raw) > x.cur {
+				fmt.Fprintf(x.out, "%s\n", x.raw[x.cur])
+			}
+		case "c", "checks":
+			x.showChecks()
+		case "h", "help", "?":
+			fmt.Fprint(x.out, tuiHelp)
+		case "":
+			x.show()
+		default:
+			fmt.Fprintf(x.out, "unknown command %q; type h for help\n", cmd)
+		}
+	}
+}
+
+func status(ok bool) string {
+	if ok {
+		return "ok"
+	}
+	return "FAIL"
+}
+
+func (x *explorer) list() {
+	for _, en := range x.entries {
+		cursor := " "
+		if en.Index == x.cur {
+			cursor = ">"
+		}
+		fmt.Fprintf(x.out, "%s %3d  %s  %-24s %s: %s  [%s]\n", cursor, en.Index, en.Timestamp, en.AuditID,
+			en.PolicyDecision, en.Outcome, status(en.PrevHash.Match && (en.IntentHash.Computed == "" || en.IntentHash.Match) && en.AgentSignature != "invalid"))
+	}
+}
+
+func (x *explorer) goTo(i int) {
+	if i < 0 || i >= len(x.entries) {
+		fmt.Fprintf(x.out, "no entry %d (0-%d)\n", i, len(x.entries)-1)
+		return
+	}
+	x.cur = i
+	x.show()
+}
+
+func (x *explorer) show() {
+	if len(x.entries) == 0 {
+		fmt.Fprintln(x.out, "no audit entries")
+		return
+	}
+	en := x.entries[x.cur]
+	fmt.Fprintf(x.out, "entry %d of %d: %s\n", en.Index, len(x.entries), en.AuditID)
+	fmt.Fprintf(x.out, "  timestamp       %s\n", en.Timestamp)
+	fmt.Fprintf(x.out, "  decision        %s\n", en.PolicyDecision)
+	fmt.Fprintf(x.out, "  outcome         %s\n", en.Outcome)
+	fmt.Fprintf(x.out, "  hash            %s\n", en.Hash)
+	fmt.Fprintf(x.out, "  prev_hash       %s [%s]\n", en.PrevHash.Claimed, status(en.PrevHash.Match))
+	if en.IntentHash.Computed == "" {
+		fmt.Fprintf(x.out, "  intent_hash     %s [not checked]\n", en.IntentHash.Claimed)
+	} else {
+		fmt.Fprintf(x.out, "  intent_hash     %s [%s]\n", en.IntentHash.Claimed, status(en.IntentHash.Match))
+	}
+	fmt.Fprintf(x.out, "  agent_signature %s\n", en.AgentSignature)
+}
+
+func (x *explorer) followPrev() {
+	if len(x.entries) == 0 {
+		return
+	}
+	prev := x.entries[x.cur].PrevHash.Claimed
+	for _, en := range x.entries {
+		if en.Hash == prev {
+			x.goTo(en.Index)
+			return
+		}
+	}
+	switch {
+	case prev == "GENESIS":
+		fmt.Fprintln(x.out, "start of the chain (GENESIS)")
+	case prev == x.start:
+		fmt.Fprintf(x.out, "the chain continues before this bundle (anchor %s)\n", prev)
+	default:
+		fmt.Fprintf(x.out, "prev_hash %s matches no entry: the chain is broken here\n", prev)
+	}
+}
+
+func (x *explorer) showChecks() {
+	for _, c := range x.checks {
+		fmt.Fprintf(x.out, "  [%-4s] %-36s %s\n", status(c.OK), c.Name, c.Detail)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runTUIScript runs dcp tui with the given commands on stdin.
+func runTUIScript(t *testing.T, script string, args ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	e := &env{stdin: strings.NewReader(script), stdout: &stdout, stderr: &stderr, getenv: func(string) string { return "" }}
+	if code := run(e, append([]string{"tui"}, args...)); code != exitOK {
+		t.Fatalf("code = %d, stderr = %s", code, stderr.String())
+	}
+	return stdout.String()
+}
+
+func TestTUIBundle(t *testing.T) {
+	out := runTUIScript(t, "c\n1\np\np\nq\n", filepath.Join(examplesDir(), "citizenship_bundle.signed.json"))
+	for _, want := range []string{
+		"[ok  ] bundle verification",
+		"entry 1 of 2: audit002",
+		"entry 0 of 2: audit001",
+		"start of the chain (GENESIS)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+}
+
+func TestTUILedger(t *testing.T) {
+	keys := testKeys(t)
+	ledger := filepath.Join(t.TempDir(), "ledger.db")
+	for _, outcome := range []string{"started", "success"} {
+		if _, stderr, code := runCLI(t, nil, "audit", "append", "--ledger", ledger, "--outcome", outcome,
+			"--intent", filepath.Join(examplesDir(), "intent.json"), "--key", filepath.Join(keys, "secret_key.txt")); code != exitOK {
+			t.Fatal(stderr)
+		}
+	}
+	out := runTUIScript(t, "n\nc\n", "--agent-key", filepath.Join(keys, "public_key.txt"), ledger)
+	for _, want := range []string{
+		"(ledger, 2 entries)",
+		"agent_signature valid",
+		"[ok  ] ledger verification",
+		"[ok  ] prev_hash chain",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+}
+
+func TestTUIBrokenChain(t *testing.T) {
+	x := &explorer{start: "GENESIS", entries: []entryInfo{
+		{Index: 0, Hash: "aa", PrevHash: hashCheck{Claimed: "GENESIS", Computed: "GENESIS", Match: true}},
+		{Index: 1, Hash: "bb", PrevHash: hashCheck{Claimed: "ff", Computed: "aa"}},
+	}}
+	var out bytes.Buffer
+	x.out = &out
+	x.cur = 1
+	x.followPrev()
+	if !strings.Contains(out.String(), "the chain is broken here") {
+		t.Fatal(out.String())
+	}
+}
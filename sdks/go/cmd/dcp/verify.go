@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// verifyReport is the outcome of verifying one signed bundle.
+type verifyReport struct {
+	File     string   `json:"file"`
+	Verified bool     `json:"verified"`
+	Errors   []string `json:"errors,omitempty"`
+	// Key is "pinned" when --pubkey was given and "embedded" when the
+	// signer's own public key from the bundle was used.
+	Key string `json:"key"`
+	// Trace is set with --explain.
+	Trace []dcp.TraceStep `json:"trace,omitempty"`
+	// Flags are reported even for a verified bundle; see
+	// VerificationResult.Flags.
+	Flags []string `json:"flags,omitempty"`
+}
+
+type verifyConfig struct {
+	pubKey         string
+	strict         bool
+	explain        bool
+	validate       bool
+	checkDomains   bool
+	liability      bool
+	pdpKeys        []string
+	insurerKeys    []string
+	countersigners []string
+	signerSets     map[string]*dcp.PrincipalSignerSet
+	guardians      map[string]string
+	checkpoint     *dcp.Checkpoint
+	segmentProof   *dcp.SegmentProof
+}
+
+func runVerify(e *env, args []string) int {
+	fs := e.flags("verify", "[flags] <signed_bundle.json>...")
+	pubKey := fs.String("pubkey", "", "signer public key, base64 or a key file; default is the key embedded in the bundle")
+	format := fs.String("format", "text", "report format: text, json or junit")
+	strict := fs.Bool("strict", false, "reject duplicate keys, unknown members and type errors (ParseSignedBundleStrict)")
+	explain := fs.Bool("explain", false, "report every verification step: canonical digests, the key used, each chain link")
+	validate := fs.Bool("validate", false, "also check every record against the schema (Validate)")
+	checkDomains := fs.Bool("check-domains", false, "also check the intent's target against the intent's and passport's domain lists")
+	liability := fs.Bool("liability", false, "also enforce the obligations of the principal's liability mode (consent, PDP-signed decision, agent-signed entries)")
+	var pdpKeys listFlag
+	fs.Var(&pdpKeys, "pdp-key", "PDP public key policy decisions may be signed with, base64 or a key file (repeatable; required for high risk tier agents)")
+	var insurerKeys listFlag
+	fs.Var(&insurerKeys, "insurer-key", "published key of an insurer or platform trusted to attest coverage, base64 or a key file (repeatable)")
+	var countersigners listFlag
+	fs.Var(&countersigners, "countersigner", "public key allowed to countersign break-glass records, base64 or a key file (repeatable; required to verify bundles with them)")
+	var signerSets listFlag
+	fs.Var(&signerSets, "signer-set", "HUMAN_ID=FILE: the signer set of a group principal, as published outside its bundles (repeatable; required to verify bundles of one)")
+	var guardians listFlag
+	fs.Var(&guardians, "guardian-key", "HUMAN_ID=KEY: the guardian's key of a dependent principal, base64 or a key file, as published outside its bundles (repeatable; required to verify bundles of one)")
+	cpPath := fs.String("checkpoint", "", "ledger checkpoint file the audit entries must be included in")
+	cpKey := fs.String("checkpoint-pubkey", "", "public key the checkpoint must be signed with, base64 or a key file")
+	proofPath := fs.String("proof", "", "segment proof for --checkpoint (from LedgerSegmentProof)")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitError
+	}
+	switch *format {
+	case "text", "json", "junit":
+	default:
+		return e.errorf("verify: unknown format %q (text, json or junit)", *format)
+	}
+	cfg := verifyConfig{strict: *strict, validate: *validate, explain: *explain, checkDomains: *checkDomains, liability: *liability}
+	var err error
+	if *pubKey != "" {
+		if cfg.pubKey, err = loadPublicKey(*pubKey); err != nil {
+			return e.errorf("verify: %v", err)
+		}
+	}
+	for _, arg := range pdpKeys {
+		key, err := loadPublicKey(arg)
+		if err != nil {
+			return e.errorf("verify: %v", err)
+		}
+		cfg.pdpKeys = append(cfg.pdpKeys, key)
+	}
+	for _, arg := range insurerKeys {
+		key, err := loadPublicKey(arg)
+		if err != nil {
+			return e.errorf("verify: %v", err)
+		}
+		cfg.insurerKeys = append(cfg.insurerKeys, key)
+	}
+	for _, arg := range countersigners {
+		key, err := loadPublicKey(arg)
+		if err != nil {
+			return e.errorf("verify: %v", err)
+		}
+		cfg.countersigners = append(cfg.countersigners, key)
+	}
+	if cfg.signerSets, err = loadSignerSets(signerSets); err != nil {
+		return e.errorf("verify: %v", err)
+	}
+	if cfg.guardians, err = loadGuardians(guardians); err != nil {
+		return e.errorf("verify: %v", err)
+	}
+	if (*cpPath == "") != (*proofPath == "") {
+		return e.errorf("verify: --checkpoint and --proof go together")
+	}
+	if *cpPath != "" {
+		key := ""
+		if *cpKey != "" {
+			if key, err = loadPublicKey(*cpKey); err != nil {
+				return e.errorf("verify: %v", err)
+			}
+		}
+		if cfg.checkpoint, err = dcp.ReadCheckpointFile(*cpPath, key); err != nil {
+			return e.errorf("verify: %v", err)
+		}
+		data, err := os.ReadFile(*proofPath)
+		if err != nil {
+			return e.errorf("verify: %v", err)
+		}
+		cfg.segmentProof = &dcp.SegmentProof{}
+		if err := json.Unmarshal(data, cfg.segmentProof); err != nil {
+			return e.errorf("verify: %s: %v", *proofPath, err)
+		}
+	}
+
+	reports := make([]verifyReport, fs.NArg())
+	code := exitOK
+	for i, path := range fs.Args() {
+		reports[i] = verifyFile(path, cfg)
+		if !reports[i].Verified {
+			code = exitFail
+		}
+	}
+	switch *format {
+	case "json":
+		var v interface{} = reports
+		if len(reports) == 1 {
+			v = reports[0]
+		}
+		err = writeJSON(e, "", v)
+	case "junit":
+		err = writeJUnit(e, reports)
+	default:
+		for _, r := range reports {
+			writeVerifyText(e, r)
+		}
+	}
+	if err != nil {
+		return e.errorf("verify: %v", err)
+	}
+	return code
+}
+
+func verifyFile(path string, cfg verifyConfig) verifyReport {
+	r := verifyReport{File: path, Key: "embedded"}
+	if cfg.pubKey != "" {
+		r.Key = "pinned"
+	}
+	fail := func(errs ...string) verifyReport {
+		r.Errors = append(r.Errors, errs...)
+		r.Verified = false
+		return r
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fail(err.Error())
+	}
+	var rsb *dcp.RawSignedBundle
+	if cfg.strict {
+		rsb, err = dcp.ParseSignedBundleStrict(data)
+	} else {
+		rsb, err = dcp.ParseSignedBundle(data)
+	}
+	if err != nil {
+		var perrs dcp.ParseErrors
+		if errors.As(err, &perrs) {
+			for _, pe := range perrs {
+				r.Errors = append(r.Errors, pe.Error())
+			}
+			return fail()
+		}
+		return fail(err.Error())
+	}
+	result := dcp.VerifyRawSignedBundleWithOptions(rsb, dcp.VerifyOptions{PublicKeyB64: cfg.pubKey, Explain: cfg.explain, CheckDomains: cfg.checkDomains,
+		PDPKeys: cfg.pdpKeys, EnforceLiability: cfg.liability, InsurerKeys: cfg.insurerKeys, Countersigners: cfg.countersigners,
+		SignerSets: cfg.signerSets, Guardians: cfg.guardians})
+	r.Verified = result.Verified
+	r.Errors = append(r.Errors, result.Errors...)
+	r.Trace = result.Trace
+	r.Flags = result.Flags
+
+	if cfg.validate || cfg.checkpoint != nil {
+		var sb dcp.SignedBundle
+		if err := json.Unmarshal(data, &sb); err != nil {
+			return fail(err.Error())
+		}
+		if cfg.validate {
+			if err := sb.Validate(); err != nil {
+				var verrs dcp.ValidationErrors
+				if errors.As(err, &verrs) {
+					for _, fe := range verrs {
+						r.Errors = append(r.Errors, fe.Error())
+					}
+				}
+				r.Verified = false
+			}
+		}
+		if cfg.checkpoint != nil {
+			if err := dcp.VerifySegment(cfg.checkpoint, sb.Bundle.AuditEntries, cfg.segmentProof); err != nil {
+				return fail(err.Error())
+			}
+		}
+	}
+	return r
+}
+
+func writeVerifyText(e *env, r verifyReport) {
+	status := "VERIFIED"
+	if !r.Verified {
+		status = "FAILED"
+	}
+	fmt.Fprintf(e.stdout, "%s: %s (%s key)\n", r.File, status, r.Key)
+	for _, flag := range r.Flags {
+		fmt.Fprintf(e.stdout, "  ! %s\n", flag)
+	}
+	for _, msg := range r.Errors {
+		fmt.Fprintf(e.stdout, "  - %s\n", msg)
+	}
+	for _, step := range r.Trace {
+		fmt.Fprintf(e.stdout, "  %s\n", formatTraceStep(step))
+	}
+}
+
+// formatTraceStep renders a trace step on one line.
+func formatTraceStep(s dcp.TraceStep) string {
+	status := "ok  "
+	if !s.OK {
+		status = "FAIL"
+	}
+	line := fmt.Sprintf("[%s] %-15s %-16s", status, s.Check, s.Target)
+	switch {
+	case s.Expected != "" && s.OK:
+		line += " " + s.Actual
+	case s.Expected != "":
+		line += fmt.Sprintf(" expected %s, got %s", s.Expected, s.Actual)
+	case s.Actual != "":
+		line += " " + s.Actual
+	}
+	if s.Detail != "" {
+		line += " (" + s.Detail + ")"
+	}
+	return strings.TrimRight(line, " ")
+}
+
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnit(e *env, reports []verifyReport) error {
+	suite := junitSuite{Name: "dcp verify", Tests: len(reports)}
+	for _, r := range reports {
+		c := junitCase{Name: r.File, ClassName: "dcp.verify", SystemOut: strings.Join(r.Flags, "\n")}
+		if !r.Verified {
+			suite.Failures++
+			msg := "verification failed"
+			if len(r.Errors) > 0 {
+				msg = r.Errors[0]
+			}
+			text := r.Errors
+			for _, step := range r.Trace {
+				text = append(text, formatTraceStep(step))
+			}
+			c.Failure = &junitFailure{Message: msg, Text: strings.Join(text, "\n")}
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	out, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.stdout, "%s%s\n", xml.Header, out)
+	return err
+}
+
+// loadSignerSets reads the signer sets named by HUMAN_ID=FILE arguments,
+// each FILE holding a signer set's JSON.
+func loadSignerSets(args []string) (map[string]*dcp.PrincipalSignerSet, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	sets := make(map[string]*dcp.PrincipalSignerSet, len(args))
+	for _, arg := range args {
+		humanID, path, ok := strings.Cut(arg, "=")
+		if !ok || humanID == "" {
+			return nil, fmt.Errorf("--signer-set %q is not HUMAN_ID=FILE", arg)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var set dcp.PrincipalSignerSet
+		if err := json.Unmarshal(data, &set); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if err := set.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		sets[humanID] = &set
+	}
+	return sets, nil
+}
+
+// loadGuardians reads the guardians' keys named by HUMAN_ID=KEY arguments,
+// each KEY as loadPublicKey accepts it.
+func loadGuardians(args []string) (map[string]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	keys := make(map[string]string, len(args))
+	for _, arg := range args {
+		humanID, keyArg, ok := strings.Cut(arg, "=")
+		if !ok || humanID == "" || keyArg == "" {
+			return nil, fmt.Errorf("--guardian-key %q is not HUMAN_ID=KEY", arg)
+		}
+		key, err := loadPublicKey(keyArg)
+		if err != nil {
+			return nil, err
+		}
+		keys[humanID] = key
+	}
+	return keys, nil
+}
+
+// loadPublicKey accepts a base64 Ed25519 public key, or a file holding one
+// as base64 text or PEM.
+func loadPublicKey(arg string) (string, error) {
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		data = []byte(arg)
+	}
+	data = bytes.TrimSpace(data)
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", arg, err)
+		}
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return "", fmt.Errorf("%s: %T is not an Ed25519 key", arg, key)
+		}
+		return base64.StdEncoding.EncodeToString(pub), nil
+	}
+	pub, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("%s is neither an Ed25519 public key nor a file holding one", arg)
+	}
+	return string(data), nil
+}
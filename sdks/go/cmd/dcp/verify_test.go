@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// signedExample signs the example bundle with fresh keys and returns the
+// key directory and the signed bundle's path.
+func signedExample(t *testing.T) (string, string) {
+	t.Helper()
+	keys := testKeys(t)
+	out := filepath.Join(t.TempDir(), "signed.json")
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(keys, "secret_key.txt"), "--out", out,
+		filepath.Join(examplesDir(), "citizenship_bundle.json")); code != exitOK {
+		t.Fatal(stderr)
+	}
+	return keys, out
+}
+
+func TestVerify(t *testing.T) {
+	keys, signed := signedExample(t)
+	pub := filepath.Join(keys, "public_key.txt")
+	stdout, _, code := runCLI(t, nil, "verify", "--pubkey", pub, "--strict", "--validate", signed)
+	if code != exitOK || !strings.Contains(stdout, "VERIFIED (pinned key)") {
+		t.Fatalf("code = %d, stdout = %s", code, stdout)
+	}
+
+	other := testKeys(t)
+	stdout, _, code = runCLI(t, nil, "verify", "--format", "json", "--pubkey", filepath.Join(other, "public_key.txt"), signed)
+	var report verifyReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatal(err)
+	}
+	if code != exitFail || report.Verified || report.Errors[0] != "SIGNATURE INVALID" {
+		t.Fatalf("code = %d, report = %+v", code, report)
+	}
+}
+
+func TestVerifyJUnit(t *testing.T) {
+	keys, signed := signedExample(t)
+	data, _ := os.ReadFile(signed)
+	tampered := filepath.Join(t.TempDir(), "tampered.json")
+	os.WriteFile(tampered, []byte(strings.Replace(string(data), `"outcome": "`, `"outcome": "x`, 1)), 0o644)
+
+	stdout, _, code := runCLI(t, nil, "verify", "--format", "junit", "--pubkey", filepath.Join(keys, "public_key.txt"), signed, tampered)
+	if code != exitFail {
+		t.Fatalf("code = %d", code)
+	}
+	var suite junitSuite
+	if err := xml.Unmarshal([]byte(stdout), &suite); err != nil {
+		t.Fatal(err)
+	}
+	if suite.Tests != 2 || suite.Failures != 1 || suite.Cases[0].Failure != nil || suite.Cases[1].Failure == nil {
+		t.Fatalf("suite = %+v", suite)
+	}
+}
+
+func TestVerifyUsageErrors(t *testing.T) {
+	if _, _, code := runCLI(t, nil, "verify"); code != exitError {
+		t.Fatal("no files should be a usage error")
+	}
+	if _, _, code := runCLI(t, nil, "verify", "--format", "yaml", "x.json"); code != exitError {
+		t.Fatal("unknown format should be a usage error")
+	}
+	if _, _, code := runCLI(t, nil, "verify", "--pubkey", "not-a-key", "x.json"); code != exitError {
+		t.Fatal("bad public key should be a usage error")
+	}
+}
+
+func TestVerifyExplain(t *testing.T) {
+	_, signed := signedExample(t)
+	stdout, _, code := runCLI(t, nil, "verify", "--explain", "--format", "json", signed)
+	var report verifyReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatal(err)
+	}
+	if code != exitOK || len(report.Trace) == 0 || report.Trace[0].Check != "canonicalize" {
+		t.Fatalf("code = %d, report = %+v", code, report)
+	}
+	stdout, _, _ = runCLI(t, nil, "verify", "--explain", signed)
+	if !strings.Contains(stdout, "[ok  ] prev_hash       audit_entries/1") {
+		t.Fatal(stdout)
+	}
+}
+
+func TestVerifyLiability(t *testing.T) {
+	keys := testKeys(t)
+	data, err := os.ReadFile(filepath.Join(examplesDir(), "citizenship_bundle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	unsigned, signed := filepath.Join(dir, "strict.json"), filepath.Join(dir, "signed.json")
+	os.WriteFile(unsigned, []byte(strings.Replace(string(data), `"owner_responsible"`, `"strict"`, 1)), 0o644)
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(keys, "secret_key.txt"), "--out", signed, unsigned); code != exitOK {
+		t.Fatal(stderr)
+	}
+	if stdout, _, code := runCLI(t, nil, "verify", signed); code != exitOK {
+		t.Fatalf("without --liability: code = %d, stdout = %s", code, stdout)
+	}
+	stdout, _, code := runCLI(t, nil, "verify", "--liability", signed)
+	if code != exitFail || !strings.Contains(stdout, "strict liability requires consent") {
+		t.Fatalf("code = %d, stdout = %s", code, stdout)
+	}
+}
+
+func TestVerifyCountersigner(t *testing.T) {
+	keys, officerKeys := testKeys(t), testKeys(t)
+	principal, err := dcp.NewKeySigner(readKey(t, keys, "secret_key.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	officer, err := dcp.NewKeySigner(readKey(t, officerKeys, "secret_key.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(examplesDir(), "citizenship_bundle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b dcp.CitizenshipBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		t.Fatal(err)
+	}
+	declared, err := b.Intent.TimestampTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := dcp.RecordFactory{Clock: func() time.Time { return declared }}
+	r, err := f.NewBreakGlassRecord(b.Intent, b.Intent.HumanID, "customer locked out of a medical account", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Sign(principal); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Countersign(officer, "officer001"); err != nil {
+		t.Fatal(err)
+	}
+	b.BreakGlass = []dcp.BreakGlassRecord{r}
+	dir := t.TempDir()
+	unsigned, signed := filepath.Join(dir, "break_glass.json"), filepath.Join(dir, "signed.json")
+	data, _ = json.Marshal(b)
+	os.WriteFile(unsigned, data, 0o644)
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(keys, "secret_key.txt"), "--out", signed, unsigned); code != exitOK {
+		t.Fatal(stderr)
+	}
+	stdout, _, code := runCLI(t, nil, "verify", signed)
+	if code != exitFail || !strings.Contains(stdout, "no countersigner keys") {
+		t.Fatalf("without --countersigner: code = %d, stdout = %s", code, stdout)
+	}
+	if stdout, _, code := runCLI(t, nil, "verify", "--countersigner", filepath.Join(officerKeys, "public_key.txt"), signed); code != exitOK || !strings.Contains(stdout, "BREAK GLASS") {
+		t.Fatalf("with --countersigner: code = %d, stdout = %s", code, stdout)
+	}
+}
+
+func TestVerifySignerSet(t *testing.T) {
+	keys, member := testKeys(t), testKeys(t)
+	data, err := os.ReadFile(filepath.Join(examplesDir(), "citizenship_bundle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b dcp.CitizenshipBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		t.Fatal(err)
+	}
+	set := dcp.PrincipalSignerSet{Threshold: 2, Signers: []dcp.PrincipalMember{
+		{HumanID: "did:human:alice001", PublicKey: readKey(t, keys, "public_key.txt")},
+		{HumanID: "did:human:bob002", PublicKey: readKey(t, member, "public_key.txt")}}}
+	b.ResponsiblePrincipalRecord.SignerSet = &set
+	dir := t.TempDir()
+	unsigned, signed, setFile := filepath.Join(dir, "group.json"), filepath.Join(dir, "signed.json"), filepath.Join(dir, "signer_set.json")
+	data, _ = json.Marshal(b)
+	os.WriteFile(unsigned, data, 0o644)
+	data, _ = json.Marshal(set)
+	os.WriteFile(setFile, data, 0o644)
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(keys, "secret_key.txt"), "--out", signed, unsigned); code != exitOK {
+		t.Fatal(stderr)
+	}
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(member, "secret_key.txt"), "--cosign-as", "did:human:bob002", "--out", signed, signed); code != exitOK {
+		t.Fatal(stderr)
+	}
+	stdout, _, code := runCLI(t, nil, "verify", signed)
+	if code != exitFail || !strings.Contains(stdout, "declared only in the bundle") {
+		t.Fatalf("without --signer-set: code = %d, stdout = %s", code, stdout)
+	}
+	anchor := b.ResponsiblePrincipalRecord.HumanID + "=" + setFile
+	if stdout, _, code := runCLI(t, nil, "verify", "--signer-set", anchor, signed); code != exitOK {
+		t.Fatalf("with --signer-set: code = %d, stdout = %s", code, stdout)
+	}
+	if _, stderr, code := runCLI(t, nil, "verify", "--signer-set", setFile, signed); code == exitOK || !strings.Contains(stderr, "HUMAN_ID=FILE") {
+		t.Fatalf("without a human_id: code = %d, stderr = %s", code, stderr)
+	}
+}
+
+func TestVerifyGuardianKey(t *testing.T) {
+	keys, guardian := testKeys(t), testKeys(t)
+	data, err := os.ReadFile(filepath.Join(examplesDir(), "citizenship_bundle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b dcp.CitizenshipBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		t.Fatal(err)
+	}
+	guardianKey := readKey(t, guardian, "public_key.txt")
+	b.ResponsiblePrincipalRecord.Guardian = &dcp.GuardianBinding{GuardianID: "did:human:bob002", GuardianPublicKey: guardianKey,
+		Relationship: dcp.GuardianParent, LegalBasis: "birth certificate"}
+	dir := t.TempDir()
+	unsigned, signed := filepath.Join(dir, "dependent.json"), filepath.Join(dir, "signed.json")
+	data, _ = json.Marshal(b)
+	os.WriteFile(unsigned, data, 0o644)
+	if _, stderr, code := runCLI(t, nil, "sign", "--key", filepath.Join(keys, "secret_key.txt"), "--out", signed, unsigned); code != exitOK {
+		t.Fatal(stderr)
+	}
+	stdout, _, code := runCLI(t, nil, "verify", signed)
+	if code != exitFail || !strings.Contains(stdout, "declared only in the bundle") {
+		t.Fatalf("without --guardian-key: code = %d, stdout = %s", code, stdout)
+	}
+	// The example passport is not countersigned, so with the guardian's
+	// key given the countersignature is what fails.
+	anchor := b.ResponsiblePrincipalRecord.HumanID + "=" + filepath.Join(guardian, "public_key.txt")
+	if stdout, _, code := runCLI(t, nil, "verify", "--guardian-key", anchor, signed); code != exitFail || !strings.Contains(stdout, "no guardian signature") {
+		t.Fatalf("with --guardian-key: code = %d, stdout = %s", code, stdout)
+	}
+	if _, stderr, code := runCLI(t, nil, "verify", "--guardian-key", guardianKey, signed); code == exitOK || !strings.Contains(stderr, "HUMAN_ID=KEY") {
+		t.Fatalf("without a human_id: code = %d, stderr = %s", code, stderr)
+	}
+}
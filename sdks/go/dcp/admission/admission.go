@@ -0,0 +1,242 @@
+// Package admission is a Kubernetes validating admission webhook refusing
+// AI agent workloads that do not reference a valid, active and unrevoked
+// agent passport.
+//
+// A pod is an agent when it carries the AgentLabel label set to "true". It
+// must then name its agent in the AgentIDAnnotation annotation, and the
+// registered passport of that agent must pass an agentauth.Authenticator:
+//
+//	metadata:
+//	  labels:
+//	    dcp-ai.org/agent: "true"
+//	  annotations:
+//	    dcp-ai.org/agent-id: dcp:agent:01a13ec4-...
+//
+// Workloads are checked through their pod templates, so an undocumented
+// agent is refused when its Deployment, StatefulSet, DaemonSet, ReplicaSet,
+// Job or CronJob is created or updated, not only when its pods are. Other
+// objects, and pods without the label, are admitted.
+//
+// The Server answers AdmissionReview requests of admission.k8s.io/v1 at
+// POST /validate; register it with a ValidatingWebhookConfiguration on the
+// CREATE and UPDATE of those kinds.
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+)
+
+const (
+	// AgentLabel, set to "true", marks a pod as an AI agent.
+	AgentLabel = "dcp-ai.org/agent"
+	// AgentIDAnnotation names the agent whose registered passport an agent
+	// pod runs under.
+	AgentIDAnnotation = "dcp-ai.org/agent-id"
+)
+
+// ReviewAPIVersion is the AdmissionReview version the Server speaks.
+const ReviewAPIVersion = "admission.k8s.io/v1"
+
+// Review is an AdmissionReview, holding a Request from the API server or
+// the Response to it.
+type Review struct {
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Request    *Request  `json:"request,omitempty"`
+	Response   *Response `json:"response,omitempty"`
+}
+
+// GroupVersionKind names the kind of an object.
+type GroupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// Request is the part of an AdmissionRequest the webhook reads.
+type Request struct {
+	UID       string           `json:"uid"`
+	Kind      GroupVersionKind `json:"kind"`
+	Name      string           `json:"name,omitempty"`
+	Namespace string           `json:"namespace,omitempty"`
+	Operation string           `json:"operation"`
+	// Object is the object as it will be admitted; empty on DELETE.
+	Object json.RawMessage `json:"object,omitempty"`
+}
+
+// Response is an AdmissionResponse.
+type Response struct {
+	UID     string  `json:"uid"`
+	Allowed bool    `json:"allowed"`
+	Result  *Status `json:"status,omitempty"`
+}
+
+// Status tells the user why a request was refused.
+type Status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// objectMeta is the part of Kubernetes object metadata the webhook reads.
+type objectMeta struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+type podTemplate struct {
+	Metadata objectMeta `json:"metadata"`
+}
+
+// object is the part of a pod or workload the webhook reads.
+type object struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		// Template is the pod template of Deployments, StatefulSets,
+		// DaemonSets, ReplicaSets and Jobs.
+		Template *podTemplate `json:"template"`
+		// JobTemplate is the job template of CronJobs.
+		JobTemplate *struct {
+			Spec struct {
+				Template *podTemplate `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+	} `json:"spec"`
+}
+
+// podMetadata returns the metadata of the pods obj, of kind, runs, or nil
+// if kind runs no pods.
+func podMetadata(kind string, obj *object) *objectMeta {
+	switch kind {
+	case "Pod":
+		return &obj.Metadata
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "ReplicationController":
+		if obj.Spec.Template != nil {
+			return &obj.Spec.Template.Metadata
+		}
+	case "CronJob":
+		if obj.Spec.JobTemplate != nil && obj.Spec.JobTemplate.Spec.Template != nil {
+			return &obj.Spec.JobTemplate.Spec.Template.Metadata
+		}
+	}
+	return nil
+}
+
+// Config configures a Server.
+type Config struct {
+	// Authenticator checks the referenced passport. Required.
+	Authenticator *agentauth.Authenticator
+	// Passports resolves the referenced passport. Required.
+	Passports agentauth.PassportSource
+	// MaxBodyBytes bounds review bodies; zero means 4 MiB.
+	MaxBodyBytes int64
+}
+
+// Server is the admission webhook. Create one with New.
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+}
+
+// New returns a Server for cfg.
+func New(cfg Config) (*Server, error) {
+	if cfg.Authenticator == nil || cfg.Passports == nil {
+		return nil, errors.New("admission: an authenticator and a passport source are required")
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 4 << 20
+	}
+	s := &Server{cfg: cfg, mux: http.NewServeMux()}
+	s.mux.HandleFunc("POST /validate", s.handleValidate)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Review decides req. Agent workloads are refused with 403 when their
+// passport is missing or invalid, and with 503 when the registry or a
+// revocation source could not be consulted: the webhook fails closed.
+func (s *Server) Review(ctx context.Context, req *Request) *Response {
+	resp := &Response{UID: req.UID, Allowed: true}
+	if len(req.Object) == 0 {
+		return resp
+	}
+	var obj object
+	if err := json.Unmarshal(req.Object, &obj); err != nil {
+		return deny(resp, http.StatusBadRequest, fmt.Sprintf("%s: %v", req.Kind.Kind, err))
+	}
+	meta := podMetadata(req.Kind.Kind, &obj)
+	if meta == nil || meta.Labels[AgentLabel] != "true" {
+		return resp
+	}
+	agentID := meta.Annotations[AgentIDAnnotation]
+	if agentID == "" {
+		return deny(resp, http.StatusForbidden, fmt.Sprintf("%s %s is labeled %s=true but has no %s annotation", req.Kind.Kind, req.Name, AgentLabel, AgentIDAnnotation))
+	}
+	p, err := s.cfg.Passports.Passport(ctx, agentID)
+	if err != nil {
+		return deny(resp, http.StatusServiceUnavailable, fmt.Sprintf("%v: registry: %v", agentauth.ErrLookup, err))
+	}
+	if p == nil {
+		return deny(resp, http.StatusForbidden, fmt.Sprintf("no passport is registered for %s", agentID))
+	}
+	if _, err := s.cfg.Authenticator.Check(ctx, p, nil); err != nil {
+		if errors.Is(err, agentauth.ErrLookup) {
+			return deny(resp, http.StatusServiceUnavailable, err.Error())
+		}
+		return deny(resp, http.StatusForbidden, err.Error())
+	}
+	return resp
+}
+
+func deny(resp *Response, code int, msg string) *Response {
+	resp.Allowed = false
+	resp.Result = &Status{Code: code, Message: "dcp: " + msg}
+	return resp
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.cfg.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", s.cfg.MaxBodyBytes))
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var review Review
+	if err := json.Unmarshal(body, &review); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if review.Request == nil {
+		writeError(w, http.StatusBadRequest, "AdmissionReview has no request")
+		return
+	}
+	writeJSON(w, http.StatusOK, &Review{
+		APIVersion: ReviewAPIVersion,
+		Kind:       "AdmissionReview",
+		Response:   s.Review(r.Context(), review.Request),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,150 @@
+package admission_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/admission"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+)
+
+func passport(t *testing.T, status dcp.Status) dcp.AgentPassport {
+	t.Helper()
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	p.Status = status
+	if err := p.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	return *p
+}
+
+type failingSource struct{}
+
+func (failingSource) Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error) {
+	return nil, errors.New("connection refused")
+}
+
+func newServer(t *testing.T, passports agentauth.PassportSource, revocations ...dcp.RevocationChecker) *admission.Server {
+	t.Helper()
+	auth := dcptest.Authenticator(t, agentauth.Config{Passports: passports, Revocations: revocations})
+	s, err := admission.New(admission.Config{Authenticator: auth, Passports: passports})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// metadata returns pod metadata labeled as an agent of agentID, or as no
+// agent if agentID is "-".
+func metadata(agentID string) string {
+	if agentID == "-" {
+		return `{"labels":{"app":"web"}}`
+	}
+	return fmt.Sprintf(`{"labels":{"app":"assistant","dcp-ai.org/agent":"true"},"annotations":{"dcp-ai.org/agent-id":%q}}`, agentID)
+}
+
+func request(kind, agentID string) *admission.Request {
+	var obj string
+	switch kind {
+	case "Pod":
+		obj = `{"metadata":` + metadata(agentID) + `,"spec":{"containers":[{"name":"a","image":"a"}]}}`
+	case "CronJob":
+		obj = `{"metadata":{"name":"nightly"},"spec":{"jobTemplate":{"spec":{"template":{"metadata":` + metadata(agentID) + `}}}}}`
+	default:
+		obj = `{"metadata":{"name":"assistant"},"spec":{"replicas":2,"template":{"metadata":` + metadata(agentID) + `}}}`
+	}
+	return &admission.Request{
+		UID:       "705ab4f5-6393-11e8-b7cc-42010a800002",
+		Kind:      admission.GroupVersionKind{Kind: kind},
+		Name:      "assistant",
+		Namespace: "agents",
+		Operation: "CREATE",
+		Object:    json.RawMessage(obj),
+	}
+}
+
+func TestReview(t *testing.T) {
+	ctx := context.Background()
+	active := passport(t, dcp.StatusActive)
+	suspended := passport(t, dcp.StatusSuspended)
+	revoked := passport(t, dcp.StatusActive)
+	revocations := &dcp.RevocationList{}
+	revocations.Add(dcp.NewRevocationRecord(revoked.AgentID, revoked.PrincipalBindingReference, "retired"))
+	s := newServer(t, grpcserver.PassportMap{
+		active.AgentID:    active,
+		suspended.AgentID: suspended,
+		revoked.AgentID:   revoked,
+	}, revocations)
+
+	for _, kind := range []string{"Pod", "Deployment", "StatefulSet", "Job", "CronJob"} {
+		if resp := s.Review(ctx, request(kind, active.AgentID)); !resp.Allowed || resp.UID != "705ab4f5-6393-11e8-b7cc-42010a800002" {
+			t.Errorf("%s of a registered agent refused: %+v", kind, resp.Result)
+		}
+		if resp := s.Review(ctx, request(kind, "")); resp.Allowed || resp.Result.Code != http.StatusForbidden {
+			t.Errorf("%s of an undocumented agent: %+v", kind, resp)
+		}
+	}
+	if resp := s.Review(ctx, request("Pod", "-")); !resp.Allowed {
+		t.Errorf("pod of no agent refused: %+v", resp.Result)
+	}
+	if resp := s.Review(ctx, &admission.Request{UID: "u", Kind: admission.GroupVersionKind{Kind: "Pod"}, Operation: "DELETE"}); !resp.Allowed {
+		t.Errorf("deletion refused: %+v", resp.Result)
+	}
+
+	for name, agentID := range map[string]string{
+		"unregistered": passport(t, dcp.StatusActive).AgentID,
+		"suspended":    suspended.AgentID,
+		"revoked":      revoked.AgentID,
+	} {
+		resp := s.Review(ctx, request("Deployment", agentID))
+		if resp.Allowed || resp.Result.Code != http.StatusForbidden || !strings.Contains(resp.Result.Message, agentID) {
+			t.Errorf("%s: %+v", name, resp.Result)
+		}
+	}
+
+	unreachable := newServer(t, failingSource{})
+	if resp := unreachable.Review(ctx, request("Pod", active.AgentID)); resp.Allowed || resp.Result.Code != http.StatusServiceUnavailable {
+		t.Errorf("registry down: %+v", resp)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	active := passport(t, dcp.StatusActive)
+	s := newServer(t, grpcserver.PassportMap{active.AgentID: active})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(body)))
+		return rec
+	}
+	body, _ := json.Marshal(&admission.Review{APIVersion: admission.ReviewAPIVersion, Kind: "AdmissionReview", Request: request("Pod", "")})
+	rec := post(string(body))
+	var review admission.Review
+	if err := json.Unmarshal(rec.Body.Bytes(), &review); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("%d %s", rec.Code, rec.Body)
+	}
+	if review.APIVersion != "admission.k8s.io/v1" || review.Kind != "AdmissionReview" || review.Response == nil ||
+		review.Response.Allowed || review.Response.UID != "705ab4f5-6393-11e8-b7cc-42010a800002" {
+		t.Fatalf("review %s", rec.Body)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(`"status":{"code":403`)) {
+		t.Fatalf("review %s", rec.Body)
+	}
+
+	if rec := post(`{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("review without request: %d", rec.Code)
+	}
+	if rec := post(`{`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid JSON: %d", rec.Code)
+	}
+}
@@ -0,0 +1,288 @@
+// Package agentauth is net/http middleware admitting only requests from
+// agents holding a valid DCP agent passport.
+//
+// An agent presents its passport in the DCP-Agent-Passport header, as
+// base64 of the passport JSON. The Authenticator checks the passport's
+// self-signature, status, age and revocation and, with a PassportSource,
+// that it is the passport registered for the agent. When the request comes
+// over TLS with a client certificate, the certificate must carry the
+// passport's Ed25519 key: the passport is then bound to the connection, and
+// a copied header is useless without the agent's key. Without mTLS, the
+// header alone proves only that the passport is valid, not that the caller
+// holds it.
+//
+// Handlers read the admitted agent with FromContext:
+//
+//	auth, _ := agentauth.New(agentauth.Config{Passports: &registry.Client{URL: registryURL}})
+//	mux.Handle("POST /v1/orders", auth.Middleware(agentauth.RequireCapabilities("purchase")(orders)))
+package agentauth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// PassportHeader carries the presented passport.
+const PassportHeader = "DCP-Agent-Passport"
+
+var (
+	// ErrNoPassport reports a request presenting no passport.
+	ErrNoPassport = errors.New("no agent passport presented")
+	// ErrInvalidPassport reports a passport that is malformed, not signed
+	// by its own key, not the registered one, or not bound to the client
+	// certificate.
+	ErrInvalidPassport = errors.New("invalid agent passport")
+	// ErrInactive reports a passport that is revoked, suspended or too old.
+	ErrInactive = errors.New("agent passport is not active")
+	// ErrLookup reports a registry or revocation source that could not be
+	// consulted.
+	ErrLookup = errors.New("agent passport could not be checked")
+)
+
+// PassportSource returns the registered passport of an agent, or nil if it
+// has none, like grpcserver.PassportSource. *registry.Client and
+// *registry.Registry are PassportSources.
+type PassportSource interface {
+	Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error)
+}
+
+// Config configures an Authenticator.
+type Config struct {
+	// Passports, if set, must hold a passport identical to the presented
+	// one. Without it, any self-signed passport is admitted.
+	Passports PassportSource
+	// Revocations are consulted for the agent; a revoked agent is refused.
+	Revocations []dcp.RevocationChecker
+	// MaxAge refuses passports created longer ago; zero means no limit.
+	MaxAge time.Duration
+	// RequireCertificate refuses requests without a TLS client certificate
+	// bound to the passport.
+	RequireCertificate bool
+	// Now is the clock passport ages are measured with; nil means time.Now.
+	Now func() time.Time
+}
+
+// Identity is an admitted agent.
+type Identity struct {
+	Passport dcp.AgentPassport
+	// CertificateBound reports whether the request's client certificate
+	// carries the passport key, proving the caller holds it.
+	CertificateBound bool
+}
+
+// AgentID returns the agent's ID.
+func (id *Identity) AgentID() string { return id.Passport.AgentID }
+
+// HumanID returns the ID of the agent's responsible principal.
+func (id *Identity) HumanID() string { return id.Passport.PrincipalBindingReference }
+
+// HasCapability reports whether the passport grants c, as the capability
+// grammar matches them: a passport holding "email:*" or "email" grants
+// "email:send".
+func (id *Identity) HasCapability(c string) bool {
+	return dcp.AuthorizeCapability(id.Passport.Capabilities, c) == nil
+}
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying id.
+func NewContext(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the agent admitted by the Middleware, if any.
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(*Identity)
+	return id, ok
+}
+
+// Authenticator checks presented passports. Create one with New.
+type Authenticator struct {
+	cfg Config
+}
+
+// New returns an Authenticator for cfg.
+func New(cfg Config) (*Authenticator, error) {
+	if cfg.MaxAge < 0 {
+		return nil, errors.New("agentauth: negative MaxAge")
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Authenticator{cfg: cfg}, nil
+}
+
+// Authenticate checks the passport presented by r and returns the agent's
+// identity. Its errors wrap ErrNoPassport, ErrInvalidPassport, ErrInactive
+// or ErrLookup.
+func (a *Authenticator) Authenticate(r *http.Request) (*Identity, error) {
+	header := strings.TrimSpace(r.Header.Get(PassportHeader))
+	if header == "" {
+		return nil, ErrNoPassport
+	}
+	p, err := DecodePassport(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPassport, err)
+	}
+	return a.Check(r.Context(), p, r.TLS)
+}
+
+// Check checks a passport presented by other means than the header, such
+// as gRPC metadata. conn, if not nil, is the TLS connection whose client
+// certificate the passport must be bound to.
+func (a *Authenticator) Check(ctx context.Context, p *dcp.AgentPassport, conn *tls.ConnectionState) (*Identity, error) {
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPassport, err)
+	}
+	if ok, err := p.VerifySignature(); err != nil || !ok {
+		return nil, fmt.Errorf("%w: %s is not signed by its public_key", ErrInvalidPassport, p.AgentID)
+	}
+	id := &Identity{Passport: *p.Clone()}
+	bound, err := certificateBound(p, conn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPassport, err)
+	}
+	if !bound && a.cfg.RequireCertificate {
+		return nil, fmt.Errorf("%w: no client certificate carries the passport key", ErrInvalidPassport)
+	}
+	id.CertificateBound = bound
+
+	if p.Status != dcp.StatusActive {
+		return nil, fmt.Errorf("%w: %s is %s", ErrInactive, p.AgentID, p.Status)
+	}
+	if a.cfg.MaxAge > 0 {
+		created, err := p.CreatedAtTime()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPassport, err)
+		}
+		if a.cfg.Now().Sub(created) > a.cfg.MaxAge {
+			return nil, fmt.Errorf("%w: %s was created at %s, more than %s ago", ErrInactive, p.AgentID, p.CreatedAt, a.cfg.MaxAge)
+		}
+	}
+	if a.cfg.Passports != nil {
+		registered, err := a.cfg.Passports.Passport(ctx, p.AgentID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: registry: %v", ErrLookup, err)
+		}
+		if registered == nil || !registered.Equal(p) {
+			return nil, fmt.Errorf("%w: %s is not the registered passport", ErrInvalidPassport, p.AgentID)
+		}
+	}
+	for _, rc := range a.cfg.Revocations {
+		rec, err := rc.CheckRevocation(ctx, p.AgentID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: revocation source: %v", ErrLookup, err)
+		}
+		if rec != nil {
+			return nil, fmt.Errorf("%w: %s was revoked at %s", ErrInactive, p.AgentID, rec.Timestamp)
+		}
+	}
+	return id, nil
+}
+
+// Middleware admits requests presenting a valid passport, with the agent's
+// Identity in their context, and answers the others with 401 (no or invalid
+// passport), 403 (inactive passport) or 503 (lookup failure).
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := a.Authenticate(r)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrInactive):
+				writeError(w, http.StatusForbidden, err.Error())
+			case errors.Is(err, ErrLookup):
+				writeError(w, http.StatusServiceUnavailable, err.Error())
+			default:
+				w.Header().Set("WWW-Authenticate", `DCP header="`+PassportHeader+`"`)
+				writeError(w, http.StatusUnauthorized, err.Error())
+			}
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// RequireCapabilities returns middleware answering 403 unless the admitted
+// agent's passport grants every capability in caps. It must run inside
+// Middleware.
+func RequireCapabilities(caps ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, ok := FromContext(r.Context())
+			if !ok {
+				writeError(w, http.StatusUnauthorized, ErrNoPassport.Error())
+				return
+			}
+			for _, c := range caps {
+				if !id.HasCapability(c) {
+					writeError(w, http.StatusForbidden, fmt.Sprintf("agent %s lacks capability %q", id.AgentID(), c))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// EncodePassport returns the DCP-Agent-Passport header value of p.
+func EncodePassport(p *dcp.AgentPassport) (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecodePassport parses a DCP-Agent-Passport header value, in standard or
+// URL-safe base64, padded or not.
+func DecodePassport(header string) (*dcp.AgentPassport, error) {
+	var data []byte
+	var err error
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if data, err = enc.DecodeString(header); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, errors.New("header is not base64")
+	}
+	var p dcp.AgentPassport
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// certificateBound reports whether the client certificate of conn carries
+// the passport key; a certificate carrying another key is an error.
+func certificateBound(p *dcp.AgentPassport, conn *tls.ConnectionState) (bool, error) {
+	if conn == nil || len(conn.PeerCertificates) == 0 {
+		return false, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(p.PublicKey)
+	if err != nil {
+		return false, fmt.Errorf("public_key: %v", err)
+	}
+	certKey, ok := conn.PeerCertificates[0].PublicKey.(ed25519.PublicKey)
+	if !ok || !certKey.Equal(ed25519.PublicKey(key)) {
+		return false, errors.New("client certificate does not carry the passport key")
+	}
+	return true, nil
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,237 @@
+package agentauth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+)
+
+var (
+	_ agentauth.PassportSource = grpcserver.PassportMap{}
+	_ agentauth.PassportSource = (*registry.Client)(nil)
+	_ agentauth.PassportSource = (*registry.Registry)(nil)
+)
+
+// newAgent returns a passport self-signed by a fresh agent key, and the key.
+func newAgent(t *testing.T, edit func(*dcp.AgentPassport)) (dcp.AgentPassport, *dcp.Keypair) {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), kp.PublicKeyB64, []string{"browse"}, dcp.RiskTierLow)
+	if edit != nil {
+		edit(&p)
+	}
+	if err := p.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	return p, kp
+}
+
+func header(t *testing.T, p *dcp.AgentPassport) string {
+	t.Helper()
+	h, err := agentauth.EncodePassport(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// whoami answers the admitted agent's ID.
+var whoami = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	id, ok := agentauth.FromContext(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"agent_id": id.AgentID(), "bound": id.CertificateBound})
+})
+
+func request(h http.Handler, passportHeader string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if passportHeader != "" {
+		req.Header.Set(agentauth.PassportHeader, passportHeader)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	p, _ := newAgent(t, nil)
+	suspended, _ := newAgent(t, func(p *dcp.AgentPassport) { p.Status = dcp.StatusSuspended })
+	revoked, _ := newAgent(t, nil)
+	unregistered, _ := newAgent(t, nil)
+	revocations := &dcp.RevocationList{}
+	revocations.Add(dcp.NewRevocationRecord(revoked.AgentID, revoked.PrincipalBindingReference, "retired"))
+	registered := grpcserver.PassportMap{p.AgentID: p, suspended.AgentID: suspended, revoked.AgentID: revoked}
+
+	auth, err := agentauth.New(agentauth.Config{Passports: registered, Revocations: []dcp.RevocationChecker{revocations}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := auth.Middleware(whoami)
+
+	rec := request(h, header(t, &p))
+	var got map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &got)
+	if rec.Code != http.StatusOK || got["agent_id"] != p.AgentID || got["bound"] != false {
+		t.Fatalf("valid passport: %d %s", rec.Code, rec.Body)
+	}
+	raw, _ := json.Marshal(p)
+	if rec := request(h, base64.RawURLEncoding.EncodeToString(raw)); rec.Code != http.StatusOK {
+		t.Fatalf("unpadded URL-safe header: %d %s", rec.Code, rec.Body)
+	}
+
+	tampered := p
+	tampered.Capabilities = []string{"browse", "purchase"}
+	for name, tc := range map[string]struct {
+		header string
+		code   int
+	}{
+		"missing":      {"", http.StatusUnauthorized},
+		"not base64":   {"{not base64}", http.StatusUnauthorized},
+		"tampered":     {header(t, &tampered), http.StatusUnauthorized},
+		"unregistered": {header(t, &unregistered), http.StatusUnauthorized},
+		"suspended":    {header(t, &suspended), http.StatusForbidden},
+		"revoked":      {header(t, &revoked), http.StatusForbidden},
+	} {
+		rec := request(h, tc.header)
+		var body map[string]string
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		if rec.Code != tc.code || body["error"] == "" {
+			t.Errorf("%s: %d %s", name, rec.Code, rec.Body)
+		}
+		if tc.code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: no WWW-Authenticate", name)
+		}
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	p, _ := newAgent(t, nil)
+	created, err := p.CreatedAtTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := created.Add(time.Hour)
+	auth, err := agentauth.New(agentauth.Config{MaxAge: 30 * time.Minute, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := request(auth.Middleware(whoami), header(t, &p)); rec.Code != http.StatusForbidden {
+		t.Fatalf("old passport: %d %s", rec.Code, rec.Body)
+	}
+	now = created.Add(time.Minute)
+	if rec := request(auth.Middleware(whoami), header(t, &p)); rec.Code != http.StatusOK {
+		t.Fatalf("fresh passport: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestRequireCapabilities(t *testing.T) {
+	p, _ := newAgent(t, nil)
+	auth, err := agentauth.New(agentauth.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := request(auth.Middleware(agentauth.RequireCapabilities("browse")(whoami)), header(t, &p)); rec.Code != http.StatusOK {
+		t.Fatalf("granted capability: %d %s", rec.Code, rec.Body)
+	}
+	if rec := request(auth.Middleware(agentauth.RequireCapabilities("browse", "purchase")(whoami)), header(t, &p)); rec.Code != http.StatusForbidden {
+		t.Fatalf("missing capability: %d %s", rec.Code, rec.Body)
+	}
+	if rec := request(agentauth.RequireCapabilities("browse")(whoami), header(t, &p)); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("without Middleware: %d %s", rec.Code, rec.Body)
+	}
+
+	// Capabilities match as the grammar does: email:* grants email:send,
+	// but a scoped grant does not reach past its scope.
+	p, _ = newAgent(t, func(p *dcp.AgentPassport) { p.Capabilities = []string{"email:*", "payments:initiate:<=100EUR"} })
+	if rec := request(auth.Middleware(agentauth.RequireCapabilities("email:send", "payments:initiate:<50EUR")(whoami)), header(t, &p)); rec.Code != http.StatusOK {
+		t.Fatalf("covered capabilities: %d %s", rec.Code, rec.Body)
+	}
+	if rec := request(auth.Middleware(agentauth.RequireCapabilities("payments:initiate")(whoami)), header(t, &p)); rec.Code != http.StatusForbidden {
+		t.Fatalf("unbounded payments: %d %s", rec.Code, rec.Body)
+	}
+}
+
+// clientCertificate returns a self-signed certificate for the Ed25519 key
+// in secretKeyB64.
+func clientCertificate(t *testing.T, secretKeyB64 string) tls.Certificate {
+	t.Helper()
+	raw, err := base64.StdEncoding.DecodeString(secretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv := ed25519.PrivateKey(raw)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, priv.Public(), priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: priv}
+}
+
+func TestCertificateBinding(t *testing.T) {
+	p, kp := newAgent(t, nil)
+	_, other := newAgent(t, nil)
+	auth, err := agentauth.New(agentauth.Config{RequireCertificate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewUnstartedServer(auth.Middleware(whoami))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	get := func(certs ...tls.Certificate) (int, map[string]interface{}) {
+		t.Helper()
+		// A fresh transport per call, so no connection is reused across
+		// certificates.
+		tr := ts.Client().Transport.(*http.Transport).Clone()
+		tr.TLSClientConfig.Certificates = certs
+		client := &http.Client{Transport: tr}
+		req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+		req.Header.Set(agentauth.PassportHeader, header(t, &p))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+	if code, body := get(clientCertificate(t, kp.SecretKeyB64)); code != http.StatusOK || body["bound"] != true {
+		t.Fatalf("bound certificate: %d %v", code, body)
+	}
+	if code, body := get(clientCertificate(t, other.SecretKeyB64)); code != http.StatusUnauthorized {
+		t.Fatalf("another agent's certificate: %d %v", code, body)
+	}
+	if code, body := get(); code != http.StatusUnauthorized {
+		t.Fatalf("no certificate: %d %v", code, body)
+	}
+}
@@ -0,0 +1,316 @@
+// Package agentcard lets DCP agents take part in A2A (Agent2Agent)
+// ecosystems: it converts an agent passport to the agent card A2A agents
+// publish at /.well-known/agent-card.json, and verifies the cards of
+// incoming agents against DCP's trust anchors.
+//
+// A card made from a passport lists the passport's capabilities as skills
+// and carries the signed passport in a DCP extension of its capabilities,
+// which A2A clients unaware of DCP ignore. Signed with the agent key, as a
+// detached JWS in the card's signatures, the card proves its publisher
+// holds the passport's key:
+//
+//	card, _ := agentcard.FromPassport(&p, agentcard.Options{Name: "Billing agent", URL: "https://billing.example.com/a2a"})
+//	card.Sign(agentSigner)
+//	h, _ := agentcard.NewHandler(card)
+//	mux.Handle("GET "+agentcard.WellKnownPath, h)
+//
+// Verify checks a card's passport with an agentauth.Authenticator, so the
+// registry and revocation sources that admit agents over HTTP admit them
+// over A2A too.
+package agentcard
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Constants of the cards made by FromPassport.
+const (
+	// ProtocolVersion is the A2A protocol version of the cards.
+	ProtocolVersion = "0.3.0"
+	// WellKnownPath is where an A2A agent serves its card.
+	WellKnownPath = "/.well-known/agent-card.json"
+	// ExtensionURI identifies the DCP extension carrying the passport.
+	ExtensionURI = "https://dcp-ai.org/extensions/a2a/passport/v1"
+	// CapabilityTag tags the skills standing for passport capabilities.
+	CapabilityTag = "dcp-capability"
+	// TransportJSONRPC is the default preferred transport.
+	TransportJSONRPC = "JSONRPC"
+)
+
+// Card is an A2A agent card. A Card decoded from JSON keeps that JSON:
+// its signatures are checked against the card as received, members this
+// type does not know included.
+type Card struct {
+	ProtocolVersion      string       `json:"protocolVersion"`
+	Name                 string       `json:"name"`
+	Description          string       `json:"description"`
+	URL                  string       `json:"url"`
+	PreferredTransport   string       `json:"preferredTransport,omitempty"`
+	AdditionalInterfaces []Interface  `json:"additionalInterfaces,omitempty"`
+	Provider             *Provider    `json:"provider,omitempty"`
+	Version              string       `json:"version"`
+	DocumentationURL     string       `json:"documentationUrl,omitempty"`
+	Capabilities         Capabilities `json:"capabilities"`
+	DefaultInputModes    []string     `json:"defaultInputModes"`
+	DefaultOutputModes   []string     `json:"defaultOutputModes"`
+	Skills               []Skill      `json:"skills"`
+	Signatures           []Signature  `json:"signatures,omitempty"`
+
+	raw json.RawMessage
+}
+
+// UnmarshalJSON decodes a card and keeps data for its signatures.
+func (c *Card) UnmarshalJSON(data []byte) error {
+	type card Card
+	var v card
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = Card(v)
+	c.raw = append(json.RawMessage{}, data...)
+	return nil
+}
+
+// Interface is an endpoint of the agent and the transport it speaks.
+type Interface struct {
+	URL       string `json:"url"`
+	Transport string `json:"transport"`
+}
+
+// Provider is the organisation running the agent.
+type Provider struct {
+	Organization string `json:"organization"`
+	URL          string `json:"url"`
+}
+
+// Capabilities are the optional A2A features the agent supports.
+type Capabilities struct {
+	Streaming              bool        `json:"streaming,omitempty"`
+	PushNotifications      bool        `json:"pushNotifications,omitempty"`
+	StateTransitionHistory bool        `json:"stateTransitionHistory,omitempty"`
+	Extensions             []Extension `json:"extensions,omitempty"`
+}
+
+// Extension is a protocol extension the agent supports.
+type Extension struct {
+	URI         string          `json:"uri"`
+	Description string          `json:"description,omitempty"`
+	Required    bool            `json:"required,omitempty"`
+	Params      json.RawMessage `json:"params,omitempty"`
+}
+
+// Skill is something the agent can do.
+type Skill struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Examples    []string `json:"examples,omitempty"`
+}
+
+// Signature is a JWS signature of the card, in flattened form with a
+// detached payload: the canonical card without its signatures.
+type Signature struct {
+	Protected string `json:"protected"`
+	Signature string `json:"signature"`
+}
+
+// passportParams are the params of the DCP extension.
+type passportParams struct {
+	Passport dcp.AgentPassport `json:"passport"`
+}
+
+// Options describe the agent beyond its passport.
+type Options struct {
+	// Name defaults to the agent ID.
+	Name        string
+	Description string
+	// URL is the agent's A2A endpoint. Required.
+	URL string
+	// Transport is the endpoint's transport; empty means JSONRPC.
+	Transport  string
+	Interfaces []Interface
+	Provider   *Provider
+	// Version is the agent's version; empty means "1.0.0".
+	Version      string
+	Capabilities Capabilities
+	// Skills are listed before those of the passport's capabilities.
+	Skills []Skill
+	// InputModes and OutputModes are media types; empty means text/plain.
+	InputModes  []string
+	OutputModes []string
+}
+
+// capabilityNames name the skills of the passport capabilities.
+var capabilityNames = map[string]string{
+	"browse":     "Web browsing",
+	"api_call":   "API calls",
+	"email":      "Email",
+	"calendar":   "Calendar",
+	"payments":   "Payments",
+	"crm":        "CRM",
+	"file_write": "File writing",
+	"code_exec":  "Code execution",
+}
+
+// FromPassport returns the unsigned card of the agent of passport p.
+func FromPassport(p *dcp.AgentPassport, opts Options) (*Card, error) {
+	if opts.URL == "" {
+		return nil, errors.New("agentcard: the agent's URL is required")
+	}
+	if p.Signature == "" {
+		return nil, fmt.Errorf("agentcard: passport of %s is not signed", p.AgentID)
+	}
+	params, err := json.Marshal(passportParams{Passport: *p})
+	if err != nil {
+		return nil, fmt.Errorf("agentcard: %v", err)
+	}
+	c := &Card{
+		ProtocolVersion:      ProtocolVersion,
+		Name:                 opts.Name,
+		Description:          opts.Description,
+		URL:                  opts.URL,
+		PreferredTransport:   opts.Transport,
+		AdditionalInterfaces: opts.Interfaces,
+		Provider:             opts.Provider,
+		Version:              opts.Version,
+		Capabilities:         opts.Capabilities,
+		DefaultInputModes:    opts.InputModes,
+		DefaultOutputModes:   opts.OutputModes,
+		Skills:               append([]Skill{}, opts.Skills...),
+	}
+	if c.Name == "" {
+		c.Name = p.AgentID
+	}
+	if c.Description == "" {
+		c.Description = "DCP agent " + p.AgentID
+	}
+	if c.PreferredTransport == "" {
+		c.PreferredTransport = TransportJSONRPC
+	}
+	if c.Version == "" {
+		c.Version = "1.0.0"
+	}
+	if len(c.DefaultInputModes) == 0 {
+		c.DefaultInputModes = []string{"text/plain"}
+	}
+	if len(c.DefaultOutputModes) == 0 {
+		c.DefaultOutputModes = []string{"text/plain"}
+	}
+	c.Capabilities.Extensions = append(append([]Extension{}, c.Capabilities.Extensions...), Extension{
+		URI:         ExtensionURI,
+		Description: "DCP agent passport of the agent",
+		Params:      params,
+	})
+	for _, capability := range p.Capabilities {
+		name := capabilityNames[capability]
+		if name == "" {
+			name = capability
+		}
+		c.Skills = append(c.Skills, Skill{
+			ID:          capability,
+			Name:        name,
+			Description: fmt.Sprintf("DCP capability %s, granted by the agent's passport", capability),
+			Tags:        []string{CapabilityTag},
+		})
+	}
+	return c, nil
+}
+
+// Passport returns the passport the card's DCP extension carries, or nil
+// if it has none.
+func (c *Card) Passport() (*dcp.AgentPassport, error) {
+	for _, ext := range c.Capabilities.Extensions {
+		if ext.URI != ExtensionURI {
+			continue
+		}
+		var params passportParams
+		if err := json.Unmarshal(ext.Params, &params); err != nil {
+			return nil, fmt.Errorf("agentcard: DCP extension params: %v", err)
+		}
+		return &params.Passport, nil
+	}
+	return nil, nil
+}
+
+// jwsHeader is the protected header of card signatures.
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Sign adds s's signature of the card. s must hold the key of the card's
+// passport, so the signature proves the publisher is the agent.
+func (c *Card) Sign(s dcp.BundleSigner) error {
+	p, err := c.Passport()
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return errors.New("agentcard: card carries no DCP passport")
+	}
+	if s.PublicKeyB64() != p.PublicKey {
+		return fmt.Errorf("agentcard: signer does not hold the key of agent %s", p.AgentID)
+	}
+	h, err := json.Marshal(jwsHeader{Alg: "EdDSA", Typ: "JOSE", Kid: p.AgentID})
+	if err != nil {
+		return err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(h)
+	input, err := c.signingInput(protected)
+	if err != nil {
+		return err
+	}
+	sig, err := s.SignCanonical(input)
+	if err != nil {
+		return fmt.Errorf("agentcard: sign: %v", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("agentcard: sign: %v", err)
+	}
+	c.Signatures = append(c.Signatures, Signature{Protected: protected, Signature: base64.RawURLEncoding.EncodeToString(raw)})
+	return nil
+}
+
+// signingInput returns the JWS signing input of a signature of the card
+// with the protected header protected.
+func (c *Card) signingInput(protected string) (string, error) {
+	var unsigned interface{}
+	if c.raw != nil {
+		var members map[string]interface{}
+		if err := json.Unmarshal(c.raw, &members); err != nil {
+			return "", fmt.Errorf("agentcard: %v", err)
+		}
+		delete(members, "signatures")
+		unsigned = members
+	} else {
+		card := *c
+		card.Signatures = nil
+		unsigned = card
+	}
+	canon, err := dcp.Canonicalize(unsigned)
+	if err != nil {
+		return "", fmt.Errorf("agentcard: canonicalize: %v", err)
+	}
+	return protected + "." + base64.RawURLEncoding.EncodeToString([]byte(canon)), nil
+}
+
+// NewHandler returns a handler serving card, for WellKnownPath.
+func NewHandler(card *Card) (http.Handler, error) {
+	data, err := json.Marshal(card)
+	if err != nil {
+		return nil, fmt.Errorf("agentcard: %v", err)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}), nil
+}
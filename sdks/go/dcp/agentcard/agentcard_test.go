@@ -0,0 +1,128 @@
+package agentcard_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentcard"
+)
+
+// newAgent returns a passport self-signed by a fresh agent key, and the
+// agent's signer.
+func newAgent(t *testing.T, caps ...string) (*dcp.AgentPassport, *dcp.KeySigner) {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), kp.PublicKeyB64, caps, dcp.RiskTierLow)
+	if err := p.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	return &p, signer
+}
+
+// newCard returns the signed card of a fresh agent able to browse and
+// email.
+func newCard(t *testing.T) (*agentcard.Card, *dcp.AgentPassport) {
+	t.Helper()
+	p, signer := newAgent(t, "browse", "email")
+	card, err := agentcard.FromPassport(p, agentcard.Options{
+		Name:     "Research agent",
+		URL:      "https://agent.example.com/a2a",
+		Provider: &agentcard.Provider{Organization: "Example", URL: "https://example.com"},
+		Skills:   []agentcard.Skill{{ID: "summarize", Name: "Summarize", Description: "Summarizes pages", Tags: []string{"text"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := card.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	return card, p
+}
+
+func TestFromPassport(t *testing.T) {
+	card, p := newCard(t)
+	if card.ProtocolVersion != agentcard.ProtocolVersion || card.Name != "Research agent" || card.PreferredTransport != agentcard.TransportJSONRPC ||
+		card.Version == "" || card.DefaultInputModes[0] != "text/plain" || len(card.Signatures) != 1 {
+		t.Fatalf("%+v", card)
+	}
+	ids := []string{}
+	for _, s := range card.Skills {
+		ids = append(ids, s.ID)
+	}
+	if strings.Join(ids, ",") != "summarize,browse,email" || card.Skills[2].Tags[0] != agentcard.CapabilityTag {
+		t.Fatalf("skills %+v", card.Skills)
+	}
+	got, err := card.Passport()
+	if err != nil || got == nil || !got.Equal(p) {
+		t.Fatalf("passport %+v, %v", got, err)
+	}
+	if !card.VerifySignature(p.PublicKey) {
+		t.Fatal("signature does not verify")
+	}
+
+	if _, err := agentcard.FromPassport(p, agentcard.Options{}); err == nil {
+		t.Fatal("card without a URL made")
+	}
+	unsigned := *p
+	unsigned.Signature = ""
+	if _, err := agentcard.FromPassport(&unsigned, agentcard.Options{URL: "https://agent.example.com"}); err == nil {
+		t.Fatal("card of an unsigned passport made")
+	}
+	_, other := newAgent(t)
+	if err := card.Sign(other); err == nil {
+		t.Fatal("card signed by another agent's key")
+	}
+}
+
+func TestCardJSON(t *testing.T) {
+	card, p := newCard(t)
+	data, err := json.Marshal(card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded agentcard.Card
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.VerifySignature(p.PublicKey) {
+		t.Fatal("signature of the received card does not verify")
+	}
+	for _, tampered := range []string{
+		strings.Replace(string(data), "Research agent", "Payments agent", 1),
+		// Members this package does not know are signed too.
+		strings.Replace(string(data), `{"protocolVersion"`, `{"iconUrl":"https://example.com/icon.png","protocolVersion"`, 1),
+	} {
+		if err := json.Unmarshal([]byte(tampered), &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.VerifySignature(p.PublicKey) {
+			t.Fatalf("tampered card verifies: %s", tampered)
+		}
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	card, _ := newCard(t)
+	h, err := agentcard.NewHandler(card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, agentcard.WellKnownPath, nil))
+	var served agentcard.Card
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" ||
+		json.Unmarshal(rec.Body.Bytes(), &served) != nil || served.URL != card.URL {
+		t.Fatalf("%d %s", rec.Code, rec.Body)
+	}
+}
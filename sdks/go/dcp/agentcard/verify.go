@@ -0,0 +1,121 @@
+package agentcard
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+)
+
+var (
+	// ErrNoPassport reports a card without the DCP extension.
+	ErrNoPassport = errors.New("agent card carries no DCP passport")
+	// ErrInvalidCard reports a card that is malformed, not signed by its
+	// passport's key, or claiming capabilities its passport does not grant.
+	ErrInvalidCard = errors.New("invalid A2A agent card")
+)
+
+// maxCardBytes caps the size of a fetched card.
+const maxCardBytes = 1 << 20
+
+// Verify checks an incoming agent's card against the trust anchors of a:
+// the passport it carries must be admitted by a, which checks it against
+// the registry and revocation sources a is configured with; the card must
+// be signed by the passport's key; and every skill tagged CapabilityTag
+// must be a capability of the passport. It returns the admitted agent.
+// Errors wrap ErrNoPassport, ErrInvalidCard or agentauth's errors.
+func Verify(ctx context.Context, a *agentauth.Authenticator, card *Card) (*agentauth.Identity, error) {
+	p, err := card.Passport()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCard, err)
+	}
+	if p == nil {
+		return nil, ErrNoPassport
+	}
+	id, err := a.Check(ctx, p, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !card.VerifySignature(p.PublicKey) {
+		return nil, fmt.Errorf("%w: not signed by the key of agent %s", ErrInvalidCard, p.AgentID)
+	}
+	for _, skill := range card.Skills {
+		for _, tag := range skill.Tags {
+			if tag == CapabilityTag && !id.HasCapability(skill.ID) {
+				return nil, fmt.Errorf("%w: skill %s is not a capability of agent %s", ErrInvalidCard, skill.ID, p.AgentID)
+			}
+		}
+	}
+	return id, nil
+}
+
+// VerifySignature reports whether one of the card's signatures is an
+// EdDSA signature by publicKeyB64.
+func (c *Card) VerifySignature(publicKeyB64 string) bool {
+	key, err := base64.StdEncoding.DecodeString(publicKeyB64)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return false
+	}
+	for _, s := range c.Signatures {
+		h, err := base64.RawURLEncoding.DecodeString(s.Protected)
+		if err != nil {
+			continue
+		}
+		var header jwsHeader
+		if json.Unmarshal(h, &header) != nil || header.Alg != "EdDSA" {
+			continue
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(s.Signature)
+		if err != nil {
+			continue
+		}
+		input, err := c.signingInput(s.Protected)
+		if err != nil {
+			return false
+		}
+		if ed25519.Verify(key, []byte(input), sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch returns the card an agent serves at WellKnownPath under baseURL,
+// with client, or http.DefaultClient if nil.
+func Fetch(ctx context.Context, client *http.Client, baseURL string) (*Card, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+WellKnownPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCardBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("agentcard: fetch %s: %s", req.URL, resp.Status)
+	}
+	if len(data) > maxCardBytes {
+		return nil, fmt.Errorf("agentcard: card of %s exceeds %d bytes", baseURL, maxCardBytes)
+	}
+	var card Card
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCard, err)
+	}
+	return &card, nil
+}
@@ -0,0 +1,97 @@
+package agentcard_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentcard"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	card, p := newCard(t)
+	auth := dcptest.Authenticator(t, agentauth.Config{Passports: grpcserver.PassportMap{p.AgentID: *p}})
+
+	id, err := agentcard.Verify(ctx, auth, card)
+	if err != nil || id.AgentID() != p.AgentID || !id.HasCapability("email") {
+		t.Fatalf("%+v, %v", id, err)
+	}
+
+	unregistered, _ := newCard(t)
+	if _, err := agentcard.Verify(ctx, auth, unregistered); !errors.Is(err, agentauth.ErrInvalidPassport) {
+		t.Fatalf("unregistered agent: %v", err)
+	}
+
+	revocations := &dcp.RevocationList{}
+	revocations.Add(dcp.NewRevocationRecord(p.AgentID, p.PrincipalBindingReference, "key compromised"))
+	revoking := dcptest.Authenticator(t, agentauth.Config{Revocations: []dcp.RevocationChecker{revocations}})
+	if _, err := agentcard.Verify(ctx, revoking, card); !errors.Is(err, agentauth.ErrInactive) {
+		t.Fatalf("revoked agent: %v", err)
+	}
+
+	unsigned := *card
+	unsigned.Signatures = nil
+	if _, err := agentcard.Verify(ctx, auth, &unsigned); !errors.Is(err, agentcard.ErrInvalidCard) {
+		t.Fatalf("unsigned card: %v", err)
+	}
+
+	// A stranger republishing the agent's passport under its own key.
+	stranger := *card
+	stranger.Signatures = nil
+	_, strangerSigner := newAgent(t)
+	if err := stranger.Sign(strangerSigner); err == nil {
+		t.Fatal("stranger signed the card")
+	}
+
+	escalated := *card
+	escalated.Skills = append(append([]agentcard.Skill{}, card.Skills...), agentcard.Skill{ID: "payments", Name: "Payments", Tags: []string{agentcard.CapabilityTag}})
+	if _, err := agentcard.Verify(ctx, auth, &escalated); !errors.Is(err, agentcard.ErrInvalidCard) {
+		t.Fatalf("card claiming a capability: %v", err)
+	}
+
+	plain := *card
+	plain.Capabilities.Extensions = nil
+	if _, err := agentcard.Verify(ctx, auth, &plain); !errors.Is(err, agentcard.ErrNoPassport) {
+		t.Fatalf("card without a passport: %v", err)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	card, p := newCard(t)
+	h, err := agentcard.NewHandler(card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET "+agentcard.WellKnownPath, h)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	fetched, err := agentcard.Fetch(context.Background(), srv.Client(), srv.URL+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := dcptest.Authenticator(t, agentauth.Config{})
+	if id, err := agentcard.Verify(context.Background(), auth, fetched); err != nil || id.AgentID() != p.AgentID {
+		t.Fatalf("fetched card: %v", err)
+	}
+
+	if _, err := agentcard.Fetch(context.Background(), srv.Client(), srv.URL+"/missing"); err == nil {
+		t.Fatal("missing card fetched")
+	}
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]string{"not", "a", "card"})
+	}))
+	defer broken.Close()
+	if _, err := agentcard.Fetch(context.Background(), broken.Client(), broken.URL); !errors.Is(err, agentcard.ErrInvalidCard) {
+		t.Fatalf("malformed card: %v", err)
+	}
+}
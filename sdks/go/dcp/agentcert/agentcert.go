@@ -0,0 +1,290 @@
+// Package agentcert issues short-lived X.509 client certificates bound to
+// agent passports, and checks the binding on TLS connections, for
+// DCP-aware mTLS.
+//
+// An agent certificate names the agent's ID as its only agent URI SAN, and
+// certifies either the passport key itself or a child key the passport key
+// attests. The attestation, signed with the passport key, rides in a
+// second URI SAN:
+//
+//	URI:dcp:agent:01a13ec4-...                          the agent_id
+//	URI:urn:dcp:key-attestation:<base64url of JSON>     an Attestation, for child keys
+//
+// so an agent can keep its passport key offline and hand its TLS stack a
+// child key that expires with the attestation.
+//
+// Certificates are issued by an Issuer holding a CA key, from a CSR that
+// proves possession of the certified key, or self-signed with SelfSign. A
+// Verifier checks the peer certificate of a TLS connection against the
+// registered passport and revocations, as the tls.Config
+// VerifyPeerCertificate hook.
+package agentcert
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// AttestationURIPrefix prefixes the URI SAN carrying an Attestation.
+const AttestationURIPrefix = "urn:dcp:key-attestation:"
+
+// DefaultTTL is the lifetime of certificates when no TTL is given.
+const DefaultTTL = time.Hour
+
+// Attestation is the passport key's statement that a child key acts for
+// the agent until NotAfter.
+type Attestation struct {
+	AgentID  string `json:"agent_id"`
+	ChildKey string `json:"child_key"`
+	NotAfter string `json:"not_after"`
+	// Signature is by the passport key over the canonical attestation with
+	// an empty signature.
+	Signature string `json:"signature"`
+}
+
+// Attest returns the attestation, by the passport key s of agentID, of
+// the Ed25519 key child until notAfter.
+func Attest(agentID string, child ed25519.PublicKey, notAfter time.Time, s dcp.BundleSigner) (*Attestation, error) {
+	a := &Attestation{
+		AgentID:  agentID,
+		ChildKey: base64.StdEncoding.EncodeToString(child),
+		NotAfter: dcp.FormatTime(notAfter),
+	}
+	canon, err := dcp.Canonicalize(a)
+	if err != nil {
+		return nil, err
+	}
+	if a.Signature, err = s.SignCanonical(canon); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Verify checks that a is signed by the key of passport p, attests key
+// for its agent, and has not expired at now.
+func (a *Attestation) Verify(p *dcp.AgentPassport, key ed25519.PublicKey, now time.Time) error {
+	if a.AgentID != p.AgentID {
+		return fmt.Errorf("attestation is for %s, not %s", a.AgentID, p.AgentID)
+	}
+	if a.ChildKey != base64.StdEncoding.EncodeToString(key) {
+		return errors.New("attestation is for another key")
+	}
+	notAfter, err := dcp.ParseTime(a.NotAfter)
+	if err != nil {
+		return fmt.Errorf("attestation not_after: %v", err)
+	}
+	if !now.Before(notAfter) {
+		return fmt.Errorf("attestation expired at %s", a.NotAfter)
+	}
+	unsigned := *a
+	unsigned.Signature = ""
+	if ok, err := dcp.VerifyObject(unsigned, a.Signature, p.PublicKey); err != nil || !ok {
+		return errors.New("attestation signature does not verify under the passport key")
+	}
+	return nil
+}
+
+// uri returns a as a URI SAN.
+func (a *Attestation) uri() (*url.URL, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return url.Parse(AttestationURIPrefix + base64.RawURLEncoding.EncodeToString(data))
+}
+
+// Binding is what an agent certificate states.
+type Binding struct {
+	AgentID string
+	Key     ed25519.PublicKey
+	// Attestation is nil when Key is meant to be the passport key.
+	Attestation *Attestation
+}
+
+// ParseBinding returns the binding stated by cert.
+func ParseBinding(cert *x509.Certificate) (*Binding, error) {
+	key, ok := cert.PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("agent certificate key is not Ed25519")
+	}
+	b := &Binding{Key: key}
+	for _, u := range cert.URIs {
+		s := u.String()
+		if encoded, ok := strings.CutPrefix(s, AttestationURIPrefix); ok {
+			if b.Attestation != nil {
+				return nil, errors.New("agent certificate carries two attestations")
+			}
+			data, err := base64.RawURLEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, errors.New("attestation URI is not base64url")
+			}
+			b.Attestation = &Attestation{}
+			if err := json.Unmarshal(data, b.Attestation); err != nil {
+				return nil, fmt.Errorf("attestation: %v", err)
+			}
+			continue
+		}
+		if b.AgentID != "" {
+			return nil, errors.New("agent certificate names two agents")
+		}
+		b.AgentID = s
+	}
+	if b.AgentID == "" {
+		return nil, errors.New("agent certificate names no agent")
+	}
+	return b, nil
+}
+
+// Check checks that b binds its key to passport p at now: the key is the
+// passport key, or one it attests.
+func (b *Binding) Check(p *dcp.AgentPassport, now time.Time) error {
+	if b.AgentID != p.AgentID {
+		return fmt.Errorf("certificate names %s, not %s", b.AgentID, p.AgentID)
+	}
+	if b.Attestation != nil {
+		return b.Attestation.Verify(p, b.Key, now)
+	}
+	if base64.StdEncoding.EncodeToString(b.Key) != p.PublicKey {
+		return errors.New("certificate key is not the passport key and carries no attestation")
+	}
+	return nil
+}
+
+// template returns the certificate template binding key to the agent of p.
+func template(p *dcp.AgentPassport, att *Attestation, now time.Time, ttl time.Duration) (*x509.Certificate, error) {
+	agentURI, err := url.Parse(p.AgentID)
+	if err != nil || agentURI.Scheme == "" {
+		return nil, fmt.Errorf("agentcert: agent_id %q is not a URI", p.AgentID)
+	}
+	uris := []*url.URL{agentURI}
+	notAfter := now.Add(ttl)
+	if att != nil {
+		u, err := att.uri()
+		if err != nil {
+			return nil, fmt.Errorf("agentcert: attestation: %v", err)
+		}
+		uris = append(uris, u)
+		// The certificate expires with the attestation.
+		if attNotAfter, err := dcp.ParseTime(att.NotAfter); err == nil && attNotAfter.Before(notAfter) {
+			notAfter = attNotAfter
+		}
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: p.AgentID},
+		URIs:         uris,
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, nil
+}
+
+// checkKey checks that key may be certified for passport p: it is the
+// passport key, or att attests it.
+func checkKey(p *dcp.AgentPassport, key crypto.PublicKey, att *Attestation, now time.Time) error {
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return errors.New("agentcert: the certified key must be Ed25519")
+	}
+	b := &Binding{AgentID: p.AgentID, Key: edKey, Attestation: att}
+	if err := b.Check(p, now); err != nil {
+		return fmt.Errorf("agentcert: %v", err)
+	}
+	return nil
+}
+
+// SelfSign returns a self-signed certificate for the agent of passport p,
+// certifying the public key of key, which is the passport key or a child
+// key att attests. ttl zero means DefaultTTL.
+func SelfSign(p *dcp.AgentPassport, key crypto.Signer, att *Attestation, ttl time.Duration) ([]byte, error) {
+	now := time.Now()
+	if err := checkKey(p, key.Public(), att, now); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	tmpl, err := template(p, att, now, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+}
+
+// NewCSR returns a DER certificate request for the agent of passport p,
+// signed with key, to send to an Issuer.
+func NewCSR(p *dcp.AgentPassport, key crypto.Signer) ([]byte, error) {
+	agentURI, err := url.Parse(p.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("agentcert: agent_id %q is not a URI", p.AgentID)
+	}
+	return x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: p.AgentID},
+		URIs:    []*url.URL{agentURI},
+	}, key)
+}
+
+// Issuer issues agent certificates under a CA.
+type Issuer struct {
+	CA  *x509.Certificate
+	Key crypto.Signer
+	// TTL is the lifetime of certificates; zero means DefaultTTL.
+	TTL time.Duration
+	// Now is the clock certificates are dated with; nil means time.Now.
+	Now func() time.Time
+}
+
+// Issue returns a DER certificate for the key of the DER request csr,
+// bound to passport p, which the caller has verified. The key must be the
+// passport key, or a child key att attests.
+func (i *Issuer) Issue(csrDER []byte, p *dcp.AgentPassport, att *Attestation) ([]byte, error) {
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, fmt.Errorf("agentcert: request: %v", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("agentcert: request signature: %v", err)
+	}
+	for _, u := range csr.URIs {
+		if u.String() != p.AgentID {
+			return nil, fmt.Errorf("agentcert: request names %s, not %s", u, p.AgentID)
+		}
+	}
+	now := time.Now()
+	if i.Now != nil {
+		now = i.Now()
+	}
+	if err := checkKey(p, csr.PublicKey, att, now); err != nil {
+		return nil, err
+	}
+	ttl := i.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	tmpl, err := template(p, att, now, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl.NotAfter.After(i.CA.NotAfter) {
+		tmpl.NotAfter = i.CA.NotAfter
+	}
+	return x509.CreateCertificate(rand.Reader, tmpl, i.CA, csr.PublicKey, i.Key)
+}
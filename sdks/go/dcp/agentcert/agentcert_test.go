@@ -0,0 +1,160 @@
+package agentcert_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentcert"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+)
+
+// child returns a fresh child key attested by the passport key until
+// notAfter.
+func child(t *testing.T, p *dcp.AgentPassport, s dcp.BundleSigner, notAfter time.Time) (ed25519.PrivateKey, *agentcert.Attestation) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	att, err := agentcert.Attest(p.AgentID, pub, notAfter, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, att
+}
+
+// newCA returns a self-signed CA certificate and its key.
+func newCA(t *testing.T) (*x509.Certificate, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "agent CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, pub, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ca, priv
+}
+
+func parse(t *testing.T, der []byte) *x509.Certificate {
+	t.Helper()
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestSelfSign(t *testing.T) {
+	p, signer, key := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	cert := parse(t, mustSelfSign(t, p, key, nil))
+	b, err := agentcert.ParseBinding(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.AgentID != p.AgentID || b.Attestation != nil || b.Check(p, time.Now()) != nil {
+		t.Fatalf("binding %+v", b)
+	}
+	if cert.NotAfter.After(time.Now().Add(agentcert.DefaultTTL)) {
+		t.Fatalf("certificate valid until %s", cert.NotAfter)
+	}
+
+	// A child key, with a certificate expiring with its attestation.
+	expires := time.Now().Add(10 * time.Minute)
+	childKey, att := child(t, p, signer, expires)
+	cert = parse(t, mustSelfSign(t, p, childKey, att))
+	b, err = agentcert.ParseBinding(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Attestation == nil || b.Check(p, time.Now()) != nil || cert.NotAfter.After(expires) {
+		t.Fatalf("child binding %+v until %s", b, cert.NotAfter)
+	}
+	if b.Check(p, expires.Add(time.Second)) == nil {
+		t.Fatal("expired attestation accepted")
+	}
+
+	other, _, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	if b.Check(other, time.Now()) == nil {
+		t.Fatal("binding accepted for another passport")
+	}
+	if _, err := agentcert.SelfSign(p, childKey, nil, 0); err == nil {
+		t.Fatal("self-signed an unattested child key")
+	}
+	_, otherSigner, otherKey := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	_, foreign := child(t, other, otherSigner, expires)
+	if _, err := agentcert.SelfSign(p, otherKey, foreign, 0); err == nil {
+		t.Fatal("self-signed under another agent's attestation")
+	}
+}
+
+func mustSelfSign(t *testing.T, p *dcp.AgentPassport, key ed25519.PrivateKey, att *agentcert.Attestation) []byte {
+	t.Helper()
+	der, err := agentcert.SelfSign(p, key, att, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestIssuer(t *testing.T) {
+	p, signer, key := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	ca, caKey := newCA(t)
+	iss := &agentcert.Issuer{CA: ca, Key: caKey, TTL: 15 * time.Minute}
+
+	csr, err := agentcert.NewCSR(p, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := iss.Issue(csr, p, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := parse(t, der)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Fatal(err)
+	}
+
+	childKey, att := child(t, p, signer, time.Now().Add(time.Hour))
+	csr, err = agentcert.NewCSR(p, childKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := iss.Issue(csr, p, nil); err == nil {
+		t.Fatal("issued for an unattested child key")
+	}
+	if _, err := iss.Issue(csr, p, att); err != nil {
+		t.Fatal(err)
+	}
+
+	other, _, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	csr, err = agentcert.NewCSR(other, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := iss.Issue(csr, p, nil); err == nil {
+		t.Fatal("issued for a request naming another agent")
+	}
+}
@@ -0,0 +1,146 @@
+package agentcert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// PassportSource returns the registered passport of an agent, or nil if it
+// has none, like agentauth.PassportSource.
+type PassportSource interface {
+	Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error)
+}
+
+// Verifier checks agent certificates against the registry.
+type Verifier struct {
+	// Passports holds the registered passports. Required.
+	Passports PassportSource
+	// Revocations are consulted for the agent; a revoked agent is refused.
+	Revocations []dcp.RevocationChecker
+	// Roots, if set, must issue agent certificates. Without it, self-signed
+	// certificates are accepted: the binding to the registered passport is
+	// what authenticates the agent.
+	Roots *x509.CertPool
+	// Timeout bounds the registry and revocation lookups of a handshake;
+	// zero means 10 seconds.
+	Timeout time.Duration
+	// Now is the clock certificates are checked against; nil means
+	// time.Now.
+	Now func() time.Time
+}
+
+// Verify checks that cert, with intermediates, binds its key to the
+// registered, active and unrevoked passport of the agent it names, and
+// returns the passport.
+func (v *Verifier) Verify(ctx context.Context, cert *x509.Certificate, intermediates []*x509.Certificate) (*dcp.AgentPassport, error) {
+	if v.Passports == nil {
+		return nil, errors.New("agentcert: a passport source is required")
+	}
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, fmt.Errorf("agentcert: certificate is valid from %s to %s", dcp.FormatTime(cert.NotBefore), dcp.FormatTime(cert.NotAfter))
+	}
+	if v.Roots != nil {
+		pool := x509.NewCertPool()
+		for _, c := range intermediates {
+			pool.AddCert(c)
+		}
+		if _, err := cert.Verify(x509.VerifyOptions{
+			Roots:         v.Roots,
+			Intermediates: pool,
+			CurrentTime:   now,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}); err != nil {
+			return nil, fmt.Errorf("agentcert: %v", err)
+		}
+	}
+	b, err := ParseBinding(cert)
+	if err != nil {
+		return nil, fmt.Errorf("agentcert: %v", err)
+	}
+	p, err := v.Passports.Passport(ctx, b.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("agentcert: registry: %v", err)
+	}
+	if p == nil {
+		return nil, fmt.Errorf("agentcert: no passport is registered for %s", b.AgentID)
+	}
+	if ok, err := p.VerifySignature(); err != nil || !ok {
+		return nil, fmt.Errorf("agentcert: registered passport of %s is not signed by its public_key", b.AgentID)
+	}
+	if p.Status != dcp.StatusActive {
+		return nil, fmt.Errorf("agentcert: %s is %s", p.AgentID, p.Status)
+	}
+	if err := b.Check(p, now); err != nil {
+		return nil, fmt.Errorf("agentcert: %v", err)
+	}
+	for _, rc := range v.Revocations {
+		rec, err := rc.CheckRevocation(ctx, p.AgentID)
+		if err != nil {
+			return nil, fmt.Errorf("agentcert: revocation source: %v", err)
+		}
+		if rec != nil {
+			return nil, fmt.Errorf("agentcert: %s was revoked at %s", p.AgentID, rec.Timestamp)
+		}
+	}
+	return p, nil
+}
+
+// VerifyPeerCertificate is a tls.Config VerifyPeerCertificate hook
+// refusing connections whose client certificate Verify refuses.
+func (v *Verifier) VerifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("agentcert: no client certificate")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		c, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("agentcert: %v", err)
+		}
+		certs[i] = c
+	}
+	timeout := v.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, err := v.Verify(ctx, certs[0], certs[1:])
+	return err
+}
+
+// ServerConfig returns a copy of base, or of an empty config if base is
+// nil, requiring client certificates and checking them with v.
+func (v *Verifier) ServerConfig(base *tls.Config) *tls.Config {
+	cfg := &tls.Config{}
+	if base != nil {
+		cfg = base.Clone()
+	}
+	// The chain, if any, is checked by Verify against v.Roots.
+	cfg.ClientAuth = tls.RequireAnyClientCert
+	cfg.VerifyPeerCertificate = v.VerifyPeerCertificate
+	return cfg
+}
+
+// AgentID returns the agent ID named by the client certificate of conn,
+// once the handshake has checked it.
+func AgentID(conn *tls.ConnectionState) (string, error) {
+	if conn == nil || len(conn.PeerCertificates) == 0 {
+		return "", errors.New("agentcert: no client certificate")
+	}
+	b, err := ParseBinding(conn.PeerCertificates[0])
+	if err != nil {
+		return "", fmt.Errorf("agentcert: %v", err)
+	}
+	return b.AgentID, nil
+}
@@ -0,0 +1,119 @@
+package agentcert_test
+
+import (
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentcert"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+)
+
+var (
+	_ agentcert.PassportSource = grpcserver.PassportMap{}
+	_ agentcert.PassportSource = (*registry.Client)(nil)
+)
+
+// serve runs a TLS server answering the agent ID of its clients, checked
+// by v.
+func serve(t *testing.T, v *agentcert.Verifier) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := agentcert.AgentID(r.TLS)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		io.WriteString(w, id)
+	}))
+	ts.TLS = v.ServerConfig(nil)
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// get calls ts presenting the certificate der, and returns the answer or
+// the handshake error.
+func get(t *testing.T, ts *httptest.Server, der []byte, key ed25519.PrivateKey) (string, error) {
+	t.Helper()
+	// A fresh transport per call, so no connection is reused across
+	// certificates.
+	tr := ts.Client().Transport.(*http.Transport).Clone()
+	tr.TLSClientConfig.Certificates = []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}
+	resp, err := (&http.Client{Transport: tr}).Get(ts.URL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body), nil
+}
+
+func TestVerifyTLS(t *testing.T) {
+	p, signer, key := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	revoked, _, revokedKey := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	unregistered, unregisteredSigner, unregisteredKey := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	revocations := &dcp.RevocationList{}
+	revocations.Add(dcp.NewRevocationRecord(revoked.AgentID, revoked.PrincipalBindingReference, "retired"))
+	v := &agentcert.Verifier{
+		Passports:   grpcserver.PassportMap{p.AgentID: *p, revoked.AgentID: *revoked},
+		Revocations: []dcp.RevocationChecker{revocations},
+	}
+	ts := serve(t, v)
+
+	if id, err := get(t, ts, mustSelfSign(t, p, key, nil), key); err != nil || id != p.AgentID {
+		t.Fatalf("passport key: %q, %v", id, err)
+	}
+	childKey, att := child(t, p, signer, time.Now().Add(time.Hour))
+	if id, err := get(t, ts, mustSelfSign(t, p, childKey, att), childKey); err != nil || id != p.AgentID {
+		t.Fatalf("attested child key: %q, %v", id, err)
+	}
+	if _, err := get(t, ts, mustSelfSign(t, revoked, revokedKey, nil), revokedKey); err == nil {
+		t.Fatal("revoked agent admitted")
+	}
+	if _, err := get(t, ts, mustSelfSign(t, unregistered, unregisteredKey, nil), unregisteredKey); err == nil {
+		t.Fatal("unregistered agent admitted")
+	}
+
+	// The registered passport changed key since the certificate was made.
+	rotated := *p
+	rotated.PublicKey = unregistered.PublicKey
+	if err := rotated.Sign(unregisteredSigner); err != nil {
+		t.Fatal(err)
+	}
+	v.Passports = grpcserver.PassportMap{p.AgentID: rotated}
+	if _, err := get(t, ts, mustSelfSign(t, p, key, nil), key); err == nil {
+		t.Fatal("certificate of a replaced key admitted")
+	}
+}
+
+func TestVerifyRoots(t *testing.T) {
+	p, _, key := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	ca, caKey := newCA(t)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	ts := serve(t, &agentcert.Verifier{Passports: grpcserver.PassportMap{p.AgentID: *p}, Roots: roots})
+
+	csr, err := agentcert.NewCSR(p, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := (&agentcert.Issuer{CA: ca, Key: caKey}).Issue(csr, p, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := get(t, ts, der, key); err != nil || id != p.AgentID {
+		t.Fatalf("CA-issued certificate: %q, %v", id, err)
+	}
+	if _, err := get(t, ts, mustSelfSign(t, p, key, nil), key); err == nil {
+		t.Fatal("self-signed certificate admitted with Roots set")
+	}
+}
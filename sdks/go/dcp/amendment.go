@@ -0,0 +1,135 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAmendmentChain is returned for intent versions that do not form a
+// chain of amendments, each superseding the one before.
+var ErrAmendmentChain = errors.New("amendment chain broken")
+
+// AmendIntent returns a new version of prev: a copy changed by amend, then
+// dated now and superseding prev. The intent, agent and principal stay
+// those of prev. An idempotency key derived from prev is derived again for
+// the new version.
+func (f *RecordFactory) AmendIntent(prev Intent, amend func(*Intent)) (Intent, error) {
+	prevHash, err := HashObject(prev)
+	if err != nil {
+		return Intent{}, fmt.Errorf("amend intent %s: hash: %w", prev.IntentID, err)
+	}
+	next := *prev.Clone()
+	if amend != nil {
+		amend(&next)
+	}
+	next.IntentID, next.AgentID, next.HumanID = prev.IntentID, prev.AgentID, prev.HumanID
+	next.Timestamp = f.timestamp()
+	next.Supersedes = prevHash
+	if derived, err := DeriveIdempotencyKey(&prev); err == nil && prev.IdempotencyKey == derived {
+		if err := next.SetIdempotencyKey(); err != nil {
+			return Intent{}, fmt.Errorf("amend intent %s: %w", prev.IntentID, err)
+		}
+	}
+	return next, nil
+}
+
+// AmendIntent calls RecordFactory.AmendIntent with the package clock.
+func AmendIntent(prev Intent, amend func(*Intent)) (Intent, error) {
+	return defaultRecords.AmendIntent(prev, amend)
+}
+
+// AmendmentChain walks back from latest through the versions it supersedes,
+// found among versions in any order, and returns the chain oldest first,
+// ending with latest. Every version must be of latest's intent, agent and
+// principal and dated no later than the one amending it. Errors wrap
+// ErrAmendmentChain.
+func AmendmentChain(latest Intent, versions []Intent) ([]Intent, error) {
+	byHash := make(map[string]int, len(versions))
+	for i := range versions {
+		h, err := HashObject(versions[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: version %d: hash: %v", ErrAmendmentChain, i, err)
+		}
+		byHash[h] = i
+	}
+	chain := []Intent{latest}
+	for cur := latest; cur.Supersedes != ""; {
+		i, ok := byHash[cur.Supersedes]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s supersedes %s, which is not among the versions", ErrAmendmentChain, cur.IntentID, cur.Supersedes)
+		}
+		if len(chain) > len(versions) {
+			return nil, fmt.Errorf("%w: the versions of %s form a loop", ErrAmendmentChain, cur.IntentID)
+		}
+		prev := versions[i]
+		if err := checkAmendment(&prev, &cur); err != nil {
+			return nil, err
+		}
+		chain = append(chain, prev)
+		cur = prev
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+// CheckAmendments reports whether history, oldest first, is the amendment
+// chain ending with latest, and every audit entry on the intent references
+// latest by its intent_hash. Errors wrap ErrAmendmentChain.
+func CheckAmendments(latest *Intent, history []Intent, entries []AuditEntry) error {
+	if err := checkAmendments(latest, history); err != nil {
+		return err
+	}
+	latestHash, err := HashObject(latest)
+	if err != nil {
+		return fmt.Errorf("%w: intent hash: %v", ErrAmendmentChain, err)
+	}
+	for i := range entries {
+		e := &entries[i]
+		if e.IntentID == latest.IntentID && e.IntentHash != latestHash {
+			return fmt.Errorf("%w: audit entry %d references %s, not the latest version %s", ErrAmendmentChain, i, e.IntentHash, latestHash)
+		}
+	}
+	return nil
+}
+
+func checkAmendments(latest *Intent, history []Intent) error {
+	chain, err := AmendmentChain(*latest, history)
+	if err != nil {
+		return err
+	}
+	if len(chain) != len(history)+1 {
+		return fmt.Errorf("%w: the chain has %d earlier versions, the history %d", ErrAmendmentChain, len(chain)-1, len(history))
+	}
+	for i := range history {
+		if !chain[i].Equal(&history[i]) {
+			return fmt.Errorf("%w: history version %d is out of order", ErrAmendmentChain, i)
+		}
+	}
+	return nil
+}
+
+// checkAmendment checks that next may amend prev.
+func checkAmendment(prev, next *Intent) error {
+	switch {
+	case prev.IntentID != next.IntentID:
+		return fmt.Errorf("%w: %s amends %s", ErrAmendmentChain, next.IntentID, prev.IntentID)
+	case prev.AgentID != next.AgentID:
+		return fmt.Errorf("%w: %s changes agent_id from %s to %s", ErrAmendmentChain, next.IntentID, prev.AgentID, next.AgentID)
+	case prev.HumanID != next.HumanID:
+		return fmt.Errorf("%w: %s changes human_id from %s to %s", ErrAmendmentChain, next.IntentID, prev.HumanID, next.HumanID)
+	}
+	from, err := prev.TimestampTime()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAmendmentChain, err)
+	}
+	at, err := next.TimestampTime()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAmendmentChain, err)
+	}
+	if at.Before(from) {
+		return fmt.Errorf("%w: amendment of %s at %s, before the version it supersedes at %s", ErrAmendmentChain, next.IntentID, next.Timestamp, prev.Timestamp)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestAmendmentChain(t *testing.T) {
+	b, human, _ := builderFixture(t)
+	bundle, err := b.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := func(i dcp.Intent) string {
+		h, _ := dcp.HashObject(i)
+		return h
+	}
+	amend := func(prev dcp.Intent, at time.Time, impact dcp.EstimatedImpact) dcp.Intent {
+		t.Helper()
+		f := dcp.RecordFactory{Clock: func() time.Time { return at }}
+		next, err := f.AmendIntent(prev, func(i *dcp.Intent) { i.EstimatedImpact = impact; i.IntentID = "intent999" })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return next
+	}
+	v1 := bundle.Intent
+	v1.Timestamp, v1.EstimatedImpact = "2026-01-01T00:50:00Z", "low"
+	v2 := amend(v1, time.Date(2026, 1, 1, 0, 55, 0, 0, time.UTC), "high")
+	if v2.IntentID != "intent001" || v2.Supersedes != hash(v1) || v2.EstimatedImpact != "high" {
+		t.Fatalf("amended: %+v", v2)
+	}
+	v3 := amend(v2, time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC), bundle.Intent.EstimatedImpact)
+	bundle, err = b.Intent(v3).Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain, err := dcp.AmendmentChain(v3, []dcp.Intent{v2, v1})
+	if err != nil || len(chain) != 3 || !chain[0].Equal(&v1) || !chain[2].Equal(&v3) {
+		t.Fatalf("chain = %v, %v", chain, err)
+	}
+	if err := dcp.CheckAmendments(&v3, []dcp.Intent{v1, v2}, bundle.AuditEntries); err != nil {
+		t.Fatal(err)
+	}
+	if err := dcp.CheckAmendments(&v2, []dcp.Intent{v1}, bundle.AuditEntries); err == nil || !strings.Contains(err.Error(), "not the latest version") {
+		t.Fatalf("entries on a later version: %v", err)
+	}
+	early := v2
+	early.Timestamp = "2026-01-01T00:40:00Z"
+	moved := v2
+	moved.HumanID = "human002"
+	for _, tc := range []struct {
+		name    string
+		latest  dcp.Intent
+		history []dcp.Intent
+		want    string
+	}{
+		{"missing version", v3, []dcp.Intent{v1}, "not among the versions"},
+		{"extra version", v2, []dcp.Intent{v1, v1}, "history 2"},
+		{"out of order", v3, []dcp.Intent{v2, v1}, "out of order"},
+		{"dated earlier", early, []dcp.Intent{v1}, "before the version it supersedes"},
+		{"another principal", moved, []dcp.Intent{v1}, "changes human_id"},
+	} {
+		if err := dcp.CheckAmendments(&tc.latest, tc.history, nil); !errors.Is(err, dcp.ErrAmendmentChain) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+
+	principal, _ := dcp.NewKeySigner(human.SecretKeyB64)
+	sign := func(b *dcp.CitizenshipBundle) *dcp.SignedBundle {
+		t.Helper()
+		sb, err := dcp.SignBundle(b, principal, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+	amended := bundle.Clone()
+	amended.IntentHistory = []dcp.Intent{v1, v2}
+	if err := amended.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	res := dcp.VerifySignedBundleWithOptions(sign(amended), dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, Explain: true})
+	if !res.Verified {
+		t.Fatalf("amended bundle: %v", res.Errors)
+	}
+	if last := res.Trace[len(res.Trace)-1]; last.Check != dcp.TraceAmendments || !last.OK {
+		t.Fatalf("trace: %+v", last)
+	}
+	data, _ := json.Marshal(sign(amended))
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundle(rsb, human.PublicKeyB64); !res.Verified {
+		t.Fatalf("raw bundle: %v", res.Errors)
+	}
+	amended.IntentHistory = amended.IntentHistory[1:]
+	if res := dcp.VerifySignedBundle(sign(amended), human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "not among the versions") {
+		t.Fatalf("truncated history: %+v", res)
+	}
+}
@@ -0,0 +1,243 @@
+// Package apiclient is a typed Go client for the SDK's HTTP services, as
+// described by the OpenAPI document of package openapi. Each service runs
+// at its own URL and has its own client type; answers are decoded into the
+// types the services themselves encode, and signed answers are verified
+// before they are returned.
+//
+// A non-2xx answer is returned as an *Error carrying the status code and
+// the service's {"error": ...} message. The registry client is
+// registry.Client, whose submission errors wrap the registry's sentinel
+// errors instead.
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/revocationserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/verifyserver"
+)
+
+// Error is a non-2xx answer from a service.
+type Error struct {
+	StatusCode int
+	// Message is the service's error message, or the status text if the
+	// answer carried none.
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Registry is the client of the passport registry (tag "registry").
+type Registry = registry.Client
+
+// Verifier is the client of a verification service (tag "verify").
+type Verifier struct {
+	// URL is the service base URL, e.g. "http://localhost:8080".
+	URL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Verify submits sb for verification. A bundle that does not verify is not
+// an error: the Result says why. explain asks for the step-by-step trace.
+func (c *Verifier) Verify(ctx context.Context, sb *dcp.SignedBundle, explain bool) (*verifyserver.Result, error) {
+	path := "/v1/verify"
+	if explain {
+		path += "?explain=true"
+	}
+	var res verifyserver.Result
+	if _, err := do(ctx, c.HTTPClient, http.MethodPost, c.URL, path, sb, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Health returns the service's health summary.
+func (c *Verifier) Health(ctx context.Context) (map[string]interface{}, error) {
+	return health(ctx, c.HTTPClient, c.URL)
+}
+
+// Revocations is the client of a revocation registry (tag "revocation").
+type Revocations struct {
+	// URL is the service base URL, e.g. "http://localhost:3003".
+	URL string
+	// PublicKeyB64 is the registry's list signing key, which List verifies
+	// the list under.
+	PublicKeyB64 string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// RevokeResult is the answer to a revocation.
+type RevokeResult struct {
+	OK        bool   `json:"ok"`
+	AgentID   string `json:"agent_id"`
+	RevokedAt string `json:"revoked_at"`
+	Sequence  uint64 `json:"sequence"`
+	// Created reports whether this request revoked the agent, rather than
+	// finding it already revoked.
+	Created bool `json:"-"`
+}
+
+// Revoke submits a signed revocation record.
+func (c *Revocations) Revoke(ctx context.Context, r dcp.RevocationRecord) (*RevokeResult, error) {
+	var res RevokeResult
+	status, err := do(ctx, c.HTTPClient, http.MethodPost, c.URL, "/revoke", r, &res)
+	if err != nil {
+		return nil, err
+	}
+	res.Created = status == http.StatusCreated
+	return &res, nil
+}
+
+// Check returns the record revoking agentID, or nil if it is not revoked.
+// The answer is not signed; use List to check against the signed list.
+func (c *Revocations) Check(ctx context.Context, agentID string) (*dcp.RevocationRecord, error) {
+	var res struct {
+		Revoked bool                  `json:"revoked"`
+		Record  *dcp.RevocationRecord `json:"record"`
+	}
+	if _, err := do(ctx, c.HTTPClient, http.MethodGet, c.URL, "/check/"+url.PathEscape(agentID), nil, &res); err != nil {
+		return nil, err
+	}
+	if !res.Revoked {
+		return nil, nil
+	}
+	if res.Record == nil {
+		return nil, fmt.Errorf("%s: %s is revoked but no record was returned", c.URL, agentID)
+	}
+	return res.Record, nil
+}
+
+// List returns the signed list after verifying it under PublicKeyB64.
+func (c *Revocations) List(ctx context.Context) (*revocationserver.SignedList, error) {
+	if c.PublicKeyB64 == "" {
+		return nil, errors.New("apiclient: the revocation list key is not set")
+	}
+	var l revocationserver.SignedList
+	if _, err := do(ctx, c.HTTPClient, http.MethodGet, c.URL, "/list", nil, &l); err != nil {
+		return nil, err
+	}
+	if ok, err := l.Verify(c.PublicKeyB64); err != nil || !ok {
+		return nil, fmt.Errorf("%s: list signature does not verify under the registry key", c.URL)
+	}
+	if l.Sequence != uint64(len(l.Revocations)) {
+		return nil, fmt.Errorf("%s: sequence %d does not match %d revocations", c.URL, l.Sequence, len(l.Revocations))
+	}
+	return &l, nil
+}
+
+// Health returns the service's health summary.
+func (c *Revocations) Health(ctx context.Context) (map[string]interface{}, error) {
+	return health(ctx, c.HTTPClient, c.URL)
+}
+
+// PDP is the client of a policy decision point (tag "pdp").
+type PDP struct {
+	// URL is the service base URL, e.g. "http://localhost:8082".
+	URL string
+	// PublicKeyB64 is the PDP's decision key; a decision that does not
+	// verify under it for the submitted intent is an error.
+	PublicKeyB64 string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Decide asks for a decision on intent and verifies it.
+func (c *PDP) Decide(ctx context.Context, intent *dcp.Intent) (*pdp.SignedDecision, error) {
+	var d pdp.SignedDecision
+	if _, err := do(ctx, c.HTTPClient, http.MethodPost, c.URL, "/v1/decide", intent, &d); err != nil {
+		return nil, err
+	}
+	if err := d.Verify(c.PublicKeyB64, intent); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.URL, err)
+	}
+	return &d, nil
+}
+
+// Policy returns the PDP's policy set and its hash, the policy_hash of its
+// decisions. The set is nil for a PDP deciding with another policy engine.
+func (c *PDP) Policy(ctx context.Context) (*pdp.PolicySet, string, error) {
+	var res struct {
+		Policy     *pdp.PolicySet `json:"policy"`
+		PolicyHash string         `json:"policy_hash"`
+	}
+	if _, err := do(ctx, c.HTTPClient, http.MethodGet, c.URL, "/v1/policy", nil, &res); err != nil {
+		return nil, "", err
+	}
+	return res.Policy, res.PolicyHash, nil
+}
+
+// Health returns the service's health summary.
+func (c *PDP) Health(ctx context.Context) (map[string]interface{}, error) {
+	return health(ctx, c.HTTPClient, c.URL)
+}
+
+func health(ctx context.Context, client *http.Client, baseURL string) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if _, err := do(ctx, client, http.MethodGet, baseURL, "/health", nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// do sends body, if any, to baseURL+path and decodes a 2xx answer into
+// out, returning the status code.
+func do(ctx context.Context, client *http.Client, method, baseURL, path string, body, out interface{}) (int, error) {
+	u := strings.TrimRight(baseURL, "/") + path
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode/100 != 2 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(data, &e)
+		if e.Error == "" {
+			e.Error = http.StatusText(resp.StatusCode)
+		}
+		return resp.StatusCode, &Error{StatusCode: resp.StatusCode, Message: e.Error}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("%s %s: %w", method, u, err)
+	}
+	return resp.StatusCode, nil
+}
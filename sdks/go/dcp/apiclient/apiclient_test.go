@@ -0,0 +1,146 @@
+package apiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/apiclient"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/revocationserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/verifyserver"
+)
+
+func readExample(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	_, thisFile, _, _ := runtime.Caller(0)
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(thisFile), "..", "..", "..", "..", "tests", "conformance", "examples", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	var sb dcp.SignedBundle
+	readExample(t, "citizenship_bundle.signed.json", &sb)
+	signer, pub := dcptest.NewSigner(t)
+	signed, err := dcp.SignBundle(&sb.Bundle, signer, dcp.Signer{Type: "human", ID: sb.Bundle.ResponsiblePrincipalRecord.HumanID}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(verifyserver.New(verifyserver.Config{TrustedKeys: []string{pub}}))
+	defer ts.Close()
+	c := &apiclient.Verifier{URL: ts.URL + "/"}
+	ctx := context.Background()
+
+	res, err := c.Verify(ctx, signed, true)
+	if err != nil || !res.Verified || len(res.Trace) == 0 {
+		t.Fatalf("Verify = %+v, %v", res, err)
+	}
+	signed.Bundle.AgentPassport.AgentID = "agent:tampered"
+	if res, err := c.Verify(ctx, signed, false); err != nil || res.Verified || len(res.Errors) == 0 {
+		t.Fatalf("tampered Verify = %+v, %v", res, err)
+	}
+	if h, err := c.Health(ctx); err != nil || h["ok"] != true {
+		t.Fatalf("Health = %v, %v", h, err)
+	}
+}
+
+func TestRevocations(t *testing.T) {
+	listSigner, listKey := dcptest.NewSigner(t)
+	alice, aliceKey := dcptest.NewSigner(t)
+	srv, err := revocationserver.New(revocationserver.Config{
+		Authority: revocationserver.KeyMap{"human:alice": aliceKey},
+		Signer:    listSigner,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	c := &apiclient.Revocations{URL: ts.URL, PublicKeyB64: listKey}
+	ctx := context.Background()
+
+	rec := dcp.NewRevocationRecord("agent:1", "human:alice", "key compromised")
+	if err := rec.Sign(alice); err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.Revoke(ctx, rec)
+	if err != nil || !res.Created || res.Sequence != 1 {
+		t.Fatalf("Revoke = %+v, %v", res, err)
+	}
+	if res, err := c.Revoke(ctx, rec); err != nil || res.Created {
+		t.Fatalf("repeated Revoke = %+v, %v", res, err)
+	}
+	if got, err := c.Check(ctx, "agent:1"); err != nil || got == nil || got.AgentID != "agent:1" {
+		t.Fatalf("Check = %+v, %v", got, err)
+	}
+	if got, err := c.Check(ctx, "agent:2"); err != nil || got != nil {
+		t.Fatalf("Check of a live agent = %+v, %v", got, err)
+	}
+	if l, err := c.List(ctx); err != nil || len(l.Revocations) != 1 {
+		t.Fatalf("List = %+v, %v", l, err)
+	}
+
+	var apiErr *apiclient.Error
+	mallory, _ := dcptest.NewSigner(t)
+	forged := dcp.NewRevocationRecord("agent:3", "human:alice", "key compromised")
+	if err := forged.Sign(mallory); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Revoke(ctx, forged); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("forged Revoke err = %v", err)
+	}
+
+	_, otherKey := dcptest.NewSigner(t)
+	wrongKey := &apiclient.Revocations{URL: ts.URL, PublicKeyB64: otherKey}
+	if _, err := wrongKey.List(ctx); err == nil {
+		t.Fatal("List verified under the wrong key")
+	}
+}
+
+func TestPDP(t *testing.T) {
+	signer, pub := dcptest.NewSigner(t)
+	policy := &pdp.PolicySet{Rules: []pdp.Rule{{Name: "email", Channels: []dcp.Channel{dcp.ChannelEmail}, Decision: dcp.DecisionApprove}}}
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	c := &apiclient.PDP{URL: ts.URL, PublicKeyB64: pub}
+	ctx := context.Background()
+
+	var intent dcp.Intent
+	readExample(t, "intent.json", &intent)
+	d, err := c.Decide(ctx, &intent)
+	if err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("Decide = %+v, %v", d, err)
+	}
+	got, hash, err := c.Policy(ctx)
+	if err != nil || len(got.Rules) != 1 || hash != d.PolicyHash {
+		t.Fatalf("Policy = %+v, %s, %v", got, hash, err)
+	}
+
+	_, otherKey := dcptest.NewSigner(t)
+	wrongKey := &apiclient.PDP{URL: ts.URL, PublicKeyB64: otherKey}
+	if _, err := wrongKey.Decide(ctx, &intent); err == nil {
+		t.Fatal("decision verified under the wrong key")
+	}
+	var apiErr *apiclient.Error
+	if _, err := c.Decide(ctx, &dcp.Intent{}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message == "" {
+		t.Fatalf("invalid Decide err = %v", err)
+	}
+}
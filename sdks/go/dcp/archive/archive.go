@@ -0,0 +1,161 @@
+// Package archive stores signed bundles in S3-compatible object storage
+// under content-addressed keys and verifies them again on retrieval.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// ErrObjectNotFound is returned by ObjectStore.GetObject for a missing key.
+var ErrObjectNotFound = errors.New("archive: object not found")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ObjectStore is the subset of an object storage API the archiver needs.
+// S3Store implements it for S3 and compatible services; MemoryStore for tests.
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, body []byte, metadata map[string]string) error
+	GetObject(ctx context.Context, key string) ([]byte, error)
+	DeleteObject(ctx context.Context, key string) error
+	ListObjects(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+}
+
+// Hooks let callers observe and steer the archive lifecycle. All are optional.
+type Hooks struct {
+	// AfterPut runs after a bundle is stored.
+	AfterPut func(ctx context.Context, key string, sb *dcp.SignedBundle)
+	// Expired reports whether an archived object is past retention;
+	// ApplyRetention deletes those for which it returns true.
+	Expired func(info ObjectInfo, now time.Time) bool
+	// BeforeDelete may veto a retention delete, e.g. for a legal hold.
+	BeforeDelete func(ctx context.Context, info ObjectInfo) bool
+}
+
+// Archiver writes signed bundles to an ObjectStore. Keys are
+// Prefix + "bundles/" + the hex SHA-256 of the canonical bundle, which is
+// the bundle_hash of the signature, so identical bundles deduplicate and a
+// key alone is enough to check what is retrieved.
+type Archiver struct {
+	Store  ObjectStore
+	Prefix string
+	Hooks  Hooks
+	// Now is the clock used for archived-at metadata and retention; nil
+	// means time.Now.
+	Now func() time.Time
+}
+
+func (a *Archiver) now() time.Time {
+	if a.Now != nil {
+		return a.Now()
+	}
+	return time.Now()
+}
+
+// Key returns the object key for a bundle hash (with or without the
+// "sha256:" prefix).
+func (a *Archiver) Key(bundleHash string) string {
+	return a.Prefix + "bundles/" + strings.TrimPrefix(bundleHash, "sha256:") + ".json"
+}
+
+// Put stores sb and returns its key. The bundle must hash to the
+// bundle_hash its signature declares.
+func (a *Archiver) Put(ctx context.Context, sb *dcp.SignedBundle) (string, error) {
+	h, err := dcp.HashObject(sb.Bundle)
+	if err != nil {
+		return "", fmt.Errorf("archive: %w", err)
+	}
+	if declared := strings.TrimPrefix(sb.Signature.BundleHash, "sha256:"); declared != "" && declared != h {
+		return "", fmt.Errorf("archive: bundle does not match its bundle_hash")
+	}
+	body, err := json.Marshal(sb)
+	if err != nil {
+		return "", fmt.Errorf("archive: %w", err)
+	}
+	key := a.Key(h)
+	meta := map[string]string{
+		"dcp-agent-id":    sb.Bundle.AgentPassport.AgentID,
+		"dcp-archived-at": a.now().UTC().Format(time.RFC3339),
+	}
+	if err := a.Store.PutObject(ctx, key, body, meta); err != nil {
+		return "", fmt.Errorf("archive: put %s: %w", key, err)
+	}
+	if a.Hooks.AfterPut != nil {
+		a.Hooks.AfterPut(ctx, key, sb)
+	}
+	return key, nil
+}
+
+// Get downloads the bundle with the given hash, checks that its content
+// matches the key and verifies it with dcp.VerifyRawSignedBundle over the
+// downloaded bytes. publicKeyB64 may be empty to use the signer's key.
+func (a *Archiver) Get(ctx context.Context, bundleHash, publicKeyB64 string) (*dcp.RawSignedBundle, error) {
+	key := a.Key(bundleHash)
+	body, err := a.Store.GetObject(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("archive: get %s: %w", key, err)
+	}
+	rsb, err := dcp.ParseSignedBundle(body)
+	if err != nil {
+		return nil, fmt.Errorf("archive: %s: %w", key, err)
+	}
+	canon, err := dcp.CanonicalizeJSON(rsb.RawBundle)
+	if err != nil {
+		return nil, fmt.Errorf("archive: %s: %w", key, err)
+	}
+	if got := sha256Hex([]byte(canon)); got != strings.TrimPrefix(bundleHash, "sha256:") {
+		return nil, fmt.Errorf("archive: %s: content hash %s does not match key", key, got)
+	}
+	if r := dcp.VerifyRawSignedBundle(rsb, publicKeyB64); !r.Verified {
+		return nil, fmt.Errorf("archive: %s failed verification: %s", key, strings.Join(r.Errors, "; "))
+	}
+	return rsb, nil
+}
+
+// ApplyRetention deletes archived bundles that Hooks.Expired reports as
+// expired and Hooks.BeforeDelete does not veto, returning the deleted keys.
+func (a *Archiver) ApplyRetention(ctx context.Context) ([]string, error) {
+	if a.Hooks.Expired == nil {
+		return nil, nil
+	}
+	now := a.now()
+	var expired []ObjectInfo
+	err := a.Store.ListObjects(ctx, a.Prefix+"bundles/", func(info ObjectInfo) error {
+		if a.Hooks.Expired(info, now) {
+			expired = append(expired, info)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("archive: list: %w", err)
+	}
+	var deleted []string
+	for _, info := range expired {
+		if a.Hooks.BeforeDelete != nil && !a.Hooks.BeforeDelete(ctx, info) {
+			continue
+		}
+		if err := a.Store.DeleteObject(ctx, info.Key); err != nil {
+			return deleted, fmt.Errorf("archive: delete %s: %w", info.Key, err)
+		}
+		deleted = append(deleted, info.Key)
+	}
+	return deleted, nil
+}
+
+// OlderThan returns an Expired hook for a fixed retention period.
+func OlderThan(d time.Duration) func(ObjectInfo, time.Time) bool {
+	return func(info ObjectInfo, now time.Time) bool {
+		return now.Sub(info.LastModified) > d
+	}
+}
@@ -0,0 +1,171 @@
+package archive_test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/archive"
+)
+
+func fixtureBundle(t *testing.T) *dcp.SignedBundle {
+	t.Helper()
+	_, thisFile, _, _ := runtime.Caller(0)
+	path := filepath.Join(filepath.Dir(thisFile), "..", "..", "..", "..", "tests", "conformance", "examples", "citizenship_bundle.signed.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb dcp.SignedBundle
+	if err := json.Unmarshal(data, &sb); err != nil {
+		t.Fatal(err)
+	}
+	return &sb
+}
+
+func testArchiver(t *testing.T, store archive.ObjectStore) {
+	ctx := context.Background()
+	sb := fixtureBundle(t)
+	var hooked []string
+	a := &archive.Archiver{Store: store, Prefix: "dcp/", Hooks: archive.Hooks{
+		AfterPut: func(_ context.Context, key string, _ *dcp.SignedBundle) { hooked = append(hooked, key) },
+	}}
+
+	key, err := a.Put(ctx, sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "dcp/bundles/" + strings.TrimPrefix(sb.Signature.BundleHash, "sha256:") + ".json"; key != want {
+		t.Fatalf("key = %s, want %s", key, want)
+	}
+	if len(hooked) != 1 || hooked[0] != key {
+		t.Fatalf("AfterPut not called: %v", hooked)
+	}
+	rsb, err := a.Get(ctx, sb.Signature.BundleHash, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsb.Bundle.AgentPassport.AgentID != sb.Bundle.AgentPassport.AgentID {
+		t.Fatal("retrieved bundle differs")
+	}
+
+	// Overwrite the object with a different, still self-consistent document.
+	tampered := *sb
+	tampered.Bundle.Intent.ActionType = "initiate_payment"
+	body, _ := json.Marshal(&tampered)
+	store.PutObject(ctx, key, body, nil)
+	if _, err := a.Get(ctx, sb.Signature.BundleHash, ""); err == nil {
+		t.Fatal("tampered object must fail on download")
+	}
+	if _, err := a.Get(ctx, strings.Repeat("0", 64), ""); !errors.Is(err, archive.ErrObjectNotFound) {
+		t.Fatalf("missing bundle: got %v", err)
+	}
+}
+
+func TestArchiverMemory(t *testing.T) {
+	testArchiver(t, archive.NewMemoryStore())
+}
+
+func TestArchiverRetention(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := archive.NewMemoryStore()
+	store.Now = func() time.Time { return now.Add(-100 * 24 * time.Hour) }
+	a := &archive.Archiver{Store: store, Now: func() time.Time { return now }}
+	key, err := a.Put(ctx, fixtureBundle(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.Hooks.Expired = archive.OlderThan(90 * 24 * time.Hour)
+	a.Hooks.BeforeDelete = func(context.Context, archive.ObjectInfo) bool { return false }
+	if deleted, _ := a.ApplyRetention(ctx); len(deleted) != 0 {
+		t.Fatal("BeforeDelete must be able to veto")
+	}
+	a.Hooks.BeforeDelete = nil
+	deleted, err := a.ApplyRetention(ctx)
+	if err != nil || len(deleted) != 1 || deleted[0] != key {
+		t.Fatalf("ApplyRetention = %v, %v", deleted, err)
+	}
+}
+
+// fakeS3 is a minimal path-style S3 endpoint for one bucket.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test/") {
+		http.Error(w, "unsigned", http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+	case r.Method == http.MethodGet && r.URL.Path == "/bucket":
+		type content struct {
+			Key          string
+			Size         int
+			LastModified string
+		}
+		var res struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []content
+		}
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			res.Contents = append(res.Contents, content{Key: k, Size: len(f.objects[k]), LastModified: "2020-01-01T00:00:00.000Z"})
+		}
+		xml.NewEncoder(w).Encode(res)
+	case r.Method == http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(body)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestArchiverS3(t *testing.T) {
+	srv := httptest.NewServer(&fakeS3{objects: map[string][]byte{}})
+	defer srv.Close()
+	store := &archive.S3Store{
+		Endpoint: srv.URL, Region: "us-east-1", Bucket: "bucket",
+		AccessKeyID: "test", SecretAccessKey: "secret",
+	}
+	testArchiver(t, store)
+
+	a := &archive.Archiver{Store: store, Prefix: "dcp/", Hooks: archive.Hooks{Expired: archive.OlderThan(time.Hour)}}
+	deleted, err := a.ApplyRetention(context.Background())
+	if err != nil || len(deleted) != 1 {
+		t.Fatalf("ApplyRetention over S3 = %v, %v", deleted, err)
+	}
+}
@@ -0,0 +1,213 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPowerOfAttorneyInvalid is returned for powers of attorney that do not
+// link a principal to the human acting on its behalf, or do not grant that
+// human the right exercised.
+var ErrPowerOfAttorneyInvalid = errors.New("power of attorney invalid")
+
+// NewPowerOfAttorney returns grantor's unsigned grant to attorney of the
+// rights in scope, from now for ttl.
+func (f *RecordFactory) NewPowerOfAttorney(grantor, attorney PrincipalMember, scope []AttorneyScope, ttl time.Duration) PowerOfAttorney {
+	now := f.now()
+	return PowerOfAttorney{
+		DCPVersion:        DCPVersion,
+		GrantorID:         grantor.HumanID,
+		GrantorPublicKey:  grantor.PublicKey,
+		AttorneyID:        attorney.HumanID,
+		AttorneyPublicKey: attorney.PublicKey,
+		Scope:             append([]AttorneyScope{}, scope...),
+		NotBefore:         FormatTime(now),
+		NotAfter:          FormatTime(now.Add(ttl)),
+	}
+}
+
+// NewPowerOfAttorney calls RecordFactory.NewPowerOfAttorney with the
+// package clock.
+func NewPowerOfAttorney(grantor, attorney PrincipalMember, scope []AttorneyScope, ttl time.Duration) PowerOfAttorney {
+	return defaultRecords.NewPowerOfAttorney(grantor, attorney, scope, ttl)
+}
+
+// Sign sets Signature to s's signature over the canonical record with an
+// empty signature. A power of attorney is signed by the grantor's key.
+func (a *PowerOfAttorney) Sign(s BundleSigner) error {
+	a.Signature = ""
+	sig, err := signWith(s, a)
+	if err != nil {
+		return fmt.Errorf("sign power of attorney for %s: %w", a.AttorneyID, err)
+	}
+	a.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against GrantorPublicKey.
+func (a *PowerOfAttorney) VerifySignature() (bool, error) {
+	if a.Signature == "" {
+		return false, fmt.Errorf("power of attorney for %s has no signature", a.AttorneyID)
+	}
+	unsigned := *a
+	unsigned.Signature = ""
+	return VerifyObject(unsigned, a.Signature, a.GrantorPublicKey)
+}
+
+// Grants reports whether a's scope includes s.
+func (a *PowerOfAttorney) Grants(s AttorneyScope) bool {
+	for _, g := range a.Scope {
+		if g == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Attorney returns a's attorney.
+func (a *PowerOfAttorney) Attorney() PrincipalMember {
+	return PrincipalMember{HumanID: a.AttorneyID, PublicKey: a.AttorneyPublicKey}
+}
+
+// CheckPowerOfAttorney reports whether chain, the principal's grant first,
+// lets its last attorney exercise scope on behalf of principal humanID at
+// t, and returns that attorney. The first grant is humanID's, made with
+// principalKeyB64, which must be given: a chain cannot vouch for its own
+// first key. Each later one is made by the attorney before it, under a grant of AttorneyDelegate, and grants
+// nothing that one lacks; and each is signed by its grantor, holds at t
+// and grants scope. Errors wrap ErrPowerOfAttorneyInvalid.
+func CheckPowerOfAttorney(chain []PowerOfAttorney, humanID, principalKeyB64 string, scope AttorneyScope, t time.Time) (PrincipalMember, error) {
+	if len(chain) == 0 {
+		return PrincipalMember{}, fmt.Errorf("%w: empty chain", ErrPowerOfAttorneyInvalid)
+	}
+	for i := range chain {
+		a := &chain[i]
+		if i == 0 {
+			if a.GrantorID != humanID {
+				return PrincipalMember{}, fmt.Errorf("%w: grant 0 is by %s, not the principal %s", ErrPowerOfAttorneyInvalid, a.GrantorID, humanID)
+			}
+			if principalKeyB64 == "" {
+				return PrincipalMember{}, fmt.Errorf("%w: no principal key is pinned for grant 0", ErrPowerOfAttorneyInvalid)
+			}
+			if a.GrantorPublicKey != principalKeyB64 {
+				return PrincipalMember{}, fmt.Errorf("%w: grant 0 is not made with the principal's key", ErrPowerOfAttorneyInvalid)
+			}
+		} else {
+			prev := &chain[i-1]
+			if a.GrantorID != prev.AttorneyID || a.GrantorPublicKey != prev.AttorneyPublicKey {
+				return PrincipalMember{}, fmt.Errorf("%w: grant %d is by %s, not the attorney %s of grant %d", ErrPowerOfAttorneyInvalid, i, a.GrantorID, prev.AttorneyID, i-1)
+			}
+			if !prev.Grants(AttorneyDelegate) {
+				return PrincipalMember{}, fmt.Errorf("%w: grant %d does not let %s delegate", ErrPowerOfAttorneyInvalid, i-1, prev.AttorneyID)
+			}
+			for _, s := range a.Scope {
+				if !prev.Grants(s) {
+					return PrincipalMember{}, fmt.Errorf("%w: grant %d grants %s, which %s does not hold", ErrPowerOfAttorneyInvalid, i, s, a.GrantorID)
+				}
+			}
+		}
+		if err := a.check(scope, t); err != nil {
+			return PrincipalMember{}, fmt.Errorf("%w: grant %d: %v", ErrPowerOfAttorneyInvalid, i, err)
+		}
+	}
+	return chain[len(chain)-1].Attorney(), nil
+}
+
+// check checks a on its own: its signature, that it grants scope and that
+// it holds at t.
+func (a *PowerOfAttorney) check(scope AttorneyScope, t time.Time) error {
+	if ok, err := a.VerifySignature(); err != nil || !ok {
+		return fmt.Errorf("signature does not verify with %s's key", a.GrantorID)
+	}
+	if !a.Grants(scope) {
+		return fmt.Errorf("%s is not granted %s", a.AttorneyID, scope)
+	}
+	from, err := ParseTime(a.NotBefore)
+	if err != nil {
+		return fmt.Errorf("not_before: %v", err)
+	}
+	until, err := ParseTime(a.NotAfter)
+	if err != nil {
+		return fmt.Errorf("not_after: %v", err)
+	}
+	if t.Before(from) || !t.Before(until) {
+		return fmt.Errorf("exercised at %s, outside %s to %s", FormatTime(t), a.NotBefore, a.NotAfter)
+	}
+	return nil
+}
+
+// checkBundleAttorney returns the attorney who may sign a bundle of
+// principal humanID under chain: one granted AttorneyBind when intent was
+// declared, whose key is the one embedded in sig, if any. The intent's
+// timestamp is signed with the bundle; the signature's created_at is not.
+func checkBundleAttorney(chain []PowerOfAttorney, humanID, principalKeyB64 string, intent *Intent, sig *BundleSignature) (PrincipalMember, error) {
+	declared, err := intent.TimestampTime()
+	if err != nil {
+		return PrincipalMember{}, fmt.Errorf("%w: intent %v", ErrPowerOfAttorneyInvalid, err)
+	}
+	attorney, err := CheckPowerOfAttorney(chain, humanID, principalKeyB64, AttorneyBind, declared)
+	if err != nil {
+		return attorney, err
+	}
+	if k := sig.SignerInfo.PublicKeyB64; k != "" && k != attorney.PublicKey {
+		return attorney, fmt.Errorf("%w: the bundle is signed by %s, not the attorney %s", ErrPowerOfAttorneyInvalid, k, attorney.HumanID)
+	}
+	return attorney, nil
+}
+
+// Validate checks a against the power of attorney schema and returns
+// ValidationErrors listing every violation, or nil.
+func (a *PowerOfAttorney) Validate() error {
+	v := newValidator()
+	a.validate(v)
+	return v.err()
+}
+
+func (a *PowerOfAttorney) validate(v validator) {
+	v.version("dcp_version", a.DCPVersion)
+	v.idOf("grantor_id", a.GrantorID, IDKindHuman)
+	v.publicKey("grantor_public_key", a.GrantorPublicKey)
+	v.idOf("attorney_id", a.AttorneyID, IDKindHuman)
+	v.publicKey("attorney_public_key", a.AttorneyPublicKey)
+	if a.AttorneyID == a.GrantorID {
+		v.at("attorney_id").fail("must differ from grantor_id")
+	}
+	if len(a.Scope) == 0 {
+		v.at("scope").fail("must grant at least one right")
+	}
+	for i, s := range a.Scope {
+		v.at("scope").index(i).enum("", string(s), attorneyScopes)
+	}
+	v.timestamp("not_before", a.NotBefore)
+	v.timestamp("not_after", a.NotAfter)
+	v.signature("signature", a.Signature)
+}
+
+// Clone returns a deep copy of a.
+func (a *PowerOfAttorney) Clone() *PowerOfAttorney {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	if a.Scope != nil {
+		c.Scope = append([]AttorneyScope{}, a.Scope...)
+	}
+	return &c
+}
+
+// Equal reports whether a and o canonicalize identically.
+func (a *PowerOfAttorney) Equal(o *PowerOfAttorney) bool {
+	return (a == nil) == (o == nil) && (a == nil || canonicalEqual(a, o))
+}
+
+func clonePowersOfAttorney(chain []PowerOfAttorney) []PowerOfAttorney {
+	if chain == nil {
+		return nil
+	}
+	c := make([]PowerOfAttorney, len(chain))
+	for i := range chain {
+		c[i] = *chain[i].Clone()
+	}
+	return c
+}
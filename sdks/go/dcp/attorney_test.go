@@ -0,0 +1,190 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// attorneyFixture returns a human with id, as a signer set member, and its
+// signer.
+func attorneyFixture(t *testing.T, id string) (dcp.PrincipalMember, dcp.BundleSigner) {
+	t.Helper()
+	kp, _ := dcp.GenerateKeypair()
+	s, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dcp.PrincipalMember{HumanID: id, PublicKey: kp.PublicKeyB64}, s
+}
+
+// grant returns grantor's signed power of attorney, from 00:30Z for a day.
+func grant(t *testing.T, grantor dcp.PrincipalMember, s dcp.BundleSigner, attorney dcp.PrincipalMember, scope ...dcp.AttorneyScope) dcp.PowerOfAttorney {
+	t.Helper()
+	f := dcp.RecordFactory{Clock: func() time.Time { return time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC) }}
+	a := f.NewPowerOfAttorney(grantor, attorney, scope, 24*time.Hour)
+	if err := a.Sign(s); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestPowerOfAttorneyChain(t *testing.T) {
+	principal, principalSigner := attorneyFixture(t, "human001")
+	attorney, attorneySigner := attorneyFixture(t, "human002")
+	sub, _ := attorneyFixture(t, "human003")
+	chain := []dcp.PowerOfAttorney{
+		grant(t, principal, principalSigner, attorney, dcp.AttorneyBind, dcp.AttorneyRevoke, dcp.AttorneyDelegate),
+		grant(t, attorney, attorneySigner, sub, dcp.AttorneyBind),
+	}
+	if err := chain[1].Validate(); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC)
+	got, err := dcp.CheckPowerOfAttorney(chain, "human001", principal.PublicKey, dcp.AttorneyBind, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != sub {
+		t.Fatalf("attorney = %+v", got)
+	}
+
+	undelegable := grant(t, principal, principalSigner, attorney, dcp.AttorneyBind)
+	bindOnly := grant(t, principal, principalSigner, attorney, dcp.AttorneyBind, dcp.AttorneyDelegate)
+	widened := grant(t, attorney, attorneySigner, sub, dcp.AttorneyBind, dcp.AttorneyRevoke)
+	forged := *chain[1].Clone()
+	forged.Scope = []dcp.AttorneyScope{dcp.AttorneyBind, dcp.AttorneyDelegate}
+	other, otherSigner := attorneyFixture(t, "human004")
+	for _, tc := range []struct {
+		name  string
+		chain []dcp.PowerOfAttorney
+		key   string
+		scope dcp.AttorneyScope
+		at    time.Time
+		want  string
+	}{
+		{"out of scope", chain, principal.PublicKey, dcp.AttorneyRevoke, at, "not granted revoke_agents"},
+		{"no delegation right", []dcp.PowerOfAttorney{undelegable, chain[1]}, principal.PublicKey, dcp.AttorneyBind, at, "delegate"},
+		{"widened", []dcp.PowerOfAttorney{bindOnly, widened}, principal.PublicKey, dcp.AttorneyBind, at, "revoke_agents, which human002 does not hold"},
+		{"expired", chain, principal.PublicKey, dcp.AttorneyBind, at.Add(48 * time.Hour), "outside"},
+		{"other principal's key", chain, other.PublicKey, dcp.AttorneyBind, at, "principal's key"},
+		{"other grantor", []dcp.PowerOfAttorney{grant(t, other, otherSigner, attorney, dcp.AttorneyBind)}, principal.PublicKey, dcp.AttorneyBind, at, "not the principal"},
+		{"broken link", []dcp.PowerOfAttorney{chain[1]}, principal.PublicKey, dcp.AttorneyBind, at, "not the principal"},
+		{"altered", []dcp.PowerOfAttorney{chain[0], forged}, principal.PublicKey, dcp.AttorneyBind, at, "signature"},
+		{"no principal key", chain, "", dcp.AttorneyBind, at, "no principal key is pinned"},
+		{"empty", nil, principal.PublicKey, dcp.AttorneyBind, at, "empty chain"},
+	} {
+		if _, err := dcp.CheckPowerOfAttorney(tc.chain, "human001", tc.key, tc.scope, tc.at); !errors.Is(err, dcp.ErrPowerOfAttorneyInvalid) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+	chain[1].Scope = []dcp.AttorneyScope{"sign_anything"}
+	chain[1].AttorneyID = "human002"
+	var verrs dcp.ValidationErrors
+	if err := chain[1].Validate(); !errors.As(err, &verrs) || len(verrs) != 2 || verrs[1].Pointer != "/scope/0" {
+		t.Fatalf("invalid power of attorney: %v", err)
+	}
+}
+
+func TestVerifyPowerOfAttorney(t *testing.T) {
+	b, human, _ := builderFixture(t)
+	principal, _ := dcp.NewKeySigner(human.SecretKeyB64)
+	attorney, attorneySigner := attorneyFixture(t, "human002")
+	bundle, err := b.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(s dcp.BundleSigner, chain ...dcp.PowerOfAttorney) *dcp.SignedBundle {
+		t.Helper()
+		c := bundle.Clone()
+		c.PowersOfAttorney = chain
+		sb, err := dcp.SignBundle(c, s, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+	poa := grant(t, dcp.PrincipalMember{HumanID: "human001", PublicKey: human.PublicKeyB64}, principal, attorney, dcp.AttorneyBind)
+
+	sb := sign(attorneySigner, poa)
+	if sb.Signature.SignerInfo.ID != "human002" {
+		t.Fatalf("signer = %+v", sb.Signature.SignerInfo)
+	}
+	res := dcp.VerifySignedBundleWithOptions(sb, dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, Explain: true})
+	if !res.Verified {
+		t.Fatalf("signed by the attorney: %v", res.Errors)
+	}
+	if step := res.Trace[1]; step.Check != dcp.TraceAttorney || !step.OK || step.Actual != "human002" || step.Detail != "for human001, 1 deep" {
+		t.Fatalf("trace: %+v", step)
+	}
+	data, _ := json.Marshal(sb)
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundle(rsb, human.PublicKeyB64); !res.Verified {
+		t.Fatalf("raw bundle: %v", res.Errors)
+	}
+
+	if res := dcp.VerifySignedBundle(sign(attorneySigner), human.PublicKeyB64); res.Verified {
+		t.Fatal("attorney signature verified without a power of attorney")
+	}
+	_, strangerSigner := attorneyFixture(t, "human003")
+	if res := dcp.VerifySignedBundle(sign(strangerSigner, poa), human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "not the attorney") {
+		t.Fatalf("signed by another: %+v", res)
+	}
+	revokeOnly := grant(t, dcp.PrincipalMember{HumanID: "human001", PublicKey: human.PublicKeyB64}, principal, attorney, dcp.AttorneyRevoke)
+	if res := dcp.VerifySignedBundle(sign(attorneySigner, revokeOnly), human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "not granted bind_agents") {
+		t.Fatalf("revocation-only grant: %+v", res)
+	}
+	if res := dcp.VerifySignedBundle(sb, ""); res.Verified || !strings.Contains(res.Errors[0], "no principal key is pinned") {
+		t.Fatalf("no pinned principal key: %+v", res)
+	}
+
+	// The grant has lapsed by the time the intent is declared, at 01:00Z.
+	// The signature's created_at is not signed, so moving it back into the
+	// grant does not help.
+	f := dcp.RecordFactory{Clock: func() time.Time { return time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC) }}
+	lapsed := f.NewPowerOfAttorney(dcp.PrincipalMember{HumanID: "human001", PublicKey: human.PublicKeyB64}, attorney, []dcp.AttorneyScope{dcp.AttorneyBind}, 15*time.Minute)
+	if err := lapsed.Sign(principal); err != nil {
+		t.Fatal(err)
+	}
+	tampered := sign(attorneySigner, lapsed)
+	tampered.Signature.CreatedAt = "2026-01-01T00:40:00Z"
+	if res := dcp.VerifySignedBundle(tampered, human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "exercised at 2026-01-01T01:00:00Z") {
+		t.Fatalf("created_at moved into a lapsed grant: %+v", res)
+	}
+}
+
+func TestRevocationByAttorney(t *testing.T) {
+	principal, principalSigner := attorneyFixture(t, "human001")
+	attorney, attorneySigner := attorneyFixture(t, "human002")
+	f := dcp.RecordFactory{Clock: func() time.Time { return time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC) }}
+	revoke := func(scope dcp.AttorneyScope) dcp.RevocationRecord {
+		t.Helper()
+		r := f.NewRevocationRecord("agent001", "human001", "lost device")
+		r.PowersOfAttorney = []dcp.PowerOfAttorney{grant(t, principal, principalSigner, attorney, scope)}
+		if err := r.Sign(attorneySigner); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	r := revoke(dcp.AttorneyRevoke)
+	if ok, err := r.VerifyPrincipalSignature(principal.PublicKey); !ok || err != nil {
+		t.Fatalf("VerifyPrincipalSignature = %v, %v", ok, err)
+	}
+	if c := r.Clone(); !c.Equal(&r) || &c.PowersOfAttorney[0] == &r.PowersOfAttorney[0] {
+		t.Fatal("Clone does not copy the powers of attorney")
+	}
+	r = revoke(dcp.AttorneyBind)
+	if _, err := r.VerifyPrincipalSignature(principal.PublicKey); !errors.Is(err, dcp.ErrPowerOfAttorneyInvalid) {
+		t.Fatalf("bind-only grant: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package dcp
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditEntryFields are the parts of an audit entry the caller supplies to
+// AuditChain.Append; the chain fills in the rest.
+type AuditEntryFields struct {
+	// AuditID identifies the entry; empty means a generated dcp:audit: ID.
+	AuditID string
+	// Intent is the intent the entry records; intent_id and intent_hash
+	// are taken from it, and agent_id and human_id when left empty.
+	Intent         Intent
+	AgentID        string
+	HumanID        string
+	PolicyDecision Outcome
+	Outcome        string
+	Evidence       AuditEvidence
+}
+
+// AuditChainOptions configures an AuditChain.
+type AuditChainOptions struct {
+	// Anchor continues an existing chain, e.g. from ChainAnchorFor or
+	// LedgerChainAnchor; nil starts at GENESIS.
+	Anchor *ChainAnchor
+	// Clock stamps entries and generated audit IDs and Rand supplies the
+	// IDs' random bits; nil means the package clock and entropy source.
+	Clock func() time.Time
+	Rand  io.Reader
+	// AgentSigner, if set, signs each entry's agent_signature.
+	AgentSigner BundleSigner
+}
+
+// AuditChain builds a hash-linked audit trail: each Append sets prev_hash to
+// the previous entry's hash (GENESIS for the first), computes intent_hash and
+// timestamps the entry. An AuditChain is safe for concurrent use.
+type AuditChain struct {
+	mu      sync.Mutex
+	opts    AuditChainOptions
+	records RecordFactory
+	head    string
+	entries []AuditEntry
+}
+
+// NewAuditChain returns an empty chain.
+func NewAuditChain(opts AuditChainOptions) *AuditChain {
+	return &AuditChain{opts: opts, records: RecordFactory{Clock: opts.Clock, Rand: opts.Rand}, head: chainStart(opts.Anchor)}
+}
+
+// Append adds an entry built from f and returns its hash, which the next
+// entry will carry as prev_hash. Once f.Intent has expired, only outcomes
+// in CancellationOutcomes may be appended; others fail with
+// ErrIntentExpired.
+func (c *AuditChain) Append(f AuditEntryFields) (string, error) {
+	intentHash, err := HashObject(f.Intent)
+	if err != nil {
+		return "", fmt.Errorf("audit chain: intent hash: %w", err)
+	}
+	entry := AuditEntry{
+		DCPVersion:     DCPVersion,
+		AuditID:        f.AuditID,
+		AgentID:        f.AgentID,
+		HumanID:        f.HumanID,
+		IntentID:       f.Intent.IntentID,
+		IntentHash:     intentHash,
+		PolicyDecision: f.PolicyDecision,
+		Outcome:        f.Outcome,
+		Evidence:       f.Evidence,
+	}
+	if entry.AgentID == "" {
+		entry.AgentID = f.Intent.AgentID
+	}
+	if entry.HumanID == "" {
+		entry.HumanID = f.Intent.HumanID
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timestamp := c.records.timestamp()
+	if err := f.Intent.checkEntry(timestamp, entry.Outcome); err != nil {
+		return "", fmt.Errorf("audit chain: %w", err)
+	}
+	return c.append(entry, timestamp)
+}
+
+// append links entry to the chain, stamped at timestamp; c.mu is held.
+func (c *AuditChain) append(entry AuditEntry, timestamp string) (string, error) {
+	if entry.AuditID == "" {
+		entry.AuditID = c.records.NewAuditID()
+	}
+	entry.PrevHash = c.head
+	entry.Timestamp = timestamp
+	if c.opts.AgentSigner != nil {
+		sig, err := signWith(c.opts.AgentSigner, entry)
+		if err != nil {
+			return "", fmt.Errorf("audit chain: sign %s: %w", entry.AuditID, err)
+		}
+		entry.AgentSignature = sig
+	}
+	h, err := HashObject(entry)
+	if err != nil {
+		return "", fmt.Errorf("audit chain: hash %s: %w", entry.AuditID, err)
+	}
+	c.entries = append(c.entries, entry)
+	c.head = h
+	return h, nil
+}
+
+// Entries returns a copy of the entries appended so far.
+func (c *AuditChain) Entries() []AuditEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]AuditEntry(nil), c.entries...)
+}
+
+// Len returns the number of entries appended.
+func (c *AuditChain) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Head returns the hash of the last entry, or the chain's starting
+// prev_hash when it is empty.
+func (c *AuditChain) Head() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head
+}
+
+// Anchor returns the anchor for a chain or bundle continuing after the
+// current last entry.
+func (c *AuditChain) Anchor() *ChainAnchor {
+	return &ChainAnchor{PrevEntryHash: c.Head()}
+}
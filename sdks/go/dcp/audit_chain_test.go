@@ -0,0 +1,97 @@
+package dcp_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func chainIntent() dcp.Intent {
+	return dcp.Intent{DCPVersion: "1.0", IntentID: "intent001", AgentID: "agent001", HumanID: "human001",
+		Timestamp: "2026-01-01T01:00:00Z", ActionType: "api_call", Target: dcp.IntentTarget{Channel: "api"},
+		DataClasses: []string{"none"}, EstimatedImpact: "low"}
+}
+
+func TestAuditChainAppend(t *testing.T) {
+	agent, _ := dcp.GenerateKeypair()
+	now := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)
+	chain := dcp.NewAuditChain(dcp.AuditChainOptions{
+		Clock:       func() time.Time { now = now.Add(time.Second); return now },
+		AgentSigner: mustSigner(t, agent),
+	})
+	intent := chainIntent()
+	var hashes []string
+	for i := 0; i < 3; i++ {
+		h, err := chain.Append(dcp.AuditEntryFields{AuditID: fmt.Sprintf("audit%03d", i), Intent: intent,
+			PolicyDecision: "approved", Outcome: "ok"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, h)
+	}
+
+	entries := chain.Entries()
+	ih, _ := dcp.HashObject(intent)
+	for i, e := range entries {
+		want := "GENESIS"
+		if i > 0 {
+			want = hashes[i-1]
+		}
+		if e.PrevHash != want || e.IntentHash != ih || e.AgentID != "agent001" || e.HumanID != "human001" {
+			t.Fatalf("entry %d = %+v", i, e)
+		}
+		if got, _ := dcp.HashObject(e); got != hashes[i] {
+			t.Fatalf("entry %d: returned hash does not match", i)
+		}
+	}
+	if entries[2].Timestamp != "2026-01-01T01:00:03Z" {
+		t.Fatalf("timestamp = %s", entries[2].Timestamp)
+	}
+	if chain.Head() != hashes[2] || chain.Anchor().PrevEntryHash != hashes[2] {
+		t.Fatal("head should be the last entry's hash")
+	}
+	if errs := dcp.VerifyAuditEntrySignatures(entries, agent.PublicKeyB64); len(errs) != 0 {
+		t.Fatalf("agent signatures: %v", errs)
+	}
+
+	resumed := dcp.NewAuditChain(dcp.AuditChainOptions{Anchor: chain.Anchor()})
+	if _, err := resumed.Append(dcp.AuditEntryFields{AuditID: "audit003", Intent: intent, Outcome: "ok"}); err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Entries()[0].PrevHash != hashes[2] {
+		t.Fatal("resumed chain should link to the anchor")
+	}
+	if _, err := chain.Append(dcp.AuditEntryFields{Intent: intent}); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := dcp.ParseID(chain.Entries()[3].AuditID); err != nil || id.Kind != dcp.IDKindAudit {
+		t.Fatalf("generated audit_id = %v, %v", id, err)
+	}
+}
+
+func TestAuditChainConcurrentAppend(t *testing.T) {
+	chain := dcp.NewAuditChain(dcp.AuditChainOptions{})
+	intent := chainIntent()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			chain.Append(dcp.AuditEntryFields{AuditID: fmt.Sprintf("audit%03d", i), Intent: intent, Outcome: "ok"})
+		}(i)
+	}
+	wg.Wait()
+	v := dcp.NewStreamVerifier(dcp.AuditStreamOptions{})
+	for _, e := range chain.Entries() {
+		raw, _ := dcp.Canonicalize(e)
+		if err := v.Push([]byte(raw)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v.Count() != 20 {
+		t.Fatalf("chain has %d entries", v.Count())
+	}
+}
@@ -0,0 +1,65 @@
+package dcp
+
+import "fmt"
+
+// unsigned returns a copy of e without its agent signature, which is the
+// form the agent signs.
+func (e AuditEntry) unsigned() AuditEntry {
+	e.AgentSignature = ""
+	return e
+}
+
+// SignAsAgent sets AgentSignature to the agent key's Ed25519 signature over
+// the canonical entry without agent_signature. Sign before computing the next
+// entry's prev_hash, since the signature is part of the chained hash.
+func (e *AuditEntry) SignAsAgent(agentSecretKeyB64 string) error {
+	sig, err := SignObject(e.unsigned(), agentSecretKeyB64)
+	if err != nil {
+		return fmt.Errorf("sign audit entry %s: %w", e.AuditID, err)
+	}
+	e.AgentSignature = sig
+	return nil
+}
+
+// VerifyAgentSignature checks AgentSignature against the agent's public key.
+func (e AuditEntry) VerifyAgentSignature(agentPublicKeyB64 string) (bool, error) {
+	if e.AgentSignature == "" {
+		return false, fmt.Errorf("audit entry %s has no agent_signature", e.AuditID)
+	}
+	return VerifyObject(e.unsigned(), e.AgentSignature, agentPublicKeyB64)
+}
+
+// AppendSignedAuditEntry links entry to the end of entries (prev_hash is
+// GENESIS or the hash of the last entry), signs it with the agent key and
+// returns the extended slice.
+func AppendSignedAuditEntry(entries []AuditEntry, entry AuditEntry, agentSecretKeyB64 string) ([]AuditEntry, error) {
+	entry.PrevHash = "GENESIS"
+	if len(entries) > 0 {
+		h, err := HashObject(entries[len(entries)-1])
+		if err != nil {
+			return nil, fmt.Errorf("hash previous audit entry: %w", err)
+		}
+		entry.PrevHash = h
+	}
+	if err := entry.SignAsAgent(agentSecretKeyB64); err != nil {
+		return nil, err
+	}
+	return append(entries, entry), nil
+}
+
+// VerifyAuditEntrySignatures requires every entry to carry a valid agent
+// signature and returns one message per failing entry. Use it to check
+// entries individually before they are bundled; VerifySignedBundle only
+// checks signatures that are present.
+func VerifyAuditEntrySignatures(entries []AuditEntry, agentPublicKeyB64 string) []string {
+	var errs []string
+	for i, e := range entries {
+		ok, err := e.VerifyAgentSignature(agentPublicKeyB64)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("agent_signature (entry %d): %v", i, err))
+		} else if !ok {
+			errs = append(errs, fmt.Sprintf("agent_signature (entry %d): invalid", i))
+		}
+	}
+	return errs
+}
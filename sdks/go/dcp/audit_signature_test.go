@@ -0,0 +1,89 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// signedEntryBundle builds a bundle whose audit entries are signed with
+// entryKey, and signs the bundle itself with bundleKey.
+func signedEntryBundle(t *testing.T, agent, entryKey, bundleKey *dcp.Keypair) *dcp.SignedBundle {
+	t.Helper()
+	intent := dcp.Intent{DCPVersion: "1.0", IntentID: "intent001", AgentID: "agent001", HumanID: "human001",
+		Timestamp: "2026-01-01T00:00:00Z", ActionType: "send_email", Target: dcp.IntentTarget{Channel: "email"},
+		DataClasses: []string{"none"}, EstimatedImpact: "low"}
+	ih, _ := dcp.HashObject(intent)
+	var entries []dcp.AuditEntry
+	for _, id := range []string{"audit001", "audit002", "audit003"} {
+		var err error
+		entries, err = dcp.AppendSignedAuditEntry(entries, dcp.AuditEntry{
+			DCPVersion: "1.0", AuditID: id, Timestamp: "2026-01-01T00:00:01Z", AgentID: "agent001",
+			HumanID: "human001", IntentID: "intent001", IntentHash: ih, PolicyDecision: "approved", Outcome: "sent",
+		}, entryKey.SecretKeyB64)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	bundle := dcp.CitizenshipBundle{
+		AgentPassport: dcp.AgentPassport{DCPVersion: "1.0", AgentID: "agent001", PublicKey: agent.PublicKeyB64},
+		Intent:        intent,
+		AuditEntries:  entries,
+	}
+	var leaves []string
+	for _, e := range entries {
+		h, _ := dcp.HashObject(e)
+		leaves = append(leaves, h)
+	}
+	root, _ := dcp.MerkleRootFromHexLeaves(leaves)
+	root = "sha256:" + root
+	bh, _ := dcp.HashObject(bundle)
+	sig, err := dcp.SignObject(bundle, bundleKey.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dcp.SignedBundle{Bundle: bundle, Signature: dcp.BundleSignature{
+		Alg: "ed25519", BundleHash: "sha256:" + bh, MerkleRoot: &root, SigB64: sig,
+		SignerInfo: dcp.Signer{Type: "human", ID: "human001", PublicKeyB64: bundleKey.PublicKeyB64},
+	}}
+}
+
+func TestAuditEntrySignatures(t *testing.T) {
+	agent, _ := dcp.GenerateKeypair()
+	owner, _ := dcp.GenerateKeypair()
+	sb := signedEntryBundle(t, agent, agent, owner)
+
+	if errs := dcp.VerifyAuditEntrySignatures(sb.Bundle.AuditEntries, agent.PublicKeyB64); len(errs) != 0 {
+		t.Fatalf("entries should verify individually: %v", errs)
+	}
+	if r := dcp.VerifySignedBundle(sb, ""); !r.Verified {
+		t.Fatalf("bundle should verify: %v", r.Errors)
+	}
+	data, _ := json.Marshal(sb)
+	if r := dcp.VerifySignedBundleJSON(data, ""); !r.Verified {
+		t.Fatalf("bundle JSON should verify: %v", r.Errors)
+	}
+
+	unsigned := sb.Bundle.AuditEntries[0]
+	unsigned.AgentSignature = ""
+	if errs := dcp.VerifyAuditEntrySignatures([]dcp.AuditEntry{unsigned}, agent.PublicKeyB64); len(errs) != 1 {
+		t.Fatal("an unsigned entry must be reported")
+	}
+}
+
+func TestAuditEntrySignedByWrongKey(t *testing.T) {
+	agent, _ := dcp.GenerateKeypair()
+	other, _ := dcp.GenerateKeypair()
+	owner, _ := dcp.GenerateKeypair()
+	sb := signedEntryBundle(t, agent, other, owner)
+
+	r := dcp.VerifySignedBundle(sb, "")
+	if r.Verified || r.Errors[0] != "agent_signature (entry 0): invalid" {
+		t.Fatalf("expected agent_signature failure, got %+v", r)
+	}
+	data, _ := json.Marshal(sb)
+	if r := dcp.VerifySignedBundleJSON(data, ""); r.Verified {
+		t.Fatal("raw verification must also check entry signatures")
+	}
+}
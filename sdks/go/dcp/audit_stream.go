@@ -0,0 +1,155 @@
+package dcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AuditStreamOptions configures a StreamVerifier.
+type AuditStreamOptions struct {
+	// ChainStart is the prev_hash expected on the first entry; "" means GENESIS.
+	ChainStart string
+	// IntentHash returns the expected intent_hash for an intent_id. When nil,
+	// intent hashes are not checked; when it reports false, the entry fails.
+	IntentHash func(intentID string) (string, bool)
+	// AgentPublicKeyB64, if set, is used to check agent_signature on entries
+	// that carry one.
+	AgentPublicKeyB64 string
+}
+
+// StreamVerifier checks audit entries one at a time: the prev_hash chain,
+// intent hashes and agent signatures, while accumulating the Merkle root.
+// Memory use does not grow with the number of entries.
+type StreamVerifier struct {
+	opts     AuditStreamOptions
+	prevHash string
+	count    int
+	merkle   MerkleAccumulator
+}
+
+// NewStreamVerifier returns a verifier positioned before the first entry.
+func NewStreamVerifier(opts AuditStreamOptions) *StreamVerifier {
+	start := opts.ChainStart
+	if start == "" {
+		start = "GENESIS"
+	}
+	return &StreamVerifier{opts: opts, prevHash: start}
+}
+
+// Push verifies the next audit entry, given as received JSON.
+func (v *StreamVerifier) Push(raw []byte) error {
+	i := v.count
+	canon, err := CanonicalizeJSON(raw)
+	if err != nil {
+		return fmt.Errorf("audit entry %d: %v", i, err)
+	}
+	var links struct {
+		PrevHash       string `json:"prev_hash"`
+		IntentID       string `json:"intent_id"`
+		IntentHash     string `json:"intent_hash"`
+		AgentSignature string `json:"agent_signature"`
+	}
+	if err := json.Unmarshal(raw, &links); err != nil {
+		return fmt.Errorf("audit entry %d: %v", i, err)
+	}
+	if links.PrevHash != v.prevHash {
+		return fmt.Errorf("prev_hash chain (entry %d): expected %s, got %s", i, v.prevHash, links.PrevHash)
+	}
+	if v.opts.IntentHash != nil {
+		want, ok := v.opts.IntentHash(links.IntentID)
+		if !ok {
+			return fmt.Errorf("intent_hash (entry %d): unknown intent %s", i, links.IntentID)
+		}
+		if links.IntentHash != want {
+			return fmt.Errorf("intent_hash (entry %d): expected %s, got %s", i, want, links.IntentHash)
+		}
+	}
+	if links.AgentSignature != "" && v.opts.AgentPublicKeyB64 != "" {
+		var generic map[string]interface{}
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return fmt.Errorf("audit entry %d: %v", i, err)
+		}
+		delete(generic, "agent_signature")
+		unsigned, err := Canonicalize(generic)
+		if err != nil {
+			return fmt.Errorf("audit entry %d: %v", i, err)
+		}
+		if ok, err := VerifyCanonical(unsigned, links.AgentSignature, v.opts.AgentPublicKeyB64); err != nil || !ok {
+			return fmt.Errorf("agent_signature (entry %d): invalid", i)
+		}
+	}
+	h := sha256HexString(canon)
+	if err := v.merkle.AddHex(h); err != nil {
+		return fmt.Errorf("audit entry %d: %v", i, err)
+	}
+	v.prevHash = h
+	v.count++
+	return nil
+}
+
+// skip moves past raw without checking it, so a caller that has reported a
+// bad entry can keep verifying the entries linked after it. Input that is
+// not JSON is hashed as is.
+func (v *StreamVerifier) skip(raw []byte) {
+	h := sha256HexString(string(raw))
+	if canon, err := CanonicalizeJSON(raw); err == nil {
+		h = sha256HexString(canon)
+	}
+	v.merkle.AddHex(h)
+	v.prevHash = h
+	v.count++
+}
+
+// Count returns the number of entries verified so far.
+func (v *StreamVerifier) Count() int {
+	return v.count
+}
+
+// LastHash returns the hash of the last verified entry, i.e. the prev_hash
+// (or ChainAnchor) the next entry must carry.
+func (v *StreamVerifier) LastHash() string {
+	return v.prevHash
+}
+
+// MerkleRoot returns the "sha256:"-prefixed Merkle root of the entries so far.
+func (v *StreamVerifier) MerkleRoot() string {
+	if v.count == 0 {
+		return ""
+	}
+	return "sha256:" + v.merkle.Root()
+}
+
+// maxAuditLineBytes bounds a single JSONL line.
+const maxAuditLineBytes = 16 << 20
+
+// VerifyAuditStream verifies a JSONL stream of audit entries, one entry per
+// line, and if expectedMerkleRoot is non-empty compares the final root with
+// it. Blank lines are skipped.
+func VerifyAuditStream(r io.Reader, opts AuditStreamOptions, expectedMerkleRoot string) (*StreamVerifier, *VerificationResult) {
+	v := NewStreamVerifier(opts)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxAuditLineBytes)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := v.Push(line); err != nil {
+			return v, &VerificationResult{Verified: false, Errors: []string{err.Error()}}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return v, &VerificationResult{Verified: false, Errors: []string{fmt.Sprintf("read audit stream: %v", err)}}
+	}
+	if expectedMerkleRoot != "" {
+		want := strings.TrimPrefix(expectedMerkleRoot, "sha256:")
+		if strings.TrimPrefix(v.MerkleRoot(), "sha256:") != want {
+			return v, &VerificationResult{Verified: false, Errors: []string{"MERKLE ROOT MISMATCH"}}
+		}
+	}
+	return v, &VerificationResult{Verified: true}
+}
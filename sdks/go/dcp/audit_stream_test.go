@@ -0,0 +1,71 @@
+package dcp_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestMerkleAccumulatorMatchesRoot(t *testing.T) {
+	for n := 0; n <= 40; n++ {
+		leaves := testLeaves(n)
+		var acc dcp.MerkleAccumulator
+		for _, l := range leaves {
+			if err := acc.AddHex(l); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want, _ := dcp.MerkleRootFromHexLeaves(leaves)
+		if got := acc.Root(); got != want {
+			t.Fatalf("n=%d: root %s, want %s", n, got, want)
+		}
+	}
+}
+
+func auditJSONL(t *testing.T, sb *dcp.SignedBundle) string {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, e := range sb.Bundle.AuditEntries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+func TestVerifyAuditStream(t *testing.T) {
+	agent, _ := dcp.GenerateKeypair()
+	owner, _ := dcp.GenerateKeypair()
+	sb := signedEntryBundle(t, agent, agent, owner)
+	ih := sb.Bundle.AuditEntries[0].IntentHash
+	opts := dcp.AuditStreamOptions{
+		IntentHash:        func(id string) (string, bool) { return ih, id == "intent001" },
+		AgentPublicKeyB64: agent.PublicKeyB64,
+	}
+
+	v, r := dcp.VerifyAuditStream(strings.NewReader(auditJSONL(t, sb)), opts, *sb.Signature.MerkleRoot)
+	if !r.Verified {
+		t.Fatalf("stream should verify: %v", r.Errors)
+	}
+	if v.Count() != 3 {
+		t.Fatalf("expected 3 entries, got %d", v.Count())
+	}
+
+	lines := strings.Split(strings.TrimSpace(auditJSONL(t, sb)), "\n")
+	reordered := strings.Join([]string{lines[0], lines[2], lines[1]}, "\n")
+	if _, r := dcp.VerifyAuditStream(strings.NewReader(reordered), opts, ""); r.Verified {
+		t.Fatal("reordered entries must break the chain")
+	}
+
+	other, _ := dcp.GenerateKeypair()
+	opts.AgentPublicKeyB64 = other.PublicKeyB64
+	if _, r := dcp.VerifyAuditStream(strings.NewReader(auditJSONL(t, sb)), opts, ""); r.Verified {
+		t.Fatal("entry signatures must be checked")
+	}
+}
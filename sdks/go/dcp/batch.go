@@ -0,0 +1,144 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrBatchMismatch is returned when a batch intent's count and manifest
+// hash, its manifest and the audit entries of its actions do not agree.
+var ErrBatchMismatch = errors.New("batch mismatch")
+
+// BatchManifestHash returns "sha256:" and the hash of the canonical
+// manifest, the list of items a batch intent acts on, such as one per
+// recipient.
+func BatchManifestHash(manifest []string) (string, error) {
+	if manifest == nil {
+		manifest = []string{}
+	}
+	h, err := HashObject(manifest)
+	if err != nil {
+		return "", fmt.Errorf("batch manifest hash: %w", err)
+	}
+	return "sha256:" + h, nil
+}
+
+// NewIntentBatch returns the batch declaration of one action per item of
+// manifest, which must not be empty.
+func NewIntentBatch(manifest []string) (*IntentBatch, error) {
+	if len(manifest) == 0 {
+		return nil, errors.New("batch: empty manifest")
+	}
+	h, err := BatchManifestHash(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return &IntentBatch{Count: len(manifest), ManifestHash: h}, nil
+}
+
+// AppendBatch fans f out into one audit entry per item of manifest, in
+// order, each with the item in evidence.batch_item, and returns their
+// hashes. f.Intent must declare the batch of manifest. The entries are
+// appended one by one; on an error, those before it stay in the chain.
+func (c *AuditChain) AppendBatch(f AuditEntryFields, manifest []string) ([]string, error) {
+	if err := checkBatchManifest(f.Intent.Batch, manifest); err != nil {
+		return nil, fmt.Errorf("audit chain: %w", err)
+	}
+	hashes := make([]string, 0, len(manifest))
+	for i, item := range manifest {
+		fi := f
+		fi.AuditID = ""
+		fi.Evidence = *f.Evidence.Clone()
+		fi.Evidence.BatchItem = &BatchItem{Index: i, Item: item}
+		h, err := c.Append(fi)
+		if err != nil {
+			return hashes, err
+		}
+		hashes = append(hashes, h)
+	}
+	return hashes, nil
+}
+
+// CheckBatch reports whether manifest matches the batch intent declares,
+// by count and hash, and the audit entries on intent that carry a batch
+// item record each item of it exactly once. Entries without a batch item,
+// such as the policy decision on the batch as a whole, are not counted.
+// Errors wrap ErrBatchMismatch.
+func CheckBatch(intent *Intent, manifest []string, entries []AuditEntry) error {
+	var items []*BatchItem
+	for i := range entries {
+		if e := &entries[i]; e.IntentID == intent.IntentID && e.Evidence.BatchItem != nil {
+			items = append(items, e.Evidence.BatchItem)
+		}
+	}
+	return checkBatch(intent.Batch, manifest, items)
+}
+
+func checkBatch(batch *IntentBatch, manifest []string, items []*BatchItem) error {
+	if err := checkBatchManifest(batch, manifest); err != nil {
+		return err
+	}
+	seen := make([]bool, len(manifest))
+	for _, it := range items {
+		switch {
+		case it.Index < 0 || it.Index >= len(manifest):
+			return fmt.Errorf("%w: audit entry for item %d of a batch of %d", ErrBatchMismatch, it.Index, len(manifest))
+		case it.Item != manifest[it.Index]:
+			return fmt.Errorf("%w: audit entry for item %d records %q, the manifest %q", ErrBatchMismatch, it.Index, it.Item, manifest[it.Index])
+		case seen[it.Index]:
+			return fmt.Errorf("%w: item %d is recorded more than once", ErrBatchMismatch, it.Index)
+		}
+		seen[it.Index] = true
+	}
+	if len(items) != len(manifest) {
+		return fmt.Errorf("%w: %d of %d actions recorded", ErrBatchMismatch, len(items), len(manifest))
+	}
+	return nil
+}
+
+// checkBatchManifest checks manifest against the declaration batch.
+func checkBatchManifest(batch *IntentBatch, manifest []string) error {
+	if batch == nil {
+		return fmt.Errorf("%w: the intent declares no batch", ErrBatchMismatch)
+	}
+	if batch.Count != len(manifest) {
+		return fmt.Errorf("%w: the intent declares %d actions, the manifest lists %d", ErrBatchMismatch, batch.Count, len(manifest))
+	}
+	h, err := BatchManifestHash(manifest)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBatchMismatch, err)
+	}
+	if h != batch.ManifestHash {
+		return fmt.Errorf("%w: manifest hash %s, the intent declares %s", ErrBatchMismatch, h, batch.ManifestHash)
+	}
+	return nil
+}
+
+// Validate checks b against the intent schema and returns ValidationErrors
+// listing every violation, or nil.
+func (b *IntentBatch) Validate() error {
+	v := newValidator()
+	b.validate(v)
+	return v.err()
+}
+
+func (b *IntentBatch) validate(v validator) {
+	if b.Count < 1 {
+		v.at("count").fail("must be at least 1")
+	}
+	v.sha256Ref("manifest_hash", b.ManifestHash)
+}
+
+// Clone returns a copy of b.
+func (b *IntentBatch) Clone() *IntentBatch {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	return &c
+}
+
+// Equal reports whether b and o canonicalize identically.
+func (b *IntentBatch) Equal(o *IntentBatch) bool {
+	return (b == nil) == (o == nil) && (b == nil || canonicalEqual(b, o))
+}
@@ -0,0 +1,121 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestAppendBatch(t *testing.T) {
+	manifest := []string{"alice@example.com", "bob@example.com", "carol@example.com"}
+	batch, err := dcp.NewIntentBatch(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.Count != 3 || !strings.HasPrefix(batch.ManifestHash, "sha256:") {
+		t.Fatalf("batch = %+v", batch)
+	}
+	if _, err := dcp.NewIntentBatch(nil); err == nil {
+		t.Fatal("empty manifest")
+	}
+	intent := dcp.Intent{DCPVersion: "1.0", IntentID: "intent001", AgentID: "agent001", HumanID: "human001",
+		Timestamp: "2026-01-01T01:00:00Z", ActionType: "send_email", Target: dcp.IntentTarget{Channel: "email"},
+		DataClasses: []string{"contact_info"}, EstimatedImpact: "medium", Batch: batch}
+	if err := intent.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	chain := dcp.NewAuditChain(dcp.AuditChainOptions{Clock: func() time.Time { return time.Date(2026, 1, 1, 1, 1, 0, 0, time.UTC) }})
+	if _, err := chain.Append(dcp.AuditEntryFields{Intent: intent, PolicyDecision: dcp.OutcomeApproved, Outcome: "policy_approved"}); err != nil {
+		t.Fatal(err)
+	}
+	hashes, err := chain.AppendBatch(dcp.AuditEntryFields{Intent: intent, PolicyDecision: dcp.OutcomeApproved, Outcome: "email_sent"}, manifest)
+	if err != nil || len(hashes) != 3 {
+		t.Fatalf("AppendBatch = %v, %v", hashes, err)
+	}
+	entries := chain.Entries()
+	if it := entries[2].Evidence.BatchItem; it == nil || it.Index != 1 || it.Item != "bob@example.com" || entries[0].Evidence.BatchItem != nil {
+		t.Fatalf("entries: %+v", entries)
+	}
+	if err := dcp.CheckBatch(&intent, manifest, entries); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.AppendBatch(dcp.AuditEntryFields{Intent: intent, Outcome: "email_sent"}, manifest[:2]); !errors.Is(err, dcp.ErrBatchMismatch) {
+		t.Fatalf("another manifest: %v", err)
+	}
+
+	swapped := append([]dcp.AuditEntry(nil), entries...)
+	swapped[1] = *entries[1].Clone()
+	swapped[1].Evidence.BatchItem.Item = "mallory@example.com"
+	for _, tc := range []struct {
+		name     string
+		manifest []string
+		entries  []dcp.AuditEntry
+		want     string
+	}{
+		{"manifest changed", []string{"alice@example.com", "bob@example.com", "dave@example.com"}, entries, "manifest hash"},
+		{"manifest longer", append(manifest[:3:3], "dave@example.com"), entries, "lists 4"},
+		{"action missing", manifest, entries[:3], "2 of 3 actions"},
+		{"action repeated", manifest, append(entries[:4:4], entries[3]), "more than once"},
+		{"other item", manifest, swapped, "mallory@example.com"},
+	} {
+		if err := dcp.CheckBatch(&intent, tc.manifest, tc.entries); !errors.Is(err, dcp.ErrBatchMismatch) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	b, human, _ := builderFixture(t)
+	manifest := []string{"alice@example.com", "bob@example.com"}
+	bundle, err := b.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	intent := bundle.Intent
+	if intent.Batch, err = dcp.NewIntentBatch(manifest); err != nil {
+		t.Fatal(err)
+	}
+	for i, item := range manifest {
+		b.AuditEntry(dcp.AuditEntry{DCPVersion: "1.0", AuditID: fmt.Sprintf("audit10%d", i), Timestamp: "2026-01-01T01:02:00Z",
+			AgentID: "agent001", HumanID: "human001", IntentID: "intent001", PolicyDecision: "approved", Outcome: "email_sent",
+			Evidence: dcp.AuditEvidence{BatchItem: &dcp.BatchItem{Index: i, Item: item}}})
+	}
+	if bundle, err = b.Intent(intent).Bundle(); err != nil {
+		t.Fatal(err)
+	}
+	principal, _ := dcp.NewKeySigner(human.SecretKeyB64)
+	sign := func(b *dcp.CitizenshipBundle) *dcp.SignedBundle {
+		t.Helper()
+		sb, err := dcp.SignBundle(b, principal, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+	withManifest := bundle.Clone()
+	withManifest.BatchManifest = manifest
+	res := dcp.VerifySignedBundleWithOptions(sign(withManifest), dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, Explain: true})
+	if !res.Verified {
+		t.Fatalf("batch bundle: %v", res.Errors)
+	}
+	if last := res.Trace[len(res.Trace)-1]; last.Check != dcp.TraceBatch || !last.OK || last.Detail != "2 actions" {
+		t.Fatalf("trace: %+v", last)
+	}
+	data, _ := json.Marshal(sign(withManifest))
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundle(rsb, human.PublicKeyB64); !res.Verified {
+		t.Fatalf("raw bundle: %v", res.Errors)
+	}
+	if res := dcp.VerifySignedBundle(sign(bundle), human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "lists 0") {
+		t.Fatalf("without the manifest: %+v", res)
+	}
+}
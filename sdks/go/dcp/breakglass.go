@@ -0,0 +1,199 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrBreakGlassInvalid is returned for a break-glass record that is not
+// signed and countersigned, or does not cover the intent it comes with.
+var ErrBreakGlassInvalid = errors.New("break-glass record invalid")
+
+// NewBreakGlassRecord returns an unsigned break-glass record authorizing
+// the action types in scope on intent, from now for ttl. A nil scope
+// authorizes the intent's own action type.
+func (f *RecordFactory) NewBreakGlassRecord(intent Intent, authorizedBy, justification string, scope []string, ttl time.Duration) (BreakGlassRecord, error) {
+	intentHash, err := HashObject(intent)
+	if err != nil {
+		return BreakGlassRecord{}, fmt.Errorf("break glass: intent hash: %w", err)
+	}
+	if scope == nil {
+		scope = []string{intent.ActionType}
+	}
+	now := f.now()
+	return BreakGlassRecord{
+		DCPVersion:    DCPVersion,
+		AgentID:       intent.AgentID,
+		IntentID:      intent.IntentID,
+		IntentHash:    intentHash,
+		AuthorizedBy:  authorizedBy,
+		Justification: justification,
+		Scope:         scope,
+		NotBefore:     FormatTime(now),
+		NotAfter:      FormatTime(now.Add(ttl)),
+	}, nil
+}
+
+// NewBreakGlassRecord calls RecordFactory.NewBreakGlassRecord with the
+// package clock.
+func NewBreakGlassRecord(intent Intent, authorizedBy, justification string, scope []string, ttl time.Duration) (BreakGlassRecord, error) {
+	return defaultRecords.NewBreakGlassRecord(intent, authorizedBy, justification, scope, ttl)
+}
+
+// authorized is the record the authorizer signs: without the signature
+// and the countersigner's members.
+func (r *BreakGlassRecord) authorized() *BreakGlassRecord {
+	c := r.Clone()
+	c.Signature, c.Countersigner, c.CountersignerPublicKey, c.Countersignature = "", "", "", ""
+	return c
+}
+
+// Sign sets Signature to the authorizer s's signature over the record
+// without its signature and countersignature, and clears any
+// countersignature, which must then be made again.
+func (r *BreakGlassRecord) Sign(s BundleSigner) error {
+	r.Countersigner, r.CountersignerPublicKey, r.Countersignature = "", "", ""
+	sig, err := signWith(s, r.authorized())
+	if err != nil {
+		return fmt.Errorf("sign break-glass record for %s: %w", r.IntentID, err)
+	}
+	r.Signature = sig
+	return nil
+}
+
+// Countersign records s, identified as id, as the countersigner and sets
+// Countersignature to its signature over the signed record without the
+// countersignature.
+func (r *BreakGlassRecord) Countersign(s BundleSigner, id string) error {
+	if r.Signature == "" {
+		return fmt.Errorf("countersign break-glass record for %s: not signed by the authorizer", r.IntentID)
+	}
+	r.Countersigner, r.CountersignerPublicKey, r.Countersignature = id, s.PublicKeyB64(), ""
+	sig, err := signWith(s, r)
+	if err != nil {
+		return fmt.Errorf("countersign break-glass record for %s: %w", r.IntentID, err)
+	}
+	r.Countersignature = sig
+	return nil
+}
+
+// VerifySignatures checks Signature against the authorizer's public key
+// and Countersignature against CountersignerPublicKey, which must be
+// another key.
+func (r *BreakGlassRecord) VerifySignatures(authorizerKeyB64 string) error {
+	if r.Signature == "" {
+		return errors.New("no signature")
+	}
+	if ok, err := VerifyObject(r.authorized(), r.Signature, authorizerKeyB64); err != nil || !ok {
+		return errors.New("signature does not verify with the authorizer's key")
+	}
+	if r.Countersignature == "" {
+		return errors.New("not countersigned")
+	}
+	if r.CountersignerPublicKey == authorizerKeyB64 {
+		return errors.New("countersigned by the authorizer")
+	}
+	unsigned := r.Clone()
+	unsigned.Countersignature = ""
+	if ok, err := VerifyObject(unsigned, r.Countersignature, r.CountersignerPublicKey); err != nil || !ok {
+		return errors.New("countersignature does not verify")
+	}
+	return nil
+}
+
+// Check reports whether r authorizes intent: it is signed with the
+// authorizer's key authorizerKeyB64 and countersigned with another, is by
+// the intent's principal, names the intent by its hash, covers its action
+// type and held when the intent was declared. Errors wrap
+// ErrBreakGlassInvalid.
+func (r *BreakGlassRecord) Check(intent *Intent, authorizerKeyB64 string) error {
+	intentHash, err := HashObject(intent)
+	if err != nil {
+		return fmt.Errorf("%w: intent hash: %v", ErrBreakGlassInvalid, err)
+	}
+	return r.check(intent, intentHash, authorizerKeyB64)
+}
+
+func (r *BreakGlassRecord) check(intent *Intent, intentHash, authorizerKeyB64 string) error {
+	if err := r.VerifySignatures(authorizerKeyB64); err != nil {
+		return fmt.Errorf("%w: %v", ErrBreakGlassInvalid, err)
+	}
+	switch {
+	case r.AuthorizedBy != intent.HumanID:
+		return fmt.Errorf("%w: authorized by %s, not the intent's principal %s", ErrBreakGlassInvalid, r.AuthorizedBy, intent.HumanID)
+	case r.IntentHash != intentHash:
+		return fmt.Errorf("%w: intent_hash %s, not the intent's %s", ErrBreakGlassInvalid, r.IntentHash, intentHash)
+	case !oneOf(intent.ActionType, r.Scope):
+		return fmt.Errorf("%w: scope does not cover %s", ErrBreakGlassInvalid, intent.ActionType)
+	}
+	declared, err := intent.TimestampTime()
+	if err != nil {
+		return fmt.Errorf("%w: intent %v", ErrBreakGlassInvalid, err)
+	}
+	from, err := ParseTime(r.NotBefore)
+	if err != nil {
+		return fmt.Errorf("%w: not_before: %v", ErrBreakGlassInvalid, err)
+	}
+	until, err := ParseTime(r.NotAfter)
+	if err != nil {
+		return fmt.Errorf("%w: not_after: %v", ErrBreakGlassInvalid, err)
+	}
+	if declared.Before(from) || !declared.Before(until) {
+		return fmt.Errorf("%w: intent declared at %s, outside %s to %s", ErrBreakGlassInvalid, intent.Timestamp, r.NotBefore, r.NotAfter)
+	}
+	return nil
+}
+
+// Flag is the line verification reports for r.
+func (r *BreakGlassRecord) Flag() string {
+	return fmt.Sprintf("BREAK GLASS: %s on %s authorized by %s and countersigned by %s, %s to %s: %s",
+		strings.Join(r.Scope, ", "), r.IntentID, r.AuthorizedBy, r.Countersigner, r.NotBefore, r.NotAfter, r.Justification)
+}
+
+// Validate checks r against the break-glass schema and returns
+// ValidationErrors listing every violation, or nil.
+func (r *BreakGlassRecord) Validate() error {
+	v := newValidator()
+	r.validate(v)
+	return v.err()
+}
+
+func (r *BreakGlassRecord) validate(v validator) {
+	v.version("dcp_version", r.DCPVersion)
+	v.idOf("agent_id", r.AgentID, IDKindAgent)
+	v.idOf("intent_id", r.IntentID, IDKindIntent)
+	v.minLen("intent_hash", r.IntentHash, 8)
+	v.idOf("authorized_by", r.AuthorizedBy, IDKindHuman)
+	v.minLen("justification", r.Justification, 1)
+	if len(r.Scope) == 0 {
+		v.at("scope").fail("must contain at least one action type")
+	}
+	for i, a := range r.Scope {
+		v.at("scope").index(i).enum("", a, actionTypes)
+	}
+	v.timestamp("not_before", r.NotBefore)
+	v.timestamp("not_after", r.NotAfter)
+	v.signature("signature", r.Signature)
+	if r.Countersigner != "" || r.CountersignerPublicKey != "" {
+		v.id("countersigner", r.Countersigner)
+		v.publicKey("countersigner_public_key", r.CountersignerPublicKey)
+	}
+	v.signature("countersignature", r.Countersignature)
+}
+
+// Clone returns a deep copy of r.
+func (r *BreakGlassRecord) Clone() *BreakGlassRecord {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.Scope = cloneStrings(r.Scope)
+	return &c
+}
+
+// Equal reports whether r and o canonicalize identically.
+func (r *BreakGlassRecord) Equal(o *BreakGlassRecord) bool {
+	return (r == nil) == (o == nil) && (r == nil || canonicalEqual(r, o))
+}
@@ -0,0 +1,128 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestBreakGlassRecords(t *testing.T) {
+	b, human, _ := builderFixture(t)
+	bundle, err := b.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal, _ := dcp.NewKeySigner(human.SecretKeyB64)
+	officer, _ := dcp.GenerateKeypair()
+	countersigner, _ := dcp.NewKeySigner(officer.SecretKeyB64)
+
+	f := dcp.RecordFactory{Clock: func() time.Time { return time.Date(2026, 1, 1, 0, 45, 0, 0, time.UTC) }}
+	r, err := f.NewBreakGlassRecord(bundle.Intent, "human001", "customer locked out of a medical account", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Countersign(countersigner, "officer001"); err == nil {
+		t.Fatal("countersigned before the authorizer signed")
+	}
+	if err := r.Sign(principal); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Check(&bundle.Intent, human.PublicKeyB64); err == nil || !strings.Contains(err.Error(), "not countersigned") {
+		t.Fatalf("without a countersignature: %v", err)
+	}
+	if err := r.Countersign(countersigner, "officer001"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Check(&bundle.Intent, human.PublicKeyB64); err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(b *dcp.CitizenshipBundle) *dcp.SignedBundle {
+		t.Helper()
+		sb, err := dcp.SignBundle(b, principal, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+	withRecord := bundle.Clone()
+	withRecord.BreakGlass = []dcp.BreakGlassRecord{r}
+	opts := dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, Countersigners: []string{officer.PublicKeyB64}}
+	res := dcp.VerifySignedBundleWithOptions(sign(withRecord), opts)
+	if !res.Verified || len(res.Flags) != 1 || !strings.HasPrefix(res.Flags[0], "BREAK GLASS: send_email on intent001 authorized by human001 and countersigned by officer001") {
+		t.Fatalf("verified with a record: %+v", res)
+	}
+	if res := dcp.VerifySignedBundle(sign(bundle), human.PublicKeyB64); !res.Verified || len(res.Flags) != 0 {
+		t.Fatalf("flags without a record: %+v", res)
+	}
+	data, _ := json.Marshal(sign(withRecord))
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundleWithOptions(rsb, opts); !res.Verified || len(res.Flags) != 1 {
+		t.Fatalf("raw bundle: %+v", res)
+	}
+	// Any key can countersign, so a record is not taken as verified
+	// without the keys allowed to.
+	res = dcp.VerifySignedBundle(sign(withRecord), human.PublicKeyB64)
+	if res.Verified || !strings.Contains(res.Errors[0], "no countersigner keys") || len(res.Flags) != 1 {
+		t.Fatalf("without countersigner keys: %+v", res)
+	}
+	res = dcp.VerifySignedBundleWithOptions(sign(withRecord), dcp.VerifyOptions{Countersigners: []string{human.PublicKeyB64}})
+	if res.Verified || !strings.Contains(res.Errors[0], "is not allowed") || len(res.Flags) != 1 {
+		t.Fatalf("countersigner not allowed: %+v", res)
+	}
+
+	selfSigned := r
+	selfSigned.Countersign(principal, "human001")
+	expired := r
+	expired.NotAfter = "2026-01-01T00:50:00Z"
+	expired.Sign(principal)
+	expired.Countersign(countersigner, "officer001")
+	narrow := r
+	narrow.Scope = []string{"browse"}
+	narrow.Sign(principal)
+	narrow.Countersign(countersigner, "officer001")
+	tampered := r
+	tampered.Justification = "routine"
+	for _, tc := range []struct {
+		name   string
+		record dcp.BreakGlassRecord
+		want   string
+	}{
+		{"countersigned by the authorizer", selfSigned, "countersigned by the authorizer"},
+		{"expired", expired, "outside"},
+		{"narrower scope", narrow, "does not cover send_email"},
+		{"tampered", tampered, "authorizer's key"},
+	} {
+		if err := tc.record.Check(&bundle.Intent, human.PublicKeyB64); !errors.Is(err, dcp.ErrBreakGlassInvalid) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+}
+
+func TestBreakGlassRecordValidate(t *testing.T) {
+	r := dcp.BreakGlassRecord{DCPVersion: "1.0", AgentID: "agent001", IntentID: "intent001", IntentHash: strings.Repeat("a", 64),
+		AuthorizedBy: "human001", Scope: []string{"teleport"}, NotBefore: "2026-01-01T00:00:00Z", NotAfter: "2026-01-01T01:00:00Z"}
+	var verrs dcp.ValidationErrors
+	if err := r.Validate(); !errors.As(err, &verrs) {
+		t.Fatalf("err = %v", err)
+	}
+	var got []string
+	for _, fe := range verrs {
+		got = append(got, fe.Pointer)
+	}
+	if strings.Join(got, " ") != "/justification /scope/0" {
+		t.Fatalf("pointers = %v", got)
+	}
+	c := r.Clone()
+	c.Scope[0] = "browse"
+	if r.Scope[0] != "teleport" || r.Equal(c) {
+		t.Fatal("clone shares its scope")
+	}
+}
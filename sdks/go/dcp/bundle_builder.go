@@ -0,0 +1,302 @@
+package dcp
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BundleSigner produces the Ed25519 signatures a BundleBuilder applies.
+// Implement it to keep keys in an HSM or KMS; KeySigner holds a key in memory.
+type BundleSigner interface {
+	// PublicKeyB64 returns the base64 public key matching the signatures.
+	PublicKeyB64() string
+	// SignCanonical signs an already canonicalized JSON document and returns
+	// the base64 signature, as checked by VerifyCanonical.
+	SignCanonical(canon string) (string, error)
+}
+
+// KeySigner is a BundleSigner over an in-memory Ed25519 secret key.
+type KeySigner struct {
+	key ed25519.PrivateKey
+}
+
+// NewKeySigner returns a signer for a base64 Ed25519 secret key as produced
+// by GenerateKeypair.
+func NewKeySigner(secretKeyB64 string) (*KeySigner, error) {
+	sk, err := base64.StdEncoding.DecodeString(secretKeyB64)
+	if err != nil {
+		return nil, fmt.Errorf("decode secret key: %w", err)
+	}
+	if len(sk) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid secret key length: got %d, want %d", len(sk), ed25519.PrivateKeySize)
+	}
+	return &KeySigner{key: ed25519.PrivateKey(sk)}, nil
+}
+
+func (s *KeySigner) PublicKeyB64() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+func (s *KeySigner) SignCanonical(canon string) (string, error) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, []byte(canon))), nil
+}
+
+func signWith(s BundleSigner, obj interface{}) (string, error) {
+	canon, err := Canonicalize(obj)
+	if err != nil {
+		return "", fmt.Errorf("canonicalize: %w", err)
+	}
+	return s.SignCanonical(canon)
+}
+
+// BundleBuilder assembles a SignedBundle from its records. It computes each
+// audit entry's intent_hash and prev_hash, signs the responsible principal
+// record and agent passport, the bundle and optionally each audit entry,
+// and fills in bundle_hash and merkle_root. Setters return the builder so
+// calls can be chained; problems are reported by Build.
+type BundleBuilder struct {
+	rpr      *ResponsiblePrincipalRecord
+	passport *AgentPassport
+	intent   *Intent
+	policy   *PolicyDecision
+	entries  []AuditEntry
+	anchor   *ChainAnchor
+	consent  *ConsentRecord
+	cover    []InsuranceAttestation
+
+	principal   BundleSigner
+	agent       BundleSigner
+	signEntries bool
+	signerType  string
+	signerID    string
+	now         func() time.Time
+}
+
+// NewBundleBuilder returns an empty builder.
+func NewBundleBuilder() *BundleBuilder {
+	return &BundleBuilder{signerType: "human", now: clockNow}
+}
+
+// ResponsiblePrincipalRecord sets the DCP-01 record. Its signature is
+// replaced when a principal signer is set.
+func (b *BundleBuilder) ResponsiblePrincipalRecord(r ResponsiblePrincipalRecord) *BundleBuilder {
+	b.rpr = &r
+	return b
+}
+
+// AgentPassport sets the DCP-01 passport. Its signature is replaced, and an
+// empty public_key filled in, when an agent signer is set.
+func (b *BundleBuilder) AgentPassport(p AgentPassport) *BundleBuilder {
+	b.passport = &p
+	return b
+}
+
+// Intent sets the DCP-02 intent the audit entries refer to.
+func (b *BundleBuilder) Intent(i Intent) *BundleBuilder {
+	b.intent = &i
+	return b
+}
+
+// PolicyDecision sets the DCP-02 policy decision.
+func (b *BundleBuilder) PolicyDecision(d PolicyDecision) *BundleBuilder {
+	b.policy = &d
+	return b
+}
+
+// AuditEntry appends an entry. Its prev_hash and intent_hash are computed
+// by Build, so they can be left empty.
+func (b *BundleBuilder) AuditEntry(e AuditEntry) *BundleBuilder {
+	b.entries = append(b.entries, e)
+	return b
+}
+
+// ChainAnchor continues the audit chain of an earlier bundle; see
+// ChainAnchorFor.
+func (b *BundleBuilder) ChainAnchor(a *ChainAnchor) *BundleBuilder {
+	b.anchor = a
+	return b
+}
+
+// Consent sets the principal's consent to the intent; see NewConsentRecord.
+// Its signature is replaced when a principal signer is set.
+func (b *BundleBuilder) Consent(c ConsentRecord) *BundleBuilder {
+	b.consent = &c
+	return b
+}
+
+// Insurance adds an attester's signed insurance attestation; see
+// NewInsuranceAttestation.
+func (b *BundleBuilder) Insurance(a InsuranceAttestation) *BundleBuilder {
+	b.cover = append(b.cover, a)
+	return b
+}
+
+// PrincipalSigner sets the key that signs the responsible principal record,
+// the consent and the bundle itself.
+func (b *BundleBuilder) PrincipalSigner(s BundleSigner) *BundleBuilder {
+	b.principal = s
+	return b
+}
+
+// AgentSigner sets the key that signs the agent passport and, with
+// signEntries, each audit entry's agent_signature.
+func (b *BundleBuilder) AgentSigner(s BundleSigner, signEntries bool) *BundleBuilder {
+	b.agent = s
+	b.signEntries = signEntries
+	return b
+}
+
+// SignerIdentity overrides the bundle signature's signer block, which
+// defaults to type "human" and the record's human_id.
+func (b *BundleBuilder) SignerIdentity(signerType, id string) *BundleBuilder {
+	b.signerType, b.signerID = signerType, id
+	return b
+}
+
+// Clock sets the time source for the signature's created_at; the default
+// is the package clock (see SetClock).
+func (b *BundleBuilder) Clock(now func() time.Time) *BundleBuilder {
+	b.now = now
+	return b
+}
+
+// Bundle returns the assembled, unsigned bundle with hashes computed and
+// record signatures applied. The result must pass CitizenshipBundle.Validate,
+// so a malformed record is reported before the bundle is signed.
+func (b *BundleBuilder) Bundle() (*CitizenshipBundle, error) {
+	switch {
+	case b.rpr == nil:
+		return nil, errors.New("bundle builder: missing responsible_principal_record")
+	case b.passport == nil:
+		return nil, errors.New("bundle builder: missing agent_passport")
+	case b.intent == nil:
+		return nil, errors.New("bundle builder: missing intent")
+	case b.policy == nil:
+		return nil, errors.New("bundle builder: missing policy_decision")
+	case len(b.entries) == 0:
+		return nil, errors.New("bundle builder: at least one audit entry is required")
+	case b.signEntries && b.agent == nil:
+		return nil, errors.New("bundle builder: signing audit entries needs an agent signer")
+	}
+
+	rpr, passport, consent := *b.rpr, *b.passport, b.consent.Clone()
+	if b.principal != nil {
+		if err := rpr.Sign(b.principal); err != nil {
+			return nil, fmt.Errorf("bundle builder: %w", err)
+		}
+		if consent != nil {
+			if err := consent.Sign(b.principal); err != nil {
+				return nil, fmt.Errorf("bundle builder: %w", err)
+			}
+		}
+	}
+	if b.agent != nil {
+		if err := passport.Sign(b.agent); err != nil {
+			return nil, fmt.Errorf("bundle builder: %w", err)
+		}
+	}
+
+	intentHash, err := HashObject(b.intent)
+	if err != nil {
+		return nil, fmt.Errorf("bundle builder: intent hash: %w", err)
+	}
+	entries := make([]AuditEntry, len(b.entries))
+	prev := chainStart(b.anchor)
+	for i, e := range b.entries {
+		e.PrevHash = prev
+		e.IntentHash = intentHash
+		e.AgentSignature = ""
+		if b.signEntries {
+			sig, err := signWith(b.agent, e)
+			if err != nil {
+				return nil, fmt.Errorf("bundle builder: sign audit entry %d: %w", i, err)
+			}
+			e.AgentSignature = sig
+		}
+		if prev, err = HashObject(e); err != nil {
+			return nil, fmt.Errorf("bundle builder: hash audit entry %d: %w", i, err)
+		}
+		entries[i] = e
+	}
+
+	bundle := &CitizenshipBundle{
+		ResponsiblePrincipalRecord: rpr,
+		AgentPassport:              passport,
+		Intent:                     *b.intent,
+		PolicyDecision:             *b.policy,
+		AuditEntries:               entries,
+		ChainAnchor:                b.anchor,
+		Consent:                    consent,
+		Insurance:                  append([]InsuranceAttestation(nil), b.cover...),
+	}
+	if err := bundle.Validate(); err != nil {
+		return nil, fmt.Errorf("bundle builder: %w", err)
+	}
+	return bundle, nil
+}
+
+// Build assembles the bundle and signs it with the principal signer,
+// returning a SignedBundle that passes VerifySignedBundle.
+func (b *BundleBuilder) Build() (*SignedBundle, error) {
+	if b.principal == nil {
+		return nil, errors.New("bundle builder: no principal signer")
+	}
+	bundle, err := b.Bundle()
+	if err != nil {
+		return nil, err
+	}
+	sb, err := SignBundle(bundle, b.principal, Signer{Type: b.signerType, ID: b.signerID}, b.now())
+	if err != nil {
+		return nil, fmt.Errorf("bundle builder: %w", err)
+	}
+	return sb, nil
+}
+
+// SignBundle signs bundle as it stands with s, computing bundle_hash and
+// merkle_root; unlike BundleBuilder it does not touch the records, so it
+// suits bundles assembled elsewhere. signer's public key is taken from s,
+// and an empty ID defaults to the principal's human_id, or to the last
+// attorney's of a bundle signed under powers of attorney.
+func SignBundle(bundle *CitizenshipBundle, s BundleSigner, signer Signer, createdAt time.Time) (*SignedBundle, error) {
+	canon, err := Canonicalize(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize: %w", err)
+	}
+	sig, err := s.SignCanonical(canon)
+	if err != nil {
+		return nil, fmt.Errorf("sign bundle: %w", err)
+	}
+	leaves, err := auditEntryLeaves(bundle.AuditEntries)
+	if err != nil {
+		return nil, err
+	}
+	root, err := MerkleRootFromHexLeaves(leaves)
+	if err != nil {
+		return nil, fmt.Errorf("merkle root: %w", err)
+	}
+	root = "sha256:" + root
+	if signer.Type == "" {
+		signer.Type = "human"
+	}
+	if signer.ID == "" {
+		signer.ID = bundle.ResponsiblePrincipalRecord.HumanID
+		if n := len(bundle.PowersOfAttorney); n > 0 {
+			signer.ID = bundle.PowersOfAttorney[n-1].AttorneyID
+		}
+	}
+	signer.PublicKeyB64 = s.PublicKeyB64()
+	return &SignedBundle{
+		Bundle: *bundle,
+		Signature: BundleSignature{
+			Alg:        "ed25519",
+			CreatedAt:  FormatTime(createdAt),
+			SignerInfo: signer,
+			BundleHash: "sha256:" + sha256HexString(canon),
+			MerkleRoot: &root,
+			SigB64:     sig,
+		},
+	}, nil
+}
@@ -0,0 +1,127 @@
+package dcp_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func builderFixture(t *testing.T) (*dcp.BundleBuilder, *dcp.Keypair, *dcp.Keypair) {
+	t.Helper()
+	human, _ := dcp.GenerateKeypair()
+	agent, _ := dcp.GenerateKeypair()
+	principal, err := dcp.NewKeySigner(human.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agentSigner, err := dcp.NewKeySigner(agent.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := func(id, outcome string) dcp.AuditEntry {
+		return dcp.AuditEntry{DCPVersion: "1.0", AuditID: id, Timestamp: "2026-01-01T01:01:00Z",
+			AgentID: "agent001", HumanID: "human001", IntentID: "intent001",
+			PolicyDecision: "approved", Outcome: outcome}
+	}
+	b := dcp.NewBundleBuilder().
+		ResponsiblePrincipalRecord(dcp.ResponsiblePrincipalRecord{DCPVersion: "1.0", HumanID: "human001",
+			LegalName: "Alice", EntityType: "natural_person", Jurisdiction: "US",
+			LiabilityMode: "owner_responsible", IssuedAt: "2026-01-01T00:00:00Z"}).
+		AgentPassport(dcp.AgentPassport{DCPVersion: "1.0", AgentID: "agent001",
+			PrincipalBindingReference: "human001", CreatedAt: "2026-01-01T00:10:00Z", Status: "active"}).
+		Intent(dcp.Intent{DCPVersion: "1.0", IntentID: "intent001", AgentID: "agent001", HumanID: "human001",
+			Timestamp: "2026-01-01T01:00:00Z", ActionType: "send_email", Target: dcp.IntentTarget{Channel: "email"},
+			DataClasses: []string{"contact_info"}, EstimatedImpact: "medium"}).
+		PolicyDecision(dcp.PolicyDecision{DCPVersion: "1.0", IntentID: "intent001", Decision: "approve",
+			RiskScore: 0.2, Reasons: []string{"low_risk"}}).
+		AuditEntry(entry("audit001", "policy_approved")).
+		AuditEntry(entry("audit002", "email_sent")).
+		PrincipalSigner(principal).
+		AgentSigner(agentSigner, true).
+		Clock(func() time.Time { return time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC) })
+	return b, human, agent
+}
+
+func TestBundleBuilder(t *testing.T) {
+	b, human, agent := builderFixture(t)
+	sb, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := dcp.VerifySignedBundle(sb, human.PublicKeyB64); !r.Verified {
+		t.Fatalf("built bundle should verify: %v", r.Errors)
+	}
+	if sb.Signature.CreatedAt != "2026-01-01T02:00:00Z" || sb.Signature.SignerInfo.ID != "human001" {
+		t.Fatalf("signature block = %+v", sb.Signature)
+	}
+
+	rpr := sb.Bundle.ResponsiblePrincipalRecord
+	sig := rpr.Signature
+	rpr.Signature = ""
+	if ok, err := dcp.VerifyObject(rpr, sig, human.PublicKeyB64); err != nil || !ok {
+		t.Fatal("responsible principal record signature should verify")
+	}
+	passport := sb.Bundle.AgentPassport
+	if passport.PublicKey != agent.PublicKeyB64 {
+		t.Fatal("passport public_key should be filled from the agent signer")
+	}
+	sig = passport.Signature
+	passport.Signature = ""
+	if ok, err := dcp.VerifyObject(passport, sig, agent.PublicKeyB64); err != nil || !ok {
+		t.Fatal("passport signature should verify")
+	}
+	if errs := dcp.VerifyAuditEntrySignatures(sb.Bundle.AuditEntries, agent.PublicKeyB64); len(errs) != 0 {
+		t.Fatalf("entry signatures: %v", errs)
+	}
+	if sb.Bundle.AuditEntries[0].PrevHash != "GENESIS" {
+		t.Fatal("first entry should start at GENESIS")
+	}
+}
+
+func TestBundleBuilderChainAnchor(t *testing.T) {
+	b, human, _ := builderFixture(t)
+	first, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	anchor, err := dcp.ChainAnchorFor(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, _, _ := builderFixture(t)
+	second, err := next.PrincipalSigner(mustSigner(t, human)).ChainAnchor(anchor).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := dcp.VerifyBundleSequence([]*dcp.SignedBundle{first, second}, human.PublicKeyB64); !r.Verified {
+		t.Fatalf("anchored bundles should verify as a sequence: %v", r.Errors)
+	}
+}
+
+func TestBundleBuilderMissingRecords(t *testing.T) {
+	if _, err := dcp.NewBundleBuilder().Build(); err == nil {
+		t.Fatal("empty builder should fail")
+	}
+	b, _, _ := builderFixture(t)
+	if _, err := b.PrincipalSigner(nil).Build(); err == nil {
+		t.Fatal("builder without a principal signer should fail")
+	}
+
+	b, _, _ = builderFixture(t)
+	_, err := b.PolicyDecision(dcp.PolicyDecision{DCPVersion: "1.0", IntentID: "intent001", Decision: "maybe"}).Build()
+	var verrs dcp.ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 2 {
+		t.Fatalf("malformed policy decision: err = %v", err)
+	}
+}
+
+func mustSigner(t *testing.T, kp *dcp.Keypair) dcp.BundleSigner {
+	t.Helper()
+	s, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
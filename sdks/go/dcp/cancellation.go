@@ -0,0 +1,161 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCancellationInvalid is returned for an intent cancellation that is not
+// the agent's cancellation of its intent.
+var ErrCancellationInvalid = errors.New("cancellation invalid")
+
+// ErrCancelledIntentSucceeded is returned when an audit entry records a
+// cancelled intent's action as done after the cancellation.
+var ErrCancelledIntentSucceeded = errors.New("cancelled intent succeeded")
+
+// CancellationOutcomes are the audit entry outcomes that do not record the
+// action as done, and so may follow an intent's cancellation.
+var CancellationOutcomes = []string{"cancelled", "aborted", "failed", "rolled_back"}
+
+// NewIntentCancellation returns the agent's unsigned cancellation of
+// intent, dated now.
+func (f *RecordFactory) NewIntentCancellation(intent Intent, reason string) (IntentCancellation, error) {
+	intentHash, err := HashObject(intent)
+	if err != nil {
+		return IntentCancellation{}, fmt.Errorf("cancellation: intent hash: %w", err)
+	}
+	return IntentCancellation{
+		DCPVersion: DCPVersion,
+		IntentID:   intent.IntentID,
+		IntentHash: intentHash,
+		AgentID:    intent.AgentID,
+		Timestamp:  f.timestamp(),
+		Reason:     reason,
+	}, nil
+}
+
+// NewIntentCancellation calls RecordFactory.NewIntentCancellation with the
+// package clock.
+func NewIntentCancellation(intent Intent, reason string) (IntentCancellation, error) {
+	return defaultRecords.NewIntentCancellation(intent, reason)
+}
+
+// Sign sets Signature to s's signature over the canonical record with an
+// empty signature. A cancellation is signed by the agent key.
+func (c *IntentCancellation) Sign(s BundleSigner) error {
+	c.Signature = ""
+	sig, err := signWith(s, c)
+	if err != nil {
+		return fmt.Errorf("sign cancellation of %s: %w", c.IntentID, err)
+	}
+	c.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against the agent's public key.
+func (c *IntentCancellation) VerifySignature(publicKeyB64 string) (bool, error) {
+	if c.Signature == "" {
+		return false, fmt.Errorf("cancellation of %s has no signature", c.IntentID)
+	}
+	unsigned := *c
+	unsigned.Signature = ""
+	return VerifyObject(unsigned, c.Signature, publicKeyB64)
+}
+
+// Check reports whether c is the cancellation of intent by its agent, whose
+// passport key is agentKeyB64, and that no audit entry on intent recorded
+// after the cancellation has an outcome outside CancellationOutcomes.
+// Errors wrap ErrCancellationInvalid or ErrCancelledIntentSucceeded.
+func (c *IntentCancellation) Check(intent *Intent, entries []AuditEntry, agentKeyB64 string) error {
+	intentHash, err := HashObject(intent)
+	if err != nil {
+		return fmt.Errorf("%w: intent hash: %v", ErrCancellationInvalid, err)
+	}
+	if err := c.check(intent, intentHash, agentKeyB64); err != nil {
+		return err
+	}
+	for i := range entries {
+		e := &entries[i]
+		if e.IntentID != c.IntentID {
+			continue
+		}
+		if err := c.checkEntry(i, e.Timestamp, e.Outcome); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *IntentCancellation) check(intent *Intent, intentHash, agentKeyB64 string) error {
+	if ok, err := c.VerifySignature(agentKeyB64); err != nil || !ok {
+		if err == nil {
+			err = errors.New("signature does not verify with the agent's key")
+		}
+		return fmt.Errorf("%w: %v", ErrCancellationInvalid, err)
+	}
+	switch {
+	case c.IntentHash != intentHash:
+		return fmt.Errorf("%w: intent_hash %s, not the intent's %s", ErrCancellationInvalid, c.IntentHash, intentHash)
+	case c.IntentID != intent.IntentID:
+		return fmt.Errorf("%w: intent_id %s, not the intent's %s", ErrCancellationInvalid, c.IntentID, intent.IntentID)
+	case c.AgentID != intent.AgentID:
+		return fmt.Errorf("%w: agent_id %s, not the intent's %s", ErrCancellationInvalid, c.AgentID, intent.AgentID)
+	}
+	if _, err := ParseTime(c.Timestamp); err != nil {
+		return fmt.Errorf("%w: %v", ErrCancellationInvalid, err)
+	}
+	return nil
+}
+
+// checkEntry checks audit entry i, with timestamp and outcome, against the
+// cancellation.
+func (c *IntentCancellation) checkEntry(i int, timestamp, outcome string) error {
+	if oneOf(outcome, CancellationOutcomes) {
+		return nil
+	}
+	cancelled, err := ParseTime(c.Timestamp)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCancellationInvalid, err)
+	}
+	at, err := ParseTime(timestamp)
+	if err != nil {
+		return fmt.Errorf("%w: audit entry %d: %v", ErrCancelledIntentSucceeded, i, err)
+	}
+	if at.After(cancelled) {
+		return fmt.Errorf("%w: audit entry %d records %q at %s, after the cancellation at %s",
+			ErrCancelledIntentSucceeded, i, outcome, timestamp, c.Timestamp)
+	}
+	return nil
+}
+
+// Validate checks c against the cancellation schema and returns
+// ValidationErrors listing every violation, or nil.
+func (c *IntentCancellation) Validate() error {
+	v := newValidator()
+	c.validate(v)
+	return v.err()
+}
+
+func (c *IntentCancellation) validate(v validator) {
+	v.version("dcp_version", c.DCPVersion)
+	v.idOf("intent_id", c.IntentID, IDKindIntent)
+	v.minLen("intent_hash", c.IntentHash, 8)
+	v.idOf("agent_id", c.AgentID, IDKindAgent)
+	v.timestamp("timestamp", c.Timestamp)
+	v.minLen("reason", c.Reason, 1)
+	v.signature("signature", c.Signature)
+}
+
+// Clone returns a copy of c.
+func (c *IntentCancellation) Clone() *IntentCancellation {
+	if c == nil {
+		return nil
+	}
+	d := *c
+	return &d
+}
+
+// Equal reports whether c and o canonicalize identically.
+func (c *IntentCancellation) Equal(o *IntentCancellation) bool {
+	return (c == nil) == (o == nil) && (c == nil || canonicalEqual(c, o))
+}
@@ -0,0 +1,106 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestIntentCancellation(t *testing.T) {
+	b, human, agent := builderFixture(t)
+	bundle, err := b.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal, _ := dcp.NewKeySigner(human.SecretKeyB64)
+	agentSigner, _ := dcp.NewKeySigner(agent.SecretKeyB64)
+
+	cancel := func(at time.Time) dcp.IntentCancellation {
+		t.Helper()
+		f := dcp.RecordFactory{Clock: func() time.Time { return at }}
+		c, err := f.NewIntentCancellation(bundle.Intent, "recipient unsubscribed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Sign(agentSigner); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	late := cancel(time.Date(2026, 1, 1, 1, 2, 0, 0, time.UTC))
+	if err := late.Check(&bundle.Intent, bundle.AuditEntries, agent.PublicKeyB64); err != nil {
+		t.Fatal(err)
+	}
+	early := cancel(time.Date(2026, 1, 1, 1, 0, 30, 0, time.UTC))
+	err = early.Check(&bundle.Intent, bundle.AuditEntries, agent.PublicKeyB64)
+	if !errors.Is(err, dcp.ErrCancelledIntentSucceeded) || !strings.Contains(err.Error(), `"policy_approved"`) {
+		t.Fatalf("success after the cancellation: %v", err)
+	}
+	aborted := append([]dcp.AuditEntry(nil), bundle.AuditEntries...)
+	for i := range aborted {
+		aborted[i].Outcome = "aborted"
+	}
+	if err := early.Check(&bundle.Intent, aborted, agent.PublicKeyB64); err != nil {
+		t.Fatalf("aborted after the cancellation: %v", err)
+	}
+	if err := early.Check(&bundle.Intent, nil, human.PublicKeyB64); !errors.Is(err, dcp.ErrCancellationInvalid) {
+		t.Fatalf("with the principal's key: %v", err)
+	}
+	other := bundle.Intent
+	other.IntentID = "intent002"
+	if err := late.Check(&other, nil, agent.PublicKeyB64); err == nil || !strings.Contains(err.Error(), "intent_hash") {
+		t.Fatalf("of another intent: %v", err)
+	}
+
+	sign := func(b *dcp.CitizenshipBundle) *dcp.SignedBundle {
+		t.Helper()
+		sb, err := dcp.SignBundle(b, principal, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+	withLate := bundle.Clone()
+	withLate.Cancellation = &late
+	res := dcp.VerifySignedBundleWithOptions(sign(withLate), dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, Explain: true})
+	if !res.Verified {
+		t.Fatalf("cancelled after the audit entries: %v", res.Errors)
+	}
+	if last := res.Trace[len(res.Trace)-1]; last.Check != dcp.TraceCancellation || !last.OK {
+		t.Fatalf("trace: %+v", last)
+	}
+	withEarly := bundle.Clone()
+	withEarly.Cancellation = &early
+	if res := dcp.VerifySignedBundle(sign(withEarly), human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "after the cancellation") {
+		t.Fatalf("cancelled before the audit entries: %+v", res)
+	}
+	data, _ := json.Marshal(sign(withEarly))
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundle(rsb, human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "after the cancellation") {
+		t.Fatalf("raw bundle: %+v", res)
+	}
+}
+
+func TestIntentCancellationValidate(t *testing.T) {
+	c := dcp.IntentCancellation{DCPVersion: "1.0", IntentID: "intent001", IntentHash: strings.Repeat("a", 64),
+		AgentID: "agent001", Timestamp: "2026-01-01T00:00:00Z"}
+	var verrs dcp.ValidationErrors
+	if err := c.Validate(); !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Pointer != "/reason" {
+		t.Fatalf("err = %v", err)
+	}
+	if at, err := c.TimestampTime(); err != nil || at.Year() != 2026 {
+		t.Fatalf("TimestampTime = %v, %v", at, err)
+	}
+	d := c.Clone()
+	d.Reason = "changed"
+	if c.Reason != "" || c.Equal(d) {
+		t.Fatal("clone is not a copy")
+	}
+}
@@ -0,0 +1,276 @@
+package dcp
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Capability grammar
+//
+//	capability = resource ":" action [ ":" constraint ]
+//	resource   = name | "*"
+//	action     = name | "*"
+//	constraint = "*" | limit | pattern
+//	limit      = ( "<=" | "<" ) amount [ unit ]
+//	pattern    = domain glob ("*.example.com") or exact token
+//
+// A bare legacy capability such as "email" (the DCP-01 enum form) parses as
+// "email:*". Examples: "email:send:*.example.com", "payments:initiate:<=100EUR".
+
+// ConstraintKind identifies the shape of a capability constraint.
+type ConstraintKind int
+
+const (
+	// ConstraintAny places no restriction on the capability.
+	ConstraintAny ConstraintKind = iota
+	// ConstraintPattern restricts the capability to targets matching a pattern.
+	ConstraintPattern
+	// ConstraintLimit restricts the capability to amounts under an upper bound.
+	ConstraintLimit
+)
+
+// CapabilityConstraint is the optional third segment of a capability.
+type CapabilityConstraint struct {
+	Kind      ConstraintKind
+	Pattern   string   // ConstraintPattern: lowercase domain glob or token
+	Inclusive bool     // ConstraintLimit: "<=" when true, "<" when false
+	Amount    *big.Rat // ConstraintLimit: upper bound
+	Unit      string   // ConstraintLimit: uppercase unit or currency, may be ""
+}
+
+// Capability is a parsed, normalized capability string.
+type Capability struct {
+	Resource   string
+	Action     string
+	Constraint CapabilityConstraint
+}
+
+var (
+	capabilityNamePattern  = regexp.MustCompile(`^(\*|[a-z0-9][a-z0-9_.-]*)$`)
+	capabilityLimitPattern = regexp.MustCompile(`^(<=|<)([0-9]+(?:\.[0-9]+)?)([A-Z]{0,8})$`)
+	capabilityTokenPattern = regexp.MustCompile(`^(\*\.)?[a-z0-9_-]+(\.[a-z0-9_-]+)*$`)
+)
+
+// ParseCapability parses and normalizes a capability string.
+func ParseCapability(s string) (Capability, error) {
+	raw := strings.TrimSpace(s)
+	if raw == "" {
+		return Capability{}, fmt.Errorf("capability: empty string")
+	}
+	parts := strings.SplitN(raw, ":", 3)
+	c := Capability{Resource: strings.ToLower(parts[0]), Action: "*"}
+	if len(parts) > 1 {
+		c.Action = strings.ToLower(parts[1])
+	}
+	if !capabilityNamePattern.MatchString(c.Resource) {
+		return Capability{}, fmt.Errorf("capability %q: invalid resource %q", s, parts[0])
+	}
+	if !capabilityNamePattern.MatchString(c.Action) {
+		return Capability{}, fmt.Errorf("capability %q: invalid action %q", s, parts[1])
+	}
+	if len(parts) == 3 {
+		con, err := parseCapabilityConstraint(parts[2])
+		if err != nil {
+			return Capability{}, fmt.Errorf("capability %q: %w", s, err)
+		}
+		c.Constraint = con
+	}
+	return c, nil
+}
+
+func parseCapabilityConstraint(s string) (CapabilityConstraint, error) {
+	if s == "" || s == "*" {
+		return CapabilityConstraint{Kind: ConstraintAny}, nil
+	}
+	if strings.HasPrefix(s, "<") {
+		m := capabilityLimitPattern.FindStringSubmatch(strings.ToUpper(s))
+		if m == nil {
+			return CapabilityConstraint{}, fmt.Errorf("invalid limit constraint %q", s)
+		}
+		amount, ok := new(big.Rat).SetString(m[2])
+		if !ok {
+			return CapabilityConstraint{}, fmt.Errorf("invalid limit amount %q", m[2])
+		}
+		return CapabilityConstraint{Kind: ConstraintLimit, Inclusive: m[1] == "<=", Amount: amount, Unit: m[3]}, nil
+	}
+	p := strings.ToLower(s)
+	if !capabilityTokenPattern.MatchString(p) {
+		return CapabilityConstraint{}, fmt.Errorf("invalid pattern constraint %q", s)
+	}
+	return CapabilityConstraint{Kind: ConstraintPattern, Pattern: p}, nil
+}
+
+// MustParseCapability is like ParseCapability but panics on error. Intended
+// for capabilities fixed at compile time.
+func MustParseCapability(s string) Capability {
+	c, err := ParseCapability(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NormalizeCapability returns the canonical string form of s.
+func NormalizeCapability(s string) (string, error) {
+	c, err := ParseCapability(s)
+	if err != nil {
+		return "", err
+	}
+	return c.String(), nil
+}
+
+// String returns the canonical form: lowercase names, the constraint segment
+// omitted when it is unrestricted, and limit amounts in shortest decimal form.
+func (c Capability) String() string {
+	base := c.Resource + ":" + c.Action
+	switch c.Constraint.Kind {
+	case ConstraintPattern:
+		return base + ":" + c.Constraint.Pattern
+	case ConstraintLimit:
+		op := "<"
+		if c.Constraint.Inclusive {
+			op = "<="
+		}
+		return base + ":" + op + formatRat(c.Constraint.Amount) + c.Constraint.Unit
+	}
+	return base
+}
+
+func formatRat(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	s := r.FloatString(18)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Covers reports whether c grants everything other grants, i.e. other is an
+// attenuation (equal or narrower) of c.
+func (c Capability) Covers(other Capability) bool {
+	if c.Resource != "*" && c.Resource != other.Resource {
+		return false
+	}
+	if c.Action != "*" && c.Action != other.Action {
+		return false
+	}
+	return c.Constraint.covers(other.Constraint)
+}
+
+func (c CapabilityConstraint) covers(other CapabilityConstraint) bool {
+	switch c.Kind {
+	case ConstraintAny:
+		return true
+	case ConstraintPattern:
+		return other.Kind == ConstraintPattern && patternCovers(c.Pattern, other.Pattern)
+	case ConstraintLimit:
+		if other.Kind != ConstraintLimit || c.Unit != other.Unit {
+			return false
+		}
+		cmp := other.Amount.Cmp(c.Amount)
+		if cmp < 0 {
+			return true
+		}
+		// Equal bounds: "<100" fits under "<=100" but not the reverse.
+		return cmp == 0 && (c.Inclusive || !other.Inclusive)
+	}
+	return false
+}
+
+// patternCovers treats a leading "*." as matching one or more labels, so
+// "*.example.com" covers "a.example.com" and "*.a.example.com" but not
+// "example.com".
+func patternCovers(parent, child string) bool {
+	if parent == child {
+		return true
+	}
+	if !strings.HasPrefix(parent, "*.") {
+		return false
+	}
+	suffix := parent[1:]
+	return strings.HasSuffix(child, suffix) && len(child) > len(suffix)
+}
+
+// MatchesTarget reports whether a concrete target (domain name or amount with
+// unit, e.g. "mail.example.com" or "42.50EUR") satisfies c's constraint.
+func (c Capability) MatchesTarget(target string) bool {
+	switch c.Constraint.Kind {
+	case ConstraintAny:
+		return true
+	case ConstraintPattern:
+		t := strings.ToLower(strings.TrimSpace(target))
+		return t == c.Constraint.Pattern || patternCovers(c.Constraint.Pattern, t)
+	case ConstraintLimit:
+		// Reuse the limit parser: "<=42EUR" yields amount 42 and unit EUR.
+		con, err := parseCapabilityConstraint("<=" + strings.TrimSpace(target))
+		if err != nil || con.Unit != c.Constraint.Unit {
+			return false
+		}
+		cmp := con.Amount.Cmp(c.Constraint.Amount)
+		return cmp < 0 || (cmp == 0 && c.Constraint.Inclusive)
+	}
+	return false
+}
+
+// CapabilitySet is a parsed list of capabilities, as carried on a passport.
+type CapabilitySet []Capability
+
+// ParseCapabilitySet parses every entry of caps, failing on the first error.
+func ParseCapabilitySet(caps []string) (CapabilitySet, error) {
+	set := make(CapabilitySet, 0, len(caps))
+	for _, s := range caps {
+		c, err := ParseCapability(s)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, c)
+	}
+	return set, nil
+}
+
+// Strings returns the normalized string form of every capability in the set.
+func (s CapabilitySet) Strings() []string {
+	out := make([]string, len(s))
+	for i, c := range s {
+		out[i] = c.String()
+	}
+	return out
+}
+
+// Covers reports whether any capability in the set covers c.
+func (s CapabilitySet) Covers(c Capability) bool {
+	for _, granted := range s {
+		if granted.Covers(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAttenuation verifies that every capability in child is covered by
+// parent. Issuers call it before delegating; verifiers call it when checking
+// that a derived credential does not exceed the one it was derived from.
+func CheckAttenuation(parent, child []string) error {
+	p, err := ParseCapabilitySet(parent)
+	if err != nil {
+		return fmt.Errorf("parent capabilities: %w", err)
+	}
+	c, err := ParseCapabilitySet(child)
+	if err != nil {
+		return fmt.Errorf("child capabilities: %w", err)
+	}
+	for _, want := range c {
+		if !p.Covers(want) {
+			return fmt.Errorf("capability %s exceeds granted set", want)
+		}
+	}
+	return nil
+}
+
+// AuthorizeCapability checks a single requested capability against the
+// granted list. It returns nil when the request is covered.
+func AuthorizeCapability(granted []string, requested string) error {
+	return CheckAttenuation(granted, []string{requested})
+}
@@ -0,0 +1,110 @@
+package dcp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestParseCapabilityNormalizes(t *testing.T) {
+	cases := map[string]string{
+		"email":                       "email:*",
+		"Email:Send":                  "email:send",
+		"email:send:*":                "email:send",
+		"email:send:*.Example.COM":    "email:send:*.example.com",
+		"payments:initiate:<=100eur":  "payments:initiate:<=100EUR",
+		"payments:initiate:<100.500":  "payments:initiate:<100.5",
+		" browse:get:docs.example.io": "browse:get:docs.example.io",
+	}
+	for in, want := range cases {
+		got, err := dcp.NormalizeCapability(in)
+		if err != nil {
+			t.Fatalf("%q: %v", in, err)
+		}
+		if got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseCapabilityRejectsMalformed(t *testing.T) {
+	for _, in := range []string{"", ":send", "email:", "email:send:<=abc", "email:send:a..b", "pay ments:x"} {
+		if _, err := dcp.ParseCapability(in); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+}
+
+func TestCapabilityCovers(t *testing.T) {
+	cases := []struct {
+		parent, child string
+		want          bool
+	}{
+		{"email", "email:send:*.example.com", true},
+		{"email:send:*.example.com", "email:send:mail.example.com", true},
+		{"email:send:*.example.com", "email:send:*.eu.example.com", true},
+		{"email:send:*.example.com", "email:send:example.com", false},
+		{"email:send:*.example.com", "email:send", false},
+		{"email:send", "email:read", false},
+		{"*:*", "payments:initiate:<=5USD", true},
+		{"payments:initiate:<=100EUR", "payments:initiate:<=50EUR", true},
+		{"payments:initiate:<=100EUR", "payments:initiate:<100EUR", true},
+		{"payments:initiate:<100EUR", "payments:initiate:<=100EUR", false},
+		{"payments:initiate:<=100EUR", "payments:initiate:<=50USD", false},
+	}
+	for _, tc := range cases {
+		got := dcp.MustParseCapability(tc.parent).Covers(dcp.MustParseCapability(tc.child))
+		if got != tc.want {
+			t.Errorf("%s covers %s: got %v, want %v", tc.parent, tc.child, got, tc.want)
+		}
+	}
+}
+
+func TestCapabilityMatchesTarget(t *testing.T) {
+	c := dcp.MustParseCapability("payments:initiate:<=100EUR")
+	if !c.MatchesTarget("100EUR") || c.MatchesTarget("100.01EUR") || c.MatchesTarget("5USD") {
+		t.Fatal("limit target matching is wrong")
+	}
+	d := dcp.MustParseCapability("email:send:*.example.com")
+	if !d.MatchesTarget("smtp.example.com") || d.MatchesTarget("example.org") {
+		t.Fatal("pattern target matching is wrong")
+	}
+}
+
+func TestCheckAttenuation(t *testing.T) {
+	parent := []string{"email:send:*.example.com", "payments:initiate:<=100EUR"}
+	if err := dcp.CheckAttenuation(parent, []string{"payments:initiate:<=20EUR"}); err != nil {
+		t.Fatalf("expected attenuation to hold: %v", err)
+	}
+	if err := dcp.CheckAttenuation(parent, []string{"payments:refund"}); err == nil {
+		t.Fatal("expected escalation to be rejected")
+	}
+	if err := dcp.AuthorizeCapability(parent, "email:send:bob.example.com"); err != nil {
+		t.Fatalf("expected request to be authorized: %v", err)
+	}
+}
+
+func TestPassportScopedCapabilities(t *testing.T) {
+	b, _, _ := builderFixture(t)
+	b.AgentPassport(dcp.AgentPassport{DCPVersion: "1.0", AgentID: "agent001", PrincipalBindingReference: "human001",
+		Capabilities: []string{"email:send:*.example.com", "payments:initiate:<=100EUR", "browse"},
+		CreatedAt:    "2026-01-01T00:10:00Z", Status: "active"})
+	if _, err := b.Build(); err != nil {
+		t.Fatalf("passport with scoped capabilities: %v", err)
+	}
+	p := dcp.AgentPassport{Capabilities: []string{"email:send:<=ten"}}
+	var verrs dcp.ValidationErrors
+	if err := p.Validate(); !errors.As(err, &verrs) || !hasPointer(verrs, "/capabilities/0") {
+		t.Fatalf("malformed capability: %v", err)
+	}
+}
+
+func hasPointer(verrs dcp.ValidationErrors, pointer string) bool {
+	for _, e := range verrs {
+		if e.Pointer == pointer {
+			return true
+		}
+	}
+	return false
+}
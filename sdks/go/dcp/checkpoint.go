@@ -0,0 +1,125 @@
+package dcp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Checkpoint is a signed tree head for an audit ledger: a commitment by the
+// ledger's key to the first TreeSize entries and their Merkle root. Agents
+// publish checkpoints periodically; verifiers check each new checkpoint
+// against the previous one with VerifyCheckpointContinuity.
+type Checkpoint struct {
+	DCPVersion string `json:"dcp_version"`
+	Origin     string `json:"origin"`
+	TreeSize   int64  `json:"tree_size"`
+	RootHash   string `json:"root_hash"`
+	Timestamp  string `json:"timestamp"`
+	Signature  string `json:"signature,omitempty"`
+	// Cosignatures are witness countersignatures; see Witness and WitnessPolicy.
+	Cosignatures []WitnessCosignature `json:"cosignatures,omitempty"`
+}
+
+// checkpointBody is the signed portion of a Checkpoint.
+type checkpointBody struct {
+	DCPVersion string `json:"dcp_version"`
+	Origin     string `json:"origin"`
+	TreeSize   int64  `json:"tree_size"`
+	RootHash   string `json:"root_hash"`
+	Timestamp  string `json:"timestamp"`
+}
+
+func (c *Checkpoint) body() checkpointBody {
+	return checkpointBody{
+		DCPVersion: c.DCPVersion,
+		Origin:     c.Origin,
+		TreeSize:   c.TreeSize,
+		RootHash:   c.RootHash,
+		Timestamp:  c.Timestamp,
+	}
+}
+
+// NewCheckpoint returns an unsigned checkpoint for the current state of tree.
+// origin identifies the ledger, typically the agent_id.
+func NewCheckpoint(origin string, tree *MerkleTree, now time.Time) *Checkpoint {
+	tree.mu.RLock()
+	size := len(tree.levels[0])
+	root := tree.rootAt(size)
+	tree.mu.RUnlock()
+	return newCheckpoint(origin, size, root, now)
+}
+
+func newCheckpoint(origin string, size int, rootHex string, now time.Time) *Checkpoint {
+	if rootHex != "" {
+		rootHex = "sha256:" + rootHex
+	}
+	return &Checkpoint{
+		DCPVersion: "1.0",
+		Origin:     origin,
+		TreeSize:   int64(size),
+		RootHash:   rootHex,
+		Timestamp:  FormatTime(now),
+	}
+}
+
+// Sign sets Signature to an Ed25519 signature over the canonical checkpoint
+// without its signature member.
+func (c *Checkpoint) Sign(secretKeyB64 string) error {
+	sig, err := SignObject(c.body(), secretKeyB64)
+	if err != nil {
+		return fmt.Errorf("sign checkpoint: %w", err)
+	}
+	c.Signature = sig
+	return nil
+}
+
+// SignWith is Sign for a key held by a BundleSigner, such as a keystore
+// key or an HSM.
+func (c *Checkpoint) SignWith(s BundleSigner) error {
+	sig, err := signWith(s, c.body())
+	if err != nil {
+		return fmt.Errorf("sign checkpoint: %w", err)
+	}
+	c.Signature = sig
+	return nil
+}
+
+// Verify checks the checkpoint's signature against publicKeyB64.
+func (c *Checkpoint) Verify(publicKeyB64 string) (bool, error) {
+	if c.Signature == "" {
+		return false, fmt.Errorf("checkpoint is not signed")
+	}
+	return VerifyObject(c.body(), c.Signature, publicKeyB64)
+}
+
+// VerifyCheckpointContinuity checks that next extends prev: both must be for
+// the same origin, next must not be older or smaller, and proof must be a
+// consistency proof from prev.TreeSize to next.TreeSize (see
+// MerkleTree.ConsistencyProof). Signatures are not checked here.
+func VerifyCheckpointContinuity(prev, next *Checkpoint, proof []string) error {
+	if prev.Origin != next.Origin {
+		return fmt.Errorf("checkpoint origin changed: %q -> %q", prev.Origin, next.Origin)
+	}
+	if next.TreeSize < prev.TreeSize {
+		return fmt.Errorf("checkpoint tree shrank: %d -> %d", prev.TreeSize, next.TreeSize)
+	}
+	prevTime, err := prev.TimestampTime()
+	if err != nil {
+		return fmt.Errorf("previous checkpoint timestamp: %w", err)
+	}
+	nextTime, err := next.TimestampTime()
+	if err != nil {
+		return fmt.Errorf("next checkpoint timestamp: %w", err)
+	}
+	if nextTime.Before(prevTime) {
+		return fmt.Errorf("checkpoint timestamp went backwards: %s -> %s", prev.Timestamp, next.Timestamp)
+	}
+	if !strings.HasPrefix(next.RootHash, "sha256:") && next.TreeSize > 0 {
+		return fmt.Errorf("checkpoint root_hash must be sha256-prefixed")
+	}
+	if !VerifyMerkleConsistency(int(prev.TreeSize), int(next.TreeSize), prev.RootHash, next.RootHash, proof) {
+		return fmt.Errorf("checkpoint %d is not consistent with checkpoint %d", next.TreeSize, prev.TreeSize)
+	}
+	return nil
+}
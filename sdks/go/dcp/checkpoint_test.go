@@ -0,0 +1,67 @@
+package dcp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestCheckpointSignVerify(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	tree, _ := dcp.NewMerkleTreeFromHexLeaves(testLeaves(5))
+	cp := dcp.NewCheckpoint("agent-1", tree, time.Now())
+	if err := cp.Sign(kp.SecretKeyB64); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := cp.Verify(kp.PublicKeyB64); err != nil || !ok {
+		t.Fatalf("checkpoint should verify: %v", err)
+	}
+	cp.TreeSize = 4
+	if ok, _ := cp.Verify(kp.PublicKeyB64); ok {
+		t.Fatal("tampered checkpoint must not verify")
+	}
+}
+
+func TestCheckpointSignWith(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, _ := dcp.NewMerkleTreeFromHexLeaves(testLeaves(3))
+	cp := dcp.NewCheckpoint("log", tree, time.Now())
+	if err := cp.SignWith(signer); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := cp.Verify(kp.PublicKeyB64); err != nil || !ok {
+		t.Fatalf("checkpoint should verify: %v", err)
+	}
+}
+
+func TestCheckpointContinuity(t *testing.T) {
+	leaves := testLeaves(12)
+	tree, _ := dcp.NewMerkleTreeFromHexLeaves(leaves[:5])
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	prev := dcp.NewCheckpoint("agent-1", tree, t0)
+	for _, l := range leaves[5:] {
+		tree.Append(l)
+	}
+	next := dcp.NewCheckpoint("agent-1", tree, t0.Add(time.Hour))
+	proof, _ := tree.ConsistencyProof(5)
+	if err := dcp.VerifyCheckpointContinuity(prev, next, proof); err != nil {
+		t.Fatal(err)
+	}
+
+	forked, _ := dcp.NewMerkleTreeFromHexLeaves(append(testLeaves(4), leaves[7:]...))
+	fork := dcp.NewCheckpoint("agent-1", forked, t0.Add(time.Hour))
+	forkProof, _ := forked.ConsistencyProof(5)
+	if dcp.VerifyCheckpointContinuity(prev, fork, forkProof) == nil {
+		t.Fatal("forked history must not be continuous")
+	}
+
+	stale := dcp.NewCheckpoint("agent-1", tree, t0.Add(-time.Hour))
+	if dcp.VerifyCheckpointContinuity(prev, stale, proof) == nil {
+		t.Fatal("checkpoint timestamps must not go backwards")
+	}
+}
@@ -0,0 +1,69 @@
+package dcp
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+	"time"
+)
+
+// The package clock and entropy source are used wherever a caller does not
+// supply its own: record constructors and ID generation, BundleBuilder,
+// AuditChain, ExportLedger and GenerateKeypair. Packages built on this one
+// draw from the entropy source through Entropy. Tests and deterministic
+// replay can replace them with SetClock and SetEntropy.
+var (
+	envMu    sync.RWMutex
+	envClock           = time.Now
+	envRand  io.Reader = rand.Reader
+)
+
+// SetClock replaces the package clock; nil restores time.Now. It returns a
+// function that restores the previous clock, so tests can defer it.
+func SetClock(now func() time.Time) (restore func()) {
+	if now == nil {
+		now = time.Now
+	}
+	envMu.Lock()
+	prev := envClock
+	envClock = now
+	envMu.Unlock()
+	return func() { SetClock(prev) }
+}
+
+// SetEntropy replaces the package source of randomness; nil restores
+// crypto/rand. r must be safe for concurrent use if the package is used
+// concurrently. Anything other than crypto/rand makes identifiers and keys
+// predictable, so keep it to tests and replay. It returns a function that
+// restores the previous source.
+func SetEntropy(r io.Reader) (restore func()) {
+	if r == nil {
+		r = rand.Reader
+	}
+	envMu.Lock()
+	prev := envRand
+	envRand = r
+	envMu.Unlock()
+	return func() { SetEntropy(prev) }
+}
+
+// Entropy returns the package source of randomness, crypto/rand unless
+// replaced with SetEntropy.
+func Entropy() io.Reader {
+	return entropy()
+}
+
+// clockNow reads the package clock.
+func clockNow() time.Time {
+	envMu.RLock()
+	now := envClock
+	envMu.RUnlock()
+	return now()
+}
+
+// entropy returns the package source of randomness.
+func entropy() io.Reader {
+	envMu.RLock()
+	defer envMu.RUnlock()
+	return envRand
+}
@@ -0,0 +1,51 @@
+package dcp_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestSetClockAndEntropy(t *testing.T) {
+	fixed := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	replay := func() (*dcp.Keypair, dcp.Intent, dcp.AuditEntry) {
+		defer dcp.SetClock(func() time.Time { return fixed })()
+		defer dcp.SetEntropy(bytes.NewReader(bytes.Repeat([]byte{7}, 256)))()
+		kp, err := dcp.GenerateKeypair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		intent := dcp.NewIntent(dcp.NewAgentID(), dcp.NewHumanID(), "browse", dcp.IntentTarget{Channel: dcp.ChannelWeb},
+			[]string{"none"}, dcp.ImpactLow)
+		chain := dcp.NewAuditChain(dcp.AuditChainOptions{})
+		if _, err := chain.Append(dcp.AuditEntryFields{Intent: intent, PolicyDecision: dcp.OutcomeApproved, Outcome: "ok"}); err != nil {
+			t.Fatal(err)
+		}
+		return kp, intent, chain.Entries()[0]
+	}
+
+	kp1, intent1, entry1 := replay()
+	kp2, intent2, entry2 := replay()
+	if *kp1 != *kp2 || intent1.IntentID != intent2.IntentID || entry1 != entry2 {
+		t.Fatal("the same clock and entropy should reproduce keys, IDs and entries")
+	}
+	if intent1.Timestamp != "2026-05-06T07:08:09Z" || entry1.Timestamp != intent1.Timestamp {
+		t.Fatalf("timestamps = %s, %s", intent1.Timestamp, entry1.Timestamp)
+	}
+
+	// Both sources are restored afterwards.
+	src := bytes.NewReader(nil)
+	restore := dcp.SetEntropy(src)
+	if dcp.Entropy() != src {
+		t.Fatal("Entropy does not return the source set")
+	}
+	restore()
+	if dcp.NewIntentID() == intent1.IntentID {
+		t.Fatal("entropy was not restored")
+	}
+	if ts := dcp.NewRevocationRecord("agent001", "human001", "r").Timestamp; ts == intent1.Timestamp {
+		t.Fatal("clock was not restored")
+	}
+}
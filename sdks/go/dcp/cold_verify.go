@@ -0,0 +1,158 @@
+package dcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SegmentProof places a run of consecutive ledger entries in the tree of a
+// checkpoint, so the run can be verified from the checkpoint alone. It is
+// the inclusion proof of the run's last entry: that entry's hash commits,
+// through the prev_hash chain, to every entry before it in the run.
+type SegmentProof struct {
+	Start     int64             `json:"start"`
+	TreeSize  int64             `json:"tree_size"`
+	Inclusion []MerkleProofStep `json:"inclusion"`
+}
+
+// LedgerSegmentProof returns the proof for the entries [start, end) of store
+// against cp, which must be a checkpoint of store covering end.
+func LedgerSegmentProof(ctx context.Context, store LedgerStore, start, end int64, cp *Checkpoint) (*SegmentProof, error) {
+	if start < 0 || end <= start || end > cp.TreeSize {
+		return nil, fmt.Errorf("segment proof: range [%d, %d) outside checkpoint size %d", start, end, cp.TreeSize)
+	}
+	summary, err := latestPruneSummary(ctx, store)
+	if err != nil {
+		return nil, fmt.Errorf("segment proof: %w", err)
+	}
+	if summary != nil && start < summary.PrunedSize {
+		return nil, fmt.Errorf("segment proof: entry %d: %w", start, ErrLedgerEntryPruned)
+	}
+	tree, err := checkpointTree(ctx, store, summary, cp)
+	if err != nil {
+		return nil, fmt.Errorf("segment proof: %w", err)
+	}
+	inclusion, err := tree.ProofAt(int(end - 1))
+	if err != nil {
+		return nil, fmt.Errorf("segment proof: %w", err)
+	}
+	return &SegmentProof{Start: start, TreeSize: cp.TreeSize, Inclusion: inclusion}, nil
+}
+
+// VerifySegment checks that entries are the ledger entries at proof.Start
+// onwards in the tree committed to by cp. cp is trusted as given; check its
+// signature first.
+func VerifySegment(cp *Checkpoint, entries []AuditEntry, proof *SegmentProof) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("segment: no entries")
+	}
+	if proof.TreeSize != cp.TreeSize {
+		return fmt.Errorf("segment: proof is for tree size %d, checkpoint has %d", proof.TreeSize, cp.TreeSize)
+	}
+	last := proof.Start + int64(len(entries)) - 1
+	if proof.Start < 0 || last >= cp.TreeSize {
+		return fmt.Errorf("segment: entries %d..%d outside checkpoint size %d", proof.Start, last, cp.TreeSize)
+	}
+	if proof.Start == 0 && entries[0].PrevHash != "GENESIS" {
+		return fmt.Errorf("segment: entry 0 must start the chain with GENESIS")
+	}
+	var h string
+	for i, e := range entries {
+		if i > 0 && e.PrevHash != h {
+			return fmt.Errorf("prev_hash chain (entry %d): expected %s, got %s", proof.Start+int64(i), h, e.PrevHash)
+		}
+		var err error
+		if h, err = HashObject(e); err != nil {
+			return fmt.Errorf("segment: %w", err)
+		}
+	}
+	if !verifyInclusionAt(h, int(last), int(cp.TreeSize), proof.Inclusion, cp.RootHash) {
+		return fmt.Errorf("segment: entry %d is not included in checkpoint %d", last, cp.TreeSize)
+	}
+	return nil
+}
+
+// verifyInclusionAt is VerifyMerkleProof that also checks the proof's shape
+// against leaf index in a tree of size leaves, so a valid proof for one
+// position cannot be presented for another.
+func verifyInclusionAt(leafHex string, index, size int, proof []MerkleProofStep, root string) bool {
+	if index < 0 || index >= size {
+		return false
+	}
+	current := leafHex
+	idx, width := index, size
+	for _, step := range proof {
+		if width == 1 {
+			return false
+		}
+		var err error
+		switch {
+		case idx%2 == 1:
+			if step.Direction != "left" {
+				return false
+			}
+			current, err = merkleParent(step.Hash, current)
+		case idx+1 < width:
+			if step.Direction != "right" {
+				return false
+			}
+			current, err = merkleParent(current, step.Hash)
+		default:
+			// The last node of an odd layer is paired with itself.
+			if step.Direction != "right" || step.Hash != current {
+				return false
+			}
+			current, err = merkleParent(current, current)
+		}
+		if err != nil {
+			return false
+		}
+		idx /= 2
+		width = (width + 1) / 2
+	}
+	return width == 1 && current == strings.TrimPrefix(root, "sha256:")
+}
+
+// VerifyBundleAgainstCheckpoint verifies an archived signed bundle with
+// VerifySignedBundle and checks that its audit entries sit in the ledger
+// committed to by cp, using a proof from LedgerSegmentProof.
+func VerifyBundleAgainstCheckpoint(sb *SignedBundle, publicKeyB64 string, cp *Checkpoint, proof *SegmentProof) *VerificationResult {
+	if r := VerifySignedBundle(sb, publicKeyB64); !r.Verified {
+		return r
+	}
+	if err := VerifySegment(cp, sb.Bundle.AuditEntries, proof); err != nil {
+		return &VerificationResult{Verified: false, Errors: []string{err.Error()}}
+	}
+	return &VerificationResult{Verified: true}
+}
+
+// ReadCheckpointFile reads a JSON checkpoint written by WriteCheckpointFile.
+// If publicKeyB64 is non-empty the checkpoint's signature must verify.
+func ReadCheckpointFile(path, publicKeyB64 string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("checkpoint file %s: %w", path, err)
+	}
+	if publicKeyB64 != "" {
+		if ok, err := cp.Verify(publicKeyB64); err != nil || !ok {
+			return nil, fmt.Errorf("checkpoint file %s: signature invalid", path)
+		}
+	}
+	return &cp, nil
+}
+
+// WriteCheckpointFile writes cp as indented JSON.
+func WriteCheckpointFile(path string, cp *Checkpoint) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,100 @@
+package dcp_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/ledgertest"
+)
+
+func TestVerifySegmentFromCheckpointFile(t *testing.T) {
+	ctx := context.Background()
+	agent, _ := dcp.GenerateKeypair()
+	operator, _ := dcp.GenerateKeypair()
+	store := dcp.NewMemoryLedger()
+	for i := 0; i < 11; i++ {
+		if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, store, ledgertest.Entry(i), agent.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cp, err := dcp.CheckpointLedger(ctx, store, "agent001", operator.SecretKeyB64, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	if err := dcp.WriteCheckpointFile(path, cp); err != nil {
+		t.Fatal(err)
+	}
+
+	// Everything below uses only the checkpoint file and the archived data.
+	trusted, err := dcp.ReadCheckpointFile(path, operator.PublicKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dcp.ReadCheckpointFile(path, agent.PublicKeyB64); err == nil {
+		t.Fatal("checkpoint file should not verify under another key")
+	}
+	for _, r := range [][2]int64{{0, 11}, {3, 7}, {10, 11}, {0, 1}} {
+		proof, err := dcp.LedgerSegmentProof(ctx, store, r[0], r[1], cp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, _ := dcp.LedgerEntries(ctx, store, r[0], r[1])
+		if err := dcp.VerifySegment(trusted, entries, proof); err != nil {
+			t.Fatalf("segment %v: %v", r, err)
+		}
+	}
+
+	proof, _ := dcp.LedgerSegmentProof(ctx, store, 3, 7, cp)
+	entries, _ := dcp.LedgerEntries(ctx, store, 3, 7)
+	shifted := *proof
+	shifted.Start = 2
+	if err := dcp.VerifySegment(trusted, entries, &shifted); err == nil {
+		t.Fatal("proof presented for the wrong position should fail")
+	}
+	if err := dcp.VerifySegment(trusted, entries[:3], proof); err == nil {
+		t.Fatal("truncated segment should fail")
+	}
+	entries[1].Outcome = "failed"
+	if err := dcp.VerifySegment(trusted, entries, proof); err == nil {
+		t.Fatal("tampered segment should fail")
+	}
+}
+
+func TestVerifyBundleAgainstCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	owner, _ := dcp.GenerateKeypair()
+	first := anchoredBundle(t, owner, "agent001", nil, "audit001", "audit002")
+	anchor, err := dcp.ChainAnchorFor(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := anchoredBundle(t, owner, "agent001", anchor, "audit003", "audit004", "audit005")
+	store := dcp.NewMemoryLedger()
+	for _, sb := range []*dcp.SignedBundle{first, second} {
+		for _, e := range sb.Bundle.AuditEntries {
+			if _, err := store.AppendEntry(ctx, e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	cp, err := dcp.CheckpointLedger(ctx, store, "agent001", owner.SecretKeyB64, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstProof, _ := dcp.LedgerSegmentProof(ctx, store, 0, 2, cp)
+	secondProof, _ := dcp.LedgerSegmentProof(ctx, store, 2, 5, cp)
+
+	if r := dcp.VerifyBundleAgainstCheckpoint(first, owner.PublicKeyB64, cp, firstProof); !r.Verified {
+		t.Fatalf("first bundle: %v", r.Errors)
+	}
+	if r := dcp.VerifyBundleAgainstCheckpoint(second, owner.PublicKeyB64, cp, secondProof); !r.Verified {
+		t.Fatalf("second bundle: %v", r.Errors)
+	}
+	if r := dcp.VerifyBundleAgainstCheckpoint(second, owner.PublicKeyB64, cp, firstProof); r.Verified {
+		t.Fatal("bundle verified with another segment's proof")
+	}
+}
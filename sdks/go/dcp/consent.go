@@ -0,0 +1,152 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrConsentRequired is returned for an intent with requires_consent set
+// that comes without a consent record.
+var ErrConsentRequired = errors.New("consent required")
+
+// ErrConsentInvalid is returned for a consent record that does not grant
+// consent to the intent it comes with.
+var ErrConsentInvalid = errors.New("consent invalid")
+
+// NewConsentRecord returns the principal's unsigned consent to intent,
+// granted now and expiring after ttl. A nil scope consents to the intent's
+// own data classes.
+func (f *RecordFactory) NewConsentRecord(intent Intent, scope []string, ttl time.Duration) (ConsentRecord, error) {
+	intentHash, err := HashObject(intent)
+	if err != nil {
+		return ConsentRecord{}, fmt.Errorf("consent: intent hash: %w", err)
+	}
+	if scope == nil {
+		scope = cloneStrings(intent.DataClasses)
+	}
+	now := f.now()
+	return ConsentRecord{
+		DCPVersion: DCPVersion,
+		HumanID:    intent.HumanID,
+		IntentHash: intentHash,
+		Scope:      scope,
+		GrantedAt:  FormatTime(now),
+		ExpiresAt:  FormatTime(now.Add(ttl)),
+	}, nil
+}
+
+// NewConsentRecord calls RecordFactory.NewConsentRecord with the package
+// clock.
+func NewConsentRecord(intent Intent, scope []string, ttl time.Duration) (ConsentRecord, error) {
+	return defaultRecords.NewConsentRecord(intent, scope, ttl)
+}
+
+// Sign sets Signature to s's signature over the canonical record with an
+// empty signature. Consent is signed by the responsible principal.
+func (c *ConsentRecord) Sign(s BundleSigner) error {
+	c.Signature = ""
+	sig, err := signWith(s, c)
+	if err != nil {
+		return fmt.Errorf("sign consent of %s: %w", c.HumanID, err)
+	}
+	c.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against the principal's public key.
+func (c *ConsentRecord) VerifySignature(publicKeyB64 string) (bool, error) {
+	if c.Signature == "" {
+		return false, fmt.Errorf("consent of %s has no signature", c.HumanID)
+	}
+	unsigned := c.Clone()
+	unsigned.Signature = ""
+	return VerifyObject(unsigned, c.Signature, publicKeyB64)
+}
+
+// Check reports whether c grants consent to intent: it is signed with the
+// principal's key publicKeyB64, is the intent's principal's, names the
+// intent by its hash, covers its data classes and held when the intent was
+// declared. Errors wrap ErrConsentInvalid.
+func (c *ConsentRecord) Check(intent *Intent, publicKeyB64 string) error {
+	intentHash, err := HashObject(intent)
+	if err != nil {
+		return fmt.Errorf("%w: intent hash: %v", ErrConsentInvalid, err)
+	}
+	return c.check(intent, intentHash, publicKeyB64)
+}
+
+func (c *ConsentRecord) check(intent *Intent, intentHash, publicKeyB64 string) error {
+	if ok, err := c.VerifySignature(publicKeyB64); err != nil || !ok {
+		if err == nil {
+			err = errors.New("signature does not verify")
+		}
+		return fmt.Errorf("%w: %v", ErrConsentInvalid, err)
+	}
+	if c.HumanID != intent.HumanID {
+		return fmt.Errorf("%w: consent of %s, not of the intent's principal %s", ErrConsentInvalid, c.HumanID, intent.HumanID)
+	}
+	if c.IntentHash != intentHash {
+		return fmt.Errorf("%w: intent_hash %s, not the intent's %s", ErrConsentInvalid, c.IntentHash, intentHash)
+	}
+	for _, class := range intent.DataClasses {
+		if !oneOf(class, c.Scope) {
+			return fmt.Errorf("%w: scope does not cover %s", ErrConsentInvalid, class)
+		}
+	}
+	declared, err := intent.TimestampTime()
+	if err != nil {
+		return fmt.Errorf("%w: intent %v", ErrConsentInvalid, err)
+	}
+	granted, err := ParseTime(c.GrantedAt)
+	if err != nil {
+		return fmt.Errorf("%w: granted_at: %v", ErrConsentInvalid, err)
+	}
+	expires, err := ParseTime(c.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%w: expires_at: %v", ErrConsentInvalid, err)
+	}
+	if declared.Before(granted) || !declared.Before(expires) {
+		return fmt.Errorf("%w: intent declared at %s, outside the consent from %s to %s",
+			ErrConsentInvalid, intent.Timestamp, c.GrantedAt, c.ExpiresAt)
+	}
+	return nil
+}
+
+// Validate checks c against the consent schema and returns ValidationErrors
+// listing every violation, or nil.
+func (c *ConsentRecord) Validate() error {
+	v := newValidator()
+	c.validate(v)
+	return v.err()
+}
+
+func (c *ConsentRecord) validate(v validator) {
+	v.version("dcp_version", c.DCPVersion)
+	v.idOf("human_id", c.HumanID, IDKindHuman)
+	v.minLen("intent_hash", c.IntentHash, 8)
+	if len(c.Scope) == 0 {
+		v.at("scope").fail("must contain at least one data class")
+	}
+	for i, class := range c.Scope {
+		v.at("scope").index(i).enum("", class, dataClasses)
+	}
+	v.timestamp("granted_at", c.GrantedAt)
+	v.timestamp("expires_at", c.ExpiresAt)
+	v.signature("signature", c.Signature)
+}
+
+// Clone returns a deep copy of c.
+func (c *ConsentRecord) Clone() *ConsentRecord {
+	if c == nil {
+		return nil
+	}
+	d := *c
+	d.Scope = cloneStrings(c.Scope)
+	return &d
+}
+
+// Equal reports whether c and o canonicalize identically.
+func (c *ConsentRecord) Equal(o *ConsentRecord) bool {
+	return (c == nil) == (o == nil) && (c == nil || canonicalEqual(c, o))
+}
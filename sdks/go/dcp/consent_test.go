@@ -0,0 +1,120 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func consentIntent() dcp.Intent {
+	yes := true
+	return dcp.Intent{DCPVersion: "1.0", IntentID: "intent001", AgentID: "agent001", HumanID: "human001",
+		Timestamp: "2026-01-01T01:00:00Z", ActionType: "send_email", Target: dcp.IntentTarget{Channel: "email"},
+		DataClasses: []string{"contact_info"}, EstimatedImpact: "medium", RequiresConsent: &yes}
+}
+
+func TestConsentVerification(t *testing.T) {
+	f := dcp.RecordFactory{Clock: func() time.Time { return time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC) }}
+	intent := consentIntent()
+	consent, err := f.NewConsentRecord(intent, nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if consent.ExpiresAt != "2026-01-01T01:30:00Z" || strings.Join(consent.Scope, ",") != "contact_info" {
+		t.Fatalf("consent = %+v", consent)
+	}
+
+	b, human, _ := builderFixture(t)
+	b.Intent(intent)
+	sb, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := dcp.VerifySignedBundle(sb, human.PublicKeyB64)
+	if r.Verified || !strings.Contains(r.Errors[0], "consent required") {
+		t.Fatalf("without consent: %+v", r)
+	}
+
+	sb, err = b.Consent(consent).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := dcp.VerifySignedBundle(sb, human.PublicKeyB64); !r.Verified {
+		t.Fatalf("with consent: %v", r.Errors)
+	}
+	if err := sb.Bundle.Consent.Check(&sb.Bundle.Intent, human.PublicKeyB64); err != nil {
+		t.Fatal(err)
+	}
+	// The raw path, after a strict parse, agrees.
+	data, _ := json.Marshal(sb)
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := dcp.VerifyRawSignedBundle(rsb, human.PublicKeyB64); !r.Verified {
+		t.Fatalf("raw: %v", r.Errors)
+	}
+
+	other, _ := dcp.GenerateKeypair()
+	for _, tc := range []struct {
+		name   string
+		mutate func(c *dcp.ConsentRecord)
+		want   string
+	}{
+		{"another principal", func(c *dcp.ConsentRecord) { c.HumanID = "human002" }, "not of the intent's principal"},
+		{"another intent", func(c *dcp.ConsentRecord) { c.IntentHash = strings.Repeat("0", 64) }, "intent_hash"},
+		{"narrower scope", func(c *dcp.ConsentRecord) { c.Scope = []string{"none"} }, "does not cover contact_info"},
+		{"expired", func(c *dcp.ConsentRecord) { c.ExpiresAt = "2026-01-01T01:00:00Z" }, "outside the consent"},
+		{"granted later", func(c *dcp.ConsentRecord) { c.GrantedAt = "2026-01-01T01:10:00Z" }, "outside the consent"},
+	} {
+		c := consent
+		tc.mutate(&c)
+		sb, err := b.Consent(c).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := dcp.VerifySignedBundleWithOptions(sb, dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, Explain: true})
+		if r.Verified || !strings.Contains(r.Errors[0], tc.want) {
+			t.Errorf("%s: %v", tc.name, r.Errors)
+			continue
+		}
+		if last := r.Trace[len(r.Trace)-1]; last.Check != dcp.TraceConsent || last.OK {
+			t.Errorf("%s: trace %+v", tc.name, last)
+		}
+	}
+
+	// Consent signed by another key than the principal's is refused.
+	otherSigner, _ := dcp.NewKeySigner(other.SecretKeyB64)
+	forged := consent
+	if err := forged.Sign(otherSigner); err != nil {
+		t.Fatal(err)
+	}
+	if err := forged.Check(&intent, human.PublicKeyB64); !errors.Is(err, dcp.ErrConsentInvalid) {
+		t.Fatalf("forged: %v", err)
+	}
+}
+
+func TestConsentRecordValidate(t *testing.T) {
+	c := dcp.ConsentRecord{DCPVersion: "1.0", HumanID: "human001", IntentHash: strings.Repeat("a", 64),
+		Scope: []string{"pii", "secrets"}, GrantedAt: "2026-01-01T00:00:00Z", ExpiresAt: "tomorrow"}
+	var verrs dcp.ValidationErrors
+	if err := c.Validate(); !errors.As(err, &verrs) {
+		t.Fatalf("err = %v", err)
+	}
+	var got []string
+	for _, fe := range verrs {
+		got = append(got, fe.Pointer)
+	}
+	if strings.Join(got, " ") != "/scope/1 /expires_at" {
+		t.Fatalf("pointers = %v", got)
+	}
+	d := c.Clone()
+	d.Scope[0] = "none"
+	if c.Scope[0] != "pii" || c.Equal(d) {
+		t.Fatal("clone shares its scope")
+	}
+}
@@ -2,7 +2,6 @@ package dcp
 
 import (
 	"crypto/ed25519"
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
@@ -63,9 +62,10 @@ type Keypair struct {
 	SecretKeyB64  string
 }
 
-// GenerateKeypair creates a new Ed25519 keypair.
+// GenerateKeypair creates a new Ed25519 keypair from the package entropy
+// source (see SetEntropy).
 func GenerateKeypair() (*Keypair, error) {
-	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	pub, priv, err := ed25519.GenerateKey(entropy())
 	if err != nil {
 		return nil, err
 	}
@@ -113,6 +113,18 @@ func VerifyObject(obj interface{}, signatureB64, publicKeyB64 string) (bool, err
 		tel.EndSpanWith(spanID, observability.SpanError, err.Error())
 		return false, fmt.Errorf("canonicalize: %w", err)
 	}
+	return verifyCanonical(tel, spanID, start, canon, signatureB64, publicKeyB64)
+}
+
+// VerifyCanonical verifies an Ed25519 detached signature over an already
+// canonicalized JSON document, e.g. the output of CanonicalizeJSON.
+func VerifyCanonical(canon string, signatureB64, publicKeyB64 string) (bool, error) {
+	tel := observability.Default()
+	spanID := tel.StartSpan("dcp.verify", map[string]interface{}{"algorithm": "ed25519"})
+	return verifyCanonical(tel, spanID, time.Now(), canon, signatureB64, publicKeyB64)
+}
+
+func verifyCanonical(tel *observability.Telemetry, spanID string, start time.Time, canon, signatureB64, publicKeyB64 string) (bool, error) {
 	sig, err := base64.StdEncoding.DecodeString(signatureB64)
 	if err != nil {
 		tel.RecordError("verify", err.Error())
@@ -125,12 +137,28 @@ func VerifyObject(obj interface{}, signatureB64, publicKeyB64 string) (bool, err
 		tel.EndSpanWith(spanID, observability.SpanError, err.Error())
 		return false, fmt.Errorf("decode public key: %w", err)
 	}
+	if len(pk) != ed25519.PublicKeySize {
+		err := fmt.Errorf("invalid public key length: got %d, want %d", len(pk), ed25519.PublicKeySize)
+		tel.RecordError("verify", err.Error())
+		tel.EndSpanWith(spanID, observability.SpanError, err.Error())
+		return false, err
+	}
 	ok := ed25519.Verify(ed25519.PublicKey(pk), []byte(canon), sig)
 	tel.RecordVerifyLatency(float64(time.Since(start).Microseconds())/1000.0, "ed25519")
 	tel.EndSpan(spanID)
 	return ok, nil
 }
 
+// CanonicalizeJSON canonicalizes raw JSON bytes without first decoding them
+// into a Go struct, so members unknown to this SDK are preserved.
+func CanonicalizeJSON(data []byte) (string, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return "", err
+	}
+	return Canonicalize(raw)
+}
+
 // HashObject computes SHA-256 of canonical JSON. Returns hex string.
 func HashObject(obj interface{}) (string, error) {
 	canon, err := Canonicalize(obj)
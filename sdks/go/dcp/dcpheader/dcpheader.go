@@ -0,0 +1,388 @@
+// Package dcpheader encodes an agent's identity and the intent a request
+// serves in two compact HTTP headers, a lighter alternative to sending a
+// signed bundle with every request:
+//
+//	DCP-Agent:  id="<agent_id>", passport="<hex SHA-256 of the passport>"
+//	DCP-Intent: id="<intent_id>", hash="<hex SHA-256 of the intent>", issued="<RFC 3339>", sig=:<base64>:
+//
+// DCP-Agent references the agent's registered passport by hash rather than
+// carrying it; the receiving site resolves it from a registry. DCP-Intent
+// is a grpcauth.IntentReference, whose detached signature by the agent key
+// covers the intent's ID and hash, the agent ID and the issue time, so it
+// also proves, within the accepted skew, that the caller holds the key.
+//
+// Agents set both headers with an Encoder, directly or through its
+// Transport; sites check them with a Decoder's Middleware:
+//
+//	d, _ := dcpheader.NewDecoder(dcpheader.Config{Authenticator: auth, Passports: &registry.Client{URL: registryURL}})
+//	mux.Handle("POST /v1/orders", d.Middleware(orders))
+package dcpheader
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcauth"
+)
+
+// Headers.
+const (
+	AgentHeader  = "DCP-Agent"
+	IntentHeader = "DCP-Intent"
+)
+
+// ErrInvalidIntent reports a DCP-Intent header that is malformed, not
+// signed by the agent key, or outside the accepted skew.
+var ErrInvalidIntent = errors.New("invalid DCP-Intent header")
+
+// AgentRef is the content of a DCP-Agent header.
+type AgentRef struct {
+	AgentID      string
+	PassportHash string
+}
+
+// NewAgentRef returns the reference to passport p.
+func NewAgentRef(p *dcp.AgentPassport) (*AgentRef, error) {
+	hash, err := dcp.HashObject(p)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentRef{AgentID: p.AgentID, PassportHash: hash}, nil
+}
+
+// String returns r as a DCP-Agent header value.
+func (r *AgentRef) String() string {
+	return "id=" + strconv.Quote(r.AgentID) + ", passport=" + strconv.Quote(r.PassportHash)
+}
+
+// Matches reports whether r refers to p.
+func (r *AgentRef) Matches(p *dcp.AgentPassport) bool {
+	hash, err := dcp.HashObject(p)
+	return err == nil && p.AgentID == r.AgentID && hash == r.PassportHash
+}
+
+// ParseAgent parses a DCP-Agent header value.
+func ParseAgent(value string) (*AgentRef, error) {
+	members, err := parseMembers(value)
+	if err != nil {
+		return nil, err
+	}
+	r := &AgentRef{AgentID: members["id"], PassportHash: members["passport"]}
+	if r.AgentID == "" || r.PassportHash == "" {
+		return nil, errors.New("id and passport are required")
+	}
+	return r, nil
+}
+
+// EncodeIntent returns ref as a DCP-Intent header value. The agent ID is
+// left to the DCP-Agent header.
+func EncodeIntent(ref *grpcauth.IntentReference) string {
+	return "id=" + strconv.Quote(ref.IntentID) +
+		", hash=" + strconv.Quote(ref.IntentHash) +
+		", issued=" + strconv.Quote(ref.IssuedAt) +
+		", sig=:" + ref.Signature + ":"
+}
+
+// ParseIntent parses a DCP-Intent header value sent by agentID.
+func ParseIntent(value, agentID string) (*grpcauth.IntentReference, error) {
+	members, err := parseMembers(value)
+	if err != nil {
+		return nil, err
+	}
+	ref := &grpcauth.IntentReference{
+		IntentID:   members["id"],
+		IntentHash: members["hash"],
+		AgentID:    agentID,
+		IssuedAt:   members["issued"],
+		Signature:  members["sig"],
+	}
+	if ref.IntentID == "" || ref.IntentHash == "" || ref.IssuedAt == "" || ref.Signature == "" {
+		return nil, errors.New("id, hash, issued and sig are required")
+	}
+	return ref, nil
+}
+
+// parseMembers parses a comma-separated list of key="string" and
+// key=:base64: members. Byte sequences are returned in standard base64.
+func parseMembers(value string) (map[string]string, error) {
+	members := map[string]string{}
+	rest := strings.TrimSpace(value)
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("member %q has no value", rest)
+		}
+		key := strings.TrimSpace(rest[:eq])
+		rest = rest[eq+1:]
+		var v string
+		switch {
+		case strings.HasPrefix(rest, `"`):
+			end := closingQuote(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("%s: unterminated string", key)
+			}
+			s, err := strconv.Unquote(rest[:end+1])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", key, err)
+			}
+			v, rest = s, rest[end+1:]
+		case strings.HasPrefix(rest, ":"):
+			end := strings.IndexByte(rest[1:], ':')
+			if end < 0 {
+				return nil, fmt.Errorf("%s: unterminated byte sequence", key)
+			}
+			raw, err := base64.StdEncoding.DecodeString(rest[1 : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("%s: not base64", key)
+			}
+			v, rest = base64.StdEncoding.EncodeToString(raw), rest[end+2:]
+		default:
+			return nil, fmt.Errorf("%s: not a string or byte sequence", key)
+		}
+		members[key] = v
+		rest = strings.TrimSpace(rest)
+		if rest != "" {
+			if rest[0] != ',' {
+				return nil, fmt.Errorf("unexpected %q", rest)
+			}
+			rest = strings.TrimSpace(rest[1:])
+		}
+	}
+	return members, nil
+}
+
+// closingQuote returns the index of the quote closing the string s starts
+// with, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// Encoder sets the DCP headers on an agent's requests.
+type Encoder struct {
+	Passport *dcp.AgentPassport
+	// Signer is the agent key the passport carries; it signs intents.
+	Signer dcp.BundleSigner
+	// Now is the clock intent declarations are dated with; nil means
+	// time.Now.
+	Now func() time.Time
+}
+
+// Encode sets the DCP-Agent header of req and, if intent is not nil, the
+// DCP-Intent header declaring it.
+func (e *Encoder) Encode(req *http.Request, intent *dcp.Intent) error {
+	if e.Passport == nil {
+		return errors.New("dcpheader: a passport is required")
+	}
+	ref, err := NewAgentRef(e.Passport)
+	if err != nil {
+		return fmt.Errorf("dcpheader: passport: %v", err)
+	}
+	req.Header.Set(AgentHeader, ref.String())
+	if intent == nil {
+		req.Header.Del(IntentHeader)
+		return nil
+	}
+	if e.Signer == nil {
+		return errors.New("dcpheader: a signer is required to declare intents")
+	}
+	now := time.Now
+	if e.Now != nil {
+		now = e.Now
+	}
+	iref, err := grpcauth.NewIntentReference(intent, e.Signer, now())
+	if err != nil {
+		return fmt.Errorf("dcpheader: intent: %v", err)
+	}
+	req.Header.Set(IntentHeader, EncodeIntent(iref))
+	return nil
+}
+
+type intentKey struct{}
+
+// WithIntent returns ctx under which the Transport declares intent.
+func WithIntent(ctx context.Context, intent *dcp.Intent) context.Context {
+	return context.WithValue(ctx, intentKey{}, intent)
+}
+
+// Transport returns a RoundTripper setting the DCP headers on each request
+// before sending it with base; nil means http.DefaultTransport. The intent
+// declared is the one of the request's context, set with WithIntent.
+func (e *Encoder) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{enc: e, base: base}
+}
+
+type transport struct {
+	enc  *Encoder
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	intent, _ := req.Context().Value(intentKey{}).(*dcp.Intent)
+	// A RoundTripper must not modify the caller's request.
+	req = req.Clone(req.Context())
+	if err := t.enc.Encode(req, intent); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Config configures a Decoder.
+type Config struct {
+	// Authenticator checks the referenced passport. Required.
+	Authenticator *agentauth.Authenticator
+	// Passports resolves the referenced passport. Required.
+	Passports agentauth.PassportSource
+	// RequireIntent refuses requests without a valid DCP-Intent header.
+	RequireIntent bool
+	// MaxSkew bounds the age of intent declarations; zero means
+	// grpcauth.DefaultMaxSkew.
+	MaxSkew time.Duration
+	// Now is the clock intent declarations are checked against; nil means
+	// time.Now.
+	Now func() time.Time
+}
+
+// Declared is what a request's DCP headers established.
+type Declared struct {
+	Identity *agentauth.Identity
+	// Intent is the declared intent, if the request carried one.
+	Intent *grpcauth.IntentReference
+}
+
+type contextKey struct{}
+
+// FromContext returns what the Middleware admitted the request with, if
+// it did.
+func FromContext(ctx context.Context) (*Declared, bool) {
+	d, ok := ctx.Value(contextKey{}).(*Declared)
+	return d, ok
+}
+
+// Decoder checks the DCP headers of incoming requests. Create one with
+// NewDecoder.
+type Decoder struct {
+	cfg Config
+}
+
+// NewDecoder returns a Decoder for cfg.
+func NewDecoder(cfg Config) (*Decoder, error) {
+	if cfg.Authenticator == nil || cfg.Passports == nil {
+		return nil, errors.New("dcpheader: an authenticator and a passport source are required")
+	}
+	if cfg.MaxSkew <= 0 {
+		cfg.MaxSkew = grpcauth.DefaultMaxSkew
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Decoder{cfg: cfg}, nil
+}
+
+// Decode checks the DCP headers of r. Its errors wrap ErrInvalidIntent or
+// the agentauth errors.
+func (d *Decoder) Decode(r *http.Request) (*Declared, error) {
+	value := strings.TrimSpace(r.Header.Get(AgentHeader))
+	if value == "" {
+		return nil, agentauth.ErrNoPassport
+	}
+	ref, err := ParseAgent(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", agentauth.ErrInvalidPassport, AgentHeader, err)
+	}
+	p, err := d.cfg.Passports.Passport(r.Context(), ref.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: registry: %v", agentauth.ErrLookup, err)
+	}
+	if p == nil {
+		return nil, fmt.Errorf("%w: no passport is registered for %s", agentauth.ErrInvalidPassport, ref.AgentID)
+	}
+	if !ref.Matches(p) {
+		return nil, fmt.Errorf("%w: %s does not reference the registered passport of %s", agentauth.ErrInvalidPassport, AgentHeader, ref.AgentID)
+	}
+	id, err := d.cfg.Authenticator.Check(r.Context(), p, r.TLS)
+	if err != nil {
+		return nil, err
+	}
+	declared := &Declared{Identity: id}
+
+	value = strings.TrimSpace(r.Header.Get(IntentHeader))
+	if value == "" {
+		if d.cfg.RequireIntent {
+			return nil, fmt.Errorf("%w: none presented", ErrInvalidIntent)
+		}
+		return declared, nil
+	}
+	if declared.Intent, err = d.checkIntent(value, p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIntent, err)
+	}
+	return declared, nil
+}
+
+func (d *Decoder) checkIntent(value string, p *dcp.AgentPassport) (*grpcauth.IntentReference, error) {
+	ref, err := ParseIntent(value, p.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ref.Verify(p.PublicKey); err != nil {
+		return nil, err
+	}
+	issued, err := dcp.ParseTime(ref.IssuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("issued: %v", err)
+	}
+	if skew := d.cfg.Now().Sub(issued); skew > d.cfg.MaxSkew || skew < -d.cfg.MaxSkew {
+		return nil, fmt.Errorf("issued at %s, outside the accepted %s", ref.IssuedAt, d.cfg.MaxSkew)
+	}
+	return ref, nil
+}
+
+// Middleware admits requests whose DCP headers check, with the Declared
+// agent and intent in their context along with the agent's
+// agentauth.Identity, and answers the others like agentauth's Middleware:
+// 401, 403 (inactive passport) or 503 (lookup failure).
+func (d *Decoder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		declared, err := d.Decode(r)
+		if err != nil {
+			switch {
+			case errors.Is(err, agentauth.ErrInactive):
+				writeError(w, http.StatusForbidden, err.Error())
+			case errors.Is(err, agentauth.ErrLookup):
+				writeError(w, http.StatusServiceUnavailable, err.Error())
+			default:
+				w.Header().Set("WWW-Authenticate", `DCP header="`+AgentHeader+`"`)
+				writeError(w, http.StatusUnauthorized, err.Error())
+			}
+			return
+		}
+		ctx := agentauth.NewContext(r.Context(), declared.Identity)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, contextKey{}, declared)))
+	})
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,158 @@
+package dcpheader_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcpheader"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+)
+
+// agent returns an Encoder for a fresh self-signed agent and an intent of
+// the agent.
+func agent(t *testing.T) (*dcpheader.Encoder, *dcp.Intent) {
+	t.Helper()
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	return &dcpheader.Encoder{Passport: p, Signer: signer}, dcptest.Intent(t, p)
+}
+
+func newDecoder(t *testing.T, cfg dcpheader.Config) *dcpheader.Decoder {
+	t.Helper()
+	cfg.Authenticator = dcptest.Authenticator(t, agentauth.Config{})
+	d, err := dcpheader.NewDecoder(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func encoded(t *testing.T, enc *dcpheader.Encoder, intent *dcp.Intent) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders", nil)
+	if err := enc.Encode(req, intent); err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestParse(t *testing.T) {
+	enc, intent := agent(t)
+	req := encoded(t, enc, intent)
+	ref, err := dcpheader.ParseAgent(req.Header.Get(dcpheader.AgentHeader))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ref.Matches(enc.Passport) {
+		t.Fatalf("agent reference %+v does not match its passport", ref)
+	}
+	iref, err := dcpheader.ParseIntent(req.Header.Get(dcpheader.IntentHeader), enc.Passport.AgentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !iref.Matches(intent) || iref.Verify(enc.Passport.PublicKey) != nil {
+		t.Fatalf("intent declaration %+v", iref)
+	}
+	for _, bad := range []string{``, `id="a"`, `id=a, passport="b"`, `id="a", passport="b`, `id="a" passport="b"`} {
+		if _, err := dcpheader.ParseAgent(bad); err == nil {
+			t.Errorf("ParseAgent(%q) succeeded", bad)
+		}
+	}
+	if _, err := dcpheader.ParseIntent(`id="i", hash="h", issued="t", sig=:not base64:`, "a"); err == nil {
+		t.Error("ParseIntent accepted a signature that is not base64")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	enc, intent := agent(t)
+	unregistered, _ := agent(t)
+	registered := grpcserver.PassportMap{enc.Passport.AgentID: *enc.Passport}
+	d := newDecoder(t, dcpheader.Config{Passports: registered})
+	ts := httptest.NewServer(d.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		declared, _ := dcpheader.FromContext(r.Context())
+		id, _ := agentauth.FromContext(r.Context())
+		got := map[string]string{"agent_id": id.AgentID()}
+		if declared.Intent != nil {
+			got["intent_id"] = declared.Intent.IntentID
+		}
+		json.NewEncoder(w).Encode(got)
+	})))
+	defer ts.Close()
+
+	call := func(enc *dcpheader.Encoder, withIntent bool) (int, map[string]string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+		if withIntent {
+			req = req.WithContext(dcpheader.WithIntent(req.Context(), intent))
+		}
+		resp, err := (&http.Client{Transport: enc.Transport(nil)}).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var got map[string]string
+		json.NewDecoder(resp.Body).Decode(&got)
+		return resp.StatusCode, got
+	}
+	if code, got := call(enc, true); code != http.StatusOK || got["agent_id"] != enc.Passport.AgentID || got["intent_id"] != intent.IntentID {
+		t.Fatalf("with intent: %d %v", code, got)
+	}
+	if code, got := call(enc, false); code != http.StatusOK || got["intent_id"] != "" {
+		t.Fatalf("without intent: %d %v", code, got)
+	}
+	if code, got := call(unregistered, false); code != http.StatusUnauthorized {
+		t.Fatalf("unregistered agent: %d %v", code, got)
+	}
+
+	// A passport the agent re-issued without registering it.
+	stale := *enc
+	reissued := *enc.Passport
+	reissued.Capabilities = []string{"browse", "email"}
+	if err := reissued.Sign(enc.Signer); err != nil {
+		t.Fatal(err)
+	}
+	stale.Passport = &reissued
+	if code, got := call(&stale, false); code != http.StatusUnauthorized {
+		t.Fatalf("unregistered passport version: %d %v", code, got)
+	}
+}
+
+func TestIntent(t *testing.T) {
+	enc, intent := agent(t)
+	other, _ := agent(t)
+	now := time.Now()
+	registered := grpcserver.PassportMap{enc.Passport.AgentID: *enc.Passport}
+	d := newDecoder(t, dcpheader.Config{Passports: registered, RequireIntent: true, Now: func() time.Time { return now }})
+
+	if _, err := d.Decode(encoded(t, enc, intent)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Decode(encoded(t, enc, nil)); !errors.Is(err, dcpheader.ErrInvalidIntent) {
+		t.Fatalf("no intent with RequireIntent: %v", err)
+	}
+
+	forged := encoded(t, enc, intent)
+	forged.Header.Set(dcpheader.IntentHeader, encoded(t, other, intent).Header.Get(dcpheader.IntentHeader))
+	if _, err := d.Decode(forged); !errors.Is(err, dcpheader.ErrInvalidIntent) {
+		t.Fatalf("intent signed by another agent: %v", err)
+	}
+
+	altered := encoded(t, enc, intent)
+	altered.Header.Set(dcpheader.IntentHeader, strings.Replace(altered.Header.Get(dcpheader.IntentHeader), intent.IntentID, "intent:other", 1))
+	if _, err := d.Decode(altered); !errors.Is(err, dcpheader.ErrInvalidIntent) {
+		t.Fatalf("altered intent ID: %v", err)
+	}
+
+	stale := *enc
+	stale.Now = func() time.Time { return now.Add(-time.Hour) }
+	if _, err := d.Decode(encoded(t, &stale, intent)); !errors.Is(err, dcpheader.ErrInvalidIntent) {
+		t.Fatalf("stale declaration: %v", err)
+	}
+}
@@ -0,0 +1,345 @@
+// Package dcpjwt carries DCP agent context to services that only speak JWT.
+//
+// An Issuer mints EdDSA (Ed25519) JWTs from a verified passport, with the
+// registered claims and:
+//
+//	dcp_agent_id      the passport's agent_id, also the sub
+//	dcp_human_id      its principal_binding_reference
+//	dcp_risk_tier     its risk_tier
+//	dcp_capabilities  its capabilities
+//
+// and publishes its key as a JWK Set for the services verifying them. A
+// Verifier checks a token's signature, issuer, audience and lifetime and
+// maps the claims back; its Middleware admits requests bearing a valid
+// token, with the Claims in their context.
+package dcpjwt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Algorithm is the JWS alg of the tokens.
+const Algorithm = "EdDSA"
+
+// DefaultTTL is the lifetime of tokens when Issuer.TTL is zero.
+const DefaultTTL = 5 * time.Minute
+
+// ErrInvalidToken reports a token that is malformed, not signed by a
+// trusted key, expired, or for another issuer or audience.
+var ErrInvalidToken = errors.New("invalid DCP JWT")
+
+// Audience is the aud claim, a single string or an array of them.
+type Audience []string
+
+// MarshalJSON encodes a single audience as a string, as is customary.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON accepts a string or an array of strings.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = Audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return errors.New("aud is neither a string nor an array of strings")
+	}
+	*a = many
+	return nil
+}
+
+// Contains reports whether aud is in a.
+func (a Audience) Contains(aud string) bool {
+	for _, have := range a {
+		if have == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// Claims are the claims of a DCP JWT.
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub"`
+	Audience  Audience `json:"aud,omitempty"`
+	IssuedAt  int64    `json:"iat"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	ID        string   `json:"jti,omitempty"`
+
+	AgentID      string       `json:"dcp_agent_id"`
+	HumanID      string       `json:"dcp_human_id"`
+	RiskTier     dcp.RiskTier `json:"dcp_risk_tier,omitempty"`
+	Capabilities []string     `json:"dcp_capabilities,omitempty"`
+}
+
+// HasCapability reports whether the token grants capability, as the
+// capability grammar matches them.
+func (c *Claims) HasCapability(capability string) bool {
+	return dcp.AuthorizeCapability(c.Capabilities, capability) == nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Issuer mints tokens.
+type Issuer struct {
+	// Signer holds the Ed25519 key tokens are signed with. Required.
+	Signer dcp.BundleSigner
+	// Issuer is the iss claim.
+	Issuer string
+	// KeyID is the kid header, naming the key in the JWK Set.
+	KeyID string
+	// TTL is the lifetime of tokens; zero means DefaultTTL.
+	TTL time.Duration
+	// Now is the clock tokens are dated with; nil means time.Now.
+	Now func() time.Time
+}
+
+// Issue returns a token for the agent of passport p, for audience. p must
+// have been verified, for instance by an agentauth.Authenticator; Issue
+// checks only its self-signature and status.
+func (i *Issuer) Issue(p *dcp.AgentPassport, audience ...string) (string, error) {
+	if i.Signer == nil {
+		return "", errors.New("dcpjwt: a signer is required")
+	}
+	if ok, err := p.VerifySignature(); err != nil || !ok {
+		return "", fmt.Errorf("dcpjwt: %s is not signed by its public_key", p.AgentID)
+	}
+	if p.Status != dcp.StatusActive {
+		return "", fmt.Errorf("dcpjwt: %s is %s", p.AgentID, p.Status)
+	}
+	now := time.Now
+	if i.Now != nil {
+		now = i.Now
+	}
+	ttl := i.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	issued := now()
+	claims := &Claims{
+		Issuer:       i.Issuer,
+		Subject:      p.AgentID,
+		Audience:     audience,
+		IssuedAt:     issued.Unix(),
+		ExpiresAt:    issued.Add(ttl).Unix(),
+		ID:           base64.RawURLEncoding.EncodeToString(jti),
+		AgentID:      p.AgentID,
+		HumanID:      p.PrincipalBindingReference,
+		RiskTier:     p.RiskTier,
+		Capabilities: p.Capabilities,
+	}
+	return i.Sign(claims)
+}
+
+// Sign returns claims as a signed token.
+func (i *Issuer) Sign(claims *Claims) (string, error) {
+	h, err := json.Marshal(header{Alg: Algorithm, Typ: "JWT", Kid: i.KeyID})
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	sig, err := i.Signer.SignCanonical(input)
+	if err != nil {
+		return "", fmt.Errorf("dcpjwt: sign: %v", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return "", fmt.Errorf("dcpjwt: sign: %v", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// JWK is an Ed25519 public key in JWK form (RFC 8037).
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+// JWKS returns the JWK Set of the issuer's key.
+func (i *Issuer) JWKS() (map[string][]JWK, error) {
+	raw, err := base64.StdEncoding.DecodeString(i.Signer.PublicKeyB64())
+	if err != nil {
+		return nil, fmt.Errorf("dcpjwt: public key: %v", err)
+	}
+	return map[string][]JWK{"keys": {{
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(raw),
+		Kid: i.KeyID,
+		Alg: Algorithm,
+		Use: "sig",
+	}}}, nil
+}
+
+// JWKSHandler serves the JWK Set, conventionally at
+// /.well-known/jwks.json.
+func (i *Issuer) JWKSHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set, err := i.JWKS()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		json.NewEncoder(w).Encode(set)
+	})
+}
+
+// Verifier checks tokens.
+type Verifier struct {
+	// PublicKeyB64 is the issuer key, used for tokens whose kid Keys does
+	// not hold.
+	PublicKeyB64 string
+	// Keys are issuer keys by kid, for issuers rotating keys.
+	Keys map[string]string
+	// Issuer, if set, must be the iss claim.
+	Issuer string
+	// Audience, if set, must be in the aud claim.
+	Audience string
+	// Leeway is the clock skew tolerated on exp and nbf.
+	Leeway time.Duration
+	// Now is the clock tokens are checked against; nil means time.Now.
+	Now func() time.Time
+}
+
+// Verify checks token and returns its claims. Its errors wrap
+// ErrInvalidToken.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	claims, err := v.verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+func (v *Verifier) verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("not a compact JWS")
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("header: %v", err)
+	}
+	if h.Alg != Algorithm {
+		return nil, fmt.Errorf("alg %q is not %s", h.Alg, Algorithm)
+	}
+	key, ok := v.Keys[h.Kid]
+	if !ok {
+		key = v.PublicKeyB64
+	}
+	if key == "" {
+		return nil, fmt.Errorf("no key for kid %q", h.Kid)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("signature is not base64url")
+	}
+	if ok, err := dcp.VerifyCanonical(parts[0]+"."+parts[1], base64.StdEncoding.EncodeToString(sig), key); err != nil || !ok {
+		return nil, errors.New("signature does not verify")
+	}
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("claims: %v", err)
+	}
+	if v.Issuer != "" && claims.Issuer != v.Issuer {
+		return nil, fmt.Errorf("issued by %q, not %q", claims.Issuer, v.Issuer)
+	}
+	if v.Audience != "" && !claims.Audience.Contains(v.Audience) {
+		return nil, fmt.Errorf("not for audience %q", v.Audience)
+	}
+	now := time.Now
+	if v.Now != nil {
+		now = v.Now
+	}
+	t := now()
+	if claims.ExpiresAt == 0 || !t.Before(time.Unix(claims.ExpiresAt, 0).Add(v.Leeway)) {
+		return nil, errors.New("expired")
+	}
+	if claims.NotBefore != 0 && t.Add(v.Leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, errors.New("not yet valid")
+	}
+	if claims.AgentID == "" || claims.Subject != claims.AgentID {
+		return nil, errors.New("sub is not the dcp_agent_id")
+	}
+	return &claims, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return errors.New("not base64url")
+	}
+	return json.Unmarshal(data, v)
+}
+
+type contextKey struct{}
+
+// FromContext returns the claims of the token the Middleware admitted the
+// request with, if any.
+func FromContext(ctx context.Context) (*Claims, bool) {
+	c, ok := ctx.Value(contextKey{}).(*Claims)
+	return c, ok
+}
+
+// Middleware admits requests bearing a valid token in their Authorization
+// header, with its Claims in their context, and answers the others 401.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "no bearer token presented")
+			return
+		}
+		claims, err := v.Verify(strings.TrimSpace(token))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, claims)))
+	})
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,146 @@
+package dcpjwt_test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcpjwt"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+)
+
+func passport(t *testing.T, status dcp.Status) *dcp.AgentPassport {
+	t.Helper()
+	s, pub := dcptest.NewSigner(t)
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), pub, []string{"browse", "email"}, dcp.RiskTierMedium)
+	p.Status = status
+	if err := p.Sign(s); err != nil {
+		t.Fatal(err)
+	}
+	return &p
+}
+
+func TestIssueVerify(t *testing.T) {
+	signer, pub := dcptest.NewSigner(t)
+	now := time.Now()
+	iss := &dcpjwt.Issuer{Signer: signer, Issuer: "https://auth.example", KeyID: "k1", Now: func() time.Time { return now }}
+	p := passport(t, dcp.StatusActive)
+	token, err := iss.Issue(p, "https://api.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := &dcpjwt.Verifier{Keys: map[string]string{"k1": pub}, Issuer: "https://auth.example", Audience: "https://api.example", Now: func() time.Time { return now }}
+	claims, err := v.Verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.AgentID != p.AgentID || claims.Subject != p.AgentID || claims.HumanID != p.PrincipalBindingReference ||
+		claims.RiskTier != dcp.RiskTierMedium || !claims.HasCapability("email") || !claims.HasCapability("email:send") || claims.HasCapability("payments") {
+		t.Fatalf("claims %+v", claims)
+	}
+
+	// The token is a plain EdDSA JWS, verifiable without this package.
+	parts := strings.Split(token, ".")
+	raw, _ := base64.StdEncoding.DecodeString(pub)
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	if !ed25519.Verify(raw, []byte(parts[0]+"."+parts[1]), sig) {
+		t.Fatal("signature does not verify as EdDSA")
+	}
+	var payload map[string]interface{}
+	data, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	json.Unmarshal(data, &payload)
+	if payload["aud"] != "https://api.example" || payload["dcp_agent_id"] != p.AgentID {
+		t.Fatalf("payload %v", payload)
+	}
+
+	other, _ := dcptest.NewSigner(t)
+	forged, err := (&dcpjwt.Issuer{Signer: other, Issuer: "https://auth.example", KeyID: "k1"}).Issue(p, "https://api.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := *v
+	later.Now = func() time.Time { return now.Add(time.Hour) }
+	elsewhere := *v
+	elsewhere.Audience = "https://other.example"
+	for name, tc := range map[string]struct {
+		v     *dcpjwt.Verifier
+		token string
+	}{
+		"forged":         {v, forged},
+		"expired":        {&later, token},
+		"other audience": {&elsewhere, token},
+		"tampered":       {v, parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"x","dcp_agent_id":"x","exp":9999999999}`)) + "." + parts[2]},
+		"not a JWS":      {v, "abc"},
+		"unsigned":       {v, base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."},
+	} {
+		if _, err := tc.v.Verify(tc.token); !errors.Is(err, dcpjwt.ErrInvalidToken) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestIssueRefuses(t *testing.T) {
+	signer, _ := dcptest.NewSigner(t)
+	iss := &dcpjwt.Issuer{Signer: signer}
+	if _, err := iss.Issue(passport(t, dcp.StatusRevoked)); err == nil {
+		t.Fatal("issued for a revoked passport")
+	}
+	tampered := passport(t, dcp.StatusActive)
+	tampered.Capabilities = append(tampered.Capabilities, "payments")
+	if _, err := iss.Issue(tampered); err == nil {
+		t.Fatal("issued for a tampered passport")
+	}
+}
+
+func TestMiddlewareAndJWKS(t *testing.T) {
+	signer, pub := dcptest.NewSigner(t)
+	iss := &dcpjwt.Issuer{Signer: signer, KeyID: "k1"}
+	rec := httptest.NewRecorder()
+	iss.JWKSHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	var set struct {
+		Keys []dcpjwt.JWK `json:"keys"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &set); err != nil || len(set.Keys) != 1 {
+		t.Fatalf("JWKS %s", rec.Body)
+	}
+	x, _ := base64.RawURLEncoding.DecodeString(set.Keys[0].X)
+	if set.Keys[0].Kty != "OKP" || set.Keys[0].Crv != "Ed25519" || base64.StdEncoding.EncodeToString(x) != pub {
+		t.Fatalf("JWK %+v", set.Keys[0])
+	}
+
+	p := passport(t, dcp.StatusActive)
+	token, err := iss.Issue(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := (&dcpjwt.Verifier{PublicKeyB64: pub}).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := dcpjwt.FromContext(r.Context())
+		w.Write([]byte(claims.AgentID))
+	}))
+	call := func(auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := call("Bearer " + token); rec.Code != http.StatusOK || rec.Body.String() != p.AgentID {
+		t.Fatalf("valid token: %d %s", rec.Code, rec.Body)
+	}
+	if rec := call(""); rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != "Bearer" {
+		t.Fatalf("no token: %d", rec.Code)
+	}
+	if rec := call("Bearer " + token + "x"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("bad token: %d", rec.Code)
+	}
+}
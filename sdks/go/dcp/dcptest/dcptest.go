@@ -0,0 +1,126 @@
+// Package dcptest provides helpers shared by the tests of the dcp
+// packages: fresh keys and agents, the conformance examples, an
+// authenticator, and in-memory gRPC connections.
+package dcptest
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+)
+
+// NewSigner returns a signer for a fresh key and its public key.
+func NewSigner(t *testing.T) (*dcp.KeySigner, string) {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, kp.PublicKeyB64
+}
+
+// Agent returns a fresh active passport of tier with capabilities, of a
+// new principal, signed by the agent, and the agent's signer and key.
+func Agent(t *testing.T, tier dcp.RiskTier, capabilities ...string) (*dcp.AgentPassport, *dcp.KeySigner, ed25519.PrivateKey) {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), kp.PublicKeyB64, capabilities, tier)
+	if err := p.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	return &p, signer, ed25519.PrivateKey(raw)
+}
+
+// ReadExample returns the conformance example name, unmarshaled into v
+// unless v is nil.
+func ReadExample(t *testing.T, name string, v interface{}) []byte {
+	t.Helper()
+	_, thisFile, _, _ := runtime.Caller(0)
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(thisFile), "..", "..", "..", "..", "tests", "conformance", "examples", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != nil {
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return data
+}
+
+// Intent returns the conformance example intent, declared by the agent of
+// p for its principal.
+func Intent(t *testing.T, p *dcp.AgentPassport) *dcp.Intent {
+	t.Helper()
+	var intent dcp.Intent
+	ReadExample(t, "intent.json", &intent)
+	intent.AgentID = p.AgentID
+	intent.HumanID = p.PrincipalBindingReference
+	return &intent
+}
+
+// Authenticator returns an agentauth.Authenticator with cfg.
+func Authenticator(t *testing.T, cfg agentauth.Config) *agentauth.Authenticator {
+	t.Helper()
+	auth, err := agentauth.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth
+}
+
+// Dial serves g over an in-memory listener and returns a connection to it
+// with opts. Both are stopped when the test ends.
+func Dial(t *testing.T, g *grpc.Server, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+	opts = append(opts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// WantCode fails the test unless err has the gRPC status code.
+func WantCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	if status.Code(err) != code {
+		t.Fatalf("got %v, want %s", err, code)
+	}
+}
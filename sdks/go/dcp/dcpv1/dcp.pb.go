@@ -0,0 +1,5178 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: dcp.proto
+
+package dcpv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VerifyBundleRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SignedBundleJson string                 `protobuf:"bytes,1,opt,name=signed_bundle_json,json=signedBundleJson,proto3" json:"signed_bundle_json,omitempty"`
+	PublicKeyB64     string                 `protobuf:"bytes,2,opt,name=public_key_b64,json=publicKeyB64,proto3" json:"public_key_b64,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *VerifyBundleRequest) Reset() {
+	*x = VerifyBundleRequest{}
+	mi := &file_dcp_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyBundleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyBundleRequest) ProtoMessage() {}
+
+func (x *VerifyBundleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyBundleRequest.ProtoReflect.Descriptor instead.
+func (*VerifyBundleRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VerifyBundleRequest) GetSignedBundleJson() string {
+	if x != nil {
+		return x.SignedBundleJson
+	}
+	return ""
+}
+
+func (x *VerifyBundleRequest) GetPublicKeyB64() string {
+	if x != nil {
+		return x.PublicKeyB64
+	}
+	return ""
+}
+
+type VerifyBundleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Verified      bool                   `protobuf:"varint,1,opt,name=verified,proto3" json:"verified,omitempty"`
+	Errors        []string               `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyBundleResponse) Reset() {
+	*x = VerifyBundleResponse{}
+	mi := &file_dcp_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyBundleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyBundleResponse) ProtoMessage() {}
+
+func (x *VerifyBundleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyBundleResponse.ProtoReflect.Descriptor instead.
+func (*VerifyBundleResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VerifyBundleResponse) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *VerifyBundleResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ValidateBundleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BundleJson    string                 `protobuf:"bytes,1,opt,name=bundle_json,json=bundleJson,proto3" json:"bundle_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateBundleRequest) Reset() {
+	*x = ValidateBundleRequest{}
+	mi := &file_dcp_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateBundleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateBundleRequest) ProtoMessage() {}
+
+func (x *ValidateBundleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateBundleRequest.ProtoReflect.Descriptor instead.
+func (*ValidateBundleRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidateBundleRequest) GetBundleJson() string {
+	if x != nil {
+		return x.BundleJson
+	}
+	return ""
+}
+
+type ValidateBundleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Errors        []string               `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateBundleResponse) Reset() {
+	*x = ValidateBundleResponse{}
+	mi := &file_dcp_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateBundleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateBundleResponse) ProtoMessage() {}
+
+func (x *ValidateBundleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateBundleResponse.ProtoReflect.Descriptor instead.
+func (*ValidateBundleResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidateBundleResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateBundleResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type HealthCheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckRequest) Reset() {
+	*x = HealthCheckRequest{}
+	mi := &file_dcp_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckRequest) ProtoMessage() {}
+
+func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
+func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{4}
+}
+
+type HealthCheckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Service       string                 `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckResponse) Reset() {
+	*x = HealthCheckResponse{}
+	mi := &file_dcp_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckResponse) ProtoMessage() {}
+
+func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
+func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HealthCheckResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *HealthCheckResponse) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *HealthCheckResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type AnchorHashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BundleHash    string                 `protobuf:"bytes,1,opt,name=bundle_hash,json=bundleHash,proto3" json:"bundle_hash,omitempty"`
+	Chain         string                 `protobuf:"bytes,2,opt,name=chain,proto3" json:"chain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnchorHashRequest) Reset() {
+	*x = AnchorHashRequest{}
+	mi := &file_dcp_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnchorHashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnchorHashRequest) ProtoMessage() {}
+
+func (x *AnchorHashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnchorHashRequest.ProtoReflect.Descriptor instead.
+func (*AnchorHashRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AnchorHashRequest) GetBundleHash() string {
+	if x != nil {
+		return x.BundleHash
+	}
+	return ""
+}
+
+func (x *AnchorHashRequest) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+type AnchorHashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Anchored      bool                   `protobuf:"varint,1,opt,name=anchored,proto3" json:"anchored,omitempty"`
+	TxHash        string                 `protobuf:"bytes,2,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Chain         string                 `protobuf:"bytes,3,opt,name=chain,proto3" json:"chain,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnchorHashResponse) Reset() {
+	*x = AnchorHashResponse{}
+	mi := &file_dcp_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnchorHashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnchorHashResponse) ProtoMessage() {}
+
+func (x *AnchorHashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnchorHashResponse.ProtoReflect.Descriptor instead.
+func (*AnchorHashResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AnchorHashResponse) GetAnchored() bool {
+	if x != nil {
+		return x.Anchored
+	}
+	return false
+}
+
+func (x *AnchorHashResponse) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *AnchorHashResponse) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+func (x *AnchorHashResponse) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+type AnchorBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BundleHashes  []string               `protobuf:"bytes,1,rep,name=bundle_hashes,json=bundleHashes,proto3" json:"bundle_hashes,omitempty"`
+	Chain         string                 `protobuf:"bytes,2,opt,name=chain,proto3" json:"chain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnchorBatchRequest) Reset() {
+	*x = AnchorBatchRequest{}
+	mi := &file_dcp_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnchorBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnchorBatchRequest) ProtoMessage() {}
+
+func (x *AnchorBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnchorBatchRequest.ProtoReflect.Descriptor instead.
+func (*AnchorBatchRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AnchorBatchRequest) GetBundleHashes() []string {
+	if x != nil {
+		return x.BundleHashes
+	}
+	return nil
+}
+
+func (x *AnchorBatchRequest) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+type AnchorBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Anchored      bool                   `protobuf:"varint,1,opt,name=anchored,proto3" json:"anchored,omitempty"`
+	MerkleRoot    string                 `protobuf:"bytes,2,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Count         uint32                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	TxHash        string                 `protobuf:"bytes,4,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Chain         string                 `protobuf:"bytes,5,opt,name=chain,proto3" json:"chain,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnchorBatchResponse) Reset() {
+	*x = AnchorBatchResponse{}
+	mi := &file_dcp_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnchorBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnchorBatchResponse) ProtoMessage() {}
+
+func (x *AnchorBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnchorBatchResponse.ProtoReflect.Descriptor instead.
+func (*AnchorBatchResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AnchorBatchResponse) GetAnchored() bool {
+	if x != nil {
+		return x.Anchored
+	}
+	return false
+}
+
+func (x *AnchorBatchResponse) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *AnchorBatchResponse) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *AnchorBatchResponse) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *AnchorBatchResponse) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+func (x *AnchorBatchResponse) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+type CheckAnchorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckAnchorRequest) Reset() {
+	*x = CheckAnchorRequest{}
+	mi := &file_dcp_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckAnchorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckAnchorRequest) ProtoMessage() {}
+
+func (x *CheckAnchorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckAnchorRequest.ProtoReflect.Descriptor instead.
+func (*CheckAnchorRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CheckAnchorRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type CheckAnchorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Anchored      bool                   `protobuf:"varint,1,opt,name=anchored,proto3" json:"anchored,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	TxHash        string                 `protobuf:"bytes,3,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Chain         string                 `protobuf:"bytes,4,opt,name=chain,proto3" json:"chain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckAnchorResponse) Reset() {
+	*x = CheckAnchorResponse{}
+	mi := &file_dcp_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckAnchorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckAnchorResponse) ProtoMessage() {}
+
+func (x *CheckAnchorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckAnchorResponse.ProtoReflect.Descriptor instead.
+func (*CheckAnchorResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CheckAnchorResponse) GetAnchored() bool {
+	if x != nil {
+		return x.Anchored
+	}
+	return false
+}
+
+func (x *CheckAnchorResponse) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *CheckAnchorResponse) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *CheckAnchorResponse) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+type RevokeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DcpVersion    string                 `protobuf:"bytes,1,opt,name=dcp_version,json=dcpVersion,proto3" json:"dcp_version,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	HumanId       string                 `protobuf:"bytes,3,opt,name=human_id,json=humanId,proto3" json:"human_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Signature     string                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeRequest) Reset() {
+	*x = RevokeRequest{}
+	mi := &file_dcp_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeRequest) ProtoMessage() {}
+
+func (x *RevokeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeRequest.ProtoReflect.Descriptor instead.
+func (*RevokeRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RevokeRequest) GetDcpVersion() string {
+	if x != nil {
+		return x.DcpVersion
+	}
+	return ""
+}
+
+func (x *RevokeRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RevokeRequest) GetHumanId() string {
+	if x != nil {
+		return x.HumanId
+	}
+	return ""
+}
+
+func (x *RevokeRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RevokeRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type RevokeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	RevokedAt     string                 `protobuf:"bytes,3,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeResponse) Reset() {
+	*x = RevokeResponse{}
+	mi := &file_dcp_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeResponse) ProtoMessage() {}
+
+func (x *RevokeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeResponse.ProtoReflect.Descriptor instead.
+func (*RevokeResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RevokeResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *RevokeResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RevokeResponse) GetRevokedAt() string {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return ""
+}
+
+type CheckRevocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRevocationRequest) Reset() {
+	*x = CheckRevocationRequest{}
+	mi := &file_dcp_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRevocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRevocationRequest) ProtoMessage() {}
+
+func (x *CheckRevocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRevocationRequest.ProtoReflect.Descriptor instead.
+func (*CheckRevocationRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CheckRevocationRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type CheckRevocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revoked       bool                   `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	Record        *RevocationRecord      `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRevocationResponse) Reset() {
+	*x = CheckRevocationResponse{}
+	mi := &file_dcp_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRevocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRevocationResponse) ProtoMessage() {}
+
+func (x *CheckRevocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRevocationResponse.ProtoReflect.Descriptor instead.
+func (*CheckRevocationResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CheckRevocationResponse) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
+}
+
+func (x *CheckRevocationResponse) GetRecord() *RevocationRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type ListRevocationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         uint32                 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        uint32                 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRevocationsRequest) Reset() {
+	*x = ListRevocationsRequest{}
+	mi := &file_dcp_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRevocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRevocationsRequest) ProtoMessage() {}
+
+func (x *ListRevocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRevocationsRequest.ProtoReflect.Descriptor instead.
+func (*ListRevocationsRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListRevocationsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRevocationsRequest) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListRevocationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revocations   []*RevocationRecord    `protobuf:"bytes,1,rep,name=revocations,proto3" json:"revocations,omitempty"`
+	Total         uint32                 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRevocationsResponse) Reset() {
+	*x = ListRevocationsResponse{}
+	mi := &file_dcp_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRevocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRevocationsResponse) ProtoMessage() {}
+
+func (x *ListRevocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRevocationsResponse.ProtoReflect.Descriptor instead.
+func (*ListRevocationsResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListRevocationsResponse) GetRevocations() []*RevocationRecord {
+	if x != nil {
+		return x.Revocations
+	}
+	return nil
+}
+
+func (x *ListRevocationsResponse) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type RevocationRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DcpVersion    string                 `protobuf:"bytes,1,opt,name=dcp_version,json=dcpVersion,proto3" json:"dcp_version,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	HumanId       string                 `protobuf:"bytes,3,opt,name=human_id,json=humanId,proto3" json:"human_id,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Signature     string                 `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevocationRecord) Reset() {
+	*x = RevocationRecord{}
+	mi := &file_dcp_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevocationRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevocationRecord) ProtoMessage() {}
+
+func (x *RevocationRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevocationRecord.ProtoReflect.Descriptor instead.
+func (*RevocationRecord) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RevocationRecord) GetDcpVersion() string {
+	if x != nil {
+		return x.DcpVersion
+	}
+	return ""
+}
+
+func (x *RevocationRecord) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RevocationRecord) GetHumanId() string {
+	if x != nil {
+		return x.HumanId
+	}
+	return ""
+}
+
+func (x *RevocationRecord) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *RevocationRecord) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RevocationRecord) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type AddEntryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BundleHash    string                 `protobuf:"bytes,1,opt,name=bundle_hash,json=bundleHash,proto3" json:"bundle_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddEntryRequest) Reset() {
+	*x = AddEntryRequest{}
+	mi := &file_dcp_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddEntryRequest) ProtoMessage() {}
+
+func (x *AddEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddEntryRequest.ProtoReflect.Descriptor instead.
+func (*AddEntryRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AddEntryRequest) GetBundleHash() string {
+	if x != nil {
+		return x.BundleHash
+	}
+	return ""
+}
+
+type AddEntryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         uint64                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	LeafHash      string                 `protobuf:"bytes,2,opt,name=leaf_hash,json=leafHash,proto3" json:"leaf_hash,omitempty"`
+	Root          string                 `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
+	Size          uint64                 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddEntryResponse) Reset() {
+	*x = AddEntryResponse{}
+	mi := &file_dcp_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddEntryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddEntryResponse) ProtoMessage() {}
+
+func (x *AddEntryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddEntryResponse.ProtoReflect.Descriptor instead.
+func (*AddEntryResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AddEntryResponse) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *AddEntryResponse) GetLeafHash() string {
+	if x != nil {
+		return x.LeafHash
+	}
+	return ""
+}
+
+func (x *AddEntryResponse) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+func (x *AddEntryResponse) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type GetRootRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRootRequest) Reset() {
+	*x = GetRootRequest{}
+	mi := &file_dcp_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRootRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRootRequest) ProtoMessage() {}
+
+func (x *GetRootRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRootRequest.ProtoReflect.Descriptor instead.
+func (*GetRootRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{21}
+}
+
+type GetRootResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Root          string                 `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	Size          uint64                 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRootResponse) Reset() {
+	*x = GetRootResponse{}
+	mi := &file_dcp_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRootResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRootResponse) ProtoMessage() {}
+
+func (x *GetRootResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRootResponse.ProtoReflect.Descriptor instead.
+func (*GetRootResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetRootResponse) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+func (x *GetRootResponse) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GetRootResponse) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+type GetProofRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         uint64                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProofRequest) Reset() {
+	*x = GetProofRequest{}
+	mi := &file_dcp_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProofRequest) ProtoMessage() {}
+
+func (x *GetProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProofRequest.ProtoReflect.Descriptor instead.
+func (*GetProofRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetProofRequest) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+type GetProofResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         uint64                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	LeafHash      string                 `protobuf:"bytes,2,opt,name=leaf_hash,json=leafHash,proto3" json:"leaf_hash,omitempty"`
+	Root          string                 `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
+	Proof         []*ProofNode           `protobuf:"bytes,4,rep,name=proof,proto3" json:"proof,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProofResponse) Reset() {
+	*x = GetProofResponse{}
+	mi := &file_dcp_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProofResponse) ProtoMessage() {}
+
+func (x *GetProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProofResponse.ProtoReflect.Descriptor instead.
+func (*GetProofResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetProofResponse) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *GetProofResponse) GetLeafHash() string {
+	if x != nil {
+		return x.LeafHash
+	}
+	return ""
+}
+
+func (x *GetProofResponse) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+func (x *GetProofResponse) GetProof() []*ProofNode {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+type ProofNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Direction     string                 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProofNode) Reset() {
+	*x = ProofNode{}
+	mi := &file_dcp_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProofNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProofNode) ProtoMessage() {}
+
+func (x *ProofNode) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProofNode.ProtoReflect.Descriptor instead.
+func (*ProofNode) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ProofNode) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *ProofNode) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+type KeyEntryV2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kid           string                 `protobuf:"bytes,1,opt,name=kid,proto3" json:"kid,omitempty"`
+	Alg           string                 `protobuf:"bytes,2,opt,name=alg,proto3" json:"alg,omitempty"`
+	PublicKey     []byte                 `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyEntryV2) Reset() {
+	*x = KeyEntryV2{}
+	mi := &file_dcp_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyEntryV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyEntryV2) ProtoMessage() {}
+
+func (x *KeyEntryV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyEntryV2.ProtoReflect.Descriptor instead.
+func (*KeyEntryV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *KeyEntryV2) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+func (x *KeyEntryV2) GetAlg() string {
+	if x != nil {
+		return x.Alg
+	}
+	return ""
+}
+
+func (x *KeyEntryV2) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *KeyEntryV2) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *KeyEntryV2) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *KeyEntryV2) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type SignatureEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alg           string                 `protobuf:"bytes,1,opt,name=alg,proto3" json:"alg,omitempty"`
+	Kid           string                 `protobuf:"bytes,2,opt,name=kid,proto3" json:"kid,omitempty"`
+	Sig           []byte                 `protobuf:"bytes,3,opt,name=sig,proto3" json:"sig,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignatureEntry) Reset() {
+	*x = SignatureEntry{}
+	mi := &file_dcp_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignatureEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureEntry) ProtoMessage() {}
+
+func (x *SignatureEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureEntry.ProtoReflect.Descriptor instead.
+func (*SignatureEntry) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SignatureEntry) GetAlg() string {
+	if x != nil {
+		return x.Alg
+	}
+	return ""
+}
+
+func (x *SignatureEntry) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+func (x *SignatureEntry) GetSig() []byte {
+	if x != nil {
+		return x.Sig
+	}
+	return nil
+}
+
+type CompositeSignature struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Classical     *SignatureEntry        `protobuf:"bytes,1,opt,name=classical,proto3" json:"classical,omitempty"`
+	Pq            *SignatureEntry        `protobuf:"bytes,2,opt,name=pq,proto3" json:"pq,omitempty"`
+	Binding       string                 `protobuf:"bytes,3,opt,name=binding,proto3" json:"binding,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompositeSignature) Reset() {
+	*x = CompositeSignature{}
+	mi := &file_dcp_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompositeSignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompositeSignature) ProtoMessage() {}
+
+func (x *CompositeSignature) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompositeSignature.ProtoReflect.Descriptor instead.
+func (*CompositeSignature) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CompositeSignature) GetClassical() *SignatureEntry {
+	if x != nil {
+		return x.Classical
+	}
+	return nil
+}
+
+func (x *CompositeSignature) GetPq() *SignatureEntry {
+	if x != nil {
+		return x.Pq
+	}
+	return nil
+}
+
+func (x *CompositeSignature) GetBinding() string {
+	if x != nil {
+		return x.Binding
+	}
+	return ""
+}
+
+type SignedPayload struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payload       []byte                 `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	PayloadHash   string                 `protobuf:"bytes,2,opt,name=payload_hash,json=payloadHash,proto3" json:"payload_hash,omitempty"`
+	CompositeSig  *CompositeSignature    `protobuf:"bytes,3,opt,name=composite_sig,json=compositeSig,proto3" json:"composite_sig,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignedPayload) Reset() {
+	*x = SignedPayload{}
+	mi := &file_dcp_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedPayload) ProtoMessage() {}
+
+func (x *SignedPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedPayload.ProtoReflect.Descriptor instead.
+func (*SignedPayload) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SignedPayload) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *SignedPayload) GetPayloadHash() string {
+	if x != nil {
+		return x.PayloadHash
+	}
+	return ""
+}
+
+func (x *SignedPayload) GetCompositeSig() *CompositeSignature {
+	if x != nil {
+		return x.CompositeSig
+	}
+	return nil
+}
+
+type BundleManifest struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	SessionNonce             string                 `protobuf:"bytes,1,opt,name=session_nonce,json=sessionNonce,proto3" json:"session_nonce,omitempty"`
+	RprHash                  string                 `protobuf:"bytes,2,opt,name=rpr_hash,json=rprHash,proto3" json:"rpr_hash,omitempty"`
+	PassportHash             string                 `protobuf:"bytes,3,opt,name=passport_hash,json=passportHash,proto3" json:"passport_hash,omitempty"`
+	IntentHash               string                 `protobuf:"bytes,4,opt,name=intent_hash,json=intentHash,proto3" json:"intent_hash,omitempty"`
+	PolicyHash               string                 `protobuf:"bytes,5,opt,name=policy_hash,json=policyHash,proto3" json:"policy_hash,omitempty"`
+	AuditMerkleRoot          string                 `protobuf:"bytes,6,opt,name=audit_merkle_root,json=auditMerkleRoot,proto3" json:"audit_merkle_root,omitempty"`
+	AuditMerkleRootSecondary string                 `protobuf:"bytes,7,opt,name=audit_merkle_root_secondary,json=auditMerkleRootSecondary,proto3" json:"audit_merkle_root_secondary,omitempty"`
+	AuditCount               int32                  `protobuf:"varint,8,opt,name=audit_count,json=auditCount,proto3" json:"audit_count,omitempty"`
+	PqCheckpoints            []string               `protobuf:"bytes,9,rep,name=pq_checkpoints,json=pqCheckpoints,proto3" json:"pq_checkpoints,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *BundleManifest) Reset() {
+	*x = BundleManifest{}
+	mi := &file_dcp_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BundleManifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BundleManifest) ProtoMessage() {}
+
+func (x *BundleManifest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BundleManifest.ProtoReflect.Descriptor instead.
+func (*BundleManifest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *BundleManifest) GetSessionNonce() string {
+	if x != nil {
+		return x.SessionNonce
+	}
+	return ""
+}
+
+func (x *BundleManifest) GetRprHash() string {
+	if x != nil {
+		return x.RprHash
+	}
+	return ""
+}
+
+func (x *BundleManifest) GetPassportHash() string {
+	if x != nil {
+		return x.PassportHash
+	}
+	return ""
+}
+
+func (x *BundleManifest) GetIntentHash() string {
+	if x != nil {
+		return x.IntentHash
+	}
+	return ""
+}
+
+func (x *BundleManifest) GetPolicyHash() string {
+	if x != nil {
+		return x.PolicyHash
+	}
+	return ""
+}
+
+func (x *BundleManifest) GetAuditMerkleRoot() string {
+	if x != nil {
+		return x.AuditMerkleRoot
+	}
+	return ""
+}
+
+func (x *BundleManifest) GetAuditMerkleRootSecondary() string {
+	if x != nil {
+		return x.AuditMerkleRootSecondary
+	}
+	return ""
+}
+
+func (x *BundleManifest) GetAuditCount() int32 {
+	if x != nil {
+		return x.AuditCount
+	}
+	return 0
+}
+
+func (x *BundleManifest) GetPqCheckpoints() []string {
+	if x != nil {
+		return x.PqCheckpoints
+	}
+	return nil
+}
+
+type AgentPassportV2 struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	DcpVersion                string                 `protobuf:"bytes,1,opt,name=dcp_version,json=dcpVersion,proto3" json:"dcp_version,omitempty"`
+	AgentId                   string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	SessionNonce              string                 `protobuf:"bytes,3,opt,name=session_nonce,json=sessionNonce,proto3" json:"session_nonce,omitempty"`
+	Keys                      []*KeyEntryV2          `protobuf:"bytes,4,rep,name=keys,proto3" json:"keys,omitempty"`
+	PrincipalBindingReference string                 `protobuf:"bytes,5,opt,name=principal_binding_reference,json=principalBindingReference,proto3" json:"principal_binding_reference,omitempty"`
+	Capabilities              []string               `protobuf:"bytes,6,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	RiskTier                  string                 `protobuf:"bytes,7,opt,name=risk_tier,json=riskTier,proto3" json:"risk_tier,omitempty"`
+	CreatedAt                 string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Status                    string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	EmergencyRevocationToken  string                 `protobuf:"bytes,10,opt,name=emergency_revocation_token,json=emergencyRevocationToken,proto3" json:"emergency_revocation_token,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *AgentPassportV2) Reset() {
+	*x = AgentPassportV2{}
+	mi := &file_dcp_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentPassportV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentPassportV2) ProtoMessage() {}
+
+func (x *AgentPassportV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentPassportV2.ProtoReflect.Descriptor instead.
+func (*AgentPassportV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *AgentPassportV2) GetDcpVersion() string {
+	if x != nil {
+		return x.DcpVersion
+	}
+	return ""
+}
+
+func (x *AgentPassportV2) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentPassportV2) GetSessionNonce() string {
+	if x != nil {
+		return x.SessionNonce
+	}
+	return ""
+}
+
+func (x *AgentPassportV2) GetKeys() []*KeyEntryV2 {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *AgentPassportV2) GetPrincipalBindingReference() string {
+	if x != nil {
+		return x.PrincipalBindingReference
+	}
+	return ""
+}
+
+func (x *AgentPassportV2) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *AgentPassportV2) GetRiskTier() string {
+	if x != nil {
+		return x.RiskTier
+	}
+	return ""
+}
+
+func (x *AgentPassportV2) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *AgentPassportV2) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AgentPassportV2) GetEmergencyRevocationToken() string {
+	if x != nil {
+		return x.EmergencyRevocationToken
+	}
+	return ""
+}
+
+type ResponsiblePrincipalRecordV2 struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DcpVersion     string                 `protobuf:"bytes,1,opt,name=dcp_version,json=dcpVersion,proto3" json:"dcp_version,omitempty"`
+	HumanId        string                 `protobuf:"bytes,2,opt,name=human_id,json=humanId,proto3" json:"human_id,omitempty"`
+	SessionNonce   string                 `protobuf:"bytes,3,opt,name=session_nonce,json=sessionNonce,proto3" json:"session_nonce,omitempty"`
+	LegalName      string                 `protobuf:"bytes,4,opt,name=legal_name,json=legalName,proto3" json:"legal_name,omitempty"`
+	EntityType     string                 `protobuf:"bytes,5,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	Jurisdiction   string                 `protobuf:"bytes,6,opt,name=jurisdiction,proto3" json:"jurisdiction,omitempty"`
+	LiabilityMode  string                 `protobuf:"bytes,7,opt,name=liability_mode,json=liabilityMode,proto3" json:"liability_mode,omitempty"`
+	OverrideRights bool                   `protobuf:"varint,8,opt,name=override_rights,json=overrideRights,proto3" json:"override_rights,omitempty"`
+	IssuedAt       string                 `protobuf:"bytes,9,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	ExpiresAt      string                 `protobuf:"bytes,10,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Contact        string                 `protobuf:"bytes,11,opt,name=contact,proto3" json:"contact,omitempty"`
+	BindingKeys    []*KeyEntryV2          `protobuf:"bytes,12,rep,name=binding_keys,json=bindingKeys,proto3" json:"binding_keys,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ResponsiblePrincipalRecordV2) Reset() {
+	*x = ResponsiblePrincipalRecordV2{}
+	mi := &file_dcp_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponsiblePrincipalRecordV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponsiblePrincipalRecordV2) ProtoMessage() {}
+
+func (x *ResponsiblePrincipalRecordV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponsiblePrincipalRecordV2.ProtoReflect.Descriptor instead.
+func (*ResponsiblePrincipalRecordV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetDcpVersion() string {
+	if x != nil {
+		return x.DcpVersion
+	}
+	return ""
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetHumanId() string {
+	if x != nil {
+		return x.HumanId
+	}
+	return ""
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetSessionNonce() string {
+	if x != nil {
+		return x.SessionNonce
+	}
+	return ""
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetLegalName() string {
+	if x != nil {
+		return x.LegalName
+	}
+	return ""
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetJurisdiction() string {
+	if x != nil {
+		return x.Jurisdiction
+	}
+	return ""
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetLiabilityMode() string {
+	if x != nil {
+		return x.LiabilityMode
+	}
+	return ""
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetOverrideRights() bool {
+	if x != nil {
+		return x.OverrideRights
+	}
+	return false
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetIssuedAt() string {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return ""
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetContact() string {
+	if x != nil {
+		return x.Contact
+	}
+	return ""
+}
+
+func (x *ResponsiblePrincipalRecordV2) GetBindingKeys() []*KeyEntryV2 {
+	if x != nil {
+		return x.BindingKeys
+	}
+	return nil
+}
+
+type IntentV2 struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DcpVersion      string                 `protobuf:"bytes,1,opt,name=dcp_version,json=dcpVersion,proto3" json:"dcp_version,omitempty"`
+	IntentId        string                 `protobuf:"bytes,2,opt,name=intent_id,json=intentId,proto3" json:"intent_id,omitempty"`
+	SessionNonce    string                 `protobuf:"bytes,3,opt,name=session_nonce,json=sessionNonce,proto3" json:"session_nonce,omitempty"`
+	AgentId         string                 `protobuf:"bytes,4,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	HumanId         string                 `protobuf:"bytes,5,opt,name=human_id,json=humanId,proto3" json:"human_id,omitempty"`
+	Timestamp       string                 `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ActionType      string                 `protobuf:"bytes,7,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
+	Target          *IntentTargetV2        `protobuf:"bytes,8,opt,name=target,proto3" json:"target,omitempty"`
+	DataClasses     []string               `protobuf:"bytes,9,rep,name=data_classes,json=dataClasses,proto3" json:"data_classes,omitempty"`
+	EstimatedImpact string                 `protobuf:"bytes,10,opt,name=estimated_impact,json=estimatedImpact,proto3" json:"estimated_impact,omitempty"`
+	RequiresConsent bool                   `protobuf:"varint,11,opt,name=requires_consent,json=requiresConsent,proto3" json:"requires_consent,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *IntentV2) Reset() {
+	*x = IntentV2{}
+	mi := &file_dcp_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntentV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentV2) ProtoMessage() {}
+
+func (x *IntentV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentV2.ProtoReflect.Descriptor instead.
+func (*IntentV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *IntentV2) GetDcpVersion() string {
+	if x != nil {
+		return x.DcpVersion
+	}
+	return ""
+}
+
+func (x *IntentV2) GetIntentId() string {
+	if x != nil {
+		return x.IntentId
+	}
+	return ""
+}
+
+func (x *IntentV2) GetSessionNonce() string {
+	if x != nil {
+		return x.SessionNonce
+	}
+	return ""
+}
+
+func (x *IntentV2) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *IntentV2) GetHumanId() string {
+	if x != nil {
+		return x.HumanId
+	}
+	return ""
+}
+
+func (x *IntentV2) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *IntentV2) GetActionType() string {
+	if x != nil {
+		return x.ActionType
+	}
+	return ""
+}
+
+func (x *IntentV2) GetTarget() *IntentTargetV2 {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *IntentV2) GetDataClasses() []string {
+	if x != nil {
+		return x.DataClasses
+	}
+	return nil
+}
+
+func (x *IntentV2) GetEstimatedImpact() string {
+	if x != nil {
+		return x.EstimatedImpact
+	}
+	return ""
+}
+
+func (x *IntentV2) GetRequiresConsent() bool {
+	if x != nil {
+		return x.RequiresConsent
+	}
+	return false
+}
+
+type IntentTargetV2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntentTargetV2) Reset() {
+	*x = IntentTargetV2{}
+	mi := &file_dcp_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntentTargetV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentTargetV2) ProtoMessage() {}
+
+func (x *IntentTargetV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentTargetV2.ProtoReflect.Descriptor instead.
+func (*IntentTargetV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *IntentTargetV2) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *IntentTargetV2) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *IntentTargetV2) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *IntentTargetV2) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type PolicyDecisionV2 struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	DcpVersion           string                 `protobuf:"bytes,1,opt,name=dcp_version,json=dcpVersion,proto3" json:"dcp_version,omitempty"`
+	IntentId             string                 `protobuf:"bytes,2,opt,name=intent_id,json=intentId,proto3" json:"intent_id,omitempty"`
+	SessionNonce         string                 `protobuf:"bytes,3,opt,name=session_nonce,json=sessionNonce,proto3" json:"session_nonce,omitempty"`
+	Decision             string                 `protobuf:"bytes,4,opt,name=decision,proto3" json:"decision,omitempty"`
+	RiskScore            int32                  `protobuf:"varint,5,opt,name=risk_score,json=riskScore,proto3" json:"risk_score,omitempty"`
+	Reasons              []string               `protobuf:"bytes,6,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	RequiredConfirmation string                 `protobuf:"bytes,7,opt,name=required_confirmation,json=requiredConfirmation,proto3" json:"required_confirmation,omitempty"`
+	AppliedPolicyHash    string                 `protobuf:"bytes,8,opt,name=applied_policy_hash,json=appliedPolicyHash,proto3" json:"applied_policy_hash,omitempty"`
+	Timestamp            string                 `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *PolicyDecisionV2) Reset() {
+	*x = PolicyDecisionV2{}
+	mi := &file_dcp_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyDecisionV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyDecisionV2) ProtoMessage() {}
+
+func (x *PolicyDecisionV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyDecisionV2.ProtoReflect.Descriptor instead.
+func (*PolicyDecisionV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *PolicyDecisionV2) GetDcpVersion() string {
+	if x != nil {
+		return x.DcpVersion
+	}
+	return ""
+}
+
+func (x *PolicyDecisionV2) GetIntentId() string {
+	if x != nil {
+		return x.IntentId
+	}
+	return ""
+}
+
+func (x *PolicyDecisionV2) GetSessionNonce() string {
+	if x != nil {
+		return x.SessionNonce
+	}
+	return ""
+}
+
+func (x *PolicyDecisionV2) GetDecision() string {
+	if x != nil {
+		return x.Decision
+	}
+	return ""
+}
+
+func (x *PolicyDecisionV2) GetRiskScore() int32 {
+	if x != nil {
+		return x.RiskScore
+	}
+	return 0
+}
+
+func (x *PolicyDecisionV2) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *PolicyDecisionV2) GetRequiredConfirmation() string {
+	if x != nil {
+		return x.RequiredConfirmation
+	}
+	return ""
+}
+
+func (x *PolicyDecisionV2) GetAppliedPolicyHash() string {
+	if x != nil {
+		return x.AppliedPolicyHash
+	}
+	return ""
+}
+
+func (x *PolicyDecisionV2) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+type AuditEventV2 struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	DcpVersion          string                 `protobuf:"bytes,1,opt,name=dcp_version,json=dcpVersion,proto3" json:"dcp_version,omitempty"`
+	AuditId             string                 `protobuf:"bytes,2,opt,name=audit_id,json=auditId,proto3" json:"audit_id,omitempty"`
+	SessionNonce        string                 `protobuf:"bytes,3,opt,name=session_nonce,json=sessionNonce,proto3" json:"session_nonce,omitempty"`
+	PrevHash            string                 `protobuf:"bytes,4,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
+	PrevHashSecondary   string                 `protobuf:"bytes,5,opt,name=prev_hash_secondary,json=prevHashSecondary,proto3" json:"prev_hash_secondary,omitempty"`
+	HashAlg             string                 `protobuf:"bytes,6,opt,name=hash_alg,json=hashAlg,proto3" json:"hash_alg,omitempty"`
+	Timestamp           string                 `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	AgentId             string                 `protobuf:"bytes,8,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	HumanId             string                 `protobuf:"bytes,9,opt,name=human_id,json=humanId,proto3" json:"human_id,omitempty"`
+	IntentId            string                 `protobuf:"bytes,10,opt,name=intent_id,json=intentId,proto3" json:"intent_id,omitempty"`
+	IntentHash          string                 `protobuf:"bytes,11,opt,name=intent_hash,json=intentHash,proto3" json:"intent_hash,omitempty"`
+	IntentHashSecondary string                 `protobuf:"bytes,12,opt,name=intent_hash_secondary,json=intentHashSecondary,proto3" json:"intent_hash_secondary,omitempty"`
+	PolicyDecision      string                 `protobuf:"bytes,13,opt,name=policy_decision,json=policyDecision,proto3" json:"policy_decision,omitempty"`
+	Outcome             string                 `protobuf:"bytes,14,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	Evidence            *AuditEvidenceV2       `protobuf:"bytes,15,opt,name=evidence,proto3" json:"evidence,omitempty"`
+	PqCheckpointRef     string                 `protobuf:"bytes,16,opt,name=pq_checkpoint_ref,json=pqCheckpointRef,proto3" json:"pq_checkpoint_ref,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *AuditEventV2) Reset() {
+	*x = AuditEventV2{}
+	mi := &file_dcp_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEventV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEventV2) ProtoMessage() {}
+
+func (x *AuditEventV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEventV2.ProtoReflect.Descriptor instead.
+func (*AuditEventV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *AuditEventV2) GetDcpVersion() string {
+	if x != nil {
+		return x.DcpVersion
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetAuditId() string {
+	if x != nil {
+		return x.AuditId
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetSessionNonce() string {
+	if x != nil {
+		return x.SessionNonce
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetPrevHash() string {
+	if x != nil {
+		return x.PrevHash
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetPrevHashSecondary() string {
+	if x != nil {
+		return x.PrevHashSecondary
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetHashAlg() string {
+	if x != nil {
+		return x.HashAlg
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetHumanId() string {
+	if x != nil {
+		return x.HumanId
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetIntentId() string {
+	if x != nil {
+		return x.IntentId
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetIntentHash() string {
+	if x != nil {
+		return x.IntentHash
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetIntentHashSecondary() string {
+	if x != nil {
+		return x.IntentHashSecondary
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetPolicyDecision() string {
+	if x != nil {
+		return x.PolicyDecision
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *AuditEventV2) GetEvidence() *AuditEvidenceV2 {
+	if x != nil {
+		return x.Evidence
+	}
+	return nil
+}
+
+func (x *AuditEventV2) GetPqCheckpointRef() string {
+	if x != nil {
+		return x.PqCheckpointRef
+	}
+	return ""
+}
+
+type AuditEvidenceV2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tool          string                 `protobuf:"bytes,1,opt,name=tool,proto3" json:"tool,omitempty"`
+	ResultRef     string                 `protobuf:"bytes,2,opt,name=result_ref,json=resultRef,proto3" json:"result_ref,omitempty"`
+	EvidenceHash  string                 `protobuf:"bytes,3,opt,name=evidence_hash,json=evidenceHash,proto3" json:"evidence_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvidenceV2) Reset() {
+	*x = AuditEvidenceV2{}
+	mi := &file_dcp_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvidenceV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvidenceV2) ProtoMessage() {}
+
+func (x *AuditEvidenceV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvidenceV2.ProtoReflect.Descriptor instead.
+func (*AuditEvidenceV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *AuditEvidenceV2) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *AuditEvidenceV2) GetResultRef() string {
+	if x != nil {
+		return x.ResultRef
+	}
+	return ""
+}
+
+func (x *AuditEvidenceV2) GetEvidenceHash() string {
+	if x != nil {
+		return x.EvidenceHash
+	}
+	return ""
+}
+
+type PQCheckpoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CheckpointId  string                 `protobuf:"bytes,1,opt,name=checkpoint_id,json=checkpointId,proto3" json:"checkpoint_id,omitempty"`
+	SessionNonce  string                 `protobuf:"bytes,2,opt,name=session_nonce,json=sessionNonce,proto3" json:"session_nonce,omitempty"`
+	EventRange    *EventRange            `protobuf:"bytes,3,opt,name=event_range,json=eventRange,proto3" json:"event_range,omitempty"`
+	MerkleRoot    string                 `protobuf:"bytes,4,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	CompositeSig  *CompositeSignature    `protobuf:"bytes,5,opt,name=composite_sig,json=compositeSig,proto3" json:"composite_sig,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PQCheckpoint) Reset() {
+	*x = PQCheckpoint{}
+	mi := &file_dcp_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PQCheckpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PQCheckpoint) ProtoMessage() {}
+
+func (x *PQCheckpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PQCheckpoint.ProtoReflect.Descriptor instead.
+func (*PQCheckpoint) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PQCheckpoint) GetCheckpointId() string {
+	if x != nil {
+		return x.CheckpointId
+	}
+	return ""
+}
+
+func (x *PQCheckpoint) GetSessionNonce() string {
+	if x != nil {
+		return x.SessionNonce
+	}
+	return ""
+}
+
+func (x *PQCheckpoint) GetEventRange() *EventRange {
+	if x != nil {
+		return x.EventRange
+	}
+	return nil
+}
+
+func (x *PQCheckpoint) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *PQCheckpoint) GetCompositeSig() *CompositeSignature {
+	if x != nil {
+		return x.CompositeSig
+	}
+	return nil
+}
+
+type EventRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromAuditId   string                 `protobuf:"bytes,1,opt,name=from_audit_id,json=fromAuditId,proto3" json:"from_audit_id,omitempty"`
+	ToAuditId     string                 `protobuf:"bytes,2,opt,name=to_audit_id,json=toAuditId,proto3" json:"to_audit_id,omitempty"`
+	Count         int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventRange) Reset() {
+	*x = EventRange{}
+	mi := &file_dcp_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventRange) ProtoMessage() {}
+
+func (x *EventRange) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventRange.ProtoReflect.Descriptor instead.
+func (*EventRange) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *EventRange) GetFromAuditId() string {
+	if x != nil {
+		return x.FromAuditId
+	}
+	return ""
+}
+
+func (x *EventRange) GetToAuditId() string {
+	if x != nil {
+		return x.ToAuditId
+	}
+	return ""
+}
+
+func (x *EventRange) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type CitizenshipBundleV2 struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	DcpBundleVersion           string                 `protobuf:"bytes,1,opt,name=dcp_bundle_version,json=dcpBundleVersion,proto3" json:"dcp_bundle_version,omitempty"`
+	Manifest                   *BundleManifest        `protobuf:"bytes,2,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	ResponsiblePrincipalRecord *SignedPayload         `protobuf:"bytes,3,opt,name=responsible_principal_record,json=responsiblePrincipalRecord,proto3" json:"responsible_principal_record,omitempty"`
+	AgentPassport              *SignedPayload         `protobuf:"bytes,4,opt,name=agent_passport,json=agentPassport,proto3" json:"agent_passport,omitempty"`
+	Intent                     *SignedPayload         `protobuf:"bytes,5,opt,name=intent,proto3" json:"intent,omitempty"`
+	PolicyDecision             *SignedPayload         `protobuf:"bytes,6,opt,name=policy_decision,json=policyDecision,proto3" json:"policy_decision,omitempty"`
+	AuditEntries               []*AuditEventV2        `protobuf:"bytes,7,rep,name=audit_entries,json=auditEntries,proto3" json:"audit_entries,omitempty"`
+	PqCheckpoints              []*PQCheckpoint        `protobuf:"bytes,8,rep,name=pq_checkpoints,json=pqCheckpoints,proto3" json:"pq_checkpoints,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *CitizenshipBundleV2) Reset() {
+	*x = CitizenshipBundleV2{}
+	mi := &file_dcp_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CitizenshipBundleV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CitizenshipBundleV2) ProtoMessage() {}
+
+func (x *CitizenshipBundleV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CitizenshipBundleV2.ProtoReflect.Descriptor instead.
+func (*CitizenshipBundleV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CitizenshipBundleV2) GetDcpBundleVersion() string {
+	if x != nil {
+		return x.DcpBundleVersion
+	}
+	return ""
+}
+
+func (x *CitizenshipBundleV2) GetManifest() *BundleManifest {
+	if x != nil {
+		return x.Manifest
+	}
+	return nil
+}
+
+func (x *CitizenshipBundleV2) GetResponsiblePrincipalRecord() *SignedPayload {
+	if x != nil {
+		return x.ResponsiblePrincipalRecord
+	}
+	return nil
+}
+
+func (x *CitizenshipBundleV2) GetAgentPassport() *SignedPayload {
+	if x != nil {
+		return x.AgentPassport
+	}
+	return nil
+}
+
+func (x *CitizenshipBundleV2) GetIntent() *SignedPayload {
+	if x != nil {
+		return x.Intent
+	}
+	return nil
+}
+
+func (x *CitizenshipBundleV2) GetPolicyDecision() *SignedPayload {
+	if x != nil {
+		return x.PolicyDecision
+	}
+	return nil
+}
+
+func (x *CitizenshipBundleV2) GetAuditEntries() []*AuditEventV2 {
+	if x != nil {
+		return x.AuditEntries
+	}
+	return nil
+}
+
+func (x *CitizenshipBundleV2) GetPqCheckpoints() []*PQCheckpoint {
+	if x != nil {
+		return x.PqCheckpoints
+	}
+	return nil
+}
+
+type BundleSignerV2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Kids          []string               `protobuf:"bytes,3,rep,name=kids,proto3" json:"kids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BundleSignerV2) Reset() {
+	*x = BundleSignerV2{}
+	mi := &file_dcp_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BundleSignerV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BundleSignerV2) ProtoMessage() {}
+
+func (x *BundleSignerV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BundleSignerV2.ProtoReflect.Descriptor instead.
+func (*BundleSignerV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *BundleSignerV2) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *BundleSignerV2) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BundleSignerV2) GetKids() []string {
+	if x != nil {
+		return x.Kids
+	}
+	return nil
+}
+
+type BundleSignatureV2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HashAlg       string                 `protobuf:"bytes,1,opt,name=hash_alg,json=hashAlg,proto3" json:"hash_alg,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Signer        *BundleSignerV2        `protobuf:"bytes,3,opt,name=signer,proto3" json:"signer,omitempty"`
+	ManifestHash  string                 `protobuf:"bytes,4,opt,name=manifest_hash,json=manifestHash,proto3" json:"manifest_hash,omitempty"`
+	CompositeSig  *CompositeSignature    `protobuf:"bytes,5,opt,name=composite_sig,json=compositeSig,proto3" json:"composite_sig,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BundleSignatureV2) Reset() {
+	*x = BundleSignatureV2{}
+	mi := &file_dcp_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BundleSignatureV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BundleSignatureV2) ProtoMessage() {}
+
+func (x *BundleSignatureV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BundleSignatureV2.ProtoReflect.Descriptor instead.
+func (*BundleSignatureV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *BundleSignatureV2) GetHashAlg() string {
+	if x != nil {
+		return x.HashAlg
+	}
+	return ""
+}
+
+func (x *BundleSignatureV2) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *BundleSignatureV2) GetSigner() *BundleSignerV2 {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *BundleSignatureV2) GetManifestHash() string {
+	if x != nil {
+		return x.ManifestHash
+	}
+	return ""
+}
+
+func (x *BundleSignatureV2) GetCompositeSig() *CompositeSignature {
+	if x != nil {
+		return x.CompositeSig
+	}
+	return nil
+}
+
+type SignedBundleV2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bundle        *CitizenshipBundleV2   `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	Signature     *BundleSignatureV2     `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignedBundleV2) Reset() {
+	*x = SignedBundleV2{}
+	mi := &file_dcp_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedBundleV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedBundleV2) ProtoMessage() {}
+
+func (x *SignedBundleV2) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedBundleV2.ProtoReflect.Descriptor instead.
+func (*SignedBundleV2) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *SignedBundleV2) GetBundle() *CitizenshipBundleV2 {
+	if x != nil {
+		return x.Bundle
+	}
+	return nil
+}
+
+func (x *SignedBundleV2) GetSignature() *BundleSignatureV2 {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type VerifyBundleV2Request struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Input:
+	//
+	//	*VerifyBundleV2Request_SignedBundleJson
+	//	*VerifyBundleV2Request_SignedBundleCbor
+	Input              isVerifyBundleV2Request_Input `protobuf_oneof:"input"`
+	VerifierPolicyJson string                        `protobuf:"bytes,3,opt,name=verifier_policy_json,json=verifierPolicyJson,proto3" json:"verifier_policy_json,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *VerifyBundleV2Request) Reset() {
+	*x = VerifyBundleV2Request{}
+	mi := &file_dcp_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyBundleV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyBundleV2Request) ProtoMessage() {}
+
+func (x *VerifyBundleV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyBundleV2Request.ProtoReflect.Descriptor instead.
+func (*VerifyBundleV2Request) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *VerifyBundleV2Request) GetInput() isVerifyBundleV2Request_Input {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *VerifyBundleV2Request) GetSignedBundleJson() string {
+	if x != nil {
+		if x, ok := x.Input.(*VerifyBundleV2Request_SignedBundleJson); ok {
+			return x.SignedBundleJson
+		}
+	}
+	return ""
+}
+
+func (x *VerifyBundleV2Request) GetSignedBundleCbor() []byte {
+	if x != nil {
+		if x, ok := x.Input.(*VerifyBundleV2Request_SignedBundleCbor); ok {
+			return x.SignedBundleCbor
+		}
+	}
+	return nil
+}
+
+func (x *VerifyBundleV2Request) GetVerifierPolicyJson() string {
+	if x != nil {
+		return x.VerifierPolicyJson
+	}
+	return ""
+}
+
+type isVerifyBundleV2Request_Input interface {
+	isVerifyBundleV2Request_Input()
+}
+
+type VerifyBundleV2Request_SignedBundleJson struct {
+	SignedBundleJson string `protobuf:"bytes,1,opt,name=signed_bundle_json,json=signedBundleJson,proto3,oneof"`
+}
+
+type VerifyBundleV2Request_SignedBundleCbor struct {
+	SignedBundleCbor []byte `protobuf:"bytes,2,opt,name=signed_bundle_cbor,json=signedBundleCbor,proto3,oneof"`
+}
+
+func (*VerifyBundleV2Request_SignedBundleJson) isVerifyBundleV2Request_Input() {}
+
+func (*VerifyBundleV2Request_SignedBundleCbor) isVerifyBundleV2Request_Input() {}
+
+type VerifyBundleV2Response struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Verified            bool                   `protobuf:"varint,1,opt,name=verified,proto3" json:"verified,omitempty"`
+	DcpVersion          string                 `protobuf:"bytes,2,opt,name=dcp_version,json=dcpVersion,proto3" json:"dcp_version,omitempty"`
+	Errors              []string               `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	Warnings            []string               `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	ClassicalValid      bool                   `protobuf:"varint,5,opt,name=classical_valid,json=classicalValid,proto3" json:"classical_valid,omitempty"`
+	PqValid             bool                   `protobuf:"varint,6,opt,name=pq_valid,json=pqValid,proto3" json:"pq_valid,omitempty"`
+	SessionBindingValid bool                   `protobuf:"varint,7,opt,name=session_binding_valid,json=sessionBindingValid,proto3" json:"session_binding_valid,omitempty"`
+	ManifestValid       bool                   `protobuf:"varint,8,opt,name=manifest_valid,json=manifestValid,proto3" json:"manifest_valid,omitempty"`
+	HashChainValid      bool                   `protobuf:"varint,9,opt,name=hash_chain_valid,json=hashChainValid,proto3" json:"hash_chain_valid,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *VerifyBundleV2Response) Reset() {
+	*x = VerifyBundleV2Response{}
+	mi := &file_dcp_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyBundleV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyBundleV2Response) ProtoMessage() {}
+
+func (x *VerifyBundleV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyBundleV2Response.ProtoReflect.Descriptor instead.
+func (*VerifyBundleV2Response) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *VerifyBundleV2Response) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *VerifyBundleV2Response) GetDcpVersion() string {
+	if x != nil {
+		return x.DcpVersion
+	}
+	return ""
+}
+
+func (x *VerifyBundleV2Response) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *VerifyBundleV2Response) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *VerifyBundleV2Response) GetClassicalValid() bool {
+	if x != nil {
+		return x.ClassicalValid
+	}
+	return false
+}
+
+func (x *VerifyBundleV2Response) GetPqValid() bool {
+	if x != nil {
+		return x.PqValid
+	}
+	return false
+}
+
+func (x *VerifyBundleV2Response) GetSessionBindingValid() bool {
+	if x != nil {
+		return x.SessionBindingValid
+	}
+	return false
+}
+
+func (x *VerifyBundleV2Response) GetManifestValid() bool {
+	if x != nil {
+		return x.ManifestValid
+	}
+	return false
+}
+
+func (x *VerifyBundleV2Response) GetHashChainValid() bool {
+	if x != nil {
+		return x.HashChainValid
+	}
+	return false
+}
+
+type RevokeV2Request struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DcpVersion    string                 `protobuf:"bytes,1,opt,name=dcp_version,json=dcpVersion,proto3" json:"dcp_version,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	HumanId       string                 `protobuf:"bytes,3,opt,name=human_id,json=humanId,proto3" json:"human_id,omitempty"`
+	RevokedKid    string                 `protobuf:"bytes,4,opt,name=revoked_kid,json=revokedKid,proto3" json:"revoked_kid,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	CompositeSig  *CompositeSignature    `protobuf:"bytes,7,opt,name=composite_sig,json=compositeSig,proto3" json:"composite_sig,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeV2Request) Reset() {
+	*x = RevokeV2Request{}
+	mi := &file_dcp_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeV2Request) ProtoMessage() {}
+
+func (x *RevokeV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeV2Request.ProtoReflect.Descriptor instead.
+func (*RevokeV2Request) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RevokeV2Request) GetDcpVersion() string {
+	if x != nil {
+		return x.DcpVersion
+	}
+	return ""
+}
+
+func (x *RevokeV2Request) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RevokeV2Request) GetHumanId() string {
+	if x != nil {
+		return x.HumanId
+	}
+	return ""
+}
+
+func (x *RevokeV2Request) GetRevokedKid() string {
+	if x != nil {
+		return x.RevokedKid
+	}
+	return ""
+}
+
+func (x *RevokeV2Request) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RevokeV2Request) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *RevokeV2Request) GetCompositeSig() *CompositeSignature {
+	if x != nil {
+		return x.CompositeSig
+	}
+	return nil
+}
+
+type RevokeV2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	RevokedKid    string                 `protobuf:"bytes,3,opt,name=revoked_kid,json=revokedKid,proto3" json:"revoked_kid,omitempty"`
+	RevokedAt     string                 `protobuf:"bytes,4,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeV2Response) Reset() {
+	*x = RevokeV2Response{}
+	mi := &file_dcp_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeV2Response) ProtoMessage() {}
+
+func (x *RevokeV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeV2Response.ProtoReflect.Descriptor instead.
+func (*RevokeV2Response) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RevokeV2Response) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *RevokeV2Response) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RevokeV2Response) GetRevokedKid() string {
+	if x != nil {
+		return x.RevokedKid
+	}
+	return ""
+}
+
+func (x *RevokeV2Response) GetRevokedAt() string {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return ""
+}
+
+type EmergencyRevokeRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AgentId          string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	HumanId          string                 `protobuf:"bytes,2,opt,name=human_id,json=humanId,proto3" json:"human_id,omitempty"`
+	RevocationSecret string                 `protobuf:"bytes,3,opt,name=revocation_secret,json=revocationSecret,proto3" json:"revocation_secret,omitempty"`
+	Timestamp        string                 `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Reason           string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *EmergencyRevokeRequest) Reset() {
+	*x = EmergencyRevokeRequest{}
+	mi := &file_dcp_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmergencyRevokeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmergencyRevokeRequest) ProtoMessage() {}
+
+func (x *EmergencyRevokeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmergencyRevokeRequest.ProtoReflect.Descriptor instead.
+func (*EmergencyRevokeRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *EmergencyRevokeRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *EmergencyRevokeRequest) GetHumanId() string {
+	if x != nil {
+		return x.HumanId
+	}
+	return ""
+}
+
+func (x *EmergencyRevokeRequest) GetRevocationSecret() string {
+	if x != nil {
+		return x.RevocationSecret
+	}
+	return ""
+}
+
+func (x *EmergencyRevokeRequest) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *EmergencyRevokeRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type EmergencyRevokeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	RevokedAt     string                 `protobuf:"bytes,3,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	KeysRevoked   int32                  `protobuf:"varint,4,opt,name=keys_revoked,json=keysRevoked,proto3" json:"keys_revoked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmergencyRevokeResponse) Reset() {
+	*x = EmergencyRevokeResponse{}
+	mi := &file_dcp_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmergencyRevokeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmergencyRevokeResponse) ProtoMessage() {}
+
+func (x *EmergencyRevokeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmergencyRevokeResponse.ProtoReflect.Descriptor instead.
+func (*EmergencyRevokeResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *EmergencyRevokeResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *EmergencyRevokeResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *EmergencyRevokeResponse) GetRevokedAt() string {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return ""
+}
+
+func (x *EmergencyRevokeResponse) GetKeysRevoked() int32 {
+	if x != nil {
+		return x.KeysRevoked
+	}
+	return 0
+}
+
+type CheckRevocationByKidRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kid           string                 `protobuf:"bytes,1,opt,name=kid,proto3" json:"kid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRevocationByKidRequest) Reset() {
+	*x = CheckRevocationByKidRequest{}
+	mi := &file_dcp_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRevocationByKidRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRevocationByKidRequest) ProtoMessage() {}
+
+func (x *CheckRevocationByKidRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRevocationByKidRequest.ProtoReflect.Descriptor instead.
+func (*CheckRevocationByKidRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *CheckRevocationByKidRequest) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+type CheckRevocationByKidResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revoked       bool                   `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Kid           string                 `protobuf:"bytes,3,opt,name=kid,proto3" json:"kid,omitempty"`
+	RevokedAt     string                 `protobuf:"bytes,4,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRevocationByKidResponse) Reset() {
+	*x = CheckRevocationByKidResponse{}
+	mi := &file_dcp_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRevocationByKidResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRevocationByKidResponse) ProtoMessage() {}
+
+func (x *CheckRevocationByKidResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRevocationByKidResponse.ProtoReflect.Descriptor instead.
+func (*CheckRevocationByKidResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *CheckRevocationByKidResponse) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
+}
+
+func (x *CheckRevocationByKidResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *CheckRevocationByKidResponse) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+func (x *CheckRevocationByKidResponse) GetRevokedAt() string {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return ""
+}
+
+func (x *CheckRevocationByKidResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RegisterKeysRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	AgentId           string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Keys              []*KeyEntryV2          `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	ProofOfPossession *CompositeSignature    `protobuf:"bytes,3,opt,name=proof_of_possession,json=proofOfPossession,proto3" json:"proof_of_possession,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RegisterKeysRequest) Reset() {
+	*x = RegisterKeysRequest{}
+	mi := &file_dcp_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterKeysRequest) ProtoMessage() {}
+
+func (x *RegisterKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterKeysRequest.ProtoReflect.Descriptor instead.
+func (*RegisterKeysRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *RegisterKeysRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RegisterKeysRequest) GetKeys() []*KeyEntryV2 {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *RegisterKeysRequest) GetProofOfPossession() *CompositeSignature {
+	if x != nil {
+		return x.ProofOfPossession
+	}
+	return nil
+}
+
+type RegisterKeysResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Ok             bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	RegisteredKids []string               `protobuf:"bytes,2,rep,name=registered_kids,json=registeredKids,proto3" json:"registered_kids,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RegisterKeysResponse) Reset() {
+	*x = RegisterKeysResponse{}
+	mi := &file_dcp_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterKeysResponse) ProtoMessage() {}
+
+func (x *RegisterKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterKeysResponse.ProtoReflect.Descriptor instead.
+func (*RegisterKeysResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *RegisterKeysResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *RegisterKeysResponse) GetRegisteredKids() []string {
+	if x != nil {
+		return x.RegisteredKids
+	}
+	return nil
+}
+
+type LookupKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kid           string                 `protobuf:"bytes,1,opt,name=kid,proto3" json:"kid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupKeyRequest) Reset() {
+	*x = LookupKeyRequest{}
+	mi := &file_dcp_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupKeyRequest) ProtoMessage() {}
+
+func (x *LookupKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupKeyRequest.ProtoReflect.Descriptor instead.
+func (*LookupKeyRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *LookupKeyRequest) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+type LookupKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Key           *KeyEntryV2            `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	AgentId       string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupKeyResponse) Reset() {
+	*x = LookupKeyResponse{}
+	mi := &file_dcp_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupKeyResponse) ProtoMessage() {}
+
+func (x *LookupKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupKeyResponse.ProtoReflect.Descriptor instead.
+func (*LookupKeyResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *LookupKeyResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *LookupKeyResponse) GetKey() *KeyEntryV2 {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *LookupKeyResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type RotateKeyRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	OldKid            string                 `protobuf:"bytes,1,opt,name=old_kid,json=oldKid,proto3" json:"old_kid,omitempty"`
+	NewKey            *KeyEntryV2            `protobuf:"bytes,2,opt,name=new_key,json=newKey,proto3" json:"new_key,omitempty"`
+	ProofOfPossession *CompositeSignature    `protobuf:"bytes,3,opt,name=proof_of_possession,json=proofOfPossession,proto3" json:"proof_of_possession,omitempty"`
+	AuthorizationSig  *CompositeSignature    `protobuf:"bytes,4,opt,name=authorization_sig,json=authorizationSig,proto3" json:"authorization_sig,omitempty"`
+	Timestamp         string                 `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RotateKeyRequest) Reset() {
+	*x = RotateKeyRequest{}
+	mi := &file_dcp_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateKeyRequest) ProtoMessage() {}
+
+func (x *RotateKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateKeyRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *RotateKeyRequest) GetOldKid() string {
+	if x != nil {
+		return x.OldKid
+	}
+	return ""
+}
+
+func (x *RotateKeyRequest) GetNewKey() *KeyEntryV2 {
+	if x != nil {
+		return x.NewKey
+	}
+	return nil
+}
+
+func (x *RotateKeyRequest) GetProofOfPossession() *CompositeSignature {
+	if x != nil {
+		return x.ProofOfPossession
+	}
+	return nil
+}
+
+func (x *RotateKeyRequest) GetAuthorizationSig() *CompositeSignature {
+	if x != nil {
+		return x.AuthorizationSig
+	}
+	return nil
+}
+
+func (x *RotateKeyRequest) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+type RotateKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	OldKid        string                 `protobuf:"bytes,2,opt,name=old_kid,json=oldKid,proto3" json:"old_kid,omitempty"`
+	NewKid        string                 `protobuf:"bytes,3,opt,name=new_kid,json=newKid,proto3" json:"new_kid,omitempty"`
+	RotatedAt     string                 `protobuf:"bytes,4,opt,name=rotated_at,json=rotatedAt,proto3" json:"rotated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateKeyResponse) Reset() {
+	*x = RotateKeyResponse{}
+	mi := &file_dcp_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateKeyResponse) ProtoMessage() {}
+
+func (x *RotateKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateKeyResponse.ProtoReflect.Descriptor instead.
+func (*RotateKeyResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *RotateKeyResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *RotateKeyResponse) GetOldKid() string {
+	if x != nil {
+		return x.OldKid
+	}
+	return ""
+}
+
+func (x *RotateKeyResponse) GetNewKid() string {
+	if x != nil {
+		return x.NewKid
+	}
+	return ""
+}
+
+func (x *RotateKeyResponse) GetRotatedAt() string {
+	if x != nil {
+		return x.RotatedAt
+	}
+	return ""
+}
+
+type VerifyRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SignedBundleJson string                 `protobuf:"bytes,1,opt,name=signed_bundle_json,json=signedBundleJson,proto3" json:"signed_bundle_json,omitempty"`
+	// explain fills VerifyResponse.trace.
+	Explain       bool `protobuf:"varint,2,opt,name=explain,proto3" json:"explain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyRequest) Reset() {
+	*x = VerifyRequest{}
+	mi := &file_dcp_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRequest) ProtoMessage() {}
+
+func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *VerifyRequest) GetSignedBundleJson() string {
+	if x != nil {
+		return x.SignedBundleJson
+	}
+	return ""
+}
+
+func (x *VerifyRequest) GetExplain() bool {
+	if x != nil {
+		return x.Explain
+	}
+	return false
+}
+
+type TraceStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Check         string                 `protobuf:"bytes,1,opt,name=check,proto3" json:"check,omitempty"`
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	Ok            bool                   `protobuf:"varint,3,opt,name=ok,proto3" json:"ok,omitempty"`
+	Expected      string                 `protobuf:"bytes,4,opt,name=expected,proto3" json:"expected,omitempty"`
+	Actual        string                 `protobuf:"bytes,5,opt,name=actual,proto3" json:"actual,omitempty"`
+	Detail        string                 `protobuf:"bytes,6,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceStep) Reset() {
+	*x = TraceStep{}
+	mi := &file_dcp_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceStep) ProtoMessage() {}
+
+func (x *TraceStep) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceStep.ProtoReflect.Descriptor instead.
+func (*TraceStep) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *TraceStep) GetCheck() string {
+	if x != nil {
+		return x.Check
+	}
+	return ""
+}
+
+func (x *TraceStep) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *TraceStep) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *TraceStep) GetExpected() string {
+	if x != nil {
+		return x.Expected
+	}
+	return ""
+}
+
+func (x *TraceStep) GetActual() string {
+	if x != nil {
+		return x.Actual
+	}
+	return ""
+}
+
+func (x *TraceStep) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type VerifyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Verified      bool                   `protobuf:"varint,1,opt,name=verified,proto3" json:"verified,omitempty"`
+	Errors        []string               `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	Trace         []*TraceStep           `protobuf:"bytes,3,rep,name=trace,proto3" json:"trace,omitempty"`
+	BundleHash    string                 `protobuf:"bytes,4,opt,name=bundle_hash,json=bundleHash,proto3" json:"bundle_hash,omitempty"`
+	AgentId       string                 `protobuf:"bytes,5,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	HumanId       string                 `protobuf:"bytes,6,opt,name=human_id,json=humanId,proto3" json:"human_id,omitempty"`
+	SignerKey     string                 `protobuf:"bytes,7,opt,name=signer_key,json=signerKey,proto3" json:"signer_key,omitempty"`
+	Trusted       bool                   `protobuf:"varint,8,opt,name=trusted,proto3" json:"trusted,omitempty"`
+	Revocation    *RevocationRecord      `protobuf:"bytes,9,opt,name=revocation,proto3" json:"revocation,omitempty"`
+	CheckedAt     string                 `protobuf:"bytes,10,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyResponse) Reset() {
+	*x = VerifyResponse{}
+	mi := &file_dcp_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResponse) ProtoMessage() {}
+
+func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *VerifyResponse) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *VerifyResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *VerifyResponse) GetTrace() []*TraceStep {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+func (x *VerifyResponse) GetBundleHash() string {
+	if x != nil {
+		return x.BundleHash
+	}
+	return ""
+}
+
+func (x *VerifyResponse) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *VerifyResponse) GetHumanId() string {
+	if x != nil {
+		return x.HumanId
+	}
+	return ""
+}
+
+func (x *VerifyResponse) GetSignerKey() string {
+	if x != nil {
+		return x.SignerKey
+	}
+	return ""
+}
+
+func (x *VerifyResponse) GetTrusted() bool {
+	if x != nil {
+		return x.Trusted
+	}
+	return false
+}
+
+func (x *VerifyResponse) GetRevocation() *RevocationRecord {
+	if x != nil {
+		return x.Revocation
+	}
+	return nil
+}
+
+func (x *VerifyResponse) GetCheckedAt() string {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return ""
+}
+
+type GetPassportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPassportRequest) Reset() {
+	*x = GetPassportRequest{}
+	mi := &file_dcp_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPassportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPassportRequest) ProtoMessage() {}
+
+func (x *GetPassportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPassportRequest.ProtoReflect.Descriptor instead.
+func (*GetPassportRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetPassportRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type GetPassportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PassportJson  string                 `protobuf:"bytes,1,opt,name=passport_json,json=passportJson,proto3" json:"passport_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPassportResponse) Reset() {
+	*x = GetPassportResponse{}
+	mi := &file_dcp_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPassportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPassportResponse) ProtoMessage() {}
+
+func (x *GetPassportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPassportResponse.ProtoReflect.Descriptor instead.
+func (*GetPassportResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetPassportResponse) GetPassportJson() string {
+	if x != nil {
+		return x.PassportJson
+	}
+	return ""
+}
+
+type GetRevocationStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRevocationStatusRequest) Reset() {
+	*x = GetRevocationStatusRequest{}
+	mi := &file_dcp_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRevocationStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRevocationStatusRequest) ProtoMessage() {}
+
+func (x *GetRevocationStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRevocationStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetRevocationStatusRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *GetRevocationStatusRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type GetRevocationStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revoked       bool                   `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	Record        *RevocationRecord      `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRevocationStatusResponse) Reset() {
+	*x = GetRevocationStatusResponse{}
+	mi := &file_dcp_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRevocationStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRevocationStatusResponse) ProtoMessage() {}
+
+func (x *GetRevocationStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRevocationStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetRevocationStatusResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *GetRevocationStatusResponse) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
+}
+
+func (x *GetRevocationStatusResponse) GetRecord() *RevocationRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type AppendAuditRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// entry_json is a complete audit entry, agent_signature included, whose
+	// prev_hash is the current head of the agent's ledger.
+	EntryJson     string `protobuf:"bytes,1,opt,name=entry_json,json=entryJson,proto3" json:"entry_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppendAuditRequest) Reset() {
+	*x = AppendAuditRequest{}
+	mi := &file_dcp_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendAuditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendAuditRequest) ProtoMessage() {}
+
+func (x *AppendAuditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendAuditRequest.ProtoReflect.Descriptor instead.
+func (*AppendAuditRequest) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *AppendAuditRequest) GetEntryJson() string {
+	if x != nil {
+		return x.EntryJson
+	}
+	return ""
+}
+
+type AppendAuditResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int64                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Hash          string                 `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppendAuditResponse) Reset() {
+	*x = AppendAuditResponse{}
+	mi := &file_dcp_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendAuditResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendAuditResponse) ProtoMessage() {}
+
+func (x *AppendAuditResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dcp_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendAuditResponse.ProtoReflect.Descriptor instead.
+func (*AppendAuditResponse) Descriptor() ([]byte, []int) {
+	return file_dcp_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *AppendAuditResponse) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *AppendAuditResponse) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+var File_dcp_proto protoreflect.FileDescriptor
+
+const file_dcp_proto_rawDesc = "" +
+	"\n" +
+	"\tdcp.proto\x12\x06dcp.v1\"i\n" +
+	"\x13VerifyBundleRequest\x12,\n" +
+	"\x12signed_bundle_json\x18\x01 \x01(\tR\x10signedBundleJson\x12$\n" +
+	"\x0epublic_key_b64\x18\x02 \x01(\tR\fpublicKeyB64\"J\n" +
+	"\x14VerifyBundleResponse\x12\x1a\n" +
+	"\bverified\x18\x01 \x01(\bR\bverified\x12\x16\n" +
+	"\x06errors\x18\x02 \x03(\tR\x06errors\"8\n" +
+	"\x15ValidateBundleRequest\x12\x1f\n" +
+	"\vbundle_json\x18\x01 \x01(\tR\n" +
+	"bundleJson\"F\n" +
+	"\x16ValidateBundleResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x16\n" +
+	"\x06errors\x18\x02 \x03(\tR\x06errors\"\x14\n" +
+	"\x12HealthCheckRequest\"Y\n" +
+	"\x13HealthCheckResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x18\n" +
+	"\aservice\x18\x02 \x01(\tR\aservice\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\"J\n" +
+	"\x11AnchorHashRequest\x12\x1f\n" +
+	"\vbundle_hash\x18\x01 \x01(\tR\n" +
+	"bundleHash\x12\x14\n" +
+	"\x05chain\x18\x02 \x01(\tR\x05chain\"}\n" +
+	"\x12AnchorHashResponse\x12\x1a\n" +
+	"\banchored\x18\x01 \x01(\bR\banchored\x12\x17\n" +
+	"\atx_hash\x18\x02 \x01(\tR\x06txHash\x12\x14\n" +
+	"\x05chain\x18\x03 \x01(\tR\x05chain\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\"O\n" +
+	"\x12AnchorBatchRequest\x12#\n" +
+	"\rbundle_hashes\x18\x01 \x03(\tR\fbundleHashes\x12\x14\n" +
+	"\x05chain\x18\x02 \x01(\tR\x05chain\"\xb5\x01\n" +
+	"\x13AnchorBatchResponse\x12\x1a\n" +
+	"\banchored\x18\x01 \x01(\bR\banchored\x12\x1f\n" +
+	"\vmerkle_root\x18\x02 \x01(\tR\n" +
+	"merkleRoot\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\rR\x05count\x12\x17\n" +
+	"\atx_hash\x18\x04 \x01(\tR\x06txHash\x12\x14\n" +
+	"\x05chain\x18\x05 \x01(\tR\x05chain\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\tR\ttimestamp\"(\n" +
+	"\x12CheckAnchorRequest\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\"~\n" +
+	"\x13CheckAnchorResponse\x12\x1a\n" +
+	"\banchored\x18\x01 \x01(\bR\banchored\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\tR\ttimestamp\x12\x17\n" +
+	"\atx_hash\x18\x03 \x01(\tR\x06txHash\x12\x14\n" +
+	"\x05chain\x18\x04 \x01(\tR\x05chain\"\x9c\x01\n" +
+	"\rRevokeRequest\x12\x1f\n" +
+	"\vdcp_version\x18\x01 \x01(\tR\n" +
+	"dcpVersion\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x19\n" +
+	"\bhuman_id\x18\x03 \x01(\tR\ahumanId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1c\n" +
+	"\tsignature\x18\x05 \x01(\tR\tsignature\"Z\n" +
+	"\x0eRevokeResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\x03 \x01(\tR\trevokedAt\"3\n" +
+	"\x16CheckRevocationRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"e\n" +
+	"\x17CheckRevocationResponse\x12\x18\n" +
+	"\arevoked\x18\x01 \x01(\bR\arevoked\x120\n" +
+	"\x06record\x18\x02 \x01(\v2\x18.dcp.v1.RevocationRecordR\x06record\"F\n" +
+	"\x16ListRevocationsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\rR\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\rR\x06offset\"k\n" +
+	"\x17ListRevocationsResponse\x12:\n" +
+	"\vrevocations\x18\x01 \x03(\v2\x18.dcp.v1.RevocationRecordR\vrevocations\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\rR\x05total\"\xbd\x01\n" +
+	"\x10RevocationRecord\x12\x1f\n" +
+	"\vdcp_version\x18\x01 \x01(\tR\n" +
+	"dcpVersion\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x19\n" +
+	"\bhuman_id\x18\x03 \x01(\tR\ahumanId\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1c\n" +
+	"\tsignature\x18\x06 \x01(\tR\tsignature\"2\n" +
+	"\x0fAddEntryRequest\x12\x1f\n" +
+	"\vbundle_hash\x18\x01 \x01(\tR\n" +
+	"bundleHash\"m\n" +
+	"\x10AddEntryResponse\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x04R\x05index\x12\x1b\n" +
+	"\tleaf_hash\x18\x02 \x01(\tR\bleafHash\x12\x12\n" +
+	"\x04root\x18\x03 \x01(\tR\x04root\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x04R\x04size\"\x10\n" +
+	"\x0eGetRootRequest\"W\n" +
+	"\x0fGetRootResponse\x12\x12\n" +
+	"\x04root\x18\x01 \x01(\tR\x04root\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x04R\x04size\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\tR\ttimestamp\"'\n" +
+	"\x0fGetProofRequest\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x04R\x05index\"\x82\x01\n" +
+	"\x10GetProofResponse\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x04R\x05index\x12\x1b\n" +
+	"\tleaf_hash\x18\x02 \x01(\tR\bleafHash\x12\x12\n" +
+	"\x04root\x18\x03 \x01(\tR\x04root\x12'\n" +
+	"\x05proof\x18\x04 \x03(\v2\x11.dcp.v1.ProofNodeR\x05proof\"=\n" +
+	"\tProofNode\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x1c\n" +
+	"\tdirection\x18\x02 \x01(\tR\tdirection\"\xa5\x01\n" +
+	"\n" +
+	"KeyEntryV2\x12\x10\n" +
+	"\x03kid\x18\x01 \x01(\tR\x03kid\x12\x10\n" +
+	"\x03alg\x18\x02 \x01(\tR\x03alg\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\fR\tpublicKey\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\tR\texpiresAt\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\"F\n" +
+	"\x0eSignatureEntry\x12\x10\n" +
+	"\x03alg\x18\x01 \x01(\tR\x03alg\x12\x10\n" +
+	"\x03kid\x18\x02 \x01(\tR\x03kid\x12\x10\n" +
+	"\x03sig\x18\x03 \x01(\fR\x03sig\"\x8c\x01\n" +
+	"\x12CompositeSignature\x124\n" +
+	"\tclassical\x18\x01 \x01(\v2\x16.dcp.v1.SignatureEntryR\tclassical\x12&\n" +
+	"\x02pq\x18\x02 \x01(\v2\x16.dcp.v1.SignatureEntryR\x02pq\x12\x18\n" +
+	"\abinding\x18\x03 \x01(\tR\abinding\"\x8d\x01\n" +
+	"\rSignedPayload\x12\x18\n" +
+	"\apayload\x18\x01 \x01(\fR\apayload\x12!\n" +
+	"\fpayload_hash\x18\x02 \x01(\tR\vpayloadHash\x12?\n" +
+	"\rcomposite_sig\x18\x03 \x01(\v2\x1a.dcp.v1.CompositeSignatureR\fcompositeSig\"\xea\x02\n" +
+	"\x0eBundleManifest\x12#\n" +
+	"\rsession_nonce\x18\x01 \x01(\tR\fsessionNonce\x12\x19\n" +
+	"\brpr_hash\x18\x02 \x01(\tR\arprHash\x12#\n" +
+	"\rpassport_hash\x18\x03 \x01(\tR\fpassportHash\x12\x1f\n" +
+	"\vintent_hash\x18\x04 \x01(\tR\n" +
+	"intentHash\x12\x1f\n" +
+	"\vpolicy_hash\x18\x05 \x01(\tR\n" +
+	"policyHash\x12*\n" +
+	"\x11audit_merkle_root\x18\x06 \x01(\tR\x0fauditMerkleRoot\x12=\n" +
+	"\x1baudit_merkle_root_secondary\x18\a \x01(\tR\x18auditMerkleRootSecondary\x12\x1f\n" +
+	"\vaudit_count\x18\b \x01(\x05R\n" +
+	"auditCount\x12%\n" +
+	"\x0epq_checkpoints\x18\t \x03(\tR\rpqCheckpoints\"\x90\x03\n" +
+	"\x0fAgentPassportV2\x12\x1f\n" +
+	"\vdcp_version\x18\x01 \x01(\tR\n" +
+	"dcpVersion\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12#\n" +
+	"\rsession_nonce\x18\x03 \x01(\tR\fsessionNonce\x12&\n" +
+	"\x04keys\x18\x04 \x03(\v2\x12.dcp.v1.KeyEntryV2R\x04keys\x12>\n" +
+	"\x1bprincipal_binding_reference\x18\x05 \x01(\tR\x19principalBindingReference\x12\"\n" +
+	"\fcapabilities\x18\x06 \x03(\tR\fcapabilities\x12\x1b\n" +
+	"\trisk_tier\x18\a \x01(\tR\briskTier\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12<\n" +
+	"\x1aemergency_revocation_token\x18\n" +
+	" \x01(\tR\x18emergencyRevocationToken\"\xc0\x03\n" +
+	"\x1cResponsiblePrincipalRecordV2\x12\x1f\n" +
+	"\vdcp_version\x18\x01 \x01(\tR\n" +
+	"dcpVersion\x12\x19\n" +
+	"\bhuman_id\x18\x02 \x01(\tR\ahumanId\x12#\n" +
+	"\rsession_nonce\x18\x03 \x01(\tR\fsessionNonce\x12\x1d\n" +
+	"\n" +
+	"legal_name\x18\x04 \x01(\tR\tlegalName\x12\x1f\n" +
+	"\ventity_type\x18\x05 \x01(\tR\n" +
+	"entityType\x12\"\n" +
+	"\fjurisdiction\x18\x06 \x01(\tR\fjurisdiction\x12%\n" +
+	"\x0eliability_mode\x18\a \x01(\tR\rliabilityMode\x12'\n" +
+	"\x0foverride_rights\x18\b \x01(\bR\x0eoverrideRights\x12\x1b\n" +
+	"\tissued_at\x18\t \x01(\tR\bissuedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\n" +
+	" \x01(\tR\texpiresAt\x12\x18\n" +
+	"\acontact\x18\v \x01(\tR\acontact\x125\n" +
+	"\fbinding_keys\x18\f \x03(\v2\x12.dcp.v1.KeyEntryV2R\vbindingKeys\"\x8b\x03\n" +
+	"\bIntentV2\x12\x1f\n" +
+	"\vdcp_version\x18\x01 \x01(\tR\n" +
+	"dcpVersion\x12\x1b\n" +
+	"\tintent_id\x18\x02 \x01(\tR\bintentId\x12#\n" +
+	"\rsession_nonce\x18\x03 \x01(\tR\fsessionNonce\x12\x19\n" +
+	"\bagent_id\x18\x04 \x01(\tR\aagentId\x12\x19\n" +
+	"\bhuman_id\x18\x05 \x01(\tR\ahumanId\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\tR\ttimestamp\x12\x1f\n" +
+	"\vaction_type\x18\a \x01(\tR\n" +
+	"actionType\x12.\n" +
+	"\x06target\x18\b \x01(\v2\x16.dcp.v1.IntentTargetV2R\x06target\x12!\n" +
+	"\fdata_classes\x18\t \x03(\tR\vdataClasses\x12)\n" +
+	"\x10estimated_impact\x18\n" +
+	" \x01(\tR\x0festimatedImpact\x12)\n" +
+	"\x10requires_consent\x18\v \x01(\bR\x0frequiresConsent\"d\n" +
+	"\x0eIntentTargetV2\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\"\xcd\x02\n" +
+	"\x10PolicyDecisionV2\x12\x1f\n" +
+	"\vdcp_version\x18\x01 \x01(\tR\n" +
+	"dcpVersion\x12\x1b\n" +
+	"\tintent_id\x18\x02 \x01(\tR\bintentId\x12#\n" +
+	"\rsession_nonce\x18\x03 \x01(\tR\fsessionNonce\x12\x1a\n" +
+	"\bdecision\x18\x04 \x01(\tR\bdecision\x12\x1d\n" +
+	"\n" +
+	"risk_score\x18\x05 \x01(\x05R\triskScore\x12\x18\n" +
+	"\areasons\x18\x06 \x03(\tR\areasons\x123\n" +
+	"\x15required_confirmation\x18\a \x01(\tR\x14requiredConfirmation\x12.\n" +
+	"\x13applied_policy_hash\x18\b \x01(\tR\x11appliedPolicyHash\x12\x1c\n" +
+	"\ttimestamp\x18\t \x01(\tR\ttimestamp\"\xc1\x04\n" +
+	"\fAuditEventV2\x12\x1f\n" +
+	"\vdcp_version\x18\x01 \x01(\tR\n" +
+	"dcpVersion\x12\x19\n" +
+	"\baudit_id\x18\x02 \x01(\tR\aauditId\x12#\n" +
+	"\rsession_nonce\x18\x03 \x01(\tR\fsessionNonce\x12\x1b\n" +
+	"\tprev_hash\x18\x04 \x01(\tR\bprevHash\x12.\n" +
+	"\x13prev_hash_secondary\x18\x05 \x01(\tR\x11prevHashSecondary\x12\x19\n" +
+	"\bhash_alg\x18\x06 \x01(\tR\ahashAlg\x12\x1c\n" +
+	"\ttimestamp\x18\a \x01(\tR\ttimestamp\x12\x19\n" +
+	"\bagent_id\x18\b \x01(\tR\aagentId\x12\x19\n" +
+	"\bhuman_id\x18\t \x01(\tR\ahumanId\x12\x1b\n" +
+	"\tintent_id\x18\n" +
+	" \x01(\tR\bintentId\x12\x1f\n" +
+	"\vintent_hash\x18\v \x01(\tR\n" +
+	"intentHash\x122\n" +
+	"\x15intent_hash_secondary\x18\f \x01(\tR\x13intentHashSecondary\x12'\n" +
+	"\x0fpolicy_decision\x18\r \x01(\tR\x0epolicyDecision\x12\x18\n" +
+	"\aoutcome\x18\x0e \x01(\tR\aoutcome\x123\n" +
+	"\bevidence\x18\x0f \x01(\v2\x17.dcp.v1.AuditEvidenceV2R\bevidence\x12*\n" +
+	"\x11pq_checkpoint_ref\x18\x10 \x01(\tR\x0fpqCheckpointRef\"i\n" +
+	"\x0fAuditEvidenceV2\x12\x12\n" +
+	"\x04tool\x18\x01 \x01(\tR\x04tool\x12\x1d\n" +
+	"\n" +
+	"result_ref\x18\x02 \x01(\tR\tresultRef\x12#\n" +
+	"\revidence_hash\x18\x03 \x01(\tR\fevidenceHash\"\xef\x01\n" +
+	"\fPQCheckpoint\x12#\n" +
+	"\rcheckpoint_id\x18\x01 \x01(\tR\fcheckpointId\x12#\n" +
+	"\rsession_nonce\x18\x02 \x01(\tR\fsessionNonce\x123\n" +
+	"\vevent_range\x18\x03 \x01(\v2\x12.dcp.v1.EventRangeR\n" +
+	"eventRange\x12\x1f\n" +
+	"\vmerkle_root\x18\x04 \x01(\tR\n" +
+	"merkleRoot\x12?\n" +
+	"\rcomposite_sig\x18\x05 \x01(\v2\x1a.dcp.v1.CompositeSignatureR\fcompositeSig\"f\n" +
+	"\n" +
+	"EventRange\x12\"\n" +
+	"\rfrom_audit_id\x18\x01 \x01(\tR\vfromAuditId\x12\x1e\n" +
+	"\vto_audit_id\x18\x02 \x01(\tR\ttoAuditId\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\"\xf5\x03\n" +
+	"\x13CitizenshipBundleV2\x12,\n" +
+	"\x12dcp_bundle_version\x18\x01 \x01(\tR\x10dcpBundleVersion\x122\n" +
+	"\bmanifest\x18\x02 \x01(\v2\x16.dcp.v1.BundleManifestR\bmanifest\x12W\n" +
+	"\x1cresponsible_principal_record\x18\x03 \x01(\v2\x15.dcp.v1.SignedPayloadR\x1aresponsiblePrincipalRecord\x12<\n" +
+	"\x0eagent_passport\x18\x04 \x01(\v2\x15.dcp.v1.SignedPayloadR\ragentPassport\x12-\n" +
+	"\x06intent\x18\x05 \x01(\v2\x15.dcp.v1.SignedPayloadR\x06intent\x12>\n" +
+	"\x0fpolicy_decision\x18\x06 \x01(\v2\x15.dcp.v1.SignedPayloadR\x0epolicyDecision\x129\n" +
+	"\raudit_entries\x18\a \x03(\v2\x14.dcp.v1.AuditEventV2R\fauditEntries\x12;\n" +
+	"\x0epq_checkpoints\x18\b \x03(\v2\x14.dcp.v1.PQCheckpointR\rpqCheckpoints\"H\n" +
+	"\x0eBundleSignerV2\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kids\x18\x03 \x03(\tR\x04kids\"\xe3\x01\n" +
+	"\x11BundleSignatureV2\x12\x19\n" +
+	"\bhash_alg\x18\x01 \x01(\tR\ahashAlg\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\tR\tcreatedAt\x12.\n" +
+	"\x06signer\x18\x03 \x01(\v2\x16.dcp.v1.BundleSignerV2R\x06signer\x12#\n" +
+	"\rmanifest_hash\x18\x04 \x01(\tR\fmanifestHash\x12?\n" +
+	"\rcomposite_sig\x18\x05 \x01(\v2\x1a.dcp.v1.CompositeSignatureR\fcompositeSig\"~\n" +
+	"\x0eSignedBundleV2\x123\n" +
+	"\x06bundle\x18\x01 \x01(\v2\x1b.dcp.v1.CitizenshipBundleV2R\x06bundle\x127\n" +
+	"\tsignature\x18\x02 \x01(\v2\x19.dcp.v1.BundleSignatureV2R\tsignature\"\xb2\x01\n" +
+	"\x15VerifyBundleV2Request\x12.\n" +
+	"\x12signed_bundle_json\x18\x01 \x01(\tH\x00R\x10signedBundleJson\x12.\n" +
+	"\x12signed_bundle_cbor\x18\x02 \x01(\fH\x00R\x10signedBundleCbor\x120\n" +
+	"\x14verifier_policy_json\x18\x03 \x01(\tR\x12verifierPolicyJsonB\a\n" +
+	"\x05input\"\xd2\x02\n" +
+	"\x16VerifyBundleV2Response\x12\x1a\n" +
+	"\bverified\x18\x01 \x01(\bR\bverified\x12\x1f\n" +
+	"\vdcp_version\x18\x02 \x01(\tR\n" +
+	"dcpVersion\x12\x16\n" +
+	"\x06errors\x18\x03 \x03(\tR\x06errors\x12\x1a\n" +
+	"\bwarnings\x18\x04 \x03(\tR\bwarnings\x12'\n" +
+	"\x0fclassical_valid\x18\x05 \x01(\bR\x0eclassicalValid\x12\x19\n" +
+	"\bpq_valid\x18\x06 \x01(\bR\apqValid\x122\n" +
+	"\x15session_binding_valid\x18\a \x01(\bR\x13sessionBindingValid\x12%\n" +
+	"\x0emanifest_valid\x18\b \x01(\bR\rmanifestValid\x12(\n" +
+	"\x10hash_chain_valid\x18\t \x01(\bR\x0ehashChainValid\"\x80\x02\n" +
+	"\x0fRevokeV2Request\x12\x1f\n" +
+	"\vdcp_version\x18\x01 \x01(\tR\n" +
+	"dcpVersion\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x19\n" +
+	"\bhuman_id\x18\x03 \x01(\tR\ahumanId\x12\x1f\n" +
+	"\vrevoked_kid\x18\x04 \x01(\tR\n" +
+	"revokedKid\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\tR\ttimestamp\x12?\n" +
+	"\rcomposite_sig\x18\a \x01(\v2\x1a.dcp.v1.CompositeSignatureR\fcompositeSig\"}\n" +
+	"\x10RevokeV2Response\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x1f\n" +
+	"\vrevoked_kid\x18\x03 \x01(\tR\n" +
+	"revokedKid\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\x04 \x01(\tR\trevokedAt\"\xb1\x01\n" +
+	"\x16EmergencyRevokeRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x19\n" +
+	"\bhuman_id\x18\x02 \x01(\tR\ahumanId\x12+\n" +
+	"\x11revocation_secret\x18\x03 \x01(\tR\x10revocationSecret\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"\x86\x01\n" +
+	"\x17EmergencyRevokeResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\x03 \x01(\tR\trevokedAt\x12!\n" +
+	"\fkeys_revoked\x18\x04 \x01(\x05R\vkeysRevoked\"/\n" +
+	"\x1bCheckRevocationByKidRequest\x12\x10\n" +
+	"\x03kid\x18\x01 \x01(\tR\x03kid\"\x9c\x01\n" +
+	"\x1cCheckRevocationByKidResponse\x12\x18\n" +
+	"\arevoked\x18\x01 \x01(\bR\arevoked\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x10\n" +
+	"\x03kid\x18\x03 \x01(\tR\x03kid\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\x04 \x01(\tR\trevokedAt\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"\xa4\x01\n" +
+	"\x13RegisterKeysRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12&\n" +
+	"\x04keys\x18\x02 \x03(\v2\x12.dcp.v1.KeyEntryV2R\x04keys\x12J\n" +
+	"\x13proof_of_possession\x18\x03 \x01(\v2\x1a.dcp.v1.CompositeSignatureR\x11proofOfPossession\"O\n" +
+	"\x14RegisterKeysResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12'\n" +
+	"\x0fregistered_kids\x18\x02 \x03(\tR\x0eregisteredKids\"$\n" +
+	"\x10LookupKeyRequest\x12\x10\n" +
+	"\x03kid\x18\x01 \x01(\tR\x03kid\"j\n" +
+	"\x11LookupKeyResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12$\n" +
+	"\x03key\x18\x02 \x01(\v2\x12.dcp.v1.KeyEntryV2R\x03key\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\"\x8b\x02\n" +
+	"\x10RotateKeyRequest\x12\x17\n" +
+	"\aold_kid\x18\x01 \x01(\tR\x06oldKid\x12+\n" +
+	"\anew_key\x18\x02 \x01(\v2\x12.dcp.v1.KeyEntryV2R\x06newKey\x12J\n" +
+	"\x13proof_of_possession\x18\x03 \x01(\v2\x1a.dcp.v1.CompositeSignatureR\x11proofOfPossession\x12G\n" +
+	"\x11authorization_sig\x18\x04 \x01(\v2\x1a.dcp.v1.CompositeSignatureR\x10authorizationSig\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\tR\ttimestamp\"t\n" +
+	"\x11RotateKeyResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x17\n" +
+	"\aold_kid\x18\x02 \x01(\tR\x06oldKid\x12\x17\n" +
+	"\anew_kid\x18\x03 \x01(\tR\x06newKid\x12\x1d\n" +
+	"\n" +
+	"rotated_at\x18\x04 \x01(\tR\trotatedAt\"W\n" +
+	"\rVerifyRequest\x12,\n" +
+	"\x12signed_bundle_json\x18\x01 \x01(\tR\x10signedBundleJson\x12\x18\n" +
+	"\aexplain\x18\x02 \x01(\bR\aexplain\"\x95\x01\n" +
+	"\tTraceStep\x12\x14\n" +
+	"\x05check\x18\x01 \x01(\tR\x05check\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x0e\n" +
+	"\x02ok\x18\x03 \x01(\bR\x02ok\x12\x1a\n" +
+	"\bexpected\x18\x04 \x01(\tR\bexpected\x12\x16\n" +
+	"\x06actual\x18\x05 \x01(\tR\x06actual\x12\x16\n" +
+	"\x06detail\x18\x06 \x01(\tR\x06detail\"\xd6\x02\n" +
+	"\x0eVerifyResponse\x12\x1a\n" +
+	"\bverified\x18\x01 \x01(\bR\bverified\x12\x16\n" +
+	"\x06errors\x18\x02 \x03(\tR\x06errors\x12'\n" +
+	"\x05trace\x18\x03 \x03(\v2\x11.dcp.v1.TraceStepR\x05trace\x12\x1f\n" +
+	"\vbundle_hash\x18\x04 \x01(\tR\n" +
+	"bundleHash\x12\x19\n" +
+	"\bagent_id\x18\x05 \x01(\tR\aagentId\x12\x19\n" +
+	"\bhuman_id\x18\x06 \x01(\tR\ahumanId\x12\x1d\n" +
+	"\n" +
+	"signer_key\x18\a \x01(\tR\tsignerKey\x12\x18\n" +
+	"\atrusted\x18\b \x01(\bR\atrusted\x128\n" +
+	"\n" +
+	"revocation\x18\t \x01(\v2\x18.dcp.v1.RevocationRecordR\n" +
+	"revocation\x12\x1d\n" +
+	"\n" +
+	"checked_at\x18\n" +
+	" \x01(\tR\tcheckedAt\"/\n" +
+	"\x12GetPassportRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\":\n" +
+	"\x13GetPassportResponse\x12#\n" +
+	"\rpassport_json\x18\x01 \x01(\tR\fpassportJson\"7\n" +
+	"\x1aGetRevocationStatusRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"i\n" +
+	"\x1bGetRevocationStatusResponse\x12\x18\n" +
+	"\arevoked\x18\x01 \x01(\bR\arevoked\x120\n" +
+	"\x06record\x18\x02 \x01(\v2\x18.dcp.v1.RevocationRecordR\x06record\"3\n" +
+	"\x12AppendAuditRequest\x12\x1d\n" +
+	"\n" +
+	"entry_json\x18\x01 \x01(\tR\tentryJson\"?\n" +
+	"\x13AppendAuditResponse\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x03R\x05index\x12\x12\n" +
+	"\x04hash\x18\x02 \x01(\tR\x04hash2\xca\x02\n" +
+	"\x13VerificationService\x12I\n" +
+	"\fVerifyBundle\x12\x1b.dcp.v1.VerifyBundleRequest\x1a\x1c.dcp.v1.VerifyBundleResponse\x12O\n" +
+	"\x0eValidateBundle\x12\x1d.dcp.v1.ValidateBundleRequest\x1a\x1e.dcp.v1.ValidateBundleResponse\x12O\n" +
+	"\x0eVerifyBundleV2\x12\x1d.dcp.v1.VerifyBundleV2Request\x1a\x1e.dcp.v1.VerifyBundleV2Response\x12F\n" +
+	"\vHealthCheck\x12\x1a.dcp.v1.HealthCheckRequest\x1a\x1b.dcp.v1.HealthCheckResponse2\xe4\x01\n" +
+	"\rAnchorService\x12C\n" +
+	"\n" +
+	"AnchorHash\x12\x19.dcp.v1.AnchorHashRequest\x1a\x1a.dcp.v1.AnchorHashResponse\x12F\n" +
+	"\vAnchorBatch\x12\x1a.dcp.v1.AnchorBatchRequest\x1a\x1b.dcp.v1.AnchorBatchResponse\x12F\n" +
+	"\vCheckAnchor\x12\x1a.dcp.v1.CheckAnchorRequest\x1a\x1b.dcp.v1.CheckAnchorResponse2\xea\x03\n" +
+	"\x11RevocationService\x127\n" +
+	"\x06Revoke\x12\x15.dcp.v1.RevokeRequest\x1a\x16.dcp.v1.RevokeResponse\x12=\n" +
+	"\bRevokeV2\x12\x17.dcp.v1.RevokeV2Request\x1a\x18.dcp.v1.RevokeV2Response\x12R\n" +
+	"\x0fEmergencyRevoke\x12\x1e.dcp.v1.EmergencyRevokeRequest\x1a\x1f.dcp.v1.EmergencyRevokeResponse\x12R\n" +
+	"\x0fCheckRevocation\x12\x1e.dcp.v1.CheckRevocationRequest\x1a\x1f.dcp.v1.CheckRevocationResponse\x12a\n" +
+	"\x14CheckRevocationByKid\x12#.dcp.v1.CheckRevocationByKidRequest\x1a$.dcp.v1.CheckRevocationByKidResponse\x12R\n" +
+	"\x0fListRevocations\x12\x1e.dcp.v1.ListRevocationsRequest\x1a\x1f.dcp.v1.ListRevocationsResponse2\xd2\x01\n" +
+	"\x16TransparencyLogService\x12=\n" +
+	"\bAddEntry\x12\x17.dcp.v1.AddEntryRequest\x1a\x18.dcp.v1.AddEntryResponse\x12:\n" +
+	"\aGetRoot\x12\x16.dcp.v1.GetRootRequest\x1a\x17.dcp.v1.GetRootResponse\x12=\n" +
+	"\bGetProof\x12\x17.dcp.v1.GetProofRequest\x1a\x18.dcp.v1.GetProofResponse2\xdb\x01\n" +
+	"\n" +
+	"KeyService\x12I\n" +
+	"\fRegisterKeys\x12\x1b.dcp.v1.RegisterKeysRequest\x1a\x1c.dcp.v1.RegisterKeysResponse\x12@\n" +
+	"\tLookupKey\x12\x18.dcp.v1.LookupKeyRequest\x1a\x19.dcp.v1.LookupKeyResponse\x12@\n" +
+	"\tRotateKey\x12\x18.dcp.v1.RotateKeyRequest\x1a\x19.dcp.v1.RotateKeyResponse2\xb5\x02\n" +
+	"\n" +
+	"DcpService\x127\n" +
+	"\x06Verify\x12\x15.dcp.v1.VerifyRequest\x1a\x16.dcp.v1.VerifyResponse\x12F\n" +
+	"\vGetPassport\x12\x1a.dcp.v1.GetPassportRequest\x1a\x1b.dcp.v1.GetPassportResponse\x12^\n" +
+	"\x13GetRevocationStatus\x12\".dcp.v1.GetRevocationStatusRequest\x1a#.dcp.v1.GetRevocationStatusResponse\x12F\n" +
+	"\vAppendAudit\x12\x1a.dcp.v1.AppendAuditRequest\x1a\x1b.dcp.v1.AppendAuditResponseB>Z<github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcpv1;dcpv1b\x06proto3"
+
+var (
+	file_dcp_proto_rawDescOnce sync.Once
+	file_dcp_proto_rawDescData []byte
+)
+
+func file_dcp_proto_rawDescGZIP() []byte {
+	file_dcp_proto_rawDescOnce.Do(func() {
+		file_dcp_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_dcp_proto_rawDesc), len(file_dcp_proto_rawDesc)))
+	})
+	return file_dcp_proto_rawDescData
+}
+
+var file_dcp_proto_msgTypes = make([]protoimpl.MessageInfo, 67)
+var file_dcp_proto_goTypes = []any{
+	(*VerifyBundleRequest)(nil),          // 0: dcp.v1.VerifyBundleRequest
+	(*VerifyBundleResponse)(nil),         // 1: dcp.v1.VerifyBundleResponse
+	(*ValidateBundleRequest)(nil),        // 2: dcp.v1.ValidateBundleRequest
+	(*ValidateBundleResponse)(nil),       // 3: dcp.v1.ValidateBundleResponse
+	(*HealthCheckRequest)(nil),           // 4: dcp.v1.HealthCheckRequest
+	(*HealthCheckResponse)(nil),          // 5: dcp.v1.HealthCheckResponse
+	(*AnchorHashRequest)(nil),            // 6: dcp.v1.AnchorHashRequest
+	(*AnchorHashResponse)(nil),           // 7: dcp.v1.AnchorHashResponse
+	(*AnchorBatchRequest)(nil),           // 8: dcp.v1.AnchorBatchRequest
+	(*AnchorBatchResponse)(nil),          // 9: dcp.v1.AnchorBatchResponse
+	(*CheckAnchorRequest)(nil),           // 10: dcp.v1.CheckAnchorRequest
+	(*CheckAnchorResponse)(nil),          // 11: dcp.v1.CheckAnchorResponse
+	(*RevokeRequest)(nil),                // 12: dcp.v1.RevokeRequest
+	(*RevokeResponse)(nil),               // 13: dcp.v1.RevokeResponse
+	(*CheckRevocationRequest)(nil),       // 14: dcp.v1.CheckRevocationRequest
+	(*CheckRevocationResponse)(nil),      // 15: dcp.v1.CheckRevocationResponse
+	(*ListRevocationsRequest)(nil),       // 16: dcp.v1.ListRevocationsRequest
+	(*ListRevocationsResponse)(nil),      // 17: dcp.v1.ListRevocationsResponse
+	(*RevocationRecord)(nil),             // 18: dcp.v1.RevocationRecord
+	(*AddEntryRequest)(nil),              // 19: dcp.v1.AddEntryRequest
+	(*AddEntryResponse)(nil),             // 20: dcp.v1.AddEntryResponse
+	(*GetRootRequest)(nil),               // 21: dcp.v1.GetRootRequest
+	(*GetRootResponse)(nil),              // 22: dcp.v1.GetRootResponse
+	(*GetProofRequest)(nil),              // 23: dcp.v1.GetProofRequest
+	(*GetProofResponse)(nil),             // 24: dcp.v1.GetProofResponse
+	(*ProofNode)(nil),                    // 25: dcp.v1.ProofNode
+	(*KeyEntryV2)(nil),                   // 26: dcp.v1.KeyEntryV2
+	(*SignatureEntry)(nil),               // 27: dcp.v1.SignatureEntry
+	(*CompositeSignature)(nil),           // 28: dcp.v1.CompositeSignature
+	(*SignedPayload)(nil),                // 29: dcp.v1.SignedPayload
+	(*BundleManifest)(nil),               // 30: dcp.v1.BundleManifest
+	(*AgentPassportV2)(nil),              // 31: dcp.v1.AgentPassportV2
+	(*ResponsiblePrincipalRecordV2)(nil), // 32: dcp.v1.ResponsiblePrincipalRecordV2
+	(*IntentV2)(nil),                     // 33: dcp.v1.IntentV2
+	(*IntentTargetV2)(nil),               // 34: dcp.v1.IntentTargetV2
+	(*PolicyDecisionV2)(nil),             // 35: dcp.v1.PolicyDecisionV2
+	(*AuditEventV2)(nil),                 // 36: dcp.v1.AuditEventV2
+	(*AuditEvidenceV2)(nil),              // 37: dcp.v1.AuditEvidenceV2
+	(*PQCheckpoint)(nil),                 // 38: dcp.v1.PQCheckpoint
+	(*EventRange)(nil),                   // 39: dcp.v1.EventRange
+	(*CitizenshipBundleV2)(nil),          // 40: dcp.v1.CitizenshipBundleV2
+	(*BundleSignerV2)(nil),               // 41: dcp.v1.BundleSignerV2
+	(*BundleSignatureV2)(nil),            // 42: dcp.v1.BundleSignatureV2
+	(*SignedBundleV2)(nil),               // 43: dcp.v1.SignedBundleV2
+	(*VerifyBundleV2Request)(nil),        // 44: dcp.v1.VerifyBundleV2Request
+	(*VerifyBundleV2Response)(nil),       // 45: dcp.v1.VerifyBundleV2Response
+	(*RevokeV2Request)(nil),              // 46: dcp.v1.RevokeV2Request
+	(*RevokeV2Response)(nil),             // 47: dcp.v1.RevokeV2Response
+	(*EmergencyRevokeRequest)(nil),       // 48: dcp.v1.EmergencyRevokeRequest
+	(*EmergencyRevokeResponse)(nil),      // 49: dcp.v1.EmergencyRevokeResponse
+	(*CheckRevocationByKidRequest)(nil),  // 50: dcp.v1.CheckRevocationByKidRequest
+	(*CheckRevocationByKidResponse)(nil), // 51: dcp.v1.CheckRevocationByKidResponse
+	(*RegisterKeysRequest)(nil),          // 52: dcp.v1.RegisterKeysRequest
+	(*RegisterKeysResponse)(nil),         // 53: dcp.v1.RegisterKeysResponse
+	(*LookupKeyRequest)(nil),             // 54: dcp.v1.LookupKeyRequest
+	(*LookupKeyResponse)(nil),            // 55: dcp.v1.LookupKeyResponse
+	(*RotateKeyRequest)(nil),             // 56: dcp.v1.RotateKeyRequest
+	(*RotateKeyResponse)(nil),            // 57: dcp.v1.RotateKeyResponse
+	(*VerifyRequest)(nil),                // 58: dcp.v1.VerifyRequest
+	(*TraceStep)(nil),                    // 59: dcp.v1.TraceStep
+	(*VerifyResponse)(nil),               // 60: dcp.v1.VerifyResponse
+	(*GetPassportRequest)(nil),           // 61: dcp.v1.GetPassportRequest
+	(*GetPassportResponse)(nil),          // 62: dcp.v1.GetPassportResponse
+	(*GetRevocationStatusRequest)(nil),   // 63: dcp.v1.GetRevocationStatusRequest
+	(*GetRevocationStatusResponse)(nil),  // 64: dcp.v1.GetRevocationStatusResponse
+	(*AppendAuditRequest)(nil),           // 65: dcp.v1.AppendAuditRequest
+	(*AppendAuditResponse)(nil),          // 66: dcp.v1.AppendAuditResponse
+}
+var file_dcp_proto_depIdxs = []int32{
+	18, // 0: dcp.v1.CheckRevocationResponse.record:type_name -> dcp.v1.RevocationRecord
+	18, // 1: dcp.v1.ListRevocationsResponse.revocations:type_name -> dcp.v1.RevocationRecord
+	25, // 2: dcp.v1.GetProofResponse.proof:type_name -> dcp.v1.ProofNode
+	27, // 3: dcp.v1.CompositeSignature.classical:type_name -> dcp.v1.SignatureEntry
+	27, // 4: dcp.v1.CompositeSignature.pq:type_name -> dcp.v1.SignatureEntry
+	28, // 5: dcp.v1.SignedPayload.composite_sig:type_name -> dcp.v1.CompositeSignature
+	26, // 6: dcp.v1.AgentPassportV2.keys:type_name -> dcp.v1.KeyEntryV2
+	26, // 7: dcp.v1.ResponsiblePrincipalRecordV2.binding_keys:type_name -> dcp.v1.KeyEntryV2
+	34, // 8: dcp.v1.IntentV2.target:type_name -> dcp.v1.IntentTargetV2
+	37, // 9: dcp.v1.AuditEventV2.evidence:type_name -> dcp.v1.AuditEvidenceV2
+	39, // 10: dcp.v1.PQCheckpoint.event_range:type_name -> dcp.v1.EventRange
+	28, // 11: dcp.v1.PQCheckpoint.composite_sig:type_name -> dcp.v1.CompositeSignature
+	30, // 12: dcp.v1.CitizenshipBundleV2.manifest:type_name -> dcp.v1.BundleManifest
+	29, // 13: dcp.v1.CitizenshipBundleV2.responsible_principal_record:type_name -> dcp.v1.SignedPayload
+	29, // 14: dcp.v1.CitizenshipBundleV2.agent_passport:type_name -> dcp.v1.SignedPayload
+	29, // 15: dcp.v1.CitizenshipBundleV2.intent:type_name -> dcp.v1.SignedPayload
+	29, // 16: dcp.v1.CitizenshipBundleV2.policy_decision:type_name -> dcp.v1.SignedPayload
+	36, // 17: dcp.v1.CitizenshipBundleV2.audit_entries:type_name -> dcp.v1.AuditEventV2
+	38, // 18: dcp.v1.CitizenshipBundleV2.pq_checkpoints:type_name -> dcp.v1.PQCheckpoint
+	41, // 19: dcp.v1.BundleSignatureV2.signer:type_name -> dcp.v1.BundleSignerV2
+	28, // 20: dcp.v1.BundleSignatureV2.composite_sig:type_name -> dcp.v1.CompositeSignature
+	40, // 21: dcp.v1.SignedBundleV2.bundle:type_name -> dcp.v1.CitizenshipBundleV2
+	42, // 22: dcp.v1.SignedBundleV2.signature:type_name -> dcp.v1.BundleSignatureV2
+	28, // 23: dcp.v1.RevokeV2Request.composite_sig:type_name -> dcp.v1.CompositeSignature
+	26, // 24: dcp.v1.RegisterKeysRequest.keys:type_name -> dcp.v1.KeyEntryV2
+	28, // 25: dcp.v1.RegisterKeysRequest.proof_of_possession:type_name -> dcp.v1.CompositeSignature
+	26, // 26: dcp.v1.LookupKeyResponse.key:type_name -> dcp.v1.KeyEntryV2
+	26, // 27: dcp.v1.RotateKeyRequest.new_key:type_name -> dcp.v1.KeyEntryV2
+	28, // 28: dcp.v1.RotateKeyRequest.proof_of_possession:type_name -> dcp.v1.CompositeSignature
+	28, // 29: dcp.v1.RotateKeyRequest.authorization_sig:type_name -> dcp.v1.CompositeSignature
+	59, // 30: dcp.v1.VerifyResponse.trace:type_name -> dcp.v1.TraceStep
+	18, // 31: dcp.v1.VerifyResponse.revocation:type_name -> dcp.v1.RevocationRecord
+	18, // 32: dcp.v1.GetRevocationStatusResponse.record:type_name -> dcp.v1.RevocationRecord
+	0,  // 33: dcp.v1.VerificationService.VerifyBundle:input_type -> dcp.v1.VerifyBundleRequest
+	2,  // 34: dcp.v1.VerificationService.ValidateBundle:input_type -> dcp.v1.ValidateBundleRequest
+	44, // 35: dcp.v1.VerificationService.VerifyBundleV2:input_type -> dcp.v1.VerifyBundleV2Request
+	4,  // 36: dcp.v1.VerificationService.HealthCheck:input_type -> dcp.v1.HealthCheckRequest
+	6,  // 37: dcp.v1.AnchorService.AnchorHash:input_type -> dcp.v1.AnchorHashRequest
+	8,  // 38: dcp.v1.AnchorService.AnchorBatch:input_type -> dcp.v1.AnchorBatchRequest
+	10, // 39: dcp.v1.AnchorService.CheckAnchor:input_type -> dcp.v1.CheckAnchorRequest
+	12, // 40: dcp.v1.RevocationService.Revoke:input_type -> dcp.v1.RevokeRequest
+	46, // 41: dcp.v1.RevocationService.RevokeV2:input_type -> dcp.v1.RevokeV2Request
+	48, // 42: dcp.v1.RevocationService.EmergencyRevoke:input_type -> dcp.v1.EmergencyRevokeRequest
+	14, // 43: dcp.v1.RevocationService.CheckRevocation:input_type -> dcp.v1.CheckRevocationRequest
+	50, // 44: dcp.v1.RevocationService.CheckRevocationByKid:input_type -> dcp.v1.CheckRevocationByKidRequest
+	16, // 45: dcp.v1.RevocationService.ListRevocations:input_type -> dcp.v1.ListRevocationsRequest
+	19, // 46: dcp.v1.TransparencyLogService.AddEntry:input_type -> dcp.v1.AddEntryRequest
+	21, // 47: dcp.v1.TransparencyLogService.GetRoot:input_type -> dcp.v1.GetRootRequest
+	23, // 48: dcp.v1.TransparencyLogService.GetProof:input_type -> dcp.v1.GetProofRequest
+	52, // 49: dcp.v1.KeyService.RegisterKeys:input_type -> dcp.v1.RegisterKeysRequest
+	54, // 50: dcp.v1.KeyService.LookupKey:input_type -> dcp.v1.LookupKeyRequest
+	56, // 51: dcp.v1.KeyService.RotateKey:input_type -> dcp.v1.RotateKeyRequest
+	58, // 52: dcp.v1.DcpService.Verify:input_type -> dcp.v1.VerifyRequest
+	61, // 53: dcp.v1.DcpService.GetPassport:input_type -> dcp.v1.GetPassportRequest
+	63, // 54: dcp.v1.DcpService.GetRevocationStatus:input_type -> dcp.v1.GetRevocationStatusRequest
+	65, // 55: dcp.v1.DcpService.AppendAudit:input_type -> dcp.v1.AppendAuditRequest
+	1,  // 56: dcp.v1.VerificationService.VerifyBundle:output_type -> dcp.v1.VerifyBundleResponse
+	3,  // 57: dcp.v1.VerificationService.ValidateBundle:output_type -> dcp.v1.ValidateBundleResponse
+	45, // 58: dcp.v1.VerificationService.VerifyBundleV2:output_type -> dcp.v1.VerifyBundleV2Response
+	5,  // 59: dcp.v1.VerificationService.HealthCheck:output_type -> dcp.v1.HealthCheckResponse
+	7,  // 60: dcp.v1.AnchorService.AnchorHash:output_type -> dcp.v1.AnchorHashResponse
+	9,  // 61: dcp.v1.AnchorService.AnchorBatch:output_type -> dcp.v1.AnchorBatchResponse
+	11, // 62: dcp.v1.AnchorService.CheckAnchor:output_type -> dcp.v1.CheckAnchorResponse
+	13, // 63: dcp.v1.RevocationService.Revoke:output_type -> dcp.v1.RevokeResponse
+	47, // 64: dcp.v1.RevocationService.RevokeV2:output_type -> dcp.v1.RevokeV2Response
+	49, // 65: dcp.v1.RevocationService.EmergencyRevoke:output_type -> dcp.v1.EmergencyRevokeResponse
+	15, // 66: dcp.v1.RevocationService.CheckRevocation:output_type -> dcp.v1.CheckRevocationResponse
+	51, // 67: dcp.v1.RevocationService.CheckRevocationByKid:output_type -> dcp.v1.CheckRevocationByKidResponse
+	17, // 68: dcp.v1.RevocationService.ListRevocations:output_type -> dcp.v1.ListRevocationsResponse
+	20, // 69: dcp.v1.TransparencyLogService.AddEntry:output_type -> dcp.v1.AddEntryResponse
+	22, // 70: dcp.v1.TransparencyLogService.GetRoot:output_type -> dcp.v1.GetRootResponse
+	24, // 71: dcp.v1.TransparencyLogService.GetProof:output_type -> dcp.v1.GetProofResponse
+	53, // 72: dcp.v1.KeyService.RegisterKeys:output_type -> dcp.v1.RegisterKeysResponse
+	55, // 73: dcp.v1.KeyService.LookupKey:output_type -> dcp.v1.LookupKeyResponse
+	57, // 74: dcp.v1.KeyService.RotateKey:output_type -> dcp.v1.RotateKeyResponse
+	60, // 75: dcp.v1.DcpService.Verify:output_type -> dcp.v1.VerifyResponse
+	62, // 76: dcp.v1.DcpService.GetPassport:output_type -> dcp.v1.GetPassportResponse
+	64, // 77: dcp.v1.DcpService.GetRevocationStatus:output_type -> dcp.v1.GetRevocationStatusResponse
+	66, // 78: dcp.v1.DcpService.AppendAudit:output_type -> dcp.v1.AppendAuditResponse
+	56, // [56:79] is the sub-list for method output_type
+	33, // [33:56] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
+}
+
+func init() { file_dcp_proto_init() }
+func file_dcp_proto_init() {
+	if File_dcp_proto != nil {
+		return
+	}
+	file_dcp_proto_msgTypes[44].OneofWrappers = []any{
+		(*VerifyBundleV2Request_SignedBundleJson)(nil),
+		(*VerifyBundleV2Request_SignedBundleCbor)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_dcp_proto_rawDesc), len(file_dcp_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   67,
+			NumExtensions: 0,
+			NumServices:   6,
+		},
+		GoTypes:           file_dcp_proto_goTypes,
+		DependencyIndexes: file_dcp_proto_depIdxs,
+		MessageInfos:      file_dcp_proto_msgTypes,
+	}.Build()
+	File_dcp_proto = out.File
+	file_dcp_proto_goTypes = nil
+	file_dcp_proto_depIdxs = nil
+}
@@ -0,0 +1,1294 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: dcp.proto
+
+package dcpv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VerificationService_VerifyBundle_FullMethodName   = "/dcp.v1.VerificationService/VerifyBundle"
+	VerificationService_ValidateBundle_FullMethodName = "/dcp.v1.VerificationService/ValidateBundle"
+	VerificationService_VerifyBundleV2_FullMethodName = "/dcp.v1.VerificationService/VerifyBundleV2"
+	VerificationService_HealthCheck_FullMethodName    = "/dcp.v1.VerificationService/HealthCheck"
+)
+
+// VerificationServiceClient is the client API for VerificationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type VerificationServiceClient interface {
+	VerifyBundle(ctx context.Context, in *VerifyBundleRequest, opts ...grpc.CallOption) (*VerifyBundleResponse, error)
+	ValidateBundle(ctx context.Context, in *ValidateBundleRequest, opts ...grpc.CallOption) (*ValidateBundleResponse, error)
+	VerifyBundleV2(ctx context.Context, in *VerifyBundleV2Request, opts ...grpc.CallOption) (*VerifyBundleV2Response, error)
+	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
+}
+
+type verificationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVerificationServiceClient(cc grpc.ClientConnInterface) VerificationServiceClient {
+	return &verificationServiceClient{cc}
+}
+
+func (c *verificationServiceClient) VerifyBundle(ctx context.Context, in *VerifyBundleRequest, opts ...grpc.CallOption) (*VerifyBundleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyBundleResponse)
+	err := c.cc.Invoke(ctx, VerificationService_VerifyBundle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *verificationServiceClient) ValidateBundle(ctx context.Context, in *ValidateBundleRequest, opts ...grpc.CallOption) (*ValidateBundleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateBundleResponse)
+	err := c.cc.Invoke(ctx, VerificationService_ValidateBundle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *verificationServiceClient) VerifyBundleV2(ctx context.Context, in *VerifyBundleV2Request, opts ...grpc.CallOption) (*VerifyBundleV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyBundleV2Response)
+	err := c.cc.Invoke(ctx, VerificationService_VerifyBundleV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *verificationServiceClient) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthCheckResponse)
+	err := c.cc.Invoke(ctx, VerificationService_HealthCheck_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VerificationServiceServer is the server API for VerificationService service.
+// All implementations must embed UnimplementedVerificationServiceServer
+// for forward compatibility.
+type VerificationServiceServer interface {
+	VerifyBundle(context.Context, *VerifyBundleRequest) (*VerifyBundleResponse, error)
+	ValidateBundle(context.Context, *ValidateBundleRequest) (*ValidateBundleResponse, error)
+	VerifyBundleV2(context.Context, *VerifyBundleV2Request) (*VerifyBundleV2Response, error)
+	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
+	mustEmbedUnimplementedVerificationServiceServer()
+}
+
+// UnimplementedVerificationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVerificationServiceServer struct{}
+
+func (UnimplementedVerificationServiceServer) VerifyBundle(context.Context, *VerifyBundleRequest) (*VerifyBundleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyBundle not implemented")
+}
+func (UnimplementedVerificationServiceServer) ValidateBundle(context.Context, *ValidateBundleRequest) (*ValidateBundleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateBundle not implemented")
+}
+func (UnimplementedVerificationServiceServer) VerifyBundleV2(context.Context, *VerifyBundleV2Request) (*VerifyBundleV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyBundleV2 not implemented")
+}
+func (UnimplementedVerificationServiceServer) HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
+}
+func (UnimplementedVerificationServiceServer) mustEmbedUnimplementedVerificationServiceServer() {}
+func (UnimplementedVerificationServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeVerificationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VerificationServiceServer will
+// result in compilation errors.
+type UnsafeVerificationServiceServer interface {
+	mustEmbedUnimplementedVerificationServiceServer()
+}
+
+func RegisterVerificationServiceServer(s grpc.ServiceRegistrar, srv VerificationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVerificationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VerificationService_ServiceDesc, srv)
+}
+
+func _VerificationService_VerifyBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServiceServer).VerifyBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VerificationService_VerifyBundle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServiceServer).VerifyBundle(ctx, req.(*VerifyBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VerificationService_ValidateBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServiceServer).ValidateBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VerificationService_ValidateBundle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServiceServer).ValidateBundle(ctx, req.(*ValidateBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VerificationService_VerifyBundleV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyBundleV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServiceServer).VerifyBundleV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VerificationService_VerifyBundleV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServiceServer).VerifyBundleV2(ctx, req.(*VerifyBundleV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VerificationService_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServiceServer).HealthCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VerificationService_HealthCheck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServiceServer).HealthCheck(ctx, req.(*HealthCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VerificationService_ServiceDesc is the grpc.ServiceDesc for VerificationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VerificationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dcp.v1.VerificationService",
+	HandlerType: (*VerificationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "VerifyBundle",
+			Handler:    _VerificationService_VerifyBundle_Handler,
+		},
+		{
+			MethodName: "ValidateBundle",
+			Handler:    _VerificationService_ValidateBundle_Handler,
+		},
+		{
+			MethodName: "VerifyBundleV2",
+			Handler:    _VerificationService_VerifyBundleV2_Handler,
+		},
+		{
+			MethodName: "HealthCheck",
+			Handler:    _VerificationService_HealthCheck_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dcp.proto",
+}
+
+const (
+	AnchorService_AnchorHash_FullMethodName  = "/dcp.v1.AnchorService/AnchorHash"
+	AnchorService_AnchorBatch_FullMethodName = "/dcp.v1.AnchorService/AnchorBatch"
+	AnchorService_CheckAnchor_FullMethodName = "/dcp.v1.AnchorService/CheckAnchor"
+)
+
+// AnchorServiceClient is the client API for AnchorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AnchorServiceClient interface {
+	AnchorHash(ctx context.Context, in *AnchorHashRequest, opts ...grpc.CallOption) (*AnchorHashResponse, error)
+	AnchorBatch(ctx context.Context, in *AnchorBatchRequest, opts ...grpc.CallOption) (*AnchorBatchResponse, error)
+	CheckAnchor(ctx context.Context, in *CheckAnchorRequest, opts ...grpc.CallOption) (*CheckAnchorResponse, error)
+}
+
+type anchorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAnchorServiceClient(cc grpc.ClientConnInterface) AnchorServiceClient {
+	return &anchorServiceClient{cc}
+}
+
+func (c *anchorServiceClient) AnchorHash(ctx context.Context, in *AnchorHashRequest, opts ...grpc.CallOption) (*AnchorHashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnchorHashResponse)
+	err := c.cc.Invoke(ctx, AnchorService_AnchorHash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *anchorServiceClient) AnchorBatch(ctx context.Context, in *AnchorBatchRequest, opts ...grpc.CallOption) (*AnchorBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnchorBatchResponse)
+	err := c.cc.Invoke(ctx, AnchorService_AnchorBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *anchorServiceClient) CheckAnchor(ctx context.Context, in *CheckAnchorRequest, opts ...grpc.CallOption) (*CheckAnchorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckAnchorResponse)
+	err := c.cc.Invoke(ctx, AnchorService_CheckAnchor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AnchorServiceServer is the server API for AnchorService service.
+// All implementations must embed UnimplementedAnchorServiceServer
+// for forward compatibility.
+type AnchorServiceServer interface {
+	AnchorHash(context.Context, *AnchorHashRequest) (*AnchorHashResponse, error)
+	AnchorBatch(context.Context, *AnchorBatchRequest) (*AnchorBatchResponse, error)
+	CheckAnchor(context.Context, *CheckAnchorRequest) (*CheckAnchorResponse, error)
+	mustEmbedUnimplementedAnchorServiceServer()
+}
+
+// UnimplementedAnchorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAnchorServiceServer struct{}
+
+func (UnimplementedAnchorServiceServer) AnchorHash(context.Context, *AnchorHashRequest) (*AnchorHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnchorHash not implemented")
+}
+func (UnimplementedAnchorServiceServer) AnchorBatch(context.Context, *AnchorBatchRequest) (*AnchorBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnchorBatch not implemented")
+}
+func (UnimplementedAnchorServiceServer) CheckAnchor(context.Context, *CheckAnchorRequest) (*CheckAnchorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckAnchor not implemented")
+}
+func (UnimplementedAnchorServiceServer) mustEmbedUnimplementedAnchorServiceServer() {}
+func (UnimplementedAnchorServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeAnchorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AnchorServiceServer will
+// result in compilation errors.
+type UnsafeAnchorServiceServer interface {
+	mustEmbedUnimplementedAnchorServiceServer()
+}
+
+func RegisterAnchorServiceServer(s grpc.ServiceRegistrar, srv AnchorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAnchorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AnchorService_ServiceDesc, srv)
+}
+
+func _AnchorService_AnchorHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnchorHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnchorServiceServer).AnchorHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnchorService_AnchorHash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnchorServiceServer).AnchorHash(ctx, req.(*AnchorHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnchorService_AnchorBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnchorBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnchorServiceServer).AnchorBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnchorService_AnchorBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnchorServiceServer).AnchorBatch(ctx, req.(*AnchorBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnchorService_CheckAnchor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckAnchorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnchorServiceServer).CheckAnchor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnchorService_CheckAnchor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnchorServiceServer).CheckAnchor(ctx, req.(*CheckAnchorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AnchorService_ServiceDesc is the grpc.ServiceDesc for AnchorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AnchorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dcp.v1.AnchorService",
+	HandlerType: (*AnchorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AnchorHash",
+			Handler:    _AnchorService_AnchorHash_Handler,
+		},
+		{
+			MethodName: "AnchorBatch",
+			Handler:    _AnchorService_AnchorBatch_Handler,
+		},
+		{
+			MethodName: "CheckAnchor",
+			Handler:    _AnchorService_CheckAnchor_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dcp.proto",
+}
+
+const (
+	RevocationService_Revoke_FullMethodName               = "/dcp.v1.RevocationService/Revoke"
+	RevocationService_RevokeV2_FullMethodName             = "/dcp.v1.RevocationService/RevokeV2"
+	RevocationService_EmergencyRevoke_FullMethodName      = "/dcp.v1.RevocationService/EmergencyRevoke"
+	RevocationService_CheckRevocation_FullMethodName      = "/dcp.v1.RevocationService/CheckRevocation"
+	RevocationService_CheckRevocationByKid_FullMethodName = "/dcp.v1.RevocationService/CheckRevocationByKid"
+	RevocationService_ListRevocations_FullMethodName      = "/dcp.v1.RevocationService/ListRevocations"
+)
+
+// RevocationServiceClient is the client API for RevocationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RevocationServiceClient interface {
+	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeResponse, error)
+	RevokeV2(ctx context.Context, in *RevokeV2Request, opts ...grpc.CallOption) (*RevokeV2Response, error)
+	EmergencyRevoke(ctx context.Context, in *EmergencyRevokeRequest, opts ...grpc.CallOption) (*EmergencyRevokeResponse, error)
+	CheckRevocation(ctx context.Context, in *CheckRevocationRequest, opts ...grpc.CallOption) (*CheckRevocationResponse, error)
+	CheckRevocationByKid(ctx context.Context, in *CheckRevocationByKidRequest, opts ...grpc.CallOption) (*CheckRevocationByKidResponse, error)
+	ListRevocations(ctx context.Context, in *ListRevocationsRequest, opts ...grpc.CallOption) (*ListRevocationsResponse, error)
+}
+
+type revocationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRevocationServiceClient(cc grpc.ClientConnInterface) RevocationServiceClient {
+	return &revocationServiceClient{cc}
+}
+
+func (c *revocationServiceClient) Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeResponse)
+	err := c.cc.Invoke(ctx, RevocationService_Revoke_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *revocationServiceClient) RevokeV2(ctx context.Context, in *RevokeV2Request, opts ...grpc.CallOption) (*RevokeV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeV2Response)
+	err := c.cc.Invoke(ctx, RevocationService_RevokeV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *revocationServiceClient) EmergencyRevoke(ctx context.Context, in *EmergencyRevokeRequest, opts ...grpc.CallOption) (*EmergencyRevokeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmergencyRevokeResponse)
+	err := c.cc.Invoke(ctx, RevocationService_EmergencyRevoke_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *revocationServiceClient) CheckRevocation(ctx context.Context, in *CheckRevocationRequest, opts ...grpc.CallOption) (*CheckRevocationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckRevocationResponse)
+	err := c.cc.Invoke(ctx, RevocationService_CheckRevocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *revocationServiceClient) CheckRevocationByKid(ctx context.Context, in *CheckRevocationByKidRequest, opts ...grpc.CallOption) (*CheckRevocationByKidResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckRevocationByKidResponse)
+	err := c.cc.Invoke(ctx, RevocationService_CheckRevocationByKid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *revocationServiceClient) ListRevocations(ctx context.Context, in *ListRevocationsRequest, opts ...grpc.CallOption) (*ListRevocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRevocationsResponse)
+	err := c.cc.Invoke(ctx, RevocationService_ListRevocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RevocationServiceServer is the server API for RevocationService service.
+// All implementations must embed UnimplementedRevocationServiceServer
+// for forward compatibility.
+type RevocationServiceServer interface {
+	Revoke(context.Context, *RevokeRequest) (*RevokeResponse, error)
+	RevokeV2(context.Context, *RevokeV2Request) (*RevokeV2Response, error)
+	EmergencyRevoke(context.Context, *EmergencyRevokeRequest) (*EmergencyRevokeResponse, error)
+	CheckRevocation(context.Context, *CheckRevocationRequest) (*CheckRevocationResponse, error)
+	CheckRevocationByKid(context.Context, *CheckRevocationByKidRequest) (*CheckRevocationByKidResponse, error)
+	ListRevocations(context.Context, *ListRevocationsRequest) (*ListRevocationsResponse, error)
+	mustEmbedUnimplementedRevocationServiceServer()
+}
+
+// UnimplementedRevocationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRevocationServiceServer struct{}
+
+func (UnimplementedRevocationServiceServer) Revoke(context.Context, *RevokeRequest) (*RevokeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+func (UnimplementedRevocationServiceServer) RevokeV2(context.Context, *RevokeV2Request) (*RevokeV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeV2 not implemented")
+}
+func (UnimplementedRevocationServiceServer) EmergencyRevoke(context.Context, *EmergencyRevokeRequest) (*EmergencyRevokeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EmergencyRevoke not implemented")
+}
+func (UnimplementedRevocationServiceServer) CheckRevocation(context.Context, *CheckRevocationRequest) (*CheckRevocationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckRevocation not implemented")
+}
+func (UnimplementedRevocationServiceServer) CheckRevocationByKid(context.Context, *CheckRevocationByKidRequest) (*CheckRevocationByKidResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckRevocationByKid not implemented")
+}
+func (UnimplementedRevocationServiceServer) ListRevocations(context.Context, *ListRevocationsRequest) (*ListRevocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRevocations not implemented")
+}
+func (UnimplementedRevocationServiceServer) mustEmbedUnimplementedRevocationServiceServer() {}
+func (UnimplementedRevocationServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeRevocationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RevocationServiceServer will
+// result in compilation errors.
+type UnsafeRevocationServiceServer interface {
+	mustEmbedUnimplementedRevocationServiceServer()
+}
+
+func RegisterRevocationServiceServer(s grpc.ServiceRegistrar, srv RevocationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRevocationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RevocationService_ServiceDesc, srv)
+}
+
+func _RevocationService_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RevocationServiceServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RevocationService_Revoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RevocationServiceServer).Revoke(ctx, req.(*RevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RevocationService_RevokeV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RevocationServiceServer).RevokeV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RevocationService_RevokeV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RevocationServiceServer).RevokeV2(ctx, req.(*RevokeV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RevocationService_EmergencyRevoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmergencyRevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RevocationServiceServer).EmergencyRevoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RevocationService_EmergencyRevoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RevocationServiceServer).EmergencyRevoke(ctx, req.(*EmergencyRevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RevocationService_CheckRevocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckRevocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RevocationServiceServer).CheckRevocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RevocationService_CheckRevocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RevocationServiceServer).CheckRevocation(ctx, req.(*CheckRevocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RevocationService_CheckRevocationByKid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckRevocationByKidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RevocationServiceServer).CheckRevocationByKid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RevocationService_CheckRevocationByKid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RevocationServiceServer).CheckRevocationByKid(ctx, req.(*CheckRevocationByKidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RevocationService_ListRevocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRevocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RevocationServiceServer).ListRevocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RevocationService_ListRevocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RevocationServiceServer).ListRevocations(ctx, req.(*ListRevocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RevocationService_ServiceDesc is the grpc.ServiceDesc for RevocationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RevocationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dcp.v1.RevocationService",
+	HandlerType: (*RevocationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Revoke",
+			Handler:    _RevocationService_Revoke_Handler,
+		},
+		{
+			MethodName: "RevokeV2",
+			Handler:    _RevocationService_RevokeV2_Handler,
+		},
+		{
+			MethodName: "EmergencyRevoke",
+			Handler:    _RevocationService_EmergencyRevoke_Handler,
+		},
+		{
+			MethodName: "CheckRevocation",
+			Handler:    _RevocationService_CheckRevocation_Handler,
+		},
+		{
+			MethodName: "CheckRevocationByKid",
+			Handler:    _RevocationService_CheckRevocationByKid_Handler,
+		},
+		{
+			MethodName: "ListRevocations",
+			Handler:    _RevocationService_ListRevocations_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dcp.proto",
+}
+
+const (
+	TransparencyLogService_AddEntry_FullMethodName = "/dcp.v1.TransparencyLogService/AddEntry"
+	TransparencyLogService_GetRoot_FullMethodName  = "/dcp.v1.TransparencyLogService/GetRoot"
+	TransparencyLogService_GetProof_FullMethodName = "/dcp.v1.TransparencyLogService/GetProof"
+)
+
+// TransparencyLogServiceClient is the client API for TransparencyLogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TransparencyLogServiceClient interface {
+	AddEntry(ctx context.Context, in *AddEntryRequest, opts ...grpc.CallOption) (*AddEntryResponse, error)
+	GetRoot(ctx context.Context, in *GetRootRequest, opts ...grpc.CallOption) (*GetRootResponse, error)
+	GetProof(ctx context.Context, in *GetProofRequest, opts ...grpc.CallOption) (*GetProofResponse, error)
+}
+
+type transparencyLogServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransparencyLogServiceClient(cc grpc.ClientConnInterface) TransparencyLogServiceClient {
+	return &transparencyLogServiceClient{cc}
+}
+
+func (c *transparencyLogServiceClient) AddEntry(ctx context.Context, in *AddEntryRequest, opts ...grpc.CallOption) (*AddEntryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddEntryResponse)
+	err := c.cc.Invoke(ctx, TransparencyLogService_AddEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transparencyLogServiceClient) GetRoot(ctx context.Context, in *GetRootRequest, opts ...grpc.CallOption) (*GetRootResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRootResponse)
+	err := c.cc.Invoke(ctx, TransparencyLogService_GetRoot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transparencyLogServiceClient) GetProof(ctx context.Context, in *GetProofRequest, opts ...grpc.CallOption) (*GetProofResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProofResponse)
+	err := c.cc.Invoke(ctx, TransparencyLogService_GetProof_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TransparencyLogServiceServer is the server API for TransparencyLogService service.
+// All implementations must embed UnimplementedTransparencyLogServiceServer
+// for forward compatibility.
+type TransparencyLogServiceServer interface {
+	AddEntry(context.Context, *AddEntryRequest) (*AddEntryResponse, error)
+	GetRoot(context.Context, *GetRootRequest) (*GetRootResponse, error)
+	GetProof(context.Context, *GetProofRequest) (*GetProofResponse, error)
+	mustEmbedUnimplementedTransparencyLogServiceServer()
+}
+
+// UnimplementedTransparencyLogServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTransparencyLogServiceServer struct{}
+
+func (UnimplementedTransparencyLogServiceServer) AddEntry(context.Context, *AddEntryRequest) (*AddEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddEntry not implemented")
+}
+func (UnimplementedTransparencyLogServiceServer) GetRoot(context.Context, *GetRootRequest) (*GetRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoot not implemented")
+}
+func (UnimplementedTransparencyLogServiceServer) GetProof(context.Context, *GetProofRequest) (*GetProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProof not implemented")
+}
+func (UnimplementedTransparencyLogServiceServer) mustEmbedUnimplementedTransparencyLogServiceServer() {
+}
+func (UnimplementedTransparencyLogServiceServer) testEmbeddedByValue() {}
+
+// UnsafeTransparencyLogServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransparencyLogServiceServer will
+// result in compilation errors.
+type UnsafeTransparencyLogServiceServer interface {
+	mustEmbedUnimplementedTransparencyLogServiceServer()
+}
+
+func RegisterTransparencyLogServiceServer(s grpc.ServiceRegistrar, srv TransparencyLogServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTransparencyLogServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TransparencyLogService_ServiceDesc, srv)
+}
+
+func _TransparencyLogService_AddEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransparencyLogServiceServer).AddEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransparencyLogService_AddEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransparencyLogServiceServer).AddEntry(ctx, req.(*AddEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransparencyLogService_GetRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransparencyLogServiceServer).GetRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransparencyLogService_GetRoot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransparencyLogServiceServer).GetRoot(ctx, req.(*GetRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransparencyLogService_GetProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransparencyLogServiceServer).GetProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransparencyLogService_GetProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransparencyLogServiceServer).GetProof(ctx, req.(*GetProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TransparencyLogService_ServiceDesc is the grpc.ServiceDesc for TransparencyLogService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TransparencyLogService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dcp.v1.TransparencyLogService",
+	HandlerType: (*TransparencyLogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddEntry",
+			Handler:    _TransparencyLogService_AddEntry_Handler,
+		},
+		{
+			MethodName: "GetRoot",
+			Handler:    _TransparencyLogService_GetRoot_Handler,
+		},
+		{
+			MethodName: "GetProof",
+			Handler:    _TransparencyLogService_GetProof_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dcp.proto",
+}
+
+const (
+	KeyService_RegisterKeys_FullMethodName = "/dcp.v1.KeyService/RegisterKeys"
+	KeyService_LookupKey_FullMethodName    = "/dcp.v1.KeyService/LookupKey"
+	KeyService_RotateKey_FullMethodName    = "/dcp.v1.KeyService/RotateKey"
+)
+
+// KeyServiceClient is the client API for KeyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KeyServiceClient interface {
+	RegisterKeys(ctx context.Context, in *RegisterKeysRequest, opts ...grpc.CallOption) (*RegisterKeysResponse, error)
+	LookupKey(ctx context.Context, in *LookupKeyRequest, opts ...grpc.CallOption) (*LookupKeyResponse, error)
+	RotateKey(ctx context.Context, in *RotateKeyRequest, opts ...grpc.CallOption) (*RotateKeyResponse, error)
+}
+
+type keyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKeyServiceClient(cc grpc.ClientConnInterface) KeyServiceClient {
+	return &keyServiceClient{cc}
+}
+
+func (c *keyServiceClient) RegisterKeys(ctx context.Context, in *RegisterKeysRequest, opts ...grpc.CallOption) (*RegisterKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterKeysResponse)
+	err := c.cc.Invoke(ctx, KeyService_RegisterKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyServiceClient) LookupKey(ctx context.Context, in *LookupKeyRequest, opts ...grpc.CallOption) (*LookupKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupKeyResponse)
+	err := c.cc.Invoke(ctx, KeyService_LookupKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyServiceClient) RotateKey(ctx context.Context, in *RotateKeyRequest, opts ...grpc.CallOption) (*RotateKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateKeyResponse)
+	err := c.cc.Invoke(ctx, KeyService_RotateKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KeyServiceServer is the server API for KeyService service.
+// All implementations must embed UnimplementedKeyServiceServer
+// for forward compatibility.
+type KeyServiceServer interface {
+	RegisterKeys(context.Context, *RegisterKeysRequest) (*RegisterKeysResponse, error)
+	LookupKey(context.Context, *LookupKeyRequest) (*LookupKeyResponse, error)
+	RotateKey(context.Context, *RotateKeyRequest) (*RotateKeyResponse, error)
+	mustEmbedUnimplementedKeyServiceServer()
+}
+
+// UnimplementedKeyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedKeyServiceServer struct{}
+
+func (UnimplementedKeyServiceServer) RegisterKeys(context.Context, *RegisterKeysRequest) (*RegisterKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterKeys not implemented")
+}
+func (UnimplementedKeyServiceServer) LookupKey(context.Context, *LookupKeyRequest) (*LookupKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupKey not implemented")
+}
+func (UnimplementedKeyServiceServer) RotateKey(context.Context, *RotateKeyRequest) (*RotateKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateKey not implemented")
+}
+func (UnimplementedKeyServiceServer) mustEmbedUnimplementedKeyServiceServer() {}
+func (UnimplementedKeyServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeKeyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KeyServiceServer will
+// result in compilation errors.
+type UnsafeKeyServiceServer interface {
+	mustEmbedUnimplementedKeyServiceServer()
+}
+
+func RegisterKeyServiceServer(s grpc.ServiceRegistrar, srv KeyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedKeyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&KeyService_ServiceDesc, srv)
+}
+
+func _KeyService_RegisterKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).RegisterKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyService_RegisterKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).RegisterKeys(ctx, req.(*RegisterKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyService_LookupKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).LookupKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyService_LookupKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).LookupKey(ctx, req.(*LookupKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyService_RotateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).RotateKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyService_RotateKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).RotateKey(ctx, req.(*RotateKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// KeyService_ServiceDesc is the grpc.ServiceDesc for KeyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KeyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dcp.v1.KeyService",
+	HandlerType: (*KeyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterKeys",
+			Handler:    _KeyService_RegisterKeys_Handler,
+		},
+		{
+			MethodName: "LookupKey",
+			Handler:    _KeyService_LookupKey_Handler,
+		},
+		{
+			MethodName: "RotateKey",
+			Handler:    _KeyService_RotateKey_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dcp.proto",
+}
+
+const (
+	DcpService_Verify_FullMethodName              = "/dcp.v1.DcpService/Verify"
+	DcpService_GetPassport_FullMethodName         = "/dcp.v1.DcpService/GetPassport"
+	DcpService_GetRevocationStatus_FullMethodName = "/dcp.v1.DcpService/GetRevocationStatus"
+	DcpService_AppendAudit_FullMethodName         = "/dcp.v1.DcpService/AppendAudit"
+)
+
+// DcpServiceClient is the client API for DcpService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DcpService is the agent-facing API of a deployment: bundle verification,
+// passport lookup, revocation status and audit appends. Signed artifacts
+// travel as JSON strings so signatures are checked over the bytes that
+// were signed.
+type DcpServiceClient interface {
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	// GetPassport answers NOT_FOUND for an unknown agent.
+	GetPassport(ctx context.Context, in *GetPassportRequest, opts ...grpc.CallOption) (*GetPassportResponse, error)
+	GetRevocationStatus(ctx context.Context, in *GetRevocationStatusRequest, opts ...grpc.CallOption) (*GetRevocationStatusResponse, error)
+	// AppendAudit answers FAILED_PRECONDITION when the entry's prev_hash is
+	// not the head of the agent's ledger.
+	AppendAudit(ctx context.Context, in *AppendAuditRequest, opts ...grpc.CallOption) (*AppendAuditResponse, error)
+}
+
+type dcpServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDcpServiceClient(cc grpc.ClientConnInterface) DcpServiceClient {
+	return &dcpServiceClient{cc}
+}
+
+func (c *dcpServiceClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, DcpService_Verify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dcpServiceClient) GetPassport(ctx context.Context, in *GetPassportRequest, opts ...grpc.CallOption) (*GetPassportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPassportResponse)
+	err := c.cc.Invoke(ctx, DcpService_GetPassport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dcpServiceClient) GetRevocationStatus(ctx context.Context, in *GetRevocationStatusRequest, opts ...grpc.CallOption) (*GetRevocationStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRevocationStatusResponse)
+	err := c.cc.Invoke(ctx, DcpService_GetRevocationStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dcpServiceClient) AppendAudit(ctx context.Context, in *AppendAuditRequest, opts ...grpc.CallOption) (*AppendAuditResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AppendAuditResponse)
+	err := c.cc.Invoke(ctx, DcpService_AppendAudit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DcpServiceServer is the server API for DcpService service.
+// All implementations must embed UnimplementedDcpServiceServer
+// for forward compatibility.
+//
+// DcpService is the agent-facing API of a deployment: bundle verification,
+// passport lookup, revocation status and audit appends. Signed artifacts
+// travel as JSON strings so signatures are checked over the bytes that
+// were signed.
+type DcpServiceServer interface {
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	// GetPassport answers NOT_FOUND for an unknown agent.
+	GetPassport(context.Context, *GetPassportRequest) (*GetPassportResponse, error)
+	GetRevocationStatus(context.Context, *GetRevocationStatusRequest) (*GetRevocationStatusResponse, error)
+	// AppendAudit answers FAILED_PRECONDITION when the entry's prev_hash is
+	// not the head of the agent's ledger.
+	AppendAudit(context.Context, *AppendAuditRequest) (*AppendAuditResponse, error)
+	mustEmbedUnimplementedDcpServiceServer()
+}
+
+// UnimplementedDcpServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDcpServiceServer struct{}
+
+func (UnimplementedDcpServiceServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedDcpServiceServer) GetPassport(context.Context, *GetPassportRequest) (*GetPassportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPassport not implemented")
+}
+func (UnimplementedDcpServiceServer) GetRevocationStatus(context.Context, *GetRevocationStatusRequest) (*GetRevocationStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRevocationStatus not implemented")
+}
+func (UnimplementedDcpServiceServer) AppendAudit(context.Context, *AppendAuditRequest) (*AppendAuditResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppendAudit not implemented")
+}
+func (UnimplementedDcpServiceServer) mustEmbedUnimplementedDcpServiceServer() {}
+func (UnimplementedDcpServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeDcpServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DcpServiceServer will
+// result in compilation errors.
+type UnsafeDcpServiceServer interface {
+	mustEmbedUnimplementedDcpServiceServer()
+}
+
+func RegisterDcpServiceServer(s grpc.ServiceRegistrar, srv DcpServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDcpServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DcpService_ServiceDesc, srv)
+}
+
+func _DcpService_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DcpServiceServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DcpService_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DcpServiceServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DcpService_GetPassport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPassportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DcpServiceServer).GetPassport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DcpService_GetPassport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DcpServiceServer).GetPassport(ctx, req.(*GetPassportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DcpService_GetRevocationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRevocationStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DcpServiceServer).GetRevocationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DcpService_GetRevocationStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DcpServiceServer).GetRevocationStatus(ctx, req.(*GetRevocationStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DcpService_AppendAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendAuditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DcpServiceServer).AppendAudit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DcpService_AppendAudit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DcpServiceServer).AppendAudit(ctx, req.(*AppendAuditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DcpService_ServiceDesc is the grpc.ServiceDesc for DcpService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DcpService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dcp.v1.DcpService",
+	HandlerType: (*DcpServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Verify",
+			Handler:    _DcpService_Verify_Handler,
+		},
+		{
+			MethodName: "GetPassport",
+			Handler:    _DcpService_GetPassport_Handler,
+		},
+		{
+			MethodName: "GetRevocationStatus",
+			Handler:    _DcpService_GetRevocationStatus_Handler,
+		},
+		{
+			MethodName: "AppendAudit",
+			Handler:    _DcpService_AppendAudit_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dcp.proto",
+}
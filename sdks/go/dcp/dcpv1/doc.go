@@ -0,0 +1,7 @@
+// Package dcpv1 is the Go binding of the dcp.v1 Protocol Buffers API in
+// api/proto/dcp.proto: message types and gRPC clients and server
+// interfaces. DcpService is implemented by package grpcserver; the other
+// services are defined for clients of servers written in other languages.
+package dcpv1
+
+//go:generate protoc -I ../../../../api/proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative dcp.proto
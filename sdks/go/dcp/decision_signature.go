@@ -0,0 +1,68 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDecisionUnverified is returned for a policy decision that is not
+// signed by a trusted PDP where one is required, so it may have been
+// written by the agent itself.
+var ErrDecisionUnverified = errors.New("policy decision unverified")
+
+// Sign sets Signature to s's signature, as the PDP, over the canonical
+// decision with an empty signature. An empty DecisionID is set to a fresh
+// dcp:decision: identifier first, so each signed decision is distinct.
+func (d *PolicyDecision) Sign(s BundleSigner) error {
+	if d.DecisionID == "" {
+		d.DecisionID = NewDecisionID()
+	}
+	d.Signature = ""
+	sig, err := signWith(s, d)
+	if err != nil {
+		return fmt.Errorf("sign policy_decision on %s: %w", d.IntentID, err)
+	}
+	d.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against a PDP's public key.
+func (d *PolicyDecision) VerifySignature(publicKeyB64 string) (bool, error) {
+	if d.Signature == "" {
+		return false, fmt.Errorf("policy_decision on %s has no signature", d.IntentID)
+	}
+	unsigned := *d
+	unsigned.Signature = ""
+	return VerifyObject(unsigned, d.Signature, publicKeyB64)
+}
+
+// CheckDecision reports whether d is a decision on intent signed by one of
+// pdpKeys. An unsigned decision passes unless passport's risk tier is high,
+// for which the decision must be signed. Errors wrap ErrDecisionUnverified.
+func CheckDecision(passport *AgentPassport, intent *Intent, d *PolicyDecision, pdpKeys []string) error {
+	if d.Signature == "" && passport.RiskTier != RiskTierHigh {
+		return nil
+	}
+	_, err := checkDecision(passport.RiskTier, intent, d, pdpKeys)
+	return err
+}
+
+// checkDecision checks d as CheckDecision does, whether signed or not, and
+// returns the PDP key its signature verifies with.
+func checkDecision(tier RiskTier, intent *Intent, d *PolicyDecision, pdpKeys []string) (string, error) {
+	if d.IntentID != intent.IntentID {
+		return "", fmt.Errorf("%w: decision on %s, the intent is %s", ErrDecisionUnverified, d.IntentID, intent.IntentID)
+	}
+	if d.Signature == "" {
+		return "", fmt.Errorf("%w: a %s risk tier agent's decision must be signed by the PDP", ErrDecisionUnverified, tier)
+	}
+	if len(pdpKeys) == 0 {
+		return "", fmt.Errorf("%w: no PDP key to check the signature against", ErrDecisionUnverified)
+	}
+	for _, key := range pdpKeys {
+		if ok, err := d.VerifySignature(key); err == nil && ok {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("%w: the signature does not verify with any PDP key", ErrDecisionUnverified)
+}
@@ -0,0 +1,107 @@
+package dcp_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestPolicyDecisionSignature(t *testing.T) {
+	pdpKey, _ := dcp.GenerateKeypair()
+	signer, _ := dcp.NewKeySigner(pdpKey.SecretKeyB64)
+	d := dcp.NewPolicyDecision("intent001", dcp.DecisionApprove, 0.2, "low_risk")
+	if err := d.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := dcp.ParseID(d.DecisionID); err != nil || id.Kind != dcp.IDKindDecision {
+		t.Fatalf("decision_id = %q, %v", d.DecisionID, err)
+	}
+	if err := d.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := d.VerifySignature(pdpKey.PublicKeyB64); !ok || err != nil {
+		t.Fatalf("VerifySignature = %v, %v", ok, err)
+	}
+
+	passport := dcp.AgentPassport{AgentID: "agent001", RiskTier: dcp.RiskTierHigh}
+	intent := dcp.Intent{IntentID: "intent001"}
+	if err := dcp.CheckDecision(&passport, &intent, &d, []string{pdpKey.PublicKeyB64}); err != nil {
+		t.Fatal(err)
+	}
+	forged := d
+	forged.RiskScore = 0
+	stranger, _ := dcp.GenerateKeypair()
+	unsigned := dcp.NewPolicyDecision("intent001", dcp.DecisionApprove, 0.2, "low_risk")
+	other := dcp.Intent{IntentID: "intent002"}
+	for _, tc := range []struct {
+		name   string
+		intent *dcp.Intent
+		d      *dcp.PolicyDecision
+		keys   []string
+		want   string
+	}{
+		{"altered", &intent, &forged, []string{pdpKey.PublicKeyB64}, "does not verify"},
+		{"untrusted PDP", &intent, &d, []string{stranger.PublicKeyB64}, "does not verify"},
+		{"no PDP key", &intent, &d, nil, "no PDP key"},
+		{"unsigned", &intent, &unsigned, []string{pdpKey.PublicKeyB64}, "must be signed"},
+		{"other intent", &other, &d, []string{pdpKey.PublicKeyB64}, "intent002"},
+	} {
+		if err := dcp.CheckDecision(&passport, tc.intent, tc.d, tc.keys); !errors.Is(err, dcp.ErrDecisionUnverified) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+	passport.RiskTier = dcp.RiskTierMedium
+	if err := dcp.CheckDecision(&passport, &intent, &unsigned, nil); err != nil {
+		t.Fatalf("unsigned, medium risk tier: %v", err)
+	}
+}
+
+func TestVerifyDecisionSignature(t *testing.T) {
+	b, human, agent := builderFixture(t)
+	pdpKey, _ := dcp.GenerateKeypair()
+	pdpSigner, _ := dcp.NewKeySigner(pdpKey.SecretKeyB64)
+	d := dcp.PolicyDecision{DCPVersion: "1.0", IntentID: "intent001", Decision: "approve", RiskScore: 0.2, Reasons: []string{"low_risk"}}
+	if err := d.Sign(pdpSigner); err != nil {
+		t.Fatal(err)
+	}
+	b.AgentPassport(dcp.AgentPassport{DCPVersion: "1.0", AgentID: "agent001", PublicKey: agent.PublicKeyB64,
+		PrincipalBindingReference: "human001", CreatedAt: "2026-01-01T00:10:00Z", Status: "active", RiskTier: dcp.RiskTierHigh})
+	principal, _ := dcp.NewKeySigner(human.SecretKeyB64)
+	sign := func(d dcp.PolicyDecision) *dcp.SignedBundle {
+		t.Helper()
+		bundle, err := b.PolicyDecision(d).Bundle()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb, err := dcp.SignBundle(bundle, principal, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+	opts := dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, PDPKeys: []string{pdpKey.PublicKeyB64}, Explain: true}
+
+	res := dcp.VerifySignedBundleWithOptions(sign(d), opts)
+	if !res.Verified {
+		t.Fatalf("signed decision: %v", res.Errors)
+	}
+	if last := res.Trace[len(res.Trace)-1]; last.Check != dcp.TraceDecision || !last.OK || last.Actual != pdpKey.PublicKeyB64 {
+		t.Fatalf("trace: %+v", last)
+	}
+	forged := d
+	forged.RiskScore = 0
+	if res := dcp.VerifySignedBundleWithOptions(sign(forged), opts); res.Verified || !strings.Contains(res.Errors[0], "does not verify") {
+		t.Fatalf("forged decision: %+v", res)
+	}
+	unsigned := d
+	unsigned.DecisionID, unsigned.Signature = "", ""
+	if res := dcp.VerifySignedBundleWithOptions(sign(unsigned), opts); res.Verified || !strings.Contains(res.Errors[0], "must be signed") {
+		t.Fatalf("unsigned decision of a high risk tier agent: %+v", res)
+	}
+	if res := dcp.VerifySignedBundle(sign(d), human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "no PDP key") {
+		t.Fatalf("no PDP key configured: %+v", res)
+	}
+}
@@ -0,0 +1,206 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDelegationInvalid is returned for a delegation chain that does not
+// link a sub-agent to a valid parent passport of its principal, or grants
+// it more than its parent holds.
+var ErrDelegationInvalid = errors.New("delegation invalid")
+
+// NewDelegationRecord returns parent's unsigned delegation of capabilities
+// to child, from now for ttl. A nil capabilities delegates child's
+// passport capabilities. It fails if parent does not hold them all.
+func (f *RecordFactory) NewDelegationRecord(parent, child *AgentPassport, capabilities []string, ttl time.Duration) (DelegationRecord, error) {
+	if capabilities == nil {
+		capabilities = cloneStrings(child.Capabilities)
+	}
+	if err := CheckAttenuation(parent.Capabilities, capabilities); err != nil {
+		return DelegationRecord{}, fmt.Errorf("delegation to %s: %w", child.AgentID, err)
+	}
+	now := f.now()
+	return DelegationRecord{
+		DCPVersion:     DCPVersion,
+		ParentAgentID:  parent.AgentID,
+		ChildAgentID:   child.AgentID,
+		ChildPublicKey: child.PublicKey,
+		HumanID:        parent.PrincipalBindingReference,
+		Capabilities:   capabilities,
+		NotBefore:      FormatTime(now),
+		NotAfter:       FormatTime(now.Add(ttl)),
+	}, nil
+}
+
+// NewDelegationRecord calls RecordFactory.NewDelegationRecord with the
+// package clock.
+func NewDelegationRecord(parent, child *AgentPassport, capabilities []string, ttl time.Duration) (DelegationRecord, error) {
+	return defaultRecords.NewDelegationRecord(parent, child, capabilities, ttl)
+}
+
+// Sign sets Signature to s's signature over the canonical record with an
+// empty signature. A delegation is signed by the parent agent's key.
+func (d *DelegationRecord) Sign(s BundleSigner) error {
+	d.Signature = ""
+	sig, err := signWith(s, d)
+	if err != nil {
+		return fmt.Errorf("sign delegation to %s: %w", d.ChildAgentID, err)
+	}
+	d.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against the parent agent's public key.
+func (d *DelegationRecord) VerifySignature(publicKeyB64 string) (bool, error) {
+	if d.Signature == "" {
+		return false, fmt.Errorf("delegation to %s has no signature", d.ChildAgentID)
+	}
+	unsigned := *d
+	unsigned.Signature = ""
+	return VerifyObject(unsigned, d.Signature, publicKeyB64)
+}
+
+// CheckDelegationChain reports whether chain, root first, delegates to
+// passport's agent: each parent passport is self-signed, active and of
+// passport's principal, and is the child of the delegation before it; each
+// delegation is signed by its parent, held when intent was declared and
+// grants no capability its parent lacks; and passport claims none its last
+// delegation does not grant. Errors wrap ErrDelegationInvalid.
+func CheckDelegationChain(passport *AgentPassport, intent *Intent, chain []DelegationLink) error {
+	if len(chain) == 0 {
+		return fmt.Errorf("%w: empty chain", ErrDelegationInvalid)
+	}
+	return checkDelegationChain(passport, intent, chain)
+}
+
+func checkDelegationChain(passport *AgentPassport, intent *Intent, chain []DelegationLink) error {
+	declared, err := intent.TimestampTime()
+	if err != nil {
+		return fmt.Errorf("%w: intent %v", ErrDelegationInvalid, err)
+	}
+	for i := range chain {
+		if err := chain[i].check(passport.PrincipalBindingReference, declared); err != nil {
+			return fmt.Errorf("%w: link %d: %v", ErrDelegationInvalid, i, err)
+		}
+		if i == 0 {
+			continue
+		}
+		prev, parent := &chain[i-1].Delegation, &chain[i].ParentPassport
+		if parent.AgentID != prev.ChildAgentID || parent.PublicKey != prev.ChildPublicKey {
+			return fmt.Errorf("%w: link %d: parent %s is not the child %s of link %d", ErrDelegationInvalid, i, parent.AgentID, prev.ChildAgentID, i-1)
+		}
+		if err := CheckAttenuation(prev.Capabilities, parent.Capabilities); err != nil {
+			return fmt.Errorf("%w: link %d: parent passport: %v", ErrDelegationInvalid, i, err)
+		}
+	}
+	last := &chain[len(chain)-1].Delegation
+	if passport.AgentID != last.ChildAgentID || passport.PublicKey != last.ChildPublicKey {
+		return fmt.Errorf("%w: the chain delegates to %s, not the bundle's agent %s", ErrDelegationInvalid, last.ChildAgentID, passport.AgentID)
+	}
+	if err := CheckAttenuation(last.Capabilities, passport.Capabilities); err != nil {
+		return fmt.Errorf("%w: agent passport: %v", ErrDelegationInvalid, err)
+	}
+	return nil
+}
+
+// check checks l on its own: the parent passport and its delegation, at
+// declared.
+func (l *DelegationLink) check(humanID string, declared time.Time) error {
+	p, d := &l.ParentPassport, &l.Delegation
+	if ok, err := p.VerifySignature(); err != nil || !ok {
+		return fmt.Errorf("parent passport %s signature does not verify", p.AgentID)
+	}
+	switch {
+	case p.Status != StatusActive:
+		return fmt.Errorf("parent %s is %s", p.AgentID, p.Status)
+	case p.PrincipalBindingReference != humanID || d.HumanID != humanID:
+		return fmt.Errorf("parent %s is of another principal than %s", p.AgentID, humanID)
+	case d.ParentAgentID != p.AgentID:
+		return fmt.Errorf("delegation by %s, not the parent %s", d.ParentAgentID, p.AgentID)
+	}
+	if ok, err := d.VerifySignature(p.PublicKey); err != nil || !ok {
+		return fmt.Errorf("delegation signature does not verify with the parent's key")
+	}
+	if err := CheckAttenuation(p.Capabilities, d.Capabilities); err != nil {
+		return err
+	}
+	from, err := ParseTime(d.NotBefore)
+	if err != nil {
+		return fmt.Errorf("not_before: %v", err)
+	}
+	until, err := ParseTime(d.NotAfter)
+	if err != nil {
+		return fmt.Errorf("not_after: %v", err)
+	}
+	if declared.Before(from) || !declared.Before(until) {
+		return fmt.Errorf("intent declared at %s, outside %s to %s", FormatTime(declared), d.NotBefore, d.NotAfter)
+	}
+	return nil
+}
+
+// Validate checks d against the delegation schema and returns
+// ValidationErrors listing every violation, or nil.
+func (d *DelegationRecord) Validate() error {
+	v := newValidator()
+	d.validate(v)
+	return v.err()
+}
+
+func (d *DelegationRecord) validate(v validator) {
+	v.version("dcp_version", d.DCPVersion)
+	v.idOf("parent_agent_id", d.ParentAgentID, IDKindAgent)
+	v.idOf("child_agent_id", d.ChildAgentID, IDKindAgent)
+	v.publicKey("child_public_key", d.ChildPublicKey)
+	v.idOf("human_id", d.HumanID, IDKindHuman)
+	for i, c := range d.Capabilities {
+		if _, err := ParseCapability(c); err != nil {
+			v.at("capabilities").index(i).fail("must be a capability")
+		}
+	}
+	v.timestamp("not_before", d.NotBefore)
+	v.timestamp("not_after", d.NotAfter)
+	v.signature("signature", d.Signature)
+}
+
+// Validate checks l against the delegation schema and returns
+// ValidationErrors listing every violation, or nil.
+func (l *DelegationLink) Validate() error {
+	v := newValidator()
+	l.validate(v)
+	return v.err()
+}
+
+func (l *DelegationLink) validate(v validator) {
+	l.ParentPassport.validate(v.at("parent_passport"))
+	l.Delegation.validate(v.at("delegation"))
+}
+
+// Clone returns a deep copy of d.
+func (d *DelegationRecord) Clone() *DelegationRecord {
+	if d == nil {
+		return nil
+	}
+	c := *d
+	c.Capabilities = cloneStrings(d.Capabilities)
+	return &c
+}
+
+// Equal reports whether d and o canonicalize identically.
+func (d *DelegationRecord) Equal(o *DelegationRecord) bool {
+	return (d == nil) == (o == nil) && (d == nil || canonicalEqual(d, o))
+}
+
+// Clone returns a deep copy of l.
+func (l *DelegationLink) Clone() *DelegationLink {
+	if l == nil {
+		return nil
+	}
+	return &DelegationLink{ParentPassport: *l.ParentPassport.Clone(), Delegation: *l.Delegation.Clone()}
+}
+
+// Equal reports whether l and o canonicalize identically.
+func (l *DelegationLink) Equal(o *DelegationLink) bool {
+	return (l == nil) == (o == nil) && (l == nil || canonicalEqual(l, o))
+}
@@ -0,0 +1,153 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// delegatedAgent returns a self-signed active passport of human001 with
+// capabilities, and its signer.
+func delegatedAgent(t *testing.T, agentID string, capabilities ...string) (dcp.AgentPassport, dcp.BundleSigner) {
+	t.Helper()
+	kp, _ := dcp.GenerateKeypair()
+	s, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := dcp.AgentPassport{DCPVersion: "1.0", AgentID: agentID, PrincipalBindingReference: "human001",
+		Capabilities: capabilities, CreatedAt: "2026-01-01T00:00:00Z", Status: "active"}
+	if err := p.Sign(s); err != nil {
+		t.Fatal(err)
+	}
+	return p, s
+}
+
+func TestDelegationChain(t *testing.T) {
+	f := dcp.RecordFactory{Clock: func() time.Time { return time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC) }}
+	root, rootSigner := delegatedAgent(t, "agent000", "email", "browse", "payments")
+	mid, midSigner := delegatedAgent(t, "agent001", "email", "browse")
+	leaf, _ := delegatedAgent(t, "agent002", "email")
+	link := func(parent *dcp.AgentPassport, s dcp.BundleSigner, child *dcp.AgentPassport, caps []string) dcp.DelegationLink {
+		t.Helper()
+		d, err := f.NewDelegationRecord(parent, child, caps, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Sign(s); err != nil {
+			t.Fatal(err)
+		}
+		return dcp.DelegationLink{ParentPassport: *parent, Delegation: d}
+	}
+	chain := []dcp.DelegationLink{
+		link(&root, rootSigner, &mid, []string{"email", "browse"}),
+		link(&mid, midSigner, &leaf, nil),
+	}
+	if err := chain[1].Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if chain[1].Delegation.NotAfter != "2026-01-01T01:30:00Z" || strings.Join(chain[1].Delegation.Capabilities, ",") != "email" {
+		t.Fatalf("delegation = %+v", chain[1].Delegation)
+	}
+	intent := dcp.Intent{IntentID: "intent001", AgentID: "agent002", Timestamp: "2026-01-01T01:00:00Z"}
+	if err := dcp.CheckDelegationChain(&leaf, &intent, chain); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.NewDelegationRecord(&mid, &leaf, []string{"payments"}, time.Hour); err == nil {
+		t.Fatal("delegated a capability the parent lacks")
+	}
+
+	// A delegation signed for more than its parent holds still fails.
+	widened := chain[1].Clone()
+	widened.Delegation.Capabilities = []string{"email", "payments"}
+	if err := widened.Delegation.Sign(midSigner); err != nil {
+		t.Fatal(err)
+	}
+	greedy := leaf
+	greedy.Capabilities = []string{"email", "browse"}
+	suspended := chain[0].Clone()
+	suspended.ParentPassport.Status = "suspended"
+	if err := suspended.ParentPassport.Sign(rootSigner); err != nil {
+		t.Fatal(err)
+	}
+	late := intent
+	late.Timestamp = "2026-01-01T02:00:00Z"
+	for _, tc := range []struct {
+		name     string
+		passport *dcp.AgentPassport
+		intent   *dcp.Intent
+		chain    []dcp.DelegationLink
+		want     string
+	}{
+		{"widened", &leaf, &intent, []dcp.DelegationLink{chain[0], *widened}, "payments"},
+		{"greedy passport", &greedy, &intent, chain, "agent passport"},
+		{"suspended parent", &leaf, &intent, []dcp.DelegationLink{*suspended, chain[1]}, "suspended"},
+		{"expired", &leaf, &late, chain, "outside"},
+		{"broken link", &leaf, &intent, []dcp.DelegationLink{chain[1], chain[1]}, "not the child"},
+		{"other agent", &mid, &intent, chain, "not the bundle's agent"},
+		{"empty", &leaf, &intent, nil, "empty chain"},
+	} {
+		if err := dcp.CheckDelegationChain(tc.passport, tc.intent, tc.chain); !errors.Is(err, dcp.ErrDelegationInvalid) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+	forged := chain[1].Clone()
+	forged.Delegation.Capabilities = []string{"browse"}
+	if err := dcp.CheckDelegationChain(&leaf, &intent, []dcp.DelegationLink{chain[0], *forged}); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Fatalf("altered delegation: %v", err)
+	}
+}
+
+func TestVerifyDelegation(t *testing.T) {
+	b, human, agent := builderFixture(t)
+	parent, parentSigner := delegatedAgent(t, "agent000", "email", "browse")
+	child := dcp.AgentPassport{DCPVersion: "1.0", AgentID: "agent001", PublicKey: agent.PublicKeyB64,
+		PrincipalBindingReference: "human001", Capabilities: []string{"email"}, CreatedAt: "2026-01-01T00:10:00Z", Status: "active"}
+	f := dcp.RecordFactory{Clock: func() time.Time { return time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC) }}
+	d, err := f.NewDelegationRecord(&parent, &child, nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Sign(parentSigner); err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := b.AgentPassport(child).Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle.DelegationChain = []dcp.DelegationLink{{ParentPassport: parent, Delegation: d}}
+	principal, _ := dcp.NewKeySigner(human.SecretKeyB64)
+	sign := func(b *dcp.CitizenshipBundle) *dcp.SignedBundle {
+		t.Helper()
+		sb, err := dcp.SignBundle(b, principal, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+	res := dcp.VerifySignedBundleWithOptions(sign(bundle), dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, Explain: true})
+	if !res.Verified {
+		t.Fatalf("delegated bundle: %v", res.Errors)
+	}
+	if last := res.Trace[len(res.Trace)-1]; last.Check != dcp.TraceDelegation || !last.OK || last.Detail != "from agent000, 1 deep" {
+		t.Fatalf("trace: %+v", last)
+	}
+	data, _ := json.Marshal(sign(bundle))
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundle(rsb, human.PublicKeyB64); !res.Verified {
+		t.Fatalf("raw bundle: %v", res.Errors)
+	}
+
+	altered := bundle.Clone()
+	altered.DelegationChain[0].Delegation.Capabilities = []string{"email", "browse"}
+	if res := dcp.VerifySignedBundle(sign(altered), human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "delegation signature") {
+		t.Fatalf("altered delegation: %+v", res)
+	}
+}
@@ -0,0 +1,160 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotPublished reports a domain that serves no discovery document.
+var ErrNotPublished = errors.New("no DCP discovery document")
+
+// maxDocumentBytes caps the size of a fetched document.
+const maxDocumentBytes = 1 << 20
+
+// maxCacheAge caps how long a document is cached whatever its response
+// asks for, so a rotated key is picked up within a day.
+const maxCacheAge = 24 * time.Hour
+
+// Client discovers counterparties' DCP configuration and caches it for as
+// long as the serving domain's Cache-Control allows, revalidating with the
+// ETag once it expires. A Client is safe for concurrent use.
+type Client struct {
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// DefaultMaxAge is how long a document is cached when its response
+	// gives no max-age; zero means one hour.
+	DefaultMaxAge time.Duration
+	// Now is the cache's clock; nil means time.Now.
+	Now func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	doc     *Document
+	etag    string
+	expires time.Time
+}
+
+// Discover returns the document of domain, fetching it from
+// https://<domain>/.well-known/dcp unless a fresh copy is cached. domain
+// may also be a base URL with an explicit scheme, such as
+// "http://localhost:8080".
+func (c *Client) Discover(ctx context.Context, domain string) (*Document, error) {
+	u := DocumentURL(domain)
+	now := c.now()
+	c.mu.Lock()
+	cached := c.cache[u]
+	c.mu.Unlock()
+	if cached != nil && now.Before(cached.expires) {
+		return cached.doc, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if cached != nil && cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", u, err)
+	}
+
+	var entry *cacheEntry
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		entry = &cacheEntry{doc: cached.doc, etag: cached.etag}
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%s: %w", u, ErrNotPublished)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s: %s", u, resp.Status)
+	case len(data) > maxDocumentBytes:
+		return nil, fmt.Errorf("%s: document exceeds %d bytes", u, maxDocumentBytes)
+	default:
+		var doc Document
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", u, err)
+		}
+		if err := doc.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", u, err)
+		}
+		entry = &cacheEntry{doc: &doc, etag: resp.Header.Get("ETag")}
+	}
+	entry.expires = now.Add(c.maxAge(resp.Header.Get("Cache-Control")))
+	c.mu.Lock()
+	if c.cache == nil {
+		c.cache = map[string]*cacheEntry{}
+	}
+	c.cache[u] = entry
+	c.mu.Unlock()
+	return entry.doc, nil
+}
+
+// Forget drops the cached document of domain, so the next Discover fetches
+// it again.
+func (c *Client) Forget(domain string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.cache, DocumentURL(domain))
+}
+
+// DocumentURL returns the URL of domain's discovery document.
+func DocumentURL(domain string) string {
+	base := strings.TrimRight(domain, "/")
+	if !strings.HasPrefix(base, "https://") && !strings.HasPrefix(base, "http://") {
+		base = "https://" + base
+	}
+	return base + WellKnownPath
+}
+
+func (c *Client) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// maxAge returns how long a response with the given Cache-Control may be
+// cached.
+func (c *Client) maxAge(cacheControl string) time.Duration {
+	age := c.DefaultMaxAge
+	if age <= 0 {
+		age = time.Hour
+	}
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == "no-store" || directive == "no-cache" {
+			return 0
+		}
+		if v, ok := strings.CutPrefix(directive, "max-age="); ok {
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				age = time.Duration(n) * time.Second
+			}
+		}
+	}
+	if age > maxCacheAge {
+		age = maxCacheAge
+	}
+	return age
+}
@@ -0,0 +1,88 @@
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/discovery"
+)
+
+func TestDocumentURL(t *testing.T) {
+	for in, want := range map[string]string{
+		"example.com":             "https://example.com/.well-known/dcp",
+		"example.com/":            "https://example.com/.well-known/dcp",
+		"http://localhost:8080":   "http://localhost:8080/.well-known/dcp",
+		"https://dcp.example.com": "https://dcp.example.com/.well-known/dcp",
+	} {
+		if got := discovery.DocumentURL(in); got != want {
+			t.Errorf("DocumentURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestClientCaches(t *testing.T) {
+	doc := testDocument(t)
+	h, err := discovery.NewHandler(doc, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fetches, notModified int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code == http.StatusNotModified {
+			notModified++
+		}
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	}))
+	defer ts.Close()
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	c := &discovery.Client{Now: func() time.Time { return now }}
+	ctx := context.Background()
+	got, err := c.Discover(ctx, ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Issuer != doc.Issuer || got.RevocationKey != doc.RevocationKey {
+		t.Fatalf("Discover = %+v", got)
+	}
+	if _, err := c.Discover(ctx, ts.URL); err != nil || fetches != 1 {
+		t.Fatalf("cached Discover: %v, %d fetches", err, fetches)
+	}
+	// Once max-age has passed the document is revalidated, not refetched.
+	now = now.Add(2 * time.Minute)
+	if again, err := c.Discover(ctx, ts.URL); err != nil || again != got || fetches != 2 || notModified != 1 {
+		t.Fatalf("revalidation: %v, %d fetches, %d not modified", err, fetches, notModified)
+	}
+	c.Forget(ts.URL)
+	if _, err := c.Discover(ctx, ts.URL); err != nil || fetches != 3 || notModified != 1 {
+		t.Fatalf("after Forget: %v, %d fetches", err, fetches)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	c := &discovery.Client{}
+	if _, err := c.Discover(context.Background(), ts.URL); !errors.Is(err, discovery.ErrNotPublished) {
+		t.Fatalf("missing document: %v", err)
+	}
+
+	invalid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"issuer": "example.com", "supported_versions": []}`))
+	}))
+	defer invalid.Close()
+	if _, err := c.Discover(context.Background(), invalid.URL); err == nil {
+		t.Fatal("invalid document accepted")
+	}
+}
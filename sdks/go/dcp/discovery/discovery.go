@@ -0,0 +1,243 @@
+// Package discovery publishes and fetches an organisation's DCP
+// configuration: the keys its principals' records are issued under and the
+// URLs of its registry, revocation and transparency log services, served
+// from https://<domain>/.well-known/dcp so a counterparty only needs the
+// domain to find them.
+//
+// The document is authenticated by the TLS certificate of the domain it is
+// served from; the registry, revocation list and log keys it names then
+// authenticate everything fetched from those services.
+package discovery
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/passportlog"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/revocationserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/verifyserver"
+)
+
+// WellKnownPath is where a domain serves its Document.
+const WellKnownPath = "/.well-known/dcp"
+
+// IssuerKey is a key the organisation issues records under, in the form of
+// the DCP-01 attestation key list.
+type IssuerKey struct {
+	KeyID        string `json:"key_id"`
+	PublicKeyB64 string `json:"public_key_b64"`
+	// ValidFrom and ValidUntil bound the key's use; empty means unbounded.
+	ValidFrom  string `json:"valid_from,omitempty"`
+	ValidUntil string `json:"valid_until,omitempty"`
+}
+
+// ValidAt reports whether t is within the key's validity period.
+func (k *IssuerKey) ValidAt(t time.Time) bool {
+	if k.ValidFrom != "" {
+		from, err := dcp.ParseTime(k.ValidFrom)
+		if err != nil || t.Before(from) {
+			return false
+		}
+	}
+	if k.ValidUntil != "" {
+		until, err := dcp.ParseTime(k.ValidUntil)
+		if err != nil || !t.Before(until) {
+			return false
+		}
+	}
+	return true
+}
+
+// Document is the /.well-known/dcp document.
+type Document struct {
+	// Issuer names the organisation, usually its domain.
+	Issuer            string      `json:"issuer"`
+	SupportedVersions []string    `json:"supported_versions"`
+	IssuerKeys        []IssuerKey `json:"issuer_keys"`
+	// RegistryURL is the base URL of a passport registry (package
+	// registry); RegistryKey, if set, is its snapshot signing key.
+	RegistryURL string `json:"registry_url,omitempty"`
+	RegistryKey string `json:"registry_key,omitempty"`
+	// RevocationURL is the base URL of a V1 revocation registry;
+	// RevocationKey, if set, is the key its list is signed with.
+	RevocationURL string `json:"revocation_url,omitempty"`
+	RevocationKey string `json:"revocation_key,omitempty"`
+	// TransparencyLogURL is the base URL of a passport log (package
+	// passportlog) and TransparencyLogKey its tree head key.
+	TransparencyLogURL string `json:"transparency_log_url,omitempty"`
+	TransparencyLogKey string `json:"transparency_log_key,omitempty"`
+}
+
+// Validate checks that the document names at least one supported version
+// and that its keys and URLs are well formed.
+func (d *Document) Validate() error {
+	if len(d.SupportedVersions) == 0 {
+		return errors.New("supported_versions is empty")
+	}
+	for i, k := range d.IssuerKeys {
+		if k.KeyID == "" {
+			return fmt.Errorf("issuer_keys[%d]: key_id is empty", i)
+		}
+		if err := checkKey(k.PublicKeyB64); err != nil {
+			return fmt.Errorf("issuer_keys[%d] %s: %w", i, k.KeyID, err)
+		}
+		for _, ts := range []string{k.ValidFrom, k.ValidUntil} {
+			if _, err := dcp.ParseTime(ts); ts != "" && err != nil {
+				return fmt.Errorf("issuer_keys[%d] %s: %w", i, k.KeyID, err)
+			}
+		}
+	}
+	for _, f := range []struct{ name, url, keyName, key string }{
+		{"registry_url", d.RegistryURL, "registry_key", d.RegistryKey},
+		{"revocation_url", d.RevocationURL, "revocation_key", d.RevocationKey},
+		{"transparency_log_url", d.TransparencyLogURL, "transparency_log_key", d.TransparencyLogKey},
+	} {
+		if f.url != "" {
+			u, err := url.Parse(f.url)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return fmt.Errorf("%s %q is not an http(s) URL", f.name, f.url)
+			}
+		}
+		if f.key != "" {
+			if f.url == "" {
+				return fmt.Errorf("%s is set without %s", f.keyName, f.name)
+			}
+			if err := checkKey(f.key); err != nil {
+				return fmt.Errorf("%s: %w", f.keyName, err)
+			}
+		}
+	}
+	if d.TransparencyLogURL != "" && d.TransparencyLogKey == "" {
+		return errors.New("transparency_log_url is set without transparency_log_key")
+	}
+	return nil
+}
+
+func checkKey(b64 string) error {
+	raw, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return errors.New("not a base64 Ed25519 public key")
+	}
+	return nil
+}
+
+// Supports reports whether the document lists version.
+func (d *Document) Supports(version string) bool {
+	for _, v := range d.SupportedVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// IssuerKeysAt returns the public keys of the issuer keys valid at t.
+func (d *Document) IssuerKeysAt(t time.Time) []string {
+	var keys []string
+	for _, k := range d.IssuerKeys {
+		if k.ValidAt(t) {
+			keys = append(keys, k.PublicKeyB64)
+		}
+	}
+	return keys
+}
+
+// Registry returns a client for the document's registry, or nil if it
+// names none.
+func (d *Document) Registry() *registry.Client {
+	if d.RegistryURL == "" {
+		return nil
+	}
+	return &registry.Client{URL: d.RegistryURL}
+}
+
+// RevocationChecker returns a checker for the document's revocation
+// registry: the signed list when RevocationKey is set, the per-agent
+// /check endpoint otherwise. It returns nil if the document names no
+// revocation registry.
+func (d *Document) RevocationChecker() dcp.RevocationChecker {
+	switch {
+	case d.RevocationURL == "":
+		return nil
+	case d.RevocationKey != "":
+		return &revocationserver.ListChecker{URL: d.RevocationURL, PublicKeyB64: d.RevocationKey}
+	default:
+		return &verifyserver.RegistryRevocations{URL: d.RevocationURL}
+	}
+}
+
+// TransparencyLog returns a client for the document's passport log, or nil
+// if it names none.
+func (d *Document) TransparencyLog() *passportlog.Client {
+	if d.TransparencyLogURL == "" {
+		return nil
+	}
+	return &passportlog.Client{URL: d.TransparencyLogURL, PublicKeyB64: d.TransparencyLogKey}
+}
+
+// NewHandler returns a handler serving doc at any path it is mounted on,
+// usually WellKnownPath. Responses carry an ETag, answer If-None-Match with
+// 304, and may be cached for maxAge; zero means one hour.
+func NewHandler(doc Document, maxAge time.Duration) (http.Handler, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+	if maxAge <= 0 {
+		maxAge = time.Hour
+	}
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return &handler{
+		body:         body,
+		etag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		cacheControl: fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())),
+	}, nil
+}
+
+type handler struct {
+	body         []byte
+	etag         string
+	cacheControl string
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	w.Header().Set("ETag", h.etag)
+	w.Header().Set("Cache-Control", h.cacheControl)
+	// The document is public, and counterparties' browsers may fetch it.
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if match := r.Header.Get("If-None-Match"); match != "" && strings.Contains(match, h.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(h.body)
+	}
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,117 @@
+package discovery_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/discovery"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/revocationserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/verifyserver"
+)
+
+func newKey(t *testing.T) string {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kp.PublicKeyB64
+}
+
+func testDocument(t *testing.T) discovery.Document {
+	t.Helper()
+	return discovery.Document{
+		Issuer:            "example.com",
+		SupportedVersions: []string{"1.0", "2.0"},
+		IssuerKeys: []discovery.IssuerKey{
+			{KeyID: "key-2025", PublicKeyB64: newKey(t), ValidUntil: "2026-01-01T00:00:00Z"},
+			{KeyID: "key-2026", PublicKeyB64: newKey(t), ValidFrom: "2026-01-01T00:00:00Z"},
+		},
+		RegistryURL:        "https://registry.example.com",
+		RevocationURL:      "https://revocation.example.com",
+		RevocationKey:      newKey(t),
+		TransparencyLogURL: "https://log.example.com",
+		TransparencyLogKey: newKey(t),
+	}
+}
+
+func TestDocumentValidate(t *testing.T) {
+	if err := (&discovery.Document{SupportedVersions: []string{"1.0"}}).Validate(); err != nil {
+		t.Fatalf("minimal document: %v", err)
+	}
+	for name, mutate := range map[string]func(*discovery.Document){
+		"no versions":        func(d *discovery.Document) { d.SupportedVersions = nil },
+		"bad issuer key":     func(d *discovery.Document) { d.IssuerKeys[0].PublicKeyB64 = "abc" },
+		"missing key id":     func(d *discovery.Document) { d.IssuerKeys[1].KeyID = "" },
+		"bad valid_from":     func(d *discovery.Document) { d.IssuerKeys[1].ValidFrom = "tomorrow" },
+		"relative url":       func(d *discovery.Document) { d.RegistryURL = "/registry" },
+		"key without url":    func(d *discovery.Document) { d.RegistryURL, d.RegistryKey = "", newKey(t) },
+		"log without key":    func(d *discovery.Document) { d.TransparencyLogKey = "" },
+		"bad revocation key": func(d *discovery.Document) { d.RevocationKey = "not-a-key" },
+	} {
+		d := testDocument(t)
+		mutate(&d)
+		if err := d.Validate(); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestDocumentAccessors(t *testing.T) {
+	d := testDocument(t)
+	if !d.Supports("2.0") || d.Supports("3.0") {
+		t.Fatal("Supports")
+	}
+	keys := d.IssuerKeysAt(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	if len(keys) != 1 || keys[0] != d.IssuerKeys[1].PublicKeyB64 {
+		t.Fatalf("IssuerKeysAt = %v", keys)
+	}
+	if _, ok := d.RevocationChecker().(*revocationserver.ListChecker); !ok {
+		t.Fatalf("RevocationChecker with a key = %T", d.RevocationChecker())
+	}
+	d.RevocationKey = ""
+	if _, ok := d.RevocationChecker().(*verifyserver.RegistryRevocations); !ok {
+		t.Fatalf("RevocationChecker without a key = %T", d.RevocationChecker())
+	}
+	if d.Registry().URL != d.RegistryURL || d.TransparencyLog().PublicKeyB64 != d.TransparencyLogKey {
+		t.Fatal("service clients do not use the document's URLs")
+	}
+	empty := discovery.Document{SupportedVersions: []string{"1.0"}}
+	if empty.Registry() != nil || empty.RevocationChecker() != nil || empty.TransparencyLog() != nil {
+		t.Fatal("clients for services the document does not name")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h, err := discovery.NewHandler(testDocument(t), 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, discovery.WellKnownPath, nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "public, max-age=600" || !strings.Contains(rec.Body.String(), `"issuer_keys"`) {
+		t.Fatalf("GET: %d %v %s", rec.Code, rec.Header(), rec.Body)
+	}
+	etag := rec.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, discovery.WellKnownPath, nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Fatalf("conditional GET: %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, discovery.WellKnownPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST: %d", rec.Code)
+	}
+
+	if _, err := discovery.NewHandler(discovery.Document{}, 0); err == nil {
+		t.Fatal("handler for an invalid document")
+	}
+}
@@ -0,0 +1,135 @@
+package dnsdiscovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+)
+
+// Client discovers passports from their operators' DNS records.
+type Client struct {
+	// Resolver defaults to a Resolver using the system's nameserver.
+	Resolver *Resolver
+	// RequireDNSSEC refuses answers the resolver did not authenticate.
+	RequireDNSSEC bool
+	// HTTPClient fetches passports from registries; nil means
+	// http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Discovery is a discovered passport and how it was found.
+type Discovery struct {
+	Passport *dcp.AgentPassport
+	Record   *Record
+	// RegistryURL is the registry the passport was fetched from.
+	RegistryURL string
+	// Authenticated reports whether DNSSEC authenticated every answer the
+	// discovery relied on.
+	Authenticated bool
+}
+
+// Discover finds the passport of the agent published as label under
+// domain: it looks up the agent's record, the domain's registry if the
+// record names none, and fetches the passport from the registry, which must
+// return a passport self-signed by the key the record names.
+func (c *Client) Discover(ctx context.Context, label, domain string) (*Discovery, error) {
+	res := c.Resolver
+	if res == nil {
+		res = &Resolver{}
+	}
+	name, err := Name(label, domain)
+	if err != nil {
+		return nil, err
+	}
+	txts, ad, err := res.LookupTXT(ctx, name)
+	if err := c.authenticated(name, ad, err); err != nil {
+		return nil, err
+	}
+	rec, err := agentRecord(name, txts)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Discovery{Record: rec, RegistryURL: rec.RegistryURL, Authenticated: ad}
+	if d.RegistryURL == "" {
+		regName, err := RegistryName(domain)
+		if err != nil {
+			return nil, err
+		}
+		svcs, ad, err := res.LookupHTTPS(ctx, regName)
+		if err := c.authenticated(regName, ad, err); err != nil {
+			return nil, err
+		}
+		d.RegistryURL = svcs[0].URL()
+		d.Authenticated = d.Authenticated && ad
+	}
+
+	reg := &registry.Client{URL: d.RegistryURL, HTTPClient: c.HTTPClient}
+	p, err := reg.Passport(ctx, rec.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("dnsdiscovery: %s: %w", d.RegistryURL, err)
+	}
+	if p == nil {
+		return nil, fmt.Errorf("dnsdiscovery: %s has no passport for %s", d.RegistryURL, rec.AgentID)
+	}
+	if err := rec.Match(p); err != nil {
+		return nil, err
+	}
+	d.Passport = p
+	return d, nil
+}
+
+// Match checks that p is the passport r names, self-signed by the key r
+// names.
+func (r *Record) Match(p *dcp.AgentPassport) error {
+	if p.AgentID != r.AgentID {
+		return fmt.Errorf("dnsdiscovery: %w: passport is of %s, not %s", ErrMismatch, p.AgentID, r.AgentID)
+	}
+	if kh, err := KeyHash(p.PublicKey); err != nil || kh != r.KeyHash {
+		return fmt.Errorf("dnsdiscovery: %w: passport key of %s is not %s", ErrMismatch, r.AgentID, r.KeyHash)
+	}
+	if ok, err := p.VerifySignature(); err != nil {
+		return fmt.Errorf("dnsdiscovery: %w: passport of %s: %v", ErrMismatch, r.AgentID, err)
+	} else if !ok {
+		return fmt.Errorf("dnsdiscovery: %w: passport of %s is not self-signed", ErrMismatch, r.AgentID)
+	}
+	return nil
+}
+
+func (c *Client) authenticated(name string, ad bool, err error) error {
+	if err != nil {
+		return err
+	}
+	if c.RequireDNSSEC && !ad {
+		return fmt.Errorf("dnsdiscovery: %s: %w", name, ErrNotAuthenticated)
+	}
+	return nil
+}
+
+// agentRecord returns the one DCP record among the TXT records at name,
+// ignoring those of other protocols.
+func agentRecord(name string, txts []string) (*Record, error) {
+	var recs []string
+	for _, txt := range txts {
+		v, _, _ := strings.Cut(txt, ";")
+		if strings.EqualFold(strings.ReplaceAll(v, " ", ""), "v="+Version) {
+			recs = append(recs, txt)
+		}
+	}
+	switch len(recs) {
+	case 0:
+		return nil, fmt.Errorf("dnsdiscovery: %s: %w", name, ErrNotPublished)
+	case 1:
+		rec, err := ParseRecord(recs[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w (at %s)", err, name)
+		}
+		return rec, nil
+	default:
+		return nil, fmt.Errorf("dnsdiscovery: %s has more than one DCP record", name)
+	}
+}
@@ -0,0 +1,108 @@
+package dnsdiscovery_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dnsdiscovery"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// registryStub serves passports on the registry API's passport route.
+type registryStub struct {
+	mu        sync.Mutex
+	passports map[string]dcp.AgentPassport
+}
+
+func (s *registryStub) set(p *dcp.AgentPassport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.passports[p.AgentID] = *p
+}
+
+func (s *registryStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, _ := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/v1/passports/"))
+	s.mu.Lock()
+	p, ok := s.passports[id]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	json.NewEncoder(w).Encode(p)
+}
+
+func TestDiscover(t *testing.T) {
+	ctx := context.Background()
+	billing, support, impostor := newAgent(t), newAgent(t), newAgent(t)
+	impostor.AgentID = support.AgentID
+	reg := &registryStub{passports: map[string]dcp.AgentPassport{}}
+	reg.set(billing)
+	reg.set(impostor)
+	srv := httptest.NewTLSServer(reg)
+	defer srv.Close()
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+
+	z := newZone(t)
+	billingRec, _ := dnsdiscovery.RecordFor(billing, srv.URL)
+	// The support agent's record names the domain's registry, which serves
+	// a passport under another key.
+	supportRec, _ := dnsdiscovery.RecordFor(support, "")
+	z.set(func(z *zone) {
+		z.txt["billing._dcp.example.com."] = []string{"v=spf1 -all", billingRec.String()}
+		z.txt["support._dcp.example.com."] = []string{supportRec.String()}
+		z.https["_dcp.example.com."] = []dnsmessage.HTTPSResource{{SVCBResource: dnsmessage.SVCBResource{Priority: 1,
+			Target: dnsmessage.MustNewName(host + "."), Params: []dnsmessage.SVCParam{{Key: dnsmessage.SVCParamPort, Value: []byte{byte(portNum >> 8), byte(portNum)}}}}}}
+		z.txt["twice._dcp.example.com."] = []string{supportRec.String(), supportRec.String()}
+		z.txt["other._dcp.example.com."] = []string{"v=spf1 -all"}
+	})
+
+	c := &dnsdiscovery.Client{Resolver: &dnsdiscovery.Resolver{Server: z.addr, Timeout: 2 * time.Second}, HTTPClient: srv.Client()}
+	d, err := c.Discover(ctx, "billing", "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Passport.AgentID != billing.AgentID || d.RegistryURL != srv.URL || d.Authenticated {
+		t.Fatalf("discovered %+v", d)
+	}
+
+	if _, err := c.Discover(ctx, "support", "example.com"); !errors.Is(err, dnsdiscovery.ErrMismatch) {
+		t.Fatalf("passport under another key: %v", err)
+	}
+	reg.set(support)
+	d, err = c.Discover(ctx, "support", "example.com")
+	if err != nil || d.RegistryURL != srv.URL {
+		t.Fatalf("registry from the HTTPS record: %+v, %v", d, err)
+	}
+
+	for _, label := range []string{"other", "missing"} {
+		if _, err := c.Discover(ctx, label, "example.com"); !errors.Is(err, dnsdiscovery.ErrNotPublished) {
+			t.Errorf("%s: %v", label, err)
+		}
+	}
+	if _, err := c.Discover(ctx, "twice", "example.com"); err == nil {
+		t.Error("discovered through two records")
+	}
+
+	// DNSSEC: unsigned answers are refused when required.
+	c.RequireDNSSEC = true
+	if _, err := c.Discover(ctx, "billing", "example.com"); !errors.Is(err, dnsdiscovery.ErrNotAuthenticated) {
+		t.Fatalf("unsigned answer: %v", err)
+	}
+	z.set(func(z *zone) { z.signed = true })
+	if d, err := c.Discover(ctx, "support", "example.com"); err != nil || !d.Authenticated {
+		t.Fatalf("signed answers: %+v, %v", d, err)
+	}
+}
@@ -0,0 +1,196 @@
+// Package dnsdiscovery finds an agent's passport from its operator's domain
+// name alone. The operator publishes, for each agent, a TXT record at
+// <label>._dcp.<domain> naming the agent, the hash of its passport key and
+// the registry that serves the passport:
+//
+//	billing._dcp.example.com. TXT "v=DCP1; id=dcp:agent:...; kh=sha256:3f1c...; reg=https://registry.example.com"
+//
+// A record without reg= uses the domain's registry, published as an HTTPS
+// record at _dcp.<domain>:
+//
+//	_dcp.example.com. HTTPS 1 registry.example.com. port=8443
+//
+// Queries go to a DNSSEC-validating resolver with the DO bit set, and the
+// resolver's AD bit says whether the answers were validated. A Client that
+// requires DNSSEC refuses unvalidated answers; the passport it fetches must
+// then carry the key the zone names, so a registry cannot substitute
+// another. The AD bit is only as trustworthy as the path to the resolver,
+// which should be local or reached over a trusted network.
+package dnsdiscovery
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Version is the v= tag of the records this package reads and writes.
+const Version = "DCP1"
+
+// ServiceLabel is the label under which a domain publishes its records.
+const ServiceLabel = "_dcp"
+
+var (
+	// ErrNotPublished reports a name with no DCP record.
+	ErrNotPublished = errors.New("no DCP record published")
+	// ErrNotAuthenticated reports an answer that DNSSEC did not validate
+	// when validation is required.
+	ErrNotAuthenticated = errors.New("DNS answer not authenticated by DNSSEC")
+	// ErrMismatch reports a passport that is not the one its record names.
+	ErrMismatch = errors.New("passport does not match its DNS record")
+)
+
+// Record is an agent's TXT record.
+type Record struct {
+	AgentID string
+	// KeyHash is "sha256:" and the hex SHA-256 of the passport's raw
+	// public key.
+	KeyHash string
+	// RegistryURL is the base URL of the registry serving the passport;
+	// empty means the domain's registry.
+	RegistryURL string
+}
+
+// RecordFor returns the record of passport p, served by the registry at
+// registryURL, or by the domain's registry if registryURL is empty.
+func RecordFor(p *dcp.AgentPassport, registryURL string) (*Record, error) {
+	kh, err := KeyHash(p.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("dnsdiscovery: agent %s: %v", p.AgentID, err)
+	}
+	r := &Record{AgentID: p.AgentID, KeyHash: kh, RegistryURL: registryURL}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// KeyHash returns the key hash of a base64 public key.
+func KeyHash(publicKeyB64 string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(publicKeyB64)
+	if err != nil || len(raw) == 0 {
+		return "", fmt.Errorf("public key is not base64")
+	}
+	sum := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// ParseRecord parses the text of a TXT record. Unknown tags are ignored so
+// later versions can add some.
+func ParseRecord(txt string) (*Record, error) {
+	var r Record
+	seen := map[string]bool{}
+	for _, field := range strings.Split(txt, ";") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		tag, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("dnsdiscovery: field %q has no '='", field)
+		}
+		tag, value = strings.ToLower(strings.TrimSpace(tag)), strings.TrimSpace(value)
+		if (len(seen) == 0) != (tag == "v") {
+			return nil, fmt.Errorf("dnsdiscovery: record does not start with v=%s", Version)
+		}
+		if seen[tag] {
+			return nil, fmt.Errorf("dnsdiscovery: repeated tag %s", tag)
+		}
+		seen[tag] = true
+		switch tag {
+		case "v":
+			if !strings.EqualFold(value, Version) {
+				return nil, fmt.Errorf("dnsdiscovery: unsupported version %q", value)
+			}
+		case "id":
+			r.AgentID = value
+		case "kh":
+			r.KeyHash = strings.ToLower(value)
+		case "reg":
+			r.RegistryURL = value
+		}
+	}
+	if !seen["v"] {
+		return nil, fmt.Errorf("dnsdiscovery: record does not start with v=%s", Version)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Validate checks that r names an agent, a SHA-256 key hash and, if any,
+// an absolute http or https registry URL.
+func (r *Record) Validate() error {
+	if r.AgentID == "" || strings.ContainsAny(r.AgentID, "; \"") {
+		return fmt.Errorf("dnsdiscovery: invalid agent id %q", r.AgentID)
+	}
+	hexHash, ok := strings.CutPrefix(r.KeyHash, "sha256:")
+	if b, err := hex.DecodeString(hexHash); !ok || err != nil || len(b) != sha256.Size {
+		return fmt.Errorf("dnsdiscovery: invalid key hash %q", r.KeyHash)
+	}
+	if r.RegistryURL != "" {
+		u, err := url.Parse(r.RegistryURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || strings.ContainsAny(r.RegistryURL, "; \"") {
+			return fmt.Errorf("dnsdiscovery: invalid registry url %q", r.RegistryURL)
+		}
+	}
+	return nil
+}
+
+// String returns the text of r's TXT record.
+func (r *Record) String() string {
+	s := "v=" + Version + "; id=" + r.AgentID + "; kh=" + r.KeyHash
+	if r.RegistryURL != "" {
+		s += "; reg=" + r.RegistryURL
+	}
+	return s
+}
+
+// ZoneLine returns r as a zone file line for the agent published as label
+// under domain, split into strings of at most 255 bytes as TXT requires.
+func (r *Record) ZoneLine(label, domain string, ttl time.Duration) (string, error) {
+	name, err := Name(label, domain)
+	if err != nil {
+		return "", err
+	}
+	var quoted []string
+	for s := r.String(); s != ""; {
+		n := min(len(s), 255)
+		quoted = append(quoted, `"`+s[:n]+`"`)
+		s = s[n:]
+	}
+	return fmt.Sprintf("%s %d IN TXT %s", name, int(ttl.Seconds()), strings.Join(quoted, " ")), nil
+}
+
+// Name returns the name of the record of the agent published as label
+// under domain, e.g. "billing._dcp.example.com.".
+func Name(label, domain string) (string, error) {
+	return fqdn(label + "." + ServiceLabel + "." + domain)
+}
+
+// RegistryName returns the name of domain's HTTPS registry record.
+func RegistryName(domain string) (string, error) {
+	return fqdn(ServiceLabel + "." + domain)
+}
+
+func fqdn(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSuffix(name, ".")) + "."
+	if _, err := dnsmessage.NewName(name); err != nil {
+		return "", fmt.Errorf("dnsdiscovery: invalid name %q", name)
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if label == "" || len(label) > 63 {
+			return "", fmt.Errorf("dnsdiscovery: invalid name %q", name)
+		}
+	}
+	return name, nil
+}
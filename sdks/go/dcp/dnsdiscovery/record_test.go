@@ -0,0 +1,89 @@
+package dnsdiscovery_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dnsdiscovery"
+)
+
+func newAgent(t *testing.T) *dcp.AgentPassport {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), kp.PublicKeyB64, []string{"api_call"}, dcp.RiskTierLow)
+	if err := p.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	return &p
+}
+
+func TestRecord(t *testing.T) {
+	p := newAgent(t)
+	rec, err := dnsdiscovery.RecordFor(p, "https://registry.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(rec.KeyHash, "sha256:") || len(rec.KeyHash) != 7+64 {
+		t.Fatalf("key hash %q", rec.KeyHash)
+	}
+	parsed, err := dnsdiscovery.ParseRecord(rec.String())
+	if err != nil || *parsed != *rec {
+		t.Fatalf("round trip of %q: %+v, %v", rec, parsed, err)
+	}
+	if err := rec.Match(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Match(newAgent(t)); err == nil {
+		t.Fatal("matched another agent's passport")
+	}
+
+	// Tags are case-insensitive, unknown ones are ignored, and reg= is
+	// optional.
+	txt := "V=dcp1;ID=" + p.AgentID + " ; kh=SHA256:" + strings.ToUpper(rec.KeyHash[7:]) + "; ttl=3600"
+	if r, err := dnsdiscovery.ParseRecord(txt); err != nil || r.KeyHash != rec.KeyHash || r.RegistryURL != "" {
+		t.Fatalf("%q: %+v, %v", txt, r, err)
+	}
+
+	for name, txt := range map[string]string{
+		"no version":       "id=" + p.AgentID + "; kh=" + rec.KeyHash,
+		"version later":    "id=" + p.AgentID + "; v=DCP1; kh=" + rec.KeyHash,
+		"other version":    "v=DCP2; id=" + p.AgentID + "; kh=" + rec.KeyHash,
+		"no id":            "v=DCP1; kh=" + rec.KeyHash,
+		"bare hash":        "v=DCP1; id=" + p.AgentID + "; kh=" + rec.KeyHash[7:],
+		"short hash":       "v=DCP1; id=" + p.AgentID + "; kh=sha256:abcd",
+		"repeated tag":     "v=DCP1; id=" + p.AgentID + "; id=" + p.AgentID + "; kh=" + rec.KeyHash,
+		"no equals":        "v=DCP1; id",
+		"relative reg":     "v=DCP1; id=" + p.AgentID + "; kh=" + rec.KeyHash + "; reg=/v1",
+		"other reg scheme": "v=DCP1; id=" + p.AgentID + "; kh=" + rec.KeyHash + "; reg=ftp://registry.example.com",
+	} {
+		if _, err := dnsdiscovery.ParseRecord(txt); err == nil {
+			t.Errorf("%s: parsed", name)
+		}
+	}
+}
+
+func TestZoneLine(t *testing.T) {
+	rec := &dnsdiscovery.Record{AgentID: "dcp:agent:" + strings.Repeat("a", 250), KeyHash: "sha256:" + strings.Repeat("0", 64)}
+	line, err := rec.ZoneLine("Billing", "example.com", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `billing._dcp.example.com. 3600 IN TXT "` + rec.String()[:255] + `" "` + rec.String()[255:] + `"`
+	if line != want {
+		t.Fatalf("zone line:\n%s\nwant:\n%s", line, want)
+	}
+	for _, label := range []string{"", "a.", strings.Repeat("a", 64)} {
+		if _, err := rec.ZoneLine(label, "example.com", time.Hour); err == nil {
+			t.Errorf("label %q accepted", label)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package dnsdiscovery
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Resolver queries a recursive resolver with DNSSEC requested: each query
+// sets the EDNS0 DO bit and the AD bit, and reports the AD bit of the
+// answer. A Resolver is safe for concurrent use.
+type Resolver struct {
+	// Server is the "host:port" of a DNSSEC-validating resolver; empty
+	// means the first nameserver of /etc/resolv.conf.
+	Server string
+	// Timeout bounds each query; zero means five seconds.
+	Timeout time.Duration
+}
+
+// Service is the target of an HTTPS record.
+type Service struct {
+	Priority uint16
+	// Host is the target name without its trailing dot.
+	Host string
+	// Port is the port= parameter; zero means 443.
+	Port uint16
+}
+
+// URL returns the base URL of the registry s serves.
+func (s Service) URL() string {
+	if s.Port == 0 || s.Port == 443 {
+		return "https://" + s.Host
+	}
+	return "https://" + s.Host + ":" + strconv.Itoa(int(s.Port))
+}
+
+// LookupTXT returns the TXT records at name, each with its strings joined,
+// and whether the answer was authenticated. A name that does not exist or
+// has no TXT records returns ErrNotPublished.
+func (r *Resolver) LookupTXT(ctx context.Context, name string) ([]string, bool, error) {
+	var txts []string
+	ad, err := r.lookup(ctx, name, dnsmessage.TypeTXT, func(p *dnsmessage.Parser) error {
+		rr, err := p.TXTResource()
+		if err == nil {
+			txts = append(txts, strings.Join(rr.TXT, ""))
+		}
+		return err
+	})
+	if err == nil && len(txts) == 0 {
+		err = fmt.Errorf("dnsdiscovery: %s TXT: %w", name, ErrNotPublished)
+	}
+	return txts, ad, err
+}
+
+// LookupHTTPS returns the service-mode HTTPS records at name by priority,
+// and whether the answer was authenticated. An alias-mode record's target
+// is returned with priority zero, first.
+func (r *Resolver) LookupHTTPS(ctx context.Context, name string) ([]Service, bool, error) {
+	var svcs []Service
+	ad, err := r.lookup(ctx, name, dnsmessage.TypeHTTPS, func(p *dnsmessage.Parser) error {
+		rr, err := p.HTTPSResource()
+		if err != nil {
+			return err
+		}
+		svc := Service{Priority: rr.Priority, Host: strings.TrimSuffix(rr.Target.String(), ".")}
+		if svc.Host == "" {
+			// "." targets the owner name itself.
+			svc.Host = strings.TrimSuffix(name, ".")
+		}
+		if port, ok := rr.GetParam(dnsmessage.SVCParamPort); ok && len(port) == 2 {
+			svc.Port = binary.BigEndian.Uint16(port)
+		}
+		svcs = append(svcs, svc)
+		return nil
+	})
+	if err == nil && len(svcs) == 0 {
+		err = fmt.Errorf("dnsdiscovery: %s HTTPS: %w", name, ErrNotPublished)
+	}
+	sort.SliceStable(svcs, func(i, j int) bool { return svcs[i].Priority < svcs[j].Priority })
+	return svcs, ad, err
+}
+
+// lookup queries name for qtype and calls parse on each answer of that
+// type.
+func (r *Resolver) lookup(ctx context.Context, name string, qtype dnsmessage.Type, parse func(*dnsmessage.Parser) error) (bool, error) {
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return false, fmt.Errorf("dnsdiscovery: invalid name %q", name)
+	}
+	q := dnsmessage.Question{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}
+	resp, err := r.exchange(ctx, q)
+	if err != nil {
+		return false, fmt.Errorf("dnsdiscovery: %s %s: %w", name, qtype, err)
+	}
+
+	var p dnsmessage.Parser
+	h, err := p.Start(resp)
+	if err != nil {
+		return false, fmt.Errorf("dnsdiscovery: %s %s: %v", name, qtype, err)
+	}
+	switch h.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return h.AuthenticData, fmt.Errorf("dnsdiscovery: %s %s: %w", name, qtype, ErrNotPublished)
+	default:
+		return false, fmt.Errorf("dnsdiscovery: %s %s: %s", name, qtype, h.RCode)
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return false, fmt.Errorf("dnsdiscovery: %s %s: %v", name, qtype, err)
+	}
+	for {
+		ah, err := p.AnswerHeader()
+		if errors.Is(err, dnsmessage.ErrSectionDone) {
+			return h.AuthenticData, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("dnsdiscovery: %s %s: %v", name, qtype, err)
+		}
+		// Answers of other types are the CNAMEs leading to the records.
+		if ah.Type != qtype || ah.Class != dnsmessage.ClassINET {
+			if err := p.SkipAnswer(); err != nil {
+				return false, fmt.Errorf("dnsdiscovery: %s %s: %v", name, qtype, err)
+			}
+			continue
+		}
+		if err := parse(&p); err != nil {
+			return false, fmt.Errorf("dnsdiscovery: %s %s: %v", name, qtype, err)
+		}
+	}
+}
+
+// exchange sends q over UDP, and again over TCP if the answer was
+// truncated, returning the raw response.
+func (r *Resolver) exchange(ctx context.Context, q dnsmessage.Question) ([]byte, error) {
+	server := r.Server
+	if server == "" {
+		var err error
+		if server, err = systemServer(); err != nil {
+			return nil, err
+		}
+	}
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var id [2]byte
+	rand.Read(id[:])
+	query, err := buildQuery(binary.BigEndian.Uint16(id[:]), q)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := roundTrip(ctx, "udp", server, query, q)
+	if err != nil {
+		return nil, err
+	}
+	var p dnsmessage.Parser
+	if h, err := p.Start(resp); err == nil && h.Truncated {
+		return roundTrip(ctx, "tcp", server, query, q)
+	}
+	return resp, nil
+}
+
+// maxUDPSize is the EDNS0 payload size advertised, the size DNS flag day
+// 2020 settled on.
+const maxUDPSize = 1232
+
+func buildQuery(id uint16, q dnsmessage.Question) ([]byte, error) {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, RecursionDesired: true, AuthenticData: true})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(q); err != nil {
+		return nil, err
+	}
+	if err := b.StartAdditionals(); err != nil {
+		return nil, err
+	}
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(maxUDPSize, dnsmessage.RCodeSuccess, true); err != nil {
+		return nil, err
+	}
+	if err := b.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+// roundTrip sends query to server and returns the response matching its ID
+// and question.
+func roundTrip(ctx context.Context, network, server string, query []byte, q dnsmessage.Question) ([]byte, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if network == "tcp" {
+		msg := make([]byte, 2+len(query))
+		binary.BigEndian.PutUint16(msg, uint16(len(query)))
+		copy(msg[2:], query)
+		if _, err := conn.Write(msg); err != nil {
+			return nil, err
+		}
+		var n [2]byte
+		if _, err := io.ReadFull(conn, n[:]); err != nil {
+			return nil, err
+		}
+		resp := make([]byte, binary.BigEndian.Uint16(n[:]))
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			return nil, err
+		}
+		if !matches(resp, query, q) {
+			return nil, errors.New("response does not match query")
+		}
+		return resp, nil
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 65535)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		// Mismatched datagrams may be spoofed or stale; keep waiting.
+		if matches(buf[:n], query, q) {
+			return buf[:n], nil
+		}
+	}
+}
+
+func matches(resp, query []byte, q dnsmessage.Question) bool {
+	var p dnsmessage.Parser
+	h, err := p.Start(resp)
+	if err != nil || !h.Response || h.ID != binary.BigEndian.Uint16(query) {
+		return false
+	}
+	got, err := p.Question()
+	return err == nil && got.Type == q.Type && got.Class == q.Class && strings.EqualFold(got.Name.String(), q.Name.String())
+}
+
+// systemServer returns the first nameserver of /etc/resolv.conf.
+func systemServer() (string, error) {
+	f, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return "", fmt.Errorf("no resolver configured: %v", err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return net.JoinHostPort(fields[1], "53"), nil
+		}
+	}
+	return "", errors.New("no nameserver in /etc/resolv.conf")
+}
@@ -0,0 +1,216 @@
+package dnsdiscovery_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dnsdiscovery"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// zone is a fake recursive resolver answering from a map over UDP and TCP.
+type zone struct {
+	addr string
+
+	mu sync.Mutex
+	// txt and https are keyed by lower-case FQDN.
+	txt   map[string][]string
+	https map[string][]dnsmessage.HTTPSResource
+	// signed reports whether answers carry the AD bit.
+	signed bool
+	// truncate makes UDP answers truncated, forcing TCP.
+	truncate bool
+	// lastDO is whether the last query set the DO bit.
+	lastDO bool
+	tcp    int
+}
+
+func newZone(t *testing.T) *zone {
+	t.Helper()
+	var pc net.PacketConn
+	var ln net.Listener
+	for i := 0; ; i++ {
+		var err error
+		if pc, err = net.ListenPacket("udp", "127.0.0.1:0"); err != nil {
+			t.Fatal(err)
+		}
+		if ln, err = net.Listen("tcp", pc.LocalAddr().String()); err == nil {
+			break
+		}
+		pc.Close()
+		if i == 10 {
+			t.Fatal(err)
+		}
+	}
+	z := &zone{addr: pc.LocalAddr().String(), txt: map[string][]string{}, https: map[string][]dnsmessage.HTTPSResource{}}
+	t.Cleanup(func() { pc.Close(); ln.Close() })
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, from, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := z.answer(buf[:n], true); resp != nil {
+				pc.WriteTo(resp, from)
+			}
+		}
+	}()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			var n [2]byte
+			io.ReadFull(conn, n[:])
+			query := make([]byte, binary.BigEndian.Uint16(n[:]))
+			io.ReadFull(conn, query)
+			z.mu.Lock()
+			z.tcp++
+			z.mu.Unlock()
+			resp := z.answer(query, false)
+			binary.BigEndian.PutUint16(n[:], uint16(len(resp)))
+			conn.Write(append(n[:], resp...))
+			conn.Close()
+		}
+	}()
+	return z
+}
+
+func (z *zone) answer(query []byte, udp bool) []byte {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	var p dnsmessage.Parser
+	h, err := p.Start(query)
+	if err != nil {
+		return nil
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil
+	}
+	p.SkipAllQuestions()
+	p.SkipAllAnswers()
+	p.SkipAllAuthorities()
+	z.lastDO = false
+	for {
+		ah, err := p.AdditionalHeader()
+		if err != nil {
+			break
+		}
+		if ah.Type == dnsmessage.TypeOPT {
+			z.lastDO = ah.DNSSECAllowed()
+		}
+		p.SkipAdditional()
+	}
+
+	name := strings.ToLower(q.Name.String())
+	txts, https := z.txt[name], z.https[name]
+	rh := dnsmessage.Header{ID: h.ID, Response: true, RecursionDesired: true, RecursionAvailable: true, AuthenticData: z.signed}
+	if txts == nil && https == nil {
+		rh.RCode = dnsmessage.RCodeNameError
+	}
+	if udp && z.truncate {
+		rh.Truncated = true
+		txts, https = nil, nil
+	}
+	b := dnsmessage.NewBuilder(nil, rh)
+	b.StartQuestions()
+	b.Question(q)
+	b.StartAnswers()
+	hdr := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 300}
+	switch q.Type {
+	case dnsmessage.TypeTXT:
+		for _, txt := range txts {
+			var parts []string
+			for ; len(txt) > 255; txt = txt[255:] {
+				parts = append(parts, txt[:255])
+			}
+			b.TXTResource(hdr, dnsmessage.TXTResource{TXT: append(parts, txt)})
+		}
+	case dnsmessage.TypeHTTPS:
+		for _, rr := range https {
+			b.HTTPSResource(hdr, rr)
+		}
+	}
+	resp, _ := b.Finish()
+	return resp
+}
+
+func (z *zone) set(f func(z *zone)) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	f(z)
+}
+
+// stats returns whether the last query set the DO bit and the number of
+// queries over TCP.
+func (z *zone) stats() (bool, int) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	return z.lastDO, z.tcp
+}
+
+func TestResolver(t *testing.T) {
+	ctx := context.Background()
+	z := newZone(t)
+	long := "v=spf1 " + strings.Repeat("include:a.example.com ", 20) + "-all"
+	port := dnsmessage.HTTPSResource{SVCBResource: dnsmessage.SVCBResource{Priority: 2, Target: dnsmessage.MustNewName("backup.example.com."),
+		Params: []dnsmessage.SVCParam{{Key: dnsmessage.SVCParamPort, Value: []byte{0x20, 0xfb}}}}}
+	z.set(func(z *zone) {
+		z.txt["example.com."] = []string{"hello", long}
+		z.https["_dcp.example.com."] = []dnsmessage.HTTPSResource{port, {SVCBResource: dnsmessage.SVCBResource{Priority: 1, Target: dnsmessage.MustNewName(".")}}}
+	})
+	r := &dnsdiscovery.Resolver{Server: z.addr, Timeout: 2 * time.Second}
+
+	txts, ad, err := r.LookupTXT(ctx, "Example.COM.")
+	if err != nil || ad || len(txts) != 2 || txts[1] != long {
+		t.Fatalf("TXT: %q, %v, %v", txts, ad, err)
+	}
+	if do, _ := z.stats(); !do {
+		t.Fatal("query did not set the DO bit")
+	}
+
+	z.set(func(z *zone) { z.signed = true })
+	svcs, ad, err := r.LookupHTTPS(ctx, "_dcp.example.com.")
+	if err != nil || !ad || len(svcs) != 2 {
+		t.Fatalf("HTTPS: %+v, %v, %v", svcs, ad, err)
+	}
+	if svcs[0].URL() != "https://_dcp.example.com" || svcs[1].URL() != "https://backup.example.com:8443" {
+		t.Fatalf("services by priority: %s, %s", svcs[0].URL(), svcs[1].URL())
+	}
+
+	z.set(func(z *zone) { z.truncate = true })
+	txts, ad, err = r.LookupTXT(ctx, "example.com.")
+	if _, tcp := z.stats(); err != nil || !ad || len(txts) != 2 || tcp != 1 {
+		t.Fatalf("TCP fallback: %q, %v, %v (%d TCP queries)", txts, ad, err, tcp)
+	}
+
+	if _, _, err := r.LookupTXT(ctx, "missing.example.com."); !errors.Is(err, dnsdiscovery.ErrNotPublished) {
+		t.Fatalf("NXDOMAIN: %v", err)
+	}
+	if _, _, err := r.LookupHTTPS(ctx, "example.com."); !errors.Is(err, dnsdiscovery.ErrNotPublished) {
+		t.Fatalf("no HTTPS records: %v", err)
+	}
+}
+
+func TestResolverUnreachable(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	r := &dnsdiscovery.Resolver{Server: pc.LocalAddr().String(), Timeout: 100 * time.Millisecond}
+	if _, _, err := r.LookupTXT(context.Background(), "example.com."); err == nil || errors.Is(err, dnsdiscovery.ErrNotPublished) {
+		t.Fatalf("silent server: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrDomainRefused is returned by CheckDomains for a target domain that a
+// passport or intent domain list does not admit.
+var ErrDomainRefused = errors.New("target domain refused")
+
+// ErrCapabilityNotGranted is returned by CheckCapability for an intent that
+// exercises a capability its agent's passport does not grant.
+var ErrCapabilityNotGranted = errors.New("capability not granted")
+
+// DomainList admits or refuses target domains by pattern: a domain, or
+// "*.example.com" for its subdomains, as in capability constraints. Deny
+// wins over Allow, and an empty Allow admits every domain not denied.
+type DomainList struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// Admits reports whether the list admits domain.
+func (l *DomainList) Admits(domain string) bool {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	for _, p := range l.Deny {
+		if domainMatches(p, domain) {
+			return false
+		}
+	}
+	if len(l.Allow) == 0 {
+		return true
+	}
+	for _, p := range l.Allow {
+		if domainMatches(p, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func domainMatches(pattern, domain string) bool {
+	pattern = strings.ToLower(pattern)
+	return pattern == domain || patternCovers(pattern, domain)
+}
+
+func (l *DomainList) clone() *DomainList {
+	if l == nil {
+		return nil
+	}
+	return &DomainList{Allow: cloneStrings(l.Allow), Deny: cloneStrings(l.Deny)}
+}
+
+func (l *DomainList) validate(v validator) {
+	for _, member := range []struct {
+		name string
+		list []string
+	}{{"allow", l.Allow}, {"deny", l.Deny}} {
+		for i, p := range member.list {
+			if !capabilityTokenPattern.MatchString(strings.ToLower(p)) {
+				v.at(member.name).index(i).fail("must be a domain or *.domain pattern")
+			}
+		}
+	}
+}
+
+func sortedDomainKeys(m map[string]DomainList) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// actionCapabilities maps each intent action_type to the capability, in
+// the grammar, that it exercises.
+var actionCapabilities = map[string]string{
+	"browse":                "browse:read",
+	"api_call":              "api_call:call",
+	"send_email":            "email:send",
+	"create_calendar_event": "calendar:create",
+	"initiate_payment":      "payments:initiate",
+	"update_crm":            "crm:update",
+	"write_file":            "file_write:write",
+	"execute_code":          "code_exec:execute",
+}
+
+// CapabilityOf returns the passport capability an intent action_type
+// exercises, such as "email" for "send_email", or "" for an unknown one.
+func CapabilityOf(actionType string) string {
+	resource, _, _ := strings.Cut(actionCapabilities[actionType], ":")
+	return resource
+}
+
+// IntentCapability returns the capability an intent action_type exercises
+// in the capability grammar, such as email:send for send_email, and false
+// for an unknown one.
+func IntentCapability(actionType string) (Capability, bool) {
+	s, ok := actionCapabilities[actionType]
+	if !ok {
+		return Capability{}, false
+	}
+	return MustParseCapability(s), true
+}
+
+// Host returns the domain the target is at: its domain, the host of its
+// URL, or the domain of its recipient, in lower case; "" if it has none.
+func (t IntentTarget) Host() string {
+	switch {
+	case t.Domain != nil && *t.Domain != "":
+		return strings.ToLower(*t.Domain)
+	case t.URL != nil:
+		if u, err := url.Parse(*t.URL); err == nil {
+			return strings.ToLower(u.Hostname())
+		}
+	case t.To != nil:
+		if _, domain, ok := strings.Cut(*t.To, "@"); ok {
+			return strings.ToLower(domain)
+		}
+	}
+	return ""
+}
+
+// targetHosts returns every host target names, in lower case: its domain,
+// the host of its URL and the domain of its recipient. Checks run on all of
+// them, so that no one of them can be used to slip past a check of another.
+func targetHosts(target IntentTarget) ([]string, error) {
+	var hosts []string
+	if d := target.Domain; d != nil && *d != "" {
+		hosts = append(hosts, strings.ToLower(*d))
+	}
+	if target.URL != nil {
+		u, err := url.Parse(*target.URL)
+		if err != nil {
+			return nil, fmt.Errorf("target url: %v", err)
+		}
+		if h := u.Hostname(); h != "" {
+			hosts = append(hosts, strings.ToLower(h))
+		}
+	}
+	if target.To != nil {
+		if _, domain, ok := strings.Cut(*target.To, "@"); ok && domain != "" {
+			hosts = append(hosts, strings.ToLower(domain))
+		}
+	}
+	return hosts, nil
+}
+
+// CheckDomains checks every host the intent's target names, its domain, URL
+// host and recipient domain, against the intent's own domain list and, if
+// passport is not nil, the passport's domain list for the capability the
+// intent exercises. An intent without a target host passes.
+func CheckDomains(passport *AgentPassport, intent *Intent) error {
+	hosts, err := targetHosts(intent.Target)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDomainRefused, err)
+	}
+	capability := CapabilityOf(intent.ActionType)
+	for _, host := range hosts {
+		if intent.Domains != nil && !intent.Domains.Admits(host) {
+			return fmt.Errorf("%w: %s is not admitted by the intent's domains", ErrDomainRefused, host)
+		}
+		if passport == nil {
+			continue
+		}
+		if list, ok := passport.CapabilityDomains[capability]; ok && !list.Admits(host) {
+			return fmt.Errorf("%w: %s is not admitted for capability %s by the passport", ErrDomainRefused, host, capability)
+		}
+	}
+	return nil
+}
+
+// CheckCapability checks that passport grants the capability intent
+// exercises, as the capability grammar matches them: "email" and "email:*"
+// grant send_email anywhere, "email:send:*.example.com" only if every host
+// the target names, its domain, URL host and recipient domain, is under
+// example.com. Intents state no amount, so a limit constraint grants the
+// action and the amount is left to policy. It fails closed: an intent of an
+// action type no capability is known for, and a passport that lists no
+// capabilities, are refused.
+func CheckCapability(passport *AgentPassport, intent *Intent) error {
+	want, ok := IntentCapability(intent.ActionType)
+	if !ok {
+		return fmt.Errorf("%w: no capability is known for action type %q", ErrCapabilityNotGranted, intent.ActionType)
+	}
+	if len(passport.Capabilities) == 0 {
+		return fmt.Errorf("%w: the passport grants no capabilities", ErrCapabilityNotGranted)
+	}
+	granted, err := ParseCapabilitySet(passport.Capabilities)
+	if err != nil {
+		return fmt.Errorf("%w: passport capabilities: %v", ErrCapabilityNotGranted, err)
+	}
+	hosts, err := targetHosts(intent.Target)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCapabilityNotGranted, err)
+	}
+	var scoped []Capability
+	for _, c := range granted {
+		if (c.Resource != "*" && c.Resource != want.Resource) || (c.Action != "*" && c.Action != want.Action) {
+			continue
+		}
+		if c.Constraint.Kind != ConstraintPattern {
+			return nil
+		}
+		scoped = append(scoped, c)
+	}
+	if len(scoped) == 0 {
+		return fmt.Errorf("%w: the passport grants no %s capability", ErrCapabilityNotGranted, want)
+	}
+	if len(hosts) == 0 {
+		return fmt.Errorf("%w: the passport grants %s only for some targets, and the intent names none", ErrCapabilityNotGranted, want)
+	}
+	for _, host := range hosts {
+		covered := false
+		for _, c := range scoped {
+			if c.MatchesTarget(host) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return fmt.Errorf("%w: the passport grants no %s capability for %s", ErrCapabilityNotGranted, want, host)
+		}
+	}
+	return nil
+}
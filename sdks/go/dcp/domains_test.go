@@ -0,0 +1,185 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestDomainListAdmits(t *testing.T) {
+	list := dcp.DomainList{Allow: []string{"example.com", "*.example.com"}, Deny: []string{"ads.example.com"}}
+	for domain, want := range map[string]bool{
+		"example.com":      true,
+		"API.example.com.": true,
+		"ads.example.com":  false,
+		"example.org":      false,
+	} {
+		if got := list.Admits(domain); got != want {
+			t.Errorf("Admits(%s) = %v", domain, got)
+		}
+	}
+	denyOnly := dcp.DomainList{Deny: []string{"*.evil.test"}}
+	if !denyOnly.Admits("example.org") || denyOnly.Admits("www.evil.test") {
+		t.Fatal("deny-only list")
+	}
+}
+
+func TestCheckDomains(t *testing.T) {
+	passport := &dcp.AgentPassport{AgentID: "agent001", CapabilityDomains: map[string]dcp.DomainList{
+		"email": {Allow: []string{"*.example.com"}},
+	}}
+	str := func(s string) *string { return &s }
+	for _, tc := range []struct {
+		name    string
+		action  string
+		target  dcp.IntentTarget
+		domains *dcp.DomainList
+		ok      bool
+	}{
+		{"allowed domain", "send_email", dcp.IntentTarget{Domain: str("mail.example.com")}, nil, true},
+		{"other domain", "send_email", dcp.IntentTarget{Domain: str("mail.example.org")}, nil, false},
+		{"recipient domain", "send_email", dcp.IntentTarget{To: str("bob@example.org")}, nil, false},
+		{"URL host slips past the domain", "send_email", dcp.IntentTarget{Domain: str("mail.example.com"), URL: str("https://mail.example.org/send")}, nil, false},
+		{"unlisted capability", "browse", dcp.IntentTarget{URL: str("https://example.org/")}, nil, true},
+		{"intent list", "browse", dcp.IntentTarget{URL: str("https://example.org/")}, &dcp.DomainList{Deny: []string{"example.org"}}, false},
+		{"no target domain", "send_email", dcp.IntentTarget{}, nil, true},
+	} {
+		intent := &dcp.Intent{AgentID: "agent001", ActionType: tc.action, Target: tc.target, Domains: tc.domains}
+		err := dcp.CheckDomains(passport, intent)
+		if (err == nil) != tc.ok || (err != nil && !errors.Is(err, dcp.ErrDomainRefused)) {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+	intent := &dcp.Intent{ActionType: "send_email", Target: dcp.IntentTarget{Domain: str("example.org")}}
+	if err := dcp.CheckDomains(nil, intent); err != nil {
+		t.Fatalf("without a passport: %v", err)
+	}
+	if dcp.CapabilityOf("initiate_payment") != "payments" || dcp.CapabilityOf("teleport") != "" {
+		t.Fatal("CapabilityOf")
+	}
+}
+
+func TestCheckCapability(t *testing.T) {
+	str := func(s string) *string { return &s }
+	for _, tc := range []struct {
+		name   string
+		caps   []string
+		action string
+		target dcp.IntentTarget
+		ok     bool
+	}{
+		{"legacy name", []string{"email"}, "send_email", dcp.IntentTarget{To: str("bob@example.org")}, true},
+		{"wildcard action", []string{"email:*"}, "send_email", dcp.IntentTarget{To: str("bob@example.org")}, true},
+		{"scoped to the domain", []string{"email:send:*.example.com"}, "send_email", dcp.IntentTarget{To: str("bob@mail.example.com")}, true},
+		{"scoped to another domain", []string{"email:send:*.example.com"}, "send_email", dcp.IntentTarget{To: str("bob@example.org")}, false},
+		{"scoped without a target", []string{"email:send:*.example.com"}, "send_email", dcp.IntentTarget{}, false},
+		{"other action", []string{"email:read"}, "send_email", dcp.IntentTarget{}, false},
+		{"other resource", []string{"calendar:*"}, "send_email", dcp.IntentTarget{}, false},
+		{"limit", []string{"payments:initiate:<=100EUR"}, "initiate_payment", dcp.IntentTarget{}, true},
+		{"domain in scope, recipient outside", []string{"email:send:*.example.com"}, "send_email", dcp.IntentTarget{Domain: str("a.example.com"), To: str("victim@evil.com")}, false},
+		{"domain in scope, URL outside", []string{"browse:read:*.example.com"}, "browse", dcp.IntentTarget{Domain: str("a.example.com"), URL: str("https://evil.com/")}, false},
+		{"URL outside", []string{"browse:read:*.example.com"}, "browse", dcp.IntentTarget{URL: str("https://evil.com/")}, false},
+		{"every host in scope", []string{"email:send:*.example.com"}, "send_email", dcp.IntentTarget{Domain: str("a.example.com"), To: str("bob@b.example.com")}, true},
+		{"hosts in scopes of two grants", []string{"email:send:*.example.com", "email:send:*.example.org"}, "send_email", dcp.IntentTarget{Domain: str("a.example.com"), To: str("bob@b.example.org")}, true},
+		{"unknown action", []string{"*:*"}, "teleport", dcp.IntentTarget{}, false},
+		{"no capabilities", nil, "send_email", dcp.IntentTarget{}, false},
+		{"empty capabilities", []string{}, "browse", dcp.IntentTarget{URL: str("https://example.com/")}, false},
+	} {
+		passport := &dcp.AgentPassport{AgentID: "agent001", Capabilities: tc.caps}
+		intent := &dcp.Intent{AgentID: "agent001", ActionType: tc.action, Target: tc.target}
+		err := dcp.CheckCapability(passport, intent)
+		if (err == nil) != tc.ok || (err != nil && !errors.Is(err, dcp.ErrCapabilityNotGranted)) {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+	if c, ok := dcp.IntentCapability("send_email"); !ok || c.String() != "email:send" {
+		t.Fatalf("IntentCapability = %v, %v", c, ok)
+	}
+}
+
+func TestValidateDomainLists(t *testing.T) {
+	agent, _ := dcp.GenerateKeypair()
+	f := dcp.RecordFactory{}
+	passport := f.NewAgentPassport(f.NewHumanID(), agent.PublicKeyB64, []string{"email"}, "low")
+	passport.CapabilityDomains = map[string]dcp.DomainList{
+		"email":    {Allow: []string{"*.example.com"}},
+		"teleport": {Deny: []string{"example.org"}},
+	}
+	intent := f.NewIntent(f.NewAgentID(), f.NewHumanID(), "api_call", dcp.IntentTarget{Channel: "api"}, []string{"none"}, "low")
+	intent.Domains = &dcp.DomainList{Allow: []string{"example.com", "https://example.com/"}}
+	var got []string
+	for _, err := range []error{passport.Validate(), intent.Validate()} {
+		var verrs dcp.ValidationErrors
+		if !errors.As(err, &verrs) {
+			t.Fatalf("err = %v", err)
+		}
+		for _, fe := range verrs {
+			got = append(got, fe.Pointer)
+		}
+	}
+	if want := "/capability_domains/teleport /domains/allow/1"; strings.Join(got, " ") != want {
+		t.Fatalf("pointers = %v, want %s", got, want)
+	}
+	// Clones do not share lists.
+	c := passport.Clone()
+	c.CapabilityDomains["email"].Allow[0] = "example.org"
+	if passport.CapabilityDomains["email"].Allow[0] != "*.example.com" {
+		t.Fatal("passport clone shares domain lists")
+	}
+	ic := intent.Clone()
+	ic.Domains.Allow[0] = "example.org"
+	if intent.Domains.Allow[0] != "example.com" {
+		t.Fatal("intent clone shares its domain list")
+	}
+}
+
+func TestVerifyCheckDomains(t *testing.T) {
+	owner, _ := dcp.GenerateKeypair()
+	domain := "pay.example.org"
+	bundle := dcp.CitizenshipBundle{
+		AgentPassport: dcp.AgentPassport{DCPVersion: "1.0", AgentID: "agent001", CapabilityDomains: map[string]dcp.DomainList{
+			"payments": {Allow: []string{"*.example.com"}},
+		}},
+		Intent: dcp.Intent{DCPVersion: "1.0", IntentID: "intent001", AgentID: "agent001", ActionType: "initiate_payment",
+			Target: dcp.IntentTarget{Channel: "payments", Domain: &domain}, DataClasses: []string{"financial_data"}},
+		PolicyDecision: dcp.PolicyDecision{Reasons: []string{}},
+		AuditEntries:   []dcp.AuditEntry{},
+	}
+	sig, err := dcp.SignObject(bundle, owner.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb := &dcp.SignedBundle{Bundle: bundle, Signature: dcp.BundleSignature{Alg: "ed25519", SigB64: sig,
+		SignerInfo: dcp.Signer{Type: "human", ID: "human001", PublicKeyB64: owner.PublicKeyB64}}}
+
+	if res := dcp.VerifySignedBundleWithOptions(sb, dcp.VerifyOptions{}); !res.Verified {
+		t.Fatalf("without CheckDomains: %v", res.Errors)
+	}
+	res := dcp.VerifySignedBundleWithOptions(sb, dcp.VerifyOptions{CheckDomains: true, Explain: true})
+	if res.Verified || !strings.Contains(res.Errors[0], "pay.example.org") {
+		t.Fatalf("with CheckDomains: %+v", res)
+	}
+	if last := res.Trace[len(res.Trace)-1]; last.Check != dcp.TraceDomains || last.OK || last.Actual != domain {
+		t.Fatalf("trace: %+v", last)
+	}
+
+	// The strict parser knows the domain list members, and the raw path
+	// checks them too.
+	data, _ := json.Marshal(sb)
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundleWithOptions(rsb, dcp.VerifyOptions{CheckDomains: true}); res.Verified {
+		t.Fatal("raw bundle verified")
+	}
+	bad := strings.Replace(string(data), `"allow":`, `"allowed":["x"],"allow":`, 1)
+	var perrs dcp.ParseErrors
+	if _, err := dcp.ParseSignedBundleStrict([]byte(bad)); !errors.As(err, &perrs) ||
+		perrs[0].Pointer != "/bundle/agent_passport/capability_domains/payments/allowed" {
+		t.Fatalf("unknown member: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package dcp
+
+// validateEntity checks r's entity_evidence against what its entity type
+// requires. natural_person and organization require none.
+func (r *ResponsiblePrincipalRecord) validateEntity(v validator) {
+	e := r.EntityEvidence
+	ev := v.at("entity_evidence")
+	switch r.EntityType {
+	case EntityIndividual, EntityCorporation, EntityNonprofit:
+		if e == nil {
+			ev.fail("is required for entity type %s", r.EntityType)
+			return
+		}
+	}
+	if e == nil {
+		return
+	}
+	if e.DateOfBirthHash != "" {
+		ev.sha256Ref("date_of_birth_hash", e.DateOfBirthHash)
+	}
+	if e.RegistrationJurisdiction != "" && e.RegistrationJurisdiction != r.Jurisdiction {
+		ev.at("registration_jurisdiction").fail("must match jurisdiction %s", r.Jurisdiction)
+	}
+	switch r.EntityType {
+	case EntityIndividual:
+		if e.DateOfBirthHash == "" && e.IDReference == "" {
+			ev.fail("must have date_of_birth_hash or id_reference for an individual")
+		}
+	case EntityCorporation:
+		ev.minLen("registration_number", e.RegistrationNumber, 1)
+		ev.minLen("registration_jurisdiction", e.RegistrationJurisdiction, 2)
+	case EntityNonprofit:
+		ev.minLen("registry_ref", e.RegistryRef, 1)
+	}
+}
+
+// Clone returns a copy of e.
+func (e *EntityEvidence) Clone() *EntityEvidence {
+	if e == nil {
+		return nil
+	}
+	c := *e
+	return &c
+}
+
+// Equal reports whether e and o canonicalize identically.
+func (e *EntityEvidence) Equal(o *EntityEvidence) bool {
+	return (e == nil) == (o == nil) && (e == nil || canonicalEqual(e, o))
+}
@@ -0,0 +1,69 @@
+package dcp_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestEntityEvidence(t *testing.T) {
+	dob := "sha256:" + strings.Repeat("ab", 32)
+	for _, tc := range []struct {
+		name     string
+		entity   dcp.EntityType
+		evidence *dcp.EntityEvidence
+		want     []string
+	}{
+		{"natural person", dcp.EntityNaturalPerson, nil, nil},
+		{"organization", dcp.EntityOrganization, nil, nil},
+		{"individual by birth date", dcp.EntityIndividual, &dcp.EntityEvidence{DateOfBirthHash: dob}, nil},
+		{"individual by ID", dcp.EntityIndividual, &dcp.EntityEvidence{IDReference: "passport:X1234567"}, nil},
+		{"corporation", dcp.EntityCorporation, &dcp.EntityEvidence{RegistrationNumber: "HRB 12345", RegistrationJurisdiction: "DE"}, nil},
+		{"nonprofit", dcp.EntityNonprofit, &dcp.EntityEvidence{RegistryRef: "VR 2041"}, nil},
+
+		{"individual without evidence", dcp.EntityIndividual, nil, []string{"/entity_evidence"}},
+		{"individual without identifiers", dcp.EntityIndividual, &dcp.EntityEvidence{RegistryRef: "VR 2041"}, []string{"/entity_evidence"}},
+		{"unhashed birth date", dcp.EntityIndividual, &dcp.EntityEvidence{DateOfBirthHash: "1990-01-01"}, []string{"/entity_evidence/date_of_birth_hash"}},
+		{"unregistered corporation", dcp.EntityCorporation, &dcp.EntityEvidence{}, []string{"/entity_evidence/registration_number", "/entity_evidence/registration_jurisdiction"}},
+		{"corporation registered elsewhere", dcp.EntityCorporation, &dcp.EntityEvidence{RegistrationNumber: "C1234567", RegistrationJurisdiction: "US"}, []string{"/entity_evidence/registration_jurisdiction"}},
+		{"nonprofit without registry", dcp.EntityNonprofit, nil, []string{"/entity_evidence"}},
+		{"nonprofit registered as a corporation", dcp.EntityNonprofit, &dcp.EntityEvidence{RegistrationNumber: "HRB 12345", RegistrationJurisdiction: "DE"}, []string{"/entity_evidence/registry_ref"}},
+	} {
+		rec := dcp.NewResponsiblePrincipalRecord("Example", tc.entity, "DE")
+		rec.EntityEvidence = tc.evidence
+		err := rec.Validate()
+		var verrs dcp.ValidationErrors
+		if tc.want == nil {
+			if err != nil {
+				t.Errorf("%s: %v", tc.name, err)
+			}
+			continue
+		}
+		if !errors.As(err, &verrs) || len(verrs) != len(tc.want) {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		for i, p := range tc.want {
+			if verrs[i].Pointer != p {
+				t.Errorf("%s: error %d at %s, want %s", tc.name, i, verrs[i].Pointer, p)
+			}
+		}
+	}
+}
+
+func TestEntityEvidenceRedacted(t *testing.T) {
+	rec := dcp.NewResponsiblePrincipalRecord("Alice", dcp.EntityIndividual, "DE")
+	rec.EntityEvidence = &dcp.EntityEvidence{IDReference: "passport:X1234567", RegistrationJurisdiction: "DE"}
+	r := rec.Redacted()
+	if r.EntityEvidence.IDReference != dcp.RedactedValue || r.EntityEvidence.RegistrationJurisdiction != "DE" {
+		t.Fatalf("redacted evidence = %+v", r.EntityEvidence)
+	}
+	if rec.EntityEvidence.IDReference != "passport:X1234567" {
+		t.Fatal("Redacted changed the original")
+	}
+	if !dcp.EntityNonprofit.IsOrganization() || dcp.EntityIndividual.IsOrganization() {
+		t.Fatal("IsOrganization")
+	}
+}
@@ -0,0 +1,242 @@
+package dcp
+
+// The enumerated members of the DCP-01/02/03 records have their own string
+// types. They marshal as plain JSON strings, so records hash and canonicalize
+// exactly as before, and a value outside the schema still round-trips
+// unchanged; IsValid reports whether it is one the schema allows.
+
+// EntityType is a responsible principal's entity_type.
+type EntityType string
+
+const (
+	EntityNaturalPerson EntityType = "natural_person"
+	EntityOrganization  EntityType = "organization"
+	// EntityIndividual is a natural person identified by a date-of-birth
+	// hash or an ID document reference in entity_evidence.
+	EntityIndividual EntityType = "individual"
+	// EntityCorporation is an organization identified by its registration
+	// number in the principal's jurisdiction.
+	EntityCorporation EntityType = "corporation"
+	// EntityNonprofit is an organization identified by its reference in a
+	// charity or nonprofit registry.
+	EntityNonprofit EntityType = "nonprofit"
+)
+
+// LiabilityMode is a responsible principal's liability_mode.
+type LiabilityMode string
+
+const (
+	// LiabilityOwnerResponsible puts liability on the principal alone and
+	// carries no obligations.
+	LiabilityOwnerResponsible LiabilityMode = "owner_responsible"
+	// LiabilityShared shares liability with the PDP operator, so the
+	// policy decision must be signed by a trusted PDP.
+	LiabilityShared LiabilityMode = "shared"
+	// LiabilityStrict additionally requires the principal's consent to every
+	// intent and the agent's signature on every audit entry.
+	LiabilityStrict LiabilityMode = "strict"
+)
+
+// LiabilityObligation is something a bundle must carry under its
+// principal's liability mode; see LiabilityMode.Obligations.
+type LiabilityObligation string
+
+const (
+	// ObligationConsent requires a consent record signed by the principal,
+	// whether or not the intent sets requires_consent.
+	ObligationConsent LiabilityObligation = "consent"
+	// ObligationSignedDecision requires the policy decision to be signed by
+	// a trusted PDP, whatever the agent's risk tier.
+	ObligationSignedDecision LiabilityObligation = "signed_decision"
+	// ObligationAgentSignatures requires an agent_signature on every audit
+	// entry.
+	ObligationAgentSignatures LiabilityObligation = "agent_signatures"
+)
+
+// RiskTier is an agent passport's risk_tier.
+type RiskTier string
+
+const (
+	RiskTierLow    RiskTier = "low"
+	RiskTierMedium RiskTier = "medium"
+	RiskTierHigh   RiskTier = "high"
+)
+
+// Status is an agent passport's status.
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusRevoked   Status = "revoked"
+	StatusSuspended Status = "suspended"
+)
+
+// Channel is an intent target's channel.
+type Channel string
+
+const (
+	ChannelWeb        Channel = "web"
+	ChannelAPI        Channel = "api"
+	ChannelEmail      Channel = "email"
+	ChannelCalendar   Channel = "calendar"
+	ChannelPayments   Channel = "payments"
+	ChannelCRM        Channel = "crm"
+	ChannelFilesystem Channel = "filesystem"
+	ChannelRuntime    Channel = "runtime"
+)
+
+// EstimatedImpact is an intent's estimated_impact.
+type EstimatedImpact string
+
+const (
+	ImpactLow    EstimatedImpact = "low"
+	ImpactMedium EstimatedImpact = "medium"
+	ImpactHigh   EstimatedImpact = "high"
+)
+
+// Decision is a policy decision's decision.
+type Decision string
+
+const (
+	DecisionApprove  Decision = "approve"
+	DecisionEscalate Decision = "escalate"
+	DecisionBlock    Decision = "block"
+)
+
+// Outcome is the policy outcome an audit entry records in policy_decision.
+type Outcome string
+
+const (
+	OutcomeApproved  Outcome = "approved"
+	OutcomeEscalated Outcome = "escalated"
+	OutcomeBlocked   Outcome = "blocked"
+)
+
+// OverrideAction is what a human override does to the agent's action.
+type OverrideAction string
+
+const (
+	OverrideHalt   OverrideAction = "halt"
+	OverrideModify OverrideAction = "modify"
+)
+
+// GuardianRelationship is a guardian's relationship to the dependent
+// principal it is bound to.
+type GuardianRelationship string
+
+const (
+	GuardianParent        GuardianRelationship = "parent"
+	GuardianLegalGuardian GuardianRelationship = "legal_guardian"
+	GuardianCustodian     GuardianRelationship = "custodian"
+)
+
+// AttorneyScope is a right a power of attorney grants the attorney.
+type AttorneyScope string
+
+const (
+	// AttorneyBind lets the attorney sign bundles binding agents to the
+	// grantor.
+	AttorneyBind AttorneyScope = "bind_agents"
+	// AttorneyRevoke lets the attorney revoke the grantor's agents.
+	AttorneyRevoke AttorneyScope = "revoke_agents"
+	// AttorneyDelegate lets the attorney pass its rights on in a further
+	// power of attorney.
+	AttorneyDelegate AttorneyScope = "delegate"
+)
+
+// AttesterType is who signs an insurance attestation.
+type AttesterType string
+
+const (
+	AttesterInsurer  AttesterType = "insurer"
+	AttesterPlatform AttesterType = "platform"
+)
+
+// CoverageClass is the class of cover an insurance attestation asserts.
+type CoverageClass string
+
+const (
+	CoverageGeneral      CoverageClass = "general_liability"
+	CoverageProfessional CoverageClass = "professional_liability"
+	CoverageCyber        CoverageClass = "cyber_liability"
+	CoverageProduct      CoverageClass = "product_liability"
+)
+
+var (
+	entityTypes = []string{
+		string(EntityNaturalPerson), string(EntityOrganization),
+		string(EntityIndividual), string(EntityCorporation), string(EntityNonprofit),
+	}
+	liabilityModes = []string{string(LiabilityOwnerResponsible), string(LiabilityShared), string(LiabilityStrict)}
+	riskTiers      = []string{string(RiskTierLow), string(RiskTierMedium), string(RiskTierHigh)}
+	statuses       = []string{string(StatusActive), string(StatusRevoked), string(StatusSuspended)}
+	channels       = []string{
+		string(ChannelWeb), string(ChannelAPI), string(ChannelEmail), string(ChannelCalendar),
+		string(ChannelPayments), string(ChannelCRM), string(ChannelFilesystem), string(ChannelRuntime),
+	}
+	impacts   = []string{string(ImpactLow), string(ImpactMedium), string(ImpactHigh)}
+	decisions = []string{string(DecisionApprove), string(DecisionEscalate), string(DecisionBlock)}
+	outcomes  = []string{string(OutcomeApproved), string(OutcomeEscalated), string(OutcomeBlocked)}
+	overrides = []string{string(OverrideHalt), string(OverrideModify)}
+
+	guardianRelationships = []string{string(GuardianParent), string(GuardianLegalGuardian), string(GuardianCustodian)}
+	attorneyScopes        = []string{string(AttorneyBind), string(AttorneyRevoke), string(AttorneyDelegate)}
+	attesterTypes         = []string{string(AttesterInsurer), string(AttesterPlatform)}
+	coverageClasses       = []string{
+		string(CoverageGeneral), string(CoverageProfessional), string(CoverageCyber), string(CoverageProduct),
+	}
+)
+
+func oneOf(s string, allowed []string) bool {
+	for _, a := range allowed {
+		if s == a {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid reports whether e is an entity type the schema allows.
+func (e EntityType) IsValid() bool { return oneOf(string(e), entityTypes) }
+
+// IsOrganization reports whether e is an organization, corporation or
+// nonprofit, rather than a natural person or individual.
+func (e EntityType) IsOrganization() bool {
+	return e == EntityOrganization || e == EntityCorporation || e == EntityNonprofit
+}
+
+// IsValid reports whether m is a liability mode the schema allows.
+func (m LiabilityMode) IsValid() bool { return oneOf(string(m), liabilityModes) }
+
+// IsValid reports whether t is a risk tier the schema allows.
+func (t RiskTier) IsValid() bool { return oneOf(string(t), riskTiers) }
+
+// IsValid reports whether s is a passport status the schema allows.
+func (s Status) IsValid() bool { return oneOf(string(s), statuses) }
+
+// IsValid reports whether c is a channel the schema allows.
+func (c Channel) IsValid() bool { return oneOf(string(c), channels) }
+
+// IsValid reports whether i is an impact level the schema allows.
+func (i EstimatedImpact) IsValid() bool { return oneOf(string(i), impacts) }
+
+// IsValid reports whether d is a policy decision the schema allows.
+func (d Decision) IsValid() bool { return oneOf(string(d), decisions) }
+
+// IsValid reports whether o is an audit policy outcome the schema allows.
+func (o Outcome) IsValid() bool { return oneOf(string(o), outcomes) }
+
+// IsValid reports whether a is an override action the schema allows.
+func (a OverrideAction) IsValid() bool { return oneOf(string(a), overrides) }
+
+// IsValid reports whether r is a guardian relationship the schema allows.
+func (r GuardianRelationship) IsValid() bool { return oneOf(string(r), guardianRelationships) }
+
+// IsValid reports whether s is an attorney scope the schema allows.
+func (s AttorneyScope) IsValid() bool { return oneOf(string(s), attorneyScopes) }
+
+// IsValid reports whether a is an attester type the schema allows.
+func (a AttesterType) IsValid() bool { return oneOf(string(a), attesterTypes) }
+
+// IsValid reports whether c is a coverage class the schema allows.
+func (c CoverageClass) IsValid() bool { return oneOf(string(c), coverageClasses) }
@@ -0,0 +1,59 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestEnumIsValid(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		valid bool
+	}{
+		{"natural_person", dcp.EntityType("natural_person").IsValid()},
+		{"owner_responsible", dcp.LiabilityOwnerResponsible.IsValid()},
+		{"high", dcp.RiskTierHigh.IsValid()},
+		{"suspended", dcp.StatusSuspended.IsValid()},
+		{"filesystem", dcp.ChannelFilesystem.IsValid()},
+		{"medium", dcp.ImpactMedium.IsValid()},
+		{"escalate", dcp.DecisionEscalate.IsValid()},
+		{"blocked", dcp.OutcomeBlocked.IsValid()},
+		{"halt", dcp.OverrideHalt.IsValid()},
+		{"legal_guardian", dcp.GuardianLegalGuardian.IsValid()},
+		{"revoke_agents", dcp.AttorneyRevoke.IsValid()},
+		{"strict", dcp.LiabilityStrict.IsValid()},
+		{"cyber_liability", dcp.CoverageCyber.IsValid()},
+		{"platform", dcp.AttesterPlatform.IsValid()},
+	} {
+		if !c.valid {
+			t.Errorf("%s should be valid", c.name)
+		}
+	}
+	if dcp.Decision("approved").IsValid() || dcp.Outcome("approve").IsValid() || dcp.Channel("").IsValid() {
+		t.Fatal("values from other enums or empty values should not be valid")
+	}
+}
+
+func TestEnumWireFormatUnchanged(t *testing.T) {
+	raw := []byte(`{"dcp_version":"1.0","intent_id":"intent001","decision":"escalate","risk_score":0.5,"reasons":["r"],"x":1}`)
+	var d dcp.PolicyDecision
+	if err := json.Unmarshal(raw, &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Decision != dcp.DecisionEscalate {
+		t.Fatalf("decision = %q", d.Decision)
+	}
+	canon, _ := dcp.Canonicalize(d)
+	if canon != `{"dcp_version":"1.0","decision":"escalate","intent_id":"intent001","reasons":["r"],"risk_score":0.5}` {
+		t.Fatalf("canonical form = %s", canon)
+	}
+
+	// Values outside the schema are kept, so received records still hash
+	// as they were signed.
+	var p dcp.AgentPassport
+	if err := json.Unmarshal([]byte(`{"status":"retired"}`), &p); err != nil || p.Status != "retired" || p.Status.IsValid() {
+		t.Fatalf("status = %q, err = %v", p.Status, err)
+	}
+}
@@ -0,0 +1,53 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrIntentExpired is returned for an action on an intent after its
+// expires_at.
+var ErrIntentExpired = errors.New("intent expired")
+
+// SetTTL sets ExpiresAt to ttl after the intent's timestamp.
+func (i *Intent) SetTTL(ttl time.Duration) error {
+	at, err := i.TimestampTime()
+	if err != nil {
+		return fmt.Errorf("intent %s: %w", i.IntentID, err)
+	}
+	expires := FormatTime(at.Add(ttl))
+	i.ExpiresAt = &expires
+	return nil
+}
+
+// Expired reports whether i has expired at t. An intent without an expiry
+// never does.
+func (i *Intent) Expired(t time.Time) (bool, error) {
+	until, err := i.ExpiresAtTime()
+	if err != nil || until.IsZero() {
+		return false, err
+	}
+	return t.After(until), nil
+}
+
+// checkEntry checks that an audit entry with timestamp and outcome may be
+// recorded on i: before it expired, or with an outcome in
+// CancellationOutcomes, which does not record the action as done.
+func (i *Intent) checkEntry(timestamp, outcome string) error {
+	if i.ExpiresAt == nil || oneOf(outcome, CancellationOutcomes) {
+		return nil
+	}
+	at, err := ParseTime(timestamp)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrIntentExpired, err)
+	}
+	expired, err := i.Expired(at)
+	if err != nil {
+		return fmt.Errorf("%w: expires_at: %v", ErrIntentExpired, err)
+	}
+	if expired {
+		return fmt.Errorf("%w: %q recorded at %s, after %s expired at %s", ErrIntentExpired, outcome, timestamp, i.IntentID, *i.ExpiresAt)
+	}
+	return nil
+}
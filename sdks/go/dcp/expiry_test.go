@@ -0,0 +1,94 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestIntentExpiry(t *testing.T) {
+	intent := dcp.Intent{DCPVersion: "1.0", IntentID: "intent001", AgentID: "agent001", HumanID: "human001",
+		Timestamp: "2026-01-01T01:00:00Z", ActionType: "send_email", Target: dcp.IntentTarget{Channel: "email"},
+		DataClasses: []string{"contact_info"}, EstimatedImpact: "medium"}
+	if expired, err := intent.Expired(time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)); expired || err != nil {
+		t.Fatalf("without an expiry: %v, %v", expired, err)
+	}
+	if err := intent.SetTTL(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if *intent.ExpiresAt != "2026-01-01T01:01:00Z" {
+		t.Fatalf("expires_at = %s", *intent.ExpiresAt)
+	}
+	if at, err := intent.ExpiresAtTime(); err != nil || at.Minute() != 1 {
+		t.Fatalf("ExpiresAtTime = %v, %v", at, err)
+	}
+
+	now := time.Date(2026, 1, 1, 1, 0, 30, 0, time.UTC)
+	chain := dcp.NewAuditChain(dcp.AuditChainOptions{Clock: func() time.Time { return now }})
+	if _, err := chain.Append(dcp.AuditEntryFields{Intent: intent, PolicyDecision: dcp.OutcomeApproved, Outcome: "email_queued"}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := chain.Append(dcp.AuditEntryFields{Intent: intent, PolicyDecision: dcp.OutcomeApproved, Outcome: "email_sent"}); !errors.Is(err, dcp.ErrIntentExpired) {
+		t.Fatalf("after expiry: %v", err)
+	}
+	if _, err := chain.Append(dcp.AuditEntryFields{Intent: intent, PolicyDecision: dcp.OutcomeApproved, Outcome: "aborted"}); err != nil {
+		t.Fatalf("aborted after expiry: %v", err)
+	}
+
+	bad := intent
+	notTime := "tomorrow"
+	bad.ExpiresAt = &notTime
+	var verrs dcp.ValidationErrors
+	if err := bad.Validate(); !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Pointer != "/expires_at" {
+		t.Fatalf("invalid expires_at: %v", err)
+	}
+	c := intent.Clone()
+	*c.ExpiresAt = "2026-01-01T02:00:00Z"
+	if *intent.ExpiresAt != "2026-01-01T01:01:00Z" {
+		t.Fatal("clone shares expires_at")
+	}
+}
+
+func TestVerifyIntentExpiry(t *testing.T) {
+	b, human, _ := builderFixture(t)
+	bundle, err := b.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	build := func(ttl time.Duration) *dcp.SignedBundle {
+		t.Helper()
+		intent := bundle.Intent
+		if err := intent.SetTTL(ttl); err != nil {
+			t.Fatal(err)
+		}
+		sb, err := b.Intent(intent).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+	res := dcp.VerifySignedBundleWithOptions(build(time.Hour), dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, Explain: true})
+	if !res.Verified {
+		t.Fatalf("entries before expiry: %v", res.Errors)
+	}
+	if last := res.Trace[len(res.Trace)-1]; last.Check != dcp.TraceExpiry || !last.OK {
+		t.Fatalf("trace: %+v", last)
+	}
+	expired := build(30 * time.Second)
+	if res := dcp.VerifySignedBundle(expired, human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "audit entry 0") {
+		t.Fatalf("entries after expiry: %+v", res)
+	}
+	data, _ := json.Marshal(expired)
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundle(rsb, human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "expired at 2026-01-01T01:00:30Z") {
+		t.Fatalf("raw bundle: %+v", res)
+	}
+}
@@ -0,0 +1,146 @@
+package dcp
+
+import "fmt"
+
+// Checks named in TraceSteps.
+const (
+	TraceCanonicalize   = "canonicalize"
+	TraceKey            = "key"
+	TraceSignature      = "signature"
+	TraceBundleHash     = "bundle_hash"
+	TraceMerkleRoot     = "merkle_root"
+	TraceIntentHash     = "intent_hash"
+	TracePrevHash       = "prev_hash"
+	TraceAgentSignature = "agent_signature"
+	TraceDomains        = "domains"
+	TraceConsent        = "consent"
+	TraceOverride       = "override"
+	TraceBreakGlass     = "break_glass"
+	TraceCancellation   = "cancellation"
+	TraceAmendments     = "amendments"
+	TraceExpiry         = "expiry"
+	TraceBatch          = "batch"
+	TraceDecision       = "decision"
+	TraceDelegation     = "delegation"
+	TraceQuorum         = "quorum"
+	TraceGuardian       = "guardian"
+	TraceAttorney       = "power_of_attorney"
+	TraceLiability      = "liability"
+	TraceInsurance      = "insurance"
+)
+
+// TraceStep is one step of an explained verification. Target names what was
+// checked, e.g. "bundle" or "audit_entries/1"; Expected and Actual hold the
+// compared values for hash checks.
+type TraceStep struct {
+	Check    string `json:"check"`
+	Target   string `json:"target,omitempty"`
+	OK       bool   `json:"ok"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// VerifyOptions configures VerifySignedBundleWithOptions and
+// VerifyRawSignedBundleWithOptions.
+type VerifyOptions struct {
+	// PublicKeyB64 pins the bundle signer's key; empty means the key
+	// embedded in signature.signer.
+	PublicKeyB64 string
+	// Explain records every step in VerificationResult.Trace, including
+	// the digest of each canonical form, so a failure can be diagnosed from
+	// the result alone. Verification still stops at the first failure.
+	Explain bool
+	// CheckDomains also checks the intent's target domain and URL against
+	// the intent's domain list and the passport's for the capability the
+	// intent exercises; see CheckDomains.
+	CheckDomains bool
+	// Countersigners are the keys allowed to countersign break-glass
+	// records. A bundle with break-glass records fails verification
+	// without them, as a countersignature by any key proves nothing.
+	Countersigners []string
+	// SignerSets are the signer sets of group principals, by human_id, as
+	// published outside their bundles, such as in the principals' registry
+	// records. A bundle's quorum is checked against the set of its
+	// principal, which the bundle must declare unchanged; a bundle that
+	// declares a signer set none is given for fails. See
+	// CheckPrincipalQuorum.
+	SignerSets map[string]*PrincipalSignerSet
+	// Guardians are the guardians' keys of dependent principals, by the
+	// principal's human_id, as published outside their bundles. A
+	// dependent's passports are checked against the key of its principal,
+	// which the bundle's guardian binding must name; a bundle that declares
+	// a guardian none is given for fails. See CheckGuardian.
+	Guardians map[string]string
+	// PDPKeys are the keys of the PDPs trusted to sign policy decisions.
+	// A signed decision is checked against them, and the decision of an
+	// agent with a high risk tier must be signed by one.
+	PDPKeys []string
+	// EnforceLiability also checks the bundle against the obligations of
+	// its principal's liability mode, failing one with an unknown mode;
+	// see LiabilityMode.Obligations. A signed decision is then checked
+	// against PDPKeys.
+	EnforceLiability bool
+	// InsurerKeys are the published keys of the insurers and platforms
+	// trusted to attest coverage. Each insurance attestation in a bundle
+	// is checked against them; with none, attestations are skipped.
+	InsurerKeys []string
+}
+
+// VerifySignedBundleWithOptions is VerifySignedBundle with options.
+func VerifySignedBundleWithOptions(sb *SignedBundle, opts VerifyOptions) *VerificationResult {
+	if sb == nil {
+		return &VerificationResult{Verified: false, Errors: []string{"nil signed bundle"}}
+	}
+	view, err := viewFromBundle(&sb.Bundle)
+	if err != nil {
+		return &VerificationResult{Verified: false, Errors: []string{err.Error()}}
+	}
+	return verifyBundleView(view, &sb.Signature, opts)
+}
+
+// VerifyRawSignedBundleWithOptions is VerifyRawSignedBundle with options.
+func VerifyRawSignedBundleWithOptions(rsb *RawSignedBundle, opts VerifyOptions) *VerificationResult {
+	if rsb == nil {
+		return &VerificationResult{Verified: false, Errors: []string{"nil signed bundle"}}
+	}
+	view, err := viewFromRaw(rsb)
+	if err != nil {
+		return &VerificationResult{Verified: false, Errors: []string{err.Error()}}
+	}
+	return verifyBundleView(view, &rsb.Signature, opts)
+}
+
+// tracer collects trace steps when enabled and ignores them otherwise.
+type tracer struct {
+	enabled bool
+	steps   []TraceStep
+	// flags are reported whether or not tracing is enabled.
+	flags []string
+}
+
+func (t *tracer) add(step TraceStep) {
+	if t.enabled {
+		t.steps = append(t.steps, step)
+	}
+}
+
+// canonical records the size and digest of a canonical form.
+func (t *tracer) canonical(target, canon string) {
+	if t.enabled {
+		t.add(TraceStep{Check: TraceCanonicalize, Target: target, OK: true, Actual: sha256HexString(canon),
+			Detail: fmt.Sprintf("%d bytes of canonical JSON", len(canon))})
+	}
+}
+
+// compare records a hash comparison and returns whether it matched.
+func (t *tracer) compare(check, target, expected, actual string) bool {
+	ok := expected == actual
+	t.add(TraceStep{Check: check, Target: target, OK: ok, Expected: expected, Actual: actual})
+	return ok
+}
+
+// fail returns a failed result carrying the trace so far.
+func (t *tracer) fail(msg string) *VerificationResult {
+	return &VerificationResult{Verified: false, Errors: []string{msg}, Trace: t.steps, Flags: t.flags}
+}
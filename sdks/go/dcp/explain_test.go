@@ -0,0 +1,56 @@
+package dcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyExplain(t *testing.T) {
+	sb := loadSignedBundle(t)
+	if r := VerifySignedBundle(sb, ""); !r.Verified || r.Trace != nil {
+		t.Fatalf("without Explain: %+v", r)
+	}
+	r := VerifySignedBundleWithOptions(sb, VerifyOptions{Explain: true})
+	if !r.Verified {
+		t.Fatal(r.Errors)
+	}
+	var checks []string
+	for _, s := range r.Trace {
+		if !s.OK {
+			t.Errorf("step %+v failed", s)
+		}
+		checks = append(checks, s.Check+" "+s.Target)
+	}
+	want := "canonicalize bundle,key bundle,signature bundle,bundle_hash bundle,merkle_root audit_entries," +
+		"canonicalize intent,intent_hash audit_entries/0,prev_hash audit_entries/0,intent_hash audit_entries/1," +
+		"prev_hash audit_entries/1,agent_signature audit_entries/0,agent_signature audit_entries/1"
+	if got := strings.Join(checks, ","); got != want {
+		t.Fatalf("trace = %s", got)
+	}
+}
+
+func TestVerifyExplainFailure(t *testing.T) {
+	sb := loadSignedBundle(t)
+	sb.Bundle.AuditEntries[0].PrevHash = "GENESIS0"
+	kp, err := GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resigned, err := SignBundle(&sb.Bundle, signer, Signer{}, clockNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := VerifySignedBundleWithOptions(resigned, VerifyOptions{PublicKeyB64: kp.PublicKeyB64, Explain: true})
+	last := r.Trace[len(r.Trace)-1]
+	if r.Verified || last.OK || last.Check != TracePrevHash || last.Target != "audit_entries/0" ||
+		last.Expected != "GENESIS" || last.Actual != "GENESIS0" {
+		t.Fatalf("result = %+v", r)
+	}
+	if r.Trace[1].Check != TraceKey || r.Trace[1].Detail != "pinned" {
+		t.Fatalf("key step = %+v", r.Trace[1])
+	}
+}
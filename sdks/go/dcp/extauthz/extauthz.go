@@ -0,0 +1,296 @@
+// Package extauthz is an Envoy external authorization (ext_authz) gRPC
+// service enforcing DCP at the proxy, so platform teams can admit only DCP
+// agents without touching application code.
+//
+// For every request, Envoy sends the request's attributes to Check, which
+// checks its DCP headers like the agentauth and dcpheader middleware: the
+// DCP-Agent-Passport header, or, with a Decoder, the compact DCP-Agent and
+// DCP-Intent headers. Admitted requests reach the upstream with the agent's
+// identity in the x-dcp-* headers, overwriting any the client sent; the
+// others are answered by Envoy with the status and {"error": ...} body
+// agentauth's middleware would give.
+//
+// Envoy forwards the client certificate with include_peer_certificate, and
+// the passport is then bound to it as on a direct mTLS connection:
+//
+//	http_filters:
+//	- name: envoy.filters.http.ext_authz
+//	  typed_config:
+//	    "@type": type.googleapis.com/envoy.extensions.filters.http.ext_authz.v3.ExtAuthz
+//	    transport_api_version: V3
+//	    include_peer_certificate: true
+//	    grpc_service:
+//	      envoy_grpc: {cluster_name: dcp_ext_authz}
+package extauthz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcpheader"
+)
+
+// Headers set on admitted requests.
+const (
+	AgentIDHeader          = "x-dcp-agent-id"
+	HumanIDHeader          = "x-dcp-human-id"
+	RiskTierHeader         = "x-dcp-risk-tier"
+	CapabilitiesHeader     = "x-dcp-capabilities"
+	CertificateBoundHeader = "x-dcp-certificate-bound"
+	// IntentIDHeader is removed from requests declaring no intent.
+	IntentIDHeader = "x-dcp-intent-id"
+)
+
+// MetadataNamespace is the namespace other Envoy filters read the admitted
+// agent from in the dynamic metadata, as agent_id, human_id, risk_tier and
+// intent_id.
+const MetadataNamespace = "envoy.filters.http.ext_authz"
+
+// Config configures a Server.
+type Config struct {
+	// Authenticator checks passports presented in the DCP-Agent-Passport
+	// header. Required.
+	Authenticator *agentauth.Authenticator
+	// Decoder, if set, checks requests presenting the compact DCP-Agent
+	// header instead.
+	Decoder *dcpheader.Decoder
+	// StripCredentials removes the DCP-Agent-Passport, DCP-Agent and
+	// DCP-Intent headers from admitted requests, leaving the upstream only
+	// the x-dcp-* identity headers.
+	StripCredentials bool
+}
+
+// Server implements the Envoy envoy.service.auth.v3.Authorization service.
+// Create one with New.
+type Server struct {
+	authv3.UnimplementedAuthorizationServer
+	cfg Config
+}
+
+var _ authv3.AuthorizationServer = (*Server)(nil)
+
+// New returns a Server for cfg.
+func New(cfg Config) (*Server, error) {
+	if cfg.Authenticator == nil {
+		return nil, errors.New("extauthz: an authenticator is required")
+	}
+	return &Server{cfg: cfg}, nil
+}
+
+// Register registers s on g.
+func (s *Server) Register(g grpc.ServiceRegistrar) {
+	authv3.RegisterAuthorizationServer(g, s)
+}
+
+// Check implements authv3.AuthorizationServer. Denials are answers, not
+// errors: UNAUTHENTICATED with HTTP 401 for a missing or invalid passport
+// or intent, PERMISSION_DENIED with 403 for an inactive passport, and
+// UNAVAILABLE with 503 when the registry or a revocation source could not
+// be consulted.
+func (s *Server) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	r, err := Request(ctx, req)
+	if err != nil {
+		return deny(codes.InvalidArgument, typev3.StatusCode_BadRequest, err.Error(), ""), nil
+	}
+	challenge := `DCP header="` + agentauth.PassportHeader + `"`
+	var (
+		id       *agentauth.Identity
+		intentID string
+	)
+	if s.cfg.Decoder != nil && r.Header.Get(dcpheader.AgentHeader) != "" {
+		challenge = `DCP header="` + dcpheader.AgentHeader + `"`
+		var declared *dcpheader.Declared
+		if declared, err = s.cfg.Decoder.Decode(r); err == nil {
+			id = declared.Identity
+			if declared.Intent != nil {
+				intentID = declared.Intent.IntentID
+			}
+		}
+	} else {
+		id, err = s.cfg.Authenticator.Authenticate(r)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, agentauth.ErrInactive):
+			return deny(codes.PermissionDenied, typev3.StatusCode_Forbidden, err.Error(), ""), nil
+		case errors.Is(err, agentauth.ErrLookup):
+			return deny(codes.Unavailable, typev3.StatusCode_ServiceUnavailable, err.Error(), ""), nil
+		default:
+			return deny(codes.Unauthenticated, typev3.StatusCode_Unauthorized, err.Error(), challenge), nil
+		}
+	}
+	return s.allow(id, intentID)
+}
+
+// allow admits a request of id, declaring intentID if not empty.
+func (s *Server) allow(id *agentauth.Identity, intentID string) (*authv3.CheckResponse, error) {
+	headers := []*corev3.HeaderValueOption{
+		header(AgentIDHeader, id.AgentID()),
+		header(HumanIDHeader, id.HumanID()),
+		header(RiskTierHeader, string(id.Passport.RiskTier)),
+		header(CapabilitiesHeader, strings.Join(id.Passport.Capabilities, ",")),
+		header(CertificateBoundHeader, strconv.FormatBool(id.CertificateBound)),
+	}
+	var remove []string
+	if intentID != "" {
+		headers = append(headers, header(IntentIDHeader, intentID))
+	} else {
+		remove = append(remove, IntentIDHeader)
+	}
+	if s.cfg.StripCredentials {
+		remove = append(remove,
+			strings.ToLower(agentauth.PassportHeader),
+			strings.ToLower(dcpheader.AgentHeader),
+			strings.ToLower(dcpheader.IntentHeader))
+	}
+	metadata, err := structpb.NewStruct(map[string]interface{}{
+		"agent_id":  id.AgentID(),
+		"human_id":  id.HumanID(),
+		"risk_tier": string(id.Passport.RiskTier),
+		"intent_id": intentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("extauthz: metadata: %v", err)
+	}
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(codes.OK)},
+		HttpResponse: &authv3.CheckResponse_OkResponse{OkResponse: &authv3.OkHttpResponse{
+			Headers:         headers,
+			HeadersToRemove: remove,
+		}},
+		DynamicMetadata: metadata,
+	}, nil
+}
+
+// deny refuses a request with code and, to the client, status and an
+// {"error": msg} body like the other DCP services, challenging it with
+// challenge as WWW-Authenticate if not empty.
+func deny(code codes.Code, status typev3.StatusCode, msg, challenge string) *authv3.CheckResponse {
+	body, _ := json.Marshal(map[string]string{"error": msg})
+	headers := []*corev3.HeaderValueOption{header("content-type", "application/json")}
+	if challenge != "" {
+		headers = append(headers, header("www-authenticate", challenge))
+	}
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(code), Message: msg},
+		HttpResponse: &authv3.CheckResponse_DeniedResponse{DeniedResponse: &authv3.DeniedHttpResponse{
+			Status:  &typev3.HttpStatus{Code: status},
+			Headers: headers,
+			Body:    string(body),
+		}},
+	}
+}
+
+// header returns the option setting name to value, replacing any value the
+// client sent, even when value is empty.
+func header(name, value string) *corev3.HeaderValueOption {
+	return &corev3.HeaderValueOption{
+		Header:         &corev3.HeaderValue{Key: name, Value: value},
+		AppendAction:   corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+		KeepEmptyValue: true,
+	}
+}
+
+// Request returns the proxied HTTP request req describes, with ctx. The
+// client certificate Envoy forwards, if any, is the peer certificate of
+// its TLS state.
+func Request(ctx context.Context, req *authv3.CheckRequest) (*http.Request, error) {
+	attrs := req.GetAttributes()
+	h := attrs.GetRequest().GetHttp()
+	if h == nil {
+		return nil, errors.New("extauthz: check request has no HTTP attributes")
+	}
+	scheme := h.GetScheme()
+	if scheme == "" {
+		scheme = "http"
+	}
+	u, err := url.ParseRequestURI(h.GetPath())
+	if err != nil {
+		return nil, fmt.Errorf("extauthz: path: %v", err)
+	}
+	u.Scheme = scheme
+	u.Host = h.GetHost()
+	r, err := http.NewRequestWithContext(ctx, h.GetMethod(), u.String(), strings.NewReader(h.GetBody()))
+	if err != nil {
+		return nil, fmt.Errorf("extauthz: %v", err)
+	}
+	for name, value := range h.GetHeaders() {
+		addHeader(r, name, value)
+	}
+	for _, hv := range h.GetHeaderMap().GetHeaders() {
+		value := hv.GetValue()
+		if value == "" {
+			value = string(hv.GetRawValue())
+		}
+		addHeader(r, hv.GetKey(), value)
+	}
+
+	if cert := attrs.GetSource().GetCertificate(); cert != "" {
+		certs, err := parseCertificates(cert)
+		if err != nil {
+			return nil, fmt.Errorf("extauthz: source certificate: %v", err)
+		}
+		r.TLS = &tls.ConnectionState{HandshakeComplete: true, PeerCertificates: certs}
+	}
+	return r, nil
+}
+
+// addHeader adds the request header name of Envoy's attributes to r,
+// skipping the HTTP/2 pseudo-headers the request line already carries.
+func addHeader(r *http.Request, name, value string) {
+	if strings.HasPrefix(name, ":") {
+		return
+	}
+	if strings.EqualFold(name, "host") {
+		r.Host = value
+		return
+	}
+	r.Header.Add(name, value)
+}
+
+// parseCertificates parses the URL-encoded PEM certificates Envoy
+// forwards as the source certificate.
+func parseCertificates(encoded string) ([]*x509.Certificate, error) {
+	data, err := url.PathUnescape(encoded)
+	if err != nil {
+		return nil, err
+	}
+	rest := []byte(data)
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM certificate")
+	}
+	return certs, nil
+}
@@ -0,0 +1,247 @@
+package extauthz_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentcert"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcpheader"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/extauthz"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+)
+
+// failingSource is a registry that cannot be reached.
+type failingSource struct{}
+
+func (failingSource) Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error) {
+	return nil, errors.New("connection refused")
+}
+
+func newServer(t *testing.T, auth agentauth.Config, cfg extauthz.Config) authv3.AuthorizationClient {
+	t.Helper()
+	cfg.Authenticator = dcptest.Authenticator(t, auth)
+	srv, err := extauthz.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := grpc.NewServer()
+	srv.Register(g)
+	return authv3.NewAuthorizationClient(dcptest.Dial(t, g))
+}
+
+// checkRequest describes a GET of /orders?id=1 with headers, as Envoy
+// does.
+func checkRequest(headers map[string]string) *authv3.CheckRequest {
+	all := map[string]string{":method": "GET", ":path": "/orders?id=1", ":authority": "api.example"}
+	for k, v := range headers {
+		all[strings.ToLower(k)] = v
+	}
+	return &authv3.CheckRequest{Attributes: &authv3.AttributeContext{
+		Request: &authv3.AttributeContext_Request{Http: &authv3.AttributeContext_HttpRequest{
+			Method:  "GET",
+			Path:    "/orders?id=1",
+			Host:    "api.example",
+			Scheme:  "https",
+			Headers: all,
+		}},
+	}}
+}
+
+func passportHeader(t *testing.T, p *dcp.AgentPassport) map[string]string {
+	t.Helper()
+	value, err := agentauth.EncodePassport(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]string{agentauth.PassportHeader: value, extauthz.AgentIDHeader: "dcp:agent:spoofed"}
+}
+
+// upstream returns the headers an allowed check sets and removes.
+func upstream(t *testing.T, resp *authv3.CheckResponse) (map[string]string, []string) {
+	t.Helper()
+	if codes.Code(resp.GetStatus().GetCode()) != codes.OK {
+		t.Fatalf("denied: %v", resp.GetStatus())
+	}
+	set := map[string]string{}
+	for _, h := range resp.GetOkResponse().GetHeaders() {
+		set[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	return set, resp.GetOkResponse().GetHeadersToRemove()
+}
+
+func wantDenied(t *testing.T, resp *authv3.CheckResponse, code codes.Code, status int) {
+	t.Helper()
+	denied := resp.GetDeniedResponse()
+	if codes.Code(resp.GetStatus().GetCode()) != code || denied == nil || int(denied.GetStatus().GetCode()) != status {
+		t.Fatalf("got %v %v, want %s %d", resp.GetStatus(), denied.GetStatus(), code, status)
+	}
+	var body map[string]string
+	if err := json.Unmarshal([]byte(denied.GetBody()), &body); err != nil || body["error"] == "" {
+		t.Fatalf("body %q", denied.GetBody())
+	}
+}
+
+func TestCheckPassport(t *testing.T) {
+	ctx := context.Background()
+	p, _, _ := dcptest.Agent(t, dcp.RiskTierMedium, "browse", "email")
+	suspended, suspendedSigner, _ := dcptest.Agent(t, dcp.RiskTierMedium, "browse", "email")
+	suspended.Status = dcp.StatusSuspended
+	if err := suspended.Sign(suspendedSigner); err != nil {
+		t.Fatal(err)
+	}
+	client := newServer(t, agentauth.Config{}, extauthz.Config{StripCredentials: true})
+
+	resp, err := client.Check(ctx, checkRequest(passportHeader(t, p)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, remove := upstream(t, resp)
+	if set[extauthz.AgentIDHeader] != p.AgentID || set[extauthz.HumanIDHeader] != p.PrincipalBindingReference ||
+		set[extauthz.RiskTierHeader] != "medium" || set[extauthz.CapabilitiesHeader] != "browse,email" ||
+		set[extauthz.CertificateBoundHeader] != "false" {
+		t.Fatalf("identity headers %v", set)
+	}
+	if strings.Join(remove, " ") != "x-dcp-intent-id dcp-agent-passport dcp-agent dcp-intent" {
+		t.Fatalf("removed %v", remove)
+	}
+	if resp.GetDynamicMetadata().GetFields()["agent_id"].GetStringValue() != p.AgentID {
+		t.Fatalf("metadata %v", resp.GetDynamicMetadata())
+	}
+
+	resp, err = client.Check(ctx, checkRequest(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDenied(t, resp, codes.Unauthenticated, http.StatusUnauthorized)
+	if h := resp.GetDeniedResponse().GetHeaders(); len(h) != 2 || h[1].GetHeader().GetValue() != `DCP header="DCP-Agent-Passport"` {
+		t.Fatalf("denied headers %v", h)
+	}
+	resp, err = client.Check(ctx, checkRequest(passportHeader(t, suspended)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDenied(t, resp, codes.PermissionDenied, http.StatusForbidden)
+
+	unreachable := newServer(t, agentauth.Config{Passports: failingSource{}}, extauthz.Config{})
+	resp, err = unreachable.Check(ctx, checkRequest(passportHeader(t, p)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDenied(t, resp, codes.Unavailable, http.StatusServiceUnavailable)
+
+	resp, err = client.Check(ctx, &authv3.CheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDenied(t, resp, codes.InvalidArgument, http.StatusBadRequest)
+}
+
+func TestCheckPeerCertificate(t *testing.T) {
+	ctx := context.Background()
+	p, _, key := dcptest.Agent(t, dcp.RiskTierMedium, "browse", "email")
+	other, _, otherKey := dcptest.Agent(t, dcp.RiskTierMedium, "browse", "email")
+	client := newServer(t, agentauth.Config{RequireCertificate: true}, extauthz.Config{})
+
+	withCert := func(p *dcp.AgentPassport, key ed25519.PrivateKey) *authv3.CheckRequest {
+		der, err := agentcert.SelfSign(p, key, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := checkRequest(passportHeader(t, p))
+		req.Attributes.Source = &authv3.AttributeContext_Peer{
+			Certificate: url.PathEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))),
+		}
+		return req
+	}
+
+	resp, err := client.Check(ctx, withCert(p, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set, _ := upstream(t, resp); set[extauthz.CertificateBoundHeader] != "true" {
+		t.Fatalf("identity headers %v", set)
+	}
+
+	// The passport of one agent over the connection of another.
+	stolen := withCert(other, otherKey)
+	stolen.Attributes.Request.Http.Headers = checkRequest(passportHeader(t, p)).Attributes.Request.Http.Headers
+	resp, err = client.Check(ctx, stolen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDenied(t, resp, codes.Unauthenticated, http.StatusUnauthorized)
+
+	resp, err = client.Check(ctx, checkRequest(passportHeader(t, p)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDenied(t, resp, codes.Unauthenticated, http.StatusUnauthorized)
+}
+
+func TestCheckCompactHeaders(t *testing.T) {
+	ctx := context.Background()
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierMedium, "browse", "email")
+	auth := dcptest.Authenticator(t, agentauth.Config{})
+	dec, err := dcpheader.NewDecoder(dcpheader.Config{Authenticator: auth, Passports: grpcserver.PassportMap{p.AgentID: *p}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newServer(t, agentauth.Config{}, extauthz.Config{Decoder: dec})
+
+	_, thisFile, _, _ := runtime.Caller(0)
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(thisFile), "..", "..", "..", "..", "tests", "conformance", "examples", "intent.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(data, &intent); err != nil {
+		t.Fatal(err)
+	}
+	intent.AgentID = p.AgentID
+	intent.HumanID = p.PrincipalBindingReference
+
+	r, _ := http.NewRequest(http.MethodGet, "https://api.example/orders", nil)
+	enc := &dcpheader.Encoder{Passport: p, Signer: signer}
+	if err := enc.Encode(r, &intent); err != nil {
+		t.Fatal(err)
+	}
+	headers := map[string]string{
+		dcpheader.AgentHeader:  r.Header.Get(dcpheader.AgentHeader),
+		dcpheader.IntentHeader: r.Header.Get(dcpheader.IntentHeader),
+	}
+	resp, err := client.Check(ctx, checkRequest(headers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, remove := upstream(t, resp)
+	if set[extauthz.AgentIDHeader] != p.AgentID || set[extauthz.IntentIDHeader] != intent.IntentID || len(remove) != 0 {
+		t.Fatalf("identity headers %v, removed %v", set, remove)
+	}
+
+	headers[dcpheader.IntentHeader] = strings.Replace(headers[dcpheader.IntentHeader], "sig=:", "sig=:AAAA", 1)
+	resp, err = client.Check(ctx, checkRequest(headers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDenied(t, resp, codes.Unauthenticated, http.StatusUnauthorized)
+	if h := resp.GetDeniedResponse().GetHeaders(); h[1].GetHeader().GetValue() != `DCP header="DCP-Agent"` {
+		t.Fatalf("denied headers %v", h)
+	}
+}
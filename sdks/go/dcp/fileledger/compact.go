@@ -0,0 +1,112 @@
+package fileledger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// compactBatch is the number of entries per record in a compacted file.
+const compactBatch = 1024
+
+// Compact rewrites the file with entries packed into large records and only
+// the latest checkpoint kept. Entries are copied byte-for-byte in meaning, so
+// every entry hash, the prev_hash chain and the Merkle root are unchanged;
+// Compact checks the root before replacing the original file, which it does
+// with an atomic rename.
+func (s *Store) Compact(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return dcp.ErrLedgerClosed
+	}
+
+	tmpPath := s.path + ".compact"
+	tmp, err := Open(tmpPath, Options{NoSync: true})
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	defer tmp.Close()
+	if tmp.size != int64(len(magic)) {
+		return fmt.Errorf("fileledger: stale compaction file %s", tmpPath)
+	}
+
+	before := dcp.NewMerkleTree()
+	var batch []dcp.AuditEntry
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		_, err := tmp.AppendBatch(ctx, batch)
+		batch = batch[:0]
+		return err
+	}
+	var rec *record
+	recOff := int64(-1)
+	for i, loc := range s.index {
+		if loc.offset != recOff {
+			if rec, _, err = readRecord(s.f, loc.offset); err != nil {
+				return fmt.Errorf("fileledger: compact: read entry %d: %w", i, err)
+			}
+			recOff = loc.offset
+		}
+		e := rec.Entries[loc.pos]
+		h, err := dcp.HashObject(e)
+		if err != nil {
+			return fmt.Errorf("fileledger: compact: %w", err)
+		}
+		if err := before.Append(h); err != nil {
+			return err
+		}
+		if batch = append(batch, e); len(batch) == compactBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if s.checkpoint != nil {
+		if err := tmp.SaveCheckpoint(ctx, s.checkpoint); err != nil {
+			return err
+		}
+	}
+
+	after := dcp.NewMerkleTree()
+	err = tmp.Range(ctx, 0, int64(len(tmp.index)), func(_ int64, e dcp.AuditEntry) error {
+		h, err := dcp.HashObject(e)
+		if err != nil {
+			return err
+		}
+		return after.Append(h)
+	})
+	if err != nil {
+		return fmt.Errorf("fileledger: compact: %w", err)
+	}
+	if after.Size() != before.Size() || after.Root() != before.Root() {
+		return fmt.Errorf("fileledger: compacted ledger does not match the original; keeping the original")
+	}
+	if err := tmp.f.Sync(); err != nil {
+		return fmt.Errorf("fileledger: compact: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("fileledger: compact: %w", err)
+	}
+	if dir, err := os.Open(filepath.Dir(s.path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	// The renamed file is now tmp's handle; adopt it and its index.
+	s.f.Close()
+	s.f, tmp.f = tmp.f, nil
+	s.size = tmp.size
+	s.index = tmp.index
+	s.checkpoint = tmp.checkpoint
+	return nil
+}
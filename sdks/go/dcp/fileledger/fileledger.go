@@ -0,0 +1,316 @@
+// Package fileledger implements dcp.LedgerStore as a single append-only log
+// file, for edge agents that cannot ship a database.
+//
+// The file starts with an 8-byte magic and is followed by framed records:
+// a big-endian uint32 payload length, the CRC-32C of the payload, then the
+// JSON payload. Each record holds either a batch of audit entries or a
+// checkpoint, and is written with a single write followed by fsync, so a
+// batch is either fully present or, after a crash, detected as a torn tail
+// and truncated on the next Open.
+package fileledger
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+var magic = []byte("DCPLOG1\n")
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+const headerLen = 8
+
+// maxRecordBytes rejects absurd lengths from a corrupted header before allocating.
+const maxRecordBytes = 64 << 20
+
+// record is the JSON payload of one log record.
+type record struct {
+	Entries    []dcp.AuditEntry `json:"entries,omitempty"`
+	Checkpoint *dcp.Checkpoint  `json:"checkpoint,omitempty"`
+}
+
+// entryLoc locates an entry: the record holding it and its position there.
+type entryLoc struct {
+	offset int64
+	length uint32
+	pos    int
+}
+
+// Options configures a Store.
+type Options struct {
+	// NoSync skips fsync after each write. Faster, but a crash may lose
+	// recently acknowledged appends (never corrupt earlier ones).
+	NoSync bool
+}
+
+// Store is a file-backed dcp.LedgerStore. It keeps an in-memory index of
+// entry positions; entry contents are read from the file on demand. A Store
+// is safe for concurrent use within one process; only one process may open
+// a file at a time.
+type Store struct {
+	path string
+	opts Options
+
+	mu         sync.RWMutex
+	f          *os.File
+	size       int64
+	index      []entryLoc
+	checkpoint *dcp.Checkpoint
+}
+
+var _ dcp.LedgerStore = (*Store)(nil)
+
+// Open opens or creates the ledger file at path. A torn record at the end of
+// the file, left by a crash mid-write, is truncated away.
+func Open(path string, opts Options) (*Store, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("fileledger: %w", err)
+	}
+	s := &Store{path: path, opts: opts, f: f}
+	if err := s.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) load() error {
+	info, err := s.f.Stat()
+	if err != nil {
+		return fmt.Errorf("fileledger: %w", err)
+	}
+	if info.Size() == 0 {
+		if _, err := s.f.WriteAt(magic, 0); err != nil {
+			return fmt.Errorf("fileledger: write header: %w", err)
+		}
+		s.size = int64(len(magic))
+		return s.sync()
+	}
+	head := make([]byte, len(magic))
+	if _, err := s.f.ReadAt(head, 0); err != nil || string(head) != string(magic) {
+		return fmt.Errorf("fileledger: %s is not a ledger file", s.path)
+	}
+	off := int64(len(magic))
+	for off < info.Size() {
+		rec, n, err := readRecord(s.f, off)
+		if err != nil {
+			// A torn or corrupt tail: keep everything before it.
+			if err := s.f.Truncate(off); err != nil {
+				return fmt.Errorf("fileledger: truncate torn tail: %w", err)
+			}
+			break
+		}
+		s.indexRecord(rec, off, n)
+		off += headerLen + int64(n)
+	}
+	s.size = off
+	return nil
+}
+
+func (s *Store) indexRecord(rec *record, off int64, n uint32) {
+	for i := range rec.Entries {
+		s.index = append(s.index, entryLoc{offset: off, length: n, pos: i})
+	}
+	if rec.Checkpoint != nil {
+		s.checkpoint = rec.Checkpoint
+	}
+}
+
+func readRecord(r io.ReaderAt, off int64) (*record, uint32, error) {
+	var hdr [headerLen]byte
+	if _, err := r.ReadAt(hdr[:], off); err != nil {
+		return nil, 0, err
+	}
+	n := binary.BigEndian.Uint32(hdr[:4])
+	if n > maxRecordBytes {
+		return nil, 0, errors.New("record too large")
+	}
+	payload := make([]byte, n)
+	if _, err := r.ReadAt(payload, off+headerLen); err != nil {
+		return nil, 0, err
+	}
+	if crc32.Checksum(payload, castagnoli) != binary.BigEndian.Uint32(hdr[4:]) {
+		return nil, 0, errors.New("checksum mismatch")
+	}
+	var rec record
+	if err := json.Unmarshal(payload, &rec); err != nil {
+		return nil, 0, err
+	}
+	return &rec, n, nil
+}
+
+func encodeRecord(rec *record) ([]byte, error) {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, headerLen+len(payload))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.Checksum(payload, castagnoli))
+	copy(buf[headerLen:], payload)
+	return buf, nil
+}
+
+func (s *Store) sync() error {
+	if s.opts.NoSync {
+		return nil
+	}
+	return s.f.Sync()
+}
+
+// writeRecord appends rec; callers hold s.mu for writing.
+func (s *Store) writeRecord(rec *record) (int64, uint32, error) {
+	if s.f == nil {
+		return 0, 0, dcp.ErrLedgerClosed
+	}
+	buf, err := encodeRecord(rec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("fileledger: %w", err)
+	}
+	off := s.size
+	if _, err := s.f.WriteAt(buf, off); err != nil {
+		// Drop whatever part of the record reached the file.
+		s.f.Truncate(off)
+		return 0, 0, fmt.Errorf("fileledger: write: %w", err)
+	}
+	if err := s.sync(); err != nil {
+		return 0, 0, fmt.Errorf("fileledger: sync: %w", err)
+	}
+	s.size = off + int64(len(buf))
+	return off, uint32(len(buf) - headerLen), nil
+}
+
+// AppendEntry appends a single entry.
+func (s *Store) AppendEntry(ctx context.Context, entry dcp.AuditEntry) (int64, error) {
+	return s.AppendBatch(ctx, []dcp.AuditEntry{entry})
+}
+
+// AppendBatch appends entries atomically with one write and one fsync and
+// returns the index of the first.
+func (s *Store) AppendBatch(ctx context.Context, entries []dcp.AuditEntry) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, errors.New("fileledger: empty batch")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := &record{Entries: entries}
+	off, n, err := s.writeRecord(rec)
+	if err != nil {
+		return 0, err
+	}
+	first := int64(len(s.index))
+	s.indexRecord(rec, off, n)
+	return first, nil
+}
+
+// GetByIndex reads the entry at index from the file.
+func (s *Store) GetByIndex(ctx context.Context, index int64) (dcp.AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if index < 0 || index >= int64(len(s.index)) {
+		return dcp.AuditEntry{}, dcp.ErrLedgerEntryNotFound
+	}
+	if s.f == nil {
+		return dcp.AuditEntry{}, dcp.ErrLedgerClosed
+	}
+	loc := s.index[index]
+	rec, _, err := readRecord(s.f, loc.offset)
+	if err != nil {
+		return dcp.AuditEntry{}, fmt.Errorf("fileledger: read entry %d: %w", index, err)
+	}
+	return rec.Entries[loc.pos], nil
+}
+
+// Range calls fn for each entry in [start, end), reading each record once.
+func (s *Store) Range(ctx context.Context, start, end int64, fn func(int64, dcp.AuditEntry) error) error {
+	s.mu.RLock()
+	if start < 0 {
+		start = 0
+	}
+	if end > int64(len(s.index)) {
+		end = int64(len(s.index))
+	}
+	var locs []entryLoc
+	if start < end {
+		locs = append(locs, s.index[start:end]...)
+	}
+	f := s.f
+	s.mu.RUnlock()
+	if f == nil {
+		return dcp.ErrLedgerClosed
+	}
+
+	var rec *record
+	recOff := int64(-1)
+	for i, loc := range locs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if loc.offset != recOff {
+			var err error
+			if rec, _, err = readRecord(f, loc.offset); err != nil {
+				return fmt.Errorf("fileledger: read entry %d: %w", start+int64(i), err)
+			}
+			recOff = loc.offset
+		}
+		if err := fn(start+int64(i), rec.Entries[loc.pos]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries.
+func (s *Store) Len(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return int64(len(s.index)), nil
+}
+
+// SaveCheckpoint appends a checkpoint record.
+func (s *Store) SaveCheckpoint(ctx context.Context, cp *dcp.Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved := *cp
+	if _, _, err := s.writeRecord(&record{Checkpoint: &saved}); err != nil {
+		return err
+	}
+	s.checkpoint = &saved
+	return nil
+}
+
+// LatestCheckpoint returns the last checkpoint record in the file.
+func (s *Store) LatestCheckpoint(ctx context.Context) (*dcp.Checkpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.checkpoint == nil {
+		return nil, dcp.ErrNoCheckpoint
+	}
+	cp := *s.checkpoint
+	return &cp, nil
+}
+
+// Close closes the file.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
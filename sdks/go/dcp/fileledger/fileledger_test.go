@@ -0,0 +1,120 @@
+package fileledger_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/fileledger"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/ledgertest"
+)
+
+func fixedNow(i int) time.Time {
+	return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Minute)
+}
+
+func openFile(t *testing.T, path string) *fileledger.Store {
+	t.Helper()
+	s, err := fileledger.Open(path, fileledger.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestFileLedgerConformance(t *testing.T) {
+	ledgertest.Run(t, func(t *testing.T) dcp.LedgerStore {
+		return openFile(t, filepath.Join(t.TempDir(), "ledger.log"))
+	})
+}
+
+func TestFileLedgerReopenAndTornTail(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ledger.log")
+	s := openFile(t, path)
+	agent, _ := dcp.GenerateKeypair()
+	for i := 0; i < 5; i++ {
+		if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, s, ledgertest.Entry(i), agent.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	// Simulate a crash halfway through writing another record.
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.Write([]byte{0, 0, 1, 0, 1, 2, 3, 4, '{', '"'})
+	f.Close()
+
+	s = openFile(t, path)
+	defer s.Close()
+	if n, _ := s.Len(ctx); n != 5 {
+		t.Fatalf("reopened ledger has %d entries, want 5", n)
+	}
+	if _, r := dcp.VerifyLedger(ctx, s, dcp.AuditStreamOptions{AgentPublicKeyB64: agent.PublicKeyB64}); !r.Verified {
+		t.Fatalf("reopened ledger should verify: %v", r.Errors)
+	}
+	if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, s, ledgertest.Entry(5), agent.SecretKeyB64); err != nil {
+		t.Fatal(err)
+	}
+	if _, r := dcp.VerifyLedger(ctx, s, dcp.AuditStreamOptions{}); !r.Verified {
+		t.Fatalf("append after recovery should keep the chain: %v", r.Errors)
+	}
+}
+
+func TestFileLedgerBatchAndCompact(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ledger.log")
+	s := openFile(t, path)
+	defer s.Close()
+	kp, _ := dcp.GenerateKeypair()
+
+	for i := 0; i < 30; i++ {
+		if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, s, ledgertest.Entry(i), kp.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+		if i%10 == 9 {
+			if _, err := dcp.CheckpointLedger(ctx, s, "agent001", kp.SecretKeyB64, fixedNow(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	first, err := s.AppendBatch(ctx, []dcp.AuditEntry{ledgertest.Entry(30), ledgertest.Entry(31)})
+	if err != nil || first != 30 {
+		t.Fatalf("AppendBatch = %d, %v", first, err)
+	}
+	cpBefore, _ := s.LatestCheckpoint(ctx)
+	sizeBefore, _ := os.Stat(path)
+
+	if err := s.Compact(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sizeAfter, _ := os.Stat(path)
+	if sizeAfter.Size() >= sizeBefore.Size() {
+		t.Fatalf("compaction did not shrink the file: %d -> %d", sizeBefore.Size(), sizeAfter.Size())
+	}
+	cp, err := s.LatestCheckpoint(ctx)
+	if err != nil || cp.RootHash != cpBefore.RootHash {
+		t.Fatal("latest checkpoint must survive compaction")
+	}
+	tree := dcp.NewMerkleTree()
+	s.Range(ctx, 0, cp.TreeSize, func(_ int64, e dcp.AuditEntry) error {
+		h, _ := dcp.HashObject(e)
+		return tree.Append(h)
+	})
+	if "sha256:"+tree.Root() != cp.RootHash {
+		t.Fatal("compacted entries must still match the checkpoint root")
+	}
+	if ok, _ := cp.Verify(kp.PublicKeyB64); !ok {
+		t.Fatal("checkpoint signature must survive compaction")
+	}
+
+	s.Close()
+	reopened := openFile(t, path)
+	defer reopened.Close()
+	if n, _ := reopened.Len(ctx); n != 32 {
+		t.Fatalf("compacted file reopened with %d entries, want 32", n)
+	}
+}
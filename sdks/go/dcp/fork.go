@@ -0,0 +1,92 @@
+package dcp
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ForkEvidence is one side of a ChainFork: an audit entry and where it was seen.
+type ForkEvidence struct {
+	Source    string     `json:"source"`
+	EntryHash string     `json:"entry_hash"`
+	Entry     AuditEntry `json:"entry"`
+}
+
+// ChainFork records two different audit entries from the same agent chained
+// onto the same prev_hash: proof that the agent presented divergent
+// histories.
+type ChainFork struct {
+	AgentID  string       `json:"agent_id"`
+	PrevHash string       `json:"prev_hash"`
+	First    ForkEvidence `json:"first"`
+	Second   ForkEvidence `json:"second"`
+}
+
+// ForkDetector indexes audit entries from many bundles by agent and
+// prev_hash and reports forks as they appear. A ForkDetector is safe for
+// concurrent use.
+type ForkDetector struct {
+	// StrictGenesis also treats two different entries chained onto
+	// "GENESIS" as a fork. Leave it off unless every bundle after an
+	// agent's first carries a ChainAnchor.
+	StrictGenesis bool
+
+	mu    sync.Mutex
+	seen  map[string]map[string]ForkEvidence
+	forks []ChainFork
+}
+
+// NewForkDetector returns an empty detector.
+func NewForkDetector() *ForkDetector {
+	return &ForkDetector{seen: map[string]map[string]ForkEvidence{}}
+}
+
+// AddBundle indexes the audit entries of sb, labelled with source (e.g. the
+// party that presented it), and returns any forks they reveal.
+func (d *ForkDetector) AddBundle(source string, sb *SignedBundle) ([]ChainFork, error) {
+	return d.AddEntries(source, sb.Bundle.AuditEntries)
+}
+
+// AddEntries indexes entries and returns any forks they reveal.
+func (d *ForkDetector) AddEntries(source string, entries []AuditEntry) ([]ChainFork, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.seen == nil {
+		d.seen = map[string]map[string]ForkEvidence{}
+	}
+	var found []ChainFork
+	for i, entry := range entries {
+		if entry.PrevHash == "GENESIS" && !d.StrictGenesis {
+			continue
+		}
+		h, err := HashObject(entry)
+		if err != nil {
+			return found, fmt.Errorf("fork detector: hash audit entry %d: %w", i, err)
+		}
+		byPrev := d.seen[entry.AgentID]
+		if byPrev == nil {
+			byPrev = map[string]ForkEvidence{}
+			d.seen[entry.AgentID] = byPrev
+		}
+		ev := ForkEvidence{Source: source, EntryHash: h, Entry: entry}
+		first, ok := byPrev[entry.PrevHash]
+		if !ok {
+			byPrev[entry.PrevHash] = ev
+			continue
+		}
+		if first.EntryHash == h {
+			continue
+		}
+		fork := ChainFork{AgentID: entry.AgentID, PrevHash: entry.PrevHash, First: first, Second: ev}
+		d.forks = append(d.forks, fork)
+		found = append(found, fork)
+	}
+	return found, nil
+}
+
+// Forks returns every fork detected so far.
+func (d *ForkDetector) Forks() []ChainFork {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]ChainFork(nil), d.forks...)
+}
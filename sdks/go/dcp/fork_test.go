@@ -0,0 +1,65 @@
+package dcp_test
+
+import (
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestForkDetector(t *testing.T) {
+	owner, _ := dcp.GenerateKeypair()
+	first := anchoredBundle(t, owner, "agent001", nil, "audit001", "audit002")
+	anchor, _ := dcp.ChainAnchorFor(first)
+	toAlice := anchoredBundle(t, owner, "agent001", anchor, "audit003")
+	toBob := anchoredBundle(t, owner, "agent001", anchor, "audit003-alt")
+
+	d := dcp.NewForkDetector()
+	for _, step := range []struct {
+		source string
+		sb     *dcp.SignedBundle
+	}{{"alice", first}, {"alice", toAlice}, {"bob", first}} {
+		forks, err := d.AddBundle(step.source, step.sb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(forks) != 0 {
+			t.Fatalf("consistent history reported as fork: %+v", forks)
+		}
+	}
+
+	forks, err := d.AddBundle("bob", toBob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forks) != 1 {
+		t.Fatalf("expected one fork, got %d", len(forks))
+	}
+	f := forks[0]
+	if f.AgentID != "agent001" || f.PrevHash != anchor.PrevEntryHash {
+		t.Fatalf("unexpected fork position: %+v", f)
+	}
+	if f.First.Source != "alice" || f.Second.Source != "bob" || f.Second.Entry.AuditID != "audit003-alt" {
+		t.Fatalf("unexpected fork evidence: %+v", f)
+	}
+	if len(d.Forks()) != 1 {
+		t.Fatal("Forks should retain the detected fork")
+	}
+}
+
+func TestForkDetectorGenesis(t *testing.T) {
+	owner, _ := dcp.GenerateKeypair()
+	a := anchoredBundle(t, owner, "agent001", nil, "audit001")
+	b := anchoredBundle(t, owner, "agent001", nil, "audit009")
+
+	lenient := dcp.NewForkDetector()
+	lenient.AddBundle("a", a)
+	if forks, _ := lenient.AddBundle("b", b); len(forks) != 0 {
+		t.Fatal("GENESIS restarts are not forks by default")
+	}
+	strict := dcp.NewForkDetector()
+	strict.StrictGenesis = true
+	strict.AddBundle("a", a)
+	if forks, _ := strict.AddBundle("b", b); len(forks) != 1 {
+		t.Fatal("StrictGenesis should report two GENESIS entries")
+	}
+}
@@ -0,0 +1,122 @@
+package grpcauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+)
+
+// Credentials are an agent's passport and key, attached to its outgoing
+// calls by the client interceptors.
+type Credentials struct {
+	Passport *dcp.AgentPassport
+	// Signer is the agent key, which signs intent references.
+	Signer dcp.BundleSigner
+	// Now is the clock intent references are stamped with; nil means
+	// time.Now.
+	Now func() time.Time
+}
+
+type outgoingIntentKey struct{}
+
+// WithIntent returns ctx whose calls through Credentials' interceptors
+// carry a signed reference to intent.
+func WithIntent(ctx context.Context, intent *dcp.Intent) context.Context {
+	return context.WithValue(ctx, outgoingIntentKey{}, intent)
+}
+
+// Unary returns the unary client interceptor.
+func (c *Credentials) Unary() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := c.attach(ctx)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// Stream returns the stream client interceptor.
+func (c *Credentials) Stream() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := c.attach(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// attach returns ctx with the passport and, if ctx has an intent, a fresh
+// reference to it in the outgoing metadata.
+func (c *Credentials) attach(ctx context.Context) (context.Context, error) {
+	if c.Passport == nil {
+		return nil, errors.New("grpcauth: credentials have no passport")
+	}
+	passport, err := agentauth.EncodePassport(c.Passport)
+	if err != nil {
+		return nil, err
+	}
+	kv := []string{PassportKey, passport}
+	if intent, ok := ctx.Value(outgoingIntentKey{}).(*dcp.Intent); ok {
+		if c.Signer == nil {
+			return nil, errors.New("grpcauth: credentials have no signer for the intent reference")
+		}
+		now := time.Now
+		if c.Now != nil {
+			now = c.Now
+		}
+		ref, err := NewIntentReference(intent, c.Signer, now())
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(ref)
+		if err != nil {
+			return nil, err
+		}
+		kv = append(kv, IntentRefKey, base64.StdEncoding.EncodeToString(data))
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...), nil
+}
+
+// PropagateUnary returns a unary client interceptor forwarding the DCP
+// credentials of the incoming call in ctx, for a service calling others on
+// an agent's behalf. Calls made outside an incoming call are unchanged.
+func PropagateUnary() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(propagate(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// PropagateStream is the stream counterpart of PropagateUnary.
+func PropagateStream() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(propagate(ctx), desc, cc, method, opts...)
+	}
+}
+
+func propagate(ctx context.Context) context.Context {
+	in, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	out, _ := metadata.FromOutgoingContext(ctx)
+	var kv []string
+	for _, key := range []string{PassportKey, IntentRefKey} {
+		if vs := in.Get(key); len(vs) > 0 && len(out.Get(key)) == 0 {
+			kv = append(kv, key, vs[0])
+		}
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
@@ -0,0 +1,258 @@
+// Package grpcauth carries DCP agent identity across gRPC calls.
+//
+// A calling agent attaches its passport and, per call, a signed reference
+// to the intent the call serves, with the client interceptors of
+// Credentials. A service checks them with the server interceptors of an
+// Interceptor, which admit the call with the agent's agentauth.Identity and
+// IntentReference in its context. A service calling further services on the
+// agent's behalf forwards both with the Propagate interceptors, so each hop
+// sees the original agent.
+//
+// Metadata:
+//
+//	dcp-agent-passport  the passport, as in the agentauth DCP-Agent-Passport header
+//	dcp-intent-ref      base64 of a JSON IntentReference
+//
+// The intent reference is signed with the agent key and carries the time
+// it was made, so a valid one also proves the caller holds the passport's
+// key, within the MaxSkew it may be replayed for.
+package grpcauth
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+)
+
+// Metadata keys.
+const (
+	PassportKey  = "dcp-agent-passport"
+	IntentRefKey = "dcp-intent-ref"
+)
+
+// DefaultMaxSkew is how old, or how far in the future, an intent reference
+// may be when Config.MaxSkew is zero.
+const DefaultMaxSkew = 5 * time.Minute
+
+// IntentReference ties a call to an intent the agent declared. It carries
+// the intent's hash rather than the intent, which the service fetches or
+// receives by other means if it needs it.
+type IntentReference struct {
+	IntentID   string `json:"intent_id"`
+	IntentHash string `json:"intent_hash"`
+	AgentID    string `json:"agent_id"`
+	IssuedAt   string `json:"issued_at"`
+	// Signature is by the agent key over the canonical reference with an
+	// empty signature.
+	Signature string `json:"signature"`
+}
+
+// NewIntentReference returns a reference to intent issued at now and
+// signed with the agent key s.
+func NewIntentReference(intent *dcp.Intent, s dcp.BundleSigner, now time.Time) (*IntentReference, error) {
+	hash, err := dcp.HashObject(intent)
+	if err != nil {
+		return nil, err
+	}
+	ref := &IntentReference{
+		IntentID:   intent.IntentID,
+		IntentHash: hash,
+		AgentID:    intent.AgentID,
+		IssuedAt:   dcp.FormatTime(now),
+	}
+	canon, err := dcp.Canonicalize(ref)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Signature, err = s.SignCanonical(canon); err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+// Verify checks the signature under publicKeyB64.
+func (r *IntentReference) Verify(publicKeyB64 string) error {
+	if r.Signature == "" {
+		return errors.New("intent reference has no signature")
+	}
+	unsigned := *r
+	unsigned.Signature = ""
+	if ok, err := dcp.VerifyObject(unsigned, r.Signature, publicKeyB64); err != nil || !ok {
+		return errors.New("intent reference signature does not verify under the passport key")
+	}
+	return nil
+}
+
+// Matches reports whether r refers to intent.
+func (r *IntentReference) Matches(intent *dcp.Intent) bool {
+	hash, err := dcp.HashObject(intent)
+	return err == nil && hash == r.IntentHash && intent.IntentID == r.IntentID
+}
+
+type intentKey struct{}
+
+// IntentFromContext returns the intent reference admitted with the call,
+// if the caller sent one.
+func IntentFromContext(ctx context.Context) (*IntentReference, bool) {
+	ref, ok := ctx.Value(intentKey{}).(*IntentReference)
+	return ref, ok
+}
+
+// Config configures an Interceptor.
+type Config struct {
+	// Authenticator checks the presented passports. Required.
+	Authenticator *agentauth.Authenticator
+	// RequireIntent refuses calls without a valid intent reference.
+	RequireIntent bool
+	// MaxSkew bounds the age of intent references; zero means
+	// DefaultMaxSkew.
+	MaxSkew time.Duration
+	// Public lists full method names, such as
+	// "/grpc.health.v1.Health/Check", served without credentials.
+	Public []string
+	// Now is the clock intent references are checked against; nil means
+	// time.Now.
+	Now func() time.Time
+}
+
+// Interceptor checks the DCP credentials of incoming calls. Create one
+// with New.
+type Interceptor struct {
+	cfg    Config
+	public map[string]bool
+}
+
+// New returns an Interceptor for cfg.
+func New(cfg Config) (*Interceptor, error) {
+	if cfg.Authenticator == nil {
+		return nil, errors.New("grpcauth: an authenticator is required")
+	}
+	if cfg.MaxSkew <= 0 {
+		cfg.MaxSkew = DefaultMaxSkew
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	i := &Interceptor{cfg: cfg, public: map[string]bool{}}
+	for _, m := range cfg.Public {
+		i.public[m] = true
+	}
+	return i, nil
+}
+
+// Unary returns the unary server interceptor.
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if i.public[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		ctx, err := i.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the stream server interceptor.
+func (i *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if i.public[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		ctx, err := i.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// authenticate checks the credentials in the metadata of ctx and returns
+// ctx carrying the agent's identity and intent reference.
+func (i *Interceptor) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(PassportKey)
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, agentauth.ErrNoPassport.Error())
+	}
+	p, err := agentauth.DecodePassport(values[0])
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "%v: %v", agentauth.ErrInvalidPassport, err)
+	}
+	var conn *tls.ConnectionState
+	if pr, ok := peer.FromContext(ctx); ok {
+		if info, ok := pr.AuthInfo.(credentials.TLSInfo); ok {
+			conn = &info.State
+		}
+	}
+	id, err := i.cfg.Authenticator.Check(ctx, p, conn)
+	switch {
+	case errors.Is(err, agentauth.ErrInactive):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, agentauth.ErrLookup):
+		return nil, status.Error(codes.Unavailable, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	ctx = agentauth.NewContext(ctx, id)
+
+	refs := md.Get(IntentRefKey)
+	if len(refs) == 0 {
+		if i.cfg.RequireIntent {
+			return nil, status.Error(codes.Unauthenticated, "no intent reference presented")
+		}
+		return ctx, nil
+	}
+	ref, err := i.checkIntent(refs[0], p)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "intent reference: %v", err)
+	}
+	return context.WithValue(ctx, intentKey{}, ref), nil
+}
+
+func (i *Interceptor) checkIntent(value string, p *dcp.AgentPassport) (*IntentReference, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("not base64")
+	}
+	var ref IntentReference
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return nil, err
+	}
+	if ref.AgentID != p.AgentID {
+		return nil, fmt.Errorf("made by %s, not %s", ref.AgentID, p.AgentID)
+	}
+	if err := ref.Verify(p.PublicKey); err != nil {
+		return nil, err
+	}
+	issued, err := dcp.ParseTime(ref.IssuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("issued_at: %v", err)
+	}
+	if skew := i.cfg.Now().Sub(issued); skew > i.cfg.MaxSkew || skew < -i.cfg.MaxSkew {
+		return nil, fmt.Errorf("issued at %s, outside the accepted %s", ref.IssuedAt, i.cfg.MaxSkew)
+	}
+	return &ref, nil
+}
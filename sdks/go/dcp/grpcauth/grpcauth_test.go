@@ -0,0 +1,158 @@
+package grpcauth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcauth"
+)
+
+// seen records the identity and intent reference of the last admitted call.
+type seen struct {
+	id  *agentauth.Identity
+	ref *grpcauth.IntentReference
+}
+
+func (s *seen) unary(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	s.id, _ = agentauth.FromContext(ctx)
+	s.ref, _ = grpcauth.IntentFromContext(ctx)
+	return handler(ctx, req)
+}
+
+func (s *seen) stream(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	s.id, _ = agentauth.FromContext(ss.Context())
+	return handler(srv, ss)
+}
+
+// serve runs a health service behind in and returns a connection to it
+// through the client interceptors opts.
+func serve(t *testing.T, in *grpcauth.Interceptor, s *seen, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+	g := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(in.Unary(), s.unary),
+		grpc.ChainStreamInterceptor(in.Stream(), s.stream),
+	)
+	healthpb.RegisterHealthServer(g, health.NewServer())
+	return dcptest.Dial(t, g, opts...)
+}
+
+func newInterceptor(t *testing.T, cfg grpcauth.Config) *grpcauth.Interceptor {
+	t.Helper()
+	cfg.Authenticator = dcptest.Authenticator(t, agentauth.Config{})
+	in, err := grpcauth.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return in
+}
+
+func TestInterceptors(t *testing.T) {
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	intent := dcptest.Intent(t, p)
+	creds := &grpcauth.Credentials{Passport: p, Signer: signer}
+	s := &seen{}
+	conn := serve(t, newInterceptor(t, grpcauth.Config{}), s,
+		grpc.WithUnaryInterceptor(creds.Unary()), grpc.WithStreamInterceptor(creds.Stream()))
+	client := healthpb.NewHealthClient(conn)
+
+	ctx := grpcauth.WithIntent(context.Background(), intent)
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if s.id == nil || s.id.AgentID() != p.AgentID {
+		t.Fatalf("identity = %+v", s.id)
+	}
+	if s.ref == nil || !s.ref.Matches(intent) {
+		t.Fatalf("intent reference = %+v", s.ref)
+	}
+
+	s.id = nil
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if s.id == nil || s.id.AgentID() != p.AgentID {
+		t.Fatalf("stream identity = %+v", s.id)
+	}
+
+	anonymous := healthpb.NewHealthClient(serve(t, newInterceptor(t, grpcauth.Config{}), &seen{}))
+	_, err = anonymous.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	dcptest.WantCode(t, err, codes.Unauthenticated)
+}
+
+func TestIntentReference(t *testing.T) {
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	intent := dcptest.Intent(t, p)
+	_, otherSigner, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	now := time.Now()
+	in := newInterceptor(t, grpcauth.Config{RequireIntent: true, Now: func() time.Time { return now }})
+	call := func(creds *grpcauth.Credentials, withIntent bool) error {
+		t.Helper()
+		client := healthpb.NewHealthClient(serve(t, in, &seen{}, grpc.WithUnaryInterceptor(creds.Unary())))
+		ctx := context.Background()
+		if withIntent {
+			ctx = grpcauth.WithIntent(ctx, intent)
+		}
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+	if err := call(&grpcauth.Credentials{Passport: p, Signer: signer}, true); err != nil {
+		t.Fatal(err)
+	}
+	dcptest.WantCode(t, call(&grpcauth.Credentials{Passport: p, Signer: signer}, false), codes.Unauthenticated)
+	dcptest.WantCode(t, call(&grpcauth.Credentials{Passport: p, Signer: otherSigner}, true), codes.Unauthenticated)
+	stale := func() time.Time { return now.Add(-time.Hour) }
+	dcptest.WantCode(t, call(&grpcauth.Credentials{Passport: p, Signer: signer, Now: stale}, true), codes.Unauthenticated)
+}
+
+func TestPublicMethods(t *testing.T) {
+	in := newInterceptor(t, grpcauth.Config{Public: []string{healthpb.Health_Check_FullMethodName}})
+	client := healthpb.NewHealthClient(serve(t, in, &seen{}))
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	intent := dcptest.Intent(t, p)
+	s := &seen{}
+	downstream := healthpb.NewHealthClient(serve(t, newInterceptor(t, grpcauth.Config{RequireIntent: true}), s,
+		grpc.WithUnaryInterceptor(grpcauth.PropagateUnary())))
+
+	// The metadata an upstream service received from the agent.
+	ref, err := grpcauth.NewIntentReference(intent, signer, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	passport, err := agentauth.EncodePassport(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refJSON, _ := json.Marshal(ref)
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		grpcauth.PassportKey, passport,
+		grpcauth.IntentRefKey, base64.StdEncoding.EncodeToString(refJSON),
+	))
+	if _, err := downstream.Check(incoming, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if s.id == nil || s.id.AgentID() != p.AgentID || s.ref == nil || s.ref.IntentID != intent.IntentID {
+		t.Fatalf("downstream saw %+v, %+v", s.id, s.ref)
+	}
+}
@@ -0,0 +1,279 @@
+// Package grpcserver implements the dcp.v1 DcpService (see
+// api/proto/dcp.proto and package dcpv1) for deployments that consume DCP
+// over gRPC. Verification and revocation status reuse a verifyserver.Server,
+// so the REST and gRPC services of one deployment give the same answers.
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcpv1"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/verifyserver"
+)
+
+// PassportSource looks up agent passports. Passport returns nil and no
+// error for an unknown agent.
+type PassportSource interface {
+	Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error)
+}
+
+// PassportMap is a fixed PassportSource keyed by agent_id.
+type PassportMap map[string]dcp.AgentPassport
+
+// Passport implements PassportSource.
+func (m PassportMap) Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error) {
+	if p, ok := m[agentID]; ok {
+		return &p, nil
+	}
+	return nil, nil
+}
+
+// Config configures a Server.
+type Config struct {
+	// Verifier answers Verify and GetRevocationStatus; nil means a
+	// verifyserver with no trust anchors or revocation sources.
+	Verifier *verifyserver.Server
+	// Passports answers GetPassport and supplies the keys AppendAudit checks
+	// agent signatures with. Nil makes GetPassport unimplemented.
+	Passports PassportSource
+	// Ledger returns the ledger AppendAudit appends an agent's entries to.
+	// Nil makes AppendAudit unimplemented.
+	Ledger func(ctx context.Context, agentID string) (dcp.LedgerStore, error)
+	// RequireAgentSignature rejects audit entries without agent_signature.
+	RequireAgentSignature bool
+}
+
+// Server implements dcpv1.DcpServiceServer. Create one with New.
+type Server struct {
+	dcpv1.UnimplementedDcpServiceServer
+	cfg Config
+
+	// mu serializes appends to ledgers that cannot link entries atomically.
+	mu sync.Mutex
+}
+
+var _ dcpv1.DcpServiceServer = (*Server)(nil)
+
+// New returns a Server for cfg.
+func New(cfg Config) *Server {
+	if cfg.Verifier == nil {
+		cfg.Verifier = verifyserver.New(verifyserver.Config{})
+	}
+	return &Server{cfg: cfg}
+}
+
+// Register registers s on g.
+func (s *Server) Register(g grpc.ServiceRegistrar) {
+	dcpv1.RegisterDcpServiceServer(g, s)
+}
+
+// Verify implements dcpv1.DcpServiceServer.
+func (s *Server) Verify(ctx context.Context, req *dcpv1.VerifyRequest) (*dcpv1.VerifyResponse, error) {
+	rsb, err := dcp.ParseSignedBundle([]byte(req.GetSignedBundleJson()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	res, err := s.cfg.Verifier.Verify(ctx, rsb, req.GetExplain())
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	out := &dcpv1.VerifyResponse{
+		Verified:   res.Verified,
+		Errors:     res.Errors,
+		BundleHash: res.BundleHash,
+		AgentId:    res.AgentID,
+		HumanId:    res.HumanID,
+		SignerKey:  res.SignerKey,
+		Trusted:    res.Trusted,
+		Revocation: revocationMessage(res.Revocation),
+		CheckedAt:  res.CheckedAt,
+	}
+	for _, step := range res.Trace {
+		out.Trace = append(out.Trace, &dcpv1.TraceStep{
+			Check: step.Check, Target: step.Target, Ok: step.OK,
+			Expected: step.Expected, Actual: step.Actual, Detail: step.Detail,
+		})
+	}
+	return out, nil
+}
+
+// GetPassport implements dcpv1.DcpServiceServer.
+func (s *Server) GetPassport(ctx context.Context, req *dcpv1.GetPassportRequest) (*dcpv1.GetPassportResponse, error) {
+	if s.cfg.Passports == nil {
+		return nil, status.Error(codes.Unimplemented, "no passport source configured")
+	}
+	p, err := s.passport(ctx, req.GetAgentId())
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &dcpv1.GetPassportResponse{PassportJson: string(data)}, nil
+}
+
+// passport returns the passport of agentID or a NotFound status.
+func (s *Server) passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error) {
+	if agentID == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
+	}
+	p, err := s.cfg.Passports.Passport(ctx, agentID)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if p == nil {
+		return nil, status.Errorf(codes.NotFound, "no passport for %s", agentID)
+	}
+	return p, nil
+}
+
+// GetRevocationStatus implements dcpv1.DcpServiceServer.
+func (s *Server) GetRevocationStatus(ctx context.Context, req *dcpv1.GetRevocationStatusRequest) (*dcpv1.GetRevocationStatusResponse, error) {
+	if req.GetAgentId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
+	}
+	rec, err := s.cfg.Verifier.Revocation(ctx, req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &dcpv1.GetRevocationStatusResponse{Revoked: rec != nil, Record: revocationMessage(rec)}, nil
+}
+
+// AppendAudit implements dcpv1.DcpServiceServer. The entry must validate,
+// its agent must not be revoked and, when present, its agent_signature must
+// verify against the agent's passport key.
+func (s *Server) AppendAudit(ctx context.Context, req *dcpv1.AppendAuditRequest) (*dcpv1.AppendAuditResponse, error) {
+	if s.cfg.Ledger == nil {
+		return nil, status.Error(codes.Unimplemented, "no audit ledger configured")
+	}
+	var entry dcp.AuditEntry
+	if err := json.Unmarshal([]byte(req.GetEntryJson()), &entry); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "entry_json: %v", err)
+	}
+	if err := entry.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.checkAgentSignature(ctx, &entry); err != nil {
+		return nil, err
+	}
+	rec, err := s.cfg.Verifier.Revocation(ctx, entry.AgentID)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if rec != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "agent %s was revoked at %s", entry.AgentID, rec.Timestamp)
+	}
+
+	store, err := s.cfg.Ledger(ctx, entry.AgentID)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	idx, err := s.appendLinked(ctx, store, entry)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := dcp.HashObject(entry)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &dcpv1.AppendAuditResponse{Index: idx, Hash: hash}, nil
+}
+
+func (s *Server) checkAgentSignature(ctx context.Context, entry *dcp.AuditEntry) error {
+	if entry.AgentSignature == "" {
+		if s.cfg.RequireAgentSignature {
+			return status.Error(codes.InvalidArgument, "agent_signature is required")
+		}
+		return nil
+	}
+	if s.cfg.Passports == nil {
+		if s.cfg.RequireAgentSignature {
+			return status.Error(codes.FailedPrecondition, "no passport source to check agent_signature with")
+		}
+		return nil
+	}
+	p, err := s.passport(ctx, entry.AgentID)
+	if err != nil {
+		return err
+	}
+	if ok, err := entry.VerifyAgentSignature(p.PublicKey); err != nil || !ok {
+		return status.Errorf(codes.InvalidArgument, "agent_signature does not verify against the passport of %s", entry.AgentID)
+	}
+	return nil
+}
+
+// errStaleHead reports an entry that does not extend the ledger head.
+var errStaleHead = errors.New("stale prev_hash")
+
+// appendLinked appends entry if its prev_hash is the ledger head.
+func (s *Server) appendLinked(ctx context.Context, store dcp.LedgerStore, entry dcp.AuditEntry) (int64, error) {
+	want := entry.PrevHash
+	var head string
+	var idx int64
+	var err error
+	if ca, ok := store.(dcp.ChainAppender); ok {
+		idx, _, err = ca.AppendChained(ctx, entry, func(e *dcp.AuditEntry) error {
+			if head = e.PrevHash; head != want {
+				return errStaleHead
+			}
+			return nil
+		})
+	} else {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if head, err = ledgerHead(ctx, store); err == nil {
+			if head != want {
+				err = errStaleHead
+			} else {
+				idx, err = store.AppendEntry(ctx, entry)
+			}
+		}
+	}
+	switch {
+	case errors.Is(err, errStaleHead):
+		return 0, status.Errorf(codes.FailedPrecondition, "prev_hash %s is not the ledger head %s", want, head)
+	case err != nil:
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+	return idx, nil
+}
+
+// ledgerHead returns the prev_hash the next entry of store must carry.
+func ledgerHead(ctx context.Context, store dcp.LedgerStore) (string, error) {
+	n, err := store.Len(ctx)
+	if err != nil {
+		return "", err
+	}
+	anchor, err := dcp.LedgerChainAnchor(ctx, store, n)
+	if err != nil {
+		return "", fmt.Errorf("ledger head: %w", err)
+	}
+	if anchor == nil {
+		return "GENESIS", nil
+	}
+	return anchor.PrevEntryHash, nil
+}
+
+func revocationMessage(r *dcp.RevocationRecord) *dcpv1.RevocationRecord {
+	if r == nil {
+		return nil
+	}
+	return &dcpv1.RevocationRecord{
+		DcpVersion: r.DCPVersion,
+		AgentId:    r.AgentID,
+		HumanId:    r.HumanID,
+		Timestamp:  r.Timestamp,
+		Reason:     r.Reason,
+		Signature:  r.Signature,
+	}
+}
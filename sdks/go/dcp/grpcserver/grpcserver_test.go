@@ -0,0 +1,166 @@
+package grpcserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcpv1"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/fileledger"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/verifyserver"
+)
+
+// dial serves srv over an in-memory connection and returns a client.
+func dial(t *testing.T, srv *grpcserver.Server) dcpv1.DcpServiceClient {
+	t.Helper()
+	g := grpc.NewServer()
+	srv.Register(g)
+	return dcpv1.NewDcpServiceClient(dcptest.Dial(t, g))
+}
+
+func TestVerifyAndRevocationStatus(t *testing.T) {
+	ctx := context.Background()
+	signed := dcptest.ReadExample(t, "citizenship_bundle.signed.json", nil)
+	revocations := &dcp.RevocationList{}
+	client := dial(t, grpcserver.New(grpcserver.Config{
+		Verifier: verifyserver.New(verifyserver.Config{Revocations: []dcp.RevocationChecker{revocations}}),
+	}))
+
+	res, err := client.Verify(ctx, &dcpv1.VerifyRequest{SignedBundleJson: string(signed), Explain: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Verified || res.AgentId != "did:agent:agent123" || len(res.Trace) == 0 || !res.Trace[1].Ok {
+		t.Fatalf("Verify = %v", res)
+	}
+	_, err = client.Verify(ctx, &dcpv1.VerifyRequest{SignedBundleJson: "{"})
+	dcptest.WantCode(t, err, codes.InvalidArgument)
+
+	st, err := client.GetRevocationStatus(ctx, &dcpv1.GetRevocationStatusRequest{AgentId: "did:agent:agent123"})
+	if err != nil || st.Revoked {
+		t.Fatalf("GetRevocationStatus = %v, %v", st, err)
+	}
+	revocations.Add(dcp.NewRevocationRecord("did:agent:agent123", "did:human:alice123", "key lost"))
+	st, err = client.GetRevocationStatus(ctx, &dcpv1.GetRevocationStatusRequest{AgentId: "did:agent:agent123"})
+	if err != nil || !st.Revoked || st.Record.GetReason() != "key lost" {
+		t.Fatalf("GetRevocationStatus = %v, %v", st, err)
+	}
+	res, err = client.Verify(ctx, &dcpv1.VerifyRequest{SignedBundleJson: string(signed)})
+	if err != nil || res.Verified || res.Revocation.GetReason() != "key lost" {
+		t.Fatalf("Verify of revoked agent = %v, %v", res, err)
+	}
+	_, err = client.GetRevocationStatus(ctx, &dcpv1.GetRevocationStatusRequest{})
+	dcptest.WantCode(t, err, codes.InvalidArgument)
+}
+
+func TestGetPassport(t *testing.T) {
+	ctx := context.Background()
+	_, err := dial(t, grpcserver.New(grpcserver.Config{})).GetPassport(ctx, &dcpv1.GetPassportRequest{AgentId: "did:agent:agent123"})
+	dcptest.WantCode(t, err, codes.Unimplemented)
+
+	var p dcp.AgentPassport
+	dcptest.ReadExample(t, "agent_passport.json", &p)
+	client := dial(t, grpcserver.New(grpcserver.Config{Passports: grpcserver.PassportMap{p.AgentID: p}}))
+	res, err := client.GetPassport(ctx, &dcpv1.GetPassportRequest{AgentId: p.AgentID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got dcp.AgentPassport
+	if err := json.Unmarshal([]byte(res.PassportJson), &got); err != nil || got.PublicKey != p.PublicKey || got.Signature != p.Signature {
+		t.Fatalf("GetPassport = %s, %v", res.PassportJson, err)
+	}
+	_, err = client.GetPassport(ctx, &dcpv1.GetPassportRequest{AgentId: "did:agent:unknown"})
+	dcptest.WantCode(t, err, codes.NotFound)
+}
+
+func TestAppendAudit(t *testing.T) {
+	ctx := context.Background()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	dcptest.ReadExample(t, "intent.json", &intent)
+	passports := grpcserver.PassportMap{intent.AgentID: {AgentID: intent.AgentID, PublicKey: kp.PublicKeyB64}}
+
+	chain := dcp.NewAuditChain(dcp.AuditChainOptions{AgentSigner: signer})
+	for _, outcome := range []string{"sent", "archived"} {
+		if _, err := chain.Append(dcp.AuditEntryFields{Intent: intent, PolicyDecision: dcp.OutcomeApproved, Outcome: outcome}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries := chain.Entries()
+	entryJSON := func(e dcp.AuditEntry) string {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// MemoryLedger links entries atomically; fileledger goes through the
+	// server's own lock.
+	fileStore, err := fileledger.Open(filepath.Join(t.TempDir(), "ledger.log"), fileledger.Options{NoSync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fileStore.Close()
+	for name, store := range map[string]dcp.LedgerStore{"memory": dcp.NewMemoryLedger(), "file": fileStore} {
+		t.Run(name, func(t *testing.T) {
+			revocations := &dcp.RevocationList{}
+			client := dial(t, grpcserver.New(grpcserver.Config{
+				Verifier:              verifyserver.New(verifyserver.Config{Revocations: []dcp.RevocationChecker{revocations}}),
+				Passports:             passports,
+				Ledger:                func(context.Context, string) (dcp.LedgerStore, error) { return store, nil },
+				RequireAgentSignature: true,
+			}))
+			for i, e := range entries {
+				res, err := client.AppendAudit(ctx, &dcpv1.AppendAuditRequest{EntryJson: entryJSON(e)})
+				if err != nil {
+					t.Fatalf("entry %d: %v", i, err)
+				}
+				if want, _ := dcp.HashObject(e); res.Index != int64(i) || res.Hash != want {
+					t.Fatalf("entry %d: %v", i, res)
+				}
+			}
+			_, err := client.AppendAudit(ctx, &dcpv1.AppendAuditRequest{EntryJson: entryJSON(entries[0])})
+			dcptest.WantCode(t, err, codes.FailedPrecondition)
+
+			tampered := entries[1]
+			tampered.Outcome = "deleted"
+			_, err = client.AppendAudit(ctx, &dcpv1.AppendAuditRequest{EntryJson: entryJSON(tampered)})
+			dcptest.WantCode(t, err, codes.InvalidArgument)
+			unsigned := entries[1]
+			unsigned.AgentSignature = ""
+			_, err = client.AppendAudit(ctx, &dcpv1.AppendAuditRequest{EntryJson: entryJSON(unsigned)})
+			dcptest.WantCode(t, err, codes.InvalidArgument)
+
+			revocations.Add(dcp.NewRevocationRecord(intent.AgentID, intent.HumanID, "retired"))
+			_, err = client.AppendAudit(ctx, &dcpv1.AppendAuditRequest{EntryJson: entryJSON(entries[1])})
+			dcptest.WantCode(t, err, codes.PermissionDenied)
+
+			if n, err := store.Len(ctx); err != nil || n != int64(len(entries)) {
+				t.Fatalf("ledger has %d entries, %v", n, err)
+			}
+		})
+	}
+}
+
+func TestAppendAuditUnconfigured(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := dial(t, grpcserver.New(grpcserver.Config{})).AppendAudit(ctx, &dcpv1.AppendAuditRequest{EntryJson: "{}"})
+	dcptest.WantCode(t, err, codes.Unimplemented)
+}
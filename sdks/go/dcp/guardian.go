@@ -0,0 +1,136 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrGuardianInvalid is returned for an agent of a dependent principal
+// whose passport is not countersigned by the principal's guardian, or
+// claims capabilities beyond DependentCapabilities.
+var ErrGuardianInvalid = errors.New("guardian countersignature invalid")
+
+// DependentCapabilities are the capabilities an agent of a dependent
+// principal, one with a guardian, may hold. They are the default of
+// NewDependentAgentPassport.
+var DependentCapabilities = []string{"browse", "calendar", "email"}
+
+// NewDependentAgentPassport returns an unsigned, active passport of an agent
+// of principal, a dependent with a guardian. A nil capabilities defaults to
+// DependentCapabilities; others must be covered by them, such as
+// "email:send" or "calendar:*". The agent signs the
+// passport, then the guardian countersigns it with GuardianCountersign.
+func (f *RecordFactory) NewDependentAgentPassport(principal *ResponsiblePrincipalRecord, publicKeyB64 string, capabilities []string) (AgentPassport, error) {
+	if principal.Guardian == nil {
+		return AgentPassport{}, fmt.Errorf("dependent passport: principal %s has no guardian", principal.HumanID)
+	}
+	if capabilities == nil {
+		capabilities = cloneStrings(DependentCapabilities)
+	}
+	if err := checkDependentCapabilities(capabilities); err != nil {
+		return AgentPassport{}, fmt.Errorf("dependent passport: %w", err)
+	}
+	return f.NewAgentPassport(principal.HumanID, publicKeyB64, capabilities, RiskTierLow), nil
+}
+
+// NewDependentAgentPassport calls RecordFactory.NewDependentAgentPassport
+// with the package clock and entropy.
+func NewDependentAgentPassport(principal *ResponsiblePrincipalRecord, publicKeyB64 string, capabilities []string) (AgentPassport, error) {
+	return defaultRecords.NewDependentAgentPassport(principal, publicKeyB64, capabilities)
+}
+
+// GuardianCountersign sets GuardianSignature to the guardian s's signature
+// over the signed passport without a guardian signature.
+func (p *AgentPassport) GuardianCountersign(s BundleSigner) error {
+	if p.Signature == "" {
+		return fmt.Errorf("guardian countersign agent_passport %s: not signed by the agent", p.AgentID)
+	}
+	p.GuardianSignature = ""
+	sig, err := signWith(s, p)
+	if err != nil {
+		return fmt.Errorf("guardian countersign agent_passport %s: %w", p.AgentID, err)
+	}
+	p.GuardianSignature = sig
+	return nil
+}
+
+// VerifyGuardianSignature checks GuardianSignature against the guardian's
+// public key.
+func (p *AgentPassport) VerifyGuardianSignature(guardianKeyB64 string) (bool, error) {
+	if p.GuardianSignature == "" {
+		return false, fmt.Errorf("agent_passport %s has no guardian signature", p.AgentID)
+	}
+	unsigned := *p
+	unsigned.GuardianSignature = ""
+	return VerifyObject(unsigned, p.GuardianSignature, guardianKeyB64)
+}
+
+// CheckGuardian reports whether passport meets principal's guardian
+// binding: it is countersigned with guardianKeyB64, the guardian's key as
+// published outside the bundle, and holds no capability
+// DependentCapabilities do not cover. The binding in principal must name
+// that key, and a binding with no key given, or a key with no binding,
+// fails: either may have been rewritten along with the passport. With
+// neither, there is nothing to check. Errors wrap ErrGuardianInvalid.
+func CheckGuardian(principal *ResponsiblePrincipalRecord, passport *AgentPassport, guardianKeyB64 string) error {
+	g := principal.Guardian
+	switch {
+	case g == nil && guardianKeyB64 == "":
+		return nil
+	case g == nil:
+		return fmt.Errorf("%w: %s of dependent %s: the guardian binding is missing from the bundle", ErrGuardianInvalid, passport.AgentID, principal.HumanID)
+	case guardianKeyB64 == "":
+		return fmt.Errorf("%w: %s of dependent %s: the guardian is declared only in the bundle, and no guardian key is given for it", ErrGuardianInvalid, passport.AgentID, principal.HumanID)
+	case g.GuardianPublicKey != guardianKeyB64:
+		return fmt.Errorf("%w: %s of dependent %s: the guardian key in the bundle is not the anchored one", ErrGuardianInvalid, passport.AgentID, principal.HumanID)
+	}
+	if ok, err := passport.VerifyGuardianSignature(guardianKeyB64); err != nil || !ok {
+		if err == nil {
+			err = fmt.Errorf("the guardian signature does not verify with %s's key", g.GuardianID)
+		}
+		return fmt.Errorf("%w: %s of dependent %s: %v", ErrGuardianInvalid, passport.AgentID, principal.HumanID, err)
+	}
+	if err := checkDependentCapabilities(passport.Capabilities); err != nil {
+		return fmt.Errorf("%w: %s of dependent %s: %v", ErrGuardianInvalid, passport.AgentID, principal.HumanID, err)
+	}
+	return nil
+}
+
+// checkDependentCapabilities checks capabilities against
+// DependentCapabilities as the capability grammar matches them, so that
+// "email" admits "email:send".
+func checkDependentCapabilities(capabilities []string) error {
+	if err := CheckAttenuation(DependentCapabilities, capabilities); err != nil {
+		return fmt.Errorf("not allowed to a dependent's agent: %v", err)
+	}
+	return nil
+}
+
+// Validate checks g against the principal schema and returns
+// ValidationErrors listing every violation, or nil.
+func (g *GuardianBinding) Validate() error {
+	v := newValidator()
+	g.validate(v)
+	return v.err()
+}
+
+func (g *GuardianBinding) validate(v validator) {
+	v.idOf("guardian_id", g.GuardianID, IDKindHuman)
+	v.publicKey("guardian_public_key", g.GuardianPublicKey)
+	v.enum("relationship", string(g.Relationship), guardianRelationships)
+	v.minLen("legal_basis", g.LegalBasis, 1)
+}
+
+// Clone returns a copy of g.
+func (g *GuardianBinding) Clone() *GuardianBinding {
+	if g == nil {
+		return nil
+	}
+	c := *g
+	return &c
+}
+
+// Equal reports whether g and o canonicalize identically.
+func (g *GuardianBinding) Equal(o *GuardianBinding) bool {
+	return (g == nil) == (o == nil) && (g == nil || canonicalEqual(g, o))
+}
@@ -0,0 +1,185 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestGuardianCountersignature(t *testing.T) {
+	guardianKey, _ := dcp.GenerateKeypair()
+	guardian, _ := dcp.NewKeySigner(guardianKey.SecretKeyB64)
+	agentKey, _ := dcp.GenerateKeypair()
+	agent, _ := dcp.NewKeySigner(agentKey.SecretKeyB64)
+	principal := dcp.ResponsiblePrincipalRecord{DCPVersion: "1.0", HumanID: "human001", LegalName: "Sam",
+		EntityType: "natural_person", Jurisdiction: "US", LiabilityMode: "owner_responsible", IssuedAt: "2026-01-01T00:00:00Z",
+		Guardian: &dcp.GuardianBinding{GuardianID: "human002", GuardianPublicKey: guardianKey.PublicKeyB64,
+			Relationship: dcp.GuardianParent, LegalBasis: "birth certificate"}}
+	if err := principal.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := dcp.NewDependentAgentPassport(&principal, agentKey.PublicKeyB64, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(p.Capabilities, ",") != "browse,calendar,email" {
+		t.Fatalf("capabilities = %v", p.Capabilities)
+	}
+	if _, err := dcp.NewDependentAgentPassport(&principal, agentKey.PublicKeyB64, []string{"payments"}); err == nil {
+		t.Fatal("payments allowed to a dependent's agent")
+	}
+	scoped, err := dcp.NewDependentAgentPassport(&principal, agentKey.PublicKeyB64, []string{"email:send", "calendar:*", "browse:read:*.example.com"})
+	if err != nil {
+		t.Fatalf("capabilities within the dependent's: %v", err)
+	}
+	if err := scoped.Sign(agent); err != nil {
+		t.Fatal(err)
+	}
+	if err := scoped.GuardianCountersign(guardian); err != nil {
+		t.Fatal(err)
+	}
+	if err := dcp.CheckGuardian(&principal, &scoped, guardianKey.PublicKeyB64); err != nil {
+		t.Fatalf("scoped capabilities: %v", err)
+	}
+	if err := p.GuardianCountersign(guardian); err == nil {
+		t.Fatal("countersigned before the agent signed")
+	}
+	if err := p.Sign(agent); err != nil {
+		t.Fatal(err)
+	}
+	if err := dcp.CheckGuardian(&principal, &p, guardianKey.PublicKeyB64); !errors.Is(err, dcp.ErrGuardianInvalid) || !strings.Contains(err.Error(), "no guardian signature") {
+		t.Fatalf("without the countersignature: %v", err)
+	}
+	if err := p.GuardianCountersign(guardian); err != nil {
+		t.Fatal(err)
+	}
+	if err := dcp.CheckGuardian(&principal, &p, guardianKey.PublicKeyB64); err != nil {
+		t.Fatal(err)
+	}
+	// The agent's signature does not cover the countersignature.
+	if ok, err := p.VerifySignature(); !ok || err != nil {
+		t.Fatalf("agent signature: %v, %v", ok, err)
+	}
+
+	widened := p
+	widened.Capabilities = []string{"browse", "payments"}
+	if err := widened.Sign(agent); err != nil {
+		t.Fatal(err)
+	}
+	if err := widened.GuardianCountersign(guardian); err != nil {
+		t.Fatal(err)
+	}
+	if err := dcp.CheckGuardian(&principal, &widened, guardianKey.PublicKeyB64); !errors.Is(err, dcp.ErrGuardianInvalid) || !strings.Contains(err.Error(), "payments") {
+		t.Fatalf("payments: %v", err)
+	}
+	selfSigned := p
+	if err := selfSigned.GuardianCountersign(agent); err != nil {
+		t.Fatal(err)
+	}
+	if err := dcp.CheckGuardian(&principal, &selfSigned, guardianKey.PublicKeyB64); !errors.Is(err, dcp.ErrGuardianInvalid) {
+		t.Fatalf("countersigned by the agent: %v", err)
+	}
+	// The binding and countersignature rewritten to the agent's key: the
+	// anchored key is the one checked.
+	rebound := principal
+	rebound.Guardian = principal.Guardian.Clone()
+	rebound.Guardian.GuardianPublicKey = agentKey.PublicKeyB64
+	if err := dcp.CheckGuardian(&rebound, &selfSigned, guardianKey.PublicKeyB64); !errors.Is(err, dcp.ErrGuardianInvalid) || !strings.Contains(err.Error(), "not the anchored one") {
+		t.Fatalf("rebound to the agent's key: %v", err)
+	}
+	if err := dcp.CheckGuardian(&rebound, &selfSigned, ""); !errors.Is(err, dcp.ErrGuardianInvalid) || !strings.Contains(err.Error(), "declared only in the bundle") {
+		t.Fatalf("no guardian key given: %v", err)
+	}
+	unbound := principal
+	unbound.Guardian = nil
+	if err := dcp.CheckGuardian(&unbound, &widened, guardianKey.PublicKeyB64); !errors.Is(err, dcp.ErrGuardianInvalid) || !strings.Contains(err.Error(), "missing from the bundle") {
+		t.Fatalf("guardian binding dropped: %v", err)
+	}
+	if err := dcp.CheckGuardian(&unbound, &widened, ""); err != nil {
+		t.Fatalf("no guardian: %v", err)
+	}
+
+	principal.Guardian.GuardianID = "human001"
+	principal.Guardian.Relationship = "friend"
+	var verrs dcp.ValidationErrors
+	if err := principal.Validate(); !errors.As(err, &verrs) || len(verrs) != 2 {
+		t.Fatalf("invalid guardian: %v", err)
+	}
+}
+
+func TestVerifyGuardian(t *testing.T) {
+	b, human, agentKey := builderFixture(t)
+	guardianKey, _ := dcp.GenerateKeypair()
+	guardian, _ := dcp.NewKeySigner(guardianKey.SecretKeyB64)
+	agent, _ := dcp.NewKeySigner(agentKey.SecretKeyB64)
+	b.ResponsiblePrincipalRecord(dcp.ResponsiblePrincipalRecord{DCPVersion: "1.0", HumanID: "human001",
+		LegalName: "Sam", EntityType: "natural_person", Jurisdiction: "US",
+		LiabilityMode: "owner_responsible", IssuedAt: "2026-01-01T00:00:00Z",
+		Guardian: &dcp.GuardianBinding{GuardianID: "human002", GuardianPublicKey: guardianKey.PublicKeyB64,
+			Relationship: dcp.GuardianLegalGuardian, LegalBasis: "court order 2025-FC-118"}})
+	p := dcp.AgentPassport{DCPVersion: "1.0", AgentID: "agent001", PrincipalBindingReference: "human001",
+		Capabilities: []string{"email"}, CreatedAt: "2026-01-01T00:10:00Z", Status: "active"}
+	if err := p.Sign(agent); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.GuardianCountersign(guardian); err != nil {
+		t.Fatal(err)
+	}
+	principal, _ := dcp.NewKeySigner(human.SecretKeyB64)
+	sign := func(p dcp.AgentPassport) *dcp.SignedBundle {
+		t.Helper()
+		bundle, err := b.AgentPassport(p).Bundle()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb, err := dcp.SignBundle(bundle, principal, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+
+	opts := dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, Guardians: map[string]string{"human001": guardianKey.PublicKeyB64}}
+	explained := opts
+	explained.Explain = true
+	res := dcp.VerifySignedBundleWithOptions(sign(p), explained)
+	if !res.Verified {
+		t.Fatalf("countersigned passport: %v", res.Errors)
+	}
+	if last := res.Trace[len(res.Trace)-1]; last.Check != dcp.TraceGuardian || !last.OK || last.Detail != "legal_guardian human002" {
+		t.Fatalf("trace: %+v", last)
+	}
+	data, _ := json.Marshal(sign(p))
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundleWithOptions(rsb, opts); !res.Verified {
+		t.Fatalf("raw bundle: %v", res.Errors)
+	}
+	if res := dcp.VerifySignedBundle(sign(p), human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "declared only in the bundle") {
+		t.Fatalf("no guardian key given: %+v", res)
+	}
+	unsigned := p
+	unsigned.GuardianSignature = ""
+	if res := dcp.VerifySignedBundleWithOptions(sign(unsigned), opts); res.Verified || !strings.Contains(res.Errors[0], "no guardian signature") {
+		t.Fatalf("without the countersignature: %+v", res)
+	}
+
+	// A principal rewritten without its guardian, re-signed by the
+	// principal's key, still fails against the anchored guardian.
+	b.ResponsiblePrincipalRecord(dcp.ResponsiblePrincipalRecord{DCPVersion: "1.0", HumanID: "human001",
+		LegalName: "Sam", EntityType: "natural_person", Jurisdiction: "US",
+		LiabilityMode: "owner_responsible", IssuedAt: "2026-01-01T00:00:00Z"})
+	if res := dcp.VerifySignedBundleWithOptions(sign(p), opts); res.Verified || !strings.Contains(res.Errors[0], "missing from the bundle") {
+		t.Fatalf("guardian dropped: %+v", res)
+	}
+	if res := dcp.VerifySignedBundle(sign(p), human.PublicKeyB64); !res.Verified {
+		t.Fatalf("no guardian either way: %v", res.Errors)
+	}
+}
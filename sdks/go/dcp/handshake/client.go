@@ -0,0 +1,100 @@
+package handshake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Client performs the handshake with a Server on behalf of an agent.
+type Client struct {
+	// URL is the base URL the Server is mounted at, e.g.
+	// "https://shop.example".
+	URL string
+	// Audience is the site's ID the proof names; empty means the origin
+	// of URL, which is what a Server mounted there expects by default.
+	Audience string
+	Passport *dcp.AgentPassport
+	// Signer is the agent key the passport carries.
+	Signer dcp.BundleSigner
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Authenticate asks for a challenge, answers it and returns the site's
+// result. A refused response is an error carrying the site's message.
+func (c *Client) Authenticate(ctx context.Context) (*Result, error) {
+	if c.Passport == nil || c.Signer == nil {
+		return nil, errors.New("handshake: a passport and a signer are required")
+	}
+	audience := c.Audience
+	if audience == "" {
+		u, err := url.Parse(c.URL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("handshake: URL %q has no origin", c.URL)
+		}
+		audience = u.Scheme + "://" + u.Host
+	}
+	var ch Challenge
+	if err := c.post(ctx, "/handshake/challenge", nil, &ch); err != nil {
+		return nil, err
+	}
+	resp, err := Respond(&ch, audience, c.Passport, c.Signer)
+	if err != nil {
+		return nil, fmt.Errorf("handshake: %v", err)
+	}
+	var result Result
+	if err := c.post(ctx, "/handshake/response", resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(c.URL, "/")+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &e) == nil && e.Error != "" {
+			return fmt.Errorf("handshake: %s: %d %s", path, resp.StatusCode, e.Error)
+		}
+		return fmt.Errorf("handshake: %s: %s", path, resp.Status)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("handshake: %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package handshake_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/handshake"
+)
+
+func TestClient(t *testing.T) {
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	_, otherSigner, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	suspended, suspendedSigner, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	suspended.Status = dcp.StatusSuspended
+	if err := suspended.Sign(suspendedSigner); err != nil {
+		t.Fatal(err)
+	}
+	// The server's audience is its origin, as the client derives it.
+	var srv *handshake.Server
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { srv.ServeHTTP(w, r) }))
+	defer ts.Close()
+	srv = newServer(t, handshake.Config{Audience: ts.URL}, agentauth.Config{})
+	ctx := context.Background()
+
+	result, err := (&handshake.Client{URL: ts.URL + "/", Passport: p, Signer: signer}).Authenticate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Authenticated || result.AgentID != p.AgentID || result.HumanID != p.PrincipalBindingReference {
+		t.Fatalf("result %+v", result)
+	}
+
+	_, err = (&handshake.Client{URL: ts.URL, Audience: site, Passport: p, Signer: signer}).Authenticate(ctx)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("another audience: %v", err)
+	}
+	_, err = (&handshake.Client{URL: ts.URL, Passport: p, Signer: otherSigner}).Authenticate(ctx)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("wrong key: %v", err)
+	}
+	_, err = (&handshake.Client{URL: ts.URL, Passport: suspended, Signer: suspendedSigner}).Authenticate(ctx)
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("suspended agent: %v", err)
+	}
+
+	resp, err := http.Post(ts.URL+"/handshake/response", "application/json", strings.NewReader("{"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid JSON: %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,311 @@
+// Package handshake authenticates a live agent by challenge and response,
+// so that a passport or signed bundle copied from an earlier exchange is
+// useless to whoever copied it.
+//
+// The verifying site issues a single-use nonce. The agent answers with its
+// passport and a Proof: the nonce, the site it means to answer and the
+// passport's hash, signed with the passport key. The site checks the proof
+// under the passport key, that it names the site, and the passport itself,
+// status and revocation included, with an agentauth.Authenticator. Only a
+// caller holding the agent key can answer a nonce, each nonce is answered
+// once, and a site cannot relay another site's challenge to an agent and
+// pass off the answer as its own.
+//
+// Server serves the handshake over HTTP and Client drives it:
+//
+//	POST /handshake/challenge   {"nonce": "...", "expires_at": "..."}
+//	POST /handshake/response    body: a Response; answers a Result
+//
+// Sites using another transport call Server.Challenge and Server.Verify
+// directly, and agents answer with Respond.
+package handshake
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+)
+
+// Defaults for the zero Config fields.
+const (
+	DefaultTTL        = time.Minute
+	DefaultMaxPending = 10000
+)
+
+var (
+	// ErrUnknownNonce reports a response to a nonce that was not issued,
+	// has expired, or was already answered.
+	ErrUnknownNonce = errors.New("unknown or expired handshake nonce")
+	// ErrInvalidProof reports a proof that is malformed, for another
+	// passport or verifier, or not signed by the passport key.
+	ErrInvalidProof = errors.New("invalid handshake proof")
+	// ErrBusy reports that MaxPending challenges are outstanding.
+	ErrBusy = errors.New("too many outstanding handshake challenges")
+)
+
+// Challenge is a nonce issued to an agent.
+type Challenge struct {
+	Nonce     string `json:"nonce"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// Proof answers a challenge.
+type Proof struct {
+	Nonce string `json:"nonce"`
+	// Audience is the verifier the agent answers, as the agent knows it:
+	// the site's origin, such as "https://shop.example", or another ID.
+	Audience     string `json:"audience"`
+	AgentID      string `json:"agent_id"`
+	PassportHash string `json:"passport_hash"`
+	// Signature is by the agent key over the canonical proof with an empty
+	// signature.
+	Signature string `json:"signature"`
+}
+
+// NewProof returns the proof answering nonce from audience for passport p,
+// signed with its key s.
+func NewProof(nonce, audience string, p *dcp.AgentPassport, s dcp.BundleSigner) (*Proof, error) {
+	if audience == "" {
+		return nil, errors.New("a proof needs an audience")
+	}
+	hash, err := dcp.HashObject(p)
+	if err != nil {
+		return nil, err
+	}
+	proof := &Proof{Nonce: nonce, Audience: audience, AgentID: p.AgentID, PassportHash: hash}
+	canon, err := dcp.Canonicalize(proof)
+	if err != nil {
+		return nil, err
+	}
+	if proof.Signature, err = s.SignCanonical(canon); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// Verify checks that the proof answers audience, is for passport p and is
+// signed by its key.
+func (pr *Proof) Verify(p *dcp.AgentPassport, audience string) error {
+	if pr.Audience != audience {
+		return fmt.Errorf("proof is for %q, not %q", pr.Audience, audience)
+	}
+	if pr.AgentID != p.AgentID {
+		return fmt.Errorf("proof is by %s, not %s", pr.AgentID, p.AgentID)
+	}
+	hash, err := dcp.HashObject(p)
+	if err != nil {
+		return err
+	}
+	if pr.PassportHash != hash {
+		return errors.New("proof is for another passport")
+	}
+	if pr.Signature == "" {
+		return errors.New("proof has no signature")
+	}
+	unsigned := *pr
+	unsigned.Signature = ""
+	if ok, err := dcp.VerifyObject(unsigned, pr.Signature, p.PublicKey); err != nil || !ok {
+		return errors.New("proof signature does not verify under the passport key")
+	}
+	return nil
+}
+
+// Response is an agent's answer to a challenge.
+type Response struct {
+	Passport dcp.AgentPassport `json:"passport"`
+	Proof    Proof             `json:"proof"`
+}
+
+// Respond returns the response of the agent with passport p and key s to
+// ch, issued by audience. The agent must take audience from what it knows
+// of the site it is talking to, such as the origin it connected to, and
+// never from the challenge.
+func Respond(ch *Challenge, audience string, p *dcp.AgentPassport, s dcp.BundleSigner) (*Response, error) {
+	proof, err := NewProof(ch.Nonce, audience, p, s)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Passport: *p, Proof: *proof}, nil
+}
+
+// Result is the answer to an accepted response.
+type Result struct {
+	Authenticated    bool   `json:"authenticated"`
+	AgentID          string `json:"agent_id"`
+	HumanID          string `json:"human_id"`
+	CertificateBound bool   `json:"certificate_bound"`
+}
+
+// Config configures a Server.
+type Config struct {
+	// Authenticator checks the responding agent's passport. Required.
+	Authenticator *agentauth.Authenticator
+	// Audience is the site's origin, such as "https://shop.example", or
+	// another ID agents know it by; proofs must name it. Required.
+	Audience string
+	// TTL is how long a challenge may be answered; zero means DefaultTTL.
+	TTL time.Duration
+	// MaxPending bounds the outstanding challenges; zero means
+	// DefaultMaxPending.
+	MaxPending int
+	// MaxBodyBytes bounds response bodies; zero means 1 MiB.
+	MaxBodyBytes int64
+	// Now is the clock challenges expire by; nil means time.Now.
+	Now func() time.Time
+	// Rand is the source of nonces; nil means the package entropy source
+	// (see dcp.SetEntropy).
+	Rand io.Reader
+}
+
+// Server issues challenges and verifies responses. Create one with New.
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+
+	mu      sync.Mutex
+	pending map[string]time.Time
+}
+
+// New returns a Server for cfg.
+func New(cfg Config) (*Server, error) {
+	if cfg.Authenticator == nil {
+		return nil, errors.New("handshake: an authenticator is required")
+	}
+	if cfg.Audience == "" {
+		return nil, errors.New("handshake: an audience is required")
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+	if cfg.MaxPending <= 0 {
+		cfg.MaxPending = DefaultMaxPending
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 1 << 20
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	s := &Server{cfg: cfg, mux: http.NewServeMux(), pending: map[string]time.Time{}}
+	s.mux.HandleFunc("POST /handshake/challenge", s.handleChallenge)
+	s.mux.HandleFunc("POST /handshake/response", s.handleResponse)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Challenge issues a nonce.
+func (s *Server) Challenge() (*Challenge, error) {
+	r := s.cfg.Rand
+	if r == nil {
+		r = dcp.Entropy()
+	}
+	raw := make([]byte, 32)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return nil, fmt.Errorf("handshake: nonce: %w", err)
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(raw)
+	now := s.cfg.Now()
+	expires := now.Add(s.cfg.TTL)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) >= s.cfg.MaxPending {
+		for n, exp := range s.pending {
+			if !now.Before(exp) {
+				delete(s.pending, n)
+			}
+		}
+		if len(s.pending) >= s.cfg.MaxPending {
+			return nil, ErrBusy
+		}
+	}
+	s.pending[nonce] = expires
+	return &Challenge{Nonce: nonce, ExpiresAt: dcp.FormatTime(expires)}, nil
+}
+
+// Verify checks resp, consuming its nonce whatever the outcome, and returns
+// the agent's identity. conn, if not nil, is the TLS connection the
+// response came over, as for agentauth.Authenticator.Check. Its errors wrap
+// ErrUnknownNonce, ErrInvalidProof or the agentauth errors.
+func (s *Server) Verify(ctx context.Context, resp *Response, conn *tls.ConnectionState) (*agentauth.Identity, error) {
+	s.mu.Lock()
+	expires, ok := s.pending[resp.Proof.Nonce]
+	delete(s.pending, resp.Proof.Nonce)
+	s.mu.Unlock()
+	if !ok || !s.cfg.Now().Before(expires) {
+		return nil, ErrUnknownNonce
+	}
+	if err := resp.Proof.Verify(&resp.Passport, s.cfg.Audience); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	return s.cfg.Authenticator.Check(ctx, &resp.Passport, conn)
+}
+
+func (s *Server) handleChallenge(w http.ResponseWriter, r *http.Request) {
+	ch, err := s.Challenge()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, ch)
+}
+
+func (s *Server) handleResponse(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.cfg.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", s.cfg.MaxBodyBytes))
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var resp Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	id, err := s.Verify(r.Context(), &resp, r.TLS)
+	switch {
+	case errors.Is(err, agentauth.ErrInactive):
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, agentauth.ErrLookup):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, &Result{
+		Authenticated:    true,
+		AgentID:          id.AgentID(),
+		HumanID:          id.HumanID(),
+		CertificateBound: id.CertificateBound,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,152 @@
+package handshake_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/handshake"
+)
+
+// site is the audience of the test servers, unless a test sets another.
+const site = "https://shop.example"
+
+func newServer(t *testing.T, cfg handshake.Config, auth agentauth.Config) *handshake.Server {
+	t.Helper()
+	cfg.Authenticator = dcptest.Authenticator(t, auth)
+	if cfg.Audience == "" {
+		cfg.Audience = site
+	}
+	s, err := handshake.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func challenge(t *testing.T, s *handshake.Server) *handshake.Challenge {
+	t.Helper()
+	ch, err := s.Challenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ch
+}
+
+func respond(t *testing.T, ch *handshake.Challenge, p *dcp.AgentPassport, signer dcp.BundleSigner) *handshake.Response {
+	t.Helper()
+	resp, err := handshake.Respond(ch, site, p, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestVerify(t *testing.T) {
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	other, otherSigner, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	s := newServer(t, handshake.Config{}, agentauth.Config{})
+	ctx := context.Background()
+
+	ch := challenge(t, s)
+	id, err := s.Verify(ctx, respond(t, ch, p, signer), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.AgentID() != p.AgentID {
+		t.Fatalf("identity %+v", id)
+	}
+	if _, err := s.Verify(ctx, respond(t, ch, p, signer), nil); !errors.Is(err, handshake.ErrUnknownNonce) {
+		t.Fatalf("answered twice: %v", err)
+	}
+	if _, err := s.Verify(ctx, respond(t, &handshake.Challenge{Nonce: "made-up"}, p, signer), nil); !errors.Is(err, handshake.ErrUnknownNonce) {
+		t.Fatalf("nonce never issued: %v", err)
+	}
+
+	// A stolen passport answered with another key.
+	stolen := respond(t, challenge(t, s), other, otherSigner)
+	stolen.Passport = *p
+	if _, err := s.Verify(ctx, stolen, nil); !errors.Is(err, handshake.ErrInvalidProof) {
+		t.Fatalf("stolen passport: %v", err)
+	}
+	forged := respond(t, challenge(t, s), p, otherSigner)
+	if _, err := s.Verify(ctx, forged, nil); !errors.Is(err, handshake.ErrInvalidProof) {
+		t.Fatalf("proof signed with another key: %v", err)
+	}
+	// A proof made for another nonce.
+	replayed := respond(t, challenge(t, s), p, signer)
+	replayed.Proof.Nonce = challenge(t, s).Nonce
+	if _, err := s.Verify(ctx, replayed, nil); !errors.Is(err, handshake.ErrInvalidProof) {
+		t.Fatalf("proof for another nonce: %v", err)
+	}
+
+	// A challenge relayed by another site: the agent names the site it
+	// answers, which is not this one.
+	relayed, err := handshake.Respond(challenge(t, s), "https://evil.example", p, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Verify(ctx, relayed, nil); !errors.Is(err, handshake.ErrInvalidProof) || !strings.Contains(err.Error(), "evil.example") {
+		t.Fatalf("relayed challenge: %v", err)
+	}
+	relayed, _ = handshake.Respond(challenge(t, s), "https://evil.example", p, signer)
+	relayed.Proof.Audience = site
+	if _, err := s.Verify(ctx, relayed, nil); !errors.Is(err, handshake.ErrInvalidProof) {
+		t.Fatalf("audience rewritten: %v", err)
+	}
+	if _, err := handshake.Respond(challenge(t, s), "", p, signer); err == nil {
+		t.Fatal("proof without an audience")
+	}
+	if _, err := handshake.New(handshake.Config{Authenticator: dcptest.Authenticator(t, agentauth.Config{})}); err == nil {
+		t.Fatal("server without an audience")
+	}
+}
+
+func TestNonceSource(t *testing.T) {
+	raw := bytes.Repeat([]byte{7}, 32)
+	s := newServer(t, handshake.Config{Rand: bytes.NewReader(raw)}, agentauth.Config{})
+	if ch := challenge(t, s); ch.Nonce != base64.RawURLEncoding.EncodeToString(raw) {
+		t.Fatalf("nonce %s not from Config.Rand", ch.Nonce)
+	}
+	// Without one, nonces come from the package entropy source.
+	defer dcp.SetEntropy(bytes.NewReader(nil))()
+	if _, err := newServer(t, handshake.Config{}, agentauth.Config{}).Challenge(); err == nil {
+		t.Fatal("nonce from an exhausted entropy source")
+	}
+}
+
+func TestRevoked(t *testing.T) {
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	revocations := &dcp.RevocationList{}
+	revocations.Add(dcp.NewRevocationRecord(p.AgentID, p.PrincipalBindingReference, "key compromised"))
+	s := newServer(t, handshake.Config{}, agentauth.Config{Revocations: []dcp.RevocationChecker{revocations}})
+	if _, err := s.Verify(context.Background(), respond(t, challenge(t, s), p, signer), nil); !errors.Is(err, agentauth.ErrInactive) {
+		t.Fatalf("revoked agent: %v", err)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierLow, "browse")
+	now := time.Now()
+	s := newServer(t, handshake.Config{TTL: time.Minute, MaxPending: 2, Now: func() time.Time { return now }}, agentauth.Config{})
+
+	late := challenge(t, s)
+	challenge(t, s)
+	if _, err := s.Challenge(); !errors.Is(err, handshake.ErrBusy) {
+		t.Fatalf("third pending challenge: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := s.Verify(context.Background(), respond(t, late, p, signer), nil); !errors.Is(err, handshake.ErrUnknownNonce) {
+		t.Fatalf("expired challenge: %v", err)
+	}
+	// The expired challenges no longer count against MaxPending.
+	challenge(t, s)
+	challenge(t, s)
+}
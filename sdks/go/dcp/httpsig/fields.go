@@ -0,0 +1,153 @@
+package httpsig
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// This file parses the subset of RFC 8941 structured fields that
+// Signature-Input and Signature use: dictionaries of inner lists of strings
+// with parameters, and of byte sequences.
+
+// parseDictionary splits a dictionary field into its member values by key.
+func parseDictionary(field string) (map[string]string, error) {
+	members := map[string]string{}
+	for _, member := range splitTopLevel(field) {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		eq := strings.IndexByte(member, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("member %q has no value", member)
+		}
+		members[strings.TrimSpace(member[:eq])] = strings.TrimSpace(member[eq+1:])
+	}
+	if len(members) == 0 {
+		return nil, errors.New("empty")
+	}
+	return members, nil
+}
+
+// splitTopLevel splits field at the commas outside strings and inner lists.
+func splitTopLevel(field string) []string {
+	var parts []string
+	depth, start, quoted := 0, 0, false
+	for i := 0; i < len(field); i++ {
+		switch c := field[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, field[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, field[start:])
+}
+
+// parseParams parses a Signature-Input member value.
+func parseParams(value string) (*Params, error) {
+	if !strings.HasPrefix(value, "(") {
+		return nil, errors.New("not an inner list")
+	}
+	end := strings.IndexByte(value, ')')
+	if end < 0 {
+		return nil, errors.New("unterminated inner list")
+	}
+	p := &Params{}
+	for _, item := range strings.Fields(value[1:end]) {
+		c, err := strconv.Unquote(item)
+		if err != nil || strings.ContainsAny(c, ";") {
+			return nil, fmt.Errorf("component %s is not a plain string", item)
+		}
+		p.Components = append(p.Components, c)
+	}
+	rest := value[end+1:]
+	for rest != "" {
+		if rest[0] != ';' {
+			return nil, fmt.Errorf("unexpected %q", rest)
+		}
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("parameter %q has no value", rest)
+		}
+		name := rest[:eq]
+		rest = rest[eq+1:]
+		raw, n, err := paramValue(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		rest = rest[n:]
+		switch name {
+		case "created", "expires":
+			t, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s is not an integer", name)
+			}
+			if name == "created" {
+				p.Created = t
+			} else {
+				p.Expires = t
+			}
+		case "keyid":
+			p.KeyID = raw
+		case "alg":
+			p.Alg = raw
+		}
+	}
+	return p, nil
+}
+
+// paramValue returns the bare value of the parameter that s starts with,
+// unquoted if it is a string, and how many bytes of s it took.
+func paramValue(s string) (string, int, error) {
+	if strings.HasPrefix(s, `"`) {
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				v, err := strconv.Unquote(s[:i+1])
+				return v, i + 1, err
+			}
+		}
+		return "", 0, errors.New("unterminated string")
+	}
+	n := strings.IndexByte(s, ';')
+	if n < 0 {
+		n = len(s)
+	}
+	return s[:n], n, nil
+}
+
+// signatureValue returns, in standard base64, the byte sequence labelled
+// label in a Signature field.
+func signatureValue(field, label string) (string, error) {
+	members, err := parseDictionary(field)
+	if err != nil {
+		return "", err
+	}
+	value, ok := members[label]
+	if !ok {
+		return "", fmt.Errorf("no signature labelled %q", label)
+	}
+	if len(value) < 2 || value[0] != ':' || value[len(value)-1] != ':' {
+		return "", errors.New("not a byte sequence")
+	}
+	raw, err := base64.StdEncoding.DecodeString(value[1 : len(value)-1])
+	if err != nil {
+		return "", errors.New("not base64")
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,517 @@
+// Package httpsig signs agent HTTP requests with the passport key, as HTTP
+// Message Signatures (RFC 9421), and verifies them on the receiving side.
+//
+// A signature covers the method, the target URI, the Content-Digest of the
+// body (RFC 9530) and, when the request carries one, the DCP-Intent-ID
+// header, so a verified request is tied to the agent holding the passport
+// key and to the intent it serves:
+//
+//	Content-Digest: sha-256=:<base64>:
+//	Signature-Input: dcp=("@method" "@target-uri" "content-digest" "dcp-intent-id");created=1700000000;keyid="<agent_id>";alg="ed25519"
+//	Signature: dcp=:<base64>:
+//
+// The keyid is the agent ID. The Signer also sends the passport in the
+// agentauth DCP-Agent-Passport header, which the Verifier checks with an
+// agentauth.Authenticator before checking the signature under its key.
+//
+// Agents sign through a Transport:
+//
+//	client := &http.Client{Transport: (&httpsig.Signer{Passport: p, Key: signer}).Transport(nil)}
+//
+// and sites admit signed requests with the Verifier's Middleware:
+//
+//	v, _ := httpsig.NewVerifier(httpsig.Config{Authenticator: auth, RequireIntent: true})
+//	mux.Handle("POST /v1/orders", v.Middleware(orders))
+//
+// The Verifier rebuilds @target-uri from the request's Host and whether it
+// came over TLS; behind a proxy rewriting either, set Config.TargetURI.
+package httpsig
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+)
+
+// Headers.
+const (
+	IntentHeader         = "DCP-Intent-ID"
+	ContentDigestHeader  = "Content-Digest"
+	SignatureHeader      = "Signature"
+	SignatureInputHeader = "Signature-Input"
+)
+
+// DefaultLabel labels the signatures of a Signer with no Label.
+const DefaultLabel = "dcp"
+
+// Algorithm is the only alg parameter signed and accepted.
+const Algorithm = "ed25519"
+
+// DefaultMaxAge is how old, or how far in the future, a signature may be
+// when Config.MaxAge is zero.
+const DefaultMaxAge = 5 * time.Minute
+
+// Covered component identifiers.
+const (
+	componentMethod    = "@method"
+	componentTarget    = "@target-uri"
+	componentDigest    = "content-digest"
+	componentIntent    = "dcp-intent-id"
+	componentSignature = "@signature-params"
+)
+
+var (
+	// ErrNoSignature reports a request carrying no signature.
+	ErrNoSignature = errors.New("no HTTP message signature presented")
+	// ErrInvalidSignature reports a signature that is malformed, does not
+	// cover the required components, is outside its validity window, or
+	// does not verify under the agent's passport key.
+	ErrInvalidSignature = errors.New("invalid HTTP message signature")
+)
+
+// Signer signs requests on behalf of an agent.
+type Signer struct {
+	// Passport is the agent's passport, sent with each request; its agent
+	// ID is the signature keyid.
+	Passport *dcp.AgentPassport
+	// Key is the agent key the passport carries.
+	Key dcp.BundleSigner
+	// Label names the signature; empty means DefaultLabel.
+	Label string
+	// Expires, if positive, adds an expires parameter that long after
+	// created.
+	Expires time.Duration
+	// Now is the clock signatures are dated with; nil means time.Now.
+	Now func() time.Time
+}
+
+// Sign adds the Content-Digest, passport, Signature-Input and Signature
+// headers to req. The body is read and replaced, so req can still be sent.
+func (s *Signer) Sign(req *http.Request) error {
+	if s.Passport == nil || s.Key == nil {
+		return errors.New("httpsig: a passport and a key are required")
+	}
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return fmt.Errorf("httpsig: body: %v", err)
+	}
+	passport, err := agentauth.EncodePassport(s.Passport)
+	if err != nil {
+		return fmt.Errorf("httpsig: passport: %v", err)
+	}
+	req.Header.Set(agentauth.PassportHeader, passport)
+	req.Header.Set(ContentDigestHeader, ContentDigest(body))
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	params := &Params{
+		Components: []string{componentMethod, componentTarget, componentDigest},
+		Created:    now().Unix(),
+		KeyID:      s.Passport.AgentID,
+		Alg:        Algorithm,
+	}
+	if req.Header.Get(IntentHeader) != "" {
+		params.Components = append(params.Components, componentIntent)
+	}
+	if s.Expires > 0 {
+		params.Expires = params.Created + int64(s.Expires/time.Second)
+	}
+	scheme := req.URL.Scheme
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	base, err := signatureBase(req, scheme+"://"+host+req.URL.RequestURI(), params.Components, params.String())
+	if err != nil {
+		return fmt.Errorf("httpsig: %v", err)
+	}
+	sig, err := s.Key.SignCanonical(base)
+	if err != nil {
+		return fmt.Errorf("httpsig: sign: %v", err)
+	}
+	label := s.Label
+	if label == "" {
+		label = DefaultLabel
+	}
+	req.Header.Set(SignatureInputHeader, label+"="+params.String())
+	req.Header.Set(SignatureHeader, label+"=:"+sig+":")
+	return nil
+}
+
+// Transport returns a RoundTripper signing each request before sending it
+// with base; nil means http.DefaultTransport.
+func (s *Signer) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{signer: s, base: base}
+}
+
+type transport struct {
+	signer *Signer
+	base   http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request.
+	req = req.Clone(req.Context())
+	if err := t.signer.Sign(req); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// ContentDigest returns the RFC 9530 sha-256 Content-Digest of body.
+func ContentDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+// Params are the signature parameters of a Signature-Input member.
+type Params struct {
+	Components []string
+	Created    int64
+	Expires    int64
+	KeyID      string
+	Alg        string
+}
+
+// String returns params as a Signature-Input member value, which is also
+// the @signature-params line of the signature base.
+func (p *Params) String() string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for i, c := range p.Components {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strconv.Quote(c))
+	}
+	b.WriteByte(')')
+	if p.Created != 0 {
+		fmt.Fprintf(&b, ";created=%d", p.Created)
+	}
+	if p.Expires != 0 {
+		fmt.Fprintf(&b, ";expires=%d", p.Expires)
+	}
+	if p.KeyID != "" {
+		fmt.Fprintf(&b, ";keyid=%s", strconv.Quote(p.KeyID))
+	}
+	if p.Alg != "" {
+		fmt.Fprintf(&b, ";alg=%s", strconv.Quote(p.Alg))
+	}
+	return b.String()
+}
+
+// covers reports whether p covers component c.
+func (p *Params) covers(c string) bool {
+	for _, have := range p.Components {
+		if have == c {
+			return true
+		}
+	}
+	return false
+}
+
+// signatureBase returns the RFC 9421 signature base of req covering
+// components, with targetURI as its @target-uri and the Signature-Input
+// member value paramsLine as its @signature-params.
+func signatureBase(req *http.Request, targetURI string, components []string, paramsLine string) (string, error) {
+	var b strings.Builder
+	for _, c := range components {
+		var value string
+		switch c {
+		case componentMethod:
+			value = req.Method
+		case componentTarget:
+			value = targetURI
+		default:
+			if strings.HasPrefix(c, "@") {
+				return "", fmt.Errorf("unsupported component %q", c)
+			}
+			values := req.Header.Values(c)
+			if len(values) == 0 {
+				return "", fmt.Errorf("covered header %q is missing", c)
+			}
+			trimmed := make([]string, len(values))
+			for i, v := range values {
+				trimmed[i] = strings.TrimSpace(v)
+			}
+			value = strings.Join(trimmed, ", ")
+		}
+		fmt.Fprintf(&b, "%q: %s\n", c, value)
+	}
+	fmt.Fprintf(&b, "%q: %s", componentSignature, paramsLine)
+	return b.String(), nil
+}
+
+// Config configures a Verifier.
+type Config struct {
+	// Authenticator checks the passport of the signing agent. Required.
+	Authenticator *agentauth.Authenticator
+	// Passports, if set, supply the passport of agents whose requests
+	// carry none, by the signature keyid.
+	Passports agentauth.PassportSource
+	// Label selects the signature to verify; empty means the only, or
+	// the DefaultLabel, one.
+	Label string
+	// RequireIntent refuses signatures not covering a DCP-Intent-ID
+	// header.
+	RequireIntent bool
+	// MaxAge bounds the age of signatures by their created parameter;
+	// zero means DefaultMaxAge.
+	MaxAge time.Duration
+	// TargetURI returns the URI the agent addressed r to; nil rebuilds it
+	// from r.TLS, r.Host and r.URL.
+	TargetURI func(r *http.Request) string
+	// Now is the clock signatures are checked against; nil means
+	// time.Now.
+	Now func() time.Time
+}
+
+// Verified is a request whose signature verified.
+type Verified struct {
+	Identity *agentauth.Identity
+	// IntentID is the covered DCP-Intent-ID header, if any.
+	IntentID string
+	Params   Params
+}
+
+type contextKey struct{}
+
+// FromContext returns the request verified by the Middleware, if any.
+func FromContext(ctx context.Context) (*Verified, bool) {
+	v, ok := ctx.Value(contextKey{}).(*Verified)
+	return v, ok
+}
+
+// Verifier checks signed requests. Create one with NewVerifier.
+type Verifier struct {
+	cfg Config
+}
+
+// NewVerifier returns a Verifier for cfg.
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if cfg.Authenticator == nil {
+		return nil, errors.New("httpsig: an authenticator is required")
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = DefaultMaxAge
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.TargetURI == nil {
+		cfg.TargetURI = targetURI
+	}
+	return &Verifier{cfg: cfg}, nil
+}
+
+// Verify checks the signature of r and the passport of its agent. The body
+// is read and replaced. Its errors wrap ErrNoSignature, ErrInvalidSignature
+// or the agentauth errors.
+func (v *Verifier) Verify(r *http.Request) (*Verified, error) {
+	if r.Header.Get(SignatureInputHeader) == "" || r.Header.Get(SignatureHeader) == "" {
+		return nil, ErrNoSignature
+	}
+	label, paramsLine, params, err := v.selectParams(r.Header.Get(SignatureInputHeader))
+	if err != nil {
+		return nil, fmt.Errorf("%w: Signature-Input: %v", ErrInvalidSignature, err)
+	}
+	sig, err := signatureValue(r.Header.Get(SignatureHeader), label)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Signature: %v", ErrInvalidSignature, err)
+	}
+	if err := v.checkParams(params); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	body, err := readBody(&r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: body: %v", ErrInvalidSignature, err)
+	}
+	if got := strings.TrimSpace(r.Header.Get(ContentDigestHeader)); got != ContentDigest(body) {
+		return nil, fmt.Errorf("%w: Content-Digest does not match the body", ErrInvalidSignature)
+	}
+
+	p, err := v.passport(r, params.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if p.AgentID != params.KeyID {
+		return nil, fmt.Errorf("%w: keyid %s is not the passport's agent %s", ErrInvalidSignature, params.KeyID, p.AgentID)
+	}
+	id, err := v.cfg.Authenticator.Check(r.Context(), p, r.TLS)
+	if err != nil {
+		return nil, err
+	}
+	// The signed @signature-params line is the member as sent, with any
+	// parameters Params does not keep.
+	base, err := signatureBase(r, v.cfg.TargetURI(r), params.Components, paramsLine)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if ok, err := dcp.VerifyCanonical(base, sig, p.PublicKey); err != nil || !ok {
+		return nil, fmt.Errorf("%w: does not verify under the passport key of %s", ErrInvalidSignature, p.AgentID)
+	}
+	verified := &Verified{Identity: id, Params: *params}
+	if params.covers(componentIntent) {
+		verified.IntentID = strings.TrimSpace(r.Header.Get(IntentHeader))
+	}
+	return verified, nil
+}
+
+// Middleware admits requests whose signature verifies, with the Verified
+// request and the agent's agentauth.Identity in their context, and answers
+// the others like agentauth's Middleware: 401, 403 (inactive passport) or
+// 503 (lookup failure).
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, err := v.Verify(r)
+		if err != nil {
+			switch {
+			case errors.Is(err, agentauth.ErrInactive):
+				writeError(w, http.StatusForbidden, err.Error())
+			case errors.Is(err, agentauth.ErrLookup):
+				writeError(w, http.StatusServiceUnavailable, err.Error())
+			default:
+				w.Header().Set("WWW-Authenticate", `Signature label="`+v.label()+`"`)
+				writeError(w, http.StatusUnauthorized, err.Error())
+			}
+			return
+		}
+		ctx := agentauth.NewContext(r.Context(), verified.Identity)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, contextKey{}, verified)))
+	})
+}
+
+func (v *Verifier) label() string {
+	if v.cfg.Label != "" {
+		return v.cfg.Label
+	}
+	return DefaultLabel
+}
+
+// selectParams returns the label, value and parameters of the
+// Signature-Input member to verify.
+func (v *Verifier) selectParams(header string) (string, string, *Params, error) {
+	members, err := parseDictionary(header)
+	if err != nil {
+		return "", "", nil, err
+	}
+	label := v.cfg.Label
+	if label == "" && len(members) == 1 {
+		for l := range members {
+			label = l
+		}
+	}
+	if label == "" {
+		label = DefaultLabel
+	}
+	value, ok := members[label]
+	if !ok {
+		return "", "", nil, fmt.Errorf("no signature labelled %q", label)
+	}
+	params, err := parseParams(value)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return label, value, params, nil
+}
+
+func (v *Verifier) checkParams(params *Params) error {
+	required := []string{componentMethod, componentTarget, componentDigest}
+	if v.cfg.RequireIntent {
+		required = append(required, componentIntent)
+	}
+	for _, c := range required {
+		if !params.covers(c) {
+			return fmt.Errorf("does not cover %q", c)
+		}
+	}
+	if params.Alg != "" && params.Alg != Algorithm {
+		return fmt.Errorf("alg %q is not %s", params.Alg, Algorithm)
+	}
+	if params.KeyID == "" {
+		return errors.New("no keyid")
+	}
+	if params.Created == 0 {
+		return errors.New("no created parameter")
+	}
+	now := v.cfg.Now()
+	created := time.Unix(params.Created, 0)
+	if skew := now.Sub(created); skew > v.cfg.MaxAge || skew < -v.cfg.MaxAge {
+		return fmt.Errorf("created at %s, outside the accepted %s", dcp.FormatTime(created), v.cfg.MaxAge)
+	}
+	if params.Expires != 0 && !now.Before(time.Unix(params.Expires, 0)) {
+		return fmt.Errorf("expired at %s", dcp.FormatTime(time.Unix(params.Expires, 0)))
+	}
+	return nil
+}
+
+// passport returns the passport r carries or, failing that, the one the
+// Passports source holds for agentID.
+func (v *Verifier) passport(r *http.Request, agentID string) (*dcp.AgentPassport, error) {
+	if header := strings.TrimSpace(r.Header.Get(agentauth.PassportHeader)); header != "" {
+		p, err := agentauth.DecodePassport(header)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", agentauth.ErrInvalidPassport, err)
+		}
+		return p, nil
+	}
+	if v.cfg.Passports == nil {
+		return nil, agentauth.ErrNoPassport
+	}
+	p, err := v.cfg.Passports.Passport(r.Context(), agentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: registry: %v", agentauth.ErrLookup, err)
+	}
+	if p == nil {
+		return nil, fmt.Errorf("%w: no passport is registered for %s", agentauth.ErrInvalidPassport, agentID)
+	}
+	return p, nil
+}
+
+// targetURI rebuilds the URI r was addressed to.
+func targetURI(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
+
+// readBody reads *body and replaces it with a reader of the same bytes.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
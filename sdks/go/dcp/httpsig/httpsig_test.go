@@ -0,0 +1,214 @@
+package httpsig_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/httpsig"
+)
+
+// newSigner returns a Signer for a fresh self-signed agent.
+func newSigner(t *testing.T) *httpsig.Signer {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), kp.PublicKeyB64, []string{"payments"}, dcp.RiskTierLow)
+	if err := p.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+	return &httpsig.Signer{Passport: &p, Key: key}
+}
+
+func newVerifier(t *testing.T, cfg httpsig.Config) *httpsig.Verifier {
+	t.Helper()
+	cfg.Authenticator = dcptest.Authenticator(t, agentauth.Config{})
+	v, err := httpsig.NewVerifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// signed returns a signed order request for intent.
+func signed(t *testing.T, s *httpsig.Signer, intent string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "http://shop.example/v1/orders?cart=7", strings.NewReader(`{"sku":"A1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if intent != "" {
+		req.Header.Set(httpsig.IntentHeader, intent)
+	}
+	if err := s.Sign(req); err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestTransport(t *testing.T) {
+	s := newSigner(t)
+	v := newVerifier(t, httpsig.Config{RequireIntent: true})
+	ts := httptest.NewServer(v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, _ := httpsig.FromContext(r.Context())
+		id, _ := agentauth.FromContext(r.Context())
+		body, _ := io.ReadAll(r.Body)
+		json.NewEncoder(w).Encode(map[string]string{"agent_id": id.AgentID(), "intent_id": verified.IntentID, "body": string(body)})
+	})))
+	defer ts.Close()
+
+	client := &http.Client{Transport: s.Transport(nil)}
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/orders", strings.NewReader(`{"sku":"A1"}`))
+	req.Header.Set(httpsig.IntentHeader, "intent:1")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got map[string]string
+	json.NewDecoder(resp.Body).Decode(&got)
+	if resp.StatusCode != http.StatusOK || got["agent_id"] != s.Passport.AgentID || got["intent_id"] != "intent:1" || got["body"] != `{"sku":"A1"}` {
+		t.Fatalf("%d %v", resp.StatusCode, got)
+	}
+	if req.Header.Get(httpsig.SignatureHeader) != "" {
+		t.Fatal("the transport signed the caller's request")
+	}
+
+	resp, err = http.Post(ts.URL+"/v1/orders", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+		t.Fatalf("unsigned request: %d", resp.StatusCode)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	s := newSigner(t)
+	other := newSigner(t)
+	now := time.Now()
+	v := newVerifier(t, httpsig.Config{Now: func() time.Time { return now }})
+
+	req := signed(t, s, "intent:1")
+	verified, err := v.Verify(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.Identity.AgentID() != s.Passport.AgentID || verified.IntentID != "intent:1" {
+		t.Fatalf("verified %+v", verified)
+	}
+	if body, _ := io.ReadAll(req.Body); string(body) != `{"sku":"A1"}` {
+		t.Fatalf("body after Verify = %q", body)
+	}
+	if verified, err := v.Verify(signed(t, s, "")); err != nil || verified.IntentID != "" {
+		t.Fatalf("without intent: %+v, %v", verified, err)
+	}
+
+	for name, tc := range map[string]struct {
+		edit func(*http.Request)
+		want error
+	}{
+		"unsigned": {func(r *http.Request) { r.Header.Del(httpsig.SignatureHeader) }, httpsig.ErrNoSignature},
+		"body": {func(r *http.Request) {
+			r.Body = io.NopCloser(strings.NewReader(`{"sku":"B2"}`))
+		}, httpsig.ErrInvalidSignature},
+		"body and digest": {func(r *http.Request) {
+			r.Body = io.NopCloser(strings.NewReader(`{"sku":"B2"}`))
+			r.Header.Set(httpsig.ContentDigestHeader, httpsig.ContentDigest([]byte(`{"sku":"B2"}`)))
+		}, httpsig.ErrInvalidSignature},
+		"method": {func(r *http.Request) { r.Method = http.MethodPut }, httpsig.ErrInvalidSignature},
+		"path":   {func(r *http.Request) { r.URL.Path = "/v1/refunds" }, httpsig.ErrInvalidSignature},
+		"host":   {func(r *http.Request) { r.Host = "other.example" }, httpsig.ErrInvalidSignature},
+		"intent": {func(r *http.Request) { r.Header.Set(httpsig.IntentHeader, "intent:2") }, httpsig.ErrInvalidSignature},
+		"label":  {func(r *http.Request) { r.Header.Set(httpsig.SignatureHeader, "other=:AAAA:") }, httpsig.ErrInvalidSignature},
+		"passport": {func(r *http.Request) {
+			r.Header.Set(agentauth.PassportHeader, signed(t, other, "").Header.Get(agentauth.PassportHeader))
+		}, httpsig.ErrInvalidSignature},
+		"signature": {func(r *http.Request) {
+			r.Header.Set(httpsig.SignatureHeader, signed(t, s, "intent:2").Header.Get(httpsig.SignatureHeader))
+		}, httpsig.ErrInvalidSignature},
+	} {
+		req := signed(t, s, "intent:1")
+		tc.edit(req)
+		if _, err := v.Verify(req); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", name, err, tc.want)
+		}
+	}
+}
+
+func TestValidityWindow(t *testing.T) {
+	s := newSigner(t)
+	now := time.Now()
+	v := newVerifier(t, httpsig.Config{RequireIntent: true, Now: func() time.Time { return now }})
+
+	if _, err := v.Verify(signed(t, s, "")); !errors.Is(err, httpsig.ErrInvalidSignature) {
+		t.Fatalf("no intent with RequireIntent: %v", err)
+	}
+	s.Now = func() time.Time { return now.Add(-time.Hour) }
+	if _, err := v.Verify(signed(t, s, "intent:1")); !errors.Is(err, httpsig.ErrInvalidSignature) {
+		t.Fatalf("stale signature: %v", err)
+	}
+	s.Now = func() time.Time { return now.Add(-time.Minute) }
+	s.Expires = 30 * time.Second
+	if _, err := v.Verify(signed(t, s, "intent:1")); !errors.Is(err, httpsig.ErrInvalidSignature) {
+		t.Fatalf("expired signature: %v", err)
+	}
+	s.Expires = 2 * time.Minute
+	if _, err := v.Verify(signed(t, s, "intent:1")); err != nil {
+		t.Fatalf("unexpired signature: %v", err)
+	}
+}
+
+func TestPassportsSource(t *testing.T) {
+	s := newSigner(t)
+	unregistered := newSigner(t)
+	v := newVerifier(t, httpsig.Config{Passports: grpcserver.PassportMap{s.Passport.AgentID: *s.Passport}})
+
+	req := signed(t, s, "intent:1")
+	req.Header.Del(agentauth.PassportHeader)
+	if _, err := v.Verify(req); err != nil {
+		t.Fatalf("registered passport: %v", err)
+	}
+	req = signed(t, unregistered, "intent:1")
+	req.Header.Del(agentauth.PassportHeader)
+	if _, err := v.Verify(req); !errors.Is(err, agentauth.ErrInvalidPassport) {
+		t.Fatalf("unregistered agent: %v", err)
+	}
+	req = signed(t, s, "intent:1")
+	req.Header.Del(agentauth.PassportHeader)
+	if _, err := newVerifier(t, httpsig.Config{}).Verify(req); !errors.Is(err, agentauth.ErrNoPassport) {
+		t.Fatalf("no passport and no source: %v", err)
+	}
+}
+
+func TestInactivePassport(t *testing.T) {
+	s := newSigner(t)
+	revocations := &dcp.RevocationList{}
+	revocations.Add(dcp.NewRevocationRecord(s.Passport.AgentID, s.Passport.PrincipalBindingReference, "retired"))
+	auth := dcptest.Authenticator(t, agentauth.Config{Revocations: []dcp.RevocationChecker{revocations}})
+	v, err := httpsig.NewVerifier(httpsig.Config{Authenticator: auth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	v.Middleware(http.NotFoundHandler()).ServeHTTP(rec, signed(t, s, "intent:1"))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("revoked agent: %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,40 @@
+package dcp
+
+import "fmt"
+
+// IdempotencyKeyPrefix starts every key DeriveIdempotencyKey returns.
+const IdempotencyKeyPrefix = "dcp-"
+
+// maxIdempotencyKey is the longest idempotency key allowed, the limit
+// payment and email providers commonly set.
+const maxIdempotencyKey = 255
+
+// DeriveIdempotencyKey returns the idempotency key of intent:
+// IdempotencyKeyPrefix and the SHA-256 of its canonical agent_id,
+// intent_id, supersedes and nonce. The key is the same however often the
+// intent is retried, and differs for each amendment, so a provider
+// deduplicates retries of one version of an action but not the action as
+// amended.
+func DeriveIdempotencyKey(intent *Intent) (string, error) {
+	h, err := HashObject(map[string]interface{}{
+		"agent_id":   intent.AgentID,
+		"intent_id":  intent.IntentID,
+		"supersedes": intent.Supersedes,
+		"nonce":      intent.Nonce,
+	})
+	if err != nil {
+		return "", fmt.Errorf("idempotency key of %s: %w", intent.IntentID, err)
+	}
+	return IdempotencyKeyPrefix + h, nil
+}
+
+// SetIdempotencyKey sets IdempotencyKey to DeriveIdempotencyKey(i). Set
+// it after the nonce and any amendment, which the key is derived from.
+func (i *Intent) SetIdempotencyKey() error {
+	key, err := DeriveIdempotencyKey(i)
+	if err != nil {
+		return err
+	}
+	i.IdempotencyKey = key
+	return nil
+}
@@ -0,0 +1,51 @@
+package dcp_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	intent := dcp.Intent{DCPVersion: "1.0", IntentID: "intent001", AgentID: "agent001", HumanID: "human001",
+		Timestamp: "2026-01-01T01:00:00Z", ActionType: "initiate_payment", Target: dcp.IntentTarget{Channel: "payments"},
+		DataClasses: []string{"financial_data"}, EstimatedImpact: "high"}
+	if err := intent.SetIdempotencyKey(); err != nil {
+		t.Fatal(err)
+	}
+	key := intent.IdempotencyKey
+	if !strings.HasPrefix(key, dcp.IdempotencyKeyPrefix) || len(key) != len(dcp.IdempotencyKeyPrefix)+64 {
+		t.Fatalf("key = %s", key)
+	}
+	if err := intent.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A retry, declared again later, keeps the key.
+	retry := intent
+	retry.Timestamp = "2026-01-01T01:05:00Z"
+	retry.IdempotencyKey = ""
+	if k, _ := dcp.DeriveIdempotencyKey(&retry); k != key {
+		t.Fatalf("retry: %s, want %s", k, key)
+	}
+	amended, err := dcp.AmendIntent(intent, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k, _ := dcp.DeriveIdempotencyKey(&amended); k == key || amended.IdempotencyKey != k {
+		t.Fatalf("amendment: %s, derived %s", amended.IdempotencyKey, k)
+	}
+	other := intent
+	other.Nonce = dcp.NewNonce()
+	if k, _ := dcp.DeriveIdempotencyKey(&other); k == key {
+		t.Fatal("another nonce keeps the key")
+	}
+
+	intent.IdempotencyKey = strings.Repeat("k", 256)
+	var verrs dcp.ValidationErrors
+	if err := intent.Validate(); !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Pointer != "/idempotency_key" {
+		t.Fatalf("long key: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package dcp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestParseID(t *testing.T) {
+	id, err := dcp.ParseID("dcp:agent:0190b6c8-5a3e-7c21-9f4d-2b8e1a7c3d50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Kind != dcp.IDKindAgent || id.Value != "0190b6c8-5a3e-7c21-9f4d-2b8e1a7c3d50" {
+		t.Fatalf("id = %+v", id)
+	}
+	if id.String() != "dcp:agent:0190b6c8-5a3e-7c21-9f4d-2b8e1a7c3d50" {
+		t.Fatalf("String() = %s", id)
+	}
+	for _, bad := range []string{"agent001", "dcp:agent", "dcp:robot:abc", "dcp:agent:", "dcp:agent:a b", "urn:dcp:agent:x"} {
+		if _, err := dcp.ParseID(bad); err == nil {
+			t.Errorf("ParseID(%q) should fail", bad)
+		}
+	}
+}
+
+func TestNewIDsHaveTheirKind(t *testing.T) {
+	for kind, id := range map[dcp.IDKind]string{
+		dcp.IDKindHuman:    dcp.NewHumanID(),
+		dcp.IDKindAgent:    dcp.NewAgentID(),
+		dcp.IDKindIntent:   dcp.NewIntentID(),
+		dcp.IDKindAudit:    dcp.NewAuditID(),
+		dcp.IDKindSession:  dcp.NewSessionID(),
+		dcp.IDKindDecision: dcp.NewDecisionID(),
+	} {
+		parsed, err := dcp.ParseID(id)
+		if err != nil || parsed.Kind != kind || !uuidV7.MatchString(parsed.Value) {
+			t.Errorf("%s id %q: %+v, %v", kind, id, parsed, err)
+		}
+	}
+}
+
+func TestValidateChecksIDKinds(t *testing.T) {
+	intent := dcp.NewIntent(dcp.NewHumanID(), "human001", "browse", dcp.IntentTarget{Channel: dcp.ChannelWeb},
+		[]string{"none"}, dcp.ImpactLow)
+	err := intent.Validate()
+	var verrs dcp.ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Pointer != "/agent_id" {
+		t.Fatalf("err = %v", err)
+	}
+	intent.AgentID = "dcp:agent:not a uuid"
+	if err := intent.Validate(); err == nil {
+		t.Fatal("malformed dcp: id should fail")
+	}
+	intent.AgentID = "agent001"
+	if err := intent.Validate(); err != nil {
+		t.Fatalf("legacy ids should still validate: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInsuranceInvalid is returned for an insurance attestation that does
+// not assert coverage for the principal and agent it comes with, when
+// their action was taken.
+var ErrInsuranceInvalid = errors.New("insurance attestation invalid")
+
+// HashPolicyNumber returns the policy_number_hash of policy number n, with
+// surrounding space trimmed, so the number itself need not be disclosed.
+func HashPolicyNumber(n string) string {
+	return "sha256:" + sha256HexString(strings.TrimSpace(n))
+}
+
+// NewInsuranceAttestation returns attester's unsigned assertion that
+// passport's agent and principal are covered for class under policy number
+// policyNumber, from now for ttl.
+func (f *RecordFactory) NewInsuranceAttestation(attester string, attesterType AttesterType, passport *AgentPassport, policyNumber string, class CoverageClass, ttl time.Duration) InsuranceAttestation {
+	now := f.now()
+	return InsuranceAttestation{
+		DCPVersion:       DCPVersion,
+		AttesterID:       attester,
+		AttesterType:     attesterType,
+		HumanID:          passport.PrincipalBindingReference,
+		AgentID:          passport.AgentID,
+		PolicyNumberHash: HashPolicyNumber(policyNumber),
+		CoverageClass:    class,
+		NotBefore:        FormatTime(now),
+		NotAfter:         FormatTime(now.Add(ttl)),
+	}
+}
+
+// NewInsuranceAttestation calls RecordFactory.NewInsuranceAttestation with
+// the package clock.
+func NewInsuranceAttestation(attester string, attesterType AttesterType, passport *AgentPassport, policyNumber string, class CoverageClass, ttl time.Duration) InsuranceAttestation {
+	return defaultRecords.NewInsuranceAttestation(attester, attesterType, passport, policyNumber, class, ttl)
+}
+
+// Sign sets Signature to s's signature over the canonical record with an
+// empty signature. An attestation is signed with the attester's published
+// key.
+func (a *InsuranceAttestation) Sign(s BundleSigner) error {
+	a.Signature = ""
+	sig, err := signWith(s, a)
+	if err != nil {
+		return fmt.Errorf("sign insurance attestation of %s: %w", a.AttesterID, err)
+	}
+	a.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against the attester's public key.
+func (a *InsuranceAttestation) VerifySignature(publicKeyB64 string) (bool, error) {
+	if a.Signature == "" {
+		return false, fmt.Errorf("insurance attestation of %s has no signature", a.AttesterID)
+	}
+	unsigned := *a
+	unsigned.Signature = ""
+	return VerifyObject(unsigned, a.Signature, publicKeyB64)
+}
+
+// Check reports whether a asserts coverage for agent agentID of principal
+// humanID at t, signed with one of attesterKeys, and returns that key.
+// Errors wrap ErrInsuranceInvalid.
+func (a *InsuranceAttestation) Check(humanID, agentID string, attesterKeys []string, t time.Time) (string, error) {
+	if a.HumanID != humanID || a.AgentID != agentID {
+		return "", fmt.Errorf("%w: covers %s of %s, not %s of %s", ErrInsuranceInvalid, a.AgentID, a.HumanID, agentID, humanID)
+	}
+	if len(attesterKeys) == 0 {
+		return "", fmt.Errorf("%w: no attester key to check the signature against", ErrInsuranceInvalid)
+	}
+	key := ""
+	for _, k := range attesterKeys {
+		if ok, err := a.VerifySignature(k); err == nil && ok {
+			key = k
+			break
+		}
+	}
+	if key == "" {
+		return "", fmt.Errorf("%w: the signature does not verify with any key of a trusted attester", ErrInsuranceInvalid)
+	}
+	from, err := ParseTime(a.NotBefore)
+	if err != nil {
+		return key, fmt.Errorf("%w: not_before: %v", ErrInsuranceInvalid, err)
+	}
+	until, err := ParseTime(a.NotAfter)
+	if err != nil {
+		return key, fmt.Errorf("%w: not_after: %v", ErrInsuranceInvalid, err)
+	}
+	if t.Before(from) || !t.Before(until) {
+		return key, fmt.Errorf("%w: %s cover from %s to %s does not hold at %s",
+			ErrInsuranceInvalid, a.CoverageClass, a.NotBefore, a.NotAfter, FormatTime(t))
+	}
+	return key, nil
+}
+
+// Validate checks a against the insurance attestation schema and returns
+// ValidationErrors listing every violation, or nil.
+func (a *InsuranceAttestation) Validate() error {
+	v := newValidator()
+	a.validate(v)
+	return v.err()
+}
+
+func (a *InsuranceAttestation) validate(v validator) {
+	v.version("dcp_version", a.DCPVersion)
+	v.minLen("attester_id", a.AttesterID, 3)
+	v.enum("attester_type", string(a.AttesterType), attesterTypes)
+	v.idOf("human_id", a.HumanID, IDKindHuman)
+	v.idOf("agent_id", a.AgentID, IDKindAgent)
+	v.sha256Ref("policy_number_hash", a.PolicyNumberHash)
+	v.enum("coverage_class", string(a.CoverageClass), coverageClasses)
+	v.timestamp("not_before", a.NotBefore)
+	v.timestamp("not_after", a.NotAfter)
+	v.signature("signature", a.Signature)
+}
+
+// Clone returns a copy of a.
+func (a *InsuranceAttestation) Clone() *InsuranceAttestation {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	return &c
+}
+
+// Equal reports whether a and o canonicalize identically.
+func (a *InsuranceAttestation) Equal(o *InsuranceAttestation) bool {
+	return (a == nil) == (o == nil) && (a == nil || canonicalEqual(a, o))
+}
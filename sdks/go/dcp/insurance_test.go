@@ -0,0 +1,122 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// attest returns an insurer's signed attestation for agent001 of human001,
+// from 00:30Z for ttl, and the insurer's key.
+func attest(t *testing.T, ttl time.Duration) (dcp.InsuranceAttestation, *dcp.Keypair) {
+	t.Helper()
+	insurer, _ := dcp.GenerateKeypair()
+	s, err := dcp.NewKeySigner(insurer.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := dcp.RecordFactory{Clock: func() time.Time { return time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC) }}
+	passport := dcp.AgentPassport{AgentID: "agent001", PrincipalBindingReference: "human001"}
+	a := f.NewInsuranceAttestation("insurer.example.com", dcp.AttesterInsurer, &passport, " POL-2026-0042 ", dcp.CoverageProfessional, ttl)
+	if err := a.Sign(s); err != nil {
+		t.Fatal(err)
+	}
+	return a, insurer
+}
+
+func TestInsuranceAttestation(t *testing.T) {
+	a, insurer := attest(t, 24*time.Hour)
+	if err := a.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if a.PolicyNumberHash != dcp.HashPolicyNumber("POL-2026-0042") || !strings.HasPrefix(a.PolicyNumberHash, "sha256:") {
+		t.Fatalf("policy_number_hash = %s", a.PolicyNumberHash)
+	}
+	at := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)
+	other, _ := dcp.GenerateKeypair()
+	key, err := a.Check("human001", "agent001", []string{other.PublicKeyB64, insurer.PublicKeyB64}, at)
+	if err != nil || key != insurer.PublicKeyB64 {
+		t.Fatalf("Check = %s, %v", key, err)
+	}
+	for _, tc := range []struct {
+		name         string
+		human, agent string
+		keys         []string
+		at           time.Time
+		want         string
+	}{
+		{"other agent", "human001", "agent002", []string{insurer.PublicKeyB64}, at, "not agent002 of human001"},
+		{"untrusted attester", "human001", "agent001", []string{other.PublicKeyB64}, at, "does not verify"},
+		{"no keys", "human001", "agent001", nil, at, "no attester key"},
+		{"lapsed", "human001", "agent001", []string{insurer.PublicKeyB64}, at.Add(48 * time.Hour), "does not hold at 2026-01-03T01:00:00Z"},
+		{"not yet", "human001", "agent001", []string{insurer.PublicKeyB64}, at.Add(-time.Hour), "does not hold"},
+	} {
+		if _, err := a.Check(tc.human, tc.agent, tc.keys, tc.at); !errors.Is(err, dcp.ErrInsuranceInvalid) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+
+	c := a.Clone()
+	if !c.Equal(&a) {
+		t.Fatal("Clone is not Equal")
+	}
+	c.CoverageClass = "life"
+	c.PolicyNumberHash = "POL-2026-0042"
+	var verrs dcp.ValidationErrors
+	if err := c.Validate(); !errors.As(err, &verrs) || len(verrs) != 2 || verrs[0].Pointer != "/policy_number_hash" || verrs[1].Pointer != "/coverage_class" {
+		t.Fatalf("invalid attestation: %v", err)
+	}
+	if ok, _ := c.VerifySignature(insurer.PublicKeyB64); ok {
+		t.Fatal("altered attestation verified")
+	}
+}
+
+func TestVerifyInsurance(t *testing.T) {
+	a, insurer := attest(t, 24*time.Hour)
+	b, human, _ := builderFixture(t)
+	sb, err := b.Insurance(a).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, InsurerKeys: []string{insurer.PublicKeyB64}, Explain: true}
+	res := dcp.VerifySignedBundleWithOptions(sb, opts)
+	if !res.Verified {
+		t.Fatalf("insured bundle: %v", res.Errors)
+	}
+	if step := res.Trace[len(res.Trace)-1]; step.Check != dcp.TraceInsurance || step.Actual != insurer.PublicKeyB64 ||
+		step.Detail != "professional_liability by insurer.example.com" {
+		t.Fatalf("trace: %+v", step)
+	}
+	data, _ := json.Marshal(sb)
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundleWithOptions(rsb, opts); !res.Verified {
+		t.Fatalf("raw bundle: %v", res.Errors)
+	}
+	if res := dcp.VerifySignedBundle(sb, human.PublicKeyB64); !res.Verified {
+		t.Fatalf("without insurer keys: %v", res.Errors)
+	}
+
+	other, _ := dcp.GenerateKeypair()
+	o := opts
+	o.InsurerKeys = []string{other.PublicKeyB64}
+	if res := dcp.VerifySignedBundleWithOptions(sb, o); res.Verified || !strings.Contains(res.Errors[0], "insurance 0") {
+		t.Fatalf("untrusted attester: %+v", res.Errors)
+	}
+	lapsed, lapsedInsurer := attest(t, 10*time.Minute)
+	b, human, _ = builderFixture(t)
+	sb, err = b.Insurance(lapsed).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o = dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, InsurerKeys: []string{lapsedInsurer.PublicKeyB64}}
+	if res := dcp.VerifySignedBundleWithOptions(sb, o); res.Verified || !strings.Contains(res.Errors[0], "does not hold at 2026-01-01T01:00:00Z") {
+		t.Fatalf("lapsed cover: %+v", res.Errors)
+	}
+}
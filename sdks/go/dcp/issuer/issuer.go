@@ -0,0 +1,424 @@
+// Package issuer is an HTTP service that mints responsible principal
+// records and agent passports: it proofs the principal's identity through
+// pluggable hooks, allocates identifiers, generates and signs with fresh
+// keys, and publishes the records to a registry in one request.
+//
+// Endpoints:
+//
+//	POST /v1/principals   body: a PrincipalRequest; answers an IssuedPrincipal
+//	POST /v1/passports    body: a PassportRequest; answers an IssuedPassport
+//	GET  /health
+//
+// V1 records are signed by the principal's and the agent's own keys, so the
+// issuer returns each secret key exactly once, in the response, and keeps
+// no copy. The API mints identities and should only be reachable by the
+// operator's onboarding frontend.
+//
+// With SPIFFE trust bundles configured, a workload requesting its passport
+// over mTLS with its X.509-SVID as client certificate gets a passport bound
+// to its SPIFFE ID: the issuer publishes a spiffe.Binding alongside it, and
+// services check with a spiffe.Verifier that the passport comes from that
+// workload.
+package issuer
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/spiffe"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/webhook"
+)
+
+// Publisher publishes issued records. *registry.Client is a Publisher;
+// Local adapts an in-process *registry.Registry.
+type Publisher interface {
+	SubmitPrincipal(ctx context.Context, rec dcp.ResponsiblePrincipalRecord, publicKeyB64 string) error
+	SubmitPassport(ctx context.Context, p dcp.AgentPassport) error
+}
+
+// Local returns a Publisher submitting to r.
+func Local(r *registry.Registry) Publisher {
+	return localPublisher{r}
+}
+
+type localPublisher struct{ r *registry.Registry }
+
+func (l localPublisher) SubmitPrincipal(ctx context.Context, rec dcp.ResponsiblePrincipalRecord, publicKeyB64 string) error {
+	return l.r.SubmitPrincipal(rec, publicKeyB64)
+}
+
+func (l localPublisher) SubmitPassport(ctx context.Context, p dcp.AgentPassport) error {
+	return l.r.SubmitPassport(p)
+}
+
+// BindingPublisher publishes the workload bindings of issued passports.
+// *spiffe.Store is a BindingPublisher.
+type BindingPublisher interface {
+	SubmitBinding(ctx context.Context, b spiffe.Binding) error
+}
+
+// Proofer checks a principal's identity before a record is issued. It may
+// correct the request, for instance replace the legal name with the one on
+// a verified document, and returns an error to refuse issuance.
+type Proofer interface {
+	ProveIdentity(ctx context.Context, req *PrincipalRequest) error
+}
+
+// ProoferFunc adapts a function to a Proofer.
+type ProoferFunc func(ctx context.Context, req *PrincipalRequest) error
+
+// ProveIdentity implements Proofer.
+func (f ProoferFunc) ProveIdentity(ctx context.Context, req *PrincipalRequest) error {
+	return f(ctx, req)
+}
+
+// ProofRecorder is a Proofer whose proof outlives issuance, such as a
+// verified OIDC login: once the record is signed, RecordProof returns the
+// proof to hand back with it, named, typically signed with the principal
+// key s.
+type ProofRecorder interface {
+	Proofer
+	RecordProof(ctx context.Context, req *PrincipalRequest, rec *dcp.ResponsiblePrincipalRecord, s dcp.BundleSigner) (name string, proof json.RawMessage, err error)
+}
+
+// ErrNotProofed wraps the error of a Proofer that refused a principal.
+var ErrNotProofed = errors.New("identity proofing failed")
+
+// ErrCapabilityRefused is returned for a passport request asking for a
+// capability the issuer does not grant.
+var ErrCapabilityRefused = errors.New("capability not issued")
+
+// PrincipalRequest asks for a responsible principal record.
+type PrincipalRequest struct {
+	LegalName    string         `json:"legal_name"`
+	EntityType   dcp.EntityType `json:"entity_type"`
+	Jurisdiction string         `json:"jurisdiction"`
+	Contact      *string        `json:"contact,omitempty"`
+	// EntityEvidence is stored in the record and must meet what
+	// EntityType requires.
+	EntityEvidence *dcp.EntityEvidence `json:"entity_evidence,omitempty"`
+	// Evidence is passed to the proofers and not stored.
+	Evidence json.RawMessage `json:"evidence,omitempty"`
+}
+
+// PassportRequest asks for a passport bound to an issued principal.
+type PassportRequest struct {
+	HumanID      string       `json:"human_id"`
+	Capabilities []string     `json:"capabilities,omitempty"`
+	RiskTier     dcp.RiskTier `json:"risk_tier,omitempty"`
+}
+
+// IssuedPrincipal is a published principal record and its key pair.
+type IssuedPrincipal struct {
+	Record       dcp.ResponsiblePrincipalRecord `json:"record"`
+	PublicKeyB64 string                         `json:"public_key_b64"`
+	SecretKeyB64 string                         `json:"secret_key_b64"`
+	// Proofs are those of the ProofRecorders among the proofers, by name.
+	Proofs map[string]json.RawMessage `json:"proofs,omitempty"`
+}
+
+// IssuedPassport is a published passport and the agent's secret key.
+type IssuedPassport struct {
+	Passport     dcp.AgentPassport `json:"passport"`
+	SecretKeyB64 string            `json:"secret_key_b64"`
+	// WorkloadBinding binds the passport to the requesting workload, when
+	// it presented an SVID.
+	WorkloadBinding *spiffe.Binding `json:"workload_binding,omitempty"`
+}
+
+// Config configures a Server.
+type Config struct {
+	// Publisher receives every issued record. Required.
+	Publisher Publisher
+	// Proofers all run, in order, before a principal record is issued.
+	Proofers []Proofer
+	// Validity sets expires_at on principal records; zero issues records
+	// that do not expire.
+	Validity time.Duration
+	// Timeout bounds each request, including proofing and publication;
+	// zero means 30 seconds.
+	Timeout time.Duration
+	// MaxBodyBytes caps request bodies; zero means 1 MiB.
+	MaxBodyBytes int64
+	// Now is the clock records are stamped with; nil means time.Now.
+	Now func() time.Time
+	// Webhooks, if set, is sent a passport.issued event for every
+	// published passport.
+	Webhooks *webhook.Dispatcher
+	// SPIFFE, if set, are the trust bundles of the workloads passports are
+	// bound to. The server's TLS config must request client certificates,
+	// e.g. with tls.RequestClientCert, for workloads to present SVIDs.
+	SPIFFE spiffe.Bundles
+	// Bindings receives the workload bindings. Required with SPIFFE.
+	Bindings BindingPublisher
+	// RequireSVID refuses passport requests presenting no SVID.
+	RequireSVID bool
+	// Capabilities, if set, bound the capabilities passports are issued
+	// with: each requested capability must be covered by one of them, as
+	// the capability grammar matches them, so "email:*" admits a request
+	// for "email:send:*.example.com". Nil issues any capability.
+	Capabilities []string
+}
+
+// Server issues records. Create one with New.
+type Server struct {
+	cfg     Config
+	records dcp.RecordFactory
+	mux     *http.ServeMux
+}
+
+// New returns a Server for cfg.
+func New(cfg Config) (*Server, error) {
+	if cfg.Publisher == nil {
+		return nil, errors.New("issuer: a publisher is required")
+	}
+	if cfg.SPIFFE != nil && cfg.Bindings == nil {
+		return nil, errors.New("issuer: a binding publisher is required with SPIFFE bundles")
+	}
+	if cfg.RequireSVID && cfg.SPIFFE == nil {
+		return nil, errors.New("issuer: RequireSVID needs SPIFFE bundles")
+	}
+	if _, err := dcp.ParseCapabilitySet(cfg.Capabilities); err != nil {
+		return nil, fmt.Errorf("issuer: %w", err)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 1 << 20
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	s := &Server{cfg: cfg, records: dcp.RecordFactory{Clock: cfg.Now}, mux: http.NewServeMux()}
+	s.mux.HandleFunc("POST /v1/principals", s.handlePrincipal)
+	s.mux.HandleFunc("POST /v1/passports", s.handlePassport)
+	s.mux.HandleFunc("GET /health", s.handleHealth)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// IssuePrincipal proofs req, then mints, signs and publishes a principal
+// record under a fresh key.
+func (s *Server) IssuePrincipal(ctx context.Context, req PrincipalRequest) (*IssuedPrincipal, error) {
+	for _, p := range s.cfg.Proofers {
+		if err := p.ProveIdentity(ctx, &req); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotProofed, err)
+		}
+	}
+	rec := s.records.NewResponsiblePrincipalRecord(req.LegalName, req.EntityType, req.Jurisdiction)
+	rec.Contact = req.Contact
+	rec.EntityEvidence = req.EntityEvidence.Clone()
+	if s.cfg.Validity > 0 {
+		exp := dcp.FormatTime(s.cfg.Now().Add(s.cfg.Validity))
+		rec.ExpiresAt = &exp
+	}
+	if err := rec.Validate(); err != nil {
+		return nil, err
+	}
+	kp, signer, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := rec.Sign(signer); err != nil {
+		return nil, err
+	}
+	issued := &IssuedPrincipal{Record: rec, PublicKeyB64: kp.PublicKeyB64, SecretKeyB64: kp.SecretKeyB64}
+	for _, p := range s.cfg.Proofers {
+		r, ok := p.(ProofRecorder)
+		if !ok {
+			continue
+		}
+		name, proof, err := r.RecordProof(ctx, &req, &rec, signer)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotProofed, err)
+		}
+		if issued.Proofs == nil {
+			issued.Proofs = map[string]json.RawMessage{}
+		}
+		issued.Proofs[name] = proof
+	}
+	if err := s.cfg.Publisher.SubmitPrincipal(ctx, rec, kp.PublicKeyB64); err != nil {
+		return nil, fmt.Errorf("issuer: publish principal %s: %w", rec.HumanID, err)
+	}
+	return issued, nil
+}
+
+// IssuePassport mints, self-signs and publishes a passport under a fresh
+// agent key. The publisher rejects a passport of an unknown principal.
+func (s *Server) IssuePassport(ctx context.Context, req PassportRequest) (*IssuedPassport, error) {
+	if s.cfg.RequireSVID {
+		return nil, fmt.Errorf("%w: an SVID is required", ErrNotProofed)
+	}
+	return s.issuePassport(ctx, req, "")
+}
+
+// IssueWorkloadPassport is IssuePassport for the workload of svid, an
+// X.509-SVID chain, leaf first, whose key the caller has seen the workload
+// prove possession of, as in a TLS handshake. The SVID must verify against
+// the SPIFFE bundles; the passport is then bound to its SPIFFE ID, and the
+// binding published before the passport.
+func (s *Server) IssueWorkloadPassport(ctx context.Context, req PassportRequest, svid []*x509.Certificate) (*IssuedPassport, error) {
+	if s.cfg.SPIFFE == nil {
+		return nil, errors.New("issuer: no SPIFFE bundles are configured")
+	}
+	spiffeID, err := s.cfg.SPIFFE.VerifySVID(svid, s.cfg.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: workload identity: %v", ErrNotProofed, err)
+	}
+	return s.issuePassport(ctx, req, spiffeID)
+}
+
+// issuePassport issues a passport bound to spiffeID, if not empty.
+func (s *Server) issuePassport(ctx context.Context, req PassportRequest, spiffeID string) (*IssuedPassport, error) {
+	tier := req.RiskTier
+	if tier == "" {
+		tier = dcp.RiskTierLow
+	}
+	kp, signer, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	p := s.records.NewAgentPassport(req.HumanID, kp.PublicKeyB64, req.Capabilities, tier)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if s.cfg.Capabilities != nil {
+		if err := dcp.CheckAttenuation(s.cfg.Capabilities, p.Capabilities); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCapabilityRefused, err)
+		}
+	}
+	if err := p.Sign(signer); err != nil {
+		return nil, err
+	}
+	issued := &IssuedPassport{Passport: p, SecretKeyB64: kp.SecretKeyB64}
+	if spiffeID != "" {
+		if issued.WorkloadBinding, err = spiffe.Bind(&p, spiffeID, s.cfg.Now(), signer); err != nil {
+			return nil, err
+		}
+		// A binding without its passport binds nothing; the reverse would
+		// leave a passport no Verifier accepts.
+		if err := s.cfg.Bindings.SubmitBinding(ctx, *issued.WorkloadBinding); err != nil {
+			return nil, fmt.Errorf("issuer: publish binding of %s: %w", p.AgentID, err)
+		}
+	}
+	if err := s.cfg.Publisher.SubmitPassport(ctx, p); err != nil {
+		return nil, fmt.Errorf("issuer: publish passport %s: %w", p.AgentID, err)
+	}
+	s.cfg.Webhooks.Emit(webhook.EventPassportIssued, p)
+	return issued, nil
+}
+
+func newKey() (*dcp.Keypair, *dcp.KeySigner, error) {
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		return nil, nil, err
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kp, signer, nil
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"ok":                   true,
+		"service":              "dcp-issuer",
+		"proofers":             len(s.cfg.Proofers),
+		"spiffe_trust_domains": len(s.cfg.SPIFFE),
+	})
+}
+
+func (s *Server) handlePrincipal(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.Timeout)
+	defer cancel()
+	var req PrincipalRequest
+	if !s.readBody(w, r, &req) {
+		return
+	}
+	issued, err := s.IssuePrincipal(ctx, req)
+	if err != nil {
+		writeIssueError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, issued)
+}
+
+func (s *Server) handlePassport(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.Timeout)
+	defer cancel()
+	var req PassportRequest
+	if !s.readBody(w, r, &req) {
+		return
+	}
+	var issued *IssuedPassport
+	var err error
+	if s.cfg.SPIFFE != nil && r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		issued, err = s.IssueWorkloadPassport(ctx, req, r.TLS.PeerCertificates)
+	} else {
+		issued, err = s.IssuePassport(ctx, req)
+	}
+	if err != nil {
+		writeIssueError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, issued)
+}
+
+// readBody decodes the request body into v, answering the request itself
+// when it cannot.
+func (s *Server) readBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.cfg.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", s.cfg.MaxBodyBytes))
+			return false
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+func writeIssueError(w http.ResponseWriter, err error) {
+	var verr dcp.ValidationErrors
+	switch {
+	case errors.Is(err, ErrNotProofed), errors.Is(err, ErrCapabilityRefused):
+		writeError(w, http.StatusForbidden, err.Error())
+	case errors.As(err, &verr):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, registry.ErrUnknownPrincipal):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		// The registry refused or could not be reached.
+		writeError(w, http.StatusBadGateway, err.Error())
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,262 @@
+package issuer_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/issuer"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/spiffe"
+)
+
+var (
+	_ issuer.Publisher        = (*registry.Client)(nil)
+	_ issuer.BindingPublisher = (*spiffe.Store)(nil)
+)
+
+func post(t *testing.T, h http.Handler, target string, v interface{}, out interface{}) int {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body)))
+	if out != nil {
+		json.Unmarshal(rec.Body.Bytes(), out)
+	}
+	return rec.Code
+}
+
+func TestIssue(t *testing.T) {
+	reg, err := registry.New(registry.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	regServer := httptest.NewServer(reg)
+	defer regServer.Close()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	// The proofer requires evidence and takes the legal name from it.
+	proofer := issuer.ProoferFunc(func(ctx context.Context, req *issuer.PrincipalRequest) error {
+		var doc struct {
+			Name string `json:"verified_name"`
+		}
+		if err := json.Unmarshal(req.Evidence, &doc); err != nil || doc.Name == "" {
+			return errors.New("no verified identity document")
+		}
+		req.LegalName = doc.Name
+		return nil
+	})
+
+	for name, pub := range map[string]issuer.Publisher{
+		"local": issuer.Local(reg),
+		"http":  &registry.Client{URL: regServer.URL},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv, err := issuer.New(issuer.Config{
+				Publisher: pub,
+				Proofers:  []issuer.Proofer{proofer},
+				Validity:  365 * 24 * time.Hour,
+				Now:       func() time.Time { return now },
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := issuer.PrincipalRequest{LegalName: "alice", EntityType: dcp.EntityNaturalPerson, Jurisdiction: "US",
+				Evidence: json.RawMessage(`{"verified_name": "Alice Example"}`)}
+			var principal issuer.IssuedPrincipal
+			if code := post(t, srv, "/v1/principals", req, &principal); code != http.StatusCreated {
+				t.Fatalf("issue principal: %d", code)
+			}
+			rec := principal.Record
+			if rec.LegalName != "Alice Example" || rec.ExpiresAt == nil || *rec.ExpiresAt != "2027-03-01T00:00:00Z" {
+				t.Fatalf("record %+v", rec)
+			}
+			if ok, err := rec.VerifySignature(principal.PublicKeyB64); !ok || err != nil {
+				t.Fatalf("record signature: %v, %v", ok, err)
+			}
+			if got := reg.Principal(rec.HumanID); got == nil || got.PublicKeyB64 != principal.PublicKeyB64 {
+				t.Fatalf("registered principal = %+v", got)
+			}
+
+			var passport issuer.IssuedPassport
+			if code := post(t, srv, "/v1/passports", issuer.PassportRequest{HumanID: rec.HumanID, Capabilities: []string{"browse"}}, &passport); code != http.StatusCreated {
+				t.Fatalf("issue passport: %d", code)
+			}
+			p := passport.Passport
+			if ok, err := p.VerifySignature(); !ok || err != nil || p.RiskTier != dcp.RiskTierLow {
+				t.Fatalf("passport %+v: %v, %v", p, ok, err)
+			}
+			signer, err := dcp.NewKeySigner(passport.SecretKeyB64)
+			if err != nil || signer.PublicKeyB64() != p.PublicKey {
+				t.Fatalf("secret key does not match the passport key: %v", err)
+			}
+			if got, _ := reg.Passport(context.Background(), p.AgentID); got == nil {
+				t.Fatal("passport not published")
+			}
+
+			var e map[string]string
+			if code := post(t, srv, "/v1/principals", issuer.PrincipalRequest{EntityType: dcp.EntityNaturalPerson, Jurisdiction: "US"}, &e); code != http.StatusForbidden {
+				t.Fatalf("unproofed principal: %d %v", code, e)
+			}
+			if code := post(t, srv, "/v1/principals", issuer.PrincipalRequest{EntityType: "robot", Jurisdiction: "US", Evidence: req.Evidence}, &e); code != http.StatusBadRequest || !strings.Contains(e["error"], "entity_type") {
+				t.Fatalf("invalid principal: %d %v", code, e)
+			}
+			corp := issuer.PrincipalRequest{LegalName: "Example Inc", EntityType: dcp.EntityCorporation, Jurisdiction: "US", Evidence: req.Evidence}
+			if code := post(t, srv, "/v1/principals", corp, &e); code != http.StatusBadRequest || !strings.Contains(e["error"], "entity_evidence") {
+				t.Fatalf("corporation without a registration: %d %v", code, e)
+			}
+			corp.EntityEvidence = &dcp.EntityEvidence{RegistrationNumber: "C1234567", RegistrationJurisdiction: "US"}
+			if code := post(t, srv, "/v1/principals", corp, &principal); code != http.StatusCreated || !principal.Record.EntityEvidence.Equal(corp.EntityEvidence) {
+				t.Fatalf("corporation: %d %+v", code, principal.Record)
+			}
+			if code := post(t, srv, "/v1/passports", issuer.PassportRequest{HumanID: dcp.NewHumanID()}, &e); code != http.StatusUnprocessableEntity {
+				t.Fatalf("passport of an unknown principal: %d %v", code, e)
+			}
+		})
+	}
+
+	// Issued capabilities are bounded as the grammar matches them.
+	bounded, err := issuer.New(issuer.Config{Publisher: issuer.Local(reg), Capabilities: []string{"email:*", "browse"}, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var principal issuer.IssuedPrincipal
+	if code := post(t, bounded, "/v1/principals", issuer.PrincipalRequest{LegalName: "Carol", EntityType: dcp.EntityNaturalPerson, Jurisdiction: "US"}, &principal); code != http.StatusCreated {
+		t.Fatalf("issue principal: %d", code)
+	}
+	req := issuer.PassportRequest{HumanID: principal.Record.HumanID, Capabilities: []string{"email:send:*.example.com", "browse:read"}}
+	if code := post(t, bounded, "/v1/passports", req, nil); code != http.StatusCreated {
+		t.Fatalf("covered capabilities: %d", code)
+	}
+	req.Capabilities = []string{"email:send", "payments:initiate"}
+	var e map[string]string
+	if code := post(t, bounded, "/v1/passports", req, &e); code != http.StatusForbidden || !strings.Contains(e["error"], "payments:initiate") {
+		t.Fatalf("uncovered capability: %d %v", code, e)
+	}
+	if _, err := issuer.New(issuer.Config{Publisher: issuer.Local(reg), Capabilities: []string{"email:send:<=ten"}}); err == nil {
+		t.Fatal("issuer with an invalid capability bound")
+	}
+
+	if _, err := issuer.New(issuer.Config{}); err == nil {
+		t.Fatal("issuer without a publisher")
+	}
+	regServer.Close()
+	srv, err := issuer.New(issuer.Config{Publisher: &registry.Client{URL: regServer.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := post(t, srv, "/v1/principals", issuer.PrincipalRequest{LegalName: "Bob", EntityType: dcp.EntityNaturalPerson, Jurisdiction: "US"}, nil); code != http.StatusBadGateway {
+		t.Fatalf("unreachable registry: %d", code)
+	}
+}
+
+// newTrustDomain returns the bundles of a fresh example.org trust domain
+// and an X.509-SVID it issued for spiffeID.
+func newTrustDomain(t *testing.T, spiffeID string) (spiffe.Bundles, *x509.Certificate) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ := x509.ParseCertificate(caDER)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(spiffeID)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		URIs:         []*url.URL{u},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, key.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return spiffe.Bundles{"example.org": pool}, leaf
+}
+
+func TestIssueWorkloadPassport(t *testing.T) {
+	reg, err := registry.New(registry.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundles, svid := newTrustDomain(t, "spiffe://example.org/agent/billing")
+	_, foreign := newTrustDomain(t, "spiffe://example.org/agent/billing")
+	store := &spiffe.Store{}
+	srv, err := issuer.New(issuer.Config{Publisher: issuer.Local(reg), SPIFFE: bundles, Bindings: store, RequireSVID: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var principal issuer.IssuedPrincipal
+	if code := post(t, srv, "/v1/principals", issuer.PrincipalRequest{LegalName: "Alice", EntityType: dcp.EntityNaturalPerson, Jurisdiction: "US"}, &principal); code != http.StatusCreated {
+		t.Fatalf("issue principal: %d", code)
+	}
+
+	request := func(chain ...*x509.Certificate) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(issuer.PassportRequest{HumanID: principal.Record.HumanID, Capabilities: []string{"api_call"}})
+		req := httptest.NewRequest(http.MethodPost, "/v1/passports", bytes.NewReader(body))
+		if chain != nil {
+			req.TLS = &tls.ConnectionState{PeerCertificates: chain}
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+	rec := request(svid)
+	var issued issuer.IssuedPassport
+	if rec.Code != http.StatusCreated || json.Unmarshal(rec.Body.Bytes(), &issued) != nil {
+		t.Fatalf("issue workload passport: %d %s", rec.Code, rec.Body)
+	}
+	b := issued.WorkloadBinding
+	if b == nil || b.SPIFFEID != "spiffe://example.org/agent/billing" || b.Verify(&issued.Passport) != nil {
+		t.Fatalf("binding %+v", b)
+	}
+	if published, _ := store.Binding(context.Background(), issued.Passport.AgentID); published == nil || *published != *b {
+		t.Fatalf("published binding %+v", published)
+	}
+
+	if rec := request(foreign); rec.Code != http.StatusForbidden {
+		t.Fatalf("SVID of an untrusted authority: %d %s", rec.Code, rec.Body)
+	}
+	if rec := request(); rec.Code != http.StatusForbidden {
+		t.Fatalf("request without an SVID: %d %s", rec.Code, rec.Body)
+	}
+
+	if _, err := issuer.New(issuer.Config{Publisher: issuer.Local(reg), SPIFFE: bundles}); err == nil {
+		t.Fatal("SPIFFE bundles without a binding publisher")
+	}
+}
@@ -0,0 +1,67 @@
+package issuer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookProofer delegates identity proofing to an HTTP endpoint. It POSTs
+// the PrincipalRequest, evidence included, to URL: a 2xx response accepts
+// the principal, and a JSON PrincipalRequest in its body replaces the
+// request; any other status refuses it, with the {"error": ...} of the
+// response as the reason.
+type WebhookProofer struct {
+	URL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ Proofer = (*WebhookProofer)(nil)
+
+// ProveIdentity implements Proofer.
+func (p *WebhookProofer) ProveIdentity(ctx context.Context, req *PrincipalRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(hreq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &e) != nil || e.Error == "" {
+			e.Error = resp.Status
+		}
+		return fmt.Errorf("%s: %s", p.URL, e.Error)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var corrected PrincipalRequest
+	if err := json.Unmarshal(data, &corrected); err != nil {
+		return fmt.Errorf("%s: %w", p.URL, err)
+	}
+	*req = corrected
+	return nil
+}
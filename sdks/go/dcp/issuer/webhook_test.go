@@ -0,0 +1,47 @@
+package issuer_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/issuer"
+)
+
+func TestWebhookProofer(t *testing.T) {
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req issuer.PrincipalRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		switch string(req.Evidence) {
+		case `"passport-scan"`:
+			req.LegalName = strings.ToUpper(req.LegalName)
+			req.Evidence = nil
+			json.NewEncoder(w).Encode(req)
+		case `"accept"`:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": "document not recognised"})
+		}
+	}))
+	defer hook.Close()
+	p := &issuer.WebhookProofer{URL: hook.URL}
+	ctx := context.Background()
+
+	req := issuer.PrincipalRequest{LegalName: "Alice", EntityType: dcp.EntityNaturalPerson, Jurisdiction: "US", Evidence: json.RawMessage(`"passport-scan"`)}
+	if err := p.ProveIdentity(ctx, &req); err != nil || req.LegalName != "ALICE" {
+		t.Fatalf("corrected request: %+v, %v", req, err)
+	}
+	req = issuer.PrincipalRequest{LegalName: "Bob", Evidence: json.RawMessage(`"accept"`)}
+	if err := p.ProveIdentity(ctx, &req); err != nil || req.LegalName != "Bob" {
+		t.Fatalf("accepted request: %+v, %v", req, err)
+	}
+	req.Evidence = json.RawMessage(`"selfie"`)
+	if err := p.ProveIdentity(ctx, &req); err == nil || !strings.Contains(err.Error(), "document not recognised") {
+		t.Fatalf("refused request: %v", err)
+	}
+}
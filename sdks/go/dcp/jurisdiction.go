@@ -0,0 +1,151 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrJurisdictionInvalid is returned for a jurisdiction that is not an ISO
+// 3166-1 country, an ISO 3166-2 subdivision or a supranational union.
+var ErrJurisdictionInvalid = errors.New("invalid jurisdiction")
+
+type country struct{ alpha2, alpha3, name string }
+
+// unions are the supranational jurisdictions and their member countries.
+var unions = map[string]struct {
+	name    string
+	members []string
+}{
+	"EU": {"European Union", []string{"AT", "BE", "BG", "HR", "CY", "CZ", "DK", "EE", "FI", "FR", "DE", "GR", "HU",
+		"IE", "IT", "LV", "LT", "LU", "MT", "NL", "PL", "PT", "RO", "SK", "SI", "ES", "SE"}},
+	"EEA": {"European Economic Area", []string{"AT", "BE", "BG", "HR", "CY", "CZ", "DK", "EE", "FI", "FR", "DE", "GR",
+		"HU", "IE", "IT", "LV", "LT", "LU", "MT", "NL", "PL", "PT", "RO", "SK", "SI", "ES", "SE", "IS", "LI", "NO"}},
+}
+
+// Jurisdiction is a parsed jurisdiction code: an ISO 3166-1 country such as
+// DE, an ISO 3166-2 subdivision such as US-CA, a supranational union such
+// as EU, or a country as a member of one, such as EU-DE.
+type Jurisdiction struct {
+	// Code is the normalized code.
+	Code string `json:"code"`
+	// Country is the ISO 3166-1 alpha-2 code; empty for a union.
+	Country string `json:"country,omitempty"`
+	// Subdivision is the ISO 3166-2 code without the country prefix.
+	Subdivision string `json:"subdivision,omitempty"`
+	// Union is set for a union and for a country as a member of one.
+	Union string `json:"union,omitempty"`
+	// Name is the country's short name, or the union's.
+	Name string `json:"name"`
+}
+
+var (
+	countryByAlpha2 = map[string]*country{}
+	countryByAlpha3 = map[string]*country{}
+)
+
+func init() {
+	for i := range countries {
+		c := &countries[i]
+		countryByAlpha2[c.alpha2] = c
+		countryByAlpha3[c.alpha3] = c
+	}
+}
+
+// countryCode looks up the country with alpha-2 or alpha-3 code s; UK is
+// taken for GB.
+func countryCode(s string) (*country, bool) {
+	if s == "UK" {
+		s = "GB"
+	}
+	if c, ok := countryByAlpha2[s]; ok {
+		return c, true
+	}
+	c, ok := countryByAlpha3[s]
+	return c, ok
+}
+
+// ParseJurisdiction normalizes s and looks it up. Normalization trims
+// space, upper-cases, reads _ and space as -, and replaces alpha-3 country
+// codes, and UK, with alpha-2 ones: " deu_by" is DE-BY. Errors wrap
+// ErrJurisdictionInvalid.
+func ParseJurisdiction(s string) (Jurisdiction, error) {
+	code := strings.NewReplacer("_", "-", " ", "-").Replace(strings.ToUpper(strings.TrimSpace(s)))
+	head, tail, sub := strings.Cut(code, "-")
+	if u, ok := unions[head]; ok {
+		if !sub {
+			return Jurisdiction{Code: head, Union: head, Name: u.name}, nil
+		}
+		c, ok := countryCode(tail)
+		if !ok || !oneOf(c.alpha2, u.members) {
+			return Jurisdiction{}, fmt.Errorf("%w: %q: %s is not a member country of %s", ErrJurisdictionInvalid, s, tail, head)
+		}
+		return Jurisdiction{Code: head + "-" + c.alpha2, Country: c.alpha2, Union: head, Name: c.name}, nil
+	}
+	c, ok := countryCode(head)
+	if !ok {
+		return Jurisdiction{}, fmt.Errorf("%w: %q: %s is not an ISO 3166-1 country code", ErrJurisdictionInvalid, s, head)
+	}
+	j := Jurisdiction{Code: c.alpha2, Country: c.alpha2, Name: c.name}
+	if sub {
+		if !oneOf(tail, strings.Fields(subdivisions[c.alpha2])) {
+			return Jurisdiction{}, fmt.Errorf("%w: %q: %s is not an ISO 3166-2 subdivision of %s", ErrJurisdictionInvalid, s, tail, c.alpha2)
+		}
+		j.Code, j.Subdivision = c.alpha2+"-"+tail, tail
+	}
+	return j, nil
+}
+
+// NormalizeJurisdiction returns the normalized code of s; see
+// ParseJurisdiction.
+func NormalizeJurisdiction(s string) (string, error) {
+	j, err := ParseJurisdiction(s)
+	return j.Code, err
+}
+
+// Within reports whether j lies within the jurisdiction code: the union of
+// its country, its country, or itself. US-CA is within US, and DE and
+// EU-DE are within EU. An invalid code holds nothing.
+func (j Jurisdiction) Within(code string) bool {
+	o, err := ParseJurisdiction(code)
+	switch {
+	case err != nil:
+		return false
+	case o.Country == "":
+		return j.Code == o.Code || oneOf(j.Country, unions[o.Union].members)
+	}
+	return j.Country == o.Country && (o.Subdivision == "" || j.Subdivision == o.Subdivision)
+}
+
+// Jurisdictions returns the lookup table of countries and unions, sorted
+// by code. Subdivisions lists the subdivisions of a country.
+func Jurisdictions() []Jurisdiction {
+	out := make([]Jurisdiction, 0, len(countries)+len(unions))
+	for _, c := range countries {
+		out = append(out, Jurisdiction{Code: c.alpha2, Country: c.alpha2, Name: c.name})
+	}
+	for code, u := range unions {
+		out = append(out, Jurisdiction{Code: code, Union: code, Name: u.name})
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].Code < out[k].Code })
+	return out
+}
+
+// Subdivisions returns the ISO 3166-2 codes of country's subdivisions, such
+// as US-CA, or the member countries of a union, sorted.
+func Subdivisions(country string) []string {
+	if u, ok := unions[country]; ok {
+		out := make([]string, len(u.members))
+		for i, m := range u.members {
+			out[i] = country + "-" + m
+		}
+		sort.Strings(out)
+		return out
+	}
+	var out []string
+	for _, s := range strings.Fields(subdivisions[country]) {
+		out = append(out, country+"-"+s)
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package dcp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestParseJurisdiction(t *testing.T) {
+	for in, want := range map[string]dcp.Jurisdiction{
+		"DE":      {Code: "DE", Country: "DE", Name: "Germany"},
+		" us-ca ": {Code: "US-CA", Country: "US", Subdivision: "CA", Name: "United States"},
+		"deu_by":  {Code: "DE-BY", Country: "DE", Subdivision: "BY", Name: "Germany"},
+		"uk":      {Code: "GB", Country: "GB", Name: "United Kingdom"},
+		"GB-ENG":  {Code: "GB-ENG", Country: "GB", Subdivision: "ENG", Name: "United Kingdom"},
+		"eu":      {Code: "EU", Union: "EU", Name: "European Union"},
+		"EU-FRA":  {Code: "EU-FR", Country: "FR", Union: "EU", Name: "France"},
+	} {
+		got, err := dcp.ParseJurisdiction(in)
+		if err != nil || got != want {
+			t.Errorf("ParseJurisdiction(%q) = %+v, %v", in, got, err)
+		}
+	}
+	for _, in := range []string{"", "XX", "US-ZZ", "EU-GDPR", "EU-US", "EEA-CH", "USA-"} {
+		if _, err := dcp.ParseJurisdiction(in); !errors.Is(err, dcp.ErrJurisdictionInvalid) {
+			t.Errorf("ParseJurisdiction(%q): %v", in, err)
+		}
+	}
+}
+
+func TestJurisdictionWithin(t *testing.T) {
+	for _, tc := range []struct {
+		j, code string
+		want    bool
+	}{
+		{"US-CA", "US", true},
+		{"US-CA", "US-CA", true},
+		{"US", "US-CA", false},
+		{"US-NY", "US-CA", false},
+		{"DE-BY", "EU", true},
+		{"EU-DE", "EU", true},
+		{"EU-DE", "DE", true},
+		{"NO", "EU", false},
+		{"NO", "EEA", true},
+		{"EU", "EU", true},
+		{"EU", "EEA", false},
+		{"DE", "EU-GDPR", false},
+	} {
+		j, err := dcp.ParseJurisdiction(tc.j)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := j.Within(tc.code); got != tc.want {
+			t.Errorf("%s within %s = %v", tc.j, tc.code, got)
+		}
+	}
+}
+
+func TestJurisdictionTable(t *testing.T) {
+	table := dcp.Jurisdictions()
+	if len(table) != 251 || table[0].Code != "AD" {
+		t.Fatalf("%d jurisdictions, first %+v", len(table), table[0])
+	}
+	for _, j := range table {
+		if got, err := dcp.ParseJurisdiction(j.Code); err != nil || got != j {
+			t.Errorf("%s: %+v, %v", j.Code, got, err)
+		}
+	}
+	if subs := dcp.Subdivisions("US"); len(subs) == 0 || subs[0] != "US-AK" {
+		t.Fatalf("US subdivisions = %v", subs)
+	}
+	if members := dcp.Subdivisions("EEA"); len(members) != 30 || members[0] != "EEA-AT" {
+		t.Fatalf("EEA members = %v", members)
+	}
+}
+
+func TestPrincipalJurisdiction(t *testing.T) {
+	rec := dcp.NewResponsiblePrincipalRecord("Alice", dcp.EntityNaturalPerson, "usa-ca")
+	if rec.Jurisdiction != "US-CA" {
+		t.Fatalf("jurisdiction = %q", rec.Jurisdiction)
+	}
+	for in, msg := range map[string]string{
+		"us-ca":   "must be normalized as US-CA",
+		"EU-GDPR": "must be an ISO 3166-1 country, an ISO 3166-2 subdivision, EU or EEA",
+	} {
+		rec.Jurisdiction = in
+		var verrs dcp.ValidationErrors
+		if err := rec.Validate(); !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Pointer != "/jurisdiction" || verrs[0].Message != msg {
+			t.Errorf("%s: %v", in, err)
+		}
+	}
+}
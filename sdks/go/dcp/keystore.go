@@ -0,0 +1,160 @@
+package dcp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// KeystoreVersion is the version of the encrypted keystore format.
+const KeystoreVersion = 1
+
+// Default scrypt cost for new keystores (N=2^15, r=8, p=1).
+const (
+	keystoreScryptN = 1 << 15
+	keystoreScryptR = 8
+	keystoreScryptP = 1
+)
+
+// ErrKeystorePassphrase is returned when a keystore cannot be decrypted,
+// which almost always means the passphrase is wrong.
+var ErrKeystorePassphrase = errors.New("keystore: wrong passphrase or corrupted keystore")
+
+// ScryptParams are the key-derivation parameters stored in a keystore.
+type ScryptParams struct {
+	N       int    `json:"n"`
+	R       int    `json:"r"`
+	P       int    `json:"p"`
+	SaltB64 string `json:"salt_b64"`
+}
+
+// Keystore is a passphrase-encrypted secret key, stored as JSON. The secret
+// key is sealed with AES-256-GCM under a key derived with scrypt; the
+// algorithm, kid and public key are authenticated but kept in the clear so
+// the file can be identified without the passphrase.
+type Keystore struct {
+	Version       int          `json:"version"`
+	Alg           string       `json:"alg"`
+	Kid           string       `json:"kid,omitempty"`
+	PublicKeyB64  string       `json:"public_key_b64"`
+	KDF           string       `json:"kdf"`
+	KDFParams     ScryptParams `json:"kdf_params"`
+	Cipher        string       `json:"cipher"`
+	NonceB64      string       `json:"nonce_b64"`
+	CiphertextB64 string       `json:"ciphertext_b64"`
+}
+
+// additionalData binds the clear-text members to the ciphertext.
+func (k *Keystore) additionalData() []byte {
+	ad, _ := json.Marshal([]interface{}{k.Version, k.Alg, k.Kid, k.PublicKeyB64})
+	return ad
+}
+
+func (k *Keystore) aead(passphrase []byte) (cipher.AEAD, error) {
+	salt, err := base64.StdEncoding.DecodeString(k.KDFParams.SaltB64)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: salt: %w", err)
+	}
+	p := k.KDFParams
+	key, err := scrypt.Key(passphrase, salt, p.N, p.R, p.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: scrypt: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptKey seals a base64 secret key of algorithm alg under passphrase.
+// Randomness comes from the package entropy source.
+func EncryptKey(alg, kid, publicKeyB64, secretKeyB64 string, passphrase []byte) (*Keystore, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("keystore: empty passphrase")
+	}
+	sk, err := base64.StdEncoding.DecodeString(secretKeyB64)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: decode secret key: %w", err)
+	}
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(entropy(), salt); err != nil {
+		return nil, fmt.Errorf("keystore: salt: %w", err)
+	}
+	k := &Keystore{
+		Version:      KeystoreVersion,
+		Alg:          alg,
+		Kid:          kid,
+		PublicKeyB64: publicKeyB64,
+		KDF:          "scrypt",
+		KDFParams: ScryptParams{
+			N: keystoreScryptN, R: keystoreScryptR, P: keystoreScryptP,
+			SaltB64: base64.StdEncoding.EncodeToString(salt),
+		},
+		Cipher: "aes-256-gcm",
+	}
+	aead, err := k.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(entropy(), nonce); err != nil {
+		return nil, fmt.Errorf("keystore: nonce: %w", err)
+	}
+	k.NonceB64 = base64.StdEncoding.EncodeToString(nonce)
+	k.CiphertextB64 = base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, sk, k.additionalData()))
+	return k, nil
+}
+
+// Decrypt returns the base64 secret key sealed in k.
+func (k *Keystore) Decrypt(passphrase []byte) (string, error) {
+	if k.Version != KeystoreVersion || k.KDF != "scrypt" || k.Cipher != "aes-256-gcm" {
+		return "", fmt.Errorf("keystore: unsupported format (version %d, %s, %s)", k.Version, k.KDF, k.Cipher)
+	}
+	aead, err := k.aead(passphrase)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(k.NonceB64)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return "", ErrKeystorePassphrase
+	}
+	ct, err := base64.StdEncoding.DecodeString(k.CiphertextB64)
+	if err != nil {
+		return "", ErrKeystorePassphrase
+	}
+	sk, err := aead.Open(nil, nonce, ct, k.additionalData())
+	if err != nil {
+		return "", ErrKeystorePassphrase
+	}
+	return base64.StdEncoding.EncodeToString(sk), nil
+}
+
+// ReadKeystore reads a keystore written by WriteKeystore.
+func ReadKeystore(path string) (*Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var k Keystore
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("keystore %s: %w", path, err)
+	}
+	return &k, nil
+}
+
+// WriteKeystore writes k as indented JSON, readable only by the owner.
+func WriteKeystore(path string, k *Keystore) error {
+	data, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
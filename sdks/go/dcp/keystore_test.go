@@ -0,0 +1,44 @@
+package dcp_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	k, err := dcp.EncryptKey("ed25519", "kid001", kp.PublicKeyB64, kp.SecretKeyB64, []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	if err := dcp.WriteKeystore(path, k); err != nil {
+		t.Fatal(err)
+	}
+	if fi, _ := os.Stat(path); fi.Mode().Perm() != 0o600 {
+		t.Fatalf("keystore mode = %v", fi.Mode())
+	}
+	loaded, err := dcp.ReadKeystore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, err := loaded.Decrypt([]byte("correct horse"))
+	if err != nil || sk != kp.SecretKeyB64 {
+		t.Fatalf("decrypt: %v", err)
+	}
+
+	if _, err := loaded.Decrypt([]byte("wrong")); !errors.Is(err, dcp.ErrKeystorePassphrase) {
+		t.Fatalf("wrong passphrase: err = %v", err)
+	}
+	loaded.PublicKeyB64 = "AAAA" + loaded.PublicKeyB64[4:]
+	if _, err := loaded.Decrypt([]byte("correct horse")); !errors.Is(err, dcp.ErrKeystorePassphrase) {
+		t.Fatal("tampering with the clear-text members should be detected")
+	}
+	if _, err := dcp.EncryptKey("ed25519", "", kp.PublicKeyB64, kp.SecretKeyB64, nil); err == nil {
+		t.Fatal("empty passphrase should be rejected")
+	}
+}
@@ -0,0 +1,246 @@
+// Package langchaingo records LangChainGo runs as a verifiable DCP action
+// log. Its Handler is a callbacks.Handler mapping chain, tool and LLM
+// events to Intents and AuditEntries on an AuditChain:
+//
+//	handler, _ := langchaingo.New(langchaingo.Config{Passport: p, Chain: chain, Decider: pdpClient})
+//	executor := agents.NewExecutor(agent, agents.WithCallbacksHandler(handler))
+//
+// Each start event declares an intent, decided by the Decider if one is
+// set, and the matching end or error event appends the audit entry, with
+// the hash of the output as evidence. Callbacks cannot stop a run: an
+// action the policy refuses is still recorded as it happened, under the
+// decision that refused it. Use package mcp's Guard to enforce decisions.
+package langchaingo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/mcp"
+)
+
+// Evidence tool names of LLM calls and chain runs; tool calls are recorded
+// under the name of their tool.
+const (
+	LLMEvidence   = "llm"
+	ChainEvidence = "chain"
+)
+
+var (
+	// LLMTool describes LLM calls when Config.LLM is nil.
+	LLMTool = mcp.Tool{ActionType: "api_call", Channel: dcp.ChannelAPI, Impact: dcp.ImpactLow}
+	// ChainTool describes chain runs when Config.Chains is nil.
+	ChainTool = mcp.Tool{ActionType: "execute_code", Channel: dcp.ChannelRuntime, Impact: dcp.ImpactLow}
+)
+
+// Config configures a Handler.
+type Config struct {
+	// Passport is the running agent's passport. Required.
+	Passport *dcp.AgentPassport
+	// Chain records the run. Required. Give it an AgentSigner to sign the
+	// entries.
+	Chain *dcp.AuditChain
+	// Decider, if set, decides each intent. Without it, entries record
+	// the actions as approved by the agent itself. An intent that could
+	// not be decided is recorded as escalated, for a human to review.
+	Decider mcp.Decider
+	// DecisionKey, if set, is the key decisions must be signed with.
+	DecisionKey string
+	// Tools describes the calls of each tool by name; tools it does not
+	// list are described by mcp.DefaultTool.
+	Tools map[string]mcp.Tool
+	// LLM and Chains describe LLM calls and chain runs; nil means LLMTool
+	// and ChainTool.
+	LLM    *mcp.Tool
+	Chains *mcp.Tool
+	// OnError is told of the decisions and entries that failed; nil
+	// ignores them.
+	OnError func(error)
+}
+
+// Handler is a callbacks.Handler auditing a LangChainGo run. It follows
+// one run at a time, matching each end event to the latest start of its
+// kind; give concurrent runs their own Handlers, which may share a chain.
+// Create one with New.
+type Handler struct {
+	callbacks.SimpleHandler
+	cfg Config
+
+	mu sync.Mutex
+	// pending holds the started LLM calls, chain runs and tool calls.
+	pending map[string][]*action
+	// nextTool is the tool of the agent action whose call starts next.
+	nextTool string
+}
+
+var _ callbacks.Handler = (*Handler)(nil)
+
+// action is a started action awaiting its end.
+type action struct {
+	tool     string
+	intent   *dcp.Intent
+	decision dcp.Outcome
+}
+
+// Kinds of pending actions.
+const (
+	kindLLM   = "llm"
+	kindChain = "chain"
+	kindTool  = "tool"
+)
+
+// New returns a Handler for cfg.
+func New(cfg Config) (*Handler, error) {
+	if cfg.Passport == nil || cfg.Chain == nil {
+		return nil, errors.New("langchaingo: a passport and an audit chain are required")
+	}
+	if cfg.LLM == nil {
+		cfg.LLM = &LLMTool
+	}
+	if cfg.Chains == nil {
+		cfg.Chains = &ChainTool
+	}
+	return &Handler{cfg: cfg, pending: map[string][]*action{}}, nil
+}
+
+// HandleLLMGenerateContentStart declares the intent of an LLM call.
+func (h *Handler) HandleLLMGenerateContentStart(ctx context.Context, _ []llms.MessageContent) {
+	h.start(ctx, kindLLM, LLMEvidence, *h.cfg.LLM)
+}
+
+// HandleLLMGenerateContentEnd records a completed LLM call.
+func (h *Handler) HandleLLMGenerateContentEnd(_ context.Context, res *llms.ContentResponse) {
+	h.end(kindLLM, mcp.OutcomeSucceeded, res)
+}
+
+// HandleLLMError records a failed LLM call.
+func (h *Handler) HandleLLMError(_ context.Context, _ error) {
+	h.end(kindLLM, mcp.OutcomeFailed, nil)
+}
+
+// HandleChainStart declares the intent of a chain run.
+func (h *Handler) HandleChainStart(ctx context.Context, _ map[string]interface{}) {
+	h.start(ctx, kindChain, ChainEvidence, *h.cfg.Chains)
+}
+
+// HandleChainEnd records a completed chain run.
+func (h *Handler) HandleChainEnd(_ context.Context, outputs map[string]interface{}) {
+	h.end(kindChain, mcp.OutcomeSucceeded, outputs)
+}
+
+// HandleChainError records a failed chain run.
+func (h *Handler) HandleChainError(_ context.Context, _ error) {
+	h.end(kindChain, mcp.OutcomeFailed, nil)
+}
+
+// HandleAgentAction notes the tool the agent calls next.
+func (h *Handler) HandleAgentAction(_ context.Context, a schema.AgentAction) {
+	h.mu.Lock()
+	h.nextTool = a.Tool
+	h.mu.Unlock()
+}
+
+// HandleToolStart declares the intent of a tool call.
+func (h *Handler) HandleToolStart(ctx context.Context, _ string) {
+	h.mu.Lock()
+	name := h.nextTool
+	h.nextTool = ""
+	h.mu.Unlock()
+	t, ok := h.cfg.Tools[name]
+	if !ok {
+		t = mcp.DefaultTool
+	}
+	if name == "" {
+		name = kindTool
+	}
+	h.start(ctx, kindTool, name, t)
+}
+
+// HandleToolEnd records a completed tool call.
+func (h *Handler) HandleToolEnd(_ context.Context, output string) {
+	h.end(kindTool, mcp.OutcomeSucceeded, output)
+}
+
+// HandleToolError records a failed tool call.
+func (h *Handler) HandleToolError(_ context.Context, _ error) {
+	h.end(kindTool, mcp.OutcomeFailed, nil)
+}
+
+// start declares the intent of an action of kind, recorded as evidence
+// tool and described by t.
+func (h *Handler) start(ctx context.Context, kind, tool string, t mcp.Tool) {
+	a := &action{tool: tool, intent: t.Intent(h.cfg.Passport), decision: dcp.OutcomeApproved}
+	if h.cfg.Decider != nil {
+		a.decision = h.decide(ctx, a)
+	}
+	h.mu.Lock()
+	h.pending[kind] = append(h.pending[kind], a)
+	h.mu.Unlock()
+}
+
+// decide returns the outcome of the policy decision for a.
+func (h *Handler) decide(ctx context.Context, a *action) dcp.Outcome {
+	d, err := h.cfg.Decider.Decide(ctx, a.intent)
+	if err == nil && h.cfg.DecisionKey != "" {
+		err = d.Verify(h.cfg.DecisionKey, a.intent)
+	}
+	if err != nil {
+		h.fail(fmt.Errorf("langchaingo: %s: policy decision: %v", a.tool, err))
+		return dcp.OutcomeEscalated
+	}
+	switch d.PolicyDecision.Decision {
+	case dcp.DecisionApprove:
+		return dcp.OutcomeApproved
+	case dcp.DecisionEscalate:
+		return dcp.OutcomeEscalated
+	default:
+		return dcp.OutcomeBlocked
+	}
+}
+
+// end records the latest started action of kind with outcome and, if not
+// nil, the hash of its output as evidence.
+func (h *Handler) end(kind, outcome string, output interface{}) {
+	h.mu.Lock()
+	stack := h.pending[kind]
+	if len(stack) == 0 {
+		h.mu.Unlock()
+		h.fail(fmt.Errorf("langchaingo: %s ended without starting", kind))
+		return
+	}
+	a := stack[len(stack)-1]
+	h.pending[kind] = stack[:len(stack)-1]
+	h.mu.Unlock()
+
+	tool := a.tool
+	entry := dcp.AuditEntryFields{
+		Intent:         *a.intent,
+		PolicyDecision: a.decision,
+		Outcome:        outcome,
+		Evidence:       dcp.AuditEvidence{Tool: &tool},
+	}
+	if output != nil {
+		if hash, err := dcp.HashObject(output); err != nil {
+			h.fail(fmt.Errorf("langchaingo: %s: output hash: %v", a.tool, err))
+		} else {
+			ref := "sha256:" + hash
+			entry.Evidence.ResultRef = &ref
+		}
+	}
+	if _, err := h.cfg.Chain.Append(entry); err != nil {
+		h.fail(fmt.Errorf("langchaingo: %v", err))
+	}
+}
+
+func (h *Handler) fail(err error) {
+	if h.cfg.OnError != nil {
+		h.cfg.OnError(err)
+	}
+}
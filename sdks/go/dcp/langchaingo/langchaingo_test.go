@@ -0,0 +1,120 @@
+package langchaingo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/langchaingo"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/mcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+func passport(t *testing.T) (*dcp.AgentPassport, *dcp.KeySigner) {
+	t.Helper()
+	s, pub := dcptest.NewSigner(t)
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), pub, []string{"api_call", "file_write", "code_exec"}, dcp.RiskTierLow)
+	if err := p.Sign(s); err != nil {
+		t.Fatal(err)
+	}
+	return &p, s
+}
+
+type entryWant struct {
+	tool     string
+	decision dcp.Outcome
+	outcome  string
+	output   bool
+}
+
+func checkEntries(t *testing.T, chain *dcp.AuditChain, want []entryWant) {
+	t.Helper()
+	entries := chain.Entries()
+	if len(entries) != len(want) {
+		t.Fatalf("%d entries, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		e := entries[i]
+		if *e.Evidence.Tool != w.tool || e.PolicyDecision != w.decision || e.Outcome != w.outcome || (e.Evidence.ResultRef != nil) != w.output {
+			t.Errorf("entry %d: %+v, want %+v", i, e, w)
+		}
+	}
+}
+
+// run replays the callbacks of an agent executor run asking the LLM, calling
+// tool, and asking the LLM again.
+func run(h *langchaingo.Handler, tool string) {
+	ctx := context.Background()
+	h.HandleChainStart(ctx, map[string]interface{}{"input": "tidy the logs"})
+	h.HandleLLMGenerateContentStart(ctx, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "tidy the logs")})
+	h.HandleLLMGenerateContentEnd(ctx, &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "Action: " + tool}}})
+	h.HandleAgentAction(ctx, schema.AgentAction{Tool: tool, ToolInput: "/var/log/old"})
+	h.HandleToolStart(ctx, "/var/log/old")
+	h.HandleToolEnd(ctx, "done")
+	h.HandleLLMGenerateContentStart(ctx, nil)
+	h.HandleLLMError(ctx, errors.New("rate limited"))
+	h.HandleChainError(ctx, errors.New("rate limited"))
+}
+
+func TestHandler(t *testing.T) {
+	p, signer := passport(t)
+	chain := dcp.NewAuditChain(dcp.AuditChainOptions{AgentSigner: signer})
+	h, err := langchaingo.New(langchaingo.Config{Passport: p, Chain: chain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run(h, "search")
+	checkEntries(t, chain, []entryWant{
+		{"llm", dcp.OutcomeApproved, mcp.OutcomeSucceeded, true},
+		{"search", dcp.OutcomeApproved, mcp.OutcomeSucceeded, true},
+		{"llm", dcp.OutcomeApproved, mcp.OutcomeFailed, false},
+		{"chain", dcp.OutcomeApproved, mcp.OutcomeFailed, false},
+	})
+	entries := chain.Entries()
+	if entries[1].AgentID != p.AgentID || entries[1].AgentSignature == "" || entries[1].PrevHash == entries[0].PrevHash {
+		t.Fatalf("entry %+v", entries[1])
+	}
+}
+
+func TestHandlerDecider(t *testing.T) {
+	p, _ := passport(t)
+	pdpSigner, pdpKey := dcptest.NewSigner(t)
+	decider, err := pdp.New(pdp.Config{Signer: pdpSigner, Policy: &pdp.PolicySet{
+		Default: dcp.DecisionApprove,
+		Rules:   []pdp.Rule{{Name: "files", Channels: []dcp.Channel{dcp.ChannelFilesystem}, Decision: dcp.DecisionBlock}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := dcp.NewAuditChain(dcp.AuditChainOptions{})
+	var errs []error
+	h, err := langchaingo.New(langchaingo.Config{
+		Passport:    p,
+		Chain:       chain,
+		Decider:     decider,
+		DecisionKey: pdpKey,
+		Tools:       map[string]mcp.Tool{"delete_file": {ActionType: "write_file", Channel: dcp.ChannelFilesystem, Impact: dcp.ImpactLow}},
+		OnError:     func(err error) { errs = append(errs, err) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A refused action still ran: the log shows it under its decision.
+	run(h, "delete_file")
+	checkEntries(t, chain, []entryWant{
+		{"llm", dcp.OutcomeApproved, mcp.OutcomeSucceeded, true},
+		{"delete_file", dcp.OutcomeBlocked, mcp.OutcomeSucceeded, true},
+		{"llm", dcp.OutcomeApproved, mcp.OutcomeFailed, false},
+		{"chain", dcp.OutcomeApproved, mcp.OutcomeFailed, false},
+	})
+
+	h.HandleToolEnd(context.Background(), "stray")
+	if len(errs) != 1 || chain.Len() != 4 {
+		t.Fatalf("stray end: %v, %d entries", errs, chain.Len())
+	}
+}
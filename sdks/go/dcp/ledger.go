@@ -0,0 +1,156 @@
+package dcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrLedgerEntryNotFound is returned for an index outside the ledger.
+var ErrLedgerEntryNotFound = errors.New("ledger: entry not found")
+
+// ErrNoCheckpoint is returned by LatestCheckpoint before any checkpoint is saved.
+var ErrNoCheckpoint = errors.New("ledger: no checkpoint")
+
+// ErrLedgerClosed is returned by writes after Close.
+var ErrLedgerClosed = errors.New("ledger: closed")
+
+// LedgerStore persists an agent's audit chain. Entries are append-only and
+// addressed by their zero-based position. Implementations must be safe for
+// concurrent use; see MemoryLedger for the reference implementation and
+// dcp/ledgertest for the shared conformance tests.
+type LedgerStore interface {
+	// AppendEntry stores entry at the end of the ledger and returns its index.
+	AppendEntry(ctx context.Context, entry AuditEntry) (int64, error)
+	// GetByIndex returns the entry at index or ErrLedgerEntryNotFound.
+	GetByIndex(ctx context.Context, index int64) (AuditEntry, error)
+	// Range calls fn for each entry in [start, end) in order, stopping at
+	// the first error fn returns. end is clamped to the ledger length.
+	Range(ctx context.Context, start, end int64, fn func(index int64, entry AuditEntry) error) error
+	// Len returns the number of entries.
+	Len(ctx context.Context) (int64, error)
+	// SaveCheckpoint records a checkpoint for the ledger.
+	SaveCheckpoint(ctx context.Context, cp *Checkpoint) error
+	// LatestCheckpoint returns the most recently saved checkpoint or ErrNoCheckpoint.
+	LatestCheckpoint(ctx context.Context) (*Checkpoint, error)
+	// Close releases the store's resources.
+	Close() error
+}
+
+// lastEntryHash returns the prev_hash the next entry appended to store must
+// carry: GENESIS for an empty ledger.
+func lastEntryHash(ctx context.Context, store LedgerStore) (string, error) {
+	n, err := store.Len(ctx)
+	if err != nil {
+		return "", err
+	}
+	if n == 0 {
+		return "GENESIS", nil
+	}
+	return entryHashAt(ctx, store, n-1)
+}
+
+// ChainAppender is implemented by stores that can link an entry to the
+// current head atomically, which matters when several writers share a
+// ledger. prepare runs after PrevHash is set and before the entry is stored.
+type ChainAppender interface {
+	AppendChained(ctx context.Context, entry AuditEntry, prepare func(*AuditEntry) error) (int64, AuditEntry, error)
+}
+
+// AppendSignedAuditEntryToLedger is AppendSignedAuditEntry against a store:
+// it links entry to the ledger's last entry, signs it with the agent key and
+// appends it, returning its index. Stores implementing ChainAppender do the
+// linking atomically; for others the caller must be the only writer.
+func AppendSignedAuditEntryToLedger(ctx context.Context, store LedgerStore, entry AuditEntry, agentSecretKeyB64 string) (int64, AuditEntry, error) {
+	if ca, ok := store.(ChainAppender); ok {
+		return ca.AppendChained(ctx, entry, func(e *AuditEntry) error {
+			return e.SignAsAgent(agentSecretKeyB64)
+		})
+	}
+	prev, err := lastEntryHash(ctx, store)
+	if err != nil {
+		return 0, entry, fmt.Errorf("ledger: %w", err)
+	}
+	entry.PrevHash = prev
+	if err := entry.SignAsAgent(agentSecretKeyB64); err != nil {
+		return 0, entry, err
+	}
+	idx, err := store.AppendEntry(ctx, entry)
+	return idx, entry, err
+}
+
+// LedgerEntries returns the entries in [start, end), e.g. to place a slice of
+// the ledger into a bundle.
+func LedgerEntries(ctx context.Context, store LedgerStore, start, end int64) ([]AuditEntry, error) {
+	var out []AuditEntry
+	err := store.Range(ctx, start, end, func(_ int64, e AuditEntry) error {
+		out = append(out, e)
+		return nil
+	})
+	return out, err
+}
+
+// LedgerChainAnchor returns the anchor for a bundle whose first entry is the
+// ledger entry at index start.
+func LedgerChainAnchor(ctx context.Context, store LedgerStore, start int64) (*ChainAnchor, error) {
+	if start == 0 {
+		return nil, nil
+	}
+	h, err := entryHashAt(ctx, store, start-1)
+	if err != nil {
+		return nil, fmt.Errorf("chain anchor: %w", err)
+	}
+	return &ChainAnchor{PrevEntryHash: h}, nil
+}
+
+// VerifyLedger streams every entry of store through a StreamVerifier. For a
+// pruned store it resumes from the prune summary, checks the summary's
+// consistency and that the remaining entries reproduce its checkpoint root;
+// use PruneSummary.Verify to also check its signatures.
+func VerifyLedger(ctx context.Context, store LedgerStore, opts AuditStreamOptions) (*StreamVerifier, *VerificationResult) {
+	start, v, summary, err := ledgerStart(ctx, store, opts)
+	if err == nil {
+		err = verifyPrunedRange(ctx, store, v, start, summary)
+	}
+	if err != nil {
+		return v, &VerificationResult{Verified: false, Errors: []string{err.Error()}}
+	}
+	return v, &VerificationResult{Verified: true}
+}
+
+// CheckpointLedger computes the ledger's Merkle root, signs a checkpoint over
+// it with secretKeyB64 and saves it to the store.
+func CheckpointLedger(ctx context.Context, store LedgerStore, origin, secretKeyB64 string, now time.Time) (*Checkpoint, error) {
+	acc := &MerkleAccumulator{}
+	start := int64(0)
+	summary, err := latestPruneSummary(ctx, store)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint ledger: %w", err)
+	}
+	if summary != nil {
+		if acc, err = summary.accumulator(); err != nil {
+			return nil, fmt.Errorf("checkpoint ledger: %w", err)
+		}
+		start = summary.PrunedSize
+	}
+	err = store.Range(ctx, start, math.MaxInt64, func(_ int64, e AuditEntry) error {
+		h, err := HashObject(e)
+		if err != nil {
+			return err
+		}
+		return acc.AddHex(h)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint ledger: %w", err)
+	}
+	cp := newCheckpoint(origin, acc.Size(), acc.Root(), now)
+	if err := cp.Sign(secretKeyB64); err != nil {
+		return nil, err
+	}
+	if err := store.SaveCheckpoint(ctx, cp); err != nil {
+		return nil, fmt.Errorf("checkpoint ledger: %w", err)
+	}
+	return cp, nil
+}
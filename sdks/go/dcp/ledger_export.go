@@ -0,0 +1,249 @@
+package dcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// LedgerExportFormat identifies the JSONL layout written by ExportLedger.
+const LedgerExportFormat = "dcp-ledger-export/1"
+
+// ledgerRecord is one line of a ledger export. The first line is a header,
+// followed for a pruned ledger by its prune summary; entry lines follow in
+// ledger order, with checkpoint lines placed directly after the entry that
+// completes their tree size.
+type ledgerRecord struct {
+	Type         string          `json:"type"`
+	Format       string          `json:"format,omitempty"`
+	Index        *int64          `json:"index,omitempty"`
+	Entry        json.RawMessage `json:"entry,omitempty"`
+	Checkpoint   *Checkpoint     `json:"checkpoint,omitempty"`
+	PruneSummary *PruneSummary   `json:"prune_summary,omitempty"`
+}
+
+// ExportOptions configures ExportLedger.
+type ExportOptions struct {
+	// CheckpointEvery, with SecretKeyB64, signs a fresh checkpoint every N
+	// entries and at the end of the export. The store's latest checkpoint
+	// is always embedded.
+	CheckpointEvery int
+	SecretKeyB64    string
+	Origin          string
+	// Now stamps fresh checkpoints; nil means the package clock.
+	Now func() time.Time
+}
+
+// ExportLedger writes every entry of store to w as JSONL, interleaved with
+// signed checkpoints, so the ledger can be moved and re-verified offline
+// with ImportLedger or VerifyLedgerExport.
+func ExportLedger(ctx context.Context, store LedgerStore, w io.Writer, opts ExportOptions) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := enc.Encode(ledgerRecord{Type: "header", Format: LedgerExportFormat}); err != nil {
+		return err
+	}
+	stored, err := store.LatestCheckpoint(ctx)
+	if err != nil && err != ErrNoCheckpoint {
+		return fmt.Errorf("export ledger: %w", err)
+	}
+	summary, err := latestPruneSummary(ctx, store)
+	if err != nil {
+		return fmt.Errorf("export ledger: %w", err)
+	}
+	acc := &MerkleAccumulator{}
+	if summary != nil {
+		if err := enc.Encode(ledgerRecord{Type: "prune_summary", PruneSummary: summary}); err != nil {
+			return err
+		}
+		if acc, err = summary.accumulator(); err != nil {
+			return fmt.Errorf("export ledger: %w", err)
+		}
+	}
+	now := clockNow
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	signing := opts.CheckpointEvery > 0 && opts.SecretKeyB64 != ""
+
+	emitCheckpoint := func() error {
+		cp := newCheckpoint(opts.Origin, acc.Size(), acc.Root(), now())
+		if err := cp.Sign(opts.SecretKeyB64); err != nil {
+			return err
+		}
+		return enc.Encode(ledgerRecord{Type: "checkpoint", Checkpoint: cp})
+	}
+	err = store.Range(ctx, 0, math.MaxInt64, func(i int64, e AuditEntry) error {
+		canon, err := Canonicalize(e)
+		if err != nil {
+			return err
+		}
+		idx := i
+		if err := enc.Encode(ledgerRecord{Type: "entry", Index: &idx, Entry: json.RawMessage(canon)}); err != nil {
+			return err
+		}
+		if err := acc.AddHex(sha256HexString(canon)); err != nil {
+			return err
+		}
+		if stored != nil && stored.TreeSize == i+1 {
+			if err := enc.Encode(ledgerRecord{Type: "checkpoint", Checkpoint: stored}); err != nil {
+				return err
+			}
+		}
+		if signing && (i+1)%int64(opts.CheckpointEvery) == 0 {
+			return emitCheckpoint()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("export ledger: %w", err)
+	}
+	if signing && acc.Size()%opts.CheckpointEvery != 0 {
+		if err := emitCheckpoint(); err != nil {
+			return fmt.Errorf("export ledger: %w", err)
+		}
+	}
+	return bw.Flush()
+}
+
+// ImportOptions configures ImportLedger and VerifyLedgerExport.
+type ImportOptions struct {
+	// CheckpointPublicKeyB64, if set, must have signed every embedded
+	// checkpoint.
+	CheckpointPublicKeyB64 string
+	// RequireCheckpoint fails an export that carries no checkpoint
+	// covering its final entry.
+	RequireCheckpoint bool
+	// Stream configures the per-entry checks.
+	Stream AuditStreamOptions
+}
+
+// ImportResult summarizes an import.
+type ImportResult struct {
+	Entries     int64
+	Checkpoints []*Checkpoint
+	// PruneSummary is set when the export came from a pruned ledger.
+	PruneSummary *PruneSummary
+}
+
+// ImportLedger reads an export produced by ExportLedger, verifies the chain,
+// each embedded checkpoint's signature and that its root matches the entries
+// before it, and appends everything to store. Importing a pruned ledger
+// requires a store implementing LedgerPruner. Entries are appended as they
+// are verified, so on error store holds a verified prefix.
+func ImportLedger(ctx context.Context, r io.Reader, store LedgerStore, opts ImportOptions) (*ImportResult, error) {
+	return importLedger(ctx, r, store, opts)
+}
+
+// VerifyLedgerExport performs ImportLedger's checks without storing anything.
+func VerifyLedgerExport(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	return importLedger(ctx, r, nil, opts)
+}
+
+func importLedger(ctx context.Context, r io.Reader, store LedgerStore, opts ImportOptions) (*ImportResult, error) {
+	v := NewStreamVerifier(opts.Stream)
+	res := &ImportResult{}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxAuditLineBytes)
+	line := 0
+	for sc.Scan() {
+		line++
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		var rec ledgerRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return res, fmt.Errorf("ledger export line %d: %w", line, err)
+		}
+		if line == 1 {
+			if rec.Type != "header" || rec.Format != LedgerExportFormat {
+				return res, fmt.Errorf("ledger export: unsupported format %q", rec.Format)
+			}
+			continue
+		}
+		switch rec.Type {
+		case "prune_summary":
+			s := rec.PruneSummary
+			if line != 2 || s == nil {
+				return res, fmt.Errorf("ledger export line %d: misplaced prune summary", line)
+			}
+			var err error
+			if opts.CheckpointPublicKeyB64 != "" {
+				err = s.Verify(opts.CheckpointPublicKeyB64)
+			} else {
+				err = s.Check()
+			}
+			if err != nil {
+				return res, fmt.Errorf("ledger export line %d: %w", line, err)
+			}
+			if v, err = resumeStreamVerifier(opts.Stream, s); err != nil {
+				return res, fmt.Errorf("ledger export line %d: %w", line, err)
+			}
+			if store != nil {
+				p, ok := store.(LedgerPruner)
+				if !ok {
+					return res, fmt.Errorf("ledger export: store cannot hold a pruned ledger")
+				}
+				if err := p.Prune(ctx, s); err != nil {
+					return res, fmt.Errorf("ledger export line %d: %w", line, err)
+				}
+			}
+			res.PruneSummary = s
+		case "entry":
+			if rec.Index == nil || *rec.Index != int64(v.Count()) {
+				return res, fmt.Errorf("ledger export line %d: entry out of order", line)
+			}
+			if err := v.Push(rec.Entry); err != nil {
+				return res, fmt.Errorf("ledger export line %d: %w", line, err)
+			}
+			if store != nil {
+				var e AuditEntry
+				if err := json.Unmarshal(rec.Entry, &e); err != nil {
+					return res, fmt.Errorf("ledger export line %d: %w", line, err)
+				}
+				if _, err := store.AppendEntry(ctx, e); err != nil {
+					return res, fmt.Errorf("ledger export line %d: %w", line, err)
+				}
+			}
+			res.Entries++
+		case "checkpoint":
+			cp := rec.Checkpoint
+			if cp == nil {
+				return res, fmt.Errorf("ledger export line %d: empty checkpoint", line)
+			}
+			if opts.CheckpointPublicKeyB64 != "" {
+				if ok, err := cp.Verify(opts.CheckpointPublicKeyB64); err != nil || !ok {
+					return res, fmt.Errorf("ledger export line %d: checkpoint signature invalid", line)
+				}
+			}
+			if cp.TreeSize != int64(v.Count()) || cp.RootHash != v.MerkleRoot() {
+				return res, fmt.Errorf("ledger export line %d: checkpoint for size %d does not match the entries", line, cp.TreeSize)
+			}
+			if store != nil {
+				if err := store.SaveCheckpoint(ctx, cp); err != nil {
+					return res, fmt.Errorf("ledger export line %d: %w", line, err)
+				}
+			}
+			res.Checkpoints = append(res.Checkpoints, cp)
+		default:
+			return res, fmt.Errorf("ledger export line %d: unknown record type %q", line, rec.Type)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return res, fmt.Errorf("read ledger export: %w", err)
+	}
+	if line == 0 {
+		return res, fmt.Errorf("ledger export: empty input")
+	}
+	if opts.RequireCheckpoint {
+		n := len(res.Checkpoints)
+		if n == 0 || res.Checkpoints[n-1].TreeSize != int64(v.Count()) {
+			return res, fmt.Errorf("ledger export: no checkpoint covers the final entry")
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,126 @@
+package dcp_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/ledgertest"
+)
+
+func exportFixture(t *testing.T, n int) (*dcp.MemoryLedger, *dcp.Keypair, *dcp.Keypair) {
+	t.Helper()
+	ctx := context.Background()
+	agent, _ := dcp.GenerateKeypair()
+	operator, _ := dcp.GenerateKeypair()
+	store := dcp.NewMemoryLedger()
+	for i := 0; i < n; i++ {
+		if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, store, ledgertest.Entry(i), agent.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+		if i == 2 {
+			if _, err := dcp.CheckpointLedger(ctx, store, "agent001", operator.SecretKeyB64, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return store, agent, operator
+}
+
+func TestExportImportLedger(t *testing.T) {
+	ctx := context.Background()
+	src, agent, operator := exportFixture(t, 7)
+	var buf bytes.Buffer
+	err := dcp.ExportLedger(ctx, src, &buf, dcp.ExportOptions{
+		CheckpointEvery: 5,
+		SecretKeyB64:    operator.SecretKeyB64,
+		Origin:          "agent001",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(buf.String(), `"type":"checkpoint"`); got != 3 {
+		t.Fatalf("export holds %d checkpoints, want 3 (stored, every 5, final)", got)
+	}
+
+	dst := dcp.NewMemoryLedger()
+	res, err := dcp.ImportLedger(ctx, bytes.NewReader(buf.Bytes()), dst, dcp.ImportOptions{
+		CheckpointPublicKeyB64: operator.PublicKeyB64,
+		RequireCheckpoint:      true,
+		Stream:                 dcp.AuditStreamOptions{AgentPublicKeyB64: agent.PublicKeyB64},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Entries != 7 || len(res.Checkpoints) != 3 {
+		t.Fatalf("imported %d entries and %d checkpoints", res.Entries, len(res.Checkpoints))
+	}
+	if _, r := dcp.VerifyLedger(ctx, dst, dcp.AuditStreamOptions{AgentPublicKeyB64: agent.PublicKeyB64}); !r.Verified {
+		t.Fatalf("imported ledger should verify: %v", r.Errors)
+	}
+	cp, err := dst.LatestCheckpoint(ctx)
+	if err != nil || cp.TreeSize != 7 {
+		t.Fatalf("latest checkpoint = %+v, %v", cp, err)
+	}
+}
+
+func TestVerifyLedgerExportRejectsTampering(t *testing.T) {
+	ctx := context.Background()
+	src, _, operator := exportFixture(t, 4)
+	var buf bytes.Buffer
+	if err := dcp.ExportLedger(ctx, src, &buf, dcp.ExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	opts := dcp.ImportOptions{CheckpointPublicKeyB64: operator.PublicKeyB64}
+	if _, err := dcp.VerifyLedgerExport(ctx, bytes.NewReader(buf.Bytes()), opts); err != nil {
+		t.Fatalf("untouched export should verify: %v", err)
+	}
+
+	tampered := strings.Replace(buf.String(), `"outcome":"ok"`, `"outcome":"failed"`, 1)
+	if _, err := dcp.VerifyLedgerExport(ctx, strings.NewReader(tampered), opts); err == nil {
+		t.Fatal("tampered entry should fail verification")
+	}
+
+	other, _ := dcp.GenerateKeypair()
+	opts.CheckpointPublicKeyB64 = other.PublicKeyB64
+	if _, err := dcp.VerifyLedgerExport(ctx, bytes.NewReader(buf.Bytes()), opts); err == nil {
+		t.Fatal("checkpoint signed by another key should fail verification")
+	}
+
+	opts = dcp.ImportOptions{RequireCheckpoint: true}
+	if _, err := dcp.VerifyLedgerExport(ctx, bytes.NewReader(buf.Bytes()), opts); err == nil {
+		t.Fatal("export without a final checkpoint should fail when one is required")
+	}
+}
+
+func TestExportImportPrunedLedger(t *testing.T) {
+	ctx := context.Background()
+	src, agent, operator := exportFixture(t, 6)
+	if _, err := dcp.PruneLedger(ctx, src, 2, "agent001", operator.SecretKeyB64, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := dcp.ExportLedger(ctx, src, &buf, dcp.ExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	dst := dcp.NewMemoryLedger()
+	res, err := dcp.ImportLedger(ctx, bytes.NewReader(buf.Bytes()), dst, dcp.ImportOptions{
+		CheckpointPublicKeyB64: operator.PublicKeyB64,
+		Stream:                 dcp.AuditStreamOptions{AgentPublicKeyB64: agent.PublicKeyB64},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.PruneSummary == nil || res.Entries != 4 {
+		t.Fatalf("imported %d entries, summary %v", res.Entries, res.PruneSummary)
+	}
+	if n, _ := dst.Len(ctx); n != 6 {
+		t.Fatalf("imported ledger Len = %d, want 6", n)
+	}
+	if _, r := dcp.VerifyLedger(ctx, dst, dcp.AuditStreamOptions{}); !r.Verified {
+		t.Fatalf("imported pruned ledger should verify: %v", r.Errors)
+	}
+}
@@ -0,0 +1,180 @@
+package dcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MemoryLedger is an in-memory LedgerStore, for tests and short-lived agents.
+type MemoryLedger struct {
+	mu sync.RWMutex
+	// entries[i] is the entry at index base+i; earlier entries were pruned.
+	entries     []AuditEntry
+	base        int64
+	summary     *PruneSummary
+	checkpoints []*Checkpoint
+	closed      bool
+}
+
+// NewMemoryLedger returns an empty in-memory ledger.
+func NewMemoryLedger() *MemoryLedger {
+	return &MemoryLedger{}
+}
+
+var (
+	_ LedgerStore   = (*MemoryLedger)(nil)
+	_ ChainAppender = (*MemoryLedger)(nil)
+	_ LedgerPruner  = (*MemoryLedger)(nil)
+)
+
+func (m *MemoryLedger) AppendEntry(ctx context.Context, entry AuditEntry) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0, ErrLedgerClosed
+	}
+	m.entries = append(m.entries, entry)
+	return m.base + int64(len(m.entries)-1), nil
+}
+
+func (m *MemoryLedger) AppendChained(ctx context.Context, entry AuditEntry, prepare func(*AuditEntry) error) (int64, AuditEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, entry, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0, entry, ErrLedgerClosed
+	}
+	entry.PrevHash = "GENESIS"
+	if m.summary != nil {
+		entry.PrevHash = m.summary.LastEntryHash
+	}
+	if n := len(m.entries); n > 0 {
+		h, err := HashObject(m.entries[n-1])
+		if err != nil {
+			return 0, entry, err
+		}
+		entry.PrevHash = h
+	}
+	if err := prepare(&entry); err != nil {
+		return 0, entry, err
+	}
+	m.entries = append(m.entries, entry)
+	return m.base + int64(len(m.entries)-1), entry, nil
+}
+
+func (m *MemoryLedger) GetByIndex(ctx context.Context, index int64) (AuditEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return AuditEntry{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if index >= 0 && index < m.base {
+		return AuditEntry{}, ErrLedgerEntryPruned
+	}
+	if index < 0 || index >= m.base+int64(len(m.entries)) {
+		return AuditEntry{}, ErrLedgerEntryNotFound
+	}
+	return m.entries[index-m.base], nil
+}
+
+func (m *MemoryLedger) Range(ctx context.Context, start, end int64, fn func(int64, AuditEntry) error) error {
+	m.mu.RLock()
+	n := m.base + int64(len(m.entries))
+	if end > n {
+		end = n
+	}
+	if start < m.base {
+		start = m.base
+	}
+	var snapshot []AuditEntry
+	if start < end {
+		snapshot = m.entries[start-m.base : end-m.base]
+	}
+	m.mu.RUnlock()
+	for i, e := range snapshot {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(start+int64(i), e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MemoryLedger) Len(ctx context.Context) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.base + int64(len(m.entries)), nil
+}
+
+func (m *MemoryLedger) SaveCheckpoint(ctx context.Context, cp *Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrLedgerClosed
+	}
+	saved := *cp
+	m.checkpoints = append(m.checkpoints, &saved)
+	return nil
+}
+
+func (m *MemoryLedger) LatestCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.checkpoints) == 0 {
+		return nil, ErrNoCheckpoint
+	}
+	cp := *m.checkpoints[len(m.checkpoints)-1]
+	return &cp, nil
+}
+
+func (m *MemoryLedger) Prune(ctx context.Context, summary *PruneSummary) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrLedgerClosed
+	}
+	n := m.base + int64(len(m.entries))
+	switch {
+	case n == 0:
+	case summary.PrunedSize <= m.base || summary.PrunedSize > n:
+		return fmt.Errorf("ledger: cannot prune to %d (stored entries %d..%d)", summary.PrunedSize, m.base, n)
+	default:
+		h, err := HashObject(m.entries[summary.PrunedSize-1-m.base])
+		if err != nil {
+			return err
+		}
+		if h != summary.LastEntryHash {
+			return fmt.Errorf("ledger: prune summary does not match entry %d", summary.PrunedSize-1)
+		}
+		m.entries = append([]AuditEntry(nil), m.entries[summary.PrunedSize-m.base:]...)
+	}
+	saved := *summary
+	m.summary = &saved
+	m.base = summary.PrunedSize
+	return nil
+}
+
+func (m *MemoryLedger) LatestPruneSummary(ctx context.Context) (*PruneSummary, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.summary == nil {
+		return nil, ErrNotPruned
+	}
+	s := *m.summary
+	return &s, nil
+}
+
+func (m *MemoryLedger) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
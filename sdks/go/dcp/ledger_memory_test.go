@@ -0,0 +1,12 @@
+package dcp_test
+
+import (
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/ledgertest"
+)
+
+func TestMemoryLedger(t *testing.T) {
+	ledgertest.Run(t, func(*testing.T) dcp.LedgerStore { return dcp.NewMemoryLedger() })
+}
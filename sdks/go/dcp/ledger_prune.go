@@ -0,0 +1,329 @@
+package dcp
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// ErrLedgerEntryPruned is returned for an index removed by pruning.
+var ErrLedgerEntryPruned = errors.New("ledger: entry pruned")
+
+// ErrNotPruned is returned by LatestPruneSummary before any pruning.
+var ErrNotPruned = errors.New("ledger: not pruned")
+
+// PruneSummary stands in for the first PrunedSize entries of a ledger once
+// they are removed. It carries what is needed to keep verifying the rest:
+// the hash of the last pruned entry (the next entry's prev_hash), the Merkle
+// frontier from which later roots are computed, and a consistency proof from
+// PrunedRoot to a signed checkpoint, which ties the summary to the ledger's
+// published history.
+type PruneSummary struct {
+	DCPVersion    string `json:"dcp_version"`
+	Origin        string `json:"origin"`
+	PrunedSize    int64  `json:"pruned_size"`
+	PrunedRoot    string `json:"pruned_root"`
+	LastEntryHash string `json:"last_entry_hash"`
+	// Frontier holds, per tree level from the leaves up, the hex root of the
+	// complete subtree ending at PrunedSize, or "" where there is none.
+	Frontier         []string   `json:"frontier"`
+	Checkpoint       Checkpoint `json:"checkpoint"`
+	ConsistencyProof []string   `json:"consistency_proof"`
+	Timestamp        string     `json:"timestamp"`
+	Signature        string     `json:"signature,omitempty"`
+}
+
+// LedgerPruner is implemented by stores that can drop old entries. Indexes of
+// the remaining entries do not change and Len still counts pruned entries.
+type LedgerPruner interface {
+	// Prune removes the entries before summary.PrunedSize and records
+	// summary. An empty store adopts summary as its starting point.
+	Prune(ctx context.Context, summary *PruneSummary) error
+	// LatestPruneSummary returns the summary of the most recent Prune or
+	// ErrNotPruned.
+	LatestPruneSummary(ctx context.Context) (*PruneSummary, error)
+}
+
+func (s PruneSummary) unsigned() PruneSummary {
+	s.Signature = ""
+	return s
+}
+
+// Sign sets Signature to an Ed25519 signature over the canonical summary
+// without its signature member.
+func (s *PruneSummary) Sign(secretKeyB64 string) error {
+	sig, err := SignObject(s.unsigned(), secretKeyB64)
+	if err != nil {
+		return fmt.Errorf("sign prune summary: %w", err)
+	}
+	s.Signature = sig
+	return nil
+}
+
+// Verify checks the summary's signature and that of its checkpoint against
+// publicKeyB64, then its internal consistency (see Check).
+func (s *PruneSummary) Verify(publicKeyB64 string) error {
+	if s.Signature == "" {
+		return fmt.Errorf("prune summary is not signed")
+	}
+	if ok, err := VerifyObject(s.unsigned(), s.Signature, publicKeyB64); err != nil || !ok {
+		return fmt.Errorf("prune summary signature invalid")
+	}
+	if ok, err := s.Checkpoint.Verify(publicKeyB64); err != nil || !ok {
+		return fmt.Errorf("prune summary checkpoint signature invalid")
+	}
+	return s.Check()
+}
+
+// Check verifies that the frontier yields PrunedRoot and that PrunedRoot is
+// consistent with the embedded checkpoint. Signatures are not checked.
+func (s *PruneSummary) Check() error {
+	if s.PrunedSize <= 0 {
+		return fmt.Errorf("prune summary: pruned_size must be positive")
+	}
+	acc, err := s.accumulator()
+	if err != nil {
+		return err
+	}
+	if "sha256:"+acc.Root() != s.PrunedRoot {
+		return fmt.Errorf("prune summary: frontier does not match pruned_root")
+	}
+	if s.Checkpoint.TreeSize < s.PrunedSize {
+		return fmt.Errorf("prune summary: checkpoint size %d is below pruned_size %d", s.Checkpoint.TreeSize, s.PrunedSize)
+	}
+	if !VerifyMerkleConsistency(int(s.PrunedSize), int(s.Checkpoint.TreeSize), s.PrunedRoot, s.Checkpoint.RootHash, s.ConsistencyProof) {
+		return fmt.Errorf("prune summary: pruned_root is not consistent with checkpoint %d", s.Checkpoint.TreeSize)
+	}
+	return nil
+}
+
+// accumulator restores the Merkle state at PrunedSize from the frontier.
+func (s *PruneSummary) accumulator() (*MerkleAccumulator, error) {
+	n := int(s.PrunedSize)
+	if len(s.Frontier) != bits.Len(uint(n)) {
+		return nil, fmt.Errorf("prune summary: frontier has %d levels, want %d", len(s.Frontier), bits.Len(uint(n)))
+	}
+	acc := &MerkleAccumulator{size: n, pending: make([]*[32]byte, len(s.Frontier))}
+	for k, f := range s.Frontier {
+		if n>>uint(k)&1 == 0 {
+			if f != "" {
+				return nil, fmt.Errorf("prune summary: unexpected frontier node at level %d", k)
+			}
+			continue
+		}
+		h, err := decodeHash(f)
+		if err != nil {
+			return nil, fmt.Errorf("prune summary: frontier level %d: %w", k, err)
+		}
+		acc.pending[k] = &h
+	}
+	return acc, nil
+}
+
+// tree returns a MerkleTree of PrunedSize leaves holding only the frontier
+// and the last pruned leaf. Every node a later root or proof needs is either
+// one of those or covers unpruned leaves, so appending the remaining entries
+// yields correct roots and proofs for sizes above PrunedSize.
+func (s *PruneSummary) tree() (*MerkleTree, error) {
+	n := int(s.PrunedSize)
+	last, err := decodeHash(s.LastEntryHash)
+	if err != nil {
+		return nil, fmt.Errorf("prune summary: last_entry_hash: %w", err)
+	}
+	t := NewMerkleTree()
+	t.levels = nil
+	for k := 0; k == 0 || n>>uint(k) > 0; k++ {
+		t.levels = append(t.levels, make([][32]byte, n>>uint(k)))
+	}
+	t.levels[0][n-1] = last
+	for k, f := range s.Frontier {
+		if f == "" {
+			continue
+		}
+		h, err := decodeHash(f)
+		if err != nil {
+			return nil, fmt.Errorf("prune summary: frontier level %d: %w", k, err)
+		}
+		t.levels[k][(n>>uint(k))-1] = h
+	}
+	return t, nil
+}
+
+// resumeStreamVerifier returns a StreamVerifier positioned after the last
+// pruned entry.
+func resumeStreamVerifier(opts AuditStreamOptions, s *PruneSummary) (*StreamVerifier, error) {
+	acc, err := s.accumulator()
+	if err != nil {
+		return nil, err
+	}
+	opts.ChainStart = s.LastEntryHash
+	v := NewStreamVerifier(opts)
+	v.count = acc.size
+	v.merkle = *acc
+	return v, nil
+}
+
+// latestPruneSummary returns store's prune summary, or nil if the store
+// cannot prune or has not been pruned.
+func latestPruneSummary(ctx context.Context, store LedgerStore) (*PruneSummary, error) {
+	p, ok := store.(LedgerPruner)
+	if !ok {
+		return nil, nil
+	}
+	s, err := p.LatestPruneSummary(ctx)
+	if errors.Is(err, ErrNotPruned) {
+		return nil, nil
+	}
+	return s, err
+}
+
+// PruneLedger removes the entries before index before, replacing them with a
+// PruneSummary signed with secretKeyB64. The store's latest checkpoint must
+// cover at least before entries; the summary proves consistency with it.
+// Building the proof reads every entry up to that checkpoint.
+func PruneLedger(ctx context.Context, store LedgerStore, before int64, origin, secretKeyB64 string, now time.Time) (*PruneSummary, error) {
+	p, ok := store.(LedgerPruner)
+	if !ok {
+		return nil, fmt.Errorf("prune ledger: store does not support pruning")
+	}
+	cp, err := store.LatestCheckpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("prune ledger: %w", err)
+	}
+	if before <= 0 || before > cp.TreeSize {
+		return nil, fmt.Errorf("prune ledger: index %d is outside the latest checkpoint (size %d)", before, cp.TreeSize)
+	}
+	prev, err := latestPruneSummary(ctx, store)
+	if err != nil {
+		return nil, fmt.Errorf("prune ledger: %w", err)
+	}
+	if prev != nil && before <= prev.PrunedSize {
+		return nil, fmt.Errorf("prune ledger: already pruned to %d", prev.PrunedSize)
+	}
+	tree, err := checkpointTree(ctx, store, prev, cp)
+	if err != nil {
+		return nil, fmt.Errorf("prune ledger: %w", err)
+	}
+
+	n := int(before)
+	root, _ := tree.RootAt(n)
+	proof, err := tree.ConsistencyProof(n)
+	if err != nil {
+		return nil, fmt.Errorf("prune ledger: %w", err)
+	}
+	last, _ := tree.Leaf(n - 1)
+	frontier := make([]string, bits.Len(uint(n)))
+	for k := range frontier {
+		if n>>uint(k)&1 == 1 {
+			frontier[k] = hex.EncodeToString(tree.levels[k][(n>>uint(k))-1][:])
+		}
+	}
+	s := &PruneSummary{
+		DCPVersion:       "1.0",
+		Origin:           origin,
+		PrunedSize:       before,
+		PrunedRoot:       "sha256:" + root,
+		LastEntryHash:    last,
+		Frontier:         frontier,
+		Checkpoint:       *cp,
+		ConsistencyProof: proof,
+		Timestamp:        FormatTime(now),
+	}
+	if err := s.Sign(secretKeyB64); err != nil {
+		return nil, err
+	}
+	if err := p.Prune(ctx, s); err != nil {
+		return nil, fmt.Errorf("prune ledger: %w", err)
+	}
+	return s, nil
+}
+
+// checkpointTree rebuilds the ledger's Merkle tree up to cp.TreeSize,
+// starting from summary when the store is pruned, and checks it against cp.
+func checkpointTree(ctx context.Context, store LedgerStore, summary *PruneSummary, cp *Checkpoint) (*MerkleTree, error) {
+	tree := NewMerkleTree()
+	start := int64(0)
+	if summary != nil {
+		var err error
+		if tree, err = summary.tree(); err != nil {
+			return nil, err
+		}
+		start = summary.PrunedSize
+	}
+	err := store.Range(ctx, start, cp.TreeSize, func(_ int64, e AuditEntry) error {
+		h, err := HashObject(e)
+		if err != nil {
+			return err
+		}
+		return tree.Append(h)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if int64(tree.Size()) != cp.TreeSize || "sha256:"+tree.Root() != cp.RootHash {
+		return nil, fmt.Errorf("entries do not match checkpoint %d", cp.TreeSize)
+	}
+	return tree, nil
+}
+
+// entryHashAt returns the hash of entry i, falling back to the prune
+// summary when i is the last pruned entry.
+func entryHashAt(ctx context.Context, store LedgerStore, i int64) (string, error) {
+	e, err := store.GetByIndex(ctx, i)
+	if errors.Is(err, ErrLedgerEntryPruned) {
+		s, serr := latestPruneSummary(ctx, store)
+		if serr != nil {
+			return "", serr
+		}
+		if s != nil && s.PrunedSize == i+1 {
+			return s.LastEntryHash, nil
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return HashObject(e)
+}
+
+// ledgerStart returns the first stored index of store and a verifier
+// positioned there.
+func ledgerStart(ctx context.Context, store LedgerStore, opts AuditStreamOptions) (int64, *StreamVerifier, *PruneSummary, error) {
+	s, err := latestPruneSummary(ctx, store)
+	if err != nil || s == nil {
+		return 0, NewStreamVerifier(opts), nil, err
+	}
+	if err := s.Check(); err != nil {
+		return 0, NewStreamVerifier(opts), nil, err
+	}
+	v, err := resumeStreamVerifier(opts, s)
+	if err != nil {
+		return 0, NewStreamVerifier(opts), nil, err
+	}
+	return s.PrunedSize, v, s, nil
+}
+
+// verifyPrunedRange streams store's entries from start through v and, if s
+// is set, checks the running root against s's checkpoint when reaching it.
+func verifyPrunedRange(ctx context.Context, store LedgerStore, v *StreamVerifier, start int64, s *PruneSummary) error {
+	checkAt := int64(-1)
+	if s != nil && s.Checkpoint.TreeSize > s.PrunedSize {
+		checkAt = s.Checkpoint.TreeSize
+	}
+	return store.Range(ctx, start, math.MaxInt64, func(_ int64, e AuditEntry) error {
+		raw, err := Canonicalize(e)
+		if err != nil {
+			return err
+		}
+		if err := v.Push([]byte(raw)); err != nil {
+			return err
+		}
+		if int64(v.Count()) == checkAt && v.MerkleRoot() != s.Checkpoint.RootHash {
+			return fmt.Errorf("ledger root at %d does not match the prune summary's checkpoint", checkAt)
+		}
+		return nil
+	})
+}
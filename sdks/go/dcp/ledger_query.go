@@ -0,0 +1,153 @@
+package dcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// AuditQuery selects ledger entries for QueryLedger. Empty fields match
+// everything; From is inclusive and To exclusive. Entries whose timestamp is
+// not RFC 3339 never match a time bound.
+type AuditQuery struct {
+	AgentID  string
+	HumanID  string
+	IntentID string
+	// ActionType matches the action_type of the entry's intent, resolved
+	// through Intents.
+	ActionType string
+	// IdempotencyKey matches the idempotency_key of the entry's intent,
+	// resolved through Intents, to join entries to a provider's logs.
+	IdempotencyKey string
+	Outcome        string
+	From           time.Time
+	To             time.Time
+
+	// Intents resolves an intent_id; required when ActionType or
+	// IdempotencyKey is set.
+	Intents func(intentID string) (Intent, bool)
+
+	// Cursor is the ledger index to resume from, taken from AuditPage.Next.
+	Cursor int64
+	// Limit caps the entries per page; 0 means no limit.
+	Limit int
+	// VerifyHashes checks the prev_hash chain of every entry read, matching
+	// or not, failing the query at the first broken link.
+	VerifyHashes bool
+}
+
+// IndexedAuditEntry is a ledger entry with its index.
+type IndexedAuditEntry struct {
+	Index int64
+	Entry AuditEntry
+}
+
+// AuditPage is one page of query results.
+type AuditPage struct {
+	Entries []IndexedAuditEntry
+	// Next is the Cursor for the following page, or -1 once the ledger is
+	// exhausted.
+	Next int64
+}
+
+var errQueryPageFull = errors.New("query page full")
+
+// QueryLedger scans store from q.Cursor and returns the entries matching q,
+// in ledger order.
+func QueryLedger(ctx context.Context, store LedgerStore, q AuditQuery) (*AuditPage, error) {
+	if q.ActionType != "" && q.Intents == nil {
+		return nil, fmt.Errorf("audit query: ActionType needs an Intents lookup")
+	}
+	if q.IdempotencyKey != "" && q.Intents == nil {
+		return nil, fmt.Errorf("audit query: IdempotencyKey needs an Intents lookup")
+	}
+	start := q.Cursor
+	if start < 0 {
+		start = 0
+	}
+	summary, err := latestPruneSummary(ctx, store)
+	if err != nil {
+		return nil, fmt.Errorf("audit query: %w", err)
+	}
+	if summary != nil && start < summary.PrunedSize {
+		start = summary.PrunedSize
+	}
+	prev := "GENESIS"
+	if q.VerifyHashes && start > 0 {
+		if prev, err = entryHashAt(ctx, store, start-1); err != nil {
+			if errors.Is(err, ErrLedgerEntryNotFound) {
+				return &AuditPage{Next: -1}, nil
+			}
+			return nil, fmt.Errorf("audit query: %w", err)
+		}
+	}
+
+	page := &AuditPage{Next: -1}
+	err = store.Range(ctx, start, math.MaxInt64, func(i int64, e AuditEntry) error {
+		if q.VerifyHashes {
+			if e.PrevHash != prev {
+				return fmt.Errorf("prev_hash chain (entry %d): expected %s, got %s", i, prev, e.PrevHash)
+			}
+			h, err := HashObject(e)
+			if err != nil {
+				return err
+			}
+			prev = h
+		}
+		if !q.matches(e) {
+			return nil
+		}
+		page.Entries = append(page.Entries, IndexedAuditEntry{Index: i, Entry: e})
+		if q.Limit > 0 && len(page.Entries) == q.Limit {
+			page.Next = i + 1
+			return errQueryPageFull
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errQueryPageFull) {
+		return nil, fmt.Errorf("audit query: %w", err)
+	}
+	return page, nil
+}
+
+func (q *AuditQuery) matches(e AuditEntry) bool {
+	if q.AgentID != "" && e.AgentID != q.AgentID {
+		return false
+	}
+	if q.HumanID != "" && e.HumanID != q.HumanID {
+		return false
+	}
+	if q.IntentID != "" && e.IntentID != q.IntentID {
+		return false
+	}
+	if q.Outcome != "" && e.Outcome != q.Outcome {
+		return false
+	}
+	if !q.From.IsZero() || !q.To.IsZero() {
+		ts, err := e.TimestampTime()
+		if err != nil {
+			return false
+		}
+		if !q.From.IsZero() && ts.Before(q.From) {
+			return false
+		}
+		if !q.To.IsZero() && !ts.Before(q.To) {
+			return false
+		}
+	}
+	if q.ActionType != "" || q.IdempotencyKey != "" {
+		intent, ok := q.Intents(e.IntentID)
+		if !ok {
+			return false
+		}
+		if q.ActionType != "" && intent.ActionType != q.ActionType {
+			return false
+		}
+		if q.IdempotencyKey != "" && intent.IdempotencyKey != q.IdempotencyKey {
+			return false
+		}
+	}
+	return true
+}
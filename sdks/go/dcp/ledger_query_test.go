@@ -0,0 +1,126 @@
+package dcp_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/ledgertest"
+)
+
+func queryFixture(t *testing.T) *dcp.MemoryLedger {
+	t.Helper()
+	ctx := context.Background()
+	agent, _ := dcp.GenerateKeypair()
+	store := dcp.NewMemoryLedger()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		e := ledgertest.Entry(i)
+		e.Timestamp = base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		e.IntentID = fmt.Sprintf("intent%03d", i%2)
+		if i%3 == 0 {
+			e.Outcome = "failed"
+		}
+		if i >= 5 {
+			e.HumanID = "human002"
+		}
+		if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, store, e, agent.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func indexes(p *dcp.AuditPage) []int64 {
+	out := make([]int64, len(p.Entries))
+	for i, e := range p.Entries {
+		out[i] = e.Index
+	}
+	return out
+}
+
+func TestQueryLedgerFilters(t *testing.T) {
+	ctx := context.Background()
+	store := queryFixture(t)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	intents := func(id string) (dcp.Intent, bool) {
+		if id == "intent001" {
+			return dcp.Intent{IntentID: id, ActionType: "send_email", IdempotencyKey: "dcp-key001"}, true
+		}
+		return dcp.Intent{IntentID: id, ActionType: "browse"}, true
+	}
+
+	cases := []struct {
+		name string
+		q    dcp.AuditQuery
+		want string
+	}{
+		{"all", dcp.AuditQuery{}, "[0 1 2 3 4 5 6 7 8 9]"},
+		{"human", dcp.AuditQuery{HumanID: "human002"}, "[5 6 7 8 9]"},
+		{"intent", dcp.AuditQuery{IntentID: "intent000"}, "[0 2 4 6 8]"},
+		{"outcome", dcp.AuditQuery{Outcome: "failed"}, "[0 3 6 9]"},
+		{"time", dcp.AuditQuery{From: base.Add(2 * time.Hour), To: base.Add(5 * time.Hour)}, "[2 3 4]"},
+		{"action type", dcp.AuditQuery{ActionType: "send_email", Intents: intents}, "[1 3 5 7 9]"},
+		{"idempotency key", dcp.AuditQuery{IdempotencyKey: "dcp-key001", Intents: intents}, "[1 3 5 7 9]"},
+		{"combined", dcp.AuditQuery{AgentID: "agent001", HumanID: "human001", Outcome: "failed"}, "[0 3]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.q.VerifyHashes = true
+			p, err := dcp.QueryLedger(ctx, store, tc.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprint(indexes(p)); got != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := dcp.QueryLedger(ctx, store, dcp.AuditQuery{ActionType: "browse"}); err == nil {
+		t.Fatal("ActionType without Intents should fail")
+	}
+	if _, err := dcp.QueryLedger(ctx, store, dcp.AuditQuery{IdempotencyKey: "dcp-key001"}); err == nil {
+		t.Fatal("IdempotencyKey without Intents should fail")
+	}
+}
+
+func TestQueryLedgerPagination(t *testing.T) {
+	ctx := context.Background()
+	store := queryFixture(t)
+	q := dcp.AuditQuery{IntentID: "intent000", Limit: 2, VerifyHashes: true}
+	var pages []string
+	for {
+		p, err := dcp.QueryLedger(ctx, store, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, fmt.Sprint(indexes(p)))
+		if p.Next < 0 {
+			break
+		}
+		q.Cursor = p.Next
+	}
+	if got := fmt.Sprint(pages); got != "[[0 2] [4 6] [8]]" {
+		t.Fatalf("pages = %s", got)
+	}
+}
+
+func TestQueryLedgerVerifyHashes(t *testing.T) {
+	ctx := context.Background()
+	store := dcp.NewMemoryLedger()
+	// Every ledgertest.Entry claims GENESIS, so entry 1 breaks the chain.
+	for i := 0; i < 4; i++ {
+		if _, err := store.AppendEntry(ctx, ledgertest.Entry(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := dcp.QueryLedger(ctx, store, dcp.AuditQuery{Outcome: "ok"}); err != nil {
+		t.Fatalf("query without verification: %v", err)
+	}
+	if _, err := dcp.QueryLedger(ctx, store, dcp.AuditQuery{Outcome: "ok", VerifyHashes: true}); err == nil {
+		t.Fatal("broken chain should fail a verifying query")
+	}
+}
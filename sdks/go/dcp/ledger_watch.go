@@ -0,0 +1,179 @@
+package dcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultWatchInterval is how often a Watcher polls when WatcherOptions
+// does not say.
+const DefaultWatchInterval = time.Second
+
+// WatchViolation reports an entry that failed verification.
+type WatchViolation struct {
+	// Index is the entry's position in the ledger or stream.
+	Index int64
+	// Raw is the entry as read.
+	Raw []byte
+	Err error
+}
+
+// WatcherOptions configures a Watcher.
+type WatcherOptions struct {
+	// Stream configures the per-entry checks.
+	Stream AuditStreamOptions
+	// Interval is the polling period; zero means DefaultWatchInterval.
+	Interval time.Duration
+	// OnEntry, if set, is called for every entry that verifies.
+	OnEntry func(index int64, entry AuditEntry)
+	// OnViolation is called for every entry that fails. The watcher then
+	// resumes from that entry, so one bad entry is reported once rather
+	// than breaking every later link. When nil, the first violation ends
+	// the watch with its error.
+	OnViolation func(WatchViolation)
+}
+
+// Watcher tails a ledger or JSONL audit stream and verifies each new entry
+// against the chain as it arrives, for monitoring running agents.
+type Watcher struct {
+	opts WatcherOptions
+	v    *StreamVerifier
+	next int64
+}
+
+// NewWatcher returns a watcher positioned before the first entry.
+func NewWatcher(opts WatcherOptions) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+	return &Watcher{opts: opts}
+}
+
+// Count returns the number of entries processed, verified or not.
+func (w *Watcher) Count() int64 {
+	return w.next
+}
+
+// handle verifies one entry and dispatches the callbacks.
+func (w *Watcher) handle(raw []byte) error {
+	index := w.next
+	err := w.v.Push(raw)
+	if err != nil {
+		if w.opts.OnViolation == nil {
+			return err
+		}
+		w.opts.OnViolation(WatchViolation{Index: index, Raw: raw, Err: err})
+		w.v.skip(raw)
+	} else if w.opts.OnEntry != nil {
+		var e AuditEntry
+		if json.Unmarshal(raw, &e) == nil {
+			w.opts.OnEntry(index, e)
+		}
+	}
+	w.next++
+	return nil
+}
+
+// wait sleeps for the polling interval, returning ctx's error if it ends first.
+func (w *Watcher) wait(ctx context.Context) error {
+	t := time.NewTimer(w.opts.Interval)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// WatchLedger follows store until ctx is done, verifying entries appended
+// after the watch starts as well as those already present. A pruned store is
+// followed from its prune summary. It returns ctx's error, or the first
+// violation when OnViolation is nil.
+func (w *Watcher) WatchLedger(ctx context.Context, store LedgerStore) error {
+	if w.v == nil {
+		start, v, _, err := ledgerStart(ctx, store, w.opts.Stream)
+		if err != nil {
+			return fmt.Errorf("watch ledger: %w", err)
+		}
+		w.v, w.next = v, start
+	}
+	for {
+		n, err := store.Len(ctx)
+		if err != nil {
+			return fmt.Errorf("watch ledger: %w", err)
+		}
+		if n < w.next {
+			err := fmt.Errorf("ledger shrank from %d to %d entries", w.next, n)
+			if w.opts.OnViolation == nil {
+				return err
+			}
+			w.opts.OnViolation(WatchViolation{Index: n, Err: err})
+			return err
+		}
+		err = store.Range(ctx, w.next, n, func(_ int64, e AuditEntry) error {
+			raw, err := Canonicalize(e)
+			if err != nil {
+				return err
+			}
+			return w.handle([]byte(raw))
+		})
+		if err != nil {
+			return err
+		}
+		if err := w.wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// WatchStream verifies a JSONL stream of audit entries, one per line. With
+// follow it behaves like tail -f: at EOF it waits for more data until ctx is
+// done, so r can be a file another process appends to. Without follow it
+// returns nil at EOF. Blank lines are skipped.
+func (w *Watcher) WatchStream(ctx context.Context, r io.Reader, follow bool) error {
+	if w.v == nil {
+		w.v = NewStreamVerifier(w.opts.Stream)
+	}
+	br := bufio.NewReader(r)
+	var partial []byte
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		chunk, err := br.ReadBytes('\n')
+		partial = append(partial, chunk...)
+		if len(partial) > maxAuditLineBytes {
+			return fmt.Errorf("watch stream: line %d exceeds %d bytes", w.next, maxAuditLineBytes)
+		}
+		if err == nil {
+			line := append([]byte(nil), bytes.TrimSpace(partial)...)
+			partial = partial[:0]
+			if len(line) > 0 {
+				if err := w.handle(line); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return fmt.Errorf("watch stream: %w", err)
+		}
+		if !follow {
+			// A final line without a newline is still an entry.
+			if line := bytes.TrimSpace(partial); len(line) > 0 {
+				return w.handle(line)
+			}
+			return nil
+		}
+		if err := w.wait(ctx); err != nil {
+			return err
+		}
+	}
+}
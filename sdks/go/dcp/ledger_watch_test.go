@@ -0,0 +1,167 @@
+package dcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/ledgertest"
+)
+
+// chainedJSONL returns n linked audit entries as JSONL.
+func chainedJSONL(t *testing.T, n int) string {
+	t.Helper()
+	agent, _ := dcp.GenerateKeypair()
+	var entries []dcp.AuditEntry
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		var err error
+		if entries, err = dcp.AppendSignedAuditEntry(entries, ledgertest.Entry(i), agent.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+		line, _ := json.Marshal(entries[i])
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+func TestWatchLedgerFollowsAppends(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	agent, _ := dcp.GenerateKeypair()
+	store := dcp.NewMemoryLedger()
+	for i := 0; i < 3; i++ {
+		if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, store, ledgertest.Entry(i), agent.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	var seen []int64
+	w := dcp.NewWatcher(dcp.WatcherOptions{
+		Stream:   dcp.AuditStreamOptions{AgentPublicKeyB64: agent.PublicKeyB64},
+		Interval: time.Millisecond,
+		OnEntry: func(i int64, _ dcp.AuditEntry) {
+			mu.Lock()
+			seen = append(seen, i)
+			if len(seen) == 6 {
+				cancel()
+			}
+			mu.Unlock()
+		},
+	})
+	done := make(chan error, 1)
+	go func() { done <- w.WatchLedger(ctx, store) }()
+	for i := 3; i < 6; i++ {
+		if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, store, ledgertest.Entry(i), agent.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("watch ended with %v, want context.Canceled", err)
+	}
+	if len(seen) != 6 || w.Count() != 6 {
+		t.Fatalf("saw %v entries, count %d", seen, w.Count())
+	}
+}
+
+func TestWatchLedgerReportsViolations(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	store := dcp.NewMemoryLedger()
+	// Every ledgertest.Entry claims GENESIS, so entries 1 and 2 break the chain.
+	for i := 0; i < 3; i++ {
+		if _, err := store.AppendEntry(ctx, ledgertest.Entry(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var violations []int64
+	w := dcp.NewWatcher(dcp.WatcherOptions{
+		Interval: time.Millisecond,
+		OnViolation: func(v dcp.WatchViolation) {
+			violations = append(violations, v.Index)
+			if v.Index == 2 {
+				cancel()
+			}
+		},
+	})
+	w.WatchLedger(ctx, store)
+	if len(violations) != 2 || violations[0] != 1 {
+		t.Fatalf("violations at %v, want [1 2]", violations)
+	}
+
+	strict := dcp.NewWatcher(dcp.WatcherOptions{Interval: time.Millisecond})
+	err := strict.WatchLedger(context.Background(), store)
+	if err == nil || !strings.Contains(err.Error(), "prev_hash chain (entry 1)") {
+		t.Fatalf("without OnViolation the watch should stop at the first violation, got %v", err)
+	}
+}
+
+func TestWatchStream(t *testing.T) {
+	entries := chainedJSONL(t, 4)
+	lines := strings.Split(strings.TrimSpace(entries), "\n")
+	tampered := strings.Join([]string{lines[0], lines[1], strings.Replace(lines[2], `"outcome":"ok"`, `"outcome":"x"`, 1), lines[3]}, "\n")
+
+	var violations []dcp.WatchViolation
+	w := dcp.NewWatcher(dcp.WatcherOptions{OnViolation: func(v dcp.WatchViolation) { violations = append(violations, v) }})
+	if err := w.WatchStream(context.Background(), strings.NewReader(tampered), false); err != nil {
+		t.Fatal(err)
+	}
+	// The tampered entry itself still links; the next one no longer does.
+	if len(violations) != 1 || violations[0].Index != 3 || w.Count() != 4 {
+		t.Fatalf("violations %+v after %d entries", violations, w.Count())
+	}
+}
+
+func TestWatchStreamFollowsFile(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lines := strings.Split(strings.TrimSpace(chainedJSONL(t, 3)), "\n")
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := os.WriteFile(path, []byte(lines[0]+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	count := make(chan int64, 3)
+	w := dcp.NewWatcher(dcp.WatcherOptions{
+		Interval: time.Millisecond,
+		OnEntry:  func(i int64, _ dcp.AuditEntry) { count <- i },
+	})
+	done := make(chan error, 1)
+	go func() { done <- w.WatchStream(ctx, f, true) }()
+	<-count
+
+	out, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Append in two writes that split a line, to exercise partial reads.
+	rest := lines[1] + "\n" + lines[2] + "\n"
+	half := len(rest) / 2
+	out.WriteString(rest[:half])
+	time.Sleep(5 * time.Millisecond)
+	out.WriteString(rest[half:])
+	out.Close()
+
+	<-count
+	if last := <-count; last != 2 {
+		t.Fatalf("last entry index %d, want 2", last)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("watch ended with %v", err)
+	}
+}
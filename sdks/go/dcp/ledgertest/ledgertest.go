@@ -0,0 +1,244 @@
+// Package ledgertest provides conformance tests shared by every
+// dcp.LedgerStore implementation.
+package ledgertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Run exercises a LedgerStore. newStore must return a fresh, empty store
+// each time it is called; Run closes the stores it creates.
+func Run(t *testing.T, newStore func(t *testing.T) dcp.LedgerStore) {
+	t.Run("AppendAndRead", func(t *testing.T) { testAppendAndRead(t, newStore(t)) })
+	t.Run("Range", func(t *testing.T) { testRange(t, newStore(t)) })
+	t.Run("Checkpoints", func(t *testing.T) { testCheckpoints(t, newStore(t)) })
+	t.Run("SignedChain", func(t *testing.T) { testSignedChain(t, newStore(t)) })
+	t.Run("Prune", func(t *testing.T) { testPrune(t, newStore(t)) })
+}
+
+// Entry returns a schema-valid audit entry for tests.
+func Entry(i int) dcp.AuditEntry {
+	tool := "tool"
+	return dcp.AuditEntry{
+		DCPVersion:     "1.0",
+		AuditID:        fmt.Sprintf("audit%06d", i),
+		PrevHash:       "GENESIS",
+		Timestamp:      "2026-01-01T00:00:00Z",
+		AgentID:        "agent001",
+		HumanID:        "human001",
+		IntentID:       "intent001",
+		IntentHash:     "0000000000000000000000000000000000000000000000000000000000000000",
+		PolicyDecision: "approved",
+		Outcome:        "ok",
+		Evidence:       dcp.AuditEvidence{Tool: &tool},
+	}
+}
+
+func testAppendAndRead(t *testing.T, s dcp.LedgerStore) {
+	defer s.Close()
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		idx, err := s.AppendEntry(ctx, Entry(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if idx != int64(i) {
+			t.Fatalf("append %d returned index %d", i, idx)
+		}
+	}
+	if n, err := s.Len(ctx); err != nil || n != 5 {
+		t.Fatalf("Len = %d, %v; want 5", n, err)
+	}
+	e, err := s.GetByIndex(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := dcp.HashObject(Entry(3))
+	if got, _ := dcp.HashObject(e); got != want {
+		t.Fatal("entry did not round-trip unchanged")
+	}
+	if _, err := s.GetByIndex(ctx, 5); !errors.Is(err, dcp.ErrLedgerEntryNotFound) {
+		t.Fatalf("out of range read: got %v, want ErrLedgerEntryNotFound", err)
+	}
+	if _, err := s.GetByIndex(ctx, -1); !errors.Is(err, dcp.ErrLedgerEntryNotFound) {
+		t.Fatalf("negative read: got %v, want ErrLedgerEntryNotFound", err)
+	}
+}
+
+func testRange(t *testing.T, s dcp.LedgerStore) {
+	defer s.Close()
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		if _, err := s.AppendEntry(ctx, Entry(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got []int64
+	err := s.Range(ctx, 2, 6, func(i int64, e dcp.AuditEntry) error {
+		if e.AuditID != Entry(int(i)).AuditID {
+			return fmt.Errorf("index %d holds %s", i, e.AuditID)
+		}
+		got = append(got, i)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != "[2 3 4 5]" {
+		t.Fatalf("Range(2, 6) visited %v", got)
+	}
+
+	entries, err := dcp.LedgerEntries(ctx, s, 8, 100)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("clamped range returned %d entries, %v", len(entries), err)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = s.Range(ctx, 0, 10, func(int64, dcp.AuditEntry) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("Range should stop at the first callback error: %v after %d calls", err, calls)
+	}
+}
+
+func testCheckpoints(t *testing.T, s dcp.LedgerStore) {
+	defer s.Close()
+	ctx := context.Background()
+	if _, err := s.LatestCheckpoint(ctx); !errors.Is(err, dcp.ErrNoCheckpoint) {
+		t.Fatalf("empty store: got %v, want ErrNoCheckpoint", err)
+	}
+	kp, _ := dcp.GenerateKeypair()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if _, err := s.AppendEntry(ctx, Entry(i)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dcp.CheckpointLedger(ctx, s, "agent001", kp.SecretKeyB64, now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cp, err := s.LatestCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.TreeSize != 3 {
+		t.Fatalf("latest checkpoint has size %d, want 3", cp.TreeSize)
+	}
+	if ok, err := cp.Verify(kp.PublicKeyB64); err != nil || !ok {
+		t.Fatalf("stored checkpoint should verify: %v", err)
+	}
+}
+
+func testSignedChain(t *testing.T, s dcp.LedgerStore) {
+	defer s.Close()
+	ctx := context.Background()
+	agent, _ := dcp.GenerateKeypair()
+	for i := 0; i < 4; i++ {
+		if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, s, Entry(i), agent.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v, r := dcp.VerifyLedger(ctx, s, dcp.AuditStreamOptions{AgentPublicKeyB64: agent.PublicKeyB64})
+	if !r.Verified {
+		t.Fatalf("ledger chain should verify: %v", r.Errors)
+	}
+	if v.Count() != 4 {
+		t.Fatalf("verified %d entries, want 4", v.Count())
+	}
+	anchor, err := dcp.LedgerChainAnchor(ctx, s, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	third, _ := s.GetByIndex(ctx, 2)
+	if anchor.PrevEntryHash != third.PrevHash {
+		t.Fatal("chain anchor should match the entry's prev_hash")
+	}
+}
+
+func testPrune(t *testing.T, s dcp.LedgerStore) {
+	defer s.Close()
+	if _, ok := s.(dcp.LedgerPruner); !ok {
+		t.Skip("store does not implement dcp.LedgerPruner")
+	}
+	ctx := context.Background()
+	agent, _ := dcp.GenerateKeypair()
+	operator, _ := dcp.GenerateKeypair()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	appendN := func(from, to int) {
+		t.Helper()
+		for i := from; i < to; i++ {
+			if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, s, Entry(i), agent.SecretKeyB64); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	appendN(0, 7)
+	if _, err := dcp.PruneLedger(ctx, s, 3, "agent001", operator.SecretKeyB64, now); err == nil {
+		t.Fatal("pruning without a checkpoint should fail")
+	}
+	before, err := dcp.CheckpointLedger(ctx, s, "agent001", operator.SecretKeyB64, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err := dcp.PruneLedger(ctx, s, 5, "agent001", operator.SecretKeyB64, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := summary.Verify(operator.PublicKeyB64); err != nil {
+		t.Fatalf("prune summary should verify: %v", err)
+	}
+	if _, err := s.GetByIndex(ctx, 4); !errors.Is(err, dcp.ErrLedgerEntryPruned) {
+		t.Fatalf("pruned index: got %v, want ErrLedgerEntryPruned", err)
+	}
+	if _, err := s.GetByIndex(ctx, 5); err != nil {
+		t.Fatalf("retained index: %v", err)
+	}
+	if n, _ := s.Len(ctx); n != 7 {
+		t.Fatalf("Len after prune = %d, want 7", n)
+	}
+	after, err := dcp.CheckpointLedger(ctx, s, "agent001", operator.SecretKeyB64, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.RootHash != before.RootHash {
+		t.Fatal("root changed by pruning")
+	}
+	if anchor, err := dcp.LedgerChainAnchor(ctx, s, 5); err != nil || anchor.PrevEntryHash != summary.LastEntryHash {
+		t.Fatalf("chain anchor at the prune boundary: %+v, %v", anchor, err)
+	}
+
+	// Prune everything stored, then keep appending and prune again.
+	if _, err := dcp.PruneLedger(ctx, s, 7, "agent001", operator.SecretKeyB64, now); err != nil {
+		t.Fatal(err)
+	}
+	appendN(7, 12)
+	if _, r := dcp.VerifyLedger(ctx, s, dcp.AuditStreamOptions{AgentPublicKeyB64: agent.PublicKeyB64}); !r.Verified {
+		t.Fatalf("pruned ledger should verify: %v", r.Errors)
+	}
+	if _, err := dcp.CheckpointLedger(ctx, s, "agent001", operator.SecretKeyB64, now); err != nil {
+		t.Fatal(err)
+	}
+	second, err := dcp.PruneLedger(ctx, s, 10, "agent001", operator.SecretKeyB64, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Verify(operator.PublicKeyB64); err != nil {
+		t.Fatalf("second prune summary should verify: %v", err)
+	}
+	v, r := dcp.VerifyLedger(ctx, s, dcp.AuditStreamOptions{AgentPublicKeyB64: agent.PublicKeyB64})
+	if !r.Verified {
+		t.Fatalf("twice-pruned ledger should verify: %v", r.Errors)
+	}
+	if v.Count() != 12 {
+		t.Fatalf("verifier reached %d, want 12", v.Count())
+	}
+}
@@ -0,0 +1,79 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLiabilityObligation is returned for a bundle that does not meet an
+// obligation of its principal's liability mode.
+var ErrLiabilityObligation = errors.New("liability obligation not met")
+
+// liabilityObligations are the obligations of each liability mode.
+var liabilityObligations = map[LiabilityMode][]LiabilityObligation{
+	LiabilityOwnerResponsible: {},
+	LiabilityShared:           {ObligationSignedDecision},
+	LiabilityStrict:           {ObligationConsent, ObligationSignedDecision, ObligationAgentSignatures},
+}
+
+// Obligations returns what a bundle must carry under m: nothing under
+// owner_responsible, a PDP-signed decision under shared, and also consent
+// and agent-signed audit entries under strict. It is nil for a mode the
+// schema does not allow.
+func (m LiabilityMode) Obligations() []LiabilityObligation {
+	o, ok := liabilityObligations[m]
+	if !ok {
+		return nil
+	}
+	return append([]LiabilityObligation{}, o...)
+}
+
+// Requires reports whether m carries obligation o.
+func (m LiabilityMode) Requires(o LiabilityObligation) bool {
+	for _, have := range liabilityObligations[m] {
+		if have == o {
+			return true
+		}
+	}
+	return false
+}
+
+// LiabilityModes returns every liability mode with its obligations, for
+// publishing as JSON.
+func LiabilityModes() map[LiabilityMode][]LiabilityObligation {
+	out := make(map[LiabilityMode][]LiabilityObligation, len(liabilityObligations))
+	for m := range liabilityObligations {
+		out[m] = m.Obligations()
+	}
+	return out
+}
+
+// checkLiability checks view against the obligations of its principal's
+// liability mode. The consent record and agent signatures present have
+// been verified by then; here they must also be there. The decision is
+// checked against pdpKeys whatever the agent's risk tier.
+func checkLiability(view *bundleView, pdpKeys []string) error {
+	mode := view.principal.LiabilityMode
+	if !mode.IsValid() {
+		return fmt.Errorf("%w: unknown liability mode %q", ErrLiabilityObligation, mode)
+	}
+	if mode.Requires(ObligationConsent) && view.consent == nil {
+		return fmt.Errorf("%w: %s liability requires consent; the bundle has none", ErrLiabilityObligation, mode)
+	}
+	if mode.Requires(ObligationSignedDecision) {
+		if view.decision.Signature == "" {
+			return fmt.Errorf("%w: %s liability requires a decision signed by the PDP; it is unsigned", ErrLiabilityObligation, mode)
+		}
+		if _, err := checkDecision(view.passport.RiskTier, view.intent, view.decision, pdpKeys); err != nil {
+			return fmt.Errorf("%w: %s liability requires a signed decision: %v", ErrLiabilityObligation, mode, err)
+		}
+	}
+	if mode.Requires(ObligationAgentSignatures) {
+		for i, entry := range view.entries {
+			if entry.agentSig == "" {
+				return fmt.Errorf("%w: %s liability requires agent signatures; audit entry %d has none", ErrLiabilityObligation, mode, i)
+			}
+		}
+	}
+	return nil
+}
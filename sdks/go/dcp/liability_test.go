@@ -0,0 +1,109 @@
+package dcp_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestLiabilityObligations(t *testing.T) {
+	modes := dcp.LiabilityModes()
+	if len(modes) != 3 || len(modes[dcp.LiabilityOwnerResponsible]) != 0 {
+		t.Fatalf("modes = %v", modes)
+	}
+	want := []dcp.LiabilityObligation{dcp.ObligationConsent, dcp.ObligationSignedDecision, dcp.ObligationAgentSignatures}
+	if got := dcp.LiabilityStrict.Obligations(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("strict obligations = %v", got)
+	}
+	if !dcp.LiabilityShared.Requires(dcp.ObligationSignedDecision) || dcp.LiabilityShared.Requires(dcp.ObligationConsent) {
+		t.Fatal("shared obligations")
+	}
+	if dcp.LiabilityMode("none").Obligations() != nil || dcp.LiabilityMode("none").IsValid() {
+		t.Fatal("unknown mode has obligations")
+	}
+}
+
+func TestVerifyLiability(t *testing.T) {
+	b, human, _ := builderFixture(t)
+	principal, _ := dcp.NewKeySigner(human.SecretKeyB64)
+	pdp, _ := dcp.GenerateKeypair()
+	pdpSigner, _ := dcp.NewKeySigner(pdp.SecretKeyB64)
+	bundle, err := b.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := dcp.RecordFactory{Clock: func() time.Time { return time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC) }}
+	consent, err := f.NewConsentRecord(bundle.Intent, nil, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := consent.Sign(principal); err != nil {
+		t.Fatal(err)
+	}
+	signedDecision := bundle.PolicyDecision
+	if err := signedDecision.Sign(pdpSigner); err != nil {
+		t.Fatal(err)
+	}
+
+	// sign returns the fixture bundle under mode, signed by the principal
+	// after edit.
+	sign := func(mode dcp.LiabilityMode, edit func(*dcp.CitizenshipBundle)) *dcp.SignedBundle {
+		t.Helper()
+		c := bundle.Clone()
+		c.ResponsiblePrincipalRecord.LiabilityMode = mode
+		if err := c.ResponsiblePrincipalRecord.Sign(principal); err != nil {
+			t.Fatal(err)
+		}
+		if edit != nil {
+			edit(c)
+		}
+		sb, err := dcp.SignBundle(c, principal, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+	complete := func(c *dcp.CitizenshipBundle) {
+		c.Consent = consent.Clone()
+		c.PolicyDecision = signedDecision
+	}
+	opts := dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, EnforceLiability: true, PDPKeys: []string{pdp.PublicKeyB64}, Explain: true}
+
+	res := dcp.VerifySignedBundleWithOptions(sign(dcp.LiabilityStrict, complete), opts)
+	if !res.Verified {
+		t.Fatalf("strict bundle: %v", res.Errors)
+	}
+	if step := res.Trace[len(res.Trace)-1]; step.Check != dcp.TraceLiability || step.Actual != "strict" || !step.OK {
+		t.Fatalf("trace: %+v", step)
+	}
+	if res := dcp.VerifySignedBundleWithOptions(sign(dcp.LiabilityOwnerResponsible, nil), opts); !res.Verified {
+		t.Fatalf("owner_responsible bundle: %v", res.Errors)
+	}
+	if res := dcp.VerifySignedBundle(sign(dcp.LiabilityStrict, nil), human.PublicKeyB64); !res.Verified {
+		t.Fatalf("strict bundle verified without the liability profile: %v", res.Errors)
+	}
+
+	for _, tc := range []struct {
+		name string
+		mode dcp.LiabilityMode
+		edit func(*dcp.CitizenshipBundle)
+		pdp  []string
+		want string
+	}{
+		{"no consent", dcp.LiabilityStrict, func(c *dcp.CitizenshipBundle) { complete(c); c.Consent = nil }, opts.PDPKeys, "requires consent"},
+		{"unsigned decision", dcp.LiabilityShared, nil, opts.PDPKeys, "it is unsigned"},
+		{"no PDP key", dcp.LiabilityShared, complete, nil, "no PDP key"},
+		{"unsigned entry", dcp.LiabilityStrict, func(c *dcp.CitizenshipBundle) { complete(c); c.AuditEntries[1].AgentSignature = "" }, opts.PDPKeys, "audit entry 1 has none"},
+		{"unknown mode", "none", complete, opts.PDPKeys, `unknown liability mode "none"`},
+	} {
+		o := opts
+		o.PDPKeys = tc.pdp
+		res := dcp.VerifySignedBundleWithOptions(sign(tc.mode, tc.edit), o)
+		if res.Verified || !strings.Contains(res.Errors[0], tc.want) || !strings.Contains(res.Errors[0], dcp.ErrLiabilityObligation.Error()) {
+			t.Errorf("%s: %+v", tc.name, res.Errors)
+		}
+	}
+}
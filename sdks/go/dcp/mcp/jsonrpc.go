@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSON-RPC error code answering a tools/call that could not be decided.
+const codeInternalError = -32603
+
+// Message is a JSON-RPC 2.0 message of MCP.
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is the error of a JSON-RPC response.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// ToolCallParams are the params of a tools/call request.
+type ToolCallParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// Content is an item of a tool result's content.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+// ToolResult is the result of a tools/call request.
+type ToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// MessageHandler exchanges one JSON-RPC message: it returns the response
+// to a request, or nil for a notification. An MCP server's dispatcher and
+// an MCP client's round trip are MessageHandlers.
+type MessageHandler func(ctx context.Context, msg []byte) ([]byte, error)
+
+// Handler returns next guarded by g: a tools/call request is passed to next
+// only if Call lets it run, and the response next returns is recorded as
+// its result, a JSON-RPC error or an isError result as a failed call. A
+// refused call is answered with an isError tool result explaining the
+// decision, and one that could not be decided with a JSON-RPC internal
+// error. Other messages go to next untouched.
+func (g *Guard) Handler(next MessageHandler) MessageHandler {
+	return func(ctx context.Context, msg []byte) ([]byte, error) {
+		var req Message
+		if err := json.Unmarshal(msg, &req); err != nil || req.Method != MethodToolsCall || len(req.ID) == 0 {
+			return next(ctx, msg)
+		}
+		var params ToolCallParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			// Malformed calls reach no tool; next rejects them.
+			return next(ctx, msg)
+		}
+
+		var resp []byte
+		var execErr error
+		_, err := g.Call(ctx, params.Name, func(ctx context.Context) (interface{}, error) {
+			if resp, execErr = next(ctx, msg); execErr != nil {
+				return nil, execErr
+			}
+			var m Message
+			if err := json.Unmarshal(resp, &m); err != nil {
+				execErr = fmt.Errorf("mcp: tool %s: response: %v", params.Name, err)
+				return nil, execErr
+			}
+			if m.Error != nil {
+				execErr = fmt.Errorf("mcp: tool %s: JSON-RPC error %d: %s", params.Name, m.Error.Code, m.Error.Message)
+				return nil, execErr
+			}
+			return m.Result, nil
+		})
+		var refused *RefusedError
+		switch {
+		case err == nil || (err == execErr && resp != nil):
+			// The tool ran: its response, even an error, is the answer.
+			return resp, nil
+		case err == execErr:
+			return nil, err
+		case errors.As(err, &refused):
+			result, _ := json.Marshal(&ToolResult{
+				Content: []Content{{Type: "text", Text: refused.Error()}},
+				IsError: true,
+			})
+			return json.Marshal(&Message{JSONRPC: "2.0", ID: req.ID, Result: result})
+		default:
+			return json.Marshal(&Message{JSONRPC: "2.0", ID: req.ID, Error: &RPCError{Code: codeInternalError, Message: err.Error()}})
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/mcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+// server is an MCP server dispatcher answering tools/call of "search" and
+// failing any other tool, and counting the calls it receives.
+type server struct{ calls int }
+
+func (s *server) handle(ctx context.Context, msg []byte) ([]byte, error) {
+	var req mcp.Message
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return nil, err
+	}
+	s.calls++
+	if req.Method != mcp.MethodToolsCall {
+		return json.Marshal(&mcp.Message{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{"tools":[]}`)})
+	}
+	var params mcp.ToolCallParams
+	json.Unmarshal(req.Params, &params)
+	if params.Name != "search" {
+		return json.Marshal(&mcp.Message{JSONRPC: "2.0", ID: req.ID, Error: &mcp.RPCError{Code: -32602, Message: "unknown tool"}})
+	}
+	return json.Marshal(&mcp.Message{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{"content":[{"type":"text","text":"3 results"}]}`)})
+}
+
+func call(t *testing.T, h mcp.MessageHandler, id int, method, tool string) *mcp.Message {
+	t.Helper()
+	params, _ := json.Marshal(&mcp.ToolCallParams{Name: tool, Arguments: json.RawMessage(`{"q":"dcp"}`)})
+	msg, _ := json.Marshal(&mcp.Message{JSONRPC: "2.0", ID: json.RawMessage(strings.Repeat("1", id)), Method: method, Params: params})
+	data, err := h(context.Background(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp mcp.Message
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.ID) != strings.Repeat("1", id) {
+		t.Fatalf("response id %s", resp.ID)
+	}
+	return &resp
+}
+
+func TestHandler(t *testing.T) {
+	g := newGuard(t)
+	srv := &server{}
+	h := g.Handler(srv.handle)
+
+	if resp := call(t, h, 1, mcp.MethodToolsCall, "search"); !strings.Contains(string(resp.Result), "3 results") {
+		t.Fatalf("search: %+v", resp)
+	}
+	if resp := call(t, h, 2, mcp.MethodToolsCall, "lookup"); resp.Error == nil || resp.Error.Code != -32602 {
+		t.Fatalf("unknown tool: %+v", resp)
+	}
+	resp := call(t, h, 3, mcp.MethodToolsCall, "delete_file")
+	var result mcp.ToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil || !result.IsError || !strings.Contains(result.Content[0].Text, "no file writes") {
+		t.Fatalf("refused: %s", resp.Result)
+	}
+	if resp := call(t, h, 4, "tools/list", ""); string(resp.Result) != `{"tools":[]}` {
+		t.Fatalf("tools/list: %+v", resp)
+	}
+	if srv.calls != 3 {
+		t.Fatalf("server received %d messages, want 3", srv.calls)
+	}
+
+	entries := g.Chain.Entries()
+	if len(entries) != 3 || entries[0].Outcome != mcp.OutcomeSucceeded || entries[1].Outcome != mcp.OutcomeFailed ||
+		entries[2].Outcome != mcp.OutcomeRefused || entries[2].PolicyDecision != dcp.OutcomeBlocked {
+		t.Fatalf("entries %+v", entries)
+	}
+}
+
+func TestHandlerUndecided(t *testing.T) {
+	g := newGuard(t)
+	g.Decider = failingDecider{}
+	srv := &server{}
+	resp := call(t, g.Handler(srv.handle), 1, mcp.MethodToolsCall, "search")
+	if resp.Error == nil || srv.calls != 0 || g.Chain.Len() != 0 {
+		t.Fatalf("undecided call: %+v, %d calls", resp, srv.calls)
+	}
+}
+
+type failingDecider struct{}
+
+func (failingDecider) Decide(ctx context.Context, intent *dcp.Intent) (*pdp.SignedDecision, error) {
+	return nil, errors.New("PDP unreachable")
+}
@@ -0,0 +1,210 @@
+// Package mcp gives Model Context Protocol tool calls DCP compliance: each
+// invocation declares an Intent, obtains a PolicyDecision, runs only if the
+// decision approves it, and appends an AuditEntry with the tool as evidence
+// to an AuditChain, whatever the outcome.
+//
+// A Guard wraps tool calls two ways. With an MCP SDK, wrap the tool
+// handler's body in Guard.Call:
+//
+//	guard := &mcp.Guard{Passport: p, Decider: pdpClient, Chain: chain, Tools: tools}
+//	result, err := guard.Call(ctx, req.Params.Name, func(ctx context.Context) (interface{}, error) {
+//		return search(ctx, req)
+//	})
+//
+// Over raw JSON-RPC, on either the server or the client side of any
+// transport, Guard.Handler wraps the function exchanging one message: it
+// guards tools/call requests and passes the other methods through. A
+// refused call is answered with a tool result flagged isError, so the model
+// sees why, and never reaches the tool.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+// MethodToolsCall is the JSON-RPC method of MCP tool invocations.
+const MethodToolsCall = "tools/call"
+
+// Outcomes the audit entries of tool calls record.
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+	OutcomeRefused   = "refused"
+)
+
+// Decider decides intents. *pdp.Server and *apiclient.PDP are Deciders.
+type Decider interface {
+	Decide(ctx context.Context, intent *dcp.Intent) (*pdp.SignedDecision, error)
+}
+
+// Tool describes the intents a tool's calls declare.
+type Tool struct {
+	// ActionType is the intent's action_type, e.g. "api_call" or
+	// "write_file".
+	ActionType string
+	Channel    dcp.Channel
+	// Domain, if set, is the intent target's domain.
+	Domain string
+	// DataClasses are the intent's data_classes; empty means "none".
+	DataClasses []string
+	Impact      dcp.EstimatedImpact
+}
+
+// DefaultTool describes the calls of tools a Guard has no Tool for.
+var DefaultTool = Tool{ActionType: "api_call", Channel: dcp.ChannelAPI, Impact: dcp.ImpactMedium}
+
+// RefusedError reports a tool call the policy decision did not approve.
+type RefusedError struct {
+	Tool     string
+	Decision dcp.PolicyDecision
+}
+
+func (e *RefusedError) Error() string {
+	return fmt.Sprintf("tool %s: policy decision is %s: %s", e.Tool, e.Decision.Decision, strings.Join(e.Decision.Reasons, "; "))
+}
+
+// Guard runs tool calls under DCP policy and audit.
+type Guard struct {
+	// Passport is the calling agent's passport. Required.
+	Passport *dcp.AgentPassport
+	// Decider decides each call's intent. Required.
+	Decider Decider
+	// DecisionKey, if set, is the key decisions must be signed with; a
+	// decision that does not verify refuses the call.
+	DecisionKey string
+	// Chain records every call. Required. Give it an AgentSigner to sign
+	// the entries.
+	Chain *dcp.AuditChain
+	// Tools describes the calls of each tool by name; tools it does not
+	// list are described by DefaultTool.
+	Tools map[string]Tool
+}
+
+type intentKey struct{}
+
+// IntentFromContext returns the intent of the tool call whose execution
+// ctx belongs to, so a tool can pass it on, e.g. with dcpheader.WithIntent.
+func IntentFromContext(ctx context.Context) (*dcp.Intent, bool) {
+	intent, ok := ctx.Value(intentKey{}).(*dcp.Intent)
+	return intent, ok
+}
+
+// Intent returns the intent a call of tool declares.
+func (g *Guard) Intent(tool string) *dcp.Intent {
+	t, ok := g.Tools[tool]
+	if !ok {
+		t = DefaultTool
+	}
+	return t.Intent(g.Passport)
+}
+
+// Intent returns the intent of a call of t by the agent of passport p.
+func (t Tool) Intent(p *dcp.AgentPassport) *dcp.Intent {
+	target := dcp.IntentTarget{Channel: t.Channel}
+	if t.Domain != "" {
+		domain := t.Domain
+		target.Domain = &domain
+	}
+	dataClasses := t.DataClasses
+	if len(dataClasses) == 0 {
+		dataClasses = []string{"none"}
+	}
+	intent := dcp.NewIntent(p.AgentID, p.PrincipalBindingReference, t.ActionType, target, dataClasses, t.Impact)
+	return &intent
+}
+
+// Call runs exec as a call of tool if the policy decision for its intent
+// approves it, and records the call in the chain: a refused call as
+// OutcomeRefused, with a *RefusedError, and an executed one as
+// OutcomeSucceeded or OutcomeFailed, with the hash of its result as
+// evidence.
+func (g *Guard) Call(ctx context.Context, tool string, exec func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if g.Passport == nil {
+		return nil, errors.New("mcp: a passport is required")
+	}
+	return g.Run(ctx, tool, g.Intent(tool), exec)
+}
+
+// Run is Call with the intent of the call given, for callers deriving it
+// from the call's arguments.
+func (g *Guard) Run(ctx context.Context, tool string, intent *dcp.Intent, exec func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if g.Decider == nil || g.Chain == nil {
+		return nil, errors.New("mcp: a decider and an audit chain are required")
+	}
+	d, err := g.Decider.Decide(ctx, intent)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: tool %s: policy decision: %v", tool, err)
+	}
+	if g.DecisionKey != "" {
+		if err := d.Verify(g.DecisionKey, intent); err != nil {
+			return nil, fmt.Errorf("mcp: tool %s: policy decision: %v", tool, err)
+		}
+	}
+	toolName := tool
+	if d.PolicyDecision.Decision != dcp.DecisionApprove {
+		if _, err := g.Chain.Append(dcp.AuditEntryFields{
+			Intent:         *intent,
+			PolicyDecision: outcome(d.PolicyDecision.Decision),
+			Outcome:        OutcomeRefused,
+			Evidence:       dcp.AuditEvidence{Tool: &toolName},
+		}); err != nil {
+			return nil, fmt.Errorf("mcp: %v", err)
+		}
+		return nil, &RefusedError{Tool: tool, Decision: d.PolicyDecision}
+	}
+
+	result, execErr := exec(context.WithValue(ctx, intentKey{}, intent))
+	entry := dcp.AuditEntryFields{
+		Intent:         *intent,
+		PolicyDecision: dcp.OutcomeApproved,
+		Outcome:        OutcomeSucceeded,
+		Evidence:       dcp.AuditEvidence{Tool: &toolName},
+	}
+	if execErr != nil || isErrorResult(result) {
+		entry.Outcome = OutcomeFailed
+	}
+	if execErr == nil && result != nil {
+		h, err := dcp.HashObject(result)
+		if err != nil {
+			return nil, fmt.Errorf("mcp: tool %s: result hash: %v", tool, err)
+		}
+		ref := "sha256:" + h
+		entry.Evidence.ResultRef = &ref
+	}
+	if _, err := g.Chain.Append(entry); err != nil {
+		return nil, fmt.Errorf("mcp: %v", err)
+	}
+	return result, execErr
+}
+
+// outcome returns the audit outcome recording decision.
+func outcome(decision dcp.Decision) dcp.Outcome {
+	switch decision {
+	case dcp.DecisionApprove:
+		return dcp.OutcomeApproved
+	case dcp.DecisionEscalate:
+		return dcp.OutcomeEscalated
+	default:
+		return dcp.OutcomeBlocked
+	}
+}
+
+// isErrorResult reports whether result is an MCP tool result flagged
+// isError: the tool ran but failed.
+func isErrorResult(result interface{}) bool {
+	switch r := result.(type) {
+	case *ToolResult:
+		return r != nil && r.IsError
+	case json.RawMessage:
+		var tr ToolResult
+		return json.Unmarshal(r, &tr) == nil && tr.IsError
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package mcp_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/mcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+// newGuard returns a Guard of a fresh agent, deciding with a PDP that
+// approves API calls, escalates emails and blocks file writes.
+func newGuard(t *testing.T) *mcp.Guard {
+	t.Helper()
+	agentSigner, agentKey := dcptest.NewSigner(t)
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), agentKey, []string{"api_call", "email", "file_write"}, dcp.RiskTierLow)
+	if err := p.Sign(agentSigner); err != nil {
+		t.Fatal(err)
+	}
+	pdpSigner, pdpKey := dcptest.NewSigner(t)
+	decider, err := pdp.New(pdp.Config{Signer: pdpSigner, Policy: &pdp.PolicySet{
+		Default: dcp.DecisionBlock,
+		Rules: []pdp.Rule{
+			{Name: "api", Channels: []dcp.Channel{dcp.ChannelAPI}, Decision: dcp.DecisionApprove},
+			{Name: "email", Channels: []dcp.Channel{dcp.ChannelEmail}, Decision: dcp.DecisionEscalate, Reason: "emails need a human"},
+			{Name: "files", Channels: []dcp.Channel{dcp.ChannelFilesystem}, Decision: dcp.DecisionBlock, Reason: "no file writes"},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &mcp.Guard{
+		Passport:    &p,
+		Decider:     decider,
+		DecisionKey: pdpKey,
+		Chain:       dcp.NewAuditChain(dcp.AuditChainOptions{AgentSigner: agentSigner}),
+		Tools: map[string]mcp.Tool{
+			"send_email":  {ActionType: "send_email", Channel: dcp.ChannelEmail, DataClasses: []string{"contact_info"}, Impact: dcp.ImpactMedium},
+			"delete_file": {ActionType: "write_file", Channel: dcp.ChannelFilesystem, Impact: dcp.ImpactHigh},
+		},
+	}
+}
+
+func TestCall(t *testing.T) {
+	ctx := context.Background()
+	g := newGuard(t)
+
+	result, err := g.Call(ctx, "search", func(ctx context.Context) (interface{}, error) {
+		intent, ok := mcp.IntentFromContext(ctx)
+		if !ok || intent.ActionType != "api_call" || intent.AgentID != g.Passport.AgentID {
+			t.Errorf("intent %+v", intent)
+		}
+		return &mcp.ToolResult{Content: []mcp.Content{{Type: "text", Text: "3 results"}}}, nil
+	})
+	if err != nil || result.(*mcp.ToolResult).Content[0].Text != "3 results" {
+		t.Fatalf("%v, %v", result, err)
+	}
+	if _, err := g.Call(ctx, "search", func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("backend down")
+	}); err == nil || err.Error() != "backend down" {
+		t.Fatalf("tool error: %v", err)
+	}
+	if _, err := g.Call(ctx, "search", func(ctx context.Context) (interface{}, error) {
+		return &mcp.ToolResult{Content: []mcp.Content{{Type: "text", Text: "no such index"}}, IsError: true}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tool := range []string{"send_email", "delete_file"} {
+		_, err := g.Call(ctx, tool, func(ctx context.Context) (interface{}, error) {
+			t.Errorf("%s ran", tool)
+			return nil, nil
+		})
+		var refused *mcp.RefusedError
+		if !errors.As(err, &refused) || refused.Tool != tool {
+			t.Fatalf("%s: %v", tool, err)
+		}
+	}
+
+	entries := g.Chain.Entries()
+	want := []struct {
+		tool     string
+		decision dcp.Outcome
+		outcome  string
+		result   bool
+	}{
+		{"search", dcp.OutcomeApproved, mcp.OutcomeSucceeded, true},
+		{"search", dcp.OutcomeApproved, mcp.OutcomeFailed, false},
+		{"search", dcp.OutcomeApproved, mcp.OutcomeFailed, true},
+		{"send_email", dcp.OutcomeEscalated, mcp.OutcomeRefused, false},
+		{"delete_file", dcp.OutcomeBlocked, mcp.OutcomeRefused, false},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("%d entries", len(entries))
+	}
+	for i, w := range want {
+		e := entries[i]
+		if *e.Evidence.Tool != w.tool || e.PolicyDecision != w.decision || e.Outcome != w.outcome ||
+			(e.Evidence.ResultRef != nil) != w.result || e.AgentID != g.Passport.AgentID || e.AgentSignature == "" {
+			t.Errorf("entry %d: %+v", i, e)
+		}
+		if w.result && !strings.HasPrefix(*e.Evidence.ResultRef, "sha256:") {
+			t.Errorf("entry %d result_ref %s", i, *e.Evidence.ResultRef)
+		}
+	}
+	if entries[1].PrevHash == entries[0].PrevHash {
+		t.Fatal("entries are not chained")
+	}
+}
+
+func TestCallDecisionKey(t *testing.T) {
+	g := newGuard(t)
+	_, g.DecisionKey = dcptest.NewSigner(t)
+	ran := false
+	if _, err := g.Call(context.Background(), "search", func(ctx context.Context) (interface{}, error) {
+		ran = true
+		return nil, nil
+	}); err == nil || ran || g.Chain.Len() != 0 {
+		t.Fatalf("decision under another key: %v, ran %v", err, ran)
+	}
+}
+
+func TestRun(t *testing.T) {
+	g := newGuard(t)
+	intent := g.Intent("search")
+	domain := "search.example.com"
+	intent.Target.Domain = &domain
+	if _, err := g.Run(context.Background(), "search", intent, func(ctx context.Context) (interface{}, error) {
+		if got, _ := mcp.IntentFromContext(ctx); got != intent {
+			t.Errorf("intent %+v", got)
+		}
+		return "ok", nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if e := g.Chain.Entries()[0]; e.IntentID != intent.IntentID || e.Outcome != mcp.OutcomeSucceeded {
+		t.Fatalf("entry %+v", e)
+	}
+}
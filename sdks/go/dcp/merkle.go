@@ -0,0 +1,226 @@
+package dcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// MerkleProofStep is one sibling hash on the path from a leaf to the root.
+// Direction is the side the sibling sits on: "left" or "right". The JSON
+// shape matches the proofs served by the transparency-log service.
+type MerkleProofStep struct {
+	Hash      string `json:"hash"`
+	Direction string `json:"direction"`
+}
+
+func merkleParent(leftHex, rightHex string) (string, error) {
+	left, err := hex.DecodeString(leftHex)
+	if err != nil {
+		return "", err
+	}
+	right, err := hex.DecodeString(rightHex)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(append(left, right...))
+	return hex.EncodeToString(h[:]), nil
+}
+
+// MerkleProof returns the inclusion proof for leaves[index] in the tree built
+// by MerkleRootFromHexLeaves (odd layers duplicate their last node).
+func MerkleProof(leaves []string, index int) ([]MerkleProofStep, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, fmt.Errorf("merkle proof: index %d out of range (%d leaves)", index, len(leaves))
+	}
+	layer := make([]string, len(leaves))
+	copy(layer, leaves)
+
+	proof := []MerkleProofStep{}
+	idx := index
+	for len(layer) > 1 {
+		if len(layer)%2 == 1 {
+			layer = append(layer, layer[len(layer)-1])
+		}
+		if idx%2 == 0 {
+			proof = append(proof, MerkleProofStep{Hash: layer[idx+1], Direction: "right"})
+		} else {
+			proof = append(proof, MerkleProofStep{Hash: layer[idx-1], Direction: "left"})
+		}
+		next := make([]string, 0, len(layer)/2)
+		for i := 0; i < len(layer); i += 2 {
+			h, err := merkleParent(layer[i], layer[i+1])
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, h)
+		}
+		layer = next
+		idx /= 2
+	}
+	return proof, nil
+}
+
+// VerifyMerkleProof recomputes the root from leafHash and proof and compares
+// it with root. Hashes are hex; root may carry a "sha256:" prefix as it does
+// in a bundle signature.
+func VerifyMerkleProof(leafHash string, proof []MerkleProofStep, root string) bool {
+	current := leafHash
+	for _, step := range proof {
+		var err error
+		switch step.Direction {
+		case "left":
+			current, err = merkleParent(step.Hash, current)
+		case "right":
+			current, err = merkleParent(current, step.Hash)
+		default:
+			return false
+		}
+		if err != nil {
+			return false
+		}
+	}
+	return current == strings.TrimPrefix(root, "sha256:")
+}
+
+// AuditEntryInclusionProof hashes entries and returns the leaf hash and
+// inclusion proof for entries[index], so a single AuditEntry can be shown to
+// belong to a bundle's merkle_root without disclosing the other entries.
+func AuditEntryInclusionProof(entries []AuditEntry, index int) (string, []MerkleProofStep, error) {
+	leaves, err := auditEntryLeaves(entries)
+	if err != nil {
+		return "", nil, err
+	}
+	proof, err := MerkleProof(leaves, index)
+	if err != nil {
+		return "", nil, err
+	}
+	return leaves[index], proof, nil
+}
+
+func auditEntryLeaves(entries []AuditEntry) ([]string, error) {
+	leaves := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		h, err := HashObject(entry)
+		if err != nil {
+			return nil, fmt.Errorf("hash audit entry %d: %w", i, err)
+		}
+		leaves = append(leaves, h)
+	}
+	return leaves, nil
+}
+
+// MerkleConsistencyProof proves that the tree over leaves[:oldSize] is a
+// prefix of the tree over all leaves, in the spirit of RFC 6962 § 2.1.2 but
+// for the duplicate-last-node tree used throughout DCP. The proof is the hash
+// of leaf oldSize-1 followed by the siblings on its path to the new root:
+// left siblings cover complete subtrees and are shared by both trees, right
+// siblings exist only in the newer tree.
+func MerkleConsistencyProof(leaves []string, oldSize int) ([]string, error) {
+	newSize := len(leaves)
+	if oldSize < 0 || oldSize > newSize {
+		return nil, fmt.Errorf("merkle consistency: old size %d out of range (new size %d)", oldSize, newSize)
+	}
+	if oldSize == 0 || oldSize == newSize {
+		return []string{}, nil
+	}
+	layer := make([]string, newSize)
+	copy(layer, leaves)
+
+	idx := oldSize - 1
+	proof := []string{layer[idx]}
+	for len(layer) > 1 {
+		if idx%2 == 1 {
+			proof = append(proof, layer[idx-1])
+		} else if idx+1 < len(layer) {
+			proof = append(proof, layer[idx+1])
+		}
+		if len(layer)%2 == 1 {
+			layer = append(layer, layer[len(layer)-1])
+		}
+		next := make([]string, 0, len(layer)/2)
+		for i := 0; i < len(layer); i += 2 {
+			h, err := merkleParent(layer[i], layer[i+1])
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, h)
+		}
+		layer = next
+		idx /= 2
+	}
+	return proof, nil
+}
+
+// VerifyMerkleConsistency checks a proof from MerkleConsistencyProof: it
+// recomputes both roots from the shared path and reports whether they match
+// oldRoot and newRoot. Roots may carry a "sha256:" prefix.
+func VerifyMerkleConsistency(oldSize, newSize int, oldRoot, newRoot string, proof []string) bool {
+	oldRoot = strings.TrimPrefix(oldRoot, "sha256:")
+	newRoot = strings.TrimPrefix(newRoot, "sha256:")
+	if oldSize < 0 || oldSize > newSize {
+		return false
+	}
+	if oldSize == 0 {
+		return len(proof) == 0
+	}
+	if oldSize == newSize {
+		return len(proof) == 0 && oldRoot == newRoot
+	}
+	if len(proof) == 0 {
+		return false
+	}
+
+	oldHash, newHash := proof[0], proof[0]
+	rest := proof[1:]
+	pop := func() (string, bool) {
+		if len(rest) == 0 {
+			return "", false
+		}
+		h := rest[0]
+		rest = rest[1:]
+		return h, true
+	}
+
+	idx := oldSize - 1
+	oldCount, newCount := oldSize, newSize
+	for newCount > 1 {
+		var err error
+		if idx%2 == 1 {
+			sibling, ok := pop()
+			if !ok {
+				return false
+			}
+			if oldHash, err = merkleParent(sibling, oldHash); err != nil {
+				return false
+			}
+			if newHash, err = merkleParent(sibling, newHash); err != nil {
+				return false
+			}
+		} else {
+			if oldCount > 1 {
+				if oldHash, err = merkleParent(oldHash, oldHash); err != nil {
+					return false
+				}
+			}
+			right := newHash
+			if idx+1 < newCount {
+				sibling, ok := pop()
+				if !ok {
+					return false
+				}
+				right = sibling
+			}
+			if newHash, err = merkleParent(newHash, right); err != nil {
+				return false
+			}
+		}
+		if oldCount > 1 {
+			oldCount = (oldCount + 1) / 2
+		}
+		newCount = (newCount + 1) / 2
+		idx /= 2
+	}
+	return len(rest) == 0 && oldHash == oldRoot && newHash == newRoot
+}
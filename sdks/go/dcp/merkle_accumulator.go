@@ -0,0 +1,84 @@
+package dcp
+
+import "encoding/hex"
+
+// MerkleAccumulator computes the same root as MerkleRootFromHexLeaves while
+// keeping only one pending node per tree level, so memory stays O(log n)
+// however many leaves are added. Use MerkleTree when proofs are needed.
+type MerkleAccumulator struct {
+	size    int
+	pending []*[32]byte
+}
+
+// AddHex adds a hex leaf hash.
+func (a *MerkleAccumulator) AddHex(leafHex string) error {
+	h, err := decodeHash(leafHex)
+	if err != nil {
+		return err
+	}
+	a.Add(h)
+	return nil
+}
+
+// Add adds a raw leaf hash.
+func (a *MerkleAccumulator) Add(leaf [32]byte) {
+	node := leaf
+	for k := 0; ; k++ {
+		if k == len(a.pending) {
+			a.pending = append(a.pending, nil)
+		}
+		if a.pending[k] == nil {
+			a.pending[k] = &node
+			break
+		}
+		node = hashPair(*a.pending[k], node)
+		a.pending[k] = nil
+	}
+	a.size++
+}
+
+// Size returns the number of leaves added.
+func (a *MerkleAccumulator) Size() int {
+	return a.size
+}
+
+// Root returns the hex root, or "" if no leaves were added.
+func (a *MerkleAccumulator) Root() string {
+	if a.size == 0 {
+		return ""
+	}
+	// Walk up the right edge as MerkleTree.layers does: at each level the
+	// unpaired node (if any) and the partial node from below are combined,
+	// duplicating a lone node.
+	var tail *[32]byte
+	for k := 0; ; k++ {
+		complete := a.size >> uint(k)
+		width := complete
+		if tail != nil {
+			width++
+		}
+		if width == 1 {
+			if complete == 1 {
+				return hex.EncodeToString(a.pending[k][:])
+			}
+			return hex.EncodeToString(tail[:])
+		}
+		var rest [][32]byte
+		if complete%2 == 1 {
+			rest = append(rest, *a.pending[k])
+		}
+		if tail != nil {
+			rest = append(rest, *tail)
+		}
+		switch len(rest) {
+		case 0:
+			tail = nil
+		case 1:
+			h := hashPair(rest[0], rest[0])
+			tail = &h
+		default:
+			h := hashPair(rest[0], rest[1])
+			tail = &h
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package dcp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// defaultMerkleMinParallel is the layer width below which hashing stays on
+// the calling goroutine; goroutine hand-off costs more than it saves there.
+const defaultMerkleMinParallel = 4096
+
+// MerkleHasher computes the same roots as MerkleRootFromHexLeaves, spreading
+// leaf decoding and each tree layer across a pool of worker goroutines. The
+// zero value is ready to use.
+type MerkleHasher struct {
+	// Workers is the pool size; 0 means runtime.GOMAXPROCS(0).
+	Workers int
+	// MinParallel is the smallest layer hashed in parallel; 0 means 4096.
+	MinParallel int
+}
+
+func (h MerkleHasher) workers() int {
+	if h.Workers > 0 {
+		return h.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+func (h MerkleHasher) minParallel() int {
+	if h.MinParallel > 0 {
+		return h.MinParallel
+	}
+	return defaultMerkleMinParallel
+}
+
+// parallelFor runs fn over [0, n) split into contiguous chunks, one per
+// worker. Small ranges run inline.
+func (h MerkleHasher) parallelFor(n int, fn func(lo, hi int)) {
+	w := h.workers()
+	if w <= 1 || n < h.minParallel() {
+		fn(0, n)
+		return
+	}
+	if w > n {
+		w = n
+	}
+	chunk := (n + w - 1) / w
+	var wg sync.WaitGroup
+	for lo := 0; lo < n; lo += chunk {
+		hi := lo + chunk
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			fn(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
+
+// RootFromHexLeaves returns the hex Merkle root of leaves, or "" if there are none.
+func (h MerkleHasher) RootFromHexLeaves(leaves []string) (string, error) {
+	if len(leaves) == 0 {
+		return "", nil
+	}
+	layer := make([][32]byte, len(leaves))
+	errs := make([]error, len(leaves))
+	h.parallelFor(len(leaves), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			layer[i], errs[i] = decodeHash(leaves[i])
+		}
+	})
+	for i, err := range errs {
+		if err != nil {
+			return "", fmt.Errorf("merkle leaf %d: %w", i, err)
+		}
+	}
+	root := h.root(layer)
+	return hex.EncodeToString(root[:]), nil
+}
+
+// root reduces layer to its root. layer may be appended to.
+func (h MerkleHasher) root(layer [][32]byte) [32]byte {
+	for len(layer) > 1 {
+		if len(layer)%2 == 1 {
+			layer = append(layer, layer[len(layer)-1])
+		}
+		half := len(layer) / 2
+		next := make([][32]byte, half)
+		h.parallelFor(half, func(lo, hi int) {
+			for i := lo; i < hi; i++ {
+				next[i] = hashPair(layer[2*i], layer[2*i+1])
+			}
+		})
+		layer = next
+	}
+	return layer[0]
+}
+
+// hashCanonLeaves returns the hex SHA-256 of each canonical string.
+func (h MerkleHasher) hashCanonLeaves(canons []string) []string {
+	leaves := make([]string, len(canons))
+	h.parallelFor(len(canons), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			leaves[i] = sha256HexString(canons[i])
+		}
+	})
+	return leaves
+}
+
+// MerkleRootFromHexLeavesParallel is MerkleRootFromHexLeaves using the given
+// number of workers (0 for GOMAXPROCS).
+func MerkleRootFromHexLeavesParallel(leaves []string, workers int) (string, error) {
+	return MerkleHasher{Workers: workers}.RootFromHexLeaves(leaves)
+}
@@ -0,0 +1,56 @@
+package dcp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestMerkleHasherMatchesSequential(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 64, 1000, 5001} {
+		leaves := testLeaves(n)
+		want, _ := dcp.MerkleRootFromHexLeaves(leaves)
+		for _, workers := range []int{1, 3, 8} {
+			h := dcp.MerkleHasher{Workers: workers, MinParallel: 2}
+			got, err := h.RootFromHexLeaves(leaves)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Fatalf("n=%d workers=%d: root %s, want %s", n, workers, got, want)
+			}
+		}
+	}
+	if root, _ := dcp.MerkleRootFromHexLeavesParallel(nil, 4); root != "" {
+		t.Fatal("empty leaves should give an empty root")
+	}
+}
+
+func TestMerkleHasherBadLeaf(t *testing.T) {
+	leaves := testLeaves(10)
+	leaves[6] = "not-hex"
+	if _, err := (dcp.MerkleHasher{Workers: 4, MinParallel: 2}).RootFromHexLeaves(leaves); err == nil {
+		t.Fatal("expected decode error")
+	}
+}
+
+func BenchmarkMerkleRoot(b *testing.B) {
+	leaves := testLeaves(1 << 20)
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := dcp.MerkleRootFromHexLeaves(leaves); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := dcp.MerkleRootFromHexLeavesParallel(leaves, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
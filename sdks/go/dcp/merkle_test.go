@@ -0,0 +1,111 @@
+package dcp_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func testLeaves(n int) []string {
+	leaves := make([]string, n)
+	for i := range leaves {
+		h := sha256.Sum256([]byte(fmt.Sprintf("leaf-%d", i)))
+		leaves[i] = hex.EncodeToString(h[:])
+	}
+	return leaves
+}
+
+func TestMerkleProofAllSizes(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		leaves := testLeaves(n)
+		root, err := dcp.MerkleRootFromHexLeaves(leaves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < n; i++ {
+			proof, err := dcp.MerkleProof(leaves, i)
+			if err != nil {
+				t.Fatalf("n=%d i=%d: %v", n, i, err)
+			}
+			if !dcp.VerifyMerkleProof(leaves[i], proof, root) {
+				t.Fatalf("n=%d i=%d: proof did not verify", n, i)
+			}
+			if !dcp.VerifyMerkleProof(leaves[i], proof, "sha256:"+root) {
+				t.Fatalf("n=%d i=%d: prefixed root did not verify", n, i)
+			}
+			if n > 1 && dcp.VerifyMerkleProof(leaves[(i+1)%n], proof, root) {
+				t.Fatalf("n=%d i=%d: proof verified the wrong leaf", n, i)
+			}
+		}
+	}
+}
+
+func TestMerkleProofOutOfRange(t *testing.T) {
+	if _, err := dcp.MerkleProof(testLeaves(3), 3); err == nil {
+		t.Fatal("expected out-of-range error")
+	}
+}
+
+func TestAuditEntryInclusionProof(t *testing.T) {
+	entries := []dcp.AuditEntry{
+		{DCPVersion: "1.0", AuditID: "a1", PrevHash: "GENESIS", Outcome: "ok"},
+		{DCPVersion: "1.0", AuditID: "a2", Outcome: "ok"},
+		{DCPVersion: "1.0", AuditID: "a3", Outcome: "ok"},
+	}
+	var leaves []string
+	for _, e := range entries {
+		h, _ := dcp.HashObject(e)
+		leaves = append(leaves, h)
+	}
+	root, _ := dcp.MerkleRootFromHexLeaves(leaves)
+
+	leaf, proof, err := dcp.AuditEntryInclusionProof(entries, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dcp.VerifyMerkleProof(leaf, proof, root) {
+		t.Fatal("audit entry proof did not verify")
+	}
+}
+
+func TestMerkleConsistencyAllSizes(t *testing.T) {
+	for n := 1; n <= 20; n++ {
+		leaves := testLeaves(n)
+		newRoot, _ := dcp.MerkleRootFromHexLeaves(leaves)
+		for m := 0; m <= n; m++ {
+			oldRoot, _ := dcp.MerkleRootFromHexLeaves(leaves[:m])
+			proof, err := dcp.MerkleConsistencyProof(leaves, m)
+			if err != nil {
+				t.Fatalf("m=%d n=%d: %v", m, n, err)
+			}
+			if !dcp.VerifyMerkleConsistency(m, n, oldRoot, newRoot, proof) {
+				t.Fatalf("m=%d n=%d: consistency proof did not verify", m, n)
+			}
+		}
+	}
+}
+
+func TestMerkleConsistencyDetectsRewrite(t *testing.T) {
+	leaves := testLeaves(11)
+	oldRoot, _ := dcp.MerkleRootFromHexLeaves(leaves[:6])
+
+	rewritten := append([]string{}, leaves...)
+	rewritten[2] = testLeaves(12)[11]
+	newRoot, _ := dcp.MerkleRootFromHexLeaves(rewritten)
+	proof, _ := dcp.MerkleConsistencyProof(rewritten, 6)
+	if dcp.VerifyMerkleConsistency(6, 11, oldRoot, newRoot, proof) {
+		t.Fatal("rewritten history must not prove consistent")
+	}
+
+	honestRoot, _ := dcp.MerkleRootFromHexLeaves(leaves)
+	honest, _ := dcp.MerkleConsistencyProof(leaves, 6)
+	if dcp.VerifyMerkleConsistency(6, 11, oldRoot, honestRoot, honest[:len(honest)-1]) {
+		t.Fatal("truncated proof must not verify")
+	}
+	if dcp.VerifyMerkleConsistency(5, 11, oldRoot, honestRoot, honest) {
+		t.Fatal("proof must be bound to the old size")
+	}
+}
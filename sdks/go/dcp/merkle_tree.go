@@ -0,0 +1,315 @@
+package dcp
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MerkleTree is an append-only Merkle tree that caches every complete
+// interior node, so Append, Root, ProofAt and ConsistencyProof cost O(log n)
+// hashes instead of rebuilding the tree. Roots are identical to
+// MerkleRootFromHexLeaves over the same leaves. A MerkleTree is safe for
+// concurrent use.
+type MerkleTree struct {
+	mu sync.RWMutex
+	// levels[0] holds the leaves; levels[k+1][j] = H(levels[k][2j] || levels[k][2j+1])
+	// and only exists once both children do. Partial right-edge nodes, which
+	// depend on the duplicate-last-node rule, are derived on demand.
+	levels [][][32]byte
+}
+
+// NewMerkleTree returns an empty tree.
+func NewMerkleTree() *MerkleTree {
+	return &MerkleTree{levels: [][][32]byte{{}}}
+}
+
+// NewMerkleTreeFromHexLeaves builds a tree over existing hex leaf hashes.
+func NewMerkleTreeFromHexLeaves(leaves []string) (*MerkleTree, error) {
+	t := NewMerkleTree()
+	for _, l := range leaves {
+		if err := t.Append(l); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func decodeHash(s string) ([32]byte, error) {
+	var h [32]byte
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return h, err
+	}
+	if len(b) != 32 {
+		return h, fmt.Errorf("hash must be 32 bytes, got %d", len(b))
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+func hashPair(left, right [32]byte) [32]byte {
+	var buf [64]byte
+	copy(buf[:32], left[:])
+	copy(buf[32:], right[:])
+	return sha256.Sum256(buf[:])
+}
+
+// Append adds a hex leaf hash.
+func (t *MerkleTree) Append(leafHex string) error {
+	h, err := decodeHash(leafHex)
+	if err != nil {
+		return fmt.Errorf("merkle tree: leaf: %w", err)
+	}
+	t.AppendHash(h)
+	return nil
+}
+
+// AppendHash adds a raw leaf hash.
+func (t *MerkleTree) AppendHash(leaf [32]byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.levels[0] = append(t.levels[0], leaf)
+	for k := 0; len(t.levels[k])%2 == 0; k++ {
+		if k+1 == len(t.levels) {
+			t.levels = append(t.levels, nil)
+		}
+		n := len(t.levels[k])
+		t.levels[k+1] = append(t.levels[k+1], hashPair(t.levels[k][n-2], t.levels[k][n-1]))
+	}
+}
+
+// Size returns the number of leaves.
+func (t *MerkleTree) Size() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.levels[0])
+}
+
+// Leaf returns the hex hash of leaf i.
+func (t *MerkleTree) Leaf(i int) (string, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if i < 0 || i >= len(t.levels[0]) {
+		return "", fmt.Errorf("merkle tree: leaf %d out of range (%d leaves)", i, len(t.levels[0]))
+	}
+	return hex.EncodeToString(t.levels[0][i][:]), nil
+}
+
+// layers returns, for the tree of the given size, each level's nodes as
+// cached nodes plus the optional partial right-edge node. Callers hold t.mu.
+func (t *MerkleTree) layers(size int) (cached [][][32]byte, tails []*[32]byte) {
+	var tail *[32]byte
+	for k := 0; ; k++ {
+		complete := size >> uint(k)
+		var level [][32]byte
+		if k < len(t.levels) {
+			level = t.levels[k][:complete]
+		}
+		cached = append(cached, level)
+		tails = append(tails, tail)
+		width := len(level)
+		if tail != nil {
+			width++
+		}
+		if width <= 1 {
+			return cached, tails
+		}
+		// Whatever the cached parents above do not cover becomes the next tail.
+		rest := make([][32]byte, 0, 2)
+		rest = append(rest, level[(complete>>1)<<1:]...)
+		if tail != nil {
+			rest = append(rest, *tail)
+		}
+		switch len(rest) {
+		case 0:
+			tail = nil
+		case 1:
+			h := hashPair(rest[0], rest[0])
+			tail = &h
+		default:
+			h := hashPair(rest[0], rest[1])
+			tail = &h
+		}
+	}
+}
+
+func layerNode(cached [][32]byte, tail *[32]byte, i int) [32]byte {
+	if i < len(cached) {
+		return cached[i]
+	}
+	return *tail
+}
+
+func layerWidth(cached [][32]byte, tail *[32]byte) int {
+	if tail != nil {
+		return len(cached) + 1
+	}
+	return len(cached)
+}
+
+// Root returns the hex root, or "" for an empty tree.
+func (t *MerkleTree) Root() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.rootAt(len(t.levels[0]))
+}
+
+// RootAt returns the root the tree had when it held size leaves.
+func (t *MerkleTree) RootAt(size int) (string, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if size < 0 || size > len(t.levels[0]) {
+		return "", fmt.Errorf("merkle tree: size %d out of range (%d leaves)", size, len(t.levels[0]))
+	}
+	return t.rootAt(size), nil
+}
+
+func (t *MerkleTree) rootAt(size int) string {
+	if size == 0 {
+		return ""
+	}
+	cached, tails := t.layers(size)
+	top := len(cached) - 1
+	root := layerNode(cached[top], tails[top], 0)
+	return hex.EncodeToString(root[:])
+}
+
+// ProofAt returns the inclusion proof for leaf i against the current root,
+// in the format accepted by VerifyMerkleProof.
+func (t *MerkleTree) ProofAt(i int) ([]MerkleProofStep, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	size := len(t.levels[0])
+	if i < 0 || i >= size {
+		return nil, fmt.Errorf("merkle proof: index %d out of range (%d leaves)", i, size)
+	}
+	cached, tails := t.layers(size)
+	proof := []MerkleProofStep{}
+	idx := i
+	for k := 0; k < len(cached)-1; k++ {
+		width := layerWidth(cached[k], tails[k])
+		if idx%2 == 1 {
+			h := layerNode(cached[k], tails[k], idx-1)
+			proof = append(proof, MerkleProofStep{Hash: hex.EncodeToString(h[:]), Direction: "left"})
+		} else {
+			sib := idx + 1
+			if sib >= width {
+				sib = idx
+			}
+			h := layerNode(cached[k], tails[k], sib)
+			proof = append(proof, MerkleProofStep{Hash: hex.EncodeToString(h[:]), Direction: "right"})
+		}
+		idx /= 2
+	}
+	return proof, nil
+}
+
+// ConsistencyProof returns the proof that the tree at oldSize is a prefix of
+// the current tree, in the format accepted by VerifyMerkleConsistency.
+func (t *MerkleTree) ConsistencyProof(oldSize int) ([]string, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	size := len(t.levels[0])
+	if oldSize < 0 || oldSize > size {
+		return nil, fmt.Errorf("merkle consistency: old size %d out of range (new size %d)", oldSize, size)
+	}
+	if oldSize == 0 || oldSize == size {
+		return []string{}, nil
+	}
+	cached, tails := t.layers(size)
+	idx := oldSize - 1
+	proof := []string{hex.EncodeToString(t.levels[0][idx][:])}
+	for k := 0; k < len(cached)-1; k++ {
+		width := layerWidth(cached[k], tails[k])
+		if idx%2 == 1 {
+			h := layerNode(cached[k], tails[k], idx-1)
+			proof = append(proof, hex.EncodeToString(h[:]))
+		} else if idx+1 < width {
+			h := layerNode(cached[k], tails[k], idx+1)
+			proof = append(proof, hex.EncodeToString(h[:]))
+		}
+		idx /= 2
+	}
+	return proof, nil
+}
+
+var merkleTreeMagic = [8]byte{'D', 'C', 'P', 'M', 'T', 'R', 'E', '1'}
+
+// MarshalBinary serializes the leaves and every cached interior node:
+// an 8-byte magic, the big-endian uint64 leaf count, then each level's
+// 32-byte nodes from the leaves upwards.
+func (t *MerkleTree) MarshalBinary() ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	total := 0
+	for _, level := range t.levels {
+		total += len(level)
+	}
+	out := make([]byte, 0, 16+32*total)
+	out = append(out, merkleTreeMagic[:]...)
+	out = binary.BigEndian.AppendUint64(out, uint64(len(t.levels[0])))
+	for _, level := range t.levels {
+		for _, h := range level {
+			out = append(out, h[:]...)
+		}
+	}
+	return out, nil
+}
+
+// UnmarshalBinary restores a tree written by MarshalBinary without rehashing
+// the leaves. Use Verify to check the cached nodes against the leaves.
+func (t *MerkleTree) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 || [8]byte(data[:8]) != merkleTreeMagic {
+		return errors.New("merkle tree: bad header")
+	}
+	n := binary.BigEndian.Uint64(data[8:16])
+	body := data[16:]
+	if n > uint64(len(body)/32) {
+		return errors.New("merkle tree: truncated data")
+	}
+	levels := [][][32]byte{}
+	for k := 0; ; k++ {
+		count := int(n >> uint(k))
+		if k > 0 && count == 0 {
+			break
+		}
+		if len(body) < 32*count {
+			return errors.New("merkle tree: truncated data")
+		}
+		level := make([][32]byte, count)
+		for j := range level {
+			copy(level[j][:], body[32*j:])
+		}
+		body = body[32*count:]
+		levels = append(levels, level)
+		if count == 0 {
+			break
+		}
+	}
+	if len(body) != 0 {
+		return errors.New("merkle tree: trailing data")
+	}
+	t.mu.Lock()
+	t.levels = levels
+	t.mu.Unlock()
+	return nil
+}
+
+// Verify recomputes every cached interior node from the leaves, detecting
+// corruption of a deserialized tree.
+func (t *MerkleTree) Verify() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for k := 0; k+1 < len(t.levels); k++ {
+		for j, h := range t.levels[k+1] {
+			if hashPair(t.levels[k][2*j], t.levels[k][2*j+1]) != h {
+				return fmt.Errorf("merkle tree: node %d at level %d does not match its children", j, k+1)
+			}
+		}
+	}
+	return nil
+}
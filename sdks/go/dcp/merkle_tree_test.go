@@ -0,0 +1,93 @@
+package dcp_test
+
+import (
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestMerkleTreeMatchesRebuild(t *testing.T) {
+	leaves := testLeaves(33)
+	tree := dcp.NewMerkleTree()
+	if tree.Root() != "" {
+		t.Fatal("empty tree should have an empty root")
+	}
+	for n := 1; n <= len(leaves); n++ {
+		if err := tree.Append(leaves[n-1]); err != nil {
+			t.Fatal(err)
+		}
+		want, _ := dcp.MerkleRootFromHexLeaves(leaves[:n])
+		if got := tree.Root(); got != want {
+			t.Fatalf("n=%d: root %s, want %s", n, got, want)
+		}
+		for i := 0; i < n; i++ {
+			proof, err := tree.ProofAt(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !dcp.VerifyMerkleProof(leaves[i], proof, want) {
+				t.Fatalf("n=%d i=%d: proof did not verify", n, i)
+			}
+		}
+		for m := 0; m <= n; m++ {
+			oldRoot, err := tree.RootAt(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wantOld, _ := dcp.MerkleRootFromHexLeaves(leaves[:m]); oldRoot != wantOld {
+				t.Fatalf("n=%d m=%d: RootAt mismatch", n, m)
+			}
+			proof, err := tree.ConsistencyProof(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !dcp.VerifyMerkleConsistency(m, n, oldRoot, want, proof) {
+				t.Fatalf("n=%d m=%d: consistency proof did not verify", n, m)
+			}
+		}
+	}
+}
+
+func TestMerkleTreeBinaryRoundTrip(t *testing.T) {
+	tree, err := dcp.NewMerkleTreeFromHexLeaves(testLeaves(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := dcp.NewMerkleTree()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Size() != 13 || restored.Root() != tree.Root() {
+		t.Fatal("restored tree differs")
+	}
+	if err := restored.Append(testLeaves(14)[13]); err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := dcp.MerkleRootFromHexLeaves(testLeaves(14)); restored.Root() != want {
+		t.Fatal("restored tree did not keep appending correctly")
+	}
+
+	data[len(data)-1] ^= 0xff
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Verify() == nil {
+		t.Fatal("corrupted node should fail Verify")
+	}
+	if restored.UnmarshalBinary(data[:len(data)-1]) == nil {
+		t.Fatal("truncated data should be rejected")
+	}
+}
+
+func TestMerkleTreeRejectsBadLeaf(t *testing.T) {
+	if err := dcp.NewMerkleTree().Append("zz"); err == nil {
+		t.Fatal("expected invalid leaf error")
+	}
+}
@@ -0,0 +1,169 @@
+package oidcproof
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/issuer"
+)
+
+// ProofName names the proofs of OIDC logins among an issued principal's
+// proofs.
+const ProofName = "oidc"
+
+// Proof records the OIDC login a principal record was issued on.
+type Proof struct {
+	HumanID string `json:"human_id"`
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+	// Email is the verified email, if the token had one.
+	Email string `json:"email,omitempty"`
+	// ClaimsHash is "sha256:" and the hash of the canonical
+	// {"email", "issuer", "subject"}, the commitment to publish in place of
+	// the claims.
+	ClaimsHash string `json:"claims_hash"`
+	// IDToken is the token the claims were verified from.
+	IDToken  string `json:"id_token"`
+	ProvedAt string `json:"proved_at"`
+	// Signature is by the principal key over the canonical proof with an
+	// empty signature.
+	Signature string `json:"signature"`
+}
+
+// ClaimsHash returns the commitment of a Proof to issuer, subject and
+// email.
+func ClaimsHash(issuerID, subject, email string) (string, error) {
+	h, err := dcp.HashObject(map[string]string{"issuer": issuerID, "subject": subject, "email": email})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + h, nil
+}
+
+// NewProof returns the proof, signed with the principal key s, that rec
+// was issued on the login of c, verified from idToken, at now.
+func NewProof(rec *dcp.ResponsiblePrincipalRecord, c *Claims, idToken string, now time.Time, s dcp.BundleSigner) (*Proof, error) {
+	proof := &Proof{
+		HumanID:  rec.HumanID,
+		Issuer:   c.Issuer,
+		Subject:  c.Subject,
+		IDToken:  idToken,
+		ProvedAt: dcp.FormatTime(now),
+	}
+	if c.EmailVerified {
+		proof.Email = c.Email
+	}
+	var err error
+	if proof.ClaimsHash, err = ClaimsHash(proof.Issuer, proof.Subject, proof.Email); err != nil {
+		return nil, err
+	}
+	canon, err := dcp.Canonicalize(proof)
+	if err != nil {
+		return nil, err
+	}
+	if proof.Signature, err = s.SignCanonical(canon); err != nil {
+		return nil, fmt.Errorf("oidcproof: sign proof of %s: %v", rec.HumanID, err)
+	}
+	return proof, nil
+}
+
+// VerifyProof checks that proof is the principal's, signed with
+// publicKeyB64 like rec, and that its ID token still verifies under the
+// provider's current keys and bears the claims it records, as does rec's
+// contact. The token may have expired.
+func (p *Provider) VerifyProof(ctx context.Context, proof *Proof, rec *dcp.ResponsiblePrincipalRecord, publicKeyB64 string) error {
+	if proof.HumanID != rec.HumanID {
+		return fmt.Errorf("oidcproof: proof is for %s, not %s", proof.HumanID, rec.HumanID)
+	}
+	if ok, err := rec.VerifySignature(publicKeyB64); err != nil || !ok {
+		return fmt.Errorf("oidcproof: record of %s is not signed by the principal key", rec.HumanID)
+	}
+	unsigned := *proof
+	unsigned.Signature = ""
+	if ok, err := dcp.VerifyObject(unsigned, proof.Signature, publicKeyB64); err != nil || !ok {
+		return fmt.Errorf("oidcproof: proof of %s is not signed by the principal key", rec.HumanID)
+	}
+	c, err := p.VerifySignature(ctx, proof.IDToken)
+	if err != nil {
+		return err
+	}
+	email := ""
+	if c.EmailVerified {
+		email = c.Email
+	}
+	if c.Issuer != proof.Issuer || c.Subject != proof.Subject || email != proof.Email {
+		return errors.New("oidcproof: proof does not record the claims of its ID token")
+	}
+	if h, err := ClaimsHash(proof.Issuer, proof.Subject, proof.Email); err != nil || h != proof.ClaimsHash {
+		return errors.New("oidcproof: claims_hash does not match the claims")
+	}
+	if proof.Email != "" && rec.Contact != nil && *rec.Contact != proof.Email {
+		return fmt.Errorf("oidcproof: record contact is not the verified email %s", proof.Email)
+	}
+	return nil
+}
+
+// Evidence is the evidence of a principal request proofed with OIDC.
+type Evidence struct {
+	IDToken string `json:"id_token"`
+}
+
+// Proofer returns the issuer.ProofRecorder admitting principal requests
+// whose evidence carries an ID token p verifies. It sets the request's
+// contact to the verified email and, for a natural person, its legal name
+// to the name claim, and records a Proof under ProofName.
+func (p *Provider) Proofer() issuer.ProofRecorder {
+	return proofer{p}
+}
+
+type proofer struct{ p *Provider }
+
+func (pr proofer) claims(ctx context.Context, req *issuer.PrincipalRequest) (*Claims, string, error) {
+	var ev Evidence
+	if err := json.Unmarshal(req.Evidence, &ev); err != nil || ev.IDToken == "" {
+		return nil, "", errors.New("evidence carries no id_token")
+	}
+	c, err := pr.p.Verify(ctx, ev.IDToken)
+	if err != nil {
+		return nil, "", err
+	}
+	return c, ev.IDToken, nil
+}
+
+// ProveIdentity implements issuer.Proofer.
+func (pr proofer) ProveIdentity(ctx context.Context, req *issuer.PrincipalRequest) error {
+	c, _, err := pr.claims(ctx, req)
+	if err != nil {
+		return err
+	}
+	if c.EmailVerified && c.Email != "" {
+		email := c.Email
+		req.Contact = &email
+	}
+	if c.Name != "" && (req.EntityType == dcp.EntityNaturalPerson || req.EntityType == dcp.EntityIndividual) {
+		req.LegalName = c.Name
+	}
+	return nil
+}
+
+// RecordProof implements issuer.ProofRecorder.
+func (pr proofer) RecordProof(ctx context.Context, req *issuer.PrincipalRequest, rec *dcp.ResponsiblePrincipalRecord, s dcp.BundleSigner) (string, json.RawMessage, error) {
+	c, token, err := pr.claims(ctx, req)
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now()
+	if pr.p.Now != nil {
+		now = pr.p.Now()
+	}
+	proof, err := NewProof(rec, c, token, now, s)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := json.Marshal(proof)
+	return ProofName, data, err
+}
@@ -0,0 +1,92 @@
+package oidcproof_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/issuer"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/oidcproof"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+)
+
+func newIssuer(t *testing.T, p *oidcproof.Provider) *issuer.Server {
+	t.Helper()
+	reg, err := registry.New(registry.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := issuer.New(issuer.Config{Publisher: issuer.Local(reg), Proofers: []issuer.Proofer{p.Proofer()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func request(token string) issuer.PrincipalRequest {
+	evidence, _ := json.Marshal(oidcproof.Evidence{IDToken: token})
+	return issuer.PrincipalRequest{LegalName: "A. Example", EntityType: dcp.EntityNaturalPerson, Jurisdiction: "DE", Evidence: evidence}
+}
+
+func TestProofer(t *testing.T) {
+	ctx := context.Background()
+	o := newOP(t)
+	srv := newIssuer(t, o.provider())
+
+	issued, err := srv.IssuePrincipal(ctx, request(o.mint(t, "rsa-1", "RS256", o.claims(nil))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := issued.Record
+	if rec.LegalName != "Alice Example" || rec.Contact == nil || *rec.Contact != "alice@example.com" {
+		t.Fatalf("record %+v", rec)
+	}
+	var proof oidcproof.Proof
+	if err := json.Unmarshal(issued.Proofs[oidcproof.ProofName], &proof); err != nil {
+		t.Fatal(err)
+	}
+	if proof.HumanID != rec.HumanID || proof.Subject != "alice-123" || proof.Email != "alice@example.com" {
+		t.Fatalf("proof %+v", proof)
+	}
+	if h, _ := oidcproof.ClaimsHash(o.srv.URL, "alice-123", "alice@example.com"); proof.ClaimsHash != h {
+		t.Fatalf("claims_hash %s, want %s", proof.ClaimsHash, h)
+	}
+
+	// Anyone can re-check the proof, with a provider of their own.
+	if err := o.provider().VerifyProof(ctx, &proof, &rec, issued.PublicKeyB64); err != nil {
+		t.Fatal(err)
+	}
+	tampered := proof
+	tampered.Subject = "mallory-666"
+	if err := o.provider().VerifyProof(ctx, &tampered, &rec, issued.PublicKeyB64); err == nil {
+		t.Fatal("tampered proof verifies")
+	}
+	other, _ := dcp.GenerateKeypair()
+	if err := o.provider().VerifyProof(ctx, &proof, &rec, other.PublicKeyB64); err == nil {
+		t.Fatal("proof verifies under another key")
+	}
+	o.retire("rsa-1")
+	if err := o.provider().VerifyProof(ctx, &proof, &rec, issued.PublicKeyB64); !errors.Is(err, oidcproof.ErrInvalidToken) {
+		t.Fatalf("proof under a retired key: %v", err)
+	}
+
+	unverified := o.mint(t, "ed-1", "EdDSA", o.claims(func(c map[string]interface{}) { c["email_verified"] = false }))
+	issued, err = srv.IssuePrincipal(ctx, request(unverified))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issued.Record.Contact != nil {
+		t.Fatalf("unverified email recorded: %s", *issued.Record.Contact)
+	}
+
+	for name, req := range map[string]issuer.PrincipalRequest{
+		"no evidence":   {LegalName: "Bob", EntityType: dcp.EntityNaturalPerson, Jurisdiction: "DE"},
+		"expired token": request(o.mint(t, "ed-1", "EdDSA", o.claims(func(c map[string]interface{}) { c["exp"] = int64(1) }))),
+	} {
+		if _, err := srv.IssuePrincipal(ctx, req); !errors.Is(err, issuer.ErrNotProofed) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+// Package oidcproof proofs the humans behind responsible principal records
+// with OpenID Connect: the onboarding frontend signs the human in with
+// their OpenID Provider and passes the ID token to the issuer as evidence,
+//
+//	POST /v1/principals
+//	{"legal_name": "...", "entity_type": "natural_person", "jurisdiction": "DE",
+//	 "evidence": {"id_token": "eyJ..."}}
+//
+// and the Proofer of a Provider admits the request only if the token is a
+// valid one for the configured client. The verified claims are recorded:
+// the record's contact is the verified email and, for a natural person, its
+// legal name the name claim; and the issuer returns a Proof, signed with the
+// principal key, committing to the issuer, subject and email and keeping
+// the ID token, so anyone can later re-check it against the provider's keys
+// with VerifyProof.
+package oidcproof
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcpjwt"
+)
+
+// ErrInvalidToken reports an ID token that is malformed, not signed by the
+// provider, expired, or for another issuer or client.
+var ErrInvalidToken = errors.New("invalid ID token")
+
+// DiscoveryPath is where a provider serves its OpenID configuration.
+const DiscoveryPath = "/.well-known/openid-configuration"
+
+// maxDocumentBytes caps the size of fetched discovery documents and key
+// sets.
+const maxDocumentBytes = 1 << 20
+
+// keyCacheTTL is how long a fetched key set is used, so keys the provider
+// retires stop verifying tokens within the hour.
+const keyCacheTTL = time.Hour
+
+// refetchInterval is the least time between key set fetches prompted by
+// an unknown key ID, so forged key IDs cannot hammer the provider.
+const refetchInterval = time.Minute
+
+// Claims are the claims of an ID token that proof a human.
+type Claims struct {
+	Issuer        string          `json:"iss"`
+	Subject       string          `json:"sub"`
+	Audience      dcpjwt.Audience `json:"aud"`
+	ExpiresAt     int64           `json:"exp"`
+	IssuedAt      int64           `json:"iat"`
+	Nonce         string          `json:"nonce,omitempty"`
+	Email         string          `json:"email,omitempty"`
+	EmailVerified bool            `json:"email_verified,omitempty"`
+	Name          string          `json:"name,omitempty"`
+}
+
+// Provider verifies the ID tokens an OpenID Provider issues to a client,
+// with the keys it publishes, found through OpenID Connect discovery. A
+// Provider is safe for concurrent use.
+type Provider struct {
+	// Issuer is the provider's issuer identifier, e.g.
+	// "https://accounts.example.com". Required.
+	Issuer string
+	// ClientID is the client ID tokens must be issued to. Required.
+	ClientID string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Leeway tolerates clock skew in token lifetimes; zero means one
+	// minute.
+	Leeway time.Duration
+	// Now is the clock token lifetimes are checked against; nil means
+	// time.Now.
+	Now func() time.Time
+
+	mu      sync.Mutex
+	keys    []jwk
+	fetched time.Time
+}
+
+// jwk is a public key of a JWK Set.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// publicKey returns k as a Go public key.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+	switch {
+	case k.Kty == "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil || len(e) > 4 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case k.Kty == "EC" && k.Crv == "P-256":
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("EC key is not on P-256")
+		}
+		return pub, nil
+	case k.Kty == "OKP" && k.Crv == "Ed25519":
+		x, err := decode(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s %s", k.Kty, k.Crv)
+}
+
+// Verify checks that rawIDToken is an ID token the provider issued to the
+// client and that it is within its lifetime, and returns its claims. Its
+// errors wrap ErrInvalidToken, except for failures to fetch the keys.
+func (p *Provider) Verify(ctx context.Context, rawIDToken string) (*Claims, error) {
+	c, err := p.VerifySignature(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if p.Now != nil {
+		now = p.Now()
+	}
+	leeway := p.Leeway
+	if leeway <= 0 {
+		leeway = time.Minute
+	}
+	if c.ExpiresAt == 0 || now.After(time.Unix(c.ExpiresAt, 0).Add(leeway)) {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	if c.IssuedAt != 0 && now.Add(leeway).Before(time.Unix(c.IssuedAt, 0)) {
+		return nil, fmt.Errorf("%w: issued in the future", ErrInvalidToken)
+	}
+	return c, nil
+}
+
+// VerifySignature is Verify without the lifetime check, for re-checking
+// a token long after it expired: it still requires the provider's current
+// keys to verify it.
+func (p *Provider) VerifySignature(ctx context.Context, rawIDToken string) (*Claims, error) {
+	if p.Issuer == "" || p.ClientID == "" {
+		return nil, errors.New("oidcproof: an issuer and a client ID are required")
+	}
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a compact JWS", ErrInvalidToken)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature is not base64url", ErrInvalidToken)
+	}
+	input := []byte(parts[0] + "." + parts[1])
+	keys, err := p.candidates(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	verified := false
+	for _, k := range keys {
+		if verifyJWS(header.Alg, k, input, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("%w: not signed by a key of %s", ErrInvalidToken, p.Issuer)
+	}
+	var c Claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	if c.Issuer != p.Issuer {
+		return nil, fmt.Errorf("%w: issued by %q, not %q", ErrInvalidToken, c.Issuer, p.Issuer)
+	}
+	if !c.Audience.Contains(p.ClientID) {
+		return nil, fmt.Errorf("%w: not issued to client %s", ErrInvalidToken, p.ClientID)
+	}
+	if c.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	return &c, nil
+}
+
+// verifyJWS reports whether sig is a signature of input by key under alg.
+func verifyJWS(alg string, key crypto.PublicKey, input, sig []byte) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			return false
+		}
+		h := sha256.Sum256(input)
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], sig) == nil
+	case *ecdsa.PublicKey:
+		if alg != "ES256" || len(sig) != 64 {
+			return false
+		}
+		h := sha256.Sum256(input)
+		return ecdsa.Verify(k, h[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]))
+	case ed25519.PublicKey:
+		return alg == "EdDSA" && ed25519.Verify(k, input, sig)
+	}
+	return false
+}
+
+// candidates returns the provider's keys with ID kid, or all of them if
+// kid is empty, fetching the key set if none is fresh or kid is unknown.
+func (p *Provider) candidates(ctx context.Context, kid string) ([]crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	age := time.Since(p.fetched)
+	keys := match(p.keys, kid)
+	if p.keys == nil || age >= keyCacheTTL || (len(keys) == 0 && age >= refetchInterval) {
+		fetched, err := p.fetchKeys(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("oidcproof: keys of %s: %v", p.Issuer, err)
+		}
+		p.keys, p.fetched = fetched, time.Now()
+		keys = match(p.keys, kid)
+	}
+	return keys, nil
+}
+
+// match returns the usable keys of set with ID kid, or all if kid is empty.
+func match(set []jwk, kid string) []crypto.PublicKey {
+	var keys []crypto.PublicKey
+	for _, k := range set {
+		if kid != "" && k.Kid != kid {
+			continue
+		}
+		if pub, err := k.publicKey(); err == nil {
+			keys = append(keys, pub)
+		}
+	}
+	return keys
+}
+
+// fetchKeys fetches the provider's key set through its discovery document.
+func (p *Provider) fetchKeys(ctx context.Context) ([]jwk, error) {
+	var config struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := p.get(ctx, strings.TrimSuffix(p.Issuer, "/")+DiscoveryPath, &config); err != nil {
+		return nil, err
+	}
+	if config.Issuer != p.Issuer {
+		return nil, fmt.Errorf("discovery document names issuer %q", config.Issuer)
+	}
+	if config.JWKSURI == "" {
+		return nil, errors.New("discovery document has no jwks_uri")
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.get(ctx, config.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+	if set.Keys == nil {
+		set.Keys = []jwk{}
+	}
+	return set.Keys, nil
+}
+
+func (p *Provider) get(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentBytes+1))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	if len(data) > maxDocumentBytes {
+		return fmt.Errorf("GET %s: response exceeds %d bytes", url, maxDocumentBytes)
+	}
+	return json.Unmarshal(data, v)
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return errors.New("not base64url")
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,173 @@
+package oidcproof_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/oidcproof"
+)
+
+const clientID = "dcp-onboarding"
+
+// op is a fake OpenID Provider signing with an RSA, an EC and an Ed25519
+// key.
+type op struct {
+	srv  *httptest.Server
+	mu   sync.Mutex
+	keys map[string]crypto.Signer
+}
+
+func newOP(t *testing.T) *op {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	o := &op{keys: map[string]crypto.Signer{"rsa-1": rsaKey, "ec-1": ecKey, "ed-1": edKey}}
+	o.srv = httptest.NewServer(http.HandlerFunc(o.serve))
+	t.Cleanup(o.srv.Close)
+	return o
+}
+
+func (o *op) serve(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case oidcproof.DiscoveryPath:
+		json.NewEncoder(w).Encode(map[string]string{"issuer": o.srv.URL, "jwks_uri": o.srv.URL + "/jwks"})
+	case "/jwks":
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		var keys []map[string]string
+		b64 := base64.RawURLEncoding.EncodeToString
+		for kid, k := range o.keys {
+			switch pub := k.Public().(type) {
+			case *rsa.PublicKey:
+				keys = append(keys, map[string]string{"kty": "RSA", "kid": kid, "n": b64(pub.N.Bytes()), "e": b64(big.NewInt(int64(pub.E)).Bytes())})
+			case *ecdsa.PublicKey:
+				keys = append(keys, map[string]string{"kty": "EC", "kid": kid, "crv": "P-256", "x": b64(pub.X.FillBytes(make([]byte, 32))), "y": b64(pub.Y.FillBytes(make([]byte, 32)))})
+			case ed25519.PublicKey:
+				keys = append(keys, map[string]string{"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": b64(pub)})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// retire removes the key kid from the provider's key set.
+func (o *op) retire(kid string) {
+	o.mu.Lock()
+	delete(o.keys, kid)
+	o.mu.Unlock()
+}
+
+// claims returns valid claims of alice, edited by edit if not nil.
+func (o *op) claims(edit func(map[string]interface{})) map[string]interface{} {
+	c := map[string]interface{}{
+		"iss":            o.srv.URL,
+		"sub":            "alice-123",
+		"aud":            clientID,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"name":           "Alice Example",
+	}
+	if edit != nil {
+		edit(c)
+	}
+	return c
+}
+
+// mint returns claims signed with the key kid under alg.
+func (o *op) mint(t *testing.T, kid, alg string, claims map[string]interface{}) string {
+	t.Helper()
+	b64 := base64.RawURLEncoding.EncodeToString
+	h, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	input := b64(h) + "." + b64(c)
+	o.mu.Lock()
+	key := o.keys[kid]
+	o.mu.Unlock()
+	var sig []byte
+	var err error
+	digest := sha256.Sum256([]byte(input))
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, digest[:])
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, []byte(input))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + b64(sig)
+}
+
+func (o *op) provider() *oidcproof.Provider {
+	return &oidcproof.Provider{Issuer: o.srv.URL, ClientID: clientID, HTTPClient: o.srv.Client()}
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	o := newOP(t)
+	p := o.provider()
+	for kid, alg := range map[string]string{"rsa-1": "RS256", "ec-1": "ES256", "ed-1": "EdDSA"} {
+		c, err := p.Verify(ctx, o.mint(t, kid, alg, o.claims(nil)))
+		if err != nil || c.Subject != "alice-123" || c.Email != "alice@example.com" || !c.EmailVerified {
+			t.Fatalf("%s: %+v, %v", alg, c, err)
+		}
+	}
+
+	for name, token := range map[string]string{
+		"wrong alg":      o.mint(t, "rsa-1", "RS512", o.claims(nil)),
+		"unknown key":    o.mint(t, "ed-1", "EdDSA", o.claims(nil))[:10] + "x.y.z",
+		"other client":   o.mint(t, "ed-1", "EdDSA", o.claims(func(c map[string]interface{}) { c["aud"] = []string{"other"} })),
+		"other issuer":   o.mint(t, "ed-1", "EdDSA", o.claims(func(c map[string]interface{}) { c["iss"] = "https://evil.example" })),
+		"expired":        o.mint(t, "ed-1", "EdDSA", o.claims(func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() })),
+		"no subject":     o.mint(t, "ed-1", "EdDSA", o.claims(func(c map[string]interface{}) { delete(c, "sub") })),
+		"not a JWS":      "not-a-token",
+		"alg none":       "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"x"}`)) + ".",
+		"tampered claim": o.mint(t, "ed-1", "EdDSA", o.claims(nil))[:40] + "A" + o.mint(t, "ed-1", "EdDSA", o.claims(nil))[41:],
+	} {
+		if _, err := p.Verify(ctx, token); !errors.Is(err, oidcproof.ErrInvalidToken) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	expired := o.mint(t, "ed-1", "EdDSA", o.claims(func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() }))
+	if _, err := p.VerifySignature(ctx, expired); err != nil {
+		t.Fatalf("signature of an expired token: %v", err)
+	}
+}
+
+func TestVerifyKeysUnreachable(t *testing.T) {
+	o := newOP(t)
+	token := o.mint(t, "ed-1", "EdDSA", o.claims(nil))
+	o.srv.Close()
+	_, err := o.provider().Verify(context.Background(), token)
+	if err == nil || errors.Is(err, oidcproof.ErrInvalidToken) {
+		t.Fatalf("unreachable provider: %v", err)
+	}
+}
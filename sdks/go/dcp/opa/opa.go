@@ -0,0 +1,223 @@
+// Package opa decides DCP intents with Open Policy Agent, so organizations
+// that keep their policies in Rego can reuse them behind the DCP-02 policy
+// decision point. Rego evaluates policies in-process, from Rego sources or
+// an OPA bundle; Remote queries the Data API of an OPA server. Both are
+// pdp.Backends:
+//
+//	backend, _ := opa.New(ctx, opa.Config{Bundle: "policy.tar.gz", Query: "data.dcp.decision"})
+//	srv, _ := pdp.New(pdp.Config{Backend: backend, Signer: signer})
+//
+// The policy's input document is
+//
+//	{"intent": {...}, "passport": {...}, "principal": {...}, "now": "2026-03-04T10:00:00Z"}
+//
+// with the records in their JSON form, and passport and principal null when
+// unknown. A PDP with a pdp.RiskScorer adds "risk": {"score": 0.4,
+// "factors": {...}}, and one with a pdp.GeoResolver "target_country". The decision document is either a boolean, true approving the
+// intent and false blocking it, or an object:
+//
+//	{"decision": "escalate", "risk_score": 0.6, "reasons": ["..."], "required_confirmation": {...}}
+//
+// of which only decision is required. An intent the decision is undefined
+// for gets the configured default, escalate if unset.
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+	"github.com/open-policy-agent/opa/v1/loader"
+	"github.com/open-policy-agent/opa/v1/rego"
+)
+
+// DefaultQuery is the query of the decision document when none is set.
+const DefaultQuery = "data.dcp.decision"
+
+// Config configures an in-process Rego backend. At least one of Modules and
+// Bundle is required.
+type Config struct {
+	// Query is the Rego query whose value is the decision document; empty
+	// means DefaultQuery.
+	Query string
+	// Modules are Rego sources by file name.
+	Modules map[string]string
+	// Bundle is the path of an OPA bundle, a directory or a .tar.gz file.
+	Bundle string
+	// Data is the base data document, in addition to a bundle's data.
+	Data map[string]interface{}
+	// Default decides intents the decision is undefined for; empty means
+	// escalate.
+	Default dcp.Decision
+}
+
+// Rego is a pdp.Backend evaluating a compiled Rego policy. Create one with
+// New; it is safe for concurrent use.
+type Rego struct {
+	query rego.PreparedEvalQuery
+	hash  string
+	def   dcp.Decision
+}
+
+// New compiles the policy of cfg.
+func New(ctx context.Context, cfg Config) (*Rego, error) {
+	if len(cfg.Modules) == 0 && cfg.Bundle == "" {
+		return nil, errors.New("opa: Rego modules or a bundle are required")
+	}
+	if err := checkDefault(cfg.Default); err != nil {
+		return nil, err
+	}
+	query := cfg.Query
+	if query == "" {
+		query = DefaultQuery
+	}
+	// The policy hash covers everything the decision depends on: the
+	// query, the sources and the data.
+	policy := map[string]interface{}{"query": query, "modules": cfg.Modules, "data": cfg.Data}
+	opts := []func(*rego.Rego){rego.Query(query)}
+	names := make([]string, 0, len(cfg.Modules))
+	for name := range cfg.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opts = append(opts, rego.Module(name, cfg.Modules[name]))
+	}
+	if cfg.Data != nil {
+		opts = append(opts, rego.Data(cfg.Data))
+	}
+	if cfg.Bundle != "" {
+		b, err := loader.NewFileLoader().AsBundle(cfg.Bundle)
+		if err != nil {
+			return nil, fmt.Errorf("opa: load bundle: %w", err)
+		}
+		modules := map[string]string{}
+		for _, m := range b.Modules {
+			modules[m.Path] = string(m.Raw)
+		}
+		policy["bundle"] = map[string]interface{}{"revision": b.Manifest.Revision, "modules": modules, "data": b.Data}
+		opts = append(opts, rego.ParsedBundle(cfg.Bundle, b))
+	}
+	hash, err := dcp.HashObject(policy)
+	if err != nil {
+		return nil, fmt.Errorf("opa: hash policy: %w", err)
+	}
+	pq, err := rego.New(opts...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("opa: compile policy: %w", err)
+	}
+	return &Rego{query: pq, hash: hash, def: cfg.Default}, nil
+}
+
+// PolicyHash returns the hash of the query, sources and data.
+func (r *Rego) PolicyHash() string {
+	return r.hash
+}
+
+// Evaluate decides the intent of in.
+func (r *Rego) Evaluate(ctx context.Context, in pdp.Input) (dcp.PolicyDecision, error) {
+	input, err := inputDocument(in)
+	if err != nil {
+		return dcp.PolicyDecision{}, err
+	}
+	rs, err := r.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return dcp.PolicyDecision{}, fmt.Errorf("opa: %w", err)
+	}
+	var result interface{}
+	if len(rs) > 0 && len(rs[0].Expressions) > 0 {
+		result = rs[0].Expressions[0].Value
+	}
+	return decision(in.Intent, result, r.def)
+}
+
+// inputDocument returns the input document of in.
+func inputDocument(in pdp.Input) (map[string]interface{}, error) {
+	now := in.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	doc := map[string]interface{}{
+		"intent":    in.Intent,
+		"passport":  in.Passport,
+		"principal": in.Principal,
+		"now":       dcp.FormatTime(now),
+	}
+	if in.Risk != nil {
+		doc["risk"] = map[string]interface{}{"score": in.Risk.Score, "factors": in.Risk.Factors}
+	}
+	if in.TargetCountry != "" {
+		doc["target_country"] = in.TargetCountry
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("opa: input: %w", err)
+	}
+	var input map[string]interface{}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, fmt.Errorf("opa: input: %w", err)
+	}
+	return input, nil
+}
+
+// decision maps the decision document result for intent to a policy
+// decision. A nil result is undefined and gets def, escalate if empty.
+func decision(intent *dcp.Intent, result interface{}, def dcp.Decision) (dcp.PolicyDecision, error) {
+	d := dcp.PolicyDecision{DCPVersion: "1.0", IntentID: intent.IntentID, Reasons: []string{}}
+	switch v := result.(type) {
+	case nil:
+		if def == "" {
+			def = dcp.DecisionEscalate
+		}
+		d.Decision = def
+		d.Reasons = append(d.Reasons, fmt.Sprintf("policy is undefined for the intent; default %s", def))
+	case bool:
+		d.Decision = dcp.DecisionBlock
+		if v {
+			d.Decision = dcp.DecisionApprove
+		}
+		d.Reasons = append(d.Reasons, fmt.Sprintf("policy: %s", d.Decision))
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return dcp.PolicyDecision{}, fmt.Errorf("opa: decision: %w", err)
+		}
+		var doc struct {
+			Decision             dcp.Decision              `json:"decision"`
+			RiskScore            float64                   `json:"risk_score"`
+			Reasons              []string                  `json:"reasons"`
+			RequiredConfirmation *dcp.RequiredConfirmation `json:"required_confirmation"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return dcp.PolicyDecision{}, fmt.Errorf("opa: decision: %w", err)
+		}
+		d.Decision, d.RiskScore, d.RequiredConfirmation = doc.Decision, doc.RiskScore, doc.RequiredConfirmation
+		if len(doc.Reasons) > 0 {
+			d.Reasons = doc.Reasons
+		} else {
+			d.Reasons = append(d.Reasons, fmt.Sprintf("policy: %s", d.Decision))
+		}
+	default:
+		return dcp.PolicyDecision{}, fmt.Errorf("opa: decision is %T, not a boolean or an object", result)
+	}
+	if d.Decision != dcp.DecisionApprove && d.RequiredConfirmation == nil {
+		d.RequiredConfirmation = &dcp.RequiredConfirmation{Type: "human_approve", Fields: []string{"action_type", "target", "estimated_impact"}}
+	}
+	if err := d.Validate(); err != nil {
+		return dcp.PolicyDecision{}, fmt.Errorf("opa: decision: %w", err)
+	}
+	return d, nil
+}
+
+func checkDefault(def dcp.Decision) error {
+	switch def {
+	case "", dcp.DecisionApprove, dcp.DecisionEscalate, dcp.DecisionBlock:
+		return nil
+	}
+	return fmt.Errorf("opa: unknown default decision %q", def)
+}
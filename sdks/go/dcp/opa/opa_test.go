@@ -0,0 +1,208 @@
+package opa_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/opa"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+const policy = `package dcp
+
+import rego.v1
+
+default decision := {"decision": "escalate", "reasons": ["no rule allows the action"]}
+
+decision := {"decision": "block", "risk_score": 0.9, "reasons": [sprintf("%s is blocked", [input.intent.target.to])]} if {
+	input.intent.target.channel == "email"
+	endswith(input.intent.target.to, data.blocked_domain)
+}
+
+decision := {"decision": "approve", "reasons": ["low-risk agent"]} if {
+	input.intent.target.channel == "email"
+	not endswith(input.intent.target.to, data.blocked_domain)
+	input.passport.risk_tier == "low"
+}
+
+allow if input.intent.action_type == "browse"
+`
+
+func testIntent(to string) *dcp.Intent {
+	return &dcp.Intent{
+		DCPVersion:      "1.0",
+		IntentID:        "intent001",
+		AgentID:         "did:agent:agent123",
+		HumanID:         "did:human:alice123",
+		Timestamp:       "2026-01-01T01:00:00Z",
+		ActionType:      "send_email",
+		Target:          dcp.IntentTarget{Channel: dcp.ChannelEmail, To: &to},
+		DataClasses:     []string{"contact_info"},
+		EstimatedImpact: dcp.ImpactMedium,
+	}
+}
+
+func TestRego(t *testing.T) {
+	ctx := context.Background()
+	r, err := opa.New(ctx, opa.Config{
+		Modules: map[string]string{"dcp.rego": policy},
+		Data:    map[string]interface{}{"blocked_domain": "@evil.example"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	low := &dcp.AgentPassport{AgentID: "did:agent:agent123", RiskTier: dcp.RiskTierLow}
+	for _, tc := range []struct {
+		name   string
+		in     pdp.Input
+		want   dcp.Decision
+		reason string
+	}{
+		{"low-risk agent", pdp.Input{Intent: testIntent("bob@example.com"), Passport: low}, dcp.DecisionApprove, "low-risk agent"},
+		{"blocked domain", pdp.Input{Intent: testIntent("eve@evil.example"), Passport: low}, dcp.DecisionBlock, "eve@evil.example is blocked"},
+		{"no passport", pdp.Input{Intent: testIntent("bob@example.com")}, dcp.DecisionEscalate, "no rule allows the action"},
+	} {
+		d, err := r.Evaluate(ctx, tc.in)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if d.Decision != tc.want || d.IntentID != "intent001" || len(d.Reasons) != 1 || d.Reasons[0] != tc.reason {
+			t.Errorf("%s: %+v", tc.name, d)
+		}
+		if d.Decision != dcp.DecisionApprove && d.RequiredConfirmation == nil {
+			t.Errorf("%s: no required confirmation", tc.name)
+		}
+	}
+
+	same, err := opa.New(ctx, opa.Config{
+		Modules: map[string]string{"dcp.rego": policy},
+		Data:    map[string]interface{}{"blocked_domain": "@evil.example"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := opa.New(ctx, opa.Config{
+		Modules: map[string]string{"dcp.rego": policy},
+		Data:    map[string]interface{}{"blocked_domain": "@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PolicyHash() != same.PolicyHash() || r.PolicyHash() == other.PolicyHash() {
+		t.Fatal("policy hash does not follow the policy")
+	}
+}
+
+func TestRegoDecisionDocuments(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		query string
+		def   dcp.Decision
+		want  dcp.Decision
+		err   string
+	}{
+		{query: "data.dcp.allow", want: dcp.DecisionApprove},
+		{query: "data.dcp.deny", want: dcp.DecisionBlock},
+		{query: "data.dcp.missing", want: dcp.DecisionEscalate},
+		{query: "data.dcp.missing", def: dcp.DecisionBlock, want: dcp.DecisionBlock},
+		{query: "data.dcp.count", err: "not a boolean or an object"},
+		{query: "data.dcp.bad", err: "decision"},
+		{query: "data.dcp.risky", err: "risk_score"},
+	} {
+		r, err := opa.New(ctx, opa.Config{Query: tc.query, Default: tc.def, Modules: map[string]string{"dcp.rego": `package dcp
+
+allow := input.now != ""
+deny := false
+count := 3
+bad := {"decision": "maybe"}
+risky := {"decision": "approve", "risk_score": 2}
+`}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := r.Evaluate(ctx, pdp.Input{Intent: testIntent("bob@example.com"), Now: time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)})
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: %+v, %v", tc.query, d, err)
+			}
+			continue
+		}
+		if err != nil || d.Decision != tc.want {
+			t.Errorf("%s: %+v, %v", tc.query, d, err)
+		}
+	}
+}
+
+func TestRegoBundle(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "dcp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dcp", "policy.rego"), []byte(policy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "data.json"), []byte(`{"blocked_domain": "@example.com"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	r, err := opa.New(ctx, opa.Config{Bundle: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := r.Evaluate(ctx, pdp.Input{Intent: testIntent("bob@example.com")})
+	if err != nil || d.Decision != dcp.DecisionBlock {
+		t.Fatalf("bundle data: %+v, %v", d, err)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	ctx := context.Background()
+	for name, cfg := range map[string]opa.Config{
+		"no policy":        {},
+		"syntax":           {Modules: map[string]string{"dcp.rego": "package dcp\n\ndecision := {"}},
+		"unknown default":  {Modules: map[string]string{"dcp.rego": policy}, Default: "maybe"},
+		"bundle not found": {Bundle: filepath.Join(t.TempDir(), "missing")},
+	} {
+		if _, err := opa.New(ctx, cfg); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func TestBackend(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := opa.New(context.Background(), opa.Config{
+		Modules: map[string]string{"dcp.rego": policy},
+		Data:    map[string]interface{}{"blocked_domain": "@evil.example"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := pdp.New(pdp.Config{Backend: r, Signer: signer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	intent := testIntent("eve@evil.example")
+	d, err := srv.Decide(context.Background(), intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.PolicyDecision.Decision != dcp.DecisionBlock || d.PolicyHash != r.PolicyHash() {
+		t.Fatalf("decision: %+v", d)
+	}
+	if err := d.Verify(kp.PublicKeyB64, intent); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pdp.New(pdp.Config{Backend: r, Policy: &pdp.PolicySet{}, Signer: signer}); err == nil {
+		t.Fatal("a policy set and a backend accepted together")
+	}
+}
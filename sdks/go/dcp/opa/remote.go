@@ -0,0 +1,101 @@
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+// Remote is a pdp.Backend querying the Data API of an OPA server, which
+// keeps loading and updating its own bundles.
+type Remote struct {
+	// URL is the base URL of the OPA server, such as http://localhost:8181.
+	URL string
+	// Path is the path of the decision document, such as dcp/decision;
+	// empty means that of DefaultQuery.
+	Path string
+	// Default decides intents the decision is undefined for; empty means
+	// escalate.
+	Default dcp.Decision
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// QueryPath returns the Data API path of a Rego query such as
+// data.dcp.decision.
+func QueryPath(query string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(query, "data."), ".", "/")
+}
+
+func (r *Remote) path() string {
+	if r.Path == "" {
+		return QueryPath(DefaultQuery)
+	}
+	return strings.Trim(r.Path, "/")
+}
+
+// PolicyHash returns the hash of the server URL and decision path. The
+// policy itself is the server's, so the hash names where decisions were
+// made rather than what they were made under.
+func (r *Remote) PolicyHash() string {
+	h, _ := dcp.HashObject(map[string]string{"opa": strings.TrimRight(r.URL, "/"), "path": r.path()})
+	return h
+}
+
+// Evaluate decides the intent of in with POST /v1/data/{path}.
+func (r *Remote) Evaluate(ctx context.Context, in pdp.Input) (dcp.PolicyDecision, error) {
+	if err := checkDefault(r.Default); err != nil {
+		return dcp.PolicyDecision{}, err
+	}
+	input, err := inputDocument(in)
+	if err != nil {
+		return dcp.PolicyDecision{}, err
+	}
+	body, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return dcp.PolicyDecision{}, fmt.Errorf("opa: %w", err)
+	}
+	url := strings.TrimRight(r.URL, "/") + "/v1/data/" + r.path()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return dcp.PolicyDecision{}, fmt.Errorf("opa: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return dcp.PolicyDecision{}, fmt.Errorf("opa: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return dcp.PolicyDecision{}, fmt.Errorf("opa: %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// OPA answers errors as {"code": ..., "message": ...}.
+		var e struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &e) != nil || e.Message == "" {
+			e.Message = strings.TrimSpace(string(data))
+		}
+		return dcp.PolicyDecision{}, fmt.Errorf("opa: %s: %s: %s", url, resp.Status, e.Message)
+	}
+	var res struct {
+		Result interface{} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return dcp.PolicyDecision{}, fmt.Errorf("opa: %s: %w", url, err)
+	}
+	return decision(in.Intent, res.Result, r.Default)
+}
@@ -0,0 +1,113 @@
+package opa_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/opa"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+// fakeOPA answers the Data API with a decision taken by decide.
+type fakeOPA struct {
+	mu     sync.Mutex
+	decide func(input map[string]interface{}) (int, string)
+	paths  []string
+}
+
+func (f *fakeOPA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Input map[string]interface{} `json:"input"`
+	}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil {
+		http.Error(w, `{"code": "invalid_parameter", "message": "bad request"}`, http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths = append(f.paths, r.URL.Path)
+	status, body := f.decide(req.Input)
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+func TestRemote(t *testing.T) {
+	f := &fakeOPA{decide: func(input map[string]interface{}) (int, string) {
+		target := input["intent"].(map[string]interface{})["target"].(map[string]interface{})
+		if input["passport"] != nil || input["now"] == "" {
+			return http.StatusOK, `{"result": false}`
+		}
+		if strings.HasSuffix(target["to"].(string), "@example.com") {
+			return http.StatusOK, `{"result": {"decision": "approve", "reasons": ["internal recipient"]}}`
+		}
+		return http.StatusOK, `{}`
+	}}
+	ts := httptest.NewServer(f)
+	defer ts.Close()
+	ctx := context.Background()
+
+	r := &opa.Remote{URL: ts.URL + "/"}
+	d, err := r.Evaluate(ctx, pdp.Input{Intent: testIntent("bob@example.com")})
+	if err != nil || d.Decision != dcp.DecisionApprove || d.Reasons[0] != "internal recipient" {
+		t.Fatalf("approve: %+v, %v", d, err)
+	}
+	d, err = r.Evaluate(ctx, pdp.Input{Intent: testIntent("bob@example.com"), Passport: &dcp.AgentPassport{AgentID: "did:agent:agent123"}})
+	if err != nil || d.Decision != dcp.DecisionBlock {
+		t.Fatalf("false: %+v, %v", d, err)
+	}
+	d, err = r.Evaluate(ctx, pdp.Input{Intent: testIntent("eve@evil.example")})
+	if err != nil || d.Decision != dcp.DecisionEscalate {
+		t.Fatalf("undefined: %+v, %v", d, err)
+	}
+	r = &opa.Remote{URL: ts.URL, Path: opa.QueryPath("data.acme.dcp.allow")}
+	if _, err := r.Evaluate(ctx, pdp.Input{Intent: testIntent("bob@example.com")}); err != nil {
+		t.Fatal(err)
+	}
+	if f.paths[0] != "/v1/data/dcp/decision" || f.paths[3] != "/v1/data/acme/dcp/allow" {
+		t.Fatalf("paths: %q", f.paths)
+	}
+	if (&opa.Remote{URL: ts.URL}).PolicyHash() == r.PolicyHash() {
+		t.Fatal("policy hash does not follow the decision path")
+	}
+}
+
+func TestRemoteErrors(t *testing.T) {
+	f := &fakeOPA{decide: func(map[string]interface{}) (int, string) {
+		return http.StatusInternalServerError, `{"code": "internal_error", "message": "policy evaluation failed"}`
+	}}
+	ts := httptest.NewServer(f)
+	defer ts.Close()
+	r := &opa.Remote{URL: ts.URL}
+	if _, err := r.Evaluate(context.Background(), pdp.Input{Intent: testIntent("bob@example.com")}); err == nil || !strings.Contains(err.Error(), "policy evaluation failed") {
+		t.Fatalf("server error: %v", err)
+	}
+
+	// An OPA server that cannot decide makes the PDP unavailable rather
+	// than deny the intent.
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := pdp.New(pdp.Config{Backend: r, Signer: signer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(testIntent("bob@example.com"))
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/decide", strings.NewReader(string(body))))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/policy", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), r.PolicyHash()) || strings.Contains(w.Body.String(), `"policy":`) {
+		t.Fatalf("policy: %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,30 @@
+// Package openapi embeds the OpenAPI 3.1 document of the SDK's HTTP
+// services: verifyserver, registry, revocationserver and pdp. Each service
+// serves it at GET /openapi.json, so a client in any language can be
+// generated from a running service; package apiclient is the Go client.
+//
+// openapi.json is maintained by hand alongside the handlers. The package
+// tests check that every documented operation is routed by its service and
+// that the response schemas list the fields the Go types encode.
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+// Spec returns a copy of the OpenAPI document.
+func Spec() []byte {
+	return append([]byte(nil), spec...)
+}
+
+// Handler serves the OpenAPI document.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	})
+}
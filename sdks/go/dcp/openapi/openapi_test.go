@@ -0,0 +1,183 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/openapi"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/revocationserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/verifyserver"
+)
+
+type document struct {
+	OpenAPI string                                `json:"openapi"`
+	Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	Tags    []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func parse(t *testing.T) document {
+	t.Helper()
+	var doc document
+	if err := json.Unmarshal(openapi.Spec(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.1") {
+		t.Fatalf("openapi = %q", doc.OpenAPI)
+	}
+	return doc
+}
+
+// services returns a handler for each tag of the document.
+func services(t *testing.T) map[string]http.Handler {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := registry.New(registry.Config{Signer: signer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	revocations, err := revocationserver.New(revocationserver.Config{Signer: signer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decider, err := pdp.New(pdp.Config{Policy: &pdp.PolicySet{}, Signer: signer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]http.Handler{
+		"verify":     verifyserver.New(verifyserver.Config{}),
+		"registry":   reg,
+		"revocation": revocations,
+		"pdp":        decider,
+	}
+}
+
+// TestOperationsRouted sends every documented operation to the services of
+// its tags and checks that each answers it in JSON rather than with the
+// mux's plain-text 404 or 405.
+func TestOperationsRouted(t *testing.T) {
+	doc := parse(t)
+	handlers := services(t)
+	for _, tag := range doc.Tags {
+		if handlers[tag.Name] == nil {
+			t.Errorf("tag %q has no service", tag.Name)
+		}
+	}
+	for path, item := range doc.Paths {
+		target := path
+		for _, param := range []string{"{human_id}", "{agent_id}"} {
+			target = strings.ReplaceAll(target, param, "unknown")
+		}
+		for method, raw := range item {
+			var op struct {
+				Tags []string `json:"tags"`
+			}
+			if err := json.Unmarshal(raw, &op); err != nil {
+				t.Fatalf("%s %s: %v", method, path, err)
+			}
+			if len(op.Tags) == 0 {
+				t.Errorf("%s %s has no tag", method, path)
+			}
+			for _, tag := range op.Tags {
+				h := handlers[tag]
+				if h == nil {
+					continue
+				}
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest(strings.ToUpper(method), target, strings.NewReader("{}")))
+				if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+					t.Errorf("%s: %s %s answered %d %q", tag, method, target, rec.Code, ct)
+				}
+			}
+		}
+	}
+}
+
+// TestSchemasMatchTypes checks that each response schema documents exactly
+// the fields its Go type encodes.
+func TestSchemasMatchTypes(t *testing.T) {
+	doc := parse(t)
+	for name, v := range map[string]interface{}{
+		"VerifyResult":         verifyserver.Result{},
+		"TraceStep":            dcp.TraceStep{},
+		"PrincipalInfo":        registry.PrincipalInfo{},
+		"RegistrySnapshot":     registry.Snapshot{},
+		"SignedRevocationList": revocationserver.SignedList{},
+		"SignedDecision":       pdp.SignedDecision{},
+		"PolicySet":            pdp.PolicySet{},
+		"PolicyBundle":         pdp.PolicyBundle{},
+		"PolicyRule":           pdp.Rule{},
+		"TimeWindow":           pdp.TimeWindow{},
+		"RateLimit":            pdp.RateLimit{},
+	} {
+		schema, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("schema %s is missing", name)
+			continue
+		}
+		var documented []string
+		for p := range schema.Properties {
+			documented = append(documented, p)
+		}
+		sort.Strings(documented)
+		encoded := jsonFields(reflect.TypeOf(v))
+		sort.Strings(encoded)
+		if !reflect.DeepEqual(documented, encoded) {
+			t.Errorf("schema %s has %v, %T encodes %v", name, documented, v, encoded)
+		}
+	}
+}
+
+// jsonFields returns the JSON names of the fields of struct type typ,
+// including those of embedded structs.
+func jsonFields(typ reflect.Type) []string {
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch {
+		case name == "-" || !f.IsExported():
+		case f.Anonymous && name == "":
+			names = append(names, jsonFields(f.Type)...)
+		case name == "":
+			names = append(names, f.Name)
+		default:
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	openapi.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" || !json.Valid(rec.Body.Bytes()) {
+		t.Fatalf("GET /openapi.json = %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	spec := openapi.Spec()
+	spec[0] = 'x'
+	if openapi.Spec()[0] == 'x' {
+		t.Fatal("Spec returned the embedded document")
+	}
+}
@@ -0,0 +1,185 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOverrideInvalid is returned for an override record that is not
+// signed by the principal or not linked to its intent and audit entry.
+var ErrOverrideInvalid = errors.New("override invalid")
+
+// OverrideOutcome is the outcome of the audit entry recording an override
+// with action a, such as "override_halt".
+func OverrideOutcome(a OverrideAction) string {
+	return "override_" + string(a)
+}
+
+// OverrideRef returns the reference the audit entry recording o carries in
+// evidence.result_ref: "sha256:" and the hash of the signed record.
+func OverrideRef(o *OverrideRecord) (string, error) {
+	h, err := HashObject(o)
+	if err != nil {
+		return "", fmt.Errorf("override: hash: %w", err)
+	}
+	return "sha256:" + h, nil
+}
+
+// AppendOverride records the principal's override of the agent's action on
+// intent. It completes o from intent and the chain, with prev_hash set to
+// the chain head and timestamped now, signs it with principal, and appends
+// an audit entry referencing it: blocked for a halt, escalated for a
+// modification. It returns the new entry's hash.
+func (c *AuditChain) AppendOverride(intent Intent, o *OverrideRecord, principal BundleSigner) (string, error) {
+	intentHash, err := HashObject(intent)
+	if err != nil {
+		return "", fmt.Errorf("audit chain: intent hash: %w", err)
+	}
+	o.DCPVersion = DCPVersion
+	o.HumanID, o.AgentID, o.IntentID, o.IntentHash = intent.HumanID, intent.AgentID, intent.IntentID, intentHash
+	decision := OutcomeBlocked
+	if o.Action == OverrideModify {
+		decision = OutcomeEscalated
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	o.Timestamp = c.records.timestamp()
+	o.PrevHash = c.head
+	if err := o.Sign(principal); err != nil {
+		return "", fmt.Errorf("audit chain: %w", err)
+	}
+	ref, err := OverrideRef(o)
+	if err != nil {
+		return "", fmt.Errorf("audit chain: %w", err)
+	}
+	return c.append(AuditEntry{
+		DCPVersion:     DCPVersion,
+		AgentID:        intent.AgentID,
+		HumanID:        intent.HumanID,
+		IntentID:       intent.IntentID,
+		IntentHash:     intentHash,
+		PolicyDecision: decision,
+		Outcome:        OverrideOutcome(o.Action),
+		Evidence:       AuditEvidence{ResultRef: &ref},
+	}, o.Timestamp)
+}
+
+// Sign sets Signature to s's signature over the canonical record with an
+// empty signature. An override is signed by the responsible principal.
+func (o *OverrideRecord) Sign(s BundleSigner) error {
+	o.Signature = ""
+	sig, err := signWith(s, o)
+	if err != nil {
+		return fmt.Errorf("sign override of %s: %w", o.IntentID, err)
+	}
+	o.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against the principal's public key.
+func (o *OverrideRecord) VerifySignature(publicKeyB64 string) (bool, error) {
+	if o.Signature == "" {
+		return false, fmt.Errorf("override of %s has no signature", o.IntentID)
+	}
+	unsigned := *o
+	unsigned.Signature = ""
+	return VerifyObject(unsigned, o.Signature, publicKeyB64)
+}
+
+// Check reports whether o is a valid override of intent recorded by entry:
+// it is signed with the principal's key publicKeyB64, names the intent, its
+// agent and principal, and entry follows the same chain head and carries
+// OverrideRef(o). Errors wrap ErrOverrideInvalid.
+func (o *OverrideRecord) Check(intent *Intent, entry *AuditEntry, publicKeyB64 string) error {
+	intentHash, err := HashObject(intent)
+	if err != nil {
+		return fmt.Errorf("%w: intent hash: %v", ErrOverrideInvalid, err)
+	}
+	if err := o.check(intent, intentHash, publicKeyB64); err != nil {
+		return err
+	}
+	ref := ""
+	if entry.Evidence.ResultRef != nil {
+		ref = *entry.Evidence.ResultRef
+	}
+	return o.checkEntry(ref, entry.PrevHash, entry.IntentHash)
+}
+
+func (o *OverrideRecord) check(intent *Intent, intentHash, publicKeyB64 string) error {
+	if ok, err := o.VerifySignature(publicKeyB64); err != nil || !ok {
+		if err == nil {
+			err = errors.New("signature does not verify")
+		}
+		return fmt.Errorf("%w: %v", ErrOverrideInvalid, err)
+	}
+	switch {
+	case o.IntentHash != intentHash:
+		return fmt.Errorf("%w: intent_hash %s, not the intent's %s", ErrOverrideInvalid, o.IntentHash, intentHash)
+	case o.IntentID != intent.IntentID:
+		return fmt.Errorf("%w: intent_id %s, not the intent's %s", ErrOverrideInvalid, o.IntentID, intent.IntentID)
+	case o.AgentID != intent.AgentID:
+		return fmt.Errorf("%w: agent_id %s, not the intent's %s", ErrOverrideInvalid, o.AgentID, intent.AgentID)
+	case o.HumanID != intent.HumanID:
+		return fmt.Errorf("%w: by %s, not the intent's principal %s", ErrOverrideInvalid, o.HumanID, intent.HumanID)
+	case o.Action == OverrideModify && o.ReplacementIntentHash == "":
+		return fmt.Errorf("%w: a modification without replacement_intent_hash", ErrOverrideInvalid)
+	}
+	return nil
+}
+
+// checkEntry checks the linkage of o to the audit entry with result_ref
+// ref, prev_hash prevHash and intent_hash intentHash.
+func (o *OverrideRecord) checkEntry(ref, prevHash, intentHash string) error {
+	want, err := OverrideRef(o)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOverrideInvalid, err)
+	}
+	switch {
+	case ref != want:
+		return fmt.Errorf("%w: audit entry references %q, not %s", ErrOverrideInvalid, ref, want)
+	case prevHash != o.PrevHash:
+		return fmt.Errorf("%w: audit entry follows %s, the override %s", ErrOverrideInvalid, prevHash, o.PrevHash)
+	case intentHash != o.IntentHash:
+		return fmt.Errorf("%w: audit entry is of intent %s, the override of %s", ErrOverrideInvalid, intentHash, o.IntentHash)
+	}
+	return nil
+}
+
+// Validate checks o against the override schema and returns
+// ValidationErrors listing every violation, or nil.
+func (o *OverrideRecord) Validate() error {
+	v := newValidator()
+	o.validate(v)
+	return v.err()
+}
+
+func (o *OverrideRecord) validate(v validator) {
+	v.version("dcp_version", o.DCPVersion)
+	v.idOf("human_id", o.HumanID, IDKindHuman)
+	v.idOf("agent_id", o.AgentID, IDKindAgent)
+	v.idOf("intent_id", o.IntentID, IDKindIntent)
+	v.minLen("intent_hash", o.IntentHash, 8)
+	v.enum("action", string(o.Action), overrides)
+	if o.Action == OverrideModify {
+		v.minLen("replacement_intent_hash", o.ReplacementIntentHash, 8)
+	}
+	v.minLen("reason", o.Reason, 1)
+	v.timestamp("timestamp", o.Timestamp)
+	v.minLen("prev_hash", o.PrevHash, 1)
+	v.signature("signature", o.Signature)
+}
+
+// Clone returns a copy of o.
+func (o *OverrideRecord) Clone() *OverrideRecord {
+	if o == nil {
+		return nil
+	}
+	c := *o
+	return &c
+}
+
+// Equal reports whether o and p canonicalize identically.
+func (o *OverrideRecord) Equal(p *OverrideRecord) bool {
+	return (o == nil) == (p == nil) && (o == nil || canonicalEqual(o, p))
+}
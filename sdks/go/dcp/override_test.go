@@ -0,0 +1,117 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestOverrideRecords(t *testing.T) {
+	human, _ := dcp.GenerateKeypair()
+	principal, err := dcp.NewKeySigner(human.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)
+	chain := dcp.NewAuditChain(dcp.AuditChainOptions{Clock: func() time.Time { return now }})
+	intent := dcp.Intent{DCPVersion: "1.0", IntentID: "intent001", AgentID: "agent001", HumanID: "human001",
+		Timestamp: "2026-01-01T00:59:00Z", ActionType: "initiate_payment", Target: dcp.IntentTarget{Channel: "payments"},
+		DataClasses: []string{"financial_data"}, EstimatedImpact: "high"}
+	if _, err := chain.Append(dcp.AuditEntryFields{Intent: intent, PolicyDecision: dcp.OutcomeApproved, Outcome: "payment_started"}); err != nil {
+		t.Fatal(err)
+	}
+	head := chain.Head()
+	o := dcp.OverrideRecord{Action: dcp.OverrideHalt, Reason: "wrong payee"}
+	if _, err := chain.AppendOverride(intent, &o, principal); err != nil {
+		t.Fatal(err)
+	}
+	entries := chain.Entries()
+	if o.PrevHash != head || entries[1].Outcome != "override_halt" || entries[1].PolicyDecision != dcp.OutcomeBlocked {
+		t.Fatalf("override %+v recorded as %+v", o, entries[1])
+	}
+	if err := o.Check(&intent, &entries[1], human.PublicKeyB64); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Check(&intent, &entries[0], human.PublicKeyB64); !errors.Is(err, dcp.ErrOverrideInvalid) {
+		t.Fatalf("against another entry: %v", err)
+	}
+	other := intent
+	other.IntentID = "intent002"
+	if err := o.Check(&other, &entries[1], human.PublicKeyB64); err == nil || !strings.Contains(err.Error(), "intent_hash") {
+		t.Fatalf("of another intent: %v", err)
+	}
+
+	sign := func(b dcp.CitizenshipBundle) *dcp.SignedBundle {
+		t.Helper()
+		sb, err := dcp.SignBundle(&b, principal, dcp.Signer{ID: "human001"}, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sb
+	}
+	bundle := dcp.CitizenshipBundle{
+		ResponsiblePrincipalRecord: dcp.ResponsiblePrincipalRecord{DCPVersion: "1.0", HumanID: "human001", OverrideRights: true},
+		AgentPassport:              dcp.AgentPassport{DCPVersion: "1.0", AgentID: "agent001"},
+		Intent:                     intent,
+		PolicyDecision:             dcp.PolicyDecision{DCPVersion: "1.0", IntentID: "intent001", Decision: "approve", Reasons: []string{}},
+		AuditEntries:               entries,
+		Overrides:                  []dcp.OverrideRecord{o},
+	}
+	res := dcp.VerifySignedBundleWithOptions(sign(bundle), dcp.VerifyOptions{Explain: true})
+	if !res.Verified {
+		t.Fatalf("bundle: %v", res.Errors)
+	}
+	if last := res.Trace[len(res.Trace)-1]; last.Check != dcp.TraceOverride || !last.OK || last.Detail != "halt" {
+		t.Fatalf("trace: %+v", last)
+	}
+	data, _ := json.Marshal(sign(bundle))
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundle(rsb, human.PublicKeyB64); !res.Verified {
+		t.Fatalf("raw bundle: %v", res.Errors)
+	}
+
+	otherKey, _ := dcp.GenerateKeypair()
+	otherSigner, _ := dcp.NewKeySigner(otherKey.SecretKeyB64)
+	forged := o
+	forged.Sign(otherSigner)
+	for _, tc := range []struct {
+		name   string
+		mutate func(b *dcp.CitizenshipBundle)
+		want   string
+	}{
+		{"no override rights", func(b *dcp.CitizenshipBundle) { b.ResponsiblePrincipalRecord.OverrideRights = false }, "no override rights"},
+		{"another signer", func(b *dcp.CitizenshipBundle) { b.Overrides = []dcp.OverrideRecord{forged} }, "signature"},
+		{"not recorded", func(b *dcp.CitizenshipBundle) { b.AuditEntries = b.AuditEntries[:1] }, "no audit entry records it"},
+	} {
+		b := *bundle.Clone()
+		tc.mutate(&b)
+		res := dcp.VerifySignedBundle(sign(b), human.PublicKeyB64)
+		if res.Verified || !strings.Contains(res.Errors[0], tc.want) {
+			t.Errorf("%s: %v", tc.name, res.Errors)
+		}
+	}
+}
+
+func TestOverrideRecordValidate(t *testing.T) {
+	o := dcp.OverrideRecord{DCPVersion: "1.0", HumanID: "human001", AgentID: "agent001", IntentID: "intent001",
+		IntentHash: strings.Repeat("a", 64), Action: dcp.OverrideModify, Reason: "lower the amount",
+		Timestamp: "2026-01-01T00:00:00Z", PrevHash: "GENESIS"}
+	var verrs dcp.ValidationErrors
+	if err := o.Validate(); !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Pointer != "/replacement_intent_hash" {
+		t.Fatalf("modification without a replacement: %v", err)
+	}
+	o.ReplacementIntentHash = strings.Repeat("b", 64)
+	if err := o.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if at, err := o.TimestampTime(); err != nil || at.Year() != 2026 {
+		t.Fatalf("TimestampTime = %v, %v", at, err)
+	}
+}
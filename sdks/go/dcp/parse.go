@@ -0,0 +1,141 @@
+package dcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RawSignedBundle is a SignedBundle together with the exact JSON it was
+// decoded from. The typed fields are convenient for reading; verification
+// of a RawSignedBundle runs over the raw bytes, so members this SDK does not
+// know about are still covered by the signature and hashes, exactly as in
+// the other SDKs.
+type RawSignedBundle struct {
+	SignedBundle
+
+	// Raw is the complete signed bundle document as received.
+	Raw json.RawMessage
+	// RawBundle is the "bundle" member as received.
+	RawBundle json.RawMessage
+	// RawIntent is the "bundle.intent" member as received.
+	RawIntent json.RawMessage
+	// RawAuditEntries holds each "bundle.audit_entries" element as received.
+	RawAuditEntries []json.RawMessage
+}
+
+// ParseSignedBundle decodes a signed bundle while retaining the received
+// bytes for verification with VerifyRawSignedBundle.
+func ParseSignedBundle(data []byte) (*RawSignedBundle, error) {
+	var outer struct {
+		Bundle json.RawMessage `json:"bundle"`
+	}
+	if err := json.Unmarshal(data, &outer); err != nil {
+		return nil, fmt.Errorf("parse signed bundle: %w", err)
+	}
+	if len(outer.Bundle) == 0 {
+		return nil, fmt.Errorf("parse signed bundle: missing bundle")
+	}
+	var inner struct {
+		Intent       json.RawMessage   `json:"intent"`
+		AuditEntries []json.RawMessage `json:"audit_entries"`
+	}
+	if err := json.Unmarshal(outer.Bundle, &inner); err != nil {
+		return nil, fmt.Errorf("parse bundle: %w", err)
+	}
+	rsb := &RawSignedBundle{
+		Raw:             append(json.RawMessage(nil), data...),
+		RawBundle:       outer.Bundle,
+		RawIntent:       inner.Intent,
+		RawAuditEntries: inner.AuditEntries,
+	}
+	if err := json.Unmarshal(data, &rsb.SignedBundle); err != nil {
+		return nil, fmt.Errorf("parse signed bundle: %w", err)
+	}
+	return rsb, nil
+}
+
+func viewFromRaw(rsb *RawSignedBundle) (*bundleView, error) {
+	bundleCanon, err := CanonicalizeJSON(rsb.RawBundle)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize error: %v", err)
+	}
+	intentCanon, err := CanonicalizeJSON(rsb.RawIntent)
+	if err != nil {
+		return nil, fmt.Errorf("intent hash: %v", err)
+	}
+	v := &bundleView{
+		bundleCanon: bundleCanon,
+		intentCanon: intentCanon,
+		agentKey:    rsb.Bundle.AgentPassport.PublicKey,
+		chainStart:  chainStart(rsb.Bundle.ChainAnchor),
+		passport:    &rsb.Bundle.AgentPassport,
+		intent:      &rsb.Bundle.Intent,
+		decision:    &rsb.Bundle.PolicyDecision,
+		consent:     rsb.Bundle.Consent,
+
+		overrides:      rsb.Bundle.Overrides,
+		overrideRights: rsb.Bundle.ResponsiblePrincipalRecord.OverrideRights,
+		breakGlass:     rsb.Bundle.BreakGlass,
+		cancellation:   rsb.Bundle.Cancellation,
+		history:        rsb.Bundle.IntentHistory,
+		batchManifest:  rsb.Bundle.BatchManifest,
+		delegation:     rsb.Bundle.DelegationChain,
+		principal:      &rsb.Bundle.ResponsiblePrincipalRecord,
+		attorneys:      rsb.Bundle.PowersOfAttorney,
+		insurance:      rsb.Bundle.Insurance,
+	}
+	for _, raw := range rsb.RawAuditEntries {
+		canon, err := CanonicalizeJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("hash audit entry: %v", err)
+		}
+		var links struct {
+			PrevHash       string `json:"prev_hash"`
+			IntentHash     string `json:"intent_hash"`
+			AgentSignature string `json:"agent_signature"`
+			Timestamp      string `json:"timestamp"`
+			Outcome        string `json:"outcome"`
+			Evidence       struct {
+				ResultRef *string    `json:"result_ref"`
+				BatchItem *BatchItem `json:"batch_item"`
+			} `json:"evidence"`
+		}
+		if err := json.Unmarshal(raw, &links); err != nil {
+			return nil, fmt.Errorf("hash audit entry: %v", err)
+		}
+		ev := entryView{canon: canon, prevHash: links.PrevHash, intentHash: links.IntentHash,
+			timestamp: links.Timestamp, outcome: links.Outcome, batchItem: links.Evidence.BatchItem}
+		if links.Evidence.ResultRef != nil {
+			ev.resultRef = *links.Evidence.ResultRef
+		}
+		if links.AgentSignature != "" {
+			var generic map[string]interface{}
+			if err := json.Unmarshal(raw, &generic); err != nil {
+				return nil, fmt.Errorf("hash audit entry: %v", err)
+			}
+			delete(generic, "agent_signature")
+			ev.agentSig = links.AgentSignature
+			if ev.unsignedCanon, err = Canonicalize(generic); err != nil {
+				return nil, fmt.Errorf("hash audit entry: %v", err)
+			}
+		}
+		v.entries = append(v.entries, ev)
+	}
+	return v, nil
+}
+
+// VerifyRawSignedBundle performs the same checks as VerifySignedBundle, but
+// over the received JSON rather than a re-encoding of the typed structs.
+func VerifyRawSignedBundle(rsb *RawSignedBundle, publicKeyB64 string) *VerificationResult {
+	return VerifyRawSignedBundleWithOptions(rsb, VerifyOptions{PublicKeyB64: publicKeyB64})
+}
+
+// VerifySignedBundleJSON parses data with ParseSignedBundle and verifies it
+// over the received bytes.
+func VerifySignedBundleJSON(data []byte, publicKeyB64 string) *VerificationResult {
+	rsb, err := ParseSignedBundle(data)
+	if err != nil {
+		return &VerificationResult{Verified: false, Errors: []string{err.Error()}}
+	}
+	return VerifyRawSignedBundle(rsb, publicKeyB64)
+}
@@ -0,0 +1,96 @@
+package dcp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readSignedBundleFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(fixturesDir(), "examples", "citizenship_bundle.signed.json"))
+	if err != nil {
+		t.Fatalf("failed to read signed bundle: %v", err)
+	}
+	return data
+}
+
+// resignWithExtension adds a member unknown to the Go structs to the intent,
+// fixes up the dependent hashes, and re-signs the bundle with a fresh key.
+func resignWithExtension(t *testing.T) ([]byte, string) {
+	t.Helper()
+	var doc map[string]interface{}
+	if err := json.Unmarshal(readSignedBundleFixture(t), &doc); err != nil {
+		t.Fatal(err)
+	}
+	bundle := doc["bundle"].(map[string]interface{})
+	intent := bundle["intent"].(map[string]interface{})
+	intent["x_vendor_extension"] = "kept"
+	ih, err := HashObject(intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := "GENESIS"
+	var leaves []string
+	for _, e := range bundle["audit_entries"].([]interface{}) {
+		entry := e.(map[string]interface{})
+		entry["intent_hash"] = ih
+		entry["prev_hash"] = prev
+		h, err := HashObject(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prev = h
+		leaves = append(leaves, h)
+	}
+	root, _ := MerkleRootFromHexLeaves(leaves)
+	bh, _ := HashObject(bundle)
+	keys, _ := GenerateKeypair()
+	sigB64, err := SignObject(bundle, keys.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := doc["signature"].(map[string]interface{})
+	sig["bundle_hash"] = "sha256:" + bh
+	sig["merkle_root"] = "sha256:" + root
+	sig["sig_b64"] = sigB64
+	sig["signer"].(map[string]interface{})["public_key_b64"] = keys.PublicKeyB64
+	out, _ := json.Marshal(doc)
+	return out, keys.PublicKeyB64
+}
+
+func TestVerifySignedBundleJSONFixture(t *testing.T) {
+	result := VerifySignedBundleJSON(readSignedBundleFixture(t), "")
+	if !result.Verified {
+		t.Fatalf("fixture should verify over raw bytes: %v", result.Errors)
+	}
+}
+
+func TestVerifyRawKeepsUnknownMembers(t *testing.T) {
+	data, pub := resignWithExtension(t)
+
+	rsb, err := ParseSignedBundle(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := VerifyRawSignedBundle(rsb, pub); !r.Verified {
+		t.Fatalf("raw verification should succeed: %v", r.Errors)
+	}
+	// The typed path drops x_vendor_extension and must not verify.
+	if r := VerifySignedBundle(&rsb.SignedBundle, pub); r.Verified {
+		t.Fatal("struct verification unexpectedly succeeded without the extension member")
+	}
+
+	tampered := strings.Replace(string(data), `"kept"`, `"changed"`, 1)
+	if r := VerifySignedBundleJSON([]byte(tampered), pub); r.Verified {
+		t.Fatal("tampered extension member should fail verification")
+	}
+}
+
+func TestParseSignedBundleRejectsMissingBundle(t *testing.T) {
+	if _, err := ParseSignedBundle([]byte(`{"signature":{}}`)); err == nil {
+		t.Fatal("expected error for missing bundle")
+	}
+}
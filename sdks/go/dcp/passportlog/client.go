@@ -0,0 +1,177 @@
+package passportlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Client submits passports to a log and audits it. Every receipt and head
+// it returns has been verified under the log's key, and successive heads
+// from Checkpoint are checked to extend one another, so a log that rewrites
+// its history is detected by any client that keeps polling.
+type Client struct {
+	// URL is the log base URL, e.g. "http://localhost:3002".
+	URL string
+	// PublicKeyB64 is the log's tree head signing key.
+	PublicKeyB64 string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	mu   sync.Mutex
+	head *dcp.Checkpoint
+}
+
+// Submit logs p and returns the verified receipt of its entry.
+func (c *Client) Submit(ctx context.Context, p dcp.AgentPassport) (*Receipt, error) {
+	var r Receipt
+	if err := c.do(ctx, http.MethodPost, "/v1/entries", p, &r); err != nil {
+		return nil, err
+	}
+	if err := r.Verify(c.PublicKeyB64); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.URL, err)
+	}
+	want, err := dcp.HashObject(p)
+	if err != nil {
+		return nil, err
+	}
+	if got, err := dcp.HashObject(r.Entry.Passport); err != nil || got != want {
+		return nil, fmt.Errorf("%s: receipt is for another passport than %s", c.URL, p.AgentID)
+	}
+	return &r, nil
+}
+
+// Receipt fetches and verifies the receipt of entry i under the log's
+// current head.
+func (c *Client) Receipt(ctx context.Context, i int64) (*Receipt, error) {
+	var r Receipt
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/entries/%d/proof", i), nil, &r); err != nil {
+		return nil, err
+	}
+	if err := r.Verify(c.PublicKeyB64); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.URL, err)
+	}
+	if r.Entry.Index != i {
+		return nil, fmt.Errorf("%s: asked for entry %d, got %d", c.URL, i, r.Entry.Index)
+	}
+	return &r, nil
+}
+
+// Checkpoint fetches the log's signed tree head. After the first call, it
+// also fetches a consistency proof from the previous head and fails if the
+// new head does not extend it.
+func (c *Client) Checkpoint(ctx context.Context) (*dcp.Checkpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.head == nil {
+		var cp dcp.Checkpoint
+		if err := c.do(ctx, http.MethodGet, "/v1/checkpoint", nil, &cp); err != nil {
+			return nil, err
+		}
+		if ok, err := cp.Verify(c.PublicKeyB64); err != nil || !ok {
+			return nil, fmt.Errorf("%s: checkpoint signature does not verify under the log key", c.URL)
+		}
+		c.head = &cp
+		return c.head, nil
+	}
+	var resp struct {
+		Checkpoint *dcp.Checkpoint `json:"checkpoint"`
+		Proof      []string        `json:"proof"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/consistency?first=%d", c.head.TreeSize), nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Checkpoint == nil {
+		return nil, fmt.Errorf("%s: consistency answer has no checkpoint", c.URL)
+	}
+	if ok, err := resp.Checkpoint.Verify(c.PublicKeyB64); err != nil || !ok {
+		return nil, fmt.Errorf("%s: checkpoint signature does not verify under the log key", c.URL)
+	}
+	if err := dcp.VerifyCheckpointContinuity(c.head, resp.Checkpoint, resp.Proof); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.URL, err)
+	}
+	c.head = resp.Checkpoint
+	return c.head, nil
+}
+
+// AgentEntries returns every logged passport with agentID. A monitor that
+// finds more than one distinct passport has found a clone.
+func (c *Client) AgentEntries(ctx context.Context, agentID string) ([]Entry, error) {
+	var resp struct {
+		Entries []Entry `json:"entries"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/agents/"+url.PathEscape(agentID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Entries, nil
+}
+
+// PrincipalEntries returns every logged passport naming humanID, for a
+// principal to compare with the agents it actually authorised.
+func (c *Client) PrincipalEntries(ctx context.Context, humanID string) ([]Entry, error) {
+	var resp struct {
+		Entries []Entry `json:"entries"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/principals/"+url.PathEscape(humanID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Entries, nil
+}
+
+// do sends body, if any, and decodes a 2xx response into out.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	u := strings.TrimRight(c.URL, "/") + path
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(data, &e)
+		if e.Error == "" {
+			e.Error = resp.Status
+		}
+		if resp.StatusCode == http.StatusBadRequest && method == http.MethodPost {
+			return fmt.Errorf("%w: %s", ErrInvalid, e.Error)
+		}
+		return fmt.Errorf("%s %s: %s", method, u, e.Error)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s %s: %w", method, u, err)
+	}
+	return nil
+}
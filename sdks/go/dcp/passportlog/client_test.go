@@ -0,0 +1,99 @@
+package passportlog_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/passportlog"
+)
+
+func TestClient(t *testing.T) {
+	srv, key := newLog(t, "")
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	c := &passportlog.Client{URL: ts.URL, PublicKeyB64: key}
+	ctx := context.Background()
+
+	p := signedPassport(t, "did:human:alice")
+	r, err := c.Submit(ctx, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Entry.Passport.AgentID != p.AgentID {
+		t.Fatalf("receipt for %s", r.Entry.Passport.AgentID)
+	}
+	if _, err := c.Submit(ctx, dcp.NewAgentPassport("did:human:alice", p.PublicKey, nil, dcp.RiskTierLow)); !errors.Is(err, passportlog.ErrInvalid) {
+		t.Fatalf("unsigned passport: %v", err)
+	}
+	if cp, err := c.Checkpoint(ctx); err != nil || cp.TreeSize != 1 {
+		t.Fatalf("Checkpoint = %+v, %v", cp, err)
+	}
+	for i := 0; i < 4; i++ {
+		if _, err := c.Submit(ctx, signedPassport(t, "did:human:bob")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cp, err := c.Checkpoint(ctx); err != nil || cp.TreeSize != 5 {
+		t.Fatalf("Checkpoint = %+v, %v", cp, err)
+	}
+	if r, err := c.Receipt(ctx, 0); err != nil || r.Checkpoint.TreeSize != 5 {
+		t.Fatalf("Receipt(0) = %+v, %v", r, err)
+	}
+	if entries, err := c.PrincipalEntries(ctx, "did:human:bob"); err != nil || len(entries) != 4 {
+		t.Fatalf("PrincipalEntries = %d, %v", len(entries), err)
+	}
+	if entries, err := c.AgentEntries(ctx, p.AgentID); err != nil || len(entries) != 1 {
+		t.Fatalf("AgentEntries = %d, %v", len(entries), err)
+	}
+
+	wrongKey := &passportlog.Client{URL: ts.URL, PublicKeyB64: p.PublicKey}
+	if _, err := wrongKey.Checkpoint(ctx); err == nil {
+		t.Fatal("checkpoint verified under the wrong key")
+	}
+}
+
+func TestClientDetectsRewrittenHistory(t *testing.T) {
+	signer, key := dcptest.NewSigner(t)
+	honest, err := passportlog.New(passportlog.Config{Signer: signer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The forked log is run with the same key but drops the first entry.
+	forked, err := passportlog.New(passportlog.Config{Signer: signer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var current http.Handler = honest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+	c := &passportlog.Client{URL: ts.URL, PublicKeyB64: key}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		p := signedPassport(t, "did:human:alice")
+		if _, _, err := honest.Submit(p); err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 {
+			if _, _, err := forked.Submit(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if _, err := c.Checkpoint(ctx); err != nil {
+		t.Fatal(err)
+	}
+	forked.Submit(signedPassport(t, "did:human:alice"))
+	forked.Submit(signedPassport(t, "did:human:alice"))
+	current = forked
+	if cp, err := c.Checkpoint(ctx); err == nil {
+		t.Fatalf("forked head %d accepted", cp.TreeSize)
+	}
+}
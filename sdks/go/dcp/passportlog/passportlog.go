@@ -0,0 +1,504 @@
+// Package passportlog is an append-only transparency log for agent
+// passports, in the manner of Certificate Transparency: every submitted
+// passport becomes a leaf of a Merkle tree whose heads the log signs, and
+// anyone can fetch inclusion proofs for entries and consistency proofs
+// between heads. Issuers log each passport they mint; principals and
+// monitors watch the log for passports naming them that they did not ask
+// for, or for a second passport claiming an agent_id.
+//
+// Endpoints:
+//
+//	POST /v1/entries               body: a signed AgentPassport; answers a Receipt
+//	GET  /v1/entries?start=&count= entries in index order
+//	GET  /v1/entries/{index}/proof a Receipt for the entry under the current head
+//	GET  /v1/checkpoint            the signed tree head, a dcp.Checkpoint
+//	GET  /v1/consistency?first=N   the head and a consistency proof from size N
+//	GET  /v1/agents/{agent_id}     every entry whose passport has this agent_id
+//	GET  /v1/principals/{human_id} every entry whose passport names this human_id
+//	GET  /health
+//
+// The tree is the duplicate-last-node tree used throughout DCP (see
+// dcp.MerkleTree), and a leaf is the hash of the canonical Entry. The log
+// accepts any passport whose self-signature verifies: it records what was
+// issued and leaves judging it to the people watching.
+package passportlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// DefaultOrigin is the checkpoint origin of a log whose Config has none.
+const DefaultOrigin = "dcp-passport-log"
+
+// maxPage caps the entries answered by one GET /v1/entries.
+const maxPage = 1000
+
+// ErrInvalid reports a submission that is not a valid, self-signed passport.
+var ErrInvalid = errors.New("invalid passport")
+
+// Entry is one logged passport.
+type Entry struct {
+	Index    int64             `json:"index"`
+	LoggedAt string            `json:"logged_at"`
+	Passport dcp.AgentPassport `json:"passport"`
+}
+
+// LeafHash returns the entry's leaf hash: the hex SHA-256 of the canonical
+// entry.
+func (e *Entry) LeafHash() (string, error) {
+	return dcp.HashObject(e)
+}
+
+// Receipt proves that an entry is in the log: Proof is its inclusion proof
+// against the root of Checkpoint, which the log signed.
+type Receipt struct {
+	Entry      Entry                 `json:"entry"`
+	LeafHash   string                `json:"leaf_hash"`
+	Proof      []dcp.MerkleProofStep `json:"proof"`
+	Checkpoint *dcp.Checkpoint       `json:"checkpoint"`
+}
+
+// Verify checks the checkpoint's signature under the log's key and the
+// entry's inclusion under the checkpoint's root.
+func (r *Receipt) Verify(publicKeyB64 string) error {
+	if r.Checkpoint == nil {
+		return errors.New("receipt has no checkpoint")
+	}
+	if ok, err := r.Checkpoint.Verify(publicKeyB64); err != nil || !ok {
+		return errors.New("receipt checkpoint signature does not verify under the log key")
+	}
+	leaf, err := r.Entry.LeafHash()
+	if err != nil {
+		return err
+	}
+	if leaf != r.LeafHash {
+		return fmt.Errorf("receipt leaf_hash does not match entry %d", r.Entry.Index)
+	}
+	if r.Entry.Index < 0 || r.Entry.Index >= r.Checkpoint.TreeSize {
+		return fmt.Errorf("entry %d is outside the tree of size %d", r.Entry.Index, r.Checkpoint.TreeSize)
+	}
+	if !dcp.VerifyMerkleProof(leaf, r.Proof, r.Checkpoint.RootHash) {
+		return fmt.Errorf("entry %d is not included under the checkpoint root", r.Entry.Index)
+	}
+	return nil
+}
+
+// Config configures a Server.
+type Config struct {
+	// Signer signs tree heads. Required.
+	Signer dcp.BundleSigner
+	// Origin names the log in its checkpoints; empty means DefaultOrigin.
+	Origin string
+	// Path is the log file, one JSON Entry per line, appended and synced on
+	// every submission. Empty keeps the log in memory only.
+	Path string
+	// MaxBodyBytes caps request bodies; zero means 1 MiB.
+	MaxBodyBytes int64
+	// Now is the clock entries and heads are stamped with; nil means
+	// time.Now.
+	Now func() time.Time
+}
+
+// Server serves the log. Create one with New and Close it when done.
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+
+	mu         sync.RWMutex
+	file       *os.File
+	tree       *dcp.MerkleTree
+	entries    []Entry
+	byPassport map[string]int64
+	byAgent    map[string][]int64
+	byHuman    map[string][]int64
+	head       *dcp.Checkpoint
+}
+
+// New returns a Server for cfg, replaying cfg.Path if it exists. A torn
+// last line, left by a crash mid-append, is truncated away.
+func New(cfg Config) (*Server, error) {
+	if cfg.Signer == nil {
+		return nil, errors.New("passportlog: a tree head signer is required")
+	}
+	if cfg.Origin == "" {
+		cfg.Origin = DefaultOrigin
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 1 << 20
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	s := &Server{
+		cfg:        cfg,
+		mux:        http.NewServeMux(),
+		tree:       dcp.NewMerkleTree(),
+		byPassport: map[string]int64{},
+		byAgent:    map[string][]int64{},
+		byHuman:    map[string][]int64{},
+	}
+	if cfg.Path != "" {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.signHead(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.mux.HandleFunc("POST /v1/entries", s.handleSubmit)
+	s.mux.HandleFunc("GET /v1/entries", s.handleEntries)
+	s.mux.HandleFunc("GET /v1/entries/{index}/proof", s.handleProof)
+	s.mux.HandleFunc("GET /v1/checkpoint", s.handleCheckpoint)
+	s.mux.HandleFunc("GET /v1/consistency", s.handleConsistency)
+	s.mux.HandleFunc("GET /v1/agents/{agent_id}", s.handleAgent)
+	s.mux.HandleFunc("GET /v1/principals/{human_id}", s.handlePrincipal)
+	s.mux.HandleFunc("GET /health", s.handleHealth)
+	return s, nil
+}
+
+func (s *Server) load() error {
+	f, err := os.OpenFile(s.cfg.Path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("passportlog: %w", err)
+	}
+	s.file = f
+	r := bufio.NewReader(f)
+	var off int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				if err := f.Truncate(off); err != nil {
+					s.Close()
+					return fmt.Errorf("passportlog: truncate torn tail: %w", err)
+				}
+			}
+			break
+		}
+		if err != nil {
+			s.Close()
+			return fmt.Errorf("passportlog: %w", err)
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			s.Close()
+			return fmt.Errorf("passportlog: %s: entry %d: %w", s.cfg.Path, len(s.entries), err)
+		}
+		if e.Index != int64(len(s.entries)) {
+			s.Close()
+			return fmt.Errorf("passportlog: %s: entry %d has index %d", s.cfg.Path, len(s.entries), e.Index)
+		}
+		if err := s.index(e); err != nil {
+			s.Close()
+			return err
+		}
+		off += int64(len(line))
+	}
+	if _, err := f.Seek(off, io.SeekStart); err != nil {
+		s.Close()
+		return fmt.Errorf("passportlog: %w", err)
+	}
+	return nil
+}
+
+// index adds e to the tree and the lookup maps. The caller holds mu, or has
+// not shared s yet.
+func (s *Server) index(e Entry) error {
+	leaf, err := e.LeafHash()
+	if err != nil {
+		return fmt.Errorf("passportlog: entry %d: %w", e.Index, err)
+	}
+	ph, err := dcp.HashObject(e.Passport)
+	if err != nil {
+		return fmt.Errorf("passportlog: entry %d: %w", e.Index, err)
+	}
+	if err := s.tree.Append(leaf); err != nil {
+		return fmt.Errorf("passportlog: entry %d: %w", e.Index, err)
+	}
+	s.entries = append(s.entries, e)
+	if _, ok := s.byPassport[ph]; !ok {
+		s.byPassport[ph] = e.Index
+	}
+	s.byAgent[e.Passport.AgentID] = append(s.byAgent[e.Passport.AgentID], e.Index)
+	s.byHuman[e.Passport.PrincipalBindingReference] = append(s.byHuman[e.Passport.PrincipalBindingReference], e.Index)
+	return nil
+}
+
+// signHead replaces the published head with a freshly signed one for the
+// current tree. The caller holds mu, or has not shared s yet.
+func (s *Server) signHead() error {
+	cp := dcp.NewCheckpoint(s.cfg.Origin, s.tree, s.cfg.Now())
+	if err := cp.SignWith(s.cfg.Signer); err != nil {
+		return fmt.Errorf("passportlog: %w", err)
+	}
+	s.head = cp
+	return nil
+}
+
+// Close closes the log file.
+func (s *Server) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Close()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Submit logs p and returns its receipt. A passport already in the log is
+// not logged again: Submit returns the receipt of the existing entry and
+// false.
+func (s *Server) Submit(p dcp.AgentPassport) (*Receipt, bool, error) {
+	if err := p.Validate(); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if ok, err := p.VerifySignature(); err != nil || !ok {
+		return nil, false, fmt.Errorf("%w: agent_passport %s self-signature does not verify", ErrInvalid, p.AgentID)
+	}
+	ph, err := dcp.HashObject(p)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.byPassport[ph]; ok {
+		r, err := s.receipt(i)
+		return r, false, err
+	}
+	e := Entry{Index: int64(len(s.entries)), LoggedAt: dcp.FormatTime(s.cfg.Now()), Passport: p}
+	if s.file != nil {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return nil, false, fmt.Errorf("passportlog: %w", err)
+		}
+		if _, err := s.file.Write(append(line, '\n')); err != nil {
+			return nil, false, fmt.Errorf("passportlog: append: %w", err)
+		}
+		if err := s.file.Sync(); err != nil {
+			return nil, false, fmt.Errorf("passportlog: append: %w", err)
+		}
+	}
+	if err := s.index(e); err != nil {
+		return nil, false, err
+	}
+	if err := s.signHead(); err != nil {
+		return nil, false, err
+	}
+	r, err := s.receipt(e.Index)
+	return r, true, err
+}
+
+// Receipt returns the receipt of entry i under the current head.
+func (s *Server) Receipt(i int64) (*Receipt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i < 0 || i >= int64(len(s.entries)) {
+		return nil, fmt.Errorf("passportlog: no entry %d (%d entries)", i, len(s.entries))
+	}
+	return s.receipt(i)
+}
+
+// receipt builds the receipt of entry i. The caller holds mu.
+func (s *Server) receipt(i int64) (*Receipt, error) {
+	leaf, err := s.tree.Leaf(int(i))
+	if err != nil {
+		return nil, err
+	}
+	proof, err := s.tree.ProofAt(int(i))
+	if err != nil {
+		return nil, err
+	}
+	return &Receipt{Entry: s.entries[i], LeafHash: leaf, Proof: proof, Checkpoint: s.head}, nil
+}
+
+// Checkpoint returns the current signed tree head.
+func (s *Server) Checkpoint() *dcp.Checkpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.head
+}
+
+// Consistency returns the current head and the proof that the tree of size
+// first is a prefix of it.
+func (s *Server) Consistency(first int64) (*dcp.Checkpoint, []string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	proof, err := s.tree.ConsistencyProof(int(first))
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.head, proof, nil
+}
+
+// AgentEntries returns every entry whose passport has agentID. More than
+// one distinct passport for an agent_id is the mark of a cloned identity.
+func (s *Server) AgentEntries(agentID string) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.collect(s.byAgent[agentID])
+}
+
+// PrincipalEntries returns every entry whose passport names humanID.
+func (s *Server) PrincipalEntries(humanID string) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.collect(s.byHuman[humanID])
+}
+
+func (s *Server) collect(indexes []int64) []Entry {
+	out := make([]Entry, 0, len(indexes))
+	for _, i := range indexes {
+		out = append(out, s.entries[i])
+	}
+	return out
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	head := s.Checkpoint()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"ok":         true,
+		"service":    "dcp-passport-log",
+		"origin":     head.Origin,
+		"size":       head.TreeSize,
+		"signer_key": s.cfg.Signer.PublicKeyB64(),
+	})
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.cfg.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", s.cfg.MaxBodyBytes))
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var p dcp.AgentPassport
+	if err := json.Unmarshal(body, &p); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	receipt, added, err := s.Submit(p)
+	switch {
+	case errors.Is(err, ErrInvalid):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case err != nil:
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case added:
+		writeJSON(w, http.StatusCreated, receipt)
+	default:
+		writeJSON(w, http.StatusOK, receipt)
+	}
+}
+
+func (s *Server) handleEntries(w http.ResponseWriter, r *http.Request) {
+	start, err := queryInt(r, "start", 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	count, err := queryInt(r, "count", 100)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if count > maxPage {
+		count = maxPage
+	}
+	s.mu.RLock()
+	size := int64(len(s.entries))
+	end := start + count
+	if start > size {
+		start = size
+	}
+	if end > size {
+		end = size
+	}
+	page := append([]Entry{}, s.entries[start:end]...)
+	s.mu.RUnlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{"entries": page, "size": size})
+}
+
+func (s *Server) handleProof(w http.ResponseWriter, r *http.Request) {
+	i, err := strconv.ParseInt(r.PathValue("index"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "index must be an integer")
+		return
+	}
+	receipt, err := s.Receipt(i)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, receipt)
+}
+
+func (s *Server) handleCheckpoint(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Checkpoint())
+}
+
+func (s *Server) handleConsistency(w http.ResponseWriter, r *http.Request) {
+	first, err := queryInt(r, "first", -1)
+	if err != nil || first < 0 {
+		writeError(w, http.StatusBadRequest, "first must be a tree size")
+		return
+	}
+	head, proof, err := s.Consistency(first)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"first": first, "checkpoint": head, "proof": proof})
+}
+
+func (s *Server) handleAgent(w http.ResponseWriter, r *http.Request) {
+	agentID := r.PathValue("agent_id")
+	writeJSON(w, http.StatusOK, map[string]interface{}{"agent_id": agentID, "entries": s.AgentEntries(agentID)})
+}
+
+func (s *Server) handlePrincipal(w http.ResponseWriter, r *http.Request) {
+	humanID := r.PathValue("human_id")
+	writeJSON(w, http.StatusOK, map[string]interface{}{"human_id": humanID, "entries": s.PrincipalEntries(humanID)})
+}
+
+// queryInt parses the non-negative query parameter name, or returns def if
+// it is absent.
+func queryInt(r *http.Request, name string, def int64) (int64, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,206 @@
+package passportlog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/passportlog"
+)
+
+func signedPassport(t *testing.T, humanID string) dcp.AgentPassport {
+	t.Helper()
+	agent, _ := dcptest.NewSigner(t)
+	p := dcp.NewAgentPassport(humanID, "", []string{"browse"}, dcp.RiskTierLow)
+	if err := p.Sign(agent); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func newLog(t *testing.T, path string) (*passportlog.Server, string) {
+	t.Helper()
+	signer, key := dcptest.NewSigner(t)
+	srv, err := passportlog.New(passportlog.Config{Signer: signer, Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	return srv, key
+}
+
+func TestSubmitAndProve(t *testing.T) {
+	srv, key := newLog(t, "")
+	var receipts []*passportlog.Receipt
+	for i := 0; i < 5; i++ {
+		r, added, err := srv.Submit(signedPassport(t, "did:human:alice"))
+		if err != nil || !added {
+			t.Fatalf("submit %d: %v, %v", i, added, err)
+		}
+		if r.Entry.Index != int64(i) || r.Checkpoint.TreeSize != int64(i+1) {
+			t.Fatalf("submit %d: index %d, tree size %d", i, r.Entry.Index, r.Checkpoint.TreeSize)
+		}
+		if err := r.Verify(key); err != nil {
+			t.Fatalf("receipt %d: %v", i, err)
+		}
+		receipts = append(receipts, r)
+	}
+	// Earlier entries are provable under the latest head.
+	r, err := srv.Receipt(1)
+	if err != nil || r.Checkpoint.TreeSize != 5 {
+		t.Fatalf("Receipt(1) = %+v, %v", r, err)
+	}
+	if err := r.Verify(key); err != nil {
+		t.Fatal(err)
+	}
+	// Resubmitting a passport answers its existing entry.
+	again, added, err := srv.Submit(receipts[2].Entry.Passport)
+	if err != nil || added || again.Entry.Index != 2 {
+		t.Fatalf("resubmit: %v, %v, %+v", added, err, again)
+	}
+
+	tampered := *r
+	tampered.Entry.Passport.Capabilities = []string{"payments"}
+	if err := tampered.Verify(key); err == nil {
+		t.Fatal("tampered entry verified")
+	}
+	if err := r.Verify(receipts[0].Entry.Passport.PublicKey); err == nil {
+		t.Fatal("receipt verified under the wrong key")
+	}
+
+	unsigned := dcp.NewAgentPassport("did:human:alice", receipts[0].Entry.Passport.PublicKey, nil, dcp.RiskTierLow)
+	if _, _, err := srv.Submit(unsigned); err == nil {
+		t.Fatal("unsigned passport logged")
+	}
+}
+
+func TestClonesAreVisible(t *testing.T) {
+	srv, _ := newLog(t, "")
+	original := signedPassport(t, "did:human:alice")
+	if _, _, err := srv.Submit(original); err != nil {
+		t.Fatal(err)
+	}
+	// Someone else mints a passport claiming the same agent_id.
+	mallory, _ := dcptest.NewSigner(t)
+	clone := dcp.NewAgentPassport("did:human:mallory", "", nil, dcp.RiskTierLow)
+	clone.AgentID = original.AgentID
+	if err := clone.Sign(mallory); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := srv.Submit(clone); err != nil {
+		t.Fatal(err)
+	}
+	if entries := srv.AgentEntries(original.AgentID); len(entries) != 2 {
+		t.Fatalf("agent entries = %d, want 2", len(entries))
+	}
+	if entries := srv.PrincipalEntries("did:human:alice"); len(entries) != 1 || entries[0].Passport.Signature != original.Signature {
+		t.Fatalf("principal entries = %+v", entries)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	srv, key := newLog(t, "")
+	for i := 0; i < 3; i++ {
+		if _, _, err := srv.Submit(signedPassport(t, "did:human:alice")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	get := func(target string) (int, []byte) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec.Code, rec.Body.Bytes()
+	}
+
+	code, body := get("/v1/consistency?first=1")
+	var cons struct {
+		Checkpoint dcp.Checkpoint `json:"checkpoint"`
+		Proof      []string       `json:"proof"`
+	}
+	if err := json.Unmarshal(body, &cons); code != http.StatusOK || err != nil {
+		t.Fatalf("consistency: %d %s", code, body)
+	}
+	if ok, _ := cons.Checkpoint.Verify(key); !ok {
+		t.Fatal("checkpoint does not verify")
+	}
+	r0, _ := srv.Receipt(0)
+	first, _ := dcp.NewMerkleTreeFromHexLeaves([]string{r0.LeafHash})
+	at, _ := dcp.ParseTime(cons.Checkpoint.Timestamp)
+	old := dcp.NewCheckpoint(cons.Checkpoint.Origin, first, at)
+	if err := dcp.VerifyCheckpointContinuity(old, &cons.Checkpoint, cons.Proof); err != nil {
+		t.Fatal(err)
+	}
+
+	code, body = get("/v1/entries?start=1&count=5")
+	var page struct {
+		Entries []passportlog.Entry `json:"entries"`
+		Size    int64               `json:"size"`
+	}
+	if err := json.Unmarshal(body, &page); code != http.StatusOK || err != nil || len(page.Entries) != 2 || page.Size != 3 {
+		t.Fatalf("entries: %d %s", code, body)
+	}
+	for target, want := range map[string]int{
+		"/v1/entries/2/proof":     http.StatusOK,
+		"/v1/entries/3/proof":     http.StatusNotFound,
+		"/v1/entries/x/proof":     http.StatusBadRequest,
+		"/v1/consistency":         http.StatusBadRequest,
+		"/v1/consistency?first=4": http.StatusBadRequest,
+		"/v1/entries?count=-1":    http.StatusBadRequest,
+		"/health":                 http.StatusOK,
+	} {
+		if code, body := get(target); code != want {
+			t.Errorf("GET %s = %d %s, want %d", target, code, body, want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/entries", bytes.NewReader([]byte(`{"agent_id": "x"}`))))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid passport: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passports.log")
+	srv, _ := newLog(t, path)
+	var last *passportlog.Receipt
+	for i := 0; i < 3; i++ {
+		r, _, err := srv.Submit(signedPassport(t, "did:human:alice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		last = r
+	}
+	srv.Close()
+
+	// A crash mid-append leaves a torn line, which is dropped on reopening.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"index": 3, "passp`)
+	f.Close()
+
+	reopened, key := newLog(t, path)
+	head := reopened.Checkpoint()
+	if head.TreeSize != 3 || head.RootHash != last.Checkpoint.RootHash {
+		t.Fatalf("reopened head = %+v, want root %s", head, last.Checkpoint.RootHash)
+	}
+	r, added, err := reopened.Submit(signedPassport(t, "did:human:bob"))
+	if err != nil || !added || r.Entry.Index != 3 {
+		t.Fatalf("submit after reopen: %v, %v, %+v", added, err, r)
+	}
+	if err := r.Verify(key); err != nil {
+		t.Fatal(err)
+	}
+	reopened.Close()
+	again, _ := newLog(t, path)
+	if again.Checkpoint().TreeSize != 4 {
+		t.Fatalf("tree size after second reopen = %d", again.Checkpoint().TreeSize)
+	}
+}
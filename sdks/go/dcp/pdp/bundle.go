@@ -0,0 +1,133 @@
+package pdp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// PolicyBundle is a policy set published as a signed, versioned document,
+// so every decision can be traced to the exact policy that made it, who
+// issued that policy and when it was in force.
+type PolicyBundle struct {
+	DCPVersion string `json:"dcp_version"`
+	// Version names this revision of the policy, such as 2026.03.1. It is
+	// recorded in every decision made under it.
+	Version string `json:"version"`
+	// EffectiveFrom and EffectiveUntil bound when the policy decides
+	// intents; an empty EffectiveUntil means until replaced.
+	EffectiveFrom  string    `json:"effective_from"`
+	EffectiveUntil string    `json:"effective_until,omitempty"`
+	Policy         PolicySet `json:"policy"`
+	// IssuerKey is the public key of the policy's issuer, set by Sign.
+	IssuerKey string `json:"issuer_key,omitempty"`
+	// Signature is by IssuerKey over the canonical bundle with an empty
+	// signature.
+	Signature string `json:"signature,omitempty"`
+}
+
+// ReadPolicyBundle reads a policy bundle file and verifies that it is
+// signed by one of trustedKeys.
+func ReadPolicyBundle(path string, trustedKeys []string) (*PolicyBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b PolicyBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("policy bundle %s: %w", path, err)
+	}
+	if err := b.Verify(trustedKeys); err != nil {
+		return nil, fmt.Errorf("policy bundle %s: %w", path, err)
+	}
+	return &b, nil
+}
+
+// Validate checks the version, the effective dates and the policy set.
+func (b *PolicyBundle) Validate() error {
+	if b.DCPVersion != dcp.DCPVersion {
+		return fmt.Errorf("dcp_version %q is not %s", b.DCPVersion, dcp.DCPVersion)
+	}
+	if b.Version == "" {
+		return errors.New("version is required")
+	}
+	from, err := dcp.ParseTime(b.EffectiveFrom)
+	if err != nil {
+		return fmt.Errorf("effective_from: %w", err)
+	}
+	if b.EffectiveUntil != "" {
+		until, err := dcp.ParseTime(b.EffectiveUntil)
+		if err != nil {
+			return fmt.Errorf("effective_until: %w", err)
+		}
+		if !until.After(from) {
+			return errors.New("effective_until is not after effective_from")
+		}
+	}
+	if err := b.Policy.Validate(); err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+	return nil
+}
+
+// Sign sets IssuerKey and Signature with the issuer's key.
+func (b *PolicyBundle) Sign(signer dcp.BundleSigner) error {
+	if err := b.Validate(); err != nil {
+		return fmt.Errorf("policy bundle %s: %w", b.Version, err)
+	}
+	b.IssuerKey, b.Signature = signer.PublicKeyB64(), ""
+	canon, err := dcp.Canonicalize(b)
+	if err != nil {
+		return fmt.Errorf("sign policy bundle %s: %w", b.Version, err)
+	}
+	if b.Signature, err = signer.SignCanonical(canon); err != nil {
+		return fmt.Errorf("sign policy bundle %s: %w", b.Version, err)
+	}
+	return nil
+}
+
+// Verify checks that the bundle is valid and signed by IssuerKey, which
+// must be one of trustedKeys.
+func (b *PolicyBundle) Verify(trustedKeys []string) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	if b.Signature == "" {
+		return fmt.Errorf("version %s is not signed", b.Version)
+	}
+	trusted := false
+	for _, k := range trustedKeys {
+		trusted = trusted || k == b.IssuerKey
+	}
+	if !trusted {
+		return fmt.Errorf("version %s: issuer key %s is not trusted", b.Version, b.IssuerKey)
+	}
+	unsigned := *b
+	unsigned.Signature = ""
+	if ok, err := dcp.VerifyObject(unsigned, b.Signature, b.IssuerKey); err != nil || !ok {
+		return fmt.Errorf("version %s: signature does not verify", b.Version)
+	}
+	return nil
+}
+
+// InEffect returns an error unless the policy decides intents at now.
+func (b *PolicyBundle) InEffect(now time.Time) error {
+	if from, err := dcp.ParseTime(b.EffectiveFrom); err != nil {
+		return fmt.Errorf("policy version %s: effective_from: %w", b.Version, err)
+	} else if now.Before(from) {
+		return fmt.Errorf("policy version %s is not in effect until %s", b.Version, b.EffectiveFrom)
+	}
+	if b.EffectiveUntil == "" {
+		return nil
+	}
+	if until, err := dcp.ParseTime(b.EffectiveUntil); err != nil {
+		return fmt.Errorf("policy version %s: effective_until: %w", b.Version, err)
+	} else if !now.Before(until) {
+		return fmt.Errorf("policy version %s expired at %s", b.Version, b.EffectiveUntil)
+	}
+	return nil
+}
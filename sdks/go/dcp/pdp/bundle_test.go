@@ -0,0 +1,185 @@
+package pdp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+func testBundle(t *testing.T) (*pdp.PolicyBundle, *dcp.Keypair) {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &pdp.PolicyBundle{
+		DCPVersion:     dcp.DCPVersion,
+		Version:        "2026.03.1",
+		EffectiveFrom:  "2026-03-01T00:00:00Z",
+		EffectiveUntil: "2026-04-01T00:00:00Z",
+		Policy: pdp.PolicySet{Default: dcp.DecisionBlock, Rules: []pdp.Rule{
+			{Name: "email", Channels: []dcp.Channel{dcp.ChannelEmail}, Decision: dcp.DecisionApprove},
+		}},
+	}
+	if err := b.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	return b, kp
+}
+
+func TestPolicyBundleVerify(t *testing.T) {
+	b, kp := testBundle(t)
+	if b.IssuerKey != kp.PublicKeyB64 {
+		t.Fatalf("issuer_key = %s", b.IssuerKey)
+	}
+	if err := b.Verify([]string{kp.PublicKeyB64}); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := dcp.GenerateKeypair()
+	if err := b.Verify([]string{other.PublicKeyB64}); err == nil || !strings.Contains(err.Error(), "not trusted") {
+		t.Fatalf("untrusted issuer: %v", err)
+	}
+
+	tampered := *b
+	tampered.Policy.Default = dcp.DecisionApprove
+	if err := tampered.Verify([]string{kp.PublicKeyB64}); err == nil || !strings.Contains(err.Error(), "does not verify") {
+		t.Fatalf("tampered policy: %v", err)
+	}
+	tampered = *b
+	tampered.Version = "2026.03.2"
+	if err := tampered.Verify([]string{kp.PublicKeyB64}); err == nil {
+		t.Fatal("changed version verified")
+	}
+	tampered = *b
+	tampered.Signature = ""
+	if err := tampered.Verify([]string{kp.PublicKeyB64}); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Fatalf("unsigned: %v", err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy-bundle.json")
+	data, _ := json.Marshal(b)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	read, err := pdp.ReadPolicyBundle(path, []string{kp.PublicKeyB64})
+	if err != nil || read.Version != b.Version {
+		t.Fatalf("ReadPolicyBundle: %+v, %v", read, err)
+	}
+	if _, err := pdp.ReadPolicyBundle(path, nil); err == nil {
+		t.Fatal("bundle read without trusted keys")
+	}
+}
+
+func TestPolicyBundleValidate(t *testing.T) {
+	for name, mutate := range map[string]func(*pdp.PolicyBundle){
+		"dcp_version":     func(b *pdp.PolicyBundle) { b.DCPVersion = "0.9" },
+		"version":         func(b *pdp.PolicyBundle) { b.Version = "" },
+		"effective_from":  func(b *pdp.PolicyBundle) { b.EffectiveFrom = "March" },
+		"effective_until": func(b *pdp.PolicyBundle) { b.EffectiveUntil = "2026-02-01T00:00:00Z" },
+		"policy":          func(b *pdp.PolicyBundle) { b.Policy.Default = "maybe" },
+	} {
+		b, _ := testBundle(t)
+		mutate(b)
+		if err := b.Validate(); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestPolicyBundleInEffect(t *testing.T) {
+	b, _ := testBundle(t)
+	for _, tc := range []struct {
+		now  time.Time
+		want string
+	}{
+		{time.Date(2026, 2, 28, 23, 59, 0, 0, time.UTC), "not in effect until"},
+		{time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), ""},
+		{time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC), ""},
+		{time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), "expired"},
+	} {
+		err := b.InEffect(tc.now)
+		if (tc.want == "" && err != nil) || (tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want))) {
+			t.Errorf("%s: %v", tc.now, err)
+		}
+	}
+	b.EffectiveUntil = ""
+	if err := b.InEffect(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("open-ended bundle: %v", err)
+	}
+}
+
+func TestServerPolicyBundle(t *testing.T) {
+	b, _ := testBundle(t)
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)
+	srv, err := pdp.New(pdp.Config{Bundle: b, Signer: signer, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(readIntent(t), &intent); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loaded ahead of its effective date, the bundle does not decide yet.
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/decide", bytes.NewReader(readIntent(t))))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "not in effect") {
+		t.Fatalf("before effective_from: %d %s", rec.Code, rec.Body)
+	}
+
+	now = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	d, err := srv.Decide(context.Background(), &intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.PolicyVersion != "2026.03.1" || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("decision: %+v", d)
+	}
+	if err := d.Verify(kp.PublicKeyB64, &intent); err != nil {
+		t.Fatal(err)
+	}
+	d.PolicyVersion = "2026.02.9"
+	if err := d.Verify(kp.PublicKeyB64, &intent); err == nil {
+		t.Fatal("decision verified with another policy version")
+	}
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/policy", nil))
+	var res struct {
+		PolicyBundle *pdp.PolicyBundle `json:"policy_bundle"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil || res.PolicyBundle == nil || res.PolicyBundle.Verify([]string{b.IssuerKey}) != nil {
+		t.Fatalf("GET /v1/policy: %+v, %v", res.PolicyBundle, err)
+	}
+
+	now = time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := srv.Decide(context.Background(), &intent); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("after effective_until: %v", err)
+	}
+	if _, err := pdp.New(pdp.Config{Bundle: b, Signer: signer, Now: func() time.Time { return now }}); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("expired bundle loaded: %v", err)
+	}
+	if _, err := pdp.New(pdp.Config{Bundle: b, Policy: &b.Policy, Signer: signer}); err == nil {
+		t.Fatal("a policy set and a bundle accepted together")
+	}
+}
@@ -0,0 +1,155 @@
+package pdp
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/observability"
+)
+
+// DecisionCache remembers decisions by agent and intent shape, so an agent
+// repeating the same kind of intent is not looked up and evaluated again
+// each time. The shape is everything a policy decides on: the agent and
+// principal, the action type, the target, the data classes, the estimated
+// impact, whether consent is required and the intent's domain list; the
+// intent's ID and timestamp are not part of it. Create one with
+// NewDecisionCache; it is safe for concurrent use.
+//
+// A cached decision is answered until its TTL passes, even where the
+// passport, principal record, risk factors or time windows it was made
+// under would decide otherwise; choose the TTL accordingly, and
+// Invalidate an agent whose records change. Revocations are checked and
+// rate limits counted for every intent.
+type DecisionCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*cached
+	hits    uint64
+	misses  uint64
+}
+
+type cached struct {
+	agentID  string
+	decision dcp.PolicyDecision
+	expires  time.Time
+}
+
+// CacheStats reports a cache's use since it was created.
+type CacheStats struct {
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+	Entries int     `json:"entries"`
+}
+
+// NewDecisionCache returns a cache keeping decisions for ttl, at most
+// maxEntries at a time; zero means 10000. When full, the decision closest
+// to expiry makes room.
+func NewDecisionCache(ttl time.Duration, maxEntries int) *DecisionCache {
+	if maxEntries <= 0 {
+		maxEntries = 10000
+	}
+	return &DecisionCache{ttl: ttl, maxEntries: maxEntries, entries: map[string]*cached{}}
+}
+
+// get returns the decision cached under key at now, if any.
+func (c *DecisionCache) get(key string, now time.Time) (dcp.PolicyDecision, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.entries[key]
+	if e == nil || !now.Before(e.expires) {
+		c.misses++
+		observability.Default().RecordCacheMiss()
+		return dcp.PolicyDecision{}, false
+	}
+	c.hits++
+	observability.Default().RecordCacheHit()
+	d := e.decision
+	d.Reasons = append([]string(nil), d.Reasons...)
+	return d, true
+}
+
+// put caches d under key from now.
+func (c *DecisionCache) put(key, agentID string, d dcp.PolicyDecision, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		var soonest string
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			} else if soonest == "" || e.expires.Before(c.entries[soonest].expires) {
+				soonest = k
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			delete(c.entries, soonest)
+		}
+	}
+	d.Reasons = append([]string(nil), d.Reasons...)
+	c.entries[key] = &cached{agentID: agentID, decision: d, expires: now.Add(c.ttl)}
+}
+
+// Invalidate drops the decisions cached for agentID, such as after its
+// passport or principal record changed.
+func (c *DecisionCache) Invalidate(agentID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if e.agentID == agentID {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// Purge drops every cached decision, such as after the policy or the
+// risk factors changed. Decisions are also cached per policy hash, so a
+// cache shared by servers under different policies never mixes them.
+func (c *DecisionCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]*cached{}
+}
+
+// Stats returns the cache's hits, misses and size.
+func (c *DecisionCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := CacheStats{Hits: c.hits, Misses: c.misses, Entries: len(c.entries)}
+	if total := c.hits + c.misses; total > 0 {
+		s.HitRate = float64(c.hits) / float64(total)
+	}
+	return s
+}
+
+// cacheKey is the key of intent's decisions under the policy of
+// policyHash.
+func cacheKey(policyHash string, intent *dcp.Intent) (string, error) {
+	classes := append([]string(nil), intent.DataClasses...)
+	sort.Strings(classes)
+	target := intent.Target
+	if target.Domain != nil {
+		domain := strings.ToLower(*target.Domain)
+		target.Domain = &domain
+	}
+	h, err := dcp.HashObject(map[string]interface{}{
+		"agent_id":         intent.AgentID,
+		"human_id":         intent.HumanID,
+		"action_type":      intent.ActionType,
+		"target":           target,
+		"data_classes":     classes,
+		"estimated_impact": intent.EstimatedImpact,
+		"requires_consent": intent.RequiresConsent,
+		"domains":          intent.Domains,
+		"batch":            intent.Batch,
+	})
+	if err != nil {
+		return "", err
+	}
+	return policyHash + ":" + h, nil
+}
@@ -0,0 +1,186 @@
+package pdp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+// countingPassports counts lookups and answers with tier.
+type countingPassports struct {
+	lookups int32
+	tier    dcp.RiskTier
+}
+
+func (c *countingPassports) Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error) {
+	atomic.AddInt32(&c.lookups, 1)
+	return &dcp.AgentPassport{AgentID: agentID, RiskTier: c.tier, Capabilities: []string{"email"}}, nil
+}
+
+func TestDecisionCache(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(readIntent(t), &intent); err != nil {
+		t.Fatal(err)
+	}
+	passports := &countingPassports{tier: dcp.RiskTierLow}
+	policy := &pdp.PolicySet{Rules: []pdp.Rule{
+		{Name: "low tier", RiskTiers: []dcp.RiskTier{dcp.RiskTierLow}, Decision: dcp.DecisionApprove},
+		{Name: "high tier", RiskTiers: []dcp.RiskTier{dcp.RiskTierHigh}, Decision: dcp.DecisionBlock},
+	}}
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	cache := pdp.NewDecisionCache(time.Minute, 0)
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Passports: passports, Cache: cache, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	decide := func(intent *dcp.Intent) dcp.Decision {
+		t.Helper()
+		d, err := srv.Decide(ctx, intent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Verify(kp.PublicKeyB64, intent); err != nil {
+			t.Fatal(err)
+		}
+		return d.PolicyDecision.Decision
+	}
+
+	// A new intent of the same shape is answered from the cache, and the
+	// decision is signed for it.
+	decide(&intent)
+	again := intent
+	again.IntentID = "intent:again"
+	again.Timestamp = "2026-03-04T10:00:30Z"
+	again.DataClasses = append([]string(nil), intent.DataClasses...)
+	if d := decide(&again); d != dcp.DecisionApprove || passports.lookups != 1 {
+		t.Fatalf("cached: %s after %d lookups", d, passports.lookups)
+	}
+	// Another shape is not.
+	other := intent
+	other.EstimatedImpact = dcp.ImpactHigh
+	decide(&other)
+	if passports.lookups != 2 {
+		t.Fatalf("other shape: %d lookups", passports.lookups)
+	}
+	// Nor is a batch of the same intent, or a batch of another size.
+	batch := intent
+	batch.Batch = &dcp.IntentBatch{Count: 50, ManifestHash: "sha256:" + strings.Repeat("0", 64)}
+	decide(&batch)
+	bigger := batch
+	bigger.Batch = &dcp.IntentBatch{Count: 5000, ManifestHash: batch.Batch.ManifestHash}
+	decide(&bigger)
+	if passports.lookups != 4 {
+		t.Fatalf("batches: %d lookups", passports.lookups)
+	}
+
+	// The passport changes; the cached decision stands until invalidated.
+	passports.tier = dcp.RiskTierHigh
+	if d := decide(&intent); d != dcp.DecisionApprove {
+		t.Fatalf("before Invalidate: %s", d)
+	}
+	cache.Invalidate(intent.AgentID)
+	if d := decide(&intent); d != dcp.DecisionBlock {
+		t.Fatalf("after Invalidate: %s", d)
+	}
+	// Decisions expire after the TTL.
+	passports.tier = dcp.RiskTierLow
+	now = now.Add(time.Minute)
+	if d := decide(&intent); d != dcp.DecisionApprove {
+		t.Fatalf("after the TTL: %s", d)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 6 || stats.HitRate != 2.0/8 || stats.Entries != 1 {
+		t.Fatalf("stats: %+v", stats)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health struct {
+		Cache *pdp.CacheStats `json:"decision_cache"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&health); err != nil || health.Cache == nil || health.Cache.Hits != 2 {
+		t.Fatalf("health: %+v, %v", health.Cache, err)
+	}
+	cache.Purge()
+	if cache.Stats().Entries != 0 {
+		t.Fatal("entries after Purge")
+	}
+
+	// An expired intent is blocked though its shape has a cached approval.
+	decide(&intent)
+	expired := intent
+	expires := dcp.FormatTime(now.Add(-time.Second))
+	expired.ExpiresAt = &expires
+	if d := decide(&expired); d != dcp.DecisionBlock {
+		t.Fatalf("expired intent: %s", d)
+	}
+}
+
+func TestDecisionCacheRevocationAndLimits(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(readIntent(t), &intent); err != nil {
+		t.Fatal(err)
+	}
+	revocations := &dcp.RevocationList{}
+	policy := &pdp.PolicySet{
+		Rules:      []pdp.Rule{{Name: "all", Decision: dcp.DecisionApprove}},
+		RateLimits: []pdp.RateLimit{{Name: "burst", Limit: 2, Window: "1h"}},
+	}
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Revocations: []dcp.RevocationChecker{revocations},
+		Cache: pdp.NewDecisionCache(time.Hour, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	// Cached decisions still count against rate limits.
+	for i, want := range []dcp.Decision{dcp.DecisionApprove, dcp.DecisionApprove, dcp.DecisionBlock} {
+		if d, err := srv.Decide(ctx, &intent); err != nil || d.PolicyDecision.Decision != want {
+			t.Fatalf("intent %d: %+v, %v", i, d, err)
+		}
+	}
+	// And a revoked agent is never answered from the cache.
+	srv, _ = pdp.New(pdp.Config{Policy: &pdp.PolicySet{Default: dcp.DecisionApprove}, Signer: signer,
+		Revocations: []dcp.RevocationChecker{revocations}, Cache: pdp.NewDecisionCache(time.Hour, 0)})
+	srv.Decide(ctx, &intent)
+	revocations.Add(dcp.RevocationRecord{AgentID: intent.AgentID, Timestamp: "2026-03-04T10:00:00Z"})
+	if d, err := srv.Decide(ctx, &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionBlock {
+		t.Fatalf("revoked: %+v, %v", d, err)
+	}
+}
+
+func TestDecisionCacheEviction(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, _ := dcp.NewKeySigner(kp.SecretKeyB64)
+	cache := pdp.NewDecisionCache(time.Hour, 2)
+	srv, err := pdp.New(pdp.Config{Policy: &pdp.PolicySet{Default: dcp.DecisionApprove}, Signer: signer, Cache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range []string{"send_email", "api_call", "execute_code"} {
+		if _, err := srv.Decide(context.Background(), testIntent(action, dcp.ChannelAPI, "", dcp.ImpactLow, "none")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := cache.Stats().Entries; n != 2 {
+		t.Fatalf("entries: %d", n)
+	}
+}
@@ -0,0 +1,137 @@
+package pdp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// celCostLimit bounds the work of one condition, so a policy cannot make
+// the PDP loop over a large list for every intent.
+const celCostLimit = 10000
+
+var (
+	celEnvOnce sync.Once
+	celEnv     *cel.Env
+	celEnvErr  error
+	// celPrograms caches compiled conditions by expression; programs are
+	// safe for concurrent use.
+	celPrograms sync.Map
+)
+
+// conditionEnv returns the environment conditions are compiled in. The
+// records are bound in their JSON form, so fields have their JSON names:
+// intent.target.domain, passport.risk_tier, principal.jurisdiction. A
+// record that was not supplied is an empty map. The string method within
+// places a jurisdiction as principal_jurisdictions does:
+// principal.jurisdiction.within("EU").
+func conditionEnv() (*cel.Env, error) {
+	celEnvOnce.Do(func() {
+		record := cel.MapType(cel.StringType, cel.DynType)
+		celEnv, celEnvErr = cel.NewEnv(
+			cel.Variable("intent", record),
+			cel.Variable("passport", record),
+			cel.Variable("principal", record),
+			cel.Variable("now", cel.TimestampType),
+			cel.Function("within", cel.MemberOverload("string_within_string",
+				[]*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(func(j, code ref.Val) ref.Val {
+					return types.Bool(inJurisdiction([]string{fmt.Sprint(code.Value())}, fmt.Sprint(j.Value())))
+				}))),
+		)
+	})
+	return celEnv, celEnvErr
+}
+
+// compileCondition compiles a CEL condition, which must be boolean.
+func compileCondition(expr string) (cel.Program, error) {
+	if p, ok := celPrograms.Load(expr); ok {
+		return p.(cel.Program), nil
+	}
+	env, err := conditionEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("condition is %s, not bool", ast.OutputType())
+	}
+	prg, err := env.Program(ast, cel.CostLimit(celCostLimit))
+	if err != nil {
+		return nil, err
+	}
+	celPrograms.Store(expr, prg)
+	return prg, nil
+}
+
+// evalCondition evaluates the condition expr for in.
+func evalCondition(expr string, in *Input) (bool, error) {
+	prg, err := compileCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	vars := map[string]interface{}{"now": in.Now}
+	for name, record := range map[string]interface{}{"intent": in.Intent, "passport": in.Passport, "principal": in.Principal} {
+		if vars[name], err = recordMap(record); err != nil {
+			return false, err
+		}
+	}
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("condition evaluated to %v, not a bool", out.Value())
+	}
+	return b, nil
+}
+
+// recordMap returns the JSON form of record, or an empty map for a nil one.
+func recordMap(record interface{}) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	switch r := record.(type) {
+	case *dcp.Intent:
+		if r == nil {
+			return m, nil
+		}
+	case *dcp.AgentPassport:
+		if r == nil {
+			return m, nil
+		}
+	case *dcp.ResponsiblePrincipalRecord:
+		if r == nil {
+			return m, nil
+		}
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return m, json.Unmarshal(data, &m)
+}
+
+// Input is what a policy set evaluates: an intent and, when known, the
+// passport of its agent and the record of its principal, at a time.
+type Input struct {
+	Intent    *dcp.Intent
+	Passport  *dcp.AgentPassport
+	Principal *dcp.ResponsiblePrincipalRecord
+	// Now is the time of evaluation; zero means time.Now.
+	Now time.Time
+	// Risk, if set, is the intent's assessment by a RiskScorer, which a
+	// policy set starts from instead of the score of the estimated impact.
+	Risk *RiskAssessment
+	// TargetCountry is the country of the target's domain by a
+	// GeoResolver; empty means that of its country-code top-level domain.
+	TargetCountry string
+}
@@ -0,0 +1,124 @@
+package pdp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// validJurisdiction reports whether j may appear in a rule's jurisdiction
+// lists: a normalized dcp.Jurisdiction code, such as "DE", "US-CA" or "EU".
+func validJurisdiction(j string) bool {
+	code, err := dcp.NormalizeJurisdiction(j)
+	return err == nil && code == j
+}
+
+// inJurisdiction reports whether jurisdiction, such as "DE" or "US-CA", is
+// within one of list: its country, itself or a union holding its country.
+// An invalid jurisdiction is within none.
+func inJurisdiction(list []string, jurisdiction string) bool {
+	j, err := dcp.ParseJurisdiction(jurisdiction)
+	if err != nil {
+		return false
+	}
+	for _, code := range list {
+		if j.Within(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// GeoResolver finds the country of an intent's target domain, so rules can
+// restrict where data may go. CountryCodeTLD and DomainCountries are
+// provided; one backed by a geolocation or WHOIS service implements the
+// interface.
+type GeoResolver interface {
+	// Country returns the ISO 3166-1 alpha-2 code of the country domain is
+	// in, or "" if it is not known.
+	Country(ctx context.Context, domain string) (string, error)
+}
+
+// CountryCodeTLD places a domain in the country of its country-code
+// top-level domain, such as DE for example.de and GB for example.co.uk.
+// Other domains, such as example.com, are not placed.
+type CountryCodeTLD struct{}
+
+func (CountryCodeTLD) Country(ctx context.Context, domain string) (string, error) {
+	return tldCountry(domain), nil
+}
+
+func tldCountry(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	switch {
+	case len(tld) != 2 || tld == "eu":
+		return ""
+	case tld == "uk":
+		return "GB"
+	}
+	return strings.ToUpper(tld)
+}
+
+// DomainCountries places domains by a list, such as the hosting locations
+// of an organization's vendors.
+type DomainCountries struct {
+	// Domains maps domains, or *.example.com for subdomains, to country
+	// codes; an exact domain wins over a pattern.
+	Domains map[string]string
+	// Fallback, if set, places the domains not in the list.
+	Fallback GeoResolver
+}
+
+func (d *DomainCountries) Country(ctx context.Context, domain string) (string, error) {
+	domain = strings.ToLower(domain)
+	if c, ok := d.Domains[domain]; ok {
+		return strings.ToUpper(c), nil
+	}
+	// The longest matching pattern is the most specific.
+	best := ""
+	for pattern := range d.Domains {
+		if strings.HasPrefix(pattern, "*.") && matchDomain([]string{pattern}, domain) && len(pattern) > len(best) {
+			best = pattern
+		}
+	}
+	if best != "" {
+		return strings.ToUpper(d.Domains[best]), nil
+	}
+	if d.Fallback != nil {
+		return d.Fallback.Country(ctx, domain)
+	}
+	return "", nil
+}
+
+// targetCountry places the target of in: in.TargetCountry if set, the
+// country of its country-code top-level domain otherwise. An intent
+// without a target domain has no country and reports false.
+func targetCountry(in *Input) (string, bool) {
+	domain := in.Intent.Target.Host()
+	if domain == "" {
+		return "", false
+	}
+	if in.TargetCountry != "" {
+		return strings.ToUpper(in.TargetCountry), true
+	}
+	return tldCountry(domain), true
+}
+
+// validateJurisdictions checks a rule's jurisdiction lists.
+func (r *Rule) validateJurisdictions() error {
+	for name, list := range map[string][]string{
+		"principal_jurisdictions": r.PrincipalJurisdictions,
+		"target_countries":        r.TargetCountries,
+		"target_outside":          r.TargetOutside,
+	} {
+		for _, j := range list {
+			if !validJurisdiction(j) || (name != "principal_jurisdictions" && strings.Contains(j, "-")) {
+				return fmt.Errorf("%s: %q is not a valid jurisdiction", name, j)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package pdp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+func TestGeoResolvers(t *testing.T) {
+	ctx := context.Background()
+	for domain, want := range map[string]string{
+		"example.de":         "DE",
+		"shop.example.co.uk": "GB",
+		"Example.FR.":        "FR",
+		"example.com":        "",
+		"europa.eu":          "",
+	} {
+		if got, _ := (pdp.CountryCodeTLD{}).Country(ctx, domain); got != want {
+			t.Errorf("CountryCodeTLD %s = %q; want %q", domain, got, want)
+		}
+	}
+
+	list := &pdp.DomainCountries{
+		Domains:  map[string]string{"*.example.com": "us", "*.eu.example.com": "IE", "crm.example.com": "de"},
+		Fallback: pdp.CountryCodeTLD{},
+	}
+	for domain, want := range map[string]string{
+		"api.example.com":    "US",
+		"api.eu.example.com": "IE",
+		"CRM.example.com":    "DE",
+		"example.nl":         "NL",
+		"example.org":        "",
+	} {
+		if got, _ := list.Country(ctx, domain); got != want {
+			t.Errorf("DomainCountries %s = %q; want %q", domain, got, want)
+		}
+	}
+}
+
+func TestEvaluateJurisdictions(t *testing.T) {
+	// EU-bound humans' agents may not send health data outside the EEA.
+	ps := &pdp.PolicySet{Default: dcp.DecisionApprove, Rules: []pdp.Rule{{
+		Name:                   "health data stays in the EEA",
+		PrincipalJurisdictions: []string{"EU"},
+		DataClasses:            []string{"health_data"},
+		TargetOutside:          []string{"EEA"},
+		Decision:               dcp.DecisionBlock,
+	}}}
+	if err := ps.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	eu := &dcp.ResponsiblePrincipalRecord{HumanID: "did:human:alice123", Jurisdiction: "DE-BY"}
+	us := &dcp.ResponsiblePrincipalRecord{HumanID: "did:human:alice123", Jurisdiction: "US-CA"}
+	for _, tc := range []struct {
+		name      string
+		domain    string
+		country   string
+		principal *dcp.ResponsiblePrincipalRecord
+		classes   []string
+		want      dcp.Decision
+	}{
+		{"US target", "clinic.example.us", "", eu, []string{"health_data"}, dcp.DecisionBlock},
+		{"Norwegian target", "clinic.example.no", "", eu, []string{"health_data"}, dcp.DecisionApprove},
+		{"resolved country", "clinic.example.com", "NO", eu, []string{"health_data"}, dcp.DecisionApprove},
+		{"unknown country", "clinic.example.com", "", eu, []string{"health_data"}, dcp.DecisionBlock},
+		{"other data", "clinic.example.us", "", eu, []string{"contact_info"}, dcp.DecisionApprove},
+		{"US principal", "clinic.example.us", "", us, []string{"health_data"}, dcp.DecisionApprove},
+		{"no principal record", "clinic.example.us", "", nil, []string{"health_data"}, dcp.DecisionApprove},
+		{"no target domain", "", "", eu, []string{"health_data"}, dcp.DecisionApprove},
+	} {
+		intent := testIntent("send_email", dcp.ChannelEmail, tc.domain, dcp.ImpactLow, tc.classes...)
+		d := ps.EvaluateInput(pdp.Input{Intent: intent, Principal: tc.principal, TargetCountry: tc.country})
+		if d.Decision != tc.want {
+			t.Errorf("%s: %s %v", tc.name, d.Decision, d.Reasons)
+		}
+	}
+
+	// A subdivision matches only itself.
+	ca := &pdp.PolicySet{Default: dcp.DecisionApprove, Rules: []pdp.Rule{{Name: "ca", PrincipalJurisdictions: []string{"US-CA"}, Decision: dcp.DecisionEscalate}}}
+	intent := testIntent("send_email", dcp.ChannelEmail, "", dcp.ImpactLow)
+	if d := ca.EvaluateInput(pdp.Input{Intent: intent, Principal: us}); d.Decision != dcp.DecisionEscalate {
+		t.Fatalf("US-CA principal: %s", d.Decision)
+	}
+	ny := *us
+	ny.Jurisdiction = "US-NY"
+	if d := ca.EvaluateInput(pdp.Input{Intent: intent, Principal: &ny}); d.Decision != dcp.DecisionApprove {
+		t.Fatalf("US-NY principal: %s", d.Decision)
+	}
+}
+
+type failingGeo struct{}
+
+func (failingGeo) Country(ctx context.Context, domain string) (string, error) {
+	return "", errors.New("whois timeout")
+}
+
+func TestDecideGeo(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(readIntent(t), &intent); err != nil {
+		t.Fatal(err)
+	}
+	domain := "mail.example.com"
+	intent.Target.Domain = &domain
+	policy := &pdp.PolicySet{Default: dcp.DecisionApprove, Rules: []pdp.Rule{
+		{Name: "only the EEA", TargetOutside: []string{"EEA"}, Decision: dcp.DecisionBlock},
+	}}
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Geo: &pdp.DomainCountries{Domains: map[string]string{"*.example.com": "IE"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, err := srv.Decide(context.Background(), &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("Irish target: %+v, %v", d, err)
+	}
+
+	failing, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Geo: failingGeo{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(intent)
+	rec := httptest.NewRecorder()
+	failing.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/decide", strings.NewReader(string(body))))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "whois timeout") {
+		t.Fatalf("failing resolver: %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,483 @@
+// Package pdp is a DCP-02 policy decision point: an HTTP service that
+// evaluates intents against a PolicySet and answers with a signed
+// PolicyDecision, so a gateway or tool runtime can enforce decisions it did
+// not make and an auditor can later prove which policy made them.
+//
+// Endpoints:
+//
+//	POST /v1/decide    body: an Intent; answers a SignedDecision
+//	GET  /v1/policy    the policy set and its hash
+//	GET  /health
+//	GET  /openapi.json the OpenAPI document of the DCP services
+//
+// A body that is not a valid intent answers 400 and an unreachable
+// revocation, passport or principal source, geo resolver or policy backend
+// 503. An intent of a revoked agent is blocked.
+//
+// Intents are evaluated against a PolicySet, the policy set of a signed
+// PolicyBundle or, for organizations with their policies in another engine,
+// a Backend; package opa provides one for Open Policy Agent. Under a bundle,
+// every decision records the policy version, and intents outside the
+// bundle's effective dates answer 503.
+//
+// The rate limits of the policy set are counted in a CounterStore, in
+// memory unless another is configured; an intent over a limit is blocked
+// with a reason starting "rate_limited", and an unreachable store answers
+// 503.
+//
+// A DecisionCache answers an agent's repeated intents of the same shape
+// without looking up and evaluating them again; /health reports its hit
+// rate.
+package pdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/openapi"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/webhook"
+)
+
+// Config configures a Server.
+type Config struct {
+	// Policy is the policy set intents are evaluated against. Exactly one
+	// of Policy, Bundle and Backend is required.
+	Policy *PolicySet
+	// Bundle is a verified policy bundle, whose policy set intents are
+	// evaluated against while it is in effect.
+	Bundle *PolicyBundle
+	// Backend evaluates intents in place of a policy set.
+	Backend Backend
+	// Signer signs decisions. Required.
+	Signer dcp.BundleSigner
+	// Revocations are consulted for the intent's agent before the policy;
+	// a revoked agent's intents are blocked.
+	Revocations []dcp.RevocationChecker
+	// Passports, if set, supplies the passport of the intent's agent, so
+	// rules can match on its risk tier. An agent without a passport is
+	// evaluated without one.
+	Passports PassportSource
+	// Principals, if set, supplies the record of the intent's principal,
+	// which rule conditions can refer to.
+	Principals PrincipalSource
+	// Counters holds the counts of the policy set's rate limits; nil means
+	// a MemoryCounters. Replicas sharing a policy share a RedisCounters.
+	Counters CounterStore
+	// Geo, if set, places target domains for the rules on target
+	// countries; nil places them by their country-code top-level domain.
+	Geo GeoResolver
+	// Cache, if set, answers intents of a shape it has decided for an
+	// agent from the decisions it remembers.
+	Cache *DecisionCache
+	// Replay, if set, remembers the intents decided, and Decide refuses
+	// one presented again with dcp.ErrReplay. Replicas share a
+	// CounterReplayGuard over a RedisCounters.
+	Replay dcp.ReplayGuard
+	// ReplayTTL is how long Replay remembers an intent; zero means 24
+	// hours.
+	ReplayTTL time.Duration
+	// Risk, if set, scores intents from weighted factors instead of their
+	// estimated impact alone, and observes every decision.
+	Risk *RiskScorer
+	// Timeout bounds each request, including revocation lookups; zero means
+	// 10 seconds.
+	Timeout time.Duration
+	// MaxBodyBytes caps request bodies; zero means 1 MiB.
+	MaxBodyBytes int64
+	// Now is the clock decisions are stamped with; nil means time.Now.
+	Now func() time.Time
+	// Webhooks, if set, is sent a policy.denied event for every block
+	// decision.
+	Webhooks *webhook.Dispatcher
+}
+
+// Backend is a policy engine that decides intents.
+type Backend interface {
+	// Evaluate decides the intent of in. An error means the backend could
+	// not decide, not that the intent is denied.
+	Evaluate(ctx context.Context, in Input) (dcp.PolicyDecision, error)
+	// PolicyHash identifies the policy the backend decides under; it is
+	// recorded in every decision.
+	PolicyHash() string
+}
+
+// PassportSource looks up passports by agent_id; registry.Client is one.
+type PassportSource interface {
+	Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error)
+}
+
+// PrincipalSource looks up principal records by human_id; a principal that
+// is not found is nil.
+type PrincipalSource interface {
+	Principal(ctx context.Context, humanID string) (*dcp.ResponsiblePrincipalRecord, error)
+}
+
+// SignedDecision is the response to POST /v1/decide. The signature covers
+// the decision together with the hashes of the intent and policy set, so
+// the decision cannot be replayed for another intent.
+type SignedDecision struct {
+	PolicyDecision dcp.PolicyDecision `json:"policy_decision"`
+	IntentHash     string             `json:"intent_hash"`
+	PolicyHash     string             `json:"policy_hash"`
+	// PolicyVersion is the version of the policy bundle the decision was
+	// made under, if any.
+	PolicyVersion string `json:"policy_version,omitempty"`
+	DecidedAt     string `json:"decided_at"`
+	SignerKey     string `json:"signer_key"`
+	// Signature is over the canonical SignedDecision with an empty
+	// signature.
+	Signature string `json:"signature"`
+}
+
+// Verify checks the signature against the PDP's key and that the decision
+// was made for intent.
+func (d *SignedDecision) Verify(publicKeyB64 string, intent *dcp.Intent) error {
+	if d.Signature == "" {
+		return errors.New("policy decision has no signature")
+	}
+	unsigned := *d
+	unsigned.Signature = ""
+	if ok, err := dcp.VerifyObject(unsigned, d.Signature, publicKeyB64); err != nil || !ok {
+		return errors.New("policy decision signature does not verify")
+	}
+	h, err := dcp.HashObject(intent)
+	if err != nil {
+		return err
+	}
+	if h != d.IntentHash || d.PolicyDecision.IntentID != intent.IntentID {
+		return fmt.Errorf("policy decision was made for another intent than %s", intent.IntentID)
+	}
+	return nil
+}
+
+// Server serves the decision API. Create one with New.
+type Server struct {
+	cfg        Config
+	policyHash string
+	mux        *http.ServeMux
+}
+
+// New returns a Server for cfg.
+func New(cfg Config) (*Server, error) {
+	if cfg.Signer == nil {
+		return nil, errors.New("pdp: a decision signer is required")
+	}
+	sources := 0
+	for _, set := range []bool{cfg.Policy != nil, cfg.Bundle != nil, cfg.Backend != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, errors.New("pdp: exactly one of a policy set, a policy bundle and a backend is required")
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.Bundle != nil {
+		if err := cfg.Bundle.Validate(); err != nil {
+			return nil, fmt.Errorf("pdp: policy bundle %s: %w", cfg.Bundle.Version, err)
+		}
+		// A bundle that is not in effect yet is loaded ahead of its time;
+		// one that has expired never decides again.
+		if until, err := dcp.ParseTime(cfg.Bundle.EffectiveUntil); err == nil && !cfg.Now().Before(until) {
+			return nil, fmt.Errorf("pdp: policy version %s expired at %s", cfg.Bundle.Version, cfg.Bundle.EffectiveUntil)
+		}
+		cfg.Policy = &cfg.Bundle.Policy
+	}
+	var h string
+	if cfg.Policy != nil {
+		if err := cfg.Policy.Validate(); err != nil {
+			return nil, fmt.Errorf("pdp: %w", err)
+		}
+		var err error
+		if h, err = cfg.Policy.Hash(); err != nil {
+			return nil, fmt.Errorf("pdp: %w", err)
+		}
+	} else {
+		h = cfg.Backend.PolicyHash()
+	}
+	if cfg.Risk != nil {
+		if err := cfg.Risk.Validate(); err != nil {
+			return nil, fmt.Errorf("pdp: %w", err)
+		}
+	}
+	if cfg.Counters == nil && cfg.Policy != nil && len(cfg.Policy.RateLimits) > 0 {
+		cfg.Counters = NewMemoryCounters()
+	}
+	if cfg.ReplayTTL <= 0 {
+		cfg.ReplayTTL = 24 * time.Hour
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 1 << 20
+	}
+	s := &Server{cfg: cfg, policyHash: h, mux: http.NewServeMux()}
+	s.mux.HandleFunc("POST /v1/decide", s.handleDecide)
+	s.mux.HandleFunc("GET /v1/policy", s.handlePolicy)
+	s.mux.HandleFunc("GET /health", s.handleHealth)
+	s.mux.Handle("GET /openapi.json", openapi.Handler())
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Decide evaluates intent and signs the decision. It returns an error only
+// when the intent is invalid or replayed, or a revocation source, record
+// source, the backend, the rate-limit counters or the replay guard could
+// not be consulted.
+func (s *Server) Decide(ctx context.Context, intent *dcp.Intent) (*SignedDecision, error) {
+	if err := intent.Validate(); err != nil {
+		return nil, err
+	}
+	var d dcp.PolicyDecision
+	now := s.cfg.Now()
+	rec, err := s.revocation(ctx, intent.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	evaluated := false
+	if rec != nil {
+		d = dcp.PolicyDecision{
+			DCPVersion: "1.0",
+			IntentID:   intent.IntentID,
+			Decision:   dcp.DecisionBlock,
+			RiskScore:  1,
+			Reasons:    []string{fmt.Sprintf("agent %s was revoked at %s", rec.AgentID, rec.Timestamp)},
+		}
+	} else if expired, refused := expiryRefusal(intent, now); refused {
+		d = expired
+	} else {
+		if s.cfg.Bundle != nil {
+			if err := s.cfg.Bundle.InEffect(now); err != nil {
+				return nil, fmt.Errorf("%w: %v", errPolicyBundle, err)
+			}
+		}
+		var key string
+		hit := false
+		if s.cfg.Cache != nil {
+			if key, err = cacheKey(s.policyHash, intent); err != nil {
+				return nil, err
+			}
+			if d, hit = s.cfg.Cache.get(key, now); hit {
+				d.IntentID = intent.IntentID
+			}
+		}
+		if !hit {
+			if d, err = s.evaluate(ctx, intent, now); err != nil {
+				return nil, err
+			}
+			if s.cfg.Cache != nil {
+				s.cfg.Cache.put(key, intent.AgentID, d, now)
+			}
+		}
+		evaluated = true
+	}
+	// Intents are remembered once the lookups that can fail are done, so
+	// that a retry after an error is not taken for a replay, and before
+	// they count toward rate limits and risk history, so that a replay
+	// does not.
+	if s.cfg.Replay != nil {
+		if err := dcp.CheckReplay(ctx, s.cfg.Replay, intent, s.cfg.ReplayTTL); err != nil {
+			if errors.Is(err, dcp.ErrReplay) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errReplayGuard, err)
+		}
+	}
+	if evaluated {
+		if s.cfg.Policy != nil && len(s.cfg.Policy.RateLimits) > 0 {
+			over, err := s.cfg.Policy.rateLimit(ctx, s.cfg.Counters, intent, now)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", errRateLimiter, err)
+			}
+			if over != "" {
+				rateLimited(&d, over)
+			}
+		}
+		if s.cfg.Risk != nil {
+			s.cfg.Risk.Observe(intent.AgentID, d.Decision, now)
+		}
+	}
+	// The decision is signed on its own too, so that it can be checked once
+	// copied into a bundle.
+	if err := d.Sign(s.cfg.Signer); err != nil {
+		return nil, fmt.Errorf("pdp: %w", err)
+	}
+	intentHash, err := dcp.HashObject(intent)
+	if err != nil {
+		return nil, err
+	}
+	sd := &SignedDecision{
+		PolicyDecision: d,
+		IntentHash:     intentHash,
+		PolicyHash:     s.policyHash,
+		DecidedAt:      dcp.FormatTime(now),
+		SignerKey:      s.cfg.Signer.PublicKeyB64(),
+	}
+	if s.cfg.Bundle != nil {
+		sd.PolicyVersion = s.cfg.Bundle.Version
+	}
+	canon, err := dcp.Canonicalize(sd)
+	if err != nil {
+		return nil, fmt.Errorf("pdp: sign decision: %w", err)
+	}
+	if sd.Signature, err = s.cfg.Signer.SignCanonical(canon); err != nil {
+		return nil, fmt.Errorf("pdp: sign decision: %w", err)
+	}
+	if d.Decision == dcp.DecisionBlock {
+		s.cfg.Webhooks.Emit(webhook.EventPolicyDenied, sd)
+	}
+	return sd, nil
+}
+
+// evaluate looks up the records of intent, scores it and decides it under
+// the policy set or backend.
+func (s *Server) evaluate(ctx context.Context, intent *dcp.Intent, now time.Time) (dcp.PolicyDecision, error) {
+	var err error
+	in := Input{Intent: intent, Now: now}
+	if s.cfg.Passports != nil {
+		if in.Passport, err = s.cfg.Passports.Passport(ctx, intent.AgentID); err != nil {
+			return dcp.PolicyDecision{}, fmt.Errorf("%w for %s: %v", errRecordSource, intent.AgentID, err)
+		}
+	}
+	if s.cfg.Principals != nil {
+		if in.Principal, err = s.cfg.Principals.Principal(ctx, intent.HumanID); err != nil {
+			return dcp.PolicyDecision{}, fmt.Errorf("%w for %s: %v", errRecordSource, intent.HumanID, err)
+		}
+	}
+	if domain := intent.Target.Host(); s.cfg.Geo != nil && domain != "" {
+		if in.TargetCountry, err = s.cfg.Geo.Country(ctx, domain); err != nil {
+			return dcp.PolicyDecision{}, fmt.Errorf("%w for %s: %v", errRecordSource, domain, err)
+		}
+	}
+	if s.cfg.Risk != nil {
+		if in.Risk, err = s.cfg.Risk.Score(ctx, in); err != nil {
+			return dcp.PolicyDecision{}, fmt.Errorf("%w: %v", errRiskFactor, err)
+		}
+	}
+	if s.cfg.Backend == nil {
+		return s.cfg.Policy.EvaluateInput(in), nil
+	}
+	if d, refused := domainRefusal(&in); refused {
+		return d, nil
+	}
+	d, err := s.cfg.Backend.Evaluate(ctx, in)
+	if err != nil {
+		return dcp.PolicyDecision{}, fmt.Errorf("%w: %v", errBackend, err)
+	}
+	return d, nil
+}
+
+// errRevocationSource, errRecordSource, errBackend, errPolicyBundle,
+// errRiskFactor, errRateLimiter and errReplayGuard mark Decide errors the
+// service answers with 503.
+var (
+	errRevocationSource = errors.New("revocation check")
+	errRecordSource     = errors.New("record lookup")
+	errBackend          = errors.New("policy backend")
+	errPolicyBundle     = errors.New("policy bundle")
+	errRiskFactor       = errors.New("risk scoring")
+	errRateLimiter      = errors.New("rate limiter")
+	errReplayGuard      = errors.New("replay guard")
+)
+
+func (s *Server) revocation(ctx context.Context, agentID string) (*dcp.RevocationRecord, error) {
+	for _, rc := range s.cfg.Revocations {
+		rec, err := rc.CheckRevocation(ctx, agentID)
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s: %v", errRevocationSource, agentID, err)
+		}
+		if rec != nil {
+			return rec, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) handleDecide(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.Timeout)
+	defer cancel()
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.cfg.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", s.cfg.MaxBodyBytes))
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(body, &intent); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	d, err := s.Decide(ctx, &intent)
+	switch {
+	case errors.Is(err, errRevocationSource), errors.Is(err, errRecordSource),
+		errors.Is(err, errBackend), errors.Is(err, errPolicyBundle), errors.Is(err, errRiskFactor),
+		errors.Is(err, errRateLimiter), errors.Is(err, errReplayGuard):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, dcp.ErrReplay):
+		writeError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, d)
+	}
+}
+
+func (s *Server) handlePolicy(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.Policy == nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"policy_hash": s.policyHash})
+		return
+	}
+	res := map[string]interface{}{"policy": s.cfg.Policy, "policy_hash": s.policyHash}
+	if s.cfg.Bundle != nil {
+		res["policy_bundle"] = s.cfg.Bundle
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	health := map[string]interface{}{
+		"ok":                 true,
+		"service":            "dcp-pdp",
+		"policy_hash":        s.policyHash,
+		"signer_key":         s.cfg.Signer.PublicKeyB64(),
+		"revocation_sources": len(s.cfg.Revocations),
+	}
+	if s.cfg.Policy != nil {
+		health["rules"] = len(s.cfg.Policy.Rules)
+	}
+	if s.cfg.Bundle != nil {
+		health["policy_version"] = s.cfg.Bundle.Version
+	}
+	if s.cfg.Cache != nil {
+		health["decision_cache"] = s.cfg.Cache.Stats()
+	}
+	writeJSON(w, http.StatusOK, health)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,300 @@
+package pdp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/webhook"
+)
+
+func readIntent(t *testing.T) []byte {
+	t.Helper()
+	_, thisFile, _, _ := runtime.Caller(0)
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(thisFile), "..", "..", "..", "..", "tests", "conformance", "examples", "intent.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+type failingPassports struct{}
+
+func (failingPassports) Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error) {
+	return nil, errors.New("unreachable")
+}
+
+type failingChecker struct{}
+
+func (failingChecker) CheckRevocation(ctx context.Context, agentID string) (*dcp.RevocationRecord, error) {
+	return nil, errors.New("unreachable")
+}
+
+func decide(t *testing.T, h http.Handler, body []byte) (int, pdp.SignedDecision, map[string]interface{}) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/decide", bytes.NewReader(body)))
+	var d pdp.SignedDecision
+	var generic map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &d)
+	json.Unmarshal(rec.Body.Bytes(), &generic)
+	return rec.Code, d, generic
+}
+
+func TestDecide(t *testing.T) {
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := &pdp.PolicySet{Rules: []pdp.Rule{{Name: "email", Channels: []dcp.Channel{dcp.ChannelEmail}, Decision: dcp.DecisionApprove}}}
+	revocations := &dcp.RevocationList{}
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Revocations: []dcp.RevocationChecker{revocations}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := readIntent(t)
+	var intent dcp.Intent
+	if err := json.Unmarshal(body, &intent); err != nil {
+		t.Fatal(err)
+	}
+
+	code, d, _ := decide(t, srv, body)
+	if code != http.StatusOK || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("decide: %d %+v", code, d)
+	}
+	if err := d.Verify(kp.PublicKeyB64, &intent); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := d.PolicyDecision.VerifySignature(kp.PublicKeyB64); !ok || err != nil || d.PolicyDecision.DecisionID == "" {
+		t.Fatalf("policy_decision signature: %v, %v", ok, err)
+	}
+	if want, _ := policy.Hash(); d.PolicyHash != want {
+		t.Fatalf("policy_hash = %s, want %s", d.PolicyHash, want)
+	}
+	other := intent
+	other.ActionType = "delete_mailbox"
+	if err := d.Verify(kp.PublicKeyB64, &other); err == nil {
+		t.Fatal("decision verified for another intent")
+	}
+	d.PolicyDecision.Decision = dcp.DecisionBlock
+	if err := d.Verify(kp.PublicKeyB64, &intent); err == nil {
+		t.Fatal("tampered decision verified")
+	}
+
+	revocations.Add(dcp.NewRevocationRecord(intent.AgentID, intent.HumanID, "retired"))
+	if _, d, _ = decide(t, srv, body); d.PolicyDecision.Decision != dcp.DecisionBlock {
+		t.Fatalf("revoked agent: %+v", d)
+	}
+
+	if code, _, generic := decide(t, srv, []byte(`{"intent_id": "x"}`)); code != http.StatusBadRequest || generic["error"] == nil {
+		t.Fatalf("invalid intent: %d %v", code, generic)
+	}
+	failing, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Revocations: []dcp.RevocationChecker{failingChecker{}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _, _ := decide(t, failing, body); code != http.StatusServiceUnavailable {
+		t.Fatalf("unreachable revocation source: %d", code)
+	}
+	failing, err = pdp.New(pdp.Config{Policy: policy, Signer: signer, Passports: failingPassports{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _, _ := decide(t, failing, body); code != http.StatusServiceUnavailable {
+		t.Fatalf("unreachable passport source: %d", code)
+	}
+
+	if _, err := pdp.New(pdp.Config{Policy: policy}); err == nil {
+		t.Fatal("server without a signer")
+	}
+	if _, err := pdp.New(pdp.Config{Policy: &pdp.PolicySet{Default: "deny"}, Signer: signer}); err == nil {
+		t.Fatal("server with an invalid policy")
+	}
+}
+
+func TestDecidePassports(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(readIntent(t), &intent); err != nil {
+		t.Fatal(err)
+	}
+	policy := &pdp.PolicySet{Default: dcp.DecisionBlock, Rules: []pdp.Rule{{Name: "low risk", RiskTiers: []dcp.RiskTier{dcp.RiskTierLow}, Decision: dcp.DecisionApprove}}}
+	passports := grpcserver.PassportMap{intent.AgentID: {AgentID: intent.AgentID, RiskTier: dcp.RiskTierLow, Capabilities: []string{"email"}}}
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Passports: passports})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, err := srv.Decide(context.Background(), &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("low-risk agent: %+v, %v", d, err)
+	}
+	intent.AgentID = "agent-without-passport"
+	if d, err := srv.Decide(context.Background(), &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionBlock {
+		t.Fatalf("agent without a passport: %+v, %v", d, err)
+	}
+}
+
+func TestDecideReplay(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := &pdp.PolicySet{Default: dcp.DecisionApprove}
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer,
+		Replay: pdp.CounterReplayGuard{Counters: pdp.NewMemoryCounters()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := readIntent(t)
+	if code, _, _ := decide(t, srv, body); code != http.StatusOK {
+		t.Fatalf("first: %d", code)
+	}
+	if code, _, generic := decide(t, srv, body); code != http.StatusConflict || !strings.Contains(generic["error"].(string), "already presented") {
+		t.Fatalf("replayed: %d %v", code, generic)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(body, &intent); err != nil {
+		t.Fatal(err)
+	}
+	intent.Nonce = dcp.NewNonce()
+	if _, err := srv.Decide(context.Background(), &intent); err != nil {
+		t.Fatalf("with a nonce: %v", err)
+	}
+	intent.ActionType = "browse"
+	if _, err := srv.Decide(context.Background(), &intent); !errors.Is(err, dcp.ErrReplay) {
+		t.Fatalf("nonce reused: %v", err)
+	}
+
+	// Replays do not count toward rate limits.
+	policy.RateLimits = []pdp.RateLimit{{Name: "hourly", Limit: 2, Window: "1h"}}
+	srv, err = pdp.New(pdp.Config{Policy: policy, Signer: signer,
+		Replay: pdp.CounterReplayGuard{Counters: pdp.NewMemoryCounters()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	intent.Nonce = dcp.NewNonce()
+	for i := 0; i < 3; i++ {
+		if _, err := srv.Decide(context.Background(), &intent); (i == 0) != (err == nil) {
+			t.Fatalf("presentation %d: %v", i, err)
+		}
+	}
+	intent.Nonce = dcp.NewNonce()
+	if d, err := srv.Decide(context.Background(), &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("after replays: %+v, %v", d, err)
+	}
+}
+
+type principalMap map[string]*dcp.ResponsiblePrincipalRecord
+
+func (m principalMap) Principal(ctx context.Context, humanID string) (*dcp.ResponsiblePrincipalRecord, error) {
+	return m[humanID], nil
+}
+
+func TestDecidePrincipals(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(readIntent(t), &intent); err != nil {
+		t.Fatal(err)
+	}
+	policy := &pdp.PolicySet{Default: dcp.DecisionBlock, Rules: []pdp.Rule{{Name: "EU principals", When: `principal.jurisdiction.startsWith("EU")`, Decision: dcp.DecisionApprove}}}
+	principals := principalMap{intent.HumanID: {HumanID: intent.HumanID, Jurisdiction: "EU-FR"}}
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Principals: principals})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, err := srv.Decide(context.Background(), &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("EU principal: %+v, %v", d, err)
+	}
+	principals[intent.HumanID].Jurisdiction = "US-NY"
+	if d, err := srv.Decide(context.Background(), &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionBlock {
+		t.Fatalf("US principal: %+v, %v", d, err)
+	}
+	// Without a record the condition cannot be evaluated.
+	delete(principals, intent.HumanID)
+	if d, err := srv.Decide(context.Background(), &intent); err != nil || !strings.Contains(strings.Join(d.PolicyDecision.Reasons, "; "), "condition failed") {
+		t.Fatalf("unknown principal: %+v, %v", d, err)
+	}
+
+	if _, err := pdp.New(pdp.Config{Policy: &pdp.PolicySet{Default: dcp.DecisionBlock, Rules: []pdp.Rule{{Name: "bad", When: "principal.", Decision: dcp.DecisionApprove}}}, Signer: signer}); err == nil {
+		t.Fatal("policy with an invalid condition loaded")
+	}
+}
+
+func TestDecideWebhooks(t *testing.T) {
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var events []webhook.Event
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ev, err := webhook.VerifyRequest(r, "secret", 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mu.Lock()
+		events = append(events, *ev)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+	hooks, err := webhook.New(webhook.Config{Endpoints: []webhook.Endpoint{{URL: hook.URL, Secret: "secret"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := &pdp.PolicySet{Default: dcp.DecisionBlock, Rules: []pdp.Rule{{Name: "email", Channels: []dcp.Channel{dcp.ChannelEmail}, Decision: dcp.DecisionApprove}}}
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Webhooks: hooks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(readIntent(t), &intent); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.Decide(context.Background(), &intent); err != nil {
+		t.Fatal(err)
+	}
+	intent.Target.Channel = dcp.ChannelPayments
+	blocked, err := srv.Decide(context.Background(), &intent)
+	if err != nil || blocked.PolicyDecision.Decision != dcp.DecisionBlock {
+		t.Fatalf("Decide = %+v, %v", blocked, err)
+	}
+	if err := hooks.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != webhook.EventPolicyDenied {
+		t.Fatalf("events = %+v", events)
+	}
+	var got pdp.SignedDecision
+	if err := json.Unmarshal(events[0].Data, &got); err != nil || got.Signature != blocked.Signature {
+		t.Fatalf("event data = %s", events[0].Data)
+	}
+}
@@ -0,0 +1,594 @@
+package pdp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Default risk thresholds, those of the gateway's decidePolicy on the V1
+// 0..1 scale.
+const (
+	DefaultEscalateAt = 0.5
+	DefaultBlockAt    = 0.8
+)
+
+// impactRisk is the base risk score of an intent's estimated_impact.
+var impactRisk = map[dcp.EstimatedImpact]float64{
+	dcp.ImpactLow:    0.1,
+	dcp.ImpactMedium: 0.4,
+	dcp.ImpactHigh:   0.7,
+}
+
+// Rule matches intents and states a decision for them. Every non-empty
+// condition must hold for the rule to match; a rule with no conditions
+// matches every intent.
+type Rule struct {
+	Name string `json:"name"`
+
+	ActionTypes []string              `json:"action_types,omitempty"`
+	Channels    []dcp.Channel         `json:"channels,omitempty"`
+	Impacts     []dcp.EstimatedImpact `json:"impacts,omitempty"`
+	Agents      []string              `json:"agents,omitempty"`
+	// Domains matches target.domain exactly, or any subdomain of a
+	// "*.example.com" pattern.
+	Domains []string `json:"domains,omitempty"`
+	// DataClasses matches intents touching any of the classes.
+	DataClasses []string `json:"data_classes,omitempty"`
+	// URLs matches target.url by scheme, host and path prefix, e.g.
+	// "https://api.example.com/v1/".
+	URLs []string `json:"urls,omitempty"`
+	// Recipients matches target.to exactly, or any address at a domain
+	// of a "*@example.com" pattern.
+	Recipients []string `json:"recipients,omitempty"`
+	// RiskTiers matches agents whose passport has one of the tiers; a
+	// passport without a tier counts as high. An intent evaluated without
+	// its agent's passport does not match.
+	RiskTiers []dcp.RiskTier `json:"risk_tiers,omitempty"`
+	// Windows matches intents evaluated within any of the time windows.
+	Windows []TimeWindow `json:"windows,omitempty"`
+	// PrincipalJurisdictions matches intents whose principal's
+	// jurisdiction is within one of the entries: ISO 3166-1 country codes,
+	// ISO 3166-2 subdivisions such as "US-CA", or the groups "EU" and
+	// "EEA". An intent evaluated without its principal's record does not
+	// match.
+	PrincipalJurisdictions []string `json:"principal_jurisdictions,omitempty"`
+	// TargetCountries matches intents whose target domain is in one of the
+	// countries or groups; TargetOutside those whose target domain is in
+	// none of them, or in a country not known. Intents without a target
+	// domain match neither.
+	TargetCountries []string `json:"target_countries,omitempty"`
+	TargetOutside   []string `json:"target_outside,omitempty"`
+	// When is a CEL expression that must evaluate to true, over the
+	// variables intent, passport and principal, the records in their JSON
+	// form, and now, a timestamp. For example:
+	//
+	//	intent.target.channel == "payments" && passport.risk_tier != "low"
+	//
+	// A condition that fails to evaluate escalates the intent.
+	When string `json:"when,omitempty"`
+
+	Decision dcp.Decision `json:"decision"`
+	// RiskScore raises the intent's risk score to at least this value.
+	RiskScore float64 `json:"risk_score,omitempty"`
+	Reason    string  `json:"reason,omitempty"`
+	// RequiredConfirmation overrides the default confirmation of escalate
+	// and block decisions, which asks a human to approve the action_type,
+	// target and estimated_impact.
+	RequiredConfirmation *dcp.RequiredConfirmation `json:"required_confirmation,omitempty"`
+}
+
+// TimeWindow is a recurring period of the week, such as office hours.
+type TimeWindow struct {
+	// Days are "mon" to "sun"; empty means every day. A day is that of
+	// the evaluation time in Timezone.
+	Days []string `json:"days,omitempty"`
+	// From and Until are "15:04" times of day, From included and Until
+	// excluded; empty means 00:00 and 24:00. An Until before From spans
+	// midnight.
+	From  string `json:"from,omitempty"`
+	Until string `json:"until,omitempty"`
+	// Timezone is an IANA time zone name; empty means UTC.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// validate checks the window's days, times and time zone.
+func (w *TimeWindow) validate() error {
+	for _, d := range w.Days {
+		if _, ok := weekdays[d]; !ok {
+			return fmt.Errorf("unknown day %q", d)
+		}
+	}
+	for _, t := range []string{w.From, w.Until} {
+		if _, err := clockMinutes(t, 0); err != nil {
+			return err
+		}
+	}
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return fmt.Errorf("timezone %q: %v", w.Timezone, err)
+	}
+	return nil
+}
+
+// contains reports whether t falls within the window. A window that does
+// not validate contains nothing.
+func (w *TimeWindow) contains(t time.Time) bool {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return false
+	}
+	t = t.In(loc)
+	if len(w.Days) > 0 {
+		ok := false
+		for _, d := range w.Days {
+			ok = ok || weekdays[d] == t.Weekday()
+		}
+		if !ok {
+			return false
+		}
+	}
+	from, err1 := clockMinutes(w.From, 0)
+	until, err2 := clockMinutes(w.Until, 24*60)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	m := t.Hour()*60 + t.Minute()
+	if until < from {
+		return m >= from || m < until
+	}
+	return m >= from && m < until
+}
+
+// clockMinutes returns the minutes since midnight of a "15:04" time, or
+// def if s is empty.
+func clockMinutes(s string, def int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("time of day %q is not HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// PolicySet is the policy a Server evaluates intents against.
+//
+// An intent's risk score is the base score of its estimated_impact, raised
+// to the risk_score of every matching rule. The decision is the most
+// restrictive of those of the matching rules and of the thresholds: a score
+// of at least escalate_at escalates, of at least block_at blocks. An intent
+// no rule matches gets Default, which is escalate when empty, so an
+// incomplete policy fails towards a human.
+//
+// A Server also enforces the set's rate limits, blocking the intents of an
+// agent over one whatever the rules decide.
+type PolicySet struct {
+	Rules      []Rule       `json:"rules"`
+	Default    dcp.Decision `json:"default,omitempty"`
+	EscalateAt float64      `json:"escalate_at,omitempty"`
+	BlockAt    float64      `json:"block_at,omitempty"`
+	RateLimits []RateLimit  `json:"rate_limits,omitempty"`
+}
+
+// ReadPolicySet reads and validates a policy set file.
+func ReadPolicySet(path string) (*PolicySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ps PolicySet
+	if err := json.Unmarshal(data, &ps); err != nil {
+		return nil, fmt.Errorf("policy set %s: %w", path, err)
+	}
+	if err := ps.Validate(); err != nil {
+		return nil, fmt.Errorf("policy set %s: %w", path, err)
+	}
+	return &ps, nil
+}
+
+// Validate checks that every decision is a DCP-02 decision and the
+// thresholds are in range.
+func (ps *PolicySet) Validate() error {
+	if ps.Default != "" && !ps.Default.IsValid() {
+		return fmt.Errorf("default: unknown decision %q", ps.Default)
+	}
+	for i, r := range ps.Rules {
+		if !r.Decision.IsValid() {
+			return fmt.Errorf("rules[%d] %s: unknown decision %q", i, r.Name, r.Decision)
+		}
+		if c := r.RequiredConfirmation; c != nil && c.Type != "human_approve" {
+			return fmt.Errorf("rules[%d] %s: required_confirmation type must be human_approve", i, r.Name)
+		}
+		if r.RiskScore < 0 || r.RiskScore > 1 {
+			return fmt.Errorf("rules[%d] %s: risk_score must be between 0 and 1", i, r.Name)
+		}
+		for _, tier := range r.RiskTiers {
+			if !tier.IsValid() {
+				return fmt.Errorf("rules[%d] %s: unknown risk tier %q", i, r.Name, tier)
+			}
+		}
+		for _, prefix := range r.URLs {
+			if u, err := url.Parse(prefix); err != nil || u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("rules[%d] %s: url %q is not absolute", i, r.Name, prefix)
+			}
+		}
+		if err := r.validateJurisdictions(); err != nil {
+			return fmt.Errorf("rules[%d] %s: %v", i, r.Name, err)
+		}
+		for j := range r.Windows {
+			if err := r.Windows[j].validate(); err != nil {
+				return fmt.Errorf("rules[%d] %s: windows[%d]: %v", i, r.Name, j, err)
+			}
+		}
+		if r.When != "" {
+			if _, err := compileCondition(r.When); err != nil {
+				return fmt.Errorf("rules[%d] %s: when: %v", i, r.Name, err)
+			}
+		}
+	}
+	limits := map[string]bool{}
+	for i := range ps.RateLimits {
+		l := &ps.RateLimits[i]
+		if err := l.validate(); err != nil {
+			return fmt.Errorf("rate_limits[%d] %s: %v", i, l.Name, err)
+		}
+		if limits[l.Name] {
+			return fmt.Errorf("rate_limits[%d] %s: name appears twice", i, l.Name)
+		}
+		limits[l.Name] = true
+	}
+	for name, v := range map[string]float64{"escalate_at": ps.EscalateAt, "block_at": ps.BlockAt} {
+		if v < 0 || v > 1 {
+			return fmt.Errorf("%s must be between 0 and 1", name)
+		}
+	}
+	return nil
+}
+
+// Hash returns the hash of the canonical policy set, recorded in every
+// decision so it can be traced to the policy that made it.
+func (ps *PolicySet) Hash() (string, error) {
+	return dcp.HashObject(ps)
+}
+
+// restrictiveness orders decisions from least to most restrictive.
+var restrictiveness = map[dcp.Decision]int{
+	dcp.DecisionApprove:  0,
+	dcp.DecisionEscalate: 1,
+	dcp.DecisionBlock:    2,
+}
+
+func stricter(a, b dcp.Decision) dcp.Decision {
+	if restrictiveness[b] > restrictiveness[a] {
+		return b
+	}
+	return a
+}
+
+// expiryRefusal blocks intent if it has expired at now, whatever the
+// policy says.
+func expiryRefusal(intent *dcp.Intent, now time.Time) (dcp.PolicyDecision, bool) {
+	expired, err := intent.Expired(now)
+	if err == nil && !expired {
+		return dcp.PolicyDecision{}, false
+	}
+	reason := fmt.Sprintf("intent %s expired at %s", intent.IntentID, *intent.ExpiresAt)
+	if err != nil {
+		reason = fmt.Sprintf("intent %s: expires_at: %v", intent.IntentID, err)
+	}
+	return dcp.PolicyDecision{
+		DCPVersion: "1.0",
+		IntentID:   intent.IntentID,
+		Decision:   dcp.DecisionBlock,
+		RiskScore:  1,
+		Reasons:    []string{reason},
+	}, true
+}
+
+// domainRefusal blocks the intent of in if its target is outside the
+// intent's domain list or the passport's for the capability it exercises,
+// or if the passport does not grant that capability for the target.
+// Domain lists and capabilities bind whatever the policy says.
+func domainRefusal(in *Input) (dcp.PolicyDecision, bool) {
+	err := dcp.CheckDomains(in.Passport, in.Intent)
+	if err == nil && in.Passport != nil {
+		err = dcp.CheckCapability(in.Passport, in.Intent)
+	}
+	if err == nil {
+		return dcp.PolicyDecision{}, false
+	}
+	return dcp.PolicyDecision{
+		DCPVersion: "1.0",
+		IntentID:   in.Intent.IntentID,
+		Decision:   dcp.DecisionBlock,
+		RiskScore:  1,
+		Reasons:    []string{err.Error()},
+	}, true
+}
+
+// Evaluate decides intent under the policy set now, without the agent's
+// passport. It does not sign the decision; see Server.Decide.
+func (ps *PolicySet) Evaluate(intent *dcp.Intent) dcp.PolicyDecision {
+	return ps.EvaluateAt(intent, nil, time.Now())
+}
+
+// EvaluateAt decides intent at now, for the agent of passport. passport may
+// be nil, in which case rules on risk_tiers do not match; a passport of
+// another agent than the intent's blocks the intent.
+func (ps *PolicySet) EvaluateAt(intent *dcp.Intent, passport *dcp.AgentPassport, now time.Time) dcp.PolicyDecision {
+	return ps.EvaluateInput(Input{Intent: intent, Passport: passport, Now: now})
+}
+
+// EvaluateInput is EvaluateAt with the principal's record, which conditions
+// can refer to. A principal other than the intent's blocks the intent, as
+// does a target the intent's or passport's domain lists refuse, an action
+// the passport's capabilities do not grant, and an intent that has expired.
+func (ps *PolicySet) EvaluateInput(in Input) dcp.PolicyDecision {
+	intent, passport := in.Intent, in.Passport
+	if in.Now.IsZero() {
+		in.Now = time.Now()
+	}
+	if in.Principal != nil && in.Principal.HumanID != intent.HumanID {
+		return dcp.PolicyDecision{
+			DCPVersion: "1.0",
+			IntentID:   intent.IntentID,
+			Decision:   dcp.DecisionBlock,
+			RiskScore:  1,
+			Reasons:    []string{fmt.Sprintf("principal record is of %s, not of the intent's principal %s", in.Principal.HumanID, intent.HumanID)},
+		}
+	}
+	if passport != nil && passport.AgentID != intent.AgentID {
+		return dcp.PolicyDecision{
+			DCPVersion: "1.0",
+			IntentID:   intent.IntentID,
+			Decision:   dcp.DecisionBlock,
+			RiskScore:  1,
+			Reasons:    []string{fmt.Sprintf("passport is of %s, not of the intent's agent %s", passport.AgentID, intent.AgentID)},
+		}
+	}
+	if d, refused := expiryRefusal(intent, in.Now); refused {
+		return d
+	}
+	if d, refused := domainRefusal(&in); refused {
+		return d
+	}
+	escalateAt, blockAt := ps.EscalateAt, ps.BlockAt
+	if escalateAt == 0 {
+		escalateAt = DefaultEscalateAt
+	}
+	if blockAt == 0 {
+		blockAt = DefaultBlockAt
+	}
+
+	d := dcp.PolicyDecision{
+		DCPVersion: "1.0",
+		IntentID:   intent.IntentID,
+		Decision:   dcp.DecisionApprove,
+		RiskScore:  impactRisk[intent.EstimatedImpact],
+		Reasons:    []string{},
+	}
+	if in.Risk != nil {
+		d.RiskScore = in.Risk.Score
+	}
+	var confirmation *dcp.RequiredConfirmation
+	matched := false
+	for _, r := range ps.Rules {
+		ok, err := r.matches(&in)
+		if err != nil {
+			d.Decision = stricter(d.Decision, dcp.DecisionEscalate)
+			d.Reasons = append(d.Reasons, fmt.Sprintf("rule %s: condition failed: %v", r.Name, err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		matched = true
+		if r.RiskScore > d.RiskScore {
+			d.RiskScore = r.RiskScore
+		}
+		if restrictiveness[r.Decision] >= restrictiveness[d.Decision] && r.RequiredConfirmation != nil {
+			confirmation = r.RequiredConfirmation
+		}
+		d.Decision = stricter(d.Decision, r.Decision)
+		reason := r.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("rule %s: %s", r.Name, r.Decision)
+		}
+		d.Reasons = append(d.Reasons, reason)
+	}
+	if !matched {
+		def := ps.Default
+		if def == "" {
+			def = dcp.DecisionEscalate
+		}
+		d.Decision = stricter(d.Decision, def)
+		d.Reasons = append(d.Reasons, fmt.Sprintf("no rule matches; default %s", def))
+	}
+	factors := ""
+	if in.Risk != nil && len(in.Risk.Factors) > 0 {
+		factors = " (" + in.Risk.String() + ")"
+	}
+	switch {
+	case d.RiskScore >= blockAt:
+		d.Decision = stricter(d.Decision, dcp.DecisionBlock)
+		d.Reasons = append(d.Reasons, fmt.Sprintf("risk score %.2f >= %.2f%s", d.RiskScore, blockAt, factors))
+	case d.RiskScore >= escalateAt:
+		d.Decision = stricter(d.Decision, dcp.DecisionEscalate)
+		d.Reasons = append(d.Reasons, fmt.Sprintf("risk score %.2f >= %.2f%s", d.RiskScore, escalateAt, factors))
+	}
+	if d.Decision != dcp.DecisionApprove {
+		if confirmation == nil {
+			confirmation = &dcp.RequiredConfirmation{Type: "human_approve", Fields: []string{"action_type", "target", "estimated_impact"}}
+		}
+		d.RequiredConfirmation = confirmation
+	}
+	return d
+}
+
+func (r *Rule) matches(in *Input) (bool, error) {
+	intent, passport := in.Intent, in.Passport
+	if len(r.ActionTypes) > 0 && !contains(r.ActionTypes, intent.ActionType) {
+		return false, nil
+	}
+	if len(r.Agents) > 0 && !contains(r.Agents, intent.AgentID) {
+		return false, nil
+	}
+	if len(r.Channels) > 0 {
+		ok := false
+		for _, c := range r.Channels {
+			ok = ok || c == intent.Target.Channel
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	if len(r.Impacts) > 0 {
+		ok := false
+		for _, i := range r.Impacts {
+			ok = ok || i == intent.EstimatedImpact
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	if len(r.Domains) > 0 {
+		if intent.Target.Domain == nil || !matchDomain(r.Domains, *intent.Target.Domain) {
+			return false, nil
+		}
+	}
+	if len(r.DataClasses) > 0 {
+		ok := false
+		for _, c := range intent.DataClasses {
+			ok = ok || contains(r.DataClasses, c)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	if len(r.URLs) > 0 {
+		if intent.Target.URL == nil || !matchURL(r.URLs, *intent.Target.URL) {
+			return false, nil
+		}
+	}
+	if len(r.Recipients) > 0 {
+		if intent.Target.To == nil || !matchRecipient(r.Recipients, *intent.Target.To) {
+			return false, nil
+		}
+	}
+	if len(r.RiskTiers) > 0 {
+		if passport == nil {
+			return false, nil
+		}
+		tier := passport.RiskTier
+		if tier == "" {
+			tier = dcp.RiskTierHigh
+		}
+		ok := false
+		for _, t := range r.RiskTiers {
+			ok = ok || t == tier
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	if len(r.Windows) > 0 {
+		ok := false
+		for i := range r.Windows {
+			ok = ok || r.Windows[i].contains(in.Now)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	if len(r.PrincipalJurisdictions) > 0 {
+		if in.Principal == nil || !inJurisdiction(r.PrincipalJurisdictions, in.Principal.Jurisdiction) {
+			return false, nil
+		}
+	}
+	if len(r.TargetCountries) > 0 || len(r.TargetOutside) > 0 {
+		country, ok := targetCountry(in)
+		if !ok {
+			return false, nil
+		}
+		if len(r.TargetCountries) > 0 && !inJurisdiction(r.TargetCountries, country) {
+			return false, nil
+		}
+		if len(r.TargetOutside) > 0 && inJurisdiction(r.TargetOutside, country) {
+			return false, nil
+		}
+	}
+	if r.When != "" {
+		return evalCondition(r.When, in)
+	}
+	return true, nil
+}
+
+// matchURL reports whether u has the scheme and host of one of the
+// prefixes and a path starting with its path.
+func matchURL(prefixes []string, u string) bool {
+	target, err := url.Parse(u)
+	if err != nil || target.Host == "" {
+		return false
+	}
+	for _, p := range prefixes {
+		prefix, err := url.Parse(p)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(prefix.Scheme, target.Scheme) && strings.EqualFold(prefix.Host, target.Host) &&
+			strings.HasPrefix(target.EscapedPath(), prefix.EscapedPath()) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchRecipient(patterns []string, to string) bool {
+	to = strings.ToLower(to)
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if domain, ok := strings.CutPrefix(p, "*@"); ok {
+			if strings.HasSuffix(to, "@"+domain) {
+				return true
+			}
+		} else if p == to {
+			return true
+		}
+	}
+	return false
+}
+
+func matchDomain(patterns []string, domain string) bool {
+	domain = strings.ToLower(domain)
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if suffix, ok := strings.CutPrefix(p, "*."); ok {
+			if strings.HasSuffix(domain, "."+suffix) {
+				return true
+			}
+		} else if p == domain {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,300 @@
+package pdp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+func testIntent(action string, channel dcp.Channel, domain string, impact dcp.EstimatedImpact, classes ...string) *dcp.Intent {
+	i := dcp.NewIntent("did:agent:agent123", "did:human:alice123", action, dcp.IntentTarget{Channel: channel}, classes, impact)
+	if domain != "" {
+		i.Target.Domain = &domain
+	}
+	return &i
+}
+
+func TestEvaluate(t *testing.T) {
+	ps := &pdp.PolicySet{
+		Default: dcp.DecisionBlock,
+		Rules: []pdp.Rule{
+			{Name: "browse", ActionTypes: []string{"browse"}, Channels: []dcp.Channel{dcp.ChannelWeb}, Decision: dcp.DecisionApprove},
+			{Name: "internal", Domains: []string{"*.example.com"}, Decision: dcp.DecisionApprove},
+			{Name: "pii", DataClasses: []string{"pii", "health"}, Decision: dcp.DecisionEscalate, Reason: "personal data needs review",
+				RequiredConfirmation: &dcp.RequiredConfirmation{Type: "human_approve", Fields: []string{"data_classes"}}},
+			{Name: "payments", Channels: []dcp.Channel{dcp.ChannelPayments}, Decision: dcp.DecisionApprove, RiskScore: 0.9},
+		},
+	}
+	if err := ps.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name         string
+		intent       *dcp.Intent
+		want         dcp.Decision
+		confirmation string // first confirmation field
+	}{
+		{"matching rule", testIntent("browse", dcp.ChannelWeb, "", dcp.ImpactLow), dcp.DecisionApprove, ""},
+		{"subdomain", testIntent("fetch", dcp.ChannelAPI, "api.example.com", dcp.ImpactLow), dcp.DecisionApprove, ""},
+		{"bare domain is not a subdomain", testIntent("fetch", dcp.ChannelAPI, "example.com", dcp.ImpactLow), dcp.DecisionBlock, "action_type"},
+		{"no rule matches", testIntent("delete", dcp.ChannelFilesystem, "", dcp.ImpactLow), dcp.DecisionBlock, "action_type"},
+		{"stricter rule wins", testIntent("browse", dcp.ChannelWeb, "", dcp.ImpactLow, "pii"), dcp.DecisionEscalate, "data_classes"},
+		{"impact threshold", testIntent("browse", dcp.ChannelWeb, "", dcp.ImpactHigh), dcp.DecisionEscalate, "action_type"},
+		{"rule risk score", testIntent("initiate_payment", dcp.ChannelPayments, "", dcp.ImpactLow), dcp.DecisionBlock, "action_type"},
+	} {
+		d := ps.Evaluate(tc.intent)
+		if d.Decision != tc.want || d.IntentID != tc.intent.IntentID || len(d.Reasons) == 0 {
+			t.Errorf("%s: %+v, want %s", tc.name, d, tc.want)
+			continue
+		}
+		if err := d.Validate(); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+		switch {
+		case tc.confirmation == "" && d.RequiredConfirmation != nil:
+			t.Errorf("%s: unexpected confirmation %+v", tc.name, d.RequiredConfirmation)
+		case tc.confirmation != "" && (d.RequiredConfirmation == nil || d.RequiredConfirmation.Fields[0] != tc.confirmation):
+			t.Errorf("%s: confirmation %+v, want %s", tc.name, d.RequiredConfirmation, tc.confirmation)
+		}
+	}
+
+	// An empty default escalates.
+	d := (&pdp.PolicySet{}).Evaluate(testIntent("browse", dcp.ChannelWeb, "", dcp.ImpactLow))
+	if d.Decision != dcp.DecisionEscalate || !strings.Contains(d.Reasons[0], "no rule matches") {
+		t.Fatalf("empty policy: %+v", d)
+	}
+}
+
+func TestEvaluateAt(t *testing.T) {
+	ps := &pdp.PolicySet{
+		Default: dcp.DecisionBlock,
+		Rules: []pdp.Rule{
+			{Name: "trusted api", URLs: []string{"https://API.example.com/v1/"}, RiskTiers: []dcp.RiskTier{dcp.RiskTierLow, dcp.RiskTierMedium}, Decision: dcp.DecisionApprove},
+			{Name: "colleagues", Recipients: []string{"*@example.com", "auditor@example.org"}, Decision: dcp.DecisionApprove},
+			{Name: "office hours", ActionTypes: []string{"deploy"}, Decision: dcp.DecisionApprove,
+				Windows: []pdp.TimeWindow{{Days: []string{"mon", "tue", "wed", "thu", "fri"}, From: "09:00", Until: "17:30", Timezone: "Europe/Berlin"}}},
+			{Name: "nightly", ActionTypes: []string{"backup"}, Decision: dcp.DecisionApprove, Windows: []pdp.TimeWindow{{From: "22:00", Until: "02:00"}}},
+		},
+	}
+	if err := ps.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	api := func(u string) *dcp.Intent {
+		i := testIntent("api_call", dcp.ChannelAPI, "", dcp.ImpactLow)
+		i.Target.URL = &u
+		return i
+	}
+	email := func(to string) *dcp.Intent {
+		i := testIntent("send_email", dcp.ChannelEmail, "", dcp.ImpactLow)
+		i.Target.To = &to
+		return i
+	}
+	passport := func(tier dcp.RiskTier) *dcp.AgentPassport {
+		return &dcp.AgentPassport{AgentID: "did:agent:agent123", RiskTier: tier, Capabilities: []string{"api_call"}}
+	}
+	// Wednesday 2026-03-04 10:00 UTC is 11:00 in Berlin.
+	wed := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		intent   *dcp.Intent
+		passport *dcp.AgentPassport
+		now      time.Time
+		want     dcp.Decision
+	}{
+		{"url and tier", api("https://api.example.com/v1/orders"), passport(dcp.RiskTierMedium), wed, dcp.DecisionApprove},
+		{"url outside the prefix", api("https://api.example.com/v2/orders"), passport(dcp.RiskTierLow), wed, dcp.DecisionBlock},
+		{"host sharing the prefix", api("https://api.example.com.evil.test/v1/"), passport(dcp.RiskTierLow), wed, dcp.DecisionBlock},
+		{"riskier tier", api("https://api.example.com/v1/orders"), passport(dcp.RiskTierHigh), wed, dcp.DecisionBlock},
+		{"passport without a tier", api("https://api.example.com/v1/orders"), passport(""), wed, dcp.DecisionBlock},
+		{"no passport", api("https://api.example.com/v1/orders"), nil, wed, dcp.DecisionBlock},
+		{"another agent's passport", api("https://api.example.com/v1/orders"), &dcp.AgentPassport{AgentID: "did:agent:other", RiskTier: dcp.RiskTierLow, Capabilities: []string{"api_call"}}, wed, dcp.DecisionBlock},
+		{"recipient domain", email("Bob@Example.com"), nil, wed, dcp.DecisionApprove},
+		{"recipient", email("auditor@example.org"), nil, wed, dcp.DecisionApprove},
+		{"other recipient", email("bob@example.org"), nil, wed, dcp.DecisionBlock},
+		{"office hours", testIntent("deploy", dcp.ChannelAPI, "", dcp.ImpactLow), nil, wed, dcp.DecisionApprove},
+		{"after hours in Berlin", testIntent("deploy", dcp.ChannelAPI, "", dcp.ImpactLow), nil, wed.Add(6*time.Hour + 30*time.Minute), dcp.DecisionBlock},
+		{"weekend", testIntent("deploy", dcp.ChannelAPI, "", dcp.ImpactLow), nil, wed.Add(72 * time.Hour), dcp.DecisionBlock},
+		{"window across midnight", testIntent("backup", dcp.ChannelAPI, "", dcp.ImpactLow), nil, wed.Add(15 * time.Hour), dcp.DecisionApprove},
+		{"window across midnight, before", testIntent("backup", dcp.ChannelAPI, "", dcp.ImpactLow), nil, wed.Add(11 * time.Hour), dcp.DecisionBlock},
+	} {
+		if d := ps.EvaluateAt(tc.intent, tc.passport, tc.now); d.Decision != tc.want || len(d.Reasons) == 0 {
+			t.Errorf("%s: %+v, want %s", tc.name, d, tc.want)
+		}
+	}
+}
+
+func TestEvaluateWhen(t *testing.T) {
+	ps := &pdp.PolicySet{
+		Default: dcp.DecisionApprove,
+		Rules: []pdp.Rule{
+			{Name: "risky payments", Decision: dcp.DecisionBlock,
+				When: `intent.target.channel == "payments" && passport.risk_tier != "low"`},
+			{Name: "foreign principal", Decision: dcp.DecisionEscalate,
+				When: `has(principal.jurisdiction) && !principal.jurisdiction.startsWith("EU")`},
+			{Name: "weekend deletes", Decision: dcp.DecisionBlock,
+				When: `intent.action_type == "delete" && now.getDayOfWeek() in [0, 6]`},
+		},
+	}
+	if err := ps.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	wed := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	eu := &dcp.ResponsiblePrincipalRecord{HumanID: "did:human:alice123", Jurisdiction: "EU-DE"}
+	us := &dcp.ResponsiblePrincipalRecord{HumanID: "did:human:alice123", Jurisdiction: "US-CA"}
+	for _, tc := range []struct {
+		name string
+		in   pdp.Input
+		want dcp.Decision
+	}{
+		{"low-risk payment", pdp.Input{Intent: testIntent("initiate_payment", dcp.ChannelPayments, "", dcp.ImpactLow),
+			Passport: &dcp.AgentPassport{AgentID: "did:agent:agent123", RiskTier: dcp.RiskTierLow, Capabilities: []string{"payments"}}, Principal: eu, Now: wed}, dcp.DecisionApprove},
+		{"medium-risk payment", pdp.Input{Intent: testIntent("initiate_payment", dcp.ChannelPayments, "", dcp.ImpactLow),
+			Passport: &dcp.AgentPassport{AgentID: "did:agent:agent123", RiskTier: dcp.RiskTierMedium, Capabilities: []string{"payments"}}, Principal: eu, Now: wed}, dcp.DecisionBlock},
+		{"foreign principal", pdp.Input{Intent: testIntent("browse", dcp.ChannelWeb, "", dcp.ImpactLow), Principal: us, Now: wed}, dcp.DecisionEscalate},
+		{"no principal", pdp.Input{Intent: testIntent("browse", dcp.ChannelWeb, "", dcp.ImpactLow), Now: wed}, dcp.DecisionApprove},
+		{"another principal's record", pdp.Input{Intent: testIntent("browse", dcp.ChannelWeb, "", dcp.ImpactLow),
+			Principal: &dcp.ResponsiblePrincipalRecord{HumanID: "did:human:bob"}, Now: wed}, dcp.DecisionBlock},
+		{"weekday delete", pdp.Input{Intent: testIntent("delete", dcp.ChannelFilesystem, "", dcp.ImpactLow), Now: wed}, dcp.DecisionApprove},
+		{"weekend delete", pdp.Input{Intent: testIntent("delete", dcp.ChannelFilesystem, "", dcp.ImpactLow), Now: wed.Add(72 * time.Hour)}, dcp.DecisionBlock},
+	} {
+		if d := ps.EvaluateInput(tc.in); d.Decision != tc.want {
+			t.Errorf("%s: %+v, want %s", tc.name, d, tc.want)
+		}
+	}
+
+	// A payment without a passport has no risk_tier to compare: the
+	// condition fails and the intent escalates.
+	d := ps.EvaluateInput(pdp.Input{Intent: testIntent("initiate_payment", dcp.ChannelPayments, "", dcp.ImpactLow), Now: wed})
+	if d.Decision != dcp.DecisionEscalate || !strings.Contains(strings.Join(d.Reasons, "; "), "risky payments: condition failed") {
+		t.Fatalf("failed condition: %+v", d)
+	}
+}
+
+func TestEvaluateWithin(t *testing.T) {
+	ps := &pdp.PolicySet{
+		Default: dcp.DecisionApprove,
+		Rules: []pdp.Rule{{Name: "outside the EEA", Decision: dcp.DecisionEscalate,
+			When: `!principal.jurisdiction.within("EEA")`}},
+	}
+	if err := ps.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for j, want := range map[string]dcp.Decision{
+		"DE-BY": dcp.DecisionApprove, "EU-FR": dcp.DecisionApprove, "NO": dcp.DecisionApprove,
+		"CH": dcp.DecisionEscalate, "EU-GDPR": dcp.DecisionEscalate,
+	} {
+		in := pdp.Input{Intent: testIntent("browse", dcp.ChannelWeb, "", dcp.ImpactLow),
+			Principal: &dcp.ResponsiblePrincipalRecord{HumanID: "did:human:alice123", Jurisdiction: j}}
+		if d := ps.EvaluateInput(in); d.Decision != want {
+			t.Errorf("%s: %+v, want %s", j, d, want)
+		}
+	}
+}
+
+func TestEvaluateDomainLists(t *testing.T) {
+	// Domain lists block whatever the rules say.
+	ps := &pdp.PolicySet{Default: dcp.DecisionApprove}
+	passport := &dcp.AgentPassport{AgentID: "did:agent:agent123", Capabilities: []string{"email"}, CapabilityDomains: map[string]dcp.DomainList{
+		"email": {Allow: []string{"*.example.com"}},
+	}}
+	in := pdp.Input{Intent: testIntent("send_email", dcp.ChannelEmail, "mail.example.org", dcp.ImpactLow, "none"), Passport: passport}
+	d := ps.EvaluateInput(in)
+	if d.Decision != dcp.DecisionBlock || !strings.Contains(d.Reasons[0], "mail.example.org") {
+		t.Fatalf("passport list: %+v", d)
+	}
+	in.Intent = testIntent("send_email", dcp.ChannelEmail, "mail.example.com", dcp.ImpactLow, "none")
+	if d := ps.EvaluateInput(in); d.Decision != dcp.DecisionApprove {
+		t.Fatalf("admitted: %+v", d)
+	}
+	in.Intent.Domains = &dcp.DomainList{Deny: []string{"mail.example.com"}}
+	if d := ps.EvaluateInput(in); d.Decision != dcp.DecisionBlock {
+		t.Fatalf("intent list: %+v", d)
+	}
+}
+
+func TestEvaluateCapabilities(t *testing.T) {
+	// Capabilities, matched with the grammar, block whatever the rules say.
+	ps := &pdp.PolicySet{Default: dcp.DecisionApprove}
+	passport := &dcp.AgentPassport{AgentID: "did:agent:agent123", Capabilities: []string{"email:send:*.example.com", "browse:*"}}
+	in := pdp.Input{Intent: testIntent("send_email", dcp.ChannelEmail, "mail.example.com", dcp.ImpactLow, "none"), Passport: passport}
+	if d := ps.EvaluateInput(in); d.Decision != dcp.DecisionApprove {
+		t.Fatalf("granted: %+v", d)
+	}
+	in.Intent = testIntent("send_email", dcp.ChannelEmail, "mail.example.org", dcp.ImpactLow, "none")
+	d := ps.EvaluateInput(in)
+	if d.Decision != dcp.DecisionBlock || !strings.Contains(d.Reasons[0], "no email:send capability for mail.example.org") {
+		t.Fatalf("other domain: %+v", d)
+	}
+	in.Intent = testIntent("initiate_payment", dcp.ChannelPayments, "", dcp.ImpactLow, "none")
+	if d := ps.EvaluateInput(in); d.Decision != dcp.DecisionBlock {
+		t.Fatalf("ungranted action: %+v", d)
+	}
+	// Every host the target names must be in scope, not just its domain.
+	in.Intent = testIntent("send_email", dcp.ChannelEmail, "mail.example.com", dcp.ImpactLow, "none")
+	victim := "victim@evil.com"
+	in.Intent.Target.To = &victim
+	if d := ps.EvaluateInput(in); d.Decision != dcp.DecisionBlock || !strings.Contains(d.Reasons[0], "evil.com") {
+		t.Fatalf("recipient outside the scope: %+v", d)
+	}
+	in.Intent = testIntent("browse", dcp.ChannelWeb, "www.example.com", dcp.ImpactLow, "none")
+	evil := "https://evil.com/"
+	in.Intent.Target.URL = &evil
+	in.Passport = &dcp.AgentPassport{AgentID: "did:agent:agent123", Capabilities: []string{"browse:read:*.example.com"}}
+	if d := ps.EvaluateInput(in); d.Decision != dcp.DecisionBlock {
+		t.Fatalf("URL outside the scope: %+v", d)
+	}
+	// Checks fail closed.
+	in.Intent = testIntent("deploy", dcp.ChannelAPI, "", dcp.ImpactLow, "none")
+	in.Passport = &dcp.AgentPassport{AgentID: "did:agent:agent123", Capabilities: []string{"*:*"}}
+	if d := ps.EvaluateInput(in); d.Decision != dcp.DecisionBlock {
+		t.Fatalf("unknown action type: %+v", d)
+	}
+	in.Intent = testIntent("browse", dcp.ChannelWeb, "www.example.com", dcp.ImpactLow, "none")
+	in.Passport = &dcp.AgentPassport{AgentID: "did:agent:agent123"}
+	if d := ps.EvaluateInput(in); d.Decision != dcp.DecisionBlock || !strings.Contains(d.Reasons[0], "grants no capabilities") {
+		t.Fatalf("passport without capabilities: %+v", d)
+	}
+}
+
+func TestEvaluateExpiry(t *testing.T) {
+	ps := &pdp.PolicySet{Default: dcp.DecisionApprove}
+	intent := testIntent("browse", dcp.ChannelWeb, "example.com", dcp.ImpactLow, "none")
+	if err := intent.SetTTL(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	declared, _ := intent.TimestampTime()
+	if d := ps.EvaluateAt(intent, nil, declared.Add(time.Hour)); d.Decision != dcp.DecisionApprove {
+		t.Fatalf("at expiry: %+v", d)
+	}
+	d := ps.EvaluateAt(intent, nil, declared.Add(time.Hour+time.Second))
+	if d.Decision != dcp.DecisionBlock || !strings.Contains(d.Reasons[0], "expired at") {
+		t.Fatalf("after expiry: %+v", d)
+	}
+}
+
+func TestPolicySetValidate(t *testing.T) {
+	for name, ps := range map[string]pdp.PolicySet{
+		"unknown decision": {Rules: []pdp.Rule{{Name: "x", Decision: "allow"}}},
+		"unknown default":  {Default: "deny"},
+		"risk score":       {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, RiskScore: 2}}},
+		"threshold":        {BlockAt: 1.5},
+		"confirmation":     {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionBlock, RequiredConfirmation: &dcp.RequiredConfirmation{Type: "sms"}}}},
+		"risk tier":        {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, RiskTiers: []dcp.RiskTier{"extreme"}}}},
+		"relative url":     {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, URLs: []string{"/v1/"}}}},
+		"window day":       {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, Windows: []pdp.TimeWindow{{Days: []string{"monday"}}}}}},
+		"window time":      {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, Windows: []pdp.TimeWindow{{From: "9am"}}}}},
+		"window timezone":  {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, Windows: []pdp.TimeWindow{{Timezone: "Mars/Olympus"}}}}},
+		"when syntax":      {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, When: `intent.action_type ==`}}},
+		"when variable":    {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, When: `bundle.verified`}}},
+		"when not bool":    {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, When: `now.getHours() + 1`}}},
+		"jurisdiction":     {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, PrincipalJurisdictions: []string{"Europe"}}}},
+		"target country":   {Rules: []pdp.Rule{{Name: "x", Decision: dcp.DecisionApprove, TargetOutside: []string{"US-CA"}}}},
+	} {
+		if err := ps.Validate(); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
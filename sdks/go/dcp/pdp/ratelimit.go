@@ -0,0 +1,147 @@
+package pdp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// RateLimitedReason starts the reason of every decision a rate limit
+// blocks, so callers can tell a throttled agent from a forbidden action.
+const RateLimitedReason = "rate_limited"
+
+// RateLimit caps how many actions each agent may declare intents for in a
+// window. The PDP counts every intent the limit applies to, including
+// those it blocks, a batch intent as its Batch.Count actions, and blocks
+// those over the limit.
+type RateLimit struct {
+	Name string `json:"name"`
+	// ActionTypes are the action types counted; empty means all.
+	ActionTypes []string `json:"action_types,omitempty"`
+	Limit       int      `json:"limit"`
+	// Window is a duration such as "1m" or "24h". Windows are fixed, the
+	// first starting at the Unix epoch.
+	Window string `json:"window"`
+}
+
+func (l *RateLimit) validate() error {
+	if l.Name == "" {
+		return errors.New("name is required")
+	}
+	if l.Limit <= 0 {
+		return errors.New("limit must be positive")
+	}
+	if w, err := time.ParseDuration(l.Window); err != nil || w <= 0 {
+		return fmt.Errorf("window %q is not a positive duration", l.Window)
+	}
+	return nil
+}
+
+func (l *RateLimit) applies(intent *dcp.Intent) bool {
+	return len(l.ActionTypes) == 0 || contains(l.ActionTypes, intent.ActionType)
+}
+
+// CounterStore holds the rate-limit counters. MemoryCounters keeps them in
+// the process; RedisCounters shares them between PDP replicas.
+type CounterStore interface {
+	// Incr adds n, which is positive, to the count of key and returns the
+	// new count. A key Incr creates expires after ttl.
+	Incr(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error)
+}
+
+// rateLimit counts intent against the policy set's rate limits at now and
+// returns the reason it is over one, or "".
+func (ps *PolicySet) rateLimit(ctx context.Context, counters CounterStore, intent *dcp.Intent, now time.Time) (string, error) {
+	over := ""
+	actions := int64(1)
+	if intent.Batch != nil && intent.Batch.Count > 1 {
+		actions = int64(intent.Batch.Count)
+	}
+	for i := range ps.RateLimits {
+		l := &ps.RateLimits[i]
+		if !l.applies(intent) {
+			continue
+		}
+		window, _ := time.ParseDuration(l.Window)
+		start := now.UnixNano() / int64(window)
+		key := l.Name + ":" + intent.AgentID + ":" + strconv.FormatInt(start, 10)
+		n, err := counters.Incr(ctx, key, actions, window)
+		if err != nil {
+			return "", fmt.Errorf("rate limit %s: %w", l.Name, err)
+		}
+		if n > int64(l.Limit) && over == "" {
+			over = fmt.Sprintf("%s: rate limit %s allows %d actions per %s", RateLimitedReason, l.Name, l.Limit, l.Window)
+		}
+	}
+	return over, nil
+}
+
+// rateLimited blocks d for the rate limit reason over.
+func rateLimited(d *dcp.PolicyDecision, over string) {
+	d.Decision, d.RequiredConfirmation = dcp.DecisionBlock, nil
+	d.Reasons = append([]string{over}, d.Reasons...)
+}
+
+// CounterReplayGuard is a dcp.ReplayGuard over a CounterStore: a key is
+// seen when its count passes one. Over a RedisCounters, the PDP replicas
+// behind a load balancer refuse an intent any of them has decided.
+type CounterReplayGuard struct {
+	Counters CounterStore
+}
+
+var _ dcp.ReplayGuard = CounterReplayGuard{}
+
+// Seen implements dcp.ReplayGuard.
+func (g CounterReplayGuard) Seen(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	n, err := g.Counters.Incr(ctx, "replay:"+key, 1, ttl)
+	if err != nil {
+		return false, err
+	}
+	return n > 1, nil
+}
+
+// MemoryCounters is a CounterStore in memory, for a single PDP. Create one
+// with NewMemoryCounters; it is safe for concurrent use.
+type MemoryCounters struct {
+	mu       sync.Mutex
+	counts   map[string]*counter
+	lastScan time.Time
+}
+
+type counter struct {
+	n       int64
+	expires time.Time
+}
+
+// NewMemoryCounters returns an empty MemoryCounters.
+func NewMemoryCounters() *MemoryCounters {
+	return &MemoryCounters{counts: map[string]*counter{}}
+}
+
+func (m *MemoryCounters) Incr(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Drop expired counters at most once a second, so memory follows the
+	// number of live windows rather than every window ever counted.
+	if now.Sub(m.lastScan) >= time.Second {
+		for k, c := range m.counts {
+			if !now.Before(c.expires) {
+				delete(m.counts, k)
+			}
+		}
+		m.lastScan = now
+	}
+	c := m.counts[key]
+	if c == nil || !now.Before(c.expires) {
+		c = &counter{expires: now.Add(ttl)}
+		m.counts[key] = c
+	}
+	c.n += n
+	return c.n, nil
+}
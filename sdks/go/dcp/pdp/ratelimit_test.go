@@ -0,0 +1,136 @@
+package pdp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+func TestMemoryCounters(t *testing.T) {
+	ctx := context.Background()
+	m := pdp.NewMemoryCounters()
+	for want := int64(1); want <= 3; want++ {
+		if n, err := m.Incr(ctx, "a", 1, time.Hour); err != nil || n != want {
+			t.Fatalf("Incr: %d, %v; want %d", n, err, want)
+		}
+	}
+	if n, _ := m.Incr(ctx, "b", 1, time.Hour); n != 1 {
+		t.Fatalf("other key: %d", n)
+	}
+	if n, _ := m.Incr(ctx, "a", 5, time.Hour); n != 8 {
+		t.Fatalf("Incr by 5: %d", n)
+	}
+	m.Incr(ctx, "short", 1, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if n, _ := m.Incr(ctx, "short", 1, time.Millisecond); n != 1 {
+		t.Fatalf("expired key: %d", n)
+	}
+}
+
+type failingCounters struct{}
+
+func (failingCounters) Incr(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestDecideRateLimits(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(readIntent(t), &intent); err != nil {
+		t.Fatal(err)
+	}
+	policy := &pdp.PolicySet{
+		Rules:      []pdp.Rule{{Name: "all", Decision: dcp.DecisionApprove}},
+		RateLimits: []pdp.RateLimit{{Name: "hourly", ActionTypes: []string{intent.ActionType}, Limit: 2, Window: "1h"}},
+	}
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if d, err := srv.Decide(ctx, &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+			t.Fatalf("intent %d: %+v, %v", i, d, err)
+		}
+	}
+	d, err := srv.Decide(ctx, &intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.PolicyDecision.Decision != dcp.DecisionBlock || !strings.HasPrefix(d.PolicyDecision.Reasons[0], pdp.RateLimitedReason+": rate limit hourly") {
+		t.Fatalf("over the limit: %+v", d.PolicyDecision)
+	}
+
+	// Other agents and action types have counts of their own.
+	other := intent
+	other.AgentID = "did:agent:other"
+	if d, err := srv.Decide(ctx, &other); err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("other agent: %+v, %v", d, err)
+	}
+	other = intent
+	other.ActionType = "api_call"
+	if d, err := srv.Decide(ctx, &other); err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("other action type: %+v, %v", d, err)
+	}
+
+	// The next window starts over.
+	now = now.Add(time.Hour)
+	if d, err := srv.Decide(ctx, &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("next window: %+v, %v", d, err)
+	}
+
+	// A batch intent counts as its actions: one of 2 then fills the
+	// window's last slot and is blocked.
+	now = now.Add(time.Hour)
+	batch := intent
+	batch.IntentID = "intent:batch"
+	batch.Batch = &dcp.IntentBatch{Count: 2, ManifestHash: "sha256:" + strings.Repeat("0", 64)}
+	if d, err := srv.Decide(ctx, &batch); err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatalf("batch within the limit: %+v, %v", d, err)
+	}
+	now = now.Add(time.Hour)
+	if d, err := srv.Decide(ctx, &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionApprove {
+		t.Fatal(err)
+	}
+	if d, err := srv.Decide(ctx, &batch); err != nil || d.PolicyDecision.Decision != dcp.DecisionBlock || !strings.Contains(d.PolicyDecision.Reasons[0], "allows 2 actions per 1h") {
+		t.Fatalf("batch over the limit: %+v, %v", d, err)
+	}
+
+	failing, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Counters: failingCounters{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	failing.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/decide", strings.NewReader(string(readIntent(t)))))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "connection refused") {
+		t.Fatalf("failing counters: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestRateLimitValidate(t *testing.T) {
+	for name, l := range map[string][]pdp.RateLimit{
+		"no name":     {{Limit: 1, Window: "1m"}},
+		"zero limit":  {{Name: "x", Window: "1m"}},
+		"bad window":  {{Name: "x", Limit: 1, Window: "a minute"}},
+		"zero window": {{Name: "x", Limit: 1, Window: "0s"}},
+		"twice":       {{Name: "x", Limit: 1, Window: "1m"}, {Name: "x", Limit: 2, Window: "1h"}},
+	} {
+		ps := pdp.PolicySet{RateLimits: l}
+		if err := ps.Validate(); err == nil || !strings.Contains(err.Error(), "rate_limits") {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package pdp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// incrScript adds to a counter and sets its expiry when it creates it, in
+// one step, so a PDP that fails in between cannot leave a counter that
+// never expires.
+const incrScript = `local n = redis.call('INCRBY', KEYS[1], ARGV[1]) if n == tonumber(ARGV[1]) then redis.call('PEXPIRE', KEYS[1], ARGV[2]) end return n`
+
+// RedisCounters is a CounterStore in Redis, so the PDP replicas behind a
+// load balancer enforce one limit between them. It speaks the Redis
+// protocol itself over a single connection, which it opens on first use
+// and reopens after an error; it is safe for concurrent use.
+type RedisCounters struct {
+	// Addr is the server's host:port.
+	Addr     string
+	Password string
+	DB       int
+	// Prefix is prepended to every key; empty means "dcp:ratelimit:".
+	Prefix string
+	// Timeout bounds each command; zero means 2 seconds.
+	Timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func (c *RedisCounters) Incr(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	prefix := c.Prefix
+	if prefix == "" {
+		prefix = "dcp:ratelimit:"
+	}
+	ms := ttl.Milliseconds()
+	if ms <= 0 {
+		ms = 1
+	}
+	reply, err := c.do(ctx, "EVAL", incrScript, "1", prefix+key, strconv.FormatInt(n, 10), strconv.FormatInt(ms, 10))
+	if err != nil {
+		return 0, fmt.Errorf("redis %s: %w", c.Addr, err)
+	}
+	count, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis %s: unexpected reply %v", c.Addr, reply)
+	}
+	return count, nil
+}
+
+// Close closes the connection, if open.
+func (c *RedisCounters) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.drop()
+}
+
+func (c *RedisCounters) drop() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.r = nil, nil
+	return err
+}
+
+func (c *RedisCounters) do(ctx context.Context, args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if c.conn == nil {
+		if err := c.dial(ctx, deadline); err != nil {
+			return nil, err
+		}
+	}
+	c.conn.SetDeadline(deadline)
+	reply, err := c.command(args...)
+	if err != nil {
+		var redisErr redisError
+		if !errors.As(err, &redisErr) {
+			// The connection is in an unknown state; start over next time.
+			c.drop()
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *RedisCounters) dial(ctx context.Context, deadline time.Time) error {
+	d := net.Dialer{Deadline: deadline}
+	conn, err := d.DialContext(ctx, "tcp", c.Addr)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(deadline)
+	c.conn, c.r = conn, bufio.NewReader(conn)
+	if c.Password != "" {
+		if _, err := c.command("AUTH", c.Password); err != nil {
+			c.drop()
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+	if c.DB != 0 {
+		if _, err := c.command("SELECT", strconv.Itoa(c.DB)); err != nil {
+			c.drop()
+			return fmt.Errorf("select %d: %w", c.DB, err)
+		}
+	}
+	return nil
+}
+
+// command sends args as a RESP array of bulk strings and reads the reply.
+func (c *RedisCounters) command(args ...string) (interface{}, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+// redisError is an error reply, after which the connection is still usable.
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// readReply reads one RESP reply: a status or bulk string, an integer, an
+// error or an array of those.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("bad bulk length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("bad array length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unknown reply type %q", line[0])
+}
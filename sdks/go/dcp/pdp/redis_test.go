@@ -0,0 +1,139 @@
+package pdp_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+// fakeRedis answers AUTH, SELECT and the counter script of RedisCounters
+// with counts kept in memory.
+type fakeRedis struct {
+	addr     string
+	password string
+
+	mu       sync.Mutex
+	counts   map[string]int64
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	f := &fakeRedis{addr: ln.Addr().String(), password: password, counts: map[string]int64{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, args[0])
+		switch {
+		case args[0] == "AUTH" && args[1] == f.password:
+			authed = true
+			io.WriteString(conn, "+OK\r\n")
+		case args[0] == "AUTH":
+			io.WriteString(conn, "-WRONGPASS invalid password\r\n")
+		case !authed:
+			io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+		case args[0] == "SELECT":
+			io.WriteString(conn, "+OK\r\n")
+		case args[0] == "EVAL" && len(args) == 6 && strings.Contains(args[1], "PEXPIRE"):
+			n, _ := strconv.ParseInt(args[4], 10, 64)
+			f.counts[args[3]] += n
+			fmt.Fprintf(conn, ":%d\r\n", f.counts[args[3]])
+		default:
+			io.WriteString(conn, "-ERR unknown command\r\n")
+		}
+		f.mu.Unlock()
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func TestRedisCounters(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRedis(t, "s3cret")
+	c := &pdp.RedisCounters{Addr: f.addr, Password: "s3cret", DB: 2}
+	defer c.Close()
+	for want := int64(1); want <= 3; want++ {
+		if n, err := c.Incr(ctx, "hourly:did:agent:a:1", 1, time.Hour); err != nil || n != want {
+			t.Fatalf("Incr: %d, %v; want %d", n, err, want)
+		}
+	}
+	f.mu.Lock()
+	if got := strings.Join(f.commands, " "); got != "AUTH SELECT EVAL EVAL EVAL" {
+		t.Fatalf("commands: %s", got)
+	}
+	if f.counts["dcp:ratelimit:hourly:did:agent:a:1"] != 3 {
+		t.Fatalf("counts: %v", f.counts)
+	}
+	f.mu.Unlock()
+
+	// Replicas sharing the server share the counts.
+	replica := &pdp.RedisCounters{Addr: f.addr, Password: "s3cret"}
+	defer replica.Close()
+	if n, err := replica.Incr(ctx, "hourly:did:agent:a:1", 1, time.Hour); err != nil || n != 4 {
+		t.Fatalf("replica: %d, %v", n, err)
+	}
+	if n, err := replica.Incr(ctx, "hourly:did:agent:a:1", 3, time.Hour); err != nil || n != 7 {
+		t.Fatalf("Incr by 3: %d, %v", n, err)
+	}
+
+	wrong := &pdp.RedisCounters{Addr: f.addr, Password: "guess"}
+	if _, err := wrong.Incr(ctx, "k", 1, time.Hour); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Fatalf("wrong password: %v", err)
+	}
+	down := &pdp.RedisCounters{Addr: "127.0.0.1:1", Timeout: time.Second}
+	if _, err := down.Incr(ctx, "k", 1, time.Hour); err == nil {
+		t.Fatal("Incr against no server succeeded")
+	}
+}
@@ -0,0 +1,261 @@
+package pdp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// RiskFactor is one input to an intent's risk score. ImpactRisk,
+// DataClassRisk, DomainReputation, TierRisk and DenialRate are provided;
+// other factors, such as a fraud service, implement the interface.
+type RiskFactor interface {
+	// Risk returns the factor's risk for in, between 0 and 1.
+	Risk(ctx context.Context, in Input) (float64, error)
+}
+
+// DecisionObserver is a RiskFactor that learns from the decisions made,
+// such as DenialRate. A Server reports every decision to the observers of
+// its scorer.
+type DecisionObserver interface {
+	Observe(agentID string, decision dcp.Decision, at time.Time)
+}
+
+// WeightedFactor is a factor and its weight in the score.
+type WeightedFactor struct {
+	// Name identifies the factor in RiskAssessment.Factors.
+	Name   string
+	Weight float64
+	Factor RiskFactor
+}
+
+// RiskScorer computes an intent's risk score as the weighted mean of its
+// factors. It replaces the score of the intent's estimated impact that a
+// policy set starts from.
+type RiskScorer struct {
+	Factors []WeightedFactor
+}
+
+// RiskAssessment is a score and the risk of each factor it was computed
+// from.
+type RiskAssessment struct {
+	Score   float64
+	Factors map[string]float64
+}
+
+// String lists the factors by name, such as "impact 0.40, data_classes
+// 0.80".
+func (a *RiskAssessment) String() string {
+	names := make([]string, 0, len(a.Factors))
+	for name := range a.Factors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %.2f", name, a.Factors[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Validate checks that every factor is named once and weighs more than
+// nothing.
+func (s *RiskScorer) Validate() error {
+	if len(s.Factors) == 0 {
+		return errors.New("risk scorer has no factors")
+	}
+	seen := map[string]bool{}
+	for i, f := range s.Factors {
+		switch {
+		case f.Name == "":
+			return fmt.Errorf("risk factor %d has no name", i)
+		case seen[f.Name]:
+			return fmt.Errorf("risk factor %s appears twice", f.Name)
+		case f.Weight <= 0:
+			return fmt.Errorf("risk factor %s: weight must be positive", f.Name)
+		case f.Factor == nil:
+			return fmt.Errorf("risk factor %s has no provider", f.Name)
+		}
+		seen[f.Name] = true
+	}
+	return nil
+}
+
+// Score assesses in. A factor that fails fails the assessment.
+func (s *RiskScorer) Score(ctx context.Context, in Input) (*RiskAssessment, error) {
+	if in.Now.IsZero() {
+		in.Now = time.Now()
+	}
+	a := &RiskAssessment{Factors: map[string]float64{}}
+	var sum, weights float64
+	for _, f := range s.Factors {
+		r, err := f.Factor.Risk(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("risk factor %s: %w", f.Name, err)
+		}
+		if r < 0 || r > 1 {
+			return nil, fmt.Errorf("risk factor %s: risk %v is not between 0 and 1", f.Name, r)
+		}
+		a.Factors[f.Name] = r
+		sum += f.Weight * r
+		weights += f.Weight
+	}
+	if weights > 0 {
+		a.Score = sum / weights
+	}
+	return a, nil
+}
+
+// Observe reports a decision to the factors that learn from decisions.
+func (s *RiskScorer) Observe(agentID string, decision dcp.Decision, at time.Time) {
+	for _, f := range s.Factors {
+		if o, ok := f.Factor.(DecisionObserver); ok {
+			o.Observe(agentID, decision, at)
+		}
+	}
+}
+
+// ImpactRisk is the risk of each estimated_impact; nil means 0.1, 0.4 and
+// 0.7 for low, medium and high, the score a policy set starts from without
+// a scorer.
+type ImpactRisk map[dcp.EstimatedImpact]float64
+
+func (m ImpactRisk) Risk(ctx context.Context, in Input) (float64, error) {
+	if m == nil {
+		m = impactRisk
+	}
+	return m[in.Intent.EstimatedImpact], nil
+}
+
+// DataClassRisk is the risk of the most sensitive data class the intent
+// touches. An intent touching none has no risk.
+type DataClassRisk struct {
+	Classes map[string]float64
+	// Default is the risk of classes not in Classes.
+	Default float64
+}
+
+func (d *DataClassRisk) Risk(ctx context.Context, in Input) (float64, error) {
+	risk := 0.0
+	for _, c := range in.Intent.DataClasses {
+		r, ok := d.Classes[c]
+		if !ok {
+			r = d.Default
+		}
+		if r > risk {
+			risk = r
+		}
+	}
+	return risk, nil
+}
+
+// DomainReputation is the risk of the intent's target domain: its domain,
+// the host of its URL or the domain of its recipient, looked up in a
+// reputation list.
+type DomainReputation struct {
+	// Domains maps domains, or *.example.com for subdomains, to their
+	// risk; a domain matching several patterns gets the highest.
+	Domains map[string]float64
+	// Unknown is the risk of domains not in the list.
+	Unknown float64
+}
+
+func (d *DomainReputation) Risk(ctx context.Context, in Input) (float64, error) {
+	domain := in.Intent.Target.Host()
+	if domain == "" {
+		return 0, nil
+	}
+	risk, found := 0.0, false
+	for pattern, r := range d.Domains {
+		if matchDomain([]string{pattern}, domain) && (!found || r > risk) {
+			risk, found = r, true
+		}
+	}
+	if !found {
+		return d.Unknown, nil
+	}
+	return risk, nil
+}
+
+// TierRisk is the risk of each passport risk tier; nil means 0, 0.5 and 1
+// for low, medium and high. An agent without a passport or tier is high.
+type TierRisk map[dcp.RiskTier]float64
+
+func (m TierRisk) Risk(ctx context.Context, in Input) (float64, error) {
+	if m == nil {
+		m = TierRisk{dcp.RiskTierLow: 0, dcp.RiskTierMedium: 0.5, dcp.RiskTierHigh: 1}
+	}
+	tier := dcp.RiskTierHigh
+	if in.Passport != nil && in.Passport.RiskTier != "" {
+		tier = in.Passport.RiskTier
+	}
+	return m[tier], nil
+}
+
+// DenialRate is the share of an agent's recent decisions that blocked an
+// intent, learned from the decisions it observes. Create one with
+// NewDenialRate; it is safe for concurrent use.
+type DenialRate struct {
+	window time.Duration
+	// MinDecisions is how many recent decisions an agent needs before its
+	// rate counts; fewer mean no risk.
+	MinDecisions int
+
+	mu        sync.Mutex
+	decisions map[string][]observed
+}
+
+type observed struct {
+	at      time.Time
+	blocked bool
+}
+
+// NewDenialRate returns a DenialRate over decisions in the last window.
+func NewDenialRate(window time.Duration) *DenialRate {
+	return &DenialRate{window: window, decisions: map[string][]observed{}}
+}
+
+func (d *DenialRate) Observe(agentID string, decision dcp.Decision, at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.decisions[agentID] = append(d.prune(agentID, at), observed{at: at, blocked: decision == dcp.DecisionBlock})
+}
+
+func (d *DenialRate) Risk(ctx context.Context, in Input) (float64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	recent := d.prune(in.Intent.AgentID, in.Now)
+	if len(recent) == 0 || len(recent) < d.MinDecisions {
+		return 0, nil
+	}
+	blocked := 0
+	for _, o := range recent {
+		if o.blocked {
+			blocked++
+		}
+	}
+	return float64(blocked) / float64(len(recent)), nil
+}
+
+// prune drops the agent's decisions older than the window before now and
+// returns the rest.
+func (d *DenialRate) prune(agentID string, now time.Time) []observed {
+	list := d.decisions[agentID]
+	i := 0
+	for i < len(list) && !list[i].at.After(now.Add(-d.window)) {
+		i++
+	}
+	list = list[i:]
+	if len(list) == 0 {
+		delete(d.decisions, agentID)
+	} else {
+		d.decisions[agentID] = list
+	}
+	return list
+}
@@ -0,0 +1,186 @@
+package pdp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+func TestRiskFactors(t *testing.T) {
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+	intent := testIntent("send", dcp.ChannelEmail, "", dcp.ImpactHigh)
+	intent.DataClasses = []string{"contact_info", "payment_data"}
+	intent.Target.To = str("Bob@Partner.Example.COM")
+
+	for _, tc := range []struct {
+		name   string
+		factor pdp.RiskFactor
+		in     pdp.Input
+		want   float64
+	}{
+		{"default impact", pdp.ImpactRisk(nil), pdp.Input{Intent: intent}, 0.7},
+		{"impact", pdp.ImpactRisk{dcp.ImpactHigh: 0.9}, pdp.Input{Intent: intent}, 0.9},
+		{"most sensitive class", &pdp.DataClassRisk{Classes: map[string]float64{"contact_info": 0.2, "payment_data": 0.8}}, pdp.Input{Intent: intent}, 0.8},
+		{"unlisted class", &pdp.DataClassRisk{Classes: map[string]float64{"contact_info": 0.2}, Default: 0.5}, pdp.Input{Intent: intent}, 0.5},
+		{"no classes", &pdp.DataClassRisk{Default: 0.5}, pdp.Input{Intent: testIntent("send", dcp.ChannelEmail, "", dcp.ImpactLow)}, 0},
+		{"recipient domain", &pdp.DomainReputation{Domains: map[string]float64{"*.example.com": 0.3, "partner.example.com": 0.1}}, pdp.Input{Intent: intent}, 0.3},
+		{"unknown domain", &pdp.DomainReputation{Domains: map[string]float64{"example.org": 0}, Unknown: 0.6}, pdp.Input{Intent: intent}, 0.6},
+		{"no target domain", &pdp.DomainReputation{Unknown: 0.6}, pdp.Input{Intent: testIntent("read", dcp.ChannelFilesystem, "", dcp.ImpactLow)}, 0},
+		{"low tier", pdp.TierRisk(nil), pdp.Input{Intent: intent, Passport: &dcp.AgentPassport{RiskTier: dcp.RiskTierLow}}, 0},
+		{"no passport", pdp.TierRisk(nil), pdp.Input{Intent: intent}, 1},
+		{"tier without passport tier", pdp.TierRisk{dcp.RiskTierHigh: 0.8}, pdp.Input{Intent: intent, Passport: &dcp.AgentPassport{}}, 0.8},
+	} {
+		got, err := tc.factor.Risk(ctx, tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("%s: %v, %v; want %v", tc.name, got, err, tc.want)
+		}
+	}
+
+	u := "https://Shop.Example.net/cart"
+	intent.Target.To, intent.Target.URL = nil, &u
+	if got, _ := (&pdp.DomainReputation{Domains: map[string]float64{"shop.example.net": 0.4}}).Risk(ctx, pdp.Input{Intent: intent}); got != 0.4 {
+		t.Fatalf("URL host: %v", got)
+	}
+}
+
+func TestDenialRate(t *testing.T) {
+	ctx := context.Background()
+	rate := pdp.NewDenialRate(time.Hour)
+	rate.MinDecisions = 3
+	t0 := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	intent := testIntent("send", dcp.ChannelEmail, "", dcp.ImpactLow)
+	risk := func(now time.Time) float64 {
+		r, err := rate.Risk(ctx, pdp.Input{Intent: intent, Now: now})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	rate.Observe(intent.AgentID, dcp.DecisionBlock, t0)
+	rate.Observe(intent.AgentID, dcp.DecisionApprove, t0.Add(10*time.Minute))
+	if r := risk(t0.Add(15 * time.Minute)); r != 0 {
+		t.Fatalf("below MinDecisions: %v", r)
+	}
+	rate.Observe(intent.AgentID, dcp.DecisionBlock, t0.Add(20*time.Minute))
+	rate.Observe(intent.AgentID, dcp.DecisionEscalate, t0.Add(30*time.Minute))
+	rate.Observe("did:agent:other", dcp.DecisionBlock, t0.Add(30*time.Minute))
+	if r := risk(t0.Add(40 * time.Minute)); r != 0.5 {
+		t.Fatalf("2 of 4 blocked: %v", r)
+	}
+	// The first block leaves the window.
+	if r := risk(t0.Add(65 * time.Minute)); math.Abs(r-1.0/3) > 1e-9 {
+		t.Fatalf("1 of 3 blocked: %v", r)
+	}
+	if r := risk(t0.Add(3 * time.Hour)); r != 0 {
+		t.Fatalf("no recent decisions: %v", r)
+	}
+}
+
+type failingFactor struct{}
+
+func (failingFactor) Risk(ctx context.Context, in pdp.Input) (float64, error) {
+	return 0, errors.New("reputation service unreachable")
+}
+
+type constFactor float64
+
+func (c constFactor) Risk(ctx context.Context, in pdp.Input) (float64, error) {
+	return float64(c), nil
+}
+
+func TestRiskScorer(t *testing.T) {
+	ctx := context.Background()
+	s := &pdp.RiskScorer{Factors: []pdp.WeightedFactor{
+		{Name: "impact", Weight: 1, Factor: pdp.ImpactRisk(nil)},
+		{Name: "tier", Weight: 3, Factor: pdp.TierRisk(nil)},
+	}}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	a, err := s.Score(ctx, pdp.Input{Intent: testIntent("send", dcp.ChannelEmail, "", dcp.ImpactMedium),
+		Passport: &dcp.AgentPassport{RiskTier: dcp.RiskTierMedium}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(a.Score-(0.4+3*0.5)/4) > 1e-9 || a.String() != "impact 0.40, tier 0.50" {
+		t.Fatalf("assessment: %+v %s", a, a)
+	}
+
+	for name, bad := range map[string]*pdp.RiskScorer{
+		"no factors":  {},
+		"no name":     {Factors: []pdp.WeightedFactor{{Weight: 1, Factor: constFactor(0)}}},
+		"twice":       {Factors: []pdp.WeightedFactor{{Name: "a", Weight: 1, Factor: constFactor(0)}, {Name: "a", Weight: 1, Factor: constFactor(0)}}},
+		"zero weight": {Factors: []pdp.WeightedFactor{{Name: "a", Factor: constFactor(0)}}},
+		"no provider": {Factors: []pdp.WeightedFactor{{Name: "a", Weight: 1}}},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+	out := &pdp.RiskScorer{Factors: []pdp.WeightedFactor{{Name: "a", Weight: 1, Factor: constFactor(1.5)}}}
+	if _, err := out.Score(ctx, pdp.Input{Intent: testIntent("send", dcp.ChannelEmail, "", dcp.ImpactLow)}); err == nil {
+		t.Fatal("risk above 1 accepted")
+	}
+}
+
+func TestDecideRisk(t *testing.T) {
+	kp, _ := dcp.GenerateKeypair()
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(readIntent(t), &intent); err != nil {
+		t.Fatal(err)
+	}
+	denials := pdp.NewDenialRate(time.Hour)
+	scorer := &pdp.RiskScorer{Factors: []pdp.WeightedFactor{
+		{Name: "domain", Weight: 1, Factor: &pdp.DomainReputation{Domains: map[string]float64{"example.com": 1}}},
+		{Name: "denials", Weight: 1, Factor: denials},
+	}}
+	policy := &pdp.PolicySet{Rules: []pdp.Rule{{Name: "email", Channels: []dcp.Channel{dcp.ChannelEmail}, Decision: dcp.DecisionApprove}}}
+	srv, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Risk: scorer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 0.5 escalates, and the reason names the factors.
+	d, err := srv.Decide(context.Background(), &intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.PolicyDecision.Decision != dcp.DecisionEscalate || d.PolicyDecision.RiskScore != 0.5 ||
+		!strings.Contains(strings.Join(d.PolicyDecision.Reasons, "; "), "(denials 0.00, domain 1.00)") {
+		t.Fatalf("decision: %+v", d.PolicyDecision)
+	}
+	// Once most of the agent's decisions are blocks, it is blocked too.
+	for i := 0; i < 3; i++ {
+		denials.Observe(intent.AgentID, dcp.DecisionBlock, time.Now())
+	}
+	if d, err = srv.Decide(context.Background(), &intent); err != nil || d.PolicyDecision.Decision != dcp.DecisionBlock {
+		t.Fatalf("after denials: %+v, %v", d, err)
+	}
+
+	failing, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Risk: &pdp.RiskScorer{Factors: []pdp.WeightedFactor{{Name: "fraud", Weight: 1, Factor: failingFactor{}}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	failing.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/decide", strings.NewReader(string(readIntent(t)))))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("failing factor: %d %s", rec.Code, rec.Body)
+	}
+	if _, err := pdp.New(pdp.Config{Policy: policy, Signer: signer, Risk: &pdp.RiskScorer{}}); err == nil {
+		t.Fatal("scorer without factors accepted")
+	}
+}
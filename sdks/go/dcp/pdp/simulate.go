@@ -0,0 +1,95 @@
+package pdp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// HistoricalIntent is an intent decided in the past: the input it was
+// evaluated with, at the time it was, and the decision it got.
+type HistoricalIntent struct {
+	Input
+	Decision dcp.Decision
+}
+
+// DecisionChange is an intent a proposed policy would decide differently.
+type DecisionChange struct {
+	IntentID   string       `json:"intent_id"`
+	AgentID    string       `json:"agent_id"`
+	ActionType string       `json:"action_type"`
+	At         time.Time    `json:"at"`
+	Before     dcp.Decision `json:"before"`
+	// After is the decision under the proposed policy, with its reasons.
+	After dcp.PolicyDecision `json:"after"`
+}
+
+// SimulationReport is the outcome of replaying intents against a proposed
+// policy.
+type SimulationReport struct {
+	Intents int              `json:"intents"`
+	Changed []DecisionChange `json:"changed"`
+	// Transitions counts the changes by "before->after", such as
+	// "approve->block".
+	Transitions map[string]int `json:"transitions"`
+}
+
+// Simulate replays history against the proposed policy set ps and reports
+// the intents it would decide differently, so a policy's effect can be
+// assessed before it is rolled out. Intents are replayed in time order at
+// the time they were decided, so time windows and rate limits apply as
+// they would have; the rate limits count the replayed intents only.
+// Inputs should carry the passport and principal record they were decided
+// with, and the risk assessment if a scorer was used.
+func Simulate(ps *PolicySet, history []HistoricalIntent) (*SimulationReport, error) {
+	if err := ps.Validate(); err != nil {
+		return nil, fmt.Errorf("simulate: %w", err)
+	}
+	order := make([]int, len(history))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return history[order[a]].Now.Before(history[order[b]].Now) })
+
+	report := &SimulationReport{Intents: len(history), Changed: []DecisionChange{}, Transitions: map[string]int{}}
+	counters := replayCounters{}
+	for _, i := range order {
+		h := history[i]
+		if h.Intent == nil {
+			return nil, fmt.Errorf("simulate: history[%d] has no intent", i)
+		}
+		if h.Now.IsZero() {
+			return nil, fmt.Errorf("simulate: intent %s has no decision time", h.Intent.IntentID)
+		}
+		d := ps.EvaluateInput(h.Input)
+		over, _ := ps.rateLimit(context.Background(), counters, h.Intent, h.Now)
+		if over != "" {
+			rateLimited(&d, over)
+		}
+		if d.Decision == h.Decision {
+			continue
+		}
+		report.Changed = append(report.Changed, DecisionChange{
+			IntentID:   h.Intent.IntentID,
+			AgentID:    h.Intent.AgentID,
+			ActionType: h.Intent.ActionType,
+			At:         h.Now,
+			Before:     h.Decision,
+			After:      d,
+		})
+		report.Transitions[string(h.Decision)+"->"+string(d.Decision)]++
+	}
+	return report, nil
+}
+
+// replayCounters counts replayed intents. The keys of rate limits name
+// their window, so counts never need to expire within a replay.
+type replayCounters map[string]int64
+
+func (c replayCounters) Incr(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	c[key] += n
+	return c[key], nil
+}
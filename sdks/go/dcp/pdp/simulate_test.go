@@ -0,0 +1,64 @@
+package pdp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+func TestSimulate(t *testing.T) {
+	t0 := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	at := func(intent *dcp.Intent, minutes int, d dcp.Decision) pdp.HistoricalIntent {
+		return pdp.HistoricalIntent{Input: pdp.Input{Intent: intent, Now: t0.Add(time.Duration(minutes) * time.Minute)}, Decision: d}
+	}
+	email := testIntent("send_email", dcp.ChannelEmail, "", dcp.ImpactLow)
+	payment := testIntent("initiate_payment", dcp.ChannelPayments, "", dcp.ImpactHigh)
+	payment.IntentID = "intent:payment"
+	history := []pdp.HistoricalIntent{
+		// Out of order: the replay sorts by time.
+		at(email, 20, dcp.DecisionApprove),
+		at(email, 0, dcp.DecisionApprove),
+		at(email, 10, dcp.DecisionApprove),
+		at(payment, 5, dcp.DecisionApprove),
+	}
+	proposed := &pdp.PolicySet{
+		Rules: []pdp.Rule{
+			{Name: "email", Channels: []dcp.Channel{dcp.ChannelEmail}, Decision: dcp.DecisionApprove},
+			{Name: "payments", Channels: []dcp.Channel{dcp.ChannelPayments}, Decision: dcp.DecisionEscalate},
+		},
+		RateLimits: []pdp.RateLimit{{Name: "email", ActionTypes: []string{"send_email"}, Limit: 2, Window: "1h"}},
+	}
+	report, err := pdp.Simulate(proposed, history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Intents != 4 || len(report.Changed) != 2 {
+		t.Fatalf("report: %+v", report)
+	}
+	if c := report.Changed[0]; c.IntentID != "intent:payment" || c.Before != dcp.DecisionApprove || c.After.Decision != dcp.DecisionEscalate {
+		t.Fatalf("payment change: %+v", c)
+	}
+	if c := report.Changed[1]; !c.At.Equal(t0.Add(20*time.Minute)) || c.After.Decision != dcp.DecisionBlock ||
+		!strings.HasPrefix(c.After.Reasons[0], pdp.RateLimitedReason) {
+		t.Fatalf("rate-limited change: %+v", c)
+	}
+	if report.Transitions["approve->escalate"] != 1 || report.Transitions["approve->block"] != 1 {
+		t.Fatalf("transitions: %v", report.Transitions)
+	}
+
+	// Replaying under the policy that made the decisions changes nothing.
+	same := &pdp.PolicySet{Default: dcp.DecisionApprove, EscalateAt: 1, BlockAt: 1}
+	if report, err := pdp.Simulate(same, history); err != nil || len(report.Changed) != 0 {
+		t.Fatalf("same policy: %+v, %v", report, err)
+	}
+
+	if _, err := pdp.Simulate(&pdp.PolicySet{Default: "deny"}, history); err == nil {
+		t.Fatal("invalid policy simulated")
+	}
+	if _, err := pdp.Simulate(proposed, []pdp.HistoricalIntent{{Input: pdp.Input{Intent: email}}}); err == nil {
+		t.Fatal("intent without a decision time simulated")
+	}
+}
@@ -0,0 +1,325 @@
+package qes
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	encasn1 "encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+var (
+	oidData                 = encasn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData           = encasn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidContentType          = encasn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest        = encasn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime          = encasn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidSigningCertificateV2 = encasn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+	oidSHA256               = encasn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidRSAEncryption        = encasn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSHA256WithRSA        = encasn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidECDSAWithSHA256      = encasn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	// oidOCSPResponse marks OCSP responses among the revocation values
+	// (RFC 5940).
+	oidOCSPResponse = encasn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 16, 2}
+)
+
+var (
+	tag0 = asn1.Tag(0).Constructed().ContextSpecific()
+	tag1 = asn1.Tag(1).Constructed().ContextSpecific()
+)
+
+// signedData is a detached CMS SignedData (RFC 5652) with one signer. The
+// signer info is kept as received, so validation data can be added without
+// touching what was signed.
+type signedData struct {
+	certs      [][]byte
+	crls       [][]byte
+	ocsps      [][]byte
+	signerInfo []byte
+}
+
+// signerInfo is the parsed SignerInfo of a signedData.
+type signerInfo struct {
+	issuer      []byte
+	serial      *big.Int
+	digestAlg   encasn1.ObjectIdentifier
+	signedAttrs []byte
+	attrs       map[string]cryptobyte.String
+	sigAlg      encasn1.ObjectIdentifier
+	signature   []byte
+}
+
+// signCMS returns the detached CMS SignedData of content by key, whose
+// certificate is chain[0], with the CAdES baseline signed attributes.
+func signCMS(content []byte, chain []*x509.Certificate, key crypto.Signer, now time.Time) (*signedData, error) {
+	cert := chain[0]
+	var sigAlg encasn1.ObjectIdentifier
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		sigAlg = oidSHA256WithRSA
+	case *ecdsa.PublicKey:
+		sigAlg = oidECDSAWithSHA256
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", pub)
+	}
+	if k, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !k.Equal(key.Public()) {
+		return nil, errors.New("key is not the certificate's")
+	}
+
+	digest := sha256.Sum256(content)
+	certHash := sha256.Sum256(cert.Raw)
+	attrs := [][]byte{
+		attribute(oidContentType, func(b *cryptobyte.Builder) { b.AddASN1ObjectIdentifier(oidData) }),
+		attribute(oidSigningTime, func(b *cryptobyte.Builder) { b.AddASN1UTCTime(now.UTC().Truncate(time.Second)) }),
+		attribute(oidMessageDigest, func(b *cryptobyte.Builder) { b.AddASN1OctetString(digest[:]) }),
+		attribute(oidSigningCertificateV2, func(b *cryptobyte.Builder) {
+			b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+						b.AddASN1OctetString(certHash[:])
+					})
+				})
+			})
+		}),
+	}
+	sortDER(attrs)
+	var set cryptobyte.Builder
+	set.AddASN1(asn1.SET, func(b *cryptobyte.Builder) { addAll(b, attrs) })
+	signed, err := set.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(signed)
+	sig, err := key.Sign(rand.Reader, h[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	var si cryptobyte.Builder
+	si.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(1)
+		b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddBytes(cert.RawIssuer)
+			b.AddASN1BigInt(cert.SerialNumber)
+		})
+		addAlgorithm(b, oidSHA256)
+		b.AddASN1(tag0, func(b *cryptobyte.Builder) { addAll(b, attrs) })
+		addAlgorithm(b, sigAlg)
+		b.AddASN1OctetString(sig)
+	})
+	info, err := si.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	sd := &signedData{signerInfo: info}
+	for _, c := range chain {
+		sd.certs = append(sd.certs, c.Raw)
+	}
+	return sd, nil
+}
+
+// attribute returns the DER of an Attribute of type oid with the one value
+// value adds.
+func attribute(oid encasn1.ObjectIdentifier, value func(*cryptobyte.Builder)) []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(oid)
+		b.AddASN1(asn1.SET, value)
+	})
+	return b.BytesOrPanic()
+}
+
+// addAlgorithm adds an AlgorithmIdentifier, with NULL parameters for RSA
+// as RFC 4055 requires and none otherwise.
+func addAlgorithm(b *cryptobyte.Builder, oid encasn1.ObjectIdentifier) {
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(oid)
+		if oid.Equal(oidSHA256WithRSA) {
+			b.AddASN1NULL()
+		}
+	})
+}
+
+// sortDER sorts the elements of a SET OF into DER order.
+func sortDER(elems [][]byte) {
+	sort.Slice(elems, func(i, j int) bool { return bytes.Compare(elems[i], elems[j]) < 0 })
+}
+
+func addAll(b *cryptobyte.Builder, elems [][]byte) {
+	for _, e := range elems {
+		b.AddBytes(e)
+	}
+}
+
+// marshal returns the DER ContentInfo of sd.
+func (sd *signedData) marshal() ([]byte, error) {
+	var revocations [][]byte
+	revocations = append(revocations, sd.crls...)
+	for _, r := range sd.ocsps {
+		var b cryptobyte.Builder
+		b.AddASN1(tag1, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier(oidOCSPResponse)
+			b.AddBytes(r)
+		})
+		revocations = append(revocations, b.BytesOrPanic())
+	}
+	certs := append([][]byte(nil), sd.certs...)
+	sortDER(certs)
+	sortDER(revocations)
+	version := int64(1)
+	if len(sd.ocsps) > 0 {
+		version = 5
+	}
+
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(oidSignedData)
+		b.AddASN1(tag0, func(b *cryptobyte.Builder) {
+			b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1Int64(version)
+				b.AddASN1(asn1.SET, func(b *cryptobyte.Builder) { addAlgorithm(b, oidSHA256) })
+				b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) { b.AddASN1ObjectIdentifier(oidData) })
+				if len(certs) > 0 {
+					b.AddASN1(tag0, func(b *cryptobyte.Builder) { addAll(b, certs) })
+				}
+				if len(revocations) > 0 {
+					b.AddASN1(tag1, func(b *cryptobyte.Builder) { addAll(b, revocations) })
+				}
+				b.AddASN1(asn1.SET, func(b *cryptobyte.Builder) { b.AddBytes(sd.signerInfo) })
+			})
+		})
+	})
+	return b.Bytes()
+}
+
+// parseCMS parses a DER ContentInfo holding a detached SignedData with one
+// signer.
+func parseCMS(der []byte) (*signedData, error) {
+	input := cryptobyte.String(der)
+	var ci, content, sd, digestAlgs, encap cryptobyte.String
+	var ct, ect encasn1.ObjectIdentifier
+	var version int64
+	if !input.ReadASN1(&ci, asn1.SEQUENCE) || !input.Empty() ||
+		!ci.ReadASN1ObjectIdentifier(&ct) || !ct.Equal(oidSignedData) ||
+		!ci.ReadASN1(&content, tag0) ||
+		!content.ReadASN1(&sd, asn1.SEQUENCE) ||
+		!sd.ReadASN1Integer(&version) ||
+		!sd.ReadASN1(&digestAlgs, asn1.SET) ||
+		!sd.ReadASN1(&encap, asn1.SEQUENCE) ||
+		!encap.ReadASN1ObjectIdentifier(&ect) {
+		return nil, errors.New("not a CMS SignedData")
+	}
+	if !ect.Equal(oidData) || !encap.Empty() {
+		return nil, errors.New("not a detached signature of data")
+	}
+
+	out := &signedData{}
+	var certs cryptobyte.String
+	var present bool
+	if !sd.ReadOptionalASN1(&certs, &present, tag0) {
+		return nil, errors.New("malformed certificates")
+	}
+	for !certs.Empty() {
+		var c cryptobyte.String
+		if !certs.ReadASN1Element(&c, asn1.SEQUENCE) {
+			return nil, errors.New("unsupported certificate choice")
+		}
+		out.certs = append(out.certs, c)
+	}
+	var revocations cryptobyte.String
+	if !sd.ReadOptionalASN1(&revocations, &present, tag1) {
+		return nil, errors.New("malformed revocation values")
+	}
+	for !revocations.Empty() {
+		var r, other cryptobyte.String
+		var format encasn1.ObjectIdentifier
+		switch {
+		case revocations.PeekASN1Tag(asn1.SEQUENCE):
+			revocations.ReadASN1Element(&r, asn1.SEQUENCE)
+			out.crls = append(out.crls, r)
+		case revocations.ReadASN1(&other, tag1) && other.ReadASN1ObjectIdentifier(&format):
+			if format.Equal(oidOCSPResponse) {
+				out.ocsps = append(out.ocsps, other)
+			}
+		default:
+			return nil, errors.New("malformed revocation values")
+		}
+	}
+
+	var infos cryptobyte.String
+	var info cryptobyte.String
+	if !sd.ReadASN1(&infos, asn1.SET) || !infos.ReadASN1Element(&info, asn1.SEQUENCE) || !infos.Empty() {
+		return nil, errors.New("a single signer info is required")
+	}
+	out.signerInfo = info
+	return out, nil
+}
+
+// parseSignerInfo parses sd's signer info, which must have signed
+// attributes.
+func (sd *signedData) parseSignerInfo() (*signerInfo, error) {
+	input := cryptobyte.String(sd.signerInfo)
+	var info, sid, digestAlg, attrs, sigAlg cryptobyte.String
+	var version int64
+	si := &signerInfo{serial: new(big.Int), attrs: map[string]cryptobyte.String{}}
+	var issuer cryptobyte.String
+	if !input.ReadASN1(&info, asn1.SEQUENCE) ||
+		!info.ReadASN1Integer(&version) || version != 1 ||
+		!info.ReadASN1(&sid, asn1.SEQUENCE) ||
+		!sid.ReadASN1Element(&issuer, asn1.SEQUENCE) ||
+		!sid.ReadASN1Integer(si.serial) ||
+		!info.ReadASN1(&digestAlg, asn1.SEQUENCE) ||
+		!digestAlg.ReadASN1ObjectIdentifier(&si.digestAlg) ||
+		!info.ReadASN1Element(&attrs, tag0) ||
+		!info.ReadASN1(&sigAlg, asn1.SEQUENCE) ||
+		!sigAlg.ReadASN1ObjectIdentifier(&si.sigAlg) ||
+		!info.ReadASN1Bytes(&si.signature, asn1.OCTET_STRING) {
+		return nil, errors.New("malformed signer info: an issuer and serial number and signed attributes are required")
+	}
+	si.issuer = issuer
+
+	// The signature covers the signed attributes with their SET OF tag.
+	si.signedAttrs = append([]byte(nil), attrs...)
+	si.signedAttrs[0] = 0x31
+	var set cryptobyte.String
+	attrs.ReadASN1(&set, tag0)
+	for !set.Empty() {
+		var attr, values, value cryptobyte.String
+		var oid encasn1.ObjectIdentifier
+		if !set.ReadASN1(&attr, asn1.SEQUENCE) ||
+			!attr.ReadASN1ObjectIdentifier(&oid) ||
+			!attr.ReadASN1(&values, asn1.SET) ||
+			!values.ReadAnyASN1Element(&value, nil) || !values.Empty() {
+			return nil, errors.New("malformed signed attribute")
+		}
+		if _, dup := si.attrs[oid.String()]; dup {
+			return nil, fmt.Errorf("signed attribute %s repeats", oid)
+		}
+		si.attrs[oid.String()] = value
+	}
+	return si, nil
+}
+
+// signatureAlgorithm returns the x509 algorithm of a SHA-256 signer info
+// signed under sigAlg.
+func signatureAlgorithm(sigAlg encasn1.ObjectIdentifier) (x509.SignatureAlgorithm, error) {
+	switch {
+	case sigAlg.Equal(oidSHA256WithRSA), sigAlg.Equal(oidRSAEncryption):
+		return x509.SHA256WithRSA, nil
+	case sigAlg.Equal(oidECDSAWithSHA256):
+		return x509.ECDSAWithSHA256, nil
+	}
+	return 0, fmt.Errorf("unsupported signature algorithm %s", sigAlg)
+}
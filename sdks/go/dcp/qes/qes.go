@@ -0,0 +1,147 @@
+// Package qes signs responsible principal records, the human bindings of
+// DCP-01, with eIDAS qualified certificates. A Signature is a detached
+// CAdES signature, a CMS SignedData (RFC 5652) over the canonical record,
+// with the signed attributes of CAdES baseline signatures (ETSI EN 319
+// 122-1): content type, message digest, signing time and the signing
+// certificate. It carries the certificate chain and, once
+// AddValidationData has embedded them as CAdES B-LT does, the CRLs and
+// OCSP responses that show the chain unrevoked when it signed, so the
+// signature can be validated long after the certificates expire.
+//
+// The principal record schema is closed, so a Signature travels beside
+// the record, like the proofs of the issuer. A Verifier checks it against
+// the trust anchors of the EU trusted lists it accepts: the certificate
+// must be qualified, name the principal, and for a qualified electronic
+// signature proper keep its key on a qualified signature creation device.
+//
+// The signing time is the signer's claim: signature time-stamps (CAdES
+// B-T) are not produced or checked.
+package qes
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+var (
+	// ErrInvalidSignature reports a signature that is malformed or does not
+	// verify over the record.
+	ErrInvalidSignature = errors.New("invalid qualified signature")
+	// ErrNotQualified reports a signing certificate that is not a qualified
+	// certificate for the signature required, or does not chain to a
+	// trusted root.
+	ErrNotQualified = errors.New("certificate is not qualified")
+	// ErrNameMismatch reports a signing certificate whose subject is not
+	// the principal of the record.
+	ErrNameMismatch = errors.New("certificate does not name the principal")
+	// ErrRevoked reports a certificate of the chain revoked before the
+	// signing time.
+	ErrRevoked = errors.New("certificate revoked")
+)
+
+// FormatCAdES is the Format of detached CAdES signatures.
+const FormatCAdES = "cades-detached"
+
+// Signature is a qualified signature of a responsible principal record.
+type Signature struct {
+	HumanID string `json:"human_id"`
+	// RecordHash is "sha256:" and the hash of the canonical record, the
+	// message digest the CMS signs.
+	RecordHash string `json:"record_hash"`
+	Format     string `json:"format"`
+	// CMS is the base64 DER of the CMS SignedData, certificates and
+	// revocation values included.
+	CMS string `json:"cms"`
+}
+
+// Sign returns the qualified signature by key of rec, as published with
+// its DCP signature, made at now. chain[0] is the signing certificate,
+// followed by the intermediates up to the trust anchor. key is typically
+// backed by the signer's QSCD, a smart card or remote signing service, and
+// must be an RSA or ECDSA key.
+func Sign(rec *dcp.ResponsiblePrincipalRecord, chain []*x509.Certificate, key crypto.Signer, now time.Time) (*Signature, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("qes: a signing certificate is required")
+	}
+	canon, err := dcp.Canonicalize(rec)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := signCMS([]byte(canon), chain, key, now)
+	if err != nil {
+		return nil, fmt.Errorf("qes: sign record of %s: %v", rec.HumanID, err)
+	}
+	der, err := sd.marshal()
+	if err != nil {
+		return nil, err
+	}
+	h, err := dcp.HashObject(rec)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{
+		HumanID:    rec.HumanID,
+		RecordHash: "sha256:" + h,
+		Format:     FormatCAdES,
+		CMS:        base64.StdEncoding.EncodeToString(der),
+	}, nil
+}
+
+// AddValidationData embeds certs, DER CRLs and DER OCSP responses in s,
+// skipping those already there. It leaves what was signed untouched: it is
+// how a signature made at B-B level is augmented to carry its long-term
+// validation data, by the signer or later by anyone.
+func (s *Signature) AddValidationData(certs []*x509.Certificate, crls, ocspResponses [][]byte) error {
+	sd, err := s.signedData()
+	if err != nil {
+		return err
+	}
+	for _, c := range certs {
+		sd.certs = appendNew(sd.certs, c.Raw)
+	}
+	for _, c := range crls {
+		if _, err := x509.ParseRevocationList(c); err != nil {
+			return fmt.Errorf("qes: CRL: %v", err)
+		}
+		sd.crls = appendNew(sd.crls, c)
+	}
+	for _, r := range ocspResponses {
+		sd.ocsps = appendNew(sd.ocsps, r)
+	}
+	der, err := sd.marshal()
+	if err != nil {
+		return err
+	}
+	s.CMS = base64.StdEncoding.EncodeToString(der)
+	return nil
+}
+
+func appendNew(set [][]byte, v []byte) [][]byte {
+	for _, e := range set {
+		if string(e) == string(v) {
+			return set
+		}
+	}
+	return append(set, v)
+}
+
+func (s *Signature) signedData() (*signedData, error) {
+	if s.Format != FormatCAdES {
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidSignature, s.Format)
+	}
+	der, err := base64.StdEncoding.DecodeString(s.CMS)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cms is not base64", ErrInvalidSignature)
+	}
+	sd, err := parseCMS(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return sd, nil
+}
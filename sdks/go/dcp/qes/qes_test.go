@@ -0,0 +1,244 @@
+package qes_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/qes"
+)
+
+var (
+	qcCompliance = asn1.ObjectIdentifier{0, 4, 0, 1862, 1, 1}
+	qcSSCD       = asn1.ObjectIdentifier{0, 4, 0, 1862, 1, 4}
+	qcType       = asn1.ObjectIdentifier{0, 4, 0, 1862, 1, 6}
+	qcTypeESign  = asn1.ObjectIdentifier{0, 4, 0, 1862, 1, 6, 1}
+	qcTypeESeal  = asn1.ObjectIdentifier{0, 4, 0, 1862, 1, 6, 2}
+)
+
+type qcStatement struct {
+	ID   asn1.ObjectIdentifier
+	Info asn1.RawValue `asn1:"optional"`
+}
+
+// qcExtension returns the QCStatements extension of the statements ids,
+// with QcType types if any.
+func qcExtension(t *testing.T, ids []asn1.ObjectIdentifier, types ...asn1.ObjectIdentifier) pkix.Extension {
+	t.Helper()
+	var stmts []qcStatement
+	for _, id := range ids {
+		stmts = append(stmts, qcStatement{ID: id})
+	}
+	if len(types) > 0 {
+		info, _ := asn1.Marshal(types)
+		stmts = append(stmts, qcStatement{ID: qcType, Info: asn1.RawValue{FullBytes: info}})
+	}
+	der, err := asn1.Marshal(stmts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkix.Extension{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 3}, Value: der}
+}
+
+// ca is a certificate authority of a test PKI.
+type ca struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+var serial int64
+
+// issue returns the certificate of tmpl for pub, issued by parent; a root
+// is its own parent.
+func issue(t *testing.T, tmpl *x509.Certificate, pub crypto.PublicKey, parent *ca) *x509.Certificate {
+	t.Helper()
+	serial++
+	tmpl.SerialNumber = big.NewInt(serial)
+	if tmpl.NotBefore.IsZero() {
+		tmpl.NotBefore = time.Now().Add(-time.Hour)
+		tmpl.NotAfter = time.Now().Add(365 * 24 * time.Hour)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent.cert, pub, parent.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// newCA returns a root, named name, and a qualified CA under it.
+func newCA(t *testing.T, name string) (root, qualified *ca) {
+	t.Helper()
+	rootKey := newKey(t)
+	tmpl := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name + " Root"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	root = &ca{key: rootKey}
+	root.cert = issue(t, tmpl, rootKey.Public(), &ca{cert: tmpl, key: rootKey})
+	qKey := newKey(t)
+	qualified = &ca{key: qKey, cert: issue(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name + " Qualified CA", Country: []string{"DE"}},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, qKey.Public(), root)}
+	return root, qualified
+}
+
+// signerCert returns a certificate for the key pub of subject, issued by
+// parent, with ext as extra extensions.
+func signerCert(t *testing.T, subject pkix.Name, pub crypto.PublicKey, parent *ca, ext ...pkix.Extension) *x509.Certificate {
+	return issue(t, &x509.Certificate{
+		Subject:         subject,
+		KeyUsage:        x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+		ExtraExtensions: ext,
+	}, pub, parent)
+}
+
+var alice = pkix.Name{
+	CommonName: "Alice Example",
+	Country:    []string{"DE"},
+	ExtraNames: []pkix.AttributeTypeAndValue{
+		{Type: asn1.ObjectIdentifier{2, 5, 4, 42}, Value: "Alice"},
+		{Type: asn1.ObjectIdentifier{2, 5, 4, 4}, Value: "Example"},
+	},
+}
+
+// newRecord returns a signed record of a principal.
+func newRecord(t *testing.T, legalName string, entity dcp.EntityType, jurisdiction string) *dcp.ResponsiblePrincipalRecord {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := dcp.NewResponsiblePrincipalRecord(legalName, entity, jurisdiction)
+	if err := rec.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	return &rec
+}
+
+func TestSign(t *testing.T) {
+	_, qualified := newCA(t, "Test")
+	key := newKey(t)
+	cert := signerCert(t, alice, key.Public(), qualified, qcExtension(t, []asn1.ObjectIdentifier{qcCompliance, qcSSCD}, qcTypeESign))
+	rec := newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "DE")
+
+	sig, err := qes.Sign(rec, []*x509.Certificate{cert, qualified.cert}, key, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, _ := dcp.HashObject(rec)
+	if sig.HumanID != rec.HumanID || sig.RecordHash != "sha256:"+h || sig.Format != qes.FormatCAdES || sig.CMS == "" {
+		t.Fatalf("signature %+v", sig)
+	}
+
+	if _, err := qes.Sign(rec, []*x509.Certificate{cert}, newKey(t), time.Now()); err == nil {
+		t.Fatal("signed with a key that is not the certificate's")
+	}
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := qes.Sign(rec, []*x509.Certificate{cert}, edKey, time.Now()); err == nil {
+		t.Fatal("signed with an Ed25519 key")
+	}
+	if _, err := qes.Sign(rec, nil, key, time.Now()); err == nil {
+		t.Fatal("signed without a certificate")
+	}
+}
+
+func TestAddValidationData(t *testing.T) {
+	root, qualified := newCA(t, "Test")
+	key := newKey(t)
+	cert := signerCert(t, alice, key.Public(), qualified, qcExtension(t, []asn1.ObjectIdentifier{qcCompliance}))
+	rec := newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "DE")
+	sig, err := qes.Sign(rec, []*x509.Certificate{cert}, key, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The intermediate is missing until the validation data adds it.
+	v := &qes.Verifier{Roots: pool(root.cert)}
+	if _, err := v.Verify(sig, rec); !errors.Is(err, qes.ErrNotQualified) {
+		t.Fatalf("chain without its intermediate: %v", err)
+	}
+	crl := newCRL(t, qualified, time.Now())
+	if err := sig.AddValidationData([]*x509.Certificate{qualified.cert}, [][]byte{crl}, nil); err != nil {
+		t.Fatal(err)
+	}
+	cms := sig.CMS
+	if err := sig.AddValidationData([]*x509.Certificate{qualified.cert, cert}, [][]byte{crl}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sig.CMS != cms {
+		t.Fatal("validation data already embedded was added again")
+	}
+	if _, err := v.Verify(sig, rec); err != nil {
+		t.Fatal(err)
+	}
+	if err := sig.AddValidationData(nil, [][]byte{[]byte("not a CRL")}, nil); err == nil {
+		t.Fatal("embedded a malformed CRL")
+	}
+
+	bad := *sig
+	bad.Format = "xades"
+	if err := bad.AddValidationData(nil, nil, nil); !errors.Is(err, qes.ErrInvalidSignature) {
+		t.Fatalf("unknown format: %v", err)
+	}
+	bad = *sig
+	bad.CMS = "bm90IENNUw=="
+	if err := bad.AddValidationData(nil, nil, nil); !errors.Is(err, qes.ErrInvalidSignature) {
+		t.Fatalf("malformed CMS: %v", err)
+	}
+}
+
+func pool(certs ...*x509.Certificate) *x509.CertPool {
+	p := x509.NewCertPool()
+	for _, c := range certs {
+		p.AddCert(c)
+	}
+	return p
+}
+
+// newCRL returns a CRL of issuer, issued at now, revoking revoked.
+func newCRL(t *testing.T, issuer *ca, now time.Time, revoked ...x509.RevocationListEntry) []byte {
+	t.Helper()
+	serial++
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(serial),
+		ThisUpdate:                now,
+		NextUpdate:                now.Add(24 * time.Hour),
+		RevokedCertificateEntries: revoked,
+	}, issuer.cert, issuer.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
@@ -0,0 +1,380 @@
+package qes
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	encasn1 "encoding/asn1"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// The QCStatements of ETSI EN 319 412-5 and the RFC 3739 extension that
+// carries them.
+var (
+	oidQCStatements = encasn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 3}
+	oidQcCompliance = encasn1.ObjectIdentifier{0, 4, 0, 1862, 1, 1}
+	oidQcSSCD       = encasn1.ObjectIdentifier{0, 4, 0, 1862, 1, 4}
+	oidQcType       = encasn1.ObjectIdentifier{0, 4, 0, 1862, 1, 6}
+	oidQcTypeESign  = encasn1.ObjectIdentifier{0, 4, 0, 1862, 1, 6, 1}
+	oidQcTypeESeal  = encasn1.ObjectIdentifier{0, 4, 0, 1862, 1, 6, 2}
+
+	oidGivenName = encasn1.ObjectIdentifier{2, 5, 4, 42}
+	oidSurname   = encasn1.ObjectIdentifier{2, 5, 4, 4}
+)
+
+// Verifier checks qualified signatures of principal records.
+type Verifier struct {
+	// Roots are the trust anchors signing certificates must chain to: the
+	// qualified CAs of the EU trusted lists the verifier accepts. Required.
+	Roots *x509.CertPool
+	// RequireQSCD admits only qualified electronic signatures and seals,
+	// whose certificate attests that the key is on a qualified signature
+	// creation device. Without it, advanced signatures with a qualified
+	// certificate are admitted too.
+	RequireQSCD bool
+	// RequireRevocationData admits only signatures whose embedded
+	// validation data shows every certificate of the chain but the root
+	// unrevoked at the signing time.
+	RequireRevocationData bool
+	// Now is the clock the signing time is checked against; nil means
+	// time.Now.
+	Now func() time.Time
+}
+
+// Result describes a valid qualified signature.
+type Result struct {
+	// Certificate is the signing certificate.
+	Certificate *x509.Certificate
+	// Chain runs from the signing certificate to a root of the Verifier.
+	Chain       []*x509.Certificate
+	SigningTime time.Time
+	// QSCD reports that the certificate attests the key is on a qualified
+	// signature creation device, making the signature a qualified one.
+	QSCD bool
+	// RevocationChecked reports that the validation data showed the chain
+	// unrevoked at the signing time.
+	RevocationChecked bool
+}
+
+// Verify checks that s is a signature of rec, as published, by a qualified
+// certificate naming the principal, that chains to a root of v and was
+// valid and unrevoked at the signing time. The certificates may have
+// expired since.
+func (v *Verifier) Verify(s *Signature, rec *dcp.ResponsiblePrincipalRecord) (*Result, error) {
+	if v.Roots == nil {
+		return nil, errors.New("qes: trust anchors are required")
+	}
+	if s.HumanID != rec.HumanID {
+		return nil, fmt.Errorf("%w: signature is for %s, not %s", ErrInvalidSignature, s.HumanID, rec.HumanID)
+	}
+	if h, err := dcp.HashObject(rec); err != nil || s.RecordHash != "sha256:"+h {
+		return nil, fmt.Errorf("%w: record_hash is not the hash of the record", ErrInvalidSignature)
+	}
+	sd, err := s.signedData()
+	if err != nil {
+		return nil, err
+	}
+	si, err := sd.parseSignerInfo()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	var signer *x509.Certificate
+	intermediates := x509.NewCertPool()
+	for _, der := range sd.certs {
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("%w: certificate: %v", ErrInvalidSignature, err)
+		}
+		if bytes.Equal(c.RawIssuer, si.issuer) && c.SerialNumber.Cmp(si.serial) == 0 {
+			signer = c
+		}
+		intermediates.AddCert(c)
+	}
+	if signer == nil {
+		return nil, fmt.Errorf("%w: the signing certificate is not embedded", ErrInvalidSignature)
+	}
+
+	canon, err := dcp.Canonicalize(rec)
+	if err != nil {
+		return nil, err
+	}
+	signingTime, err := checkAttributes(si, []byte(canon), signer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	alg, err := signatureAlgorithm(si.sigAlg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if err := signer.CheckSignature(alg, si.signedAttrs, si.signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	if signingTime.After(now) {
+		return nil, fmt.Errorf("%w: signed in the future, at %s", ErrInvalidSignature, dcp.FormatTime(signingTime))
+	}
+	if issued, err := rec.IssuedAtTime(); err == nil && signingTime.Before(issued) {
+		return nil, fmt.Errorf("%w: signed at %s, before the record was issued", ErrInvalidSignature, dcp.FormatTime(signingTime))
+	}
+
+	chains, err := signer.Verify(x509.VerifyOptions{
+		Roots:         v.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   signingTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotQualified, err)
+	}
+	if signer.KeyUsage&x509.KeyUsageContentCommitment == 0 {
+		return nil, fmt.Errorf("%w: key usage lacks non-repudiation", ErrNotQualified)
+	}
+	qc, err := parseQCStatements(signer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotQualified, err)
+	}
+	if !qc.compliance {
+		return nil, fmt.Errorf("%w: no QcCompliance statement", ErrNotQualified)
+	}
+	if !qc.admits(rec.EntityType) {
+		return nil, fmt.Errorf("%w: QcType does not admit signatures for a %s", ErrNotQualified, rec.EntityType)
+	}
+	if v.RequireQSCD && !qc.sscd {
+		return nil, fmt.Errorf("%w: the key is not on a QSCD", ErrNotQualified)
+	}
+	if err := checkName(signer, rec); err != nil {
+		return nil, err
+	}
+
+	res := &Result{Certificate: signer, Chain: chains[0], SigningTime: signingTime, QSCD: qc.sscd}
+	if res.RevocationChecked, err = checkRevocation(sd, res.Chain, signingTime); err != nil {
+		return nil, err
+	}
+	if v.RequireRevocationData && !res.RevocationChecked {
+		return nil, errors.New("qes: the validation data does not show the chain unrevoked at the signing time")
+	}
+	return res, nil
+}
+
+// checkAttributes checks the CAdES signed attributes of si against the
+// signed content and the signing certificate, and returns the signing
+// time.
+func checkAttributes(si *signerInfo, content []byte, signer *x509.Certificate) (time.Time, error) {
+	if !si.digestAlg.Equal(oidSHA256) {
+		return time.Time{}, fmt.Errorf("unsupported digest algorithm %s", si.digestAlg)
+	}
+	var ct encasn1.ObjectIdentifier
+	value := si.attrs[oidContentType.String()]
+	if !value.ReadASN1ObjectIdentifier(&ct) || !ct.Equal(oidData) {
+		return time.Time{}, errors.New("content type is not data")
+	}
+	var digest []byte
+	value = si.attrs[oidMessageDigest.String()]
+	want := sha256.Sum256(content)
+	if !value.ReadASN1Bytes(&digest, asn1.OCTET_STRING) || !bytes.Equal(digest, want[:]) {
+		return time.Time{}, errors.New("message digest is not the digest of the record")
+	}
+
+	// The first ESSCertIDv2 identifies the signing certificate.
+	var attr, certs, id, hashAlg cryptobyte.String
+	var certHash []byte
+	var hasAlg bool
+	value = si.attrs[oidSigningCertificateV2.String()]
+	if !value.ReadASN1(&attr, asn1.SEQUENCE) || !attr.ReadASN1(&certs, asn1.SEQUENCE) ||
+		!certs.ReadASN1(&id, asn1.SEQUENCE) ||
+		!id.ReadOptionalASN1(&hashAlg, &hasAlg, asn1.SEQUENCE) ||
+		!id.ReadASN1Bytes(&certHash, asn1.OCTET_STRING) {
+		return time.Time{}, errors.New("no signing certificate attribute")
+	}
+	if hasAlg {
+		var oid encasn1.ObjectIdentifier
+		if !hashAlg.ReadASN1ObjectIdentifier(&oid) || !oid.Equal(oidSHA256) {
+			return time.Time{}, errors.New("unsupported signing certificate hash")
+		}
+	}
+	if h := sha256.Sum256(signer.Raw); !bytes.Equal(certHash, h[:]) {
+		return time.Time{}, errors.New("signing certificate attribute names another certificate")
+	}
+
+	var t time.Time
+	value = si.attrs[oidSigningTime.String()]
+	ok := false
+	if value.PeekASN1Tag(asn1.UTCTime) {
+		ok = value.ReadASN1UTCTime(&t)
+	} else {
+		ok = value.ReadASN1GeneralizedTime(&t)
+	}
+	if !ok {
+		return time.Time{}, errors.New("no signing time")
+	}
+	return t, nil
+}
+
+// qcStatements are the QCStatements of a certificate this package knows.
+type qcStatements struct {
+	compliance bool
+	sscd       bool
+	types      []encasn1.ObjectIdentifier
+}
+
+func parseQCStatements(cert *x509.Certificate) (*qcStatements, error) {
+	qc := &qcStatements{}
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidQCStatements) {
+			continue
+		}
+		input := cryptobyte.String(ext.Value)
+		var stmts cryptobyte.String
+		if !input.ReadASN1(&stmts, asn1.SEQUENCE) || !input.Empty() {
+			return nil, errors.New("malformed QCStatements")
+		}
+		for !stmts.Empty() {
+			var stmt cryptobyte.String
+			var id encasn1.ObjectIdentifier
+			if !stmts.ReadASN1(&stmt, asn1.SEQUENCE) || !stmt.ReadASN1ObjectIdentifier(&id) {
+				return nil, errors.New("malformed QCStatement")
+			}
+			switch {
+			case id.Equal(oidQcCompliance):
+				qc.compliance = true
+			case id.Equal(oidQcSSCD):
+				qc.sscd = true
+			case id.Equal(oidQcType):
+				var types cryptobyte.String
+				if !stmt.ReadASN1(&types, asn1.SEQUENCE) {
+					return nil, errors.New("malformed QcType")
+				}
+				for !types.Empty() {
+					var t encasn1.ObjectIdentifier
+					if !types.ReadASN1ObjectIdentifier(&t) {
+						return nil, errors.New("malformed QcType")
+					}
+					qc.types = append(qc.types, t)
+				}
+			}
+		}
+	}
+	return qc, nil
+}
+
+// admits reports whether the certificate types allow signing for a
+// principal of entity type e: a person signs, an organization signs or
+// seals. A certificate without QcType is one for signatures.
+func (qc *qcStatements) admits(e dcp.EntityType) bool {
+	if len(qc.types) == 0 {
+		return true
+	}
+	for _, t := range qc.types {
+		if t.Equal(oidQcTypeESign) || (e.IsOrganization() && t.Equal(oidQcTypeESeal)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkName checks that cert's subject is the principal of rec: for a
+// natural person its common name, or given name and surname, is the legal
+// name; for an organization its organization is. A subject country must
+// be that of the jurisdiction.
+func checkName(cert *x509.Certificate, rec *dcp.ResponsiblePrincipalRecord) error {
+	var names []string
+	if rec.EntityType.IsOrganization() {
+		names = cert.Subject.Organization
+	} else {
+		names = []string{cert.Subject.CommonName}
+		var given, surname string
+		for _, n := range cert.Subject.Names {
+			switch {
+			case n.Type.Equal(oidGivenName):
+				given = fmt.Sprint(n.Value)
+			case n.Type.Equal(oidSurname):
+				surname = fmt.Sprint(n.Value)
+			}
+		}
+		if given != "" && surname != "" {
+			names = append(names, given+" "+surname)
+		}
+	}
+	named := false
+	for _, n := range names {
+		named = named || fold(n) == fold(rec.LegalName)
+	}
+	if !named {
+		return fmt.Errorf("%w: %q is not %q", ErrNameMismatch, cert.Subject.String(), rec.LegalName)
+	}
+	if len(cert.Subject.Country) > 0 {
+		country, _, _ := strings.Cut(rec.Jurisdiction, "-")
+		for _, c := range cert.Subject.Country {
+			if strings.EqualFold(c, country) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: certificate is for %v, not jurisdiction %s", ErrNameMismatch, cert.Subject.Country, rec.Jurisdiction)
+	}
+	return nil
+}
+
+// fold folds case and runs of white space in a name.
+func fold(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// checkRevocation checks the certificates of chain but the root against
+// the CRLs and OCSP responses of sd, and reports whether they show each
+// unrevoked at t. Validation data that expired before t is ignored.
+func checkRevocation(sd *signedData, chain []*x509.Certificate, t time.Time) (bool, error) {
+	var crls []*x509.RevocationList
+	for _, der := range sd.crls {
+		crl, err := x509.ParseRevocationList(der)
+		if err != nil {
+			return false, fmt.Errorf("%w: CRL: %v", ErrInvalidSignature, err)
+		}
+		crls = append(crls, crl)
+	}
+	covered := true
+	for i := 0; i+1 < len(chain); i++ {
+		cert, issuer := chain[i], chain[i+1]
+		known := false
+		for _, crl := range crls {
+			if !bytes.Equal(crl.RawIssuer, issuer.RawSubject) || crl.CheckSignatureFrom(issuer) != nil ||
+				(!crl.NextUpdate.IsZero() && crl.NextUpdate.Before(t)) {
+				continue
+			}
+			for _, e := range crl.RevokedCertificateEntries {
+				if e.SerialNumber.Cmp(cert.SerialNumber) == 0 && !e.RevocationTime.After(t) {
+					return false, fmt.Errorf("%w: %s at %s", ErrRevoked, cert.Subject, dcp.FormatTime(e.RevocationTime))
+				}
+			}
+			known = true
+		}
+		for _, der := range sd.ocsps {
+			resp, err := ocsp.ParseResponseForCert(der, cert, issuer)
+			if err != nil || (!resp.NextUpdate.IsZero() && resp.NextUpdate.Before(t)) {
+				continue
+			}
+			switch resp.Status {
+			case ocsp.Revoked:
+				if !resp.RevokedAt.After(t) {
+					return false, fmt.Errorf("%w: %s at %s", ErrRevoked, cert.Subject, dcp.FormatTime(resp.RevokedAt))
+				}
+				known = true
+			case ocsp.Good:
+				known = true
+			}
+		}
+		covered = covered && known
+	}
+	return covered, nil
+}
@@ -0,0 +1,226 @@
+package qes_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/qes"
+)
+
+func TestVerify(t *testing.T) {
+	root, qualified := newCA(t, "Test")
+	key := newKey(t)
+	cert := signerCert(t, alice, key.Public(), qualified, qcExtension(t, []asn1.ObjectIdentifier{qcCompliance, qcSSCD}, qcTypeESign))
+	chain := []*x509.Certificate{cert, qualified.cert}
+	rec := newRecord(t, "Alice  example", dcp.EntityNaturalPerson, "DE-BY")
+	v := &qes.Verifier{Roots: pool(root.cert), RequireQSCD: true}
+
+	sig, err := qes.Sign(rec, chain, key, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := v.Verify(sig, rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Certificate.Equal(cert) || len(res.Chain) != 3 || !res.QSCD || res.RevocationChecked {
+		t.Fatalf("result %+v", res)
+	}
+
+	// Tampering with the record breaks the hash, then the signed digest.
+	tampered := *rec
+	tampered.LegalName = "Mallory Example"
+	if _, err := v.Verify(sig, &tampered); !errors.Is(err, qes.ErrInvalidSignature) {
+		t.Fatalf("tampered record: %v", err)
+	}
+	rehashed := *sig
+	h, _ := dcp.HashObject(&tampered)
+	rehashed.RecordHash = "sha256:" + h
+	if _, err := v.Verify(&rehashed, &tampered); !errors.Is(err, qes.ErrInvalidSignature) {
+		t.Fatalf("tampered record, rehashed: %v", err)
+	}
+
+	// Another trusted list, or none of this CA, does not admit it.
+	otherRoot, _ := newCA(t, "Other")
+	if _, err := (&qes.Verifier{Roots: pool(otherRoot.cert)}).Verify(sig, rec); !errors.Is(err, qes.ErrNotQualified) {
+		t.Fatalf("untrusted root: %v", err)
+	}
+	if _, err := (&qes.Verifier{}).Verify(sig, rec); err == nil {
+		t.Fatal("verified without trust anchors")
+	}
+
+	// Signatures before the record was issued, or from the future, are
+	// refused.
+	if early, err := qes.Sign(rec, chain, key, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	} else if _, err := v.Verify(early, rec); !errors.Is(err, qes.ErrInvalidSignature) {
+		t.Fatalf("signed before issuance: %v", err)
+	}
+	late, _ := qes.Sign(rec, chain, key, time.Now().Add(time.Hour))
+	if _, err := v.Verify(late, rec); !errors.Is(err, qes.ErrInvalidSignature) {
+		t.Fatalf("signed in the future: %v", err)
+	}
+
+	// The signature outlives the certificates.
+	later := &qes.Verifier{Roots: pool(root.cert), Now: func() time.Time { return time.Now().Add(5 * 365 * 24 * time.Hour) }}
+	if _, err := later.Verify(sig, rec); err != nil {
+		t.Fatalf("after the certificates expired: %v", err)
+	}
+}
+
+func TestVerifyRSA(t *testing.T) {
+	root, qualified := newCA(t, "Test")
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := signerCert(t, alice, key.Public(), qualified, qcExtension(t, []asn1.ObjectIdentifier{qcCompliance, qcSSCD}))
+	rec := newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "DE")
+	sig, err := qes.Sign(rec, []*x509.Certificate{cert, qualified.cert}, key, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&qes.Verifier{Roots: pool(root.cert), RequireQSCD: true}).Verify(sig, rec); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyCertificate(t *testing.T) {
+	root, qualified := newCA(t, "Test")
+	v := &qes.Verifier{Roots: pool(root.cert)}
+	org := pkix.Name{CommonName: "Example Seal", Organization: []string{"Example GmbH"}, Country: []string{"DE"}}
+
+	for _, c := range []struct {
+		name    string
+		subject pkix.Name
+		ext     []pkix.Extension
+		usage   x509.KeyUsage
+		record  *dcp.ResponsiblePrincipalRecord
+		qscd    bool
+		want    error
+	}{
+		{"advanced signature", alice, []pkix.Extension{qcExtension(t, []asn1.ObjectIdentifier{qcCompliance})}, 0,
+			newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "DE"), false, nil},
+		{"advanced signature, QES required", alice, []pkix.Extension{qcExtension(t, []asn1.ObjectIdentifier{qcCompliance})}, 0,
+			newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "DE"), true, qes.ErrNotQualified},
+		{"not qualified", alice, nil, 0,
+			newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "DE"), false, qes.ErrNotQualified},
+		{"not for non-repudiation", alice, []pkix.Extension{qcExtension(t, []asn1.ObjectIdentifier{qcCompliance})}, x509.KeyUsageDigitalSignature,
+			newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "DE"), false, qes.ErrNotQualified},
+		{"given name and surname", pkix.Name{CommonName: "A. Example", ExtraNames: alice.ExtraNames}, []pkix.Extension{qcExtension(t, []asn1.ObjectIdentifier{qcCompliance})}, 0,
+			newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "FR"), false, nil},
+		{"other person", alice, []pkix.Extension{qcExtension(t, []asn1.ObjectIdentifier{qcCompliance})}, 0,
+			newRecord(t, "Bob Example", dcp.EntityNaturalPerson, "DE"), false, qes.ErrNameMismatch},
+		{"other country", alice, []pkix.Extension{qcExtension(t, []asn1.ObjectIdentifier{qcCompliance})}, 0,
+			newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "FR"), false, qes.ErrNameMismatch},
+		{"organization seal", org, []pkix.Extension{qcExtension(t, []asn1.ObjectIdentifier{qcCompliance, qcSSCD}, qcTypeESeal)}, 0,
+			newRecord(t, "Example GmbH", dcp.EntityOrganization, "DE"), true, nil},
+		{"seal of a person", alice, []pkix.Extension{qcExtension(t, []asn1.ObjectIdentifier{qcCompliance}, qcTypeESeal)}, 0,
+			newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "DE"), false, qes.ErrNotQualified},
+	} {
+		key := newKey(t)
+		usage := c.usage
+		if usage == 0 {
+			usage = x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment
+		}
+		cert := issue(t, &x509.Certificate{Subject: c.subject, KeyUsage: usage, ExtraExtensions: c.ext}, key.Public(), qualified)
+		sig, err := qes.Sign(c.record, []*x509.Certificate{cert, qualified.cert}, key, time.Now())
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		v.RequireQSCD = c.qscd
+		if _, err := v.Verify(sig, c.record); !errors.Is(err, c.want) {
+			t.Errorf("%s: %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+func TestVerifyRevocation(t *testing.T) {
+	root, qualified := newCA(t, "Test")
+	key := newKey(t)
+	cert := signerCert(t, alice, key.Public(), qualified, qcExtension(t, []asn1.ObjectIdentifier{qcCompliance, qcSSCD}))
+	rec := newRecord(t, "Alice Example", dcp.EntityNaturalPerson, "DE")
+	signedAt := time.Now()
+	v := &qes.Verifier{Roots: pool(root.cert), RequireRevocationData: true}
+
+	sign := func() *qes.Signature {
+		sig, err := qes.Sign(rec, []*x509.Certificate{cert, qualified.cert}, key, signedAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+	sig := sign()
+	if _, err := v.Verify(sig, rec); err == nil {
+		t.Fatal("verified without the required validation data")
+	}
+
+	// A CRL for the signer, an OCSP response for the qualified CA.
+	if err := sig.AddValidationData(nil, [][]byte{newCRL(t, qualified, signedAt)}, [][]byte{newOCSP(t, root, qualified.cert, ocsp.Good, time.Time{})}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := v.Verify(sig, rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.RevocationChecked {
+		t.Fatal("revocation not checked")
+	}
+
+	// Revoked after signing: the signature stands.
+	sig = sign()
+	revokedLater := x509.RevocationListEntry{SerialNumber: cert.SerialNumber, RevocationTime: signedAt.Add(time.Minute)}
+	sig.AddValidationData(nil, [][]byte{newCRL(t, qualified, signedAt.Add(2*time.Minute), revokedLater)}, [][]byte{newOCSP(t, root, qualified.cert, ocsp.Good, time.Time{})})
+	if _, err := v.Verify(sig, rec); err != nil {
+		t.Fatalf("revoked after signing: %v", err)
+	}
+
+	// Revoked before signing, by CRL or OCSP: it does not.
+	sig = sign()
+	revoked := x509.RevocationListEntry{SerialNumber: cert.SerialNumber, RevocationTime: signedAt.Add(-time.Minute)}
+	sig.AddValidationData(nil, [][]byte{newCRL(t, qualified, signedAt, revoked)}, nil)
+	if _, err := v.Verify(sig, rec); !errors.Is(err, qes.ErrRevoked) {
+		t.Fatalf("revoked by CRL: %v", err)
+	}
+	sig = sign()
+	sig.AddValidationData(nil, [][]byte{newCRL(t, qualified, signedAt)}, [][]byte{newOCSP(t, root, qualified.cert, ocsp.Revoked, signedAt.Add(-time.Hour))})
+	if _, err := v.Verify(sig, rec); !errors.Is(err, qes.ErrRevoked) {
+		t.Fatalf("revoked by OCSP: %v", err)
+	}
+
+	// Validation data from another CA, or expired before signing, shows
+	// nothing.
+	_, other := newCA(t, "Other")
+	sig = sign()
+	sig.AddValidationData(nil, [][]byte{newCRL(t, other, signedAt), newCRL(t, qualified, signedAt.Add(-48*time.Hour))}, [][]byte{newOCSP(t, root, qualified.cert, ocsp.Good, time.Time{})})
+	if res, err := (&qes.Verifier{Roots: pool(root.cert)}).Verify(sig, rec); err != nil || res.RevocationChecked {
+		t.Fatalf("foreign and stale CRLs: %+v, %v", res, err)
+	}
+}
+
+// newOCSP returns the response of issuer, for cert, of status, revoked at
+// revokedAt if revoked.
+func newOCSP(t *testing.T, issuer *ca, cert *x509.Certificate, status int, revokedAt time.Time) []byte {
+	t.Helper()
+	now := time.Now()
+	der, err := ocsp.CreateResponse(issuer.cert, issuer.cert, ocsp.Response{
+		Status:       status,
+		SerialNumber: cert.SerialNumber,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(24 * time.Hour),
+		RevokedAt:    revokedAt,
+	}, issuer.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
@@ -0,0 +1,186 @@
+package dcp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrQuorumNotMet is returned for a bundle of a group principal that does
+// not carry valid signatures from Threshold members of its signer set.
+var ErrQuorumNotMet = errors.New("principal quorum not met")
+
+// Member returns the member of s with humanID, or nil.
+func (s *PrincipalSignerSet) Member(humanID string) *PrincipalMember {
+	for i := range s.Signers {
+		if s.Signers[i].HumanID == humanID {
+			return &s.Signers[i]
+		}
+	}
+	return nil
+}
+
+// memberWithKey returns the member of s with key publicKeyB64, or nil.
+func (s *PrincipalSignerSet) memberWithKey(publicKeyB64 string) *PrincipalMember {
+	for i := range s.Signers {
+		if s.Signers[i].PublicKey == publicKeyB64 {
+			return &s.Signers[i]
+		}
+	}
+	return nil
+}
+
+// duplicateSigner returns the index of the first member of s whose
+// human_id or key an earlier member already has, or -1. A key listed
+// twice would let one signature count as two.
+func (s *PrincipalSignerSet) duplicateSigner() int {
+	ids, keys := map[string]bool{}, map[string]bool{}
+	for i, m := range s.Signers {
+		if ids[m.HumanID] || keys[m.PublicKey] {
+			return i
+		}
+		ids[m.HumanID], keys[m.PublicKey] = true, true
+	}
+	return -1
+}
+
+// CosignBundle adds signer's signature over sb's bundle as the cosignature
+// of humanID, replacing any earlier one of theirs. Members of a group
+// principal other than the bundle signer cosign until the quorum is met.
+func CosignBundle(sb *SignedBundle, signer BundleSigner, humanID string) error {
+	sig, err := signWith(signer, sb.Bundle)
+	if err != nil {
+		return fmt.Errorf("cosign bundle as %s: %w", humanID, err)
+	}
+	kept := sb.Signature.Cosignatures[:0]
+	for _, c := range sb.Signature.Cosignatures {
+		if c.HumanID != humanID {
+			kept = append(kept, c)
+		}
+	}
+	sb.Signature.Cosignatures = append(kept, PrincipalCosignature{HumanID: humanID, SigB64: sig})
+	return nil
+}
+
+// CheckPrincipalQuorum reports whether sb carries the quorum of anchored,
+// the signer set of its principal as published outside the bundle, such as
+// in the principal's registry record. The signer set the bundle's principal
+// record declares must equal anchored, and is refused when anchored is nil:
+// whoever signs the bundle could rewrite a set it alone declares. sig_b64,
+// if its signer is a member, and the cosignatures, each of which must be a
+// member's and verify, must together be from at least Threshold members
+// with distinct keys; a signer set that lists a human or key twice, or whose
+// threshold is not between 1 and its number of members, meets no quorum.
+// Any such quorum is accepted. A principal without a signer set needs no
+// quorum, and takes no cosignatures. Errors wrap ErrQuorumNotMet.
+func CheckPrincipalQuorum(sb *SignedBundle, anchored *PrincipalSignerSet) error {
+	rec := &sb.Bundle.ResponsiblePrincipalRecord
+	if rec.SignerSet == nil && anchored == nil && len(sb.Signature.Cosignatures) == 0 {
+		return nil
+	}
+	set, err := anchoredSignerSet(rec, anchored)
+	if err != nil {
+		return err
+	}
+	canon, err := Canonicalize(sb.Bundle)
+	if err != nil {
+		return fmt.Errorf("%w: canonicalize: %v", ErrQuorumNotMet, err)
+	}
+	_, err = checkQuorum(set, canon, sb.Signature.SignerInfo.PublicKeyB64, sb.Signature.SigB64, sb.Signature.Cosignatures)
+	return err
+}
+
+// anchoredSignerSet returns the signer set the quorum of rec's bundles is
+// checked against: anchored, which rec must declare unchanged.
+func anchoredSignerSet(rec *ResponsiblePrincipalRecord, anchored *PrincipalSignerSet) (*PrincipalSignerSet, error) {
+	switch {
+	case anchored == nil && rec.SignerSet != nil:
+		return nil, fmt.Errorf("%w: the signer set of %s is declared only in the bundle, and no anchored signer set is given for it", ErrQuorumNotMet, rec.HumanID)
+	case anchored != nil && !anchored.Equal(rec.SignerSet):
+		return nil, fmt.Errorf("%w: the signer set of %s in the bundle is not the anchored one", ErrQuorumNotMet, rec.HumanID)
+	}
+	return anchored, nil
+}
+
+// checkQuorum returns the members whose signatures over canon count toward
+// the quorum of set, in set order.
+func checkQuorum(set *PrincipalSignerSet, canon, signerKey, sig string, cosigs []PrincipalCosignature) ([]string, error) {
+	if set == nil {
+		return nil, fmt.Errorf("%w: cosignatures on a bundle of a principal without a signer set", ErrQuorumNotMet)
+	}
+	if set.Threshold < 1 || set.Threshold > len(set.Signers) {
+		return nil, fmt.Errorf("%w: threshold %d is not between 1 and the %d signers", ErrQuorumNotMet, set.Threshold, len(set.Signers))
+	}
+	if i := set.duplicateSigner(); i >= 0 {
+		return nil, fmt.Errorf("%w: signer %d repeats the human_id or key of an earlier one", ErrQuorumNotMet, i)
+	}
+	// signed holds the keys with a signature over canon; each counts once.
+	signed := map[string]bool{}
+	if set.memberWithKey(signerKey) != nil {
+		if ok, err := VerifyCanonical(canon, sig, signerKey); err == nil && ok {
+			signed[signerKey] = true
+		}
+	}
+	for i, c := range cosigs {
+		m := set.Member(c.HumanID)
+		if m == nil {
+			return nil, fmt.Errorf("%w: cosignature %d is by %s, not a member of the signer set", ErrQuorumNotMet, i, c.HumanID)
+		}
+		if ok, err := VerifyCanonical(canon, c.SigB64, m.PublicKey); err != nil || !ok {
+			return nil, fmt.Errorf("%w: cosignature %d by %s does not verify", ErrQuorumNotMet, i, c.HumanID)
+		}
+		signed[m.PublicKey] = true
+	}
+	var members []string
+	for _, m := range set.Signers {
+		if signed[m.PublicKey] {
+			members = append(members, m.HumanID)
+			delete(signed, m.PublicKey)
+		}
+	}
+	if len(members) < set.Threshold {
+		return members, fmt.Errorf("%w: %d of %d required signatures (%s)", ErrQuorumNotMet, len(members), set.Threshold, strings.Join(members, ", "))
+	}
+	return members, nil
+}
+
+// Validate checks s against the principal schema and returns
+// ValidationErrors listing every violation, or nil.
+func (s *PrincipalSignerSet) Validate() error {
+	v := newValidator()
+	s.validate(v)
+	return v.err()
+}
+
+func (s *PrincipalSignerSet) validate(v validator) {
+	if s.Threshold < 1 || s.Threshold > len(s.Signers) {
+		v.at("threshold").fail("must be between 1 and the number of signers")
+	}
+	ids, keys := map[string]bool{}, map[string]bool{}
+	for i, m := range s.Signers {
+		mv := v.at("signers").index(i)
+		mv.idOf("human_id", m.HumanID, IDKindHuman)
+		mv.publicKey("public_key", m.PublicKey)
+		if ids[m.HumanID] || keys[m.PublicKey] {
+			mv.fail("must be a distinct human and key")
+		}
+		ids[m.HumanID], keys[m.PublicKey] = true, true
+	}
+}
+
+// Clone returns a deep copy of s.
+func (s *PrincipalSignerSet) Clone() *PrincipalSignerSet {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.Signers != nil {
+		c.Signers = append([]PrincipalMember{}, s.Signers...)
+	}
+	return &c
+}
+
+// Equal reports whether s and o canonicalize identically.
+func (s *PrincipalSignerSet) Equal(o *PrincipalSignerSet) bool {
+	return (s == nil) == (o == nil) && (s == nil || canonicalEqual(s, o))
+}
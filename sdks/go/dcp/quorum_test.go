@@ -0,0 +1,167 @@
+package dcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestPrincipalQuorum(t *testing.T) {
+	b, human, _ := builderFixture(t)
+	var members []dcp.PrincipalMember
+	signers := map[string]dcp.BundleSigner{}
+	for _, id := range []string{"human002", "human003"} {
+		kp, _ := dcp.GenerateKeypair()
+		signers[id], _ = dcp.NewKeySigner(kp.SecretKeyB64)
+		members = append(members, dcp.PrincipalMember{HumanID: id, PublicKey: kp.PublicKeyB64})
+	}
+	set := &dcp.PrincipalSignerSet{Threshold: 2, Signers: append([]dcp.PrincipalMember{{HumanID: "human001", PublicKey: human.PublicKeyB64}}, members...)}
+	if err := set.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	b.ResponsiblePrincipalRecord(dcp.ResponsiblePrincipalRecord{DCPVersion: "1.0", HumanID: "human000",
+		LegalName: "Example Corp board", EntityType: "organization", Jurisdiction: "US",
+		LiabilityMode: "owner_responsible", IssuedAt: "2026-01-01T00:00:00Z", SignerSet: set})
+	sb, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The signer set is checked against one anchored outside the bundle.
+	if res := dcp.VerifySignedBundle(sb, human.PublicKeyB64); res.Verified || !strings.Contains(res.Errors[0], "declared only in the bundle") {
+		t.Fatalf("no anchored signer set: %+v", res)
+	}
+	opts := dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, SignerSets: map[string]*dcp.PrincipalSignerSet{"human000": set.Clone()}}
+	if res := dcp.VerifySignedBundleWithOptions(sb, opts); res.Verified || !strings.Contains(res.Errors[0], "1 of 2") {
+		t.Fatalf("one signature: %+v", res)
+	}
+	if err := dcp.CosignBundle(sb, signers["human003"], "human003"); err != nil {
+		t.Fatal(err)
+	}
+	explained := opts
+	explained.Explain = true
+	res := dcp.VerifySignedBundleWithOptions(sb, explained)
+	if !res.Verified {
+		t.Fatalf("quorum: %v", res.Errors)
+	}
+	if last := res.Trace[len(res.Trace)-1]; last.Check != dcp.TraceQuorum || last.Actual != "human001, human003" || last.Expected != "2 of 3" {
+		t.Fatalf("trace: %+v", last)
+	}
+	if err := dcp.CheckPrincipalQuorum(sb, set); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(sb)
+	rsb, err := dcp.ParseSignedBundleStrict(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := dcp.VerifyRawSignedBundleWithOptions(rsb, opts); !res.Verified {
+		t.Fatalf("raw bundle: %v", res.Errors)
+	}
+
+	// Any quorum will do: the two other members without the bundle signer.
+	other, _ := dcp.GenerateKeypair()
+	outsider, _ := dcp.NewKeySigner(other.SecretKeyB64)
+	byOthers := *sb
+	byOthers.Signature = *sb.Signature.Clone()
+	byOthers.Signature.SignerInfo.PublicKeyB64 = other.PublicKeyB64
+	byOthers.Signature.SigB64, _ = outsider.SignCanonical(mustCanonical(t, sb.Bundle))
+	if err := dcp.CosignBundle(&byOthers, signers["human002"], "human002"); err != nil {
+		t.Fatal(err)
+	}
+	byOthersOpts := opts
+	byOthersOpts.PublicKeyB64 = other.PublicKeyB64
+	if res := dcp.VerifySignedBundleWithOptions(&byOthers, byOthersOpts); !res.Verified {
+		t.Fatalf("quorum of cosigners: %v", res.Errors)
+	}
+
+	forged := byOthers
+	forged.Signature = *byOthers.Signature.Clone()
+	forged.Signature.Cosignatures[1].HumanID = "human001"
+	stranger := byOthers
+	stranger.Signature = *byOthers.Signature.Clone()
+	if err := dcp.CosignBundle(&stranger, outsider, "human009"); err != nil {
+		t.Fatal(err)
+	}
+	for name, sb := range map[string]*dcp.SignedBundle{"forged cosignature": &forged, "non-member": &stranger} {
+		if err := dcp.CheckPrincipalQuorum(sb, set); !errors.Is(err, dcp.ErrQuorumNotMet) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	// One key listed under two members counts once.
+	b, human, _ = builderFixture(t)
+	principal, _ := dcp.NewKeySigner(human.SecretKeyB64)
+	twice := &dcp.PrincipalSignerSet{Threshold: 2, Signers: []dcp.PrincipalMember{
+		{HumanID: "human001", PublicKey: human.PublicKeyB64}, {HumanID: "human002", PublicKey: human.PublicKeyB64}}}
+	bundle, err := b.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle.ResponsiblePrincipalRecord.SignerSet = twice
+	sb, err = dcp.SignBundle(bundle, principal, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	twiceOpts := dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, SignerSets: map[string]*dcp.PrincipalSignerSet{bundle.ResponsiblePrincipalRecord.HumanID: twice}}
+	if res := dcp.VerifySignedBundleWithOptions(sb, twiceOpts); res.Verified || !strings.Contains(res.Errors[0], "signer 1 repeats") {
+		t.Fatalf("key listed twice: %+v", res)
+	}
+
+	// A bundle signer cannot rewrite or drop the signer set, and a
+	// threshold below 1 meets no quorum even when anchored.
+	b, human, _ = builderFixture(t)
+	principal, _ = dcp.NewKeySigner(human.SecretKeyB64)
+	bundle, err = b.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	humanID := bundle.ResponsiblePrincipalRecord.HumanID
+	anchored := &dcp.PrincipalSignerSet{Threshold: 2, Signers: append([]dcp.PrincipalMember{{HumanID: "human001", PublicKey: human.PublicKeyB64}}, members...)}
+	zero := anchored.Clone()
+	zero.Threshold = 0
+	for _, tc := range []struct {
+		name     string
+		declared *dcp.PrincipalSignerSet
+		anchored *dcp.PrincipalSignerSet
+		want     string
+	}{
+		{"threshold rewritten", zero, anchored, "not the anchored one"},
+		{"signer set dropped", nil, anchored, "not the anchored one"},
+		{"threshold of 0", zero, zero, "threshold 0 is not between 1 and the 3 signers"},
+	} {
+		c := bundle.Clone()
+		c.ResponsiblePrincipalRecord.SignerSet = tc.declared
+		sb, err := dcp.SignBundle(c, principal, dcp.Signer{}, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		o := dcp.VerifyOptions{PublicKeyB64: human.PublicKeyB64, SignerSets: map[string]*dcp.PrincipalSignerSet{humanID: tc.anchored}}
+		if res := dcp.VerifySignedBundleWithOptions(sb, o); res.Verified || !strings.Contains(res.Errors[0], tc.want) {
+			t.Errorf("%s: %+v", tc.name, res)
+		}
+		if err := dcp.CheckPrincipalQuorum(sb, tc.anchored); !errors.Is(err, dcp.ErrQuorumNotMet) {
+			t.Errorf("%s: CheckPrincipalQuorum = %v", tc.name, err)
+		}
+	}
+
+	set.Threshold = 5
+	set.Signers = append(set.Signers, set.Signers[0])
+	var verrs dcp.ValidationErrors
+	if err := set.Validate(); !errors.As(err, &verrs) || len(verrs) != 2 || verrs[0].Pointer != "/threshold" || verrs[1].Pointer != "/signers/3" {
+		t.Fatalf("invalid set: %v", err)
+	}
+}
+
+func mustCanonical(t *testing.T, v interface{}) string {
+	t.Helper()
+	canon, err := dcp.Canonicalize(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return canon
+}
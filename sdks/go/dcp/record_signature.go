@@ -0,0 +1,56 @@
+package dcp
+
+import "fmt"
+
+// Sign sets Signature to s's signature over the canonical record with an
+// empty signature. The record is signed by the responsible principal, whose
+// key it does not carry: verifiers obtain it from the bundle signer or a
+// registry.
+func (r *ResponsiblePrincipalRecord) Sign(s BundleSigner) error {
+	r.Signature = ""
+	sig, err := signWith(s, r)
+	if err != nil {
+		return fmt.Errorf("sign responsible_principal_record %s: %w", r.HumanID, err)
+	}
+	r.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against the principal's public key.
+func (r *ResponsiblePrincipalRecord) VerifySignature(publicKeyB64 string) (bool, error) {
+	if r.Signature == "" {
+		return false, fmt.Errorf("responsible_principal_record %s has no signature", r.HumanID)
+	}
+	unsigned := *r
+	unsigned.Signature = ""
+	return VerifyObject(unsigned, r.Signature, publicKeyB64)
+}
+
+// Sign sets Signature to s's signature over the canonical passport with an
+// empty signature and no guardian signature. A passport is signed by the
+// agent key it names, so an empty PublicKey is set to s's key first.
+func (p *AgentPassport) Sign(s BundleSigner) error {
+	if p.PublicKey == "" {
+		p.PublicKey = s.PublicKeyB64()
+	}
+	p.Signature = ""
+	unsigned := *p
+	unsigned.GuardianSignature = ""
+	sig, err := signWith(s, unsigned)
+	if err != nil {
+		return fmt.Errorf("sign agent_passport %s: %w", p.AgentID, err)
+	}
+	p.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against the passport's own public_key,
+// proving the passport was issued by the holder of the agent key.
+func (p *AgentPassport) VerifySignature() (bool, error) {
+	if p.Signature == "" {
+		return false, fmt.Errorf("agent_passport %s has no signature", p.AgentID)
+	}
+	unsigned := *p
+	unsigned.Signature, unsigned.GuardianSignature = "", ""
+	return VerifyObject(unsigned, p.Signature, p.PublicKey)
+}
@@ -0,0 +1,53 @@
+package dcp_test
+
+import (
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestRecordSignatures(t *testing.T) {
+	newSigner := func() (*dcp.KeySigner, string) {
+		kp, err := dcp.GenerateKeypair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := dcp.NewKeySigner(kp.SecretKeyB64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s, kp.PublicKeyB64
+	}
+	principal, principalKey := newSigner()
+	agent, agentKey := newSigner()
+
+	rpr := dcp.NewResponsiblePrincipalRecord("Alice", dcp.EntityNaturalPerson, "US")
+	if err := rpr.Sign(principal); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := rpr.VerifySignature(principalKey); !ok || err != nil {
+		t.Fatalf("rpr VerifySignature = %v, %v", ok, err)
+	}
+	if ok, _ := rpr.VerifySignature(agentKey); ok {
+		t.Fatal("rpr verified under the wrong key")
+	}
+
+	p := dcp.NewAgentPassport(rpr.HumanID, "", []string{"browse"}, dcp.RiskTierLow)
+	if err := p.Sign(agent); err != nil {
+		t.Fatal(err)
+	}
+	if p.PublicKey != agentKey {
+		t.Fatalf("passport public_key = %q, want the signer's key", p.PublicKey)
+	}
+	if ok, err := p.VerifySignature(); !ok || err != nil {
+		t.Fatalf("passport VerifySignature = %v, %v", ok, err)
+	}
+	p.RiskTier = dcp.RiskTierHigh
+	if ok, _ := p.VerifySignature(); ok {
+		t.Fatal("tampered passport verified")
+	}
+	p.Signature = ""
+	if _, err := p.VerifySignature(); err == nil {
+		t.Fatal("unsigned passport verified")
+	}
+}
@@ -0,0 +1,188 @@
+package dcp
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// DCPVersion is the dcp_version the record constructors stamp.
+const DCPVersion = "1.0"
+
+// RecordFactory builds DCP records with dcp_version, identifiers and
+// timestamps filled in. Identifiers are dcp: identifiers over UUIDv7 (see
+// ParseID), so they sort by creation time; timestamps are RFC 3339 in UTC.
+// Clock and Rand default to the package clock and entropy source (see
+// SetClock); set them for reproducible output.
+type RecordFactory struct {
+	Clock func() time.Time
+	Rand  io.Reader
+}
+
+// defaultRecords backs the package-level constructors.
+var defaultRecords RecordFactory
+
+func (f *RecordFactory) now() time.Time {
+	if f.Clock != nil {
+		return f.Clock()
+	}
+	return clockNow()
+}
+
+// timestamp returns the current time in the RFC 3339 form records carry.
+func (f *RecordFactory) timestamp() string {
+	return FormatTime(f.now())
+}
+
+// NewID returns a fresh UUIDv7, the value part of the dcp: identifiers the
+// factory mints. It panics if the random source fails, which crypto/rand
+// does not.
+func (f *RecordFactory) NewID() string {
+	r := f.Rand
+	if r == nil {
+		r = entropy()
+	}
+	id, err := newUUIDv7(f.now(), r)
+	if err != nil {
+		panic(fmt.Sprintf("dcp: %v", err))
+	}
+	return id
+}
+
+// NewResponsiblePrincipalRecord returns an unsigned DCP-01 record for a new
+// principal with a generated human_id, issued now, with liability mode
+// owner_responsible. A valid jurisdiction is normalized; an invalid one is
+// kept for Validate to report.
+func (f *RecordFactory) NewResponsiblePrincipalRecord(legalName string, entityType EntityType, jurisdiction string) ResponsiblePrincipalRecord {
+	if code, err := NormalizeJurisdiction(jurisdiction); err == nil {
+		jurisdiction = code
+	}
+	return ResponsiblePrincipalRecord{
+		DCPVersion:    DCPVersion,
+		HumanID:       f.NewHumanID(),
+		LegalName:     legalName,
+		EntityType:    entityType,
+		Jurisdiction:  jurisdiction,
+		LiabilityMode: LiabilityOwnerResponsible,
+		IssuedAt:      f.timestamp(),
+	}
+}
+
+// NewAgentPassport returns an unsigned, active DCP-01 passport with a
+// generated agent_id, bound to the principal humanID.
+func (f *RecordFactory) NewAgentPassport(humanID, publicKeyB64 string, capabilities []string, riskTier RiskTier) AgentPassport {
+	return AgentPassport{
+		DCPVersion:                DCPVersion,
+		AgentID:                   f.NewAgentID(),
+		PublicKey:                 publicKeyB64,
+		PrincipalBindingReference: humanID,
+		Capabilities:              capabilities,
+		RiskTier:                  riskTier,
+		CreatedAt:                 f.timestamp(),
+		Status:                    StatusActive,
+	}
+}
+
+// NewIntent returns a DCP-02 intent with a generated intent_id, declared now.
+func (f *RecordFactory) NewIntent(agentID, humanID, actionType string, target IntentTarget, dataClasses []string, estimatedImpact EstimatedImpact) Intent {
+	return Intent{
+		DCPVersion:      DCPVersion,
+		IntentID:        f.NewIntentID(),
+		AgentID:         agentID,
+		HumanID:         humanID,
+		Timestamp:       f.timestamp(),
+		ActionType:      actionType,
+		Target:          target,
+		DataClasses:     dataClasses,
+		EstimatedImpact: estimatedImpact,
+	}
+}
+
+// NewPolicyDecision returns a DCP-02 policy decision on intentID. Reasons
+// is never nil, as the schema requires the array.
+func (f *RecordFactory) NewPolicyDecision(intentID string, decision Decision, riskScore float64, reasons ...string) PolicyDecision {
+	if reasons == nil {
+		reasons = []string{}
+	}
+	return PolicyDecision{
+		DCPVersion: DCPVersion,
+		IntentID:   intentID,
+		Decision:   decision,
+		RiskScore:  riskScore,
+		Reasons:    reasons,
+	}
+}
+
+// NewAuditEntry returns a DCP-03 audit entry for intent with a generated
+// audit_id, timestamped now, with intent_hash computed. prev_hash is
+// GENESIS; entries continuing a chain are better built with AuditChain or
+// BundleBuilder, which link them.
+func (f *RecordFactory) NewAuditEntry(intent Intent, policyDecision Outcome, outcome string) (AuditEntry, error) {
+	intentHash, err := HashObject(intent)
+	if err != nil {
+		return AuditEntry{}, fmt.Errorf("audit entry: intent hash: %w", err)
+	}
+	return AuditEntry{
+		DCPVersion:     DCPVersion,
+		AuditID:        f.NewAuditID(),
+		PrevHash:       "GENESIS",
+		Timestamp:      f.timestamp(),
+		AgentID:        intent.AgentID,
+		HumanID:        intent.HumanID,
+		IntentID:       intent.IntentID,
+		IntentHash:     intentHash,
+		PolicyDecision: policyDecision,
+		Outcome:        outcome,
+	}, nil
+}
+
+// NewRevocationRecord returns an unsigned revocation of agentID, dated now.
+func (f *RecordFactory) NewRevocationRecord(agentID, humanID, reason string) RevocationRecord {
+	return RevocationRecord{
+		DCPVersion: DCPVersion,
+		AgentID:    agentID,
+		HumanID:    humanID,
+		Timestamp:  f.timestamp(),
+		Reason:     reason,
+	}
+}
+
+// NewID returns a fresh UUIDv7; see RecordFactory.NewID.
+func NewID() string {
+	return defaultRecords.NewID()
+}
+
+// NewResponsiblePrincipalRecord calls RecordFactory.NewResponsiblePrincipalRecord
+// with the package clock and entropy source.
+func NewResponsiblePrincipalRecord(legalName string, entityType EntityType, jurisdiction string) ResponsiblePrincipalRecord {
+	return defaultRecords.NewResponsiblePrincipalRecord(legalName, entityType, jurisdiction)
+}
+
+// NewAgentPassport calls RecordFactory.NewAgentPassport with the package
+// clock and entropy source.
+func NewAgentPassport(humanID, publicKeyB64 string, capabilities []string, riskTier RiskTier) AgentPassport {
+	return defaultRecords.NewAgentPassport(humanID, publicKeyB64, capabilities, riskTier)
+}
+
+// NewIntent calls RecordFactory.NewIntent with the package clock and entropy
+// source.
+func NewIntent(agentID, humanID, actionType string, target IntentTarget, dataClasses []string, estimatedImpact EstimatedImpact) Intent {
+	return defaultRecords.NewIntent(agentID, humanID, actionType, target, dataClasses, estimatedImpact)
+}
+
+// NewPolicyDecision calls RecordFactory.NewPolicyDecision.
+func NewPolicyDecision(intentID string, decision Decision, riskScore float64, reasons ...string) PolicyDecision {
+	return defaultRecords.NewPolicyDecision(intentID, decision, riskScore, reasons...)
+}
+
+// NewAuditEntry calls RecordFactory.NewAuditEntry with the package clock and
+// entropy source.
+func NewAuditEntry(intent Intent, policyDecision Outcome, outcome string) (AuditEntry, error) {
+	return defaultRecords.NewAuditEntry(intent, policyDecision, outcome)
+}
+
+// NewRevocationRecord calls RecordFactory.NewRevocationRecord with the
+// package clock.
+func NewRevocationRecord(agentID, humanID, reason string) RevocationRecord {
+	return defaultRecords.NewRevocationRecord(agentID, humanID, reason)
+}
@@ -0,0 +1,380 @@
+package dcp
+
+// Clone returns a deep copy, Equal reports whether two records canonicalize
+// to the same bytes (so they hash and sign identically; a nil and an empty
+// list differ), and Redacted returns a copy safe to log.
+
+// RedactedValue replaces personal data in Redacted copies.
+const RedactedValue = "[REDACTED]"
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
+
+func cloneStringPtr(p *string) *string {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func cloneBoolPtr(p *bool) *bool {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// redactStringPtr masks a non-empty optional member, keeping absent ones absent.
+func redactStringPtr(p *string) *string {
+	if p == nil {
+		return nil
+	}
+	v := RedactedValue
+	return &v
+}
+
+// canonicalEqual compares two records by canonical form. Records that fail
+// to canonicalize are only equal to themselves.
+func canonicalEqual(a, b interface{}) bool {
+	ca, errA := Canonicalize(a)
+	cb, errB := Canonicalize(b)
+	return errA == nil && errB == nil && ca == cb
+}
+
+// Clone returns a deep copy of r.
+func (r *ResponsiblePrincipalRecord) Clone() *ResponsiblePrincipalRecord {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.ExpiresAt = cloneStringPtr(r.ExpiresAt)
+	c.Contact = cloneStringPtr(r.Contact)
+	c.SignerSet = r.SignerSet.Clone()
+	c.Guardian = r.Guardian.Clone()
+	c.EntityEvidence = r.EntityEvidence.Clone()
+	return &c
+}
+
+// Equal reports whether r and o canonicalize identically.
+func (r *ResponsiblePrincipalRecord) Equal(o *ResponsiblePrincipalRecord) bool {
+	return (r == nil) == (o == nil) && (r == nil || canonicalEqual(r, o))
+}
+
+// Redacted returns a copy of r with legal_name, contact and an
+// individual's identifying entity_evidence masked.
+func (r *ResponsiblePrincipalRecord) Redacted() *ResponsiblePrincipalRecord {
+	c := r.Clone()
+	if c == nil {
+		return nil
+	}
+	c.LegalName = RedactedValue
+	c.Contact = redactStringPtr(c.Contact)
+	if e := c.EntityEvidence; e != nil {
+		if e.DateOfBirthHash != "" {
+			e.DateOfBirthHash = RedactedValue
+		}
+		if e.IDReference != "" {
+			e.IDReference = RedactedValue
+		}
+	}
+	return c
+}
+
+// Clone returns a deep copy of p.
+func (p *AgentPassport) Clone() *AgentPassport {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	c.Capabilities = cloneStrings(p.Capabilities)
+	if p.CapabilityDomains != nil {
+		c.CapabilityDomains = make(map[string]DomainList, len(p.CapabilityDomains))
+		for k, l := range p.CapabilityDomains {
+			c.CapabilityDomains[k] = *l.clone()
+		}
+	}
+	return &c
+}
+
+// Equal reports whether p and o canonicalize identically.
+func (p *AgentPassport) Equal(o *AgentPassport) bool {
+	return (p == nil) == (o == nil) && (p == nil || canonicalEqual(p, o))
+}
+
+// Clone returns a deep copy of t.
+func (t *IntentTarget) Clone() *IntentTarget {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	c.To = cloneStringPtr(t.To)
+	c.Domain = cloneStringPtr(t.Domain)
+	c.URL = cloneStringPtr(t.URL)
+	return &c
+}
+
+// Equal reports whether t and o canonicalize identically.
+func (t *IntentTarget) Equal(o *IntentTarget) bool {
+	return (t == nil) == (o == nil) && (t == nil || canonicalEqual(t, o))
+}
+
+// Redacted returns a copy of t with the recipient address masked.
+func (t *IntentTarget) Redacted() *IntentTarget {
+	c := t.Clone()
+	if c == nil {
+		return nil
+	}
+	c.To = redactStringPtr(c.To)
+	return c
+}
+
+// Clone returns a deep copy of i.
+func (i *Intent) Clone() *Intent {
+	if i == nil {
+		return nil
+	}
+	c := *i
+	c.Target = *i.Target.Clone()
+	c.DataClasses = cloneStrings(i.DataClasses)
+	c.RequiresConsent = cloneBoolPtr(i.RequiresConsent)
+	c.Domains = i.Domains.clone()
+	c.ExpiresAt = cloneStringPtr(i.ExpiresAt)
+	c.Batch = i.Batch.Clone()
+	return &c
+}
+
+// Equal reports whether i and o canonicalize identically.
+func (i *Intent) Equal(o *Intent) bool {
+	return (i == nil) == (o == nil) && (i == nil || canonicalEqual(i, o))
+}
+
+// Redacted returns a copy of i with its target redacted.
+func (i *Intent) Redacted() *Intent {
+	c := i.Clone()
+	if c == nil {
+		return nil
+	}
+	c.Target = *c.Target.Redacted()
+	return c
+}
+
+// Clone returns a deep copy of c.
+func (c *RequiredConfirmation) Clone() *RequiredConfirmation {
+	if c == nil {
+		return nil
+	}
+	cp := *c
+	cp.Fields = cloneStrings(c.Fields)
+	return &cp
+}
+
+// Equal reports whether c and o canonicalize identically.
+func (c *RequiredConfirmation) Equal(o *RequiredConfirmation) bool {
+	return (c == nil) == (o == nil) && (c == nil || canonicalEqual(c, o))
+}
+
+// Clone returns a deep copy of d.
+func (d *PolicyDecision) Clone() *PolicyDecision {
+	if d == nil {
+		return nil
+	}
+	c := *d
+	c.Reasons = cloneStrings(d.Reasons)
+	c.RequiredConfirmation = d.RequiredConfirmation.Clone()
+	return &c
+}
+
+// Equal reports whether d and o canonicalize identically.
+func (d *PolicyDecision) Equal(o *PolicyDecision) bool {
+	return (d == nil) == (o == nil) && (d == nil || canonicalEqual(d, o))
+}
+
+// Clone returns a deep copy of e.
+func (e *AuditEvidence) Clone() *AuditEvidence {
+	if e == nil {
+		return nil
+	}
+	c := &AuditEvidence{Tool: cloneStringPtr(e.Tool), ResultRef: cloneStringPtr(e.ResultRef)}
+	if e.BatchItem != nil {
+		item := *e.BatchItem
+		c.BatchItem = &item
+	}
+	return c
+}
+
+// Equal reports whether e and o canonicalize identically.
+func (e *AuditEvidence) Equal(o *AuditEvidence) bool {
+	return (e == nil) == (o == nil) && (e == nil || canonicalEqual(e, o))
+}
+
+// Clone returns a deep copy of e.
+func (e *AuditEntry) Clone() *AuditEntry {
+	if e == nil {
+		return nil
+	}
+	c := *e
+	c.Evidence = *e.Evidence.Clone()
+	return &c
+}
+
+// Equal reports whether e and o canonicalize identically.
+func (e *AuditEntry) Equal(o *AuditEntry) bool {
+	return (e == nil) == (o == nil) && (e == nil || canonicalEqual(e, o))
+}
+
+// Clone returns a copy of a.
+func (a *ChainAnchor) Clone() *ChainAnchor {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	return &c
+}
+
+// Equal reports whether a and o canonicalize identically.
+func (a *ChainAnchor) Equal(o *ChainAnchor) bool {
+	return (a == nil) == (o == nil) && (a == nil || canonicalEqual(a, o))
+}
+
+// Clone returns a deep copy of b.
+func (b *CitizenshipBundle) Clone() *CitizenshipBundle {
+	if b == nil {
+		return nil
+	}
+	c := CitizenshipBundle{
+		ResponsiblePrincipalRecord: *b.ResponsiblePrincipalRecord.Clone(),
+		AgentPassport:              *b.AgentPassport.Clone(),
+		Intent:                     *b.Intent.Clone(),
+		PolicyDecision:             *b.PolicyDecision.Clone(),
+		ChainAnchor:                b.ChainAnchor.Clone(),
+		Consent:                    b.Consent.Clone(),
+		Cancellation:               b.Cancellation.Clone(),
+		BatchManifest:              cloneStrings(b.BatchManifest),
+	}
+	if b.AuditEntries != nil {
+		c.AuditEntries = make([]AuditEntry, len(b.AuditEntries))
+		for i := range b.AuditEntries {
+			c.AuditEntries[i] = *b.AuditEntries[i].Clone()
+		}
+	}
+	if b.IntentHistory != nil {
+		c.IntentHistory = make([]Intent, len(b.IntentHistory))
+		for i := range b.IntentHistory {
+			c.IntentHistory[i] = *b.IntentHistory[i].Clone()
+		}
+	}
+	if b.Overrides != nil {
+		c.Overrides = append([]OverrideRecord{}, b.Overrides...)
+	}
+	if b.BreakGlass != nil {
+		c.BreakGlass = make([]BreakGlassRecord, len(b.BreakGlass))
+		for i := range b.BreakGlass {
+			c.BreakGlass[i] = *b.BreakGlass[i].Clone()
+		}
+	}
+	if b.DelegationChain != nil {
+		c.DelegationChain = make([]DelegationLink, len(b.DelegationChain))
+		for i := range b.DelegationChain {
+			c.DelegationChain[i] = *b.DelegationChain[i].Clone()
+		}
+	}
+	c.PowersOfAttorney = clonePowersOfAttorney(b.PowersOfAttorney)
+	if b.Insurance != nil {
+		c.Insurance = append([]InsuranceAttestation{}, b.Insurance...)
+	}
+	return &c
+}
+
+// Equal reports whether b and o canonicalize identically.
+func (b *CitizenshipBundle) Equal(o *CitizenshipBundle) bool {
+	return (b == nil) == (o == nil) && (b == nil || canonicalEqual(b, o))
+}
+
+// Redacted returns a copy of b with its principal record and intent
+// redacted. The copy no longer matches its signatures.
+func (b *CitizenshipBundle) Redacted() *CitizenshipBundle {
+	c := b.Clone()
+	if c == nil {
+		return nil
+	}
+	c.ResponsiblePrincipalRecord = *c.ResponsiblePrincipalRecord.Redacted()
+	c.Intent = *c.Intent.Redacted()
+	return c
+}
+
+// Clone returns a copy of s.
+func (s *Signer) Clone() *Signer {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// Equal reports whether s and o canonicalize identically.
+func (s *Signer) Equal(o *Signer) bool {
+	return (s == nil) == (o == nil) && (s == nil || canonicalEqual(s, o))
+}
+
+// Clone returns a deep copy of s.
+func (s *BundleSignature) Clone() *BundleSignature {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.MerkleRoot = cloneStringPtr(s.MerkleRoot)
+	if s.Cosignatures != nil {
+		c.Cosignatures = append([]PrincipalCosignature{}, s.Cosignatures...)
+	}
+	return &c
+}
+
+// Equal reports whether s and o canonicalize identically.
+func (s *BundleSignature) Equal(o *BundleSignature) bool {
+	return (s == nil) == (o == nil) && (s == nil || canonicalEqual(s, o))
+}
+
+// Clone returns a deep copy of sb.
+func (sb *SignedBundle) Clone() *SignedBundle {
+	if sb == nil {
+		return nil
+	}
+	return &SignedBundle{Bundle: *sb.Bundle.Clone(), Signature: *sb.Signature.Clone()}
+}
+
+// Equal reports whether sb and o canonicalize identically.
+func (sb *SignedBundle) Equal(o *SignedBundle) bool {
+	return (sb == nil) == (o == nil) && (sb == nil || canonicalEqual(sb, o))
+}
+
+// Redacted returns a copy of sb with its bundle redacted. The copy no
+// longer verifies.
+func (sb *SignedBundle) Redacted() *SignedBundle {
+	c := sb.Clone()
+	if c == nil {
+		return nil
+	}
+	c.Bundle = *c.Bundle.Redacted()
+	return c
+}
+
+// Clone returns a deep copy of r.
+func (r *RevocationRecord) Clone() *RevocationRecord {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.PowersOfAttorney = clonePowersOfAttorney(r.PowersOfAttorney)
+	return &c
+}
+
+// Equal reports whether r and o canonicalize identically.
+func (r *RevocationRecord) Equal(o *RevocationRecord) bool {
+	return (r == nil) == (o == nil) && (r == nil || canonicalEqual(r, o))
+}
@@ -0,0 +1,118 @@
+package dcp_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// fill sets every member reachable from v to a non-zero value, so a Clone
+// that forgets a newly added pointer or slice member is caught below.
+func fill(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fill(v.Field(i))
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		for i := 0; i < 2; i++ {
+			fill(v.Index(i))
+		}
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Float64:
+		v.SetFloat(0.5)
+	case reflect.Int, reflect.Int64:
+		v.SetInt(1)
+	}
+}
+
+// sharesMemory reports the first path at which a and b alias.
+func sharesMemory(a, b reflect.Value, path string) string {
+	switch a.Kind() {
+	case reflect.Ptr:
+		if !a.IsNil() && a.Pointer() == b.Pointer() {
+			return path
+		}
+		if !a.IsNil() {
+			return sharesMemory(a.Elem(), b.Elem(), path)
+		}
+	case reflect.Slice:
+		if a.Len() > 0 && a.Pointer() == b.Pointer() {
+			return path
+		}
+		for i := 0; i < a.Len(); i++ {
+			if p := sharesMemory(a.Index(i), b.Index(i), path+"[]"); p != "" {
+				return p
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if p := sharesMemory(a.Field(i), b.Field(i), path+"."+a.Type().Field(i).Name); p != "" {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+func TestCloneIsDeep(t *testing.T) {
+	for _, rec := range []interface{}{
+		&dcp.SignedBundle{}, &dcp.RevocationRecord{},
+	} {
+		fill(reflect.ValueOf(rec).Elem())
+		clone := reflect.ValueOf(rec).MethodByName("Clone").Call(nil)[0].Interface()
+		if !reflect.DeepEqual(rec, clone) {
+			t.Fatalf("%T: clone differs from original", rec)
+		}
+		if p := sharesMemory(reflect.ValueOf(rec).Elem(), reflect.ValueOf(clone).Elem(), ""); p != "" {
+			t.Fatalf("%T: clone aliases the original at %s", rec, p)
+		}
+		if eq := reflect.ValueOf(rec).MethodByName("Equal").Call([]reflect.Value{reflect.ValueOf(clone)})[0]; !eq.Bool() {
+			t.Fatalf("%T: clone should be Equal", rec)
+		}
+	}
+}
+
+func TestEqualAndRedacted(t *testing.T) {
+	b, _, _ := builderFixture(t)
+	sb, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contact := "alice@example.com"
+	to := "bob@example.com"
+	sb.Bundle.ResponsiblePrincipalRecord.Contact = &contact
+	sb.Bundle.Intent.Target.To = &to
+
+	other := sb.Clone()
+	other.Bundle.AuditEntries[1].Outcome = "email_bounced"
+	if sb.Equal(other) || !sb.Equal(sb.Clone()) {
+		t.Fatal("Equal should follow the canonical form")
+	}
+	var nilBundle *dcp.SignedBundle
+	if sb.Equal(nil) || !nilBundle.Equal(nil) {
+		t.Fatal("nil handling")
+	}
+
+	red := sb.Redacted()
+	rpr := red.Bundle.ResponsiblePrincipalRecord
+	if rpr.LegalName != dcp.RedactedValue || *rpr.Contact != dcp.RedactedValue || *red.Bundle.Intent.Target.To != dcp.RedactedValue {
+		t.Fatalf("redacted = %+v", red.Bundle)
+	}
+	if sb.Bundle.ResponsiblePrincipalRecord.LegalName != "Alice" || *sb.Bundle.ResponsiblePrincipalRecord.Contact != contact {
+		t.Fatal("Redacted must not modify the original")
+	}
+	canon, _ := dcp.Canonicalize(red)
+	if strings.Contains(canon, "alice") || strings.Contains(canon, "Alice") || strings.Contains(canon, "bob@") {
+		t.Fatalf("redacted bundle still leaks personal data: %s", canon)
+	}
+}
@@ -0,0 +1,67 @@
+package dcp_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+var uuidV7 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewIDIsOrderedUUIDv7(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := dcp.RecordFactory{Clock: func() time.Time { now = now.Add(time.Millisecond); return now }}
+	prev := ""
+	for i := 0; i < 100; i++ {
+		id := f.NewID()
+		if !uuidV7.MatchString(id) {
+			t.Fatalf("id %q is not a UUIDv7", id)
+		}
+		if id <= prev {
+			t.Fatalf("id %q does not sort after %q", id, prev)
+		}
+		prev = id
+	}
+	if !uuidV7.MatchString(dcp.NewID()) {
+		t.Fatal("package-level NewID should return a UUIDv7")
+	}
+}
+
+func TestRecordFactoryIsDeterministic(t *testing.T) {
+	clock := func() time.Time { return time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("x", 3600)) }
+	f := dcp.RecordFactory{Clock: clock, Rand: bytes.NewReader(make([]byte, 64))}
+	rpr := f.NewResponsiblePrincipalRecord("Acme Corp", "organization", "US")
+	if rpr.HumanID != "dcp:human:019cb706-4398-7000-8000-000000000000" {
+		t.Fatalf("human_id = %s", rpr.HumanID)
+	}
+	if rpr.IssuedAt != "2026-03-04T04:06:07Z" || rpr.DCPVersion != dcp.DCPVersion || rpr.LiabilityMode != "owner_responsible" {
+		t.Fatalf("record = %+v", rpr)
+	}
+
+	passport := f.NewAgentPassport(rpr.HumanID, "cHVi", []string{"api_call"}, "low")
+	intent := f.NewIntent(passport.AgentID, rpr.HumanID, "api_call", dcp.IntentTarget{Channel: "api"}, []string{"none"}, "low")
+	if passport.Status != "active" || passport.PrincipalBindingReference != rpr.HumanID || intent.Timestamp != rpr.IssuedAt {
+		t.Fatalf("passport = %+v, intent = %+v", passport, intent)
+	}
+	entry, err := f.NewAuditEntry(intent, "approved", "ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ih, _ := dcp.HashObject(intent)
+	if entry.IntentHash != ih || entry.IntentID != intent.IntentID || entry.AgentID != passport.AgentID || entry.PrevHash != "GENESIS" {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if d := f.NewPolicyDecision(intent.IntentID, "approve", 0.1); d.Reasons == nil {
+		t.Fatal("reasons should be an empty array, not null")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewID should panic when the random source fails")
+		}
+	}()
+	(&dcp.RecordFactory{Rand: bytes.NewReader(nil)}).NewID()
+}
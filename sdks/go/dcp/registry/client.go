@@ -0,0 +1,147 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Client calls a registry's HTTP API. Submission errors wrap ErrInvalid,
+// ErrConflict and ErrUnknownPrincipal as Registry's do.
+type Client struct {
+	// URL is the registry base URL, e.g. "http://localhost:8081".
+	URL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// SubmitPrincipal submits a signed principal record and its key.
+func (c *Client) SubmitPrincipal(ctx context.Context, rec dcp.ResponsiblePrincipalRecord, publicKeyB64 string) error {
+	_, err := c.do(ctx, http.MethodPost, "/v1/principals", Principal{Record: rec, PublicKeyB64: publicKeyB64}, nil)
+	return err
+}
+
+// SubmitPassport submits a self-signed passport.
+func (c *Client) SubmitPassport(ctx context.Context, p dcp.AgentPassport) error {
+	_, err := c.do(ctx, http.MethodPost, "/v1/passports", p, nil)
+	return err
+}
+
+// Passport returns the passport of agentID, or nil if none is registered,
+// which makes a Client a grpcserver.PassportSource.
+func (c *Client) Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error) {
+	var p dcp.AgentPassport
+	found, err := c.do(ctx, http.MethodGet, "/v1/passports/"+url.PathEscape(agentID), nil, &p)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Principal returns the principal humanID and the IDs of its agents, or nil.
+func (c *Client) Principal(ctx context.Context, humanID string) (*PrincipalInfo, error) {
+	var info PrincipalInfo
+	found, err := c.do(ctx, http.MethodGet, "/v1/principals/"+url.PathEscape(humanID), nil, &info)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// PrincipalPassports returns the passports binding to humanID, or nil if
+// the principal is not registered.
+func (c *Client) PrincipalPassports(ctx context.Context, humanID string) ([]dcp.AgentPassport, error) {
+	var resp struct {
+		Passports []dcp.AgentPassport `json:"passports"`
+	}
+	found, err := c.do(ctx, http.MethodGet, "/v1/principals/"+url.PathEscape(humanID)+"/passports", nil, &resp)
+	if err != nil || !found {
+		return nil, err
+	}
+	return resp.Passports, nil
+}
+
+// Snapshot fetches the registry snapshot and verifies it under the
+// registry's key.
+func (c *Client) Snapshot(ctx context.Context, publicKeyB64 string) (*Snapshot, error) {
+	var s Snapshot
+	found, err := c.do(ctx, http.MethodGet, "/v1/snapshot", nil, &s)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s: registry serves no snapshot", c.URL)
+	}
+	if ok, err := s.Verify(publicKeyB64); err != nil || !ok {
+		return nil, fmt.Errorf("%s: snapshot signature does not verify under the registry key", c.URL)
+	}
+	return &s, nil
+}
+
+// do sends body, if any, and decodes a 2xx response into out. A 404 is
+// reported as not found rather than as an error.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) (bool, error) {
+	u := strings.TrimRight(c.URL, "/") + path
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return false, err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return false, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return false, nil
+	}
+	if resp.StatusCode/100 != 2 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(data, &e)
+		if e.Error == "" {
+			e.Error = resp.Status
+		}
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return false, fmt.Errorf("%w: %s", ErrInvalid, e.Error)
+		case http.StatusConflict:
+			return false, fmt.Errorf("%w: %s", ErrConflict, e.Error)
+		case http.StatusUnprocessableEntity:
+			return false, fmt.Errorf("%w: %s", ErrUnknownPrincipal, e.Error)
+		}
+		return false, fmt.Errorf("%s %s: %s", method, u, e.Error)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return false, fmt.Errorf("%s %s: %w", method, u, err)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,66 @@
+package registry_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+)
+
+var _ grpcserver.PassportSource = (*registry.Client)(nil)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	principal, principalKey := dcptest.NewSigner(t)
+	snapshotSigner, snapshotKey := dcptest.NewSigner(t)
+	reg, err := registry.New(registry.Config{Signer: snapshotSigner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(reg)
+	defer ts.Close()
+	c := &registry.Client{URL: ts.URL}
+	rpr, p := principalAndPassport(t, principal)
+
+	if err := c.SubmitPassport(ctx, p); !errors.Is(err, registry.ErrUnknownPrincipal) {
+		t.Fatalf("passport of an unknown principal: %v", err)
+	}
+	if err := c.SubmitPrincipal(ctx, rpr, principalKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SubmitPrincipal(ctx, rpr, snapshotKey); !errors.Is(err, registry.ErrInvalid) {
+		t.Fatalf("record under the wrong key: %v", err)
+	}
+	if err := c.SubmitPassport(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.Passport(ctx, p.AgentID)
+	if err != nil || got == nil || got.Signature != p.Signature {
+		t.Fatalf("Passport = %+v, %v", got, err)
+	}
+	if got, err := c.Passport(ctx, "did:agent:unknown"); got != nil || err != nil {
+		t.Fatalf("unknown passport = %+v, %v", got, err)
+	}
+	info, err := c.Principal(ctx, rpr.HumanID)
+	if err != nil || info == nil || len(info.AgentIDs) != 1 || info.PublicKeyB64 != principalKey {
+		t.Fatalf("Principal = %+v, %v", info, err)
+	}
+	if ps, err := c.PrincipalPassports(ctx, rpr.HumanID); err != nil || len(ps) != 1 || ps[0].AgentID != p.AgentID {
+		t.Fatalf("PrincipalPassports = %+v, %v", ps, err)
+	}
+	if ps, err := c.PrincipalPassports(ctx, "did:human:unknown"); ps != nil || err != nil {
+		t.Fatalf("unknown principal's passports = %+v, %v", ps, err)
+	}
+	snap, err := c.Snapshot(ctx, snapshotKey)
+	if err != nil || snap.Sequence != 2 {
+		t.Fatalf("Snapshot = %+v, %v", snap, err)
+	}
+	if _, err := c.Snapshot(ctx, principalKey); err == nil {
+		t.Fatal("snapshot accepted under the wrong key")
+	}
+}
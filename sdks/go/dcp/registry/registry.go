@@ -0,0 +1,350 @@
+// Package registry stores responsible principal records and agent passports
+// and serves them to verifiers, over HTTP or as a signed snapshot that can
+// be checked offline.
+//
+// Endpoints:
+//
+//	POST /v1/principals                    body: {"record": RPR, "public_key_b64": "..."}
+//	POST /v1/passports                     body: an AgentPassport
+//	GET  /v1/principals/{human_id}         the record, its key and its agents
+//	GET  /v1/principals/{human_id}/passports
+//	GET  /v1/passports/{agent_id}
+//	GET  /v1/snapshot                      a signed Snapshot of the registry
+//	GET  /health
+//	GET  /openapi.json                     the OpenAPI document of the DCP services
+//
+// Every submission must carry a valid signature. A principal record is
+// signed by the principal, whose key is registered with its first record
+// and may not change afterwards. A passport is signed by the agent key it
+// names, which likewise may not change, and must reference a registered
+// principal.
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Submission errors. The HTTP API answers them with 400, 409 and 422.
+var (
+	ErrInvalid          = errors.New("invalid submission")
+	ErrConflict         = errors.New("conflicts with the registered key")
+	ErrUnknownPrincipal = errors.New("unknown principal")
+)
+
+// Principal is a responsible principal record and the key it is signed with.
+type Principal struct {
+	Record       dcp.ResponsiblePrincipalRecord `json:"record"`
+	PublicKeyB64 string                         `json:"public_key_b64"`
+}
+
+// Config configures a Registry.
+type Config struct {
+	// Path is the JSON file the registry is kept in, rewritten after every
+	// accepted submission. Empty keeps the registry in memory only.
+	Path string
+	// Signer signs snapshots. Without one, Snapshot fails.
+	Signer dcp.BundleSigner
+	// MaxBodyBytes caps request bodies; zero means 1 MiB.
+	MaxBodyBytes int64
+	// Now is the clock snapshots are stamped with; nil means time.Now.
+	Now func() time.Time
+}
+
+// state is the persisted form of a Registry.
+type state struct {
+	Sequence   uint64              `json:"sequence"`
+	Principals []Principal         `json:"principals"`
+	Passports  []dcp.AgentPassport `json:"passports"`
+}
+
+// Registry holds the registered principals and passports. It is safe for
+// concurrent use. Create one with New.
+type Registry struct {
+	cfg Config
+	mux *http.ServeMux
+
+	mu         sync.RWMutex
+	sequence   uint64
+	principals map[string]Principal
+	passports  map[string]dcp.AgentPassport
+}
+
+// New returns a Registry for cfg, loading cfg.Path if it exists.
+func New(cfg Config) (*Registry, error) {
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 1 << 20
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	r := &Registry{cfg: cfg, principals: map[string]Principal{}, passports: map[string]dcp.AgentPassport{}}
+	if cfg.Path != "" {
+		data, err := os.ReadFile(cfg.Path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("registry: %w", err)
+		default:
+			var st state
+			if err := json.Unmarshal(data, &st); err != nil {
+				return nil, fmt.Errorf("registry: %s: %w", cfg.Path, err)
+			}
+			r.sequence = st.Sequence
+			for _, p := range st.Principals {
+				r.principals[p.Record.HumanID] = p
+			}
+			for _, p := range st.Passports {
+				r.passports[p.AgentID] = p
+			}
+		}
+	}
+	r.mux = newServeMux(r)
+	return r, nil
+}
+
+// SubmitPrincipal registers or replaces the record of a principal. The
+// record must validate and its signature verify under publicKeyB64, which
+// must be the key the principal was first registered with.
+func (r *Registry) SubmitPrincipal(rec dcp.ResponsiblePrincipalRecord, publicKeyB64 string) error {
+	if err := rec.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if ok, err := rec.VerifySignature(publicKeyB64); err != nil || !ok {
+		return fmt.Errorf("%w: responsible_principal_record signature does not verify under public_key_b64", ErrInvalid)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev, existed := r.principals[rec.HumanID]
+	if existed && prev.PublicKeyB64 != publicKeyB64 {
+		return fmt.Errorf("principal %s: %w", rec.HumanID, ErrConflict)
+	}
+	r.principals[rec.HumanID] = Principal{Record: rec, PublicKeyB64: publicKeyB64}
+	if err := r.commit(); err != nil {
+		if existed {
+			r.principals[rec.HumanID] = prev
+		} else {
+			delete(r.principals, rec.HumanID)
+		}
+		return err
+	}
+	return nil
+}
+
+// SubmitPassport registers or replaces a passport. It must validate, be
+// signed by its own public_key, bind to a registered principal and keep the
+// key its agent was first registered with.
+func (r *Registry) SubmitPassport(p dcp.AgentPassport) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if ok, err := p.VerifySignature(); err != nil || !ok {
+		return fmt.Errorf("%w: agent_passport signature does not verify under its public_key", ErrInvalid)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.principals[p.PrincipalBindingReference]; !ok {
+		return fmt.Errorf("passport %s: %w %s", p.AgentID, ErrUnknownPrincipal, p.PrincipalBindingReference)
+	}
+	prev, existed := r.passports[p.AgentID]
+	if existed && prev.PublicKey != p.PublicKey {
+		return fmt.Errorf("passport %s: %w", p.AgentID, ErrConflict)
+	}
+	r.passports[p.AgentID] = p
+	if err := r.commit(); err != nil {
+		if existed {
+			r.passports[p.AgentID] = prev
+		} else {
+			delete(r.passports, p.AgentID)
+		}
+		return err
+	}
+	return nil
+}
+
+// commit advances the sequence number and saves the registry. The caller
+// holds mu and restores the previous entry if commit fails.
+func (r *Registry) commit() error {
+	r.sequence++
+	if err := r.save(); err != nil {
+		r.sequence--
+		return fmt.Errorf("registry: %w", err)
+	}
+	return nil
+}
+
+// save writes the registry to cfg.Path through a sibling file and a rename,
+// so a crash never leaves a torn file. The caller holds mu.
+func (r *Registry) save() error {
+	if r.cfg.Path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(r.stateLocked(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := r.cfg.Path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, r.cfg.Path); err != nil {
+		return err
+	}
+	if dir, err := os.Open(filepath.Dir(r.cfg.Path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// stateLocked returns the registry contents sorted by ID. The caller holds mu.
+func (r *Registry) stateLocked() state {
+	st := state{Sequence: r.sequence, Principals: []Principal{}, Passports: []dcp.AgentPassport{}}
+	for _, p := range r.principals {
+		st.Principals = append(st.Principals, p)
+	}
+	for _, p := range r.passports {
+		st.Passports = append(st.Passports, p)
+	}
+	sort.Slice(st.Principals, func(i, j int) bool { return st.Principals[i].Record.HumanID < st.Principals[j].Record.HumanID })
+	sort.Slice(st.Passports, func(i, j int) bool { return st.Passports[i].AgentID < st.Passports[j].AgentID })
+	return st
+}
+
+// Passport returns the passport of agentID, or nil if none is registered. It
+// makes a Registry a grpcserver.PassportSource.
+func (r *Registry) Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.passports[agentID]; ok {
+		return &p, nil
+	}
+	return nil, nil
+}
+
+// RevocationKey returns the key of humanID if agentID's passport binds to
+// it, and "" otherwise. It makes a Registry a revocationserver.Authority
+// that accepts revocations from an agent's own principal only.
+func (r *Registry) RevocationKey(ctx context.Context, agentID, humanID string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.passports[agentID]; !ok || p.PrincipalBindingReference != humanID {
+		return "", nil
+	}
+	return r.principals[humanID].PublicKeyB64, nil
+}
+
+// Principal returns the registered principal humanID, or nil.
+func (r *Registry) Principal(humanID string) *Principal {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.principals[humanID]; ok {
+		return &p
+	}
+	return nil
+}
+
+// PassportsOf returns the passports bound to humanID, sorted by agent_id.
+func (r *Registry) PassportsOf(humanID string) []dcp.AgentPassport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []dcp.AgentPassport{}
+	for _, p := range r.passports {
+		if p.PrincipalBindingReference == humanID {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].AgentID < out[j].AgentID })
+	return out
+}
+
+// Sequence returns the number of submissions the registry has accepted.
+func (r *Registry) Sequence() uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sequence
+}
+
+// Snapshot returns the whole registry signed with cfg.Signer.
+func (r *Registry) Snapshot() (*Snapshot, error) {
+	if r.cfg.Signer == nil {
+		return nil, errors.New("registry: no snapshot signer configured")
+	}
+	r.mu.RLock()
+	st := r.stateLocked()
+	r.mu.RUnlock()
+	s := &Snapshot{
+		DCPVersion:  "1.0",
+		GeneratedAt: dcp.FormatTime(r.cfg.Now()),
+		Sequence:    st.Sequence,
+		Principals:  st.Principals,
+		Passports:   st.Passports,
+		SignerKey:   r.cfg.Signer.PublicKeyB64(),
+	}
+	canon, err := dcp.Canonicalize(s)
+	if err != nil {
+		return nil, fmt.Errorf("registry: snapshot: %w", err)
+	}
+	if s.Signature, err = r.cfg.Signer.SignCanonical(canon); err != nil {
+		return nil, fmt.Errorf("registry: snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// Snapshot is a signed copy of a registry for verifiers that cannot reach
+// it. Sequence grows with every accepted submission, so a verifier can
+// refuse a snapshot older than one it has already seen.
+type Snapshot struct {
+	DCPVersion  string              `json:"dcp_version"`
+	GeneratedAt string              `json:"generated_at"`
+	Sequence    uint64              `json:"sequence"`
+	Principals  []Principal         `json:"principals"`
+	Passports   []dcp.AgentPassport `json:"passports"`
+	SignerKey   string              `json:"signer_key"`
+	// Signature is over the canonical snapshot with an empty signature.
+	Signature string `json:"signature"`
+}
+
+// Verify checks the snapshot signature against the registry's key, which
+// the verifier must know in advance; SignerKey only says which key signed.
+func (s *Snapshot) Verify(publicKeyB64 string) (bool, error) {
+	if s.Signature == "" {
+		return false, errors.New("registry snapshot has no signature")
+	}
+	unsigned := *s
+	unsigned.Signature = ""
+	return dcp.VerifyObject(unsigned, s.Signature, publicKeyB64)
+}
+
+// Passport returns the passport of agentID in the snapshot, or nil. It makes
+// a verified Snapshot a grpcserver.PassportSource.
+func (s *Snapshot) Passport(ctx context.Context, agentID string) (*dcp.AgentPassport, error) {
+	for i := range s.Passports {
+		if s.Passports[i].AgentID == agentID {
+			p := s.Passports[i]
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+// Principal returns the principal humanID in the snapshot, or nil.
+func (s *Snapshot) Principal(humanID string) *Principal {
+	for i := range s.Principals {
+		if s.Principals[i].Record.HumanID == humanID {
+			p := s.Principals[i]
+			return &p
+		}
+	}
+	return nil
+}
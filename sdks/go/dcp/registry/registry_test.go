@@ -0,0 +1,196 @@
+package registry_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+)
+
+var (
+	_ grpcserver.PassportSource = (*registry.Registry)(nil)
+	_ grpcserver.PassportSource = (*registry.Snapshot)(nil)
+)
+
+// principalAndPassport returns a signed principal record and a passport of
+// one of its agents signed by a fresh agent key.
+func principalAndPassport(t *testing.T, principal *dcp.KeySigner) (dcp.ResponsiblePrincipalRecord, dcp.AgentPassport) {
+	t.Helper()
+	rpr := dcp.NewResponsiblePrincipalRecord("Alice", dcp.EntityNaturalPerson, "US")
+	if err := rpr.Sign(principal); err != nil {
+		t.Fatal(err)
+	}
+	agent, _ := dcptest.NewSigner(t)
+	p := dcp.NewAgentPassport(rpr.HumanID, "", []string{"browse"}, dcp.RiskTierLow)
+	if err := p.Sign(agent); err != nil {
+		t.Fatal(err)
+	}
+	return rpr, p
+}
+
+func do(t *testing.T, h http.Handler, method, target string, body interface{}) (int, map[string]interface{}) {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, &buf))
+	var out map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec.Code, out
+}
+
+func TestSubmitAndLookup(t *testing.T) {
+	principal, principalKey := dcptest.NewSigner(t)
+	snapshotSigner, snapshotKey := dcptest.NewSigner(t)
+	reg, err := registry.New(registry.Config{Signer: snapshotSigner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpr, p := principalAndPassport(t, principal)
+
+	if code, out := do(t, reg, http.MethodPost, "/v1/passports", p); code != http.StatusUnprocessableEntity {
+		t.Fatalf("passport of an unknown principal: %d %v", code, out)
+	}
+	if code, out := do(t, reg, http.MethodPost, "/v1/principals", registry.Principal{Record: rpr, PublicKeyB64: principalKey}); code != http.StatusCreated {
+		t.Fatalf("submit principal: %d %v", code, out)
+	}
+	if code, out := do(t, reg, http.MethodPost, "/v1/passports", p); code != http.StatusCreated || out["sequence"] != float64(2) {
+		t.Fatalf("submit passport: %d %v", code, out)
+	}
+
+	code, out := do(t, reg, http.MethodGet, "/v1/principals/"+rpr.HumanID, nil)
+	if code != http.StatusOK || out["public_key_b64"] != principalKey {
+		t.Fatalf("get principal: %d %v", code, out)
+	}
+	if ids, _ := out["agent_ids"].([]interface{}); len(ids) != 1 || ids[0] != p.AgentID {
+		t.Fatalf("agent_ids = %v", out["agent_ids"])
+	}
+	if code, out := do(t, reg, http.MethodGet, "/v1/passports/"+p.AgentID, nil); code != http.StatusOK || out["public_key"] != p.PublicKey {
+		t.Fatalf("get passport: %d %v", code, out)
+	}
+	if code, out := do(t, reg, http.MethodGet, "/v1/principals/"+rpr.HumanID+"/passports", nil); code != http.StatusOK || len(out["passports"].([]interface{})) != 1 {
+		t.Fatalf("get principal passports: %d %v", code, out)
+	}
+	if code, _ := do(t, reg, http.MethodGet, "/v1/passports/did:agent:unknown", nil); code != http.StatusNotFound {
+		t.Fatalf("unknown passport: %d", code)
+	}
+
+	// The snapshot verifies under the registry key and answers lookups offline.
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/snapshot", nil))
+	var snap registry.Snapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snap); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := snap.Verify(snapshotKey); !ok || err != nil {
+		t.Fatalf("snapshot Verify = %v, %v", ok, err)
+	}
+	if snap.Sequence != 2 || snap.Principal(rpr.HumanID) == nil {
+		t.Fatalf("snapshot %+v", snap)
+	}
+	if got, _ := snap.Passport(context.Background(), p.AgentID); got == nil || got.Signature != p.Signature {
+		t.Fatalf("snapshot passport = %+v", got)
+	}
+	if ok, _ := snap.Verify(principalKey); ok {
+		t.Fatal("snapshot verified under the wrong key")
+	}
+	snap.Passports = snap.Passports[:0]
+	if ok, _ := snap.Verify(snapshotKey); ok {
+		t.Fatal("tampered snapshot verified")
+	}
+}
+
+func TestSubmitRejections(t *testing.T) {
+	principal, principalKey := dcptest.NewSigner(t)
+	reg, err := registry.New(registry.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpr, p := principalAndPassport(t, principal)
+	if err := reg.SubmitPrincipal(rpr, principalKey); err != nil {
+		t.Fatal(err)
+	}
+
+	other, otherKey := dcptest.NewSigner(t)
+	if err := reg.SubmitPrincipal(rpr, otherKey); !errors.Is(err, registry.ErrInvalid) {
+		t.Fatalf("record under the wrong key: %v", err)
+	}
+	resigned := rpr
+	if err := resigned.Sign(other); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SubmitPrincipal(resigned, otherKey); !errors.Is(err, registry.ErrConflict) {
+		t.Fatalf("principal key change: %v", err)
+	}
+
+	tampered := p
+	tampered.Capabilities = []string{"payments"}
+	if err := reg.SubmitPassport(tampered); !errors.Is(err, registry.ErrInvalid) {
+		t.Fatalf("tampered passport: %v", err)
+	}
+	if err := reg.SubmitPassport(p); err != nil {
+		t.Fatal(err)
+	}
+	rekeyed := p
+	rekeyed.PublicKey = ""
+	if err := rekeyed.Sign(other); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SubmitPassport(rekeyed); !errors.Is(err, registry.ErrConflict) {
+		t.Fatalf("agent key change: %v", err)
+	}
+	// An updated passport must be signed again.
+	p.Status = dcp.StatusSuspended
+	p.Signature = ""
+	if err := reg.SubmitPassport(p); !errors.Is(err, registry.ErrInvalid) {
+		t.Fatalf("unsigned update: %v", err)
+	}
+
+	if code, out := do(t, reg, http.MethodPost, "/v1/principals", map[string]interface{}{"record": rpr}); code != http.StatusBadRequest {
+		t.Fatalf("missing public key: %d %v", code, out)
+	}
+	if code, _ := do(t, reg, http.MethodGet, "/v1/snapshot", nil); code != http.StatusNotImplemented {
+		t.Fatalf("snapshot without a signer: %d", code)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	principal, principalKey := dcptest.NewSigner(t)
+	rpr, p := principalAndPassport(t, principal)
+
+	reg, err := registry.New(registry.Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SubmitPrincipal(rpr, principalKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SubmitPassport(p); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := registry.New(registry.Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Sequence() != 2 || reopened.Principal(rpr.HumanID) == nil {
+		t.Fatalf("reopened registry: sequence %d", reopened.Sequence())
+	}
+	if got, _ := reopened.Passport(context.Background(), p.AgentID); got == nil || got.PublicKey != p.PublicKey {
+		t.Fatalf("reopened passport = %+v", got)
+	}
+}
@@ -0,0 +1,168 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/openapi"
+)
+
+// PrincipalInfo is the response to GET /v1/principals/{human_id}.
+type PrincipalInfo struct {
+	Principal
+	AgentIDs []string `json:"agent_ids"`
+}
+
+func newServeMux(r *Registry) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/principals", r.handleSubmitPrincipal)
+	mux.HandleFunc("POST /v1/passports", r.handleSubmitPassport)
+	mux.HandleFunc("GET /v1/principals/{human_id}", r.handlePrincipal)
+	mux.HandleFunc("GET /v1/principals/{human_id}/passports", r.handlePrincipalPassports)
+	mux.HandleFunc("GET /v1/passports/{agent_id}", r.handlePassport)
+	mux.HandleFunc("GET /v1/snapshot", r.handleSnapshot)
+	mux.HandleFunc("GET /health", r.handleHealth)
+	mux.Handle("GET /openapi.json", openapi.Handler())
+	return mux
+}
+
+// ServeHTTP serves the registry API described in the package comment.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
+func (r *Registry) handleHealth(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	principals, passports, seq := len(r.principals), len(r.passports), r.sequence
+	r.mu.RUnlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"ok":         true,
+		"service":    "dcp-registry",
+		"principals": principals,
+		"passports":  passports,
+		"sequence":   seq,
+		"snapshots":  r.cfg.Signer != nil,
+	})
+}
+
+func (r *Registry) handleSubmitPrincipal(w http.ResponseWriter, req *http.Request) {
+	var body Principal
+	if !r.readBody(w, req, &body) {
+		return
+	}
+	if body.PublicKeyB64 == "" {
+		writeError(w, http.StatusBadRequest, "public_key_b64 is required")
+		return
+	}
+	if err := r.SubmitPrincipal(body.Record, body.PublicKeyB64); err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"human_id": body.Record.HumanID, "sequence": r.Sequence()})
+}
+
+func (r *Registry) handleSubmitPassport(w http.ResponseWriter, req *http.Request) {
+	var p dcp.AgentPassport
+	if !r.readBody(w, req, &p) {
+		return
+	}
+	if err := r.SubmitPassport(p); err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"agent_id": p.AgentID, "sequence": r.Sequence()})
+}
+
+func (r *Registry) handlePrincipal(w http.ResponseWriter, req *http.Request) {
+	humanID := req.PathValue("human_id")
+	p := r.Principal(humanID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "no principal "+humanID)
+		return
+	}
+	info := PrincipalInfo{Principal: *p, AgentIDs: []string{}}
+	for _, pp := range r.PassportsOf(humanID) {
+		info.AgentIDs = append(info.AgentIDs, pp.AgentID)
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (r *Registry) handlePrincipalPassports(w http.ResponseWriter, req *http.Request) {
+	humanID := req.PathValue("human_id")
+	if r.Principal(humanID) == nil {
+		writeError(w, http.StatusNotFound, "no principal "+humanID)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"passports": r.PassportsOf(humanID)})
+}
+
+func (r *Registry) handlePassport(w http.ResponseWriter, req *http.Request) {
+	agentID := req.PathValue("agent_id")
+	p, _ := r.Passport(req.Context(), agentID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "no passport for "+agentID)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (r *Registry) handleSnapshot(w http.ResponseWriter, req *http.Request) {
+	if r.cfg.Signer == nil {
+		writeError(w, http.StatusNotImplemented, "no snapshot signer configured")
+		return
+	}
+	s, err := r.Snapshot()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s)
+}
+
+// readBody decodes the request body into v, answering the request itself
+// when it cannot.
+func (r *Registry) readBody(w http.ResponseWriter, req *http.Request, v interface{}) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, r.cfg.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", r.cfg.MaxBodyBytes))
+			return false
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+func writeSubmitError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrInvalid):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrConflict):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrUnknownPrincipal):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
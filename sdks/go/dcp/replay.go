@@ -0,0 +1,114 @@
+package dcp
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrReplay is returned for an intent presented again within the replay
+// window.
+var ErrReplay = errors.New("intent replayed")
+
+// NewNonce returns 16 random bytes in hex, for an intent's nonce. It panics
+// if the random source fails, which crypto/rand does not.
+func (f *RecordFactory) NewNonce() string {
+	r := f.Rand
+	if r == nil {
+		r = entropy()
+	}
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(r, b); err != nil {
+		panic(fmt.Sprintf("dcp: nonce: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// NewNonce calls RecordFactory.NewNonce with the package entropy source.
+func NewNonce() string { return defaultRecords.NewNonce() }
+
+// A ReplayGuard remembers the intents a counterparty has accepted.
+// MemoryReplayGuard keeps them in the process.
+type ReplayGuard interface {
+	// Seen records key for ttl and reports whether it was already
+	// recorded and had not expired.
+	Seen(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// ReplayKey is the key a ReplayGuard remembers intent by: its agent and
+// nonce, or, for an intent without a nonce, its agent and hash, so the
+// same intent presented twice has the same key.
+func ReplayKey(intent *Intent) (string, error) {
+	if intent.Nonce != "" {
+		return intent.AgentID + ":nonce:" + intent.Nonce, nil
+	}
+	h, err := HashObject(intent)
+	if err != nil {
+		return "", fmt.Errorf("replay key: %w", err)
+	}
+	return intent.AgentID + ":intent:" + h, nil
+}
+
+// CheckReplay records intent in g for ttl and fails with ErrReplay if g has
+// seen it within ttl. Other errors are g's. ttl should be no shorter than
+// the counterparty accepts an intent for, e.g. its longest expiry.
+func CheckReplay(ctx context.Context, g ReplayGuard, intent *Intent, ttl time.Duration) error {
+	key, err := ReplayKey(intent)
+	if err != nil {
+		return err
+	}
+	seen, err := g.Seen(ctx, key, ttl)
+	if err != nil {
+		return fmt.Errorf("replay guard: %w", err)
+	}
+	if seen {
+		return fmt.Errorf("%w: %s of %s was already presented", ErrReplay, intent.IntentID, intent.AgentID)
+	}
+	return nil
+}
+
+// MemoryReplayGuard is a ReplayGuard in memory, for a single process.
+// Create one with NewMemoryReplayGuard; it is safe for concurrent use.
+type MemoryReplayGuard struct {
+	mu       sync.Mutex
+	clock    func() time.Time
+	expires  map[string]time.Time
+	lastScan time.Time
+}
+
+var _ ReplayGuard = (*MemoryReplayGuard)(nil)
+
+// NewMemoryReplayGuard returns an empty MemoryReplayGuard. clock may be
+// nil, for the package clock.
+func NewMemoryReplayGuard(clock func() time.Time) *MemoryReplayGuard {
+	if clock == nil {
+		clock = clockNow
+	}
+	return &MemoryReplayGuard{clock: clock, expires: map[string]time.Time{}}
+}
+
+// Seen implements ReplayGuard.
+func (g *MemoryReplayGuard) Seen(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := g.clock()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// Drop expired keys at most once a second, so memory follows the
+	// number of live keys rather than every key ever seen.
+	if now.Sub(g.lastScan) >= time.Second {
+		for k, exp := range g.expires {
+			if !now.Before(exp) {
+				delete(g.expires, k)
+			}
+		}
+		g.lastScan = now
+	}
+	if exp, ok := g.expires[key]; ok && now.Before(exp) {
+		return true, nil
+	}
+	g.expires[key] = now.Add(ttl)
+	return false, nil
+}
@@ -0,0 +1,58 @@
+package dcp_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestReplayGuard(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := dcp.NewMemoryReplayGuard(func() time.Time { return now })
+	ctx := context.Background()
+	intent := dcp.Intent{DCPVersion: "1.0", IntentID: "intent001", AgentID: "agent001", HumanID: "human001",
+		Timestamp: "2026-01-01T00:00:00Z", ActionType: "browse", Target: dcp.IntentTarget{Channel: "web"},
+		DataClasses: []string{"none"}, EstimatedImpact: "low"}
+
+	if err := dcp.CheckReplay(ctx, g, &intent, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := dcp.CheckReplay(ctx, g, &intent, time.Hour); !errors.Is(err, dcp.ErrReplay) {
+		t.Fatalf("same intent again: %v", err)
+	}
+	other := intent
+	other.IntentID = "intent002"
+	if err := dcp.CheckReplay(ctx, g, &other, time.Hour); err != nil {
+		t.Fatalf("another intent: %v", err)
+	}
+
+	f := dcp.RecordFactory{Rand: bytes.NewReader(bytes.Repeat([]byte{0xab}, 16))}
+	intent.Nonce = f.NewNonce()
+	if intent.Nonce != strings.Repeat("ab", 16) {
+		t.Fatalf("nonce = %s", intent.Nonce)
+	}
+	if err := dcp.CheckReplay(ctx, g, &intent, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// With a nonce, a changed intent is still a replay.
+	intent.ActionType = "send_email"
+	if err := dcp.CheckReplay(ctx, g, &intent, time.Hour); !errors.Is(err, dcp.ErrReplay) {
+		t.Fatalf("nonce reused: %v", err)
+	}
+	// Keys are forgotten after their ttl.
+	now = now.Add(time.Hour)
+	if err := dcp.CheckReplay(ctx, g, &intent, time.Hour); err != nil {
+		t.Fatalf("after the ttl: %v", err)
+	}
+
+	intent.Nonce = "short"
+	var verrs dcp.ValidationErrors
+	if err := intent.Validate(); !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Pointer != "/nonce" {
+		t.Fatalf("short nonce: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package dcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Sign sets Signature to s's signature over the canonical record with an
+// empty signature, the form revocation records are signed in. The record is
+// normally signed by the responsible principal named in human_id.
+func (r *RevocationRecord) Sign(s BundleSigner) error {
+	r.Signature = ""
+	sig, err := signWith(s, r)
+	if err != nil {
+		return fmt.Errorf("sign revocation of %s: %w", r.AgentID, err)
+	}
+	r.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against the signer's public key.
+func (r *RevocationRecord) VerifySignature(publicKeyB64 string) (bool, error) {
+	if r.Signature == "" {
+		return false, fmt.Errorf("revocation of %s has no signature", r.AgentID)
+	}
+	unsigned := *r
+	unsigned.Signature = ""
+	return VerifyObject(unsigned, r.Signature, publicKeyB64)
+}
+
+// VerifyPrincipalSignature checks that r is signed on behalf of its
+// principal, whose key is principalKeyB64: by that key, or, when r carries
+// powers of attorney, by the attorney they lead to, granted AttorneyRevoke
+// at r's timestamp.
+func (r *RevocationRecord) VerifyPrincipalSignature(principalKeyB64 string) (bool, error) {
+	if len(r.PowersOfAttorney) == 0 {
+		return r.VerifySignature(principalKeyB64)
+	}
+	at, err := ParseTime(r.Timestamp)
+	if err != nil {
+		return false, fmt.Errorf("revocation of %s: timestamp: %v", r.AgentID, err)
+	}
+	attorney, err := CheckPowerOfAttorney(r.PowersOfAttorney, r.HumanID, principalKeyB64, AttorneyRevoke, at)
+	if err != nil {
+		return false, fmt.Errorf("revocation of %s: %w", r.AgentID, err)
+	}
+	return r.VerifySignature(attorney.PublicKey)
+}
+
+// RevocationList is a verifier's local set of signed revocation records,
+// gathered from files, peers or a registry (see docs/STORAGE_AND_ANCHORING.md).
+// It holds at most one record per agent: the first one added.
+type RevocationList struct {
+	Revocations []RevocationRecord `json:"revocations"`
+}
+
+// Add adds r unless the list already revokes r.AgentID, and reports whether
+// it was added.
+func (l *RevocationList) Add(r RevocationRecord) bool {
+	if _, ok := l.Lookup(r.AgentID); ok {
+		return false
+	}
+	l.Revocations = append(l.Revocations, r)
+	return true
+}
+
+// Lookup returns the revocation of agentID, if the list has one.
+func (l *RevocationList) Lookup(agentID string) (RevocationRecord, bool) {
+	for _, r := range l.Revocations {
+		if r.AgentID == agentID {
+			return r, true
+		}
+	}
+	return RevocationRecord{}, false
+}
+
+// IsRevoked reports whether the list revokes agentID.
+func (l *RevocationList) IsRevoked(agentID string) bool {
+	_, ok := l.Lookup(agentID)
+	return ok
+}
+
+// RevocationChecker looks up whether an agent has been revoked when a bundle
+// is verified. CheckRevocation returns nil and no error for an agent that is
+// not revoked; an error means the answer is unknown, and callers should fail
+// closed.
+type RevocationChecker interface {
+	CheckRevocation(ctx context.Context, agentID string) (*RevocationRecord, error)
+}
+
+var _ RevocationChecker = (*RevocationList)(nil)
+
+// CheckRevocation implements RevocationChecker.
+func (l *RevocationList) CheckRevocation(ctx context.Context, agentID string) (*RevocationRecord, error) {
+	if r, ok := l.Lookup(agentID); ok {
+		return &r, nil
+	}
+	return nil, nil
+}
+
+// ReadRevocationList reads a list written by WriteRevocationList. A missing
+// file is an empty list.
+func ReadRevocationList(path string) (*RevocationList, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &RevocationList{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var l RevocationList
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("revocation list %s: %w", path, err)
+	}
+	return &l, nil
+}
+
+// WriteRevocationList writes l as indented JSON.
+func WriteRevocationList(path string, l *RevocationList) error {
+	if l.Revocations == nil {
+		l = &RevocationList{Revocations: []RevocationRecord{}}
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
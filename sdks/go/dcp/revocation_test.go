@@ -0,0 +1,65 @@
+package dcp_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestRevocationRecordSign(t *testing.T) {
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := dcp.NewRevocationRecord(dcp.NewAgentID(), dcp.NewHumanID(), "key compromised")
+	if err := r.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := r.VerifySignature(kp.PublicKeyB64); !ok || err != nil {
+		t.Fatalf("VerifySignature = %v, %v", ok, err)
+	}
+	r.Reason = "changed"
+	if ok, _ := r.VerifySignature(kp.PublicKeyB64); ok {
+		t.Fatal("tampered revocation verified")
+	}
+}
+
+func TestRevocationList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "revocations.json")
+	l, err := dcp.ReadRevocationList(path)
+	if err != nil || len(l.Revocations) != 0 {
+		t.Fatalf("missing file: %v, %v", l, err)
+	}
+	first := dcp.NewRevocationRecord("dcp:agent:a1", "dcp:human:h1", "first")
+	if !l.Add(first) || l.Add(dcp.NewRevocationRecord("dcp:agent:a1", "dcp:human:h1", "again")) {
+		t.Fatal("Add should keep one record per agent")
+	}
+	if err := dcp.WriteRevocationList(path, l); err != nil {
+		t.Fatal(err)
+	}
+	l, err = dcp.ReadRevocationList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := l.Lookup("dcp:agent:a1"); !ok || r.Reason != "first" {
+		t.Fatalf("Lookup = %+v, %v", r, ok)
+	}
+	if l.IsRevoked("dcp:agent:a2") {
+		t.Fatal("unlisted agent revoked")
+	}
+	if r, err := l.CheckRevocation(context.Background(), "dcp:agent:a1"); err != nil || r == nil || r.Reason != "first" {
+		t.Fatalf("CheckRevocation = %+v, %v", r, err)
+	}
+	if r, err := l.CheckRevocation(context.Background(), "dcp:agent:a2"); err != nil || r != nil {
+		t.Fatalf("CheckRevocation(unlisted) = %+v, %v", r, err)
+	}
+}
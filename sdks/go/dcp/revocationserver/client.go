@@ -0,0 +1,151 @@
+package revocationserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// ListChecker is a dcp.RevocationChecker backed by the signed list of a
+// revocation registry. Unlike the per-agent /check answers, the list is
+// verified under the registry's key, and a list with a lower sequence than
+// one already seen is rejected, so neither a forged nor a replayed list can
+// un-revoke an agent.
+type ListChecker struct {
+	// URL is the registry base URL, e.g. "http://localhost:3003".
+	URL string
+	// PublicKeyB64 is the registry's list signing key.
+	PublicKeyB64 string
+	// MaxAge is how long a fetched list is used before it is fetched again;
+	// zero means one minute. Once it has passed, a failed fetch is an error
+	// rather than an answer from the stale list.
+	MaxAge time.Duration
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	mu      sync.Mutex
+	list    *SignedList
+	fetched time.Time
+}
+
+var _ dcp.RevocationChecker = (*ListChecker)(nil)
+
+// CheckRevocation implements dcp.RevocationChecker.
+func (c *ListChecker) CheckRevocation(ctx context.Context, agentID string) (*dcp.RevocationRecord, error) {
+	l, err := c.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return l.RevocationList().CheckRevocation(ctx, agentID)
+}
+
+func (c *ListChecker) current(ctx context.Context) (*SignedList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	maxAge := c.MaxAge
+	if maxAge <= 0 {
+		maxAge = time.Minute
+	}
+	if c.list != nil && time.Since(c.fetched) < maxAge {
+		return c.list, nil
+	}
+	l, err := FetchList(ctx, c.HTTPClient, c.URL, c.PublicKeyB64)
+	if err != nil {
+		return nil, err
+	}
+	if c.list != nil && l.Sequence < c.list.Sequence {
+		return nil, fmt.Errorf("revocation list from %s has sequence %d, older than %d", c.URL, l.Sequence, c.list.Sequence)
+	}
+	c.list, c.fetched = l, time.Now()
+	return l, nil
+}
+
+// FetchList fetches the signed list of the registry at baseURL and verifies
+// it under publicKeyB64. A nil client means http.DefaultClient.
+func FetchList(ctx context.Context, client *http.Client, baseURL, publicKeyB64 string) (*SignedList, error) {
+	u := strings.TrimRight(baseURL, "/") + "/list"
+	var l SignedList
+	found, err := getJSON(ctx, client, u, &l)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s: %s", u, http.StatusText(http.StatusNotFound))
+	}
+	if ok, err := l.Verify(publicKeyB64); err != nil || !ok {
+		return nil, fmt.Errorf("%s: list signature does not verify under the registry key", u)
+	}
+	if l.Sequence != uint64(len(l.Revocations)) {
+		return nil, fmt.Errorf("%s: sequence %d does not match %d revocations", u, l.Sequence, len(l.Revocations))
+	}
+	return &l, nil
+}
+
+// RegistryAuthority is an Authority backed by a passport registry's HTTP API
+// (package registry): a revocation must come from the principal the agent's
+// passport binds to, signed with that principal's registered key.
+type RegistryAuthority struct {
+	// URL is the registry base URL, e.g. "http://localhost:8081".
+	URL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ Authority = (*RegistryAuthority)(nil)
+
+// RevocationKey implements Authority.
+func (a *RegistryAuthority) RevocationKey(ctx context.Context, agentID, humanID string) (string, error) {
+	base := strings.TrimRight(a.URL, "/")
+	var passport dcp.AgentPassport
+	found, err := getJSON(ctx, a.HTTPClient, base+"/v1/passports/"+url.PathEscape(agentID), &passport)
+	if err != nil || !found || passport.PrincipalBindingReference != humanID {
+		return "", err
+	}
+	var principal struct {
+		PublicKeyB64 string `json:"public_key_b64"`
+	}
+	if _, err := getJSON(ctx, a.HTTPClient, base+"/v1/principals/"+url.PathEscape(humanID), &principal); err != nil {
+		return "", err
+	}
+	return principal.PublicKeyB64, nil
+}
+
+// getJSON decodes the response to GET u into v. A 404 is reported as
+// not found rather than as an error.
+func getJSON(ctx context.Context, client *http.Client, u string, v interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%s: %s", u, resp.Status)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("%s: %w", u, err)
+	}
+	return true, nil
+}
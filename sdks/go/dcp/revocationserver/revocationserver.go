@@ -0,0 +1,306 @@
+// Package revocationserver is an HTTP revocation registry for V1 agents. It
+// speaks the V1 API of services/revocation, so verifyserver.RegistryRevocations
+// and existing clients work against it unchanged, and additionally signs the
+// list it publishes.
+//
+// Endpoints:
+//
+//	POST /revoke                           body: a signed RevocationRecord
+//	GET  /check/{agent_id}                 {"revoked": bool, "record": {...}}
+//	GET  /list                             the SignedList
+//	GET  /.well-known/dcp-revocations.json the SignedList
+//	GET  /health
+//	GET  /openapi.json                     the OpenAPI document of the DCP services
+//
+// A revocation is accepted only if its signature verifies under the key the
+// Authority names for its agent and human_id, or under the key of an
+// attorney that key granted the right to revoke. Revocations are permanent:
+// the list only grows, and its sequence number is the number of records in
+// it, so a verifier holding a list can tell whether another one is newer.
+package revocationserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/openapi"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/webhook"
+)
+
+// Authority decides who may revoke an agent. RevocationKey returns the key
+// a revocation of agentID by humanID must be signed with, or "" if humanID
+// may not revoke agentID. registry.Registry is an Authority that accepts
+// revocations from an agent's own principal.
+type Authority interface {
+	RevocationKey(ctx context.Context, agentID, humanID string) (string, error)
+}
+
+// KeyMap is a fixed Authority mapping human_id to the principal's key. It
+// lets a principal revoke any agent that names it.
+type KeyMap map[string]string
+
+// RevocationKey implements Authority.
+func (m KeyMap) RevocationKey(ctx context.Context, agentID, humanID string) (string, error) {
+	return m[humanID], nil
+}
+
+// Config configures a Server.
+type Config struct {
+	// Authority checks submitted revocations. Nil rejects all submissions,
+	// which leaves a read-only mirror of Path.
+	Authority Authority
+	// Signer signs the published list. Required.
+	Signer dcp.BundleSigner
+	// Path is the revocation list file, in the format of dcp revoke and
+	// dcp.WriteRevocationList, rewritten after every accepted revocation.
+	// Empty keeps the list in memory only.
+	Path string
+	// MaxBodyBytes caps request bodies; zero means 1 MiB.
+	MaxBodyBytes int64
+	// Now is the clock lists are stamped with; nil means time.Now.
+	Now func() time.Time
+	// Webhooks, if set, is sent an agent.revoked event for every accepted
+	// revocation.
+	Webhooks *webhook.Dispatcher
+}
+
+// SignedList is the published revocation list.
+type SignedList struct {
+	DCPVersion string `json:"dcp_version"`
+	// Sequence is the number of revocations in the list.
+	Sequence    uint64                 `json:"sequence"`
+	UpdatedAt   string                 `json:"updated_at"`
+	Revocations []dcp.RevocationRecord `json:"revocations"`
+	SignerKey   string                 `json:"signer_key"`
+	// Signature is over the canonical list with an empty signature.
+	Signature string `json:"signature"`
+}
+
+// Verify checks the list signature against the registry's key, which the
+// verifier must know in advance; SignerKey only says which key signed.
+func (l *SignedList) Verify(publicKeyB64 string) (bool, error) {
+	if l.Signature == "" {
+		return false, errors.New("revocation list has no signature")
+	}
+	unsigned := *l
+	unsigned.Signature = ""
+	return dcp.VerifyObject(unsigned, l.Signature, publicKeyB64)
+}
+
+// RevocationList returns the records as a dcp.RevocationList.
+func (l *SignedList) RevocationList() *dcp.RevocationList {
+	return &dcp.RevocationList{Revocations: l.Revocations}
+}
+
+// Server serves the revocation registry. Create one with New.
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+
+	mu     sync.RWMutex
+	list   *dcp.RevocationList
+	signed *SignedList
+}
+
+var _ dcp.RevocationChecker = (*Server)(nil)
+
+// New returns a Server for cfg, loading cfg.Path if it exists.
+func New(cfg Config) (*Server, error) {
+	if cfg.Signer == nil {
+		return nil, errors.New("revocationserver: a list signer is required")
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 1 << 20
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	list := &dcp.RevocationList{}
+	if cfg.Path != "" {
+		var err error
+		if list, err = dcp.ReadRevocationList(cfg.Path); err != nil {
+			return nil, fmt.Errorf("revocationserver: %w", err)
+		}
+	}
+	s := &Server{cfg: cfg, list: list, mux: http.NewServeMux()}
+	if err := s.sign(); err != nil {
+		return nil, err
+	}
+	s.mux.HandleFunc("POST /revoke", s.handleRevoke)
+	s.mux.HandleFunc("GET /check/{agent_id}", s.handleCheck)
+	s.mux.HandleFunc("GET /list", s.handleList)
+	s.mux.HandleFunc("GET /.well-known/dcp-revocations.json", s.handleList)
+	s.mux.HandleFunc("GET /health", s.handleHealth)
+	s.mux.Handle("GET /openapi.json", openapi.Handler())
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// sign replaces the published list with a freshly signed copy of s.list.
+// The caller holds mu, or has not shared s yet.
+func (s *Server) sign() error {
+	records := append([]dcp.RevocationRecord{}, s.list.Revocations...)
+	l := &SignedList{
+		DCPVersion:  "1.0",
+		Sequence:    uint64(len(records)),
+		UpdatedAt:   dcp.FormatTime(s.cfg.Now()),
+		Revocations: records,
+		SignerKey:   s.cfg.Signer.PublicKeyB64(),
+	}
+	canon, err := dcp.Canonicalize(l)
+	if err != nil {
+		return fmt.Errorf("revocationserver: sign list: %w", err)
+	}
+	if l.Signature, err = s.cfg.Signer.SignCanonical(canon); err != nil {
+		return fmt.Errorf("revocationserver: sign list: %w", err)
+	}
+	s.signed = l
+	return nil
+}
+
+// ErrUnauthorized reports a revocation whose signer may not revoke its agent.
+var ErrUnauthorized = errors.New("not authorized to revoke this agent")
+
+// Revoke adds r to the list after checking it with the Authority. It
+// reports false if the agent was already revoked, in which case the list
+// keeps the earlier record.
+func (s *Server) Revoke(ctx context.Context, r dcp.RevocationRecord) (bool, error) {
+	if err := r.Validate(); err != nil {
+		return false, err
+	}
+	if s.cfg.Authority == nil {
+		return false, fmt.Errorf("%w: this registry accepts no revocations", ErrUnauthorized)
+	}
+	key, err := s.cfg.Authority.RevocationKey(ctx, r.AgentID, r.HumanID)
+	if err != nil {
+		return false, fmt.Errorf("revocationserver: authority: %w", err)
+	}
+	if key == "" {
+		return false, fmt.Errorf("%s: %w %s", r.HumanID, ErrUnauthorized, r.AgentID)
+	}
+	if ok, err := r.VerifyPrincipalSignature(key); err != nil || !ok {
+		return false, fmt.Errorf("%w: signature does not verify under the key of %s", ErrUnauthorized, r.HumanID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.list.Add(r) {
+		return false, nil
+	}
+	if s.cfg.Path != "" {
+		if err := dcp.WriteRevocationList(s.cfg.Path, s.list); err != nil {
+			s.list.Revocations = s.list.Revocations[:len(s.list.Revocations)-1]
+			return false, fmt.Errorf("revocationserver: %w", err)
+		}
+	}
+	if err := s.sign(); err != nil {
+		return true, err
+	}
+	s.cfg.Webhooks.Emit(webhook.EventAgentRevoked, r)
+	return true, nil
+}
+
+// CheckRevocation implements dcp.RevocationChecker, so a verifyserver in the
+// same process can consult the registry directly.
+func (s *Server) CheckRevocation(ctx context.Context, agentID string) (*dcp.RevocationRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list.CheckRevocation(ctx, agentID)
+}
+
+// List returns the current signed list.
+func (s *Server) List() *SignedList {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.signed
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	l := s.List()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"ok":                 true,
+		"service":            "dcp-revocation",
+		"supported_versions": []string{"1.0"},
+		"total_revocations":  len(l.Revocations),
+		"sequence":           l.Sequence,
+		"signer_key":         l.SignerKey,
+	})
+}
+
+func (s *Server) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.cfg.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", s.cfg.MaxBodyBytes))
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var rec dcp.RevocationRecord
+	if err := json.Unmarshal(body, &rec); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if err := rec.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	added, err := s.Revoke(r.Context(), rec)
+	if errors.Is(err, ErrUnauthorized) {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		// The authority could not be asked or the list not saved.
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	existing, _ := s.CheckRevocation(r.Context(), rec.AgentID)
+	status := http.StatusCreated
+	if !added {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"ok":         true,
+		"agent_id":   rec.AgentID,
+		"revoked_at": existing.Timestamp,
+		"sequence":   s.List().Sequence,
+	})
+}
+
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	agentID := r.PathValue("agent_id")
+	rec, _ := s.CheckRevocation(r.Context(), agentID)
+	if rec != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"revoked": true, "record": rec})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"revoked": false, "agent_id": agentID})
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.List())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
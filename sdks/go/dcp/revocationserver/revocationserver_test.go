@@ -0,0 +1,211 @@
+package revocationserver_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/registry"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/revocationserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/verifyserver"
+)
+
+var _ revocationserver.Authority = (*registry.Registry)(nil)
+
+func signedRevocation(t *testing.T, s dcp.BundleSigner, agentID, humanID string) dcp.RevocationRecord {
+	t.Helper()
+	r := dcp.NewRevocationRecord(agentID, humanID, "key compromised")
+	if err := r.Sign(s); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func post(t *testing.T, h http.Handler, target string, v interface{}) (int, map[string]interface{}) {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body)))
+	var out map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec.Code, out
+}
+
+func TestRevokeAndCheck(t *testing.T) {
+	listSigner, listKey := dcptest.NewSigner(t)
+	alice, aliceKey := dcptest.NewSigner(t)
+	mallory, _ := dcptest.NewSigner(t)
+	path := filepath.Join(t.TempDir(), "revocations.json")
+	srv, err := revocationserver.New(revocationserver.Config{
+		Authority: revocationserver.KeyMap{"did:human:alice123": aliceKey},
+		Signer:    listSigner,
+		Path:      path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	checker := &verifyserver.RegistryRevocations{URL: ts.URL}
+	ctx := context.Background()
+
+	if rec, err := checker.CheckRevocation(ctx, "did:agent:agent123"); rec != nil || err != nil {
+		t.Fatalf("before revocation: %v, %v", rec, err)
+	}
+	for name, r := range map[string]dcp.RevocationRecord{
+		"wrong signer":    signedRevocation(t, mallory, "did:agent:agent123", "did:human:alice123"),
+		"unknown human":   signedRevocation(t, mallory, "did:agent:agent123", "did:human:mallory"),
+		"unsigned record": dcp.NewRevocationRecord("did:agent:agent123", "did:human:alice123", "x"),
+	} {
+		if code, out := post(t, srv, "/revoke", r); code != http.StatusForbidden && code != http.StatusBadRequest {
+			t.Errorf("%s: %d %v", name, code, out)
+		}
+	}
+
+	first := signedRevocation(t, alice, "did:agent:agent123", "did:human:alice123")
+	if code, out := post(t, srv, "/revoke", first); code != http.StatusCreated || out["sequence"] != float64(1) {
+		t.Fatalf("revoke: %d %v", code, out)
+	}
+	if code, out := post(t, srv, "/revoke", signedRevocation(t, alice, "did:agent:agent123", "did:human:alice123")); code != http.StatusOK || out["revoked_at"] != first.Timestamp {
+		t.Fatalf("second revoke: %d %v", code, out)
+	}
+	if rec, err := checker.CheckRevocation(ctx, "did:agent:agent123"); err != nil || rec == nil || rec.Signature != first.Signature {
+		t.Fatalf("after revocation: %v, %v", rec, err)
+	}
+
+	l, err := revocationserver.FetchList(ctx, nil, ts.URL, listKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Sequence != 1 || !l.RevocationList().IsRevoked("did:agent:agent123") {
+		t.Fatalf("list: %+v", l)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/dcp-revocations.json", nil))
+	var wellKnown revocationserver.SignedList
+	if err := json.Unmarshal(rec.Body.Bytes(), &wellKnown); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := wellKnown.Verify(listKey); !ok || err != nil || wellKnown.Sequence != 1 {
+		t.Fatalf("well-known list: %v, %v, %+v", ok, err, wellKnown)
+	}
+	if _, err := revocationserver.FetchList(ctx, nil, ts.URL, aliceKey); err == nil {
+		t.Fatal("list verified under the wrong key")
+	}
+
+	// The list file is in the format dcp revoke and FileRevocations use.
+	if l, err := dcp.ReadRevocationList(path); err != nil || !l.IsRevoked("did:agent:agent123") {
+		t.Fatalf("list file: %v, %v", l, err)
+	}
+	reopened, err := revocationserver.New(revocationserver.Config{Signer: listSigner, Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.List().Sequence != 1 {
+		t.Fatalf("reopened sequence = %d", reopened.List().Sequence)
+	}
+	if _, err := reopened.Revoke(ctx, signedRevocation(t, alice, "did:agent:other", "did:human:alice123")); err == nil {
+		t.Fatal("server without an authority accepted a revocation")
+	}
+}
+
+func TestListChecker(t *testing.T) {
+	listSigner, listKey := dcptest.NewSigner(t)
+	alice, aliceKey := dcptest.NewSigner(t)
+	srv, err := revocationserver.New(revocationserver.Config{
+		Authority: revocationserver.KeyMap{"did:human:alice123": aliceKey},
+		Signer:    listSigner,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replay []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if replay != nil && r.URL.Path == "/list" {
+			w.Write(replay)
+			return
+		}
+		srv.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+
+	empty, err := json.Marshal(srv.List())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.Revoke(ctx, signedRevocation(t, alice, "did:agent:agent123", "did:human:alice123")); err != nil {
+		t.Fatal(err)
+	}
+	// MaxAge of a nanosecond refetches on every check.
+	checker := &revocationserver.ListChecker{URL: ts.URL, PublicKeyB64: listKey, MaxAge: 1}
+	if rec, err := checker.CheckRevocation(ctx, "did:agent:agent123"); err != nil || rec == nil {
+		t.Fatalf("CheckRevocation = %v, %v", rec, err)
+	}
+	// An older, validly signed list must not un-revoke the agent.
+	replay = empty
+	if rec, err := checker.CheckRevocation(ctx, "did:agent:agent123"); err == nil {
+		t.Fatalf("replayed list accepted: %v", rec)
+	}
+	bad := &revocationserver.ListChecker{URL: ts.URL, PublicKeyB64: aliceKey}
+	if _, err := bad.CheckRevocation(ctx, "did:agent:agent123"); err == nil {
+		t.Fatal("list accepted under the wrong key")
+	}
+}
+
+func TestRegistryAuthority(t *testing.T) {
+	principal, principalKey := dcptest.NewSigner(t)
+	agent, _ := dcptest.NewSigner(t)
+	listSigner, _ := dcptest.NewSigner(t)
+	reg, err := registry.New(registry.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpr := dcp.NewResponsiblePrincipalRecord("Alice", dcp.EntityNaturalPerson, "US")
+	if err := rpr.Sign(principal); err != nil {
+		t.Fatal(err)
+	}
+	p := dcp.NewAgentPassport(rpr.HumanID, "", nil, dcp.RiskTierLow)
+	if err := p.Sign(agent); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SubmitPrincipal(rpr, principalKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SubmitPassport(p); err != nil {
+		t.Fatal(err)
+	}
+	regServer := httptest.NewServer(reg)
+	defer regServer.Close()
+
+	for name, authority := range map[string]revocationserver.Authority{
+		"in-process": reg,
+		"http":       &revocationserver.RegistryAuthority{URL: regServer.URL},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv, err := revocationserver.New(revocationserver.Config{Authority: authority, Signer: listSigner})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Only the agent's own principal may revoke it.
+			if code, _ := post(t, srv, "/revoke", signedRevocation(t, principal, "did:agent:unknown", rpr.HumanID)); code != http.StatusForbidden {
+				t.Fatalf("revocation of another agent: %d", code)
+			}
+			if code, _ := post(t, srv, "/revoke", signedRevocation(t, agent, p.AgentID, rpr.HumanID)); code != http.StatusForbidden {
+				t.Fatalf("revocation signed by the agent: %d", code)
+			}
+			if code, out := post(t, srv, "/revoke", signedRevocation(t, principal, p.AgentID, rpr.HumanID)); code != http.StatusCreated {
+				t.Fatalf("revocation by the principal: %d %v", code, out)
+			}
+		})
+	}
+}
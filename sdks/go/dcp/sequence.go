@@ -0,0 +1,68 @@
+package dcp
+
+import "fmt"
+
+// ChainAnchorFor returns the anchor a bundle following prev should declare.
+// The new bundle's first audit entry must use PrevEntryHash as its prev_hash.
+func ChainAnchorFor(prev *SignedBundle) (*ChainAnchor, error) {
+	entries := prev.Bundle.AuditEntries
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("chain anchor: previous bundle has no audit entries")
+	}
+	h, err := HashObject(entries[len(entries)-1])
+	if err != nil {
+		return nil, fmt.Errorf("chain anchor: %w", err)
+	}
+	return &ChainAnchor{PrevEntryHash: h, PrevBundleHash: prev.Signature.BundleHash}, nil
+}
+
+// VerifyBundleSequence verifies each bundle with VerifySignedBundle and then
+// checks that every bundle after the first is anchored to the final audit
+// entry (and, if declared, the bundle_hash) of the bundle before it, for the
+// same agent. The first bundle may start at GENESIS or at an anchor the
+// caller has verified separately.
+func VerifyBundleSequence(bundles []*SignedBundle, publicKeyB64 string) *VerificationResult {
+	if len(bundles) == 0 {
+		return &VerificationResult{Verified: false, Errors: []string{"empty bundle sequence"}}
+	}
+	for i, sb := range bundles {
+		if r := VerifySignedBundle(sb, publicKeyB64); !r.Verified {
+			errs := make([]string, len(r.Errors))
+			for j, e := range r.Errors {
+				errs[j] = fmt.Sprintf("bundle %d: %s", i, e)
+			}
+			return &VerificationResult{Verified: false, Errors: errs}
+		}
+		if i == 0 {
+			continue
+		}
+		prev := bundles[i-1]
+		if sb.Bundle.AgentPassport.AgentID != prev.Bundle.AgentPassport.AgentID {
+			return &VerificationResult{
+				Verified: false,
+				Errors:   []string{fmt.Sprintf("bundle %d: agent_id changed from %s to %s", i, prev.Bundle.AgentPassport.AgentID, sb.Bundle.AgentPassport.AgentID)},
+			}
+		}
+		anchor := sb.Bundle.ChainAnchor
+		if anchor == nil {
+			return &VerificationResult{Verified: false, Errors: []string{fmt.Sprintf("bundle %d: missing chain_anchor", i)}}
+		}
+		expected, err := ChainAnchorFor(prev)
+		if err != nil {
+			return &VerificationResult{Verified: false, Errors: []string{fmt.Sprintf("bundle %d: %v", i, err)}}
+		}
+		if anchor.PrevEntryHash != expected.PrevEntryHash {
+			return &VerificationResult{
+				Verified: false,
+				Errors:   []string{fmt.Sprintf("chain_anchor (bundle %d): expected %s, got %s", i, expected.PrevEntryHash, anchor.PrevEntryHash)},
+			}
+		}
+		if anchor.PrevBundleHash != "" && anchor.PrevBundleHash != expected.PrevBundleHash {
+			return &VerificationResult{
+				Verified: false,
+				Errors:   []string{fmt.Sprintf("chain_anchor (bundle %d): prev_bundle_hash expected %s, got %s", i, expected.PrevBundleHash, anchor.PrevBundleHash)},
+			}
+		}
+	}
+	return &VerificationResult{Verified: true}
+}
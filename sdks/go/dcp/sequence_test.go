@@ -0,0 +1,89 @@
+package dcp_test
+
+import (
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// anchoredBundle builds and signs a bundle whose chain starts at anchor.
+func anchoredBundle(t *testing.T, owner *dcp.Keypair, agentID string, anchor *dcp.ChainAnchor, auditIDs ...string) *dcp.SignedBundle {
+	t.Helper()
+	intent := dcp.Intent{DCPVersion: "1.0", IntentID: "intent-" + auditIDs[0], AgentID: agentID, HumanID: "human001",
+		Timestamp: "2026-01-01T00:00:00Z", ActionType: "api_call", Target: dcp.IntentTarget{Channel: "api"},
+		DataClasses: []string{"none"}, EstimatedImpact: "low"}
+	ih, _ := dcp.HashObject(intent)
+	prev := "GENESIS"
+	if anchor != nil {
+		prev = anchor.PrevEntryHash
+	}
+	var entries []dcp.AuditEntry
+	var leaves []string
+	for _, id := range auditIDs {
+		e := dcp.AuditEntry{DCPVersion: "1.0", AuditID: id, PrevHash: prev, Timestamp: "2026-01-01T00:00:01Z",
+			AgentID: agentID, HumanID: "human001", IntentID: intent.IntentID, IntentHash: ih,
+			PolicyDecision: "approved", Outcome: "ok"}
+		h, _ := dcp.HashObject(e)
+		entries = append(entries, e)
+		leaves = append(leaves, h)
+		prev = h
+	}
+	bundle := dcp.CitizenshipBundle{
+		AgentPassport: dcp.AgentPassport{DCPVersion: "1.0", AgentID: agentID},
+		Intent:        intent,
+		AuditEntries:  entries,
+		ChainAnchor:   anchor,
+	}
+	root, _ := dcp.MerkleRootFromHexLeaves(leaves)
+	root = "sha256:" + root
+	bh, _ := dcp.HashObject(bundle)
+	sig, err := dcp.SignObject(bundle, owner.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dcp.SignedBundle{Bundle: bundle, Signature: dcp.BundleSignature{
+		Alg: "ed25519", BundleHash: "sha256:" + bh, MerkleRoot: &root, SigB64: sig,
+		SignerInfo: dcp.Signer{Type: "human", ID: "human001", PublicKeyB64: owner.PublicKeyB64},
+	}}
+}
+
+func TestVerifyBundleSequence(t *testing.T) {
+	owner, _ := dcp.GenerateKeypair()
+	first := anchoredBundle(t, owner, "agent001", nil, "audit001", "audit002")
+	anchor, err := dcp.ChainAnchorFor(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := anchoredBundle(t, owner, "agent001", anchor, "audit003")
+	anchor2, _ := dcp.ChainAnchorFor(second)
+	third := anchoredBundle(t, owner, "agent001", anchor2, "audit004", "audit005")
+
+	if r := dcp.VerifyBundleSequence([]*dcp.SignedBundle{first, second, third}, ""); !r.Verified {
+		t.Fatalf("sequence should verify: %v", r.Errors)
+	}
+	if r := dcp.VerifyBundleSequence([]*dcp.SignedBundle{first, third}, ""); r.Verified {
+		t.Fatal("a gap in the sequence must be detected")
+	}
+	unanchored := anchoredBundle(t, owner, "agent001", nil, "audit003")
+	if r := dcp.VerifyBundleSequence([]*dcp.SignedBundle{first, unanchored}, ""); r.Verified {
+		t.Fatal("a restarted chain must be detected")
+	}
+	other := anchoredBundle(t, owner, "agent002", anchor, "audit003")
+	if r := dcp.VerifyBundleSequence([]*dcp.SignedBundle{first, other}, ""); r.Verified {
+		t.Fatal("a different agent must not continue the chain")
+	}
+}
+
+func TestChainAnchorReplacesGenesis(t *testing.T) {
+	owner, _ := dcp.GenerateKeypair()
+	first := anchoredBundle(t, owner, "agent001", nil, "audit001")
+	anchor, _ := dcp.ChainAnchorFor(first)
+	second := anchoredBundle(t, owner, "agent001", anchor, "audit002")
+	if r := dcp.VerifySignedBundle(second, ""); !r.Verified {
+		t.Fatalf("anchored bundle should verify on its own: %v", r.Errors)
+	}
+	second.Bundle.ChainAnchor = nil
+	if r := dcp.VerifySignedBundle(second, ""); r.Verified {
+		t.Fatal("anchored chain must not verify as a GENESIS chain")
+	}
+}
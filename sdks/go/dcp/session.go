@@ -0,0 +1,292 @@
+package dcp
+
+import (
+	"fmt"
+	"time"
+)
+
+// Session member types.
+const (
+	SessionMemberIntent     = "intent"
+	SessionMemberAuditEntry = "audit_entry"
+)
+
+// Session groups the intents of one multi-step action, such as browse, fill
+// in a form and submit it, with the audit entries that record them. Members
+// form a hash chain rooted in the session header, so they cannot be
+// reordered, dropped or moved to another session without breaking it. The
+// intent and audit schemas are closed, so the grouping lives here rather
+// than in the records themselves.
+type Session struct {
+	DCPVersion string `json:"dcp_version"`
+	SessionID  string `json:"session_id"`
+	AgentID    string `json:"agent_id"`
+	HumanID    string `json:"human_id"`
+	StartedAt  string `json:"started_at"`
+	// ClosedAt is set by Close; a closed session takes no more members.
+	ClosedAt string          `json:"closed_at,omitempty"`
+	Members  []SessionMember `json:"members"`
+	// Signature is by the agent key over the canonical session with an
+	// empty signature.
+	Signature string `json:"signature,omitempty"`
+}
+
+// SessionMember is one link of a session's chain.
+type SessionMember struct {
+	// PrevHash is the hash of the previous member, or of the session
+	// header for the first.
+	PrevHash string `json:"prev_hash"`
+	// Type is SessionMemberIntent or SessionMemberAuditEntry.
+	Type string `json:"type"`
+	// ID is the intent_id or audit_id of the member.
+	ID string `json:"id"`
+	// Hash is the HashObject hash of the intent or audit entry.
+	Hash string `json:"hash"`
+}
+
+// sessionHeader is the part of a session its chain is rooted in.
+type sessionHeader struct {
+	DCPVersion string `json:"dcp_version"`
+	SessionID  string `json:"session_id"`
+	AgentID    string `json:"agent_id"`
+	HumanID    string `json:"human_id"`
+	StartedAt  string `json:"started_at"`
+}
+
+// NewSession returns an open session for agentID acting for humanID, with a
+// generated session_id, started now.
+func (f *RecordFactory) NewSession(agentID, humanID string) *Session {
+	return &Session{
+		DCPVersion: DCPVersion,
+		SessionID:  f.NewSessionID(),
+		AgentID:    agentID,
+		HumanID:    humanID,
+		StartedAt:  f.timestamp(),
+		Members:    []SessionMember{},
+	}
+}
+
+// NewSession returns an open session; see RecordFactory.NewSession.
+func NewSession(agentID, humanID string) *Session {
+	return defaultRecords.NewSession(agentID, humanID)
+}
+
+// Head returns the hash the next member will carry as prev_hash.
+func (s *Session) Head() (string, error) {
+	if n := len(s.Members); n > 0 {
+		return HashObject(s.Members[n-1])
+	}
+	return HashObject(s.header())
+}
+
+func (s *Session) header() sessionHeader {
+	return sessionHeader{DCPVersion: s.DCPVersion, SessionID: s.SessionID, AgentID: s.AgentID, HumanID: s.HumanID, StartedAt: s.StartedAt}
+}
+
+// AddIntent appends intent, which must be the session's agent and
+// principal's, and returns the new head.
+func (s *Session) AddIntent(intent Intent) (string, error) {
+	if intent.AgentID != s.AgentID || intent.HumanID != s.HumanID {
+		return "", fmt.Errorf("session %s: intent %s is not by %s for %s", s.SessionID, intent.IntentID, s.AgentID, s.HumanID)
+	}
+	if s.member(SessionMemberIntent, intent.IntentID) >= 0 {
+		return "", fmt.Errorf("session %s: intent %s already added", s.SessionID, intent.IntentID)
+	}
+	return s.add(SessionMemberIntent, intent.IntentID, intent)
+}
+
+// AddAuditEntry appends entry, which must record an intent already in the
+// session, and returns the new head.
+func (s *Session) AddAuditEntry(entry AuditEntry) (string, error) {
+	i := s.member(SessionMemberIntent, entry.IntentID)
+	if i < 0 {
+		return "", fmt.Errorf("session %s: audit entry %s records intent %s, which is not in the session", s.SessionID, entry.AuditID, entry.IntentID)
+	}
+	if entry.IntentHash != s.Members[i].Hash {
+		return "", fmt.Errorf("session %s: audit entry %s: intent_hash does not match intent %s", s.SessionID, entry.AuditID, entry.IntentID)
+	}
+	if s.member(SessionMemberAuditEntry, entry.AuditID) >= 0 {
+		return "", fmt.Errorf("session %s: audit entry %s already added", s.SessionID, entry.AuditID)
+	}
+	return s.add(SessionMemberAuditEntry, entry.AuditID, entry)
+}
+
+func (s *Session) add(typ, id string, record interface{}) (string, error) {
+	if s.ClosedAt != "" {
+		return "", fmt.Errorf("session %s is closed", s.SessionID)
+	}
+	hash, err := HashObject(record)
+	if err != nil {
+		return "", fmt.Errorf("session %s: hash %s: %w", s.SessionID, id, err)
+	}
+	prev, err := s.Head()
+	if err != nil {
+		return "", fmt.Errorf("session %s: %w", s.SessionID, err)
+	}
+	s.Members = append(s.Members, SessionMember{PrevHash: prev, Type: typ, ID: id, Hash: hash})
+	return s.Head()
+}
+
+// member returns the index of the member of type typ and ID id, or -1.
+func (s *Session) member(typ, id string) int {
+	for i, m := range s.Members {
+		if m.Type == typ && m.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Close marks the session finished at now.
+func (s *Session) Close(now time.Time) {
+	s.ClosedAt = FormatTime(now)
+}
+
+// Sign sets Signature with the agent key.
+func (s *Session) Sign(signer BundleSigner) error {
+	s.Signature = ""
+	sig, err := signWith(signer, s)
+	if err != nil {
+		return fmt.Errorf("sign session %s: %w", s.SessionID, err)
+	}
+	s.Signature = sig
+	return nil
+}
+
+// VerifySignature checks Signature against the agent's publicKeyB64.
+func (s *Session) VerifySignature(publicKeyB64 string) (bool, error) {
+	if s.Signature == "" {
+		return false, fmt.Errorf("session %s is not signed", s.SessionID)
+	}
+	unsigned := *s
+	unsigned.Signature = ""
+	return VerifyObject(unsigned, s.Signature, publicKeyB64)
+}
+
+// VerifySession checks that s is internally consistent with the intents and
+// audit entries it groups, which must be exactly its members:
+//
+//   - the chain links every member to the one before and the first to the
+//     session header;
+//   - each member's hash is that of its record, and no record appears twice;
+//   - intents and audit entries are by the session's agent for its
+//     principal and fall between started_at and closed_at;
+//   - each audit entry records an intent that joined the session before it,
+//     and is not older than that intent.
+//
+// The signature is checked separately with VerifySignature.
+func VerifySession(s *Session, intents []Intent, entries []AuditEntry) *VerificationResult {
+	var errs []string
+	fail := func(format string, args ...interface{}) { errs = append(errs, fmt.Sprintf(format, args...)) }
+
+	started, err := ParseTime(s.StartedAt)
+	if err != nil {
+		fail("started_at: %v", err)
+	}
+	var closed time.Time
+	if s.ClosedAt != "" {
+		if closed, err = ParseTime(s.ClosedAt); err != nil {
+			fail("closed_at: %v", err)
+		} else if closed.Before(started) {
+			fail("closed_at is before started_at")
+		}
+	}
+	inWindow := func(what, ts string) (time.Time, bool) {
+		t, err := ParseTime(ts)
+		switch {
+		case err != nil:
+			fail("%s: timestamp: %v", what, err)
+		case t.Before(started):
+			fail("%s is before the session started", what)
+		case !closed.IsZero() && t.After(closed):
+			fail("%s is after the session closed", what)
+		default:
+			return t, true
+		}
+		return t, false
+	}
+
+	byIntent := map[string]*Intent{}
+	for i := range intents {
+		byIntent[intents[i].IntentID] = &intents[i]
+	}
+	byEntry := map[string]*AuditEntry{}
+	for i := range entries {
+		byEntry[entries[i].AuditID] = &entries[i]
+	}
+
+	head, err := HashObject(s.header())
+	if err != nil {
+		fail("session header: %v", err)
+	}
+	seen := map[string]bool{}
+	intentAt := map[string]time.Time{}
+	intentHash := map[string]string{}
+	for i, m := range s.Members {
+		what := fmt.Sprintf("member %d (%s %s)", i, m.Type, m.ID)
+		if m.PrevHash != head {
+			fail("%s: prev_hash does not link to the previous member", what)
+		}
+		if head, err = HashObject(m); err != nil {
+			fail("%s: %v", what, err)
+		}
+		if seen[m.Type+" "+m.ID] {
+			fail("%s: appears twice", what)
+		}
+		seen[m.Type+" "+m.ID] = true
+
+		var record interface{}
+		var agentID, humanID, ts string
+		switch m.Type {
+		case SessionMemberIntent:
+			intent := byIntent[m.ID]
+			if intent == nil {
+				fail("%s: intent not supplied", what)
+				continue
+			}
+			record, agentID, humanID, ts = *intent, intent.AgentID, intent.HumanID, intent.Timestamp
+		case SessionMemberAuditEntry:
+			entry := byEntry[m.ID]
+			if entry == nil {
+				fail("%s: audit entry not supplied", what)
+				continue
+			}
+			record, agentID, humanID, ts = *entry, entry.AgentID, entry.HumanID, entry.Timestamp
+			if h, ok := intentHash[entry.IntentID]; !ok {
+				fail("%s: records intent %s, which is not an earlier member", what, entry.IntentID)
+			} else if entry.IntentHash != h {
+				fail("%s: intent_hash does not match intent %s", what, entry.IntentID)
+			}
+		default:
+			fail("%s: unknown member type", what)
+			continue
+		}
+		if h, err := HashObject(record); err != nil || h != m.Hash {
+			fail("%s: hash does not match the record", what)
+		}
+		if agentID != s.AgentID || humanID != s.HumanID {
+			fail("%s: not by %s for %s", what, s.AgentID, s.HumanID)
+		}
+		t, ok := inWindow(what, ts)
+		if m.Type == SessionMemberIntent {
+			intentAt[m.ID], intentHash[m.ID] = t, m.Hash
+		} else if at, known := intentAt[byEntry[m.ID].IntentID]; ok && known && t.Before(at) {
+			fail("%s: is older than intent %s", what, byEntry[m.ID].IntentID)
+		}
+	}
+
+	for _, intent := range intents {
+		if !seen[SessionMemberIntent+" "+intent.IntentID] {
+			fail("intent %s is not a member of the session", intent.IntentID)
+		}
+	}
+	for _, entry := range entries {
+		if !seen[SessionMemberAuditEntry+" "+entry.AuditID] {
+			fail("audit entry %s is not a member of the session", entry.AuditID)
+		}
+	}
+	if len(errs) > 0 {
+		return &VerificationResult{Verified: false, Errors: errs}
+	}
+	return &VerificationResult{Verified: true}
+}
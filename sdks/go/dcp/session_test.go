@@ -0,0 +1,156 @@
+package dcp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// checkoutSession returns a closed session of three intents, browse, fill
+// in a form and submit it, each recorded by one audit entry.
+func checkoutSession(t *testing.T) (*dcp.Session, []dcp.Intent, []dcp.AuditEntry) {
+	t.Helper()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := func() time.Time { now = now.Add(time.Second); return now }
+	records := &dcp.RecordFactory{Clock: clock}
+	agentID, humanID := records.NewAgentID(), records.NewHumanID()
+	s := records.NewSession(agentID, humanID)
+	chain := dcp.NewAuditChain(dcp.AuditChainOptions{Clock: clock})
+
+	var intents []dcp.Intent
+	for _, action := range []string{"browse", "fill_form", "submit_form"} {
+		intent := records.NewIntent(agentID, humanID, action, dcp.IntentTarget{Channel: dcp.ChannelWeb}, []string{"none"}, dcp.ImpactLow)
+		if _, err := s.AddIntent(intent); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := chain.Append(dcp.AuditEntryFields{Intent: intent, PolicyDecision: "approved", Outcome: "ok"}); err != nil {
+			t.Fatal(err)
+		}
+		entries := chain.Entries()
+		if _, err := s.AddAuditEntry(entries[len(entries)-1]); err != nil {
+			t.Fatal(err)
+		}
+		intents = append(intents, intent)
+	}
+	s.Close(clock())
+	return s, intents, chain.Entries()
+}
+
+func TestSession(t *testing.T) {
+	s, intents, entries := checkoutSession(t)
+	if id, err := dcp.ParseID(s.SessionID); err != nil || id.Kind != dcp.IDKindSession {
+		t.Fatalf("session_id %q: %v", s.SessionID, err)
+	}
+	if len(s.Members) != 6 || s.Members[1].Type != dcp.SessionMemberAuditEntry || s.Members[1].ID != entries[0].AuditID {
+		t.Fatalf("members: %+v", s.Members)
+	}
+	if r := dcp.VerifySession(s, intents, entries); !r.Verified {
+		t.Fatal(r.Errors)
+	}
+	if _, err := s.AddIntent(dcp.NewIntent(s.AgentID, s.HumanID, "browse", dcp.IntentTarget{Channel: dcp.ChannelWeb}, []string{"none"}, dcp.ImpactLow)); err == nil {
+		t.Fatal("added an intent to a closed session")
+	}
+
+	agent, _ := dcp.GenerateKeypair()
+	other, _ := dcp.GenerateKeypair()
+	if err := s.Sign(mustSigner(t, agent)); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.VerifySignature(agent.PublicKeyB64); !ok || err != nil {
+		t.Fatalf("signature: %v, %v", ok, err)
+	}
+	if ok, _ := s.VerifySignature(other.PublicKeyB64); ok {
+		t.Fatal("signature verifies under another key")
+	}
+	s.Members = s.Members[:4]
+	if ok, _ := s.VerifySignature(agent.PublicKeyB64); ok {
+		t.Fatal("truncated session verifies")
+	}
+}
+
+func TestSessionAdd(t *testing.T) {
+	records := &dcp.RecordFactory{}
+	agentID, humanID := records.NewAgentID(), records.NewHumanID()
+	s := records.NewSession(agentID, humanID)
+	intent := records.NewIntent(agentID, humanID, "browse", dcp.IntentTarget{Channel: dcp.ChannelWeb}, []string{"none"}, dcp.ImpactLow)
+	stranger := records.NewIntent(records.NewAgentID(), humanID, "browse", dcp.IntentTarget{Channel: dcp.ChannelWeb}, []string{"none"}, dcp.ImpactLow)
+	if _, err := s.AddIntent(stranger); err == nil {
+		t.Fatal("added another agent's intent")
+	}
+	chain := dcp.NewAuditChain(dcp.AuditChainOptions{})
+	chain.Append(dcp.AuditEntryFields{Intent: intent, PolicyDecision: "approved", Outcome: "ok"})
+	entry := chain.Entries()[0]
+	if _, err := s.AddAuditEntry(entry); err == nil {
+		t.Fatal("added an audit entry before its intent")
+	}
+	head, err := s.AddIntent(intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h, _ := s.Head(); h != head {
+		t.Fatal("AddIntent did not return the head")
+	}
+	if _, err := s.AddIntent(intent); err == nil {
+		t.Fatal("added an intent twice")
+	}
+	tampered := entry
+	tampered.IntentHash = strings.Repeat("0", 64)
+	if _, err := s.AddAuditEntry(tampered); err == nil {
+		t.Fatal("added an audit entry for another version of the intent")
+	}
+	if _, err := s.AddAuditEntry(entry); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifySession(t *testing.T) {
+	for name, tc := range map[string]struct {
+		mutate func(s *dcp.Session, intents []dcp.Intent, entries []dcp.AuditEntry) ([]dcp.Intent, []dcp.AuditEntry)
+		want   string
+	}{
+		"reordered members": {func(s *dcp.Session, i []dcp.Intent, e []dcp.AuditEntry) ([]dcp.Intent, []dcp.AuditEntry) {
+			s.Members[2], s.Members[4] = s.Members[4], s.Members[2]
+			return i, e
+		}, "prev_hash"},
+		"moved to another session": {func(s *dcp.Session, i []dcp.Intent, e []dcp.AuditEntry) ([]dcp.Intent, []dcp.AuditEntry) {
+			s.SessionID = dcp.NewSessionID()
+			return i, e
+		}, "member 0 (intent"},
+		"altered intent": {func(s *dcp.Session, i []dcp.Intent, e []dcp.AuditEntry) ([]dcp.Intent, []dcp.AuditEntry) {
+			i[2].EstimatedImpact = dcp.ImpactHigh
+			return i, e
+		}, "hash does not match"},
+		"missing intent": {func(s *dcp.Session, i []dcp.Intent, e []dcp.AuditEntry) ([]dcp.Intent, []dcp.AuditEntry) {
+			return i[1:], e
+		}, "intent not supplied"},
+		"extra entry": {func(s *dcp.Session, i []dcp.Intent, e []dcp.AuditEntry) ([]dcp.Intent, []dcp.AuditEntry) {
+			extra := e[0]
+			extra.AuditID = dcp.NewAuditID()
+			return i, append(e, extra)
+		}, "is not a member of the session"},
+		"intent after close": {func(s *dcp.Session, i []dcp.Intent, e []dcp.AuditEntry) ([]dcp.Intent, []dcp.AuditEntry) {
+			s.ClosedAt = i[1].Timestamp
+			return i, e
+		}, "after the session closed"},
+		"entry for a later intent": {func(s *dcp.Session, i []dcp.Intent, e []dcp.AuditEntry) ([]dcp.Intent, []dcp.AuditEntry) {
+			// Rebuild the chain with the second entry before its intent.
+			members := s.Members
+			s.Members = nil
+			for _, m := range []dcp.SessionMember{members[0], members[1], members[3], members[2], members[4], members[5]} {
+				prev, _ := s.Head()
+				m.PrevHash = prev
+				s.Members = append(s.Members, m)
+			}
+			return i, e
+		}, "not an earlier member"},
+	} {
+		s, intents, entries := checkoutSession(t)
+		intents, entries = tc.mutate(s, intents, entries)
+		r := dcp.VerifySession(s, intents, entries)
+		if r.Verified || !strings.Contains(strings.Join(r.Errors, "\n"), tc.want) {
+			t.Errorf("%s: %v", name, r.Errors)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package sitepolicy
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// ErrRefused reports an intent the site's policy does not admit.
+var ErrRefused = errors.New("refused by site policy")
+
+// levels orders risk tiers and impacts alike.
+var levels = map[string]int{"low": 1, "medium": 2, "high": 3}
+
+// Check reports whether p admits the agent of passport, which may be nil,
+// acting on intent. The rule for the path of the intent's target URL, or
+// for "/" if it has none, must admit the passport's risk tier, the
+// intent's action type and estimated impact and every data class it
+// touches. A passport without a risk tier counts as high risk. The error
+// wraps ErrRefused and lists every reason.
+func (p *Policy) Check(passport *dcp.AgentPassport, intent *dcp.Intent) error {
+	path := "/"
+	if intent.Target.URL != nil {
+		if u, err := url.Parse(*intent.Target.URL); err == nil && u.EscapedPath() != "" {
+			path = u.EscapedPath()
+		}
+	}
+	r := p.RuleFor(path)
+	if r == nil {
+		return nil
+	}
+
+	var reasons []string
+	switch {
+	case passport == nil:
+		if r.RequirePassport {
+			reasons = append(reasons, "a passport is required")
+		}
+	case r.MaxRiskTier != "":
+		tier := passport.RiskTier
+		if tier == "" {
+			tier = dcp.RiskTierHigh
+		}
+		if levels[string(tier)] == 0 || levels[string(tier)] > levels[string(r.MaxRiskTier)] {
+			reasons = append(reasons, fmt.Sprintf("risk tier %s exceeds %s", tier, r.MaxRiskTier))
+		}
+	}
+	if r.MaxImpact != "" && (levels[string(intent.EstimatedImpact)] == 0 || levels[string(intent.EstimatedImpact)] > levels[string(r.MaxImpact)]) {
+		reasons = append(reasons, fmt.Sprintf("impact %s exceeds %s", intent.EstimatedImpact, r.MaxImpact))
+	}
+	if !capabilityAdmitted(r, intent.ActionType) {
+		reasons = append(reasons, fmt.Sprintf("action %s is not admitted", intent.ActionType))
+	}
+	for _, c := range intent.DataClasses {
+		if c == "none" {
+			continue
+		}
+		if (len(r.AllowDataClasses) > 0 && !listed(r.AllowDataClasses, c)) || listed(r.DisallowDataClasses, c) {
+			reasons = append(reasons, fmt.Sprintf("data class %s is not admitted", c))
+		}
+	}
+	if len(reasons) > 0 {
+		return fmt.Errorf("%w under %s: %s", ErrRefused, r.Path, strings.Join(reasons, "; "))
+	}
+	return nil
+}
+
+// capabilityAdmitted reports whether r admits the capability of action.
+// The rule's capabilities were checked by Validate.
+func capabilityAdmitted(r *Rule, action string) bool {
+	want, err := dcp.ParseCapability(action)
+	if err != nil {
+		return false
+	}
+	if denied, _ := dcp.ParseCapabilitySet(r.DisallowCapabilities); denied.Covers(want) {
+		return false
+	}
+	if len(r.AllowCapabilities) == 0 {
+		return true
+	}
+	allowed, _ := dcp.ParseCapabilitySet(r.AllowCapabilities)
+	return allowed.Covers(want)
+}
+
+// listed reports whether list names class, or is "*".
+func listed(list []string, class string) bool {
+	for _, c := range list {
+		if c == "*" || c == class {
+			return true
+		}
+	}
+	return false
+}
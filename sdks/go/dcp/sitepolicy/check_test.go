@@ -0,0 +1,64 @@
+package sitepolicy_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/sitepolicy"
+)
+
+func passport(tier dcp.RiskTier) *dcp.AgentPassport {
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), "", []string{"browse"}, tier)
+	return &p
+}
+
+func intent(action, url string, impact dcp.EstimatedImpact, classes ...string) *dcp.Intent {
+	if len(classes) == 0 {
+		classes = []string{"none"}
+	}
+	i := dcp.NewIntent("dcp:agent:a", "dcp:human:h", action, dcp.IntentTarget{Channel: dcp.ChannelWeb, URL: &url}, classes, impact)
+	return &i
+}
+
+func TestCheck(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		passport *dcp.AgentPassport
+		intent   *dcp.Intent
+		ok       bool
+	}{
+		{"browse", passport(dcp.RiskTierMedium), intent("browse", "https://shop.example.com/products", dcp.ImpactLow), true},
+		{"anonymous browse", nil, intent("browse", "https://shop.example.com/", dcp.ImpactLow), true},
+		{"high risk", passport(dcp.RiskTierHigh), intent("browse", "https://shop.example.com/", dcp.ImpactLow), false},
+		{"no tier", passport(""), intent("browse", "https://shop.example.com/", dcp.ImpactLow), false},
+		{"unlisted action", passport(dcp.RiskTierLow), intent("send_email", "https://shop.example.com/", dcp.ImpactLow), false},
+		{"disallowed data", passport(dcp.RiskTierLow), intent("api_call", "https://shop.example.com/api", dcp.ImpactLow, "contact_info", "credentials"), false},
+		{"allowed data", passport(dcp.RiskTierLow), intent("api_call", "https://shop.example.com/api", dcp.ImpactLow, "contact_info"), true},
+		{"payment", passport(dcp.RiskTierLow), intent("initiate_payment", "https://shop.example.com/checkout/pay", dcp.ImpactMedium), true},
+		{"payment, medium risk", passport(dcp.RiskTierMedium), intent("initiate_payment", "https://shop.example.com/checkout/pay", dcp.ImpactMedium), false},
+		{"payment, high impact", passport(dcp.RiskTierLow), intent("initiate_payment", "https://shop.example.com/checkout", dcp.ImpactHigh), false},
+		{"payment, anonymous", nil, intent("initiate_payment", "https://shop.example.com/checkout", dcp.ImpactLow), false},
+		{"payment outside checkout", passport(dcp.RiskTierLow), intent("initiate_payment", "https://shop.example.com/cart", dcp.ImpactLow), false},
+	} {
+		err := shop.Check(c.passport, c.intent)
+		if c.ok && err != nil {
+			t.Errorf("%s: %v", c.name, err)
+		}
+		if !c.ok && !errors.Is(err, sitepolicy.ErrRefused) {
+			t.Errorf("%s: %v, want a refusal", c.name, err)
+		}
+	}
+
+	p := &sitepolicy.Policy{Rules: []sitepolicy.Rule{{Path: "/", DisallowCapabilities: []string{"execute_code"}, AllowDataClasses: []string{"contact_info"}}}}
+	if err := p.Check(nil, intent("browse", "https://example.com/", dcp.ImpactHigh, "contact_info")); err != nil {
+		t.Fatal(err)
+	}
+	err := p.Check(nil, intent("execute_code", "https://example.com/", dcp.ImpactLow, "pii"))
+	if !errors.Is(err, sitepolicy.ErrRefused) || err.Error() != "refused by site policy under /: action execute_code is not admitted; data class pii is not admitted" {
+		t.Fatalf("%v", err)
+	}
+	if err := (&sitepolicy.Policy{}).Check(nil, intent("execute_code", "https://example.com/", dcp.ImpactHigh)); err != nil {
+		t.Fatalf("empty policy: %v", err)
+	}
+}
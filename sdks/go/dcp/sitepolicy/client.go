@@ -0,0 +1,125 @@
+package sitepolicy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Client fetches sites' policies, caches them, and checks intents against
+// them before the agent acts. A Client is safe for concurrent use.
+type Client struct {
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// MaxAge is how long a policy is cached; zero means one hour.
+	MaxAge time.Duration
+	// Now is the cache's clock; nil means time.Now.
+	Now func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	policy  *Policy
+	expires time.Time
+}
+
+// Fetch returns the policy of site, a domain or a base URL such as
+// "http://localhost:8080", fetching it unless a fresh copy is cached. A
+// site that serves no policy gets an empty one, which admits every agent.
+func (c *Client) Fetch(ctx context.Context, site string) (*Policy, error) {
+	u := PolicyURL(site)
+	now := time.Now()
+	if c.Now != nil {
+		now = c.Now()
+	}
+	c.mu.Lock()
+	cached := c.cache[u]
+	c.mu.Unlock()
+	if cached != nil && now.Before(cached.expires) {
+		return cached.policy, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain")
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPolicyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", u, err)
+	}
+
+	var p *Policy
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		p = &Policy{}
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s: %s", u, resp.Status)
+	default:
+		if p, err = Parse(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", u, err)
+		}
+	}
+	maxAge := c.MaxAge
+	if maxAge <= 0 {
+		maxAge = time.Hour
+	}
+	c.mu.Lock()
+	if c.cache == nil {
+		c.cache = map[string]*cacheEntry{}
+	}
+	c.cache[u] = &cacheEntry{policy: p, expires: now.Add(maxAge)}
+	c.mu.Unlock()
+	return p, nil
+}
+
+// Preflight checks intent against the policy of the site it targets: the
+// site of its target URL, or of its target domain on the web and api
+// channels. Intents on other channels target no site and pass.
+func (c *Client) Preflight(ctx context.Context, passport *dcp.AgentPassport, intent *dcp.Intent) error {
+	var site string
+	switch {
+	case intent.Target.URL != nil:
+		u, err := url.Parse(*intent.Target.URL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("sitepolicy: target url %q is not absolute", *intent.Target.URL)
+		}
+		site = u.Scheme + "://" + u.Host
+	case intent.Target.Domain != nil && (intent.Target.Channel == dcp.ChannelWeb || intent.Target.Channel == dcp.ChannelAPI):
+		site = *intent.Target.Domain
+	default:
+		return nil
+	}
+	p, err := c.Fetch(ctx, site)
+	if err != nil {
+		return fmt.Errorf("sitepolicy: %w", err)
+	}
+	return p.Check(passport, intent)
+}
+
+// PolicyURL returns the URL of site's policy.
+func PolicyURL(site string) string {
+	base := strings.TrimRight(site, "/")
+	if !strings.HasPrefix(base, "https://") && !strings.HasPrefix(base, "http://") {
+		base = "https://" + base
+	}
+	return base + WellKnownPath
+}
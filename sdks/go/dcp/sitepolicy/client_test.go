@@ -0,0 +1,82 @@
+package sitepolicy_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/sitepolicy"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	var fetches int32
+	mux := http.NewServeMux()
+	h, _ := sitepolicy.NewHandler(shop)
+	mux.Handle(sitepolicy.WellKnownPath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		h.ServeHTTP(w, r)
+	}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	bare := httptest.NewServer(http.NotFoundHandler())
+	defer bare.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Path /"))
+	}))
+	defer broken.Close()
+
+	now := time.Now()
+	c := &sitepolicy.Client{HTTPClient: srv.Client(), Now: func() time.Time { return now }}
+	if err := c.Preflight(ctx, passport(dcp.RiskTierLow), intent("browse", srv.URL+"/products", dcp.ImpactLow)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Preflight(ctx, passport(dcp.RiskTierLow), intent("initiate_payment", srv.URL+"/cart", dcp.ImpactLow)); !errors.Is(err, sitepolicy.ErrRefused) {
+		t.Fatalf("payment outside checkout: %v", err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("fetched %d times", n)
+	}
+	now = now.Add(2 * time.Hour)
+	if _, err := c.Fetch(ctx, srv.URL); err != nil || atomic.LoadInt32(&fetches) != 2 {
+		t.Fatalf("expired policy not refetched: %v", err)
+	}
+
+	// A site without dcp.txt admits everyone; one with a broken file fails
+	// the pre-flight.
+	if err := c.Preflight(ctx, nil, intent("execute_code", bare.URL+"/", dcp.ImpactHigh)); err != nil {
+		t.Fatalf("site without a policy: %v", err)
+	}
+	err := c.Preflight(ctx, nil, intent("browse", broken.URL+"/", dcp.ImpactLow))
+	if err == nil || errors.Is(err, sitepolicy.ErrRefused) {
+		t.Fatalf("broken policy: %v", err)
+	}
+
+	// Only web and API intents target a site.
+	email := dcp.NewIntent("dcp:agent:a", "dcp:human:h", "send_email", dcp.IntentTarget{Channel: dcp.ChannelEmail, Domain: ptr("example.invalid")}, []string{"contact_info"}, dcp.ImpactLow)
+	if err := c.Preflight(ctx, nil, &email); err != nil {
+		t.Fatalf("email intent: %v", err)
+	}
+	relative := intent("browse", "/products", dcp.ImpactLow)
+	if err := c.Preflight(ctx, nil, relative); err == nil {
+		t.Fatal("pre-flight of a relative URL")
+	}
+}
+
+func TestPolicyURL(t *testing.T) {
+	for site, want := range map[string]string{
+		"shop.example.com":       "https://shop.example.com/.well-known/dcp.txt",
+		"http://localhost:8080/": "http://localhost:8080/.well-known/dcp.txt",
+	} {
+		if got := sitepolicy.PolicyURL(site); got != want {
+			t.Errorf("%s: %s", site, got)
+		}
+	}
+}
+
+func ptr(s string) *string { return &s }
@@ -0,0 +1,305 @@
+// Package sitepolicy reads and writes dcp.txt, the file in which a website
+// declares, at https://<domain>/.well-known/dcp.txt, which agents it
+// admits: their risk tier, the actions they may take, the impact of those
+// actions and the classes of data they may touch. Like robots.txt it is
+// plain text, grouped by path:
+//
+//	# dcp.txt for shop.example.com
+//	DCP-Version: 1.0
+//	Contact: mailto:agents@shop.example.com
+//
+//	Path: /
+//	Max-Risk-Tier: medium
+//	Allow-Capability: browse, api_call
+//	Disallow-Data-Class: credentials, health_data
+//
+//	Path: /checkout
+//	Max-Risk-Tier: low
+//	Require-Passport: yes
+//	Allow-Capability: browse, initiate_payment
+//	Max-Impact: medium
+//
+// The group whose path is the longest prefix of a request's path applies;
+// a path no group matches, or a site without the file, admits every agent.
+// Capabilities are in the grammar of package dcp and name intent action
+// types, so "browse" admits intents whose action_type is browse. The same
+// policy may be served as JSON instead, the form Policy marshals to.
+//
+// Sites generate the file with Policy.Text and serve it with
+// NewHandler; agents check their intents against it before acting with
+// Policy.Check, or with a Client that fetches and caches the files of the
+// sites they visit.
+package sitepolicy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// WellKnownPath is where a site serves its policy.
+const WellKnownPath = "/.well-known/dcp.txt"
+
+// Policy is a site's dcp.txt.
+type Policy struct {
+	// Version is the DCP version the policy is written for.
+	Version string `json:"dcp_version,omitempty"`
+	// Contact is how agent operators reach the site, e.g. a mailto: URL.
+	Contact string `json:"contact,omitempty"`
+	// Terms is the URL of the site's terms for agents.
+	Terms string `json:"terms,omitempty"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is what a site admits under a path. Empty fields admit anything.
+type Rule struct {
+	// Path is the prefix of the request paths the rule applies to.
+	Path string `json:"path"`
+	// MaxRiskTier is the riskiest passport tier admitted.
+	MaxRiskTier dcp.RiskTier `json:"max_risk_tier,omitempty"`
+	// MaxImpact is the highest estimated impact of intents admitted.
+	MaxImpact dcp.EstimatedImpact `json:"max_impact,omitempty"`
+	// RequirePassport refuses agents that present no passport.
+	RequirePassport bool `json:"require_passport,omitempty"`
+	// AllowCapabilities, if any, are the capabilities admitted;
+	// DisallowCapabilities are refused even if allowed.
+	AllowCapabilities    []string `json:"allow_capabilities,omitempty"`
+	DisallowCapabilities []string `json:"disallow_capabilities,omitempty"`
+	// AllowDataClasses, if any, are the data classes intents may touch;
+	// DisallowDataClasses they may not.
+	AllowDataClasses    []string `json:"allow_data_classes,omitempty"`
+	DisallowDataClasses []string `json:"disallow_data_classes,omitempty"`
+}
+
+// The fields of the text form, in the order Text writes them.
+const (
+	fieldVersion            = "DCP-Version"
+	fieldContact            = "Contact"
+	fieldTerms              = "Terms"
+	fieldPath               = "Path"
+	fieldMaxRiskTier        = "Max-Risk-Tier"
+	fieldMaxImpact          = "Max-Impact"
+	fieldRequirePassport    = "Require-Passport"
+	fieldAllowCapability    = "Allow-Capability"
+	fieldDisallowCapability = "Disallow-Capability"
+	fieldAllowDataClass     = "Allow-Data-Class"
+	fieldDisallowDataClass  = "Disallow-Data-Class"
+)
+
+// maxPolicyBytes caps the size of a policy.
+const maxPolicyBytes = 512 << 10
+
+// Parse parses and validates a policy in the text form, or in the JSON
+// form if data is a JSON object. Fields it does not know are ignored, so
+// newer files still parse.
+func Parse(data []byte) (*Policy, error) {
+	if len(data) > maxPolicyBytes {
+		return nil, fmt.Errorf("dcp.txt exceeds %d bytes", maxPolicyBytes)
+	}
+	var p Policy
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &p); err != nil {
+			return nil, fmt.Errorf("dcp.txt: %w", err)
+		}
+	} else if err := p.parseText(data); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("dcp.txt: %w", err)
+	}
+	return &p, nil
+}
+
+func (p *Policy) parseText(data []byte) error {
+	rule := -1
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("dcp.txt line %d: not a \"Field: value\" line", n)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch {
+		case strings.EqualFold(key, fieldVersion):
+			p.Version = value
+		case strings.EqualFold(key, fieldContact):
+			p.Contact = value
+		case strings.EqualFold(key, fieldTerms):
+			p.Terms = value
+		case strings.EqualFold(key, fieldPath):
+			p.Rules = append(p.Rules, Rule{Path: value})
+			rule = len(p.Rules) - 1
+		default:
+			if !isRuleField(key) {
+				continue
+			}
+			if rule < 0 {
+				return fmt.Errorf("dcp.txt line %d: %s before the first %s", n, key, fieldPath)
+			}
+			if err := p.Rules[rule].set(key, value); err != nil {
+				return fmt.Errorf("dcp.txt line %d: %v", n, err)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+func isRuleField(key string) bool {
+	for _, f := range []string{fieldMaxRiskTier, fieldMaxImpact, fieldRequirePassport,
+		fieldAllowCapability, fieldDisallowCapability, fieldAllowDataClass, fieldDisallowDataClass} {
+		if strings.EqualFold(key, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// set sets the rule field key from a text value; list fields accumulate
+// comma-separated values across lines.
+func (r *Rule) set(key, value string) error {
+	list := func() []string {
+		var out []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				out = append(out, v)
+			}
+		}
+		return out
+	}
+	switch {
+	case strings.EqualFold(key, fieldMaxRiskTier):
+		r.MaxRiskTier = dcp.RiskTier(strings.ToLower(value))
+	case strings.EqualFold(key, fieldMaxImpact):
+		r.MaxImpact = dcp.EstimatedImpact(strings.ToLower(value))
+	case strings.EqualFold(key, fieldRequirePassport):
+		switch strings.ToLower(value) {
+		case "yes", "true":
+			r.RequirePassport = true
+		case "no", "false":
+			r.RequirePassport = false
+		default:
+			return fmt.Errorf("%s is %q, not yes or no", fieldRequirePassport, value)
+		}
+	case strings.EqualFold(key, fieldAllowCapability):
+		r.AllowCapabilities = append(r.AllowCapabilities, list()...)
+	case strings.EqualFold(key, fieldDisallowCapability):
+		r.DisallowCapabilities = append(r.DisallowCapabilities, list()...)
+	case strings.EqualFold(key, fieldAllowDataClass):
+		r.AllowDataClasses = append(r.AllowDataClasses, list()...)
+	case strings.EqualFold(key, fieldDisallowDataClass):
+		r.DisallowDataClasses = append(r.DisallowDataClasses, list()...)
+	}
+	return nil
+}
+
+// Validate checks that every rule has a distinct absolute path, valid tiers
+// and impacts, and capabilities in the grammar of package dcp.
+func (p *Policy) Validate() error {
+	paths := map[string]bool{}
+	for i, r := range p.Rules {
+		if !strings.HasPrefix(r.Path, "/") {
+			return fmt.Errorf("rules[%d]: path %q does not start with /", i, r.Path)
+		}
+		if paths[r.Path] {
+			return fmt.Errorf("rules[%d]: path %s repeats", i, r.Path)
+		}
+		paths[r.Path] = true
+		if r.MaxRiskTier != "" && !r.MaxRiskTier.IsValid() {
+			return fmt.Errorf("rules[%d] %s: unknown risk tier %q", i, r.Path, r.MaxRiskTier)
+		}
+		if r.MaxImpact != "" && !r.MaxImpact.IsValid() {
+			return fmt.Errorf("rules[%d] %s: unknown impact %q", i, r.Path, r.MaxImpact)
+		}
+		for _, caps := range [][]string{r.AllowCapabilities, r.DisallowCapabilities} {
+			if _, err := dcp.ParseCapabilitySet(caps); err != nil {
+				return fmt.Errorf("rules[%d] %s: %w", i, r.Path, err)
+			}
+		}
+		for _, classes := range [][]string{r.AllowDataClasses, r.DisallowDataClasses} {
+			for _, c := range classes {
+				if c == "" || strings.ContainsAny(c, ", \t") {
+					return fmt.Errorf("rules[%d] %s: invalid data class %q", i, r.Path, c)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// RuleFor returns the rule whose path is the longest prefix of path, or
+// nil if none is.
+func (p *Policy) RuleFor(path string) *Rule {
+	var best *Rule
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if strings.HasPrefix(path, r.Path) && (best == nil || len(r.Path) > len(best.Path)) {
+			best = r
+		}
+	}
+	return best
+}
+
+// Text returns the text form of p, which Parse reads back.
+func (p *Policy) Text() ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	field := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", key, value)
+		}
+	}
+	field(fieldVersion, p.Version)
+	field(fieldContact, p.Contact)
+	field(fieldTerms, p.Terms)
+	for _, r := range p.Rules {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		field(fieldPath, r.Path)
+		field(fieldMaxRiskTier, string(r.MaxRiskTier))
+		field(fieldMaxImpact, string(r.MaxImpact))
+		if r.RequirePassport {
+			field(fieldRequirePassport, "yes")
+		}
+		field(fieldAllowCapability, strings.Join(r.AllowCapabilities, ", "))
+		field(fieldDisallowCapability, strings.Join(r.DisallowCapabilities, ", "))
+		field(fieldAllowDataClass, strings.Join(r.AllowDataClasses, ", "))
+		field(fieldDisallowDataClass, strings.Join(r.DisallowDataClasses, ", "))
+	}
+	return b.Bytes(), nil
+}
+
+// NewHandler returns the handler serving p at WellKnownPath: as text, or
+// as JSON to requests that accept application/json.
+func NewHandler(p *Policy) (http.Handler, error) {
+	text, err := p.Text()
+	if err != nil {
+		return nil, fmt.Errorf("sitepolicy: %v", err)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("sitepolicy: %v", err)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Vary", "Accept")
+		if strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(data)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write(text)
+	}), nil
+}
@@ -0,0 +1,132 @@
+package sitepolicy_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/sitepolicy"
+)
+
+const shopTxt = `# dcp.txt for shop.example.com
+DCP-Version: 2.0
+Contact: mailto:agents@shop.example.com
+Crawl-Delay: 10   # not a DCP field: ignored
+
+Path: /
+Max-Risk-Tier: Medium
+Allow-Capability: browse, api_call
+Disallow-Data-Class: credentials
+disallow-data-class: health_data
+
+Path: /checkout
+Max-Risk-Tier: low
+Require-Passport: yes
+Allow-Capability: browse, initiate_payment
+Max-Impact: medium
+`
+
+var shop = &sitepolicy.Policy{
+	Version: "2.0",
+	Contact: "mailto:agents@shop.example.com",
+	Rules: []sitepolicy.Rule{
+		{Path: "/", MaxRiskTier: dcp.RiskTierMedium, AllowCapabilities: []string{"browse", "api_call"},
+			DisallowDataClasses: []string{"credentials", "health_data"}},
+		{Path: "/checkout", MaxRiskTier: dcp.RiskTierLow, RequirePassport: true,
+			AllowCapabilities: []string{"browse", "initiate_payment"}, MaxImpact: dcp.ImpactMedium},
+	},
+}
+
+func TestParse(t *testing.T) {
+	p, err := sitepolicy.Parse([]byte(shopTxt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p, shop) {
+		t.Fatalf("parsed %+v", p)
+	}
+
+	data, _ := json.Marshal(shop)
+	if p, err := sitepolicy.Parse(data); err != nil || !reflect.DeepEqual(p, shop) {
+		t.Fatalf("JSON form: %+v, %v", p, err)
+	}
+
+	for name, txt := range map[string]string{
+		"no colon":         "Path /",
+		"rule before path": "Max-Risk-Tier: low\nPath: /",
+		"relative path":    "Path: checkout",
+		"repeated path":    "Path: /\nPath: /",
+		"unknown tier":     "Path: /\nMax-Risk-Tier: extreme",
+		"unknown impact":   "Path: /\nMax-Impact: catastrophic",
+		"bad capability":   "Path: /\nAllow-Capability: payments:initiate:<=lots",
+		"bad yes or no":    "Path: /\nRequire-Passport: maybe",
+		"bad JSON":         `{"rules": [`,
+	} {
+		if _, err := sitepolicy.Parse([]byte(txt)); err == nil {
+			t.Errorf("%s: parsed", name)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	text, err := shop.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := sitepolicy.Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p, shop) {
+		t.Fatalf("round trip:\n%s", text)
+	}
+	if !strings.Contains(string(text), "Require-Passport: yes\n") {
+		t.Fatalf("generated:\n%s", text)
+	}
+	bad := &sitepolicy.Policy{Rules: []sitepolicy.Rule{{Path: "relative"}}}
+	if _, err := bad.Text(); err == nil {
+		t.Fatal("generated an invalid policy")
+	}
+}
+
+func TestRuleFor(t *testing.T) {
+	for path, want := range map[string]string{"/": "/", "/products/1": "/", "/checkout": "/checkout", "/checkout/pay": "/checkout"} {
+		if r := shop.RuleFor(path); r == nil || r.Path != want {
+			t.Errorf("%s: %+v, want %s", path, r, want)
+		}
+	}
+	p := &sitepolicy.Policy{Rules: []sitepolicy.Rule{{Path: "/api"}}}
+	if r := p.RuleFor("/"); r != nil {
+		t.Fatalf("/ matched %+v", r)
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	h, err := sitepolicy.NewHandler(shop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	for _, accept := range []string{"", "application/json"} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+sitepolicy.WellKnownPath, nil)
+		req.Header.Set("Accept", accept)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if p, err := sitepolicy.Parse(data); err != nil || !reflect.DeepEqual(p, shop) {
+			t.Fatalf("Accept %q (%s): %v", accept, resp.Header.Get("Content-Type"), err)
+		}
+	}
+	if _, err := sitepolicy.NewHandler(&sitepolicy.Policy{Rules: []sitepolicy.Rule{{}}}); err == nil {
+		t.Fatal("served an invalid policy")
+	}
+}
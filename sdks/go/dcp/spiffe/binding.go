@@ -0,0 +1,197 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+)
+
+// ErrNotBound reports a workload that is not the one its passport is bound
+// to, or a passport bound to none.
+var ErrNotBound = errors.New("workload is not bound to the agent passport")
+
+// Binding is the statement, by the passport key, that an agent runs as the
+// workload of a SPIFFE ID.
+type Binding struct {
+	AgentID  string `json:"agent_id"`
+	SPIFFEID string `json:"spiffe_id"`
+	BoundAt  string `json:"bound_at"`
+	// Signature is by the passport key over the canonical binding with an
+	// empty signature.
+	Signature string `json:"signature"`
+}
+
+// Bind returns the binding of the agent of passport p to spiffeID at now,
+// signed with the passport key s.
+func Bind(p *dcp.AgentPassport, spiffeID string, now time.Time, s dcp.BundleSigner) (*Binding, error) {
+	if _, err := TrustDomain(spiffeID); err != nil {
+		return nil, fmt.Errorf("spiffe: %v", err)
+	}
+	if s.PublicKeyB64() != p.PublicKey {
+		return nil, fmt.Errorf("spiffe: signer does not hold the key of agent %s", p.AgentID)
+	}
+	b := &Binding{AgentID: p.AgentID, SPIFFEID: spiffeID, BoundAt: dcp.FormatTime(now)}
+	canon, err := dcp.Canonicalize(b)
+	if err != nil {
+		return nil, err
+	}
+	if b.Signature, err = s.SignCanonical(canon); err != nil {
+		return nil, fmt.Errorf("spiffe: sign binding of %s: %v", p.AgentID, err)
+	}
+	return b, nil
+}
+
+// Verify checks that b binds the agent of passport p and is signed by its
+// key.
+func (b *Binding) Verify(p *dcp.AgentPassport) error {
+	if b.AgentID != p.AgentID {
+		return fmt.Errorf("binding is for %s, not %s", b.AgentID, p.AgentID)
+	}
+	unsigned := *b
+	unsigned.Signature = ""
+	if ok, err := dcp.VerifyObject(unsigned, b.Signature, p.PublicKey); err != nil || !ok {
+		return errors.New("binding signature does not verify under the passport key")
+	}
+	return nil
+}
+
+// BindingSource returns the binding of an agent, or nil if it has none.
+type BindingSource interface {
+	Binding(ctx context.Context, agentID string) (*Binding, error)
+}
+
+// Store is an in-memory BindingSource the issuer publishes bindings to. It
+// is safe for concurrent use.
+type Store struct {
+	mu       sync.RWMutex
+	bindings map[string]Binding
+}
+
+// SubmitBinding stores b, replacing the agent's previous binding.
+func (s *Store) SubmitBinding(ctx context.Context, b Binding) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.bindings == nil {
+		s.bindings = map[string]Binding{}
+	}
+	s.bindings[b.AgentID] = b
+	return nil
+}
+
+// Binding implements BindingSource.
+func (s *Store) Binding(ctx context.Context, agentID string) (*Binding, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.bindings[agentID]
+	if !ok {
+		return nil, nil
+	}
+	return &b, nil
+}
+
+// Verifier checks that passports are presented by the workloads they are
+// bound to.
+type Verifier struct {
+	// Bundles are the trusted trust domains. Required.
+	Bundles Bundles
+	// Bindings holds the published bindings. Required.
+	Bindings BindingSource
+	// Now is the clock SVIDs are checked against; nil means time.Now.
+	Now func() time.Time
+}
+
+// Check checks that the client certificate chain of conn is an SVID of the
+// workload passport p is bound to, and returns its SPIFFE ID. The caller
+// checks the passport itself. Errors wrap ErrInvalidSVID, ErrNotBound or,
+// when the binding could not be looked up, agentauth.ErrLookup.
+func (v *Verifier) Check(ctx context.Context, p *dcp.AgentPassport, conn *tls.ConnectionState) (string, error) {
+	if v.Bundles == nil || v.Bindings == nil {
+		return "", errors.New("spiffe: trust bundles and a binding source are required")
+	}
+	if conn == nil || len(conn.PeerCertificates) == 0 {
+		return "", fmt.Errorf("%w: no client certificate", ErrInvalidSVID)
+	}
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	id, err := v.Bundles.VerifySVID(conn.PeerCertificates, now)
+	if err != nil {
+		return "", err
+	}
+	b, err := v.Bindings.Binding(ctx, p.AgentID)
+	if err != nil {
+		return "", fmt.Errorf("%w: binding source: %v", agentauth.ErrLookup, err)
+	}
+	if b == nil {
+		return "", fmt.Errorf("%w: no workload is bound to %s", ErrNotBound, p.AgentID)
+	}
+	if err := b.Verify(p); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNotBound, err)
+	}
+	if b.SPIFFEID != id {
+		return "", fmt.Errorf("%w: %s is bound to %s, not %s", ErrNotBound, p.AgentID, b.SPIFFEID, id)
+	}
+	return id, nil
+}
+
+// Middleware admits requests whose DCP-Agent-Passport header a admits and
+// whose client SVID is that of the workload the passport is bound to, with
+// the agent's agentauth.Identity in their context. It answers the others
+// with 401 (no or invalid passport or SVID), 403 (inactive passport or
+// another workload) or 503 (lookup failure). The SVID takes the place of
+// an agent certificate: a must not require one.
+func (v *Verifier) Middleware(a *agentauth.Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := v.authenticate(a, r)
+		if err != nil {
+			switch {
+			case errors.Is(err, agentauth.ErrInactive), errors.Is(err, ErrNotBound):
+				writeError(w, http.StatusForbidden, err.Error())
+			case errors.Is(err, agentauth.ErrLookup):
+				writeError(w, http.StatusServiceUnavailable, err.Error())
+			default:
+				w.Header().Set("WWW-Authenticate", `DCP header="`+agentauth.PassportHeader+`"`)
+				writeError(w, http.StatusUnauthorized, err.Error())
+			}
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(agentauth.NewContext(r.Context(), id)))
+	})
+}
+
+func (v *Verifier) authenticate(a *agentauth.Authenticator, r *http.Request) (*agentauth.Identity, error) {
+	header := strings.TrimSpace(r.Header.Get(agentauth.PassportHeader))
+	if header == "" {
+		return nil, agentauth.ErrNoPassport
+	}
+	p, err := agentauth.DecodePassport(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", agentauth.ErrInvalidPassport, err)
+	}
+	// The client certificate is the SVID, not one carrying the passport key.
+	id, err := a.Check(r.Context(), p, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := v.Check(r.Context(), p, r.TLS); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,144 @@
+package spiffe_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/spiffe"
+)
+
+func newAgent(t *testing.T) (*dcp.AgentPassport, *dcp.KeySigner) {
+	t.Helper()
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), kp.PublicKeyB64, []string{"api_call"}, dcp.RiskTierLow)
+	if err := p.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	return &p, signer
+}
+
+func TestBind(t *testing.T) {
+	p, signer := newAgent(t)
+	b, err := spiffe.Bind(p, "spiffe://example.org/agent", time.Now(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Verify(p); err != nil {
+		t.Fatal(err)
+	}
+	tampered := *b
+	tampered.SPIFFEID = "spiffe://example.org/other"
+	if err := tampered.Verify(p); err == nil {
+		t.Fatal("tampered binding verifies")
+	}
+	other, otherSigner := newAgent(t)
+	if err := b.Verify(other); err == nil {
+		t.Fatal("binding verifies for another agent")
+	}
+	if _, err := spiffe.Bind(p, "spiffe://example.org/agent", time.Now(), otherSigner); err == nil {
+		t.Fatal("bound with another agent's key")
+	}
+	if _, err := spiffe.Bind(p, "https://example.org/agent", time.Now(), signer); err == nil {
+		t.Fatal("bound to a URI that is not a SPIFFE ID")
+	}
+}
+
+// client returns an HTTP client presenting cert, with key, to srv.
+func client(srv *httptest.Server, cert *x509.Certificate, key *ecdsa.PrivateKey) *http.Client {
+	transport := srv.Client().Transport.(*http.Transport).Clone()
+	if cert != nil {
+		transport.TLSClientConfig.Certificates = []tls.Certificate{{Certificate: [][]byte{cert.Raw}, PrivateKey: key, Leaf: cert}}
+	}
+	return &http.Client{Transport: transport}
+}
+
+func TestMiddleware(t *testing.T) {
+	authority := newCA(t, "example.org")
+	p, signer := newAgent(t)
+	b, err := spiffe.Bind(p, "spiffe://example.org/agent/billing", time.Now(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &spiffe.Store{}
+	store.SubmitBinding(context.Background(), *b)
+	unbound, _ := newAgent(t)
+	revocations := &dcp.RevocationList{}
+	revoked, revokedSigner := newAgent(t)
+	rb, _ := spiffe.Bind(revoked, "spiffe://example.org/agent/billing", time.Now(), revokedSigner)
+	store.SubmitBinding(context.Background(), *rb)
+	revocations.Add(dcp.NewRevocationRecord(revoked.AgentID, revoked.PrincipalBindingReference, "retired"))
+
+	auth := dcptest.Authenticator(t, agentauth.Config{Revocations: []dcp.RevocationChecker{revocations}})
+	v := &spiffe.Verifier{Bundles: authority.bundles(), Bindings: store}
+	srv := httptest.NewUnstartedServer(v.Middleware(auth, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := agentauth.FromContext(r.Context())
+		w.Write([]byte(id.AgentID()))
+	})))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	billing, billingKey := authority.svid(t, nil, "spiffe://example.org/agent/billing")
+	support, supportKey := authority.svid(t, nil, "spiffe://example.org/agent/support")
+	for _, c := range []struct {
+		name     string
+		passport *dcp.AgentPassport
+		cert     *x509.Certificate
+		key      *ecdsa.PrivateKey
+		want     int
+	}{
+		{"bound workload", p, billing, billingKey, http.StatusOK},
+		{"other workload", p, support, supportKey, http.StatusForbidden},
+		{"unbound passport", unbound, billing, billingKey, http.StatusForbidden},
+		{"revoked passport", revoked, billing, billingKey, http.StatusForbidden},
+		{"no SVID", p, nil, nil, http.StatusUnauthorized},
+		{"no passport", nil, billing, billingKey, http.StatusUnauthorized},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if c.passport != nil {
+			h, _ := agentauth.EncodePassport(c.passport)
+			req.Header.Set(agentauth.PassportHeader, h)
+		}
+		resp, err := client(srv, c.cert, c.key).Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.want {
+			t.Errorf("%s: %d, want %d", c.name, resp.StatusCode, c.want)
+		}
+	}
+}
+
+func TestCheckLookup(t *testing.T) {
+	authority := newCA(t, "example.org")
+	p, _ := newAgent(t)
+	leaf, _ := authority.svid(t, nil, "spiffe://example.org/agent")
+	v := &spiffe.Verifier{Bundles: authority.bundles(), Bindings: failingSource{}}
+	if _, err := v.Check(context.Background(), p, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}}); !errors.Is(err, agentauth.ErrLookup) {
+		t.Fatalf("%v", err)
+	}
+}
+
+type failingSource struct{}
+
+func (failingSource) Binding(ctx context.Context, agentID string) (*spiffe.Binding, error) {
+	return nil, errors.New("binding store unreachable")
+}
@@ -0,0 +1,121 @@
+// Package spiffe binds agent passports to SPIFFE workload identities, so
+// a passport is only honoured when presented by the workload it was issued
+// to, as SPIRE attests it.
+//
+// At issuance the workload proves its identity with its X.509-SVID, which
+// is verified against the trust bundle of its trust domain; the issuer then
+// records a Binding of the agent to the SVID's SPIFFE ID, signed with the
+// passport key, and publishes it. When the agent calls a service over mTLS
+// with its SVID as client certificate, a Verifier checks the SVID and that
+// its SPIFFE ID is the one bound to the presented passport:
+//
+//	v := &spiffe.Verifier{Bundles: bundles, Bindings: store}
+//	mux.Handle("POST /v1/orders", v.Middleware(auth, orders))
+package spiffe
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSVID reports a certificate chain that is not a valid X.509-SVID
+// of a trusted domain.
+var ErrInvalidSVID = errors.New("invalid SVID")
+
+// TrustDomain checks that id is a SPIFFE ID and returns its trust domain.
+func TrustDomain(id string) (string, error) {
+	rest, ok := strings.CutPrefix(id, "spiffe://")
+	if !ok {
+		return "", fmt.Errorf("%q is not a spiffe:// URI", id)
+	}
+	td, path, _ := strings.Cut(rest, "/")
+	if td == "" {
+		return "", fmt.Errorf("SPIFFE ID %q has no trust domain", id)
+	}
+	for _, c := range td {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+			return "", fmt.Errorf("SPIFFE ID %q: invalid trust domain character %q", id, c)
+		}
+	}
+	if path == "" {
+		if strings.HasSuffix(rest, "/") {
+			return "", fmt.Errorf("SPIFFE ID %q has a trailing slash", id)
+		}
+		return td, nil
+	}
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", fmt.Errorf("SPIFFE ID %q: invalid path segment %q", id, seg)
+		}
+		for _, c := range seg {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+				return "", fmt.Errorf("SPIFFE ID %q: invalid path character %q", id, c)
+			}
+		}
+	}
+	return td, nil
+}
+
+// Bundles are the X.509 trust bundles of the trusted SPIFFE trust domains,
+// by trust domain name.
+type Bundles map[string]*x509.CertPool
+
+// VerifySVID checks that chain, leaf first, is an X.509-SVID issued under
+// the bundle of its trust domain and valid at now, and returns its SPIFFE
+// ID. Its errors wrap ErrInvalidSVID.
+func (b Bundles) VerifySVID(chain []*x509.Certificate, now time.Time) (string, error) {
+	if len(chain) == 0 {
+		return "", fmt.Errorf("%w: no certificate", ErrInvalidSVID)
+	}
+	leaf := chain[0]
+	id, err := leafID(leaf)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSVID, err)
+	}
+	td, err := TrustDomain(id)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSVID, err)
+	}
+	roots := b[td]
+	if roots == nil {
+		return "", fmt.Errorf("%w: trust domain %s is not trusted", ErrInvalidSVID, td)
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSVID, err)
+	}
+	return id, nil
+}
+
+// leafID returns the SPIFFE ID of an X.509-SVID leaf certificate, which
+// must name exactly one URI and may not be a CA.
+func leafID(leaf *x509.Certificate) (string, error) {
+	if len(leaf.URIs) != 1 {
+		return "", fmt.Errorf("leaf certificate has %d URI SANs, not one", len(leaf.URIs))
+	}
+	if leaf.IsCA {
+		return "", errors.New("leaf certificate is a CA")
+	}
+	if leaf.KeyUsage&(x509.KeyUsageCertSign|x509.KeyUsageCRLSign) != 0 {
+		return "", errors.New("leaf certificate may sign certificates")
+	}
+	if leaf.KeyUsage != 0 && leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return "", errors.New("leaf certificate may not sign")
+	}
+	u := leaf.URIs[0]
+	if u.Scheme != "spiffe" || u.User != nil || u.RawQuery != "" || u.Fragment != "" || u.Port() != "" {
+		return "", fmt.Errorf("URI SAN %s is not a SPIFFE ID", u.Redacted())
+	}
+	return u.String(), nil
+}
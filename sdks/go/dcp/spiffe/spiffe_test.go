@@ -0,0 +1,153 @@
+package spiffe_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/spiffe"
+)
+
+// ca is the signing authority of a trust domain.
+type ca struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func newCA(t *testing.T, trustDomain string) *ca {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse("spiffe://" + trustDomain)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: trustDomain},
+		URIs:                  []*url.URL{u},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ca{cert: cert, key: key}
+}
+
+func (c *ca) bundles() spiffe.Bundles {
+	pool := x509.NewCertPool()
+	pool.AddCert(c.cert)
+	return spiffe.Bundles{c.cert.URIs[0].Host: pool}
+}
+
+// svid returns an X.509-SVID of c for uris, edited by edit if not nil, and
+// its key.
+func (c *ca) svid(t *testing.T, edit func(*x509.Certificate), uris ...string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	for _, s := range uris {
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl.URIs = append(tmpl.URIs, u)
+	}
+	if edit != nil {
+		edit(tmpl)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, c.cert, key.Public(), c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestTrustDomain(t *testing.T) {
+	for id, want := range map[string]string{
+		"spiffe://example.org":               "example.org",
+		"spiffe://example.org/ns/prod/agent": "example.org",
+		"spiffe://prod-1.example_org/a.b-c":  "prod-1.example_org",
+	} {
+		if td, err := spiffe.TrustDomain(id); err != nil || td != want {
+			t.Errorf("%s: %q, %v", id, td, err)
+		}
+	}
+	for _, id := range []string{
+		"https://example.org/agent",
+		"spiffe:///agent",
+		"spiffe://Example.org/agent",
+		"spiffe://example.org/",
+		"spiffe://example.org//agent",
+		"spiffe://example.org/a/../b",
+		"spiffe://example.org/agent?x=1",
+	} {
+		if _, err := spiffe.TrustDomain(id); err == nil {
+			t.Errorf("%s accepted", id)
+		}
+	}
+}
+
+func TestVerifySVID(t *testing.T) {
+	authority := newCA(t, "example.org")
+	bundles := authority.bundles()
+	now := time.Now()
+
+	leaf, _ := authority.svid(t, nil, "spiffe://example.org/agent/billing")
+	if id, err := bundles.VerifySVID([]*x509.Certificate{leaf}, now); err != nil || id != "spiffe://example.org/agent/billing" {
+		t.Fatalf("%q, %v", id, err)
+	}
+
+	other := newCA(t, "other.org")
+	foreign, _ := other.svid(t, nil, "spiffe://other.org/agent")
+	forged, _ := other.svid(t, nil, "spiffe://example.org/agent/billing")
+	twoIDs, _ := authority.svid(t, nil, "spiffe://example.org/a", "spiffe://example.org/b")
+	notSPIFFE, _ := authority.svid(t, nil, "https://example.org/agent")
+	caLeaf, _ := authority.svid(t, func(c *x509.Certificate) {
+		c.IsCA, c.BasicConstraintsValid = true, true
+	}, "spiffe://example.org/agent")
+	for name, chain := range map[string][]*x509.Certificate{
+		"no certificate":   nil,
+		"untrusted domain": {foreign},
+		"other authority":  {forged},
+		"two IDs":          {twoIDs},
+		"not SPIFFE":       {notSPIFFE},
+		"CA leaf":          {caLeaf},
+	} {
+		if _, err := bundles.VerifySVID(chain, now); !errors.Is(err, spiffe.ErrInvalidSVID) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if _, err := bundles.VerifySVID([]*x509.Certificate{leaf}, now.Add(2*time.Hour)); !errors.Is(err, spiffe.ErrInvalidSVID) {
+		t.Errorf("expired: %v", err)
+	}
+}
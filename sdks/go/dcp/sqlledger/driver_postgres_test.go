@@ -0,0 +1,8 @@
+//go:build postgres
+
+package sqlledger_test
+
+// Linking a driver requires adding it to go.mod, so it is opt-in:
+// go get github.com/jackc/pgx/v5 && DCP_TEST_POSTGRES_DSN=postgres://... \
+// go test -tags postgres ./dcp/sqlledger
+import _ "github.com/jackc/pgx/v5/stdlib"
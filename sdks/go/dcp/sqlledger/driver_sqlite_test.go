@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package sqlledger_test
+
+// Linking a driver requires adding it to go.mod, so it is opt-in:
+// go get modernc.org/sqlite && go test -tags sqlite ./dcp/sqlledger
+import _ "modernc.org/sqlite"
@@ -0,0 +1,142 @@
+package sqlledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// migration is one schema step. ddl is portable across the supported
+// dialects unless byDialect overrides it.
+type migration struct {
+	ddl       string
+	byDialect map[string]string
+}
+
+func (m migration) script(d dialect) string {
+	if s, ok := m.byDialect[d.name]; ok {
+		return s
+	}
+	return m.ddl
+}
+
+// migrations are applied in order and recorded in dcp_ledger_migrations.
+// Append new steps; never edit a released one.
+var migrations = []migration{
+	// 1: entries and checkpoints.
+	{ddl: `CREATE TABLE IF NOT EXISTS dcp_ledger_entries (
+		ledger_id  TEXT   NOT NULL,
+		idx        BIGINT NOT NULL,
+		audit_id   TEXT   NOT NULL,
+		agent_id   TEXT   NOT NULL,
+		human_id   TEXT   NOT NULL,
+		intent_id  TEXT   NOT NULL,
+		ts         TEXT   NOT NULL,
+		ts_unix_ms BIGINT,
+		entry_hash TEXT   NOT NULL,
+		entry_json TEXT   NOT NULL,
+		PRIMARY KEY (ledger_id, idx)
+	);
+	CREATE TABLE IF NOT EXISTS dcp_ledger_checkpoints (
+		ledger_id       TEXT   NOT NULL,
+		seq             BIGINT NOT NULL,
+		tree_size       BIGINT NOT NULL,
+		checkpoint_json TEXT   NOT NULL,
+		PRIMARY KEY (ledger_id, seq)
+	)`},
+	// 2: query indexes.
+	{ddl: `CREATE INDEX IF NOT EXISTS dcp_ledger_entries_agent ON dcp_ledger_entries (ledger_id, agent_id, idx);
+	CREATE INDEX IF NOT EXISTS dcp_ledger_entries_intent ON dcp_ledger_entries (ledger_id, intent_id, idx);
+	CREATE INDEX IF NOT EXISTS dcp_ledger_entries_time ON dcp_ledger_entries (ledger_id, ts_unix_ms)`},
+	// 3: flattened view for audit queries.
+	{byDialect: map[string]string{
+		"sqlite": `CREATE VIEW IF NOT EXISTS dcp_audit_entries AS
+		SELECT ledger_id, idx, audit_id, agent_id, human_id, intent_id, ts, entry_hash,
+			json_extract(entry_json, '$.policy_decision') AS policy_decision,
+			json_extract(entry_json, '$.outcome') AS outcome
+		FROM dcp_ledger_entries`,
+		"postgres": `CREATE OR REPLACE VIEW dcp_audit_entries AS
+		SELECT ledger_id, idx, audit_id, agent_id, human_id, intent_id, ts, entry_hash,
+			entry_json::jsonb ->> 'policy_decision' AS policy_decision,
+			entry_json::jsonb ->> 'outcome' AS outcome
+		FROM dcp_ledger_entries;
+		CREATE OR REPLACE VIEW dcp_ledger_heads AS
+		SELECT e.ledger_id, e.idx + 1 AS length, e.entry_hash AS head_hash, e.ts AS head_ts
+		FROM dcp_ledger_entries e
+		JOIN (SELECT ledger_id, MAX(idx) AS idx FROM dcp_ledger_entries GROUP BY ledger_id) m
+			ON m.ledger_id = e.ledger_id AND m.idx = e.idx`,
+	}},
+	// 4: prune summaries.
+	{ddl: `CREATE TABLE IF NOT EXISTS dcp_ledger_prunes (
+		ledger_id       TEXT   NOT NULL,
+		pruned_size     BIGINT NOT NULL,
+		last_entry_hash TEXT   NOT NULL,
+		summary_json    TEXT   NOT NULL,
+		PRIMARY KEY (ledger_id, pruned_size)
+	)`},
+}
+
+// migrationsLock is the lock key held while migrating, so concurrent
+// collectors apply each step once.
+const migrationsLock = "migrations"
+
+func migrate(ctx context.Context, db *sql.DB, d dialect) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS dcp_ledger_migrations (
+		version    BIGINT PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("sqlledger: create migrations table: %w", err)
+	}
+	for v := 1; v <= len(migrations); v++ {
+		if err := applyMigration(ctx, db, d, v); err != nil {
+			return fmt.Errorf("sqlledger: migration %d: %w", v, err)
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, d dialect, version int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if d.lock != nil {
+		if err := d.lock(ctx, tx, migrationsLock); err != nil {
+			return err
+		}
+	}
+	var applied int
+	err = tx.QueryRowContext(ctx, d.rebind(`SELECT COUNT(*) FROM dcp_ledger_migrations WHERE version = ?`), version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		return nil
+	}
+	// Not every driver accepts several statements per Exec.
+	for _, stmt := range splitStatements(migrations[version-1].script(d)) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, d.rebind(`INSERT INTO dcp_ledger_migrations (version, applied_at) VALUES (?, ?)`),
+		version, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func splitStatements(script string) []string {
+	var out []string
+	for _, stmt := range strings.Split(script, ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			out = append(out, stmt)
+		}
+	}
+	return out
+}
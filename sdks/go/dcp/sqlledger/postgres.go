@@ -0,0 +1,45 @@
+package sqlledger
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+var postgresDialect = dialect{
+	name:   "postgres",
+	rebind: rebindDollar,
+	lock: func(ctx context.Context, tx *sql.Tx, key string) error {
+		// Held until the transaction ends; keyed per ledger so agents
+		// append concurrently while each chain has a single writer.
+		_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, "dcp:"+key)
+		return err
+	},
+}
+
+// rebindDollar rewrites "?" placeholders as $1, $2, ... The package's
+// queries contain no "?" inside literals.
+func rebindDollar(q string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range q {
+		if r == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// OpenPostgres prepares db as a ledger collector: it applies pending schema
+// migrations, including the dcp_audit_entries view for ad-hoc audit
+// queries, and returns the Store for opts.LedgerID. Use Store.Ledger for the
+// chains of other agents in the same database. Unless opts.SkipVerify is
+// set, that first chain is verified. The Store takes ownership of db.
+func OpenPostgres(ctx context.Context, db *sql.DB, opts Options) (*Store, error) {
+	return open(ctx, db, postgresDialect, opts)
+}
@@ -0,0 +1,10 @@
+package sqlledger
+
+import "testing"
+
+func TestRebindDollar(t *testing.T) {
+	got := rebindDollar(`SELECT a FROM t WHERE x = ? AND y IN (?, ?)`)
+	if want := `SELECT a FROM t WHERE x = $1 AND y IN ($2, $3)`; got != want {
+		t.Fatalf("rebindDollar = %q, want %q", got, want)
+	}
+}
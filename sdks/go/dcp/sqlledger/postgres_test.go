@@ -0,0 +1,114 @@
+package sqlledger_test
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/ledgertest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/sqlledger"
+)
+
+// postgresDB connects to the server named by DCP_TEST_POSTGRES_DSN using a
+// linked driver (see driver_postgres_test.go), or skips.
+func postgresDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("DCP_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("DCP_TEST_POSTGRES_DSN not set")
+	}
+	for _, name := range sql.Drivers() {
+		if name == "pgx" || name == "postgres" {
+			db, err := sql.Open(name, dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return db
+		}
+	}
+	t.Skip("no PostgreSQL driver linked; run with -tags postgres")
+	return nil
+}
+
+// uniqueLedger returns a ledger ID no other test run uses, and removes its
+// rows when the test ends.
+func uniqueLedger(t *testing.T) string {
+	t.Helper()
+	b := make([]byte, 8)
+	rand.Read(b)
+	id := "test-" + hex.EncodeToString(b)
+	db := postgresDB(t)
+	t.Cleanup(func() {
+		defer db.Close()
+		db.Exec(`DELETE FROM dcp_ledger_entries WHERE ledger_id = $1`, id)
+		db.Exec(`DELETE FROM dcp_ledger_checkpoints WHERE ledger_id = $1`, id)
+	})
+	return id
+}
+
+func openPostgres(t *testing.T, opts sqlledger.Options) *sqlledger.Store {
+	t.Helper()
+	s, err := sqlledger.OpenPostgres(context.Background(), postgresDB(t), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestPostgresConformance(t *testing.T) {
+	postgresDB(t).Close()
+	ledgertest.Run(t, func(t *testing.T) dcp.LedgerStore {
+		return openPostgres(t, sqlledger.Options{LedgerID: uniqueLedger(t)})
+	})
+}
+
+func TestPostgresConcurrentWritersPerAgent(t *testing.T) {
+	postgresDB(t).Close()
+	ctx := context.Background()
+	agents := []string{uniqueLedger(t), uniqueLedger(t)}
+	key, _ := dcp.GenerateKeypair()
+
+	// Separate connection pools stand in for separate collector processes,
+	// so only the advisory lock keeps each chain linear.
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for w := 0; w < 4; w++ {
+		s := openPostgres(t, sqlledger.Options{LedgerID: agents[0], SkipVerify: true})
+		defer s.Close()
+		for _, id := range agents {
+			ledger := s.Ledger(id)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 5; i++ {
+					if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, ledger, ledgertest.Entry(i), key.SecretKeyB64); err != nil {
+						errs <- err
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	s := openPostgres(t, sqlledger.Options{LedgerID: agents[0]})
+	defer s.Close()
+	for _, id := range agents {
+		v, r := dcp.VerifyLedger(ctx, s.Ledger(id), dcp.AuditStreamOptions{AgentPublicKeyB64: key.PublicKeyB64})
+		if !r.Verified || v.Count() != 20 {
+			t.Fatalf("ledger %s: verified=%v count=%d errors=%v", id, r.Verified, v.Count(), r.Errors)
+		}
+	}
+	var n int
+	if err := postgresDB(t).QueryRow(`SELECT COUNT(*) FROM dcp_audit_entries WHERE ledger_id = $1 AND outcome = 'ok'`, agents[1]).Scan(&n); err != nil || n != 20 {
+		t.Fatalf("dcp_audit_entries view: %d rows, %v", n, err)
+	}
+}
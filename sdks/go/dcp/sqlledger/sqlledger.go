@@ -0,0 +1,486 @@
+// Package sqlledger implements dcp.LedgerStore on database/sql, for SQLite
+// (a single agent's local ledger) and PostgreSQL (a central collector
+// holding one chain per agent).
+//
+// The package does not import a database driver: register one in your
+// program (for example modernc.org/sqlite or github.com/jackc/pgx/v5/stdlib)
+// and pass the resulting *sql.DB to OpenSQLite or OpenPostgres.
+package sqlledger
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// DefaultLedgerID names the chain used when Options.LedgerID is empty.
+const DefaultLedgerID = "default"
+
+// rangeBatch is how many rows Range reads before calling back, so callbacks
+// never run while a result set holds a connection.
+const rangeBatch = 1000
+
+// Options configures a Store.
+type Options struct {
+	// LedgerID selects the chain within the database; several ledgers may
+	// share one database.
+	LedgerID string
+	// SkipVerify disables the hash-chain check performed on open.
+	SkipVerify bool
+	// AgentPublicKeyB64, if set, makes the check on open also verify
+	// agent_signature on entries that carry one.
+	AgentPublicKeyB64 string
+}
+
+// dialect captures the differences between SQL engines.
+type dialect struct {
+	name    string
+	pragmas []string
+	// rebind rewrites "?" placeholders for the engine.
+	rebind func(string) string
+	// lock, if set, takes a cross-process lock on key for the rest of tx.
+	lock func(ctx context.Context, tx *sql.Tx, key string) error
+}
+
+var sqliteDialect = dialect{
+	name: "sqlite",
+	pragmas: []string{
+		"PRAGMA journal_mode=WAL",
+		"PRAGMA synchronous=NORMAL",
+		"PRAGMA busy_timeout=5000",
+	},
+	rebind: func(q string) string { return q },
+}
+
+// Store is a dcp.LedgerStore backed by a SQL database. Appends are
+// serialized within the process, giving SQLite its single writer; on
+// PostgreSQL an advisory lock per ledger also serializes other processes.
+type Store struct {
+	db       *sql.DB
+	d        dialect
+	ledgerID string
+	ownsDB   bool
+
+	mu *sync.Mutex
+}
+
+var (
+	_ dcp.LedgerStore   = (*Store)(nil)
+	_ dcp.ChainAppender = (*Store)(nil)
+	_ dcp.LedgerPruner  = (*Store)(nil)
+)
+
+// OpenSQLite prepares db for use as a ledger: it enables WAL mode, applies
+// pending schema migrations and, unless opts.SkipVerify is set, verifies the
+// stored hash chain. The Store takes ownership of db and closes it on Close.
+// For an in-memory database call db.SetMaxOpenConns(1) first so every query
+// sees the same database.
+func OpenSQLite(ctx context.Context, db *sql.DB, opts Options) (*Store, error) {
+	return open(ctx, db, sqliteDialect, opts)
+}
+
+func open(ctx context.Context, db *sql.DB, d dialect, opts Options) (*Store, error) {
+	s := &Store{db: db, d: d, ledgerID: opts.LedgerID, ownsDB: true, mu: &sync.Mutex{}}
+	if s.ledgerID == "" {
+		s.ledgerID = DefaultLedgerID
+	}
+	for _, p := range d.pragmas {
+		if _, err := db.ExecContext(ctx, p); err != nil {
+			return nil, fmt.Errorf("sqlledger: %s: %w", p, err)
+		}
+	}
+	if err := migrate(ctx, db, d); err != nil {
+		return nil, err
+	}
+	if !opts.SkipVerify {
+		if _, r := dcp.VerifyLedger(ctx, s, dcp.AuditStreamOptions{AgentPublicKeyB64: opts.AgentPublicKeyB64}); !r.Verified {
+			return nil, fmt.Errorf("sqlledger: ledger %s failed verification: %s", s.ledgerID, strings.Join(r.Errors, "; "))
+		}
+	}
+	return s, nil
+}
+
+func (s *Store) q(query string) string {
+	return s.d.rebind(query)
+}
+
+// Ledger returns a Store for another chain in the same database, e.g. one
+// per agent on a collector. It shares s's connection pool; closing it does
+// not close the database. The chain is not verified; use dcp.VerifyLedger.
+func (s *Store) Ledger(ledgerID string) *Store {
+	return &Store{db: s.db, d: s.d, ledgerID: ledgerID, mu: s.mu}
+}
+
+// ID returns the ledger's identifier.
+func (s *Store) ID() string {
+	return s.ledgerID
+}
+
+// beginWrite starts a transaction holding the ledger's write lock.
+func (s *Store) beginWrite(ctx context.Context) (*sql.Tx, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sqlledger: begin: %w", err)
+	}
+	if s.d.lock != nil {
+		if err := s.d.lock(ctx, tx, "ledger:"+s.ledgerID); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("sqlledger: lock ledger %s: %w", s.ledgerID, err)
+		}
+	}
+	return tx, nil
+}
+
+// AppendEntry stores entry as canonical JSON alongside its hash and the
+// columns used by Find.
+func (s *Store) AppendEntry(ctx context.Context, entry dcp.AuditEntry) (int64, error) {
+	idx, _, err := s.append(ctx, entry, nil)
+	return idx, err
+}
+
+// AppendChained implements dcp.ChainAppender: under the ledger's write lock
+// it sets entry.PrevHash to the current head, applies prepare and stores the
+// result, so concurrent writers cannot fork the chain.
+func (s *Store) AppendChained(ctx context.Context, entry dcp.AuditEntry, prepare func(*dcp.AuditEntry) error) (int64, dcp.AuditEntry, error) {
+	return s.append(ctx, entry, prepare)
+}
+
+func (s *Store) append(ctx context.Context, entry dcp.AuditEntry, prepare func(*dcp.AuditEntry) error) (int64, dcp.AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, err := s.beginWrite(ctx)
+	if err != nil {
+		return 0, entry, err
+	}
+	defer tx.Rollback()
+
+	idx := int64(0)
+	head := "GENESIS"
+	err = tx.QueryRowContext(ctx, s.q(`SELECT idx + 1, entry_hash FROM dcp_ledger_entries WHERE ledger_id = ? ORDER BY idx DESC LIMIT 1`), s.ledgerID).Scan(&idx, &head)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Every entry may have been pruned; continue from the summary.
+		err = tx.QueryRowContext(ctx, s.q(`SELECT pruned_size, last_entry_hash FROM dcp_ledger_prunes WHERE ledger_id = ? ORDER BY pruned_size DESC LIMIT 1`), s.ledgerID).Scan(&idx, &head)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, entry, fmt.Errorf("sqlledger: read head: %w", err)
+	}
+	if prepare != nil {
+		entry.PrevHash = head
+		if err := prepare(&entry); err != nil {
+			return 0, entry, err
+		}
+	}
+
+	canon, err := dcp.Canonicalize(entry)
+	if err != nil {
+		return 0, entry, fmt.Errorf("sqlledger: %w", err)
+	}
+	var tsMillis sql.NullInt64
+	if ts, err := time.Parse(time.RFC3339, entry.Timestamp); err == nil {
+		tsMillis = sql.NullInt64{Int64: ts.UnixMilli(), Valid: true}
+	}
+	_, err = tx.ExecContext(ctx, s.q(`INSERT INTO dcp_ledger_entries
+		(ledger_id, idx, audit_id, agent_id, human_id, intent_id, ts, ts_unix_ms, entry_hash, entry_json)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		s.ledgerID, idx, entry.AuditID, entry.AgentID, entry.HumanID, entry.IntentID,
+		entry.Timestamp, tsMillis, sha256Hex(canon), canon)
+	if err != nil {
+		return 0, entry, fmt.Errorf("sqlledger: insert: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, entry, fmt.Errorf("sqlledger: commit: %w", err)
+	}
+	return idx, entry, nil
+}
+
+func sha256Hex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func decodeEntry(raw string) (dcp.AuditEntry, error) {
+	var e dcp.AuditEntry
+	if err := json.Unmarshal([]byte(raw), &e); err != nil {
+		return e, fmt.Errorf("sqlledger: decode entry: %w", err)
+	}
+	return e, nil
+}
+
+// GetByIndex returns the entry at index.
+func (s *Store) GetByIndex(ctx context.Context, index int64) (dcp.AuditEntry, error) {
+	var raw string
+	err := s.db.QueryRowContext(ctx, s.q(`SELECT entry_json FROM dcp_ledger_entries WHERE ledger_id = ? AND idx = ?`), s.ledgerID, index).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		pruned, err := s.prunedSize(ctx)
+		if err != nil {
+			return dcp.AuditEntry{}, err
+		}
+		if index >= 0 && index < pruned {
+			return dcp.AuditEntry{}, dcp.ErrLedgerEntryPruned
+		}
+		return dcp.AuditEntry{}, dcp.ErrLedgerEntryNotFound
+	}
+	if err != nil {
+		return dcp.AuditEntry{}, fmt.Errorf("sqlledger: %w", err)
+	}
+	return decodeEntry(raw)
+}
+
+func (s *Store) prunedSize(ctx context.Context) (int64, error) {
+	var n int64
+	err := s.db.QueryRowContext(ctx, s.q(`SELECT COALESCE(MAX(pruned_size), 0) FROM dcp_ledger_prunes WHERE ledger_id = ?`), s.ledgerID).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("sqlledger: %w", err)
+	}
+	return n, nil
+}
+
+type indexedEntry struct {
+	idx   int64
+	entry dcp.AuditEntry
+}
+
+// Range calls fn for each entry in [start, end), reading in batches.
+func (s *Store) Range(ctx context.Context, start, end int64, fn func(int64, dcp.AuditEntry) error) error {
+	if start < 0 {
+		start = 0
+	}
+	for start < end {
+		batch, err := s.queryEntries(ctx,
+			`SELECT idx, entry_json FROM dcp_ledger_entries WHERE ledger_id = ? AND idx >= ? AND idx < ? ORDER BY idx LIMIT ?`,
+			s.ledgerID, start, end, rangeBatch)
+		if err != nil {
+			return err
+		}
+		for _, ie := range batch {
+			if err := fn(ie.idx, ie.entry); err != nil {
+				return err
+			}
+		}
+		if len(batch) < rangeBatch {
+			return nil
+		}
+		start = batch[len(batch)-1].idx + 1
+	}
+	return nil
+}
+
+func (s *Store) queryEntries(ctx context.Context, query string, args ...interface{}) ([]indexedEntry, error) {
+	rows, err := s.db.QueryContext(ctx, s.q(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlledger: %w", err)
+	}
+	defer rows.Close()
+	var out []indexedEntry
+	for rows.Next() {
+		var ie indexedEntry
+		var raw string
+		if err := rows.Scan(&ie.idx, &raw); err != nil {
+			return nil, fmt.Errorf("sqlledger: %w", err)
+		}
+		if ie.entry, err = decodeEntry(raw); err != nil {
+			return nil, err
+		}
+		out = append(out, ie)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlledger: %w", err)
+	}
+	return out, nil
+}
+
+// Len returns the number of entries in the ledger, including pruned ones.
+func (s *Store) Len(ctx context.Context) (int64, error) {
+	var n int64
+	err := s.db.QueryRowContext(ctx, s.q(`SELECT COALESCE(MAX(n), 0) FROM (
+		SELECT MAX(idx) + 1 AS n FROM dcp_ledger_entries WHERE ledger_id = ?
+		UNION ALL
+		SELECT MAX(pruned_size) AS n FROM dcp_ledger_prunes WHERE ledger_id = ?
+	) lengths`), s.ledgerID, s.ledgerID).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("sqlledger: %w", err)
+	}
+	return n, nil
+}
+
+// SaveCheckpoint appends cp to the ledger's checkpoint history.
+func (s *Store) SaveCheckpoint(ctx context.Context, cp *dcp.Checkpoint) error {
+	raw, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("sqlledger: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, err := s.beginWrite(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var seq int64
+	err = tx.QueryRowContext(ctx, s.q(`SELECT COALESCE(MAX(seq) + 1, 0) FROM dcp_ledger_checkpoints WHERE ledger_id = ?`), s.ledgerID).Scan(&seq)
+	if err != nil {
+		return fmt.Errorf("sqlledger: save checkpoint: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, s.q(`INSERT INTO dcp_ledger_checkpoints (ledger_id, seq, tree_size, checkpoint_json) VALUES (?, ?, ?, ?)`),
+		s.ledgerID, seq, cp.TreeSize, string(raw))
+	if err != nil {
+		return fmt.Errorf("sqlledger: save checkpoint: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sqlledger: save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// LatestCheckpoint returns the most recently saved checkpoint.
+func (s *Store) LatestCheckpoint(ctx context.Context) (*dcp.Checkpoint, error) {
+	var raw string
+	err := s.db.QueryRowContext(ctx, s.q(`SELECT checkpoint_json FROM dcp_ledger_checkpoints WHERE ledger_id = ? ORDER BY seq DESC LIMIT 1`), s.ledgerID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, dcp.ErrNoCheckpoint
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sqlledger: %w", err)
+	}
+	var cp dcp.Checkpoint
+	if err := json.Unmarshal([]byte(raw), &cp); err != nil {
+		return nil, fmt.Errorf("sqlledger: decode checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// Prune implements dcp.LedgerPruner: it deletes the entries before
+// summary.PrunedSize and records summary, in one transaction.
+func (s *Store) Prune(ctx context.Context, summary *dcp.PruneSummary) error {
+	raw, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("sqlledger: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, err := s.beginWrite(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var stored int64
+	if err := tx.QueryRowContext(ctx, s.q(`SELECT COUNT(*) FROM dcp_ledger_entries WHERE ledger_id = ?`), s.ledgerID).Scan(&stored); err != nil {
+		return fmt.Errorf("sqlledger: prune: %w", err)
+	}
+	var pruned int64
+	if err := tx.QueryRowContext(ctx, s.q(`SELECT COALESCE(MAX(pruned_size), 0) FROM dcp_ledger_prunes WHERE ledger_id = ?`), s.ledgerID).Scan(&pruned); err != nil {
+		return fmt.Errorf("sqlledger: prune: %w", err)
+	}
+	if stored > 0 || pruned > 0 {
+		if summary.PrunedSize <= pruned {
+			return fmt.Errorf("sqlledger: ledger %s is already pruned to %d", s.ledgerID, pruned)
+		}
+		var hash string
+		err := tx.QueryRowContext(ctx, s.q(`SELECT entry_hash FROM dcp_ledger_entries WHERE ledger_id = ? AND idx = ?`), s.ledgerID, summary.PrunedSize-1).Scan(&hash)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("sqlledger: cannot prune ledger %s to %d: entry %d not stored", s.ledgerID, summary.PrunedSize, summary.PrunedSize-1)
+		}
+		if err != nil {
+			return fmt.Errorf("sqlledger: prune: %w", err)
+		}
+		if hash != summary.LastEntryHash {
+			return fmt.Errorf("sqlledger: prune summary does not match entry %d", summary.PrunedSize-1)
+		}
+		if _, err := tx.ExecContext(ctx, s.q(`DELETE FROM dcp_ledger_entries WHERE ledger_id = ? AND idx < ?`), s.ledgerID, summary.PrunedSize); err != nil {
+			return fmt.Errorf("sqlledger: prune: %w", err)
+		}
+	}
+	_, err = tx.ExecContext(ctx, s.q(`INSERT INTO dcp_ledger_prunes (ledger_id, pruned_size, last_entry_hash, summary_json) VALUES (?, ?, ?, ?)`),
+		s.ledgerID, summary.PrunedSize, summary.LastEntryHash, string(raw))
+	if err != nil {
+		return fmt.Errorf("sqlledger: prune: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sqlledger: prune: %w", err)
+	}
+	return nil
+}
+
+// LatestPruneSummary returns the summary recorded by the most recent Prune.
+func (s *Store) LatestPruneSummary(ctx context.Context) (*dcp.PruneSummary, error) {
+	var raw string
+	err := s.db.QueryRowContext(ctx, s.q(`SELECT summary_json FROM dcp_ledger_prunes WHERE ledger_id = ? ORDER BY pruned_size DESC LIMIT 1`), s.ledgerID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, dcp.ErrNotPruned
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sqlledger: %w", err)
+	}
+	var summary dcp.PruneSummary
+	if err := json.Unmarshal([]byte(raw), &summary); err != nil {
+		return nil, fmt.Errorf("sqlledger: decode prune summary: %w", err)
+	}
+	return &summary, nil
+}
+
+// Filter selects entries for Find. Empty fields match everything; From is
+// inclusive and To exclusive. Entries whose timestamp is not RFC 3339 never
+// match a time bound.
+type Filter struct {
+	AgentID  string
+	IntentID string
+	From     time.Time
+	To       time.Time
+	// Limit caps the number of results; 0 means no limit.
+	Limit int
+}
+
+// Find returns the entries matching f in ledger order, using the indexes on
+// agent_id, intent_id and timestamp.
+func (s *Store) Find(ctx context.Context, f Filter) ([]dcp.AuditEntry, error) {
+	query := `SELECT idx, entry_json FROM dcp_ledger_entries WHERE ledger_id = ?`
+	args := []interface{}{s.ledgerID}
+	if f.AgentID != "" {
+		query += ` AND agent_id = ?`
+		args = append(args, f.AgentID)
+	}
+	if f.IntentID != "" {
+		query += ` AND intent_id = ?`
+		args = append(args, f.IntentID)
+	}
+	if !f.From.IsZero() {
+		query += ` AND ts_unix_ms >= ?`
+		args = append(args, f.From.UnixMilli())
+	}
+	if !f.To.IsZero() {
+		query += ` AND ts_unix_ms < ?`
+		args = append(args, f.To.UnixMilli())
+	}
+	query += ` ORDER BY idx`
+	if f.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, f.Limit)
+	}
+	rows, err := s.queryEntries(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dcp.AuditEntry, len(rows))
+	for i, ie := range rows {
+		out[i] = ie.entry
+	}
+	return out, nil
+}
+
+// Close closes the underlying database if s was returned by an Open
+// function; for a Store from Ledger it does nothing.
+func (s *Store) Close() error {
+	if !s.ownsDB {
+		return nil
+	}
+	return s.db.Close()
+}
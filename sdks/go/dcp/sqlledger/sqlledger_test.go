@@ -0,0 +1,124 @@
+package sqlledger_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/ledgertest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/sqlledger"
+)
+
+// sqliteDB opens an in-memory database with whichever SQLite driver is
+// linked into the test binary (see driver_sqlite_test.go), or skips.
+func sqliteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	for _, name := range sql.Drivers() {
+		if name == "sqlite" || name == "sqlite3" {
+			db, err := sql.Open(name, ":memory:")
+			if err != nil {
+				t.Fatal(err)
+			}
+			db.SetMaxOpenConns(1)
+			return db
+		}
+	}
+	t.Skip("no SQLite driver linked; run with -tags sqlite")
+	return nil
+}
+
+func openSQLite(t *testing.T, db *sql.DB, opts sqlledger.Options) *sqlledger.Store {
+	t.Helper()
+	s, err := sqlledger.OpenSQLite(context.Background(), db, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	ledgertest.Run(t, func(t *testing.T) dcp.LedgerStore {
+		return openSQLite(t, sqliteDB(t), sqlledger.Options{})
+	})
+}
+
+func TestSQLiteFind(t *testing.T) {
+	s := openSQLite(t, sqliteDB(t), sqlledger.Options{})
+	defer s.Close()
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 6; i++ {
+		e := ledgertest.Entry(i)
+		e.Timestamp = base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		if i%2 == 1 {
+			e.IntentID = "intent002"
+		}
+		if _, err := s.AppendEntry(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := s.Find(ctx, sqlledger.Filter{IntentID: "intent002", From: base.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].AuditID != ledgertest.Entry(3).AuditID {
+		t.Fatalf("unexpected Find result: %+v", got)
+	}
+	if got, _ := s.Find(ctx, sqlledger.Filter{AgentID: "agent001", Limit: 4}); len(got) != 4 {
+		t.Fatalf("limit not applied: %d entries", len(got))
+	}
+}
+
+func TestSQLiteVerifiesOnOpen(t *testing.T) {
+	db := sqliteDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	s := openSQLite(t, db, sqlledger.Options{})
+	agent, _ := dcp.GenerateKeypair()
+	for i := 0; i < 3; i++ {
+		if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, s, ledgertest.Entry(i), agent.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+	}
+	openSQLite(t, db, sqlledger.Options{AgentPublicKeyB64: agent.PublicKeyB64})
+
+	if _, err := db.Exec(`UPDATE dcp_ledger_entries SET entry_json = replace(entry_json, '"outcome":"ok"', '"outcome":"changed"') WHERE idx = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlledger.OpenSQLite(ctx, db, sqlledger.Options{}); err == nil {
+		t.Fatal("a tampered ledger must fail verification on open")
+	}
+}
+
+func TestSQLiteLedgersAndView(t *testing.T) {
+	db := sqliteDB(t)
+	s := openSQLite(t, db, sqlledger.Options{LedgerID: "agent-a"})
+	defer s.Close()
+	ctx := context.Background()
+	key, _ := dcp.GenerateKeypair()
+	other := s.Ledger("agent-b")
+	for i := 0; i < 3; i++ {
+		if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, s, ledgertest.Entry(i), key.SecretKeyB64); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := dcp.AppendSignedAuditEntryToLedger(ctx, other, ledgertest.Entry(9), key.SecretKeyB64); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := other.Len(ctx); n != 1 {
+		t.Fatalf("ledgers must be independent, agent-b has %d entries", n)
+	}
+	if first, _ := other.GetByIndex(ctx, 0); first.PrevHash != "GENESIS" {
+		t.Fatal("each ledger starts its own chain")
+	}
+	if _, r := dcp.VerifyLedger(ctx, s, dcp.AuditStreamOptions{AgentPublicKeyB64: key.PublicKeyB64}); !r.Verified {
+		t.Fatal(r.Errors)
+	}
+	other.Close()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM dcp_audit_entries WHERE ledger_id = 'agent-a' AND outcome = 'ok'`).Scan(&n); err != nil || n != 3 {
+		t.Fatalf("dcp_audit_entries view: %d rows, %v", n, err)
+	}
+}
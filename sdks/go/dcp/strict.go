@@ -0,0 +1,296 @@
+package dcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Parse error codes reported by ParseSignedBundleStrict.
+const (
+	ParseErrSyntax       = "syntax"
+	ParseErrDuplicateKey = "duplicate_key"
+	ParseErrUnknownField = "unknown_field"
+	ParseErrMissingField = "missing_field"
+	ParseErrType         = "type"
+)
+
+// ParseError is a single problem found while strictly parsing a document.
+// Pointer is an RFC 6901 JSON pointer to the offending location.
+type ParseError struct {
+	Pointer string `json:"pointer"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *ParseError) Error() string {
+	p := e.Pointer
+	if p == "" {
+		p = "/"
+	}
+	return fmt.Sprintf("%s: %s", p, e.Message)
+}
+
+// ParseErrors collects every ParseError found in a document.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, pe := range e {
+		msgs[i] = pe.Error()
+	}
+	return "strict parse: " + strings.Join(msgs, "; ")
+}
+
+// strictSchema mirrors the JSON Schema constraints in schemas/v1 that the Go
+// structs cannot express on their own: required members, and whether members
+// outside the struct are tolerated (additionalProperties: true).
+type strictSchema struct {
+	required []string
+	open     bool
+}
+
+var strictSchemas = map[reflect.Type]strictSchema{
+	reflect.TypeOf(SignedBundle{}): {required: []string{"bundle", "signature"}},
+	reflect.TypeOf(CitizenshipBundle{}): {required: []string{
+		"responsible_principal_record", "agent_passport", "intent", "policy_decision", "audit_entries",
+	}},
+	reflect.TypeOf(ResponsiblePrincipalRecord{}): {required: []string{
+		"dcp_version", "human_id", "legal_name", "entity_type", "jurisdiction", "liability_mode",
+		"override_rights", "issued_at", "expires_at", "signature",
+	}},
+	reflect.TypeOf(AgentPassport{}): {required: []string{
+		"dcp_version", "agent_id", "public_key", "principal_binding_reference", "created_at", "status", "signature",
+	}},
+	reflect.TypeOf(Intent{}): {required: []string{
+		"dcp_version", "intent_id", "agent_id", "human_id", "timestamp", "action_type", "target",
+		"data_classes", "estimated_impact",
+	}},
+	reflect.TypeOf(IntentTarget{}): {required: []string{"channel"}, open: true},
+	reflect.TypeOf(DomainList{}):   {},
+	reflect.TypeOf(PolicyDecision{}): {required: []string{
+		"dcp_version", "intent_id", "decision", "risk_score", "reasons",
+	}},
+	reflect.TypeOf(RequiredConfirmation{}): {required: []string{"type"}},
+	reflect.TypeOf(AuditEntry{}): {required: []string{
+		"dcp_version", "audit_id", "prev_hash", "timestamp", "agent_id", "human_id", "intent_id",
+		"intent_hash", "policy_decision", "outcome", "evidence",
+	}},
+	reflect.TypeOf(AuditEvidence{}): {open: true},
+	reflect.TypeOf(BatchItem{}):     {required: []string{"index", "item"}},
+	reflect.TypeOf(IntentBatch{}):   {required: []string{"count", "manifest_hash"}},
+	reflect.TypeOf(ChainAnchor{}):   {required: []string{"prev_entry_hash"}},
+	reflect.TypeOf(ConsentRecord{}): {required: []string{
+		"dcp_version", "human_id", "intent_hash", "scope", "granted_at", "expires_at", "signature",
+	}},
+	reflect.TypeOf(OverrideRecord{}): {required: []string{
+		"dcp_version", "human_id", "agent_id", "intent_id", "intent_hash", "action", "reason", "timestamp",
+		"prev_hash", "signature",
+	}},
+	reflect.TypeOf(BreakGlassRecord{}): {required: []string{
+		"dcp_version", "agent_id", "intent_id", "intent_hash", "authorized_by", "justification", "scope",
+		"not_before", "not_after", "signature", "countersigner", "countersigner_public_key", "countersignature",
+	}},
+	reflect.TypeOf(IntentCancellation{}): {required: []string{
+		"dcp_version", "intent_id", "intent_hash", "agent_id", "timestamp", "reason", "signature",
+	}},
+	reflect.TypeOf(DelegationRecord{}): {required: []string{
+		"dcp_version", "parent_agent_id", "child_agent_id", "child_public_key", "human_id", "capabilities",
+		"not_before", "not_after", "signature",
+	}},
+	reflect.TypeOf(DelegationLink{}): {required: []string{"parent_passport", "delegation"}},
+	reflect.TypeOf(PowerOfAttorney{}): {required: []string{
+		"dcp_version", "grantor_id", "grantor_public_key", "attorney_id", "attorney_public_key", "scope",
+		"not_before", "not_after", "signature",
+	}},
+	reflect.TypeOf(InsuranceAttestation{}): {required: []string{
+		"dcp_version", "attester_id", "attester_type", "human_id", "agent_id", "policy_number_hash",
+		"coverage_class", "not_before", "not_after", "signature",
+	}},
+	reflect.TypeOf(GuardianBinding{}): {required: []string{
+		"guardian_id", "guardian_public_key", "relationship", "legal_basis",
+	}},
+	reflect.TypeOf(PrincipalSignerSet{}):   {required: []string{"threshold", "signers"}},
+	reflect.TypeOf(PrincipalMember{}):      {required: []string{"human_id", "public_key"}},
+	reflect.TypeOf(PrincipalCosignature{}): {required: []string{"human_id", "sig_b64"}},
+	reflect.TypeOf(BundleSignature{}):      {required: []string{"alg", "created_at", "signer", "bundle_hash", "sig_b64"}},
+	reflect.TypeOf(Signer{}):               {required: []string{"type", "id", "public_key_b64"}},
+}
+
+// ParseSignedBundleStrict parses a signed bundle received from an untrusted
+// party. Unlike json.Unmarshal it rejects duplicate object keys, members not
+// defined by DCP-01/02/03 (the struct-level equivalent of
+// DisallowUnknownFields, relaxed only where the schema sets
+// additionalProperties), missing required members, and JSON values of the
+// wrong type, including null for non-nullable members. All problems are
+// reported together as ParseErrors.
+func ParseSignedBundleStrict(data []byte) (*RawSignedBundle, error) {
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, ParseErrors{{Code: ParseErrSyntax, Message: err.Error()}}
+	}
+	errs := findDuplicateKeys(data)
+	strictWalk(generic, reflect.TypeOf(SignedBundle{}), "", &errs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return ParseSignedBundle(data)
+}
+
+func jsonPointerToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func jsonFieldNames(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func strictWalk(v interface{}, t reflect.Type, path string, errs *ParseErrors) {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		nullable = true
+		t = t.Elem()
+	}
+	if v == nil {
+		// Only pointer members (expires_at, evidence.tool, ...) are nullable in the schemas.
+		if !nullable {
+			*errs = append(*errs, &ParseError{Pointer: path, Code: ParseErrType, Message: "null is not allowed"})
+		}
+		return
+	}
+	typeErr := func(want string) {
+		*errs = append(*errs, &ParseError{Pointer: path, Code: ParseErrType, Message: "expected " + want})
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			typeErr("object")
+			return
+		}
+		schema := strictSchemas[t]
+		fields := jsonFieldNames(t)
+		for _, name := range schema.required {
+			if _, ok := obj[name]; !ok {
+				*errs = append(*errs, &ParseError{
+					Pointer: path + "/" + jsonPointerToken(name),
+					Code:    ParseErrMissingField,
+					Message: "required member is missing",
+				})
+			}
+		}
+		for _, name := range sortedKeys(obj) {
+			ft, known := fields[name]
+			p := path + "/" + jsonPointerToken(name)
+			if !known {
+				if !schema.open {
+					*errs = append(*errs, &ParseError{Pointer: p, Code: ParseErrUnknownField, Message: "unknown member"})
+				}
+				continue
+			}
+			strictWalk(obj[name], ft, p, errs)
+		}
+	case reflect.Slice:
+		arr, ok := v.([]interface{})
+		if !ok {
+			typeErr("array")
+			return
+		}
+		for i, item := range arr {
+			strictWalk(item, t.Elem(), fmt.Sprintf("%s/%d", path, i), errs)
+		}
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			typeErr("object")
+			return
+		}
+		for _, name := range sortedKeys(obj) {
+			strictWalk(obj[name], t.Elem(), path+"/"+jsonPointerToken(name), errs)
+		}
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			typeErr("string")
+		}
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			typeErr("boolean")
+		}
+	case reflect.Float64, reflect.Int:
+		if _, ok := v.(float64); !ok {
+			typeErr("number")
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// findDuplicateKeys scans the token stream, since encoding/json silently keeps
+// the last occurrence of a repeated key.
+func findDuplicateKeys(data []byte) ParseErrors {
+	var errs ParseErrors
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := scanDuplicateKeys(dec, "", &errs); err != nil && !errors.Is(err, io.EOF) {
+		errs = append(errs, &ParseError{Code: ParseErrSyntax, Message: err.Error()})
+	}
+	return errs
+}
+
+func scanDuplicateKeys(dec *json.Decoder, path string, errs *ParseErrors) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+	switch delim {
+	case '{':
+		seen := map[string]bool{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyTok.(string)
+			p := path + "/" + jsonPointerToken(key)
+			if seen[key] {
+				*errs = append(*errs, &ParseError{Pointer: p, Code: ParseErrDuplicateKey, Message: "duplicate key"})
+			}
+			seen[key] = true
+			if err := scanDuplicateKeys(dec, p, errs); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; dec.More(); i++ {
+			if err := scanDuplicateKeys(dec, fmt.Sprintf("%s/%d", path, i), errs); err != nil {
+				return err
+			}
+		}
+	}
+	_, err = dec.Token() // closing delimiter
+	return err
+}
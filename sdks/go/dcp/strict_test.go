@@ -0,0 +1,86 @@
+package dcp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func strictErrors(t *testing.T, doc string) ParseErrors {
+	t.Helper()
+	_, err := ParseSignedBundleStrict([]byte(doc))
+	if err == nil {
+		t.Fatal("expected strict parse to fail")
+	}
+	var pe ParseErrors
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected ParseErrors, got %T", err)
+	}
+	return pe
+}
+
+func hasParseError(errs ParseErrors, code, pointer string) bool {
+	for _, e := range errs {
+		if e.Code == code && e.Pointer == pointer {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseSignedBundleStrictAcceptsFixture(t *testing.T) {
+	rsb, err := ParseSignedBundleStrict(readSignedBundleFixture(t))
+	if err != nil {
+		t.Fatalf("fixture should parse strictly: %v", err)
+	}
+	if r := VerifyRawSignedBundle(rsb, ""); !r.Verified {
+		t.Fatalf("fixture should verify: %v", r.Errors)
+	}
+}
+
+func TestParseSignedBundleStrictUnknownField(t *testing.T) {
+	doc := strings.Replace(string(readSignedBundleFixture(t)), `"intent_id": "intent001",`, `"intent_id": "intent001", "smuggled": true,`, 1)
+	errs := strictErrors(t, doc)
+	if !hasParseError(errs, ParseErrUnknownField, "/bundle/intent/smuggled") {
+		t.Fatalf("missing unknown_field error: %v", errs)
+	}
+}
+
+func TestParseSignedBundleStrictOpenObjects(t *testing.T) {
+	doc := strings.Replace(string(readSignedBundleFixture(t)), `"tool": "smtp",`, `"tool": "smtp", "latency_ms": 12,`, 1)
+	if _, err := ParseSignedBundleStrict([]byte(doc)); err != nil {
+		t.Fatalf("evidence allows additional members per schema: %v", err)
+	}
+}
+
+func TestParseSignedBundleStrictDuplicateKey(t *testing.T) {
+	doc := strings.Replace(string(readSignedBundleFixture(t)), `"decision": "approve",`, `"decision": "block", "decision": "approve",`, 1)
+	errs := strictErrors(t, doc)
+	if !hasParseError(errs, ParseErrDuplicateKey, "/bundle/policy_decision/decision") {
+		t.Fatalf("missing duplicate_key error: %v", errs)
+	}
+}
+
+func TestParseSignedBundleStrictMissingAndTypeErrors(t *testing.T) {
+	doc := string(readSignedBundleFixture(t))
+	doc = strings.Replace(doc, `"legal_name": "Alice Example",`, ``, 1)
+	doc = strings.Replace(doc, `"override_rights": true`, `"override_rights": "yes"`, 1)
+	doc = strings.Replace(doc, `"audit_id": "audit001"`, `"audit_id": null`, 1)
+	errs := strictErrors(t, doc)
+	for _, want := range []struct{ code, ptr string }{
+		{ParseErrMissingField, "/bundle/responsible_principal_record/legal_name"},
+		{ParseErrType, "/bundle/responsible_principal_record/override_rights"},
+		{ParseErrType, "/bundle/audit_entries/0/audit_id"},
+	} {
+		if !hasParseError(errs, want.code, want.ptr) {
+			t.Errorf("missing %s at %s: %v", want.code, want.ptr, errs)
+		}
+	}
+}
+
+func TestParseSignedBundleStrictSyntax(t *testing.T) {
+	errs := strictErrors(t, `{"bundle": `)
+	if errs[0].Code != ParseErrSyntax {
+		t.Fatalf("expected syntax error, got %v", errs)
+	}
+}
@@ -0,0 +1,88 @@
+package dcp
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timestamps are kept as strings in the record structs so that records
+// canonicalize to exactly the bytes that were signed; a time.Time would
+// normalize offsets and fractional seconds. The accessors below parse them.
+
+// FormatTime returns t in the form DCP records carry: RFC 3339 in UTC,
+// to the second.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ParseTime parses an RFC 3339 timestamp as found in a DCP record.
+func ParseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timestamp %q: not RFC 3339", s)
+	}
+	return t, nil
+}
+
+// IssuedAtTime parses issued_at.
+func (r *ResponsiblePrincipalRecord) IssuedAtTime() (time.Time, error) {
+	return ParseTime(r.IssuedAt)
+}
+
+// ExpiresAtTime parses expires_at. A record without an expiry returns the
+// zero time and no error.
+func (r *ResponsiblePrincipalRecord) ExpiresAtTime() (time.Time, error) {
+	if r.ExpiresAt == nil {
+		return time.Time{}, nil
+	}
+	return ParseTime(*r.ExpiresAt)
+}
+
+// CreatedAtTime parses created_at.
+func (p *AgentPassport) CreatedAtTime() (time.Time, error) {
+	return ParseTime(p.CreatedAt)
+}
+
+// TimestampTime parses timestamp.
+func (i *Intent) TimestampTime() (time.Time, error) {
+	return ParseTime(i.Timestamp)
+}
+
+// ExpiresAtTime parses expires_at. An intent without an expiry returns the
+// zero time and no error.
+func (i *Intent) ExpiresAtTime() (time.Time, error) {
+	if i.ExpiresAt == nil {
+		return time.Time{}, nil
+	}
+	return ParseTime(*i.ExpiresAt)
+}
+
+// TimestampTime parses timestamp.
+func (e *AuditEntry) TimestampTime() (time.Time, error) {
+	return ParseTime(e.Timestamp)
+}
+
+// CreatedAtTime parses created_at.
+func (s *BundleSignature) CreatedAtTime() (time.Time, error) {
+	return ParseTime(s.CreatedAt)
+}
+
+// TimestampTime parses timestamp.
+func (r *RevocationRecord) TimestampTime() (time.Time, error) {
+	return ParseTime(r.Timestamp)
+}
+
+// TimestampTime parses timestamp.
+func (o *OverrideRecord) TimestampTime() (time.Time, error) {
+	return ParseTime(o.Timestamp)
+}
+
+// TimestampTime parses timestamp.
+func (c *IntentCancellation) TimestampTime() (time.Time, error) {
+	return ParseTime(c.Timestamp)
+}
+
+// TimestampTime parses timestamp.
+func (c *Checkpoint) TimestampTime() (time.Time, error) {
+	return ParseTime(c.Timestamp)
+}
@@ -0,0 +1,48 @@
+package dcp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestTimestampAccessors(t *testing.T) {
+	expires := "2027-01-01T00:00:00+02:00"
+	rpr := dcp.ResponsiblePrincipalRecord{IssuedAt: "2026-01-01T12:30:00.250Z", ExpiresAt: &expires}
+	issued, err := rpr.IssuedAtTime()
+	if err != nil || !issued.Equal(time.Date(2026, 1, 1, 12, 30, 0, 250e6, time.UTC)) {
+		t.Fatalf("issued_at = %v, %v", issued, err)
+	}
+	exp, err := rpr.ExpiresAtTime()
+	if err != nil || !exp.Equal(time.Date(2026, 12, 31, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expires_at = %v, %v", exp, err)
+	}
+	rpr.ExpiresAt = nil
+	if exp, err := rpr.ExpiresAtTime(); err != nil || !exp.IsZero() {
+		t.Fatalf("missing expires_at = %v, %v", exp, err)
+	}
+
+	entry := dcp.AuditEntry{Timestamp: "01/02/2026"}
+	if _, err := entry.TimestampTime(); err == nil {
+		t.Fatal("non-RFC 3339 timestamp should fail to parse")
+	}
+
+	// Parsing must not disturb the signed form.
+	before, _ := dcp.Canonicalize(rpr)
+	rpr.IssuedAtTime()
+	if after, _ := dcp.Canonicalize(rpr); after != before {
+		t.Fatal("canonical form changed")
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	ts := time.Date(2026, 3, 4, 5, 6, 7, 891, time.FixedZone("CET", 3600))
+	if got := dcp.FormatTime(ts); got != "2026-03-04T04:06:07Z" {
+		t.Fatalf("FormatTime = %s", got)
+	}
+	back, err := dcp.ParseTime(dcp.FormatTime(ts))
+	if err != nil || !back.Equal(ts.Truncate(time.Second)) {
+		t.Fatalf("round trip = %v, %v", back, err)
+	}
+}
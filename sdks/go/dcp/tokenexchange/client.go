@@ -0,0 +1,98 @@
+package tokenexchange
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcpheader"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcauth"
+)
+
+// Client exchanges an agent's passport for tokens.
+type Client struct {
+	// URL is the token endpoint, e.g. "https://auth.example/token".
+	URL      string
+	Passport *dcp.AgentPassport
+	// Signer is the agent key the passport carries; it declares intents.
+	Signer dcp.BundleSigner
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Now is the clock intent declarations are dated with; nil means
+	// time.Now.
+	Now func() time.Time
+}
+
+// Exchange declares intent and returns a token for it, narrowed to scopes
+// if any are given. A refusal is an *Error.
+func (c *Client) Exchange(ctx context.Context, intent *dcp.Intent, scopes ...string) (*TokenResponse, error) {
+	if c.Passport == nil || c.Signer == nil {
+		return nil, errors.New("tokenexchange: a passport and a signer are required")
+	}
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+	ref, err := grpcauth.NewIntentReference(intent, c.Signer, now())
+	if err != nil {
+		return nil, fmt.Errorf("tokenexchange: intent: %v", err)
+	}
+	passport, err := agentauth.EncodePassport(c.Passport)
+	if err != nil {
+		return nil, fmt.Errorf("tokenexchange: passport: %v", err)
+	}
+	intentJSON, err := json.Marshal(intent)
+	if err != nil {
+		return nil, fmt.Errorf("tokenexchange: intent: %v", err)
+	}
+	form := url.Values{
+		"grant_type":         {GrantTypeTokenExchange},
+		"subject_token":      {passport},
+		"subject_token_type": {TokenTypeAgentPassport},
+		IntentParam:          {base64.StdEncoding.EncodeToString(intentJSON)},
+		IntentRefParam:       {dcpheader.EncodeIntent(ref)},
+	}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		e := &Error{Status: resp.StatusCode}
+		if json.Unmarshal(data, e) != nil || e.Code == "" {
+			return nil, fmt.Errorf("tokenexchange: %s", resp.Status)
+		}
+		return nil, e
+	}
+	var tr TokenResponse
+	if err := json.Unmarshal(data, &tr); err != nil {
+		return nil, fmt.Errorf("tokenexchange: %v", err)
+	}
+	return &tr, nil
+}
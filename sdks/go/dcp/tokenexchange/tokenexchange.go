@@ -0,0 +1,427 @@
+// Package tokenexchange bridges DCP into OAuth-protected APIs: an OAuth 2.0
+// Token Exchange (RFC 8693) endpoint trading an agent's passport and a
+// declared intent for a short-lived bearer token scoped to what the
+// passport grants and the policy approves.
+//
+// Endpoints:
+//
+//	POST /token        RFC 8693 token exchange; answers an RFC 6749 token response
+//	POST /introspect   RFC 7662 token introspection
+//
+// The token request is form-encoded:
+//
+//	grant_type=urn:ietf:params:oauth:grant-type:token-exchange
+//	subject_token=<DCP-Agent-Passport header value>
+//	subject_token_type=urn:dcp:params:oauth:token-type:agent-passport
+//	dcp_intent=<base64 of the intent JSON>
+//	dcp_intent_ref=<DCP-Intent header value declaring the intent>
+//	scope=<optional, space-separated subset of the grantable scopes>
+//
+// The intent declaration is signed with the passport key, so a copied
+// passport cannot be exchanged. The passport is checked with an
+// agentauth.Authenticator and the intent decided by a Decider, such as a
+// pdp.Server or apiclient.PDP: a blocked or escalated intent gets no
+// token. The grantable scopes are those Config.Scopes maps the passport's
+// capabilities to.
+//
+// Tokens are opaque and held in memory; resource servers check them at
+// the introspection endpoint, which answers the DCP context of the token
+// as dcp_* members.
+package tokenexchange
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcpheader"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/grpcauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+)
+
+// Grant, token and parameter identifiers.
+const (
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	TokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeAgentPassport = "urn:dcp:params:oauth:token-type:agent-passport"
+	IntentParam            = "dcp_intent"
+	IntentRefParam         = "dcp_intent_ref"
+)
+
+// DefaultTTL is the lifetime of tokens when Config.TTL is zero.
+const DefaultTTL = 5 * time.Minute
+
+// Error is an OAuth error response (RFC 6749 section 5.2).
+type Error struct {
+	Status      int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+func oauthError(status int, code, format string, args ...interface{}) *Error {
+	return &Error{Status: status, Code: code, Description: fmt.Sprintf(format, args...)}
+}
+
+// Decider decides intents. *pdp.Server and *apiclient.PDP are Deciders.
+type Decider interface {
+	Decide(ctx context.Context, intent *dcp.Intent) (*pdp.SignedDecision, error)
+}
+
+// Config configures a Server.
+type Config struct {
+	// Authenticator checks the presented passports. Required.
+	Authenticator *agentauth.Authenticator
+	// Decider decides the declared intents. Required.
+	Decider Decider
+	// DecisionKey, if set, is the key the Decider's decisions must be
+	// signed with.
+	DecisionKey string
+	// Scopes maps a capability to the scopes it grants; a capability it
+	// does not list grants the scope of its own name. nil grants every
+	// capability as a scope.
+	Scopes map[string][]string
+	// TTL is the lifetime of issued tokens; zero means DefaultTTL.
+	TTL time.Duration
+	// MaxSkew bounds the age of intent declarations; zero means
+	// grpcauth.DefaultMaxSkew.
+	MaxSkew time.Duration
+	// IntrospectionToken, if set, is the bearer token resource servers
+	// must present to the introspection endpoint.
+	IntrospectionToken string
+	// Now is the clock tokens expire by; nil means time.Now.
+	Now func() time.Time
+}
+
+// Token is an issued access token and the context it was issued in.
+type Token struct {
+	AccessToken string
+	Scopes      []string
+	AgentID     string
+	HumanID     string
+	IntentID    string
+	RiskScore   float64
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+}
+
+// TokenResponse is the answer to a successful exchange.
+type TokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+	Scope           string `json:"scope,omitempty"`
+}
+
+// Introspection is the answer of the introspection endpoint. An unknown or
+// expired token is only {"active": false}.
+type Introspection struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	HumanID   string   `json:"dcp_human_id,omitempty"`
+	IntentID  string   `json:"dcp_intent_id,omitempty"`
+	RiskScore *float64 `json:"dcp_risk_score,omitempty"`
+}
+
+// Request is a token exchange request.
+type Request struct {
+	Passport *dcp.AgentPassport
+	Intent   *dcp.Intent
+	// IntentRef declares Intent, signed with the passport key.
+	IntentRef *grpcauth.IntentReference
+	// Scopes, if not empty, narrows the token to these scopes.
+	Scopes []string
+}
+
+// Server exchanges passports for tokens. Create one with New.
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+
+	mu     sync.Mutex
+	tokens map[string]*Token
+}
+
+// New returns a Server for cfg.
+func New(cfg Config) (*Server, error) {
+	if cfg.Authenticator == nil || cfg.Decider == nil {
+		return nil, errors.New("tokenexchange: an authenticator and a decider are required")
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+	if cfg.MaxSkew <= 0 {
+		cfg.MaxSkew = grpcauth.DefaultMaxSkew
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	s := &Server{cfg: cfg, mux: http.NewServeMux(), tokens: map[string]*Token{}}
+	s.mux.HandleFunc("POST /token", s.handleToken)
+	s.mux.HandleFunc("POST /introspect", s.handleIntrospect)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Exchange checks req and issues a token. Its errors are *Error.
+func (s *Server) Exchange(ctx context.Context, req *Request) (*Token, error) {
+	if req.Passport == nil || req.Intent == nil || req.IntentRef == nil {
+		return nil, oauthError(http.StatusBadRequest, "invalid_request", "a passport, an intent and its declaration are required")
+	}
+	id, err := s.cfg.Authenticator.Check(ctx, req.Passport, nil)
+	switch {
+	case errors.Is(err, agentauth.ErrLookup):
+		return nil, oauthError(http.StatusServiceUnavailable, "temporarily_unavailable", "%v", err)
+	case err != nil:
+		return nil, oauthError(http.StatusBadRequest, "invalid_grant", "%v", err)
+	}
+	if err := s.checkIntent(req); err != nil {
+		return nil, oauthError(http.StatusBadRequest, "invalid_grant", "intent: %v", err)
+	}
+
+	d, err := s.cfg.Decider.Decide(ctx, req.Intent)
+	if err != nil {
+		return nil, oauthError(http.StatusServiceUnavailable, "temporarily_unavailable", "policy decision: %v", err)
+	}
+	if s.cfg.DecisionKey != "" {
+		if err := d.Verify(s.cfg.DecisionKey, req.Intent); err != nil {
+			return nil, oauthError(http.StatusServiceUnavailable, "temporarily_unavailable", "policy decision: %v", err)
+		}
+	}
+	if decision := d.PolicyDecision.Decision; decision != dcp.DecisionApprove {
+		return nil, oauthError(http.StatusBadRequest, "invalid_grant", "intent %s: policy decision is %s: %s",
+			req.Intent.IntentID, decision, strings.Join(d.PolicyDecision.Reasons, "; "))
+	}
+
+	scopes, err := s.scopes(&id.Passport, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, oauthError(http.StatusInternalServerError, "server_error", "%v", err)
+	}
+	now := s.cfg.Now()
+	t := &Token{
+		AccessToken: base64.RawURLEncoding.EncodeToString(raw),
+		Scopes:      scopes,
+		AgentID:     id.AgentID(),
+		HumanID:     id.HumanID(),
+		IntentID:    req.Intent.IntentID,
+		RiskScore:   d.PolicyDecision.RiskScore,
+		IssuedAt:    now,
+		ExpiresAt:   now.Add(s.cfg.TTL),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, old := range s.tokens {
+		if !now.Before(old.ExpiresAt) {
+			delete(s.tokens, k)
+		}
+	}
+	s.tokens[t.AccessToken] = t
+	return t, nil
+}
+
+// checkIntent checks that the declaration is the passport agent's, signed
+// with its key, recent, and declares the intent.
+func (s *Server) checkIntent(req *Request) error {
+	if req.Intent.AgentID != req.Passport.AgentID || req.IntentRef.AgentID != req.Passport.AgentID {
+		return fmt.Errorf("not declared by %s", req.Passport.AgentID)
+	}
+	if err := req.Intent.Validate(); err != nil {
+		return err
+	}
+	if !req.IntentRef.Matches(req.Intent) {
+		return errors.New("the declaration is of another intent")
+	}
+	if err := req.IntentRef.Verify(req.Passport.PublicKey); err != nil {
+		return err
+	}
+	issued, err := dcp.ParseTime(req.IntentRef.IssuedAt)
+	if err != nil {
+		return fmt.Errorf("issued: %v", err)
+	}
+	if skew := s.cfg.Now().Sub(issued); skew > s.cfg.MaxSkew || skew < -s.cfg.MaxSkew {
+		return fmt.Errorf("declared at %s, outside the accepted %s", req.IntentRef.IssuedAt, s.cfg.MaxSkew)
+	}
+	return nil
+}
+
+// scopes returns the scopes granted to p, narrowed to requested if it is
+// not empty.
+func (s *Server) scopes(p *dcp.AgentPassport, requested []string) ([]string, error) {
+	grantable := map[string]bool{}
+	for _, c := range p.Capabilities {
+		mapped, ok := s.cfg.Scopes[c]
+		if !ok {
+			mapped = []string{c}
+		}
+		for _, scope := range mapped {
+			grantable[scope] = true
+		}
+	}
+	var scopes []string
+	if len(requested) == 0 {
+		for scope := range grantable {
+			scopes = append(scopes, scope)
+		}
+	} else {
+		for _, scope := range requested {
+			if !grantable[scope] {
+				return nil, oauthError(http.StatusBadRequest, "invalid_scope", "scope %q is not granted by the capabilities of %s", scope, p.AgentID)
+			}
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes, nil
+}
+
+// Introspect returns the live token accessToken, or nil.
+func (s *Server) Introspect(accessToken string) *Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[accessToken]
+	if !ok || !s.cfg.Now().Before(t.ExpiresAt) {
+		return nil
+	}
+	return t
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, oauthError(http.StatusBadRequest, "invalid_request", "%v", err))
+		return
+	}
+	req, err := parseRequest(r.PostForm)
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+	t, err := s.Exchange(r.Context(), req)
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+	// RFC 6749 section 5.1: token responses are not to be cached.
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, &TokenResponse{
+		AccessToken:     t.AccessToken,
+		IssuedTokenType: TokenTypeAccessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(t.ExpiresAt.Sub(t.IssuedAt) / time.Second),
+		Scope:           strings.Join(t.Scopes, " "),
+	})
+}
+
+func parseRequest(form map[string][]string) (*Request, error) {
+	get := func(k string) string {
+		if v := form[k]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	if gt := get("grant_type"); gt != GrantTypeTokenExchange {
+		return nil, oauthError(http.StatusBadRequest, "unsupported_grant_type", "grant_type %q", gt)
+	}
+	if tt := get("subject_token_type"); tt != TokenTypeAgentPassport {
+		return nil, oauthError(http.StatusBadRequest, "invalid_request", "subject_token_type must be %s", TokenTypeAgentPassport)
+	}
+	if rt := get("requested_token_type"); rt != "" && rt != TokenTypeAccessToken {
+		return nil, oauthError(http.StatusBadRequest, "invalid_request", "only %s tokens are issued", TokenTypeAccessToken)
+	}
+	p, err := agentauth.DecodePassport(get("subject_token"))
+	if err != nil {
+		return nil, oauthError(http.StatusBadRequest, "invalid_request", "subject_token: %v", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(get(IntentParam))
+	if err != nil {
+		return nil, oauthError(http.StatusBadRequest, "invalid_request", "%s is not base64", IntentParam)
+	}
+	var intent dcp.Intent
+	if err := json.Unmarshal(data, &intent); err != nil {
+		return nil, oauthError(http.StatusBadRequest, "invalid_request", "%s: %v", IntentParam, err)
+	}
+	ref, err := dcpheader.ParseIntent(get(IntentRefParam), p.AgentID)
+	if err != nil {
+		return nil, oauthError(http.StatusBadRequest, "invalid_request", "%s: %v", IntentRefParam, err)
+	}
+	return &Request{Passport: p, Intent: &intent, IntentRef: ref, Scopes: strings.Fields(get("scope"))}, nil
+}
+
+func (s *Server) handleIntrospect(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.IntrospectionToken != "" {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(s.cfg.IntrospectionToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeOAuthError(w, oauthError(http.StatusUnauthorized, "invalid_client", "introspection requires the resource server token"))
+			return
+		}
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, oauthError(http.StatusBadRequest, "invalid_request", "%v", err))
+		return
+	}
+	t := s.Introspect(r.PostForm.Get("token"))
+	if t == nil {
+		writeJSON(w, http.StatusOK, &Introspection{})
+		return
+	}
+	risk := t.RiskScore
+	writeJSON(w, http.StatusOK, &Introspection{
+		Active:    true,
+		Scope:     strings.Join(t.Scopes, " "),
+		TokenType: "Bearer",
+		Subject:   t.AgentID,
+		IssuedAt:  t.IssuedAt.Unix(),
+		ExpiresAt: t.ExpiresAt.Unix(),
+		HumanID:   t.HumanID,
+		IntentID:  t.IntentID,
+		RiskScore: &risk,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeOAuthError answers in the RFC 6749 error form OAuth clients expect,
+// rather than the {"error": ...} form of the other DCP services.
+func writeOAuthError(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = oauthError(http.StatusInternalServerError, "server_error", "%v", err)
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, e.Status, e)
+}
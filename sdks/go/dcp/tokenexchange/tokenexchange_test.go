@@ -0,0 +1,166 @@
+package tokenexchange_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/agentauth"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/tokenexchange"
+)
+
+var _ tokenexchange.Decider = (*pdp.Server)(nil)
+
+// agent returns a Client for a fresh self-signed agent, and an email
+// intent of the agent.
+func agent(t *testing.T) (*tokenexchange.Client, *dcp.Intent) {
+	t.Helper()
+	p, signer, _ := dcptest.Agent(t, dcp.RiskTierLow, "email", "calendar")
+	return &tokenexchange.Client{Passport: p, Signer: signer}, dcptest.Intent(t, p)
+}
+
+// newServer returns a token exchange approving email intents only, behind
+// an HTTP server.
+func newServer(t *testing.T, cfg tokenexchange.Config) (*tokenexchange.Server, *httptest.Server) {
+	t.Helper()
+	pdpSigner, pdpKey := dcptest.NewSigner(t)
+	policy := &pdp.PolicySet{Default: dcp.DecisionBlock, Rules: []pdp.Rule{{Name: "email", Channels: []dcp.Channel{dcp.ChannelEmail}, Decision: dcp.DecisionApprove}}}
+	decider, err := pdp.New(pdp.Config{Policy: policy, Signer: pdpSigner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Authenticator = dcptest.Authenticator(t, agentauth.Config{})
+	cfg.Decider = decider
+	cfg.DecisionKey = pdpKey
+	s, err := tokenexchange.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func introspect(t *testing.T, ts *httptest.Server, bearer, token string) (int, map[string]interface{}) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/introspect", strings.NewReader(url.Values{"token": {token}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&got)
+	return resp.StatusCode, got
+}
+
+func TestExchange(t *testing.T) {
+	client, intent := agent(t)
+	_, ts := newServer(t, tokenexchange.Config{
+		Scopes:             map[string][]string{"email": {"mail.read", "mail.send"}},
+		IntrospectionToken: "rs-secret",
+	})
+	client.URL = ts.URL + "/token"
+	ctx := context.Background()
+
+	tok, err := client.Exchange(ctx, intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.TokenType != "Bearer" || tok.IssuedTokenType != tokenexchange.TokenTypeAccessToken || tok.ExpiresIn != 300 || tok.Scope != "calendar mail.read mail.send" {
+		t.Fatalf("token %+v", tok)
+	}
+	code, got := introspect(t, ts, "rs-secret", tok.AccessToken)
+	if code != http.StatusOK || got["active"] != true || got["sub"] != client.Passport.AgentID ||
+		got["dcp_intent_id"] != intent.IntentID || got["dcp_human_id"] != client.Passport.PrincipalBindingReference {
+		t.Fatalf("introspection: %d %v", code, got)
+	}
+	if code, _ := introspect(t, ts, "", tok.AccessToken); code != http.StatusUnauthorized {
+		t.Fatalf("introspection without the resource server token: %d", code)
+	}
+	if _, got := introspect(t, ts, "rs-secret", "not-a-token"); got["active"] != false || len(got) != 1 {
+		t.Fatalf("unknown token: %v", got)
+	}
+
+	tok, err = client.Exchange(ctx, intent, "mail.send")
+	if err != nil || tok.Scope != "mail.send" {
+		t.Fatalf("narrowed: %+v, %v", tok, err)
+	}
+	var e *tokenexchange.Error
+	if _, err := client.Exchange(ctx, intent, "payments"); !errors.As(err, &e) || e.Code != "invalid_scope" {
+		t.Fatalf("scope not granted: %v", err)
+	}
+}
+
+func TestRefused(t *testing.T) {
+	client, intent := agent(t)
+	other, _ := agent(t)
+	now := time.Now()
+	_, ts := newServer(t, tokenexchange.Config{Now: func() time.Time { return now }})
+	client.URL = ts.URL + "/token"
+	ctx := context.Background()
+
+	refused := func(name string, c *tokenexchange.Client, intent *dcp.Intent, code string) {
+		t.Helper()
+		var e *tokenexchange.Error
+		if _, err := c.Exchange(ctx, intent); !errors.As(err, &e) || e.Code != code || e.Status != http.StatusBadRequest {
+			t.Errorf("%s: got %v, want %s", name, err, code)
+		}
+	}
+
+	calendar := *intent
+	calendar.Target = dcp.IntentTarget{Channel: dcp.ChannelCalendar}
+	refused("blocked by policy", client, &calendar, "invalid_grant")
+
+	stolen := *client
+	stolen.Signer = other.Signer
+	refused("passport with another key", &stolen, intent, "invalid_grant")
+
+	stale := *client
+	stale.Now = func() time.Time { return now.Add(-time.Hour) }
+	refused("stale declaration", &stale, intent, "invalid_grant")
+
+	resp, err := http.PostForm(ts.URL+"/token", url.Values{"grant_type": {"client_credentials"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body map[string]string
+	json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusBadRequest || body["error"] != "unsupported_grant_type" {
+		t.Fatalf("other grant type: %d %v", resp.StatusCode, body)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	client, intent := agent(t)
+	now := time.Now()
+	s, ts := newServer(t, tokenexchange.Config{TTL: time.Minute, Now: func() time.Time { return now }})
+	client.URL = ts.URL + "/token"
+	client.Now = func() time.Time { return now }
+
+	tok, err := client.Exchange(context.Background(), intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Introspect(tok.AccessToken) == nil {
+		t.Fatal("fresh token is not live")
+	}
+	now = now.Add(2 * time.Minute)
+	if s.Introspect(tok.AccessToken) != nil {
+		t.Fatal("expired token is live")
+	}
+}
@@ -0,0 +1,61 @@
+package toolcall
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// AnthropicTool is a tool definition of the Anthropic Messages API.
+type AnthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// AnthropicContentBlock is a content block of an assistant message; only
+// tool_use blocks are run.
+type AnthropicContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text,omitempty"`
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+// AnthropicToolResult is the tool_result block answering a tool_use block.
+type AnthropicToolResult struct {
+	Type      string `json:"type"`
+	ToolUseID string `json:"tool_use_id"`
+	Content   string `json:"content"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+// AnthropicTools returns the definitions of r's tools, for the tools of a
+// messages request.
+func (r *Runner) AnthropicTools() []AnthropicTool {
+	defs := make([]AnthropicTool, len(r.tools))
+	for i, t := range r.tools {
+		defs[i] = AnthropicTool{Name: t.Name, Description: t.Description, InputSchema: t.Parameters}
+	}
+	return defs
+}
+
+// RunAnthropic runs the tool_use blocks of an assistant message's content
+// in order and returns the tool_result blocks answering them, the content
+// of the next user message. Refused and failed calls are answered flagged
+// is_error, with the reason. It fails only if a call could not be decided
+// or audited, when the conversation should not go on.
+func (r *Runner) RunAnthropic(ctx context.Context, content []AnthropicContentBlock) ([]AnthropicToolResult, error) {
+	var results []AnthropicToolResult
+	for _, b := range content {
+		if b.Type != "tool_use" {
+			continue
+		}
+		res := r.call(ctx, b.Name, b.Input)
+		if res.err != nil {
+			return nil, res.err
+		}
+		results = append(results, AnthropicToolResult{Type: "tool_result", ToolUseID: b.ID, Content: res.content, IsError: res.isError})
+	}
+	return results, nil
+}
@@ -0,0 +1,69 @@
+package toolcall_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/toolcall"
+)
+
+type failingDecider struct{}
+
+func (failingDecider) Decide(ctx context.Context, intent *dcp.Intent) (*pdp.SignedDecision, error) {
+	return nil, errors.New("PDP unreachable")
+}
+
+func TestAnthropicTools(t *testing.T) {
+	r, _, _ := newRunner(t)
+	defs := r.AnthropicTools()
+	if len(defs) != 3 || defs[0].Name != "fetch_page" || defs[0].Description != "Fetch a web page" || !strings.Contains(string(defs[1].InputSchema), `"to"`) {
+		t.Fatalf("%+v", defs)
+	}
+}
+
+func TestRunAnthropic(t *testing.T) {
+	r, g, _ := newRunner(t)
+	var content []toolcall.AnthropicContentBlock
+	if err := json.Unmarshal([]byte(`[
+		{"type":"text","text":"Let me check."},
+		{"type":"tool_use","id":"toolu_1","name":"fetch_page","input":{"url":"https://example.com/missing"}},
+		{"type":"tool_use","id":"toolu_2","name":"send_email","input":{"to":"alice@example.com","body":"hi"}},
+		{"type":"tool_use","id":"toolu_3","name":"fetch_page","input":{"url":"https://example.com"}}
+	]`), &content); err != nil {
+		t.Fatal(err)
+	}
+	results, err := r.RunAnthropic(context.Background(), content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("%+v", results)
+	}
+	for i, w := range []struct {
+		id      string
+		text    string
+		isError bool
+	}{
+		{"toolu_1", "404 Not Found", true},
+		{"toolu_2", "emails need a human", true},
+		{"toolu_3", "<html>https://example.com</html>", false},
+	} {
+		res := results[i]
+		if res.Type != "tool_result" || res.ToolUseID != w.id || !strings.Contains(res.Content, w.text) || res.IsError != w.isError {
+			t.Errorf("result %d: %+v", i, res)
+		}
+	}
+	if g.Chain.Len() != 3 {
+		t.Fatalf("%d entries", g.Chain.Len())
+	}
+
+	g.Decider = failingDecider{}
+	if _, err := r.RunAnthropic(context.Background(), content); err == nil {
+		t.Fatal("undecided calls ran")
+	}
+}
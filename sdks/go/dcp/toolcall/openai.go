@@ -0,0 +1,64 @@
+package toolcall
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// OpenAITool is a tool definition of the OpenAI Chat Completions API.
+type OpenAITool struct {
+	Type     string         `json:"type"`
+	Function OpenAIFunction `json:"function"`
+}
+
+// OpenAIFunction is the function of an OpenAITool.
+type OpenAIFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// OpenAIToolCall is a tool call of an assistant message.
+type OpenAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+		// Arguments is the JSON object of the arguments, as a string.
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// OpenAIToolMessage is the "tool" message answering a tool call.
+type OpenAIToolMessage struct {
+	Role       string `json:"role"`
+	ToolCallID string `json:"tool_call_id"`
+	Content    string `json:"content"`
+}
+
+// OpenAITools returns the definitions of r's tools, for the tools of a
+// chat completion request.
+func (r *Runner) OpenAITools() []OpenAITool {
+	defs := make([]OpenAITool, len(r.tools))
+	for i, t := range r.tools {
+		defs[i] = OpenAITool{Type: "function", Function: OpenAIFunction{Name: t.Name, Description: t.Description, Parameters: t.Parameters}}
+	}
+	return defs
+}
+
+// RunOpenAI runs the tool calls of an assistant message in order and
+// returns the tool messages answering them. Refused and failed calls are
+// answered with the reason, which the Chat Completions API has no error
+// flag for. It fails only if a call could not be decided or audited, when
+// the conversation should not go on.
+func (r *Runner) RunOpenAI(ctx context.Context, calls []OpenAIToolCall) ([]OpenAIToolMessage, error) {
+	msgs := make([]OpenAIToolMessage, 0, len(calls))
+	for _, c := range calls {
+		res := r.call(ctx, c.Function.Name, json.RawMessage(c.Function.Arguments))
+		if res.err != nil {
+			return nil, res.err
+		}
+		msgs = append(msgs, OpenAIToolMessage{Role: "tool", ToolCallID: c.ID, Content: res.content})
+	}
+	return msgs, nil
+}
@@ -0,0 +1,56 @@
+package toolcall_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/toolcall"
+)
+
+func TestOpenAITools(t *testing.T) {
+	r, _, _ := newRunner(t)
+	data, err := json.Marshal(r.OpenAITools())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var defs []struct {
+		Type     string
+		Function struct {
+			Name       string
+			Parameters struct{ Type string }
+		}
+	}
+	if err := json.Unmarshal(data, &defs); err != nil || len(defs) != 3 || defs[0].Type != "function" ||
+		defs[0].Function.Name != "fetch_page" || defs[2].Function.Parameters.Type != "object" {
+		t.Fatalf("%s", data)
+	}
+}
+
+func TestRunOpenAI(t *testing.T) {
+	r, g, _ := newRunner(t)
+	var calls []toolcall.OpenAIToolCall
+	if err := json.Unmarshal([]byte(`[
+		{"id":"call_1","type":"function","function":{"name":"fetch_page","arguments":"{\"url\":\"https://example.com\"}"}},
+		{"id":"call_2","type":"function","function":{"name":"pay_invoice","arguments":"{\"invoiceId\":\"42\"}"}}
+	]`), &calls); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := r.RunOpenAI(context.Background(), calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || msgs[0].Role != "tool" || msgs[0].ToolCallID != "call_1" || msgs[0].Content != "<html>https://example.com</html>" ||
+		msgs[1].ToolCallID != "call_2" || !strings.Contains(msgs[1].Content, "refused by policy") {
+		t.Fatalf("%+v", msgs)
+	}
+	if g.Chain.Len() != 2 {
+		t.Fatalf("%d entries", g.Chain.Len())
+	}
+
+	g.Decider = failingDecider{}
+	if _, err := r.RunOpenAI(context.Background(), calls); err == nil || g.Chain.Len() != 2 {
+		t.Fatalf("undecided calls: %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+// Package toolcall enforces DCP policy on the function calls of OpenAI and
+// Anthropic models. A Runner holds the tools offered to the model; each call
+// the model makes declares an Intent, derived from the tool's schema and
+// the call's arguments, runs only if the policy decision approves it, and is
+// recorded on an AuditChain:
+//
+//	runner, _ := toolcall.NewRunner(guard, toolcall.Tool{Name: "send_email", Parameters: schema, Run: send})
+//	req.Tools = runner.OpenAITools()
+//	...
+//	replies, err := runner.RunOpenAI(ctx, resp.Choices[0].Message.ToolCalls)
+//
+// A refused call never reaches the tool: the model receives the decision as
+// the call's error result, and may explain it or try something else.
+package toolcall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/mcp"
+)
+
+// Tool is a function offered to the model.
+type Tool struct {
+	Name        string
+	Description string
+	// Parameters is the JSON Schema of the arguments, an object schema;
+	// nil means the tool takes none.
+	Parameters json.RawMessage
+	// Intent describes the intents of the tool's calls; nil means the one
+	// Describe derives from Name and Parameters.
+	Intent *mcp.Tool
+	// Run executes a call with its arguments, a JSON object, and returns
+	// the result given to the model. Required.
+	Run func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// noParameters is the schema of the arguments of a tool taking none.
+var noParameters = json.RawMessage(`{"type":"object","properties":{}}`)
+
+// Runner runs the calls of its tools under the policy and audit of a Guard.
+// Create one with NewRunner.
+type Runner struct {
+	guard *mcp.Guard
+	tools []Tool
+	index map[string]int
+}
+
+// NewRunner returns a Runner of tools guarded by g, which must have a
+// passport, a decider and an audit chain. Its Tools are not used: each
+// tool's intent is described by the tool.
+func NewRunner(g *mcp.Guard, tools ...Tool) (*Runner, error) {
+	if g == nil || g.Passport == nil || g.Decider == nil || g.Chain == nil {
+		return nil, errors.New("toolcall: a guard with a passport, decider and audit chain is required")
+	}
+	r := &Runner{guard: g, index: map[string]int{}}
+	for _, t := range tools {
+		if t.Name == "" || t.Run == nil {
+			return nil, errors.New("toolcall: a tool needs a name and a Run function")
+		}
+		if _, dup := r.index[t.Name]; dup {
+			return nil, fmt.Errorf("toolcall: tool %s defined twice", t.Name)
+		}
+		if len(t.Parameters) == 0 {
+			t.Parameters = noParameters
+		}
+		if t.Intent == nil {
+			described := Describe(t.Name, t.Parameters)
+			t.Intent = &described
+		}
+		r.index[t.Name] = len(r.tools)
+		r.tools = append(r.tools, t)
+	}
+	return r, nil
+}
+
+// Call runs a call of the tool name with args, a JSON object, returning the
+// tool's result. A call the policy refuses fails with a *mcp.RefusedError.
+func (r *Runner) Call(ctx context.Context, name string, args json.RawMessage) (string, error) {
+	res := r.call(ctx, name, args)
+	if res.err != nil {
+		return "", res.err
+	}
+	if res.isError {
+		return "", errors.New(res.content)
+	}
+	return res.content, nil
+}
+
+// result is the outcome of a call as the model sees it: content flagged
+// isError, unless err reports the call could not be decided or audited.
+type result struct {
+	content string
+	isError bool
+	err     error
+}
+
+func (r *Runner) call(ctx context.Context, name string, args json.RawMessage) result {
+	i, ok := r.index[name]
+	if !ok {
+		return result{content: fmt.Sprintf("unknown tool %q", name), isError: true}
+	}
+	t := r.tools[i]
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(args, &fields); err != nil {
+		return result{content: fmt.Sprintf("tool %s: arguments are not a JSON object: %v", name, err), isError: true}
+	}
+
+	intent := t.Intent.Intent(r.guard.Passport)
+	target(&intent.Target, fields)
+	var execErr error
+	out, err := r.guard.Run(ctx, name, intent, func(ctx context.Context) (interface{}, error) {
+		out, err := t.Run(ctx, args)
+		execErr = err
+		return out, err
+	})
+	var refused *mcp.RefusedError
+	switch {
+	case err == nil:
+		return result{content: out.(string)}
+	case err == execErr:
+		return result{content: fmt.Sprintf("tool %s failed: %v", name, err), isError: true}
+	case errors.As(err, &refused):
+		return result{content: "refused by policy: " + refused.Error(), isError: true}
+	default:
+		return result{err: fmt.Errorf("toolcall: %v", err)}
+	}
+}
+
+// target completes the target of a call's intent with its arguments: a URL
+// argument gives the URL and, if not set, the domain; a recipient the to;
+// a domain or host the domain.
+func target(t *dcp.IntentTarget, args map[string]interface{}) {
+	if s := stringArg(args, "url", "uri", "link", "href"); s != "" {
+		t.URL = &s
+		if u, err := url.Parse(s); err == nil && u.Hostname() != "" && t.Domain == nil {
+			host := u.Hostname()
+			t.Domain = &host
+		}
+	}
+	if s := stringArg(args, "to", "recipient", "email", "email_address"); s != "" {
+		t.To = &s
+	}
+	if s := stringArg(args, "domain", "host", "hostname"); s != "" && t.Domain == nil {
+		t.Domain = &s
+	}
+}
+
+// stringArg returns the first non-empty string argument of keys.
+func stringArg(args map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := args[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// rules map words of tool names and parameters to the intents of their
+// calls; the first rule with a matching word applies.
+var rules = []struct {
+	words []string
+	tool  mcp.Tool
+}{
+	{[]string{"pay", "payment", "payments", "transfer", "charge", "refund", "amount", "iban", "invoice"},
+		mcp.Tool{ActionType: "initiate_payment", Channel: dcp.ChannelPayments, DataClasses: []string{"financial_data"}, Impact: dcp.ImpactHigh}},
+	{[]string{"exec", "execute", "shell", "command", "code", "script", "eval", "bash", "python"},
+		mcp.Tool{ActionType: "execute_code", Channel: dcp.ChannelRuntime, Impact: dcp.ImpactHigh}},
+	{[]string{"email", "mail", "recipient", "recipients", "subject", "cc", "bcc"},
+		mcp.Tool{ActionType: "send_email", Channel: dcp.ChannelEmail, DataClasses: []string{"contact_info"}, Impact: dcp.ImpactMedium}},
+	{[]string{"calendar", "event", "meeting", "schedule", "attendees", "invitees"},
+		mcp.Tool{ActionType: "create_calendar_event", Channel: dcp.ChannelCalendar, DataClasses: []string{"contact_info"}, Impact: dcp.ImpactMedium}},
+	{[]string{"crm", "lead", "customer", "deal", "opportunity"},
+		mcp.Tool{ActionType: "update_crm", Channel: dcp.ChannelCRM, DataClasses: []string{"contact_info"}, Impact: dcp.ImpactMedium}},
+	{[]string{"file", "filename", "path", "write", "save", "delete", "mkdir"},
+		mcp.Tool{ActionType: "write_file", Channel: dcp.ChannelFilesystem, Impact: dcp.ImpactMedium}},
+	{[]string{"browse", "url", "uri", "fetch", "navigate", "webpage", "scrape"},
+		mcp.Tool{ActionType: "browse", Channel: dcp.ChannelWeb, Impact: dcp.ImpactLow}},
+}
+
+// Describe derives the intents of a tool's calls from its name and the
+// properties of its parameters schema: a "send_email" tool, or one taking
+// a "recipient", sends email; one taking an "amount" initiates a payment.
+// Tools matching no rule are described by mcp.DefaultTool. Set Tool.Intent
+// where the guess is wrong.
+func Describe(name string, parameters json.RawMessage) mcp.Tool {
+	words := map[string]bool{}
+	for _, w := range split(name) {
+		words[w] = true
+	}
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if json.Unmarshal(parameters, &schema) == nil {
+		for prop := range schema.Properties {
+			for _, w := range split(prop) {
+				words[w] = true
+			}
+		}
+	}
+	for _, rule := range rules {
+		for _, w := range rule.words {
+			if words[w] {
+				return rule.tool
+			}
+		}
+	}
+	return mcp.DefaultTool
+}
+
+// split returns the lowercased words of a snake_case, kebab-case or
+// camelCase identifier.
+func split(s string) []string {
+	var words []string
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			words = append(words, b.String())
+			b.Reset()
+		}
+	}
+	prev := rune(0)
+	for _, c := range s {
+		switch {
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			flush()
+		case unicode.IsUpper(c):
+			if unicode.IsLower(prev) {
+				flush()
+			}
+			b.WriteRune(unicode.ToLower(c))
+		default:
+			b.WriteRune(c)
+		}
+		prev = c
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,187 @@
+package toolcall_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/dcptest"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/mcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/pdp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/toolcall"
+)
+
+// recorder is a Decider noting the intents it decides.
+type recorder struct {
+	mcp.Decider
+	mu      sync.Mutex
+	intents []*dcp.Intent
+}
+
+func (r *recorder) Decide(ctx context.Context, intent *dcp.Intent) (*pdp.SignedDecision, error) {
+	r.mu.Lock()
+	r.intents = append(r.intents, intent)
+	r.mu.Unlock()
+	return r.Decider.Decide(ctx, intent)
+}
+
+// newRunner returns a Runner of a fresh agent's browse, send_email and
+// pay_invoice tools, deciding with a PDP that approves web requests,
+// escalates emails and blocks payments.
+func newRunner(t *testing.T) (*toolcall.Runner, *mcp.Guard, *recorder) {
+	t.Helper()
+	agentSigner, agentKey := dcptest.NewSigner(t)
+	p := dcp.NewAgentPassport(dcp.NewHumanID(), agentKey, []string{"browse", "email", "payments"}, dcp.RiskTierLow)
+	if err := p.Sign(agentSigner); err != nil {
+		t.Fatal(err)
+	}
+	pdpSigner, pdpKey := dcptest.NewSigner(t)
+	decider, err := pdp.New(pdp.Config{Signer: pdpSigner, Policy: &pdp.PolicySet{
+		Default: dcp.DecisionBlock,
+		Rules: []pdp.Rule{
+			{Name: "web", Channels: []dcp.Channel{dcp.ChannelWeb}, Decision: dcp.DecisionApprove},
+			{Name: "email", Channels: []dcp.Channel{dcp.ChannelEmail}, Decision: dcp.DecisionEscalate, Reason: "emails need a human"},
+			{Name: "payments", Channels: []dcp.Channel{dcp.ChannelPayments}, Decision: dcp.DecisionBlock, Reason: "no payments"},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := &recorder{Decider: decider}
+	g := &mcp.Guard{
+		Passport:    &p,
+		Decider:     rec,
+		DecisionKey: pdpKey,
+		Chain:       dcp.NewAuditChain(dcp.AuditChainOptions{AgentSigner: agentSigner}),
+	}
+	r, err := toolcall.NewRunner(g,
+		toolcall.Tool{
+			Name:        "fetch_page",
+			Description: "Fetch a web page",
+			Parameters:  json.RawMessage(`{"type":"object","properties":{"url":{"type":"string"}},"required":["url"]}`),
+			Run: func(ctx context.Context, args json.RawMessage) (string, error) {
+				var a struct{ URL string }
+				json.Unmarshal(args, &a)
+				if strings.Contains(a.URL, "missing") {
+					return "", errors.New("404 Not Found")
+				}
+				return "<html>" + a.URL + "</html>", nil
+			},
+		},
+		toolcall.Tool{
+			Name:       "send_email",
+			Parameters: json.RawMessage(`{"type":"object","properties":{"to":{"type":"string"},"body":{"type":"string"}}}`),
+			Run: func(ctx context.Context, args json.RawMessage) (string, error) {
+				t.Error("send_email ran")
+				return "", nil
+			},
+		},
+		toolcall.Tool{
+			Name:       "pay_invoice",
+			Parameters: json.RawMessage(`{"type":"object","properties":{"invoiceId":{"type":"string"}}}`),
+			Run: func(ctx context.Context, args json.RawMessage) (string, error) {
+				t.Error("pay_invoice ran")
+				return "", nil
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, g, rec
+}
+
+func TestDescribe(t *testing.T) {
+	for _, c := range []struct {
+		name, parameters string
+		actionType       string
+		channel          dcp.Channel
+	}{
+		{"sendEmail", `{}`, "send_email", dcp.ChannelEmail},
+		{"notify", `{"properties":{"recipient":{},"text":{}}}`, "send_email", dcp.ChannelEmail},
+		{"transfer_funds", `{}`, "initiate_payment", dcp.ChannelPayments},
+		{"refund", `{"properties":{"Amount":{}}}`, "initiate_payment", dcp.ChannelPayments},
+		{"run_python", `{"properties":{"code":{}}}`, "execute_code", dcp.ChannelRuntime},
+		{"book", `{"properties":{"meeting_time":{},"attendees":{}}}`, "create_calendar_event", dcp.ChannelCalendar},
+		{"update-lead", `{}`, "update_crm", dcp.ChannelCRM},
+		{"save_note", `{"properties":{"filePath":{}}}`, "write_file", dcp.ChannelFilesystem},
+		{"get", `{"properties":{"url":{}}}`, "browse", dcp.ChannelWeb},
+		{"get_weather", `{"properties":{"city":{}}}`, "api_call", dcp.ChannelAPI},
+		{"lookup", ``, "api_call", dcp.ChannelAPI},
+	} {
+		tool := toolcall.Describe(c.name, json.RawMessage(c.parameters))
+		if tool.ActionType != c.actionType || tool.Channel != c.channel {
+			t.Errorf("%s %s: %+v", c.name, c.parameters, tool)
+		}
+	}
+}
+
+func TestCall(t *testing.T) {
+	ctx := context.Background()
+	r, g, rec := newRunner(t)
+
+	out, err := r.Call(ctx, "fetch_page", json.RawMessage(`{"url":"https://example.com/a"}`))
+	if err != nil || out != "<html>https://example.com/a</html>" {
+		t.Fatalf("%q, %v", out, err)
+	}
+	intent := rec.intents[0]
+	if intent.ActionType != "browse" || *intent.Target.URL != "https://example.com/a" || *intent.Target.Domain != "example.com" {
+		t.Fatalf("intent %+v", intent.Target)
+	}
+
+	if _, err := r.Call(ctx, "fetch_page", json.RawMessage(`{"url":"https://example.com/missing"}`)); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("tool error: %v", err)
+	}
+	if _, err := r.Call(ctx, "send_email", json.RawMessage(`{"to":"alice@example.com"}`)); err == nil || !strings.Contains(err.Error(), "emails need a human") {
+		t.Fatalf("escalated: %v", err)
+	}
+	if to := rec.intents[2].Target.To; to == nil || *to != "alice@example.com" {
+		t.Fatalf("email target %+v", rec.intents[2].Target)
+	}
+	if _, err := r.Call(ctx, "pay_invoice", nil); err == nil || !strings.Contains(err.Error(), "no payments") {
+		t.Fatalf("blocked: %v", err)
+	}
+	if _, err := r.Call(ctx, "delete_everything", nil); err == nil {
+		t.Fatal("unknown tool ran")
+	}
+	if _, err := r.Call(ctx, "fetch_page", json.RawMessage(`["x"]`)); err == nil {
+		t.Fatal("arguments that are not an object were accepted")
+	}
+
+	entries := g.Chain.Entries()
+	want := []struct {
+		decision dcp.Outcome
+		outcome  string
+	}{
+		{dcp.OutcomeApproved, mcp.OutcomeSucceeded},
+		{dcp.OutcomeApproved, mcp.OutcomeFailed},
+		{dcp.OutcomeEscalated, mcp.OutcomeRefused},
+		{dcp.OutcomeBlocked, mcp.OutcomeRefused},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("%d entries", len(entries))
+	}
+	for i, w := range want {
+		if e := entries[i]; e.PolicyDecision != w.decision || e.Outcome != w.outcome || e.IntentID != rec.intents[i].IntentID {
+			t.Errorf("entry %d: %+v", i, e)
+		}
+	}
+}
+
+func TestNewRunner(t *testing.T) {
+	_, g, _ := newRunner(t)
+	run := func(ctx context.Context, args json.RawMessage) (string, error) { return "", nil }
+	if _, err := toolcall.NewRunner(&mcp.Guard{}, toolcall.Tool{Name: "a", Run: run}); err == nil {
+		t.Fatal("guard without a passport accepted")
+	}
+	if _, err := toolcall.NewRunner(g, toolcall.Tool{Name: "a"}); err == nil {
+		t.Fatal("tool without Run accepted")
+	}
+	if _, err := toolcall.NewRunner(g, toolcall.Tool{Name: "a", Run: run}, toolcall.Tool{Name: "a", Run: run}); err == nil {
+		t.Fatal("duplicate tool accepted")
+	}
+}
@@ -6,16 +6,60 @@ type ResponsiblePrincipalRecord struct {
 	DCPVersion     string  `json:"dcp_version"`
 	HumanID        string  `json:"human_id"`
 	LegalName      string  `json:"legal_name"`
-	EntityType     string  `json:"entity_type"`
+	EntityType     EntityType  `json:"entity_type"`
 	Jurisdiction   string  `json:"jurisdiction"`
-	LiabilityMode  string  `json:"liability_mode"`
+	LiabilityMode  LiabilityMode  `json:"liability_mode"`
 	OverrideRights bool    `json:"override_rights"`
 	IssuedAt       string  `json:"issued_at"`
 	ExpiresAt      *string `json:"expires_at"`
 	Contact        *string `json:"contact,omitempty"`
+	// EntityEvidence identifies the principal as its entity type
+	// requires; see EntityType.
+	EntityEvidence *EntityEvidence `json:"entity_evidence,omitempty"`
+	// SignerSet is set when the binding is held jointly, e.g. by a board:
+	// bundles must then carry a quorum of its members' signatures.
+	SignerSet      *PrincipalSignerSet `json:"signer_set,omitempty"`
+	// Guardian is set when the principal is a minor or other dependent:
+	// its agents' passports must then be countersigned by the guardian.
+	Guardian       *GuardianBinding `json:"guardian,omitempty"`
 	Signature      string  `json:"signature"`
 }
 
+// EntityEvidence identifies a principal: a corporation by its registration
+// number and the jurisdiction of the register, an individual by a hash of
+// its date of birth or a reference to an ID document, and a nonprofit by
+// its reference in a nonprofit registry.
+type EntityEvidence struct {
+	RegistrationNumber       string `json:"registration_number,omitempty"`
+	RegistrationJurisdiction string `json:"registration_jurisdiction,omitempty"`
+	DateOfBirthHash          string `json:"date_of_birth_hash,omitempty"`
+	IDReference              string `json:"id_reference,omitempty"`
+	RegistryRef              string `json:"registry_ref,omitempty"`
+}
+
+// GuardianBinding names the guardian of a dependent principal, the key
+// the guardian countersigns with, their relationship and its legal basis,
+// such as a court order.
+type GuardianBinding struct {
+	GuardianID        string               `json:"guardian_id"`
+	GuardianPublicKey string               `json:"guardian_public_key"`
+	Relationship      GuardianRelationship `json:"relationship"`
+	LegalBasis        string               `json:"legal_basis"`
+}
+
+// PrincipalSignerSet lists the humans holding a group binding, of whom
+// Threshold must sign.
+type PrincipalSignerSet struct {
+	Threshold int               `json:"threshold"`
+	Signers   []PrincipalMember `json:"signers"`
+}
+
+// PrincipalMember is one human of a PrincipalSignerSet and their key.
+type PrincipalMember struct {
+	HumanID   string `json:"human_id"`
+	PublicKey string `json:"public_key"`
+}
+
 // AgentPassport represents DCP-01 Agent Passport.
 type AgentPassport struct {
 	DCPVersion            string   `json:"dcp_version"`
@@ -23,15 +67,19 @@ type AgentPassport struct {
 	PublicKey             string   `json:"public_key"`
 	PrincipalBindingReference string   `json:"principal_binding_reference"`
 	Capabilities          []string `json:"capabilities,omitempty"`
-	RiskTier              string   `json:"risk_tier,omitempty"`
+	CapabilityDomains     map[string]DomainList `json:"capability_domains,omitempty"`
+	RiskTier              RiskTier   `json:"risk_tier,omitempty"`
 	CreatedAt             string   `json:"created_at"`
-	Status                string   `json:"status"`
+	Status                Status   `json:"status"`
 	Signature             string   `json:"signature"`
+	// GuardianSignature is the guardian's countersignature on the passport
+	// of an agent bound to a dependent principal; see GuardianBinding.
+	GuardianSignature     string   `json:"guardian_signature,omitempty"`
 }
 
 // IntentTarget represents the target of an intent action.
 type IntentTarget struct {
-	Channel string  `json:"channel"`
+	Channel Channel  `json:"channel"`
 	To      *string `json:"to,omitempty"`
 	Domain  *string `json:"domain,omitempty"`
 	URL     *string `json:"url,omitempty"`
@@ -47,23 +95,64 @@ type Intent struct {
 	ActionType      string       `json:"action_type"`
 	Target          IntentTarget `json:"target"`
 	DataClasses     []string     `json:"data_classes"`
-	EstimatedImpact string       `json:"estimated_impact"`
+	EstimatedImpact EstimatedImpact `json:"estimated_impact"`
 	RequiresConsent *bool        `json:"requires_consent,omitempty"`
+	Domains         *DomainList  `json:"domains,omitempty"`
+	// Supersedes is the intent_hash of the version this intent amends.
+	Supersedes      string       `json:"supersedes,omitempty"`
+	// ExpiresAt ends the intent: no action on it may be recorded later.
+	ExpiresAt       *string      `json:"expires_at,omitempty"`
+	// Nonce makes the intent unique, so a counterparty can refuse it when
+	// presented again; see ReplayGuard.
+	Nonce           string       `json:"nonce,omitempty"`
+	// IdempotencyKey is passed to the systems that carry out the action,
+	// such as payment and email providers, which deduplicate by it; see
+	// DeriveIdempotencyKey.
+	IdempotencyKey  string       `json:"idempotency_key,omitempty"`
+	Batch           *IntentBatch `json:"batch,omitempty"`
+}
+
+// IntentBatch declares that an intent covers Count homogeneous actions, one
+// per item of a manifest whose hash is ManifestHash; see
+// BatchManifestHash.
+type IntentBatch struct {
+	Count        int    `json:"count"`
+	ManifestHash string `json:"manifest_hash"`
+}
+
+// RequiredConfirmation describes the human confirmation a policy decision demands.
+type RequiredConfirmation struct {
+	Type   string   `json:"type"`
+	Fields []string `json:"fields,omitempty"`
 }
 
 // PolicyDecision represents DCP-02 Policy Decision.
 type PolicyDecision struct {
-	DCPVersion string   `json:"dcp_version"`
-	IntentID   string   `json:"intent_id"`
-	Decision   string   `json:"decision"`
-	RiskScore  float64  `json:"risk_score"`
-	Reasons    []string `json:"reasons"`
+	DCPVersion           string                `json:"dcp_version"`
+	IntentID             string                `json:"intent_id"`
+	Decision             Decision                `json:"decision"`
+	RiskScore            float64               `json:"risk_score"`
+	Reasons              []string              `json:"reasons"`
+	RequiredConfirmation *RequiredConfirmation `json:"required_confirmation,omitempty"`
+	// DecisionID and Signature are set by a PDP signing the decision; see
+	// PolicyDecision.Sign.
+	DecisionID string `json:"decision_id,omitempty"`
+	Signature  string `json:"signature,omitempty"`
 }
 
 // AuditEvidence represents evidence attached to an audit entry.
 type AuditEvidence struct {
 	Tool      *string `json:"tool"`
 	ResultRef *string `json:"result_ref"`
+	// BatchItem is set on an entry recording one action of a batch intent.
+	BatchItem *BatchItem `json:"batch_item,omitempty"`
+}
+
+// BatchItem names the action of a batch intent an audit entry records: the
+// item at Index of the manifest.
+type BatchItem struct {
+	Index int    `json:"index"`
+	Item  string `json:"item"`
 }
 
 // AuditEntry represents DCP-03 Audit Entry.
@@ -76,9 +165,10 @@ type AuditEntry struct {
 	HumanID        string        `json:"human_id"`
 	IntentID       string        `json:"intent_id"`
 	IntentHash     string        `json:"intent_hash"`
-	PolicyDecision string        `json:"policy_decision"`
+	PolicyDecision Outcome        `json:"policy_decision"`
 	Outcome        string        `json:"outcome"`
 	Evidence       AuditEvidence `json:"evidence"`
+	AgentSignature string        `json:"agent_signature,omitempty"`
 }
 
 // CitizenshipBundle represents a full DCP Citizenship Bundle.
@@ -86,8 +176,149 @@ type CitizenshipBundle struct {
 	ResponsiblePrincipalRecord ResponsiblePrincipalRecord `json:"responsible_principal_record"`
 	AgentPassport      AgentPassport      `json:"agent_passport"`
 	Intent             Intent             `json:"intent"`
+	IntentHistory      []Intent           `json:"intent_history,omitempty"`
 	PolicyDecision     PolicyDecision     `json:"policy_decision"`
 	AuditEntries       []AuditEntry       `json:"audit_entries"`
+	ChainAnchor        *ChainAnchor       `json:"chain_anchor,omitempty"`
+	Consent            *ConsentRecord     `json:"consent,omitempty"`
+	Overrides          []OverrideRecord   `json:"overrides,omitempty"`
+	BreakGlass         []BreakGlassRecord `json:"break_glass,omitempty"`
+	Cancellation       *IntentCancellation `json:"cancellation,omitempty"`
+	BatchManifest      []string           `json:"batch_manifest,omitempty"`
+	// DelegationChain is set when the agent is a sub-agent: it links the
+	// agent back to the agent its principal created, root first.
+	DelegationChain    []DelegationLink   `json:"delegation_chain,omitempty"`
+	// PowersOfAttorney is set when the bundle is signed by an attorney of
+	// the principal rather than the principal: it links the principal to
+	// the signer, the principal's grant first.
+	PowersOfAttorney   []PowerOfAttorney  `json:"powers_of_attorney,omitempty"`
+	// Insurance holds attestations of coverage for the principal and the
+	// agent, each checked against its attester's published key.
+	Insurance          []InsuranceAttestation `json:"insurance,omitempty"`
+}
+
+// ChainAnchor continues a bundle's audit chain from an earlier bundle: the
+// first entry's prev_hash is PrevEntryHash instead of "GENESIS".
+type ChainAnchor struct {
+	PrevEntryHash  string `json:"prev_entry_hash"`
+	PrevBundleHash string `json:"prev_bundle_hash,omitempty"`
+}
+
+// ConsentRecord is the responsible principal's signed consent to one
+// intent, identified by its intent_hash. Scope lists the data classes
+// consented to, and the consent holds from GrantedAt until ExpiresAt.
+type ConsentRecord struct {
+	DCPVersion string   `json:"dcp_version"`
+	HumanID    string   `json:"human_id"`
+	IntentHash string   `json:"intent_hash"`
+	Scope      []string `json:"scope"`
+	GrantedAt  string   `json:"granted_at"`
+	ExpiresAt  string   `json:"expires_at"`
+	Signature  string   `json:"signature"`
+}
+
+// OverrideRecord is the responsible principal's signed exercise of its
+// override rights: it halts or modifies the agent's action on an intent.
+// PrevHash is the audit chain head the override follows, and the audit
+// entry recording it references the record by hash.
+type OverrideRecord struct {
+	DCPVersion            string         `json:"dcp_version"`
+	HumanID               string         `json:"human_id"`
+	AgentID               string         `json:"agent_id"`
+	IntentID              string         `json:"intent_id"`
+	IntentHash            string         `json:"intent_hash"`
+	Action                OverrideAction `json:"action"`
+	ReplacementIntentHash string         `json:"replacement_intent_hash,omitempty"`
+	Reason                string         `json:"reason"`
+	Timestamp             string         `json:"timestamp"`
+	PrevHash              string         `json:"prev_hash"`
+	Signature             string         `json:"signature"`
+}
+
+// IntentCancellation is the agent's signed withdrawal of an intent it
+// declared: no audit entry after Timestamp may record the action as done.
+type IntentCancellation struct {
+	DCPVersion string `json:"dcp_version"`
+	IntentID   string `json:"intent_id"`
+	IntentHash string `json:"intent_hash"`
+	AgentID    string `json:"agent_id"`
+	Timestamp  string `json:"timestamp"`
+	Reason     string `json:"reason"`
+	Signature  string `json:"signature"`
+}
+
+// DelegationRecord is an agent's signed delegation of some of its
+// capabilities to a sub-agent, ChildAgentID with key ChildPublicKey, of
+// the same principal HumanID, between NotBefore and NotAfter.
+type DelegationRecord struct {
+	DCPVersion     string   `json:"dcp_version"`
+	ParentAgentID  string   `json:"parent_agent_id"`
+	ChildAgentID   string   `json:"child_agent_id"`
+	ChildPublicKey string   `json:"child_public_key"`
+	HumanID        string   `json:"human_id"`
+	Capabilities   []string `json:"capabilities"`
+	NotBefore      string   `json:"not_before"`
+	NotAfter       string   `json:"not_after"`
+	Signature      string   `json:"signature"`
+}
+
+// PowerOfAttorney is a human's signed grant to another, the attorney, of
+// the rights in Scope over its agents between NotBefore and NotAfter. It
+// is signed by the grantor's key, GrantorPublicKey.
+type PowerOfAttorney struct {
+	DCPVersion        string          `json:"dcp_version"`
+	GrantorID         string          `json:"grantor_id"`
+	GrantorPublicKey  string          `json:"grantor_public_key"`
+	AttorneyID        string          `json:"attorney_id"`
+	AttorneyPublicKey string          `json:"attorney_public_key"`
+	Scope             []AttorneyScope `json:"scope"`
+	NotBefore         string          `json:"not_before"`
+	NotAfter          string          `json:"not_after"`
+	Signature         string          `json:"signature"`
+}
+
+// InsuranceAttestation is an insurer's or platform's signed assertion that
+// the agent AgentID of principal HumanID is covered, for CoverageClass,
+// under the policy whose number hashes to PolicyNumberHash, between
+// NotBefore and NotAfter. It is signed with the attester's published key.
+type InsuranceAttestation struct {
+	DCPVersion       string        `json:"dcp_version"`
+	AttesterID       string        `json:"attester_id"`
+	AttesterType     AttesterType  `json:"attester_type"`
+	HumanID          string        `json:"human_id"`
+	AgentID          string        `json:"agent_id"`
+	PolicyNumberHash string        `json:"policy_number_hash"`
+	CoverageClass    CoverageClass `json:"coverage_class"`
+	NotBefore        string        `json:"not_before"`
+	NotAfter         string        `json:"not_after"`
+	Signature        string        `json:"signature"`
+}
+
+// DelegationLink is one step of a delegation chain: the delegating agent's
+// passport and its delegation to the next agent.
+type DelegationLink struct {
+	ParentPassport AgentPassport    `json:"parent_passport"`
+	Delegation     DelegationRecord `json:"delegation"`
+}
+
+// BreakGlassRecord authorizes an emergency action outside normal policy:
+// AuthorizedBy permits the action types in Scope on one intent between
+// NotBefore and NotAfter, for the stated Justification. The authorizer
+// signs it, and a second party, Countersigner, countersigns it.
+type BreakGlassRecord struct {
+	DCPVersion             string   `json:"dcp_version"`
+	AgentID                string   `json:"agent_id"`
+	IntentID               string   `json:"intent_id"`
+	IntentHash             string   `json:"intent_hash"`
+	AuthorizedBy           string   `json:"authorized_by"`
+	Justification          string   `json:"justification"`
+	Scope                  []string `json:"scope"`
+	NotBefore              string   `json:"not_before"`
+	NotAfter               string   `json:"not_after"`
+	Signature              string   `json:"signature"`
+	Countersigner          string   `json:"countersigner"`
+	CountersignerPublicKey string   `json:"countersigner_public_key"`
+	Countersignature       string   `json:"countersignature"`
 }
 
 // Signer represents the bundle signer information.
@@ -105,6 +336,16 @@ type BundleSignature struct {
 	BundleHash string  `json:"bundle_hash"`
 	MerkleRoot *string `json:"merkle_root"`
 	SigB64     string  `json:"sig_b64"`
+	// Cosignatures are further signatures over the bundle by members of
+	// a group principal's signer set; see CosignBundle.
+	Cosignatures []PrincipalCosignature `json:"cosignatures,omitempty"`
+}
+
+// PrincipalCosignature is a signer set member's signature over the
+// canonical bundle, like sig_b64.
+type PrincipalCosignature struct {
+	HumanID string `json:"human_id"`
+	SigB64  string `json:"sig_b64"`
 }
 
 // SignedBundle represents a signed DCP Citizenship Bundle.
@@ -117,6 +358,12 @@ type SignedBundle struct {
 type VerificationResult struct {
 	Verified bool     `json:"verified"`
 	Errors   []string `json:"errors,omitempty"`
+	// Trace lists each step performed when verification ran with
+	// VerifyOptions.Explain.
+	Trace []TraceStep `json:"trace,omitempty"`
+	// Flags lists what a reviewer must see even in a verified bundle, such
+	// as actions taken under a break-glass record.
+	Flags []string `json:"flags,omitempty"`
 }
 
 // RevocationRecord represents a DCP agent revocation.
@@ -126,5 +373,8 @@ type RevocationRecord struct {
 	HumanID    string `json:"human_id"`
 	Timestamp  string `json:"timestamp"`
 	Reason     string `json:"reason"`
+	// PowersOfAttorney is set when the record is signed by an attorney of
+	// HumanID; see VerifyPrincipalSignature.
+	PowersOfAttorney []PowerOfAttorney `json:"powers_of_attorney,omitempty"`
 	Signature  string `json:"signature"`
 }
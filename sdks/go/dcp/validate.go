@@ -0,0 +1,496 @@
+package dcp
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FieldError is a single constraint violation found by Validate. Pointer is
+// an RFC 6901 JSON pointer to the offending member.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	p := e.Pointer
+	if p == "" {
+		p = "/"
+	}
+	return fmt.Sprintf("%s: %s", p, e.Message)
+}
+
+// ValidationErrors collects every FieldError found in a record.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "validate: " + strings.Join(msgs, "; ")
+}
+
+// Allowed values of the schemas/v1 enumerations that have no type of their
+// own; see enums.go for the rest.
+var (
+	capabilities    = []string{"browse", "api_call", "email", "calendar", "payments", "crm", "file_write", "code_exec"}
+	actionTypes     = []string{"browse", "api_call", "send_email", "create_calendar_event", "initiate_payment", "update_crm", "write_file", "execute_code"}
+	dataClasses     = []string{"none", "contact_info", "pii", "credentials", "financial_data", "health_data", "children_data", "company_confidential"}
+	confirmTypes    = []string{"human_approve"}
+	signerTypes     = []string{"human", "organization"}
+	sha256Reference = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+)
+
+// validator accumulates FieldErrors for the record at path.
+type validator struct {
+	path string
+	errs *ValidationErrors
+}
+
+func newValidator() validator {
+	return validator{errs: &ValidationErrors{}}
+}
+
+func (v validator) at(member string) validator {
+	return validator{path: v.path + "/" + jsonPointerToken(member), errs: v.errs}
+}
+
+func (v validator) index(i int) validator {
+	return validator{path: fmt.Sprintf("%s/%d", v.path, i), errs: v.errs}
+}
+
+// field is the validator for member, or v itself when member is empty.
+func (v validator) field(member string) validator {
+	if member == "" {
+		return v
+	}
+	return v.at(member)
+}
+
+func (v validator) fail(format string, args ...interface{}) {
+	*v.errs = append(*v.errs, &FieldError{Pointer: v.path, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns the collected errors, or nil when there are none.
+func (v validator) err() error {
+	if len(*v.errs) == 0 {
+		return nil
+	}
+	return *v.errs
+}
+
+func (v validator) version(member, s string) {
+	if s != DCPVersion {
+		v.field(member).fail("must be %q", DCPVersion)
+	}
+}
+
+func (v validator) minLen(member, s string, n int) {
+	switch {
+	case s == "":
+		v.field(member).fail("is required")
+	case len(s) < n:
+		v.field(member).fail("must be at least %d characters", n)
+	}
+}
+
+// id checks an identifier member.
+func (v validator) id(member, s string) {
+	v.minLen(member, s, 6)
+}
+
+// idOf checks an identifier member that names an entity of kind k: a dcp:
+// identifier must parse and be of that kind.
+func (v validator) idOf(member, s string, k IDKind) {
+	if !IsSchemeID(s) {
+		v.id(member, s)
+		return
+	}
+	id, err := ParseID(s)
+	switch {
+	case err != nil:
+		v.field(member).fail("must be dcp:<kind>:<value>")
+	case id.Kind != k:
+		v.field(member).fail("must be a dcp:%s: identifier, not dcp:%s:", k, id.Kind)
+	}
+}
+
+func (v validator) timestamp(member, s string) {
+	if s == "" {
+		v.field(member).fail("is required")
+	} else if _, err := ParseTime(s); err != nil {
+		v.field(member).fail("must be an RFC 3339 date-time")
+	}
+}
+
+func (v validator) enum(member, s string, allowed []string) {
+	if oneOf(s, allowed) {
+		return
+	}
+	if s == "" {
+		v.field(member).fail("is required")
+		return
+	}
+	v.field(member).fail("must be one of %s", strings.Join(allowed, ", "))
+}
+
+// signature checks an optional base64 Ed25519 signature; records are
+// validated before they are signed, so an empty signature is accepted.
+func (v validator) signature(member, s string) {
+	if s == "" {
+		return
+	}
+	sig, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		v.field(member).fail("must be a base64 Ed25519 signature")
+	}
+}
+
+func (v validator) publicKey(member, s string) {
+	if s == "" {
+		v.field(member).fail("is required")
+		return
+	}
+	pk, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(pk) != ed25519.PublicKeySize {
+		v.field(member).fail("must be a base64 Ed25519 public key")
+	}
+}
+
+func (v validator) sha256Ref(member, s string) {
+	if !sha256Reference.MatchString(s) {
+		v.field(member).fail("must be sha256: followed by 64 lowercase hex digits")
+	}
+}
+
+// Validate checks r against the DCP-01 schema and returns ValidationErrors
+// listing every violation, or nil.
+func (r *ResponsiblePrincipalRecord) Validate() error {
+	v := newValidator()
+	r.validate(v)
+	return v.err()
+}
+
+func (r *ResponsiblePrincipalRecord) validate(v validator) {
+	v.version("dcp_version", r.DCPVersion)
+	v.idOf("human_id", r.HumanID, IDKindHuman)
+	v.minLen("legal_name", r.LegalName, 1)
+	v.enum("entity_type", string(r.EntityType), entityTypes)
+	if j, err := ParseJurisdiction(r.Jurisdiction); err != nil {
+		v.at("jurisdiction").fail("must be an ISO 3166-1 country, an ISO 3166-2 subdivision, EU or EEA")
+	} else if j.Code != r.Jurisdiction {
+		v.at("jurisdiction").fail("must be normalized as %s", j.Code)
+	}
+	r.validateEntity(v)
+	v.enum("liability_mode", string(r.LiabilityMode), liabilityModes)
+	v.timestamp("issued_at", r.IssuedAt)
+	if r.ExpiresAt != nil {
+		v.timestamp("expires_at", *r.ExpiresAt)
+	}
+	if r.SignerSet != nil {
+		r.SignerSet.validate(v.at("signer_set"))
+	}
+	if r.Guardian != nil {
+		r.Guardian.validate(v.at("guardian"))
+		if r.Guardian.GuardianID == r.HumanID {
+			v.at("guardian").at("guardian_id").fail("must not be the principal")
+		}
+	}
+	v.signature("signature", r.Signature)
+}
+
+// Validate checks p against the DCP-01 schema and returns ValidationErrors
+// listing every violation, or nil.
+func (p *AgentPassport) Validate() error {
+	v := newValidator()
+	p.validate(v)
+	return v.err()
+}
+
+func (p *AgentPassport) validate(v validator) {
+	v.version("dcp_version", p.DCPVersion)
+	v.idOf("agent_id", p.AgentID, IDKindAgent)
+	v.publicKey("public_key", p.PublicKey)
+	v.idOf("principal_binding_reference", p.PrincipalBindingReference, IDKindHuman)
+	for i, c := range p.Capabilities {
+		if _, err := ParseCapability(c); err != nil {
+			v.at("capabilities").index(i).fail("must be a capability")
+		}
+	}
+	for _, c := range sortedDomainKeys(p.CapabilityDomains) {
+		cv := v.at("capability_domains").at(c)
+		cv.enum("", c, capabilities)
+		list := p.CapabilityDomains[c]
+		list.validate(cv)
+	}
+	if p.RiskTier != "" {
+		v.enum("risk_tier", string(p.RiskTier), riskTiers)
+	}
+	v.timestamp("created_at", p.CreatedAt)
+	v.enum("status", string(p.Status), statuses)
+	v.signature("signature", p.Signature)
+	v.signature("guardian_signature", p.GuardianSignature)
+}
+
+// Validate checks i against the DCP-02 schema and returns ValidationErrors
+// listing every violation, or nil.
+func (i *Intent) Validate() error {
+	v := newValidator()
+	i.validate(v)
+	return v.err()
+}
+
+func (i *Intent) validate(v validator) {
+	v.version("dcp_version", i.DCPVersion)
+	v.idOf("intent_id", i.IntentID, IDKindIntent)
+	v.idOf("agent_id", i.AgentID, IDKindAgent)
+	v.idOf("human_id", i.HumanID, IDKindHuman)
+	v.timestamp("timestamp", i.Timestamp)
+	v.enum("action_type", i.ActionType, actionTypes)
+	i.Target.validate(v.at("target"))
+	if len(i.DataClasses) == 0 {
+		v.at("data_classes").fail("must list at least one data class")
+	}
+	for n, c := range i.DataClasses {
+		v.at("data_classes").index(n).enum("", c, dataClasses)
+	}
+	v.enum("estimated_impact", string(i.EstimatedImpact), impacts)
+	if i.Domains != nil {
+		i.Domains.validate(v.at("domains"))
+	}
+	if i.Supersedes != "" {
+		v.minLen("supersedes", i.Supersedes, 8)
+	}
+	if i.ExpiresAt != nil {
+		v.timestamp("expires_at", *i.ExpiresAt)
+	}
+	if i.Nonce != "" {
+		v.minLen("nonce", i.Nonce, 16)
+	}
+	if i.IdempotencyKey != "" {
+		v.minLen("idempotency_key", i.IdempotencyKey, 8)
+		if len(i.IdempotencyKey) > maxIdempotencyKey {
+			v.field("idempotency_key").fail("must be at most %d characters", maxIdempotencyKey)
+		}
+	}
+	if i.Batch != nil {
+		i.Batch.validate(v.at("batch"))
+	}
+}
+
+// Validate checks t against the DCP-02 schema and returns ValidationErrors
+// listing every violation, or nil.
+func (t *IntentTarget) Validate() error {
+	v := newValidator()
+	t.validate(v)
+	return v.err()
+}
+
+func (t *IntentTarget) validate(v validator) {
+	v.enum("channel", string(t.Channel), channels)
+}
+
+// Validate checks d against the DCP-02 schema and returns ValidationErrors
+// listing every violation, or nil.
+func (d *PolicyDecision) Validate() error {
+	v := newValidator()
+	d.validate(v)
+	return v.err()
+}
+
+func (d *PolicyDecision) validate(v validator) {
+	v.version("dcp_version", d.DCPVersion)
+	v.idOf("intent_id", d.IntentID, IDKindIntent)
+	v.enum("decision", string(d.Decision), decisions)
+	if d.RiskScore < 0 || d.RiskScore > 1 {
+		v.at("risk_score").fail("must be between 0 and 1")
+	}
+	if len(d.Reasons) == 0 {
+		v.at("reasons").fail("must list at least one reason")
+	}
+	if d.RequiredConfirmation != nil {
+		d.RequiredConfirmation.validate(v.at("required_confirmation"))
+	}
+	if d.DecisionID != "" {
+		v.idOf("decision_id", d.DecisionID, IDKindDecision)
+	}
+	v.signature("signature", d.Signature)
+}
+
+// Validate checks c against the DCP-02 schema and returns ValidationErrors
+// listing every violation, or nil.
+func (c *RequiredConfirmation) Validate() error {
+	v := newValidator()
+	c.validate(v)
+	return v.err()
+}
+
+func (c *RequiredConfirmation) validate(v validator) {
+	v.enum("type", c.Type, confirmTypes)
+}
+
+// Validate checks e against the DCP-03 schema and returns ValidationErrors
+// listing every violation, or nil. It does not check the hash chain; see
+// VerifySignedBundle and NewStreamVerifier for that.
+func (e *AuditEntry) Validate() error {
+	v := newValidator()
+	e.validate(v)
+	return v.err()
+}
+
+func (e *AuditEntry) validate(v validator) {
+	v.version("dcp_version", e.DCPVersion)
+	v.idOf("audit_id", e.AuditID, IDKindAudit)
+	v.minLen("prev_hash", e.PrevHash, 1)
+	v.timestamp("timestamp", e.Timestamp)
+	v.idOf("agent_id", e.AgentID, IDKindAgent)
+	v.idOf("human_id", e.HumanID, IDKindHuman)
+	v.idOf("intent_id", e.IntentID, IDKindIntent)
+	v.minLen("intent_hash", e.IntentHash, 8)
+	v.enum("policy_decision", string(e.PolicyDecision), outcomes)
+	v.minLen("outcome", e.Outcome, 1)
+	if it := e.Evidence.BatchItem; it != nil {
+		if it.Index < 0 {
+			v.at("evidence").at("batch_item").at("index").fail("must not be negative")
+		}
+		v.at("evidence").at("batch_item").minLen("item", it.Item, 1)
+	}
+	v.signature("agent_signature", e.AgentSignature)
+}
+
+// Validate checks a against the bundle schema and returns ValidationErrors
+// listing every violation, or nil.
+func (a *ChainAnchor) Validate() error {
+	v := newValidator()
+	a.validate(v)
+	return v.err()
+}
+
+func (a *ChainAnchor) validate(v validator) {
+	v.minLen("prev_entry_hash", a.PrevEntryHash, 8)
+	if a.PrevBundleHash != "" {
+		v.sha256Ref("prev_bundle_hash", a.PrevBundleHash)
+	}
+}
+
+// Validate checks every record in b and returns ValidationErrors listing
+// every violation, or nil.
+func (b *CitizenshipBundle) Validate() error {
+	v := newValidator()
+	b.validate(v)
+	return v.err()
+}
+
+func (b *CitizenshipBundle) validate(v validator) {
+	b.ResponsiblePrincipalRecord.validate(v.at("responsible_principal_record"))
+	b.AgentPassport.validate(v.at("agent_passport"))
+	b.Intent.validate(v.at("intent"))
+	b.PolicyDecision.validate(v.at("policy_decision"))
+	if len(b.AuditEntries) == 0 {
+		v.at("audit_entries").fail("must contain at least one entry")
+	}
+	for i := range b.AuditEntries {
+		b.AuditEntries[i].validate(v.at("audit_entries").index(i))
+	}
+	if b.ChainAnchor != nil {
+		b.ChainAnchor.validate(v.at("chain_anchor"))
+	}
+	if b.Consent != nil {
+		b.Consent.validate(v.at("consent"))
+	}
+	for i := range b.IntentHistory {
+		b.IntentHistory[i].validate(v.at("intent_history").index(i))
+	}
+	for i := range b.Overrides {
+		b.Overrides[i].validate(v.at("overrides").index(i))
+	}
+	for i := range b.BreakGlass {
+		b.BreakGlass[i].validate(v.at("break_glass").index(i))
+	}
+	if b.Cancellation != nil {
+		b.Cancellation.validate(v.at("cancellation"))
+	}
+	for i := range b.DelegationChain {
+		b.DelegationChain[i].validate(v.at("delegation_chain").index(i))
+	}
+	for i := range b.PowersOfAttorney {
+		b.PowersOfAttorney[i].validate(v.at("powers_of_attorney").index(i))
+	}
+	for i := range b.Insurance {
+		b.Insurance[i].validate(v.at("insurance").index(i))
+	}
+}
+
+// Validate checks s against the signed bundle schema and returns
+// ValidationErrors listing every violation, or nil.
+func (s *Signer) Validate() error {
+	v := newValidator()
+	s.validate(v)
+	return v.err()
+}
+
+func (s *Signer) validate(v validator) {
+	v.enum("type", s.Type, signerTypes)
+	v.id("id", s.ID)
+	v.publicKey("public_key_b64", s.PublicKeyB64)
+}
+
+// Validate checks s against the signed bundle schema and returns
+// ValidationErrors listing every violation, or nil.
+func (s *BundleSignature) Validate() error {
+	v := newValidator()
+	s.validate(v)
+	return v.err()
+}
+
+func (s *BundleSignature) validate(v validator) {
+	v.enum("alg", s.Alg, []string{"ed25519"})
+	v.timestamp("created_at", s.CreatedAt)
+	s.SignerInfo.validate(v.at("signer"))
+	v.sha256Ref("bundle_hash", s.BundleHash)
+	if s.MerkleRoot != nil {
+		v.sha256Ref("merkle_root", *s.MerkleRoot)
+	}
+	if s.SigB64 == "" {
+		v.at("sig_b64").fail("is required")
+	}
+	v.signature("sig_b64", s.SigB64)
+	for i := range s.Cosignatures {
+		cv := v.at("cosignatures").index(i)
+		cv.idOf("human_id", s.Cosignatures[i].HumanID, IDKindHuman)
+		if s.Cosignatures[i].SigB64 == "" {
+			cv.at("sig_b64").fail("is required")
+		}
+		cv.signature("sig_b64", s.Cosignatures[i].SigB64)
+	}
+}
+
+// Validate checks the bundle and its signature block and returns
+// ValidationErrors listing every violation, or nil. It checks structure
+// only; use VerifySignedBundle to check the signature itself.
+func (sb *SignedBundle) Validate() error {
+	v := newValidator()
+	sb.Bundle.validate(v.at("bundle"))
+	sb.Signature.validate(v.at("signature"))
+	return v.err()
+}
+
+// Validate checks r against the revocation record schema and returns
+// ValidationErrors listing every violation, or nil.
+func (r *RevocationRecord) Validate() error {
+	v := newValidator()
+	v.version("dcp_version", r.DCPVersion)
+	v.idOf("agent_id", r.AgentID, IDKindAgent)
+	v.idOf("human_id", r.HumanID, IDKindHuman)
+	v.timestamp("timestamp", r.Timestamp)
+	v.minLen("reason", r.Reason, 1)
+	for i := range r.PowersOfAttorney {
+		r.PowersOfAttorney[i].validate(v.at("powers_of_attorney").index(i))
+	}
+	v.signature("signature", r.Signature)
+	return v.err()
+}
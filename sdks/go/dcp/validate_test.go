@@ -0,0 +1,78 @@
+package dcp
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidateFixtureBundle(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(fixturesDir(), "examples", "citizenship_bundle.signed.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb SignedBundle
+	if err := json.Unmarshal(data, &sb); err != nil {
+		t.Fatal(err)
+	}
+	if err := sb.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateReportsEveryField(t *testing.T) {
+	agent, _ := GenerateKeypair()
+	f := RecordFactory{}
+	intent := f.NewIntent(f.NewAgentID(), f.NewHumanID(), "api_call", IntentTarget{Channel: "api"}, []string{"none"}, "low")
+	if err := intent.Validate(); err != nil {
+		t.Fatalf("constructed intent: %v", err)
+	}
+	passport := f.NewAgentPassport(f.NewHumanID(), agent.PublicKeyB64, []string{"api_call"}, "low")
+	if err := passport.Validate(); err != nil {
+		t.Fatalf("constructed passport: %v", err)
+	}
+
+	intent.DCPVersion = "2.0"
+	intent.AgentID = "abc"
+	intent.Timestamp = "yesterday"
+	intent.Target.Channel = "carrier_pigeon"
+	intent.DataClasses = []string{"none", "secrets"}
+	intent.EstimatedImpact = ""
+	err := intent.Validate()
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("err = %v", err)
+	}
+	var got []string
+	for _, fe := range verrs {
+		got = append(got, fe.Pointer)
+	}
+	want := []string{"/dcp_version", "/agent_id", "/timestamp", "/target/channel", "/data_classes/1", "/estimated_impact"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("pointers = %v, want %v", got, want)
+	}
+
+	passport.PublicKey = "not base64!"
+	passport.Capabilities = []string{"email:send:<=ten"}
+	passport.Signature = "c2ln"
+	bundle := CitizenshipBundle{AgentPassport: passport, Intent: intent}
+	err = bundle.Validate()
+	if !errors.As(err, &verrs) {
+		t.Fatalf("err = %v", err)
+	}
+	pointers := map[string]bool{}
+	for _, fe := range verrs {
+		pointers[fe.Pointer] = true
+	}
+	for _, p := range []string{
+		"/agent_passport/public_key", "/agent_passport/capabilities/0", "/agent_passport/signature",
+		"/intent/target/channel", "/responsible_principal_record/human_id", "/policy_decision/reasons", "/audit_entries",
+	} {
+		if !pointers[p] {
+			t.Errorf("missing error for %s in %v", p, err)
+		}
+	}
+}
@@ -7,87 +7,500 @@ import (
 	"strings"
 )
 
+// bundleView is the canonical form of everything VerifySignedBundle checks.
+// It is built either from the typed structs or from the raw received JSON,
+// so both paths share one set of checks.
+type bundleView struct {
+	bundleCanon string
+	intentCanon string
+	agentKey    string
+	chainStart  string
+	entries     []entryView
+	// passport and intent are checked by VerifyOptions.CheckDomains.
+	passport *AgentPassport
+	intent   *Intent
+	decision *PolicyDecision
+	consent  *ConsentRecord
+	// overrides are checked against overrideRights, the principal's
+	// override_rights.
+	overrides      []OverrideRecord
+	overrideRights bool
+	breakGlass     []BreakGlassRecord
+	cancellation   *IntentCancellation
+	history        []Intent
+	batchManifest  []string
+	delegation     []DelegationLink
+	principal      *ResponsiblePrincipalRecord
+	attorneys      []PowerOfAttorney
+	insurance      []InsuranceAttestation
+}
+
+type entryView struct {
+	canon      string
+	prevHash   string
+	intentHash string
+	// agentSig and unsignedCanon are set when the entry carries an
+	// agent_signature; unsignedCanon is the entry without that member.
+	agentSig      string
+	unsignedCanon string
+	// resultRef is evidence.result_ref, which links an override.
+	resultRef string
+	// timestamp and outcome are checked against a cancellation.
+	timestamp string
+	outcome   string
+	// batchItem is evidence.batch_item, the batch action the entry records.
+	batchItem *BatchItem
+}
+
+func viewFromBundle(b *CitizenshipBundle) (*bundleView, error) {
+	bundleCanon, err := Canonicalize(b)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize error: %v", err)
+	}
+	intentCanon, err := Canonicalize(b.Intent)
+	if err != nil {
+		return nil, fmt.Errorf("intent hash: %v", err)
+	}
+	v := &bundleView{
+		bundleCanon: bundleCanon,
+		intentCanon: intentCanon,
+		agentKey:    b.AgentPassport.PublicKey,
+		chainStart:  chainStart(b.ChainAnchor),
+		passport:    &b.AgentPassport,
+		intent:      &b.Intent,
+		decision:    &b.PolicyDecision,
+		consent:     b.Consent,
+
+		overrides:      b.Overrides,
+		overrideRights: b.ResponsiblePrincipalRecord.OverrideRights,
+		breakGlass:     b.BreakGlass,
+		cancellation:   b.Cancellation,
+		history:        b.IntentHistory,
+		batchManifest:  b.BatchManifest,
+		delegation:     b.DelegationChain,
+		principal:      &b.ResponsiblePrincipalRecord,
+		attorneys:      b.PowersOfAttorney,
+		insurance:      b.Insurance,
+	}
+	for _, entry := range b.AuditEntries {
+		canon, err := Canonicalize(entry)
+		if err != nil {
+			return nil, fmt.Errorf("hash audit entry: %v", err)
+		}
+		ev := entryView{canon: canon, prevHash: entry.PrevHash, intentHash: entry.IntentHash,
+			timestamp: entry.Timestamp, outcome: entry.Outcome, batchItem: entry.Evidence.BatchItem}
+		if entry.Evidence.ResultRef != nil {
+			ev.resultRef = *entry.Evidence.ResultRef
+		}
+		if entry.AgentSignature != "" {
+			ev.agentSig = entry.AgentSignature
+			if ev.unsignedCanon, err = Canonicalize(entry.unsigned()); err != nil {
+				return nil, fmt.Errorf("hash audit entry: %v", err)
+			}
+		}
+		v.entries = append(v.entries, ev)
+	}
+	return v, nil
+}
+
+// chainStart is the prev_hash expected on a bundle's first audit entry.
+func chainStart(anchor *ChainAnchor) string {
+	if anchor != nil {
+		return anchor.PrevEntryHash
+	}
+	return "GENESIS"
+}
+
+func sha256HexString(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
 // VerifySignedBundle performs full DCP verification on a signed bundle.
-// Checks signature, bundle_hash, merkle_root, intent_hash chain, and prev_hash chain.
+// Checks signature, bundle_hash, merkle_root, intent_hash chain, prev_hash chain,
+// and any per-entry agent signatures.
 func VerifySignedBundle(sb *SignedBundle, publicKeyB64 string) *VerificationResult {
-	if sb == nil {
-		return &VerificationResult{Verified: false, Errors: []string{"nil signed bundle"}}
-	}
+	return VerifySignedBundleWithOptions(sb, VerifyOptions{PublicKeyB64: publicKeyB64})
+}
 
-	pubKey := publicKeyB64
+func verifyBundleView(view *bundleView, sig *BundleSignature, opts VerifyOptions) *VerificationResult {
+	t := &tracer{enabled: opts.Explain}
+	t.canonical("bundle", view.bundleCanon)
+
+	pubKey, source := opts.PublicKeyB64, "pinned"
+	if len(view.attorneys) > 0 {
+		// Signed under powers of attorney: the pinned key, if any, is the
+		// principal's, and the bundle is signed by the last attorney, whose
+		// key then stands for the principal's in the checks below.
+		attorney, err := checkBundleAttorney(view.attorneys, view.principal.HumanID, opts.PublicKeyB64, view.intent, sig)
+		step := TraceStep{Check: TraceAttorney, Target: "powers_of_attorney", OK: err == nil, Actual: attorney.HumanID,
+			Detail: fmt.Sprintf("for %s, %d deep", view.principal.HumanID, len(view.attorneys))}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+		pubKey, source = attorney.PublicKey, "attorney "+attorney.HumanID
+	}
 	if pubKey == "" {
-		pubKey = sb.Signature.SignerInfo.PublicKeyB64
+		pubKey, source = sig.SignerInfo.PublicKeyB64, "embedded in signature.signer"
 	}
 	if pubKey == "" {
-		return &VerificationResult{Verified: false, Errors: []string{"missing public key"}}
+		t.add(TraceStep{Check: TraceKey, Target: "bundle", Detail: "no pinned key and none embedded"})
+		return t.fail("missing public key")
 	}
+	t.add(TraceStep{Check: TraceKey, Target: "bundle", OK: true, Actual: pubKey, Detail: source})
 
 	// 1) Signature verification
-	ok, err := VerifyObject(sb.Bundle, sb.Signature.SigB64, pubKey)
-	if err != nil || !ok {
-		return &VerificationResult{Verified: false, Errors: []string{"SIGNATURE INVALID"}}
+	ok, err := VerifyCanonical(view.bundleCanon, sig.SigB64, pubKey)
+	step := TraceStep{Check: TraceSignature, Target: "bundle", OK: err == nil && ok, Detail: sig.Alg}
+	if err != nil {
+		step.Detail = err.Error()
+	}
+	t.add(step)
+	if !step.OK {
+		return t.fail("SIGNATURE INVALID")
 	}
 
 	// 2) bundle_hash
-	if strings.HasPrefix(sb.Signature.BundleHash, "sha256:") {
-		canon, err := Canonicalize(sb.Bundle)
+	if strings.HasPrefix(sig.BundleHash, "sha256:") {
+		expectedHex := sha256HexString(view.bundleCanon)
+		got := sig.BundleHash[len("sha256:"):]
+		if !t.compare(TraceBundleHash, "bundle", expectedHex, got) {
+			return t.fail("BUNDLE HASH MISMATCH")
+		}
+	} else {
+		t.add(TraceStep{Check: TraceBundleHash, Target: "bundle", OK: true, Actual: sig.BundleHash, Detail: "not a sha256: hash; skipped"})
+	}
+
+	// 3) merkle_root
+	if sig.MerkleRoot != nil && strings.HasPrefix(*sig.MerkleRoot, "sha256:") {
+		canons := make([]string, len(view.entries))
+		for i, entry := range view.entries {
+			canons[i] = entry.canon
+		}
+		var hasher MerkleHasher
+		expectedMerkle, err := hasher.RootFromHexLeaves(hasher.hashCanonLeaves(canons))
 		if err != nil {
-			return &VerificationResult{Verified: false, Errors: []string{fmt.Sprintf("canonicalize error: %v", err)}}
+			t.add(TraceStep{Check: TraceMerkleRoot, Target: "audit_entries", Detail: err.Error()})
+			return t.fail(fmt.Sprintf("merkle root: %v", err))
 		}
-		h := sha256.Sum256([]byte(canon))
-		expectedHex := hex.EncodeToString(h[:])
-		got := sb.Signature.BundleHash[len("sha256:"):]
-		if got != expectedHex {
-			return &VerificationResult{Verified: false, Errors: []string{"BUNDLE HASH MISMATCH"}}
+		gotMerkle := (*sig.MerkleRoot)[len("sha256:"):]
+		if !t.compare(TraceMerkleRoot, "audit_entries", expectedMerkle, gotMerkle) {
+			return t.fail("MERKLE ROOT MISMATCH")
 		}
+	} else {
+		t.add(TraceStep{Check: TraceMerkleRoot, Target: "audit_entries", OK: true, Detail: "not claimed; skipped"})
 	}
 
-	// 3) merkle_root
-	if sb.Signature.MerkleRoot != nil && strings.HasPrefix(*sb.Signature.MerkleRoot, "sha256:") {
-		var leaves []string
-		for _, entry := range sb.Bundle.AuditEntries {
-			h, err := HashObject(entry)
-			if err != nil {
-				return &VerificationResult{Verified: false, Errors: []string{fmt.Sprintf("hash audit entry: %v", err)}}
+	// 4) intent_hash and prev_hash chain
+	t.canonical("intent", view.intentCanon)
+	expectedIntentHash := sha256HexString(view.intentCanon)
+
+	prevHashExpected := view.chainStart
+	for i, entry := range view.entries {
+		target := fmt.Sprintf("audit_entries/%d", i)
+		if !t.compare(TraceIntentHash, target, expectedIntentHash, entry.intentHash) {
+			return t.fail(fmt.Sprintf("intent_hash (entry %d): expected %s, got %s", i, expectedIntentHash, entry.intentHash))
+		}
+		if !t.compare(TracePrevHash, target, prevHashExpected, entry.prevHash) {
+			return t.fail(fmt.Sprintf("prev_hash chain (entry %d): expected %s, got %s", i, prevHashExpected, entry.prevHash))
+		}
+		prevHashExpected = sha256HexString(entry.canon)
+	}
+
+	// 5) per-entry agent signatures, where present
+	for i, entry := range view.entries {
+		target := fmt.Sprintf("audit_entries/%d", i)
+		if entry.agentSig == "" {
+			t.add(TraceStep{Check: TraceAgentSignature, Target: target, OK: true, Detail: "absent; skipped"})
+			continue
+		}
+		ok, err := VerifyCanonical(entry.unsignedCanon, entry.agentSig, view.agentKey)
+		t.add(TraceStep{Check: TraceAgentSignature, Target: target, OK: err == nil && ok, Actual: view.agentKey, Detail: "agent_passport.public_key"})
+		if err != nil || !ok {
+			return t.fail(fmt.Sprintf("agent_signature (entry %d): invalid", i))
+		}
+	}
+
+	// 6) consent, required when the intent asks for it and checked when
+	// present, against the key the bundle verified with
+	if view.consent != nil {
+		err := view.consent.check(view.intent, expectedIntentHash, pubKey)
+		step := TraceStep{Check: TraceConsent, Target: "consent", OK: err == nil, Actual: pubKey, Detail: "signed by the bundle signer"}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+	} else if view.intent.RequiresConsent != nil && *view.intent.RequiresConsent {
+		t.add(TraceStep{Check: TraceConsent, Target: "consent", Detail: "intent requires consent; none in the bundle"})
+		return t.fail(ErrConsentRequired.Error() + ": the intent requires consent and the bundle has none")
+	}
+
+	// 7) overrides, each signed by the bundle signer, of the intent, and
+	// recorded by an audit entry
+	for i := range view.overrides {
+		o := &view.overrides[i]
+		target := fmt.Sprintf("overrides/%d", i)
+		err := o.check(view.intent, expectedIntentHash, pubKey)
+		if err == nil && !view.overrideRights {
+			err = fmt.Errorf("%w: the principal has no override rights", ErrOverrideInvalid)
+		}
+		if err == nil {
+			err = fmt.Errorf("%w: no audit entry records it", ErrOverrideInvalid)
+			for _, entry := range view.entries {
+				if entry.resultRef != "" {
+					if err = o.checkEntry(entry.resultRef, entry.prevHash, entry.intentHash); err == nil {
+						break
+					}
+				}
 			}
-			leaves = append(leaves, h)
 		}
-		expectedMerkle, err := MerkleRootFromHexLeaves(leaves)
+		step := TraceStep{Check: TraceOverride, Target: target, OK: err == nil, Actual: pubKey, Detail: string(o.Action)}
 		if err != nil {
-			return &VerificationResult{Verified: false, Errors: []string{fmt.Sprintf("merkle root: %v", err)}}
+			step.Detail = err.Error()
 		}
-		gotMerkle := (*sb.Signature.MerkleRoot)[len("sha256:"):]
-		if gotMerkle != expectedMerkle {
-			return &VerificationResult{Verified: false, Errors: []string{"MERKLE ROOT MISMATCH"}}
+		t.add(step)
+		if err != nil {
+			return t.fail(fmt.Sprintf("override %d: %v", i, err))
 		}
 	}
 
-	// 4) intent_hash and prev_hash chain
-	expectedIntentHash, err := HashObject(sb.Bundle.Intent)
-	if err != nil {
-		return &VerificationResult{Verified: false, Errors: []string{fmt.Sprintf("intent hash: %v", err)}}
+	// 8) break-glass records, signed by the bundle signer and countersigned
+	// with an allowed key; each is flagged in the result. Any key can
+	// countersign, so without allowed keys a record cannot be verified.
+	for i := range view.breakGlass {
+		r := &view.breakGlass[i]
+		target := fmt.Sprintf("break_glass/%d", i)
+		err := r.check(view.intent, expectedIntentHash, pubKey)
+		switch {
+		case err != nil:
+		case len(opts.Countersigners) == 0:
+			err = fmt.Errorf("%w: no countersigner keys to check the countersignature of %s against", ErrBreakGlassInvalid, r.Countersigner)
+		case !oneOf(r.CountersignerPublicKey, opts.Countersigners):
+			err = fmt.Errorf("%w: countersigner key %s is not allowed", ErrBreakGlassInvalid, r.CountersignerPublicKey)
+		}
+		step := TraceStep{Check: TraceBreakGlass, Target: target, OK: err == nil, Actual: r.CountersignerPublicKey, Detail: r.Justification}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		t.flags = append(t.flags, r.Flag())
+		if err != nil {
+			return t.fail(fmt.Sprintf("break_glass %d: %v", i, err))
+		}
 	}
 
-	prevHashExpected := "GENESIS"
-	for i, entry := range sb.Bundle.AuditEntries {
-		if entry.IntentHash != expectedIntentHash {
-			return &VerificationResult{
-				Verified: false,
-				Errors: []string{fmt.Sprintf("intent_hash (entry %d): expected %s, got %s", i, expectedIntentHash, entry.IntentHash)},
+	// 9) a cancellation, signed by the agent, and no success recorded after it
+	if c := view.cancellation; c != nil {
+		err := c.check(view.intent, expectedIntentHash, view.agentKey)
+		for i := 0; err == nil && i < len(view.entries); i++ {
+			err = c.checkEntry(i, view.entries[i].timestamp, view.entries[i].outcome)
+		}
+		step := TraceStep{Check: TraceCancellation, Target: "cancellation", OK: err == nil, Actual: view.agentKey, Detail: "cancelled at " + c.Timestamp}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+	}
+
+	// 10) the intent's earlier versions, each amended by the next
+	if view.intent.Supersedes != "" || len(view.history) > 0 {
+		err := checkAmendments(view.intent, view.history)
+		step := TraceStep{Check: TraceAmendments, Target: "intent_history", OK: err == nil, Expected: view.intent.Supersedes,
+			Detail: fmt.Sprintf("%d earlier versions", len(view.history))}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+	}
+
+	// 11) no action recorded after the intent expired
+	if view.intent.ExpiresAt != nil {
+		var err error
+		for i := 0; err == nil && i < len(view.entries); i++ {
+			if err = view.intent.checkEntry(view.entries[i].timestamp, view.entries[i].outcome); err != nil {
+				err = fmt.Errorf("audit entry %d: %w", i, err)
+			}
+		}
+		step := TraceStep{Check: TraceExpiry, Target: "intent.expires_at", OK: err == nil, Expected: *view.intent.ExpiresAt}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+	}
+
+	// 12) a batch intent's manifest, and one audit entry per action
+	if view.intent.Batch != nil || view.batchManifest != nil {
+		var items []*BatchItem
+		for _, entry := range view.entries {
+			if entry.batchItem != nil {
+				items = append(items, entry.batchItem)
 			}
 		}
-		if entry.PrevHash != prevHashExpected {
-			return &VerificationResult{
-				Verified: false,
-				Errors: []string{fmt.Sprintf("prev_hash chain (entry %d): expected %s, got %s", i, prevHashExpected, entry.PrevHash)},
+		err := checkBatch(view.intent.Batch, view.batchManifest, items)
+		step := TraceStep{Check: TraceBatch, Target: "batch_manifest", OK: err == nil,
+			Detail: fmt.Sprintf("%d actions", len(view.batchManifest))}
+		if view.intent.Batch != nil {
+			step.Expected = view.intent.Batch.ManifestHash
+		}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+	}
+
+	// 13) the policy decision, signed by a trusted PDP; a signed decision is
+	// skipped when no PDP key is configured, unless the agent's risk tier
+	// requires one
+	if d, tier := view.decision, view.passport.RiskTier; d.Signature != "" || tier == RiskTierHigh {
+		if len(opts.PDPKeys) == 0 && tier != RiskTierHigh {
+			t.add(TraceStep{Check: TraceDecision, Target: "policy_decision", OK: true, Detail: "no PDP key configured; skipped"})
+		} else {
+			key, err := checkDecision(tier, view.intent, d, opts.PDPKeys)
+			step := TraceStep{Check: TraceDecision, Target: "policy_decision", OK: err == nil, Actual: key, Detail: d.DecisionID}
+			if err != nil {
+				step.Detail = err.Error()
 			}
+			t.add(step)
+			if err != nil {
+				return t.fail(err.Error())
+			}
+		}
+	}
+
+	// 14) a sub-agent's delegation chain, back to a parent passport of its
+	// principal, each link granting no more than the one before
+	if len(view.delegation) > 0 {
+		err := checkDelegationChain(view.passport, view.intent, view.delegation)
+		root := view.delegation[0].ParentPassport.AgentID
+		step := TraceStep{Check: TraceDelegation, Target: "delegation_chain", OK: err == nil, Actual: root,
+			Detail: fmt.Sprintf("from %s, %d deep", root, len(view.delegation))}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+	}
+
+	// 15) a group principal's quorum: signatures over the bundle from
+	// enough members of its signer set, as anchored outside the bundle
+	anchored := opts.SignerSets[view.principal.HumanID]
+	if view.principal.SignerSet != nil || anchored != nil || len(sig.Cosignatures) > 0 {
+		set, err := anchoredSignerSet(view.principal, anchored)
+		var members []string
+		if err == nil {
+			members, err = checkQuorum(set, view.bundleCanon, pubKey, sig.SigB64, sig.Cosignatures)
+		}
+		step := TraceStep{Check: TraceQuorum, Target: "signature.cosignatures", OK: err == nil, Actual: strings.Join(members, ", ")}
+		if anchored != nil {
+			step.Expected = fmt.Sprintf("%d of %d", anchored.Threshold, len(anchored.Signers))
+		}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+	}
+
+	// 16) a dependent principal's guardian: the agent's passport, and those
+	// of the agents it was delegated by, countersigned by the guardian and
+	// within the capabilities allowed to a dependent's agents; the guardian
+	// is the one anchored in opts
+	anchoredGuardian := opts.Guardians[view.principal.HumanID]
+	if g := view.principal.Guardian; g != nil || anchoredGuardian != "" {
+		err := CheckGuardian(view.principal, view.passport, anchoredGuardian)
+		for i := 0; err == nil && i < len(view.delegation); i++ {
+			err = CheckGuardian(view.principal, &view.delegation[i].ParentPassport, anchoredGuardian)
+		}
+		step := TraceStep{Check: TraceGuardian, Target: "agent_passport.guardian_signature", OK: err == nil, Actual: anchoredGuardian}
+		if g != nil {
+			step.Detail = string(g.Relationship) + " " + g.GuardianID
+		}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+	}
+
+	// 17) target domains against the intent's and passport's domain lists
+	if opts.CheckDomains {
+		err := CheckDomains(view.passport, view.intent)
+		step := TraceStep{Check: TraceDomains, Target: "intent", OK: err == nil, Actual: view.intent.Target.Host()}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+	}
+
+	// 18) the obligations of the principal's liability mode
+	if opts.EnforceLiability {
+		mode := view.principal.LiabilityMode
+		err := checkLiability(view, opts.PDPKeys)
+		step := TraceStep{Check: TraceLiability, Target: "responsible_principal_record.liability_mode", OK: err == nil, Actual: string(mode),
+			Detail: fmt.Sprint(mode.Obligations())}
+		if err != nil {
+			step.Detail = err.Error()
+		}
+		t.add(step)
+		if err != nil {
+			return t.fail(err.Error())
+		}
+	}
+
+	// 19) insurance attestations, signed by a trusted attester and covering
+	// the principal and agent when the intent was declared
+	for i := range view.insurance {
+		a := &view.insurance[i]
+		target := fmt.Sprintf("insurance/%d", i)
+		if len(opts.InsurerKeys) == 0 {
+			t.add(TraceStep{Check: TraceInsurance, Target: target, OK: true, Detail: "no insurer key configured; skipped"})
+			continue
+		}
+		declared, err := view.intent.TimestampTime()
+		key := ""
+		if err != nil {
+			err = fmt.Errorf("%w: intent %v", ErrInsuranceInvalid, err)
+		} else {
+			key, err = a.Check(view.principal.HumanID, view.passport.AgentID, opts.InsurerKeys, declared)
+		}
+		step := TraceStep{Check: TraceInsurance, Target: target, OK: err == nil, Actual: key,
+			Detail: string(a.CoverageClass) + " by " + a.AttesterID}
+		if err != nil {
+			step.Detail = err.Error()
 		}
-		h, err := HashObject(entry)
+		t.add(step)
 		if err != nil {
-			return &VerificationResult{Verified: false, Errors: []string{fmt.Sprintf("hash entry: %v", err)}}
+			return t.fail(fmt.Sprintf("insurance %d: %v", i, err))
 		}
-		prevHashExpected = h
 	}
 
-	return &VerificationResult{Verified: true}
+	return &VerificationResult{Verified: true, Trace: t.steps, Flags: t.flags}
 }
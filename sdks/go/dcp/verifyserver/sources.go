@@ -0,0 +1,123 @@
+package verifyserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// FileRevocations is a revocation source backed by a list file written by
+// dcp revoke or dcp.WriteRevocationList. The file is read again whenever
+// its modification time changes, so it can be replaced while the server
+// runs.
+type FileRevocations struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	list    *dcp.RevocationList
+}
+
+var _ dcp.RevocationChecker = (*FileRevocations)(nil)
+
+// NewFileRevocations reads the list at path. A missing file is an error
+// here, unlike in dcp.ReadRevocationList, so a mistyped path is caught at
+// startup.
+func NewFileRevocations(path string) (*FileRevocations, error) {
+	f := &FileRevocations{path: path}
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	if _, err := f.current(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *FileRevocations) current() (*dcp.RevocationList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		return nil, err
+	}
+	if f.list == nil || !fi.ModTime().Equal(f.modTime) {
+		l, err := dcp.ReadRevocationList(f.path)
+		if err != nil {
+			return nil, err
+		}
+		f.list, f.modTime = l, fi.ModTime()
+	}
+	return f.list, nil
+}
+
+// CheckRevocation implements dcp.RevocationChecker.
+func (f *FileRevocations) CheckRevocation(ctx context.Context, agentID string) (*dcp.RevocationRecord, error) {
+	l, err := f.current()
+	if err != nil {
+		return nil, err
+	}
+	return l.CheckRevocation(ctx, agentID)
+}
+
+// RegistryRevocations is a revocation source that queries a registry
+// speaking the services/revocation API: GET {URL}/check/{agent_id} answers
+// {"revoked": bool, "record": {...}}.
+type RegistryRevocations struct {
+	// URL is the registry base URL, e.g. "http://localhost:3003".
+	URL string
+	// HTTPClient defaults to http.DefaultClient; the request context
+	// carries the server's timeout.
+	HTTPClient *http.Client
+}
+
+var _ dcp.RevocationChecker = (*RegistryRevocations)(nil)
+
+// CheckRevocation implements dcp.RevocationChecker.
+func (r *RegistryRevocations) CheckRevocation(ctx context.Context, agentID string) (*dcp.RevocationRecord, error) {
+	u := strings.TrimRight(r.URL, "/") + "/check/" + url.PathEscape(agentID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", u, resp.Status)
+	}
+	var status struct {
+		Revoked bool                  `json:"revoked"`
+		Record  *dcp.RevocationRecord `json:"record"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, fmt.Errorf("%s: %w", u, err)
+	}
+	if !status.Revoked {
+		return nil, nil
+	}
+	if status.Record == nil {
+		// Some registries answer with the status alone.
+		return &dcp.RevocationRecord{AgentID: agentID, Reason: "revoked by " + r.URL}, nil
+	}
+	return status.Record, nil
+}
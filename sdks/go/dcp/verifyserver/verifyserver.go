@@ -0,0 +1,295 @@
+// Package verifyserver is an HTTP service that verifies V1 signed bundles,
+// so clients in any language can use the Go verifier over REST.
+//
+// Endpoints:
+//
+//	POST /v1/verify    body: a SignedBundle; ?explain=true adds the trace
+//	GET  /health       liveness and configuration summary
+//	GET  /openapi.json the OpenAPI document of the DCP services
+//
+// A verification that ran answers 200 with a Result, verified or not. A
+// body that is not a signed bundle answers 400, an oversized body 413, and
+// an unreachable revocation source 503: the service fails closed rather
+// than report a bundle valid without knowing whether its agent is revoked.
+package verifyserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/openapi"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/webhook"
+)
+
+// Defaults for zero Config fields.
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxBodyBytes = 1 << 20
+)
+
+// Config configures a Server.
+type Config struct {
+	// TrustedKeys are the base64 Ed25519 keys accepted as bundle signers.
+	// When empty, a bundle is verified against the key embedded in its
+	// signature, which proves integrity but not who signed it.
+	TrustedKeys []string
+	// PDPKeys are the keys of the PDPs trusted to sign policy decisions;
+	// see dcp.VerifyOptions.PDPKeys.
+	PDPKeys []string
+	// Countersigners are the keys allowed to countersign break-glass
+	// records; see dcp.VerifyOptions.Countersigners. Without them, a
+	// bundle with break-glass records fails verification.
+	Countersigners []string
+	// SignerSets are the signer sets of group principals, by human_id, as
+	// published outside their bundles; see dcp.VerifyOptions.SignerSets.
+	SignerSets map[string]*dcp.PrincipalSignerSet
+	// Guardians are the guardians' keys of dependent principals, by the
+	// principal's human_id; see dcp.VerifyOptions.Guardians.
+	Guardians map[string]string
+	// EnforceLiability also checks each bundle against the obligations of
+	// its principal's liability mode; see dcp.VerifyOptions.EnforceLiability.
+	EnforceLiability bool
+	// InsurerKeys are the published keys of the insurers and platforms
+	// trusted to attest coverage; see dcp.VerifyOptions.InsurerKeys.
+	InsurerKeys []string
+	// Revocations are consulted in order for the bundle's agent once the
+	// signature and hashes check out. The first revocation found fails
+	// verification.
+	Revocations []dcp.RevocationChecker
+	// Timeout bounds each request, including revocation lookups.
+	Timeout time.Duration
+	// MaxBodyBytes caps the request body.
+	MaxBodyBytes int64
+	// Now is the clock used for expiry checks; nil means time.Now.
+	Now func() time.Time
+	// Webhooks, if set, is sent a bundle.verified or bundle.failed event
+	// for every bundle checked.
+	Webhooks *webhook.Dispatcher
+}
+
+// Result is the response to POST /v1/verify.
+type Result struct {
+	Verified bool `json:"verified"`
+	// Errors is empty when Verified is true.
+	Errors []string `json:"errors"`
+	// Trace is set when the request asked for ?explain=true.
+	Trace []dcp.TraceStep `json:"trace,omitempty"`
+	// Flags are reported even for a verified bundle, such as its
+	// break-glass records.
+	Flags      []string `json:"flags,omitempty"`
+	BundleHash string   `json:"bundle_hash,omitempty"`
+	AgentID    string   `json:"agent_id,omitempty"`
+	HumanID    string   `json:"human_id,omitempty"`
+	// SignerKey is the key the signature was checked against, or the
+	// principal's for a bundle signed under powers of attorney, and
+	// Trusted whether it is one of Config.TrustedKeys.
+	SignerKey string `json:"signer_key,omitempty"`
+	Trusted   bool   `json:"trusted"`
+	// Revocation is the record that revoked the agent, if any.
+	Revocation *dcp.RevocationRecord `json:"revocation,omitempty"`
+	CheckedAt  string                `json:"checked_at"`
+}
+
+// Server serves the verification API. Create one with New.
+type Server struct {
+	cfg     Config
+	trusted map[string]bool
+	mux     *http.ServeMux
+}
+
+// New returns a Server for cfg, filling in defaults.
+func New(cfg Config) *Server {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	s := &Server{cfg: cfg, trusted: make(map[string]bool, len(cfg.TrustedKeys)), mux: http.NewServeMux()}
+	for _, k := range cfg.TrustedKeys {
+		s.trusted[k] = true
+	}
+	s.mux.HandleFunc("POST /v1/verify", s.handleVerify)
+	s.mux.HandleFunc("GET /health", s.handleHealth)
+	s.mux.Handle("GET /openapi.json", openapi.Handler())
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"ok":                 true,
+		"service":            "dcp-verify",
+		"supported_versions": []string{"1.0"},
+		"trusted_keys":       len(s.cfg.TrustedKeys),
+		"revocation_sources": len(s.cfg.Revocations),
+		"timeout_ms":         s.cfg.Timeout.Milliseconds(),
+		"max_body_bytes":     s.cfg.MaxBodyBytes,
+	})
+}
+
+func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.Timeout)
+	defer cancel()
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.cfg.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", s.cfg.MaxBodyBytes))
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	explain, _ := strconv.ParseBool(r.URL.Query().Get("explain"))
+	rsb, err := dcp.ParseSignedBundle(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := s.Verify(ctx, rsb, explain)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// Verify checks rsb against the configured trust anchors and revocation
+// sources. It returns an error only when a revocation source could not be
+// consulted.
+func (s *Server) Verify(ctx context.Context, rsb *dcp.RawSignedBundle, explain bool) (*Result, error) {
+	res, err := s.verify(ctx, rsb, explain)
+	if err != nil {
+		return nil, err
+	}
+	event := *res
+	event.Trace = nil
+	if res.Verified {
+		s.cfg.Webhooks.Emit(webhook.EventBundleVerified, &event)
+	} else {
+		s.cfg.Webhooks.Emit(webhook.EventBundleFailed, &event)
+	}
+	return res, nil
+}
+
+func (s *Server) verify(ctx context.Context, rsb *dcp.RawSignedBundle, explain bool) (*Result, error) {
+	b := &rsb.Bundle
+	res := &Result{
+		Errors:     []string{},
+		BundleHash: rsb.Signature.BundleHash,
+		AgentID:    b.AgentPassport.AgentID,
+		HumanID:    b.ResponsiblePrincipalRecord.HumanID,
+		CheckedAt:  dcp.FormatTime(s.cfg.Now()),
+	}
+	fail := func(msg string) (*Result, error) {
+		res.Errors = append(res.Errors, msg)
+		return res, nil
+	}
+
+	key := rsb.Signature.SignerInfo.PublicKeyB64
+	if chain := b.PowersOfAttorney; len(chain) > 0 {
+		// Signed by an attorney: the principal's key, which the first
+		// grant is made with, is the one trusted and pinned, and the
+		// chain links it to the signer's.
+		key = chain[0].GrantorPublicKey
+		if len(s.trusted) > 0 && !s.trusted[key] {
+			res.SignerKey = key
+			return fail("principal key of the powers of attorney is not a trusted key")
+		}
+	} else if len(s.trusted) > 0 && !s.trusted[key] {
+		if key != "" {
+			res.SignerKey = key
+			return fail("signer key is not a trusted key")
+		}
+		key = s.findTrustedKey(rsb)
+		if key == "" {
+			return fail("no trusted key verifies the bundle signature")
+		}
+	}
+	res.SignerKey, res.Trusted = key, s.trusted[key]
+
+	vr := dcp.VerifyRawSignedBundleWithOptions(rsb, dcp.VerifyOptions{PublicKeyB64: key, Explain: explain, PDPKeys: s.cfg.PDPKeys,
+		Countersigners: s.cfg.Countersigners, EnforceLiability: s.cfg.EnforceLiability, InsurerKeys: s.cfg.InsurerKeys,
+		SignerSets: s.cfg.SignerSets, Guardians: s.cfg.Guardians})
+	res.Trace = vr.Trace
+	res.Flags = vr.Flags
+	if !vr.Verified {
+		res.Errors = append(res.Errors, vr.Errors...)
+		return res, nil
+	}
+
+	if b.AgentPassport.Status == dcp.StatusRevoked {
+		return fail("agent passport status is revoked")
+	}
+	if exp, err := b.ResponsiblePrincipalRecord.ExpiresAtTime(); err != nil {
+		return fail(fmt.Sprintf("responsible_principal_record.expires_at: %v", err))
+	} else if !exp.IsZero() && s.cfg.Now().After(exp) {
+		return fail("responsible principal record expired at " + *b.ResponsiblePrincipalRecord.ExpiresAt)
+	}
+	rec, err := s.Revocation(ctx, res.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	if rec != nil {
+		res.Revocation = rec
+		return fail(fmt.Sprintf("agent %s was revoked at %s: %s", rec.AgentID, rec.Timestamp, rec.Reason))
+	}
+	res.Verified = true
+	return res, nil
+}
+
+// Revocation consults the revocation sources in order and returns the first
+// record revoking agentID, or nil.
+func (s *Server) Revocation(ctx context.Context, agentID string) (*dcp.RevocationRecord, error) {
+	for _, rc := range s.cfg.Revocations {
+		rec, err := rc.CheckRevocation(ctx, agentID)
+		if err != nil {
+			return nil, fmt.Errorf("revocation check for %s: %w", agentID, err)
+		}
+		if rec != nil {
+			return rec, nil
+		}
+	}
+	return nil, nil
+}
+
+// findTrustedKey returns the trusted key the bundle signature verifies
+// against, for bundles that do not embed their signer's key.
+func (s *Server) findTrustedKey(rsb *dcp.RawSignedBundle) string {
+	canon, err := dcp.CanonicalizeJSON(rsb.RawBundle)
+	if err != nil {
+		return ""
+	}
+	for _, k := range s.cfg.TrustedKeys {
+		if ok, _ := dcp.VerifyCanonical(canon, rsb.Signature.SigB64, k); ok {
+			return k
+		}
+	}
+	return ""
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers in the {"error": ...} form of the other DCP services.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
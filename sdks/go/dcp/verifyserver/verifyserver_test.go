@@ -0,0 +1,376 @@
+package verifyserver_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/verifyserver"
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/webhook"
+)
+
+// fixture returns the conformance bundle.
+func fixture(t *testing.T) *dcp.SignedBundle {
+	t.Helper()
+	_, thisFile, _, _ := runtime.Caller(0)
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(thisFile), "..", "..", "..", "..", "tests", "conformance", "examples", "citizenship_bundle.signed.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb dcp.SignedBundle
+	if err := json.Unmarshal(data, &sb); err != nil {
+		t.Fatal(err)
+	}
+	return &sb
+}
+
+// signedFixture re-signs the conformance bundle with a fresh key, after
+// applying edit, and returns the JSON and the public key. The key is
+// embedded in the signature unless embed is false.
+func signedFixture(t *testing.T, embed bool, edit func(*dcp.CitizenshipBundle)) ([]byte, string) {
+	t.Helper()
+	sb := fixture(t)
+	if edit != nil {
+		edit(&sb.Bundle)
+	}
+	kp, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := dcp.NewKeySigner(kp.SecretKeyB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := dcp.Signer{Type: "human", ID: sb.Bundle.ResponsiblePrincipalRecord.HumanID}
+	if embed {
+		info.PublicKeyB64 = kp.PublicKeyB64
+	}
+	signed, err := dcp.SignBundle(&sb.Bundle, signer, info, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out, kp.PublicKeyB64
+}
+
+func post(t *testing.T, h http.Handler, target string, body []byte) (int, map[string]interface{}, verifyserver.Result) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body)))
+	var generic map[string]interface{}
+	var res verifyserver.Result
+	json.Unmarshal(rec.Body.Bytes(), &generic)
+	json.Unmarshal(rec.Body.Bytes(), &res)
+	return rec.Code, generic, res
+}
+
+func TestVerify(t *testing.T) {
+	body, pub := signedFixture(t, true, nil)
+	srv := verifyserver.New(verifyserver.Config{})
+
+	code, _, res := post(t, srv, "/v1/verify", body)
+	if code != http.StatusOK || !res.Verified || len(res.Errors) != 0 {
+		t.Fatalf("code %d, result %+v", code, res)
+	}
+	if res.SignerKey != pub || res.Trusted || res.AgentID != "did:agent:agent123" || res.Trace != nil {
+		t.Fatalf("result %+v", res)
+	}
+
+	_, _, res = post(t, srv, "/v1/verify?explain=true", body)
+	if !res.Verified || len(res.Trace) == 0 || res.Trace[0].Check != dcp.TraceCanonicalize {
+		t.Fatalf("explain: %+v", res)
+	}
+
+	tampered := bytes.Replace(body, []byte(`"outcome":"`), []byte(`"outcome":"x`), 1)
+	code, _, res = post(t, srv, "/v1/verify", tampered)
+	if code != http.StatusOK || res.Verified || len(res.Errors) == 0 {
+		t.Fatalf("tampered: code %d, result %+v", code, res)
+	}
+}
+
+func TestVerifyTrustedKeys(t *testing.T) {
+	body, pub := signedFixture(t, true, nil)
+	other, err := dcp.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, res := post(t, verifyserver.New(verifyserver.Config{TrustedKeys: []string{other.PublicKeyB64}}), "/v1/verify", body)
+	if res.Verified || !strings.Contains(strings.Join(res.Errors, ";"), "not a trusted key") {
+		t.Fatalf("untrusted signer: %+v", res)
+	}
+
+	srv := verifyserver.New(verifyserver.Config{TrustedKeys: []string{other.PublicKeyB64, pub}})
+	if _, _, res = post(t, srv, "/v1/verify", body); !res.Verified || !res.Trusted {
+		t.Fatalf("trusted signer: %+v", res)
+	}
+
+	// Without an embedded key the trusted key that verifies is found.
+	bare, pub := signedFixture(t, false, nil)
+	srv = verifyserver.New(verifyserver.Config{TrustedKeys: []string{other.PublicKeyB64, pub}})
+	if _, _, res = post(t, srv, "/v1/verify", bare); !res.Verified || res.SignerKey != pub {
+		t.Fatalf("bare signature: %+v", res)
+	}
+	srv = verifyserver.New(verifyserver.Config{TrustedKeys: []string{other.PublicKeyB64}})
+	if _, _, res = post(t, srv, "/v1/verify", bare); res.Verified {
+		t.Fatalf("bare signature by an untrusted key verified: %+v", res)
+	}
+}
+
+func TestVerifyBundleState(t *testing.T) {
+	expired, _ := signedFixture(t, true, func(b *dcp.CitizenshipBundle) {
+		at := "2020-01-01T00:00:00Z"
+		b.ResponsiblePrincipalRecord.ExpiresAt = &at
+	})
+	revoked, _ := signedFixture(t, true, func(b *dcp.CitizenshipBundle) {
+		b.AgentPassport.Status = dcp.StatusRevoked
+	})
+	srv := verifyserver.New(verifyserver.Config{})
+	for name, body := range map[string][]byte{"expired": expired, "revoked": revoked} {
+		if _, _, res := post(t, srv, "/v1/verify", body); res.Verified {
+			t.Errorf("%s bundle verified: %+v", name, res)
+		}
+	}
+}
+
+func TestVerifyRevocations(t *testing.T) {
+	body, _ := signedFixture(t, true, nil)
+	path := filepath.Join(t.TempDir(), "revocations.json")
+	if err := dcp.WriteRevocationList(path, &dcp.RevocationList{}); err != nil {
+		t.Fatal(err)
+	}
+	file, err := verifyserver.NewFileRevocations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyserver.NewFileRevocations(path + ".missing"); err == nil {
+		t.Fatal("missing revocation file accepted")
+	}
+
+	var registryRevoked bool
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/check/") {
+			http.NotFound(w, r)
+			return
+		}
+		if registryRevoked {
+			json.NewEncoder(w).Encode(map[string]interface{}{"revoked": true,
+				"record": dcp.NewRevocationRecord("did:agent:agent123", "did:human:alice123", "registry")})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"revoked": false})
+	}))
+	defer registry.Close()
+
+	srv := verifyserver.New(verifyserver.Config{Revocations: []dcp.RevocationChecker{
+		file, &verifyserver.RegistryRevocations{URL: registry.URL},
+	}})
+	if _, _, res := post(t, srv, "/v1/verify", body); !res.Verified {
+		t.Fatalf("no revocations: %+v", res)
+	}
+
+	registryRevoked = true
+	if _, _, res := post(t, srv, "/v1/verify", body); res.Verified || res.Revocation == nil || res.Revocation.Reason != "registry" {
+		t.Fatalf("registry revocation: %+v", res)
+	}
+
+	// The file is picked up again once it changes.
+	l := &dcp.RevocationList{}
+	l.Add(dcp.NewRevocationRecord("did:agent:agent123", "did:human:alice123", "file"))
+	if err := dcp.WriteRevocationList(path, l); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, res := post(t, srv, "/v1/verify", body); res.Verified || res.Revocation == nil || res.Revocation.Reason != "file" {
+		t.Fatalf("file revocation: %+v", res)
+	}
+
+	registry.Close()
+	srv = verifyserver.New(verifyserver.Config{Revocations: []dcp.RevocationChecker{&verifyserver.RegistryRevocations{URL: registry.URL}}})
+	if code, generic, _ := post(t, srv, "/v1/verify", body); code != http.StatusServiceUnavailable || generic["error"] == nil {
+		t.Fatalf("unreachable registry: %d %v", code, generic)
+	}
+}
+
+func TestVerifyRequests(t *testing.T) {
+	srv := verifyserver.New(verifyserver.Config{MaxBodyBytes: 64})
+	if code, generic, _ := post(t, srv, "/v1/verify", []byte(`{"bundle":`)); code != http.StatusBadRequest || generic["error"] == nil {
+		t.Fatalf("bad JSON: %d %v", code, generic)
+	}
+	if code, _, _ := post(t, srv, "/v1/verify", bytes.Repeat([]byte(" "), 100)); code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized body: %d", code)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/verify", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET /v1/verify: %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil || health["ok"] != true || health["max_body_bytes"] != float64(64) {
+		t.Fatalf("health: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestVerifyWebhooks(t *testing.T) {
+	body, _ := signedFixture(t, true, nil)
+	var mu sync.Mutex
+	var events []webhook.Event
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ev, err := webhook.VerifyRequest(r, "secret", 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mu.Lock()
+		events = append(events, *ev)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+	hooks, err := webhook.New(webhook.Config{Endpoints: []webhook.Endpoint{{URL: hook.URL, Secret: "secret"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := verifyserver.New(verifyserver.Config{Webhooks: hooks})
+	post(t, srv, "/v1/verify?explain=true", body)
+	post(t, srv, "/v1/verify", bytes.Replace(body, []byte(`"outcome":"`), []byte(`"outcome":"x`), 1))
+	if err := hooks.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Type != webhook.EventBundleVerified || events[1].Type != webhook.EventBundleFailed {
+		t.Fatalf("events = %+v", events)
+	}
+	var res verifyserver.Result
+	if err := json.Unmarshal(events[0].Data, &res); err != nil || !res.Verified || res.Trace != nil {
+		t.Fatalf("event data = %s", events[0].Data)
+	}
+}
+
+func TestVerifyPowerOfAttorney(t *testing.T) {
+	principal, _ := dcp.GenerateKeypair()
+	attorney, _ := dcp.GenerateKeypair()
+	principalSigner, _ := dcp.NewKeySigner(principal.SecretKeyB64)
+	attorneySigner, _ := dcp.NewKeySigner(attorney.SecretKeyB64)
+	sb := fixture(t)
+	// The grant must hold when the intent was declared.
+	declared, _ := sb.Bundle.Intent.TimestampTime()
+	f := dcp.RecordFactory{Clock: func() time.Time { return declared.Add(-time.Minute) }}
+	poa := f.NewPowerOfAttorney(dcp.PrincipalMember{HumanID: sb.Bundle.ResponsiblePrincipalRecord.HumanID, PublicKey: principal.PublicKeyB64},
+		dcp.PrincipalMember{HumanID: "did:human:bob456", PublicKey: attorney.PublicKeyB64}, []dcp.AttorneyScope{dcp.AttorneyBind}, time.Hour)
+	if err := poa.Sign(principalSigner); err != nil {
+		t.Fatal(err)
+	}
+	sb.Bundle.PowersOfAttorney = []dcp.PowerOfAttorney{poa}
+	signed, err := dcp.SignBundle(&sb.Bundle, attorneySigner, dcp.Signer{PublicKeyB64: attorney.PublicKeyB64}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(signed)
+
+	if _, _, res := post(t, verifyserver.New(verifyserver.Config{}), "/v1/verify", body); !res.Verified || res.SignerKey != principal.PublicKeyB64 {
+		t.Fatalf("no trusted keys: %+v", res)
+	}
+	srv := verifyserver.New(verifyserver.Config{TrustedKeys: []string{principal.PublicKeyB64}})
+	if _, _, res := post(t, srv, "/v1/verify", body); !res.Verified || !res.Trusted {
+		t.Fatalf("principal trusted: %+v", res)
+	}
+	srv = verifyserver.New(verifyserver.Config{TrustedKeys: []string{attorney.PublicKeyB64}})
+	if _, _, res := post(t, srv, "/v1/verify", body); res.Verified || !strings.Contains(strings.Join(res.Errors, ";"), "not a trusted key") {
+		t.Fatalf("only the attorney trusted: %+v", res)
+	}
+}
+
+func TestVerifyBreakGlass(t *testing.T) {
+	principal, _ := dcp.GenerateKeypair()
+	officer, _ := dcp.GenerateKeypair()
+	principalSigner, _ := dcp.NewKeySigner(principal.SecretKeyB64)
+	officerSigner, _ := dcp.NewKeySigner(officer.SecretKeyB64)
+	sb := fixture(t)
+	declared, err := sb.Bundle.Intent.TimestampTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := dcp.RecordFactory{Clock: func() time.Time { return declared }}
+	r, err := f.NewBreakGlassRecord(sb.Bundle.Intent, sb.Bundle.Intent.HumanID, "customer locked out of a medical account", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Sign(principalSigner); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Countersign(officerSigner, "officer001"); err != nil {
+		t.Fatal(err)
+	}
+	sb.Bundle.BreakGlass = []dcp.BreakGlassRecord{r}
+	signed, err := dcp.SignBundle(&sb.Bundle, principalSigner, dcp.Signer{PublicKeyB64: principal.PublicKeyB64}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(signed)
+
+	srv := verifyserver.New(verifyserver.Config{Countersigners: []string{officer.PublicKeyB64}})
+	if _, _, res := post(t, srv, "/v1/verify", body); !res.Verified || len(res.Flags) != 1 {
+		t.Fatalf("allowed countersigner: %+v", res)
+	}
+	if _, _, res := post(t, verifyserver.New(verifyserver.Config{}), "/v1/verify", body); res.Verified || !strings.Contains(strings.Join(res.Errors, ";"), "no countersigner keys") {
+		t.Fatalf("no countersigner keys: %+v", res)
+	}
+}
+
+func TestVerifyLiability(t *testing.T) {
+	body, _ := signedFixture(t, true, func(b *dcp.CitizenshipBundle) {
+		b.ResponsiblePrincipalRecord.LiabilityMode = dcp.LiabilityStrict
+	})
+	if _, _, res := post(t, verifyserver.New(verifyserver.Config{}), "/v1/verify", body); !res.Verified {
+		t.Fatalf("without EnforceLiability: %+v", res)
+	}
+	srv := verifyserver.New(verifyserver.Config{EnforceLiability: true})
+	if _, _, res := post(t, srv, "/v1/verify", body); res.Verified || !strings.Contains(strings.Join(res.Errors, ";"), "strict liability requires consent") {
+		t.Fatalf("with EnforceLiability: %+v", res)
+	}
+}
+
+func TestVerifyInsurance(t *testing.T) {
+	insurer, _ := dcp.GenerateKeypair()
+	signer, _ := dcp.NewKeySigner(insurer.SecretKeyB64)
+	body, _ := signedFixture(t, true, func(b *dcp.CitizenshipBundle) {
+		declared, err := b.Intent.TimestampTime()
+		if err != nil {
+			t.Fatal(err)
+		}
+		f := dcp.RecordFactory{Clock: func() time.Time { return declared.Add(-time.Minute) }}
+		a := f.NewInsuranceAttestation("insurer.example.com", dcp.AttesterInsurer, &b.AgentPassport, "POL-2026-0042", dcp.CoverageProfessional, time.Hour)
+		if err := a.Sign(signer); err != nil {
+			t.Fatal(err)
+		}
+		b.Insurance = []dcp.InsuranceAttestation{a}
+	})
+
+	srv := verifyserver.New(verifyserver.Config{InsurerKeys: []string{insurer.PublicKeyB64}})
+	if _, _, res := post(t, srv, "/v1/verify", body); !res.Verified {
+		t.Fatalf("trusted attester: %+v", res)
+	}
+	other, _ := dcp.GenerateKeypair()
+	srv = verifyserver.New(verifyserver.Config{InsurerKeys: []string{other.PublicKeyB64}})
+	if _, _, res := post(t, srv, "/v1/verify", body); res.Verified || !strings.Contains(strings.Join(res.Errors, ";"), "any key of a trusted attester") {
+		t.Fatalf("untrusted attester: %+v", res)
+	}
+}
@@ -0,0 +1,452 @@
+// Package webhook notifies external systems of protocol events: bundles
+// verified or rejected, agents revoked, passports issued and intents
+// blocked. A Dispatcher POSTs each event to the endpoints subscribed to it,
+// signed with the endpoint's shared secret, and retries with exponential
+// backoff; an event that still cannot be delivered is appended to a
+// dead-letter file instead of being lost.
+//
+// A delivery is a JSON Event with the headers
+//
+//	DCP-Webhook-ID         the event ID, the same on every retry
+//	DCP-Webhook-Event      the event type
+//	DCP-Webhook-Signature  t=<unix seconds>,v1=<hex HMAC-SHA256>
+//
+// The HMAC is keyed with the secret and covers "<t>.<body>", so a receiver
+// can reject forged and replayed deliveries with Verify or VerifyRequest.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+// Event types.
+const (
+	// EventBundleVerified carries the verifyserver.Result of a bundle that
+	// verified, without its trace.
+	EventBundleVerified = "bundle.verified"
+	// EventBundleFailed carries the verifyserver.Result of a bundle that
+	// did not.
+	EventBundleFailed = "bundle.failed"
+	// EventAgentRevoked carries the accepted dcp.RevocationRecord.
+	EventAgentRevoked = "agent.revoked"
+	// EventPassportIssued carries the issued dcp.AgentPassport.
+	EventPassportIssued = "passport.issued"
+	// EventPolicyDenied carries the pdp.SignedDecision blocking an intent.
+	EventPolicyDenied = "policy.denied"
+)
+
+// Delivery headers.
+const (
+	IDHeader        = "DCP-Webhook-ID"
+	EventHeader     = "DCP-Webhook-Event"
+	SignatureHeader = "DCP-Webhook-Signature"
+)
+
+// DefaultTolerance is how far a signature timestamp may be from the
+// receiver's clock when Verify is given no tolerance.
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrClosed is returned by Emit after Close.
+	ErrClosed = errors.New("webhook dispatcher is closed")
+	// ErrQueueFull reports an event dead-lettered because an endpoint's
+	// queue was full.
+	ErrQueueFull = errors.New("webhook queue is full")
+	// ErrSignature reports a delivery whose signature does not verify.
+	ErrSignature = errors.New("webhook signature does not verify")
+)
+
+// Event is the body of a delivery.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt string          `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Endpoint is a subscriber.
+type Endpoint struct {
+	URL string
+	// Secret keys the signature of every delivery to URL. Required.
+	Secret string
+	// Events are the event types delivered; empty means all.
+	Events []string
+}
+
+func (e *Endpoint) wants(eventType string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, t := range e.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Config configures a Dispatcher.
+type Config struct {
+	Endpoints []Endpoint
+	// MaxAttempts bounds the deliveries of an event to an endpoint; zero
+	// means 5.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled before each
+	// further one; zero means 1 second.
+	Backoff time.Duration
+	// Timeout bounds each delivery attempt; zero means 10 seconds.
+	Timeout time.Duration
+	// QueueSize is the number of events waiting per endpoint beyond which
+	// new events are dead-lettered; zero means 1000.
+	QueueSize int
+	// DeadLetterPath is the file undeliverable events are appended to, one
+	// JSON DeadLetter per line. Empty drops them.
+	DeadLetterPath string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Now is the clock events and signatures are stamped with; nil means
+	// time.Now.
+	Now func() time.Time
+}
+
+// DeadLetter is an event that could not be delivered to URL.
+type DeadLetter struct {
+	Event    Event  `json:"event"`
+	URL      string `json:"url"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error"`
+	FailedAt string `json:"failed_at"`
+}
+
+// Dispatcher delivers events in the background, in order per endpoint, so
+// a slow endpoint delays only its own deliveries. Create one with New and
+// Close it to flush pending deliveries. A nil *Dispatcher drops events, so
+// services can call Emit whether or not webhooks are configured.
+type Dispatcher struct {
+	cfg    Config
+	queues []chan Event
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	closed bool
+
+	deadMu sync.Mutex
+}
+
+// New validates cfg and starts a Dispatcher.
+func New(cfg Config) (*Dispatcher, error) {
+	for i, ep := range cfg.Endpoints {
+		if !strings.HasPrefix(ep.URL, "http://") && !strings.HasPrefix(ep.URL, "https://") {
+			return nil, fmt.Errorf("webhook: endpoint %q is not an http(s) URL", ep.URL)
+		}
+		if ep.Secret == "" {
+			return nil, fmt.Errorf("webhook: endpoints[%d] %s has no secret", i, ep.URL)
+		}
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1000
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	d := &Dispatcher{cfg: cfg}
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	for i := range cfg.Endpoints {
+		q := make(chan Event, cfg.QueueSize)
+		d.queues = append(d.queues, q)
+		d.wg.Add(1)
+		go d.run(&d.cfg.Endpoints[i], q)
+	}
+	return d, nil
+}
+
+// Emit queues an event of eventType carrying data for every subscribed
+// endpoint and returns without waiting for delivery. It fails only if data
+// cannot be encoded, the Dispatcher is closed, or a queue is full.
+func (d *Dispatcher) Emit(eventType string, data interface{}) error {
+	if d == nil || len(d.queues) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("webhook: %s: %w", eventType, err)
+	}
+	ev := Event{ID: newEventID(), Type: eventType, CreatedAt: dcp.FormatTime(d.cfg.Now()), Data: raw}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return ErrClosed
+	}
+	var errs []error
+	for i, q := range d.queues {
+		ep := &d.cfg.Endpoints[i]
+		if !ep.wants(eventType) {
+			continue
+		}
+		select {
+		case q <- ev:
+		default:
+			d.deadLetter(ep, ev, 0, ErrQueueFull)
+			errs = append(errs, fmt.Errorf("%s: %w", ep.URL, ErrQueueFull))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops accepting events and waits for the queued ones to be
+// delivered. If ctx ends first, pending retries are abandoned and the
+// remaining events are dead-lettered.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, q := range d.queues {
+			close(q)
+		}
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) run(ep *Endpoint, q <-chan Event) {
+	defer d.wg.Done()
+	for ev := range q {
+		d.deliver(ep, ev)
+	}
+}
+
+// deliver posts ev to ep until it is accepted, refused outright, or out of
+// attempts, and dead-letters it in the last two cases.
+func (d *Dispatcher) deliver(ep *Endpoint, ev Event) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		d.deadLetter(ep, ev, 0, err)
+		return
+	}
+	wait := d.cfg.Backoff
+	attempt := 0
+	for {
+		attempt++
+		retry, err := d.post(ep, ev, body)
+		if err == nil {
+			return
+		}
+		if !retry || attempt >= d.cfg.MaxAttempts {
+			d.deadLetter(ep, ev, attempt, err)
+			return
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-d.ctx.Done():
+			t.Stop()
+			d.deadLetter(ep, ev, attempt, fmt.Errorf("%v; abandoned at close", err))
+			return
+		}
+		wait *= 2
+	}
+}
+
+// post makes one delivery attempt. It reports whether a failure may be
+// retried: network errors, timeouts, 429 and 5xx answers may, other
+// answers may not.
+func (d *Dispatcher) post(ep *Endpoint, ev Event, body []byte) (bool, error) {
+	if err := d.ctx.Err(); err != nil {
+		return false, errors.New("dispatcher closed before delivery")
+	}
+	ctx, cancel := context.WithTimeout(d.ctx, d.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, ev.ID)
+	req.Header.Set(EventHeader, ev.Type)
+	req.Header.Set(SignatureHeader, Sign(ep.Secret, d.cfg.Now(), body))
+	resp, err := d.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode/100 == 2:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode >= 500:
+		return true, fmt.Errorf("%s answered %s", ep.URL, resp.Status)
+	default:
+		return false, fmt.Errorf("%s answered %s", ep.URL, resp.Status)
+	}
+}
+
+// deadLetter records that ev could not be delivered to ep.
+func (d *Dispatcher) deadLetter(ep *Endpoint, ev Event, attempts int, cause error) {
+	if d.cfg.DeadLetterPath == "" {
+		return
+	}
+	line, err := json.Marshal(DeadLetter{
+		Event:    ev,
+		URL:      ep.URL,
+		Attempts: attempts,
+		Error:    cause.Error(),
+		FailedAt: dcp.FormatTime(d.cfg.Now()),
+	})
+	if err != nil {
+		return
+	}
+	d.deadMu.Lock()
+	defer d.deadMu.Unlock()
+	f, err := os.OpenFile(d.cfg.DeadLetterPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return
+	}
+	f.Write(append(line, '\n'))
+	f.Close()
+}
+
+// ReadDeadLetters returns the dead letters recorded in path, oldest first.
+// A missing file holds none.
+func ReadDeadLetters(path string) ([]DeadLetter, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []DeadLetter
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var dl DeadLetter
+		if err := json.Unmarshal(line, &dl); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, i+1, err)
+		}
+		out = append(out, dl)
+	}
+	return out, nil
+}
+
+// Sign returns the DCP-Webhook-Signature value of body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+func mac(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify checks a DCP-Webhook-Signature header against body and secret,
+// and that its timestamp is within tolerance of now; zero tolerance means
+// DefaultTolerance. Any of several v1 values may match, so a sender can
+// sign with both secrets while one is rotated.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return fmt.Errorf("%w: malformed header", ErrSignature)
+	}
+	if skew := now.Sub(time.Unix(sec, 0)); skew > tolerance || skew < -tolerance {
+		return fmt.Errorf("%w: timestamp is %s from now", ErrSignature, skew.Round(time.Second))
+	}
+	want := mac(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(want)) {
+			return nil
+		}
+	}
+	return ErrSignature
+}
+
+// VerifyRequest reads a delivery, verifies its signature at the current
+// time, and returns its event. A receiver should deduplicate events by ID,
+// since a delivery whose answer was lost is retried.
+func VerifyRequest(r *http.Request, secret string, tolerance time.Duration) (*Event, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if err := Verify(secret, r.Header.Get(SignatureHeader), body, time.Now(), tolerance); err != nil {
+		return nil, err
+	}
+	var ev Event
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+	return &ev, nil
+}
+
+func newEventID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return "evt_" + hex.EncodeToString(b[:])
+}
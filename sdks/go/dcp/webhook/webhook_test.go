@@ -0,0 +1,196 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp/webhook"
+)
+
+// receiver records the events delivered to it, answering each attempt with
+// the next status of statuses and 204 once they run out.
+type receiver struct {
+	t        *testing.T
+	secret   string
+	mu       sync.Mutex
+	statuses []int
+	attempts int
+	events   []webhook.Event
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ev, err := webhook.VerifyRequest(r, rc.secret, 0)
+	if err != nil {
+		rc.t.Errorf("delivery: %v", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get(webhook.IDHeader) != ev.ID || r.Header.Get(webhook.EventHeader) != ev.Type {
+		rc.t.Errorf("headers %v do not match event %+v", r.Header, ev)
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.attempts++
+	if len(rc.statuses) > 0 {
+		status := rc.statuses[0]
+		rc.statuses = rc.statuses[1:]
+		if status/100 != 2 {
+			w.WriteHeader(status)
+			return
+		}
+	}
+	rc.events = append(rc.events, *ev)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestDispatcher(t *testing.T) {
+	all := &receiver{t: t, secret: "s1", statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	revocations := &receiver{t: t, secret: "s2"}
+	allSrv := httptest.NewServer(all)
+	defer allSrv.Close()
+	revSrv := httptest.NewServer(revocations)
+	defer revSrv.Close()
+
+	d, err := webhook.New(webhook.Config{
+		Endpoints: []webhook.Endpoint{
+			{URL: allSrv.URL, Secret: "s1"},
+			{URL: revSrv.URL, Secret: "s2", Events: []string{webhook.EventAgentRevoked}},
+		},
+		Backoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Emit(webhook.EventBundleVerified, map[string]string{"agent_id": "agent:1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Emit(webhook.EventAgentRevoked, map[string]string{"agent_id": "agent:2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Emit(webhook.EventAgentRevoked, nil); !errors.Is(err, webhook.ErrClosed) {
+		t.Fatalf("Emit after Close = %v", err)
+	}
+
+	if all.attempts != 4 || len(all.events) != 2 || all.events[0].Type != webhook.EventBundleVerified || all.events[1].Type != webhook.EventAgentRevoked {
+		t.Fatalf("all: %d attempts, events %+v", all.attempts, all.events)
+	}
+	if len(revocations.events) != 1 || revocations.events[0].ID != all.events[1].ID {
+		t.Fatalf("revocations: events %+v", revocations.events)
+	}
+	var data map[string]string
+	if err := json.Unmarshal(revocations.events[0].Data, &data); err != nil || data["agent_id"] != "agent:2" {
+		t.Fatalf("data = %s", revocations.events[0].Data)
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	failing := &receiver{t: t, secret: "s", statuses: []int{500, 500, 500, http.StatusGone}}
+	srv := httptest.NewServer(failing)
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	d, err := webhook.New(webhook.Config{
+		Endpoints:      []webhook.Endpoint{{URL: srv.URL, Secret: "s"}},
+		MaxAttempts:    3,
+		Backoff:        time.Millisecond,
+		DeadLetterPath: path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first event runs out of attempts; the second is refused outright.
+	d.Emit(webhook.EventPolicyDenied, map[string]int{"n": 1})
+	d.Emit(webhook.EventPolicyDenied, map[string]int{"n": 2})
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	dead, err := webhook.ReadDeadLetters(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 2 || dead[0].Attempts != 3 || dead[1].Attempts != 1 || dead[0].URL != srv.URL || dead[0].Error == "" {
+		t.Fatalf("dead letters = %+v", dead)
+	}
+	if failing.attempts != 4 || len(failing.events) != 0 {
+		t.Fatalf("%d attempts, events %+v", failing.attempts, failing.events)
+	}
+}
+
+func TestCloseAbandonsRetries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	d, err := webhook.New(webhook.Config{
+		Endpoints:      []webhook.Endpoint{{URL: srv.URL, Secret: "s"}},
+		Backoff:        time.Hour,
+		DeadLetterPath: path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Emit(webhook.EventBundleFailed, nil)
+	d.Emit(webhook.EventBundleFailed, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := d.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close = %v", err)
+	}
+	if dead, err := webhook.ReadDeadLetters(path); err != nil || len(dead) != 2 {
+		t.Fatalf("dead letters = %+v, %v", dead, err)
+	}
+}
+
+func TestNilDispatcher(t *testing.T) {
+	var d *webhook.Dispatcher
+	if err := d.Emit(webhook.EventAgentRevoked, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, ep := range []webhook.Endpoint{
+		{URL: "localhost:9000", Secret: "s"},
+		{URL: "https://example.com/hook"},
+	} {
+		if _, err := webhook.New(webhook.Config{Endpoints: []webhook.Endpoint{ep}}); err == nil {
+			t.Errorf("New accepted %+v", ep)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"id":"evt_1"}`)
+	sig := webhook.Sign("secret", now, body)
+	if err := webhook.Verify("secret", sig, body, now.Add(time.Minute), 0); err != nil {
+		t.Fatal(err)
+	}
+	rotated := sig + ",v1=" + webhook.Sign("old", now, body)[len("t=1700000000,v1="):]
+	if err := webhook.Verify("old", rotated, body, now, 0); err != nil {
+		t.Fatalf("rotated secret: %v", err)
+	}
+	for name, err := range map[string]error{
+		"wrong secret": webhook.Verify("other", sig, body, now, 0),
+		"edited body":  webhook.Verify("secret", sig, []byte(`{"id":"evt_2"}`), now, 0),
+		"stale":        webhook.Verify("secret", sig, body, now.Add(10*time.Minute), 0),
+		"malformed":    webhook.Verify("secret", "v1=abc", body, now, 0),
+	} {
+		if !errors.Is(err, webhook.ErrSignature) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+}
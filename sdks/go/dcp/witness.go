@@ -0,0 +1,106 @@
+package dcp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WitnessCosignature is an independent witness's countersignature on a
+// Checkpoint, in the spirit of sumdb/note cosigning. It covers the signed
+// checkpoint body and the time the witness observed it.
+type WitnessCosignature struct {
+	WitnessID  string `json:"witness_id"`
+	CosignedAt string `json:"cosigned_at"`
+	Signature  string `json:"signature"`
+}
+
+type cosignBody struct {
+	Checkpoint checkpointBody `json:"checkpoint"`
+	WitnessID  string         `json:"witness_id"`
+	CosignedAt string         `json:"cosigned_at"`
+}
+
+// Witness countersigns checkpoints only after checking that each one extends
+// the last checkpoint it cosigned for the same origin, so an operator cannot
+// obtain cosignatures on two diverging histories from the same witness.
+type Witness struct {
+	ID           string
+	SecretKeyB64 string
+
+	mu     sync.Mutex
+	latest map[string]*Checkpoint
+}
+
+// NewWitness returns a witness that signs with secretKeyB64.
+func NewWitness(id, secretKeyB64 string) *Witness {
+	return &Witness{ID: id, SecretKeyB64: secretKeyB64, latest: map[string]*Checkpoint{}}
+}
+
+// Cosign verifies c's operator signature and its continuity with the last
+// checkpoint this witness cosigned for c.Origin, then appends the witness's
+// cosignature to c. proof is a consistency proof from that previous
+// checkpoint's size; it is ignored the first time an origin is seen.
+func (w *Witness) Cosign(c *Checkpoint, operatorPublicKeyB64 string, proof []string, now time.Time) error {
+	ok, err := c.Verify(operatorPublicKeyB64)
+	if err != nil || !ok {
+		return fmt.Errorf("witness %s: checkpoint signature invalid", w.ID)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if prev := w.latest[c.Origin]; prev != nil {
+		if err := VerifyCheckpointContinuity(prev, c, proof); err != nil {
+			return fmt.Errorf("witness %s: %w", w.ID, err)
+		}
+	}
+	cs := WitnessCosignature{WitnessID: w.ID, CosignedAt: FormatTime(now)}
+	sig, err := SignObject(cosignBody{Checkpoint: c.body(), WitnessID: cs.WitnessID, CosignedAt: cs.CosignedAt}, w.SecretKeyB64)
+	if err != nil {
+		return fmt.Errorf("witness %s: %w", w.ID, err)
+	}
+	cs.Signature = sig
+
+	kept := c.Cosignatures[:0]
+	for _, existing := range c.Cosignatures {
+		if existing.WitnessID != w.ID {
+			kept = append(kept, existing)
+		}
+	}
+	c.Cosignatures = append(kept, cs)
+
+	snapshot := *c
+	snapshot.Cosignatures = nil
+	w.latest[c.Origin] = &snapshot
+	return nil
+}
+
+// WitnessPolicy requires at least Threshold valid cosignatures from the
+// witnesses it knows, keyed by witness ID to base64 Ed25519 public key.
+type WitnessPolicy struct {
+	Witnesses map[string]string
+	Threshold int
+}
+
+// Verify checks c's cosignatures against the policy. Cosignatures from
+// unknown witnesses are ignored and each witness counts at most once. The
+// operator signature is not checked here.
+func (p WitnessPolicy) Verify(c *Checkpoint) error {
+	if p.Threshold <= 0 || p.Threshold > len(p.Witnesses) {
+		return fmt.Errorf("witness policy: threshold %d invalid for %d witnesses", p.Threshold, len(p.Witnesses))
+	}
+	valid := map[string]bool{}
+	for _, cs := range c.Cosignatures {
+		pub, known := p.Witnesses[cs.WitnessID]
+		if !known || valid[cs.WitnessID] {
+			continue
+		}
+		body := cosignBody{Checkpoint: c.body(), WitnessID: cs.WitnessID, CosignedAt: cs.CosignedAt}
+		if ok, err := VerifyObject(body, cs.Signature, pub); err == nil && ok {
+			valid[cs.WitnessID] = true
+		}
+	}
+	if len(valid) < p.Threshold {
+		return fmt.Errorf("witness policy: %d of %d required cosignatures", len(valid), p.Threshold)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package dcp_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dcp-ai-protocol/dcp-ai/sdks/go/v2/dcp"
+)
+
+func TestWitnessCosigningPolicy(t *testing.T) {
+	operator, _ := dcp.GenerateKeypair()
+	policy := dcp.WitnessPolicy{Witnesses: map[string]string{}, Threshold: 2}
+	var witnesses []*dcp.Witness
+	for i := 0; i < 3; i++ {
+		kp, _ := dcp.GenerateKeypair()
+		id := fmt.Sprintf("witness-%d", i)
+		policy.Witnesses[id] = kp.PublicKeyB64
+		witnesses = append(witnesses, dcp.NewWitness(id, kp.SecretKeyB64))
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tree, _ := dcp.NewMerkleTreeFromHexLeaves(testLeaves(4))
+	cp := dcp.NewCheckpoint("agent-1", tree, now)
+	cp.Sign(operator.SecretKeyB64)
+
+	if err := witnesses[0].Cosign(cp, operator.PublicKeyB64, nil, now); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Verify(cp) == nil {
+		t.Fatal("one cosignature must not satisfy a 2-of-3 policy")
+	}
+	if err := witnesses[0].Cosign(cp, operator.PublicKeyB64, nil, now); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Verify(cp) == nil {
+		t.Fatal("a witness must only count once")
+	}
+	if err := witnesses[1].Cosign(cp, operator.PublicKeyB64, nil, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Verify(cp); err != nil {
+		t.Fatal(err)
+	}
+
+	cp.Cosignatures[1].CosignedAt = now.Add(time.Minute).Format(time.RFC3339)
+	if policy.Verify(cp) == nil {
+		t.Fatal("tampered cosignature must not count")
+	}
+}
+
+func TestWitnessRefusesFork(t *testing.T) {
+	operator, _ := dcp.GenerateKeypair()
+	kp, _ := dcp.GenerateKeypair()
+	w := dcp.NewWitness("w", kp.SecretKeyB64)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	leaves := testLeaves(8)
+	tree, _ := dcp.NewMerkleTreeFromHexLeaves(leaves[:3])
+	first := dcp.NewCheckpoint("agent-1", tree, now)
+	first.Sign(operator.SecretKeyB64)
+	if err := w.Cosign(first, operator.PublicKeyB64, nil, now); err != nil {
+		t.Fatal(err)
+	}
+
+	forked, _ := dcp.NewMerkleTreeFromHexLeaves(append([]string{leaves[7]}, leaves[1:6]...))
+	fork := dcp.NewCheckpoint("agent-1", forked, now.Add(time.Minute))
+	fork.Sign(operator.SecretKeyB64)
+	proof, _ := forked.ConsistencyProof(3)
+	if w.Cosign(fork, operator.PublicKeyB64, proof, now) == nil {
+		t.Fatal("witness must refuse a forked history")
+	}
+
+	for _, l := range leaves[3:6] {
+		tree.Append(l)
+	}
+	honest := dcp.NewCheckpoint("agent-1", tree, now.Add(time.Minute))
+	honest.Sign(operator.SecretKeyB64)
+	proof, _ = tree.ConsistencyProof(3)
+	if err := w.Cosign(honest, operator.PublicKeyB64, proof, now); err != nil {
+		t.Fatal(err)
+	}
+}